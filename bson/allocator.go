@@ -0,0 +1,84 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// Allocator provides the buffers that hold copies of BSON documents, such as the bytes of a Raw
+// value materialized by a Decoder. Implementations can pool buffers so that high-throughput
+// applications can return them with Release once they are no longer needed instead of leaving them
+// to the garbage collector. Implementations must be safe for concurrent use.
+type Allocator interface {
+	// Allocate returns a byte slice with a length of 0 and a capacity of at least size.
+	Allocate(size int) []byte
+
+	// Release returns a buffer obtained from Allocate to the Allocator. The buffer must not be
+	// used after it is released.
+	Release(b []byte)
+}
+
+const (
+	minPooledBufferBits = 8  // 256 bytes
+	maxPooledBufferBits = 25 // 32 MiB, enough for any document the server returns
+)
+
+// poolAllocator is an Allocator backed by one sync.Pool for each power-of-two buffer size.
+type poolAllocator struct {
+	pools [maxPooledBufferBits - minPooledBufferBits + 1]sync.Pool
+}
+
+var _ Allocator = (*poolAllocator)(nil)
+
+// NewPoolAllocator returns an Allocator that reuses released buffers. Buffers are grouped into
+// power-of-two size classes between 256 bytes and 32 MiB; larger buffers are allocated as usual and
+// are not retained when released.
+func NewPoolAllocator() Allocator {
+	return &poolAllocator{}
+}
+
+// sizeClass returns the index of the pool holding buffers large enough for size bytes, or -1 if
+// size is too large to be pooled.
+func sizeClass(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	n := bits.Len(uint(size - 1))
+	if n < minPooledBufferBits {
+		return 0
+	}
+	if n > maxPooledBufferBits {
+		return -1
+	}
+	return n - minPooledBufferBits
+}
+
+// Allocate implements the Allocator interface.
+func (pa *poolAllocator) Allocate(size int) []byte {
+	class := sizeClass(size)
+	if class < 0 {
+		return make([]byte, 0, size)
+	}
+	if b, ok := pa.pools[class].Get().(*[]byte); ok {
+		return (*b)[:0]
+	}
+	return make([]byte, 0, 1<<(class+minPooledBufferBits))
+}
+
+// Release implements the Allocator interface. Buffers whose capacity is not one of the pooled size
+// classes, such as buffers grown by append after they were allocated, are dropped.
+func (pa *poolAllocator) Release(b []byte) {
+	c := cap(b)
+	class := sizeClass(c)
+	if class < 0 || c != 1<<(class+minPooledBufferBits) {
+		return
+	}
+	b = b[:0]
+	pa.pools[class].Put(&b)
+}
@@ -0,0 +1,109 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// recordingAllocator is an Allocator that records the sizes it was asked for.
+type recordingAllocator struct {
+	sizes []int
+}
+
+func (ra *recordingAllocator) Allocate(size int) []byte {
+	ra.sizes = append(ra.sizes, size)
+	return make([]byte, 0, size)
+}
+
+func (*recordingAllocator) Release([]byte) {}
+
+func TestPoolAllocator(t *testing.T) {
+	testCases := []struct {
+		size    int
+		wantCap int
+	}{
+		{0, 256},
+		{1, 256},
+		{256, 256},
+		{257, 512},
+		{5000, 8192},
+		{1 << 25, 1 << 25},
+		{1<<25 + 1, 1<<25 + 1},
+	}
+	for _, tc := range testCases {
+		b := NewPoolAllocator().Allocate(tc.size)
+		assert.Equal(t, 0, len(b), "expected empty buffer for size %d", tc.size)
+		assert.Equal(t, tc.wantCap, cap(b), "expected capacity %d for size %d, got %d", tc.wantCap, tc.size, cap(b))
+	}
+
+	t.Run("release", func(t *testing.T) {
+		pa := NewPoolAllocator()
+		b := pa.Allocate(1000)
+		pa.Release(append(b, 1, 2, 3))
+
+		// sync.Pool may drop released buffers, so only the length and capacity of the reused buffer
+		// are checked.
+		b = pa.Allocate(600)
+		assert.Equal(t, 0, len(b), "expected released buffer to be reset")
+		assert.Equal(t, 1024, cap(b), "expected capacity 1024, got %d", cap(b))
+
+		// Buffers that do not match a size class are dropped rather than pooled.
+		pa.Release(make([]byte, 0, 1000))
+	})
+}
+
+func TestDecoderSetAllocator(t *testing.T) {
+	type document struct {
+		Name   string `bson:"name"`
+		Nested Raw    `bson:"nested"`
+	}
+
+	nested, err := Marshal(D{{"a", int32(1)}, {"b", "two"}})
+	require.NoError(t, err, "Marshal error: %v", err)
+	data, err := Marshal(D{{"name", "doc"}, {"nested", Raw(nested)}})
+	require.NoError(t, err, "Marshal error: %v", err)
+
+	t.Run("struct field", func(t *testing.T) {
+		alloc := &recordingAllocator{}
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.SetAllocator(alloc)
+
+		var got document
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, Raw(nested), got.Nested, "expected nested document to be copied")
+		assert.Equal(t, []int{len(nested)}, alloc.sizes, "expected one allocation of the nested document size")
+		assert.Equal(t, len(nested), cap(got.Nested), "expected the allocated buffer to be used")
+	})
+	t.Run("existing Raw is reused", func(t *testing.T) {
+		alloc := &recordingAllocator{}
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.SetAllocator(alloc)
+
+		got := document{Nested: make(Raw, 0, 64)}
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, Raw(nested), got.Nested, "expected nested document to be copied")
+		assert.Len(t, alloc.sizes, 0, "expected no allocations, got %v", alloc.sizes)
+	})
+	t.Run("top-level", func(t *testing.T) {
+		alloc := &recordingAllocator{}
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.SetAllocator(alloc)
+
+		var got Raw
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, Raw(data), got, "expected document to be copied")
+		assert.Equal(t, []int{len(data)}, alloc.sizes, "expected one allocation of the document size")
+	})
+}
@@ -89,6 +89,17 @@ type EncodeContext struct {
 	nilByteSliceAsEmpty     bool
 	omitZeroStruct          bool
 	useJSONStructTags       bool
+
+	// useJSONAndTextMarshalers causes values that implement json.Marshaler or
+	// encoding.TextMarshaler to be encoded using those methods.
+	useJSONAndTextMarshalers bool
+
+	// timeEncoding specifies the BSON type that time.Time values are encoded as.
+	timeEncoding TimeEncoding
+
+	// sortFields causes the fields of maps and structs to be encoded in ascending order of their
+	// names.
+	sortFields bool
 }
 
 // DecodeContext is the contextual information required for a Codec to decode a
@@ -117,6 +128,13 @@ type DecodeContext struct {
 	useLocalTimeZone  bool
 	zeroMaps          bool
 	zeroStructs       bool
+
+	// useJSONAndTextMarshalers causes values that implement json.Unmarshaler or
+	// encoding.TextUnmarshaler to be decoded using those methods.
+	useJSONAndTextMarshalers bool
+
+	// allocator, if non-nil, provides the buffers for Raw values that are decoded into a nil Raw.
+	allocator Allocator
 }
 
 // ValueEncoder is the interface implemented by types that can encode a provided Go type to BSON.
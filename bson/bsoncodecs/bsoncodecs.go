@@ -0,0 +1,289 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bsoncodecs
+
+import (
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	tDuration = reflect.TypeOf(time.Duration(0))
+	tTime     = reflect.TypeOf(time.Time{})
+)
+
+// RegisterUUID registers an encoder and decoder for the UUID type T, such as uuid.UUID from
+// github.com/google/uuid or github.com/gofrs/uuid, on reg.
+func RegisterUUID[T ~[16]byte](reg *bson.Registry) {
+	t := typeOf[T]()
+	reg.RegisterTypeEncoder(t, bson.ValueEncoderFunc(func(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+		if !val.IsValid() || val.Type() != t {
+			return bson.ValueEncoderError{Name: "UUIDEncodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+		u := val.Interface().(T)
+		return vw.WriteBinaryWithSubtype(u[:], bson.TypeBinaryUUID)
+	}))
+	reg.RegisterTypeDecoder(t, bson.ValueDecoderFunc(func(_ bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+		if !val.CanSet() || val.Type() != t {
+			return bson.ValueDecoderError{Name: "UUIDDecodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+
+		var u [16]byte
+		switch vrType := vr.Type(); vrType {
+		case bson.TypeBinary:
+			b, subtype, err := vr.ReadBinary()
+			if err != nil {
+				return err
+			}
+			if subtype != bson.TypeBinaryUUID && subtype != bson.TypeBinaryUUIDOld {
+				return fmt.Errorf("cannot decode binary subtype %v into a %s", subtype, t)
+			}
+			if len(b) != len(u) {
+				return fmt.Errorf("cannot decode %d bytes into a %s", len(b), t)
+			}
+			copy(u[:], b)
+		case bson.TypeString:
+			s, err := vr.ReadString()
+			if err != nil {
+				return err
+			}
+			u, err = parseUUID(s)
+			if err != nil {
+				return fmt.Errorf("cannot decode %q into a %s: %w", s, t, err)
+			}
+		default:
+			if err := readNull(vr, t); err != nil {
+				return err
+			}
+		}
+
+		val.Set(reflect.ValueOf(T(u)))
+		return nil
+	}))
+}
+
+// parseUUID parses a UUID in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form.
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID format")
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, err
+	}
+	return u, nil
+}
+
+// RegisterDecimal registers an encoder and decoder for the arbitrary-precision decimal type T,
+// such as decimal.Decimal from github.com/shopspring/decimal, on reg. Values are converted to and
+// from BSON decimal128 through their string representation, using the String method of T to
+// encode and parse to decode. Encoding a value that does not fit in a decimal128 returns an error.
+func RegisterDecimal[T fmt.Stringer](reg *bson.Registry, parse func(string) (T, error)) {
+	t := typeOf[T]()
+	reg.RegisterTypeEncoder(t, bson.ValueEncoderFunc(func(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+		if !val.IsValid() || val.Type() != t {
+			return bson.ValueEncoderError{Name: "DecimalEncodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+		s := val.Interface().(T).String()
+		d, err := bson.ParseDecimal128(s)
+		if err != nil {
+			return fmt.Errorf("cannot encode %s %q as a decimal128: %w", t, s, err)
+		}
+		return vw.WriteDecimal128(d)
+	}))
+	reg.RegisterTypeDecoder(t, bson.ValueDecoderFunc(func(_ bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+		if !val.CanSet() || val.Type() != t {
+			return bson.ValueDecoderError{Name: "DecimalDecodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+
+		var s string
+		switch vrType := vr.Type(); vrType {
+		case bson.TypeDecimal128:
+			d, err := vr.ReadDecimal128()
+			if err != nil {
+				return err
+			}
+			s = d.String()
+		case bson.TypeInt32:
+			i32, err := vr.ReadInt32()
+			if err != nil {
+				return err
+			}
+			s = strconv.FormatInt(int64(i32), 10)
+		case bson.TypeInt64:
+			i64, err := vr.ReadInt64()
+			if err != nil {
+				return err
+			}
+			s = strconv.FormatInt(i64, 10)
+		case bson.TypeDouble:
+			f64, err := vr.ReadDouble()
+			if err != nil {
+				return err
+			}
+			s = strconv.FormatFloat(f64, 'g', -1, 64)
+		case bson.TypeString:
+			var err error
+			if s, err = vr.ReadString(); err != nil {
+				return err
+			}
+		default:
+			if err := readNull(vr, t); err != nil {
+				return err
+			}
+			val.Set(reflect.Zero(t))
+			return nil
+		}
+
+		d, err := parse(s)
+		if err != nil {
+			return fmt.Errorf("cannot decode %q into a %s: %w", s, t, err)
+		}
+		val.Set(reflect.ValueOf(d))
+		return nil
+	}))
+}
+
+// RegisterText registers an encoder and decoder on reg that encode values of type T as BSON
+// strings with MarshalText and decode BSON strings into values of type T with UnmarshalText. It
+// is intended for types such as language.Tag from golang.org/x/text/language that have a
+// canonical text form.
+func RegisterText[T encoding.TextMarshaler, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](reg *bson.Registry) {
+	t := typeOf[T]()
+	reg.RegisterTypeEncoder(t, bson.ValueEncoderFunc(func(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+		if !val.IsValid() || val.Type() != t {
+			return bson.ValueEncoderError{Name: "TextEncodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+		b, err := val.Interface().(T).MarshalText()
+		if err != nil {
+			return err
+		}
+		return vw.WriteString(string(b))
+	}))
+	reg.RegisterTypeDecoder(t, bson.ValueDecoderFunc(func(_ bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+		if !val.CanSet() || val.Type() != t {
+			return bson.ValueDecoderError{Name: "TextDecodeValue", Types: []reflect.Type{t}, Received: val}
+		}
+
+		var v T
+		if vr.Type() == bson.TypeString {
+			s, err := vr.ReadString()
+			if err != nil {
+				return err
+			}
+			if err := PT(&v).UnmarshalText([]byte(s)); err != nil {
+				return fmt.Errorf("cannot decode %q into a %s: %w", s, t, err)
+			}
+		} else if err := readNull(vr, t); err != nil {
+			return err
+		}
+
+		val.Set(reflect.ValueOf(v))
+		return nil
+	}))
+}
+
+// RegisterNetIPAddr registers an encoder and decoder for netip.Addr on reg that encode addresses
+// as BSON strings, e.g. "192.0.2.1" or "2001:db8::1". The zero netip.Addr is encoded as an empty
+// string.
+func RegisterNetIPAddr(reg *bson.Registry) {
+	RegisterText[netip.Addr](reg)
+}
+
+// RegisterDurationMillis registers an encoder and decoder for time.Duration on reg that encode
+// durations as BSON int64 values with the number of milliseconds. Without it, durations are
+// encoded as int64 values with the number of nanoseconds.
+func RegisterDurationMillis(reg *bson.Registry) {
+	reg.RegisterTypeEncoder(tDuration, bson.ValueEncoderFunc(func(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+		if !val.IsValid() || val.Type() != tDuration {
+			return bson.ValueEncoderError{Name: "DurationMillisEncodeValue", Types: []reflect.Type{tDuration}, Received: val}
+		}
+		return vw.WriteInt64(time.Duration(val.Int()).Milliseconds())
+	}))
+	reg.RegisterTypeDecoder(tDuration, bson.ValueDecoderFunc(func(_ bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+		if !val.CanSet() || val.Type() != tDuration {
+			return bson.ValueDecoderError{Name: "DurationMillisDecodeValue", Types: []reflect.Type{tDuration}, Received: val}
+		}
+
+		var d time.Duration
+		switch vrType := vr.Type(); vrType {
+		case bson.TypeInt32:
+			i32, err := vr.ReadInt32()
+			if err != nil {
+				return err
+			}
+			d = time.Duration(i32) * time.Millisecond
+		case bson.TypeInt64:
+			i64, err := vr.ReadInt64()
+			if err != nil {
+				return err
+			}
+			d = time.Duration(i64) * time.Millisecond
+		case bson.TypeDouble:
+			f64, err := vr.ReadDouble()
+			if err != nil {
+				return err
+			}
+			d = time.Duration(f64 * float64(time.Millisecond))
+		default:
+			if err := readNull(vr, tDuration); err != nil {
+				return err
+			}
+		}
+
+		val.SetInt(int64(d))
+		return nil
+	}))
+}
+
+// RegisterTimeEncoding registers an encoder for time.Time on reg that encodes times as the BSON
+// type specified by te. Unlike bson.Encoder.SetTimeEncoding, it applies to every Encoder that uses
+// reg. The default decoder for time.Time decodes values of all encodings.
+func RegisterTimeEncoding(reg *bson.Registry, te bson.TimeEncoding) {
+	reg.RegisterTypeEncoder(tTime, bson.ValueEncoderFunc(func(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+		if !val.IsValid() || val.Type() != tTime {
+			return bson.ValueEncoderError{Name: "TimeEncodeValue", Types: []reflect.Type{tTime}, Received: val}
+		}
+		t := val.Interface().(time.Time)
+		switch te {
+		case bson.TimeEncodingMillis:
+			return vw.WriteInt64(int64(bson.NewDateTimeFromTime(t)))
+		case bson.TimeEncodingRFC3339:
+			return vw.WriteString(t.UTC().Format(time.RFC3339Nano))
+		default:
+			return vw.WriteDateTime(int64(bson.NewDateTimeFromTime(t)))
+		}
+	}))
+}
+
+// readNull reads a BSON null or undefined value from vr, and returns an error for values of any
+// other type.
+func readNull(vr bson.ValueReader, t reflect.Type) error {
+	switch vrType := vr.Type(); vrType {
+	case bson.TypeNull:
+		return vr.ReadNull()
+	case bson.TypeUndefined:
+		return vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a %s", vrType, t)
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
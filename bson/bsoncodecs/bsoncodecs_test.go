@@ -0,0 +1,175 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bsoncodecs
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+type testUUID [16]byte
+
+type testDecimal struct{ s string }
+
+func (d testDecimal) String() string { return d.s }
+
+func parseTestDecimal(s string) (testDecimal, error) {
+	if strings.ContainsAny(s, "NI") {
+		return testDecimal{}, errors.New("not a number")
+	}
+	return testDecimal{s: s}, nil
+}
+
+type testDoc struct {
+	UUID     testUUID      `bson:"uuid"`
+	Decimal  testDecimal   `bson:"decimal"`
+	Addr     netip.Addr    `bson:"addr"`
+	Duration time.Duration `bson:"duration"`
+}
+
+func newTestRegistry() *bson.Registry {
+	reg := bson.NewRegistry()
+	RegisterUUID[testUUID](reg)
+	RegisterDecimal(reg, parseTestDecimal)
+	RegisterNetIPAddr(reg)
+	RegisterDurationMillis(reg)
+	return reg
+}
+
+func marshal(t *testing.T, reg *bson.Registry, val interface{}) bson.Raw {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+	enc.SetRegistry(reg)
+	require.NoError(t, enc.Encode(val), "Encode error")
+	return buf.Bytes()
+}
+
+func unmarshal(reg *bson.Registry, doc bson.Raw, val interface{}) error {
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(doc)))
+	dec.SetRegistry(reg)
+	return dec.Decode(val)
+}
+
+func TestCodecs(t *testing.T) {
+	t.Parallel()
+
+	reg := newTestRegistry()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		want := testDoc{
+			UUID:     testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+			Decimal:  testDecimal{s: "1.50"},
+			Addr:     netip.MustParseAddr("2001:db8::1"),
+			Duration: 1500 * time.Millisecond,
+		}
+		doc := marshal(t, reg, want)
+
+		subtype, data := doc.Lookup("uuid").Binary()
+		assert.Equal(t, bson.TypeBinaryUUID, subtype, "UUID subtype mismatch")
+		assert.Equal(t, want.UUID[:], data, "UUID bytes mismatch")
+		assert.Equal(t, "1.50", doc.Lookup("decimal").Decimal128().String(), "decimal mismatch")
+		assert.Equal(t, "2001:db8::1", doc.Lookup("addr").StringValue(), "address mismatch")
+		assert.Equal(t, int64(1500), doc.Lookup("duration").Int64(), "duration mismatch")
+
+		var got testDoc
+		require.NoError(t, unmarshal(reg, doc, &got), "Decode error")
+		assert.Equal(t, want, got, "round trip mismatch")
+	})
+
+	t.Run("alternate encodings", func(t *testing.T) {
+		t.Parallel()
+
+		doc := marshal(t, bson.NewRegistry(), bson.D{
+			{"uuid", "123e4567-e89b-12d3-a456-426614174000"},
+			{"decimal", int32(42)},
+			{"duration", 2.5},
+		})
+
+		var got testDoc
+		require.NoError(t, unmarshal(reg, doc, &got), "Decode error")
+		want := testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+		assert.Equal(t, want, got.UUID, "UUID mismatch")
+		assert.Equal(t, "42", got.Decimal.String(), "decimal mismatch")
+		assert.Equal(t, 2500*time.Microsecond, got.Duration, "duration mismatch")
+	})
+
+	t.Run("null", func(t *testing.T) {
+		t.Parallel()
+
+		doc := marshal(t, bson.NewRegistry(), bson.D{
+			{"uuid", nil},
+			{"decimal", nil},
+			{"addr", nil},
+			{"duration", nil},
+		})
+
+		got := testDoc{Duration: time.Second, Decimal: testDecimal{s: "1"}}
+		require.NoError(t, unmarshal(reg, doc, &got), "Decode error")
+		assert.Equal(t, testDoc{}, got, "expected zero values")
+	})
+
+	errorTests := []struct {
+		name string
+		doc  bson.D
+	}{
+		{"UUID with wrong subtype", bson.D{{"uuid", bson.Binary{Subtype: bson.TypeBinaryGeneric, Data: make([]byte, 16)}}}},
+		{"UUID with wrong length", bson.D{{"uuid", bson.Binary{Subtype: bson.TypeBinaryUUID, Data: make([]byte, 15)}}}},
+		{"malformed UUID string", bson.D{{"uuid", "123e4567e89b12d3a456426614174000"}}},
+		{"non-finite decimal", bson.D{{"decimal", bson.NewDecimal128(0x7c00000000000000, 0)}}},
+		{"malformed address", bson.D{{"addr", "not an address"}}},
+		{"duration of wrong type", bson.D{{"duration", "1s"}}},
+	}
+	for _, tc := range errorTests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got testDoc
+			err := unmarshal(reg, marshal(t, bson.NewRegistry(), tc.doc), &got)
+			assert.Error(t, err, "expected a decode error")
+		})
+	}
+
+	t.Run("decimal out of range", func(t *testing.T) {
+		t.Parallel()
+
+		dec := testDecimal{s: "1." + strings.Repeat("1", 40)}
+		buf := new(bytes.Buffer)
+		enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+		enc.SetRegistry(reg)
+		err := enc.Encode(bson.D{{"decimal", dec}})
+		assert.Error(t, err, "expected an encode error")
+	})
+
+	t.Run("time encoding", func(t *testing.T) {
+		t.Parallel()
+
+		timeReg := bson.NewRegistry()
+		RegisterTimeEncoding(timeReg, bson.TimeEncodingMillis)
+
+		tt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+		doc := marshal(t, timeReg, bson.D{{"t", tt}})
+		assert.Equal(t, tt.UnixMilli(), doc.Lookup("t").Int64(), "time mismatch")
+
+		var got struct{ T time.Time }
+		require.NoError(t, unmarshal(timeReg, doc, &got), "Decode error")
+		assert.Equal(t, tt, got.T, "round trip mismatch")
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package bsoncodecs provides codecs for common Go types that the default BSON registry does not
+// handle natively. The codecs are registered on a bson.Registry, which can be configured on a
+// mongo.Client with the SetRegistry option. See the bson docs for more details on registries.
+//
+// To avoid adding dependencies to the driver, the codecs for third-party types are generic over
+// the type they are registered for:
+//
+//	reg := bson.NewRegistry()
+//	bsoncodecs.RegisterUUID[uuid.UUID](reg)                       // github.com/google/uuid
+//	bsoncodecs.RegisterDecimal(reg, decimal.NewFromString)        // github.com/shopspring/decimal
+//	bsoncodecs.RegisterText[language.Tag](reg)                    // golang.org/x/text/language
+//	bsoncodecs.RegisterNetIPAddr(reg)
+//	bsoncodecs.RegisterDurationMillis(reg)
+//	bsoncodecs.RegisterTimeEncoding(reg, bson.TimeEncodingRFC3339)
+//
+//	client, err := mongo.Connect(options.Client().SetRegistry(reg))
+//
+// The encodings used are:
+//
+//  1. UUIDs are encoded as BSON binary with subtype 4. Binary values with subtype 3 or 4 and
+//     strings in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form are decoded.
+//
+//  2. Decimals are encoded as BSON decimal128. Decimal128, int32, int64, double, and string
+//     values are decoded.
+//
+//  3. Types that implement encoding.TextMarshaler and encoding.TextUnmarshaler, such as
+//     language.Tag and netip.Addr, are encoded as BSON strings with their text representation.
+//
+//  4. time.Duration values are encoded as BSON int64 values with the number of milliseconds,
+//     truncating any smaller unit. Int32, int64, and double values are decoded.
+//
+//  5. time.Time values are encoded as the BSON type selected by the bson.TimeEncoding passed to
+//     RegisterTimeEncoding. The default time.Time decoder decodes values of all encodings.
+//
+// For all codecs, BSON null and undefined values are decoded as the zero value of the type.
+package bsoncodecs
@@ -0,0 +1,128 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// CanonicalNumbers is the policy that Canonicalize applies to the types of numbers.
+type CanonicalNumbers int
+
+const (
+	// CanonicalNumbersPreserve keeps the type of all numbers.
+	CanonicalNumbersPreserve CanonicalNumbers = iota
+
+	// CanonicalNumbersInt64 converts 32-bit integers to 64-bit integers, so that integers that only
+	// differ by their encoded width are equal. Doubles and Decimal128 values keep their type.
+	CanonicalNumbersInt64
+
+	// CanonicalNumbersByValue converts 32-bit integers, and the doubles and Decimal128 values that
+	// are integers in the range of a 64-bit integer, to 64-bit integers, so that numbers that the
+	// server considers equal, such as 1, NumberLong(1) and 1.0, are equal. Negative zero is
+	// converted to 0. Other doubles and Decimal128 values keep their type, so 1.5 and
+	// NumberDecimal("1.5") are still different.
+	CanonicalNumbersByValue
+)
+
+// String returns the name of the policy.
+func (cn CanonicalNumbers) String() string {
+	switch cn {
+	case CanonicalNumbersPreserve:
+		return "preserve"
+	case CanonicalNumbersInt64:
+		return "int64"
+	case CanonicalNumbersByValue:
+		return "byValue"
+	default:
+		return "CanonicalNumbers(" + strconv.Itoa(int(cn)) + ")"
+	}
+}
+
+// Canonicalize returns a copy of the document in a canonical form, so that documents that only
+// differ by the order of their fields or, depending on numbers, by the types of their numbers are
+// byte-wise equal. The fields of the document and of its embedded documents are sorted by key, in
+// byte-wise order, and the numbers are converted according to numbers. The order of array elements
+// is kept, and their keys are set to their indexes. The scopes of JavaScript code with scope values
+// are not changed. Fields with duplicate keys are kept in their original relative order.
+//
+// Canonicalize returns an error if the document is not valid BSON.
+func Canonicalize(doc Raw, numbers CanonicalNumbers) (Raw, error) {
+	dst, err := appendCanonicalDocument(make([]byte, 0, len(doc)), bsoncore.Document(doc), numbers, false)
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// appendCanonicalDocument appends the canonical form of the document or, if array is true, of the
+// array doc to dst.
+func appendCanonicalDocument(dst []byte, doc bsoncore.Document, numbers CanonicalNumbers, array bool) ([]byte, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	if !array {
+		sort.SliceStable(elems, func(i, j int) bool { return elems[i].Key() < elems[j].Key() })
+	}
+
+	idx, dst := bsoncore.AppendDocumentStart(dst)
+	for i, elem := range elems {
+		key := elem.Key()
+		if array {
+			key = strconv.Itoa(i)
+		}
+
+		v := canonicalNumber(elem.Value(), numbers)
+		dst = bsoncore.AppendHeader(dst, v.Type, key)
+		switch v.Type {
+		case bsoncore.TypeEmbeddedDocument, bsoncore.TypeArray:
+			dst, err = appendCanonicalDocument(dst, v.Data, numbers, v.Type == bsoncore.TypeArray)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			dst = append(dst, v.Data...)
+		}
+	}
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+// canonicalNumber returns v converted according to numbers if it is a number, or v otherwise.
+func canonicalNumber(v bsoncore.Value, numbers CanonicalNumbers) bsoncore.Value {
+	if numbers == CanonicalNumbersPreserve {
+		return v
+	}
+
+	int64Value := func(i int64) bsoncore.Value {
+		return bsoncore.Value{Type: bsoncore.TypeInt64, Data: bsoncore.AppendInt64(nil, i)}
+	}
+	switch v.Type {
+	case bsoncore.TypeInt32:
+		return int64Value(int64(v.Int32()))
+	case bsoncore.TypeDouble:
+		// -2^63 is the only bound that is exactly representable as a double.
+		f := v.Double()
+		if numbers == CanonicalNumbersByValue && f == math.Trunc(f) && f >= math.MinInt64 && f < -math.MinInt64 {
+			return int64Value(int64(f))
+		}
+	case bsoncore.TypeDecimal128:
+		if numbers != CanonicalNumbersByValue {
+			return v
+		}
+		h, l := v.Decimal128()
+		r, err := NewDecimal128(h, l).BigRat()
+		if err == nil && r.IsInt() && r.Num().IsInt64() {
+			return int64Value(r.Num().Int64())
+		}
+	}
+	return v
+}
@@ -0,0 +1,125 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	marshal := func(t *testing.T, d D) Raw {
+		t.Helper()
+		b, err := Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	dec := func(t *testing.T, s string) Decimal128 {
+		t.Helper()
+		d, err := ParseDecimal128(s)
+		require.NoError(t, err, "ParseDecimal128 error: %v", err)
+		return d
+	}
+
+	t.Run("sorts keys", func(t *testing.T) {
+		doc := marshal(t, D{
+			{"b", int32(1)},
+			{"a", D{{"z", true}, {"y", A{D{{"n", 1}, {"m", 2}}, "x"}}}},
+			{"b", "dup"},
+		})
+		got, err := Canonicalize(doc, CanonicalNumbersPreserve)
+		require.NoError(t, err, "Canonicalize error: %v", err)
+
+		want := marshal(t, D{
+			{"a", D{{"y", A{D{{"m", 2}, {"n", 1}}, "x"}}, {"z", true}}},
+			{"b", int32(1)},
+			{"b", "dup"},
+		})
+		assert.Equal(t, want, got, "expected %v, got %v", want, got)
+	})
+	t.Run("numbers", func(t *testing.T) {
+		doc := marshal(t, D{
+			{"i32", int32(1)},
+			{"i64", int64(2)},
+			{"double", 3.0},
+			{"fraction", 3.5},
+			{"negZero", math.Copysign(0, -1)},
+			{"large", 1e19},
+			{"nan", math.NaN()},
+			{"decimal", dec(t, "4.00")},
+			{"decimalFraction", dec(t, "4.5")},
+			{"nested", A{int32(5), 6.0}},
+		})
+
+		testCases := []struct {
+			numbers CanonicalNumbers
+			want    D
+		}{
+			{CanonicalNumbersPreserve, D{
+				{"decimal", dec(t, "4.00")},
+				{"decimalFraction", dec(t, "4.5")},
+				{"double", 3.0},
+				{"fraction", 3.5},
+				{"i32", int32(1)},
+				{"i64", int64(2)},
+				{"large", 1e19},
+				{"nan", math.NaN()},
+				{"negZero", math.Copysign(0, -1)},
+				{"nested", A{int32(5), 6.0}},
+			}},
+			{CanonicalNumbersInt64, D{
+				{"decimal", dec(t, "4.00")},
+				{"decimalFraction", dec(t, "4.5")},
+				{"double", 3.0},
+				{"fraction", 3.5},
+				{"i32", int64(1)},
+				{"i64", int64(2)},
+				{"large", 1e19},
+				{"nan", math.NaN()},
+				{"negZero", math.Copysign(0, -1)},
+				{"nested", A{int64(5), 6.0}},
+			}},
+			{CanonicalNumbersByValue, D{
+				{"decimal", int64(4)},
+				{"decimalFraction", dec(t, "4.5")},
+				{"double", int64(3)},
+				{"fraction", 3.5},
+				{"i32", int64(1)},
+				{"i64", int64(2)},
+				{"large", 1e19},
+				{"nan", math.NaN()},
+				{"negZero", int64(0)},
+				{"nested", A{int64(5), int64(6)}},
+			}},
+		}
+		for _, tc := range testCases {
+			tc := tc // Capture range variable.
+
+			t.Run(tc.numbers.String(), func(t *testing.T) {
+				got, err := Canonicalize(doc, tc.numbers)
+				require.NoError(t, err, "Canonicalize error: %v", err)
+				want := marshal(t, tc.want)
+				assert.True(t, bytes.Equal(want, got), "expected %v, got %v", want, got)
+			})
+		}
+	})
+	t.Run("equal documents", func(t *testing.T) {
+		a, err := Canonicalize(marshal(t, D{{"x", 1.0}, {"y", D{{"b", int64(2)}, {"a", "s"}}}}), CanonicalNumbersByValue)
+		require.NoError(t, err, "Canonicalize error: %v", err)
+		b, err := Canonicalize(marshal(t, D{{"y", D{{"a", "s"}, {"b", int32(2)}}}, {"x", int32(1)}}), CanonicalNumbersByValue)
+		require.NoError(t, err, "Canonicalize error: %v", err)
+		assert.True(t, bytes.Equal(a, b), "expected canonical documents to be equal: %v, %v", a, b)
+	})
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := Canonicalize(Raw{0x05, 0x00}, CanonicalNumbersPreserve)
+		assert.Error(t, err, "expected an error for an invalid document")
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// decimal128FieldCodec is the codec used for struct fields with the "decimal128" struct tag
+// option. It encodes float32, float64, and string fields, and pointers to them, as BSON
+// decimal128 values and decodes BSON decimal128 values into them. Values of other BSON types are
+// decoded with the decoder registered for the type of the field.
+type decimal128FieldCodec struct {
+	// fallback is the decoder registered for the type of the field.
+	fallback ValueDecoder
+}
+
+// decimal128FieldKind reports whether a struct field of type t can have the "decimal128" struct
+// tag option.
+func decimal128FieldKind(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// EncodeValue implements the ValueEncoder interface.
+func (dc *decimal128FieldCodec) EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return vw.WriteNull()
+		}
+		val = val.Elem()
+	}
+
+	var s string
+	switch val.Kind() {
+	case reflect.Float32:
+		s = strconv.FormatFloat(val.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		s = strconv.FormatFloat(val.Float(), 'g', -1, 64)
+	case reflect.String:
+		s = val.String()
+	default:
+		return ValueEncoderError{
+			Name:     "Decimal128FieldEncodeValue",
+			Kinds:    []reflect.Kind{reflect.Float32, reflect.Float64, reflect.String},
+			Received: val,
+		}
+	}
+
+	d, err := ParseDecimal128(s)
+	if err != nil {
+		return fmt.Errorf("cannot encode %q as a decimal128: %w", s, err)
+	}
+	return vw.WriteDecimal128(d)
+}
+
+// DecodeValue implements the ValueDecoder interface.
+func (dc *decimal128FieldCodec) DecodeValue(dctx DecodeContext, vr ValueReader, val reflect.Value) error {
+	if vr.Type() != TypeDecimal128 {
+		if dc.fallback == nil {
+			return errNoDecoder{Type: val.Type()}
+		}
+		return dc.fallback.DecodeValue(dctx, vr, val)
+	}
+
+	d, err := vr.ReadDecimal128()
+	if err != nil {
+		return err
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Float32, reflect.Float64:
+		bitSize := 64
+		if val.Kind() == reflect.Float32 {
+			bitSize = 32
+		}
+		f, err := decimal128ToFloat(d, bitSize, dctx.truncate)
+		if err != nil {
+			return err
+		}
+		val.SetFloat(f)
+	case reflect.String:
+		val.SetString(d.String())
+	default:
+		return ValueDecoderError{
+			Name:     "Decimal128FieldDecodeValue",
+			Kinds:    []reflect.Kind{reflect.Float32, reflect.Float64, reflect.String},
+			Received: val,
+		}
+	}
+	return nil
+}
+
+// decimal128ToFloat converts d to a float with the given bit size. It returns an error if d is
+// out of the range of the float or, unless truncate is true, if the float does not convert back
+// to the same decimal value.
+func decimal128ToFloat(d Decimal128, bitSize int, truncate bool) (float64, error) {
+	s := d.String()
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("cannot decode decimal128 %s into a float%d: %w", s, bitSize, err)
+	}
+	if truncate || math.IsNaN(f) || math.IsInf(f, 0) {
+		return f, nil
+	}
+
+	want, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("cannot decode decimal128 %s into a float%d", s, bitSize)
+	}
+	got, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	if got == nil || got.Cmp(want) != 0 {
+		return 0, fmt.Errorf("decimal128 %s cannot be represented exactly as a float%d; "+
+			"use the truncate struct tag option to allow loss of precision", s, bitSize)
+	}
+	return f, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestDecimal128FieldCodec(t *testing.T) {
+	type priced struct {
+		Price    float64  `bson:"price,decimal128"`
+		Discount float32  `bson:"discount,decimal128"`
+		Amount   string   `bson:"amount,decimal128"`
+		Tax      *float64 `bson:"tax,decimal128"`
+		Approx   float64  `bson:"approx,decimal128,truncate"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		tax := 0.07
+		want := priced{Price: 19.99, Discount: 0.25, Amount: "1234567890.123456789", Tax: &tax, Approx: 0.1}
+		b, err := Marshal(want)
+		require.NoError(t, err, "Marshal error")
+
+		doc := Raw(b)
+		for _, key := range []string{"price", "discount", "amount", "tax", "approx"} {
+			assert.Equal(t, TypeDecimal128, doc.Lookup(key).Type, "expected %q to be a decimal128", key)
+		}
+		assert.Equal(t, "19.99", doc.Lookup("price").Decimal128().String(), "price mismatch")
+		assert.Equal(t, "1234567890.123456789", doc.Lookup("amount").Decimal128().String(), "amount mismatch")
+
+		var got priced
+		require.NoError(t, Unmarshal(b, &got), "Unmarshal error")
+		assert.Equal(t, want, got, "round trip mismatch")
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		b, err := Marshal(priced{Amount: "0"})
+		require.NoError(t, err, "Marshal error")
+		assert.Equal(t, TypeNull, Raw(b).Lookup("tax").Type, "expected a nil pointer to be null")
+
+		got := priced{Tax: new(float64)}
+		require.NoError(t, Unmarshal(b, &got), "Unmarshal error")
+		assert.Nil(t, got.Tax, "expected null to be decoded as a nil pointer")
+	})
+
+	t.Run("other BSON types", func(t *testing.T) {
+		b, err := Marshal(D{{"price", 2.5}, {"amount", "12.50"}})
+		require.NoError(t, err, "Marshal error")
+
+		var got priced
+		require.NoError(t, Unmarshal(b, &got), "Unmarshal error")
+		assert.Equal(t, 2.5, got.Price, "price mismatch")
+		assert.Equal(t, "12.50", got.Amount, "amount mismatch")
+	})
+
+	t.Run("invalid string", func(t *testing.T) {
+		_, err := Marshal(priced{Amount: "twelve"})
+		assert.Error(t, err, "expected an error for a string that is not a decimal")
+	})
+
+	decodeErrors := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"precision loss", "price", "0.12345678901234567890123"},
+		{"float32 precision loss", "discount", "0.1234567890"},
+		{"float32 overflow", "discount", "1E+39"},
+		{"float64 overflow", "price", "1E+309"},
+	}
+	for _, tc := range decodeErrors {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := ParseDecimal128(tc.value)
+			require.NoError(t, err, "ParseDecimal128 error")
+			b, err := Marshal(D{{tc.key, d}})
+			require.NoError(t, err, "Marshal error")
+
+			var got priced
+			assert.Error(t, Unmarshal(b, &got), "expected a decode error")
+		})
+	}
+
+	t.Run("truncate", func(t *testing.T) {
+		d, err := ParseDecimal128("0.12345678901234567890123")
+		require.NoError(t, err, "ParseDecimal128 error")
+		b, err := Marshal(D{{"approx", d}})
+		require.NoError(t, err, "Marshal error")
+
+		var got priced
+		require.NoError(t, Unmarshal(b, &got), "Unmarshal error")
+		assert.InDelta(t, 0.123456789012345678, got.Approx, 1e-17, "approx mismatch")
+	})
+
+	t.Run("unsupported field type", func(t *testing.T) {
+		type invalid struct {
+			N int `bson:"n,decimal128"`
+		}
+		_, err := Marshal(invalid{N: 1})
+		assert.Error(t, err, "expected an error for an int field")
+	})
+}
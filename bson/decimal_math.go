@@ -0,0 +1,409 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"math/big"
+)
+
+// RoundingMode determines how a Decimal128 value is rounded when a result has more than the 34
+// significant digits of a decimal128 value.
+type RoundingMode int
+
+// These constants are the rounding modes of Decimal128 conversions and arithmetic.
+const (
+	// RoundHalfEven rounds to the nearest value, and ties to the value with an even last digit.
+	// It is the rounding mode of IEEE 754 and of the server.
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest value, and ties away from zero.
+	RoundHalfUp
+
+	// RoundDown rounds toward zero, i.e. truncates.
+	RoundDown
+
+	// RoundUp rounds away from zero.
+	RoundUp
+
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+)
+
+// decimal128Digits is the maximum number of significant digits of a decimal128 value.
+const decimal128Digits = 34
+
+var maxCoefficient = new(big.Int).Exp(ten, big.NewInt(decimal128Digits), nil)
+
+// BigRat returns the value of d as a big.Rat. It returns ErrParseNaN, ErrParseInf or
+// ErrParseNegInf if d is not finite. Negative zero is returned as zero.
+func (d Decimal128) BigRat() (*big.Rat, error) {
+	bi, exp, err := d.BigInt()
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(big.Rat).SetInt(bi)
+	if exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(exp)))
+	} else if exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-exp)))
+	}
+	return r, nil
+}
+
+// BigFloat returns the value of d as a big.Float with a precision of prec bits, rounded to the
+// nearest even value. A prec of 0 means a precision of 128 bits. Infinite values are returned as
+// infinite big.Float values. It returns ErrParseNaN if d is NaN.
+func (d Decimal128) BigFloat(prec uint) (*big.Float, error) {
+	if prec == 0 {
+		prec = 128
+	}
+	f := new(big.Float).SetPrec(prec)
+
+	switch {
+	case d.IsNaN():
+		return nil, ErrParseNaN
+	case d.IsInf() != 0:
+		return f.SetInf(d.IsInf() < 0), nil
+	}
+
+	r, err := d.BigRat()
+	if err != nil {
+		return nil, err
+	}
+	f.SetRat(r)
+	if d.isNeg() && r.Sign() == 0 {
+		f.Neg(f)
+	}
+	return f, nil
+}
+
+// ParseDecimal128FromBigRat converts r to a Decimal128, rounded with mode to 34 significant
+// digits. Values too large for a Decimal128 are converted to an infinity or to the largest finite
+// value, depending on mode. The returned accuracy reports whether the Decimal128 is below, equal
+// to, or above r. Exact values are converted without trailing zeros after the decimal point, e.g.
+// 1/2 is converted to 0.5 and 100 to 100.
+func ParseDecimal128FromBigRat(r *big.Rat, mode RoundingMode) (Decimal128, big.Accuracy) {
+	num := new(big.Int).Abs(r.Num())
+	return decimalFromQuo(num, r.Denom(), r.Sign() < 0, mode)
+}
+
+// ParseDecimal128FromBigFloat converts f to a Decimal128 in the same way as
+// ParseDecimal128FromBigRat. Infinite values are converted to infinite Decimal128 values.
+func ParseDecimal128FromBigFloat(f *big.Float, mode RoundingMode) (Decimal128, big.Accuracy) {
+	if f.IsInf() {
+		if f.Signbit() {
+			return dNegInf, big.Exact
+		}
+		return dPosInf, big.Exact
+	}
+
+	r, _ := f.Rat(nil)
+	num := new(big.Int).Abs(r.Num())
+	return decimalFromQuo(num, r.Denom(), f.Signbit(), mode)
+}
+
+// Cmp compares d and o and returns -1 if d < o, 0 if d == o, and +1 if d > o. Values that are
+// equal but have different representations, such as 1.0 and 1.00 or 0 and -0, are equal. NaN is
+// less than any other value and equal to NaN, as in the sort order of the server.
+func (d Decimal128) Cmp(o Decimal128) int {
+	switch {
+	case d.IsNaN() && o.IsNaN():
+		return 0
+	case d.IsNaN():
+		return -1
+	case o.IsNaN():
+		return 1
+	case d.IsInf() != 0 || o.IsInf() != 0:
+		return cmpInt(d.IsInf(), o.IsInf())
+	}
+
+	dr, _ := d.BigRat()
+	or, _ := o.BigRat()
+	return dr.Cmp(or)
+}
+
+// Neg returns d with its sign inverted. The negation of NaN is NaN.
+func (d Decimal128) Neg() Decimal128 {
+	if d.IsNaN() {
+		return d
+	}
+	return Decimal128{h: d.h ^ 1<<63, l: d.l}
+}
+
+// Add returns the sum of d and o, rounded with mode to 34 significant digits as specified by
+// IEEE 754. A sum too large for a Decimal128 is an infinity or the largest finite value, depending
+// on mode. The sum with NaN, and the sum of infinities of opposite signs, are NaN.
+func (d Decimal128) Add(o Decimal128, mode RoundingMode) Decimal128 {
+	switch {
+	case d.IsNaN() || o.IsNaN():
+		return dNaN
+	case d.IsInf() != 0 && o.IsInf() != 0 && d.IsInf() != o.IsInf():
+		return dNaN
+	case d.IsInf() != 0:
+		return d
+	case o.IsInf() != 0:
+		return o
+	}
+
+	dc, de, dn := d.finite()
+	oc, oe, on := o.finite()
+
+	// Align the coefficients to the smaller exponent, which is the exponent of the exact sum.
+	exp := de
+	if oe < exp {
+		exp = oe
+	}
+	dc.Mul(dc, pow10(de-exp))
+	oc.Mul(oc, pow10(oe-exp))
+	if dn {
+		dc.Neg(dc)
+	}
+	if on {
+		oc.Neg(oc)
+	}
+	sum := dc.Add(dc, oc)
+
+	var neg bool
+	switch sum.Sign() {
+	case -1:
+		neg = true
+	case 0:
+		// The sum of zeros of the same sign keeps that sign. Other zero sums are positive, or
+		// negative when rounding toward negative infinity.
+		neg = (dn && on) || (dn != on && mode == RoundFloor)
+	}
+	d128, _ := decimalFromCoefficient(sum.Abs(sum), exp, neg, mode)
+	return d128
+}
+
+// Sub returns the difference of d and o, rounded in the same way as Add.
+func (d Decimal128) Sub(o Decimal128, mode RoundingMode) Decimal128 {
+	return d.Add(o.Neg(), mode)
+}
+
+// Mul returns the product of d and o, rounded with mode to 34 significant digits as specified by
+// IEEE 754. A product too large for a Decimal128 is an infinity or the largest finite value,
+// depending on mode. The product with NaN, and the product of an infinity and zero, are NaN.
+func (d Decimal128) Mul(o Decimal128, mode RoundingMode) Decimal128 {
+	neg := d.isNeg() != o.isNeg()
+	switch {
+	case d.IsNaN() || o.IsNaN():
+		return dNaN
+	case d.IsInf() != 0 || o.IsInf() != 0:
+		if d.isZeroValue() || o.isZeroValue() {
+			return dNaN
+		}
+		if neg {
+			return dNegInf
+		}
+		return dPosInf
+	}
+
+	dc, de, _ := d.finite()
+	oc, oe, _ := o.finite()
+	d128, _ := decimalFromCoefficient(dc.Mul(dc, oc), de+oe, neg, mode)
+	return d128
+}
+
+// isNeg returns whether the sign bit of d is set.
+func (d Decimal128) isNeg() bool {
+	return d.h>>63 == 1
+}
+
+// isZeroValue returns whether d is a zero of any sign and exponent.
+func (d Decimal128) isZeroValue() bool {
+	bi, _, err := d.BigInt()
+	return err == nil && bi.Sign() == 0
+}
+
+// finite returns the absolute coefficient, the exponent and the sign of a finite d.
+func (d Decimal128) finite() (*big.Int, int, bool) {
+	bi, exp, _ := d.BigInt()
+	return bi.Abs(bi), exp, d.isNeg()
+}
+
+// decimalFromQuo converts the quotient num/den of a non-negative num and a positive den to a
+// Decimal128 with the sign neg.
+func decimalFromQuo(num, den *big.Int, neg bool, mode RoundingMode) (Decimal128, big.Accuracy) {
+	if num.Sign() == 0 {
+		d128, _ := decimalFromCoefficient(num, 0, neg, mode)
+		return d128, big.Exact
+	}
+
+	// Choose the exponent of the result so that the quotient has 34 digits, or fewer for subnormal
+	// values, to round it once.
+	exp := numDigits(num) - numDigits(den) - decimal128Digits
+	if exp < MinDecimal128Exp {
+		exp = MinDecimal128Exp
+	}
+	q, r := new(big.Int), new(big.Int)
+	for {
+		n, d := num, den
+		if exp > 0 {
+			d = new(big.Int).Mul(den, pow10(exp))
+		} else if exp < 0 {
+			n = new(big.Int).Mul(num, pow10(-exp))
+		}
+		q.QuoRem(n, d, r)
+		if q.Cmp(maxCoefficient) < 0 {
+			acc := roundQuotient(q, r, d, neg, mode)
+			if acc == 0 {
+				// Use the smallest non-negative exponent for exact values.
+				for exp < 0 && q.Sign() != 0 && new(big.Int).Rem(q, ten).Sign() == 0 {
+					q.Quo(q, ten)
+					exp++
+				}
+			}
+			return decimalFromRounded(q, exp, neg, mode, acc)
+		}
+		exp++
+	}
+}
+
+// decimalFromCoefficient converts the exact value coef * 10^exp of a non-negative coef to a
+// Decimal128 with the sign neg, rounding coef to 34 digits and to the minimum exponent.
+func decimalFromCoefficient(coef *big.Int, exp int, neg bool, mode RoundingMode) (Decimal128, big.Accuracy) {
+	drop := numDigits(coef) - decimal128Digits
+	if exp+drop < MinDecimal128Exp {
+		drop = MinDecimal128Exp - exp
+	}
+
+	var acc int
+	if drop > 0 {
+		d := pow10(drop)
+		q, r := new(big.Int).QuoRem(coef, d, new(big.Int))
+		acc = roundQuotient(q, r, d, neg, mode)
+		coef = q
+		exp += drop
+	}
+	return decimalFromRounded(coef, exp, neg, mode, acc)
+}
+
+// decimalFromRounded encodes a rounded non-negative coef of at most 34 digits, or exactly 10^34
+// after rounding up, and the exponent exp as a Decimal128 with the sign neg. acc is the accuracy
+// of the magnitude of the rounded value: -1 if it was truncated, 0 if it is exact and +1 if it was
+// incremented.
+func decimalFromRounded(coef *big.Int, exp int, neg bool, mode RoundingMode, acc int) (Decimal128, big.Accuracy) {
+	if coef.Cmp(maxCoefficient) == 0 {
+		coef = new(big.Int).Quo(coef, ten)
+		exp++
+	}
+
+	if exp > MaxDecimal128Exp {
+		if coef.Sign() == 0 {
+			exp = MaxDecimal128Exp
+		}
+		// Clamp the exponent if the coefficient has room for the trailing zeros.
+		for exp > MaxDecimal128Exp {
+			next := new(big.Int).Mul(coef, ten)
+			if next.Cmp(maxCoefficient) >= 0 {
+				return decimalOverflow(neg, mode)
+			}
+			coef = next
+			exp--
+		}
+	}
+
+	signed := coef
+	if neg {
+		signed = new(big.Int).Neg(coef)
+	}
+	d128, _ := ParseDecimal128FromBigInt(signed, exp)
+	if neg {
+		d128.h |= 1 << 63
+	}
+	return d128, signedAccuracy(acc, neg)
+}
+
+// decimalOverflow returns the value of an overflow with the sign neg, which is an infinity or the
+// largest finite value depending on mode.
+func decimalOverflow(neg bool, mode RoundingMode) (Decimal128, big.Accuracy) {
+	toInf := true
+	switch mode {
+	case RoundDown:
+		toInf = false
+	case RoundCeiling:
+		toInf = !neg
+	case RoundFloor:
+		toInf = neg
+	}
+
+	if toInf {
+		if neg {
+			return dNegInf, big.Below
+		}
+		return dPosInf, big.Above
+	}
+
+	maxFinite := new(big.Int).Sub(maxCoefficient, big.NewInt(1))
+	if neg {
+		maxFinite.Neg(maxFinite)
+	}
+	d128, _ := ParseDecimal128FromBigInt(maxFinite, MaxDecimal128Exp)
+	return d128, signedAccuracy(-1, neg)
+}
+
+// roundQuotient rounds the non-negative quotient q with remainder r of a division by d in place,
+// and returns -1 if q was truncated, 0 if the division was exact and +1 if q was incremented.
+func roundQuotient(q, r, d *big.Int, neg bool, mode RoundingMode) int {
+	if r.Sign() == 0 {
+		return 0
+	}
+
+	var inc bool
+	switch mode {
+	case RoundHalfEven:
+		half := new(big.Int).Lsh(r, 1).Cmp(d)
+		inc = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case RoundHalfUp:
+		inc = new(big.Int).Lsh(r, 1).Cmp(d) >= 0
+	case RoundUp:
+		inc = true
+	case RoundCeiling:
+		inc = !neg
+	case RoundFloor:
+		inc = neg
+	}
+
+	if !inc {
+		return -1
+	}
+	q.Add(q, big.NewInt(1))
+	return 1
+}
+
+// signedAccuracy converts the accuracy of a magnitude to the accuracy of a value with the sign neg.
+func signedAccuracy(acc int, neg bool) big.Accuracy {
+	if neg {
+		acc = -acc
+	}
+	return big.Accuracy(acc)
+}
+
+// pow10 returns 10^n for a non-negative n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+// numDigits returns the number of decimal digits of a non-negative x, which is 1 for 0.
+func numDigits(x *big.Int) int {
+	return len(x.Text(10))
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func mustParseDecimal128(t *testing.T, s string) Decimal128 {
+	t.Helper()
+
+	d, err := ParseDecimal128(s)
+	require.NoError(t, err, "ParseDecimal128(%q) error", s)
+	return d
+}
+
+func TestDecimal128_BigRat(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{s: "12345", want: "12345/1"},
+		{s: "-1.25", want: "-5/4"},
+		{s: "1E+3", want: "1000/1"},
+		{s: "-0", want: "0/1"},
+		{s: "1E-6176", want: "1/1" + strings.Repeat("0", 6176)},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.s, func(t *testing.T) {
+			r, err := mustParseDecimal128(t, c.s).BigRat()
+			require.NoError(t, err, "BigRat error")
+			assert.Equal(t, c.want, r.String(), "BigRat mismatch")
+		})
+	}
+
+	_, err := mustParseDecimal128(t, "NaN").BigRat()
+	assert.ErrorIs(t, err, ErrParseNaN, "expected an error for NaN")
+	_, err = mustParseDecimal128(t, "-Infinity").BigRat()
+	assert.ErrorIs(t, err, ErrParseNegInf, "expected an error for -Infinity")
+}
+
+func TestDecimal128_BigFloat(t *testing.T) {
+	f, err := mustParseDecimal128(t, "-2.5").BigFloat(0)
+	require.NoError(t, err, "BigFloat error")
+	assert.Equal(t, uint(128), f.Prec(), "precision mismatch")
+	assert.Equal(t, "-2.5", f.Text('g', 10), "BigFloat mismatch")
+
+	f, err = mustParseDecimal128(t, "-0").BigFloat(53)
+	require.NoError(t, err, "BigFloat error")
+	assert.True(t, f.Signbit(), "expected negative zero to keep its sign")
+
+	f, err = mustParseDecimal128(t, "Infinity").BigFloat(53)
+	require.NoError(t, err, "BigFloat error")
+	assert.True(t, f.IsInf() && !f.Signbit(), "expected +Inf, got %v", f)
+
+	_, err = mustParseDecimal128(t, "NaN").BigFloat(53)
+	assert.ErrorIs(t, err, ErrParseNaN, "expected an error for NaN")
+}
+
+func TestParseDecimal128FromBigRat(t *testing.T) {
+	cases := []struct {
+		name string
+		r    *big.Rat
+		mode RoundingMode
+		want string
+		acc  big.Accuracy
+	}{
+		{name: "exact fraction", r: big.NewRat(1, 2), want: "0.5", acc: big.Exact},
+		{name: "exact integer", r: big.NewRat(100, 1), want: "100", acc: big.Exact},
+		{name: "zero", r: new(big.Rat), want: "0", acc: big.Exact},
+		{
+			name: "half even",
+			r:    big.NewRat(2, 3),
+			want: "0.6666666666666666666666666666666667",
+			acc:  big.Above,
+		},
+		{
+			name: "down",
+			r:    big.NewRat(2, 3),
+			mode: RoundDown,
+			want: "0.6666666666666666666666666666666666",
+			acc:  big.Below,
+		},
+		{
+			name: "floor negative",
+			r:    big.NewRat(-1, 3),
+			mode: RoundFloor,
+			want: "-0.3333333333333333333333333333333334",
+			acc:  big.Below,
+		},
+		{
+			name: "ceiling negative",
+			r:    big.NewRat(-1, 3),
+			mode: RoundCeiling,
+			want: "-0.3333333333333333333333333333333333",
+			acc:  big.Above,
+		},
+		{
+			name: "overflow",
+			r:    new(big.Rat).SetInt(new(big.Int).Exp(ten, big.NewInt(6145), nil)),
+			want: "Infinity",
+			acc:  big.Above,
+		},
+		{
+			name: "overflow toward zero",
+			r:    new(big.Rat).SetInt(new(big.Int).Exp(ten, big.NewInt(6145), nil)),
+			mode: RoundDown,
+			want: "9.999999999999999999999999999999999E+6144",
+			acc:  big.Below,
+		},
+		{
+			name: "underflow",
+			r:    new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(ten, big.NewInt(6177), nil)),
+			want: "0E-6176",
+			acc:  big.Below,
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			got, acc := ParseDecimal128FromBigRat(c.r, c.mode)
+			assert.Equal(t, c.want, got.String(), "ParseDecimal128FromBigRat mismatch")
+			assert.Equal(t, c.acc, acc, "accuracy mismatch")
+		})
+	}
+}
+
+func TestParseDecimal128FromBigFloat(t *testing.T) {
+	got, acc := ParseDecimal128FromBigFloat(big.NewFloat(0.1), RoundHalfEven)
+	assert.Equal(t, "0.1000000000000000055511151231257827", got.String(), "ParseDecimal128FromBigFloat mismatch")
+	assert.Equal(t, big.Below, acc, "accuracy mismatch")
+
+	got, _ = ParseDecimal128FromBigFloat(new(big.Float).SetInf(true), RoundHalfEven)
+	assert.Equal(t, "-Infinity", got.String(), "expected -Infinity")
+
+	got, _ = ParseDecimal128FromBigFloat(new(big.Float).Neg(new(big.Float)), RoundHalfEven)
+	assert.Equal(t, "-0", got.String(), "expected negative zero")
+}
+
+func TestDecimal128_Cmp(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0", b: "1.00", want: 0},
+		{a: "0", b: "-0", want: 0},
+		{a: "-1", b: "0.5", want: -1},
+		{a: "1E+3", b: "999", want: 1},
+		{a: "NaN", b: "NaN", want: 0},
+		{a: "NaN", b: "-Infinity", want: -1},
+		{a: "Infinity", b: "NaN", want: 1},
+		{a: "Infinity", b: "9.999999999999999999999999999999999E+6144", want: 1},
+		{a: "-Infinity", b: "-Infinity", want: 0},
+	}
+	for _, c := range cases {
+		a, b := mustParseDecimal128(t, c.a), mustParseDecimal128(t, c.b)
+		assert.Equal(t, c.want, a.Cmp(b), "%s.Cmp(%s) mismatch", c.a, c.b)
+		assert.Equal(t, -c.want, b.Cmp(a), "%s.Cmp(%s) mismatch", c.b, c.a)
+	}
+}
+
+func TestDecimal128_Arithmetic(t *testing.T) {
+	cases := []struct {
+		op   string
+		a, b string
+		mode RoundingMode
+		want string
+	}{
+		{op: "add", a: "1.1", b: "2.25", want: "3.35"},
+		{op: "add", a: "1E+2", b: "1", want: "101"},
+		{op: "add", a: "1", b: "-1", want: "0"},
+		{op: "add", a: "1", b: "-1", mode: RoundFloor, want: "-0"},
+		{op: "add", a: "-0", b: "-0", want: "-0"},
+		{op: "add", a: "1.00", b: "-1", want: "0.00"},
+		{op: "add", a: "9999999999999999999999999999999999", b: "1", want: "1.000000000000000000000000000000000E+34"},
+		{op: "add", a: "1234567890123456789012345678901234", b: "0.5", want: "1234567890123456789012345678901234"},
+		{op: "add", a: "1234567890123456789012345678901234", b: "0.5", mode: RoundHalfUp, want: "1234567890123456789012345678901235"},
+		{op: "add", a: "9.999999999999999999999999999999999E+6144", b: "1E+6111", want: "Infinity"},
+		{op: "add", a: "9.999999999999999999999999999999999E+6144", b: "1E+6111", mode: RoundDown, want: "9.999999999999999999999999999999999E+6144"},
+		{op: "add", a: "Infinity", b: "-Infinity", want: "NaN"},
+		{op: "add", a: "Infinity", b: "1", want: "Infinity"},
+		{op: "add", a: "NaN", b: "1", want: "NaN"},
+		{op: "sub", a: "1", b: "0.1", want: "0.9"},
+		{op: "sub", a: "-Infinity", b: "-Infinity", want: "NaN"},
+		{op: "mul", a: "1.5", b: "-2", want: "-3.0"},
+		{op: "mul", a: "-0", b: "5", want: "-0"},
+		{op: "mul", a: "1E+6111", b: "10", want: "1.0E+6112"},
+		{op: "mul", a: "1E+6144", b: "10", want: "Infinity"},
+		{op: "mul", a: "-1E+6144", b: "10", mode: RoundCeiling, want: "-9.999999999999999999999999999999999E+6144"},
+		{op: "mul", a: "1E-6176", b: "0.1", want: "0E-6176"},
+		{op: "mul", a: "1E-6176", b: "0.1", mode: RoundUp, want: "1E-6176"},
+		{op: "mul", a: "3333333333333333333333333333333333", b: "3", want: "9999999999999999999999999999999999"},
+		{op: "mul", a: "3333333333333333333333333333333334", b: "3", want: "1.000000000000000000000000000000000E+34"},
+		{op: "mul", a: "Infinity", b: "-2", want: "-Infinity"},
+		{op: "mul", a: "Infinity", b: "0", want: "NaN"},
+	}
+	for _, c := range cases {
+		a, b := mustParseDecimal128(t, c.a), mustParseDecimal128(t, c.b)
+
+		var got Decimal128
+		switch c.op {
+		case "add":
+			got = a.Add(b, c.mode)
+		case "sub":
+			got = a.Sub(b, c.mode)
+		case "mul":
+			got = a.Mul(b, c.mode)
+		}
+		assert.Equal(t, c.want, got.String(), "%s %s %s mismatch", c.a, c.op, c.b)
+	}
+
+	assert.Equal(t, "-1.5", mustParseDecimal128(t, "1.5").Neg().String(), "Neg mismatch")
+	assert.Equal(t, "NaN", mustParseDecimal128(t, "NaN").Neg().String(), "Neg mismatch")
+}
@@ -0,0 +1,86 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"fmt"
+)
+
+// DecodeLimits are limits on the BSON documents read by a Decoder. A zero limit means no limit.
+type DecodeLimits struct {
+	// MaxDepth is the maximum nesting depth of a document. A document without embedded documents
+	// or arrays has a depth of 1, and each level of embedded documents or arrays adds 1.
+	MaxDepth int
+
+	// MaxArrayLength is the maximum number of elements of an array.
+	MaxArrayLength int
+
+	// MaxDocumentSize is the maximum size in bytes of a top-level document.
+	MaxDocumentSize int
+}
+
+// DefensiveDecodeLimits are limits suitable for decoding untrusted BSON. The maximum depth is the
+// nesting depth the server allows for stored documents and the maximum size is the maximum size of
+// a BSON document stored by the server.
+var DefensiveDecodeLimits = DecodeLimits{
+	MaxDepth:        100,
+	MaxArrayLength:  1 << 20,
+	MaxDocumentSize: 16 * 1024 * 1024,
+}
+
+// DecodeLimit identifies a limit of DecodeLimits.
+type DecodeLimit string
+
+// These constants identify the limits of DecodeLimits.
+const (
+	DecodeLimitDepth        DecodeLimit = "depth"
+	DecodeLimitArrayLength  DecodeLimit = "array length"
+	DecodeLimitDocumentSize DecodeLimit = "document size"
+)
+
+// DecodeLimitError is the error returned by a Decoder when a document exceeds one of its
+// DecodeLimits. The error is returned as soon as the limit is exceeded, before the rest of the
+// document is read.
+type DecodeLimitError struct {
+	// Limit is the limit that was exceeded.
+	Limit DecodeLimit
+
+	// Max is the value of the limit.
+	Max int
+
+	// Actual is the value that exceeded the limit. For arrays, it is the number of elements read
+	// when the limit was exceeded, which is Max+1.
+	Actual int
+}
+
+func (e *DecodeLimitError) Error() string {
+	return fmt.Sprintf("BSON document exceeds the maximum %s of %d: %d", e.Limit, e.Max, e.Actual)
+}
+
+// checkDocumentSize returns a DecodeLimitError if length exceeds the maximum document size.
+func (l *DecodeLimits) checkDocumentSize(length int32) error {
+	if l == nil || l.MaxDocumentSize <= 0 || int64(length) <= int64(l.MaxDocumentSize) {
+		return nil
+	}
+	return &DecodeLimitError{Limit: DecodeLimitDocumentSize, Max: l.MaxDocumentSize, Actual: int(length)}
+}
+
+// checkDepth returns a DecodeLimitError if depth exceeds the maximum depth.
+func (l *DecodeLimits) checkDepth(depth int) error {
+	if l == nil || l.MaxDepth <= 0 || depth <= l.MaxDepth {
+		return nil
+	}
+	return &DecodeLimitError{Limit: DecodeLimitDepth, Max: l.MaxDepth, Actual: depth}
+}
+
+// checkArrayLength returns a DecodeLimitError if length exceeds the maximum array length.
+func (l *DecodeLimits) checkArrayLength(length int) error {
+	if l == nil || l.MaxArrayLength <= 0 || length <= l.MaxArrayLength {
+		return nil
+	}
+	return &DecodeLimitError{Limit: DecodeLimitArrayLength, Max: l.MaxArrayLength, Actual: length}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// nestedDocument returns a document with depth levels of documents embedded under the key "a".
+func nestedDocument(depth int) []byte {
+	doc := bsoncore.BuildDocument(nil, bsoncore.AppendInt32Element(nil, "x", 1))
+	for i := 1; i < depth; i++ {
+		doc = bsoncore.BuildDocument(nil, bsoncore.AppendDocumentElement(nil, "a", doc))
+	}
+	return doc
+}
+
+func TestDecoder_SetLimits(t *testing.T) {
+	array := bsoncore.NewArrayBuilder().AppendInt32(1).AppendInt32(2).AppendInt32(3).Build()
+	arrayDoc := bsoncore.BuildDocument(nil, bsoncore.AppendArrayElement(nil, "arr", array))
+
+	cases := []struct {
+		name   string
+		doc    []byte
+		limits DecodeLimits
+		val    interface{}
+		want   *DecodeLimitError
+	}{
+		{
+			name:   "depth within limit",
+			doc:    nestedDocument(3),
+			limits: DecodeLimits{MaxDepth: 3},
+			val:    &M{},
+		},
+		{
+			name:   "depth exceeded",
+			doc:    nestedDocument(4),
+			limits: DecodeLimits{MaxDepth: 3},
+			val:    &M{},
+			want:   &DecodeLimitError{Limit: DecodeLimitDepth, Max: 3, Actual: 4},
+		},
+		{
+			name:   "depth exceeded by arrays",
+			doc:    arrayDoc,
+			limits: DecodeLimits{MaxDepth: 1},
+			val:    &D{},
+			want:   &DecodeLimitError{Limit: DecodeLimitDepth, Max: 1, Actual: 2},
+		},
+		{
+			name:   "array length within limit",
+			doc:    arrayDoc,
+			limits: DecodeLimits{MaxArrayLength: 3},
+			val:    &struct{ Arr []int32 }{},
+		},
+		{
+			name:   "array length exceeded",
+			doc:    arrayDoc,
+			limits: DecodeLimits{MaxArrayLength: 2},
+			val:    &struct{ Arr []int32 }{},
+			want:   &DecodeLimitError{Limit: DecodeLimitArrayLength, Max: 2, Actual: 3},
+		},
+		{
+			name:   "document size exceeded",
+			doc:    arrayDoc,
+			limits: DecodeLimits{MaxDocumentSize: len(arrayDoc) - 1},
+			val:    &M{},
+			want:   &DecodeLimitError{Limit: DecodeLimitDocumentSize, Max: len(arrayDoc) - 1, Actual: len(arrayDoc)},
+		},
+		{
+			name:   "document size exceeded by Raw",
+			doc:    arrayDoc,
+			limits: DecodeLimits{MaxDocumentSize: len(arrayDoc) - 1},
+			val:    &Raw{},
+			want:   &DecodeLimitError{Limit: DecodeLimitDocumentSize, Max: len(arrayDoc) - 1, Actual: len(arrayDoc)},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dec := NewDecoder(NewDocumentReader(bytes.NewReader(tc.doc)))
+			dec.SetLimits(tc.limits)
+			err := dec.Decode(tc.val)
+			if tc.want == nil {
+				require.NoError(t, err, "Decode error")
+				return
+			}
+
+			var limitErr *DecodeLimitError
+			require.True(t, errors.As(err, &limitErr), "expected a *DecodeLimitError, got %v", err)
+			assert.Equal(t, tc.want, limitErr, "DecodeLimitError mismatch")
+		})
+	}
+}
+
+func TestDecoder_SetLimitsStream(t *testing.T) {
+	// The depth of each document is checked separately when decoding a stream of documents.
+	var stream []byte
+	for i := 0; i < 3; i++ {
+		stream = append(stream, nestedDocument(2)...)
+	}
+
+	dec := NewDecoder(NewDocumentReader(bytes.NewReader(stream)))
+	dec.SetLimits(DecodeLimits{MaxDepth: 2})
+	for i := 0; i < 3; i++ {
+		var m M
+		require.NoError(t, dec.Decode(&m), "Decode error for document %d", i)
+	}
+}
+
+func TestDefensiveDecodeLimits(t *testing.T) {
+	dec := NewDecoder(NewDocumentReader(bytes.NewReader(nestedDocument(101))))
+	dec.SetLimits(DefensiveDecodeLimits)
+
+	var m M
+	err := dec.Decode(&m)
+	var limitErr *DecodeLimitError
+	require.True(t, errors.As(err, &limitErr), "expected a *DecodeLimitError, got %v", err)
+	assert.Equal(t, DecodeLimitDepth, limitErr.Limit, "limit mismatch")
+}
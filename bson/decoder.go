@@ -30,6 +30,10 @@ var decPool = sync.Pool{
 type Decoder struct {
 	dc DecodeContext
 	vr ValueReader
+
+	interner *stringInterner
+	zeroCopy bool
+	limits   *DecodeLimits
 }
 
 // NewDecoder returns a new decoder that reads from vr.
@@ -45,6 +49,11 @@ func NewDecoder(vr ValueReader) *Decoder {
 //
 // See [Unmarshal] for details about BSON unmarshaling behavior.
 func (d *Decoder) Decode(val interface{}) error {
+	if vr, ok := d.vr.(*valueReader); ok && d.limits != nil {
+		vr.limits = d.limits
+		defer func() { vr.limits = nil }()
+	}
+
 	if unmarshaler, ok := val.(Unmarshaler); ok {
 		// TODO(skriptble): Reuse a []byte here and use the AppendDocumentBytes method.
 		buf, err := copyDocumentToBytes(d.vr)
@@ -73,6 +82,17 @@ func (d *Decoder) Decode(val interface{}) error {
 		return err
 	}
 
+	if vr, ok := d.vr.(*valueReader); ok {
+		if d.interner != nil {
+			vr.interner = d.interner
+			defer func() { vr.interner = nil }()
+		}
+		if d.zeroCopy {
+			vr.zeroCopy = true
+			defer func() { vr.zeroCopy = false }()
+		}
+	}
+
 	return decoder.DecodeValue(d.dc, d.vr, rval)
 }
 
@@ -93,6 +113,13 @@ func (d *Decoder) DefaultDocumentM() {
 	d.dc.defaultDocumentType = reflect.TypeOf(M{})
 }
 
+// DefaultDocumentOrderedMap causes the Decoder to always unmarshal documents into the *OrderedMap
+// type, which preserves the order of their fields. This behavior is restricted to data typed as
+// "interface{}" or "map[string]interface{}".
+func (d *Decoder) DefaultDocumentOrderedMap() {
+	d.dc.defaultDocumentType = reflect.TypeOf((*OrderedMap)(nil))
+}
+
 // AllowTruncatingDoubles causes the Decoder to truncate the fractional part of BSON "double" values
 // when attempting to unmarshal them into a Go integer (int, int8, int16, int32, or int64) struct
 // field. The truncation logic does not apply to BSON "decimal128" values.
@@ -129,8 +156,61 @@ func (d *Decoder) ZeroMaps() {
 	d.dc.zeroMaps = true
 }
 
+// InternStrings causes the Decoder to reuse a single string for each distinct document key it reads
+// instead of allocating the same keys again for every document. String values no longer than
+// maxValueLen bytes are interned as well, which helps when decoding many documents with repeated
+// enum-like values. If maxValueLen is 0, only keys are interned. The interned strings are retained
+// by the Decoder, so it should be reused across calls to Decode to benefit from interning.
+//
+// Interning only applies to BSON read by a ValueReader created with NewDocumentReader.
+func (d *Decoder) InternStrings(maxValueLen int) {
+	d.interner = newStringInterner(maxValueLen)
+}
+
+// SetAllocator causes the Decoder to obtain the buffers for Raw values from a. The buffer is
+// allocated when the Raw being decoded into is nil, so previously decoded Raw values are still
+// reused in place. Callers own the returned values and can hand their buffers back with
+// a.Release once they are no longer referenced. A nil Allocator restores the default behavior.
+func (d *Decoder) SetAllocator(a Allocator) {
+	d.dc.allocator = a
+}
+
+// UnsafeZeroCopy causes the Decoder to decode BSON string values and binary data into strings and
+// byte slices that refer to the underlying BSON document instead of copying it, which avoids an
+// allocation for every such field. It only applies to documents read by a ValueReader created with
+// NewDocumentReaderFromBytes.
+//
+// The decoded values share memory with the document, so the document must not be modified or
+// reused, for example by returning it to a buffer pool, while any decoded value is in use. The
+// decoded byte slices must be treated as read-only. Any decoded value keeps the whole document
+// reachable, so retaining a small value can retain a much larger document.
+func (d *Decoder) UnsafeZeroCopy() {
+	d.zeroCopy = true
+}
+
+// SetLimits causes the Decoder to return a *DecodeLimitError as soon as a document exceeds one of
+// the given limits, which protects services that decode untrusted BSON, e.g. from a message queue,
+// from stack exhaustion and excessive memory use. DefensiveDecodeLimits are suitable limits for
+// most such services. The document size limit is checked before the document is read. A
+// document decoded into an Unmarshaler or a Raw value is only checked against the size limit.
+//
+// Limits only apply to BSON read by a ValueReader created with NewDocumentReader.
+func (d *Decoder) SetLimits(limits DecodeLimits) {
+	d.limits = &limits
+}
+
 // ZeroStructs causes the Decoder to delete any existing values from Go structs in the destination
 // value passed to Decode before unmarshaling BSON documents into them.
 func (d *Decoder) ZeroStructs() {
 	d.dc.zeroStructs = true
 }
+
+// UseJSONAndTextMarshalers causes the Decoder to decode values into types that implement
+// json.Unmarshaler or encoding.TextUnmarshaler, but have no other registered decoder, by calling
+// UnmarshalJSON or UnmarshalText. UnmarshalJSON is passed the relaxed Extended JSON representation
+// of the BSON value. UnmarshalText is used for BSON string values if the type does not implement
+// json.Unmarshaler. Decoders registered for the exact type and implementations of Unmarshaler and
+// ValueUnmarshaler take precedence.
+func (d *Decoder) UseJSONAndTextMarshalers() {
+	d.dc.useJSONAndTextMarshalers = true
+}
@@ -41,7 +41,9 @@ func registerDefaultDecoders(reg *Registry) {
 	uintCodec := &uintCodec{}
 
 	reg.RegisterTypeDecoder(tD, ValueDecoderFunc(dDecodeValue))
+	reg.RegisterTypeDecoder(tOrderedMap, ValueDecoderFunc(orderedMapDecodeValue))
 	reg.RegisterTypeDecoder(tBinary, decodeAdapter{binaryDecodeValue, binaryDecodeType})
+	reg.RegisterTypeDecoder(tVector, decodeAdapter{vectorDecodeValue, vectorDecodeType})
 	reg.RegisterTypeDecoder(tUndefined, decodeAdapter{undefinedDecodeValue, undefinedDecodeType})
 	reg.RegisterTypeDecoder(tDateTime, decodeAdapter{dateTimeDecodeValue, dateTimeDecodeType})
 	reg.RegisterTypeDecoder(tNull, decodeAdapter{nullDecodeValue, nullDecodeType})
@@ -162,6 +164,52 @@ func dDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
 	return nil
 }
 
+// orderedMapDecodeValue is the ValueDecoderFunc for OrderedMap. It reuses the storage of the
+// destination OrderedMap.
+func orderedMapDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !val.CanAddr() || !val.CanSet() || val.Type() != tOrderedMap {
+		return ValueDecoderError{Name: "OrderedMapDecodeValue", Types: []reflect.Type{tOrderedMap}, Received: val}
+	}
+
+	m := val.Addr().Interface().(*OrderedMap)
+	switch vrType := vr.Type(); vrType {
+	case Type(0), TypeEmbeddedDocument:
+	case TypeNull:
+		m.reset()
+		return vr.ReadNull()
+	default:
+		return fmt.Errorf("cannot decode %v into an OrderedMap", vrType)
+	}
+
+	dr, err := vr.ReadDocument()
+	if err != nil {
+		return err
+	}
+
+	decoder, err := dc.LookupDecoder(tEmpty)
+	if err != nil {
+		return err
+	}
+
+	m.reset()
+	for {
+		key, elemVr, err := dr.ReadElement()
+		if errors.Is(err, ErrEOD) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		var v interface{}
+		err = decoder.DecodeValue(dc, elemVr, reflect.ValueOf(&v).Elem())
+		if err != nil {
+			return err
+		}
+		m.Set(key, v)
+	}
+	return nil
+}
+
 func booleanDecodeType(_ DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
 	if t.Kind() != reflect.Bool {
 		return emptyValue, ValueDecoderError{
@@ -545,6 +593,56 @@ func binaryDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) erro
 	return nil
 }
 
+func vectorDecodeType(_ DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
+	if t != tVector {
+		return emptyValue, ValueDecoderError{
+			Name:     "VectorDecodeValue",
+			Types:    []reflect.Type{tVector},
+			Received: reflect.Zero(t),
+		}
+	}
+
+	var vec Vector
+	switch vrType := vr.Type(); vrType {
+	case TypeBinary:
+		data, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return emptyValue, err
+		}
+		vec, err = NewVectorFromBinary(Binary{Subtype: subtype, Data: data})
+		if err != nil {
+			return emptyValue, err
+		}
+	case TypeNull:
+		if err := vr.ReadNull(); err != nil {
+			return emptyValue, err
+		}
+	case TypeUndefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return emptyValue, err
+		}
+	default:
+		return emptyValue, fmt.Errorf("cannot decode %v into a Vector", vrType)
+	}
+
+	return reflect.ValueOf(vec), nil
+}
+
+// vectorDecodeValue is the ValueDecoderFunc for Vector.
+func vectorDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tVector {
+		return ValueDecoderError{Name: "VectorDecodeValue", Types: []reflect.Type{tVector}, Received: val}
+	}
+
+	elem, err := vectorDecodeType(dc, vr, tVector)
+	if err != nil {
+		return err
+	}
+
+	val.Set(elem)
+	return nil
+}
+
 func undefinedDecodeType(_ DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
 	if t != tUndefined {
 		return emptyValue, ValueDecoderError{
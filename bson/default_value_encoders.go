@@ -64,12 +64,14 @@ func registerDefaultEncoders(reg *Registry) {
 	reg.RegisterTypeEncoder(tEmpty, &emptyInterfaceCodec{})
 	reg.RegisterTypeEncoder(tCoreArray, &arrayCodec{})
 	reg.RegisterTypeEncoder(tOID, ValueEncoderFunc(objectIDEncodeValue))
+	reg.RegisterTypeEncoder(tOrderedMap, ValueEncoderFunc(orderedMapEncodeValue))
 	reg.RegisterTypeEncoder(tDecimal, ValueEncoderFunc(decimal128EncodeValue))
 	reg.RegisterTypeEncoder(tJSONNumber, ValueEncoderFunc(jsonNumberEncodeValue))
 	reg.RegisterTypeEncoder(tURL, ValueEncoderFunc(urlEncodeValue))
 	reg.RegisterTypeEncoder(tJavaScript, ValueEncoderFunc(javaScriptEncodeValue))
 	reg.RegisterTypeEncoder(tSymbol, ValueEncoderFunc(symbolEncodeValue))
 	reg.RegisterTypeEncoder(tBinary, ValueEncoderFunc(binaryEncodeValue))
+	reg.RegisterTypeEncoder(tVector, ValueEncoderFunc(vectorEncodeValue))
 	reg.RegisterTypeEncoder(tUndefined, ValueEncoderFunc(undefinedEncodeValue))
 	reg.RegisterTypeEncoder(tDateTime, ValueEncoderFunc(dateTimeEncodeValue))
 	reg.RegisterTypeEncoder(tNull, ValueEncoderFunc(nullEncodeValue))
@@ -159,6 +161,25 @@ func objectIDEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) err
 	return vw.WriteObjectID(val.Interface().(ObjectID))
 }
 
+// orderedMapEncodeValue is the ValueEncoderFunc for OrderedMap.
+func orderedMapEncodeValue(ec EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tOrderedMap {
+		return ValueEncoderError{Name: "OrderedMapEncodeValue", Types: []reflect.Type{tOrderedMap}, Received: val}
+	}
+
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	m := val.Interface().(OrderedMap)
+	for _, e := range m.elems {
+		if err := encodeElement(ec, dw, e); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
 // decimal128EncodeValue is the ValueEncoderFunc for Decimal128.
 func decimal128EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
 	if !val.IsValid() || val.Type() != tDecimal {
@@ -364,6 +385,16 @@ func binaryEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error
 	return vw.WriteBinaryWithSubtype(b.Data, b.Subtype)
 }
 
+// vectorEncodeValue is the ValueEncoderFunc for Vector.
+func vectorEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tVector {
+		return ValueEncoderError{Name: "VectorEncodeValue", Types: []reflect.Type{tVector}, Received: val}
+	}
+	b := val.Interface().(Vector).Binary()
+
+	return vw.WriteBinaryWithSubtype(b.Data, b.Subtype)
+}
+
 // undefinedEncodeValue is the ValueEncoderFunc for Undefined.
 func undefinedEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
 	if !val.IsValid() || val.Type() != tUndefined {
@@ -0,0 +1,184 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// DiffOp is the operation of a DiffEntry.
+type DiffOp string
+
+// These constants are the operations of a DiffEntry.
+const (
+	// DiffAdd means that the value only exists in the second document.
+	DiffAdd DiffOp = "add"
+
+	// DiffRemove means that the value only exists in the first document.
+	DiffRemove DiffOp = "remove"
+
+	// DiffReplace means that the value differs between the documents.
+	DiffReplace DiffOp = "replace"
+)
+
+// DiffEntry is a difference between two documents, see Diff.
+type DiffEntry struct {
+	Op DiffOp
+
+	// Path holds the keys leading from the root document to the value, with array indexes as
+	// their decimal string representation, like the path of a WalkFunc.
+	Path []string
+
+	// Old is the value in the first document, or the zero RawValue for DiffAdd.
+	Old RawValue
+
+	// New is the value in the second document, or the zero RawValue for DiffRemove.
+	New RawValue
+}
+
+// Pointer returns the path of the entry as a JSON Pointer (RFC 6901), such as "/a/b/3/c", which
+// can be passed to Raw.LookupPath.
+func (e DiffEntry) Pointer() string {
+	var sb strings.Builder
+	for _, key := range e.Path {
+		sb.WriteByte('/')
+		sb.WriteString(escapePointerToken(key))
+	}
+	return sb.String()
+}
+
+// String returns the entry as a single line, such as `~ /a/b: 1 -> 2`, `+ /c: "x"`, or `- /d: true`,
+// with the values as Extended JSON.
+func (e DiffEntry) String() string {
+	switch e.Op {
+	case DiffAdd:
+		return "+ " + e.Pointer() + ": " + e.New.String()
+	case DiffRemove:
+		return "- " + e.Pointer() + ": " + e.Old.String()
+	default:
+		return "~ " + e.Pointer() + ": " + e.Old.String() + " -> " + e.New.String()
+	}
+}
+
+// DocumentDiff is the list of differences between two documents returned by Diff.
+type DocumentDiff []DiffEntry
+
+// String returns the entries of the diff, one per line.
+func (d DocumentDiff) String() string {
+	lines := make([]string, 0, len(d))
+	for _, e := range d {
+		lines = append(lines, e.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the differences that turn document a into document b, or an empty DocumentDiff if
+// the documents are equal. Fields are matched by key regardless of their order, and the entries
+// are sorted by key at each level so that the diff is deterministic. Embedded documents and arrays
+// are compared recursively, and array elements are matched by index, so that elements beyond the
+// length of the other array are added or removed. Other values are equal if they have the same
+// type and the same bytes, so 1 and NumberLong(1) differ; compare the documents returned by
+// Canonicalize with CanonicalNumbersByValue to ignore the types of numbers. If a key is duplicated
+// in a document, only its first value is compared.
+//
+// The values of the entries refer to the bytes of a and b. Diff returns an error if a document is
+// not valid BSON.
+func Diff(a, b Raw) (DocumentDiff, error) {
+	d := DocumentDiff{}
+	if err := diffDocuments(&d, nil, bsoncore.Document(a), bsoncore.Document(b), false); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// diffDocuments appends the differences between the documents or, if array is true, the arrays a
+// and b at path to d.
+func diffDocuments(d *DocumentDiff, path []string, a, b bsoncore.Document, array bool) error {
+	aKeys, aValues, err := diffFields(a)
+	if err != nil {
+		return err
+	}
+	bKeys, bValues, err := diffFields(b)
+	if err != nil {
+		return err
+	}
+
+	keys := aKeys
+	for _, key := range bKeys {
+		if _, ok := aValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	if array {
+		// The keys of arrays are their indexes, which are sorted numerically.
+		sort.SliceStable(keys, func(i, j int) bool {
+			ki, _ := strconv.Atoi(keys[i])
+			kj, _ := strconv.Atoi(keys[j])
+			return ki < kj
+		})
+	} else {
+		sort.Strings(keys)
+	}
+
+	for _, key := range keys {
+		av, inA := aValues[key]
+		bv, inB := bValues[key]
+		elemPath := append(path[:len(path):len(path)], key)
+		switch {
+		case !inA:
+			*d = append(*d, DiffEntry{Op: DiffAdd, Path: elemPath, New: convertFromCoreValue(bv)})
+		case !inB:
+			*d = append(*d, DiffEntry{Op: DiffRemove, Path: elemPath, Old: convertFromCoreValue(av)})
+		case av.Type == bv.Type && (av.Type == bsoncore.TypeEmbeddedDocument || av.Type == bsoncore.TypeArray):
+			err := diffDocuments(d, elemPath, av.Data, bv.Data, av.Type == bsoncore.TypeArray)
+			if err != nil {
+				return err
+			}
+		case !av.Equal(bv):
+			*d = append(*d, DiffEntry{
+				Op:   DiffReplace,
+				Path: elemPath,
+				Old:  convertFromCoreValue(av),
+				New:  convertFromCoreValue(bv),
+			})
+		}
+	}
+	return nil
+}
+
+// diffFields returns the keys of the document in order, without duplicates, and the first value
+// for each key.
+func diffFields(doc bsoncore.Document) ([]string, map[string]bsoncore.Value, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, 0, len(elems))
+	values := make(map[string]bsoncore.Value, len(elems))
+	for _, elem := range elems {
+		key := elem.Key()
+		if _, ok := values[key]; ok {
+			continue
+		}
+		keys = append(keys, key)
+		values[key] = elem.Value()
+	}
+	return keys, values, nil
+}
+
+// escapePointerToken replaces "~" and "/" in a JSON Pointer reference token with the "~0" and "~1"
+// escape sequences.
+func escapePointerToken(tok string) string {
+	if !strings.ContainsAny(tok, "~/") {
+		return tok
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+}
@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestDiff(t *testing.T) {
+	marshal := func(t *testing.T, d D) Raw {
+		t.Helper()
+		b, err := Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+
+	t.Run("equal documents", func(t *testing.T) {
+		a := marshal(t, D{{"a", int32(1)}, {"b", A{"x", D{{"c", true}}}}})
+		b := marshal(t, D{{"b", A{"x", D{{"c", true}}}}, {"a", int32(1)}})
+		d, err := Diff(a, b)
+		require.NoError(t, err, "Diff error: %v", err)
+		assert.Len(t, d, 0, "expected no differences, got %v", d)
+	})
+	t.Run("differences", func(t *testing.T) {
+		a := marshal(t, D{
+			{"z", "removed"},
+			{"n", int32(1)},
+			{"doc", D{{"x/y", int32(1)}, {"same", "s"}}},
+			{"arr", A{int32(1), int32(2), int32(3)}},
+			{"type", D{{"k", 1}}},
+		})
+		b := marshal(t, D{
+			{"n", int64(1)},
+			{"doc", D{{"same", "s"}, {"x/y", int32(2)}}},
+			{"arr", A{int32(1), int32(5)}},
+			{"type", "str"},
+			{"added", true},
+		})
+		d, err := Diff(a, b)
+		require.NoError(t, err, "Diff error: %v", err)
+
+		want := []struct {
+			op      DiffOp
+			pointer string
+		}{
+			{DiffAdd, "/added"},
+			{DiffReplace, "/arr/1"},
+			{DiffRemove, "/arr/2"},
+			{DiffReplace, "/doc/x~1y"},
+			{DiffReplace, "/n"},
+			{DiffReplace, "/type"},
+			{DiffRemove, "/z"},
+		}
+		require.Len(t, d, len(want), "expected %d differences, got %v", len(want), d)
+		for i, w := range want {
+			assert.Equal(t, w.op, d[i].Op, "op mismatch for entry %d", i)
+			assert.Equal(t, w.pointer, d[i].Pointer(), "pointer mismatch for entry %d", i)
+		}
+		assert.Equal(t, []string{"doc", "x/y"}, d[3].Path, "path mismatch")
+		assert.Equal(t, b.Lookup("doc", "x/y"), b.LookupPath(d[3].Pointer()), "expected the pointer to address the value")
+
+		wantString := `+ /added: true
+~ /arr/1: {"$numberInt":"2"} -> {"$numberInt":"5"}
+- /arr/2: {"$numberInt":"3"}
+~ /doc/x~1y: {"$numberInt":"1"} -> {"$numberInt":"2"}
+~ /n: {"$numberInt":"1"} -> {"$numberLong":"1"}
+~ /type: {"k": {"$numberInt":"1"}} -> "str"
+- /z: "removed"`
+		assert.Equal(t, wantString, d.String(), "String mismatch")
+	})
+	t.Run("canonical numbers", func(t *testing.T) {
+		a, err := Canonicalize(marshal(t, D{{"n", int32(1)}, {"f", 2.0}}), CanonicalNumbersByValue)
+		require.NoError(t, err, "Canonicalize error: %v", err)
+		b, err := Canonicalize(marshal(t, D{{"n", int64(1)}, {"f", int32(2)}}), CanonicalNumbersByValue)
+		require.NoError(t, err, "Canonicalize error: %v", err)
+
+		d, err := Diff(a, b)
+		require.NoError(t, err, "Diff error: %v", err)
+		assert.Len(t, d, 0, "expected no differences, got %v", d)
+	})
+	t.Run("large arrays", func(t *testing.T) {
+		arr := make(A, 12)
+		for i := range arr {
+			arr[i] = int32(i)
+		}
+		d, err := Diff(marshal(t, D{{"a", arr[:2]}}), marshal(t, D{{"a", arr}}))
+		require.NoError(t, err, "Diff error: %v", err)
+		require.Len(t, d, 10, "expected ten added elements, got %v", d)
+		assert.Equal(t, "/a/2", d[0].Pointer(), "expected array indexes to be sorted numerically")
+		assert.Equal(t, "/a/11", d[9].Pointer(), "expected array indexes to be sorted numerically")
+	})
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := Diff(Raw{0x05, 0x00}, marshal(t, D{}))
+		assert.Error(t, err, "expected an error for an invalid document")
+	})
+}
@@ -39,13 +39,37 @@ func NewEncoder(vw ValueWriter) *Encoder {
 //
 // See [Marshal] for details about BSON marshaling behavior.
 func (e *Encoder) Encode(val interface{}) error {
+	return e.encode(e.vw, val)
+}
+
+// EncodeAppend appends the BSON encoding of val as a document to dst and returns the extended
+// buffer, using the configuration and registry of the Encoder but not its ValueWriter. Passing a
+// dst buffer with enough capacity, such as one reused across calls, avoids allocating a new buffer
+// for every document. If an error occurs, dst is returned unmodified.
+//
+// See [Marshal] for details about BSON marshaling behavior.
+func (e *Encoder) EncodeAppend(dst []byte, val interface{}) ([]byte, error) {
+	vw := vwPool.Get().(*valueWriter)
+	defer func() {
+		vw.buf = nil // don't retain the caller's buffer
+		putValueWriter(vw)
+	}()
+
+	vw.reset(dst)
+	if err := e.encode(vw, val); err != nil {
+		return dst, err
+	}
+	return vw.buf, nil
+}
+
+func (e *Encoder) encode(vw ValueWriter, val interface{}) error {
 	if marshaler, ok := val.(Marshaler); ok {
 		// TODO(skriptble): Should we have a MarshalAppender interface so that we can have []byte reuse?
 		buf, err := marshaler.MarshalBSON()
 		if err != nil {
 			return err
 		}
-		return copyDocumentFromBytes(e.vw, buf)
+		return copyDocumentFromBytes(vw, buf)
 	}
 
 	encoder, err := e.ec.LookupEncoder(reflect.TypeOf(val))
@@ -53,7 +77,7 @@ func (e *Encoder) Encode(val interface{}) error {
 		return err
 	}
 
-	return encoder.EncodeValue(e.ec, e.vw, reflect.ValueOf(val))
+	return encoder.EncodeValue(e.ec, vw, reflect.ValueOf(val))
 }
 
 // Reset will reset the state of the Encoder, using the same *EncodeContext used in
@@ -121,3 +145,31 @@ func (e *Encoder) OmitZeroStruct() {
 func (e *Encoder) UseJSONStructTags() {
 	e.ec.useJSONStructTags = true
 }
+
+// UseJSONAndTextMarshalers causes the Encoder to encode values whose types implement json.Marshaler
+// or encoding.TextMarshaler, but have no other registered encoder, by calling MarshalJSON or
+// MarshalText. The output of MarshalJSON is parsed as relaxed Extended JSON and the output of
+// MarshalText is encoded as a BSON string. If a type implements both interfaces, MarshalJSON is
+// used. Encoders registered for the exact type and implementations of Marshaler and ValueMarshaler
+// take precedence.
+func (e *Encoder) UseJSONAndTextMarshalers() {
+	e.ec.useJSONAndTextMarshalers = true
+}
+
+// SetTimeEncoding sets the BSON type that the Encoder encodes time.Time values as. The default is
+// TimeEncodingDateTime. Use TimeEncodingMillis or TimeEncodingRFC3339 for documents that are shared
+// with systems that cannot handle BSON datetime values. Encoders registered for time.Time take
+// precedence.
+func (e *Encoder) SetTimeEncoding(te TimeEncoding) {
+	e.ec.timeEncoding = te
+}
+
+// SortFields causes the Encoder to marshal the fields of Go maps and structs, including the fields
+// of inline structs and maps, in ascending byte order of their BSON field names instead of in map
+// iteration order and struct field order, so that equal values always marshal to the same bytes.
+// This is useful for content hashing, cache keys, and reproducible test fixtures. The elements of
+// ordered types such as bson.D, and the documents returned by Marshaler implementations, are not
+// reordered.
+func (e *Encoder) SortFields() {
+	e.ec.sortFields = true
+}
@@ -148,6 +148,17 @@ func TestEncoderConfiguration(t *testing.T) {
 		MyString string
 	}
 
+	type sortedInner struct {
+		B string `bson:"b"`
+	}
+
+	type sortedOuter struct {
+		Z      int32            `bson:"z"`
+		Inner  sortedInner      `bson:",inline"`
+		Nested map[string]int32 `bson:"nested"`
+		Extra  map[string]int32 `bson:",inline"`
+	}
+
 	testCases := []struct {
 		description string
 		configure   func(*Encoder)
@@ -248,6 +259,47 @@ func TestEncoderConfiguration(t *testing.T) {
 			}{},
 			want: bsoncore.NewDocumentBuilder().Build(),
 		},
+		// Test that SortFields encodes the fields of maps in ascending order of their names.
+		{
+			description: "SortFields map",
+			configure: func(enc *Encoder) {
+				enc.SortFields()
+			},
+			input: M{"c": int32(3), "a": int32(1), "b": D{{"y", int32(2)}, {"x", int32(1)}}, "aa": int32(0)},
+			want: bsoncore.NewDocumentBuilder().
+				AppendInt32("a", 1).
+				AppendInt32("aa", 0).
+				AppendDocument("b", bsoncore.NewDocumentBuilder().
+					AppendInt32("y", 2).
+					AppendInt32("x", 1).
+					Build()).
+				AppendInt32("c", 3).
+				Build(),
+		},
+		// Test that SortFields encodes the fields of structs, including those of inline structs
+		// and maps, and of their nested maps in ascending order of their names.
+		{
+			description: "SortFields struct",
+			configure: func(enc *Encoder) {
+				enc.SortFields()
+			},
+			input: sortedOuter{
+				Z:      1,
+				Inner:  sortedInner{B: "b"},
+				Nested: map[string]int32{"y": 2, "x": 1},
+				Extra:  map[string]int32{"a": 1, "m": 2},
+			},
+			want: bsoncore.NewDocumentBuilder().
+				AppendInt32("a", 1).
+				AppendString("b", "b").
+				AppendInt32("m", 2).
+				AppendDocument("nested", bsoncore.NewDocumentBuilder().
+					AppendInt32("x", 1).
+					AppendInt32("y", 2).
+					Build()).
+				AppendInt32("z", 1).
+				Build(),
+		},
 		// Test that UseJSONStructTags causes the Encoder to fall back to "json" struct tags if
 		// "bson" struct tags are not available.
 		{
@@ -301,3 +353,17 @@ func TestEncoderConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestEncoderEncodeAppend(t *testing.T) {
+	type document struct {
+		Level int `bson:"level"`
+	}
+
+	enc := NewEncoder(nil)
+	enc.IntMinSize()
+
+	got, err := enc.EncodeAppend([]byte{0xFF}, document{Level: 1})
+	require.NoError(t, err, "EncodeAppend error: %v", err)
+	assert.Equal(t, byte(0xFF), got[0], "expected dst prefix to be kept")
+	assert.Equal(t, TypeInt32, Raw(got[1:]).Lookup("level").Type, "expected Encoder configuration to be applied")
+}
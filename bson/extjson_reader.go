@@ -39,7 +39,10 @@ func newExtJSONValueReader(r io.Reader, canonicalOnly bool) (*extJSONValueReader
 }
 
 func (ejvr *extJSONValueReader) reset(r io.Reader, canonicalOnly bool) (*extJSONValueReader, error) {
-	p := newExtJSONParser(r, canonicalOnly)
+	return ejvr.resetParser(newExtJSONParser(r, canonicalOnly))
+}
+
+func (ejvr *extJSONValueReader) resetParser(p *extJSONParser) (*extJSONValueReader, error) {
 	typ, err := p.peekType()
 
 	if err != nil {
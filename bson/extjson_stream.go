@@ -0,0 +1,186 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// An ExtJSONEncoder writes a stream of Extended JSON documents to an io.Writer. Each document is
+// written to the io.Writer as soon as it has been encoded and is followed by a newline, so the
+// output can be read back with an ExtJSONDecoder or processed line by line, as with mongoexport
+// files. Encoded documents are not retained after they are written.
+//
+// By default, documents are written in relaxed mode.
+type ExtJSONEncoder struct {
+	w   io.Writer
+	vw  *extJSONValueWriter
+	enc *Encoder
+}
+
+// NewExtJSONEncoder returns a new ExtJSONEncoder that writes to w.
+func NewExtJSONEncoder(w io.Writer) *ExtJSONEncoder {
+	vw := newExtJSONWriter(w, false, false, true)
+	return &ExtJSONEncoder{
+		w:   w,
+		vw:  vw,
+		enc: NewEncoder(vw),
+	}
+}
+
+// Canonical causes the ExtJSONEncoder to write documents in canonical mode instead of relaxed mode.
+func (e *ExtJSONEncoder) Canonical() {
+	e.vw.canonical = true
+}
+
+// EscapeHTML causes the ExtJSONEncoder to escape the characters <, >, and & in strings.
+func (e *ExtJSONEncoder) EscapeHTML() {
+	e.vw.escapeHTML = true
+}
+
+// ValidateUTF8 causes the ExtJSONEncoder to return an error when encoding a string or key that is
+// not valid UTF-8. By default, invalid bytes are written as the Unicode replacement character.
+func (e *ExtJSONEncoder) ValidateUTF8() {
+	e.vw.validateUTF8 = true
+}
+
+// SetRegistry replaces the current registry of the ExtJSONEncoder with r.
+func (e *ExtJSONEncoder) SetRegistry(r *Registry) {
+	e.enc.SetRegistry(r)
+}
+
+// Encode writes the Extended JSON encoding of val to the stream, followed by a newline. If an error
+// occurs, nothing is written for val and the ExtJSONEncoder can continue to be used.
+//
+// See [MarshalExtJSON] for details about Extended JSON marshaling behavior.
+func (e *ExtJSONEncoder) Encode(val interface{}) error {
+	err := e.enc.Encode(val)
+	if err != nil {
+		// Discard the partially encoded document so the next call starts from a clean state.
+		vw := newExtJSONWriter(e.w, e.vw.canonical, e.vw.escapeHTML, true)
+		vw.validateUTF8 = e.vw.validateUTF8
+		e.vw = vw
+		e.enc.Reset(vw)
+	}
+	return err
+}
+
+// An ExtJSONDecoder reads a stream of Extended JSON documents from an io.Reader. The stream may
+// contain any number of documents separated by optional whitespace, such as the output of
+// mongoexport, or a single top-level JSON array of documents, such as the output of
+// "mongoexport --jsonArray". Input is read incrementally, so each call to Decode only holds the
+// document being decoded in memory.
+type ExtJSONDecoder struct {
+	r             *bufio.Reader
+	dec           *Decoder
+	vr            *extJSONValueReader
+	ar            ArrayReader
+	canonicalOnly bool
+	validateUTF8  bool
+	done          bool
+}
+
+// NewExtJSONDecoder returns a new ExtJSONDecoder that reads from r.
+func NewExtJSONDecoder(r io.Reader) *ExtJSONDecoder {
+	return &ExtJSONDecoder{
+		r:   bufio.NewReader(r),
+		dec: NewDecoder(nil),
+	}
+}
+
+// CanonicalOnly causes the ExtJSONDecoder to return an error when reading Extended JSON that was
+// not written in canonical mode. It must be called before the first call to Decode.
+func (d *ExtJSONDecoder) CanonicalOnly() {
+	d.canonicalOnly = true
+}
+
+// ValidateUTF8 causes the ExtJSONDecoder to return an error when reading a string or key that is not
+// valid UTF-8.
+func (d *ExtJSONDecoder) ValidateUTF8() {
+	d.validateUTF8 = true
+	if d.vr != nil {
+		d.vr.p.js.validateUTF8 = true
+	}
+}
+
+// SetRegistry replaces the current registry of the ExtJSONDecoder with r.
+func (d *ExtJSONDecoder) SetRegistry(r *Registry) {
+	d.dec.SetRegistry(r)
+}
+
+// Decode reads the next Extended JSON document from the stream and decodes it into the value
+// pointed to by val. It returns io.EOF when there are no more documents in the stream.
+//
+// See [UnmarshalExtJSON] for details about Extended JSON unmarshaling behavior.
+func (d *ExtJSONDecoder) Decode(val interface{}) error {
+	if d.done {
+		return io.EOF
+	}
+
+	if d.vr == nil {
+		if err := d.start(); err != nil {
+			return err
+		}
+	}
+
+	if d.ar != nil {
+		evr, err := d.ar.ReadValue()
+		if errors.Is(err, ErrEOA) {
+			d.done = true
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+		d.dec.Reset(evr)
+	}
+
+	err := d.dec.Decode(val)
+	if errors.Is(err, io.EOF) {
+		d.done = true
+	}
+	return err
+}
+
+// start creates the value reader for the stream once the first non-whitespace byte is available,
+// so that an empty stream results in io.EOF rather than an error.
+func (d *ExtJSONDecoder) start() error {
+	for {
+		c, err := d.r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			d.done = true
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+		if !isWhiteSpace(c) {
+			_ = d.r.UnreadByte()
+			break
+		}
+	}
+
+	p := newExtJSONParser(d.r, d.canonicalOnly)
+	p.js.validateUTF8 = d.validateUTF8
+	vr, err := new(extJSONValueReader).resetParser(p)
+	if err != nil {
+		return err
+	}
+	d.vr = vr
+	d.dec.Reset(vr)
+
+	if vr.Type() == TypeArray {
+		ar, err := vr.ReadArray()
+		if err != nil {
+			return err
+		}
+		d.ar = ar
+	}
+	return nil
+}
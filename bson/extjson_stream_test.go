@@ -0,0 +1,127 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestExtJSONEncoder(t *testing.T) {
+	t.Run("relaxed", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewExtJSONEncoder(&buf)
+		for i := int64(0); i < 2; i++ {
+			err := enc.Encode(D{{"a", i}})
+			require.NoError(t, err, "Encode error: %v", err)
+		}
+		assert.Equal(t, "{\"a\":0}\n{\"a\":1}\n", buf.String(), "unexpected output")
+	})
+	t.Run("canonical", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewExtJSONEncoder(&buf)
+		enc.Canonical()
+		err := enc.Encode(D{{"a", int64(1)}})
+		require.NoError(t, err, "Encode error: %v", err)
+		assert.Equal(t, "{\"a\":{\"$numberLong\":\"1\"}}\n", buf.String(), "unexpected output")
+	})
+	t.Run("validate UTF-8", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewExtJSONEncoder(&buf)
+
+		err := enc.Encode(D{{"a", "\xff"}})
+		require.NoError(t, err, "Encode error: %v", err)
+		assert.Equal(t, "{\"a\":\"\\ufffd\"}\n", buf.String(), "expected invalid UTF-8 to be replaced")
+
+		buf.Reset()
+		enc.ValidateUTF8()
+		err = enc.Encode(D{{"a", D{{"b", "\xff"}}}})
+		assert.Error(t, err, "expected error for invalid UTF-8")
+		assert.Equal(t, "", buf.String(), "expected nothing to be written")
+
+		err = enc.Encode(D{{"a", "valid"}})
+		require.NoError(t, err, "Encode error: %v", err)
+		assert.Equal(t, "{\"a\":\"valid\"}\n", buf.String(), "expected encoder to recover after error")
+	})
+}
+
+func TestExtJSONDecoder(t *testing.T) {
+	decodeAll := func(t *testing.T, dec *ExtJSONDecoder) []D {
+		t.Helper()
+
+		var docs []D
+		for {
+			var doc D
+			err := dec.Decode(&doc)
+			if err == io.EOF {
+				return docs
+			}
+			require.NoError(t, err, "Decode error: %v", err)
+			docs = append(docs, doc)
+		}
+	}
+
+	want := []D{{{"a", int32(1)}}, {{"b", int64(2)}}}
+
+	testCases := []struct {
+		name  string
+		input string
+		want  []D
+	}{
+		{"empty", "", nil},
+		{"whitespace", " \n\t", nil},
+		{"newline delimited", "{\"a\":1}\n{\"b\":{\"$numberLong\":\"2\"}}\n", want},
+		{"concatenated", "{\"a\":1}{\"b\":{\"$numberLong\":\"2\"}}", want},
+		{"array", "[{\"a\":1},\n{\"b\":{\"$numberLong\":\"2\"}}]", want},
+		{"empty array", "[]", nil},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := decodeAll(t, NewExtJSONDecoder(strings.NewReader(tc.input)))
+			assert.Equal(t, tc.want, got, "unexpected documents")
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewExtJSONEncoder(&buf)
+		enc.Canonical()
+		for _, doc := range want {
+			err := enc.Encode(doc)
+			require.NoError(t, err, "Encode error: %v", err)
+		}
+
+		dec := NewExtJSONDecoder(&buf)
+		dec.CanonicalOnly()
+		assert.Equal(t, want, decodeAll(t, dec), "unexpected documents")
+	})
+	t.Run("canonical only", func(t *testing.T) {
+		dec := NewExtJSONDecoder(strings.NewReader(`{"a":{"$date":"2024-01-01T00:00:00Z"}}`))
+		dec.CanonicalOnly()
+		var doc D
+		err := dec.Decode(&doc)
+		assert.Error(t, err, "expected error for relaxed Extended JSON")
+	})
+	t.Run("validate UTF-8", func(t *testing.T) {
+		input := "{\"a\":\"\xff\"}"
+
+		var doc D
+		err := NewExtJSONDecoder(strings.NewReader(input)).Decode(&doc)
+		require.NoError(t, err, "Decode error: %v", err)
+
+		dec := NewExtJSONDecoder(strings.NewReader(input))
+		dec.ValidateUTF8()
+		err = dec.Decode(&doc)
+		assert.Error(t, err, "expected error for invalid UTF-8")
+	})
+}
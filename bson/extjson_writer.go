@@ -27,11 +27,12 @@ type extJSONValueWriter struct {
 	w   io.Writer
 	buf []byte
 
-	stack      []ejvwState
-	frame      int64
-	canonical  bool
-	escapeHTML bool
-	newlines   bool
+	stack        []ejvwState
+	frame        int64
+	canonical    bool
+	escapeHTML   bool
+	newlines     bool
+	validateUTF8 bool
 }
 
 // NewExtJSONValueWriter creates a ValueWriter that writes Extended JSON to w.
@@ -77,6 +78,7 @@ func (ejvw *extJSONValueWriter) reset(buf []byte, canonical, escapeHTML bool) {
 	ejvw.stack[0] = ejvwState{mode: mTopLevel}
 	ejvw.canonical = canonical
 	ejvw.escapeHTML = escapeHTML
+	ejvw.validateUTF8 = false
 	ejvw.frame = 0
 	ejvw.buf = buf
 	ejvw.w = nil
@@ -150,6 +152,15 @@ func (ejvw *extJSONValueWriter) writeExtendedSingleValue(key string, value strin
 	ejvw.buf = append(ejvw.buf, []byte(s)...)
 }
 
+// checkUTF8 returns an error if UTF-8 validation is enabled and s is not valid UTF-8. Otherwise,
+// invalid bytes are written as the Unicode replacement character.
+func (ejvw *extJSONValueWriter) checkUTF8(s string) error {
+	if ejvw.validateUTF8 && !utf8.ValidString(s) {
+		return fmt.Errorf("invalid UTF-8 in string %q", s)
+	}
+	return nil
+}
+
 func (ejvw *extJSONValueWriter) WriteArray() (ArrayWriter, error) {
 	if err := ejvw.ensureElementValue(mArray, "WriteArray"); err != nil {
 		return nil, err
@@ -197,6 +208,9 @@ func (ejvw *extJSONValueWriter) WriteCodeWithScope(code string) (DocumentWriter,
 	if err := ejvw.ensureElementValue(mCodeWithScope, "WriteCodeWithScope"); err != nil {
 		return nil, err
 	}
+	if err := ejvw.checkUTF8(code); err != nil {
+		return nil, err
+	}
 
 	var buf bytes.Buffer
 	buf.WriteString(`{"$code":`)
@@ -343,6 +357,9 @@ func (ejvw *extJSONValueWriter) WriteJavascript(code string) error {
 	if err := ejvw.ensureElementValue(mode(0), "WriteJavascript"); err != nil {
 		return err
 	}
+	if err := ejvw.checkUTF8(code); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	writeStringWithEscapes(code, &buf, ejvw.escapeHTML)
@@ -406,6 +423,9 @@ func (ejvw *extJSONValueWriter) WriteRegex(pattern string, options string) error
 	if err := ejvw.ensureElementValue(mode(0), "WriteRegex"); err != nil {
 		return err
 	}
+	if err := ejvw.checkUTF8(pattern); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	buf.WriteString(`{"$regularExpression":{"pattern":`)
@@ -424,6 +444,9 @@ func (ejvw *extJSONValueWriter) WriteString(s string) error {
 	if err := ejvw.ensureElementValue(mode(0), "WriteString"); err != nil {
 		return err
 	}
+	if err := ejvw.checkUTF8(s); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	writeStringWithEscapes(s, &buf, ejvw.escapeHTML)
@@ -439,6 +462,9 @@ func (ejvw *extJSONValueWriter) WriteSymbol(symbol string) error {
 	if err := ejvw.ensureElementValue(mode(0), "WriteSymbol"); err != nil {
 		return err
 	}
+	if err := ejvw.checkUTF8(symbol); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	writeStringWithEscapes(symbol, &buf, ejvw.escapeHTML)
@@ -483,6 +509,10 @@ func (ejvw *extJSONValueWriter) WriteUndefined() error {
 func (ejvw *extJSONValueWriter) WriteDocumentElement(key string) (ValueWriter, error) {
 	switch ejvw.stack[ejvw.frame].mode {
 	case mDocument, mTopLevel, mCodeWithScope:
+		if err := ejvw.checkUTF8(key); err != nil {
+			return nil, err
+		}
+
 		var buf bytes.Buffer
 		writeStringWithEscapes(key, &buf, ejvw.escapeHTML)
 
@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"unicode"
 	"unicode/utf16"
+	"unicode/utf8"
 )
 
 type jsonTokenType byte
@@ -42,10 +43,11 @@ type jsonToken struct {
 }
 
 type jsonScanner struct {
-	r           io.Reader
-	buf         []byte
-	pos         int
-	lastReadErr error
+	r            io.Reader
+	buf          []byte
+	pos          int
+	lastReadErr  error
+	validateUTF8 bool
 }
 
 // nextToken returns the next JSON token if one exists. A token is a character
@@ -303,6 +305,9 @@ func (js *jsonScanner) scanString() (*jsonToken, error) {
 				return nil, fmt.Errorf("invalid escape sequence in JSON string '\\%c'", c)
 			}
 		case '"':
+			if js.validateUTF8 && !utf8.Valid(b.Bytes()) {
+				return nil, errors.New("invalid UTF-8 in JSON string")
+			}
 			return &jsonToken{t: jttString, v: b.String(), p: p}, nil
 		default:
 			b.WriteByte(c)
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 )
 
@@ -87,6 +88,11 @@ func (mc *mapCodec) encodeMapElements(ec EncodeContext, dw DocumentWriter, val r
 	}
 
 	keys := val.MapKeys()
+	if ec.sortFields {
+		if keys, err = mc.sortKeys(keys, ec.stringifyMapKeysWithFmt); err != nil {
+			return err
+		}
+	}
 	for _, key := range keys {
 		keyStr, err := mc.encodeKey(key, ec.stringifyMapKeysWithFmt)
 		if err != nil {
@@ -124,6 +130,32 @@ func (mc *mapCodec) encodeMapElements(ec EncodeContext, dw DocumentWriter, val r
 	return nil
 }
 
+// sortKeys returns the map keys sorted by their BSON field names.
+func (mc *mapCodec) sortKeys(keys []reflect.Value, encodeKeysWithStringer bool) ([]reflect.Value, error) {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		name, err := mc.encodeKey(key, encodeKeysWithStringer)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = name
+	}
+	sort.Sort(keysByName{keys: keys, names: names})
+	return keys, nil
+}
+
+type keysByName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (k keysByName) Len() int           { return len(k.keys) }
+func (k keysByName) Less(i, j int) bool { return k.names[i] < k.names[j] }
+func (k keysByName) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.names[i], k.names[j] = k.names[j], k.names[i]
+}
+
 // DecodeValue is the ValueDecoder for map[string/decimal]* types.
 func (mc *mapCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
 	if val.Kind() != reflect.Map || (!val.CanSet() && val.IsNil()) {
@@ -87,6 +87,20 @@ func Marshal(val interface{}) ([]byte, error) {
 	return buf, nil
 }
 
+// MarshalAppend appends the BSON encoding of val as a BSON document to dst and returns the extended
+// buffer. Reusing dst across calls, or taking it from a pool, avoids allocating a new buffer for
+// every call. If an error occurs, dst is returned unmodified.
+//
+// MarshalAppend will use the default registry created by NewRegistry to recursively marshal val.
+// See Marshal for details about BSON marshaling behavior.
+func MarshalAppend(dst []byte, val interface{}) ([]byte, error) {
+	enc := encPool.Get().(*Encoder)
+	defer encPool.Put(enc)
+	enc.Reset(nil)
+	enc.SetRegistry(defaultRegistry)
+	return enc.EncodeAppend(dst, val)
+}
+
 // MarshalValue returns the BSON encoding of val.
 //
 // MarshalValue will use bson.NewRegistry() to transform val into a BSON value. If val is a struct, this function will
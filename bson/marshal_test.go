@@ -326,3 +326,36 @@ func TestMarshalConcurrently(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMarshalAppend(t *testing.T) {
+	t.Parallel()
+
+	doc := D{{"a", int32(1)}, {"b", "two"}}
+	want, err := Marshal(doc)
+	require.NoError(t, err, "Marshal error: %v", err)
+
+	t.Run("appends to dst", func(t *testing.T) {
+		prefix := []byte{0x01, 0x02, 0x03}
+		got, err := MarshalAppend(prefix, doc)
+		require.NoError(t, err, "MarshalAppend error: %v", err)
+		assert.Equal(t, append(prefix, want...), got, "expected document to be appended to dst")
+	})
+	t.Run("reuses capacity", func(t *testing.T) {
+		buf := make([]byte, 0, 128)
+		got, err := MarshalAppend(buf, doc)
+		require.NoError(t, err, "MarshalAppend error: %v", err)
+		assert.Equal(t, want, got, "expected document to match Marshal")
+		assert.Equal(t, &buf[:1][0], &got[0], "expected dst to be reused")
+	})
+	t.Run("Marshaler", func(t *testing.T) {
+		got, err := MarshalAppend([]byte{0xFF}, Raw(want))
+		require.NoError(t, err, "MarshalAppend error: %v", err)
+		assert.Equal(t, append([]byte{0xFF}, want...), got, "expected Marshaler output to be appended")
+	})
+	t.Run("error", func(t *testing.T) {
+		dst := []byte{0x01}
+		got, err := MarshalAppend(dst, int32(1))
+		assert.Error(t, err, "expected error marshaling a non-document value")
+		assert.Equal(t, dst, got, "expected dst to be returned on error")
+	})
+}
@@ -0,0 +1,217 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+var (
+	tJSONMarshaler     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	tJSONUnmarshaler   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	tTextMarshaler     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	tTextUnmarshaler   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	interopWrapperKey  = "v"
+	interopWrapperHead = []byte(`{"` + interopWrapperKey + `":`)
+)
+
+// implementsEither reports whether t or a pointer to t implements either of the given interfaces.
+func implementsEither(t reflect.Type, a, b reflect.Type) bool {
+	if t.Implements(a) || t.Implements(b) {
+		return true
+	}
+	if t.Kind() != reflect.Ptr {
+		pt := reflect.PtrTo(t)
+		return pt.Implements(a) || pt.Implements(b)
+	}
+	return false
+}
+
+// lookupInteropEncoder returns a marshalerInteropEncoder for types that implement json.Marshaler or
+// encoding.TextMarshaler. Pointers to types that have a registered type encoder, such as
+// *time.Time, are excluded so they continue to be encoded by the encoder for the element type.
+func (r *Registry) lookupInteropEncoder(valueType reflect.Type) (ValueEncoder, bool) {
+	if !implementsEither(valueType, tJSONMarshaler, tTextMarshaler) {
+		return nil, false
+	}
+	if valueType.Kind() == reflect.Ptr {
+		if enc, found := r.lookupTypeEncoder(valueType.Elem()); found && enc != nil {
+			return nil, false
+		}
+	}
+
+	fallback, _ := r.kindEncoders.Load(valueType.Kind())
+	return &marshalerInteropEncoder{fallback: fallback}, true
+}
+
+// lookupInteropDecoder returns a marshalerInteropDecoder for types that implement json.Unmarshaler
+// or encoding.TextUnmarshaler, following the same rules as lookupInteropEncoder.
+func (r *Registry) lookupInteropDecoder(valueType reflect.Type) (ValueDecoder, bool) {
+	if !implementsEither(valueType, tJSONUnmarshaler, tTextUnmarshaler) {
+		return nil, false
+	}
+	if valueType.Kind() == reflect.Ptr {
+		if dec, found := r.lookupTypeDecoder(valueType.Elem()); found && dec != nil {
+			return nil, false
+		}
+	}
+
+	fallback, _ := r.kindDecoders.Load(valueType.Kind())
+	return &marshalerInteropDecoder{fallback: fallback}, true
+}
+
+// marshalerInteropEncoder encodes values using their MarshalJSON or MarshalText method if the
+// EncodeContext enables it, and with the encoder for their kind otherwise. The result of
+// MarshalJSON is parsed as relaxed Extended JSON, so JSON objects become documents, arrays become
+// arrays, and numbers become int32, int64, or double values. The result of MarshalText is encoded
+// as a string.
+type marshalerInteropEncoder struct {
+	fallback ValueEncoder
+}
+
+func (e *marshalerInteropEncoder) EncodeValue(ec EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !ec.useJSONAndTextMarshalers {
+		return e.encodeFallback(ec, vw, val)
+	}
+
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return vw.WriteNull()
+	}
+
+	target := val
+	if !target.Type().Implements(tJSONMarshaler) && !target.Type().Implements(tTextMarshaler) {
+		if !target.CanAddr() {
+			return e.encodeFallback(ec, vw, val)
+		}
+		target = target.Addr()
+	}
+
+	switch m := target.Interface().(type) {
+	case json.Marshaler:
+		b, err := m.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("error calling MarshalJSON for type %s: %w", val.Type(), err)
+		}
+		return writeJSONValue(vw, b)
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		if err != nil {
+			return fmt.Errorf("error calling MarshalText for type %s: %w", val.Type(), err)
+		}
+		return vw.WriteString(string(b))
+	}
+	return e.encodeFallback(ec, vw, val)
+}
+
+func (e *marshalerInteropEncoder) encodeFallback(ec EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if e.fallback == nil {
+		return errNoEncoder{Type: val.Type()}
+	}
+	return e.fallback.EncodeValue(ec, vw, val)
+}
+
+// writeJSONValue parses the JSON value b as relaxed Extended JSON and writes it to vw.
+func writeJSONValue(vw ValueWriter, b []byte) error {
+	doc := make([]byte, 0, len(interopWrapperHead)+len(b)+1)
+	doc = append(doc, interopWrapperHead...)
+	doc = append(doc, b...)
+	doc = append(doc, '}')
+
+	var raw Raw
+	if err := UnmarshalExtJSON(doc, false, &raw); err != nil {
+		return fmt.Errorf("error parsing the output of MarshalJSON: %w", err)
+	}
+	rv, err := raw.LookupErr(interopWrapperKey)
+	if err != nil {
+		return err
+	}
+	return copyValueFromBytes(vw, rv.Type, rv.Value)
+}
+
+// marshalerInteropDecoder decodes values using their UnmarshalJSON or UnmarshalText method if the
+// DecodeContext enables it, and with the decoder for their kind otherwise. UnmarshalJSON is passed
+// the relaxed Extended JSON representation of the BSON value. UnmarshalText is used for BSON string
+// values if the type does not implement json.Unmarshaler.
+type marshalerInteropDecoder struct {
+	fallback ValueDecoder
+}
+
+func (d *marshalerInteropDecoder) DecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !dc.useJSONAndTextMarshalers || !val.CanSet() {
+		return d.decodeFallback(dc, vr, val)
+	}
+
+	target := val
+	if val.Kind() == reflect.Ptr {
+		if vr.Type() == TypeNull {
+			val.Set(reflect.Zero(val.Type()))
+			return vr.ReadNull()
+		}
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+	}
+	if !target.Type().Implements(tJSONUnmarshaler) && !target.Type().Implements(tTextUnmarshaler) {
+		target = target.Addr()
+	}
+
+	switch u := target.Interface().(type) {
+	case json.Unmarshaler:
+		b, err := readJSONValue(vr)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalJSON(b)
+	case encoding.TextUnmarshaler:
+		if vr.Type() != TypeString {
+			return fmt.Errorf("cannot decode %v into %s using UnmarshalText", vr.Type(), val.Type())
+		}
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalText([]byte(s))
+	}
+	return d.decodeFallback(dc, vr, val)
+}
+
+func (d *marshalerInteropDecoder) decodeFallback(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if d.fallback == nil {
+		return errNoDecoder{Type: val.Type()}
+	}
+	return d.fallback.DecodeValue(dc, vr, val)
+}
+
+// readJSONValue reads a value from vr and returns its relaxed Extended JSON representation.
+func readJSONValue(vr ValueReader) ([]byte, error) {
+	t, data, err := copyValueToBytes(vr)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	doc = bsoncore.AppendHeader(doc, bsoncore.Type(t), interopWrapperKey)
+	doc = append(doc, data...)
+	doc, err = bsoncore.AppendDocumentEnd(doc, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := MarshalExtJSON(Raw(doc), false, false)
+	if err != nil {
+		return nil, err
+	}
+	// Strip the wrapping document.
+	b = bytes.TrimPrefix(b, interopWrapperHead)
+	return b[:len(b)-1], nil
+}
@@ -0,0 +1,160 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// textLevel implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("low"), nil
+	case 1:
+		return []byte("high"), nil
+	}
+	return nil, errors.New("invalid level")
+}
+
+func (l *textLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low":
+		*l = 0
+	case "high":
+		*l = 1
+	default:
+		return errors.New("invalid level")
+	}
+	return nil
+}
+
+// jsonPoint implements json.Marshaler and json.Unmarshaler with pointer receivers.
+type jsonPoint struct {
+	x, y int
+}
+
+func (p *jsonPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.x, p.y})
+}
+
+func (p *jsonPoint) UnmarshalJSON(b []byte) error {
+	var coords []int
+	if err := json.Unmarshal(b, &coords); err != nil {
+		return err
+	}
+	if len(coords) != 2 {
+		return errors.New("expected two coordinates")
+	}
+	p.x, p.y = coords[0], coords[1]
+	return nil
+}
+
+type interopDocument struct {
+	Level    textLevel  `bson:"level"`
+	Point    jsonPoint  `bson:"point"`
+	PointPtr *jsonPoint `bson:"pointPtr"`
+	IP       net.IP     `bson:"ip"`
+	Time     *time.Time `bson:"time"`
+}
+
+func TestMarshalerInterop(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := interopDocument{
+		Level:    1,
+		Point:    jsonPoint{x: 1, y: 2},
+		PointPtr: &jsonPoint{x: 3, y: 4},
+		IP:       net.ParseIP("127.0.0.1"),
+		Time:     &now,
+	}
+
+	encode := func(t *testing.T, val interface{}, interop bool) Raw {
+		t.Helper()
+
+		buf := new(bytes.Buffer)
+		enc := NewEncoder(NewDocumentWriter(buf))
+		if interop {
+			enc.UseJSONAndTextMarshalers()
+		}
+		err := enc.Encode(val)
+		require.NoError(t, err, "Encode error: %v", err)
+		return buf.Bytes()
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		// Pass a pointer so the fields are addressable and the pointer receiver MarshalJSON method
+		// of jsonPoint can be used, as with encoding/json.
+		got := encode(t, &doc, true)
+
+		assert.Equal(t, "high", got.Lookup("level").StringValue(), "expected level to use MarshalText")
+		assert.Equal(t, `[{"$numberInt":"1"},{"$numberInt":"2"}]`, got.Lookup("point").Array().String(),
+			"expected point to use MarshalJSON")
+		assert.Equal(t, `[{"$numberInt":"3"},{"$numberInt":"4"}]`, got.Lookup("pointPtr").Array().String(),
+			"expected pointPtr to use MarshalJSON")
+		assert.Equal(t, "127.0.0.1", got.Lookup("ip").StringValue(), "expected ip to use MarshalText")
+		assert.Equal(t, TypeDateTime, got.Lookup("time").Type, "expected registered time encoder to be used")
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		got := encode(t, doc, false)
+
+		assert.Equal(t, TypeInt32, got.Lookup("level").Type, "expected level to be encoded as an integer")
+		assert.Equal(t, TypeBinary, got.Lookup("ip").Type, "expected ip to be encoded as binary")
+	})
+	t.Run("round trip", func(t *testing.T) {
+		data := encode(t, &doc, true)
+
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.UseJSONAndTextMarshalers()
+		var got interopDocument
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, doc.Level, got.Level, "expected level to round trip")
+		assert.Equal(t, doc.Point, got.Point, "expected point to round trip")
+		assert.Equal(t, doc.PointPtr, got.PointPtr, "expected pointPtr to round trip")
+		assert.True(t, doc.IP.Equal(got.IP), "expected ip to round trip, got %v", got.IP)
+		assert.Equal(t, doc.Time, got.Time, "expected time to round trip")
+	})
+	t.Run("null pointer", func(t *testing.T) {
+		data := encode(t, &interopDocument{}, true)
+		assert.Equal(t, TypeNull, data.Lookup("pointPtr").Type, "expected nil pointer to be encoded as null")
+
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.UseJSONAndTextMarshalers()
+		got := interopDocument{PointPtr: &jsonPoint{}}
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Nil(t, got.PointPtr, "expected null to decode to a nil pointer")
+	})
+	t.Run("errors", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		enc := NewEncoder(NewDocumentWriter(buf))
+		enc.UseJSONAndTextMarshalers()
+		err := enc.Encode(D{{"level", textLevel(5)}})
+		assert.ErrorContains(t, err, "MarshalText", "expected MarshalText error")
+
+		data := encode(t, D{{"level", "medium"}}, false)
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.UseJSONAndTextMarshalers()
+		var got struct {
+			Level textLevel `bson:"level"`
+		}
+		err = dec.Decode(&got)
+		assert.True(t, err != nil && strings.Contains(err.Error(), "invalid level"),
+			"expected UnmarshalText error, got %v", err)
+	})
+}
@@ -18,7 +18,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"sync/atomic"
 	"time"
 )
 
@@ -42,15 +41,13 @@ func NewObjectID() ObjectID {
 	return NewObjectIDFromTimestamp(time.Now())
 }
 
-// NewObjectIDFromTimestamp generates a new ObjectID based on the given time.
+// NewObjectIDFromTimestamp generates a new ObjectID based on the given time. The ObjectID is
+// generated by the ObjectIDGenerator set with SetObjectIDGenerator, if any.
 func NewObjectIDFromTimestamp(timestamp time.Time) ObjectID {
-	var b [12]byte
-
-	binary.BigEndian.PutUint32(b[0:4], uint32(timestamp.Unix()))
-	copy(b[4:9], processUnique[:])
-	putUint24(b[9:12], atomic.AddUint32(&objectIDCounter, 1))
-
-	return b
+	if gen, ok := objectIDGenerator.Load().(objectIDGeneratorHolder); ok && gen.ObjectIDGenerator != nil {
+		return gen.NewObjectID(timestamp)
+	}
+	return defaultObjectIDGenerator.NewObjectID(timestamp)
 }
 
 // Timestamp extracts the time part of the ObjectId.
@@ -0,0 +1,185 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ObjectIDGenerator generates ObjectIDs. Implementations must be safe for concurrent use.
+type ObjectIDGenerator interface {
+	// NewObjectID generates a new ObjectID for the given time.
+	NewObjectID(timestamp time.Time) ObjectID
+}
+
+// ObjectIDGeneratorFunc is a function that implements ObjectIDGenerator.
+type ObjectIDGeneratorFunc func(timestamp time.Time) ObjectID
+
+// NewObjectID calls f(timestamp).
+func (f ObjectIDGeneratorFunc) NewObjectID(timestamp time.Time) ObjectID {
+	return f(timestamp)
+}
+
+// objectIDGeneratorHolder wraps an ObjectIDGenerator so generators of different types can be
+// stored in the same atomic.Value.
+type objectIDGeneratorHolder struct {
+	ObjectIDGenerator
+}
+
+var objectIDGenerator atomic.Value
+
+var defaultObjectIDGenerator = &standardObjectIDGenerator{
+	processUnique: processUnique,
+	counter:       &objectIDCounter,
+}
+
+// SetObjectIDGenerator sets the ObjectIDGenerator used by NewObjectID and NewObjectIDFromTimestamp,
+// and therefore by the driver to generate the _id of inserted documents and the ids of GridFS files.
+// Passing nil restores the default generator. SetObjectIDGenerator is safe to call concurrently
+// with the generation of ObjectIDs, but it is typically called once during program
+// initialization.
+func SetObjectIDGenerator(gen ObjectIDGenerator) {
+	objectIDGenerator.Store(objectIDGeneratorHolder{gen})
+}
+
+// standardObjectIDGenerator generates ObjectIDs in the format of the ObjectID specification: a
+// 4-byte timestamp in seconds, a 5-byte process-unique value and a 3-byte counter.
+type standardObjectIDGenerator struct {
+	processUnique [5]byte
+	counter       *uint32
+}
+
+func (g *standardObjectIDGenerator) NewObjectID(timestamp time.Time) ObjectID {
+	var b [12]byte
+
+	binary.BigEndian.PutUint32(b[0:4], uint32(timestamp.Unix()))
+	copy(b[4:9], g.processUnique[:])
+	putUint24(b[9:12], atomic.AddUint32(g.counter, 1))
+
+	return b
+}
+
+// NewStandardObjectIDGenerator returns an ObjectIDGenerator that generates ObjectIDs in the
+// standard format with the given 5-byte process-unique value and a counter that starts after
+// counter. By default, the process-unique value and the initial counter are random. Custom values
+// can be used to, for example, encode a machine id and a process id in the process-unique value
+// of deployments that assign them, or to generate reproducible ObjectIDs in tests.
+func NewStandardObjectIDGenerator(processUnique [5]byte, counter uint32) ObjectIDGenerator {
+	return &standardObjectIDGenerator{
+		processUnique: processUnique,
+		counter:       &counter,
+	}
+}
+
+// sortableObjectIDGenerator generates ObjectIDs that sort by their creation time with millisecond
+// precision. See NewSortableObjectIDGenerator.
+type sortableObjectIDGenerator struct {
+	mu     sync.Mutex
+	rand   io.Reader
+	millis int64
+	last   ObjectID
+}
+
+// NewSortableObjectIDGenerator returns an ObjectIDGenerator of K-sortable ObjectIDs, similar to
+// ULIDs. The ObjectIDs consist of the 4-byte timestamp in seconds of standard ObjectIDs, so that
+// ObjectID.Timestamp and ObjectIDFromTimeRange work as usual, followed by the milliseconds of the
+// timestamp as a 2-byte integer and 6 random bytes.
+//
+// The ObjectIDs generated by the same generator are strictly increasing: an ObjectID generated in
+// the same millisecond as the previous one, or with an earlier timestamp, e.g. because the clock
+// was adjusted, is generated by incrementing the previous ObjectID. ObjectIDs generated by
+// different generators, e.g. in different processes, are ordered by their timestamp in
+// milliseconds.
+func NewSortableObjectIDGenerator() ObjectIDGenerator {
+	return &sortableObjectIDGenerator{rand: rand.Reader}
+}
+
+func (g *sortableObjectIDGenerator) NewObjectID(timestamp time.Time) ObjectID {
+	millis := timestamp.UnixNano() / int64(time.Millisecond)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if millis <= g.millis && !g.last.IsZero() {
+		g.last = incrementObjectID(g.last)
+		return g.last
+	}
+
+	var b ObjectID
+	binary.BigEndian.PutUint32(b[0:4], uint32(millis/1000))
+	binary.BigEndian.PutUint16(b[4:6], uint16(millis%1000))
+	if _, err := io.ReadFull(g.rand, b[6:12]); err != nil {
+		panic(fmt.Errorf("cannot generate ObjectID with crypto.rand.Reader: %w", err))
+	}
+
+	g.millis = millis
+	g.last = b
+	return b
+}
+
+// incrementObjectID returns id incremented by one as a 96-bit big-endian integer.
+func incrementObjectID(id ObjectID) ObjectID {
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i]++
+		if id[i] != 0 {
+			break
+		}
+	}
+	return id
+}
+
+// ObjectIDFromTimeRange returns the bounds of the ObjectIDs generated from start up to end, to
+// query documents by the time their ObjectID _id was generated:
+//
+//	from, to := bson.ObjectIDFromTimeRange(start, end)
+//	filter := bson.D{{"_id", bson.D{{"$gte", from}, {"$lt", to}}}}
+//
+// Because the timestamp of an ObjectID is in seconds, start is rounded down and end is rounded up
+// to a whole second, so the range may include ObjectIDs generated up to one second before start
+// or after end. Times outside of the range of ObjectID timestamps are clamped to it.
+func ObjectIDFromTimeRange(start, end time.Time) (from, to ObjectID) {
+	endSecs := end.Unix()
+	if end.Nanosecond() != 0 {
+		endSecs++
+	}
+	return MinObjectIDFromTimestamp(start), objectIDFromSeconds(endSecs, 0x00)
+}
+
+// MinObjectIDFromTimestamp returns the smallest ObjectID with the timestamp of t in seconds.
+func MinObjectIDFromTimestamp(t time.Time) ObjectID {
+	return objectIDFromSeconds(t.Unix(), 0x00)
+}
+
+// MaxObjectIDFromTimestamp returns the largest ObjectID with the timestamp of t in seconds.
+func MaxObjectIDFromTimestamp(t time.Time) ObjectID {
+	return objectIDFromSeconds(t.Unix(), 0xff)
+}
+
+// objectIDFromSeconds returns the ObjectID with the timestamp secs, clamped to the range of
+// ObjectID timestamps, whose other bytes are fill.
+func objectIDFromSeconds(secs int64, fill byte) ObjectID {
+	switch {
+	case secs < 0:
+		secs = 0
+	case secs > math.MaxUint32:
+		secs = math.MaxUint32
+	}
+
+	var b ObjectID
+	binary.BigEndian.PutUint32(b[0:4], uint32(secs))
+	for i := 4; i < len(b); i++ {
+		b[i] = fill
+	}
+	return b
+}
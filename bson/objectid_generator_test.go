@@ -0,0 +1,85 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestSetObjectIDGenerator(t *testing.T) {
+	want := ObjectID{1, 2, 3}
+	SetObjectIDGenerator(ObjectIDGeneratorFunc(func(time.Time) ObjectID { return want }))
+	defer SetObjectIDGenerator(nil)
+
+	assert.Equal(t, want, NewObjectID(), "expected the ObjectID of the custom generator")
+	assert.Equal(t, want, NewObjectIDFromTimestamp(time.Now()), "expected the ObjectID of the custom generator")
+
+	SetObjectIDGenerator(nil)
+	assert.NotEqual(t, want, NewObjectID(), "expected the default generator to be restored")
+}
+
+func TestNewStandardObjectIDGenerator(t *testing.T) {
+	gen := NewStandardObjectIDGenerator([5]byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee}, 0xfffffe)
+	ts := time.Unix(0x01020304, 0)
+
+	id := gen.NewObjectID(ts)
+	assert.Equal(t, "01020304aabbccddeeffffff", id.Hex(), "ObjectID mismatch")
+	id = gen.NewObjectID(ts)
+	assert.Equal(t, "01020304aabbccddee000000", id.Hex(), "expected the counter to wrap around")
+}
+
+func TestNewSortableObjectIDGenerator(t *testing.T) {
+	gen := NewSortableObjectIDGenerator()
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 250*int(time.Millisecond), time.UTC)
+
+	id := gen.NewObjectID(ts)
+	assert.Equal(t, ts.Truncate(time.Second), id.Timestamp(), "timestamp mismatch")
+	assert.Equal(t, []byte{0x00, 0xfa}, id[4:6], "expected the milliseconds in bytes 4-5")
+
+	prev := id
+	for _, tsi := range []time.Time{
+		ts,                       // same millisecond
+		ts.Add(-time.Second),     // clock moved backwards
+		ts.Add(time.Millisecond), // next millisecond
+		ts.Add(time.Second),      // next second
+	} {
+		id := gen.NewObjectID(tsi)
+		require.Equal(t, 1, bytes.Compare(id[:], prev[:]), "expected %s > %s", id, prev)
+		prev = id
+	}
+}
+
+func TestIncrementObjectID(t *testing.T) {
+	id := incrementObjectID(ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0xff, 0xff})
+	assert.Equal(t, ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0}, id, "increment mismatch")
+}
+
+func TestObjectIDFromTimeRange(t *testing.T) {
+	start := time.Unix(1000, 500)
+	end := time.Unix(2000, 1)
+
+	from, to := ObjectIDFromTimeRange(start, end)
+	assert.Equal(t, "000003e80000000000000000", from.Hex(), "from mismatch")
+	assert.Equal(t, "000007d10000000000000000", to.Hex(), "expected end to be rounded up")
+
+	_, to = ObjectIDFromTimeRange(start, time.Unix(2000, 0))
+	assert.Equal(t, "000007d00000000000000000", to.Hex(), "expected a whole second end to be kept")
+
+	id := NewObjectIDFromTimestamp(time.Unix(1500, 0))
+	assert.True(t, bytes.Compare(from[:], id[:]) <= 0 && bytes.Compare(id[:], to[:]) < 0,
+		"expected %s to be in [%s, %s)", id, from, to)
+
+	assert.Equal(t, "000003e8ffffffffffffffff", MaxObjectIDFromTimestamp(start).Hex(), "max mismatch")
+	assert.Equal(t, NilObjectID, MinObjectIDFromTimestamp(time.Unix(-5, 0)), "expected negative times to be clamped")
+	assert.Equal(t, "ffffffff0000000000000000", MinObjectIDFromTimestamp(time.Unix(1<<40, 0)).Hex(),
+		"expected large times to be clamped")
+}
@@ -0,0 +1,118 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+// OrderedMap is a BSON document that preserves the order of its fields, like D, and supports
+// looking up fields by key in constant time, like M. It is useful for documents whose field order
+// matters but which are also read by key, such as command documents and index key specifications.
+//
+// OrderedMap values are encoded as BSON documents with their fields in order, and BSON documents
+// are decoded into an OrderedMap in the order of their fields. To also decode the embedded
+// documents of an OrderedMap, or the documents decoded into an interface{}, as *OrderedMap, use
+// Decoder.DefaultDocumentOrderedMap.
+//
+// If a decoded document contains the same key more than once, the last value is kept at the
+// position of the first occurrence of the key.
+//
+// The zero value is an empty map ready to use. An OrderedMap must not be copied after first use;
+// use pointers to OrderedMap instead.
+type OrderedMap struct {
+	elems D
+	index map[string]int
+}
+
+// NewOrderedMap returns an OrderedMap with the given elements in order. If a key is given more
+// than once, the last value is kept at the position of the first occurrence of the key.
+func NewOrderedMap(elems ...E) *OrderedMap {
+	m := &OrderedMap{elems: make(D, 0, len(elems))}
+	for _, e := range elems {
+		m.Set(e.Key, e.Value)
+	}
+	return m
+}
+
+// Len returns the number of fields of m.
+func (m *OrderedMap) Len() int {
+	return len(m.elems)
+}
+
+// Get returns the value of key and whether m contains key.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	i, ok := m.index[key]
+	if !ok {
+		return nil, false
+	}
+	return m.elems[i].Value, true
+}
+
+// Has returns whether m contains key.
+func (m *OrderedMap) Has(key string) bool {
+	_, ok := m.index[key]
+	return ok
+}
+
+// Set sets the value of key. If m already contains key, its value is replaced at its current
+// position. Otherwise, key is added after the last field.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if i, ok := m.index[key]; ok {
+		m.elems[i].Value = value
+		return
+	}
+	if m.index == nil {
+		m.index = make(map[string]int)
+	}
+	m.index[key] = len(m.elems)
+	m.elems = append(m.elems, E{Key: key, Value: value})
+}
+
+// Delete removes key from m and returns whether m contained key. The order of the remaining
+// fields is preserved.
+func (m *OrderedMap) Delete(key string) bool {
+	i, ok := m.index[key]
+	if !ok {
+		return false
+	}
+	m.elems = append(m.elems[:i], m.elems[i+1:]...)
+	delete(m.index, key)
+	for j := i; j < len(m.elems); j++ {
+		m.index[m.elems[j].Key] = j
+	}
+	return true
+}
+
+// Keys returns the keys of m in order.
+func (m *OrderedMap) Keys() []string {
+	keys := make([]string, len(m.elems))
+	for i, e := range m.elems {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// D returns a copy of the fields of m in order.
+func (m *OrderedMap) D() D {
+	d := make(D, len(m.elems))
+	copy(d, m.elems)
+	return d
+}
+
+// Range calls fn for each field of m in order until fn returns false. fn must not modify m.
+func (m *OrderedMap) Range(fn func(key string, value interface{}) bool) {
+	for _, e := range m.elems {
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// reset removes all fields from m, keeping its allocated storage.
+func (m *OrderedMap) reset() {
+	m.elems = m.elems[:0]
+	for k := range m.index {
+		delete(m.index, k)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap(E{"b", 1}, E{"a", 2}, E{"b", 3})
+	assert.Equal(t, []string{"b", "a"}, m.Keys(), "expected duplicate keys to keep their first position")
+	v, ok := m.Get("b")
+	assert.True(t, ok, "expected key b to be found")
+	assert.Equal(t, 3, v, "expected the last value of a duplicate key")
+
+	m.Set("c", 4)
+	m.Set("a", 5)
+	assert.Equal(t, D{{"b", 3}, {"a", 5}, {"c", 4}}, m.D(), "D mismatch")
+
+	assert.True(t, m.Delete("b"), "expected key b to be deleted")
+	assert.False(t, m.Delete("b"), "expected key b to be gone")
+	assert.False(t, m.Has("b"), "expected key b to be gone")
+	v, _ = m.Get("c")
+	assert.Equal(t, 4, v, "expected the index to be updated after Delete")
+	assert.Equal(t, 2, m.Len(), "Len mismatch")
+
+	var keys []string
+	m.Range(func(key string, _ interface{}) bool {
+		keys = append(keys, key)
+		return false
+	})
+	assert.Equal(t, []string{"a"}, keys, "expected Range to stop when fn returns false")
+
+	var zero OrderedMap
+	zero.Set("x", 1)
+	assert.Equal(t, 1, zero.Len(), "expected the zero value to be usable")
+}
+
+func TestOrderedMapCodec(t *testing.T) {
+	// Field order that differs from the sorted order of the keys must be preserved.
+	doc, err := Marshal(D{
+		{"createIndexes", "coll"},
+		{"indexes", A{D{{"key", D{{"z", 1}, {"a", -1}}}, {"name", "z_1_a_-1"}}}},
+	})
+	require.NoError(t, err, "Marshal error")
+
+	t.Run("round trip", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(doc)))
+		dec.DefaultDocumentOrderedMap()
+		var m OrderedMap
+		require.NoError(t, dec.Decode(&m), "Decode error")
+
+		assert.Equal(t, []string{"createIndexes", "indexes"}, m.Keys(), "keys mismatch")
+		indexes, _ := m.Get("indexes")
+		index := indexes.(A)[0].(*OrderedMap)
+		key, _ := index.Get("key")
+		assert.Equal(t, []string{"z", "a"}, key.(*OrderedMap).Keys(), "expected nested documents to keep their order")
+
+		got, err := Marshal(&m)
+		require.NoError(t, err, "Marshal error")
+		assert.Equal(t, Raw(doc), Raw(got), "expected the document to be re-encoded without changes")
+	})
+	t.Run("struct fields", func(t *testing.T) {
+		type command struct {
+			CreateIndexes string       `bson:"createIndexes"`
+			Indexes       []OrderedMap `bson:"indexes"`
+			Comment       *OrderedMap  `bson:"comment,omitempty"`
+		}
+
+		var cmd command
+		require.NoError(t, Unmarshal(doc, &cmd), "Unmarshal error")
+		require.Len(t, cmd.Indexes, 1, "expected one index")
+		key, _ := cmd.Indexes[0].Get("key")
+		assert.Equal(t, D{{"z", int32(1)}, {"a", int32(-1)}}, key, "expected nested documents to decode as D by default")
+
+		got, err := Marshal(cmd)
+		require.NoError(t, err, "Marshal error")
+		assert.Equal(t, Raw(doc), Raw(got), "expected the document to be re-encoded without changes")
+	})
+	t.Run("reuses the destination", func(t *testing.T) {
+		m := NewOrderedMap(E{"stale", true})
+		require.NoError(t, Unmarshal(doc, m), "Unmarshal error")
+		assert.False(t, m.Has("stale"), "expected existing fields to be removed")
+		assert.Equal(t, 2, m.Len(), "Len mismatch")
+	})
+}
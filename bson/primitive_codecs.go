@@ -72,13 +72,21 @@ func rawEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
 }
 
 // rawDecodeValue is the ValueDecoderFunc for Reader.
-func rawDecodeValue(_ DecodeContext, vr ValueReader, val reflect.Value) error {
+func rawDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
 	if !val.CanSet() || val.Type() != tRaw {
 		return ValueDecoderError{Name: "RawDecodeValue", Types: []reflect.Type{tRaw}, Received: val}
 	}
 
 	if val.IsNil() {
-		val.Set(reflect.MakeSlice(val.Type(), 0, 0))
+		if dc.allocator != nil {
+			var size int
+			if vr, ok := vr.(*valueReader); ok {
+				size = vr.peekDocumentLength()
+			}
+			val.Set(reflect.ValueOf(Raw(dc.allocator.Allocate(size))))
+		} else {
+			val.Set(reflect.MakeSlice(val.Type(), 0, 0))
+		}
 	}
 
 	val.SetLen(0)
@@ -0,0 +1,157 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// LookupPath searches the document for the value at the given path. The path is either a
+// dot-separated list of keys, such as "a.b.3.c", or a JSON Pointer (RFC 6901), such as "/a/b/3/c".
+// Path segments that address an array are parsed as array indexes. The intermediate documents and
+// arrays are not decoded or copied, and the returned RawValue refers to the bytes of r. If an error
+// occurs or if the value doesn't exist, an empty RawValue is returned.
+func (r Raw) LookupPath(path string) RawValue {
+	val, _ := r.LookupPathErr(path)
+	return val
+}
+
+// LookupPathErr is the same as LookupPath, except it returns an error in addition to an empty
+// RawValue.
+func (r Raw) LookupPathErr(path string) (RawValue, error) {
+	sep := byte('.')
+	pointer := strings.HasPrefix(path, "/")
+	if pointer {
+		sep = '/'
+		path = path[1:]
+	} else if path == "" {
+		return RawValue{}, bsoncore.ErrEmptyKey
+	}
+
+	val := RawValue{Type: TypeEmbeddedDocument, Value: r}
+	var parent string
+	for {
+		seg, rest, more := strings.Cut(path, string(sep))
+		if pointer {
+			seg = unescapePointerToken(seg)
+		}
+
+		var err error
+		val, err = lookupPathSegment(val, parent, seg)
+		if err != nil {
+			return RawValue{}, err
+		}
+		if !more {
+			return val, nil
+		}
+		parent, path = seg, rest
+	}
+}
+
+// lookupPathSegment returns the value for seg in the document or array val, which is the value for
+// the key parent.
+func lookupPathSegment(val RawValue, parent, seg string) (RawValue, error) {
+	switch val.Type {
+	case TypeEmbeddedDocument:
+		v, err := bsoncore.Document(val.Value).LookupErr(seg)
+		return convertFromCoreValue(v), err
+	case TypeArray:
+		idx, err := strconv.ParseUint(seg, 10, 0)
+		if err != nil {
+			return RawValue{}, fmt.Errorf("invalid array index %q for key %q", seg, parent)
+		}
+		v, err := bsoncore.Array(val.Value).IndexErr(uint(idx))
+		if errors.Is(err, bsoncore.ErrOutOfBounds) {
+			return RawValue{}, bsoncore.ErrElementNotFound
+		}
+		return convertFromCoreValue(v), err
+	default:
+		return RawValue{}, bsoncore.InvalidDepthTraversalError{Key: parent, Type: bsoncore.Type(val.Type)}
+	}
+}
+
+// unescapePointerToken replaces the "~1" and "~0" escape sequences of a JSON Pointer reference
+// token with "/" and "~".
+func unescapePointerToken(tok string) string {
+	if !strings.Contains(tok, "~") {
+		return tok
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+}
+
+// WalkFunc is the type of the function called by Raw.Walk for each value in a document. The path
+// holds the keys leading from the root document to the value, with array indexes as their decimal
+// string representation. The path slice is reused between calls, so it must be copied if it is
+// retained; the RawValue refers to the bytes of the walked document.
+//
+// If the function returns SkipValue when called for a document or an array, Walk does not descend
+// into it. If it returns SkipAll, Walk stops and returns nil. Any other non-nil error stops the
+// walk and is returned by Walk.
+type WalkFunc func(path []string, val RawValue) error
+
+// SkipValue is used as a return value from a WalkFunc to skip the contents of the current document
+// or array.
+var SkipValue = errors.New("skip this value")
+
+// SkipAll is used as a return value from a WalkFunc to stop walking the document.
+var SkipAll = errors.New("skip everything")
+
+// Walk calls fn for each value in the document in depth-first order, descending into embedded
+// documents and arrays after fn has been called for them. The values are read directly from r
+// without allocating intermediate documents.
+func (r Raw) Walk(fn WalkFunc) error {
+	err := walkDocument(bsoncore.Document(r), make([]string, 0, 8), fn)
+	if errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func walkDocument(doc bsoncore.Document, path []string, fn WalkFunc) error {
+	length, rem, ok := bsoncore.ReadLength(doc)
+	if !ok || length < 5 || int(length) > len(doc) {
+		return bsoncore.NewInsufficientBytesError(doc, rem)
+	}
+	rem = doc[4 : length-1]
+
+	for len(rem) > 0 {
+		var elem bsoncore.Element
+		elem, rem, ok = bsoncore.ReadElement(rem)
+		if !ok {
+			return bsoncore.NewInsufficientBytesError(doc, rem)
+		}
+		key, err := elem.KeyErr()
+		if err != nil {
+			return err
+		}
+		v, err := elem.ValueErr()
+		if err != nil {
+			return err
+		}
+
+		elemPath := append(path, key)
+		err = fn(elemPath, convertFromCoreValue(v))
+		if errors.Is(err, SkipValue) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if v.Type == bsoncore.TypeEmbeddedDocument || v.Type == bsoncore.TypeArray {
+			if err := walkDocument(v.Data, elemPath, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func TestRawLookupPath(t *testing.T) {
+	doc, err := Marshal(D{
+		{"a", D{
+			{"b", A{int32(0), int32(1), "two", D{{"c", "found"}}}},
+			{"x/y", D{{"m~n", true}}},
+		}},
+		{"s", "str"},
+	})
+	require.NoError(t, err, "Marshal error: %v", err)
+
+	testCases := []struct {
+		name    string
+		path    string
+		want    RawValue
+		wantErr error
+	}{
+		{"dotted", "a.b.3.c", RawValue{Type: TypeString, Value: bsoncore.AppendString(nil, "found")}, nil},
+		{"array element", "a.b.2", RawValue{Type: TypeString, Value: bsoncore.AppendString(nil, "two")}, nil},
+		{"pointer", "/a/b/3/c", RawValue{Type: TypeString, Value: bsoncore.AppendString(nil, "found")}, nil},
+		{"pointer escapes", "/a/x~1y/m~0n", RawValue{Type: TypeBoolean, Value: []byte{0x01}}, nil},
+		{"missing key", "a.z", RawValue{}, bsoncore.ErrElementNotFound},
+		{"index out of bounds", "a.b.4", RawValue{}, bsoncore.ErrElementNotFound},
+		{"empty", "", RawValue{}, bsoncore.ErrEmptyKey},
+		{
+			"traverse string",
+			"s.t",
+			RawValue{},
+			bsoncore.InvalidDepthTraversalError{Key: "s", Type: bsoncore.TypeString},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Raw(doc).LookupPathErr(tc.path)
+			assert.Equal(t, tc.wantErr, err, "expected error %v, got %v", tc.wantErr, err)
+			assert.True(t, tc.want.Equal(got), "expected value %v, got %v", tc.want, got)
+		})
+	}
+
+	t.Run("invalid array index", func(t *testing.T) {
+		_, err := Raw(doc).LookupPathErr("a.b.c")
+		assert.ErrorContains(t, err, `invalid array index "c"`, "expected invalid array index error")
+	})
+}
+
+func TestRawWalk(t *testing.T) {
+	doc, err := Marshal(D{
+		{"a", int32(1)},
+		{"b", D{{"c", A{"x", "y"}}, {"d", true}}},
+		{"e", D{{"f", "skipped"}}},
+		{"g", "last"},
+	})
+	require.NoError(t, err, "Marshal error: %v", err)
+
+	t.Run("visits every value", func(t *testing.T) {
+		var got []string
+		err := Raw(doc).Walk(func(path []string, val RawValue) error {
+			got = append(got, strings.Join(path, ".")+":"+val.Type.String())
+			return nil
+		})
+		require.NoError(t, err, "Walk error: %v", err)
+
+		want := []string{
+			"a:32-bit integer",
+			"b:embedded document",
+			"b.c:array",
+			"b.c.0:string",
+			"b.c.1:string",
+			"b.d:boolean",
+			"e:embedded document",
+			"e.f:string",
+			"g:string",
+		}
+		assert.Equal(t, want, got, "expected visited paths to match")
+	})
+	t.Run("SkipValue and SkipAll", func(t *testing.T) {
+		var got []string
+		err := Raw(doc).Walk(func(path []string, _ RawValue) error {
+			p := strings.Join(path, ".")
+			got = append(got, p)
+			switch p {
+			case "b", "e":
+				return SkipValue
+			case "g":
+				return SkipAll
+			}
+			return nil
+		})
+		require.NoError(t, err, "Walk error: %v", err)
+		assert.Equal(t, []string{"a", "b", "e", "g"}, got, "expected skipped documents not to be visited")
+	})
+	t.Run("error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := Raw(doc).Walk(func([]string, RawValue) error { return errStop })
+		assert.ErrorIs(t, err, errStop, "expected WalkFunc error to be returned")
+
+		err = Raw(doc[:len(doc)-3]).Walk(func([]string, RawValue) error { return nil })
+		assert.Error(t, err, "expected error for a truncated document")
+	})
+}
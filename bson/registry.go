@@ -80,6 +80,10 @@ func (entme errNoTypeMapEntry) Error() string {
 // behavior for all values for a specific kind.
 //
 // Read [Registry.LookupDecoder] and [Registry.LookupEncoder] for Registry lookup procedure.
+//
+// Additionally, named encoders/decoders can be registered using the RegisterNamedEncoder and
+// RegisterNamedDecoder methods. They are never selected by type, but can be chosen for a single
+// struct field with the "codec" struct tag option (e.g. `bson:"ts,codec=unixMilli"`).
 type Registry struct {
 	interfaceEncoders []interfaceValueEncoder
 	interfaceDecoders []interfaceValueDecoder
@@ -88,6 +92,8 @@ type Registry struct {
 	kindEncoders      *kindEncoderCache
 	kindDecoders      *kindDecoderCache
 	typeMap           sync.Map // map[Type]reflect.Type
+	namedEncoders     sync.Map // map[string]ValueEncoder
+	namedDecoders     sync.Map // map[string]ValueDecoder
 }
 
 // NewRegistry creates a new empty Registry.
@@ -223,6 +229,44 @@ func (r *Registry) RegisterTypeMapEntry(bt Type, rt reflect.Type) {
 	r.typeMap.Store(bt, rt)
 }
 
+// RegisterNamedEncoder registers the provided ValueEncoder under the provided name. A named encoder
+// is only used for struct fields that select it with the "codec" struct tag option, which makes it
+// possible to store values of the same type in different formats:
+//
+//	type Event struct {
+//	    Created time.Time `bson:"created"`
+//	    Legacy  time.Time `bson:"legacy,codec=unixMilli"`
+//	}
+//
+// Named encoders must be registered before the Registry is first used to encode a struct that
+// refers to them.
+func (r *Registry) RegisterNamedEncoder(name string, enc ValueEncoder) {
+	r.namedEncoders.Store(name, enc)
+}
+
+// RegisterNamedDecoder registers the provided ValueDecoder under the provided name. See
+// RegisterNamedEncoder for how named codecs are selected.
+func (r *Registry) RegisterNamedDecoder(name string, dec ValueDecoder) {
+	r.namedDecoders.Store(name, dec)
+}
+
+// lookupNamedCodec returns the encoder and decoder registered under name. Either may be nil if only
+// the other one was registered. An error is returned if neither was registered.
+func (r *Registry) lookupNamedCodec(name string) (ValueEncoder, ValueDecoder, error) {
+	var enc ValueEncoder
+	var dec ValueDecoder
+	if v, ok := r.namedEncoders.Load(name); ok {
+		enc = v.(ValueEncoder)
+	}
+	if v, ok := r.namedDecoders.Load(name); ok {
+		dec = v.(ValueDecoder)
+	}
+	if enc == nil && dec == nil {
+		return nil, nil, fmt.Errorf("no encoder or decoder registered with name %q", name)
+	}
+	return enc, dec, nil
+}
+
 // LookupEncoder returns the first matching encoder in the Registry. It uses the following lookup
 // order:
 //
@@ -257,6 +301,11 @@ func (r *Registry) LookupEncoder(valueType reflect.Type) (ValueEncoder, error) {
 		return r.typeEncoders.LoadOrStore(valueType, enc), nil
 	}
 
+	enc, found = r.lookupInteropEncoder(valueType)
+	if found {
+		return r.typeEncoders.LoadOrStore(valueType, enc), nil
+	}
+
 	if v, ok := r.kindEncoders.Load(valueType.Kind()); ok {
 		return r.storeTypeEncoder(valueType, v), nil
 	}
@@ -326,6 +375,11 @@ func (r *Registry) LookupDecoder(valueType reflect.Type) (ValueDecoder, error) {
 		return r.storeTypeDecoder(valueType, dec), nil
 	}
 
+	dec, found = r.lookupInteropDecoder(valueType)
+	if found {
+		return r.storeTypeDecoder(valueType, dec), nil
+	}
+
 	if v, ok := r.kindDecoders.Load(valueType.Kind()); ok {
 		return r.storeTypeDecoder(valueType, v), nil
 	}
@@ -0,0 +1,45 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+// maxInternedStrings is the maximum number of distinct strings held by a stringInterner. Once it is
+// reached, new strings are allocated as usual so that decoding many unique values cannot grow the
+// table without bound.
+const maxInternedStrings = 4096
+
+// stringInterner returns a single shared string for each distinct sequence of bytes it is given. It
+// is not safe for concurrent use.
+type stringInterner struct {
+	strings     map[string]string
+	maxValueLen int
+}
+
+func newStringInterner(maxValueLen int) *stringInterner {
+	return &stringInterner{
+		strings:     make(map[string]string),
+		maxValueLen: maxValueLen,
+	}
+}
+
+// intern returns the interned string for b.
+func (si *stringInterner) intern(b []byte) string {
+	// The compiler does not allocate when converting b to a string for a map lookup.
+	if s, ok := si.strings[string(b)]; ok {
+		return s
+	}
+
+	s := string(b)
+	if len(si.strings) < maxInternedStrings {
+		si.strings[s] = s
+	}
+	return s
+}
+
+// internValue reports whether string values of length n should be interned.
+func (si *stringInterner) internValue(n int) bool {
+	return si != nil && n <= si.maxValueLen
+}
@@ -0,0 +1,78 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// stringData returns a pointer to the bytes backing s.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestDecoderInternStrings(t *testing.T) {
+	longKey := strings.Repeat("k", 8192)
+	docs := []D{
+		{{"status", "active"}, {"description", "first document"}, {longKey, int32(1)}},
+		{{"status", "active"}, {"description", "second document"}, {longKey, int32(2)}},
+	}
+
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		b, err := Marshal(doc)
+		require.NoError(t, err, "Marshal error: %v", err)
+		buf.Write(b)
+	}
+	data := buf.Bytes()
+
+	decodeAll := func(t *testing.T, dec *Decoder) []D {
+		t.Helper()
+
+		got := make([]D, len(docs))
+		for i := range got {
+			err := dec.Decode(&got[i])
+			require.NoError(t, err, "Decode error: %v", err)
+		}
+		assert.Equal(t, docs, got, "expected decoded documents to match")
+		return got
+	}
+
+	t.Run("keys", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.InternStrings(0)
+		got := decodeAll(t, dec)
+
+		assert.Equal(t, stringData(got[0][0].Key), stringData(got[1][0].Key), "expected keys to be interned")
+		assert.Equal(t, stringData(got[0][2].Key), stringData(got[1][2].Key),
+			"expected keys longer than the read buffer to be interned")
+		assert.NotEqual(t, stringData(got[0][0].Value.(string)), stringData(got[1][0].Value.(string)),
+			"expected values not to be interned")
+	})
+	t.Run("short values", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.InternStrings(len("active"))
+		got := decodeAll(t, dec)
+
+		assert.Equal(t, stringData(got[0][0].Value.(string)), stringData(got[1][0].Value.(string)),
+			"expected short values to be interned")
+		assert.NotEqual(t, stringData(got[0][1].Key), stringData(got[0][1].Value.(string)),
+			"expected long values not to be interned")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		got := decodeAll(t, NewDecoder(NewDocumentReader(bytes.NewReader(data))))
+
+		assert.NotEqual(t, stringData(got[0][0].Key), stringData(got[1][0].Key), "expected keys not to be interned")
+	})
+}
@@ -14,6 +14,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 )
 
 // DecodeError represents an error that occurs when unmarshalling BSON bytes into a native Go type.
@@ -103,12 +105,57 @@ func (sc *structCodec) EncodeValue(ec EncodeContext, vw ValueWriter, val reflect
 		return err
 	}
 
+	if ec.sortFields && sd.inlineMap >= 0 {
+		return sc.encodeSorted(ec, vw, val, sd)
+	}
+	return sc.encodeStruct(ec, vw, val, sd)
+}
+
+// encodeSorted encodes the struct val, which has an inline map, into a temporary document and
+// copies the document to vw with its elements sorted by key, so that the elements of the inline map
+// are sorted with the struct fields.
+func (sc *structCodec) encodeSorted(ec EncodeContext, vw ValueWriter, val reflect.Value, sd *structDescription) error {
+	tmp := vwPool.Get().(*valueWriter)
+	defer putValueWriter(tmp)
+
+	tmp.reset(tmp.buf[:0])
+	if err := sc.encodeStruct(ec, tmp, val, sd); err != nil {
+		return err
+	}
+	sorted, err := sortDocumentElements(tmp.buf)
+	if err != nil {
+		return err
+	}
+	return copyDocumentFromBytes(vw, sorted)
+}
+
+// sortDocumentElements returns a copy of the document doc with its elements sorted by key.
+func sortDocumentElements(doc []byte) ([]byte, error) {
+	elems, err := bsoncore.Document(doc).Elements()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(elems, func(i, j int) bool {
+		return elems[i].Key() < elems[j].Key()
+	})
+	idx, dst := bsoncore.AppendDocumentStart(make([]byte, 0, len(doc)))
+	for _, elem := range elems {
+		dst = append(dst, elem...)
+	}
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+func (sc *structCodec) encodeStruct(ec EncodeContext, vw ValueWriter, val reflect.Value, sd *structDescription) error {
 	dw, err := vw.WriteDocument()
 	if err != nil {
 		return err
 	}
+	fields := sd.fl
+	if ec.sortFields {
+		fields = sd.sortedFl
+	}
 	var rv reflect.Value
-	for _, desc := range sd.fl {
+	for _, desc := range fields {
 		if desc.inline == nil {
 			rv = val.Field(desc.idx)
 		} else {
@@ -163,15 +210,18 @@ func (sc *structCodec) EncodeValue(ec EncodeContext, vw ValueWriter, val reflect
 		}
 
 		ectx := EncodeContext{
-			Registry:                ec.Registry,
-			minSize:                 desc.minSize || ec.minSize,
-			errorOnInlineDuplicates: ec.errorOnInlineDuplicates,
-			stringifyMapKeysWithFmt: ec.stringifyMapKeysWithFmt,
-			nilMapAsEmpty:           ec.nilMapAsEmpty,
-			nilSliceAsEmpty:         ec.nilSliceAsEmpty,
-			nilByteSliceAsEmpty:     ec.nilByteSliceAsEmpty,
-			omitZeroStruct:          ec.omitZeroStruct,
-			useJSONStructTags:       ec.useJSONStructTags,
+			Registry:                 ec.Registry,
+			minSize:                  desc.minSize || ec.minSize,
+			errorOnInlineDuplicates:  ec.errorOnInlineDuplicates,
+			stringifyMapKeysWithFmt:  ec.stringifyMapKeysWithFmt,
+			nilMapAsEmpty:            ec.nilMapAsEmpty,
+			nilSliceAsEmpty:          ec.nilSliceAsEmpty,
+			nilByteSliceAsEmpty:      ec.nilByteSliceAsEmpty,
+			omitZeroStruct:           ec.omitZeroStruct,
+			useJSONStructTags:        ec.useJSONStructTags,
+			useJSONAndTextMarshalers: ec.useJSONAndTextMarshalers,
+			timeEncoding:             ec.timeEncoding,
+			sortFields:               ec.sortFields,
 		}
 		err = encoder.EncodeValue(ectx, vw2, rv)
 		if err != nil {
@@ -263,8 +313,18 @@ func (sc *structCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect
 		return err
 	}
 
+	// If the document is read by a valueReader, read the keys as bytes so known fields can be looked
+	// up without allocating a string for every element.
+	kr, _ := dr.(*valueReader)
 	for {
-		name, vr, err := dr.ReadElement()
+		var name string
+		var key []byte
+		var vr ValueReader
+		if kr != nil {
+			key, vr, err = kr.readElementKey()
+		} else {
+			name, vr, err = dr.ReadElement()
+		}
 		if errors.Is(err, ErrEOD) {
 			break
 		}
@@ -272,7 +332,17 @@ func (sc *structCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect
 			return err
 		}
 
-		fd, exists := sd.fm[name]
+		var fd fieldDescription
+		var exists bool
+		if kr != nil {
+			// Converting key to a string for the map lookup does not allocate.
+			fd, exists = sd.fm[string(key)]
+			if !exists {
+				name = string(key)
+			}
+		} else {
+			fd, exists = sd.fm[name]
+		}
 		if !exists {
 			// if the original name isn't found in the struct description, try again with the name in lowercase
 			// this could match if a BSON tag isn't specified because by default, describeStruct lowercases all field
@@ -337,15 +407,17 @@ func (sc *structCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect
 		field = field.Addr()
 
 		dctx := DecodeContext{
-			Registry:            dc.Registry,
-			truncate:            fd.truncate || dc.truncate,
-			defaultDocumentType: dc.defaultDocumentType,
-			binaryAsSlice:       dc.binaryAsSlice,
-			objectIDAsHexString: dc.objectIDAsHexString,
-			useJSONStructTags:   dc.useJSONStructTags,
-			useLocalTimeZone:    dc.useLocalTimeZone,
-			zeroMaps:            dc.zeroMaps,
-			zeroStructs:         dc.zeroStructs,
+			Registry:                 dc.Registry,
+			truncate:                 fd.truncate || dc.truncate,
+			defaultDocumentType:      dc.defaultDocumentType,
+			binaryAsSlice:            dc.binaryAsSlice,
+			objectIDAsHexString:      dc.objectIDAsHexString,
+			useJSONStructTags:        dc.useJSONStructTags,
+			useLocalTimeZone:         dc.useLocalTimeZone,
+			zeroMaps:                 dc.zeroMaps,
+			zeroStructs:              dc.zeroStructs,
+			useJSONAndTextMarshalers: dc.useJSONAndTextMarshalers,
+			allocator:                dc.allocator,
 		}
 
 		if fd.decoder == nil {
@@ -395,6 +467,7 @@ func isEmpty(v reflect.Value, omitZeroStruct bool) bool {
 type structDescription struct {
 	fm        map[string]fieldDescription
 	fl        []fieldDescription
+	sortedFl  []fieldDescription // the fields of fl sorted by name
 	inlineMap int
 	inline    bool
 }
@@ -520,6 +593,29 @@ func (sc *structCodec) describeStructSlow(
 		description.minSize = stags.MinSize
 		description.truncate = stags.Truncate
 
+		if stags.Decimal128 {
+			if stags.Codec != "" || !decimal128FieldKind(sfType) {
+				return nil, fmt.Errorf("(struct %s) field %s: the decimal128 option requires a float32, "+
+					"float64, or string field without a codec option", t.String(), sf.Name)
+			}
+			codec := &decimal128FieldCodec{fallback: description.decoder}
+			description.encoder = codec
+			description.decoder = codec
+		}
+
+		if stags.Codec != "" {
+			enc, dec, err := r.lookupNamedCodec(stags.Codec)
+			if err != nil {
+				return nil, fmt.Errorf("(struct %s) field %s: %w", t.String(), sf.Name, err)
+			}
+			if enc != nil {
+				description.encoder = enc
+			}
+			if dec != nil {
+				description.decoder = dec
+			}
+		}
+
 		if stags.Inline {
 			sd.inline = true
 			switch sfType.Kind() {
@@ -597,6 +693,7 @@ func (sc *structCodec) describeStructSlow(
 		sd.fm[name] = dominant
 	}
 
+	sd.sortedFl = append(make([]fieldDescription, 0, len(sd.fl)), sd.fl...)
 	sort.Sort(byIndex(sd.fl))
 
 	return sd, nil
@@ -7,11 +7,13 @@
 package bson
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 )
 
 var _ Zeroer = testZeroer{}
@@ -168,3 +170,58 @@ func TestIsZero(t *testing.T) {
 		})
 	}
 }
+
+func TestStructCodecNamedCodec(t *testing.T) {
+	t.Parallel()
+
+	unixMilliEncoder := ValueEncoderFunc(func(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+		return vw.WriteInt64(val.Interface().(time.Time).UnixMilli())
+	})
+	unixMilliDecoder := ValueDecoderFunc(func(_ DecodeContext, vr ValueReader, val reflect.Value) error {
+		ms, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		val.Set(reflect.ValueOf(time.UnixMilli(ms).UTC()))
+		return nil
+	})
+
+	type event struct {
+		Created time.Time `bson:"created"`
+		Legacy  time.Time `bson:"legacy,codec=unixMilli"`
+	}
+
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		reg := NewRegistry()
+		reg.RegisterNamedEncoder("unixMilli", unixMilliEncoder)
+		reg.RegisterNamedDecoder("unixMilli", unixMilliDecoder)
+
+		buf := new(bytes.Buffer)
+		enc := NewEncoder(NewDocumentWriter(buf))
+		enc.SetRegistry(reg)
+		err := enc.Encode(event{Created: ts, Legacy: ts})
+		require.NoError(t, err, "Encode error: %v", err)
+
+		doc := Raw(buf.Bytes())
+		assert.Equal(t, TypeDateTime, doc.Lookup("created").Type, "expected created to use the time.Time encoder")
+		assert.Equal(t, ts.UnixMilli(), doc.Lookup("legacy").Int64(), "expected legacy to use the named encoder")
+
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(doc)))
+		dec.SetRegistry(reg)
+		var got event
+		err = dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, event{Created: ts, Legacy: ts}, got, "expected event to round trip")
+	})
+	t.Run("unregistered", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Marshal(event{})
+		assert.ErrorContains(t, err, `no encoder or decoder registered with name "unixMilli"`,
+			"expected error for unregistered named codec")
+	})
+}
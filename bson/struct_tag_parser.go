@@ -27,7 +27,14 @@ import (
 //	           feasible while preserving the numeric value.
 //
 //	Truncate   When unmarshaling a BSON double, it is permitted to lose precision to fit within
-//	           a float32.
+//	           a float32. For Decimal128 fields, it is permitted to lose precision when
+//	           unmarshaling a BSON decimal128 into a float32 or float64.
+//
+//	Decimal128 Marshal a float32, float64, or string field, or a pointer to one, as a BSON
+//	           decimal128, and unmarshal BSON decimal128 values into it. Strings must be valid
+//	           decimal128 values. Unmarshaling returns an error if the decimal128 value is out
+//	           of the range of a float field, or cannot be represented exactly by it unless
+//	           Truncate is also set. Other BSON types are unmarshaled as usual for the field type.
 //
 //	Inline     Inline the field, which must be a struct or a map, causing all of its fields
 //	           or keys to be processed as if they were part of the outer struct. For maps,
@@ -35,13 +42,19 @@ import (
 //
 //	Skip       This struct field should be skipped. This is usually denoted by parsing a "-"
 //	           for the name.
+//
+//	Codec      The name of an encoder and decoder registered with Registry.RegisterNamedEncoder
+//	           and Registry.RegisterNamedDecoder to use for the field instead of the ones
+//	           registered for its type. This is denoted by parsing "codec=<name>".
 type structTags struct {
-	Name      string
-	OmitEmpty bool
-	MinSize   bool
-	Truncate  bool
-	Inline    bool
-	Skip      bool
+	Name       string
+	OmitEmpty  bool
+	MinSize    bool
+	Truncate   bool
+	Decimal128 bool
+	Inline     bool
+	Skip       bool
+	Codec      string
 }
 
 // DefaultStructTagParser is the StructTagParser used by the StructCodec by default.
@@ -64,6 +77,8 @@ type structTags struct {
 //	    D string `bson:",omitempty" json:"jsonkey"`
 //	    E int64  ",minsize"
 //	    F int64  "myf,omitempty,minsize"
+//	    G time.Time "myg,codec=unixMilli"
+//	    H float64 "price,decimal128"
 //	}
 //
 // A struct tag either consisting entirely of '-' or with a bson key with a
@@ -112,8 +127,14 @@ func parseTags(key string, tag string) (*structTags, error) {
 			st.MinSize = true
 		case "truncate":
 			st.Truncate = true
+		case "decimal128":
+			st.Decimal128 = true
 		case "inline":
 			st.Inline = true
+		default:
+			if idx > 0 && strings.HasPrefix(str, "codec=") {
+				st.Codec = strings.TrimPrefix(str, "codec=")
+			}
 		}
 	}
 
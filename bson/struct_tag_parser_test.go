@@ -50,6 +50,18 @@ func TestStructTagParsers(t *testing.T) {
 			&structTags{Name: "bar", OmitEmpty: true, MinSize: true, Truncate: true, Inline: true},
 			parseStructTags,
 		},
+		{
+			"default codec",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`bson:"bar,omitempty,codec=unixMilli"`)},
+			&structTags{Name: "bar", OmitEmpty: true, Codec: "unixMilli"},
+			parseStructTags,
+		},
+		{
+			"default decimal128",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`bson:"price,decimal128,truncate"`)},
+			&structTags{Name: "price", Decimal128: true, Truncate: true},
+			parseStructTags,
+		},
 		{
 			"default all options default name",
 			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`,omitempty,minsize,truncate,inline`)},
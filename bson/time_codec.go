@@ -16,6 +16,25 @@ const (
 	timeFormatString = "2006-01-02T15:04:05.999Z07:00"
 )
 
+// TimeEncoding specifies the BSON type that the Encoder encodes time.Time values as. Values of
+// all encodings can be decoded back into a time.Time.
+type TimeEncoding int8
+
+// These constants specify valid values for TimeEncoding.
+const (
+	// TimeEncodingDateTime encodes time.Time values as BSON datetime values with millisecond
+	// precision. This is the default.
+	TimeEncodingDateTime TimeEncoding = iota
+
+	// TimeEncodingMillis encodes time.Time values as BSON int64 values with the number of
+	// milliseconds since the Unix epoch.
+	TimeEncodingMillis
+
+	// TimeEncodingRFC3339 encodes time.Time values as BSON strings in the RFC 3339 format with
+	// nanosecond precision, converted to UTC so that the strings sort in time order.
+	TimeEncodingRFC3339
+)
+
 // timeCodec is the Codec used for time.Time values.
 type timeCodec struct {
 	// useLocalTimeZone specifies if we should decode into the local time zone. Defaults to false.
@@ -99,11 +118,21 @@ func (tc *timeCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect.V
 }
 
 // EncodeValue is the ValueEncoderFunc for time.TIme.
-func (tc *timeCodec) EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+func (tc *timeCodec) EncodeValue(ec EncodeContext, vw ValueWriter, val reflect.Value) error {
 	if !val.IsValid() || val.Type() != tTime {
 		return ValueEncoderError{Name: "TimeEncodeValue", Types: []reflect.Type{tTime}, Received: val}
 	}
 	tt := val.Interface().(time.Time)
-	dt := NewDateTimeFromTime(tt)
-	return vw.WriteDateTime(int64(dt))
+	return encodeTime(vw, tt, ec.timeEncoding)
+}
+
+func encodeTime(vw ValueWriter, t time.Time, te TimeEncoding) error {
+	switch te {
+	case TimeEncodingMillis:
+		return vw.WriteInt64(int64(NewDateTimeFromTime(t)))
+	case TimeEncodingRFC3339:
+		return vw.WriteString(t.UTC().Format(time.RFC3339Nano))
+	default:
+		return vw.WriteDateTime(int64(NewDateTimeFromTime(t)))
+	}
 }
@@ -7,6 +7,7 @@
 package bson
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 	"time"
@@ -78,4 +79,58 @@ func TestTimeCodec(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TimeEncoding", func(t *testing.T) {
+		tt := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.FixedZone("UTC+2", 2*60*60))
+		testCases := []struct {
+			name     string
+			encoding TimeEncoding
+			want     RawValue
+			decoded  time.Time
+		}{
+			{
+				name:     "DateTime",
+				encoding: TimeEncodingDateTime,
+				want:     RawValue{Type: TypeDateTime, Value: bsoncore.AppendDateTime(nil, tt.UnixMilli())},
+				decoded:  tt.Truncate(time.Millisecond).UTC(),
+			},
+			{
+				name:     "Millis",
+				encoding: TimeEncodingMillis,
+				want:     RawValue{Type: TypeInt64, Value: bsoncore.AppendInt64(nil, tt.UnixMilli())},
+				decoded:  tt.Truncate(time.Millisecond).UTC(),
+			},
+			{
+				name:     "RFC3339",
+				encoding: TimeEncodingRFC3339,
+				want:     RawValue{Type: TypeString, Value: bsoncore.AppendString(nil, "2024-03-01T10:30:00.123456789Z")},
+				decoded:  tt.UTC(),
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				buf := new(bytes.Buffer)
+				enc := NewEncoder(NewDocumentWriter(buf))
+				enc.SetTimeEncoding(tc.encoding)
+				err := enc.Encode(D{{"t", tt}, {"p", &tt}})
+				assert.Nil(t, err, "Encode error: %v", err)
+
+				doc := Raw(buf.Bytes())
+				assert.Equal(t, tc.want, doc.Lookup("t"), "expected encoded value %v, got %v", tc.want, doc.Lookup("t"))
+				assert.Equal(t, tc.want, doc.Lookup("p"), "expected encoded pointer value %v, got %v", tc.want, doc.Lookup("p"))
+
+				var got struct{ T time.Time }
+				err = Unmarshal(doc, &got)
+				assert.Nil(t, err, "Unmarshal error: %v", err)
+				assert.Equal(t, tc.decoded, got.T, "expected decoded time %v, got %v", tc.decoded, got.T)
+
+				buf = new(bytes.Buffer)
+				enc.Reset(NewDocumentWriter(buf))
+				err = enc.Encode(struct{ T time.Time }{T: tt})
+				assert.Nil(t, err, "Encode error: %v", err)
+				doc = Raw(buf.Bytes())
+				assert.Equal(t, tc.want, doc.Lookup("t"), "expected encoded struct field value %v, got %v", tc.want, doc.Lookup("t"))
+			})
+		}
+	})
 }
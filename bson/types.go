@@ -72,6 +72,7 @@ const (
 	TypeBinaryEncrypted   byte = 0x06
 	TypeBinaryColumn      byte = 0x07
 	TypeBinarySensitive   byte = 0x08
+	TypeBinaryVector      byte = 0x09
 	TypeBinaryUserDefined byte = 0x80
 )
 
@@ -95,6 +96,7 @@ var tUnmarshaler = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
 var tZeroer = reflect.TypeOf((*Zeroer)(nil)).Elem()
 
 var tBinary = reflect.TypeOf(Binary{})
+var tVector = reflect.TypeOf(Vector{})
 var tUndefined = reflect.TypeOf(Undefined{})
 var tOID = reflect.TypeOf(ObjectID{})
 var tDateTime = reflect.TypeOf(DateTime(0))
@@ -111,6 +113,7 @@ var tMaxKey = reflect.TypeOf(MaxKey{})
 var tD = reflect.TypeOf(D{})
 var tA = reflect.TypeOf(A{})
 var tE = reflect.TypeOf(E{})
+var tOrderedMap = reflect.TypeOf(OrderedMap{})
 
 var tCoreDocument = reflect.TypeOf(bsoncore.Document{})
 var tCoreArray = reflect.TypeOf(bsoncore.Array{})
@@ -38,7 +38,7 @@ type ValueUnmarshaler interface {
 // Unmarshal parses the BSON-encoded data and stores the result in the value
 // pointed to by val. If val is nil or not a pointer, Unmarshal returns an error.
 func Unmarshal(data []byte, val interface{}) error {
-	vr := newDocumentReader(bytes.NewReader(data))
+	vr := newDocumentReaderFromBytes(data)
 	if l, err := vr.peekLength(); err != nil {
 		return err
 	} else if int(l) != len(data) {
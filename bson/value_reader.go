@@ -27,6 +27,9 @@ type vrState struct {
 	mode  mode
 	vType Type
 	end   int64
+
+	// count is the number of elements read of an array.
+	count int
 }
 
 // valueReader is for reading BSON values.
@@ -36,6 +39,22 @@ type valueReader struct {
 
 	stack []vrState
 	frame int64
+
+	// interner, if set, is used to intern keys and short string values.
+	interner *stringInterner
+	scratch  []byte
+
+	// src is the document being read if it is held in memory. If zeroCopy is also set, strings and
+	// binary data refer to src instead of being copied. consumed is the number of bytes read before
+	// offset was last reset, so the position in src is consumed+offset.
+	src      []byte
+	zeroCopy bool
+	consumed int64
+
+	// limits, if set, are the limits of the documents being read. depth is the number of embedded
+	// documents and arrays being read.
+	limits *DecodeLimits
+	depth  int
 }
 
 // NewDocumentReader returns a ValueReader using b for the underlying BSON
@@ -84,12 +103,16 @@ func (vr *valueReader) advanceFrame() {
 	vr.stack[vr.frame].mode = 0
 	vr.stack[vr.frame].vType = 0
 	vr.stack[vr.frame].end = 0
+	vr.stack[vr.frame].count = 0
 }
 
 func (vr *valueReader) pushDocument() error {
 	vr.advanceFrame()
 
 	vr.stack[vr.frame].mode = mDocument
+	if err := vr.enterNested(); err != nil {
+		return err
+	}
 
 	length, err := vr.readLength()
 	if err != nil {
@@ -104,6 +127,9 @@ func (vr *valueReader) pushArray() error {
 	vr.advanceFrame()
 
 	vr.stack[vr.frame].mode = mArray
+	if err := vr.enterNested(); err != nil {
+		return err
+	}
 
 	length, err := vr.readLength()
 	if err != nil {
@@ -132,6 +158,9 @@ func (vr *valueReader) pushCodeWithScope() (int64, error) {
 	vr.advanceFrame()
 
 	vr.stack[vr.frame].mode = mCodeWithScope
+	if err := vr.enterNested(); err != nil {
+		return 0, err
+	}
 
 	length, err := vr.readLength()
 	if err != nil {
@@ -150,6 +179,9 @@ func (vr *valueReader) pop() error {
 	case mDocument, mArray, mCodeWithScope:
 		cnt = 2 // we pop twice to jump over the vrElement: vrDocument -> vrElement -> vrDocument/TopLevel/etc...
 	}
+	if cnt == 2 && vr.frame > 0 {
+		vr.depth--
+	}
 	for i := 0; i < cnt && vr.frame > 0; i++ {
 		if vr.offset < vr.stack[vr.frame].end {
 			_, err := vr.r.Discard(int(vr.stack[vr.frame].end - vr.offset))
@@ -165,11 +197,20 @@ func (vr *valueReader) pop() error {
 		} else {
 			vr.stack[0].end = 0
 		}
+		vr.consumed += vr.offset
 		vr.offset = 0
 	}
 	return nil
 }
 
+// enterNested increments the number of embedded documents and arrays being read and returns a
+// DecodeLimitError if the document is nested too deeply. The top-level document counts toward the
+// depth.
+func (vr *valueReader) enterNested() error {
+	vr.depth++
+	return vr.limits.checkDepth(vr.depth + 1)
+}
+
 func (vr *valueReader) invalidTransitionErr(destination mode, name string, modes []mode) error {
 	te := TransitionError{
 		name:        name,
@@ -253,14 +294,27 @@ func (vr *valueReader) appendNextElement(dst []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	buf := make([]byte, length)
-	_, err = io.ReadFull(vr.r, buf)
-	if err != nil {
-		return nil, err
+	return vr.appendBytes(dst, length)
+}
+
+// peekDocumentLength returns the length of the document that the next call to readValueBytes
+// would read, or 0 if the next value is not a document or its length cannot be determined.
+func (vr *valueReader) peekDocumentLength() int {
+	switch vr.stack[vr.frame].mode {
+	case mTopLevel:
+	case mElement, mValue:
+		if vr.stack[vr.frame].vType != TypeEmbeddedDocument {
+			return 0
+		}
+	default:
+		return 0
 	}
-	dst = append(dst, buf...)
-	vr.offset += int64(len(buf))
-	return dst, err
+
+	length, err := vr.peekLength()
+	if err != nil || length < 0 {
+		return 0
+	}
+	return int(length)
 }
 
 func (vr *valueReader) readValueBytes(dst []byte) (Type, []byte, error) {
@@ -270,6 +324,9 @@ func (vr *valueReader) readValueBytes(dst []byte) (Type, []byte, error) {
 		if err != nil {
 			return Type(0), nil, err
 		}
+		if err := vr.limits.checkDocumentSize(length); err != nil {
+			return Type(0), nil, err
+		}
 		dst, err = vr.appendBytes(dst, length)
 		if err != nil {
 			return Type(0), nil, err
@@ -343,10 +400,14 @@ func (vr *valueReader) ReadBinary() (b []byte, btype byte, err error) {
 		}
 	}
 
-	b = make([]byte, length)
-	err = vr.read(b)
-	if err != nil {
-		return nil, 0, err
+	if borrowed, ok := vr.borrow(int(length)); ok {
+		b = borrowed
+	} else {
+		b = make([]byte, length)
+		err = vr.read(b)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
 	if err := vr.pop(); err != nil {
@@ -385,6 +446,10 @@ func (vr *valueReader) ReadDocument() (DocumentReader, error) {
 		if length <= 4 {
 			return nil, fmt.Errorf("invalid string length: %d", length)
 		}
+		if err := vr.limits.checkDocumentSize(length); err != nil {
+			return nil, err
+		}
+		vr.depth = 0
 
 		vr.stack[vr.frame].end = int64(length) + vr.offset - 4
 		return vr, nil
@@ -664,33 +729,61 @@ func (vr *valueReader) ReadUndefined() error {
 }
 
 func (vr *valueReader) ReadElement() (string, ValueReader, error) {
+	t, err := vr.readElementType()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name, err := vr.readCString()
+	if err != nil {
+		return "", nil, err
+	}
+
+	vr.pushElement(t)
+	return name, vr, nil
+}
+
+// readElementKey is the same as ReadElement, except that it returns the key as bytes that are only
+// valid until the next read from vr. This lets callers that only use the key for a lookup avoid
+// allocating a string for every element.
+func (vr *valueReader) readElementKey() ([]byte, ValueReader, error) {
+	t, err := vr.readElementType()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := vr.readCStringBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vr.pushElement(t)
+	return key, vr, nil
+}
+
+// readElementType reads the type of the next element in the current document, returning ErrEOD
+// at the end of the document.
+func (vr *valueReader) readElementType() (Type, error) {
 	switch vr.stack[vr.frame].mode {
 	case mTopLevel, mDocument, mCodeWithScope:
 	default:
-		return "", nil, vr.invalidTransitionErr(mElement, "ReadElement", []mode{mTopLevel, mDocument, mCodeWithScope})
+		return 0, vr.invalidTransitionErr(mElement, "ReadElement", []mode{mTopLevel, mDocument, mCodeWithScope})
 	}
 
 	t, err := vr.readByte()
 	if err != nil {
-		return "", nil, err
+		return 0, err
 	}
 
 	if t == 0 {
 		if vr.offset != vr.stack[vr.frame].end {
-			return "", nil, vr.invalidDocumentLengthError()
+			return 0, vr.invalidDocumentLengthError()
 		}
 
 		_ = vr.pop() // Ignore the error because the call here never reads from the underlying reader.
-		return "", nil, ErrEOD
+		return 0, ErrEOD
 	}
-
-	name, err := vr.readCString()
-	if err != nil {
-		return "", nil, err
-	}
-
-	vr.pushElement(Type(t))
-	return name, vr, nil
+	return Type(t), nil
 }
 
 func (vr *valueReader) ReadValue() (ValueReader, error) {
@@ -718,6 +811,11 @@ func (vr *valueReader) ReadValue() (ValueReader, error) {
 		return nil, err
 	}
 
+	vr.stack[vr.frame].count++
+	if err := vr.limits.checkArrayLength(vr.stack[vr.frame].count); err != nil {
+		return nil, err
+	}
+
 	vr.pushValue(Type(t))
 	return vr, nil
 }
@@ -731,13 +829,22 @@ func (vr *valueReader) read(p []byte) error {
 	return nil
 }
 
+// appendBytes reads length bytes and appends them to dst. The bytes are read directly into the
+// spare capacity of dst if it is large enough.
 func (vr *valueReader) appendBytes(dst []byte, length int32) ([]byte, error) {
-	buf := make([]byte, length)
-	err := vr.read(buf)
+	start := len(dst)
+	if cap(dst)-start < int(length) {
+		grown := make([]byte, start, start+int(length))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:start+int(length)]
+
+	err := vr.read(dst[start:])
 	if err != nil {
 		return nil, err
 	}
-	return append(dst, buf...), nil
+	return dst, nil
 }
 
 func (vr *valueReader) readByte() (byte, error) {
@@ -750,6 +857,10 @@ func (vr *valueReader) readByte() (byte, error) {
 }
 
 func (vr *valueReader) readCString() (string, error) {
+	if vr.interner != nil {
+		return vr.readInternedCString()
+	}
+
 	str, err := vr.r.ReadString(0x00)
 	if err != nil {
 		return "", err
@@ -759,6 +870,38 @@ func (vr *valueReader) readCString() (string, error) {
 	return str[:l-1], nil
 }
 
+// readInternedCString reads a cstring directly from the buffer so that strings that have already
+// been interned can be returned without allocating. Strings that do not fit in the buffer are
+// collected in the scratch buffer first.
+func (vr *valueReader) readInternedCString() (string, error) {
+	b, err := vr.readCStringBytes()
+	if err != nil {
+		return "", err
+	}
+	return vr.interner.intern(b), nil
+}
+
+// readCStringBytes reads a C string and returns it without the null terminator. The returned bytes
+// refer to the read buffer or to vr.scratch and are only valid until the next read.
+func (vr *valueReader) readCStringBytes() ([]byte, error) {
+	vr.scratch = vr.scratch[:0]
+	for {
+		b, err := vr.r.ReadSlice(0x00)
+		vr.offset += int64(len(b))
+		if err == nil {
+			if len(vr.scratch) == 0 {
+				return b[:len(b)-1], nil
+			}
+			vr.scratch = append(vr.scratch, b[:len(b)-1]...)
+			return vr.scratch, nil
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, err
+		}
+		vr.scratch = append(vr.scratch, b...)
+	}
+}
+
 func (vr *valueReader) readString() (string, error) {
 	length, err := vr.readLength()
 	if err != nil {
@@ -768,7 +911,24 @@ func (vr *valueReader) readString() (string, error) {
 		return "", fmt.Errorf("invalid string length: %d", length)
 	}
 
-	buf := make([]byte, length)
+	if buf, ok := vr.borrow(int(length)); ok {
+		if buf[length-1] != 0x00 {
+			return "", fmt.Errorf("string does not end with null byte, but with %v", buf[length-1])
+		}
+		return unsafeString(buf[:length-1]), nil
+	}
+
+	intern := vr.interner.internValue(int(length) - 1)
+
+	var buf []byte
+	if intern {
+		if cap(vr.scratch) < int(length) {
+			vr.scratch = make([]byte, length)
+		}
+		buf = vr.scratch[:length]
+	} else {
+		buf = make([]byte, length)
+	}
 	err = vr.read(buf)
 	if err != nil {
 		return "", err
@@ -778,6 +938,9 @@ func (vr *valueReader) readString() (string, error) {
 		return "", fmt.Errorf("string does not end with null byte, but with %v", buf[length-1])
 	}
 
+	if intern {
+		return vr.interner.intern(buf[:length-1]), nil
+	}
 	return string(buf[:length-1]), nil
 }
 
@@ -0,0 +1,177 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Vector data types, stored in the first byte of a binary vector (subtype 9).
+const (
+	VectorInt8      byte = 0x03
+	VectorFloat32   byte = 0x27
+	VectorPackedBit byte = 0x10
+)
+
+// These are the errors returned when constructing or decoding an invalid Vector.
+var (
+	// ErrVectorPaddingInvalid is returned when the padding of a packed bit vector is greater than
+	// 7, or is non-zero for an empty vector.
+	ErrVectorPaddingInvalid = errors.New("padding must be 0 for an empty packed bit vector and between 0 and 7 otherwise")
+
+	// ErrNotVector is returned when a Binary value does not have subtype TypeBinaryVector.
+	ErrNotVector = errors.New("binary subtype is not a vector")
+
+	// ErrInsufficientVectorData is returned when a binary vector is too short to contain its header
+	// or its declared elements.
+	ErrInsufficientVectorData = errors.New("insufficient data for vector")
+
+	// ErrNonZeroVectorPadding is returned when the padding of a non-packed bit vector is not 0.
+	ErrNonZeroVectorPadding = errors.New("padding must be 0 for int8 and float32 vectors")
+)
+
+// Vector represents a BSON binary vector (binary subtype 9). A Vector holds int8, float32, or
+// packed bit elements and can be used as a struct field or value in place of a Binary so embeddings
+// round-trip without manual subtype packing.
+//
+// The zero value of Vector is an empty int8 vector.
+type Vector struct {
+	dType       byte
+	int8Data    []int8
+	float32Data []float32
+	bitData     []byte
+	bitPadding  uint8
+}
+
+// NewVector returns an int8 or float32 Vector that holds data.
+func NewVector[T int8 | float32](data []T) Vector {
+	switch d := any(data).(type) {
+	case []float32:
+		return Vector{dType: VectorFloat32, float32Data: d}
+	case []int8:
+		return Vector{dType: VectorInt8, int8Data: d}
+	}
+	// Unreachable because of the type constraint.
+	return Vector{}
+}
+
+// NewPackedBitVector returns a packed bit Vector. Each byte of bits holds 8 vector elements, most
+// significant bit first. The padding is the number of least significant bits of the final byte that
+// are not part of the vector. It returns ErrVectorPaddingInvalid if the padding is invalid.
+func NewPackedBitVector(bits []byte, padding uint8) (Vector, error) {
+	if padding > 7 || (len(bits) == 0 && padding != 0) {
+		return Vector{}, ErrVectorPaddingInvalid
+	}
+	return Vector{dType: VectorPackedBit, bitData: bits, bitPadding: padding}, nil
+}
+
+// NewVectorFromBinary decodes the Vector stored in b. It returns an error if b does not have subtype
+// TypeBinaryVector or does not contain a valid vector.
+func NewVectorFromBinary(b Binary) (Vector, error) {
+	if b.Subtype != TypeBinaryVector {
+		return Vector{}, ErrNotVector
+	}
+	return newVectorFromData(b.Data)
+}
+
+func newVectorFromData(data []byte) (Vector, error) {
+	if len(data) < 2 {
+		return Vector{}, ErrInsufficientVectorData
+	}
+	dType, padding, body := data[0], data[1], data[2:]
+
+	switch dType {
+	case VectorInt8:
+		if padding != 0 {
+			return Vector{}, ErrNonZeroVectorPadding
+		}
+		values := make([]int8, len(body))
+		for i, b := range body {
+			values[i] = int8(b)
+		}
+		return NewVector(values), nil
+	case VectorFloat32:
+		if padding != 0 {
+			return Vector{}, ErrNonZeroVectorPadding
+		}
+		if len(body)%4 != 0 {
+			return Vector{}, ErrInsufficientVectorData
+		}
+		values := make([]float32, len(body)/4)
+		for i := range values {
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(body[4*i:]))
+		}
+		return NewVector(values), nil
+	case VectorPackedBit:
+		bits := make([]byte, len(body))
+		copy(bits, body)
+		return NewPackedBitVector(bits, padding)
+	default:
+		return Vector{}, fmt.Errorf("invalid vector data type 0x%02x", dType)
+	}
+}
+
+// Type returns the data type of the vector: VectorInt8, VectorFloat32, or VectorPackedBit.
+func (v Vector) Type() byte {
+	if v.dType == 0 {
+		return VectorInt8
+	}
+	return v.dType
+}
+
+// Int8OK returns the elements of an int8 vector and true, or nil and false if v is not an int8
+// vector.
+func (v Vector) Int8OK() ([]int8, bool) {
+	if v.Type() != VectorInt8 {
+		return nil, false
+	}
+	return v.int8Data, true
+}
+
+// Float32OK returns the elements of a float32 vector and true, or nil and false if v is not a
+// float32 vector.
+func (v Vector) Float32OK() ([]float32, bool) {
+	if v.Type() != VectorFloat32 {
+		return nil, false
+	}
+	return v.float32Data, true
+}
+
+// PackedBitOK returns the bytes and padding of a packed bit vector and true, or nil, 0, and false
+// if v is not a packed bit vector.
+func (v Vector) PackedBitOK() ([]byte, uint8, bool) {
+	if v.Type() != VectorPackedBit {
+		return nil, 0, false
+	}
+	return v.bitData, v.bitPadding, true
+}
+
+// Binary returns v encoded as a Binary value with subtype TypeBinaryVector.
+func (v Vector) Binary() Binary {
+	var data []byte
+	switch v.Type() {
+	case VectorFloat32:
+		data = make([]byte, 2+4*len(v.float32Data))
+		for i, f := range v.float32Data {
+			binary.LittleEndian.PutUint32(data[2+4*i:], math.Float32bits(f))
+		}
+	case VectorPackedBit:
+		data = make([]byte, 2, 2+len(v.bitData))
+		data[1] = v.bitPadding
+		data = append(data, v.bitData...)
+	default:
+		data = make([]byte, 2, 2+len(v.int8Data))
+		for _, i := range v.int8Data {
+			data = append(data, byte(i))
+		}
+	}
+	data[0] = v.Type()
+	return Binary{Subtype: TypeBinaryVector, Data: data}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+)
+
+func TestVectorBinary(t *testing.T) {
+	t.Run("float32", func(t *testing.T) {
+		b := NewVector([]float32{127, 7}).Binary()
+		assert.Equal(t, TypeBinaryVector, b.Subtype, "unexpected subtype")
+		assert.Equal(t, []byte{0x27, 0x00, 0x00, 0x00, 0xFE, 0x42, 0x00, 0x00, 0xE0, 0x40}, b.Data, "unexpected data")
+	})
+	t.Run("int8", func(t *testing.T) {
+		b := NewVector([]int8{127, -128}).Binary()
+		assert.Equal(t, TypeBinaryVector, b.Subtype, "unexpected subtype")
+		assert.Equal(t, []byte{0x03, 0x00, 0x7F, 0x80}, b.Data, "unexpected data")
+	})
+	t.Run("packed bit", func(t *testing.T) {
+		v, err := NewPackedBitVector([]byte{0xFF, 0xE0}, 5)
+		assert.NoError(t, err, "NewPackedBitVector error: %v", err)
+		b := v.Binary()
+		assert.Equal(t, TypeBinaryVector, b.Subtype, "unexpected subtype")
+		assert.Equal(t, []byte{0x10, 0x05, 0xFF, 0xE0}, b.Data, "unexpected data")
+	})
+	t.Run("packed bit invalid padding", func(t *testing.T) {
+		_, err := NewPackedBitVector([]byte{0xFF}, 8)
+		assert.ErrorIs(t, err, ErrVectorPaddingInvalid, "expected ErrVectorPaddingInvalid")
+
+		_, err = NewPackedBitVector(nil, 1)
+		assert.ErrorIs(t, err, ErrVectorPaddingInvalid, "expected ErrVectorPaddingInvalid")
+	})
+}
+
+func TestVector(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		bits, err := NewPackedBitVector([]byte{0xFF, 0x80}, 7)
+		assert.NoError(t, err, "NewPackedBitVector error: %v", err)
+
+		type embedding struct {
+			Float32 Vector
+			Int8    Vector
+			Bits    Vector
+		}
+		want := embedding{
+			Float32: NewVector([]float32{1.5, -2}),
+			Int8:    NewVector([]int8{1, -1}),
+			Bits:    bits,
+		}
+
+		b, err := Marshal(want)
+		assert.NoError(t, err, "Marshal error: %v", err)
+
+		subtype, _, ok := Raw(b).Lookup("float32").BinaryOK()
+		assert.True(t, ok, "expected Float32 to be stored as binary")
+		assert.Equal(t, TypeBinaryVector, subtype, "unexpected subtype")
+
+		var got embedding
+		err = Unmarshal(b, &got)
+		assert.NoError(t, err, "Unmarshal error: %v", err)
+		assert.Equal(t, want, got, "vectors did not round trip")
+
+		f, ok := got.Float32.Float32OK()
+		assert.True(t, ok, "expected float32 vector")
+		assert.Equal(t, []float32{1.5, -2}, f, "unexpected float32 elements")
+		_, ok = got.Float32.Int8OK()
+		assert.False(t, ok, "expected float32 vector not to be an int8 vector")
+	})
+	t.Run("invalid binary", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			b    Binary
+			err  error
+		}{
+			{"wrong subtype", Binary{Subtype: TypeBinaryGeneric, Data: []byte{VectorInt8, 0}}, ErrNotVector},
+			{"missing header", Binary{Subtype: TypeBinaryVector, Data: []byte{VectorInt8}}, ErrInsufficientVectorData},
+			{"truncated float32", Binary{Subtype: TypeBinaryVector, Data: []byte{VectorFloat32, 0, 1, 2}}, ErrInsufficientVectorData},
+			{"int8 padding", Binary{Subtype: TypeBinaryVector, Data: []byte{VectorInt8, 1, 1}}, ErrNonZeroVectorPadding},
+			{"packed bit padding", Binary{Subtype: TypeBinaryVector, Data: []byte{VectorPackedBit, 8, 1}}, ErrVectorPaddingInvalid},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := NewVectorFromBinary(tc.b)
+				assert.ErrorIs(t, err, tc.err, "expected error %v, got %v", tc.err, err)
+			})
+		}
+	})
+	t.Run("decode invalid vector", func(t *testing.T) {
+		doc := D{{"v", Binary{Subtype: TypeBinaryVector, Data: []byte{VectorPackedBit, 1}}}}
+		b, err := Marshal(doc)
+		assert.NoError(t, err, "Marshal error: %v", err)
+
+		var got struct{ V Vector }
+		err = Unmarshal(b, &got)
+		assert.ErrorIs(t, err, ErrVectorPaddingInvalid, "expected ErrVectorPaddingInvalid, got %v", err)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// NewDocumentReaderFromBytes returns a ValueReader that reads the BSON document in b. It behaves
+// like NewDocumentReader(bytes.NewReader(b)), but also allows a Decoder configured with
+// UnsafeZeroCopy to decode strings and byte slices that refer to b instead of copying them.
+func NewDocumentReaderFromBytes(b []byte) ValueReader {
+	return newDocumentReaderFromBytes(b)
+}
+
+func newDocumentReaderFromBytes(b []byte) *valueReader {
+	vr := newDocumentReader(bytes.NewReader(b))
+	vr.src = b
+	return vr
+}
+
+// borrow returns the next n bytes of the document without copying them if zero-copy decoding is
+// enabled and the document is held in memory. The returned slice has its capacity limited to n so
+// that appending to it cannot overwrite the rest of the document.
+func (vr *valueReader) borrow(n int) ([]byte, bool) {
+	start := vr.consumed + vr.offset
+	end := start + int64(n)
+	if !vr.zeroCopy || vr.src == nil || n < 0 || end > int64(len(vr.src)) {
+		return nil, false
+	}
+	if _, err := vr.r.Discard(n); err != nil {
+		return nil, false
+	}
+	vr.offset += int64(n)
+	return vr.src[start:end:end], true
+}
+
+// unsafeString returns a string that shares its memory with b. b must not be modified while the
+// string is in use.
+func unsafeString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,65 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"testing"
+	"unsafe"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestDecoderUnsafeZeroCopy(t *testing.T) {
+	type document struct {
+		S    string `bson:"s"`
+		B    []byte `bson:"b"`
+		Nums []int  `bson:"nums"`
+	}
+	want := document{S: "hello", B: []byte{1, 2, 3}, Nums: []int{1, 2}}
+
+	data, err := Marshal(want)
+	require.NoError(t, err, "Marshal error: %v", err)
+
+	start := uintptr(unsafe.Pointer(&data[0]))
+	end := start + uintptr(len(data))
+	within := func(p uintptr) bool { return p >= start && p < end }
+
+	t.Run("enabled", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReaderFromBytes(data))
+		dec.UnsafeZeroCopy()
+
+		var got document
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, want, got, "expected decoded document to match")
+
+		assert.True(t, within(stringData(got.S)), "expected string to refer to the document")
+		assert.True(t, within(uintptr(unsafe.Pointer(&got.B[0]))), "expected byte slice to refer to the document")
+		assert.Equal(t, len(got.B), cap(got.B), "expected byte slice capacity to be limited to its length")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReaderFromBytes(data))
+
+		var got document
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, want, got, "expected decoded document to match")
+		assert.False(t, within(stringData(got.S)), "expected string to be copied")
+	})
+	t.Run("streaming reader", func(t *testing.T) {
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(data)))
+		dec.UnsafeZeroCopy()
+
+		var got document
+		err := dec.Decode(&got)
+		require.NoError(t, err, "Decode error: %v", err)
+		assert.Equal(t, want, got, "expected decoded document to match")
+		assert.False(t, within(stringData(got.S)), "expected string to be copied")
+	})
+}
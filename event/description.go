@@ -46,6 +46,7 @@ type ServerDescription struct {
 	Tags                     tag.Set
 	TopologyVersionProcessID bson.ObjectID
 	TopologyVersionCounter   int64
+	AverageRTT               time.Duration
 }
 
 // TopologyDescription contains information about a MongoDB cluster.
@@ -0,0 +1,179 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package event
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/tag"
+)
+
+// These constants are the names of the fields compared by DiffServerDescriptions.
+const (
+	FieldKind            = "Kind"
+	FieldSetName         = "SetName"
+	FieldSetVersion      = "SetVersion"
+	FieldElectionID      = "ElectionID"
+	FieldTopologyVersion = "TopologyVersion"
+	FieldPrimary         = "Primary"
+	FieldHosts           = "Hosts"
+	FieldTags            = "Tags"
+	FieldWireVersion     = "WireVersion"
+	FieldRTT             = "RTT"
+)
+
+// rttBucketBounds are the upper bounds of the RTT buckets returned by RTTBucket.
+var rttBucketBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// RTTBucket returns the index of the bucket that contains the given round-trip time. The buckets
+// are bounded by 1ms, 5ms, 10ms, 25ms, 50ms, 100ms, 250ms, 500ms, and 1s, so the bucket of an RTT
+// only changes if the latency of a server changes significantly.
+func RTTBucket(rtt time.Duration) int {
+	for i, bound := range rttBucketBounds {
+		if rtt < bound {
+			return i
+		}
+	}
+	return len(rttBucketBounds)
+}
+
+// ServerDescriptionDiff describes the differences between two descriptions of a server.
+type ServerDescriptionDiff struct {
+	// Fields are the names of the fields that changed, in the order of the Field constants.
+	// Hosts covers the Hosts, Passives, and Arbiters fields, WireVersion covers the
+	// MinWireVersion and MaxWireVersion fields, and RTT only changes if the AverageRTT moved to
+	// another RTTBucket. Fields that change with every heartbeat, such as LastWriteTime, are not
+	// compared.
+	Fields []string
+
+	previous, current ServerDescription
+}
+
+// DiffServerDescriptions returns the differences between the previous and current descriptions of
+// a server.
+func DiffServerDescriptions(previous, current ServerDescription) ServerDescriptionDiff {
+	diff := ServerDescriptionDiff{previous: previous, current: current}
+	add := func(field string, changed bool) {
+		if changed {
+			diff.Fields = append(diff.Fields, field)
+		}
+	}
+
+	add(FieldKind, previous.Kind != current.Kind)
+	add(FieldSetName, previous.SetName != current.SetName)
+	add(FieldSetVersion, previous.SetVersion != current.SetVersion)
+	add(FieldElectionID, previous.ElectionID != current.ElectionID)
+	add(FieldTopologyVersion, previous.TopologyVersionProcessID != current.TopologyVersionProcessID ||
+		previous.TopologyVersionCounter != current.TopologyVersionCounter)
+	add(FieldPrimary, previous.Primary != current.Primary)
+	add(FieldHosts, !stringsEqual(previous.Hosts, current.Hosts) ||
+		!stringsEqual(previous.Passives, current.Passives) ||
+		!stringsEqual(previous.Arbiters, current.Arbiters))
+	add(FieldTags, !tagSetsEqual(previous.Tags, current.Tags))
+	add(FieldWireVersion, previous.MinWireVersion != current.MinWireVersion ||
+		previous.MaxWireVersion != current.MaxWireVersion)
+	add(FieldRTT, RTTBucket(previous.AverageRTT) != RTTBucket(current.AverageRTT))
+
+	return diff
+}
+
+// Empty returns true if none of the compared fields changed.
+func (d ServerDescriptionDiff) Empty() bool {
+	return len(d.Fields) == 0
+}
+
+// Changed returns true if the field with the given name changed.
+func (d ServerDescriptionDiff) Changed(field string) bool {
+	for _, f := range d.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// KindChanged returns true if the type of the server changed.
+func (d ServerDescriptionDiff) KindChanged() bool {
+	return d.Changed(FieldKind)
+}
+
+// PrimaryChanged returns true if the server became or stopped being the primary of its replica
+// set, or if the primary it reports changed.
+func (d ServerDescriptionDiff) PrimaryChanged() bool {
+	const primary = "RSPrimary"
+	return (d.KindChanged() && (d.previous.Kind == primary || d.current.Kind == primary)) ||
+		d.Changed(FieldPrimary)
+}
+
+// ElectionChanged returns true if the replica set version or the election ID reported by the
+// server changed, which happens after an election or a reconfiguration.
+func (d ServerDescriptionDiff) ElectionChanged() bool {
+	return d.Changed(FieldSetVersion) || d.Changed(FieldElectionID)
+}
+
+// TopologyVersionChanged returns true if the topology version reported by the server changed.
+func (d ServerDescriptionDiff) TopologyVersionChanged() bool {
+	return d.Changed(FieldTopologyVersion)
+}
+
+// RTTBucketChanged returns true if the average round-trip time of the server moved to another
+// RTTBucket.
+func (d ServerDescriptionDiff) RTTBucketChanged() bool {
+	return d.Changed(FieldRTT)
+}
+
+// OnServerTransition returns a ServerMonitor.ServerDescriptionChanged callback that only calls fn
+// for events whose diff matches. For example, the following monitor is only called when a server
+// becomes or stops being the primary:
+//
+//	monitor := &event.ServerMonitor{
+//	  ServerDescriptionChanged: event.OnServerTransition(
+//	    event.ServerDescriptionDiff.PrimaryChanged,
+//	    func(evt *event.ServerDescriptionChangedEvent) {
+//	      log.Printf("primary changed: %v is now %v", evt.Address, evt.NewDescription.Kind)
+//	    },
+//	  ),
+//	}
+func OnServerTransition(
+	match func(ServerDescriptionDiff) bool,
+	fn func(*ServerDescriptionChangedEvent),
+) func(*ServerDescriptionChangedEvent) {
+	return func(evt *ServerDescriptionChangedEvent) {
+		if match(evt.Diff) {
+			fn(evt)
+		}
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func tagSetsEqual(a, b tag.Set) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return a.ContainsAll(b)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/tag"
+)
+
+func TestDiffServerDescriptions(t *testing.T) {
+	t.Parallel()
+
+	secondary := ServerDescription{
+		Addr:           "a:27017",
+		Kind:           "RSSecondary",
+		SetName:        "rs0",
+		SetVersion:     1,
+		Hosts:          []string{"a:27017", "b:27017"},
+		Primary:        "b:27017",
+		Tags:           tag.Set{{Name: "dc", Value: "east"}},
+		MaxWireVersion: 21,
+		AverageRTT:     2 * time.Millisecond,
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		current := secondary
+		current.AverageRTT = 3 * time.Millisecond
+		current.LastWriteTime = time.Now()
+		current.Tags = tag.Set{{Name: "dc", Value: "east"}}
+
+		diff := DiffServerDescriptions(secondary, current)
+		assert.True(t, diff.Empty(), "expected empty diff, got %v", diff.Fields)
+		assert.False(t, diff.RTTBucketChanged(), "expected RTT bucket to be unchanged")
+	})
+
+	t.Run("stepped up", func(t *testing.T) {
+		t.Parallel()
+
+		primary := secondary
+		primary.Kind = "RSPrimary"
+		primary.Primary = "a:27017"
+		primary.ElectionID = bson.NewObjectID()
+		primary.AverageRTT = 30 * time.Millisecond
+
+		diff := DiffServerDescriptions(secondary, primary)
+		assert.Equal(t, []string{FieldKind, FieldElectionID, FieldPrimary, FieldRTT}, diff.Fields, "fields mismatch")
+		assert.True(t, diff.KindChanged(), "expected kind to change")
+		assert.True(t, diff.PrimaryChanged(), "expected primary to change")
+		assert.True(t, diff.ElectionChanged(), "expected election to change")
+		assert.False(t, diff.TopologyVersionChanged(), "expected topology version to be unchanged")
+		assert.True(t, diff.RTTBucketChanged(), "expected RTT bucket to change")
+	})
+
+	t.Run("other changes", func(t *testing.T) {
+		t.Parallel()
+
+		other := secondary
+		other.Kind = "RSArbiter"
+		assert.False(t, DiffServerDescriptions(secondary, other).PrimaryChanged(), "expected primary to be unchanged")
+
+		other = secondary
+		other.Hosts = []string{"a:27017"}
+		other.TopologyVersionCounter = 1
+		diff := DiffServerDescriptions(secondary, other)
+		assert.Equal(t, []string{FieldTopologyVersion, FieldHosts}, diff.Fields, "fields mismatch")
+	})
+}
+
+func TestOnServerTransition(t *testing.T) {
+	t.Parallel()
+
+	var called int
+	callback := OnServerTransition(ServerDescriptionDiff.PrimaryChanged, func(*ServerDescriptionChangedEvent) {
+		called++
+	})
+
+	prev := ServerDescription{Kind: "RSSecondary"}
+	current := ServerDescription{Kind: "RSPrimary"}
+	callback(&ServerDescriptionChangedEvent{Diff: DiffServerDescriptions(prev, prev)})
+	callback(&ServerDescriptionChangedEvent{Diff: DiffServerDescriptions(prev, current)})
+	assert.Equal(t, 1, called, "expected callback to be called once")
+}
+
+func TestRTTBucket(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, RTTBucket(500*time.Microsecond), "bucket mismatch")
+	assert.Equal(t, 1, RTTBucket(time.Millisecond), "bucket mismatch")
+	assert.Equal(t, 4, RTTBucket(49*time.Millisecond), "bucket mismatch")
+	assert.Equal(t, 9, RTTBucket(2*time.Second), "bucket mismatch")
+}
@@ -8,6 +8,7 @@ package event_test
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -57,3 +58,43 @@ func ExampleCommandMonitor() {
 		}
 	}()
 }
+
+// DenyFields removes fields from the commands and replies published to a CommandMonitor.
+func ExampleDenyFields() {
+	redact := event.DenyFields("ssn", "email")
+
+	cmd, _ := bson.Marshal(bson.D{
+		{"insert", "users"},
+		{"documents", bson.A{
+			bson.D{{"name", "Ada"}, {"ssn", "123-45-6789"}, {"email", "ada@example.com"}},
+		}},
+	})
+	fmt.Println(redact("insert", cmd))
+
+	allow := event.AllowFields("insert", "ordered")
+	fmt.Println(allow("insert", cmd))
+
+	// Output:
+	// {"insert": "users","documents": [{"name": "Ada"}]}
+	// {"insert": "users"}
+}
+
+// Redact is applied to every command and reply before the events are published.
+func ExampleCommandMonitor_redact() {
+	cmdMonitor := &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			log.Printf("Command: %v\n", evt.Command)
+		},
+		Redact: event.DenyFields("password", "ssn"),
+	}
+	clientOpts := options.Client().ApplyURI("mongodb://localhost:27017").SetMonitor(cmdMonitor)
+	client, err := mongo.Connect(clientOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err = client.Disconnect(context.TODO()); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
@@ -27,6 +27,10 @@ type CommandStartedEvent struct {
 	// ServiceID contains the ID of the server to which the command was sent if it is running behind a load balancer.
 	// Otherwise, it is unset.
 	ServiceID *bson.ObjectID
+	// Comment is the comment of the command, as set with the SetComment option of the operation, which can be used
+	// to correlate the event with server logs and profiler entries. It is unset if the command has no comment or is
+	// redacted.
+	Comment bson.RawValue
 }
 
 // CommandFinishedEvent represents a generic command finishing.
@@ -42,12 +46,65 @@ type CommandFinishedEvent struct {
 	// ServiceID contains the ID of the server to which the command was sent if it is running behind a load balancer.
 	// Otherwise, it is unset.
 	ServiceID *bson.ObjectID
+	// RetryAttempt is 0 for the first attempt of an operation and is incremented each time the
+	// command is retried.
+	RetryAttempt int
+	// RetryReason describes the error that caused the command to be retried, such as "network error"
+	// or "not writable primary". It is empty for the first attempt.
+	RetryReason string
+	// PreviousServerAddress is the address of the server the previous attempt was sent to if the
+	// command was retried. Otherwise, it is empty.
+	PreviousServerAddress address.Address
+	// Comment is the comment of the command, as in CommandStartedEvent.
+	Comment bson.RawValue
+	// CollectionName is the name of the collection the command ran on, such as the collection of
+	// a find or insert command. It is empty if the command does not run on a collection, such as
+	// a database aggregation, or is redacted.
+	CollectionName string
 }
 
 // CommandSucceededEvent represents an event generated when a command's execution succeeds.
 type CommandSucceededEvent struct {
 	CommandFinishedEvent
 	Reply bson.Raw
+
+	// RequestSize is the size in bytes of the wire message sent to the server. If the message was
+	// compressed, it is the compressed size.
+	RequestSize int
+	// UncompressedRequestSize is the size in bytes of the wire message sent to the server before
+	// compression. It is equal to RequestSize if the message was not compressed.
+	UncompressedRequestSize int
+	// ReplySize is the size in bytes of the wire message received from the server. If the message
+	// was compressed, it is the compressed size. It is 0 if the command did not expect a reply.
+	ReplySize int
+	// UncompressedReplySize is the size in bytes of the wire message received from the server after
+	// decompression. It is equal to ReplySize if the message was not compressed.
+	UncompressedReplySize int
+	// RequestDocuments is the number of documents sent as the document sequence of a write command,
+	// such as the documents of an insert command.
+	RequestDocuments int
+	// ReplyDocuments is the number of documents in the cursor batch of the reply, such as the
+	// firstBatch of a find command.
+	ReplyDocuments int
+}
+
+// RequestCompressionRatio returns the ratio of the uncompressed to the compressed size of the
+// request wire message. It returns 1 if the message was not compressed.
+func (e *CommandSucceededEvent) RequestCompressionRatio() float64 {
+	return compressionRatio(e.UncompressedRequestSize, e.RequestSize)
+}
+
+// ReplyCompressionRatio returns the ratio of the uncompressed to the compressed size of the reply
+// wire message. It returns 1 if the message was not compressed or there was no reply.
+func (e *CommandSucceededEvent) ReplyCompressionRatio() float64 {
+	return compressionRatio(e.UncompressedReplySize, e.ReplySize)
+}
+
+func compressionRatio(uncompressed, compressed int) float64 {
+	if compressed == 0 {
+		return 1
+	}
+	return float64(uncompressed) / float64(compressed)
 }
 
 // CommandFailedEvent represents an event generated when a command's execution fails.
@@ -61,6 +118,13 @@ type CommandMonitor struct {
 	Started   func(context.Context, *CommandStartedEvent)
 	Succeeded func(context.Context, *CommandSucceededEvent)
 	Failed    func(context.Context, *CommandFailedEvent)
+
+	// Redact, if set, is called with the command name and the command or reply document before a
+	// CommandStartedEvent or CommandSucceededEvent is published, and the returned document is used
+	// as the Command or Reply of the event. The document must not be modified or retained. Commands
+	// that are always redacted, such as authentication commands, are not passed to Redact. See
+	// DenyFields and AllowFields for common redaction functions.
+	Redact RedactFunc
 }
 
 // strings for pool command monitoring reasons
@@ -71,6 +135,7 @@ const (
 	ReasonConnectionErrored = "connectionError"
 	ReasonTimedOut          = "timeout"
 	ReasonError             = "error"
+	ReasonConnectionBudget  = "connectionBudget"
 )
 
 // strings for pool command monitoring types
@@ -86,6 +151,14 @@ const (
 	ConnectionCheckOutFailed  = "ConnectionCheckOutFailed"
 	ConnectionCheckedOut      = "ConnectionCheckedOut"
 	ConnectionCheckedIn       = "ConnectionCheckedIn"
+	ConnectionPinned          = "ConnectionPinned"
+	ConnectionUnpinned        = "ConnectionUnpinned"
+)
+
+// strings for the reasons of ConnectionPinned and ConnectionUnpinned events
+const (
+	PinReasonCursor      = "cursor"
+	PinReasonTransaction = "transaction"
 )
 
 // MonitorPoolOptions contains pool options as formatted in pool events
@@ -103,8 +176,9 @@ type PoolEvent struct {
 	PoolOptions  *MonitorPoolOptions `json:"options"`
 	Duration     time.Duration       `json:"duration"`
 	Reason       string              `json:"reason"`
-	// ServiceID is only set if the Type is PoolCleared and the server is deployed behind a load balancer. This field
-	// can be used to distinguish between individual servers in a load balanced deployment.
+	// ServiceID is only set if the Type is PoolCleared, ConnectionPinned, or ConnectionUnpinned and the server is
+	// deployed behind a load balancer. This field can be used to distinguish between individual servers in a load
+	// balanced deployment.
 	ServiceID    *bson.ObjectID `json:"serviceId"`
 	Interruption bool           `json:"interruptInUseConnections"`
 	Error        error          `json:"error"`
@@ -121,6 +195,10 @@ type ServerDescriptionChangedEvent struct {
 	TopologyID          bson.ObjectID // A unique identifier for the topology this server is a part of
 	PreviousDescription ServerDescription
 	NewDescription      ServerDescription
+
+	// Diff describes the fields that differ between PreviousDescription and NewDescription. Use
+	// OnServerTransition to only handle specific changes.
+	Diff ServerDescriptionDiff
 }
 
 // ServerOpeningEvent is an event generated when the server is initialized.
@@ -152,6 +230,18 @@ type TopologyClosedEvent struct {
 	TopologyID bson.ObjectID // A unique identifier for the topology this server is a part of
 }
 
+// SRVHostsChangedEvent is an event generated when polling the SRV records of a "mongodb+srv" URI
+// adds hosts to or removes hosts from the topology.
+type SRVHostsChangedEvent struct {
+	TopologyID bson.ObjectID // A unique identifier for the topology whose SRV records were polled
+
+	// Added are the hosts added to the topology, and Removed are the hosts removed from the
+	// topology. When srvMaxHosts is set, hosts in the SRV records that were not selected are not
+	// reported as added.
+	Added   []address.Address
+	Removed []address.Address
+}
+
 // ServerHeartbeatStartedEvent is an event generated when the heartbeat is started.
 type ServerHeartbeatStartedEvent struct {
 	ConnectionID string // The address this heartbeat was sent to with a unique identifier
@@ -190,4 +280,56 @@ type ServerMonitor struct {
 	ServerHeartbeatStarted     func(*ServerHeartbeatStartedEvent)
 	ServerHeartbeatSucceeded   func(*ServerHeartbeatSucceededEvent)
 	ServerHeartbeatFailed      func(*ServerHeartbeatFailedEvent)
+	// SRVHostsChanged is called when the topology is locked, so the callback should not attempt
+	// any operation that requires server selection on the same client.
+	SRVHostsChanged func(*SRVHostsChangedEvent)
+}
+
+// strings for query analysis monitoring types
+const (
+	CryptSharedLibLoaded     = "CryptSharedLibLoaded"
+	CryptSharedLibNotLoaded  = "CryptSharedLibNotLoaded"
+	MongocryptdSpawned       = "MongocryptdSpawned"
+	MongocryptdSpawnFailed   = "MongocryptdSpawnFailed"
+	MongocryptdSpawnBypassed = "MongocryptdSpawnBypassed"
+)
+
+// QueryAnalysisEvent contains information about the lifecycle of the component used to analyze
+// commands for automatic encryption. Query analysis is done either by the crypt_shared library or
+// by a mongocryptd process spawned by the Client.
+type QueryAnalysisEvent struct {
+	Type string
+	// CryptSharedLibVersion is the version string of the loaded crypt_shared library. It is only
+	// set if the Type is CryptSharedLibLoaded.
+	CryptSharedLibVersion string
+	// MongocryptdPath and MongocryptdArgs are the path and arguments used to spawn mongocryptd. They
+	// are only set if the Type is MongocryptdSpawned or MongocryptdSpawnFailed.
+	MongocryptdPath string
+	MongocryptdArgs []string
+	// Error is only set if the Type is MongocryptdSpawnFailed.
+	Error error
+}
+
+// QueryAnalysisMonitor represents a monitor that is triggered for events about the crypt_shared
+// library and the mongocryptd process used for automatic encryption.
+type QueryAnalysisMonitor struct {
+	Event func(*QueryAnalysisEvent)
+}
+
+// TransactionAttemptEvent is an event generated after each attempt to run a transaction with the
+// mongo.WithTransaction helper.
+type TransactionAttemptEvent struct {
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	// Duration is how long the attempt took, including the callback and any commit retries.
+	Duration time.Duration
+	// Committed is true if the transaction was committed by this attempt.
+	Committed bool
+	// Failure is the error that ended the attempt, or nil if the attempt committed or the callback
+	// aborted the transaction.
+	Failure error
+	// Retrying is true if the transaction will be attempted again.
+	Retrying bool
+	// Backoff is the delay before the next attempt. It is only set if Retrying is true.
+	Backoff time.Duration
 }
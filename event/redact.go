@@ -0,0 +1,79 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package event
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// RedactFunc returns a redacted copy of a command or reply document for the command with the given
+// name. It is used by CommandMonitor.Redact.
+type RedactFunc func(commandName string, doc bson.Raw) bson.Raw
+
+// DenyFields returns a RedactFunc that removes every field with one of the given names, at any depth
+// of the document and including fields of documents within arrays, such as the documents of an
+// insert command or the batch of a cursor reply.
+func DenyFields(fields ...string) RedactFunc {
+	deny := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		deny[f] = struct{}{}
+	}
+	return func(_ string, doc bson.Raw) bson.Raw {
+		return bson.Raw(redactDocument(nil, bsoncore.Document(doc), false, 0, func(key string, _ int) bool {
+			_, ok := deny[key]
+			return !ok
+		}))
+	}
+}
+
+// AllowFields returns a RedactFunc that keeps only the top-level fields with one of the given names.
+// Embedded documents and arrays in the allowed fields are kept as they are. For example,
+// AllowFields("insert", "ordered", "ok", "n") keeps the collection name and options of an insert
+// command and the counts of its reply while removing the inserted documents.
+func AllowFields(fields ...string) RedactFunc {
+	allow := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		allow[f] = struct{}{}
+	}
+	return func(_ string, doc bson.Raw) bson.Raw {
+		return bson.Raw(redactDocument(nil, bsoncore.Document(doc), false, 0, func(key string, depth int) bool {
+			if depth > 0 {
+				return true
+			}
+			_, ok := allow[key]
+			return ok
+		}))
+	}
+}
+
+// redactDocument appends a copy of doc to dst that contains only the fields for which keep returns
+// true. Embedded documents and arrays are redacted recursively, and the elements of arrays are
+// always kept. Invalid documents are replaced by an empty document so that their contents are never
+// published.
+func redactDocument(dst []byte, doc bsoncore.Document, array bool, depth int, keep func(key string, depth int) bool) []byte {
+	idx, dst := bsoncore.AppendDocumentStart(dst)
+	elems, err := doc.Elements()
+	if err == nil {
+		for _, elem := range elems {
+			key := elem.Key()
+			if !array && !keep(key, depth) {
+				continue
+			}
+			val := elem.Value()
+			switch val.Type {
+			case bsoncore.TypeEmbeddedDocument, bsoncore.TypeArray:
+				dst = bsoncore.AppendHeader(dst, val.Type, key)
+				dst = redactDocument(dst, val.Data, val.Type == bsoncore.TypeArray, depth+1, keep)
+			default:
+				dst = bsoncore.AppendValueElement(dst, key, val)
+			}
+		}
+	}
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+	return dst
+}
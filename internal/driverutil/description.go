@@ -68,6 +68,10 @@ func EqualServers(srv1, srv2 description.Server) bool {
 		return false
 	}
 
+	if srv1.DataFederation != srv2.DataFederation {
+		return false
+	}
+
 	if srv1.LastError != nil || srv2.LastError != nil {
 		if srv1.LastError == nil || srv2.LastError == nil {
 			return false
@@ -236,6 +240,12 @@ func NewServerDescription(addr address.Address, response bson.Raw) description.S
 				desc.LastError = err
 				return desc
 			}
+		case "dataLake":
+			if _, ok := element.Value().DocumentOK(); !ok {
+				desc.LastError = fmt.Errorf("expected 'dataLake' to be a document but it's a BSON %s", element.Value().Type)
+				return desc
+			}
+			desc.DataFederation = true
 		case "electionId":
 			desc.ElectionID, ok = element.Value().ObjectIDOK()
 			if !ok {
@@ -87,6 +87,7 @@ const (
 	ReasonConnClosedIdle               = "Connection has been available but unused for longer than the configured max idle time"
 	ReasonConnClosedError              = "An error occurred while using the connection"
 	ReasonConnClosedPoolClosed         = "Connection pool was closed"
+	ReasonConnClosedConnectionBudget   = "Connection was closed to give its slot in the client connection budget to another pool"
 	ReasonConnCheckoutFailedTimout     = "Wait queue timeout elapsed without a connection becoming available"
 	ReasonConnCheckoutFailedError      = "An error occurred while trying to establish a new connection"
 	ReasonConnCheckoutFailedPoolClosed = "Connection pool was closed"
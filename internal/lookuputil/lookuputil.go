@@ -0,0 +1,196 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package lookuputil validates the variables of $lookup stages, which the server silently
+// evaluates to missing values when they are misspelled or referenced incorrectly.
+package lookuputil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ValidateLet returns an error if a variable of let has an invalid name or is defined more than
+// once.
+func ValidateLet(let bson.D) error {
+	defined := make(map[string]bool, len(let))
+	for _, e := range let {
+		if err := ValidateVariableName(e.Key); err != nil {
+			return err
+		}
+		if defined[e.Key] {
+			return fmt.Errorf("$lookup variable %q defined more than once", e.Key)
+		}
+		defined[e.Key] = true
+	}
+	return nil
+}
+
+// ValidateReferences checks the references of the stages of a $lookup sub-pipeline to the
+// variables defined by let. It returns an error if the pipeline
+//   - references a variable that is not defined;
+//   - does not reference a variable of let, including when it references a field with the name of
+//     the variable;
+//   - references a variable in a $match stage outside of $expr, where variables are not evaluated.
+//
+// Variables defined in the pipeline, such as those of $let, $map, $filter, $reduce, and nested
+// $lookup stages, and system variables such as "$$ROOT" can be referenced without being defined
+// in let.
+func ValidateReferences(let bson.D, pipeline []interface{}) error {
+	refs := &variableRefs{
+		vars:   make(map[string][]string),
+		fields: make(map[string]bool),
+		local:  make(map[string]bool),
+	}
+	for i, stage := range pipeline {
+		raw, err := bson.Marshal(stage)
+		if err != nil {
+			return fmt.Errorf("error marshaling $lookup pipeline stage %d: %w", i, err)
+		}
+		path := "pipeline." + strconv.Itoa(i)
+		elems, err := bson.Raw(raw).Elements()
+		if err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			if err := refs.collect(path+"."+elem.Key(), elem.Key(), elem.Value(), elem.Key() == "$match"); err != nil {
+				return err
+			}
+		}
+	}
+
+	defined := make(map[string]bool, len(let))
+	for _, e := range let {
+		defined[e.Key] = true
+	}
+	names := make([]string, 0, len(refs.vars))
+	for name := range refs.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] && !refs.local[name] {
+			return fmt.Errorf("$lookup pipeline references undefined variable %q at %s",
+				"$$"+name, refs.vars[name][0])
+		}
+	}
+	for _, e := range let {
+		if _, ok := refs.vars[e.Key]; ok {
+			continue
+		}
+		if refs.fields[e.Key] {
+			return fmt.Errorf("$lookup pipeline references field %q instead of variable %q",
+				"$"+e.Key, "$$"+e.Key)
+		}
+		return fmt.Errorf("$lookup variable %q is not referenced by the pipeline", e.Key)
+	}
+	return nil
+}
+
+// variableRefs collects the variable references of an aggregation pipeline.
+type variableRefs struct {
+	vars   map[string][]string // the paths of the references to each variable
+	fields map[string]bool     // the top-level field names of the field paths
+	local  map[string]bool     // the variables defined in the pipeline
+}
+
+// collect adds the references of val, found at path under the key key, to r. inMatch reports
+// whether val is in a $match stage outside of $expr.
+func (r *variableRefs) collect(path, key string, val bson.RawValue, inMatch bool) error {
+	switch val.Type {
+	case bson.TypeString:
+		s := val.StringValue()
+		switch {
+		case strings.HasPrefix(s, "$$"):
+			name := strings.SplitN(s[2:], ".", 2)[0]
+			if isSystemVariable(name) {
+				return nil
+			}
+			if inMatch {
+				return fmt.Errorf("$lookup pipeline references variable %q at %s in $match outside of $expr",
+					s, path)
+			}
+			r.vars[name] = append(r.vars[name], path)
+		case strings.HasPrefix(s, "$"):
+			r.fields[strings.SplitN(s[1:], ".", 2)[0]] = true
+		}
+	case bson.TypeEmbeddedDocument, bson.TypeArray:
+		doc := val.Value
+		elems, err := bson.Raw(doc).Elements()
+		if err != nil {
+			return err
+		}
+		if val.Type == bson.TypeEmbeddedDocument {
+			r.defineLocal(key, bson.Raw(doc))
+		}
+		for _, elem := range elems {
+			match := inMatch && elem.Key() != "$expr"
+			if err := r.collect(path+"."+elem.Key(), elem.Key(), elem.Value(), match); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// defineLocal adds the variables defined by the operator key with the arguments args to r.
+func (r *variableRefs) defineLocal(key string, args bson.Raw) {
+	switch key {
+	case "$let":
+		if vars, ok := args.Lookup("vars").DocumentOK(); ok {
+			elems, _ := vars.Elements()
+			for _, elem := range elems {
+				r.local[elem.Key()] = true
+			}
+		}
+	case "$map", "$filter":
+		if as, ok := args.Lookup("as").StringValueOK(); ok {
+			r.local[as] = true
+		} else {
+			r.local["this"] = true
+		}
+	case "$reduce":
+		r.local["value"] = true
+		r.local["this"] = true
+	case "$lookup":
+		if let, ok := args.Lookup("let").DocumentOK(); ok {
+			elems, _ := let.Elements()
+			for _, elem := range elems {
+				r.local[elem.Key()] = true
+			}
+		}
+	}
+}
+
+// isSystemVariable reports whether name is the name of a system variable such as ROOT. User
+// variable names cannot start with an uppercase letter.
+func isSystemVariable(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// ValidateVariableName returns an error if name is not a valid user variable name, which starts
+// with a lowercase ASCII letter or a non-ASCII character and contains only ASCII letters, digits,
+// underscores, and non-ASCII characters.
+func ValidateVariableName(name string) error {
+	if name == "" {
+		return errors.New("$lookup variable name cannot be empty")
+	}
+	for i, r := range name {
+		switch {
+		case r >= utf8.RuneSelf, r >= 'a' && r <= 'z':
+		case i > 0 && (r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_'):
+		default:
+			return fmt.Errorf("invalid $lookup variable name %q", name)
+		}
+	}
+	return nil
+}
@@ -18,9 +18,10 @@ const defaultPort = "27017"
 type Address string
 
 // Network is the network protocol for this address. In most cases this will be
-// "tcp" or "unix".
+// "tcp" or "unix". Addresses ending in "sock" and absolute paths are unix
+// domain sockets.
 func (a Address) Network() string {
-	if strings.HasSuffix(string(a), "sock") {
+	if strings.HasSuffix(string(a), "sock") || strings.HasPrefix(string(a), "/") {
 		return "unix"
 	}
 	return "tcp"
@@ -23,6 +23,7 @@ func TestAddress_String(t *testing.T) {
 		{"a:27017", "a:27017"},
 		{"a.sock", "a.sock"},
 		{"A.sock", "A.sock"},
+		{"/run/MongoDB/socket", "/run/MongoDB/socket"},
 	}
 
 	for _, test := range tests {
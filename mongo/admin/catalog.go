@@ -0,0 +1,113 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package admin
+
+import (
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Parameter describes a server parameter of the catalog used by GetParameter and SetParameter.
+type Parameter struct {
+	// Name is the name of the parameter.
+	Name string
+
+	// Type is the Go type of the parameter value as returned by getParameter.
+	Type reflect.Type
+
+	// MinServerVersion is the first MongoDB version that supports the parameter.
+	MinServerVersion string
+
+	// Runtime is whether the parameter can be changed with setParameter. Other parameters can only
+	// be set when the server starts.
+	Runtime bool
+
+	// Description is a short description of the parameter.
+	Description string
+}
+
+var (
+	int32Type    = reflect.TypeOf(int32(0))
+	int64Type    = reflect.TypeOf(int64(0))
+	boolType     = reflect.TypeOf(false)
+	stringType   = reflect.TypeOf("")
+	documentType = reflect.TypeOf(bson.Raw(nil))
+)
+
+var catalog = map[string]Parameter{}
+
+func init() {
+	for _, p := range []Parameter{
+		{"changeStreamOptions", documentType, "6.0", true,
+			"Options of change streams, such as the expiration of pre- and post-images."},
+		{"cursorTimeoutMillis", int64Type, "3.0", true,
+			"Time after which idle cursors are closed."},
+		{"diagnosticDataCollectionEnabled", boolType, "3.2", true,
+			"Whether full-time diagnostic data capture is enabled."},
+		{"diagnosticDataCollectionPeriodMillis", int32Type, "3.2", true,
+			"Interval at which diagnostic data is captured."},
+		{"enableFlowControl", boolType, "4.2", true,
+			"Whether the rate of writes on the primary is limited to keep majority committed lag low."},
+		{"featureCompatibilityVersion", documentType, "3.4", false,
+			"Feature compatibility version of the deployment. Use setFeatureCompatibilityVersion to change it."},
+		{"flowControlTargetLagSeconds", int32Type, "4.2", true,
+			"Target majority committed lag when flow control is enabled."},
+		{"internalQueryFrameworkControl", stringType, "6.0", true,
+			"Query engine used by the server, such as \"trySbeEngine\" or \"forceClassicEngine\"."},
+		{"localLogicalSessionTimeoutMinutes", int32Type, "3.6", false,
+			"Time after which idle sessions expire."},
+		{"logComponentVerbosity", documentType, "3.0", true,
+			"Log verbosity level of each log component."},
+		{"logLevel", int32Type, "1.8", true,
+			"Default log verbosity level."},
+		{"maxIndexBuildMemoryUsageMegabytes", int32Type, "3.4", true,
+			"Maximum memory used by all concurrent index builds."},
+		{"maxNumActiveUserIndexBuilds", int32Type, "4.4", true,
+			"Maximum number of concurrent index builds."},
+		{"maxSessions", int32Type, "4.0", false,
+			"Maximum number of sessions that can be cached."},
+		{"maxTransactionLockRequestTimeoutMillis", int32Type, "4.0", true,
+			"Time a transaction waits to acquire locks before it is aborted."},
+		{"notablescan", boolType, "1.8", true,
+			"Whether queries that require a collection scan are rejected."},
+		{"quiet", boolType, "2.2", true,
+			"Whether the server logs fewer messages."},
+		{"redactClientLogData", boolType, "3.4", true,
+			"Whether log messages are redacted (enterprise only)."},
+		{"transactionLifetimeLimitSeconds", int32Type, "4.0", true,
+			"Time after which transactions are aborted."},
+		{"ttlMonitorEnabled", boolType, "2.2", true,
+			"Whether the background thread that deletes expired documents from TTL indexes runs."},
+		{"wiredTigerConcurrentReadTransactions", int32Type, "3.0", true,
+			"Maximum number of concurrent read transactions in the WiredTiger storage engine."},
+		{"wiredTigerConcurrentWriteTransactions", int32Type, "3.0", true,
+			"Maximum number of concurrent write transactions in the WiredTiger storage engine."},
+		{"wiredTigerEngineRuntimeConfig", stringType, "3.0", true,
+			"Runtime configuration string of the WiredTiger storage engine."},
+	} {
+		catalog[p.Name] = p
+	}
+}
+
+// LookupParameter returns the description of the parameter with the given name and true, or false
+// if the parameter is not in the catalog.
+func LookupParameter(name string) (Parameter, bool) {
+	p, ok := catalog[name]
+	return p, ok
+}
+
+// Parameters returns the parameters of the catalog, sorted by name.
+func Parameters() []Parameter {
+	params := make([]Parameter, 0, len(catalog))
+	for _, p := range catalog {
+		params = append(params, p)
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
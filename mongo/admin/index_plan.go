@@ -0,0 +1,573 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// IndexPlan declares the indexes of collections across databases. ApplyIndexPlan creates the
+// indexes of the plan that do not exist and reports how the existing indexes differ from it.
+//
+// A plan can be decoded from an Extended JSON document with ParseIndexPlan, e.g.
+//
+//	{
+//	  "databases": [{
+//	    "name": "app",
+//	    "collections": [{
+//	      "name": "users",
+//	      "indexes": [
+//	        {"keys": {"email": 1}, "unique": true},
+//	        {"name": "recent", "keys": {"createdAt": -1}, "expireAfterSeconds": 86400}
+//	      ]
+//	    }]
+//	  }]
+//	}
+//
+// Documents in other formats, such as YAML, can be converted to JSON first.
+type IndexPlan struct {
+	Databases []DatabaseIndexPlan `bson:"databases"`
+}
+
+// DatabaseIndexPlan declares the indexes of the collections of a database.
+type DatabaseIndexPlan struct {
+	Name        string                `bson:"name"`
+	Collections []CollectionIndexPlan `bson:"collections"`
+}
+
+// CollectionIndexPlan declares the indexes of a collection. The _id index is implicit.
+type CollectionIndexPlan struct {
+	Name    string      `bson:"name"`
+	Indexes []IndexSpec `bson:"indexes"`
+}
+
+// IndexSpec declares an index.
+type IndexSpec struct {
+	// Name is the name of the index. If it is empty, the name is generated from Keys like the
+	// server does, e.g. "email_1_createdAt_-1".
+	Name string `bson:"name,omitempty"`
+
+	// Keys is the keys document of the index. It must not be empty.
+	Keys bson.D `bson:"keys"`
+
+	Unique                  *bool  `bson:"unique,omitempty"`
+	Sparse                  *bool  `bson:"sparse,omitempty"`
+	Hidden                  *bool  `bson:"hidden,omitempty"`
+	ExpireAfterSeconds      *int32 `bson:"expireAfterSeconds,omitempty"`
+	PartialFilterExpression bson.D `bson:"partialFilterExpression,omitempty"`
+}
+
+// ParseIndexPlan decodes an IndexPlan from an Extended JSON document. Relaxed and canonical
+// Extended JSON are accepted.
+func ParseIndexPlan(data []byte) (*IndexPlan, error) {
+	var plan IndexPlan
+	if err := bson.UnmarshalExtJSON(data, false, &plan); err != nil {
+		return nil, fmt.Errorf("failed to decode index plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// IndexDriftKind is a way in which an existing index differs from an IndexPlan.
+type IndexDriftKind string
+
+// These constants are the kinds of IndexDrift.
+const (
+	// IndexMissing indicates that a planned index does not exist, because the plan was applied in
+	// a dry run or creating the index failed.
+	IndexMissing IndexDriftKind = "missing"
+
+	// IndexMismatch indicates that an index exists with the name of a planned index, but with
+	// different keys or options. ApplyIndexPlan does not change such indexes.
+	IndexMismatch IndexDriftKind = "mismatch"
+
+	// IndexUnlisted indicates that an index exists that is not in the plan and was not dropped.
+	IndexUnlisted IndexDriftKind = "unlisted"
+)
+
+// IndexRef identifies an index.
+type IndexRef struct {
+	Database   string
+	Collection string
+	Name       string
+}
+
+// String returns the index as "database.collection.name".
+func (ref IndexRef) String() string {
+	return ref.Database + "." + ref.Collection + "." + ref.Name
+}
+
+// IndexDrift is a difference between an IndexPlan and the indexes of a deployment.
+type IndexDrift struct {
+	IndexRef
+	Kind IndexDriftKind
+
+	// Planned is the planned index. It is nil for an IndexUnlisted drift.
+	Planned *IndexSpec
+
+	// Existing is the existing index. It is nil for an IndexMissing drift.
+	Existing *mongo.IndexSpecification
+}
+
+// IndexPlanFailure is the error that prevented the plan of a collection from being applied.
+type IndexPlanFailure struct {
+	Database   string
+	Collection string
+	Err        error
+}
+
+// IndexPlanResult describes a run of ApplyIndexPlan. Its lists are sorted by database,
+// collection, and index name.
+type IndexPlanResult struct {
+	// Created are the indexes that were created.
+	Created []IndexRef
+
+	// Dropped are the unlisted indexes that were dropped.
+	Dropped []IndexRef
+
+	// Drift are the differences between the plan and the indexes that remain after the run.
+	Drift []IndexDrift
+
+	// Failed are the collections whose plan could not be applied.
+	Failed []IndexPlanFailure
+
+	// DryRun is whether the changes were only planned.
+	DryRun bool
+}
+
+// IndexPlanProgress reports that the plan of a collection has been applied.
+type IndexPlanProgress struct {
+	Database   string
+	Collection string
+
+	// Completed is the number of collections whose plan has been applied, including this one.
+	Completed int
+
+	// Total is the number of collections in the plan.
+	Total int
+
+	// Created are the names of the indexes of the collection that were created.
+	Created []string
+
+	// Err is the error that prevented the plan of the collection from being applied, if any.
+	Err error
+}
+
+// ApplyIndexPlanOptions configures ApplyIndexPlan.
+type ApplyIndexPlanOptions struct {
+	// Concurrency is the maximum number of collections whose indexes are built concurrently. The
+	// indexes of a collection are always created with a single createIndexes command. The default
+	// is 1.
+	Concurrency int
+
+	// Progress is called after the plan of each collection has been applied. Calls are not
+	// concurrent, but may come from different goroutines.
+	Progress func(IndexPlanProgress)
+
+	// DropUnlisted drops the existing indexes of the collections of the plan that are not in the
+	// plan. The _id index is never dropped. Collections that are not in the plan are not changed.
+	DropUnlisted bool
+
+	// DryRun only compares the plan with the existing indexes, without creating or dropping any.
+	DryRun bool
+}
+
+// collectionTask is the plan of a collection to apply.
+type collectionTask struct {
+	db   string
+	plan CollectionIndexPlan
+}
+
+// collectionOutcome is the outcome of applying the plan of a collection.
+type collectionOutcome struct {
+	created []string
+	dropped []string
+	drift   []IndexDrift
+}
+
+// ApplyIndexPlan applies plan to the deployment of client: it creates the planned indexes that do
+// not exist and, with DropUnlisted, drops the existing indexes of the planned collections that
+// are not in the plan. Indexes that exist with the name of a planned index but a different
+// definition are reported as IndexMismatch drift and left unchanged, because rebuilding an index
+// can be expensive and is best done deliberately.
+//
+// The plan is validated before any command is sent. If the plan of some collections cannot be
+// applied, the other collections are still processed, the failures are listed in the result, and
+// an error describing them is returned with the result. opts can be nil.
+func ApplyIndexPlan(
+	ctx context.Context,
+	client *mongo.Client,
+	plan *IndexPlan,
+	opts *ApplyIndexPlanOptions,
+) (*IndexPlanResult, error) {
+	if opts == nil {
+		opts = &ApplyIndexPlanOptions{}
+	}
+	tasks, err := planTasks(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	result := &IndexPlanResult{DryRun: opts.DryRun}
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		completed int
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, task := range tasks {
+		task := task
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			outcome, err := applyCollectionPlan(ctx, client, task, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			completed++
+			for _, name := range outcome.created {
+				result.Created = append(result.Created, IndexRef{task.db, task.plan.Name, name})
+			}
+			for _, name := range outcome.dropped {
+				result.Dropped = append(result.Dropped, IndexRef{task.db, task.plan.Name, name})
+			}
+			result.Drift = append(result.Drift, outcome.drift...)
+			if err != nil {
+				result.Failed = append(result.Failed, IndexPlanFailure{task.db, task.plan.Name, err})
+			}
+			if opts.Progress != nil {
+				opts.Progress(IndexPlanProgress{
+					Database:   task.db,
+					Collection: task.plan.Name,
+					Completed:  completed,
+					Total:      len(tasks),
+					Created:    outcome.created,
+					Err:        err,
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	sortRefs(result.Created)
+	sortRefs(result.Dropped)
+	sort.Slice(result.Drift, func(i, j int) bool { return refLess(result.Drift[i].IndexRef, result.Drift[j].IndexRef) })
+	sort.Slice(result.Failed, func(i, j int) bool {
+		a, b := result.Failed[i], result.Failed[j]
+		return a.Database < b.Database || (a.Database == b.Database && a.Collection < b.Collection)
+	})
+
+	if len(result.Failed) > 0 {
+		first := result.Failed[0]
+		return result, fmt.Errorf("failed to apply the index plan of %d collection(s); %s.%s: %w",
+			len(result.Failed), first.Database, first.Collection, first.Err)
+	}
+	return result, nil
+}
+
+// planTasks validates plan and returns the plans of its collections, with the generated names of
+// unnamed indexes.
+func planTasks(plan *IndexPlan) ([]collectionTask, error) {
+	if plan == nil {
+		return nil, errors.New("index plan must not be nil")
+	}
+
+	var tasks []collectionTask
+	seen := make(map[string]struct{})
+	for _, db := range plan.Databases {
+		if db.Name == "" {
+			return nil, errors.New("index plan database name must not be empty")
+		}
+		for _, coll := range db.Collections {
+			if coll.Name == "" {
+				return nil, fmt.Errorf("index plan collection name in database %q must not be empty", db.Name)
+			}
+			ns := db.Name + "." + coll.Name
+			if _, ok := seen[ns]; ok {
+				return nil, fmt.Errorf("collection %q is planned more than once", ns)
+			}
+			seen[ns] = struct{}{}
+
+			indexes := make([]IndexSpec, 0, len(coll.Indexes))
+			names := make(map[string]struct{}, len(coll.Indexes))
+			for _, spec := range coll.Indexes {
+				if len(spec.Keys) == 0 {
+					return nil, fmt.Errorf("index of collection %q must have keys", ns)
+				}
+				if spec.Name == "" {
+					name, err := indexName(spec.Keys)
+					if err != nil {
+						return nil, fmt.Errorf("cannot name index of collection %q: %w", ns, err)
+					}
+					spec.Name = name
+				}
+				if spec.Name == "_id_" {
+					return nil, fmt.Errorf("the _id index of collection %q must not be planned", ns)
+				}
+				if _, ok := names[spec.Name]; ok {
+					return nil, fmt.Errorf("index %q of collection %q is planned more than once", spec.Name, ns)
+				}
+				names[spec.Name] = struct{}{}
+				indexes = append(indexes, spec)
+			}
+			coll.Indexes = indexes
+			tasks = append(tasks, collectionTask{db: db.Name, plan: coll})
+		}
+	}
+	return tasks, nil
+}
+
+// indexName returns the name the server generates for an index with the given keys.
+func indexName(keys bson.D) (string, error) {
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		var value string
+		switch v := key.Value.(type) {
+		case int32:
+			value = strconv.FormatInt(int64(v), 10)
+		case int64:
+			value = strconv.FormatInt(v, 10)
+		case int:
+			value = strconv.Itoa(v)
+		case float64:
+			if v != math.Trunc(v) {
+				return "", fmt.Errorf("invalid index key value %v for %q", v, key.Key)
+			}
+			value = strconv.FormatInt(int64(v), 10)
+		case string:
+			value = v
+		default:
+			return "", fmt.Errorf("invalid index key value %v for %q", key.Value, key.Key)
+		}
+		parts = append(parts, key.Key, value)
+	}
+	return strings.Join(parts, "_"), nil
+}
+
+// applyCollectionPlan applies the plan of a collection and returns what changed and the drift
+// that remains.
+func applyCollectionPlan(
+	ctx context.Context,
+	client *mongo.Client,
+	task collectionTask,
+	opts *ApplyIndexPlanOptions,
+) (collectionOutcome, error) {
+	var outcome collectionOutcome
+
+	iv := client.Database(task.db).Collection(task.plan.Name).Indexes()
+	existing, err := iv.ListSpecifications(ctx)
+	if err != nil {
+		return outcome, err
+	}
+	byName := make(map[string]*mongo.IndexSpecification, len(existing))
+	for i := range existing {
+		byName[existing[i].Name] = &existing[i]
+	}
+
+	ref := func(name string) IndexRef { return IndexRef{task.db, task.plan.Name, name} }
+
+	var missing []IndexSpec
+	planned := make(map[string]struct{}, len(task.plan.Indexes))
+	for i := range task.plan.Indexes {
+		spec := &task.plan.Indexes[i]
+		planned[spec.Name] = struct{}{}
+
+		current, ok := byName[spec.Name]
+		switch {
+		case !ok:
+			missing = append(missing, *spec)
+		case !matchesSpec(spec, current):
+			outcome.drift = append(outcome.drift, IndexDrift{IndexRef: ref(spec.Name), Kind: IndexMismatch, Planned: spec, Existing: current})
+		}
+	}
+
+	var unlisted []*mongo.IndexSpecification
+	for i := range existing {
+		if _, ok := planned[existing[i].Name]; !ok && existing[i].Name != "_id_" {
+			unlisted = append(unlisted, &existing[i])
+		}
+	}
+
+	reportMissing := func() {
+		for i := range missing {
+			outcome.drift = append(outcome.drift, IndexDrift{IndexRef: ref(missing[i].Name), Kind: IndexMissing, Planned: &missing[i]})
+		}
+	}
+	reportUnlisted := func(specs []*mongo.IndexSpecification) {
+		for _, spec := range specs {
+			outcome.drift = append(outcome.drift, IndexDrift{IndexRef: ref(spec.Name), Kind: IndexUnlisted, Existing: spec})
+		}
+	}
+
+	if opts.DryRun {
+		reportMissing()
+		reportUnlisted(unlisted)
+		return outcome, nil
+	}
+
+	if len(missing) > 0 {
+		models := make([]mongo.IndexModel, 0, len(missing))
+		for _, spec := range missing {
+			models = append(models, indexModel(spec))
+		}
+		if _, err := iv.CreateMany(ctx, models); err != nil {
+			reportMissing()
+			reportUnlisted(unlisted)
+			return outcome, err
+		}
+		for _, spec := range missing {
+			outcome.created = append(outcome.created, spec.Name)
+		}
+	}
+
+	if !opts.DropUnlisted {
+		reportUnlisted(unlisted)
+		return outcome, nil
+	}
+	for i, spec := range unlisted {
+		if err := iv.DropOne(ctx, spec.Name); err != nil {
+			reportUnlisted(unlisted[i:])
+			return outcome, err
+		}
+		outcome.dropped = append(outcome.dropped, spec.Name)
+	}
+	return outcome, nil
+}
+
+// indexModel returns the IndexModel that creates the index of spec.
+func indexModel(spec IndexSpec) mongo.IndexModel {
+	opts := options.Index().SetName(spec.Name)
+	if spec.Unique != nil {
+		opts.SetUnique(*spec.Unique)
+	}
+	if spec.Sparse != nil {
+		opts.SetSparse(*spec.Sparse)
+	}
+	if spec.Hidden != nil {
+		opts.SetHidden(*spec.Hidden)
+	}
+	if spec.ExpireAfterSeconds != nil {
+		opts.SetExpireAfterSeconds(*spec.ExpireAfterSeconds)
+	}
+	if spec.PartialFilterExpression != nil {
+		opts.SetPartialFilterExpression(spec.PartialFilterExpression)
+	}
+	return mongo.IndexModel{Keys: spec.Keys, Options: opts}
+}
+
+// matchesSpec reports whether the existing index has the keys and options of spec.
+func matchesSpec(spec *IndexSpec, existing *mongo.IndexSpecification) bool {
+	keys, err := bson.Marshal(spec.Keys)
+	if err != nil || !equalDocuments(keys, existing.KeysDocument) {
+		return false
+	}
+	if !equalFlags(spec.Unique, existing.Unique) || !equalFlags(spec.Sparse, existing.Sparse) ||
+		!equalFlags(spec.Hidden, existing.Hidden) {
+		return false
+	}
+	if (spec.ExpireAfterSeconds == nil) != (existing.ExpireAfterSeconds == nil) ||
+		(spec.ExpireAfterSeconds != nil && *spec.ExpireAfterSeconds != *existing.ExpireAfterSeconds) {
+		return false
+	}
+	if spec.PartialFilterExpression == nil || existing.PartialFilterExpression == nil {
+		return spec.PartialFilterExpression == nil && existing.PartialFilterExpression == nil
+	}
+	filter, err := bson.Marshal(spec.PartialFilterExpression)
+	return err == nil && equalDocuments(filter, existing.PartialFilterExpression)
+}
+
+// equalFlags reports whether two boolean index options are equal, where unset means false.
+func equalFlags(a, b *bool) bool {
+	return (a != nil && *a) == (b != nil && *b)
+}
+
+// equalDocuments reports whether a and b have the same keys in the same order with equal values,
+// where numbers of different types are equal if they have the same value. The server may return
+// the numbers of an index definition with a different type than the one they were created with.
+func equalDocuments(a, b bson.Raw) bool {
+	aElems, err := a.Elements()
+	if err != nil {
+		return false
+	}
+	bElems, err := b.Elements()
+	if err != nil || len(aElems) != len(bElems) {
+		return false
+	}
+	for i := range aElems {
+		if aElems[i].Key() != bElems[i].Key() || !equalValues(aElems[i].Value(), bElems[i].Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalValues(a, b bson.RawValue) bool {
+	if af, ok := number(a); ok {
+		bf, ok := number(b)
+		return ok && af == bf
+	}
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case bson.TypeEmbeddedDocument:
+		return equalDocuments(a.Document(), b.Document())
+	case bson.TypeArray:
+		return equalDocuments(bson.Raw(a.Array()), bson.Raw(b.Array()))
+	}
+	return bytes.Equal(a.Value, b.Value)
+}
+
+// number returns the value of a numeric BSON value as a float64.
+func number(v bson.RawValue) (float64, bool) {
+	switch v.Type {
+	case bson.TypeInt32:
+		return float64(v.Int32()), true
+	case bson.TypeInt64:
+		return float64(v.Int64()), true
+	case bson.TypeDouble:
+		return v.Double(), true
+	}
+	return 0, false
+}
+
+func refLess(a, b IndexRef) bool {
+	if a.Database != b.Database {
+		return a.Database < b.Database
+	}
+	if a.Collection != b.Collection {
+		return a.Collection < b.Collection
+	}
+	return a.Name < b.Name
+}
+
+func sortRefs(refs []IndexRef) {
+	sort.Slice(refs, func(i, j int) bool { return refLess(refs[i], refs[j]) })
+}
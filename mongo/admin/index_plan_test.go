@@ -0,0 +1,296 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+)
+
+const testPlan = `{
+	"databases": [{
+		"name": "app",
+		"collections": [
+			{"name": "orders", "indexes": [{"keys": {"customer": 1, "createdAt": -1}}]},
+			{"name": "users", "indexes": [
+				{"keys": {"email": 1}, "unique": true},
+				{"name": "recent", "keys": {"createdAt": -1}, "expireAfterSeconds": 86400}
+			]}
+		]
+	}]
+}`
+
+func newPlanClient(t *testing.T, d *mongotest.Deployment) *mongo.Client {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+	return client
+}
+
+// handleListIndexes replies to the listIndexes commands of d with the given indexes of each
+// collection.
+func handleListIndexes(d *mongotest.Deployment, indexes map[string][]bson.D) {
+	d.Handle("listIndexes", func(cmd mongotest.Command) mongotest.Reply {
+		coll := cmd.Document.Lookup("listIndexes").StringValue()
+		docs := []interface{}{bson.D{{"v", 2}, {"key", bson.D{{"_id", 1}}}, {"name", "_id_"}}}
+		for _, index := range indexes[coll] {
+			docs = append(docs, index)
+		}
+		return mongotest.Cursor(cmd.Database+"."+coll, 0, docs...)
+	})
+}
+
+// createdIndexes returns the names of the indexes created on coll.
+func createdIndexes(d *mongotest.Deployment, coll string) []string {
+	var names []string
+	for _, cmd := range d.Commands("createIndexes") {
+		if cmd.Document.Lookup("createIndexes").StringValue() != coll {
+			continue
+		}
+		values, _ := cmd.Document.Lookup("indexes").Array().Values()
+		for _, v := range values {
+			names = append(names, v.Document().Lookup("name").StringValue())
+		}
+	}
+	return names
+}
+
+func TestParseIndexPlan(t *testing.T) {
+	t.Parallel()
+
+	plan, err := ParseIndexPlan([]byte(testPlan))
+	require.NoError(t, err, "ParseIndexPlan error")
+	require.Len(t, plan.Databases, 1, "expected one database")
+	users := plan.Databases[0].Collections[1]
+	assert.Equal(t, bson.D{{"email", int32(1)}}, users.Indexes[0].Keys, "keys mismatch")
+	assert.True(t, *users.Indexes[0].Unique, "expected a unique index")
+	assert.Equal(t, int32(86400), *users.Indexes[1].ExpireAfterSeconds, "TTL mismatch")
+
+	tasks, err := planTasks(plan)
+	require.NoError(t, err, "planTasks error")
+	assert.Equal(t, "customer_1_createdAt_-1", tasks[0].plan.Indexes[0].Name, "generated name mismatch")
+	assert.Equal(t, "email_1", tasks[1].plan.Indexes[0].Name, "generated name mismatch")
+	assert.Equal(t, "recent", tasks[1].plan.Indexes[1].Name, "expected the planned name")
+
+	_, err = ParseIndexPlan([]byte(`{"databases": 1}`))
+	assert.Error(t, err, "expected error for an invalid plan document")
+}
+
+func TestPlanTasksValidation(t *testing.T) {
+	t.Parallel()
+
+	keys := bson.D{{"a", 1}}
+	testCases := []struct {
+		name string
+		plan *IndexPlan
+	}{
+		{"nil plan", nil},
+		{"empty database name", &IndexPlan{Databases: []DatabaseIndexPlan{{}}}},
+		{"empty collection name", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{}}},
+		}}},
+		{"duplicate collection", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{Name: "users"}, {Name: "users"}}},
+		}}},
+		{"no keys", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{Name: "users", Indexes: []IndexSpec{{Name: "a"}}}}},
+		}}},
+		{"duplicate index", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{Name: "users", Indexes: []IndexSpec{
+				{Keys: keys}, {Name: "a_1", Keys: bson.D{{"b", 1}}},
+			}}}},
+		}}},
+		{"_id index", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{Name: "users", Indexes: []IndexSpec{
+				{Name: "_id_", Keys: bson.D{{"_id", 1}}},
+			}}}},
+		}}},
+		{"invalid key value", &IndexPlan{Databases: []DatabaseIndexPlan{
+			{Name: "app", Collections: []CollectionIndexPlan{{Name: "users", Indexes: []IndexSpec{
+				{Keys: bson.D{{"a", true}}},
+			}}}},
+		}}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := planTasks(tc.plan)
+			assert.Error(t, err, "expected validation error")
+		})
+	}
+}
+
+func TestApplyIndexPlan(t *testing.T) {
+	t.Parallel()
+
+	plan, err := ParseIndexPlan([]byte(testPlan))
+	require.NoError(t, err, "ParseIndexPlan error")
+
+	// The server returns the numbers of an index definition as doubles in some versions.
+	email := bson.D{{"v", 2}, {"key", bson.D{{"email", 1.0}}}, {"name", "email_1"}, {"unique", true}}
+	legacy := bson.D{{"v", 2}, {"key", bson.D{{"legacy", 1}}}, {"name", "legacy_1"}}
+
+	t.Run("creates missing and drops unlisted indexes", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		handleListIndexes(d, map[string][]bson.D{"users": {email, legacy}})
+		d.Handle("createIndexes", func(mongotest.Command) mongotest.Reply { return mongotest.Success() })
+		d.AddReplies("dropIndexes", mongotest.Success())
+
+		var (
+			mu       sync.Mutex
+			progress []IndexPlanProgress
+		)
+		opts := &ApplyIndexPlanOptions{
+			Concurrency:  2,
+			DropUnlisted: true,
+			Progress: func(p IndexPlanProgress) {
+				mu.Lock()
+				defer mu.Unlock()
+				progress = append(progress, p)
+			},
+		}
+		res, err := ApplyIndexPlan(context.Background(), newPlanClient(t, d), plan, opts)
+		require.NoError(t, err, "ApplyIndexPlan error")
+
+		assert.Equal(t, []IndexRef{
+			{"app", "orders", "customer_1_createdAt_-1"},
+			{"app", "users", "recent"},
+		}, res.Created, "created indexes mismatch")
+		assert.Equal(t, []IndexRef{{"app", "users", "legacy_1"}}, res.Dropped, "dropped indexes mismatch")
+		assert.Len(t, res.Drift, 0, "expected no drift")
+
+		assert.Equal(t, []string{"recent"}, createdIndexes(d, "users"), "expected only the missing index to be created")
+		create := d.Commands("createIndexes")
+		require.Len(t, create, 2, "expected one createIndexes command per collection")
+		drop := d.Commands("dropIndexes")
+		require.Len(t, drop, 1, "expected one dropIndexes command")
+		assert.Equal(t, "legacy_1", drop[0].Document.Lookup("index").StringValue(), "dropped index mismatch")
+
+		require.Len(t, progress, 2, "expected progress for each collection")
+		assert.Equal(t, 2, progress[1].Completed, "completed count mismatch")
+		assert.Equal(t, 2, progress[1].Total, "total count mismatch")
+	})
+
+	t.Run("reports mismatched indexes", func(t *testing.T) {
+		t.Parallel()
+
+		notUnique := bson.D{{"v", 2}, {"key", bson.D{{"email", 1}}}, {"name", "email_1"}}
+		d := mongotest.NewDeployment()
+		handleListIndexes(d, map[string][]bson.D{"users": {notUnique, legacy}})
+		d.Handle("createIndexes", func(mongotest.Command) mongotest.Reply { return mongotest.Success() })
+
+		res, err := ApplyIndexPlan(context.Background(), newPlanClient(t, d), plan, nil)
+		require.NoError(t, err, "ApplyIndexPlan error")
+		assert.Equal(t, []string{"recent"}, createdIndexes(d, "users"), "expected the mismatched index to be left unchanged")
+		assert.Len(t, d.Commands("dropIndexes"), 0, "expected no dropIndexes command")
+
+		require.Len(t, res.Drift, 2, "drift mismatch")
+		assert.Equal(t, IndexRef{"app", "users", "email_1"}, res.Drift[0].IndexRef, "drift index mismatch")
+		assert.Equal(t, IndexMismatch, res.Drift[0].Kind, "drift kind mismatch")
+		assert.Equal(t, "email_1", res.Drift[0].Existing.Name, "existing index mismatch")
+		assert.Equal(t, IndexRef{"app", "users", "legacy_1"}, res.Drift[1].IndexRef, "drift index mismatch")
+		assert.Equal(t, IndexUnlisted, res.Drift[1].Kind, "drift kind mismatch")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		handleListIndexes(d, map[string][]bson.D{"users": {email, legacy}})
+
+		res, err := ApplyIndexPlan(context.Background(), newPlanClient(t, d), plan, &ApplyIndexPlanOptions{
+			DropUnlisted: true,
+			DryRun:       true,
+		})
+		require.NoError(t, err, "ApplyIndexPlan error")
+		assert.True(t, res.DryRun, "expected a dry run result")
+		assert.Len(t, d.Commands("createIndexes", "dropIndexes"), 0, "expected no changes")
+		assert.Len(t, res.Created, 0, "expected no created indexes")
+
+		kinds := make(map[string]IndexDriftKind)
+		for _, drift := range res.Drift {
+			kinds[drift.String()] = drift.Kind
+		}
+		assert.Equal(t, map[string]IndexDriftKind{
+			"app.orders.customer_1_createdAt_-1": IndexMissing,
+			"app.users.recent":                   IndexMissing,
+			"app.users.legacy_1":                 IndexUnlisted,
+		}, kinds, "drift mismatch")
+	})
+
+	t.Run("failures do not stop other collections", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		handleListIndexes(d, map[string][]bson.D{"users": {email}})
+		d.Handle("createIndexes", func(cmd mongotest.Command) mongotest.Reply {
+			if cmd.Document.Lookup("createIndexes").StringValue() == "orders" {
+				return mongotest.CommandError(85, "IndexOptionsConflict", "conflict")
+			}
+			return mongotest.Success()
+		})
+
+		res, err := ApplyIndexPlan(context.Background(), newPlanClient(t, d), plan, nil)
+		require.Error(t, err, "expected ApplyIndexPlan error")
+		require.Len(t, res.Failed, 1, "expected one failed collection")
+		assert.Equal(t, "orders", res.Failed[0].Collection, "failed collection mismatch")
+
+		var ce mongo.CommandError
+		assert.True(t, errors.As(err, &ce), "expected the command error to be wrapped")
+		assert.Equal(t, []IndexRef{{"app", "users", "recent"}}, res.Created, "expected the other collection to be applied")
+		require.Len(t, res.Drift, 1, "drift mismatch")
+		assert.Equal(t, IndexMissing, res.Drift[0].Kind, "expected the failed index to be reported missing")
+	})
+}
+
+func TestEqualDocuments(t *testing.T) {
+	t.Parallel()
+
+	marshal := func(doc bson.D) bson.Raw {
+		raw, err := bson.Marshal(doc)
+		require.NoError(t, err, "Marshal error")
+		return raw
+	}
+
+	testCases := []struct {
+		name string
+		a, b bson.D
+		want bool
+	}{
+		{"equal", bson.D{{"a", 1}, {"b", "text"}}, bson.D{{"a", 1}, {"b", "text"}}, true},
+		{"numeric types", bson.D{{"a", int32(1)}}, bson.D{{"a", 1.0}}, true},
+		{"nested", bson.D{{"a", bson.D{{"$gt", int64(5)}}}}, bson.D{{"a", bson.D{{"$gt", 5.0}}}}, true},
+		{"different order", bson.D{{"a", 1}, {"b", 1}}, bson.D{{"b", 1}, {"a", 1}}, false},
+		{"different value", bson.D{{"a", 1}}, bson.D{{"a", -1}}, false},
+		{"different length", bson.D{{"a", 1}}, bson.D{{"a", 1}, {"b", 1}}, false},
+		{"number and string", bson.D{{"a", 1}}, bson.D{{"a", "1"}}, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, equalDocuments(marshal(tc.a), marshal(tc.b)), "equalDocuments mismatch")
+		})
+	}
+}
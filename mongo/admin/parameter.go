@@ -0,0 +1,136 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package admin provides typed helpers for the getParameter and setParameter administration
+// commands, with a catalog of common server parameters and their types:
+//
+//	limit, err := admin.GetParameter[int32](ctx, client, "transactionLifetimeLimitSeconds")
+//	if err != nil {
+//		return err
+//	}
+//	previous, err := admin.SetParameter(ctx, client, "notablescan", true)
+//
+// Parameters that are not in the catalog can be read and set too, but their values are not
+// checked before the command is sent. See Parameters for the catalog.
+//
+// ApplyIndexPlan applies a declarative plan of the indexes of collections across databases and
+// reports how the existing indexes drift from it:
+//
+//	plan, err := admin.ParseIndexPlan(data)
+//	if err != nil {
+//		return err
+//	}
+//	result, err := admin.ApplyIndexPlan(ctx, client, plan, &admin.ApplyIndexPlanOptions{Concurrency: 4})
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var rawValueType = reflect.TypeOf(bson.RawValue{})
+
+// GetParameter runs the getParameter command on the admin database and returns the value of the
+// parameter with the given name, decoded as a T. If the parameter is in the catalog, GetParameter
+// returns an error without running the command if T cannot hold its values; use bson.RawValue or
+// interface{} to get any value. Because parameters are set per server, the opts parameter can be
+// used to specify which server to read from with a read preference (see the options.RunCmdOptions
+// documentation).
+func GetParameter[T any](
+	ctx context.Context,
+	client *mongo.Client,
+	name string,
+	opts ...options.Lister[options.RunCmdOptions],
+) (T, error) {
+	var value T
+	if err := checkParameterType(name, reflect.TypeOf(&value).Elem()); err != nil {
+		return value, err
+	}
+
+	cmd := bson.D{{"getParameter", 1}, {name, 1}}
+	resp, err := client.Database("admin").RunCommand(ctx, cmd, opts...).Raw()
+	if err != nil {
+		return value, err
+	}
+	raw, err := resp.LookupErr(name)
+	if err != nil {
+		return value, fmt.Errorf("parameter %q not returned by getParameter", name)
+	}
+	if err := raw.Unmarshal(&value); err != nil {
+		return value, fmt.Errorf("failed to decode parameter %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// SetParameter runs the setParameter command on the admin database of the primary, or of the
+// server selected by the read preference of opts, to set the parameter with the given name to
+// value, and returns its previous value. If the parameter is in the catalog, SetParameter returns
+// an error without running the command if the parameter can only be set at startup or if T cannot
+// hold its values.
+func SetParameter[T any](
+	ctx context.Context,
+	client *mongo.Client,
+	name string,
+	value T,
+	opts ...options.Lister[options.RunCmdOptions],
+) (T, error) {
+	var previous T
+	if p, ok := catalog[name]; ok && !p.Runtime {
+		return previous, fmt.Errorf("parameter %q can only be set at startup", name)
+	}
+	if err := checkParameterType(name, reflect.TypeOf(&value).Elem()); err != nil {
+		return previous, err
+	}
+
+	cmd := bson.D{{"setParameter", 1}, {name, value}}
+	resp, err := client.Database("admin").RunCommand(ctx, cmd, opts...).Raw()
+	if err != nil {
+		return previous, err
+	}
+	if raw, err := resp.LookupErr("was"); err == nil {
+		if err := raw.Unmarshal(&previous); err != nil {
+			return previous, fmt.Errorf("failed to decode previous value of parameter %q: %w", name, err)
+		}
+	}
+	return previous, nil
+}
+
+// checkParameterType returns an error if the parameter with the given name is in the catalog and
+// values of type t cannot hold its values.
+func checkParameterType(name string, t reflect.Type) error {
+	if name == "" {
+		return errors.New("parameter name must not be empty")
+	}
+	p, ok := catalog[name]
+	if !ok || t.Kind() == reflect.Interface || t == rawValueType {
+		return nil
+	}
+	if want, got := typeClass(p.Type), typeClass(t); want != got {
+		return fmt.Errorf("parameter %q is a %s, which cannot be held by a %v", name, want, t)
+	}
+	return nil
+}
+
+// typeClass returns the BSON type class of the values of t.
+func typeClass(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "document"
+}
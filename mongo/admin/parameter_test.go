@@ -0,0 +1,86 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package admin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	p, ok := LookupParameter("transactionLifetimeLimitSeconds")
+	require.True(t, ok, "expected parameter in catalog")
+	assert.Equal(t, reflect.TypeOf(int32(0)), p.Type, "type mismatch")
+	assert.True(t, p.Runtime, "expected parameter to be settable at runtime")
+
+	_, ok = LookupParameter("noSuchParameter")
+	assert.False(t, ok, "expected parameter to not be in catalog")
+
+	params := Parameters()
+	require.Len(t, params, len(catalog), "parameters length mismatch")
+	for i := 1; i < len(params); i++ {
+		assert.True(t, params[i-1].Name < params[i].Name, "expected parameters to be sorted")
+	}
+}
+
+func TestCheckParameterType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		param   string
+		typ     reflect.Type
+		wantErr bool
+	}{
+		{"number", "logLevel", reflect.TypeOf(int64(0)), false},
+		{"bool", "notablescan", reflect.TypeOf(false), false},
+		{"document", "logComponentVerbosity", reflect.TypeOf(bson.M{}), false},
+		{"raw value", "logLevel", reflect.TypeOf(bson.RawValue{}), false},
+		{"interface", "logLevel", reflect.TypeOf((*interface{})(nil)).Elem(), false},
+		{"unknown parameter", "noSuchParameter", reflect.TypeOf(""), false},
+		{"bool for number", "logLevel", reflect.TypeOf(false), true},
+		{"number for string", "wiredTigerEngineRuntimeConfig", reflect.TypeOf(0), true},
+		{"string for document", "changeStreamOptions", reflect.TypeOf(""), true},
+		{"empty name", "", reflect.TypeOf(0), true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkParameterType(tc.param, tc.typ)
+			if tc.wantErr {
+				assert.Error(t, err, "expected error")
+			} else {
+				assert.NoError(t, err, "checkParameterType error")
+			}
+		})
+	}
+}
+
+func TestParameterValidation(t *testing.T) {
+	t.Parallel()
+
+	client, err := mongo.Connect()
+	require.NoError(t, err, "Connect error")
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	_, err = GetParameter[string](context.Background(), client, "logLevel")
+	assert.Error(t, err, "expected type error")
+
+	_, err = SetParameter(context.Background(), client, "maxSessions", int32(10))
+	assert.Error(t, err, "expected startup parameter error")
+}
@@ -0,0 +1,64 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+)
+
+// OutputStageError is returned by Aggregate, without running the command, if the pipeline contains
+// a $out or $merge stage that the server would reject.
+//
+// Pipelines that end with a $out or $merge stage use the write concern from the AggregateOptions
+// or, if it is not set, the write concern of the Collection or Database. They run on the primary unless the read preference allows secondaries and the selected
+// server is MongoDB 5.0 or newer, in which case they may run on a secondary that writes the output
+// to the primary.
+type OutputStageError struct {
+	// Stage is the name of the stage, "$out" or "$merge".
+	Stage string
+
+	// Index is the index of the stage in the pipeline.
+	Index int
+
+	// Reason describes why the stage cannot be run.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e OutputStageError) Error() string {
+	return fmt.Sprintf("invalid %s stage at index %d of the aggregation pipeline: %s", e.Stage, e.Index, e.Reason)
+}
+
+// checkOutputStages returns an OutputStageError if pipeline, which is run in sess, contains a $out
+// or $merge stage that is not the last stage, or any such stage while a transaction is running.
+func checkOutputStages(pipeline bsoncore.Document, sess *session.Client) error {
+	stages, err := bsoncore.Array(pipeline).Values()
+	if err != nil {
+		return err
+	}
+	for i, stage := range stages {
+		doc, ok := stage.DocumentOK()
+		if !ok {
+			continue
+		}
+		elem, err := doc.IndexErr(0)
+		if err != nil || (elem.Key() != "$out" && elem.Key() != "$merge") {
+			continue
+		}
+
+		if i != len(stages)-1 {
+			return OutputStageError{Stage: elem.Key(), Index: i, Reason: "it must be the last stage"}
+		}
+		if sess.TransactionRunning() {
+			return OutputStageError{Stage: elem.Key(), Index: i, Reason: "it cannot be used in a transaction"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+)
+
+func TestCheckOutputStages(t *testing.T) {
+	t.Parallel()
+
+	marshalPipeline := func(t *testing.T, pipeline interface{}) []byte {
+		t.Helper()
+
+		arr, _, err := marshalAggregatePipeline(pipeline, nil, nil)
+		require.NoError(t, err, "marshalAggregatePipeline error")
+		return arr
+	}
+	inTransaction := &session.Client{TransactionState: session.InProgress}
+
+	testCases := []struct {
+		name     string
+		pipeline interface{}
+		sess     *session.Client
+		want     *OutputStageError
+	}{
+		{
+			name:     "no output stage",
+			pipeline: Pipeline{{{"$match", bson.D{}}}},
+		},
+		{
+			name:     "final $out",
+			pipeline: Pipeline{{{"$match", bson.D{}}}, {{"$out", "out"}}},
+		},
+		{
+			name:     "non-final $merge",
+			pipeline: Pipeline{{{"$merge", "out"}}, {{"$match", bson.D{}}}},
+			want:     &OutputStageError{Stage: "$merge", Index: 0, Reason: "it must be the last stage"},
+		},
+		{
+			name:     "$out in transaction",
+			pipeline: Pipeline{{{"$out", "out"}}},
+			sess:     inTransaction,
+			want:     &OutputStageError{Stage: "$out", Index: 0, Reason: "it cannot be used in a transaction"},
+		},
+		{
+			name:     "no output stage in transaction",
+			pipeline: Pipeline{{{"$match", bson.D{}}}},
+			sess:     inTransaction,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkOutputStages(marshalPipeline(t, tc.pipeline), tc.sess)
+			if tc.want == nil {
+				assert.NoError(t, err, "checkOutputStages error")
+				return
+			}
+			var ose OutputStageError
+			require.True(t, errors.As(err, &ose), "expected OutputStageError, got %v", err)
+			assert.Equal(t, *tc.want, ose, "OutputStageError mismatch")
+		})
+	}
+
+	t.Run("Aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		var started []*event.CommandStartedEvent
+		monitor := &event.CommandMonitor{
+			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+				started = append(started, evt)
+			},
+		}
+		md := drivertest.NewMockDeployment(bson.D{{"ok", 1}, {"cursor", bson.D{
+			{"id", int64(0)},
+			{"ns", "db.coll"},
+			{"firstBatch", bson.A{}},
+		}}})
+		clientOpts := options.Client().SetMonitor(monitor).SetWriteConcern(writeconcern.Majority())
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = md
+
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		defer func() { _ = client.Disconnect(context.Background()) }()
+		coll := client.Database("db").Collection("coll")
+
+		_, err = coll.Aggregate(context.Background(), Pipeline{{{"$out", "out"}}, {{"$match", bson.D{}}}})
+		assert.True(t, errors.As(err, &OutputStageError{}), "expected OutputStageError, got %v", err)
+		assert.Len(t, started, 0, "expected the invalid pipeline to not be sent")
+
+		_, err = coll.Aggregate(context.Background(), Pipeline{{{"$match", bson.D{}}}, {{"$out", "out"}}})
+		require.NoError(t, err, "Aggregate error")
+		require.Len(t, started, 1, "expected one command")
+		w := started[0].Command.Lookup("writeConcern", "w")
+		assert.Equal(t, "majority", w.StringValue(), "expected the collection write concern")
+	})
+}
@@ -0,0 +1,372 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package audit records an audit trail of the changes made to the documents of a collection.
+//
+// A Collection wraps a mongo.Collection. Each change made through its write methods is recorded as
+// an Entry in a shadow collection, with the actor that made the change, the time of the change,
+// the document before and after the change, and the changed fields:
+//
+//	users := audit.New(db.Collection("users"), db.Collection("users_audit")).
+//		SetRedact("password", "profile.ssn")
+//
+//	ctx = audit.WithActor(ctx, "alice@example.com")
+//	_, err := users.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{{"$set", bson.D{{"email", email}}}})
+//
+// If the context of a method is in a transaction, the change and its entry are written in that
+// transaction. Otherwise, the change is made in a new transaction if SetTransactions is enabled,
+// which requires a replica set or sharded cluster, or without a transaction, in which case a
+// failure can leave a change without its entry and a concurrent change can be recorded with an
+// inaccurate before or after document.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// Redacted replaces the values of redacted fields in entries.
+const Redacted = "[REDACTED]"
+
+// Operation is the kind of change recorded by an Entry.
+type Operation string
+
+// These constants are the operations recorded by entries.
+const (
+	OperationInsert  Operation = "insert"
+	OperationUpdate  Operation = "update"
+	OperationReplace Operation = "replace"
+	OperationDelete  Operation = "delete"
+)
+
+// Entry is a document of the shadow collection that records a change to a document.
+type Entry struct {
+	ID         bson.ObjectID `bson:"_id"`
+	Collection string        `bson:"collection"`
+	Operation  Operation     `bson:"op"`
+	DocumentID interface{}   `bson:"documentId"`
+	Actor      interface{}   `bson:"actor,omitempty"`
+	Time       time.Time     `bson:"at"`
+
+	// Before is the document before the change. It is empty for an insert.
+	Before bson.Raw `bson:"before,omitempty"`
+
+	// After is the document after the change. It is empty for a delete.
+	After bson.Raw `bson:"after,omitempty"`
+
+	// Diff contains the fields that differ between Before and After. It is empty for an insert
+	// or a delete.
+	Diff []Change `bson:"diff,omitempty"`
+}
+
+// Change is a field of a document that was set, modified, or removed. Fields of embedded documents
+// are compared individually, while arrays are compared as a whole.
+type Change struct {
+	// Path is the dotted path of the field, e.g. "address.city".
+	Path string `bson:"path"`
+
+	// Old is the value before the change. It is empty if the field was set.
+	Old bson.RawValue `bson:"old,omitempty"`
+
+	// New is the value after the change. It is empty if the field was removed.
+	New bson.RawValue `bson:"new,omitempty"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that carries actor, which the default actor function of a
+// Collection records as the actor of the changes made with the returned context.
+func WithActor(ctx context.Context, actor interface{}) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, or nil if ctx carries no actor.
+func ActorFromContext(ctx context.Context) interface{} {
+	return ctx.Value(actorKey{})
+}
+
+// Collection is a mongo.Collection that records its changes in a shadow collection.
+type Collection struct {
+	coll         *mongo.Collection
+	primary      *mongo.Collection
+	shadow       *mongo.Collection
+	actor        func(context.Context) interface{}
+	redact       map[string]struct{}
+	transactions bool
+	now          func() time.Time
+}
+
+// New returns a Collection that makes changes to coll and records them in shadow. The shadow
+// collection must belong to the same client as coll for the entries to be written in the
+// transactions of the changes.
+func New(coll, shadow *mongo.Collection) *Collection {
+	return &Collection{
+		coll:    coll,
+		primary: coll.Clone(options.Collection().SetReadPreference(readpref.Primary())),
+		shadow:  shadow,
+		actor:   ActorFromContext,
+		now:     time.Now,
+	}
+}
+
+// SetActor sets the function that returns the actor recorded for a change from the context of the
+// method that makes the change. The default is ActorFromContext.
+func (c *Collection) SetActor(fn func(ctx context.Context) interface{}) *Collection {
+	c.actor = fn
+	return c
+}
+
+// SetRedact sets the dotted paths of the fields whose values are replaced with Redacted in the
+// documents and changes of entries. A redacted field whose value changes is still recorded in the
+// Diff of an entry, without its values.
+func (c *Collection) SetRedact(paths ...string) *Collection {
+	c.redact = make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		c.redact[path] = struct{}{}
+	}
+	return c
+}
+
+// SetTransactions configures the Collection to make each change and write its entry in a new
+// transaction if the context of the method is not already in a transaction. Transactions require
+// a replica set or sharded cluster. The default is false.
+func (c *Collection) SetTransactions(transactions bool) *Collection {
+	c.transactions = transactions
+	return c
+}
+
+// Collection returns the wrapped mongo.Collection, whose changes are not recorded.
+func (c *Collection) Collection() *mongo.Collection {
+	return c.coll
+}
+
+// InsertOne inserts document like mongo.Collection.InsertOne and records the insert.
+func (c *Collection) InsertOne(
+	ctx context.Context,
+	document interface{},
+	opts ...options.Lister[options.InsertOneOptions],
+) (*mongo.InsertOneResult, error) {
+	var res *mongo.InsertOneResult
+	err := c.run(ctx, func(ctx context.Context) error {
+		var err error
+		if res, err = c.coll.InsertOne(ctx, document, opts...); err != nil || !res.Acknowledged {
+			return err
+		}
+		after, err := c.findByID(ctx, res.InsertedID)
+		if err != nil {
+			return err
+		}
+		return c.record(ctx, OperationInsert, res.InsertedID, nil, after)
+	})
+	return res, err
+}
+
+// UpdateOne updates a document like mongo.Collection.UpdateOne and records the update if it
+// changes the document.
+func (c *Collection) UpdateOne(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	opts ...options.Lister[options.UpdateOneOptions],
+) (*mongo.UpdateResult, error) {
+	args, err := mongoutil.NewOptions[options.UpdateOneOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	var res *mongo.UpdateResult
+	err = c.run(ctx, func(ctx context.Context) error {
+		before, target, err := c.findTarget(ctx, filter, args.Collation, args.Hint)
+		if err != nil {
+			return err
+		}
+		if res, err = c.coll.UpdateOne(ctx, target, update, opts...); err != nil {
+			return err
+		}
+		return c.recordChange(ctx, OperationUpdate, before, res)
+	})
+	return res, err
+}
+
+// ReplaceOne replaces a document like mongo.Collection.ReplaceOne and records the replacement if
+// it changes the document.
+func (c *Collection) ReplaceOne(
+	ctx context.Context,
+	filter interface{},
+	replacement interface{},
+	opts ...options.Lister[options.ReplaceOptions],
+) (*mongo.UpdateResult, error) {
+	args, err := mongoutil.NewOptions[options.ReplaceOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	var res *mongo.UpdateResult
+	err = c.run(ctx, func(ctx context.Context) error {
+		before, target, err := c.findTarget(ctx, filter, args.Collation, args.Hint)
+		if err != nil {
+			return err
+		}
+		if res, err = c.coll.ReplaceOne(ctx, target, replacement, opts...); err != nil {
+			return err
+		}
+		return c.recordChange(ctx, OperationReplace, before, res)
+	})
+	return res, err
+}
+
+// DeleteOne deletes a document like mongo.Collection.DeleteOne and records the delete.
+func (c *Collection) DeleteOne(
+	ctx context.Context,
+	filter interface{},
+	opts ...options.Lister[options.DeleteOneOptions],
+) (*mongo.DeleteResult, error) {
+	args, err := mongoutil.NewOptions[options.DeleteOneOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	var res *mongo.DeleteResult
+	err = c.run(ctx, func(ctx context.Context) error {
+		before, target, err := c.findTarget(ctx, filter, args.Collation, args.Hint)
+		if err != nil {
+			return err
+		}
+		if res, err = c.coll.DeleteOne(ctx, target, opts...); err != nil {
+			return err
+		}
+		if before == nil || res.DeletedCount == 0 {
+			return nil
+		}
+		return c.record(ctx, OperationDelete, before.Lookup("_id"), before, nil)
+	})
+	return res, err
+}
+
+// run runs fn in the transaction of ctx, in a new transaction if transactions are enabled, or
+// without a transaction otherwise.
+func (c *Collection) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if sess := mongo.SessionFromContext(ctx); sess != nil && sess.ClientSession().TransactionRunning() {
+		return fn(ctx)
+	}
+	if !c.transactions {
+		return fn(ctx)
+	}
+
+	sess, err := c.coll.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// findTarget returns the document that matches filter, or nil if there is none, and a filter that
+// only matches that document if it still matches filter.
+func (c *Collection) findTarget(
+	ctx context.Context,
+	filter interface{},
+	collation *options.Collation,
+	hint interface{},
+) (bson.Raw, interface{}, error) {
+	findOpts := options.FindOne()
+	if collation != nil {
+		findOpts.SetCollation(collation)
+	}
+	if hint != nil {
+		findOpts.SetHint(hint)
+	}
+	doc, err := c.primary.FindOne(ctx, filter, findOpts).Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, filter, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id, err := doc.LookupErr("_id")
+	if err != nil {
+		return nil, nil, fmt.Errorf("document has no _id: %w", err)
+	}
+	return doc, bson.D{{"$and", bson.A{filter, bson.D{{"_id", id}}}}}, nil
+}
+
+func (c *Collection) findByID(ctx context.Context, id interface{}) (bson.Raw, error) {
+	doc, err := c.primary.FindOne(ctx, bson.D{{"_id", id}}).Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// recordChange records the update or replacement of before, or the upsert, described by res.
+func (c *Collection) recordChange(ctx context.Context, op Operation, before bson.Raw, res *mongo.UpdateResult) error {
+	var id interface{}
+	switch {
+	case res.UpsertedID != nil:
+		id, op = res.UpsertedID, OperationInsert
+	case before != nil && res.ModifiedCount > 0:
+		id = before.Lookup("_id")
+	default:
+		return nil
+	}
+
+	after, err := c.findByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.record(ctx, op, id, before, after)
+}
+
+// record writes the entry of a change to the shadow collection.
+func (c *Collection) record(ctx context.Context, op Operation, id interface{}, before, after bson.Raw) error {
+	entry := Entry{
+		ID:         bson.NewObjectID(),
+		Collection: c.coll.Name(),
+		Operation:  op,
+		DocumentID: id,
+		Time:       c.now(),
+	}
+	if c.actor != nil {
+		entry.Actor = c.actor(ctx)
+	}
+
+	var err error
+	if before != nil && after != nil {
+		if entry.Diff, err = c.diff("", before, after); err != nil {
+			return err
+		}
+		if len(entry.Diff) == 0 {
+			return nil
+		}
+	}
+	if entry.Before, err = c.redactDocument("", before); err != nil {
+		return err
+	}
+	if entry.After, err = c.redactDocument("", after); err != nil {
+		return err
+	}
+
+	if _, err = c.shadow.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record %s of document %v: %w", op, id, err)
+	}
+	return nil
+}
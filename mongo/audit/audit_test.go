@@ -0,0 +1,236 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+)
+
+var testTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestCollection(t *testing.T, d *mongotest.Deployment) *Collection {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	db := client.Database("db")
+	c := New(db.Collection("users"), db.Collection("users_audit"))
+	c.now = func() time.Time { return testTime }
+	return c
+}
+
+func mustMarshal(t *testing.T, doc interface{}) bson.Raw {
+	t.Helper()
+
+	raw, err := bson.Marshal(doc)
+	require.NoError(t, err, "Marshal error")
+	return raw
+}
+
+// entries returns the entries inserted into the shadow collection.
+func entries(t *testing.T, d *mongotest.Deployment) []Entry {
+	t.Helper()
+
+	var result []Entry
+	for _, cmd := range d.Commands("insert") {
+		if cmd.Document.Lookup("insert").StringValue() != "users_audit" {
+			continue
+		}
+		docs, err := cmd.Document.Lookup("documents").Array().Values()
+		require.NoError(t, err, "Values error")
+		for _, doc := range docs {
+			var entry Entry
+			require.NoError(t, bson.Unmarshal(doc.Document(), &entry), "Unmarshal error")
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func TestCollection(t *testing.T) {
+	t.Parallel()
+
+	before := bson.D{
+		{"_id", 1},
+		{"email", "a@example.com"},
+		{"password", "old"},
+		{"profile", bson.D{{"city", "Paris"}, {"ssn", "123"}}},
+		{"tags", bson.A{"a"}},
+	}
+	after := bson.D{
+		{"_id", 1},
+		{"email", "b@example.com"},
+		{"password", "new"},
+		{"profile", bson.D{{"city", "Paris"}, {"ssn", "456"}}},
+		{"tags", bson.A{"a", "b"}},
+		{"verified", true},
+	}
+
+	t.Run("UpdateOne", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find",
+			mongotest.Cursor("db.users", 0, before),
+			mongotest.Cursor("db.users", 0, after))
+		d.AddReplies("update", mongotest.Success(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		d.AddReplies("insert", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		c := newTestCollection(t, d).SetRedact("password", "profile.ssn")
+
+		ctx := WithActor(context.Background(), "alice")
+		res, err := c.UpdateOne(ctx, bson.D{{"email", "a@example.com"}}, bson.D{{"$set", bson.D{{"x", 1}}}})
+		require.NoError(t, err, "UpdateOne error")
+		assert.Equal(t, int64(1), res.ModifiedCount, "modified count mismatch")
+
+		update := d.Commands("update")[0].Document.Lookup("updates").Array().Index(0).Document()
+		assert.Equal(t, int32(1), update.Lookup("q", "$and", "1", "_id").Int32(),
+			"expected the update to target the found document")
+
+		got := entries(t, d)
+		require.Len(t, got, 1, "expected one entry")
+		entry := got[0]
+		assert.Equal(t, "users", entry.Collection, "collection mismatch")
+		assert.Equal(t, OperationUpdate, entry.Operation, "operation mismatch")
+		assert.Equal(t, int32(1), entry.DocumentID, "document ID mismatch")
+		assert.Equal(t, "alice", entry.Actor, "actor mismatch")
+		assert.True(t, entry.Time.Equal(testTime), "time mismatch")
+		assert.Equal(t, Redacted, entry.Before.Lookup("password").StringValue(), "expected redacted password")
+		assert.Equal(t, Redacted, entry.After.Lookup("profile", "ssn").StringValue(), "expected redacted ssn")
+		assert.Equal(t, "Paris", entry.After.Lookup("profile", "city").StringValue(), "city mismatch")
+
+		paths := make([]string, 0, len(entry.Diff))
+		for _, change := range entry.Diff {
+			paths = append(paths, change.Path)
+		}
+		assert.Equal(t, []string{"email", "password", "profile.ssn", "tags", "verified"}, paths, "changed paths mismatch")
+		assert.Equal(t, "a@example.com", entry.Diff[0].Old.StringValue(), "old email mismatch")
+		assert.Equal(t, "b@example.com", entry.Diff[0].New.StringValue(), "new email mismatch")
+		assert.Equal(t, Redacted, entry.Diff[1].New.StringValue(), "expected redacted password change")
+		assert.Equal(t, Redacted, entry.Diff[2].Old.StringValue(), "expected redacted ssn change")
+		assert.True(t, entry.Diff[4].Old.IsZero(), "expected no old value for an added field")
+	})
+
+	t.Run("UpdateOne without change", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.users", 0, before))
+		d.AddReplies("update", mongotest.Success(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}))
+		c := newTestCollection(t, d)
+
+		_, err := c.UpdateOne(context.Background(), bson.D{}, bson.D{{"$set", bson.D{{"email", "a@example.com"}}}})
+		require.NoError(t, err, "UpdateOne error")
+		assert.Len(t, entries(t, d), 0, "expected no entry")
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find",
+			mongotest.Cursor("db.users", 0),
+			mongotest.Cursor("db.users", 0, after))
+		d.AddReplies("update", mongotest.Success(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 0},
+			bson.E{Key: "upserted", Value: bson.A{bson.D{{"index", 0}, {"_id", 1}}}}))
+		d.AddReplies("insert", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		c := newTestCollection(t, d)
+
+		_, err := c.ReplaceOne(context.Background(), bson.D{{"_id", 1}}, after)
+		require.NoError(t, err, "ReplaceOne error")
+		got := entries(t, d)
+		require.Len(t, got, 1, "expected one entry")
+		assert.Equal(t, OperationInsert, got[0].Operation, "expected the upsert to be recorded as an insert")
+		assert.Nil(t, got[0].Before, "expected no before document")
+		assert.Equal(t, mustMarshal(t, after), got[0].After, "after document mismatch")
+	})
+
+	t.Run("InsertOne", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("insert",
+			mongotest.Success(bson.E{Key: "n", Value: 1}),
+			mongotest.Success(bson.E{Key: "n", Value: 1}))
+		d.AddReplies("find", mongotest.Cursor("db.users", 0, after))
+		c := newTestCollection(t, d).SetActor(func(context.Context) interface{} { return "service" })
+
+		res, err := c.InsertOne(context.Background(), after)
+		require.NoError(t, err, "InsertOne error")
+		assert.Equal(t, int32(1), res.InsertedID, "inserted ID mismatch")
+
+		got := entries(t, d)
+		require.Len(t, got, 1, "expected one entry")
+		assert.Equal(t, OperationInsert, got[0].Operation, "operation mismatch")
+		assert.Equal(t, "service", got[0].Actor, "actor mismatch")
+		assert.Len(t, got[0].Diff, 0, "expected no diff for an insert")
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.users", 0, before))
+		d.AddReplies("delete", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		d.AddReplies("insert", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		c := newTestCollection(t, d).SetRedact("profile")
+
+		_, err := c.DeleteOne(context.Background(), bson.D{{"email", "a@example.com"}})
+		require.NoError(t, err, "DeleteOne error")
+
+		got := entries(t, d)
+		require.Len(t, got, 1, "expected one entry")
+		assert.Equal(t, OperationDelete, got[0].Operation, "operation mismatch")
+		assert.Equal(t, Redacted, got[0].Before.Lookup("profile").StringValue(), "expected redacted profile")
+		assert.Nil(t, got[0].After, "expected no after document")
+	})
+
+	t.Run("DeleteOne without match", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.users", 0))
+		d.AddReplies("delete", mongotest.Success(bson.E{Key: "n", Value: 0}))
+		c := newTestCollection(t, d)
+
+		_, err := c.DeleteOne(context.Background(), bson.D{{"_id", 2}})
+		require.NoError(t, err, "DeleteOne error")
+		assert.Len(t, entries(t, d), 0, "expected no entry")
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.users", 0, before))
+		d.AddReplies("delete", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		d.AddReplies("insert", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		d.AddReplies("commitTransaction", mongotest.Success())
+		c := newTestCollection(t, d).SetTransactions(true)
+
+		_, err := c.DeleteOne(context.Background(), bson.D{{"_id", 1}})
+		require.NoError(t, err, "DeleteOne error")
+
+		find := d.Commands("find")[0].Document
+		assert.True(t, find.Lookup("startTransaction").Boolean(), "expected the find to start a transaction")
+		insert := d.Commands("insert")[0].Document
+		assert.Equal(t, find.Lookup("txnNumber"), insert.Lookup("txnNumber"),
+			"expected the entry to be written in the transaction")
+		assert.False(t, insert.Lookup("autocommit").Boolean(), "expected the entry to be written in the transaction")
+		assert.Len(t, d.Commands("commitTransaction"), 1, "expected the transaction to be committed")
+	})
+}
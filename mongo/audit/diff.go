@@ -0,0 +1,129 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package audit
+
+import (
+	"bytes"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// redactedValue is the value of redacted fields.
+var redactedValue = bson.RawValue{Type: bson.TypeString, Value: bsoncore.AppendString(nil, Redacted)}
+
+// diff returns the changes between the fields of before and after, which are the embedded
+// documents at path, or the top-level documents if path is empty.
+func (c *Collection) diff(path string, before, after bson.Raw) ([]Change, error) {
+	beforeElems, err := before.Elements()
+	if err != nil {
+		return nil, err
+	}
+	afterElems, err := after.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	afterValues := make(map[string]bson.RawValue, len(afterElems))
+	for _, elem := range afterElems {
+		afterValues[elem.Key()] = elem.Value()
+	}
+
+	var changes []Change
+	seen := make(map[string]struct{}, len(beforeElems))
+	for _, elem := range beforeElems {
+		key := elem.Key()
+		seen[key] = struct{}{}
+		old := elem.Value()
+		nv, ok := afterValues[key]
+		fieldPath := join(path, key)
+
+		switch {
+		case !ok:
+			changes = append(changes, c.change(fieldPath, old, bson.RawValue{}))
+		case old.Type == bson.TypeEmbeddedDocument && nv.Type == bson.TypeEmbeddedDocument && !c.redacted(fieldPath):
+			nested, err := c.diff(fieldPath, old.Document(), nv.Document())
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, nested...)
+		case old.Type != nv.Type || !bytes.Equal(old.Value, nv.Value):
+			changes = append(changes, c.change(fieldPath, old, nv))
+		}
+	}
+	for _, elem := range afterElems {
+		if _, ok := seen[elem.Key()]; !ok {
+			changes = append(changes, c.change(join(path, elem.Key()), bson.RawValue{}, elem.Value()))
+		}
+	}
+	return changes, nil
+}
+
+// change returns the Change of the field at path from old to nv with redacted values.
+func (c *Collection) change(path string, old, nv bson.RawValue) Change {
+	return Change{Path: path, Old: c.redactValue(path, old), New: c.redactValue(path, nv)}
+}
+
+// redacted reports whether the field at path or one of its parents is redacted.
+func (c *Collection) redacted(path string) bool {
+	if len(c.redact) == 0 {
+		return false
+	}
+	for i := 0; i <= len(path); i++ {
+		if i == len(path) || path[i] == '.' {
+			if _, ok := c.redact[path[:i]]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue returns val, the value of the field at path, with redacted fields replaced.
+func (c *Collection) redactValue(path string, val bson.RawValue) bson.RawValue {
+	switch {
+	case val.IsZero():
+		return val
+	case c.redacted(path):
+		return redactedValue
+	case val.Type == bson.TypeEmbeddedDocument:
+		doc, err := c.redactDocument(path, val.Document())
+		if err != nil {
+			return val
+		}
+		return bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: doc}
+	default:
+		return val
+	}
+}
+
+// redactDocument returns doc, the embedded document at path, or the top-level document if path is
+// empty, with redacted fields replaced.
+func (c *Collection) redactDocument(path string, doc bson.Raw) (bson.Raw, error) {
+	if doc == nil || len(c.redact) == 0 {
+		return doc, nil
+	}
+
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	idx, dst := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		val := c.redactValue(join(path, elem.Key()), elem.Value())
+		dst = bsoncore.AppendValueElement(dst, elem.Key(), bsoncore.Value{Type: bsoncore.Type(val.Type), Data: val.Value})
+	}
+	dst, err = bsoncore.AppendDocumentEnd(dst, idx)
+	return bson.Raw(dst), err
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
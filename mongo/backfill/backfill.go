@@ -0,0 +1,326 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package backfill runs an aggregation over a large collection as several smaller aggregations,
+// for backfills whose intermediate results are too large to be written with a single $out or
+// $merge stage.
+//
+// A Backfill partitions the source collection by ranges of a field with a $bucketAuto stage, then
+// runs the pipeline for each range in parallel and merges the results into the target collection:
+//
+//	target := db.Collection("orders_v2")
+//	pipeline := mongo.Pipeline{{{"$set", bson.D{{"total", bson.D{{"$sum", "$items.price"}}}}}}}
+//	res, err := backfill.New(pipeline, target).SetPartitions(64).Run(ctx, db.Collection("orders"))
+//	...
+//	fmt.Printf("backfilled %d documents in %d partitions\n", res.SourceCount, len(res.Partitions))
+//
+// Because each partition is merged separately, a failed backfill can leave some partitions merged.
+// A pipeline whose merge is idempotent, such as the default "merge" whenMatched behavior, can be
+// run again to complete it.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultField is the default field by which a Backfill partitions the source collection.
+	DefaultField = "_id"
+
+	// DefaultPartitions is the default number of partitions of a Backfill.
+	DefaultPartitions = 16
+
+	// DefaultParallelism is the default number of partitions that a Backfill merges concurrently.
+	DefaultParallelism = 4
+)
+
+// ErrCountMismatch is wrapped by the error returned by Run if the partitions of a verified
+// Backfill do not match the documents of the source collection, e.g. because the partition field
+// has values of different BSON types or the collection was modified during the backfill.
+var ErrCountMismatch = errors.New("backfill partitions do not match the source collection")
+
+// Backfill runs an aggregation pipeline over a source collection in partitions and merges the
+// results into a target collection with a $merge stage.
+type Backfill struct {
+	pipeline       interface{}
+	target         *mongo.Collection
+	field          string
+	partitions     int
+	parallelism    int
+	on             []string
+	whenMatched    string
+	whenNotMatched string
+	verify         bool
+}
+
+// New creates a Backfill that runs pipeline over each partition of the source collection and
+// merges the results into target. The pipeline must not end with a $out or $merge stage. A nil
+// pipeline copies the source documents.
+func New(pipeline interface{}, target *mongo.Collection) *Backfill {
+	return &Backfill{
+		pipeline:    pipeline,
+		target:      target,
+		field:       DefaultField,
+		partitions:  DefaultPartitions,
+		parallelism: DefaultParallelism,
+		verify:      true,
+	}
+}
+
+// SetField sets the field by which the source collection is partitioned. The field should be
+// indexed and its values should all have the same BSON type, because range queries only match
+// values of the type of their bounds. The default value is DefaultField.
+func (b *Backfill) SetField(field string) *Backfill {
+	b.field = field
+	return b
+}
+
+// SetPartitions sets the number of partitions of the source collection. The server may create
+// fewer partitions if the field has fewer distinct values. The default value is
+// DefaultPartitions.
+func (b *Backfill) SetPartitions(n int) *Backfill {
+	b.partitions = n
+	return b
+}
+
+// SetParallelism sets the number of partitions that are merged concurrently. The default value is
+// DefaultParallelism.
+func (b *Backfill) SetParallelism(n int) *Backfill {
+	b.parallelism = n
+	return b
+}
+
+// SetOn sets the "on" fields of the $merge stage, which identify the target document of a result.
+// The default value is nil, in which case the server matches results by their _id.
+func (b *Backfill) SetOn(fields ...string) *Backfill {
+	b.on = fields
+	return b
+}
+
+// SetWhenMatched sets the "whenMatched" behavior of the $merge stage, e.g. "replace" or "fail".
+// The default value is "", in which case the server merges results into the matched documents.
+func (b *Backfill) SetWhenMatched(action string) *Backfill {
+	b.whenMatched = action
+	return b
+}
+
+// SetWhenNotMatched sets the "whenNotMatched" behavior of the $merge stage, e.g. "discard". The
+// default value is "", in which case the server inserts results that match no document.
+func (b *Backfill) SetWhenNotMatched(action string) *Backfill {
+	b.whenNotMatched = action
+	return b
+}
+
+// SetVerify sets whether Run verifies that the partitions match all documents of the source
+// collection. If set, each partition is counted before it is merged and Run returns an error
+// wrapping ErrCountMismatch if a partition does not match the number of documents reported by
+// $bucketAuto, or if the partitions do not add up to the documents of the collection. A partition
+// with a mismatched count is not merged. The default value is true.
+func (b *Backfill) SetVerify(verify bool) *Backfill {
+	b.verify = verify
+	return b
+}
+
+// Partition is a range of values of the partition field of a Backfill.
+type Partition struct {
+	// Min is the inclusive lower bound of the partition.
+	Min bson.RawValue
+
+	// Max is the upper bound of the partition. It is exclusive, except for the last partition.
+	Max bson.RawValue
+
+	// Count is the number of source documents in the partition.
+	Count int64
+}
+
+// Result is the result of a Backfill.
+type Result struct {
+	// Partitions are the partitions of the source collection, in the order of their ranges.
+	Partitions []Partition
+
+	// SourceCount is the number of documents in the source collection, which is the sum of the
+	// counts of the partitions.
+	SourceCount int64
+}
+
+// Run partitions source and merges the results of the pipeline of each partition into the target
+// collection. The returned Result describes the partitions, even if Run returns an error after the
+// source was partitioned. Run does not merge any more partitions after the first error.
+func (b *Backfill) Run(ctx context.Context, source *mongo.Collection) (*Result, error) {
+	if b.target == nil {
+		return nil, errors.New("backfill requires a target collection")
+	}
+	if b.field == "" {
+		return nil, errors.New("backfill requires a partition field")
+	}
+	if b.partitions < 1 {
+		return nil, fmt.Errorf("backfill partitions must be at least 1, got %d", b.partitions)
+	}
+	if b.parallelism < 1 {
+		return nil, fmt.Errorf("backfill parallelism must be at least 1, got %d", b.parallelism)
+	}
+	stages, err := marshalStages(b.pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if b.verify {
+		if total, err = source.CountDocuments(ctx, bson.D{}); err != nil {
+			return nil, fmt.Errorf("error counting source documents: %w", err)
+		}
+	}
+
+	res := &Result{}
+	if res.Partitions, err = b.partition(ctx, source); err != nil {
+		return nil, err
+	}
+	for _, p := range res.Partitions {
+		res.SourceCount += p.Count
+	}
+	if b.verify && res.SourceCount != total {
+		return res, fmt.Errorf("%w: partitions hold %d documents, the collection has %d",
+			ErrCountMismatch, res.SourceCount, total)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(b.parallelism)
+	for i := range res.Partitions {
+		i := i
+		g.Go(func() error {
+			return b.merge(gctx, source, stages, res.Partitions, i)
+		})
+	}
+	return res, g.Wait()
+}
+
+// partition returns the partitions of source computed by a $bucketAuto stage.
+func (b *Backfill) partition(ctx context.Context, source *mongo.Collection) ([]Partition, error) {
+	pipeline := bson.A{bson.D{{"$bucketAuto", bson.D{
+		{"groupBy", "$" + b.field},
+		{"buckets", b.partitions},
+	}}}}
+	cur, err := source.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, fmt.Errorf("error partitioning source collection: %w", err)
+	}
+
+	var buckets []struct {
+		ID struct {
+			Min bson.RawValue `bson:"min"`
+			Max bson.RawValue `bson:"max"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cur.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("error partitioning source collection: %w", err)
+	}
+
+	partitions := make([]Partition, 0, len(buckets))
+	for _, bucket := range buckets {
+		partitions = append(partitions, Partition{Min: bucket.ID.Min, Max: bucket.ID.Max, Count: bucket.Count})
+	}
+	return partitions, nil
+}
+
+// merge runs the pipeline over partitions[i] and merges its results into the target collection.
+func (b *Backfill) merge(
+	ctx context.Context,
+	source *mongo.Collection,
+	stages []bson.Raw,
+	partitions []Partition,
+	i int,
+) error {
+	p := partitions[i]
+	upper := "$lt"
+	if i == len(partitions)-1 {
+		upper = "$lte"
+	}
+	filter := bson.D{{b.field, bson.D{{"$gte", p.Min}, {upper, p.Max}}}}
+
+	if b.verify {
+		n, err := source.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("error counting partition %d: %w", i, err)
+		}
+		if n != p.Count {
+			return fmt.Errorf("%w: partition %d matches %d documents, expected %d", ErrCountMismatch, i, n, p.Count)
+		}
+	}
+
+	pipeline := make(bson.A, 0, len(stages)+2)
+	pipeline = append(pipeline, bson.D{{"$match", filter}})
+	for _, s := range stages {
+		pipeline = append(pipeline, s)
+	}
+	pipeline = append(pipeline, b.mergeStage())
+
+	cur, err := source.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return fmt.Errorf("error merging partition %d: %w", i, err)
+	}
+	return cur.Close(ctx)
+}
+
+// mergeStage returns the $merge stage of the partition pipelines.
+func (b *Backfill) mergeStage() bson.D {
+	spec := bson.D{{"into", bson.D{
+		{"db", b.target.Database().Name()},
+		{"coll", b.target.Name()},
+	}}}
+	if len(b.on) > 0 {
+		spec = append(spec, bson.E{"on", b.on})
+	}
+	if b.whenMatched != "" {
+		spec = append(spec, bson.E{"whenMatched", b.whenMatched})
+	}
+	if b.whenNotMatched != "" {
+		spec = append(spec, bson.E{"whenNotMatched", b.whenNotMatched})
+	}
+	return bson.D{{"$merge", spec}}
+}
+
+// marshalStages returns the stages of pipeline. A nil pipeline has no stages.
+func marshalStages(pipeline interface{}) ([]bson.Raw, error) {
+	if pipeline == nil {
+		return nil, nil
+	}
+	t, data, err := bson.MarshalValue(pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling pipeline: %w", err)
+	}
+	if t != bson.TypeArray {
+		return nil, fmt.Errorf("pipeline must be an array of stages, got BSON type %s", t)
+	}
+	values, err := bson.RawArray(data).Values()
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]bson.Raw, 0, len(values))
+	for i, val := range values {
+		doc, ok := val.DocumentOK()
+		if !ok {
+			return nil, fmt.Errorf("pipeline stage %d: expected a document, got BSON type %s", i, val.Type)
+		}
+		elem, err := doc.IndexErr(0)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline stage %d: %w", i, err)
+		}
+		if key := elem.Key(); key == "$out" || key == "$merge" {
+			return nil, fmt.Errorf("pipeline stage %d: %s is added by the backfill and cannot be used", i, key)
+		}
+		stages = append(stages, doc)
+	}
+	return stages, nil
+}
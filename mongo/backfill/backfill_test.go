@@ -0,0 +1,137 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package backfill
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+)
+
+func cursorReply(docs ...interface{}) mongotest.Reply {
+	return mongotest.Cursor("db.orders", 0, docs...)
+}
+
+func bucket(min, max int32, count int32) bson.D {
+	return bson.D{{"_id", bson.D{{"min", min}, {"max", max}}}, {"count", count}}
+}
+
+// newDatabase returns a Database of a Client that runs its operations against a deployment that
+// replies to aggregate commands with replies.
+func newDatabase(t *testing.T, replies ...mongotest.Reply) (*mongo.Database, *mongotest.Deployment) {
+	t.Helper()
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("aggregate", replies...)
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return client.Database("db"), d
+}
+
+func pipelineOf(t *testing.T, cmd bson.Raw) []bson.Raw {
+	t.Helper()
+
+	values, err := cmd.Lookup("pipeline").Array().Values()
+	require.NoError(t, err, "pipeline error")
+	stages := make([]bson.Raw, 0, len(values))
+	for _, v := range values {
+		stages = append(stages, v.Document())
+	}
+	return stages
+}
+
+func TestBackfill(t *testing.T) {
+	t.Parallel()
+
+	pipeline := mongo.Pipeline{{{"$set", bson.D{{"migrated", true}}}}}
+
+	t.Run("merges partitions", func(t *testing.T) {
+		t.Parallel()
+
+		db, d := newDatabase(t,
+			cursorReply(bson.D{{"n", int32(5)}}),
+			cursorReply(bucket(1, 3, 2), bucket(3, 9, 3)),
+			cursorReply(bson.D{{"n", int32(2)}}),
+			cursorReply(),
+			cursorReply(bson.D{{"n", int32(3)}}),
+			cursorReply(),
+		)
+		res, err := New(pipeline, db.Collection("orders_v2")).
+			SetField("orderId").
+			SetPartitions(2).
+			SetParallelism(1).
+			SetWhenMatched("replace").
+			Run(context.Background(), db.Collection("orders"))
+		require.NoError(t, err, "Run error")
+
+		require.Len(t, res.Partitions, 2, "expected a partition per bucket")
+		assert.Equal(t, int64(5), res.SourceCount, "source count mismatch")
+		assert.Equal(t, int64(3), res.Partitions[1].Count, "partition count mismatch")
+		assert.Equal(t, int32(3), res.Partitions[1].Min.Int32(), "partition min mismatch")
+
+		cmds := d.Commands("aggregate")
+		require.Len(t, cmds, 6, "expected a count and a merge per partition")
+		bucketAuto := pipelineOf(t, cmds[1].Document)[0].Lookup("$bucketAuto")
+		assert.Equal(t, "$orderId", bucketAuto.Document().Lookup("groupBy").StringValue(), "groupBy mismatch")
+		assert.Equal(t, int32(2), bucketAuto.Document().Lookup("buckets").Int32(), "buckets mismatch")
+
+		stages := pipelineOf(t, cmds[3].Document)
+		require.Len(t, stages, 3, "expected $match, the pipeline and $merge")
+		assert.Equal(t, `{"$match": {"orderId": {"$gte": {"$numberInt":"1"},"$lt": {"$numberInt":"3"}}}}`,
+			stages[0].String(), "first partition filter mismatch")
+		assert.Equal(t, "$set", stages[1].Index(0).Key(), "expected the pipeline after the filter")
+		assert.Equal(t, `{"$merge": {"into": {"db": "db","coll": "orders_v2"},"whenMatched": "replace"}}`,
+			stages[2].String(), "merge stage mismatch")
+
+		last := pipelineOf(t, cmds[5].Document)[0]
+		assert.Equal(t, `{"$match": {"orderId": {"$gte": {"$numberInt":"3"},"$lte": {"$numberInt":"9"}}}}`,
+			last.String(), "expected the last partition to include its max")
+	})
+
+	t.Run("count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		db, d := newDatabase(t,
+			cursorReply(bson.D{{"n", int32(5)}}),
+			cursorReply(bucket(1, 9, 5)),
+			cursorReply(bson.D{{"n", int32(4)}}),
+		)
+		res, err := New(pipeline, db.Collection("orders_v2")).Run(context.Background(), db.Collection("orders"))
+		assert.ErrorIs(t, err, ErrCountMismatch, "expected ErrCountMismatch")
+		require.NotNil(t, res, "expected the partitions")
+		assert.Len(t, d.Commands("aggregate"), 3, "expected the mismatched partition not to be merged")
+
+		db, _ = newDatabase(t,
+			cursorReply(bson.D{{"n", int32(6)}}),
+			cursorReply(bucket(1, 9, 5)),
+		)
+		_, err = New(pipeline, db.Collection("orders_v2")).Run(context.Background(), db.Collection("orders"))
+		assert.ErrorIs(t, err, ErrCountMismatch, "expected ErrCountMismatch for a missing document")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		db, _ := newDatabase(t)
+		source := db.Collection("orders")
+		_, err := New(mongo.Pipeline{{{"$out", "x"}}}, db.Collection("x")).Run(context.Background(), source)
+		assert.ErrorContains(t, err, "$out", "expected an error for a pipeline with $out")
+		_, err = New(bson.D{{"$match", bson.D{}}}, db.Collection("x")).Run(context.Background(), source)
+		assert.ErrorContains(t, err, "array of stages", "expected an error for a pipeline document")
+		_, err = New(nil, nil).Run(context.Background(), source)
+		assert.ErrorContains(t, err, "target", "expected an error without a target")
+		_, err = New(nil, db.Collection("x")).SetParallelism(0).Run(context.Background(), source)
+		assert.ErrorContains(t, err, "parallelism", "expected an error without parallelism")
+	})
+}
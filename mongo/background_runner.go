@@ -0,0 +1,292 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+const (
+	defaultBackgroundQueueSize     = 1000
+	defaultBackgroundPauseInterval = time.Second
+)
+
+// ErrBackgroundRunnerClosed is returned by BackgroundRunner.Submit if the runner has been closed.
+var ErrBackgroundRunnerClosed = errors.New("background runner is closed")
+
+// BackgroundOperation is an operation executed by a BackgroundRunner, such as an index build or a
+// mass update. The Context passed to the operation is cancelled if the runner is closed before the
+// operation completes.
+type BackgroundOperation func(ctx context.Context) error
+
+// BackgroundRunner executes queued maintenance operations at a limited rate and concurrency. Before
+// starting each operation, the runner checks the heartbeat round trip time of the servers of the
+// Client and pauses while it exceeds the configured threshold. Operations run by a BackgroundRunner check out connections
+// with CheckoutPriorityBackground so they do not delay foreground operations waiting on an
+// exhausted connection pool.
+//
+// A BackgroundRunner is safe for concurrent use by multiple goroutines.
+type BackgroundRunner struct {
+	queue         chan BackgroundOperation
+	interval      time.Duration
+	threshold     time.Duration
+	rtt           func() time.Duration
+	pauseInterval time.Duration
+	errorHandler  func(error)
+
+	limiterMu sync.Mutex
+	next      time.Time
+
+	paused int32
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stop       chan struct{}
+	unregister func()
+
+	mu        sync.RWMutex // guards closed and calls to pending.Add
+	closed    bool
+	closeOnce sync.Once
+	pending   sync.WaitGroup
+	workers   sync.WaitGroup
+}
+
+// NewBackgroundRunner creates a BackgroundRunner that executes operations for the given Client and
+// starts its worker goroutines. The returned runner must be closed with Close when it is no longer
+// needed. The runner is registered with the Client, so Client.Disconnect closes it if it is still
+// open.
+func NewBackgroundRunner(client *Client, opts ...options.Lister[options.BackgroundRunnerOptions]) (*BackgroundRunner, error) {
+	args, err := mongoutil.NewOptions[options.BackgroundRunnerOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	concurrency := 1
+	if args.Concurrency != nil {
+		concurrency = *args.Concurrency
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("background runner concurrency must be at least 1, got %d", concurrency)
+	}
+
+	queueSize := defaultBackgroundQueueSize
+	if args.QueueSize != nil {
+		queueSize = *args.QueueSize
+	}
+	if queueSize < 0 {
+		return nil, fmt.Errorf("background runner queue size must not be negative, got %d", queueSize)
+	}
+
+	r := &BackgroundRunner{
+		queue:         make(chan BackgroundOperation, queueSize),
+		rtt:           client.maxServerRTT,
+		pauseInterval: defaultBackgroundPauseInterval,
+		errorHandler:  args.ErrorHandler,
+		stop:          make(chan struct{}),
+	}
+
+	if args.OpsPerSecond != nil {
+		ops := *args.OpsPerSecond
+		if ops < 0 {
+			return nil, fmt.Errorf("background runner ops per second must not be negative, got %v", ops)
+		}
+		if ops > 0 {
+			r.interval = time.Duration(float64(time.Second) / ops)
+		}
+	}
+	if args.RTTThreshold != nil {
+		r.threshold = *args.RTTThreshold
+	}
+	if args.RTTFunc != nil {
+		r.rtt = args.RTTFunc
+	}
+	if args.PauseInterval != nil && *args.PauseInterval > 0 {
+		r.pauseInterval = *args.PauseInterval
+	}
+
+	r.ctx, r.cancel = context.WithCancel(WithCheckoutPriority(context.Background(), CheckoutPriorityBackground))
+
+	r.workers.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go r.work()
+	}
+	r.unregister = client.Register(r)
+
+	return r, nil
+}
+
+// Submit queues op for execution. It blocks if the queue is full until there is space, ctx expires,
+// or the runner is closed.
+func (r *BackgroundRunner) Submit(ctx context.Context, op BackgroundOperation) error {
+	if op == nil {
+		return errors.New("background operation must not be nil")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return ErrBackgroundRunnerClosed
+	}
+	r.pending.Add(1)
+	r.mu.RUnlock()
+
+	select {
+	case r.queue <- op:
+		return nil
+	case <-ctx.Done():
+		r.pending.Done()
+		return ctx.Err()
+	case <-r.ctx.Done():
+		r.pending.Done()
+		return ErrBackgroundRunnerClosed
+	}
+}
+
+// Paused reports whether the runner is currently paused because the round trip time exceeds the
+// configured threshold.
+func (r *BackgroundRunner) Paused() bool {
+	return atomic.LoadInt32(&r.paused) == 1
+}
+
+// Close stops accepting new operations and waits for all queued and running operations to complete.
+// If ctx expires first, the Contexts of the remaining operations are cancelled, Close waits for them
+// to return, and then returns the error from ctx.
+func (r *BackgroundRunner) Close(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var err error
+	r.closeOnce.Do(func() {
+		r.unregister()
+
+		r.mu.Lock()
+		r.closed = true
+		r.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			r.pending.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+			r.cancel()
+			<-done
+		}
+
+		close(r.stop)
+		r.workers.Wait()
+		r.cancel()
+	})
+	return err
+}
+
+func (r *BackgroundRunner) work() {
+	defer r.workers.Done()
+
+	for {
+		select {
+		case op := <-r.queue:
+			r.run(op)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *BackgroundRunner) run(op BackgroundOperation) {
+	defer r.pending.Done()
+
+	err := r.waitUntilReady()
+	if err == nil {
+		err = op(r.ctx)
+	}
+	if err != nil && r.errorHandler != nil {
+		r.errorHandler(err)
+	}
+}
+
+// waitUntilReady blocks while the runner is paused and until the rate limit allows another
+// operation to start. It returns an error if the runner is closed while waiting.
+func (r *BackgroundRunner) waitUntilReady() error {
+	for r.threshold > 0 && r.rtt() > r.threshold {
+		atomic.StoreInt32(&r.paused, 1)
+		if err := r.sleep(r.pauseInterval); err != nil {
+			return err
+		}
+	}
+	atomic.StoreInt32(&r.paused, 0)
+
+	return r.sleep(r.reserve())
+}
+
+// reserve reserves the next slot allowed by the rate limit and returns how long to wait for it.
+func (r *BackgroundRunner) reserve() time.Duration {
+	if r.interval == 0 {
+		return 0
+	}
+
+	r.limiterMu.Lock()
+	defer r.limiterMu.Unlock()
+
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	return wait
+}
+
+func (r *BackgroundRunner) sleep(d time.Duration) error {
+	if d <= 0 {
+		return r.ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// maxServerRTT returns the highest average heartbeat round trip time of the servers known to the
+// Client, or 0 if the Client is not connected to a topology.
+func (c *Client) maxServerRTT() time.Duration {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return 0
+	}
+
+	var maxRTT time.Duration
+	for _, s := range topo.Description().Servers {
+		if s.AverageRTTSet && s.AverageRTT > maxRTT {
+			maxRTT = s.AverageRTT
+		}
+	}
+	return maxRTT
+}
@@ -0,0 +1,134 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+func TestBackgroundRunner(t *testing.T) {
+	client, err := newClient()
+	require.NoError(t, err, "newClient error: %v", err)
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewBackgroundRunner(client, options.BackgroundRunner().SetConcurrency(0))
+		assert.Error(t, err, "expected error for concurrency 0")
+
+		_, err = NewBackgroundRunner(client, options.BackgroundRunner().SetOpsPerSecond(-1))
+		assert.Error(t, err, "expected error for negative ops per second")
+	})
+	t.Run("runs operations and reports errors", func(t *testing.T) {
+		var errs []error
+		var mu sync.Mutex
+		r, err := NewBackgroundRunner(client, options.BackgroundRunner().
+			SetConcurrency(2).
+			SetErrorHandler(func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}))
+		require.NoError(t, err, "NewBackgroundRunner error: %v", err)
+
+		opErr := errors.New("op failed")
+		var ran int32
+		for i := 0; i < 10; i++ {
+			i := i
+			err := r.Submit(context.Background(), func(ctx context.Context) error {
+				atomic.AddInt32(&ran, 1)
+				assert.Equal(t, driver.CheckoutPriorityBackground, driver.CheckoutPriorityFromContext(ctx),
+					"expected background checkout priority")
+				if i == 0 {
+					return opErr
+				}
+				return nil
+			})
+			require.NoError(t, err, "Submit error: %v", err)
+		}
+
+		err = r.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+		assert.Equal(t, int32(10), atomic.LoadInt32(&ran), "expected all operations to run")
+		assert.Equal(t, []error{opErr}, errs, "expected operation error to be reported")
+
+		err = r.Submit(context.Background(), func(context.Context) error { return nil })
+		assert.ErrorIs(t, err, ErrBackgroundRunnerClosed, "expected ErrBackgroundRunnerClosed")
+	})
+	t.Run("limits rate", func(t *testing.T) {
+		r, err := NewBackgroundRunner(client, options.BackgroundRunner().
+			SetConcurrency(4).
+			SetOpsPerSecond(100))
+		require.NoError(t, err, "NewBackgroundRunner error: %v", err)
+
+		start := time.Now()
+		for i := 0; i < 6; i++ {
+			err := r.Submit(context.Background(), func(context.Context) error { return nil })
+			require.NoError(t, err, "Submit error: %v", err)
+		}
+		err = r.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+
+		// Six operations at 100 ops/sec must take at least 50ms.
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "expected operations to be rate limited")
+	})
+	t.Run("pauses while the round trip time is high", func(t *testing.T) {
+		var rtt int64 = int64(time.Second)
+		r, err := NewBackgroundRunner(client, options.BackgroundRunner().
+			SetRTTThreshold(100*time.Millisecond).
+			SetRTTFunc(func() time.Duration { return time.Duration(atomic.LoadInt64(&rtt)) }).
+			SetPauseInterval(time.Millisecond))
+		require.NoError(t, err, "NewBackgroundRunner error: %v", err)
+
+		done := make(chan struct{})
+		err = r.Submit(context.Background(), func(context.Context) error {
+			close(done)
+			return nil
+		})
+		require.NoError(t, err, "Submit error: %v", err)
+
+		assert.Eventually(t, r.Paused, time.Second, time.Millisecond, "expected runner to pause")
+		select {
+		case <-done:
+			t.Fatal("expected operation not to run while paused")
+		default:
+		}
+
+		atomic.StoreInt64(&rtt, 0)
+		<-done
+		assert.False(t, r.Paused(), "expected runner to resume")
+
+		err = r.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+	})
+	t.Run("close cancels operations when context expires", func(t *testing.T) {
+		r, err := NewBackgroundRunner(client)
+		require.NoError(t, err, "NewBackgroundRunner error: %v", err)
+
+		started := make(chan struct{})
+		err = r.Submit(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, err, "Submit error: %v", err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err = r.Close(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "expected context.DeadlineExceeded")
+	})
+}
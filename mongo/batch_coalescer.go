@@ -0,0 +1,112 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// batchCoalescer combines the batches of consecutive getMore commands of a tailable await cursor
+// as configured by options.BatchCoalescing.
+type batchCoalescer struct {
+	minDocuments int
+	window       time.Duration
+
+	// maxAwaitTime is the maximum await time set for the cursor, which is restored after the
+	// getMores of a combined batch.
+	maxAwaitTime *time.Duration
+
+	// err is the error of a getMore that ended a combined batch. It is reported once the combined
+	// batch has been consumed so that its documents are not lost.
+	err error
+}
+
+// newBatchCoalescer returns a batchCoalescer, or nil if opts does not require batches to be
+// combined.
+func newBatchCoalescer(opts *options.BatchCoalescing, maxAwaitTime *time.Duration) *batchCoalescer {
+	if opts == nil || opts.MinDocuments <= 1 || opts.Window <= 0 {
+		return nil
+	}
+	return &batchCoalescer{
+		minDocuments: opts.MinDocuments,
+		window:       opts.Window,
+		maxAwaitTime: maxAwaitTime,
+	}
+}
+
+// setMaxAwaitTime records the maximum await time set for the cursor.
+func (bco *batchCoalescer) setMaxAwaitTime(d time.Duration) {
+	if bco != nil {
+		bco.maxAwaitTime = &d
+	}
+}
+
+// takeErr returns and clears the error of the getMore that ended the last combined batch.
+func (bco *batchCoalescer) takeErr() error {
+	if bco == nil {
+		return nil
+	}
+	err := bco.err
+	bco.err = nil
+	return err
+}
+
+// coalesce returns batch, the current batch of bc, combined with the batches of the following
+// getMores of bc until the result contains bco.minDocuments documents, bco.window has elapsed, or
+// bc is exhausted or fails. It returns batch as is if bco is nil or batch is large enough.
+func (bco *batchCoalescer) coalesce(ctx context.Context, bc batchCursor, batch *bsoncore.Iterator) *bsoncore.Iterator {
+	if bco == nil || bc.ID() == 0 || batch.Count() >= bco.minDocuments {
+		return batch
+	}
+
+	idx, arr := bsoncore.AppendArrayStart(nil)
+	var n int
+	appendBatch := func(batch *bsoncore.Iterator) {
+		docs, _ := batch.Documents()
+		for _, doc := range docs {
+			arr = bsoncore.AppendDocumentElement(arr, strconv.Itoa(n), doc)
+			n++
+		}
+	}
+	appendBatch(batch)
+
+	defer func() {
+		if bco.maxAwaitTime != nil {
+			bc.SetMaxAwaitTime(*bco.maxAwaitTime)
+		} else {
+			bc.SetMaxAwaitTime(0)
+		}
+	}()
+
+	deadline := time.Now().Add(bco.window)
+	for n < bco.minDocuments && bc.ID() != 0 {
+		await := time.Until(deadline)
+		if await < time.Millisecond {
+			break
+		}
+		if bco.maxAwaitTime != nil && *bco.maxAwaitTime < await {
+			await = *bco.maxAwaitTime
+		}
+		bc.SetMaxAwaitTime(await)
+
+		if !bc.Next(ctx) {
+			if bco.err = bc.Err(); bco.err != nil {
+				break
+			}
+			continue
+		}
+		appendBatch(bc.Batch())
+	}
+
+	arr, _ = bsoncore.AppendArrayEnd(arr, idx)
+	return &bsoncore.Iterator{List: arr}
+}
@@ -0,0 +1,195 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// scriptedBatchCursor returns the given batches of documents with an int32 "n" field, one per call
+// to Next. An empty batch is returned like a getMore that timed out without documents. Once the
+// batches are consumed, Next fails with err if it is set, and the cursor is exhausted otherwise.
+type scriptedBatchCursor struct {
+	batches [][]int32
+	err     error
+	delay   time.Duration // of each call to Next, like the await of a getMore
+
+	batch  *bsoncore.Iterator
+	id     int64
+	curErr error
+	awaits []time.Duration
+}
+
+func newScriptedBatchCursor(err error, batches ...[]int32) *scriptedBatchCursor {
+	return &scriptedBatchCursor{batches: batches, err: err, id: 1}
+}
+
+func (sc *scriptedBatchCursor) ID() int64 { return sc.id }
+
+func (sc *scriptedBatchCursor) Next(context.Context) bool {
+	time.Sleep(sc.delay)
+	sc.batch = nil
+	if len(sc.batches) == 0 {
+		if sc.err != nil {
+			sc.curErr = sc.err
+		} else {
+			sc.id = 0
+		}
+		return false
+	}
+
+	idx, arr := bsoncore.AppendArrayStart(nil)
+	for i, n := range sc.batches[0] {
+		doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "n", n))
+		arr = bsoncore.AppendDocumentElement(arr, strconv.Itoa(i), doc)
+	}
+	arr, _ = bsoncore.AppendArrayEnd(arr, idx)
+	sc.batch = &bsoncore.Iterator{List: arr}
+	sc.batches = sc.batches[1:]
+	return !sc.batch.Empty()
+}
+
+func (sc *scriptedBatchCursor) Batch() *bsoncore.Iterator       { return sc.batch }
+func (sc *scriptedBatchCursor) Server() driver.Server           { return nil }
+func (sc *scriptedBatchCursor) Err() error                      { return sc.curErr }
+func (sc *scriptedBatchCursor) Close(context.Context) error     { return nil }
+func (sc *scriptedBatchCursor) SetBatchSize(int32)              {}
+func (sc *scriptedBatchCursor) SetComment(interface{})          {}
+func (sc *scriptedBatchCursor) SetMaxAwaitTime(d time.Duration) { sc.awaits = append(sc.awaits, d) }
+
+func batchValues(t *testing.T, batch *bsoncore.Iterator) []int32 {
+	t.Helper()
+
+	docs, err := batch.Documents()
+	require.NoError(t, err, "Documents error")
+	values := make([]int32, 0, len(docs))
+	for _, doc := range docs {
+		values = append(values, doc.Lookup("n").Int32())
+	}
+	return values
+}
+
+func TestBatchCoalescer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, newBatchCoalescer(nil, nil), "expected no coalescer without options")
+		assert.Nil(t, newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 1, Window: time.Second}, nil),
+			"expected no coalescer for a single document")
+		assert.Nil(t, newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 10}, nil),
+			"expected no coalescer without window")
+	})
+
+	t.Run("combines batches until MinDocuments", func(t *testing.T) {
+		t.Parallel()
+
+		bco := newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 3, Window: time.Minute}, nil)
+		bc := newScriptedBatchCursor(nil, []int32{1}, nil, []int32{2, 3}, []int32{4})
+		require.True(t, bc.Next(context.Background()), "expected first batch")
+
+		batch := bco.coalesce(context.Background(), bc, bc.Batch())
+		assert.Equal(t, []int32{1, 2, 3}, batchValues(t, batch), "batch mismatch")
+		assert.Len(t, bc.batches, 1, "expected the last batch to not be fetched")
+		require.Len(t, bc.awaits, 3, "expected two getMores and a reset of the max await time")
+		for _, await := range bc.awaits[:2] {
+			assert.True(t, await > 0 && await <= time.Minute, "expected await time within the window, got %v", await)
+		}
+		assert.Equal(t, time.Duration(0), bc.awaits[2], "expected the max await time to be reset")
+	})
+
+	t.Run("returns large batch as is", func(t *testing.T) {
+		t.Parallel()
+
+		bco := newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 2, Window: time.Minute}, nil)
+		bc := newScriptedBatchCursor(nil, []int32{1, 2}, []int32{3})
+		require.True(t, bc.Next(context.Background()), "expected first batch")
+
+		first := bc.Batch()
+		assert.Equal(t, first, bco.coalesce(context.Background(), bc, first), "expected the batch to be returned as is")
+		assert.Len(t, bc.awaits, 0, "expected no getMore")
+	})
+
+	t.Run("window elapses", func(t *testing.T) {
+		t.Parallel()
+
+		bco := newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 3, Window: 20 * time.Millisecond}, nil)
+		batches := [][]int32{{1}}
+		for i := 0; i < 100; i++ {
+			batches = append(batches, nil)
+		}
+		bc := newScriptedBatchCursor(nil, batches...)
+		require.True(t, bc.Next(context.Background()), "expected first batch")
+		bc.delay = 5 * time.Millisecond
+
+		start := time.Now()
+		batch := bco.coalesce(context.Background(), bc, bc.Batch())
+		assert.Equal(t, []int32{1}, batchValues(t, batch), "batch mismatch")
+		assert.True(t, time.Since(start) >= 19*time.Millisecond, "expected coalescing to wait for the window")
+		assert.True(t, len(bc.batches) > 0, "expected coalescing to stop once the window elapsed")
+	})
+
+	t.Run("max await time caps await", func(t *testing.T) {
+		t.Parallel()
+
+		maxAwait := 5 * time.Millisecond
+		bco := newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 3, Window: time.Minute}, &maxAwait)
+		bc := newScriptedBatchCursor(nil, []int32{1}, []int32{2}, []int32{3})
+		require.True(t, bc.Next(context.Background()), "expected first batch")
+
+		bco.coalesce(context.Background(), bc, bc.Batch())
+		assert.Equal(t, []time.Duration{maxAwait, maxAwait, maxAwait}, bc.awaits, "await times mismatch")
+
+		bco.setMaxAwaitTime(time.Millisecond)
+		bc = newScriptedBatchCursor(nil, []int32{1}, []int32{2, 3})
+		require.True(t, bc.Next(context.Background()), "expected first batch")
+		bco.coalesce(context.Background(), bc, bc.Batch())
+		assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, bc.awaits, "expected the updated max await time")
+	})
+
+	t.Run("error is reported after the combined batch", func(t *testing.T) {
+		t.Parallel()
+
+		getMoreErr := errors.New("getMore failed")
+		bc := newScriptedBatchCursor(getMoreErr, []int32{1}, []int32{2})
+		cursor, err := newCursor(bc, nil, nil)
+		require.NoError(t, err, "newCursor error")
+		cursor.coalesce = newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 5, Window: time.Minute}, nil)
+
+		var values []int32
+		for cursor.Next(context.Background()) {
+			values = append(values, cursor.Current.Lookup("n").Int32())
+		}
+		assert.Equal(t, []int32{1, 2}, values, "expected the documents received before the error")
+		assert.Equal(t, getMoreErr, cursor.Err(), "expected the getMore error")
+	})
+
+	t.Run("TryNext runs a single getMore", func(t *testing.T) {
+		t.Parallel()
+
+		bc := newScriptedBatchCursor(nil, []int32{1}, []int32{2}, []int32{3})
+		cursor, err := newCursor(bc, nil, nil)
+		require.NoError(t, err, "newCursor error")
+		cursor.coalesce = newBatchCoalescer(&options.BatchCoalescing{MinDocuments: 5, Window: time.Minute}, nil)
+
+		require.True(t, cursor.TryNext(context.Background()), "expected a document")
+		assert.Equal(t, int32(1), cursor.Current.Lookup("n").Int32(), "document mismatch")
+		assert.Len(t, bc.batches, 2, "expected a single getMore")
+		assert.Len(t, bc.awaits, 0, "expected batches to not be combined")
+	})
+}
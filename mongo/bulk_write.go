@@ -178,6 +178,14 @@ func (bw *bulkWrite) runInsert(ctx context.Context, batch bulkWriteBatch) (opera
 		if err != nil {
 			return operation.InsertResult{}, err
 		}
+		doc, err = bw.collection.etag.stamp(doc)
+		if err != nil {
+			return operation.InsertResult{}, err
+		}
+		doc, err = bw.collection.transforms.apply(doc, bw.collection.bsonOpts, bw.collection.registry)
+		if err != nil {
+			return operation.InsertResult{}, err
+		}
 
 		docs[i] = doc
 		i++
@@ -188,6 +196,7 @@ func (bw *bulkWrite) runInsert(ctx context.Context, batch bulkWriteBatch) (opera
 		ServerSelector(bw.selector).ClusterClock(bw.collection.client.clock).
 		Database(bw.collection.db.name).Collection(bw.collection.name).
 		Deployment(bw.collection.client.deployment).Crypt(bw.collection.client.cryptFLE).
+		CommandCache(bw.collection.commandCache).
 		ServerAPI(bw.collection.client.serverAPI).Timeout(bw.collection.client.timeout).
 		Logger(bw.collection.client.logger).Authenticator(bw.collection.client.authenticator)
 	if bw.comment != nil {
@@ -205,7 +214,7 @@ func (bw *bulkWrite) runInsert(ctx context.Context, batch bulkWriteBatch) (opera
 	}
 
 	retry := driver.RetryNone
-	if bw.collection.client.retryWrites && batch.canRetry {
+	if bw.collection.client.retryWritesEnabled() && batch.canRetry {
 		retry = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retry)
@@ -278,7 +287,7 @@ func (bw *bulkWrite) runDelete(ctx context.Context, batch bulkWriteBatch) (opera
 		op = op.Ordered(*bw.ordered)
 	}
 	retry := driver.RetryNone
-	if bw.collection.client.retryWrites && batch.canRetry {
+	if bw.collection.client.retryWritesEnabled() && batch.canRetry {
 		retry = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retry)
@@ -330,6 +339,7 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 	docs := make([]bsoncore.Document, len(batch.models))
 	var hasHint bool
 	var hasArrayFilters bool
+	var hasPipeline bool
 	for i, model := range batch.models {
 		var doc bsoncore.Document
 		var err error
@@ -337,11 +347,13 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 		switch converted := model.(type) {
 		case *ReplaceOneModel:
 			doc, err = updateDoc{
-				filter:    converted.Filter,
-				update:    converted.Replacement,
-				hint:      converted.Hint,
-				collation: converted.Collation,
-				upsert:    converted.Upsert,
+				filter:     converted.Filter,
+				update:     converted.Replacement,
+				hint:       converted.Hint,
+				collation:  converted.Collation,
+				upsert:     converted.Upsert,
+				etag:       bw.collection.etag,
+				transforms: bw.collection.transforms,
 			}.marshal(bw.collection.bsonOpts, bw.collection.registry)
 			hasHint = hasHint || (converted.Hint != nil)
 		case *UpdateOneModel:
@@ -353,6 +365,7 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 				collation:      converted.Collation,
 				upsert:         converted.Upsert,
 				checkDollarKey: true,
+				transforms:     bw.collection.transforms,
 			}.marshal(bw.collection.bsonOpts, bw.collection.registry)
 			hasHint = hasHint || (converted.Hint != nil)
 			hasArrayFilters = hasArrayFilters || (converted.ArrayFilters != nil)
@@ -366,6 +379,7 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 				upsert:         converted.Upsert,
 				multi:          true,
 				checkDollarKey: true,
+				transforms:     bw.collection.transforms,
 			}.marshal(bw.collection.bsonOpts, bw.collection.registry)
 			hasHint = hasHint || (converted.Hint != nil)
 			hasArrayFilters = hasArrayFilters || (converted.ArrayFilters != nil)
@@ -373,6 +387,7 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 		if err != nil {
 			return operation.UpdateResult{}, err
 		}
+		hasPipeline = hasPipeline || doc.Lookup("u").Type == bsoncore.TypeArray
 
 		docs[i] = doc
 	}
@@ -382,7 +397,8 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 		ServerSelector(bw.selector).ClusterClock(bw.collection.client.clock).
 		Database(bw.collection.db.name).Collection(bw.collection.name).
 		Deployment(bw.collection.client.deployment).Crypt(bw.collection.client.cryptFLE).Hint(hasHint).
-		ArrayFilters(hasArrayFilters).ServerAPI(bw.collection.client.serverAPI).
+		ArrayFilters(hasArrayFilters).Pipeline(hasPipeline).CommandCache(bw.collection.commandCache).
+		ServerAPI(bw.collection.client.serverAPI).
 		Timeout(bw.collection.client.timeout).Logger(bw.collection.client.logger).
 		Authenticator(bw.collection.client.authenticator)
 	if bw.comment != nil {
@@ -406,7 +422,7 @@ func (bw *bulkWrite) runUpdate(ctx context.Context, batch bulkWriteBatch) (opera
 		op = op.BypassDocumentValidation(*bw.bypassDocumentValidation)
 	}
 	retry := driver.RetryNone
-	if bw.collection.client.retryWrites && batch.canRetry {
+	if bw.collection.client.retryWritesEnabled() && batch.canRetry {
 		retry = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retry)
@@ -425,6 +441,8 @@ type updateDoc struct {
 	upsert         *bool
 	multi          bool
 	checkDollarKey bool
+	etag           *etagConfig        // stamps the replacement document, if set
+	transforms     *fieldTransformers // derives the fields of the update, if set
 }
 
 func (doc updateDoc) marshal(bsonOpts *options.BSONOptions, registry *bson.Registry) (bsoncore.Document, error) {
@@ -440,6 +458,21 @@ func (doc updateDoc) marshal(bsonOpts *options.BSONOptions, registry *bson.Regis
 	if err != nil {
 		return nil, err
 	}
+	if doc.checkDollarKey && u.Type == bsoncore.TypeArray {
+		if err := validateUpdatePipeline(u.Data, doc.arrayFilters != nil); err != nil {
+			return nil, err
+		}
+	}
+	if doc.etag != nil && u.Type == bsoncore.TypeEmbeddedDocument {
+		stamped, err := doc.etag.stamp(u.Data)
+		if err != nil {
+			return nil, err
+		}
+		u.Data = stamped
+	}
+	if u, err = doc.transforms.applyUpdate(u, bsonOpts, registry); err != nil {
+		return nil, err
+	}
 
 	updateDoc = bsoncore.AppendValueElement(updateDoc, "u", u)
 
@@ -210,14 +210,16 @@ func (uom *UpdateOneModel) SetFilter(filter interface{}) *UpdateOneModel {
 }
 
 // SetUpdate specifies the modifications to be made to the selected document. The value must be a document containing
-// update operators (https://www.mongodb.com/docs/manual/reference/operator/update/). It cannot be nil or empty.
+// update operators (https://www.mongodb.com/docs/manual/reference/operator/update/) or an aggregation pipeline of
+// $addFields, $set, $project, $unset, $replaceRoot, and $replaceWith stages, which requires MongoDB 4.2 or later. It
+// cannot be nil or empty.
 func (uom *UpdateOneModel) SetUpdate(update interface{}) *UpdateOneModel {
 	uom.Update = update
 	return uom
 }
 
 // SetArrayFilters specifies a set of filters to determine which elements should be modified when updating an array
-// field.
+// field. It cannot be used with an aggregation pipeline update.
 func (uom *UpdateOneModel) SetArrayFilters(filters []interface{}) *UpdateOneModel {
 	uom.ArrayFilters = filters
 	return uom
@@ -274,14 +276,16 @@ func (umm *UpdateManyModel) SetFilter(filter interface{}) *UpdateManyModel {
 }
 
 // SetUpdate specifies the modifications to be made to the selected documents. The value must be a document containing
-// update operators (https://www.mongodb.com/docs/manual/reference/operator/update/). It cannot be nil or empty.
+// update operators (https://www.mongodb.com/docs/manual/reference/operator/update/) or an aggregation pipeline of
+// $addFields, $set, $project, $unset, $replaceRoot, and $replaceWith stages, which requires MongoDB 4.2 or later. It
+// cannot be nil or empty.
 func (umm *UpdateManyModel) SetUpdate(update interface{}) *UpdateManyModel {
 	umm.Update = update
 	return umm
 }
 
 // SetArrayFilters specifies a set of filters to determine which elements should be modified when updating an array
-// field.
+// field. It cannot be used with an aggregation pipeline update.
 func (umm *UpdateManyModel) SetArrayFilters(filters []interface{}) *UpdateManyModel {
 	umm.ArrayFilters = filters
 	return umm
@@ -0,0 +1,93 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+)
+
+// memoryCausalTimeStore is a session.CausalTimeStore that keeps the times in memory.
+type memoryCausalTimeStore struct {
+	mu    sync.Mutex
+	times session.CausalTimes
+	saves int
+}
+
+func (s *memoryCausalTimeStore) Load(context.Context) (session.CausalTimes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.times, nil
+}
+
+func (s *memoryCausalTimeStore) Save(_ context.Context, times session.CausalTimes) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.times = times
+	s.saves++
+	return nil
+}
+
+// causalTimeStoreDeployment is a MockDeployment that shares causal times through a store.
+type causalTimeStoreDeployment struct {
+	*drivertest.MockDeployment
+	store session.CausalTimeStore
+}
+
+func (d causalTimeStoreDeployment) CausalTimeStore() session.CausalTimeStore { return d.store }
+
+func TestCausalTimeStore(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryCausalTimeStore{}
+	md := drivertest.NewMockDeployment()
+	var started []bson.Raw
+	clientOpts := options.Client().SetMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			started = append(started, evt.Command)
+		},
+	})
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = causalTimeStoreDeployment{MockDeployment: md, store: store}
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+	coll := client.Database("db").Collection("coll")
+
+	// The operation time of a write made by another replica of the application.
+	store.times = session.CausalTimes{OperationTime: &bson.Timestamp{T: 100, I: 1}}
+
+	md.AddResponses(bson.D{
+		{"ok", 1},
+		{"cursor", bson.D{{"id", int64(0)}, {"ns", "db.coll"}, {"firstBatch", bson.A{}}}},
+		{"operationTime", bson.Timestamp{T: 100, I: 5}},
+	})
+	cursor, err := coll.Find(context.Background(), bson.D{})
+	require.NoError(t, err, "Find error")
+	require.NoError(t, cursor.Close(context.Background()), "Close error")
+
+	require.Len(t, started, 1, "expected a single command")
+	afterClusterTime, err := started[0].LookupErr("readConcern", "afterClusterTime")
+	require.NoError(t, err, "expected the find command to read after the stored operation time")
+	ts, inc := afterClusterTime.Timestamp()
+	assert.Equal(t, bson.Timestamp{T: 100, I: 1}, bson.Timestamp{T: ts, I: inc}, "afterClusterTime mismatch")
+
+	assert.Equal(t, 1, store.saves, "expected the advanced times to be saved")
+	assert.Equal(t, &bson.Timestamp{T: 100, I: 5}, store.times.OperationTime, "saved operation time mismatch")
+}
@@ -0,0 +1,307 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// OperationType is the type of the operation described by a change event.
+type OperationType string
+
+// These constants are the operation types of change events.
+const (
+	OperationTypeInsert       OperationType = "insert"
+	OperationTypeUpdate       OperationType = "update"
+	OperationTypeReplace      OperationType = "replace"
+	OperationTypeDelete       OperationType = "delete"
+	OperationTypeDrop         OperationType = "drop"
+	OperationTypeRename       OperationType = "rename"
+	OperationTypeDropDatabase OperationType = "dropDatabase"
+	OperationTypeInvalidate   OperationType = "invalidate"
+
+	// The following DDL events are reported by MongoDB 6.0+ if the ShowExpandedEvents option of
+	// the change stream is set.
+
+	OperationTypeCreate                   OperationType = "create"
+	OperationTypeCreateIndexes            OperationType = "createIndexes"
+	OperationTypeDropIndexes              OperationType = "dropIndexes"
+	OperationTypeModify                   OperationType = "modify"
+	OperationTypeShardCollection          OperationType = "shardCollection"
+	OperationTypeRefineCollectionShardKey OperationType = "refineCollectionShardKey"
+	OperationTypeReshardCollection        OperationType = "reshardCollection"
+)
+
+// ChangeEventSchema is a version of the schema of change events. A ChangeEvent decoded with a
+// schema only has the fields and operation types of that schema; the fields added by later
+// versions are kept in ChangeEvent.Unknown, so that code written against a schema keeps working
+// when the server is upgraded.
+type ChangeEventSchema int
+
+// These constants are the versions of the schema of change events.
+const (
+	// ChangeEventSchemaV1 is the schema of the change events of MongoDB 4.0 to 5.x.
+	ChangeEventSchemaV1 ChangeEventSchema = 1
+
+	// ChangeEventSchemaV2 is the schema of MongoDB 6.0+, which adds the wallTime, collectionUUID,
+	// operationDescription, and fullDocumentBeforeChange fields, the disambiguatedPaths of update
+	// descriptions, and the expanded DDL events.
+	ChangeEventSchemaV2 ChangeEventSchema = 2
+
+	// ChangeEventSchemaLatest is the latest schema supported by the driver.
+	ChangeEventSchemaLatest = ChangeEventSchemaV2
+)
+
+// schema returns the first schema that has operation type t, or 0 if no schema has it.
+func (t OperationType) schema() ChangeEventSchema {
+	switch t {
+	case OperationTypeInsert, OperationTypeUpdate, OperationTypeReplace, OperationTypeDelete,
+		OperationTypeDrop, OperationTypeRename, OperationTypeDropDatabase, OperationTypeInvalidate:
+		return ChangeEventSchemaV1
+	case OperationTypeCreate, OperationTypeCreateIndexes, OperationTypeDropIndexes, OperationTypeModify,
+		OperationTypeShardCollection, OperationTypeRefineCollectionShardKey, OperationTypeReshardCollection:
+		return ChangeEventSchemaV2
+	}
+	return 0
+}
+
+// IsKnown reports whether t is an operation type of the given schema. Change streams can report
+// operation types added by servers newer than the driver, which are not known to any schema.
+func (t OperationType) IsKnown(schema ChangeEventSchema) bool {
+	s := t.schema()
+	return s != 0 && s <= schema
+}
+
+// IsDDL reports whether t is the type of an event that changes a collection, a database, or their
+// indexes rather than documents.
+func (t OperationType) IsDDL() bool {
+	switch t {
+	case OperationTypeInsert, OperationTypeUpdate, OperationTypeReplace, OperationTypeDelete,
+		OperationTypeInvalidate:
+		return false
+	}
+	return t.schema() != 0
+}
+
+// ChangeNamespace is the namespace of a change event.
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll,omitempty"`
+}
+
+// TruncatedArray describes an array truncated by an update.
+type TruncatedArray struct {
+	Field   string `bson:"field"`
+	NewSize int32  `bson:"newSize"`
+}
+
+// UpdateDescription describes the fields changed by an update event.
+type UpdateDescription struct {
+	UpdatedFields   bson.Raw
+	RemovedFields   []string
+	TruncatedArrays []TruncatedArray
+
+	// DisambiguatedPaths maps the updated and removed fields whose paths are ambiguous, such as
+	// fields with dots in their names, to their path components. It is only set by schema v2.
+	DisambiguatedPaths bson.Raw
+
+	// Unknown holds the fields of the update description that are not in the schema.
+	Unknown bson.Raw
+}
+
+// ChangeEvent is a change event returned by a change stream, decoded with a ChangeEventSchema.
+// Fields that are not reported for the operation type of the event are left empty.
+type ChangeEvent struct {
+	// ID is the resume token of the event.
+	ID bson.Raw
+
+	// OperationType is the type of the operation. It can be an operation type that is not known to
+	// the schema; see OperationType.IsKnown.
+	OperationType OperationType
+
+	ClusterTime bson.Timestamp
+	Namespace   ChangeNamespace
+
+	// To is the new namespace of a rename event.
+	To *ChangeNamespace
+
+	DocumentKey bson.Raw
+
+	// FullDocument is set for insert and replace events, and for update events if the
+	// FullDocument option of the change stream is set.
+	FullDocument bson.Raw
+
+	UpdateDescription *UpdateDescription
+
+	// TxnNumber and LSID identify the transaction of events of operations that ran in one.
+	TxnNumber *int64
+	LSID      bson.Raw
+
+	// The following fields are only set by schema v2.
+
+	WallTime                 time.Time
+	CollectionUUID           *bson.Binary
+	OperationDescription     bson.Raw
+	FullDocumentBeforeChange bson.Raw
+
+	// Schema is the schema the event was decoded with.
+	Schema ChangeEventSchema
+
+	// Unknown holds the fields of the event that are not in the schema, such as the fields added
+	// by newer servers, in the order they were received.
+	Unknown bson.Raw
+}
+
+// DecodeChangeEvent decodes the change event doc with the given schema. The fields of doc that are
+// not in the schema do not make decoding fail; they are returned in the Unknown field of the event.
+func DecodeChangeEvent(doc bson.Raw, schema ChangeEventSchema) (*ChangeEvent, error) {
+	if schema < ChangeEventSchemaV1 || schema > ChangeEventSchemaLatest {
+		return nil, fmt.Errorf("unsupported change event schema %d", schema)
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	evt := &ChangeEvent{Schema: schema}
+	var unknown unknownFields
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+
+		var dst interface{}
+		switch key {
+		case "_id":
+			dst = &evt.ID
+		case "operationType":
+			dst = &evt.OperationType
+		case "clusterTime":
+			dst = &evt.ClusterTime
+		case "ns":
+			dst = &evt.Namespace
+		case "to":
+			dst = &evt.To
+		case "documentKey":
+			dst = &evt.DocumentKey
+		case "fullDocument":
+			if val.Type == bson.TypeNull {
+				continue
+			}
+			dst = &evt.FullDocument
+		case "updateDescription":
+			desc, err := decodeUpdateDescription(val, schema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode change event field %q: %w", key, err)
+			}
+			evt.UpdateDescription = desc
+			continue
+		case "txnNumber":
+			dst = &evt.TxnNumber
+		case "lsid":
+			dst = &evt.LSID
+		}
+		if dst == nil && schema >= ChangeEventSchemaV2 {
+			switch key {
+			case "wallTime":
+				dst = &evt.WallTime
+			case "collectionUUID":
+				dst = &evt.CollectionUUID
+			case "operationDescription":
+				dst = &evt.OperationDescription
+			case "fullDocumentBeforeChange":
+				if val.Type == bson.TypeNull {
+					continue
+				}
+				dst = &evt.FullDocumentBeforeChange
+			}
+		}
+
+		if dst == nil {
+			unknown.add(elem)
+			continue
+		}
+		if err := val.Unmarshal(dst); err != nil {
+			return nil, fmt.Errorf("failed to decode change event field %q: %w", key, err)
+		}
+	}
+	evt.Unknown = unknown.document()
+	return evt, nil
+}
+
+// decodeUpdateDescription decodes the updateDescription field of a change event with the given
+// schema.
+func decodeUpdateDescription(val bson.RawValue, schema ChangeEventSchema) (*UpdateDescription, error) {
+	doc, ok := val.DocumentOK()
+	if !ok {
+		return nil, fmt.Errorf("expected a document, got BSON type %s", val.Type)
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &UpdateDescription{}
+	var unknown unknownFields
+	for _, elem := range elems {
+		var dst interface{}
+		switch elem.Key() {
+		case "updatedFields":
+			dst = &desc.UpdatedFields
+		case "removedFields":
+			dst = &desc.RemovedFields
+		case "truncatedArrays":
+			dst = &desc.TruncatedArrays
+		case "disambiguatedPaths":
+			if schema >= ChangeEventSchemaV2 {
+				dst = &desc.DisambiguatedPaths
+			}
+		}
+		if dst == nil {
+			unknown.add(elem)
+			continue
+		}
+		if err := elem.Value().Unmarshal(dst); err != nil {
+			return nil, fmt.Errorf("field %q: %w", elem.Key(), err)
+		}
+	}
+	desc.Unknown = unknown.document()
+	return desc, nil
+}
+
+// unknownFields collects the elements of a document that are not in a schema.
+type unknownFields struct {
+	idx int32
+	doc bsoncore.Document
+}
+
+func (u *unknownFields) add(elem bson.RawElement) {
+	if u.doc == nil {
+		u.idx, u.doc = bsoncore.AppendDocumentStart(nil)
+	}
+	u.doc = append(u.doc, elem...)
+}
+
+// document returns the collected elements as a document, or nil if there are none.
+func (u *unknownFields) document() bson.Raw {
+	if u.doc == nil {
+		return nil
+	}
+	doc, _ := bsoncore.AppendDocumentEnd(u.doc, u.idx)
+	return bson.Raw(doc)
+}
+
+// DecodeEvent decodes the current event document of the change stream with the given schema. See
+// DecodeChangeEvent for more information.
+func (cs *ChangeStream) DecodeEvent(schema ChangeEventSchema) (*ChangeEvent, error) {
+	if cs.cursor == nil {
+		return nil, ErrNilCursor
+	}
+	return DecodeChangeEvent(cs.Current, schema)
+}
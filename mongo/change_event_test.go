@@ -0,0 +1,154 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestOperationType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		op      OperationType
+		ddl     bool
+		knownV1 bool
+		knownV2 bool
+	}{
+		{OperationTypeInsert, false, true, true},
+		{OperationTypeInvalidate, false, true, true},
+		{OperationTypeDrop, true, true, true},
+		{OperationTypeRename, true, true, true},
+		{OperationTypeCreate, true, false, true},
+		{OperationTypeShardCollection, true, false, true},
+		{OperationType("futureEvent"), false, false, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(string(tc.op), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.ddl, tc.op.IsDDL(), "IsDDL mismatch")
+			assert.Equal(t, tc.knownV1, tc.op.IsKnown(ChangeEventSchemaV1), "IsKnown(V1) mismatch")
+			assert.Equal(t, tc.knownV2, tc.op.IsKnown(ChangeEventSchemaV2), "IsKnown(V2) mismatch")
+		})
+	}
+}
+
+func TestDecodeChangeEvent(t *testing.T) {
+	t.Parallel()
+
+	wallTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	update, err := bson.Marshal(bson.D{
+		{"_id", bson.D{{"_data", "token"}}},
+		{"operationType", "update"},
+		{"clusterTime", bson.Timestamp{T: 10, I: 1}},
+		{"wallTime", bson.NewDateTimeFromTime(wallTime)},
+		{"ns", bson.D{{"db", "db"}, {"coll", "coll"}}},
+		{"documentKey", bson.D{{"_id", 1}}},
+		{"fullDocument", nil},
+		{"updateDescription", bson.D{
+			{"updatedFields", bson.D{{"a.b", 2}}},
+			{"removedFields", bson.A{"c"}},
+			{"truncatedArrays", bson.A{bson.D{{"field", "arr"}, {"newSize", int32(1)}}}},
+			{"disambiguatedPaths", bson.D{{"a.b", bson.A{"a.b"}}}},
+			{"newDescriptionField", true},
+		}},
+		{"txnNumber", int64(3)},
+		{"futureField", "x"},
+	})
+	require.NoError(t, err, "Marshal error")
+
+	t.Run("v2", func(t *testing.T) {
+		t.Parallel()
+
+		evt, err := DecodeChangeEvent(update, ChangeEventSchemaV2)
+		require.NoError(t, err, "DecodeChangeEvent error")
+
+		assert.Equal(t, OperationTypeUpdate, evt.OperationType, "operation type mismatch")
+		assert.Equal(t, "token", evt.ID.Lookup("_data").StringValue(), "resume token mismatch")
+		assert.Equal(t, bson.Timestamp{T: 10, I: 1}, evt.ClusterTime, "cluster time mismatch")
+		assert.True(t, evt.WallTime.Equal(wallTime), "wall time mismatch")
+		assert.Equal(t, ChangeNamespace{Database: "db", Collection: "coll"}, evt.Namespace, "namespace mismatch")
+		assert.Nil(t, evt.FullDocument, "expected no full document")
+		require.NotNil(t, evt.TxnNumber, "expected a txnNumber")
+		assert.Equal(t, int64(3), *evt.TxnNumber, "txnNumber mismatch")
+
+		desc := evt.UpdateDescription
+		require.NotNil(t, desc, "expected an update description")
+		assert.Equal(t, int32(2), desc.UpdatedFields.Lookup("a.b").Int32(), "updated fields mismatch")
+		assert.Equal(t, []string{"c"}, desc.RemovedFields, "removed fields mismatch")
+		assert.Equal(t, []TruncatedArray{{Field: "arr", NewSize: 1}}, desc.TruncatedArrays, "truncated arrays mismatch")
+		assert.NotNil(t, desc.DisambiguatedPaths, "expected disambiguated paths")
+		assert.True(t, desc.Unknown.Lookup("newDescriptionField").Boolean(), "expected unknown description field")
+
+		elems, err := evt.Unknown.Elements()
+		require.NoError(t, err, "Elements error")
+		require.Len(t, elems, 1, "expected one unknown field")
+		assert.Equal(t, "x", evt.Unknown.Lookup("futureField").StringValue(), "unknown field mismatch")
+	})
+
+	t.Run("v1", func(t *testing.T) {
+		t.Parallel()
+
+		evt, err := DecodeChangeEvent(update, ChangeEventSchemaV1)
+		require.NoError(t, err, "DecodeChangeEvent error")
+
+		assert.True(t, evt.WallTime.IsZero(), "expected no wall time")
+		assert.Nil(t, evt.UpdateDescription.DisambiguatedPaths, "expected no disambiguated paths")
+		_, err = evt.UpdateDescription.Unknown.LookupErr("disambiguatedPaths")
+		assert.NoError(t, err, "expected disambiguated paths to be unknown")
+		_, err = evt.Unknown.LookupErr("wallTime")
+		assert.NoError(t, err, "expected wall time to be unknown")
+		_, err = evt.Unknown.LookupErr("futureField")
+		assert.NoError(t, err, "expected future field to be unknown")
+	})
+
+	t.Run("DDL event", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := bson.Marshal(bson.D{
+			{"_id", bson.D{{"_data", "token"}}},
+			{"operationType", "rename"},
+			{"ns", bson.D{{"db", "db"}, {"coll", "old"}}},
+			{"to", bson.D{{"db", "db"}, {"coll", "new"}}},
+			{"operationDescription", bson.D{{"to", bson.D{{"db", "db"}, {"coll", "new"}}}}},
+		})
+		require.NoError(t, err, "Marshal error")
+
+		evt, err := DecodeChangeEvent(doc, ChangeEventSchemaLatest)
+		require.NoError(t, err, "DecodeChangeEvent error")
+		assert.True(t, evt.OperationType.IsDDL(), "expected a DDL event")
+		require.NotNil(t, evt.To, "expected a to namespace")
+		assert.Equal(t, "new", evt.To.Collection, "to namespace mismatch")
+		assert.NotNil(t, evt.OperationDescription, "expected an operation description")
+		assert.Nil(t, evt.Unknown, "expected no unknown fields")
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := bson.Marshal(bson.D{{"operationType", 1}})
+		require.NoError(t, err, "Marshal error")
+
+		_, err = DecodeChangeEvent(doc, ChangeEventSchemaLatest)
+		assert.Error(t, err, "expected an error for an invalid operation type")
+	})
+
+	t.Run("unsupported schema", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeChangeEvent(update, ChangeEventSchema(3))
+		assert.Error(t, err, "expected an error for an unsupported schema")
+	})
+}
@@ -89,6 +89,7 @@ type ChangeStream struct {
 	selector        description.ServerSelector
 	operationTime   *bson.Timestamp
 	wireVersion     *description.VersionRange
+	coalesce        *batchCoalescer
 }
 
 type changeStreamConfig struct {
@@ -195,6 +196,7 @@ func newChangeStream(ctx context.Context, config changeStreamConfig, pipeline in
 	if cs.options.MaxAwaitTime != nil {
 		cs.cursorOptions.SetMaxAwaitTime(*cs.options.MaxAwaitTime)
 	}
+	cs.coalesce = newBatchCoalescer(cs.options.BatchCoalescing, cs.options.MaxAwaitTime)
 	if cs.options.Custom != nil {
 		// Marshal all custom options before passing to the initial aggregate. Return
 		// any errors from Marshaling.
@@ -583,6 +585,21 @@ func (cs *ChangeStream) SetBatchSize(size int32) {
 	cs.cursor.SetBatchSize(size)
 }
 
+// SetMaxAwaitTime sets the maximum amount of time that the server waits for new events on each
+// subsequent getMore command of the ChangeStream, including the getMore commands of a change stream
+// that is resumed after an error.
+//
+// The time.Duration value passed by this setter will be converted and rounded down to the nearest
+// millisecond.
+func (cs *ChangeStream) SetMaxAwaitTime(dur time.Duration) {
+	cs.options.MaxAwaitTime = &dur
+	cs.cursorOptions.SetMaxAwaitTime(dur)
+	cs.coalesce.setMaxAwaitTime(dur)
+	if cs.cursor != nil {
+		cs.cursor.SetMaxAwaitTime(dur)
+	}
+}
+
 // Decode will unmarshal the current event document into val and return any errors from the unmarshalling process
 // without any modification. If val is nil or is a typed nil, an error will be returned.
 func (cs *ChangeStream) Decode(val interface{}) error {
@@ -713,13 +730,23 @@ func (cs *ChangeStream) loopNext(ctx context.Context, nonBlocking bool) {
 			return
 		}
 
-		if cs.cursor.Next(ctx) {
-			// non-empty batch returned
-			cs.batch, cs.err = cs.cursor.Batch().Documents()
+		// Report the error of a getMore that ended the previous combined batch before fetching
+		// the next batch.
+		cs.err = replaceErrors(cs.coalesce.takeErr())
+		if cs.err == nil && cs.cursor.Next(ctx) {
+			// non-empty batch returned. TryNext runs at most one getMore, so only combine batches
+			// for Next.
+			batch := cs.cursor.Batch()
+			if !nonBlocking {
+				batch = cs.coalesce.coalesce(ctx, cs.cursor, batch)
+			}
+			cs.batch, cs.err = batch.Documents()
 			return
 		}
 
-		cs.err = replaceErrors(cs.cursor.Err())
+		if cs.err == nil {
+			cs.err = replaceErrors(cs.cursor.Err())
+		}
 		if cs.err == nil {
 			// Check if cursor is alive
 			if cs.ID() == 0 {
@@ -0,0 +1,175 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ChangeStreamEvent is a change event whose documents are decoded into values of type T, e.g. a
+// struct with the fields of the documents of the watched collection. It can be decoded from the
+// change streams of Client.Watch, Database.Watch, and Collection.Watch with
+// DecodeChangeStreamEvent:
+//
+//	cs, err := coll.Watch(ctx, mongo.Pipeline{}, options.ChangeStream().SetPrePostImages(options.WhenAvailable))
+//	...
+//	for cs.Next(ctx) {
+//		evt, err := mongo.DecodeChangeStreamEvent[Order](cs)
+//		if err != nil {
+//			return err
+//		}
+//		if evt.FullDocumentBeforeChange != nil && evt.FullDocument != nil {
+//			fmt.Println(evt.FullDocumentBeforeChange.Status, "->", evt.FullDocument.Status)
+//		}
+//	}
+//
+// The embedded ChangeEvent holds the metadata of the event and its raw documents, e.g.
+// evt.ChangeEvent.FullDocument.
+type ChangeStreamEvent[T any] struct {
+	ChangeEvent
+
+	// FullDocument is the decoded fullDocument field, or nil if the event does not have one.
+	FullDocument *T
+
+	// FullDocumentBeforeChange is the decoded fullDocumentBeforeChange field, or nil if the event
+	// does not have one.
+	FullDocumentBeforeChange *T
+
+	// UpdateDescription is the parsed update description of an update event, or nil for the other
+	// operation types.
+	UpdateDescription *ChangeStreamUpdateDescription
+}
+
+// ChangeStreamUpdateDescription is the update description of a ChangeStreamEvent.
+type ChangeStreamUpdateDescription struct {
+	// UpdatedFields maps the paths of the updated fields, which cannot be decoded into the type
+	// of the documents because they are dotted, to their new values.
+	UpdatedFields bson.Raw
+
+	RemovedFields   []string
+	TruncatedArrays []TruncatedArray
+
+	// DisambiguatedPaths maps the updated and removed fields whose paths are ambiguous to their
+	// path components, which are field names as strings and array indexes as ints. Servers
+	// before 6.1 do not report it; use Path to get the path of a field regardless of the server
+	// version.
+	DisambiguatedPaths map[string][]interface{}
+}
+
+// Path returns the path components of the updated or removed field, which are field names as
+// strings and array indexes as ints. If the field has no disambiguated path, its components are
+// the parts of the field split at dots, all of which are strings.
+func (d *ChangeStreamUpdateDescription) Path(field string) []interface{} {
+	if path, ok := d.DisambiguatedPaths[field]; ok {
+		return path
+	}
+	parts := strings.Split(field, ".")
+	path := make([]interface{}, 0, len(parts))
+	for _, p := range parts {
+		path = append(path, p)
+	}
+	return path
+}
+
+// DecodeChangeStreamEvent decodes the current event of cs with the latest ChangeEventSchema, and
+// its fullDocument and fullDocumentBeforeChange fields into values of type T with the registry and
+// BSON options of cs. A null fullDocument, such as the post-image of a deleted document, leaves
+// FullDocument nil.
+func DecodeChangeStreamEvent[T any](cs *ChangeStream) (*ChangeStreamEvent[T], error) {
+	if cs.cursor == nil {
+		return nil, ErrNilCursor
+	}
+	return decodeChangeStreamEvent[T](cs.Current, cs.bsonOpts, cs.registry)
+}
+
+func decodeChangeStreamEvent[T any](
+	doc bson.Raw,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (*ChangeStreamEvent[T], error) {
+	evt, err := DecodeChangeEvent(doc, ChangeEventSchemaLatest)
+	if err != nil {
+		return nil, err
+	}
+	typed := &ChangeStreamEvent[T]{ChangeEvent: *evt}
+
+	if typed.FullDocument, err = decodeEventDocument[T](evt.FullDocument, bsonOpts, registry); err != nil {
+		return nil, fmt.Errorf("failed to decode change event field \"fullDocument\": %w", err)
+	}
+	typed.FullDocumentBeforeChange, err = decodeEventDocument[T](evt.FullDocumentBeforeChange, bsonOpts, registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode change event field \"fullDocumentBeforeChange\": %w", err)
+	}
+	if desc := evt.UpdateDescription; desc != nil {
+		paths, err := decodeDisambiguatedPaths(desc.DisambiguatedPaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode change event field \"updateDescription\": %w", err)
+		}
+		typed.UpdateDescription = &ChangeStreamUpdateDescription{
+			UpdatedFields:      desc.UpdatedFields,
+			RemovedFields:      desc.RemovedFields,
+			TruncatedArrays:    desc.TruncatedArrays,
+			DisambiguatedPaths: paths,
+		}
+	}
+	return typed, nil
+}
+
+// decodeEventDocument decodes doc into a value of type T, or returns nil if doc is nil.
+func decodeEventDocument[T any](doc bson.Raw, bsonOpts *options.BSONOptions, registry *bson.Registry) (*T, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	v := new(T)
+	if err := getDecoder(doc, bsonOpts, registry).Decode(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeDisambiguatedPaths decodes the disambiguatedPaths field of an update description.
+func decodeDisambiguatedPaths(doc bson.Raw) (map[string][]interface{}, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string][]interface{}, len(elems))
+	for _, elem := range elems {
+		arr, ok := elem.Value().ArrayOK()
+		if !ok {
+			return nil, fmt.Errorf("disambiguated path of field %q: expected an array, got BSON type %s",
+				elem.Key(), elem.Value().Type)
+		}
+		values, err := arr.Values()
+		if err != nil {
+			return nil, err
+		}
+		path := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			if s, ok := v.StringValueOK(); ok {
+				path = append(path, s)
+				continue
+			}
+			i, ok := v.AsInt64OK()
+			if !ok {
+				return nil, fmt.Errorf("disambiguated path of field %q: unexpected BSON type %s",
+					elem.Key(), v.Type)
+			}
+			path = append(path, int(i))
+		}
+		paths[elem.Key()] = path
+	}
+	return paths, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type changeStreamEventTestDoc struct {
+	ID     int32  `bson:"_id"`
+	Status string `bson:"status"`
+}
+
+func TestDecodeChangeStreamEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("update with images", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := bson.Marshal(bson.D{
+			{"_id", bson.D{{"_data", "token"}}},
+			{"operationType", "update"},
+			{"ns", bson.D{{"db", "db"}, {"coll", "orders"}}},
+			{"documentKey", bson.D{{"_id", 1}}},
+			{"fullDocument", bson.D{{"_id", 1}, {"status", "shipped"}}},
+			{"fullDocumentBeforeChange", bson.D{{"_id", 1}, {"status", "paid"}}},
+			{"updateDescription", bson.D{
+				{"updatedFields", bson.D{{"status", "shipped"}, {"a.b", 1}}},
+				{"removedFields", bson.A{"note"}},
+				{"truncatedArrays", bson.A{bson.D{{"field", "items"}, {"newSize", int32(2)}}}},
+				{"disambiguatedPaths", bson.D{{"a.b", bson.A{"a.b"}}, {"items.0", bson.A{"items", int32(0)}}}},
+			}},
+		})
+		require.NoError(t, err, "Marshal error")
+
+		evt, err := decodeChangeStreamEvent[changeStreamEventTestDoc](doc, nil, nil)
+		require.NoError(t, err, "decodeChangeStreamEvent error")
+
+		assert.Equal(t, OperationTypeUpdate, evt.OperationType, "operation type mismatch")
+		assert.Equal(t, "orders", evt.Namespace.Collection, "namespace mismatch")
+		assert.Equal(t, &changeStreamEventTestDoc{ID: 1, Status: "shipped"}, evt.FullDocument, "post-image mismatch")
+		assert.Equal(t, &changeStreamEventTestDoc{ID: 1, Status: "paid"}, evt.FullDocumentBeforeChange,
+			"pre-image mismatch")
+		assert.NotNil(t, evt.ChangeEvent.FullDocument, "expected the raw post-image to be kept")
+
+		desc := evt.UpdateDescription
+		require.NotNil(t, desc, "expected an update description")
+		assert.Equal(t, []string{"note"}, desc.RemovedFields, "removed fields mismatch")
+		assert.Equal(t, []TruncatedArray{{Field: "items", NewSize: 2}}, desc.TruncatedArrays, "truncated arrays mismatch")
+		assert.Equal(t, []interface{}{"a.b"}, desc.Path("a.b"), "disambiguated path mismatch")
+		assert.Equal(t, []interface{}{"items", 0}, desc.Path("items.0"), "disambiguated index mismatch")
+		assert.Equal(t, []interface{}{"x", "y"}, desc.Path("x.y"), "expected an ambiguous path to be split")
+	})
+
+	t.Run("delete without images", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := bson.Marshal(bson.D{
+			{"_id", bson.D{{"_data", "token"}}},
+			{"operationType", "delete"},
+			{"documentKey", bson.D{{"_id", 1}}},
+			{"fullDocument", nil},
+			{"fullDocumentBeforeChange", nil},
+		})
+		require.NoError(t, err, "Marshal error")
+
+		evt, err := decodeChangeStreamEvent[changeStreamEventTestDoc](doc, nil, nil)
+		require.NoError(t, err, "decodeChangeStreamEvent error")
+		assert.Nil(t, evt.FullDocument, "expected no post-image")
+		assert.Nil(t, evt.FullDocumentBeforeChange, "expected no pre-image")
+		assert.Nil(t, evt.UpdateDescription, "expected no update description")
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := bson.Marshal(bson.D{{"operationType", "insert"}, {"fullDocument", bson.D{{"_id", "x"}}}})
+		require.NoError(t, err, "Marshal error")
+
+		_, err = decodeChangeStreamEvent[changeStreamEventTestDoc](doc, nil, nil)
+		assert.Error(t, err, "expected an error for a document of the wrong type")
+	})
+
+	t.Run("nil cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeChangeStreamEvent[changeStreamEventTestDoc](&ChangeStream{})
+		assert.ErrorIs(t, err, ErrNilCursor, "expected ErrNilCursor")
+	})
+}
+
+func TestChangeStreamSetPrePostImages(t *testing.T) {
+	t.Parallel()
+
+	opts, err := mongoutil.NewOptions[options.ChangeStreamOptions](
+		options.ChangeStream().SetPrePostImages(options.Required))
+	require.NoError(t, err, "NewOptions error")
+	assert.Equal(t, options.Required, *opts.FullDocument, "FullDocument mismatch")
+	assert.Equal(t, options.Required, *opts.FullDocumentBeforeChange, "FullDocumentBeforeChange mismatch")
+
+	_, err = mongoutil.NewOptions[options.ChangeStreamOptions](
+		options.ChangeStream().SetPrePostImages(options.UpdateLookup))
+	assert.Error(t, err, "expected an error for an invalid value")
+}
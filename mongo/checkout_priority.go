@@ -0,0 +1,59 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// CheckoutPriority is the priority class of an operation waiting to check out a connection from a
+// connection pool. When a pool has no available connections, waiting operations with a higher
+// priority are given connections before operations with a lower priority. Operations with the same
+// priority are given connections in the order they started waiting.
+type CheckoutPriority = driver.CheckoutPriority
+
+// These constants are the supported checkout priorities.
+const (
+	// CheckoutPriorityBackground is for bulk or background work that can tolerate waiting longer
+	// for a connection.
+	CheckoutPriorityBackground = driver.CheckoutPriorityBackground
+
+	// CheckoutPriorityNormal is the priority used for operations that do not specify one.
+	CheckoutPriorityNormal = driver.CheckoutPriorityNormal
+
+	// CheckoutPriorityCritical is for latency-sensitive work, such as health checks or payment
+	// writes, that should be given a connection ahead of other waiting operations.
+	CheckoutPriorityCritical = driver.CheckoutPriorityCritical
+)
+
+// WithCheckoutPriority returns a copy of ctx that carries the given checkout priority. Operations
+// run with the returned Context use that priority when waiting for a connection.
+//
+// For example, to give a write priority over background work when the pool is exhausted:
+//
+//	ctx = mongo.WithCheckoutPriority(ctx, mongo.CheckoutPriorityCritical)
+//	_, err := coll.InsertOne(ctx, payment)
+func WithCheckoutPriority(ctx context.Context, priority CheckoutPriority) context.Context {
+	return driver.WithCheckoutPriority(ctx, priority)
+}
+
+// WithCheckoutQueueTimeout returns a copy of ctx that limits how long operations run with it wait
+// for a connection when the pool is exhausted, independently of the deadline of ctx. An operation
+// that waits longer fails with a timeout error, for which IsTimeout returns true. A non-positive
+// timeout means the wait is only limited by ctx.
+//
+// For example, to shed batch work quickly instead of queueing it behind interactive requests for
+// the whole operation timeout:
+//
+//	ctx = mongo.WithCheckoutPriority(ctx, mongo.CheckoutPriorityBackground)
+//	ctx = mongo.WithCheckoutQueueTimeout(ctx, 50*time.Millisecond)
+func WithCheckoutQueueTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return driver.WithCheckoutQueueTimeout(ctx, timeout)
+}
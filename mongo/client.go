@@ -8,7 +8,6 @@ package mongo
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -67,22 +66,36 @@ type Client struct {
 	bsonOpts       *options.BSONOptions
 	registry       *bson.Registry
 	monitor        *event.CommandMonitor
+	sizeStats      *commandSizeRecorder
 	serverAPI      *driver.ServerAPIOptions
 	serverMonitor  *event.ServerMonitor
 	sessionPool    *session.Pool
+	maxIdleSess    uint64
 	timeout        *time.Duration
 	httpClient     *http.Client
 	logger         *logger.Logger
+	cursorMemory   *cursorMemoryTracker
+	cursorLeaks    *cursorLeakGuard
+	shardZones     shardZonesCache
+	closeHooks     closeHookRegistry
 
 	// in-use encryption fields
 	keyVaultClientFLE  *Client
 	keyVaultCollFLE    *Collection
+	keyCacheFLE        *keyCache
 	mongocryptdFLE     *mongocryptdClient
 	cryptFLE           driver.Crypt
 	metadataClientFLE  *Client
 	internalClientFLE  *Client
 	encryptedFieldsMap map[string]interface{}
+	queryAnalysis      QueryAnalysisProvider
+	cryptSharedLibVer  string
 	authenticator      driver.Authenticator
+
+	// dataFederation is whether the Client was created with Atlas Data Federation hosts.
+	dataFederation bool
+	// retryWritesSet is whether RetryWrites was set, which overrides the Data Federation default.
+	retryWritesSet bool
 }
 
 // Connect creates a new Client and then initializes it using the Connect method.
@@ -143,6 +156,12 @@ func newClient(opts ...options.Lister[options.ClientOptions]) (*Client, error) {
 	}
 	client := &Client{id: id}
 
+	// Atlas Data Federation
+	if isDataFederationHosts(args.Hosts) {
+		client.dataFederation = true
+		applyDataFederationDefaults(args)
+	}
+
 	// ClusterClock
 	client.clock = new(session.ClusterClock)
 
@@ -155,6 +174,11 @@ func newClient(opts ...options.Lister[options.ClientOptions]) (*Client, error) {
 	if args.Monitor != nil {
 		client.monitor = args.Monitor
 	}
+	// CommandSizeStats
+	if args.CommandSizeStats != nil && *args.CommandSizeStats {
+		client.sizeStats = newCommandSizeRecorder()
+		client.monitor = client.sizeStats.monitor(client.monitor)
+	}
 	// ServerMonitor
 	if args.ServerMonitor != nil {
 		client.serverMonitor = args.ServerMonitor
@@ -182,6 +206,7 @@ func newClient(opts ...options.Lister[options.ClientOptions]) (*Client, error) {
 	client.retryWrites = true // retry writes on by default
 	if args.RetryWrites != nil {
 		client.retryWrites = *args.RetryWrites
+		client.retryWritesSet = true
 	}
 	client.retryReads = true
 	if args.RetryReads != nil {
@@ -194,6 +219,24 @@ func newClient(opts ...options.Lister[options.ClientOptions]) (*Client, error) {
 	if args.WriteConcern != nil {
 		client.writeConcern = args.WriteConcern
 	}
+	// MaxIdleSessions
+	if args.MaxIdleSessions != nil {
+		client.maxIdleSess = *args.MaxIdleSessions
+	}
+	// MaxCursorMemory
+	var maxCursorMemory int64
+	if args.MaxCursorMemory != nil {
+		maxCursorMemory = *args.MaxCursorMemory
+	}
+	client.cursorMemory = newCursorMemoryTracker(
+		maxCursorMemory,
+		args.CursorMemoryBackpressure != nil && *args.CursorMemoryBackpressure,
+	)
+	// CursorLeakHandler and KillLeakedCursors
+	client.cursorLeaks = newCursorLeakGuard(
+		args.CursorLeakHandler,
+		args.KillLeakedCursors != nil && *args.KillLeakedCursors,
+	)
 	// AutoEncryptionOptions
 	if args.AutoEncryptionOptions != nil {
 		if err := client.configureAutoEncryption(args); err != nil {
@@ -299,6 +342,7 @@ func (c *Client) connect() error {
 		updateChan = sub.Updates
 	}
 	c.sessionPool = session.NewPool(updateChan)
+	c.sessionPool.SetMaxIdle(int(c.maxIdleSess))
 	return nil
 }
 
@@ -323,7 +367,13 @@ func (c *Client) Disconnect(ctx context.Context) error {
 		defer httputil.CloseIdleHTTPConnections(c.httpClient)
 	}
 
-	c.endSessions(ctx)
+	// Stop the resources registered with OnClose and Register while their operations can still
+	// run, then return their first error once the Client is disconnected.
+	hookErr := c.closeHooks.run(ctx)
+
+	if !c.IsDataFederation() {
+		c.endSessions(ctx)
+	}
 	if c.mongocryptdFLE != nil {
 		if err := c.mongocryptdFLE.disconnect(ctx); err != nil {
 			return err
@@ -346,15 +396,20 @@ func (c *Client) Disconnect(ctx context.Context) error {
 			return err
 		}
 	}
+	if c.keyCacheFLE != nil {
+		c.keyCacheFLE.close()
+	}
 	if c.cryptFLE != nil {
 		c.cryptFLE.Close()
 	}
 
 	if disconnector, ok := c.deployment.(driver.Disconnector); ok {
-		return replaceErrors(disconnector.Disconnect(ctx))
+		if err := replaceErrors(disconnector.Disconnect(ctx)); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return hookErr
 }
 
 // Ping sends a ping command to verify that the client can connect to the deployment.
@@ -432,6 +487,9 @@ func (c *Client) StartSession(opts ...options.Lister[options.SessionOptions]) (*
 	if sessArgs.Snapshot != nil {
 		coreOpts.Snapshot = sessArgs.Snapshot
 	}
+	if sessArgs.SnapshotTime != nil {
+		coreOpts.SnapshotTime = sessArgs.SnapshotTime
+	}
 
 	sess, err := session.NewClientSession(c.sessionPool, c.id, coreOpts)
 	if err != nil {
@@ -487,21 +545,47 @@ func (c *Client) configureAutoEncryption(args *options.ClientOptions) error {
 		return err
 	}
 
-	mc, err := c.newMongoCrypt(args.AutoEncryptionOptions)
+	kr := keyRetriever{coll: c.keyVaultCollFLE}
+	if c.keyCacheFLE, err = newKeyCache(kr.cryptKeys, aeArgs); err != nil {
+		return err
+	}
+
+	mc, err := c.newMongoCrypt(args.AutoEncryptionOptions, nil)
 	if err != nil {
 		return err
 	}
 
-	// If the crypt_shared library was not loaded, try to spawn and connect to mongocryptd.
-	if mc.CryptSharedLibVersionString() == "" {
+	bypass := (aeArgs.BypassAutoEncryption != nil && *aeArgs.BypassAutoEncryption) ||
+		(aeArgs.BypassQueryAnalysis != nil && *aeArgs.BypassQueryAnalysis)
+
+	c.cryptSharedLibVer = mc.CryptSharedLibVersionString()
+	if c.cryptSharedLibVer != "" {
+		c.queryAnalysis = QueryAnalysisCryptShared
+		publishQueryAnalysisEvent(aeArgs.QueryAnalysisMonitor, &event.QueryAnalysisEvent{
+			Type:                  event.CryptSharedLibLoaded,
+			CryptSharedLibVersion: c.cryptSharedLibVer,
+		})
+	} else {
+		publishQueryAnalysisEvent(aeArgs.QueryAnalysisMonitor, &event.QueryAnalysisEvent{
+			Type: event.CryptSharedLibNotLoaded,
+		})
+
+		// If the crypt_shared library was not loaded, try to spawn and connect to mongocryptd.
 		mongocryptdFLE, err := newMongocryptdClient(args.AutoEncryptionOptions)
 		if err != nil {
 			return err
 		}
 		c.mongocryptdFLE = mongocryptdFLE
+		if !bypass {
+			c.queryAnalysis = QueryAnalysisMongocryptd
+		}
 	}
 
 	c.configureCryptFLE(mc, args.AutoEncryptionOptions)
+	if aeArgs.KeyAltNameResolver != nil {
+		c.cryptFLE = c.newKeyAltNameCrypt(c.cryptFLE, aeArgs.KeyAltNameResolver, args.AutoEncryptionOptions)
+	}
+	c.keyCacheFLE.start()
 	return nil
 }
 
@@ -568,7 +652,12 @@ func (c *Client) configureMetadataClientFLE(clientArgs *options.ClientOptions) e
 	return err
 }
 
-func (c *Client) newMongoCrypt(opts options.Lister[options.AutoEncryptionOptions]) (*mongocrypt.MongoCrypt, error) {
+// newMongoCrypt creates the MongoCrypt used for automatic encryption. If keyID is not nil, it replaces the
+// options.KeyAltNamePlaceholder key IDs in the SchemaMap and EncryptedFieldsMap.
+func (c *Client) newMongoCrypt(
+	opts options.Lister[options.AutoEncryptionOptions],
+	keyID *bson.Binary,
+) (*mongocrypt.MongoCrypt, error) {
 	args, err := mongoutil.NewOptions[options.AutoEncryptionOptions](opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
@@ -581,6 +670,11 @@ func (c *Client) newMongoCrypt(opts options.Lister[options.AutoEncryptionOptions
 		if err != nil {
 			return nil, err
 		}
+		if keyID != nil {
+			// JSON schemas take an array of key IDs.
+			keyIDs := bsoncore.NewArrayBuilder().AppendBinary(keyID.Subtype, keyID.Data).Build()
+			schema = replaceKeyAltNamePlaceholder(schema, bsoncore.Value{Type: bsoncore.TypeArray, Data: keyIDs})
+		}
 		cryptSchemaMap[k] = schema
 	}
 
@@ -591,6 +685,12 @@ func (c *Client) newMongoCrypt(opts options.Lister[options.AutoEncryptionOptions
 		if err != nil {
 			return nil, err
 		}
+		if keyID != nil {
+			encryptedFields = replaceKeyAltNamePlaceholder(encryptedFields, bsoncore.Value{
+				Type: bsoncore.TypeBinary,
+				Data: bsoncore.AppendBinary(nil, keyID.Subtype, keyID.Data),
+			})
+		}
 		cryptEncryptedFieldsMap[k] = encryptedFields
 	}
 
@@ -629,7 +729,8 @@ func (c *Client) newMongoCrypt(opts options.Lister[options.AutoEncryptionOptions
 		SetEncryptedFieldsMap(cryptEncryptedFieldsMap).
 		SetCryptSharedLibDisabled(cryptSharedLibDisabled || bypassAutoEncryption).
 		SetCryptSharedLibOverridePath(cryptSharedLibPath).
-		SetHTTPClient(args.HTTPClient))
+		SetHTTPClient(args.HTTPClient).
+		SetKeyExpiration(args.KeyExpiration))
 	if err != nil {
 		return nil, err
 	}
@@ -646,21 +747,52 @@ func (c *Client) newMongoCrypt(opts options.Lister[options.AutoEncryptionOptions
 
 	// If the "cryptSharedLibRequired" extra option is set to true, check the MongoCrypt version
 	// string to confirm that the library was successfully loaded. If the version string is empty,
-	// return an error indicating that we couldn't load the crypt_shared library.
+	// return an error indicating that we couldn't load the crypt_shared library. The
+	// CryptSharedLibRequired option takes precedence over the extra option.
+	requiredBy := `extra option "cryptSharedLibRequired"`
+	if args.CryptSharedLibRequired != nil {
+		cryptSharedLibRequired = *args.CryptSharedLibRequired
+		requiredBy = "option CryptSharedLibRequired"
+	}
 	if cryptSharedLibRequired && mc.CryptSharedLibVersionString() == "" {
-		return nil, errors.New(
-			`AutoEncryption extra option "cryptSharedLibRequired" is true, but we failed to load the crypt_shared library`)
+		return nil, fmt.Errorf(
+			"AutoEncryption %s is true, but we failed to load the crypt_shared library", requiredBy)
 	}
 
 	return mc, nil
 }
 
+func publishQueryAnalysisEvent(monitor *event.QueryAnalysisMonitor, evt *event.QueryAnalysisEvent) {
+	if monitor != nil && monitor.Event != nil {
+		monitor.Event(evt)
+	}
+}
+
+// AutoEncryptionStatus returns the state of automatic encryption for the Client, including whether the
+// crypt_shared library or mongocryptd is used to analyze commands and any error from the most recent
+// attempt to spawn mongocryptd.
+func (c *Client) AutoEncryptionStatus() AutoEncryptionStatus {
+	status := AutoEncryptionStatus{
+		Enabled:               c.cryptFLE != nil,
+		Provider:              c.queryAnalysis,
+		CryptSharedLibVersion: c.cryptSharedLibVer,
+	}
+	if c.mongocryptdFLE != nil {
+		c.mongocryptdFLE.status(&status)
+	}
+	return status
+}
+
 //nolint:unused // the unused linter thinks that this function is unreachable because "c.newMongoCrypt" always panics without the "cse" build tag set.
 func (c *Client) configureCryptFLE(mc *mongocrypt.MongoCrypt, opts options.Lister[options.AutoEncryptionOptions]) {
+	c.cryptFLE = c.newCryptFLE(mc, opts)
+}
+
+//nolint:unused // see configureCryptFLE.
+func (c *Client) newCryptFLE(mc *mongocrypt.MongoCrypt, opts options.Lister[options.AutoEncryptionOptions]) driver.Crypt {
 	args, _ := mongoutil.NewOptions[options.AutoEncryptionOptions](opts)
 
 	bypass := args.BypassAutoEncryption != nil && *args.BypassAutoEncryption
-	kr := keyRetriever{coll: c.keyVaultCollFLE}
 	var cir collInfoRetriever
 	// If bypass is true, c.metadataClientFLE is nil and the collInfoRetriever
 	// will not be used. If bypass is false, to the parent client or the
@@ -669,10 +801,10 @@ func (c *Client) configureCryptFLE(mc *mongocrypt.MongoCrypt, opts options.Liste
 		cir = collInfoRetriever{client: c.metadataClientFLE}
 	}
 
-	c.cryptFLE = driver.NewCrypt(&driver.CryptOptions{
+	return driver.NewCrypt(&driver.CryptOptions{
 		MongoCrypt:           mc,
 		CollInfoFn:           cir.cryptCollInfo,
-		KeyFn:                kr.cryptKeys,
+		KeyFn:                c.keyCacheFLE.cryptKeys,
 		MarkFn:               c.mongocryptdFLE.markCommand,
 		TLSConfig:            args.TLSConfig,
 		BypassAutoEncryption: bypass,
@@ -886,6 +1018,38 @@ func (c *Client) NumberSessionsInProgress() int {
 	return int(c.sessionPool.CheckedOut())
 }
 
+// CheckReadPrefTags returns an error wrapping ErrNoTagSetMatch if the tag sets of rp do not match
+// any replica set member in the deployment as most recently discovered by the Client. A read
+// preference whose tag sets match no members causes read operations to fail server selection, so
+// this can be used to detect misconfigured tag sets, for example after connecting. It returns nil
+// if rp has no tag sets, if the deployment is not a replica set, or if no members have been
+// discovered yet.
+func (c *Client) CheckReadPrefTags(rp *readpref.ReadPref) error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok || rp == nil || len(rp.TagSets()) == 0 {
+		return nil
+	}
+
+	var members []description.Server
+	for _, s := range topo.Description().Servers {
+		if s.Kind == description.ServerKindRSPrimary || s.Kind == description.ServerKindRSSecondary {
+			members = append(members, s)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	for _, ts := range rp.TagSets() {
+		for _, s := range members {
+			if s.Tags.ContainsAll(ts) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrNoTagSetMatch, rp.TagSets())
+}
+
 func (c *Client) createBaseCursorOptions() driver.CursorOptions {
 	return driver.CursorOptions{
 		CommandMonitor: c.monitor,
@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Closer is a resource that can be closed gracefully, such as a ChangeStream, a BackgroundRunner,
+// or a TenantRouter.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// OnClose registers fn to be called by Disconnect before the Client ends its sessions and closes
+// its connections, so that resources using the Client can be stopped while operations can still
+// run. Disconnect calls the registered functions one at a time in the reverse order of their
+// registration, with the Context passed to Disconnect. If a function returns an error, Disconnect
+// still calls the remaining functions and disconnects, then returns the first error.
+//
+// The returned function unregisters fn. Functions registered after Disconnect was called are
+// never called.
+func (c *Client) OnClose(fn func(ctx context.Context) error) (unregister func()) {
+	return c.closeHooks.add(fn)
+}
+
+// Register registers a resource that depends on the Client to be closed by Disconnect, in the
+// same way as a function registered with OnClose. BackgroundRunners and TenantRouters are
+// registered with their Client when they are created and unregistered when they are closed.
+//
+// A resource that is not safe for concurrent use, such as a ChangeStream, must not be in use when
+// Disconnect is called.
+func (c *Client) Register(r Closer) (unregister func()) {
+	return c.closeHooks.add(r.Close)
+}
+
+// closeHookRegistry holds the functions called by Client.Disconnect. The zero value is ready to
+// use.
+type closeHookRegistry struct {
+	mu     sync.Mutex
+	nextID uint64
+	hooks  map[uint64]func(context.Context) error
+	closed bool
+}
+
+// add registers fn and returns a function that unregisters it.
+func (r *closeHookRegistry) add(fn func(context.Context) error) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed || fn == nil {
+		return func() {}
+	}
+	if r.hooks == nil {
+		r.hooks = make(map[uint64]func(context.Context) error)
+	}
+	id := r.nextID
+	r.nextID++
+	r.hooks[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.hooks, id)
+	}
+}
+
+// run calls the registered functions in the reverse order of their registration and returns the
+// first error. After run, no more functions can be registered.
+func (r *closeHookRegistry) run(ctx context.Context) error {
+	r.mu.Lock()
+	ids := make([]uint64, 0, len(r.hooks))
+	for id := range r.hooks {
+		ids = append(ids, id)
+	}
+	hooks := r.hooks
+	r.hooks = nil
+	r.closed = true
+	r.mu.Unlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+
+	var firstErr error
+	for _, id := range ids {
+		if err := hooks[id](ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,86 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+type closerFunc func(ctx context.Context) error
+
+func (f closerFunc) Close(ctx context.Context) error { return f(ctx) }
+
+func TestClientOnClose(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) *Client {
+		t.Helper()
+
+		clientOpts := options.Client()
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = drivertest.NewMockDeployment()
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		return client
+	}
+
+	t.Run("runs hooks in reverse order", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		var order []string
+		hook := func(name string, err error) func(context.Context) error {
+			return func(context.Context) error {
+				order = append(order, name)
+				return err
+			}
+		}
+
+		errFirst := errors.New("first")
+		client.OnClose(hook("a", nil))
+		unregister := client.OnClose(hook("unregistered", nil))
+		client.OnClose(hook("b", errors.New("second")))
+		client.Register(closerFunc(hook("c", errFirst)))
+		unregister()
+
+		err := client.Disconnect(context.Background())
+		assert.ErrorIs(t, err, errFirst, "expected the first hook error")
+		assert.Equal(t, []string{"c", "b", "a"}, order, "hook order mismatch")
+
+		client.OnClose(hook("late", nil))
+		assert.Len(t, client.closeHooks.hooks, 0, "expected hooks registered after Disconnect to be ignored")
+	})
+
+	t.Run("closes dependent resources", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		runner, err := NewBackgroundRunner(client)
+		require.NoError(t, err, "NewBackgroundRunner error")
+		router, err := NewTenantRouter(client)
+		require.NoError(t, err, "NewTenantRouter error")
+		closed, err := NewTenantRouter(client)
+		require.NoError(t, err, "NewTenantRouter error")
+		require.NoError(t, closed.Close(context.Background()), "Close error")
+		assert.Len(t, client.closeHooks.hooks, 2, "expected the closed router to be unregistered")
+
+		require.NoError(t, client.Disconnect(context.Background()), "Disconnect error")
+		err = runner.Submit(context.Background(), func(context.Context) error { return nil })
+		assert.ErrorIs(t, err, ErrBackgroundRunnerClosed, "expected the runner to be closed")
+		_, err = router.Database(WithTenant(context.Background(), "acme"))
+		assert.ErrorIs(t, err, ErrTenantRouterClosed, "expected the router to be closed")
+	})
+}
@@ -11,7 +11,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
@@ -167,9 +170,171 @@ func (ce *ClientEncryption) CreateDataKey(
 		return bson.Binary{}, ErrClientDisconnected
 	}
 
+	dataKeyDoc, err := ce.newDataKeyDocument(ctx, kmsProvider, opts...)
+	if err != nil {
+		return bson.Binary{}, err
+	}
+
+	// insert key into key vault
+	_, err = ce.keyVaultColl.InsertOne(ctx, dataKeyDoc)
+	if err != nil {
+		return bson.Binary{}, err
+	}
+
+	subtype, data := bson.Raw(dataKeyDoc).Lookup("_id").Binary()
+	return bson.Binary{Subtype: subtype, Data: data}, nil
+}
+
+// CreateDataKeys creates n new key documents and inserts them into the key vault collection with a single InsertMany,
+// which is considerably faster than calling CreateDataKey n times when provisioning many keys, such as a key per
+// tenant. Returns the _id values of the created documents as UUIDs (BSON binary subtype 0x04), in order.
+//
+// The key documents are created concurrently, each with its own request to the KMS provider; the concurrency and
+// the rate of KMS requests can be limited with the opts parameter (see the options.CreateDataKeysOptions
+// documentation). The optsPerKey function, if not nil, is called with the index of each key, from 0 to n-1, and
+// returns the options for that key, such as its key alternate names. It can be called concurrently.
+//
+// If creating any key document fails, no keys are inserted. If the insert fails, some of the keys may have been
+// inserted; the returned error is a BulkWriteException describing the keys that were not.
+func (ce *ClientEncryption) CreateDataKeys(
+	ctx context.Context,
+	kmsProvider string,
+	n int,
+	optsPerKey func(i int) []options.Lister[options.DataKeyOptions],
+	opts ...options.Lister[options.CreateDataKeysOptions],
+) ([]bson.Binary, error) {
+	if ce.closed {
+		return nil, ErrClientDisconnected
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	args, err := mongoutil.NewOptions[options.CreateDataKeysOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	docs, err := ce.newDataKeyDocuments(ctx, kmsProvider, n, optsPerKey, args)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDocs := make([]interface{}, len(docs))
+	ids := make([]bson.Binary, len(docs))
+	for i, doc := range docs {
+		keyDocs[i] = doc
+		subtype, data := bson.Raw(doc).Lookup("_id").Binary()
+		ids[i] = bson.Binary{Subtype: subtype, Data: data}
+	}
+
+	// insert keys into key vault
+	if _, err := ce.keyVaultColl.InsertMany(ctx, keyDocs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// defaultCreateDataKeysConcurrency is the number of key documents CreateDataKeys creates concurrently if
+// CreateDataKeysOptions.Concurrency is not set.
+const defaultCreateDataKeysConcurrency = 8
+
+// newDataKeyDocuments creates n key documents concurrently, limited by the concurrency and rate limit of args. It
+// stops at the first error.
+func (ce *ClientEncryption) newDataKeyDocuments(
+	ctx context.Context,
+	kmsProvider string,
+	n int,
+	optsPerKey func(i int) []options.Lister[options.DataKeyOptions],
+	args *options.CreateDataKeysOptions,
+) ([]bsoncore.Document, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := defaultCreateDataKeysConcurrency
+	if args.Concurrency != nil {
+		concurrency = *args.Concurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	// ticks, if not nil, limits the rate at which key documents are created.
+	var ticks <-chan time.Time
+	if args.KMSRequestsPerSecond != nil && *args.KMSRequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *args.KMSRequestsPerSecond))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	docs := make([]bsoncore.Document, n)
+	indexes := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var keyOpts []options.Lister[options.DataKeyOptions]
+				if optsPerKey != nil {
+					keyOpts = optsPerKey(i)
+				}
+				doc, err := ce.newDataKeyDocument(ctx, kmsProvider, keyOpts...)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("error creating data key %d: %w", i, err)
+						cancel()
+					})
+					continue
+				}
+				docs[i] = doc
+			}
+		}()
+	}
+
+send:
+	for i := 0; i < n; i++ {
+		if ticks != nil && i > 0 {
+			select {
+			case <-ticks:
+			case <-ctx.Done():
+				break send
+			}
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// newDataKeyDocument creates a new key document without inserting it into the key vault collection.
+func (ce *ClientEncryption) newDataKeyDocument(
+	ctx context.Context,
+	kmsProvider string,
+	opts ...options.Lister[options.DataKeyOptions],
+) (bsoncore.Document, error) {
 	args, err := mongoutil.NewOptions[options.DataKeyOptions](opts...)
 	if err != nil {
-		return bson.Binary{}, fmt.Errorf("failed to construct options from builder: %w", err)
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
 	}
 
 	co := mcopts.DataKey().SetKeyAltNames(args.KeyAltNames)
@@ -179,7 +344,7 @@ func (ce *ClientEncryption) CreateDataKey(
 			ce.keyVaultClient.bsonOpts,
 			ce.keyVaultClient.registry)
 		if err != nil {
-			return bson.Binary{}, err
+			return nil, err
 		}
 		co.SetMasterKey(keyDoc)
 	}
@@ -188,19 +353,7 @@ func (ce *ClientEncryption) CreateDataKey(
 	}
 
 	// create data key document
-	dataKeyDoc, err := ce.crypt.CreateDataKey(ctx, kmsProvider, co)
-	if err != nil {
-		return bson.Binary{}, err
-	}
-
-	// insert key into key vault
-	_, err = ce.keyVaultColl.InsertOne(ctx, dataKeyDoc)
-	if err != nil {
-		return bson.Binary{}, err
-	}
-
-	subtype, data := bson.Raw(dataKeyDoc).Lookup("_id").Binary()
-	return bson.Binary{Subtype: subtype, Data: data}, nil
+	return ce.crypt.CreateDataKey(ctx, kmsProvider, co)
 }
 
 // transformExplicitEncryptionOptions creates explicit encryption options to be passed to libmongocrypt.
@@ -361,14 +514,45 @@ func (ce *ClientEncryption) GetKey(ctx context.Context, id bson.Binary) *SingleR
 	return ce.keyVaultColl.FindOne(ctx, filter)
 }
 
-// GetKeys finds all documents in the key vault collection. Returns the result of the internal find() operation on the
-// key vault collection.
-func (ce *ClientEncryption) GetKeys(ctx context.Context) (*Cursor, error) {
+// GetKeys finds all documents in the key vault collection that match the filters of the options, or all documents if
+// no filter is set. Returns the result of the internal find() operation on the key vault collection.
+func (ce *ClientEncryption) GetKeys(ctx context.Context, opts ...options.Lister[options.GetKeysOptions]) (*Cursor, error) {
 	if ce.closed {
 		return nil, ErrClientDisconnected
 	}
 
-	return ce.keyVaultColl.Find(ctx, bson.D{})
+	args, err := mongoutil.NewOptions[options.GetKeysOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+	return ce.keyVaultColl.Find(ctx, getKeysFilter(args))
+}
+
+// getKeysFilter returns the filter on key documents for the GetKeys options.
+func getKeysFilter(args *options.GetKeysOptions) bson.D {
+	filter := bson.D{}
+	if args.KeyAltNamePrefix != nil {
+		filter = append(filter, bson.E{Key: "keyAltNames", Value: bson.Regex{Pattern: "^" + regexp.QuoteMeta(*args.KeyAltNamePrefix)}})
+	}
+	if args.CreatedAfter != nil || args.CreatedBefore != nil {
+		created := bson.D{}
+		if args.CreatedAfter != nil {
+			created = append(created, bson.E{Key: "$gte", Value: *args.CreatedAfter})
+		}
+		if args.CreatedBefore != nil {
+			created = append(created, bson.E{Key: "$lt", Value: *args.CreatedBefore})
+		}
+		filter = append(filter, bson.E{Key: "creationDate", Value: created})
+	}
+	if provider := args.MasterKeyProvider; provider != nil {
+		if strings.Contains(*provider, ":") {
+			filter = append(filter, bson.E{Key: "masterKey.provider", Value: *provider})
+		} else {
+			pattern := "^" + regexp.QuoteMeta(*provider) + "(:.*)?$"
+			filter = append(filter, bson.E{Key: "masterKey.provider", Value: bson.Regex{Pattern: pattern}})
+		}
+	}
+	return filter
 }
 
 // RemoveKeyAltName removes a keyAltName from the keyAltNames array of the key document in the key vault collection with
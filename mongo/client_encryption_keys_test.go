@@ -0,0 +1,114 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	mcopts "go.mongodb.org/mongo-driver/v2/x/mongo/driver/mongocrypt/options"
+)
+
+// dataKeyCrypt is a driver.Crypt that creates key documents without a KMS provider.
+type dataKeyCrypt struct {
+	driver.Crypt
+
+	mu       sync.Mutex
+	active   int
+	peak     int
+	created  int32
+	failAt   int32
+	altNames [][]string
+}
+
+func (c *dataKeyCrypt) CreateDataKey(_ context.Context, _ string, opts *mcopts.DataKeyOptions) (bsoncore.Document, error) {
+	c.mu.Lock()
+	c.active++
+	if c.active > c.peak {
+		c.peak = c.active
+	}
+	c.altNames = append(c.altNames, opts.KeyAltNames)
+	c.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	c.mu.Lock()
+	c.active--
+	c.mu.Unlock()
+
+	n := atomic.AddInt32(&c.created, 1)
+	if n == c.failAt {
+		return nil, errors.New("KMS request failed")
+	}
+	return bsoncore.NewDocumentBuilder().
+		AppendBinary("_id", bson.TypeBinaryUUID, []byte{byte(n)}).
+		Build(), nil
+}
+
+func TestClientEncryptionNewDataKeyDocuments(t *testing.T) {
+	newClientEncryption := func(crypt driver.Crypt) *ClientEncryption {
+		return &ClientEncryption{crypt: crypt, keyVaultClient: setupClient()}
+	}
+	args := func(concurrency int, rate float64) *options.CreateDataKeysOptions {
+		return &options.CreateDataKeysOptions{Concurrency: &concurrency, KMSRequestsPerSecond: &rate}
+	}
+
+	t.Run("creates keys concurrently", func(t *testing.T) {
+		crypt := &dataKeyCrypt{}
+		ce := newClientEncryption(crypt)
+
+		optsPerKey := func(i int) []options.Lister[options.DataKeyOptions] {
+			return []options.Lister[options.DataKeyOptions]{
+				options.DataKey().SetKeyAltNames([]string{"tenant"}),
+			}
+		}
+		docs, err := ce.newDataKeyDocuments(context.Background(), "local", 20, optsPerKey, args(4, 0))
+		require.NoError(t, err, "newDataKeyDocuments error: %v", err)
+
+		assert.Len(t, docs, 20, "expected a key document for each key")
+		for i, doc := range docs {
+			assert.NotNil(t, doc, "expected key document %d", i)
+		}
+		assert.True(t, crypt.peak <= 4, "expected at most 4 concurrent KMS requests, got %d", crypt.peak)
+		assert.Equal(t, []string{"tenant"}, crypt.altNames[0], "expected per-key options to be applied")
+	})
+	t.Run("rate limit", func(t *testing.T) {
+		ce := newClientEncryption(&dataKeyCrypt{})
+
+		start := time.Now()
+		_, err := ce.newDataKeyDocuments(context.Background(), "local", 5, nil, args(5, 100))
+		require.NoError(t, err, "newDataKeyDocuments error: %v", err)
+		assert.True(t, time.Since(start) >= 40*time.Millisecond,
+			"expected 5 keys at 100 requests per second to take at least 40ms, took %v", time.Since(start))
+	})
+	t.Run("stops at first error", func(t *testing.T) {
+		crypt := &dataKeyCrypt{failAt: 3}
+		ce := newClientEncryption(crypt)
+
+		docs, err := ce.newDataKeyDocuments(context.Background(), "local", 50, nil, args(1, 0))
+		assert.ErrorContains(t, err, "KMS request failed", "expected KMS error")
+		assert.Nil(t, docs, "expected no key documents")
+		assert.True(t, atomic.LoadInt32(&crypt.created) < 50, "expected key creation to stop after the error")
+	})
+	t.Run("closed", func(t *testing.T) {
+		ce := newClientEncryption(&dataKeyCrypt{})
+		ce.closed = true
+
+		_, err := ce.CreateDataKeys(context.Background(), "local", 1, nil)
+		assert.ErrorIs(t, err, ErrClientDisconnected, "expected ErrClientDisconnected")
+	})
+}
@@ -84,6 +84,11 @@ func TestClientEncryption_ErrClientDisconnected(t *testing.T) {
 		_, err := ce.GetKeys(context.Background())
 		assert.ErrorIs(t, err, ErrClientDisconnected)
 	})
+	t.Run("KeyUsage", func(t *testing.T) {
+		t.Parallel()
+		_, err := ce.KeyUsage(context.Background(), nil, nil)
+		assert.ErrorIs(t, err, ErrClientDisconnected)
+	})
 	t.Run("RemoveKeyAltName", func(t *testing.T) {
 		t.Parallel()
 		err := ce.RemoveKeyAltName(context.Background(), bson.Binary{}, "").err
@@ -0,0 +1,192 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// encryptedPayloadKeyIDSubtypes are the subtypes of the encrypted payloads (BSON binary subtype 6)
+// that store the UUID of their data key in bytes 1 to 16: the deterministic and random payloads of
+// Client-Side Field Level Encryption and the indexed and unindexed payloads of Queryable
+// Encryption.
+var encryptedPayloadKeyIDSubtypes = map[byte]bool{1: true, 2: true, 6: true, 7: true, 9: true, 14: true, 15: true, 16: true, 17: true}
+
+// KeyUsage describes the references to a data key found by ClientEncryption.KeyUsage.
+type KeyUsage struct {
+	// KeyID is the UUID of the data key.
+	KeyID bson.Binary
+
+	// Count is the number of encrypted values that reference the key.
+	Count int64
+
+	// Fields are the sorted paths of the fields with encrypted values that reference the key.
+	// Array indexes are omitted from the paths.
+	Fields []string
+}
+
+// KeyUsageResult is the result of ClientEncryption.KeyUsage.
+type KeyUsageResult struct {
+	// Documents is the number of documents scanned.
+	Documents int64
+
+	// Referenced are the keys referenced by the scanned documents.
+	Referenced []KeyUsage
+
+	// Unreferenced are the IDs of the keys in the key vault that no scanned document references.
+	// They are candidates for retirement if the scanned documents are all the documents encrypted
+	// with keys of the key vault.
+	Unreferenced []bson.Binary
+
+	// Missing are the IDs of the keys referenced by the scanned documents that are not in the key
+	// vault, whose values cannot be decrypted.
+	Missing []bson.Binary
+}
+
+// KeyUsage scans the documents of coll that match filter and reports which data keys of the key
+// vault their encrypted values reference, to support retiring the keys that are no longer used.
+// All documents are scanned if filter is nil. The keys of the key vault are those returned by
+// GetKeys with the given options.
+//
+// coll must be a collection of a Client without automatic encryption, because the encrypted values
+// read by a Client with automatic encryption are decrypted.
+func (ce *ClientEncryption) KeyUsage(
+	ctx context.Context,
+	coll *Collection,
+	filter interface{},
+	opts ...options.Lister[options.GetKeysOptions],
+) (*KeyUsageResult, error) {
+	if ce.closed {
+		return nil, ErrClientDisconnected
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	usage := make(map[string]*keyUsage)
+	result := &KeyUsageResult{}
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		result.Documents++
+		if err := collectKeyUsage(usage, "", cursor.Current); err != nil {
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	keys, err := ce.GetKeys(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer keys.Close(ctx)
+	inVault := make(map[string]bool)
+	for keys.Next(ctx) {
+		subtype, data, ok := keys.Current.Lookup("_id").BinaryOK()
+		if !ok {
+			continue
+		}
+		inVault[string(data)] = true
+		if _, ok := usage[string(data)]; !ok {
+			result.Unreferenced = append(result.Unreferenced, bson.Binary{Subtype: subtype, Data: data})
+		}
+	}
+	if err := keys.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, u := range usage {
+		keyID := bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte(id)}
+		fields := make([]string, 0, len(u.fields))
+		for field := range u.fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		result.Referenced = append(result.Referenced, KeyUsage{KeyID: keyID, Count: u.count, Fields: fields})
+		if !inVault[id] {
+			result.Missing = append(result.Missing, keyID)
+		}
+	}
+
+	sort.Slice(result.Referenced, func(i, j int) bool {
+		return bytes.Compare(result.Referenced[i].KeyID.Data, result.Referenced[j].KeyID.Data) < 0
+	})
+	sortKeyIDs(result.Unreferenced)
+	sortKeyIDs(result.Missing)
+	return result, nil
+}
+
+type keyUsage struct {
+	count  int64
+	fields map[string]struct{}
+}
+
+// collectKeyUsage adds the key references of the encrypted values in doc, the document at path,
+// to usage.
+func collectKeyUsage(usage map[string]*keyUsage, path string, doc bson.Raw) error {
+	elems, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		fieldPath := elem.Key()
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		if err := collectValueKeyUsage(usage, fieldPath, elem.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func collectValueKeyUsage(usage map[string]*keyUsage, path string, val bson.RawValue) error {
+	switch val.Type {
+	case bson.TypeEmbeddedDocument:
+		return collectKeyUsage(usage, path, val.Document())
+	case bson.TypeArray:
+		values, err := val.Array().Values()
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if err := collectValueKeyUsage(usage, path, v); err != nil {
+				return err
+			}
+		}
+	case bson.TypeBinary:
+		subtype, data := val.Binary()
+		if subtype != bson.TypeBinaryEncrypted || len(data) < 17 || !encryptedPayloadKeyIDSubtypes[data[0]] {
+			return nil
+		}
+		id := string(data[1:17])
+		u, ok := usage[id]
+		if !ok {
+			u = &keyUsage{fields: make(map[string]struct{})}
+			usage[id] = u
+		}
+		u.count++
+		u.fields[path] = struct{}{}
+	}
+	return nil
+}
+
+func sortKeyIDs(ids []bson.Binary) {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i].Data, ids[j].Data) < 0
+	})
+}
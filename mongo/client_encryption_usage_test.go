@@ -0,0 +1,118 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// newKeyUsageTestClientEncryption returns a ClientEncryption whose key vault client runs its
+// operations against d.
+func newKeyUsageTestClientEncryption(t *testing.T, d *mongotest.Deployment) (*mongo.ClientEncryption, *mongo.Client) {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return mongo.NewKeyVaultClientEncryption(client), client
+}
+
+func encryptedPayload(subtype byte, keyID byte) bson.Binary {
+	data := make([]byte, 30)
+	data[0] = subtype
+	for i := 1; i <= 16; i++ {
+		data[i] = keyID
+	}
+	return bson.Binary{Subtype: bson.TypeBinaryEncrypted, Data: data}
+}
+
+func keyUUID(b byte) bson.Binary {
+	data := make([]byte, 16)
+	for i := range data {
+		data[i] = b
+	}
+	return bson.Binary{Subtype: bson.TypeBinaryUUID, Data: data}
+}
+
+func TestClientEncryptionGetKeys(t *testing.T) {
+	t.Parallel()
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("find", mongotest.Cursor("keyvault.datakeys", 0), mongotest.Cursor("keyvault.datakeys", 0))
+	ce, _ := newKeyUsageTestClientEncryption(t, d)
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	opts := options.GetKeys().
+		SetKeyAltNamePrefix("tenant.").
+		SetCreatedAfter(after).
+		SetCreatedBefore(before).
+		SetMasterKeyProvider("aws")
+	cursor, err := ce.GetKeys(context.Background(), opts)
+	require.NoError(t, err, "GetKeys error")
+	require.NoError(t, cursor.Close(context.Background()), "Close error")
+
+	cursor, err = ce.GetKeys(context.Background(), options.GetKeys().SetMasterKeyProvider("aws:prod"))
+	require.NoError(t, err, "GetKeys error")
+	require.NoError(t, cursor.Close(context.Background()), "Close error")
+
+	cmds := d.Commands("find")
+	require.Len(t, cmds, 2, "expected two find commands")
+	filter := cmds[0].Document.Lookup("filter").Document()
+	pattern, _ := filter.Lookup("keyAltNames").Regex()
+	assert.Equal(t, `^tenant\.`, pattern, "key alt name pattern mismatch")
+	assert.Equal(t, after.UnixMilli(), filter.Lookup("creationDate", "$gte").DateTime(), "created after mismatch")
+	assert.Equal(t, before.UnixMilli(), filter.Lookup("creationDate", "$lt").DateTime(), "created before mismatch")
+	pattern, _ = filter.Lookup("masterKey.provider").Regex()
+	assert.Equal(t, `^aws(:.*)?$`, pattern, "provider pattern mismatch")
+
+	provider := cmds[1].Document.Lookup("filter", "masterKey.provider")
+	assert.Equal(t, "aws:prod", provider.StringValue(), "expected a named provider to match exactly")
+}
+
+func TestClientEncryptionKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("find", mongotest.Cursor("db.coll", 0,
+		bson.D{
+			{"_id", 1},
+			{"ssn", encryptedPayload(1, 0xa)},
+			{"cards", bson.A{bson.D{{"number", encryptedPayload(2, 0xb)}}, bson.D{{"number", encryptedPayload(2, 0xb)}}}},
+			{"plain", bson.Binary{Subtype: bson.TypeBinaryEncrypted, Data: []byte{0}}},
+		},
+		bson.D{{"_id", 2}, {"profile", bson.D{{"email", encryptedPayload(16, 0xa)}}}, {"legacy", encryptedPayload(14, 0xd)}},
+	), mongotest.Cursor("keyvault.datakeys", 0,
+		bson.D{{"_id", keyUUID(0xa)}},
+		bson.D{{"_id", keyUUID(0xb)}},
+		bson.D{{"_id", keyUUID(0xc)}},
+	))
+	ce, client := newKeyUsageTestClientEncryption(t, d)
+
+	coll := client.Database("db").Collection("coll")
+	res, err := ce.KeyUsage(context.Background(), coll, nil)
+	require.NoError(t, err, "KeyUsage error")
+
+	assert.Equal(t, int64(2), res.Documents, "documents mismatch")
+	want := []mongo.KeyUsage{
+		{KeyID: keyUUID(0xa), Count: 2, Fields: []string{"profile.email", "ssn"}},
+		{KeyID: keyUUID(0xb), Count: 2, Fields: []string{"cards.number"}},
+		{KeyID: keyUUID(0xd), Count: 1, Fields: []string{"legacy"}},
+	}
+	assert.Equal(t, want, res.Referenced, "referenced keys mismatch")
+	assert.Equal(t, []bson.Binary{keyUUID(0xc)}, res.Unreferenced, "unreferenced keys mismatch")
+	assert.Equal(t, []bson.Binary{keyUUID(0xd)}, res.Missing, "missing keys mismatch")
+}
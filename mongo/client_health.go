@@ -0,0 +1,122 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// HealthReport describes the state of the deployment as most recently observed by the monitoring
+// of a Client. See Client.Health.
+type HealthReport struct {
+	// TopologyType is the type of the deployment, such as "ReplicaSetWithPrimary", "Sharded", or
+	// "Unknown" if no server has been discovered yet.
+	TopologyType string
+
+	// Servers are the servers of the deployment, sorted by address.
+	Servers []ServerHealth
+
+	// Writable is whether a server that accepts writes, such as a primary, a standalone, or a
+	// mongos, is available.
+	Writable bool
+
+	// Readable is whether a data-bearing server, such as a primary or a secondary, is available.
+	Readable bool
+}
+
+// ServerHealth describes the state of a server in a HealthReport.
+type ServerHealth struct {
+	// Address is the address of the server.
+	Address address.Address
+
+	// Type is the type of the server, such as "RSPrimary", "RSSecondary", "Mongos", or "Unknown"
+	// if the server could not be reached.
+	Type string
+
+	// LastHeartbeat is the time the server description was last updated by a heartbeat. It is
+	// zero for load balancers, which are not monitored.
+	LastHeartbeat time.Time
+
+	// LastError is the error of the last heartbeat if it failed, or nil.
+	LastError error
+
+	// RTTMin, RTTAverage, and RTTP90 are the minimum, moving average, and 90th percentile of the
+	// round-trip times of the recent heartbeats. RTTMin and RTTP90 are computed from RTTSamples
+	// samples.
+	RTTMin     time.Duration
+	RTTAverage time.Duration
+	RTTP90     time.Duration
+	RTTSamples int
+
+	// OperationCount is the number of operations in progress on the server.
+	OperationCount int64
+}
+
+// Available is whether the server was reachable at its last heartbeat.
+func (sh ServerHealth) Available() bool {
+	return sh.Type != description.UnknownStr
+}
+
+// Health returns a report of the state of every server of the deployment as observed by the
+// monitoring of the Client, including the error of its last heartbeat and round-trip time
+// statistics, and whether a writable and a readable server are available. Unlike Ping, which runs
+// a command on a single selected server, Health does not send any command or wait for server
+// selection, so it is cheap enough to be used by readiness and liveness probes. Because it reports
+// the latest heartbeats, a server that just became unreachable can be reported as available for up
+// to the heartbeat interval.
+//
+// Health returns an error if ctx is done or the Client is not connected to a deployment.
+func (c *Client) Health(ctx context.Context) (*HealthReport, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return nil, errors.New("health report is only available for clients connected to a deployment")
+	}
+	return newHealthReport(topo.Description(), topo.ServerHealth()), nil
+}
+
+func newHealthReport(desc description.Topology, servers []topology.ServerHealth) *HealthReport {
+	report := &HealthReport{
+		TopologyType: desc.Kind.String(),
+		Servers:      make([]ServerHealth, 0, len(servers)),
+	}
+
+	for _, s := range servers {
+		d := s.Description
+		report.Servers = append(report.Servers, ServerHealth{
+			Address:        d.Addr,
+			Type:           d.Kind.String(),
+			LastHeartbeat:  d.LastUpdateTime,
+			LastError:      d.LastError,
+			RTTMin:         s.RTT.Min,
+			RTTAverage:     s.RTT.Average,
+			RTTP90:         s.RTT.P90,
+			RTTSamples:     s.RTT.Samples,
+			OperationCount: s.OperationCount,
+		})
+
+		switch d.Kind {
+		case description.ServerKindStandalone, description.ServerKindRSPrimary,
+			description.ServerKindMongos, description.ServerKindLoadBalancer:
+			report.Writable = true
+			report.Readable = true
+		case description.ServerKindRSSecondary:
+			report.Readable = true
+		}
+	}
+	return report
+}
@@ -0,0 +1,93 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+func TestClientHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("report", func(t *testing.T) {
+		t.Parallel()
+
+		heartbeat := time.Now()
+		heartbeatErr := errors.New("connection refused")
+		report := newHealthReport(
+			description.Topology{Kind: description.TopologyKindReplicaSetNoPrimary},
+			[]topology.ServerHealth{
+				{
+					Description: description.Server{Addr: "a:27017", Kind: description.ServerKindRSSecondary, LastUpdateTime: heartbeat},
+					RTT:         topology.RTTStats{Min: time.Millisecond, Average: 2 * time.Millisecond, P90: 3 * time.Millisecond, Samples: 10},
+				},
+				{
+					Description:    description.Server{Addr: "b:27017", LastError: heartbeatErr},
+					OperationCount: 2,
+				},
+			},
+		)
+
+		assert.Equal(t, "ReplicaSetNoPrimary", report.TopologyType, "topology type mismatch")
+		assert.False(t, report.Writable, "expected no writable server")
+		assert.True(t, report.Readable, "expected a readable server")
+		require.Len(t, report.Servers, 2, "servers length mismatch")
+
+		a, b := report.Servers[0], report.Servers[1]
+		assert.True(t, a.Available(), "expected %v to be available", a.Address)
+		assert.Equal(t, "RSSecondary", a.Type, "server type mismatch")
+		assert.Equal(t, heartbeat, a.LastHeartbeat, "last heartbeat mismatch")
+		assert.Equal(t, 3*time.Millisecond, a.RTTP90, "RTT P90 mismatch")
+		assert.Equal(t, 10, a.RTTSamples, "RTT samples mismatch")
+		assert.False(t, b.Available(), "expected %v to be unavailable", b.Address)
+		assert.Equal(t, heartbeatErr, b.LastError, "last error mismatch")
+		assert.Equal(t, int64(2), b.OperationCount, "operation count mismatch")
+	})
+
+	t.Run("writable", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kind := range []description.ServerKind{
+			description.ServerKindStandalone,
+			description.ServerKindRSPrimary,
+			description.ServerKindMongos,
+			description.ServerKindLoadBalancer,
+		} {
+			report := newHealthReport(description.Topology{}, []topology.ServerHealth{
+				{Description: description.Server{Addr: "a:27017", Kind: kind}},
+			})
+			assert.True(t, report.Writable, "expected %v to be writable", kind)
+			assert.True(t, report.Readable, "expected %v to be readable", kind)
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := setupClient().Health(ctx)
+		assert.True(t, errors.Is(err, context.Canceled), "expected Canceled, got %v", err)
+	})
+
+	t.Run("disconnected", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := setupClient().Health(context.Background())
+		require.NoError(t, err, "Health error")
+		assert.Equal(t, "Unknown", report.TopologyType, "topology type mismatch")
+		assert.False(t, report.Writable, "expected no writable server")
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+var errResetUnsupported = errors.New("reset is only supported for clients connected to a deployment")
+
+// Reset closes all pooled and monitoring connections of the Client and restarts the discovery of
+// the deployment from the hosts of the Client options, without disconnecting the Client. The
+// Databases, Collections, and other resources created from the Client remain valid and use the new
+// connections. Reset also discards the idle server sessions of the Client, so that the sessions
+// of the Client are not used concurrently by another process.
+//
+// Reset is intended for use after the connections of the Client became unusable without being
+// closed, such as in the child of a process fork, or after the network of the process changed,
+// e.g. because a VPN reconnected. Operations that are in progress when Reset is called may fail
+// with a network error. Operations started after Reset returns wait for the deployment to be
+// discovered again, up to the server selection timeout.
+//
+// Reset returns an error if the Client is disconnected.
+func (c *Client) Reset(ctx context.Context) error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return errResetUnsupported
+	}
+	if err := topo.Reset(ctx); err != nil {
+		return replaceErrors(err)
+	}
+	if c.sessionPool != nil {
+		c.sessionPool.Clear()
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestClient_Reset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("topology", func(t *testing.T) {
+		t.Parallel()
+
+		client := setupClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+		require.NotNil(t, client, "expected client")
+		coll := client.Database("db").Collection("coll")
+
+		sess, err := client.sessionPool.GetSession()
+		require.NoError(t, err, "GetSession error")
+		client.sessionPool.ReturnSession(sess)
+
+		assert.NoError(t, client.Reset(context.Background()), "Reset error")
+		assert.Equal(t, 0, client.sessionPool.Stats().Idle, "expected idle sessions to be discarded")
+		assert.Equal(t, client, coll.Database().Client(), "expected the collection to keep its client")
+
+		require.NoError(t, client.Disconnect(context.Background()), "Disconnect error")
+		assert.Error(t, client.Reset(context.Background()), "expected Reset error after Disconnect")
+	})
+
+	t.Run("other deployment", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{}
+		assert.Error(t, client.Reset(context.Background()), "expected Reset error")
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+var errSuspendUnsupported = errors.New("suspend and resume are only supported for clients connected to a deployment")
+
+// Suspend closes the idle pooled connections and the monitoring connections of the Client and
+// stops monitoring the deployment, without disconnecting the Client. Connections that are in use
+// are closed when they are returned to the pool. The Client resumes transparently when the next
+// operation checks out a connection, which opens new connections as needed, or when Resume is
+// called.
+//
+// Suspend is intended for function-as-a-service environments such as AWS Lambda, in which the
+// process is frozen between invocations. Sockets kept open while the process is frozen are often
+// closed by the network or the server, which causes errors on the first operations of the next
+// invocation. Call Suspend before the handler returns, e.g. with defer, and keep using the same
+// Client in the next invocation.
+//
+// Until the Client is resumed, server selection uses the last known description of the
+// deployment, and the SRV records of a mongodb+srv URI keep being polled.
+func (c *Client) Suspend() error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return errSuspendUnsupported
+	}
+	topo.Hibernate()
+	return nil
+}
+
+// Resume restarts the monitoring of a Client suspended with Suspend, so that the description of
+// the deployment is refreshed before the next operation runs. Calling Resume is optional because
+// operations resume the servers they use. Resume does nothing if the Client is not suspended.
+func (c *Client) Resume() error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return errSuspendUnsupported
+	}
+	topo.Wake()
+	return nil
+}
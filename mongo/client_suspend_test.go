@@ -0,0 +1,39 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestClient_Suspend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("topology", func(t *testing.T) {
+		t.Parallel()
+
+		client := setupClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+		require.NotNil(t, client, "expected client")
+
+		assert.NoError(t, client.Suspend(), "Suspend error")
+		assert.NoError(t, client.Suspend(), "second Suspend error")
+		assert.NoError(t, client.Resume(), "Resume error")
+		assert.NoError(t, client.Resume(), "second Resume error")
+	})
+
+	t.Run("other deployment", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{}
+		assert.Error(t, client.Suspend(), "expected Suspend error")
+		assert.Error(t, client.Resume(), "expected Resume error")
+	})
+}
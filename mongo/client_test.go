@@ -112,7 +112,7 @@ func TestClient(t *testing.T) {
 				},
 			}
 			cs := "mongodb://localhost:27017/"
-			cs += "?readpreference=secondary&readPreferenceTags=one:1&readPreferenceTags=two:2&maxStaleness=5"
+			cs += "?readpreference=secondary&readPreferenceTags=one:1&readPreferenceTags=two:2&maxStaleness=100"
 
 			client := setupClient(options.Client().ApplyURI(cs))
 			gotMode := client.readPreference.Mode()
@@ -121,7 +121,7 @@ func TestClient(t *testing.T) {
 			assert.Equal(t, gotTags, tags, "expected tags %v, got %v", tags, gotTags)
 			gotStaleness, flag := client.readPreference.MaxStaleness()
 			assert.True(t, flag, "expected max staleness to be set but was not")
-			wantStaleness := time.Duration(5) * time.Second
+			wantStaleness := time.Duration(100) * time.Second
 			assert.Equal(t, gotStaleness, wantStaleness, "expected staleness %v, got %v", wantStaleness, gotStaleness)
 		})
 	})
@@ -41,6 +41,9 @@ type Collection struct {
 	writeSelector  description.ServerSelector
 	bsonOpts       *options.BSONOptions
 	registry       *bson.Registry
+	commandCache   *operation.CommandCache
+	etag           *etagConfig
+	transforms     *fieldTransformers
 }
 
 // aggregateParams is used to store information to configure an Aggregate operation.
@@ -119,6 +122,9 @@ func newCollection(db *Database, name string, opts ...options.Lister[options.Col
 		writeSelector:  writeSelector,
 		bsonOpts:       bsonOpts,
 		registry:       reg,
+		commandCache:   operation.NewCommandCache(),
+		etag:           newETagConfig(args.ETag),
+		transforms:     newFieldTransformers(args.Transformers),
 	}
 
 	return coll
@@ -135,6 +141,9 @@ func (coll *Collection) copy() *Collection {
 		readSelector:   coll.readSelector,
 		writeSelector:  coll.writeSelector,
 		registry:       coll.registry,
+		commandCache:   coll.commandCache,
+		etag:           coll.etag,
+		transforms:     coll.transforms,
 	}
 }
 
@@ -162,6 +171,14 @@ func (coll *Collection) Clone(opts ...options.Lister[options.CollectionOptions])
 		copyColl.registry = args.Registry
 	}
 
+	if args.ETag != nil {
+		copyColl.etag = newETagConfig(args.ETag)
+	}
+
+	if args.Transformers != nil {
+		copyColl.transforms = newFieldTransformers(args.Transformers)
+	}
+
 	copyColl.readSelector = &serverselector.Composite{
 		Selectors: []description.ServerSelector{
 			&serverselector.ReadPref{ReadPref: copyColl.readPreference},
@@ -211,9 +228,16 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	// Ensure opts have the default case at the front.
+	opts = append([]options.Lister[options.BulkWriteOptions]{options.BulkWrite()}, opts...)
+	args, err := mongoutil.NewOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	wc, err := operationWriteConcern(sess, coll.writeConcern, args.WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 	if !wc.Acknowledged() {
 		sess = nil
@@ -226,11 +250,7 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 			return nil, ErrNilDocument
 		}
 	}
-
-	// Ensure opts have the default case at the front.
-	opts = append([]options.Lister[options.BulkWriteOptions]{options.BulkWrite()}, opts...)
-	args, err := mongoutil.NewOptions(opts...)
-	if err != nil {
+	if err := validateUpdateModels(models, coll.bsonOpts, coll.registry); err != nil {
 		return nil, err
 	}
 
@@ -261,11 +281,16 @@ func (coll *Collection) insert(
 		ctx = context.Background()
 	}
 
+	args, err := mongoutil.NewOptions[options.InsertManyOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
 	result := make([]interface{}, len(documents))
 	docs := make([]bsoncore.Document, len(documents))
 
 	for i, doc := range documents {
-		bsoncoreDoc, err := marshal(doc, coll.bsonOpts, coll.registry)
+		bsoncoreDoc, err := marshalDocument(doc, args.RawDocuments, coll.bsonOpts, coll.registry)
 		if err != nil {
 			return nil, err
 		}
@@ -273,6 +298,14 @@ func (coll *Collection) insert(
 		if err != nil {
 			return nil, err
 		}
+		bsoncoreDoc, err = coll.etag.stamp(bsoncoreDoc)
+		if err != nil {
+			return nil, err
+		}
+		bsoncoreDoc, err = coll.transforms.apply(bsoncoreDoc, coll.bsonOpts, coll.registry)
+		if err != nil {
+			return nil, err
+		}
 
 		docs[i] = bsoncoreDoc
 		result[i] = id
@@ -284,14 +317,14 @@ func (coll *Collection) insert(
 		defer sess.EndSession()
 	}
 
-	err := coll.client.validSession(sess)
+	err = coll.client.validSession(sess)
 	if err != nil {
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := operationWriteConcern(sess, coll.writeConcern, args.WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 	if !wc.Acknowledged() {
 		sess = nil
@@ -304,12 +337,7 @@ func (coll *Collection) insert(
 		ServerSelector(selector).ClusterClock(coll.client.clock).
 		Database(coll.db.name).Collection(coll.name).
 		Deployment(coll.client.deployment).Crypt(coll.client.cryptFLE).Ordered(true).
-		ServerAPI(coll.client.serverAPI).Timeout(coll.client.timeout).Logger(coll.client.logger).Authenticator(coll.client.authenticator)
-
-	args, err := mongoutil.NewOptions[options.InsertManyOptions](opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
-	}
+		CommandCache(coll.commandCache).ServerAPI(coll.client.serverAPI).Timeout(coll.client.timeout).Logger(coll.client.logger).Authenticator(coll.client.authenticator)
 
 	if args.BypassDocumentValidation != nil && *args.BypassDocumentValidation {
 		op = op.BypassDocumentValidation(*args.BypassDocumentValidation)
@@ -325,7 +353,7 @@ func (coll *Collection) insert(
 		op = op.Ordered(*args.Ordered)
 	}
 	retry := driver.RetryNone
-	if coll.client.retryWrites {
+	if coll.client.retryWritesEnabled() {
 		retry = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retry)
@@ -375,6 +403,12 @@ func (coll *Collection) InsertOne(ctx context.Context, document interface{},
 	if args.Comment != nil {
 		imOpts.SetComment(args.Comment)
 	}
+	if args.RawDocuments != nil {
+		imOpts.SetRawDocuments(*args.RawDocuments)
+	}
+	if args.WriteConcern != nil {
+		imOpts.SetWriteConcern(args.WriteConcern)
+	}
 	res, err := coll.insert(ctx, []interface{}{document}, imOpts)
 
 	rr, err := processWriteError(err)
@@ -436,9 +470,13 @@ func (coll *Collection) InsertMany(
 	// create and return a BulkWriteException
 	bwErrors := make([]BulkWriteError, 0, len(writeException.WriteErrors))
 	for _, we := range writeException.WriteErrors {
+		var request WriteModel
+		if we.Index >= 0 && we.Index < len(docSlice) {
+			request = NewInsertOneModel().SetDocument(docSlice[we.Index])
+		}
 		bwErrors = append(bwErrors, BulkWriteError{
 			WriteError: we,
-			Request:    nil,
+			Request:    request,
 		})
 	}
 
@@ -477,9 +515,9 @@ func (coll *Collection) delete(
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := operationWriteConcern(sess, coll.writeConcern, args.WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 	if !wc.Acknowledged() {
 		sess = nil
@@ -537,7 +575,7 @@ func (coll *Collection) delete(
 
 	// deleteMany cannot be retried
 	retryMode := driver.RetryNone
-	if deleteOne && coll.client.retryWrites {
+	if deleteOne && coll.client.retryWritesEnabled() {
 		retryMode = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retryMode)
@@ -571,10 +609,11 @@ func (coll *Collection) DeleteOne(
 		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
 	}
 	deleteOptions := &options.DeleteManyOptions{
-		Collation: args.Collation,
-		Comment:   args.Comment,
-		Hint:      args.Hint,
-		Let:       args.Let,
+		Collation:    args.Collation,
+		Comment:      args.Comment,
+		Hint:         args.Hint,
+		Let:          args.Let,
+		WriteConcern: args.WriteConcern,
 	}
 
 	return coll.delete(ctx, filter, true, rrOne, deleteOptions)
@@ -628,6 +667,7 @@ func (coll *Collection) updateOrReplace(
 		upsert:         args.Upsert,
 		multi:          multi,
 		checkDollarKey: checkDollarKey,
+		transforms:     coll.transforms,
 	}.marshal(coll.bsonOpts, coll.registry)
 	if err != nil {
 		return nil, err
@@ -644,9 +684,9 @@ func (coll *Collection) updateOrReplace(
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := operationWriteConcern(sess, coll.writeConcern, args.WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 	if !wc.Acknowledged() {
 		sess = nil
@@ -659,7 +699,9 @@ func (coll *Collection) updateOrReplace(
 		ServerSelector(selector).ClusterClock(coll.client.clock).
 		Database(coll.db.name).Collection(coll.name).
 		Deployment(coll.client.deployment).Crypt(coll.client.cryptFLE).Hint(args.Hint != nil).
-		ArrayFilters(args.ArrayFilters != nil).Ordered(true).ServerAPI(coll.client.serverAPI).
+		ArrayFilters(args.ArrayFilters != nil).Pipeline(updateDoc.Lookup("u").Type == bsoncore.TypeArray).
+		Ordered(true).CommandCache(coll.commandCache).
+		ServerAPI(coll.client.serverAPI).
 		Timeout(coll.client.timeout).Logger(coll.client.logger).Authenticator(coll.client.authenticator)
 	if args.Let != nil {
 		let, err := marshal(args.Let, coll.bsonOpts, coll.registry)
@@ -681,7 +723,7 @@ func (coll *Collection) updateOrReplace(
 	}
 	retry := driver.RetryNone
 	// retryable writes are only enabled updateOne/replaceOne operations
-	if !multi && coll.client.retryWrites {
+	if !multi && coll.client.retryWritesEnabled() {
 		retry = driver.RetryOncePerCommand
 	}
 	op = op.Retry(retry)
@@ -773,6 +815,7 @@ func (coll *Collection) UpdateOne(
 		Hint:                     args.Hint,
 		Upsert:                   args.Upsert,
 		Let:                      args.Let,
+		WriteConcern:             args.WriteConcern,
 	}
 
 	return coll.updateOrReplace(ctx, f, update, false, rrOne, true, updateOptions)
@@ -847,7 +890,7 @@ func (coll *Collection) ReplaceOne(
 		return nil, err
 	}
 
-	r, err := marshal(replacement, coll.bsonOpts, coll.registry)
+	r, err := marshalDocument(replacement, args.RawDocuments, coll.bsonOpts, coll.registry)
 	if err != nil {
 		return nil, err
 	}
@@ -855,6 +898,9 @@ func (coll *Collection) ReplaceOne(
 	if err := ensureNoDollarKey(r); err != nil {
 		return nil, err
 	}
+	if r, err = coll.etag.stamp(r); err != nil {
+		return nil, err
+	}
 
 	updateOptions := &options.UpdateManyOptions{
 		BypassDocumentValidation: args.BypassDocumentValidation,
@@ -863,6 +909,7 @@ func (coll *Collection) ReplaceOne(
 		Hint:                     args.Hint,
 		Let:                      args.Let,
 		Comment:                  args.Comment,
+		WriteConcern:             args.WriteConcern,
 	}
 
 	return coll.updateOrReplace(ctx, f, r, false, rrOne, false, updateOptions)
@@ -927,29 +974,34 @@ func aggregate(a aggregateParams, opts ...options.Lister[options.AggregateOption
 	if err = a.client.validSession(sess); err != nil {
 		return nil, err
 	}
+	if err = checkOutputStages(pipelineArr, sess); err != nil {
+		return nil, err
+	}
+
+	args, err := mongoutil.NewOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	var wc *writeconcern.WriteConcern
 	if hasOutputStage {
-		wc = a.writeConcern
+		if wc, err = operationWriteConcern(sess, a.writeConcern, args.WriteConcern); err != nil {
+			return nil, err
+		}
 	}
-	rc := a.readConcern
-	if sess.TransactionRunning() {
-		wc = nil
-		rc = nil
+	rc, err := operationReadConcern(sess, a.readConcern, args.ReadConcern)
+	if err != nil {
+		return nil, err
 	}
 	if !wc.Acknowledged() {
 		closeImplicitSession(sess)
 		sess = nil
 	}
 
-	selector := makeReadPrefSelector(sess, a.readSelector, a.client.localThreshold)
+	readSelector, localThreshold := latencyWindow(a.readSelector, a.readPreference, a.client.localThreshold, args.LocalThreshold)
+	selector := makeReadPrefSelector(sess, readSelector, localThreshold)
 	if hasOutputStage {
-		selector = makeOutputAggregateSelector(sess, a.readPreference, a.client.localThreshold)
-	}
-
-	args, err := mongoutil.NewOptions(opts...)
-	if err != nil {
-		return nil, err
+		selector = makeOutputAggregateSelector(sess, a.readPreference, localThreshold)
 	}
 
 	cursorOpts := a.client.createBaseCursorOptions()
@@ -1053,7 +1105,7 @@ func aggregate(a aggregateParams, opts ...options.Lister[options.AggregateOption
 	if err != nil {
 		return nil, replaceErrors(err)
 	}
-	cursor, err := newCursorWithSession(bc, a.client.bsonOpts, a.registry, sess)
+	cursor, err := newCursorWithSession(a.ctx, bc, a.client.bsonOpts, a.registry, sess, a.client.cursorMemory, a.client.cursorLeaks)
 	return cursor, replaceErrors(err)
 }
 
@@ -1090,12 +1142,13 @@ func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 		return 0, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := operationReadConcern(sess, coll.readConcern, args.ReadConcern)
+	if err != nil {
+		return 0, err
 	}
 
-	selector := makeReadPrefSelector(sess, coll.readSelector, coll.client.localThreshold)
+	readSelector, localThreshold := latencyWindow(coll.readSelector, coll.readPreference, coll.client.localThreshold, args.LocalThreshold)
+	selector := makeReadPrefSelector(sess, readSelector, localThreshold)
 	op := operation.NewAggregate(pipelineArr).Session(sess).ReadConcern(rc).ReadPreference(coll.readPreference).
 		CommandMonitor(coll.client.monitor).ServerSelector(selector).ClusterClock(coll.client.clock).Database(coll.db.name).
 		Collection(coll.name).Deployment(coll.client.deployment).Crypt(coll.client.cryptFLE).ServerAPI(coll.client.serverAPI).
@@ -1121,6 +1174,13 @@ func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 		}
 		op.Hint(hintVal)
 	}
+	if args.Let != nil {
+		let, err := marshal(args.Let, coll.bsonOpts, coll.registry)
+		if err != nil {
+			return 0, err
+		}
+		op.Let(let)
+	}
 	retry := driver.RetryNone
 	if coll.client.retryReads {
 		retry = driver.RetryOncePerCommand
@@ -1151,7 +1211,8 @@ func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 }
 
 // EstimatedDocumentCount executes a count command and returns an estimate of the number of documents in the collection
-// using collection metadata.
+// using collection metadata. The StaleFallback option can be used to count the documents with CountDocuments instead if
+// the metadata appears to be stale.
 //
 // The opts parameter can be used to specify options for the operation (see the options.EstimatedDocumentCountOptions
 // documentation).
@@ -1178,17 +1239,18 @@ func (coll *Collection) EstimatedDocumentCount(
 		return 0, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
-	}
-
 	args, err := mongoutil.NewOptions[options.EstimatedDocumentCountOptions](opts...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to construct options from builder: %w", err)
 	}
 
-	selector := makeReadPrefSelector(sess, coll.readSelector, coll.client.localThreshold)
+	rc, err := operationReadConcern(sess, coll.readConcern, args.ReadConcern)
+	if err != nil {
+		return 0, err
+	}
+
+	readSelector, localThreshold := latencyWindow(coll.readSelector, coll.readPreference, coll.client.localThreshold, args.LocalThreshold)
+	selector := makeReadPrefSelector(sess, readSelector, localThreshold)
 	op := operation.NewCount().Session(sess).ClusterClock(coll.client.clock).
 		Database(coll.db.name).Collection(coll.name).CommandMonitor(coll.client.monitor).
 		Deployment(coll.client.deployment).ReadConcern(rc).ReadPreference(coll.readPreference).
@@ -1210,7 +1272,10 @@ func (coll *Collection) EstimatedDocumentCount(
 	op.Retry(retry)
 
 	err = op.Execute(ctx)
-	return op.Result().N, replaceErrors(err)
+	if err != nil || args.StaleFallback == nil || !*args.StaleFallback {
+		return op.Result().N, replaceErrors(err)
+	}
+	return coll.checkEstimatedCount(ctx, op.Result().N, args)
 }
 
 // Distinct executes a distinct command to find the unique values for a specified field in the collection.
@@ -1250,13 +1315,6 @@ func (coll *Collection) Distinct(
 		return &DistinctResult{err: err}
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
-	}
-
-	selector := makeReadPrefSelector(sess, coll.readSelector, coll.client.localThreshold)
-
 	args, err := mongoutil.NewOptions[options.DistinctOptions](opts...)
 	if err != nil {
 		err = fmt.Errorf("failed to construct options from builder: %w", err)
@@ -1264,6 +1322,14 @@ func (coll *Collection) Distinct(
 		return &DistinctResult{err: err}
 	}
 
+	rc, err := operationReadConcern(sess, coll.readConcern, args.ReadConcern)
+	if err != nil {
+		return &DistinctResult{err: err}
+	}
+
+	readSelector, localThreshold := latencyWindow(coll.readSelector, coll.readPreference, coll.client.localThreshold, args.LocalThreshold)
+	selector := makeReadPrefSelector(sess, readSelector, localThreshold)
+
 	op := operation.NewDistinct(fieldName, f).
 		Session(sess).ClusterClock(coll.client.clock).
 		Database(coll.db.name).Collection(coll.name).CommandMonitor(coll.client.monitor).
@@ -1360,12 +1426,13 @@ func (coll *Collection) find(
 		return nil, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := operationReadConcern(sess, coll.readConcern, args.ReadConcern)
+	if err != nil {
+		return nil, err
 	}
 
-	selector := makeReadPrefSelector(sess, coll.readSelector, coll.client.localThreshold)
+	readSelector, localThreshold := latencyWindow(coll.readSelector, coll.readPreference, coll.client.localThreshold, args.LocalThreshold)
+	selector := makeReadPrefSelector(sess, readSelector, localThreshold)
 	op := operation.NewFind(f).
 		Session(sess).ReadConcern(rc).ReadPreference(coll.readPreference).
 		CommandMonitor(coll.client.monitor).ServerSelector(selector).
@@ -1495,7 +1562,18 @@ func (coll *Collection) find(
 	if err != nil {
 		return nil, replaceErrors(err)
 	}
-	return newCursorWithSession(bc, coll.bsonOpts, coll.registry, sess)
+	cur, err = newCursorWithSession(ctx, bc, coll.bsonOpts, coll.registry, sess, coll.client.cursorMemory, coll.client.cursorLeaks)
+	if err != nil {
+		return nil, err
+	}
+	cur.transforms = coll.transforms
+	if cur.ID() != 0 {
+		cur.failover = coll.newCursorFailover(ctx, f, omitMaxTimeMS, args)
+	}
+	if args.CursorType != nil && *args.CursorType == options.TailableAwait {
+		cur.coalesce = newBatchCoalescer(args.BatchCoalescing, args.MaxAwaitTime)
+	}
+	return cur, nil
 }
 
 func newFindArgsFromFindOneArgs(args *options.FindOneOptions) *options.FindOptions {
@@ -1506,6 +1584,7 @@ func newFindArgsFromFindOneArgs(args *options.FindOneOptions) *options.FindOptio
 		v.Collation = args.Collation
 		v.Comment = args.Comment
 		v.Hint = args.Hint
+		v.Let = args.Let
 		v.Max = args.Max
 		v.Min = args.Min
 		v.Projection = args.Projection
@@ -1513,6 +1592,8 @@ func newFindArgsFromFindOneArgs(args *options.FindOneOptions) *options.FindOptio
 		v.ShowRecordID = args.ShowRecordID
 		v.Skip = args.Skip
 		v.Sort = args.Sort
+		v.ReadConcern = args.ReadConcern
+		v.LocalThreshold = args.LocalThreshold
 	}
 	return v
 }
@@ -1539,15 +1620,20 @@ func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 	}
 	cursor, err := coll.find(ctx, filter, false, newFindArgsFromFindOneArgs(args))
 	return &SingleResult{
-		ctx:      ctx,
-		cur:      cursor,
-		bsonOpts: coll.bsonOpts,
-		reg:      coll.registry,
-		err:      replaceErrors(err),
+		ctx:        ctx,
+		cur:        cursor,
+		bsonOpts:   coll.bsonOpts,
+		reg:        coll.registry,
+		transforms: coll.transforms,
+		err:        replaceErrors(err),
 	}
 }
 
-func (coll *Collection) findAndModify(ctx context.Context, op *operation.FindAndModify) *SingleResult {
+func (coll *Collection) findAndModify(
+	ctx context.Context,
+	op *operation.FindAndModify,
+	wcOverride *writeconcern.WriteConcern,
+) *SingleResult {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -1564,9 +1650,9 @@ func (coll *Collection) findAndModify(ctx context.Context, op *operation.FindAnd
 		return &SingleResult{err: err}
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := operationWriteConcern(sess, coll.writeConcern, wcOverride)
+	if err != nil {
+		return &SingleResult{err: err}
 	}
 	if !wc.Acknowledged() {
 		sess = nil
@@ -1575,7 +1661,7 @@ func (coll *Collection) findAndModify(ctx context.Context, op *operation.FindAnd
 	selector := makePinnedSelector(sess, coll.writeSelector)
 
 	retry := driver.RetryNone
-	if coll.client.retryWrites {
+	if coll.client.retryWritesEnabled() {
 		retry = driver.RetryOnce
 	}
 
@@ -1600,6 +1686,7 @@ func (coll *Collection) findAndModify(ctx context.Context, op *operation.FindAnd
 		rdr:          bson.Raw(op.Result().Value),
 		bsonOpts:     coll.bsonOpts,
 		reg:          coll.registry,
+		transforms:   coll.transforms,
 		Acknowledged: rr.isAcknowledged(),
 	}
 }
@@ -1676,7 +1763,7 @@ func (coll *Collection) FindOneAndDelete(
 		op = op.Let(let)
 	}
 
-	return coll.findAndModify(ctx, op)
+	return coll.findAndModify(ctx, op, args.WriteConcern)
 }
 
 // FindOneAndReplace executes a findAndModify command to replace at most one document in the collection
@@ -1711,6 +1798,12 @@ func (coll *Collection) FindOneAndReplace(
 	if firstElem, err := r.IndexErr(0); err == nil && strings.HasPrefix(firstElem.Key(), "$") {
 		return &SingleResult{err: errors.New("replacement document cannot contain keys beginning with '$'")}
 	}
+	if r, err = coll.etag.stamp(r); err != nil {
+		return &SingleResult{err: err}
+	}
+	if r, err = coll.transforms.apply(r, coll.bsonOpts, coll.registry); err != nil {
+		return &SingleResult{err: err}
+	}
 
 	args, err := mongoutil.NewOptions[options.FindOneAndReplaceOptions](opts...)
 	if err != nil {
@@ -1773,7 +1866,7 @@ func (coll *Collection) FindOneAndReplace(
 		op = op.Let(let)
 	}
 
-	return coll.findAndModify(ctx, op)
+	return coll.findAndModify(ctx, op, args.WriteConcern)
 }
 
 // FindOneAndUpdate executes a findAndModify command to update at most one document in the collection and returns the
@@ -1817,6 +1910,9 @@ func (coll *Collection) FindOneAndUpdate(
 	if err != nil {
 		return &SingleResult{err: err}
 	}
+	if u, err = coll.transforms.applyUpdate(u, coll.bsonOpts, coll.registry); err != nil {
+		return &SingleResult{err: err}
+	}
 	op = op.Update(u)
 
 	if args.ArrayFilters != nil {
@@ -1882,7 +1978,7 @@ func (coll *Collection) FindOneAndUpdate(
 		op = op.Let(let)
 	}
 
-	return coll.findAndModify(ctx, op)
+	return coll.findAndModify(ctx, op, args.WriteConcern)
 }
 
 // Watch returns a change stream for all changes on the corresponding collection. See
@@ -2112,6 +2208,27 @@ func makePinnedSelector(sess *session.Client, fallback description.ServerSelecto
 	return pss
 }
 
+// latencyWindow returns selector and localThreshold, the read selector and latency window of an
+// operation, or the read selector of rp with the latency window of override if it is set.
+func latencyWindow(
+	selector description.ServerSelector,
+	rp *readpref.ReadPref,
+	localThreshold time.Duration,
+	override *time.Duration,
+) (description.ServerSelector, time.Duration) {
+	if override == nil {
+		return selector, localThreshold
+	}
+
+	selector = &serverselector.Composite{
+		Selectors: []description.ServerSelector{
+			&serverselector.ReadPref{ReadPref: rp},
+			&serverselector.Latency{Latency: *override},
+		},
+	}
+	return selector, *override
+}
+
 func makeReadPrefSelector(
 	sess *session.Client,
 	selector description.ServerSelector,
@@ -2149,6 +2266,46 @@ func makeOutputAggregateSelector(
 	return makePinnedSelector(sess, selector)
 }
 
+// operationReadConcern returns the read concern for an operation that runs in sess: override,
+// the read concern from the options of the operation, if it is set, or def otherwise. Operations
+// in a transaction use the read concern of the transaction, so setting override for them is an
+// error.
+func operationReadConcern(
+	sess *session.Client,
+	def, override *readconcern.ReadConcern,
+) (*readconcern.ReadConcern, error) {
+	if sess.TransactionRunning() {
+		if override != nil {
+			return nil, ErrConcernInTransaction
+		}
+		return nil, nil
+	}
+	if override != nil {
+		return override, nil
+	}
+	return def, nil
+}
+
+// operationWriteConcern returns the write concern for an operation that runs in sess: override,
+// the write concern from the options of the operation, if it is set, or def otherwise. Operations
+// in a transaction use the write concern of the transaction, so setting override for them is an
+// error.
+func operationWriteConcern(
+	sess *session.Client,
+	def, override *writeconcern.WriteConcern,
+) (*writeconcern.WriteConcern, error) {
+	if sess.TransactionRunning() {
+		if override != nil {
+			return nil, ErrConcernInTransaction
+		}
+		return nil, nil
+	}
+	if override != nil {
+		return override, nil
+	}
+	return def, nil
+}
+
 // isUnorderedMap returns true if val is a map with more than 1 element. It is typically used to
 // check for unordered Go values that are used in nested command documents where different field
 // orders mean different things. Examples are the "sort" and "hint" fields.
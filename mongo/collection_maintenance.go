@@ -0,0 +1,358 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+// ErrMaintenanceNotAllowed is returned by Compact and ReIndex if the server selected by the read
+// preference of the collection cannot run the command safely.
+var ErrMaintenanceNotAllowed = errors.New("maintenance command not allowed on the selected server")
+
+// CompactResult is the result of a Compact operation.
+type CompactResult struct {
+	// Server is the address of the server that ran the command.
+	Server address.Address
+
+	// BytesFreed is the amount of storage space freed by the compaction.
+	BytesFreed int64
+
+	// EstimatedBytesFreed is the amount of storage space that compaction would free. It is only
+	// set for dry runs.
+	EstimatedBytesFreed int64
+}
+
+// ReIndexResult is the result of a ReIndex operation.
+type ReIndexResult struct {
+	// Server is the address of the server that ran the command.
+	Server address.Address
+
+	// IndexesBefore and IndexesAfter are the number of indexes of the collection before and after
+	// the indexes were rebuilt.
+	IndexesBefore int32
+	IndexesAfter  int32
+
+	// Indexes are the rebuilt indexes.
+	Indexes []IndexSpecification
+}
+
+// MaintenanceOperation is a compact, reIndex, or convertToCapped command in progress on the
+// collection, as reported by the $currentOp aggregation stage.
+type MaintenanceOperation struct {
+	// OpID identifies the operation. It is an int32 for mongod and a string for mongos, and can
+	// be passed to the killOp command.
+	OpID bson.RawValue
+
+	// Command is the name of the command, such as "compact".
+	Command string
+
+	// Host is the address of the mongod running the command.
+	Host string
+
+	// Running is how long the command has been running.
+	Running time.Duration
+
+	// Message describes the progress of the command, if it reports any.
+	Message string
+
+	// Done and Total are the units of work done and to do, if the command reports them. For
+	// reIndex, they are the number of documents indexed by the current phase.
+	Done  int64
+	Total int64
+
+	// Raw is the document returned by $currentOp.
+	Raw bson.Raw
+}
+
+// maintenanceCommands are the commands reported by MaintenanceOperations.
+var maintenanceCommands = []string{"compact", "reIndex", "convertToCapped"}
+
+// maintenanceSelector selects the first server chosen by base that check accepts, so that the
+// command runs on a server the caller inspected and selected records which one it is.
+type maintenanceSelector struct {
+	base     description.ServerSelector
+	check    func(description.Server) error
+	selected description.Server
+}
+
+var _ description.ServerSelector = &maintenanceSelector{}
+
+// SelectServer implements the description.ServerSelector interface.
+func (ms *maintenanceSelector) SelectServer(
+	topo description.Topology,
+	candidates []description.Server,
+) ([]description.Server, error) {
+	suitable, err := ms.base.SelectServer(topo, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for _, s := range suitable {
+		if err := ms.check(s); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ms.selected = s
+		return []description.Server{s}, nil
+	}
+	return nil, firstErr
+}
+
+// compactCheck rejects the servers that compact must not run on: mongos, which does not support
+// it, and primaries, unless force is true.
+func compactCheck(force bool) func(description.Server) error {
+	return func(s description.Server) error {
+		switch s.Kind {
+		case description.ServerKindMongos:
+			return fmt.Errorf("%w: compact must run directly against a shard member, not mongos %v",
+				ErrMaintenanceNotAllowed, s.Addr)
+		case description.ServerKindRSPrimary:
+			if !force {
+				return fmt.Errorf("%w: refusing to compact on primary %v; use a secondary read preference or set Force",
+					ErrMaintenanceNotAllowed, s.Addr)
+			}
+		}
+		return nil
+	}
+}
+
+// reIndexCheck rejects the servers that are not standalones, the only servers on which reIndex is
+// supported.
+func reIndexCheck(s description.Server) error {
+	if s.Kind != description.ServerKindStandalone {
+		return fmt.Errorf("%w: reIndex can only run on a standalone, but %v is a %v",
+			ErrMaintenanceNotAllowed, s.Addr, s.Kind)
+	}
+	return nil
+}
+
+// runMaintenance runs cmd on a server selected by the read preference of the collection and
+// accepted by check, and returns the response and the address of the server.
+func (coll *Collection) runMaintenance(
+	ctx context.Context,
+	cmd bson.D,
+	check func(description.Server) error,
+) (bson.Raw, address.Address, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rp := readpref.Primary()
+	if coll.readPreference != nil {
+		rp = coll.readPreference
+	}
+	op, sess, err := coll.db.processRunCommand(ctx, cmd, false, options.RunCmd().SetReadPreference(rp))
+	defer closeImplicitSession(sess)
+	if err != nil {
+		return nil, "", err
+	}
+
+	selector := &maintenanceSelector{
+		base: &serverselector.Composite{
+			Selectors: []description.ServerSelector{
+				&serverselector.ReadPref{ReadPref: rp},
+				&serverselector.Latency{Latency: coll.client.localThreshold},
+			},
+		},
+		check: check,
+	}
+	err = op.ServerSelector(selector).Execute(ctx)
+	return bson.Raw(op.Result()), selector.selected.Addr, replaceErrors(err)
+}
+
+// Compact runs the compact command to rewrite the data and indexes of the collection and release
+// unused disk space to the operating system. The command runs on the server selected by the read
+// preference of the collection, so use a secondary read preference to compact the members of a
+// replica set one at a time. Compact returns an error wrapping ErrMaintenanceNotAllowed instead of
+// running the command on a primary, where it can block operations, unless the Force option is set,
+// or on a mongos, which does not support it.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.CompactOptions documentation).
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/compact/.
+func (coll *Collection) Compact(
+	ctx context.Context,
+	opts ...options.Lister[options.CompactOptions],
+) (*CompactResult, error) {
+	args, err := mongoutil.NewOptions[options.CompactOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	cmd := bson.D{{"compact", coll.name}}
+	if args.Force != nil {
+		cmd = append(cmd, bson.E{"force", *args.Force})
+	}
+	if args.FreeSpaceTargetMB != nil {
+		cmd = append(cmd, bson.E{"freeSpaceTargetMB", *args.FreeSpaceTargetMB})
+	}
+	if args.DryRun != nil {
+		cmd = append(cmd, bson.E{"dryRun", *args.DryRun})
+	}
+
+	resp, server, err := coll.runMaintenance(ctx, cmd, compactCheck(args.Force != nil && *args.Force))
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		BytesFreed          int64 `bson:"bytesFreed"`
+		EstimatedBytesFreed int64 `bson:"estimatedBytesFreed"`
+	}
+	if err := bson.Unmarshal(resp, &res); err != nil {
+		return nil, err
+	}
+	return &CompactResult{
+		Server:              server,
+		BytesFreed:          res.BytesFreed,
+		EstimatedBytesFreed: res.EstimatedBytesFreed,
+	}, nil
+}
+
+// ReIndex runs the reIndex command to drop and rebuild all indexes of the collection. Because the
+// command blocks all other operations on the collection, it is only supported on standalones, and
+// ReIndex returns an error wrapping ErrMaintenanceNotAllowed instead of running it on any other
+// type of server. To rebuild the indexes of a replica set, drop and create them with IndexView.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/reIndex/.
+func (coll *Collection) ReIndex(ctx context.Context) (*ReIndexResult, error) {
+	resp, server, err := coll.runMaintenance(ctx, bson.D{{"reIndex", coll.name}}, reIndexCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		IndexesBefore int32                            `bson:"nIndexesWas"`
+		IndexesAfter  int32                            `bson:"nIndexes"`
+		Indexes       []indexListSpecificationResponse `bson:"indexes"`
+	}
+	if err := bson.Unmarshal(resp, &res); err != nil {
+		return nil, err
+	}
+
+	result := &ReIndexResult{
+		Server:        server,
+		IndexesBefore: res.IndexesBefore,
+		IndexesAfter:  res.IndexesAfter,
+		Indexes:       make([]IndexSpecification, len(res.Indexes)),
+	}
+	for i, spec := range res.Indexes {
+		result.Indexes[i] = spec.specification()
+		if result.Indexes[i].Namespace == "" {
+			result.Indexes[i].Namespace = coll.db.Name() + "." + coll.name
+		}
+	}
+	return result, nil
+}
+
+// ConvertToCapped runs the convertToCapped command to replace the collection with a capped
+// collection of at most sizeBytes bytes that contains its documents. The indexes of the collection,
+// other than the _id index, are not preserved, and the command holds an exclusive lock on the
+// database while it copies the documents. ConvertToCapped does not support sharded collections.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/convertToCapped/.
+func (coll *Collection) ConvertToCapped(ctx context.Context, sizeBytes int64) error {
+	if sizeBytes <= 0 {
+		return errors.New("capped collection size must be positive")
+	}
+
+	cmd := bson.D{{"convertToCapped", coll.name}, {"size", sizeBytes}}
+	return coll.db.RunCommand(ctx, cmd).Err()
+}
+
+// MaintenanceOperations runs the $currentOp aggregation stage and returns the compact, reIndex, and
+// convertToCapped commands in progress on the collection on the server selected by the read
+// preference of the collection, so that automation can report the progress of the commands started
+// by Compact, ReIndex and ConvertToCapped, which block until they finish. Against a sharded
+// cluster, the operations of all shards are returned.
+func (coll *Collection) MaintenanceOperations(ctx context.Context) ([]MaintenanceOperation, error) {
+	dbOpts := options.Database()
+	if coll.readPreference != nil {
+		dbOpts.SetReadPreference(coll.readPreference)
+	}
+	admin := coll.client.Database("admin", dbOpts)
+
+	cursor, err := admin.Aggregate(ctx, maintenanceOperationsPipeline(coll.db.Name(), coll.name))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ops []MaintenanceOperation
+	for cursor.Next(ctx) {
+		op, err := newMaintenanceOperation(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	return ops, cursor.Err()
+}
+
+func maintenanceOperationsPipeline(dbName, collName string) bson.A {
+	commands := make(bson.A, 0, len(maintenanceCommands))
+	for _, name := range maintenanceCommands {
+		commands = append(commands, bson.D{{"command." + name, collName}})
+	}
+	return bson.A{
+		bson.D{{"$currentOp", bson.D{{"allUsers", true}}}},
+		bson.D{{"$match", bson.D{
+			{"command.$db", dbName},
+			{"$or", commands},
+		}}},
+	}
+}
+
+func newMaintenanceOperation(raw bson.Raw) (MaintenanceOperation, error) {
+	var doc struct {
+		OpID             bson.RawValue `bson:"opid"`
+		Host             string        `bson:"host"`
+		MicrosecsRunning int64         `bson:"microsecs_running"`
+		Message          string        `bson:"msg"`
+		Progress         struct {
+			Done  int64 `bson:"done"`
+			Total int64 `bson:"total"`
+		} `bson:"progress"`
+		Command bson.Raw `bson:"command"`
+	}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return MaintenanceOperation{}, err
+	}
+
+	op := MaintenanceOperation{
+		OpID:    doc.OpID,
+		Host:    doc.Host,
+		Running: time.Duration(doc.MicrosecsRunning) * time.Microsecond,
+		Message: doc.Message,
+		Done:    doc.Progress.Done,
+		Total:   doc.Progress.Total,
+		Raw:     raw,
+	}
+	if elem, err := doc.Command.IndexErr(0); err == nil {
+		op.Command = elem.Key()
+	}
+	return op, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+func TestMaintenanceSelector(t *testing.T) {
+	t.Parallel()
+
+	primary := description.Server{Addr: "a:27017", Kind: description.ServerKindRSPrimary}
+	secondary := description.Server{Addr: "b:27017", Kind: description.ServerKindRSSecondary}
+	topo := description.Topology{
+		Kind:    description.TopologyKindReplicaSetWithPrimary,
+		Servers: []description.Server{primary, secondary},
+	}
+
+	selector := func(rp *readpref.ReadPref, check func(description.Server) error) *maintenanceSelector {
+		return &maintenanceSelector{base: &serverselector.ReadPref{ReadPref: rp}, check: check}
+	}
+
+	t.Run("compact on primary", func(t *testing.T) {
+		t.Parallel()
+
+		ms := selector(readpref.Primary(), compactCheck(false))
+		_, err := ms.SelectServer(topo, topo.Servers)
+		assert.True(t, errors.Is(err, ErrMaintenanceNotAllowed), "expected ErrMaintenanceNotAllowed, got %v", err)
+
+		ms = selector(readpref.Primary(), compactCheck(true))
+		selected, err := ms.SelectServer(topo, topo.Servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, []description.Server{primary}, selected, "selected mismatch")
+		assert.Equal(t, primary.Addr, ms.selected.Addr, "recorded server mismatch")
+	})
+
+	t.Run("compact skips primary", func(t *testing.T) {
+		t.Parallel()
+
+		ms := selector(readpref.Nearest(), compactCheck(false))
+		selected, err := ms.SelectServer(topo, topo.Servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, []description.Server{secondary}, selected, "selected mismatch")
+	})
+
+	t.Run("compact on mongos", func(t *testing.T) {
+		t.Parallel()
+
+		mongos := description.Server{Addr: "c:27017", Kind: description.ServerKindMongos}
+		sharded := description.Topology{Kind: description.TopologyKindSharded, Servers: []description.Server{mongos}}
+		_, err := selector(readpref.Primary(), compactCheck(true)).SelectServer(sharded, sharded.Servers)
+		assert.True(t, errors.Is(err, ErrMaintenanceNotAllowed), "expected ErrMaintenanceNotAllowed, got %v", err)
+	})
+
+	t.Run("reIndex", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := selector(readpref.Nearest(), reIndexCheck).SelectServer(topo, topo.Servers)
+		assert.True(t, errors.Is(err, ErrMaintenanceNotAllowed), "expected ErrMaintenanceNotAllowed, got %v", err)
+
+		standalone := description.Server{Addr: "d:27017", Kind: description.ServerKindStandalone}
+		single := description.Topology{Kind: description.TopologyKindSingle, Servers: []description.Server{standalone}}
+		selected, err := selector(readpref.Primary(), reIndexCheck).SelectServer(single, single.Servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, []description.Server{standalone}, selected, "selected mismatch")
+	})
+}
+
+func TestMaintenanceOperations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pipeline", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := bson.MarshalExtJSON(bson.D{{"p", maintenanceOperationsPipeline("db", "coll")}}, false, false)
+		require.NoError(t, err, "MarshalExtJSON error")
+		want := `{"p":[{"$currentOp":{"allUsers":true}},{"$match":{"command.$db":"db","$or":[` +
+			`{"command.compact":"coll"},{"command.reIndex":"coll"},{"command.convertToCapped":"coll"}]}}]}`
+		assert.Equal(t, want, string(got), "pipeline mismatch")
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+
+		raw, err := bson.Marshal(bson.D{
+			{"opid", int32(42)},
+			{"host", "b:27017"},
+			{"microsecs_running", int64(1500000)},
+			{"msg", "Index Build: scanning collection 20/80 25%"},
+			{"progress", bson.D{{"done", int32(20)}, {"total", int32(80)}}},
+			{"command", bson.D{{"reIndex", "coll"}, {"$db", "db"}}},
+		})
+		require.NoError(t, err, "Marshal error")
+
+		op, err := newMaintenanceOperation(raw)
+		require.NoError(t, err, "newMaintenanceOperation error")
+		assert.Equal(t, int32(42), op.OpID.Int32(), "opid mismatch")
+		assert.Equal(t, "reIndex", op.Command, "command mismatch")
+		assert.Equal(t, 1500*time.Millisecond, op.Running, "running mismatch")
+		assert.Equal(t, int64(20), op.Done, "done mismatch")
+		assert.Equal(t, int64(80), op.Total, "total mismatch")
+	})
+}
+
+func TestConvertToCapped(t *testing.T) {
+	t.Parallel()
+
+	err := setupColl("convertToCapped").ConvertToCapped(context.Background(), 0)
+	assert.Error(t, err, "expected error for non-positive size")
+}
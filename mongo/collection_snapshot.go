@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// WithSnapshot runs fn with a context containing a new snapshot session, so that all reads
+// performed by fn with the context, on this collection or any other collection of the same Client,
+// read from the same point-in-time snapshot of the data. The session is ended when fn returns.
+//
+// The snapshot time is chosen by the server for the first read performed by fn, unless it is set
+// with SessionOptions.SetSnapshotTime. WithSnapshot returns the snapshot time so that it can be
+// passed to a later WithSnapshot call to read from the same snapshot again, or nil if fn did not
+// perform any read. Within fn, the snapshot time can be obtained with
+// SessionFromContext(ctx).SnapshotTime().
+//
+// Snapshot sessions are read-only: write operations, including aggregations with an $out or
+// $merge stage, return ErrSnapshotWrite, and starting a transaction returns an error. Snapshot
+// reads require MongoDB 5.0+ replica sets or sharded clusters, see ServerFeatures.SnapshotReads.
+//
+// The opts parameter can be used to specify additional options for the session. The Snapshot
+// option is always set to true, so CausalConsistency cannot be set to true.
+//
+// If the ctx parameter already contains a Session, that Session will be replaced with the newly
+// created one. Any error returned by fn will be returned without any modifications.
+func (coll *Collection) WithSnapshot(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+	opts ...options.Lister[options.SessionOptions],
+) (*bson.Timestamp, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	sessOpts := append(opts[:len(opts):len(opts)], options.Session().SetSnapshot(true))
+	sess, err := coll.client.StartSession(sessOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(ctx)
+
+	err = fn(NewSessionContext(ctx, sess))
+	return sess.SnapshotTime(), err
+}
@@ -0,0 +1,113 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestCollectionWithSnapshot(t *testing.T) {
+	t.Parallel()
+
+	newCollection := func(t *testing.T) (*Collection, *drivertest.MockDeployment) {
+		t.Helper()
+
+		md := drivertest.NewMockDeployment()
+		clientOpts := options.Client()
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = md
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		return client.Database("db").Collection("coll"), md
+	}
+
+	t.Run("exposes the snapshot time", func(t *testing.T) {
+		t.Parallel()
+
+		coll, md := newCollection(t)
+		md.AddResponses(bson.D{
+			{"ok", 1},
+			{"cursor", bson.D{
+				{"id", int64(0)},
+				{"ns", "db.coll"},
+				{"firstBatch", bson.A{}},
+				{"atClusterTime", bson.Timestamp{T: 10, I: 2}},
+			}},
+		})
+
+		ts, err := coll.WithSnapshot(context.Background(), func(ctx context.Context) error {
+			assert.Nil(t, SessionFromContext(ctx).SnapshotTime(), "expected no snapshot time before the first read")
+			cursor, err := coll.Find(ctx, bson.D{})
+			if err != nil {
+				return err
+			}
+			return cursor.Close(ctx)
+		})
+		require.NoError(t, err, "WithSnapshot error")
+		assert.Equal(t, &bson.Timestamp{T: 10, I: 2}, ts, "snapshot time mismatch")
+	})
+	t.Run("pinned snapshot time", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newCollection(t)
+		want := bson.Timestamp{T: 20, I: 1}
+		ts, err := coll.WithSnapshot(context.Background(), func(ctx context.Context) error {
+			got := SessionFromContext(ctx).SnapshotTime()
+			assert.Equal(t, &want, got, "snapshot time mismatch")
+			return nil
+		}, options.Session().SetSnapshotTime(want))
+		require.NoError(t, err, "WithSnapshot error")
+		assert.Equal(t, &want, ts, "snapshot time mismatch")
+	})
+	t.Run("writes are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newCollection(t)
+		_, err := coll.WithSnapshot(context.Background(), func(ctx context.Context) error {
+			_, err := coll.InsertOne(ctx, bson.D{{"x", 1}})
+			assert.True(t, errors.Is(err, ErrSnapshotWrite), "expected ErrSnapshotWrite from InsertOne, got %v", err)
+
+			_, err = coll.UpdateOne(ctx, bson.D{}, bson.D{{"$set", bson.D{{"x", 2}}}})
+			assert.True(t, errors.Is(err, ErrSnapshotWrite), "expected ErrSnapshotWrite from UpdateOne, got %v", err)
+
+			_, err = coll.Aggregate(ctx, Pipeline{{{"$out", "archive"}}})
+			assert.True(t, errors.Is(err, ErrSnapshotWrite), "expected ErrSnapshotWrite from Aggregate, got %v", err)
+
+			err = SessionFromContext(ctx).StartTransaction()
+			assert.Error(t, err, "expected StartTransaction error")
+			return errors.New("fn error")
+		})
+		assert.EqualError(t, err, "fn error", "expected the fn error to be returned")
+	})
+	t.Run("causal consistency conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newCollection(t)
+		_, err := coll.WithSnapshot(context.Background(), func(context.Context) error {
+			t.Fatal("fn should not be called")
+			return nil
+		}, options.Session().SetCausalConsistency(true))
+		assert.Error(t, err, "expected an error for a causally consistent snapshot session")
+	})
+	t.Run("snapshot time requires snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newCollection(t)
+		_, err := coll.client.StartSession(options.Session().SetSnapshotTime(bson.Timestamp{T: 1}))
+		assert.Error(t, err, "expected an error for a snapshot time without snapshot")
+	})
+}
@@ -11,16 +11,22 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/ptrutil"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
 )
 
@@ -295,6 +301,28 @@ func TestNewFindArgsFromFindOneArgs(t *testing.T) {
 				Limit: ptrutil.Ptr(int64(-1)),
 			},
 		},
+		{
+			name: "comment and let",
+			args: &options.FindOneOptions{
+				Comment: "trace-42",
+				Let:     bson.D{{"x", 1}},
+			},
+			want: &options.FindOptions{
+				Comment: "trace-42",
+				Let:     bson.D{{"x", 1}},
+				Limit:   ptrutil.Ptr(int64(-1)),
+			},
+		},
+		{
+			name: "local threshold",
+			args: &options.FindOneOptions{
+				LocalThreshold: ptrutil.Ptr(5 * time.Millisecond),
+			},
+			want: &options.FindOptions{
+				LocalThreshold: ptrutil.Ptr(5 * time.Millisecond),
+				Limit:          ptrutil.Ptr(int64(-1)),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -307,3 +335,176 @@ func TestNewFindArgsFromFindOneArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestOperationConcernOverrides(t *testing.T) {
+	t.Parallel()
+
+	cursorReply := bson.D{{"ok", 1}, {"cursor", bson.D{
+		{"id", int64(0)},
+		{"ns", "db.coll"},
+		{"firstBatch", bson.A{}},
+	}}}
+
+	testCases := []struct {
+		name     string
+		response bson.D
+		run      func(*Collection) error
+		field    string
+		want     bson.D
+	}{
+		{
+			name:     "InsertOne write concern",
+			response: bson.D{{"ok", 1}, {"n", 1}},
+			run: func(coll *Collection) error {
+				opts := options.InsertOne().SetWriteConcern(&writeconcern.WriteConcern{W: 2})
+				_, err := coll.InsertOne(context.Background(), bson.D{{"x", 1}}, opts)
+				return err
+			},
+			field: "writeConcern",
+			want:  bson.D{{"w", int32(2)}},
+		},
+		{
+			name:     "UpdateOne write concern",
+			response: bson.D{{"ok", 1}, {"n", 1}, {"nModified", 1}},
+			run: func(coll *Collection) error {
+				opts := options.UpdateOne().SetWriteConcern(writeconcern.Majority())
+				_, err := coll.UpdateOne(context.Background(), bson.D{}, bson.D{{"$set", bson.D{{"x", 1}}}}, opts)
+				return err
+			},
+			field: "writeConcern",
+			want:  bson.D{{"w", "majority"}},
+		},
+		{
+			name:     "DeleteOne write concern",
+			response: bson.D{{"ok", 1}, {"n", 1}},
+			run: func(coll *Collection) error {
+				opts := options.DeleteOne().SetWriteConcern(writeconcern.Majority())
+				_, err := coll.DeleteOne(context.Background(), bson.D{}, opts)
+				return err
+			},
+			field: "writeConcern",
+			want:  bson.D{{"w", "majority"}},
+		},
+		{
+			name:     "FindOneAndDelete write concern",
+			response: bson.D{{"ok", 1}, {"value", bson.D{{"_id", 1}}}},
+			run: func(coll *Collection) error {
+				opts := options.FindOneAndDelete().SetWriteConcern(writeconcern.Majority())
+				return coll.FindOneAndDelete(context.Background(), bson.D{}, opts).Err()
+			},
+			field: "writeConcern",
+			want:  bson.D{{"w", "majority"}},
+		},
+		{
+			name:     "Find read concern",
+			response: cursorReply,
+			run: func(coll *Collection) error {
+				opts := options.Find().SetReadConcern(readconcern.Majority())
+				_, err := coll.Find(context.Background(), bson.D{}, opts)
+				return err
+			},
+			field: "readConcern",
+			want:  bson.D{{"level", "majority"}},
+		},
+		{
+			name:     "FindOne read concern",
+			response: cursorReply,
+			run: func(coll *Collection) error {
+				opts := options.FindOne().SetReadConcern(readconcern.Local())
+				err := coll.FindOne(context.Background(), bson.D{}, opts).Err()
+				if errors.Is(err, ErrNoDocuments) {
+					return nil
+				}
+				return err
+			},
+			field: "readConcern",
+			want:  bson.D{{"level", "local"}},
+		},
+		{
+			name:     "Distinct read concern",
+			response: bson.D{{"ok", 1}, {"values", bson.A{}}},
+			run: func(coll *Collection) error {
+				opts := options.Distinct().SetReadConcern(readconcern.Majority())
+				return coll.Distinct(context.Background(), "x", bson.D{}, opts).Err()
+			},
+			field: "readConcern",
+			want:  bson.D{{"level", "majority"}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var started []*event.CommandStartedEvent
+			monitor := &event.CommandMonitor{
+				Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+					started = append(started, evt)
+				},
+			}
+			md := drivertest.NewMockDeployment(tc.response)
+			clientOpts := options.Client().SetMonitor(monitor)
+			clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+				args.Deployment = md
+
+				return nil
+			})
+			client, err := Connect(clientOpts)
+			require.NoError(t, err, "Connect error")
+			defer func() { _ = client.Disconnect(context.Background()) }()
+
+			coll := client.Database("db").Collection("coll")
+			require.NoError(t, tc.run(coll), "operation error")
+			require.Len(t, started, 1, "expected one command")
+
+			want, err := bson.Marshal(tc.want)
+			require.NoError(t, err, "Marshal error")
+			got := started[0].Command.Lookup(tc.field)
+			assert.Equal(t, bson.Raw(want), got.Document(), "%s mismatch", tc.field)
+		})
+	}
+
+	t.Run("in transaction", func(t *testing.T) {
+		t.Parallel()
+
+		sess := &session.Client{TransactionState: session.InProgress}
+
+		_, err := operationWriteConcern(sess, writeconcern.W1(), writeconcern.Majority())
+		assert.ErrorIs(t, err, ErrConcernInTransaction, "expected error for write concern override")
+		_, err = operationReadConcern(sess, readconcern.Local(), readconcern.Majority())
+		assert.ErrorIs(t, err, ErrConcernInTransaction, "expected error for read concern override")
+
+		wc, err := operationWriteConcern(sess, writeconcern.W1(), nil)
+		require.NoError(t, err, "operationWriteConcern error")
+		assert.Nil(t, wc, "expected no write concern in a transaction")
+	})
+}
+
+func TestLatencyWindow(t *testing.T) {
+	t.Parallel()
+
+	fast := description.Server{Addr: "fast:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 5 * time.Millisecond, AverageRTTSet: true}
+	slow := description.Server{Addr: "slow:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 15 * time.Millisecond, AverageRTTSet: true}
+	topo := description.Topology{Kind: description.TopologyKindReplicaSetNoPrimary, Servers: []description.Server{fast, slow}}
+
+	rp := readpref.Nearest()
+	readSelector := &serverselector.Composite{
+		Selectors: []description.ServerSelector{
+			&serverselector.ReadPref{ReadPref: rp},
+			&serverselector.Latency{Latency: defaultLocalThreshold},
+		},
+	}
+
+	selector, localThreshold := latencyWindow(readSelector, rp, defaultLocalThreshold, nil)
+	assert.Equal(t, defaultLocalThreshold, localThreshold, "expected the latency window of the client")
+	selected, err := selector.SelectServer(topo, topo.Servers)
+	require.NoError(t, err, "SelectServer error")
+	assert.Len(t, selected, 2, "expected both servers within the latency window of the client")
+
+	selector, localThreshold = latencyWindow(readSelector, rp, defaultLocalThreshold, ptrutil.Ptr(5*time.Millisecond))
+	assert.Equal(t, 5*time.Millisecond, localThreshold, "expected the overridden latency window")
+	selected, err = selector.SelectServer(topo, topo.Servers)
+	require.NoError(t, err, "SelectServer error")
+	assert.Equal(t, []description.Server{fast}, selected, "expected only the fast server")
+}
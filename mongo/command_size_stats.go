@@ -0,0 +1,152 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// CommandSizeStats contains the aggregated sizes of the wire messages of the commands that a
+// Client ran on a collection. See Client.CommandSizeStats.
+//
+// Sizes are in bytes and include the whole wire message, so a request size bounds the size of the
+// largest document sent by the command and a reply size bounds the size of the largest document
+// returned. Only commands that succeeded are counted.
+type CommandSizeStats struct {
+	// Database and Collection are the namespace the commands ran on. Collection is empty for
+	// commands that do not run on a collection, such as database aggregations.
+	Database   string
+	Collection string
+
+	// Commands is the number of commands counted.
+	Commands int64
+
+	// RequestBytes and ReplyBytes are the total sizes of the requests and replies as sent over
+	// the network, i.e. after compression if the messages were compressed.
+	RequestBytes int64
+	ReplyBytes   int64
+
+	// UncompressedRequestBytes and UncompressedReplyBytes are the total sizes of the requests
+	// before compression and of the replies after decompression.
+	UncompressedRequestBytes int64
+	UncompressedReplyBytes   int64
+
+	// MaxRequestSize is the size of the largest uncompressed request and MaxRequestCommand is the
+	// name of the command that sent it, such as "insert".
+	MaxRequestSize    int
+	MaxRequestCommand string
+
+	// MaxReplySize is the size of the largest uncompressed reply and MaxReplyCommand is the name
+	// of the command that received it, such as "find".
+	MaxReplySize    int
+	MaxReplyCommand string
+}
+
+// CommandSizeStats returns the sizes of the commands run by the Client, aggregated per collection
+// and sorted by database and collection name. It returns nil unless command size statistics were
+// enabled with options.ClientOptionsBuilder.SetCommandSizeStats.
+func (c *Client) CommandSizeStats() []CommandSizeStats {
+	if c.sizeStats == nil {
+		return nil
+	}
+	return c.sizeStats.stats()
+}
+
+// ResetCommandSizeStats discards the command size statistics collected so far, e.g. to profile the
+// commands run by a part of an application in isolation.
+func (c *Client) ResetCommandSizeStats() {
+	if c.sizeStats == nil {
+		return
+	}
+	c.sizeStats.reset()
+}
+
+type sizeStatsKey struct {
+	database   string
+	collection string
+}
+
+// commandSizeRecorder aggregates the sizes reported in CommandSucceededEvents.
+type commandSizeRecorder struct {
+	mu         sync.Mutex
+	namespaces map[sizeStatsKey]*CommandSizeStats
+}
+
+func newCommandSizeRecorder() *commandSizeRecorder {
+	return &commandSizeRecorder{namespaces: make(map[sizeStatsKey]*CommandSizeStats)}
+}
+
+// monitor returns a CommandMonitor that records the sizes of succeeded commands and then forwards
+// all events to next, which may be nil.
+func (r *commandSizeRecorder) monitor(next *event.CommandMonitor) *event.CommandMonitor {
+	m := &event.CommandMonitor{}
+	if next != nil {
+		*m = *next
+	}
+	succeeded := m.Succeeded
+	m.Succeeded = func(ctx context.Context, evt *event.CommandSucceededEvent) {
+		r.record(evt)
+		if succeeded != nil {
+			succeeded(ctx, evt)
+		}
+	}
+	return m
+}
+
+func (r *commandSizeRecorder) record(evt *event.CommandSucceededEvent) {
+	key := sizeStatsKey{database: evt.DatabaseName, collection: evt.CollectionName}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.namespaces[key]
+	if !ok {
+		s = &CommandSizeStats{Database: key.database, Collection: key.collection}
+		r.namespaces[key] = s
+	}
+	s.Commands++
+	s.RequestBytes += int64(evt.RequestSize)
+	s.ReplyBytes += int64(evt.ReplySize)
+	s.UncompressedRequestBytes += int64(evt.UncompressedRequestSize)
+	s.UncompressedReplyBytes += int64(evt.UncompressedReplySize)
+	if evt.UncompressedRequestSize > s.MaxRequestSize {
+		s.MaxRequestSize = evt.UncompressedRequestSize
+		s.MaxRequestCommand = evt.CommandName
+	}
+	if evt.UncompressedReplySize > s.MaxReplySize {
+		s.MaxReplySize = evt.UncompressedReplySize
+		s.MaxReplyCommand = evt.CommandName
+	}
+}
+
+func (r *commandSizeRecorder) stats() []CommandSizeStats {
+	r.mu.Lock()
+	stats := make([]CommandSizeStats, 0, len(r.namespaces))
+	for _, s := range r.namespaces {
+		stats = append(stats, *s)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Database != stats[j].Database {
+			return stats[i].Database < stats[j].Database
+		}
+		return stats[i].Collection < stats[j].Collection
+	})
+	return stats
+}
+
+func (r *commandSizeRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.namespaces = make(map[sizeStatsKey]*CommandSizeStats)
+}
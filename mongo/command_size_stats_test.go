@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestCommandSizeRecorder(t *testing.T) {
+	t.Parallel()
+
+	var forwarded int
+	r := newCommandSizeRecorder()
+	monitor := r.monitor(&event.CommandMonitor{
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { forwarded++ },
+	})
+
+	succeeded := func(coll, name string, request, uncompressedRequest, reply int) *event.CommandSucceededEvent {
+		return &event.CommandSucceededEvent{
+			CommandFinishedEvent:    event.CommandFinishedEvent{DatabaseName: "db", CollectionName: coll, CommandName: name},
+			RequestSize:             request,
+			UncompressedRequestSize: uncompressedRequest,
+			ReplySize:               reply,
+			UncompressedReplySize:   reply,
+		}
+	}
+	monitor.Succeeded(context.Background(), succeeded("users", "insert", 500, 1000, 50))
+	monitor.Succeeded(context.Background(), succeeded("users", "find", 80, 80, 4000))
+	monitor.Succeeded(context.Background(), succeeded("", "aggregate", 100, 100, 100))
+
+	want := []CommandSizeStats{
+		{
+			Database:                 "db",
+			Commands:                 1,
+			RequestBytes:             100,
+			ReplyBytes:               100,
+			UncompressedRequestBytes: 100,
+			UncompressedReplyBytes:   100,
+			MaxRequestSize:           100,
+			MaxRequestCommand:        "aggregate",
+			MaxReplySize:             100,
+			MaxReplyCommand:          "aggregate",
+		},
+		{
+			Database:                 "db",
+			Collection:               "users",
+			Commands:                 2,
+			RequestBytes:             580,
+			ReplyBytes:               4050,
+			UncompressedRequestBytes: 1080,
+			UncompressedReplyBytes:   4050,
+			MaxRequestSize:           1000,
+			MaxRequestCommand:        "insert",
+			MaxReplySize:             4000,
+			MaxReplyCommand:          "find",
+		},
+	}
+	assert.Equal(t, want, r.stats(), "stats mismatch")
+	assert.Equal(t, 3, forwarded, "expected events to be forwarded to the application monitor")
+
+	r.reset()
+	assert.Len(t, r.stats(), 0, "expected no stats after reset")
+}
+
+func TestClientCommandSizeStats(t *testing.T) {
+	t.Parallel()
+
+	md := drivertest.NewMockDeployment()
+	clientOpts := options.Client().SetCommandSizeStats(true)
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = md
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+
+	md.AddResponses(bson.D{{"ok", 1}, {"n", 1}})
+	_, err = client.Database("db").Collection("coll").InsertOne(context.Background(), bson.D{{"x", 1}})
+	require.NoError(t, err, "InsertOne error")
+
+	stats := client.CommandSizeStats()
+	require.Len(t, stats, 1, "expected stats for a single collection")
+	assert.Equal(t, "coll", stats[0].Collection, "collection mismatch")
+	assert.Equal(t, int64(1), stats[0].Commands, "command count mismatch")
+	assert.Equal(t, "insert", stats[0].MaxRequestCommand, "largest request command mismatch")
+	assert.True(t, stats[0].MaxRequestSize > 0, "expected a positive request size")
+
+	disabled, err := newClient()
+	require.NoError(t, err, "newClient error")
+	assert.Nil(t, disabled.CommandSizeStats(), "expected no stats when disabled")
+}
@@ -0,0 +1,25 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// WithoutCompression returns a copy of ctx that disables wire compression for the operations run
+// with it, even if the Client was configured with compressors. Use it for operations whose
+// payloads are already compressed, such as images or archives, to avoid spending CPU time on
+// bytes that do not compress. Replies may still be compressed by the server.
+//
+// For example:
+//
+//	_, err := coll.InsertOne(mongo.WithoutCompression(ctx), bson.D{{"jpeg", data}})
+func WithoutCompression(ctx context.Context) context.Context {
+	return driver.WithoutCompression(ctx)
+}
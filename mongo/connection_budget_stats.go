@@ -0,0 +1,55 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import "go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+
+// ConnectionBudgetStats contains statistics about the connection budget of a Client, see
+// options.ClientOptionsBuilder.SetMaxConnections and Client.ConnectionBudgetStats.
+//
+// Throttled only increases. A Throttled value that keeps increasing means that the budget is too
+// small for the load, and checkouts wait for connections to be closed by other pools.
+type ConnectionBudgetStats struct {
+	// Max is the maximum number of connections of all the connection pools.
+	Max uint64
+
+	// InUse is the number of connections of all the connection pools, including the connections
+	// being established.
+	InUse uint64
+
+	// Throttled is the total number of times a connection pool waited for the budget to create a
+	// connection.
+	Throttled int64
+
+	// Waiting is the number of connection pools currently waiting for the budget.
+	Waiting int
+
+	// Servers is the number of connections of the connection pool of each server, by address.
+	Servers map[string]uint64
+}
+
+// ConnectionBudgetStats returns statistics about the connection budget of the Client, and false if
+// the Client has no connection budget because options.ClientOptionsBuilder.SetMaxConnections was
+// not set or the Client is not connected.
+func (c *Client) ConnectionBudgetStats() (ConnectionBudgetStats, bool) {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return ConnectionBudgetStats{}, false
+	}
+
+	s, ok := topo.ConnectionBudgetStats()
+	if !ok {
+		return ConnectionBudgetStats{}, false
+	}
+	return ConnectionBudgetStats{
+		Max:       s.Max,
+		InUse:     s.InUse,
+		Throttled: s.Throttled,
+		Waiting:   s.Waiting,
+		Servers:   s.Servers,
+	}, true
+}
@@ -0,0 +1,455 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// defaultKeyExpiration is the default expiration of the data keys cached by libmongocrypt, which
+// is also used for the key document cache.
+const defaultKeyExpiration = 60 * time.Second
+
+// KeyCacheStats contains statistics about the key vault documents fetched by a Client for
+// automatic encryption and decryption. See Client.KeyCacheStats.
+//
+// A data key is only requested when it is not in the data key cache of libmongocrypt, e.g. because
+// it has expired (see options.AutoEncryptionOptionsBuilder.SetKeyExpiration), so Hits and Misses
+// only count the requests that the libmongocrypt cache could not serve.
+type KeyCacheStats struct {
+	// Enabled is true if the key document cache is enabled, see
+	// options.AutoEncryptionOptionsBuilder.SetKeyCacheRefreshBefore.
+	Enabled bool
+
+	// Entries is the number of key vault documents in the key document cache.
+	Entries int
+
+	// Hits is the number of requested data keys served by the key document cache.
+	Hits int64
+
+	// Misses is the number of requested data keys that were fetched from the key vault collection
+	// while an operation waited for them. If the key document cache is disabled, every requested
+	// data key is a miss.
+	Misses int64
+
+	// Fetches is the number of queries run on the key vault collection while an operation waited
+	// for them.
+	Fetches int64
+
+	// Refreshes is the number of key vault documents fetched again in the background before they
+	// expired, and RefreshErrors is the number of background queries that failed.
+	Refreshes     int64
+	RefreshErrors int64
+
+	// Evictions is the number of key vault documents evicted from the key document cache because
+	// it was full.
+	Evictions int64
+}
+
+// KeyCacheStats returns statistics about the key vault documents fetched by the Client for
+// automatic encryption and decryption. It returns the zero value if the Client was not configured
+// with AutoEncryptionOptions.
+func (c *Client) KeyCacheStats() KeyCacheStats {
+	if c.keyCacheFLE == nil {
+		return KeyCacheStats{}
+	}
+	return c.keyCacheFLE.stats()
+}
+
+type keyCacheEntry struct {
+	id       bsoncore.Value
+	doc      bsoncore.Document
+	altNames []string
+	fetched  time.Time
+	used     time.Time
+}
+
+// keyCache caches the key vault documents requested by libmongocrypt. Documents are cached for
+// the key expiration and the documents that were used during the last key expiration are fetched
+// again in the background before they expire, so they are available when the data keys expire
+// from the libmongocrypt cache and are requested again.
+type keyCache struct {
+	fetch         func(ctx context.Context, filter bsoncore.Document) ([]bsoncore.Document, error)
+	enabled       bool
+	expiration    time.Duration
+	maxEntries    int
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*keyCacheEntry
+	altNames map[string]string
+	counters KeyCacheStats
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newKeyCache(
+	fetch func(ctx context.Context, filter bsoncore.Document) ([]bsoncore.Document, error),
+	args *options.AutoEncryptionOptions,
+) (*keyCache, error) {
+	kc := &keyCache{
+		fetch:      fetch,
+		expiration: defaultKeyExpiration,
+		now:        time.Now,
+		entries:    make(map[string]*keyCacheEntry),
+		altNames:   make(map[string]string),
+		done:       make(chan struct{}),
+	}
+	if args.KeyExpiration != nil {
+		if *args.KeyExpiration < 0 {
+			return nil, errors.New("key expiration must not be negative")
+		}
+		kc.expiration = *args.KeyExpiration
+	}
+	if args.KeyCacheMaxEntries != nil {
+		if *args.KeyCacheMaxEntries < 0 {
+			return nil, errors.New("key cache max entries must not be negative")
+		}
+		kc.maxEntries = *args.KeyCacheMaxEntries
+		kc.enabled = true
+	}
+	if args.KeyCacheRefreshBefore != nil {
+		if *args.KeyCacheRefreshBefore < 0 {
+			return nil, errors.New("key cache refresh before must not be negative")
+		}
+		if kc.expiration > 0 && *args.KeyCacheRefreshBefore >= kc.expiration {
+			return nil, errors.New("key cache refresh before must be less than the key expiration")
+		}
+		kc.refreshBefore = *args.KeyCacheRefreshBefore
+		kc.enabled = true
+	}
+	return kc, nil
+}
+
+// start starts refreshing the cached documents in the background, if enabled.
+func (kc *keyCache) start() {
+	if !kc.enabled || kc.expiration == 0 || kc.refreshBefore == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(kc.refreshBefore / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-kc.done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), kc.refreshBefore)
+				kc.refresh(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+func (kc *keyCache) close() {
+	kc.closeOnce.Do(func() { close(kc.done) })
+}
+
+func (kc *keyCache) stats() KeyCacheStats {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	s := kc.counters
+	s.Enabled = kc.enabled
+	s.Entries = len(kc.entries)
+	return s
+}
+
+// cryptKeys returns the key vault documents that match filter, which is a filter created by
+// libmongocrypt, from the cache if possible.
+func (kc *keyCache) cryptKeys(ctx context.Context, filter bsoncore.Document) ([]bsoncore.Document, error) {
+	ids, names, ok := parseKeyFilter(filter)
+	if !kc.enabled || !ok {
+		kc.mu.Lock()
+		kc.counters.Misses += int64(len(ids) + len(names))
+		kc.counters.Fetches++
+		kc.mu.Unlock()
+		return kc.fetch(ctx, filter)
+	}
+
+	now := kc.now()
+	var docs []bsoncore.Document
+	found := make(map[string]bool)
+	var missingIDs []bsoncore.Value
+	var missingNames []string
+
+	kc.mu.Lock()
+	for _, id := range ids {
+		key := string(id.Data)
+		if e := kc.get(key, now); e != nil {
+			if !found[key] {
+				found[key] = true
+				docs = append(docs, e.doc)
+			}
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+	for _, name := range names {
+		key, ok := kc.altNames[name]
+		if e := kc.get(key, now); ok && e != nil {
+			if !found[key] {
+				found[key] = true
+				docs = append(docs, e.doc)
+			}
+			continue
+		}
+		missingNames = append(missingNames, name)
+	}
+	kc.counters.Hits += int64(len(ids) + len(names) - len(missingIDs) - len(missingNames))
+	if len(missingIDs)+len(missingNames) > 0 {
+		kc.counters.Misses += int64(len(missingIDs) + len(missingNames))
+		kc.counters.Fetches++
+	}
+	kc.mu.Unlock()
+
+	if len(missingIDs)+len(missingNames) == 0 {
+		return docs, nil
+	}
+
+	fetched, err := kc.fetch(ctx, keyFilter(missingIDs, missingNames))
+	if err != nil {
+		return nil, err
+	}
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	for _, doc := range fetched {
+		key, ok := kc.store(doc, now)
+		if ok {
+			if found[key] {
+				continue
+			}
+			found[key] = true
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// get returns the entry with the given key if it has not expired and marks it as used. kc.mu must
+// be held.
+func (kc *keyCache) get(key string, now time.Time) *keyCacheEntry {
+	e, ok := kc.entries[key]
+	if !ok || kc.expired(e, now) {
+		return nil
+	}
+	e.used = now
+	return e
+}
+
+func (kc *keyCache) expired(e *keyCacheEntry, now time.Time) bool {
+	return kc.expiration > 0 && now.Sub(e.fetched) >= kc.expiration
+}
+
+// store adds doc to the cache and returns its key. It returns false if doc has no binary _id and
+// cannot be cached. kc.mu must be held.
+func (kc *keyCache) store(doc bsoncore.Document, now time.Time) (string, bool) {
+	id, err := doc.LookupErr("_id")
+	if err != nil || id.Type != bsoncore.TypeBinary {
+		return "", false
+	}
+	key := string(id.Data)
+
+	var altNames []string
+	if arr, ok := doc.Lookup("keyAltNames").ArrayOK(); ok {
+		values, _ := arr.Values()
+		for _, v := range values {
+			if name, ok := v.StringValueOK(); ok {
+				altNames = append(altNames, name)
+			}
+		}
+	}
+
+	used := now
+	if old, ok := kc.entries[key]; ok {
+		kc.removeAltNames(key, old)
+		used = old.used
+	}
+	kc.entries[key] = &keyCacheEntry{id: id, doc: doc, altNames: altNames, fetched: now, used: used}
+	for _, name := range altNames {
+		kc.altNames[name] = key
+	}
+	kc.evict()
+	return key, true
+}
+
+func (kc *keyCache) remove(key string) {
+	if e, ok := kc.entries[key]; ok {
+		kc.removeAltNames(key, e)
+		delete(kc.entries, key)
+	}
+}
+
+func (kc *keyCache) removeAltNames(key string, e *keyCacheEntry) {
+	for _, name := range e.altNames {
+		if kc.altNames[name] == key {
+			delete(kc.altNames, name)
+		}
+	}
+}
+
+// evict removes the least recently used entries while the cache is full. kc.mu must be held.
+func (kc *keyCache) evict() {
+	for kc.maxEntries > 0 && len(kc.entries) > kc.maxEntries {
+		var lru string
+		var lruUsed time.Time
+		for key, e := range kc.entries {
+			if lru == "" || e.used.Before(lruUsed) {
+				lru, lruUsed = key, e.used
+			}
+		}
+		kc.remove(lru)
+		kc.counters.Evictions++
+	}
+}
+
+// refresh fetches the cached documents that expire within the refresh period and were used during
+// the last key expiration, and removes the expired documents.
+func (kc *keyCache) refresh(ctx context.Context) {
+	now := kc.now()
+
+	kc.mu.Lock()
+	var ids []bsoncore.Value
+	keys := make(map[string]bool)
+	for key, e := range kc.entries {
+		switch {
+		case now.Sub(e.used) >= kc.expiration:
+			if kc.expired(e, now) {
+				kc.remove(key)
+			}
+		case now.Sub(e.fetched) >= kc.expiration-kc.refreshBefore:
+			ids = append(ids, e.id)
+			keys[key] = true
+		}
+	}
+	kc.mu.Unlock()
+
+	if len(ids) == 0 {
+		return
+	}
+
+	docs, err := kc.fetch(ctx, keyFilter(ids, nil))
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if err != nil {
+		kc.counters.RefreshErrors++
+		return
+	}
+	for _, doc := range docs {
+		if key, ok := kc.store(doc, now); ok {
+			delete(keys, key)
+			kc.counters.Refreshes++
+		}
+	}
+	// The remaining keys were deleted from the key vault collection.
+	for key := range keys {
+		kc.remove(key)
+	}
+}
+
+// parseKeyFilter returns the key IDs and key alternate names matched by a key vault filter created
+// by libmongocrypt, which has the form
+//
+//	{"$or": [{"_id": {"$in": [<ids>]}}, {"keyAltNames": {"$in": [<names>]}}]}
+//
+// It returns false if filter does not have that form.
+func parseKeyFilter(filter bsoncore.Document) ([]bsoncore.Value, []string, bool) {
+	elems, err := filter.Elements()
+	if err != nil || len(elems) != 1 || elems[0].Key() != "$or" {
+		return nil, nil, false
+	}
+	clauses, ok := elems[0].Value().ArrayOK()
+	if !ok {
+		return nil, nil, false
+	}
+	values, err := clauses.Values()
+	if err != nil {
+		return nil, nil, false
+	}
+
+	var ids []bsoncore.Value
+	var names []string
+	for _, v := range values {
+		clause, ok := v.DocumentOK()
+		if !ok {
+			return nil, nil, false
+		}
+		celems, err := clause.Elements()
+		if err != nil || len(celems) != 1 {
+			return nil, nil, false
+		}
+		in, err := celems[0].Value().Document().LookupErr("$in")
+		if err != nil {
+			return nil, nil, false
+		}
+		arr, ok := in.ArrayOK()
+		if !ok {
+			return nil, nil, false
+		}
+		invals, err := arr.Values()
+		if err != nil {
+			return nil, nil, false
+		}
+
+		switch celems[0].Key() {
+		case "_id":
+			for _, id := range invals {
+				if id.Type != bsoncore.TypeBinary {
+					return nil, nil, false
+				}
+				ids = append(ids, id)
+			}
+		case "keyAltNames":
+			for _, name := range invals {
+				str, ok := name.StringValueOK()
+				if !ok {
+					return nil, nil, false
+				}
+				names = append(names, str)
+			}
+		default:
+			return nil, nil, false
+		}
+	}
+	return ids, names, true
+}
+
+// keyFilter returns a key vault filter with the form of the filters created by libmongocrypt.
+func keyFilter(ids []bsoncore.Value, names []string) bsoncore.Document {
+	idArr := bsoncore.NewArrayBuilder()
+	for _, id := range ids {
+		idArr.AppendValue(id)
+	}
+	nameArr := bsoncore.NewArrayBuilder()
+	for _, name := range names {
+		nameArr.AppendString(name)
+	}
+
+	return bsoncore.NewDocumentBuilder().
+		AppendArray("$or", bsoncore.NewArrayBuilder().
+			AppendDocument(bsoncore.NewDocumentBuilder().
+				AppendDocument("_id", bsoncore.NewDocumentBuilder().AppendArray("$in", idArr.Build()).Build()).
+				Build()).
+			AppendDocument(bsoncore.NewDocumentBuilder().
+				AppendDocument("keyAltNames", bsoncore.NewDocumentBuilder().AppendArray("$in", nameArr.Build()).Build()).
+				Build()).
+			Build()).
+		Build()
+}
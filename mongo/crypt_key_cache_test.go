@@ -0,0 +1,245 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/ptrutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func testKeyID(b byte) bsoncore.Value {
+	id := make([]byte, 16)
+	id[15] = b
+	return bsoncore.Value{Type: bsoncore.TypeBinary, Data: bsoncore.AppendBinary(nil, 4, id)}
+}
+
+func testKeyDocument(b byte, altNames ...string) bsoncore.Document {
+	names := bsoncore.NewArrayBuilder()
+	for _, name := range altNames {
+		names.AppendString(name)
+	}
+	return bsoncore.NewDocumentBuilder().
+		AppendValue("_id", testKeyID(b)).
+		AppendArray("keyAltNames", names.Build()).
+		Build()
+}
+
+// fakeKeyVault is a key vault collection that records the filters it is queried with.
+type fakeKeyVault struct {
+	keys    []bsoncore.Document
+	filters []bsoncore.Document
+	err     error
+}
+
+func (kv *fakeKeyVault) fetch(_ context.Context, filter bsoncore.Document) ([]bsoncore.Document, error) {
+	kv.filters = append(kv.filters, filter)
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	ids, names, _ := parseKeyFilter(filter)
+	var docs []bsoncore.Document
+	for _, key := range kv.keys {
+		id := string(key.Lookup("_id").Data)
+		matched := false
+		for _, want := range ids {
+			matched = matched || string(want.Data) == id
+		}
+		altNames, _ := key.Lookup("keyAltNames").Array().Values()
+		for _, name := range altNames {
+			for _, want := range names {
+				matched = matched || name.StringValue() == want
+			}
+		}
+		if matched {
+			docs = append(docs, key)
+		}
+	}
+	return docs, nil
+}
+
+func newTestKeyCache(t *testing.T, kv *fakeKeyVault, args *options.AutoEncryptionOptions, now *time.Time) *keyCache {
+	t.Helper()
+
+	kc, err := newKeyCache(kv.fetch, args)
+	require.NoError(t, err, "newKeyCache error")
+	kc.now = func() time.Time { return *now }
+	return kc
+}
+
+func TestKeyCache(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1)}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{}, &now)
+
+		for i := 0; i < 2; i++ {
+			docs, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1)}, nil))
+			require.NoError(t, err, "cryptKeys error")
+			assert.Len(t, docs, 1, "expected one key")
+		}
+		assert.Len(t, kv.filters, 2, "expected every request to query the key vault")
+		assert.Equal(t, KeyCacheStats{Misses: 2, Fetches: 2}, kc.stats(), "stats mismatch")
+	})
+	t.Run("hits by ID and alternate name", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1, "alpha"), testKeyDocument(2, "beta")}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{KeyCacheMaxEntries: ptrutil.Ptr(10)}, &now)
+
+		docs, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1)}, []string{"beta"}))
+		require.NoError(t, err, "cryptKeys error")
+		assert.Len(t, docs, 2, "expected two keys")
+
+		now = now.Add(30 * time.Second)
+		docs, err = kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(2)}, []string{"alpha"}))
+		require.NoError(t, err, "cryptKeys error")
+		assert.Len(t, docs, 2, "expected two keys")
+		assert.Len(t, kv.filters, 1, "expected the second request to be served by the cache")
+
+		// The documents expire after the default key expiration.
+		now = now.Add(30 * time.Second)
+		_, err = kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1), testKeyID(2)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+		assert.Len(t, kv.filters, 2, "expected expired documents to be fetched again")
+
+		assert.Equal(t, KeyCacheStats{Enabled: true, Entries: 2, Hits: 2, Misses: 4, Fetches: 2}, kc.stats(), "stats mismatch")
+	})
+	t.Run("fetches only missing keys", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1), testKeyDocument(2)}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{KeyCacheMaxEntries: ptrutil.Ptr(10)}, &now)
+
+		_, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+		docs, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1), testKeyID(2)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+		assert.Len(t, docs, 2, "expected two keys")
+
+		require.Len(t, kv.filters, 2, "expected two queries")
+		ids, names, ok := parseKeyFilter(kv.filters[1])
+		require.True(t, ok, "expected a libmongocrypt key filter")
+		assert.Equal(t, []bsoncore.Value{testKeyID(2)}, ids, "expected only the missing key to be fetched")
+		assert.Len(t, names, 0, "expected no alternate names")
+	})
+	t.Run("evicts least recently used", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1), testKeyDocument(2), testKeyDocument(3)}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{KeyCacheMaxEntries: ptrutil.Ptr(2)}, &now)
+
+		for _, b := range []byte{1, 2, 1, 3} {
+			now = now.Add(time.Second)
+			_, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(b)}, nil))
+			require.NoError(t, err, "cryptKeys error")
+		}
+		stats := kc.stats()
+		assert.Equal(t, 2, stats.Entries, "expected the cache to be full")
+		assert.Equal(t, int64(1), stats.Evictions, "expected one eviction")
+		_, ok := kc.entries[string(testKeyID(2).Data)]
+		assert.False(t, ok, "expected the least recently used key to be evicted")
+	})
+	t.Run("refreshes used keys before they expire", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1), testKeyDocument(2)}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{
+			KeyExpiration:         ptrutil.Ptr(time.Minute),
+			KeyCacheRefreshBefore: ptrutil.Ptr(10 * time.Second),
+		}, &now)
+
+		_, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1), testKeyID(2)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+
+		now = start.Add(20 * time.Second)
+		kc.refresh(context.Background())
+		assert.Len(t, kv.filters, 1, "expected no refresh before the refresh period")
+
+		now = start.Add(55 * time.Second)
+		kc.refresh(context.Background())
+		assert.Len(t, kv.filters, 2, "expected a refresh within the refresh period")
+
+		// The data keys expire from the libmongocrypt cache and are requested again.
+		now = start.Add(61 * time.Second)
+		_, err = kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+		assert.Len(t, kv.filters, 2, "expected the refreshed key to be served by the cache")
+
+		// Key 2 has not been used during the last key expiration, so it is not refreshed again and
+		// is removed when it expires.
+		now = start.Add(110 * time.Second)
+		kc.refresh(context.Background())
+		require.Len(t, kv.filters, 3, "expected a refresh")
+		ids, _, _ := parseKeyFilter(kv.filters[2])
+		assert.Equal(t, []bsoncore.Value{testKeyID(1)}, ids, "expected only the used key to be refreshed")
+
+		now = start.Add(116 * time.Second)
+		kc.refresh(context.Background())
+		stats := kc.stats()
+		assert.Equal(t, 1, stats.Entries, "expected the unused key to be removed")
+		assert.Equal(t, int64(3), stats.Refreshes, "Refreshes mismatch")
+	})
+	t.Run("keeps keys when a refresh fails", func(t *testing.T) {
+		t.Parallel()
+
+		kv := &fakeKeyVault{keys: []bsoncore.Document{testKeyDocument(1)}}
+		now := start
+		kc := newTestKeyCache(t, kv, &options.AutoEncryptionOptions{KeyCacheRefreshBefore: ptrutil.Ptr(10 * time.Second)}, &now)
+
+		_, err := kc.cryptKeys(context.Background(), keyFilter([]bsoncore.Value{testKeyID(1)}, nil))
+		require.NoError(t, err, "cryptKeys error")
+
+		kv.err = errors.New("key vault unavailable")
+		now = start.Add(55 * time.Second)
+		kc.refresh(context.Background())
+		stats := kc.stats()
+		assert.Equal(t, int64(1), stats.RefreshErrors, "RefreshErrors mismatch")
+		assert.Equal(t, 1, stats.Entries, "expected the key to be kept")
+	})
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newKeyCache(nil, &options.AutoEncryptionOptions{
+			KeyExpiration:         ptrutil.Ptr(time.Minute),
+			KeyCacheRefreshBefore: ptrutil.Ptr(time.Minute),
+		})
+		assert.EqualError(t, err, "key cache refresh before must be less than the key expiration")
+
+		_, err = newKeyCache(nil, &options.AutoEncryptionOptions{KeyCacheMaxEntries: ptrutil.Ptr(-1)})
+		assert.EqualError(t, err, "key cache max entries must not be negative")
+	})
+}
+
+func TestParseKeyFilter(t *testing.T) {
+	t.Parallel()
+
+	ids, names, ok := parseKeyFilter(keyFilter([]bsoncore.Value{testKeyID(1)}, []string{"alpha"}))
+	require.True(t, ok, "expected a libmongocrypt key filter")
+	assert.Equal(t, []bsoncore.Value{testKeyID(1)}, ids, "ids mismatch")
+	assert.Equal(t, []string{"alpha"}, names, "names mismatch")
+
+	_, _, ok = parseKeyFilter(bsoncore.NewDocumentBuilder().AppendString("keyAltNames", "alpha").Build())
+	assert.False(t, ok, "expected other filters not to be parsed")
+}
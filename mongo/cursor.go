@@ -13,9 +13,12 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"runtime"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
@@ -33,9 +36,18 @@ type Cursor struct {
 	bc            batchCursor
 	batch         *bsoncore.Iterator
 	batchLength   int
+	batchCount    int
 	bsonOpts      *options.BSONOptions
 	registry      *bson.Registry
 	clientSession *session.Client
+	memTracker    *cursorMemoryTracker
+	bufferedBytes int64
+	decodeWorkers int
+	failover      *cursorFailover
+	leakGuard     *cursorLeakGuard
+	origin        *cursorOrigin
+	coalesce      *batchCoalescer
+	transforms    *fieldTransformers
 
 	err error
 }
@@ -45,14 +57,19 @@ func newCursor(
 	bsonOpts *options.BSONOptions,
 	registry *bson.Registry,
 ) (*Cursor, error) {
-	return newCursorWithSession(bc, bsonOpts, registry, nil)
+	return newCursorWithSession(context.Background(), bc, bsonOpts, registry, nil, nil, nil)
 }
 
+// newCursorWithSession creates a Cursor for bc. If buffering the initial batch of bc would exceed
+// the memory limit of memTracker, the cursor is closed with ctx and an error is returned.
 func newCursorWithSession(
+	ctx context.Context,
 	bc batchCursor,
 	bsonOpts *options.BSONOptions,
 	registry *bson.Registry,
 	clientSession *session.Client,
+	memTracker *cursorMemoryTracker,
+	leakGuard *cursorLeakGuard,
 ) (*Cursor, error) {
 	if registry == nil {
 		registry = defaultRegistry
@@ -65,15 +82,30 @@ func newCursorWithSession(
 		bsonOpts:      bsonOpts,
 		registry:      registry,
 		clientSession: clientSession,
+		memTracker:    memTracker,
+		leakGuard:     leakGuard,
 	}
 	if bc.ID() == 0 {
 		c.closeImplicitSession()
 	}
 
+	// Account for the initial batch returned by the command that created the cursor.
+	if err := c.bufferBatch(c.bc.Batch()); err != nil {
+		_ = c.Close(ctx)
+		return nil, err
+	}
+	leakGuard.track(c)
+	if c.origin == nil && memTracker != nil {
+		// The leak guard's finalizer releases the buffered batch of tracked cursors, so only
+		// untracked cursors need a finalizer to release it if they are never closed.
+		runtime.SetFinalizer(c, (*Cursor).releaseBatch)
+	}
+
 	// Initialize just the batchLength here so RemainingBatchLength will return an
 	// accurate result. The actual batch will be pulled up by the first
 	// Next/TryNext call.
 	c.batchLength = c.bc.Batch().Count()
+	c.batchCount = c.batchLength
 	return c, nil
 }
 
@@ -132,6 +164,7 @@ func NewCursorFromDocuments(documents []interface{}, preloadedErr error, registr
 	// provided contents, and thus already has a batch before calls to Next/TryNext.
 	c.batch = c.bc.Batch()
 	c.batchLength = c.bc.Batch().Count()
+	c.batchCount = c.batchLength
 
 	return c, nil
 }
@@ -181,8 +214,12 @@ func (c *Cursor) next(ctx context.Context, nonBlocking bool) bool {
 		// Consume the next document in the current batch.
 		c.batchLength--
 		c.Current = bson.Raw(val.Data)
+		c.countReturned()
 		return true
 	case errors.Is(err, io.EOF): // Need to do a getMore
+		if c.failover != nil {
+			c.failover.recordLast(c.Current)
+		}
 	default:
 		c.err = err
 		return false
@@ -191,11 +228,29 @@ func (c *Cursor) next(ctx context.Context, nonBlocking bool) bool {
 	// call the Next method in a loop until at least one document is returned in the next batch or
 	// the context times out.
 	for {
+		// The current batch has been consumed, so its memory can be reused by other cursors.
+		c.releaseBatch()
+		if c.err = c.memTracker.waitForCapacity(ctx); c.err != nil {
+			return false
+		}
+
+		// Report the error of a getMore that ended the previous combined batch.
+		if err := c.coalesce.takeErr(); err != nil {
+			c.err = replaceErrors(err)
+			if c.handleCursorLost(ctx) {
+				continue
+			}
+			return false
+		}
+
 		// If we don't have a next batch
 		if !c.bc.Next(ctx) {
 			// Do we have an error? If so we return false.
 			c.err = replaceErrors(c.bc.Err())
 			if c.err != nil {
+				if c.handleCursorLost(ctx) {
+					continue
+				}
 				return false
 			}
 			// Is the cursor ID zero?
@@ -217,13 +272,22 @@ func (c *Cursor) next(ctx context.Context, nonBlocking bool) bool {
 		}
 
 		// Use the new batch to update the batch and batchLength fields. Consume the first document in the batch.
+		// TryNext runs at most one getMore, so only combine batches for Next.
 		c.batch = c.bc.Batch()
+		if !nonBlocking {
+			c.batch = c.coalesce.coalesce(ctx, c.bc, c.batch)
+		}
+		if c.err = c.bufferBatch(c.batch); c.err != nil {
+			return false
+		}
 		c.batchLength = c.batch.Count()
+		c.batchCount = c.batchLength
 		val, err = c.batch.Next()
 		switch {
 		case err == nil:
 			c.batchLength--
 			c.Current = bson.Raw(val.Data)
+			c.countReturned()
 			return true
 		case errors.Is(err, io.EOF): // Empty batch so we continue
 		default:
@@ -233,12 +297,21 @@ func (c *Cursor) next(ctx context.Context, nonBlocking bool) bool {
 	}
 }
 
+// countReturned counts a document returned by Next or TryNext for the failover state of the
+// cursor.
+func (c *Cursor) countReturned() {
+	if c.failover != nil {
+		c.failover.returned++
+		c.failover.restarts = 0
+	}
+}
+
 func getDecoder(
 	data []byte,
 	opts *options.BSONOptions,
 	reg *bson.Registry,
 ) *bson.Decoder {
-	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(data)))
+	dec := bson.NewDecoder(bson.NewDocumentReaderFromBytes(data))
 
 	if opts != nil {
 		if opts.AllowTruncatingDoubles {
@@ -250,21 +323,33 @@ func getDecoder(
 		if opts.DefaultDocumentM {
 			dec.DefaultDocumentM()
 		}
+		if opts.DefaultDocumentOrderedMap {
+			dec.DefaultDocumentOrderedMap()
+		}
 		if opts.ObjectIDAsHexString {
 			dec.ObjectIDAsHexString()
 		}
 		if opts.UseJSONStructTags {
 			dec.UseJSONStructTags()
 		}
+		if opts.UseJSONAndTextMarshalers {
+			dec.UseJSONAndTextMarshalers()
+		}
 		if opts.UseLocalTimeZone {
 			dec.UseLocalTimeZone()
 		}
+		if opts.UnsafeZeroCopy {
+			dec.UnsafeZeroCopy()
+		}
 		if opts.ZeroMaps {
 			dec.ZeroMaps()
 		}
 		if opts.ZeroStructs {
 			dec.ZeroStructs()
 		}
+		if opts.Allocator != nil {
+			dec.SetAllocator(opts.Allocator)
+		}
 	}
 
 	if reg != nil {
@@ -277,18 +362,39 @@ func getDecoder(
 // Decode will unmarshal the current document into val and return any errors from the unmarshalling process without any
 // modification. If val is nil or is a typed nil, an error will be returned.
 func (c *Cursor) Decode(val interface{}) error {
-	dec := getDecoder(c.Current, c.bsonOpts, c.registry)
+	doc, err := c.transforms.strip(c.Current)
+	if err != nil {
+		return err
+	}
+	dec := getDecoder(doc, c.bsonOpts, c.registry)
 
 	return dec.Decode(val)
 }
 
+// DecodeInto is like Decode, but allows the value pointed to by val to be reused. If reuse is false, the value is
+// reset to its zero value before the current document is decoded into it. If reuse is true, the document is decoded
+// into the value as it is, so memory referenced by the value, such as a map, slice, or pointer field, can be reused
+// across calls. In that case fields that are not present in the document keep their previous values.
+func (c *Cursor) DecodeInto(val interface{}, reuse bool) error {
+	if !reuse {
+		rv := reflect.ValueOf(val)
+		if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		}
+	}
+
+	return c.Decode(val)
+}
+
 // Err returns the last error seen by the Cursor, or nil if no error has occurred.
 func (c *Cursor) Err() error { return c.err }
 
 // Close closes this cursor. Next and TryNext must not be called after Close has been called. Close is idempotent. After
 // the first call, any subsequent calls will not change the state.
 func (c *Cursor) Close(ctx context.Context) error {
+	c.leakGuard.untrack(c)
 	defer c.closeImplicitSession()
+	c.releaseBatch()
 	return replaceErrors(c.bc.Close(ctx))
 }
 
@@ -299,6 +405,23 @@ func (c *Cursor) Close(ctx context.Context) error {
 //
 // This method requires driver version >= 1.1.0.
 func (c *Cursor) All(ctx context.Context, results interface{}) error {
+	return c.all(ctx, results, false, false)
+}
+
+// AllInto is like All, but reuses the memory already allocated for results to reduce allocations on
+// hot read paths. Documents are decoded into the existing elements of the slice, including any
+// elements between its length and its capacity, and the slice only grows if its capacity is
+// exceeded. The slice pointed to by results is resliced to the number of documents returned.
+//
+// If reuse is false, each element is reset to its zero value before a document is decoded into it.
+// If reuse is true, documents are decoded into elements as they are, so memory referenced by an
+// element, such as a map, slice, or pointer field, can be reused. In that case fields that are not
+// present in the document keep their previous values.
+func (c *Cursor) AllInto(ctx context.Context, results interface{}, reuse bool) error {
+	return c.all(ctx, results, true, !reuse)
+}
+
+func (c *Cursor) all(ctx context.Context, results interface{}, useCapacity, reset bool) error {
 	resultsVal := reflect.ValueOf(results)
 	if resultsVal.Kind() != reflect.Ptr {
 		return fmt.Errorf("results argument must be a pointer to a slice, but was a %s", resultsVal.Kind())
@@ -317,6 +440,10 @@ func (c *Cursor) All(ctx context.Context, results interface{}) error {
 	var index int
 	var err error
 
+	if useCapacity {
+		sliceVal = sliceVal.Slice(0, sliceVal.Cap())
+	}
+
 	// Defer a call to Close to try to clean up the cursor server-side when all
 	// documents have not been exhausted. Use context.Background() to ensure Close
 	// completes even if the context passed to All has errored.
@@ -324,16 +451,24 @@ func (c *Cursor) All(ctx context.Context, results interface{}) error {
 
 	batch := c.batch // exhaust the current batch before iterating the batch cursor
 	for {
-		sliceVal, index, err = c.addFromBatch(sliceVal, elementType, batch, index)
+		sliceVal, index, err = c.addFromBatch(sliceVal, elementType, batch, index, reset)
 		if err != nil {
 			return err
 		}
 
+		c.releaseBatch()
+		if err = c.memTracker.waitForCapacity(ctx); err != nil {
+			return err
+		}
+
 		if !c.bc.Next(ctx) {
 			break
 		}
 
 		batch = c.bc.Batch()
+		if err = c.bufferBatch(batch); err != nil {
+			return err
+		}
 	}
 
 	if err = replaceErrors(c.bc.Err()); err != nil {
@@ -344,28 +479,134 @@ func (c *Cursor) All(ctx context.Context, results interface{}) error {
 	return nil
 }
 
+// BufferedBytes returns the number of bytes of the batch currently buffered by the cursor. The
+// buffered batch is released when all of its documents have been iterated or the cursor is closed.
+func (c *Cursor) BufferedBytes() int64 {
+	return c.bufferedBytes
+}
+
+// bufferBatch accounts for the memory used by batch.
+func (c *Cursor) bufferBatch(batch *bsoncore.Iterator) error {
+	if batch == nil {
+		return nil
+	}
+
+	n := int64(len(batch.List))
+	if err := c.memTracker.reserve(n); err != nil {
+		return err
+	}
+	c.bufferedBytes = n
+	return nil
+}
+
+// releaseBatch releases the memory accounted for the current batch.
+func (c *Cursor) releaseBatch() {
+	c.memTracker.release(c.bufferedBytes)
+	c.bufferedBytes = 0
+}
+
 // RemainingBatchLength returns the number of documents left in the current batch. If this returns zero, the subsequent
 // call to Next or TryNext will do a network request to fetch the next batch.
 func (c *Cursor) RemainingBatchLength() int {
 	return c.batchLength
 }
 
-// addFromBatch adds all documents from batch to sliceVal starting at the given index. It returns the new slice value,
-// the next empty index in the slice, and an error if one occurs.
+// BatchLength returns the number of documents in the current batch, including the documents that
+// have already been iterated. Before the first call to Next or TryNext, the current batch is the
+// batch returned by the command that created the cursor.
+func (c *Cursor) BatchLength() int {
+	return c.batchCount
+}
+
+// RemainingBatch returns the documents left in the current batch without advancing the cursor, so
+// that they can be processed together, for example to prefetch related data for the whole batch.
+// The documents are only valid until the call to Next or TryNext that fetches the next batch. If
+// continued access is required, a copy must be made.
+func (c *Cursor) RemainingBatch() ([]bson.Raw, error) {
+	batch := c.batch
+	if batch == nil {
+		batch = c.bc.Batch()
+	}
+	docs, err := batch.Documents()
+	if err != nil {
+		return nil, err
+	}
+
+	start := len(docs) - c.batchLength
+	if start < 0 {
+		start = 0
+	}
+	remaining := make([]bson.Raw, 0, len(docs)-start)
+	for _, doc := range docs[start:] {
+		remaining = append(remaining, bson.Raw(doc))
+	}
+	return remaining, nil
+}
+
+// cursorMetadata is implemented by batch cursors that know where they were created.
+type cursorMetadata interface {
+	ServerAddress() address.Address
+	Namespace() (database, collection string)
+}
+
+// Server returns the address of the server the cursor was created on, which also serves the
+// getMore commands that fetch the following batches. It returns an empty address for cursors
+// that are not backed by a server cursor, such as the ones created by NewCursorFromDocuments.
+func (c *Cursor) Server() address.Address {
+	if md, ok := c.bc.(cursorMetadata); ok {
+		return md.ServerAddress()
+	}
+	return ""
+}
+
+// Namespace returns the namespace of the cursor in the form "database.collection". For cursors
+// created by commands that are not run against a collection, such as a database aggregation, the
+// collection is the one reported by the server, such as "$cmd.aggregate". It returns an empty
+// string for cursors that are not backed by a server cursor.
+func (c *Cursor) Namespace() string {
+	md, ok := c.bc.(cursorMetadata)
+	if !ok {
+		return ""
+	}
+	db, coll := md.Namespace()
+	if db == "" && coll == "" {
+		return ""
+	}
+	return db + "." + coll
+}
+
+// PostBatchResumeToken returns the postBatchResumeToken of the last batch returned by the server
+// for a change stream cursor, or nil if the server did not return one.
+func (c *Cursor) PostBatchResumeToken() bson.Raw {
+	if pbrt, ok := c.bc.(interface{ PostBatchResumeToken() bsoncore.Document }); ok {
+		return bson.Raw(pbrt.PostBatchResumeToken())
+	}
+	return nil
+}
+
+// addFromBatch adds all documents from batch to sliceVal starting at the given index. If reset is true, existing
+// elements are set to their zero value before being decoded into. It returns the new slice value, the next empty index
+// in the slice, and an error if one occurs.
 func (c *Cursor) addFromBatch(sliceVal reflect.Value, elemType reflect.Type, batch *bsoncore.Iterator,
-	index int) (reflect.Value, int, error) {
+	index int, reset bool) (reflect.Value, int, error) {
 
 	docs, err := batch.Documents()
 	if err != nil {
 		return sliceVal, index, err
 	}
 
+	if c.decodeWorkers > 1 && len(docs) > 1 {
+		return c.addFromBatchParallel(sliceVal, elemType, docs, index, reset)
+	}
+
 	for _, doc := range docs {
 		if sliceVal.Len() == index {
 			// slice is full
 			newElem := reflect.New(elemType)
 			sliceVal = reflect.Append(sliceVal, newElem.Elem())
 			sliceVal = sliceVal.Slice(0, sliceVal.Cap())
+		} else if reset {
+			sliceVal.Index(index).Set(reflect.Zero(elemType))
 		}
 
 		currElem := sliceVal.Index(index).Addr().Interface()
@@ -381,6 +622,68 @@ func (c *Cursor) addFromBatch(sliceVal reflect.Value, elemType reflect.Type, bat
 	return sliceVal, index, nil
 }
 
+// addFromBatchParallel is like addFromBatch, but decodes the documents with up to c.decodeWorkers
+// goroutines. Each document is decoded into the slice element at its position, so the order of the
+// results is preserved. If decoding fails, the returned index is the position of the first document
+// that could not be decoded, as it would be when decoding sequentially.
+func (c *Cursor) addFromBatchParallel(sliceVal reflect.Value, elemType reflect.Type, docs []bsoncore.Document,
+	index int, reset bool) (reflect.Value, int, error) {
+
+	oldLen := sliceVal.Len()
+	if n := index + len(docs); oldLen < n {
+		sliceVal = reflect.AppendSlice(sliceVal, reflect.MakeSlice(sliceVal.Type(), n-oldLen, n-oldLen))
+		sliceVal = sliceVal.Slice(0, sliceVal.Cap())
+	}
+	if reset {
+		for i := index; i < oldLen && i < index+len(docs); i++ {
+			sliceVal.Index(i).Set(reflect.Zero(elemType))
+		}
+	}
+
+	workers := c.decodeWorkers
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+
+	errs := make([]error, len(docs))
+	next := make(chan int, len(docs))
+	for i := range docs {
+		next <- i
+	}
+	close(next)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				currElem := sliceVal.Index(index + i).Addr().Interface()
+				errs[i] = getDecoder(docs[i], c.bsonOpts, c.registry).Decode(currElem)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return sliceVal, index + i, err
+		}
+	}
+	return sliceVal, index + len(docs), nil
+}
+
+// SetDecodeWorkers sets the number of goroutines that All and AllInto use to decode the documents
+// of each batch. Decoding is CPU-bound, so decoding large batches of documents in parallel can
+// reduce the time spent in All. The documents are stored in the results slice in the order they
+// were returned by the server regardless of the number of workers. The registry and any custom
+// decoders used by the cursor must be safe for concurrent use. A value of 0 or 1, the default,
+// decodes documents sequentially.
+func (c *Cursor) SetDecodeWorkers(workers int) {
+	c.decodeWorkers = workers
+}
+
 func (c *Cursor) closeImplicitSession() {
 	if c.clientSession != nil && c.clientSession.IsImplicit {
 		c.clientSession.EndSession()
@@ -403,6 +706,7 @@ func (c *Cursor) SetBatchSize(batchSize int32) {
 // down to the nearest millisecond.
 func (c *Cursor) SetMaxAwaitTime(dur time.Duration) {
 	c.bc.SetMaxAwaitTime(dur)
+	c.coalesce.setMaxAwaitTime(dur)
 }
 
 // SetComment will set a user-configurable comment that can be used to identify
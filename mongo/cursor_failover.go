@@ -0,0 +1,331 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// maxCursorRestarts is the maximum number of consecutive times a cursor restarts its query, after
+// the server it was created on becomes unreachable or reports CursorNotFound, without returning a
+// document in between.
+const maxCursorRestarts = 3
+
+// CursorHostUnreachableError is the error of a Cursor returned by Find when the server the cursor
+// was created on, e.g. the mongos a sharded cursor is pinned to, becomes unreachable while the
+// cursor is iterated. The cursor cannot be resumed on another server, but the error contains the
+// state needed by the application to restart the query, such as the _id of the last document
+// returned. See FindOptionsBuilder.SetRestartOnHostUnreachable to restart eligible queries
+// automatically.
+type CursorHostUnreachableError struct {
+	// Host is the address of the unreachable server.
+	Host address.Address
+
+	// Namespace is the namespace of the query, in the form "database.collection".
+	Namespace string
+
+	// FilterHash is the hex-encoded SHA-256 hash of the query filter document, which identifies the
+	// query without including the filter values.
+	FilterHash string
+
+	// LastID is the _id of the last document returned by the cursor. It is the zero value if no
+	// document has been returned or the documents do not include _id.
+	LastID bson.RawValue
+
+	// DocumentsReturned is the number of documents returned by the cursor.
+	DocumentsReturned int64
+
+	// RestartErr is the error of the automatic restart of the query, if it was attempted.
+	RestartErr error
+
+	// Err is the error that made the server unreachable.
+	Err error
+}
+
+// Error implements the error interface.
+func (e CursorHostUnreachableError) Error() string {
+	msg := fmt.Sprintf("cursor on %s for namespace %q lost its server after %d documents: %v",
+		e.Host, e.Namespace, e.DocumentsReturned, e.Err)
+	if e.RestartErr != nil {
+		msg += fmt.Sprintf("; restarting the query failed: %v", e.RestartErr)
+	}
+	return msg
+}
+
+// Unwrap returns the error that made the server unreachable.
+func (e CursorHostUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// isHostUnreachable reports whether err means that the server of a cursor cannot be reached to
+// run getMore commands.
+func isHostUnreachable(err error) bool {
+	if IsNetworkError(err) || errors.Is(err, topology.ErrServerClosed) {
+		return true
+	}
+	var pe driver.RetryablePoolError
+	return errors.As(err, &pe) && pe.Retryable()
+}
+
+// isCursorNotFound reports whether err is a CursorNotFound error returned by getMore, e.g. because
+// the server killed an idle tailable cursor.
+func isCursorNotFound(err error) bool {
+	var ce CommandError
+	return errors.As(err, &ce) && ce.Code == errorCursorNotFound
+}
+
+// cursorRestartFunc runs the query of a cursor again, resuming after the document with the given
+// resume value if it is not the zero value.
+type cursorRestartFunc func(ctx context.Context, lastID bson.RawValue, returned int64) (*Cursor, error)
+
+// cursorFailover records the state of a Find cursor needed to report or recover from the loss of
+// its server or of its server-side cursor.
+type cursorFailover struct {
+	namespace string
+	filter    bsoncore.Document
+	returned  int64
+	restarts  int
+
+	// lastID is the _id of the last returned document, and lastResume is the value of resumeField
+	// if it is not _id.
+	lastID      bson.RawValue
+	lastResume  bson.RawValue
+	resumeField string
+
+	// resumable is whether the query can be resumed after the last returned document. The query is
+	// restarted when its server becomes unreachable if restartOnUnreachable is set, or when the
+	// server reports CursorNotFound if restartOnNotFound is set.
+	resumable            bool
+	restartOnUnreachable bool
+	restartOnNotFound    bool
+	restart              cursorRestartFunc
+}
+
+// recordLast copies the _id and resume field of doc, the last document returned before fetching
+// the next batch.
+func (cf *cursorFailover) recordLast(doc bson.Raw) {
+	if doc == nil {
+		return
+	}
+	cf.lastID = copyRawValue(doc, "_id")
+	if cf.resumeField != "" && cf.resumeField != "_id" {
+		cf.lastResume = copyRawValue(doc, cf.resumeField)
+	}
+}
+
+// resumeValue returns the value resumed after when the query is restarted.
+func (cf *cursorFailover) resumeValue() bson.RawValue {
+	if cf.resumeField != "" && cf.resumeField != "_id" {
+		return cf.lastResume
+	}
+	return cf.lastID
+}
+
+func copyRawValue(doc bson.Raw, key string) bson.RawValue {
+	val, err := doc.LookupErr(key)
+	if err != nil {
+		return bson.RawValue{}
+	}
+	return bson.RawValue{Type: val.Type, Value: append([]byte(nil), val.Value...)}
+}
+
+func (cf *cursorFailover) newError(host address.Address, err error) CursorHostUnreachableError {
+	sum := sha256.Sum256(cf.filter)
+	return CursorHostUnreachableError{
+		Host:              host,
+		Namespace:         cf.namespace,
+		FilterHash:        hex.EncodeToString(sum[:]),
+		LastID:            cf.lastID,
+		DocumentsReturned: cf.returned,
+		Err:               err,
+	}
+}
+
+// canRestart reports whether the query can be restarted without returning documents again.
+func (cf *cursorFailover) canRestart() bool {
+	if cf.restart == nil || cf.restarts >= maxCursorRestarts {
+		return false
+	}
+	return cf.returned == 0 || (cf.resumable && cf.resumeValue().Type != 0)
+}
+
+// handleCursorLost handles the error of a getMore that lost the server-side cursor. If a tailable
+// cursor was not found on the server, the query is restarted if enabled. If the server of the
+// cursor became unreachable, the query is restarted if enabled, and c.err is replaced with a
+// CursorHostUnreachableError otherwise. It returns true if the query was restarted and iteration
+// can continue with the new batch cursor.
+func (c *Cursor) handleCursorLost(ctx context.Context) bool {
+	cf := c.failover
+	if cf == nil {
+		return false
+	}
+
+	if isCursorNotFound(c.err) {
+		if !cf.restartOnNotFound || !cf.canRestart() {
+			return false
+		}
+		restarted, err := cf.restart(ctx, cf.resumeValue(), cf.returned)
+		if err != nil {
+			// Keep reporting CursorNotFound, which the application handles the same way as when
+			// restarts are disabled.
+			return false
+		}
+		return c.adopt(ctx, restarted)
+	}
+
+	if !isHostUnreachable(c.err) {
+		return false
+	}
+
+	hostErr := cf.newError(c.Server(), c.err)
+	if !cf.restartOnUnreachable || !cf.canRestart() {
+		c.err = hostErr
+		return false
+	}
+
+	restarted, err := cf.restart(ctx, cf.resumeValue(), cf.returned)
+	if err != nil {
+		hostErr.RestartErr = err
+		c.err = hostErr
+		return false
+	}
+	return c.adopt(ctx, restarted)
+}
+
+// adopt replaces the batch cursor and session of c with those of the restarted cursor. It returns
+// false if the restarted cursor has an error, which becomes the error of c.
+func (c *Cursor) adopt(ctx context.Context, restarted *Cursor) bool {
+	c.failover.restarts++
+	if restarted.err != nil {
+		_ = restarted.Close(ctx)
+		c.err = restarted.err
+		return false
+	}
+
+	// Release the old cursor without waiting to reach its server, which only unpins its
+	// connection when running against a load balancer.
+	killCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_ = c.bc.Close(killCtx)
+	c.closeImplicitSession()
+
+	// The batch of the new cursor is accounted for again when it is read by next. The new cursor
+	// is dropped after its batch cursor and session are moved to c, so remove its leak finalizer
+	// to keep it from closing them when it is garbage collected.
+	restarted.releaseBatch()
+	restarted.leakGuard.untrack(restarted)
+	c.bc = restarted.bc
+	c.clientSession = restarted.clientSession
+	c.err = nil
+	return true
+}
+
+// newCursorFailover creates the failover state of a cursor returned by Find with the given filter
+// and options.
+func (coll *Collection) newCursorFailover(
+	ctx context.Context,
+	filter bsoncore.Document,
+	omitMaxTimeMS bool,
+	args *options.FindOptions,
+) *cursorFailover {
+	cf := &cursorFailover{
+		namespace: coll.db.name + "." + coll.name,
+		filter:    filter,
+	}
+
+	// Tailable cursors return documents in insertion order, in which the values of the resume
+	// field must increase.
+	field, cmp := "_id", "$gt"
+	tailable := args.CursorType != nil && *args.CursorType != options.NonTailable
+	switch {
+	case tailable && args.TailableResumeField != nil:
+		cf.restartOnNotFound = true
+		cf.resumable = true
+		cf.resumeField = *args.TailableResumeField
+		field = cf.resumeField
+	case !tailable && args.RestartOnHostUnreachable != nil && *args.RestartOnHostUnreachable:
+		cf.restartOnUnreachable = true
+		if args.Sort != nil {
+			if sort, err := marshal(args.Sort, coll.bsonOpts, coll.registry); err == nil {
+				dir := idSortDirection(sort)
+				cf.resumable = dir != 0
+				if dir < 0 {
+					cmp = "$lt"
+				}
+			}
+		}
+	default:
+		return cf
+	}
+
+	// Restart in the session of the original query if it was explicit. Implicit sessions are
+	// replaced with the implicit session of the restarted query.
+	sess := SessionFromContext(ctx)
+	cf.restart = func(ctx context.Context, last bson.RawValue, returned int64) (*Cursor, error) {
+		if sess != nil {
+			ctx = NewSessionContext(ctx, sess)
+		}
+
+		restartArgs := *args
+		restartArgs.RestartOnHostUnreachable = nil
+		restartArgs.TailableResumeField = nil
+		if returned == 0 {
+			return coll.find(ctx, bson.Raw(filter), omitMaxTimeMS, &restartArgs)
+		}
+
+		resumeFilter := bson.D{{"$and", bson.A{
+			bson.Raw(filter),
+			bson.D{{field, bson.D{{cmp, last}}}},
+		}}}
+
+		// The documents skipped by the original query precede the last returned document.
+		restartArgs.Skip = nil
+		if args.Limit != nil {
+			limit := *args.Limit
+			if limit < 0 {
+				limit = -limit
+			}
+			remaining := limit - returned
+			if remaining <= 0 {
+				return newEmptyCursor(), nil
+			}
+			restartArgs.Limit = &remaining
+		}
+		return coll.find(ctx, resumeFilter, omitMaxTimeMS, &restartArgs)
+	}
+	return cf
+}
+
+// idSortDirection returns 1 or -1 if sort only sorts by _id in ascending or descending order, or 0
+// otherwise.
+func idSortDirection(sort bsoncore.Document) int {
+	elems, err := sort.Elements()
+	if err != nil || len(elems) != 1 || elems[0].Key() != "_id" {
+		return 0
+	}
+	dir, ok := elems[0].Value().AsInt64OK()
+	switch {
+	case !ok:
+		return 0
+	case dir == 1:
+		return 1
+	case dir == -1:
+		return -1
+	}
+	return 0
+}
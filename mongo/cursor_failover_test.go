@@ -0,0 +1,288 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// unreachableBatchCursor returns its batches and then fails with failErr, or as if its server
+// became unreachable if failErr is nil.
+type unreachableBatchCursor struct {
+	testBatchCursor
+	failErr error
+	err     error
+}
+
+func (ubc *unreachableBatchCursor) ID() int64 { return 10 }
+
+func (ubc *unreachableBatchCursor) Next(ctx context.Context) bool {
+	if len(ubc.batches) == 0 {
+		ubc.err = ubc.failErr
+		if ubc.err == nil {
+			ubc.err = driver.Error{Message: "connection reset", Labels: []string{driver.NetworkError}}
+		}
+		return false
+	}
+	return ubc.testBatchCursor.Next(ctx)
+}
+
+func (ubc *unreachableBatchCursor) Err() error { return ubc.err }
+
+func newIDBatchCursor(ids ...int32) *testBatchCursor {
+	values := make([]bsoncore.Value, 0, len(ids))
+	for _, id := range ids {
+		doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "_id", id))
+		values = append(values, bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: doc})
+	}
+	return &testBatchCursor{
+		batches: []*bsoncore.Iterator{{List: bsoncore.BuildArray(nil, values...)}},
+	}
+}
+
+func newUnreachableCursor(t *testing.T, cf *cursorFailover, ids ...int32) *Cursor {
+	t.Helper()
+
+	return newFailingCursor(t, cf, nil, ids...)
+}
+
+func newFailingCursor(t *testing.T, cf *cursorFailover, failErr error, ids ...int32) *Cursor {
+	t.Helper()
+
+	bc := &unreachableBatchCursor{testBatchCursor: *newIDBatchCursor(ids...), failErr: failErr}
+	cursor, err := newCursor(bc, nil, nil)
+	require.NoError(t, err, "newCursor error")
+	cursor.failover = cf
+	return cursor
+}
+
+func TestCursorFailover(t *testing.T) {
+	t.Parallel()
+
+	filter := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "status", "active"))
+
+	t.Run("host unreachable error", func(t *testing.T) {
+		t.Parallel()
+
+		cf := &cursorFailover{namespace: "db.coll", filter: filter, restartOnNotFound: true}
+		cursor := newUnreachableCursor(t, cf, 1, 2)
+		for cursor.Next(context.Background()) {
+		}
+
+		var hostErr CursorHostUnreachableError
+		require.True(t, errors.As(cursor.Err(), &hostErr), "expected CursorHostUnreachableError, got %v", cursor.Err())
+		assert.Equal(t, "db.coll", hostErr.Namespace, "namespace mismatch")
+		assert.Equal(t, int64(2), hostErr.DocumentsReturned, "documents returned mismatch")
+		assert.Equal(t, int32(2), hostErr.LastID.Int32(), "last _id mismatch")
+		assert.Equal(t, 64, len(hostErr.FilterHash), "expected a hex-encoded SHA-256 hash")
+		assert.True(t, IsNetworkError(cursor.Err()), "expected the network error to be wrapped")
+	})
+
+	t.Run("restarts after the last document", func(t *testing.T) {
+		t.Parallel()
+
+		var gotID bson.RawValue
+		var gotReturned int64
+		cf := &cursorFailover{
+			namespace:            "db.coll",
+			filter:               filter,
+			resumable:            true,
+			restartOnUnreachable: true,
+			restart: func(_ context.Context, lastID bson.RawValue, returned int64) (*Cursor, error) {
+				gotID, gotReturned = lastID, returned
+				return newCursor(newIDBatchCursor(3, 4), nil, nil)
+			},
+		}
+		cursor := newUnreachableCursor(t, cf, 1, 2)
+
+		var ids []int32
+		for cursor.Next(context.Background()) {
+			ids = append(ids, cursor.Current.Lookup("_id").Int32())
+		}
+		require.NoError(t, cursor.Err(), "cursor error")
+		assert.Equal(t, []int32{1, 2, 3, 4}, ids, "documents mismatch")
+		assert.Equal(t, int32(2), gotID.Int32(), "restart _id mismatch")
+		assert.Equal(t, int64(2), gotReturned, "restart documents returned mismatch")
+	})
+
+	t.Run("restart untracks the restarted cursor", func(t *testing.T) {
+		t.Parallel()
+
+		g := newCursorLeakGuard(func(options.CursorLeak) {}, true)
+		var restarted *Cursor
+		cf := &cursorFailover{
+			namespace:            "db.coll",
+			filter:               filter,
+			resumable:            true,
+			restartOnUnreachable: true,
+			restart: func(context.Context, bson.RawValue, int64) (*Cursor, error) {
+				var err error
+				restarted, err = newCursorWithSession(context.Background(), newIDBatchCursor(2), nil, nil, nil, nil, g)
+				return restarted, err
+			},
+		}
+		cursor := newUnreachableCursor(t, cf, 1)
+		require.True(t, cursor.Next(context.Background()), "expected the first document")
+		require.True(t, cursor.Next(context.Background()), "expected the document after the restart")
+
+		require.NotNil(t, restarted, "expected the cursor to be restarted")
+		assert.Nil(t, restarted.origin, "expected the restarted cursor to be untracked")
+	})
+
+	t.Run("does not restart unsorted queries", func(t *testing.T) {
+		t.Parallel()
+
+		cf := &cursorFailover{
+			namespace:            "db.coll",
+			filter:               filter,
+			restartOnUnreachable: true,
+			restart: func(context.Context, bson.RawValue, int64) (*Cursor, error) {
+				t.Error("unexpected restart")
+				return nil, nil
+			},
+		}
+		cursor := newUnreachableCursor(t, cf, 1)
+		for cursor.Next(context.Background()) {
+		}
+
+		var hostErr CursorHostUnreachableError
+		assert.True(t, errors.As(cursor.Err(), &hostErr), "expected CursorHostUnreachableError, got %v", cursor.Err())
+	})
+
+	t.Run("restart error", func(t *testing.T) {
+		t.Parallel()
+
+		restartErr := errors.New("no servers")
+		cf := &cursorFailover{
+			namespace:            "db.coll",
+			filter:               filter,
+			resumable:            true,
+			restartOnUnreachable: true,
+			restart: func(context.Context, bson.RawValue, int64) (*Cursor, error) {
+				return nil, restartErr
+			},
+		}
+		cursor := newUnreachableCursor(t, cf, 1)
+		for cursor.Next(context.Background()) {
+		}
+
+		var hostErr CursorHostUnreachableError
+		require.True(t, errors.As(cursor.Err(), &hostErr), "expected CursorHostUnreachableError, got %v", cursor.Err())
+		assert.Equal(t, restartErr, hostErr.RestartErr, "restart error mismatch")
+	})
+
+	t.Run("tailable cursor not found", func(t *testing.T) {
+		t.Parallel()
+
+		notFound := driver.Error{Code: errorCursorNotFound, Message: "cursor id 10 not found"}
+		restarts := 0
+		cf := &cursorFailover{
+			namespace:         "db.coll",
+			filter:            filter,
+			resumable:         true,
+			restartOnNotFound: true,
+			resumeField:       "_id",
+			restart: func(_ context.Context, last bson.RawValue, _ int64) (*Cursor, error) {
+				restarts++
+				assert.Equal(t, int32(restarts), last.Int32(), "resume value mismatch")
+				if restarts == 2 {
+					return newCursor(newIDBatchCursor(3), nil, nil)
+				}
+				return newFailingCursor(t, nil, notFound, int32(restarts+1)), nil
+			},
+		}
+		cursor := newFailingCursor(t, cf, notFound, 1)
+
+		var ids []int32
+		for cursor.Next(context.Background()) {
+			ids = append(ids, cursor.Current.Lookup("_id").Int32())
+		}
+		require.NoError(t, cursor.Err(), "cursor error")
+		assert.Equal(t, []int32{1, 2, 3}, ids, "documents mismatch")
+		assert.Equal(t, 2, restarts, "restart count mismatch")
+	})
+
+	t.Run("tailable restart disabled", func(t *testing.T) {
+		t.Parallel()
+
+		notFound := driver.Error{Code: errorCursorNotFound, Message: "cursor id 10 not found"}
+		cursor := newFailingCursor(t, &cursorFailover{namespace: "db.coll", filter: filter}, notFound, 1)
+		for cursor.Next(context.Background()) {
+		}
+		assert.True(t, isCursorNotFound(cursor.Err()), "expected CursorNotFound, got %v", cursor.Err())
+	})
+
+	t.Run("tailable options", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := mongoutil.NewOptions[options.FindOptions](
+			options.Find().SetCursorType(options.TailableAwait).SetTailableResumeField("ts").SetRestartOnHostUnreachable(true))
+		require.NoError(t, err, "options error")
+
+		cf := setupColl("tailable").newCursorFailover(context.Background(), filter, false, args)
+		assert.True(t, cf.restartOnNotFound, "expected restart on CursorNotFound")
+		assert.False(t, cf.restartOnUnreachable, "expected no restart on unreachable host for tailable cursors")
+		assert.Equal(t, "ts", cf.resumeField, "resume field mismatch")
+
+		cf.recordLast(bson.Raw(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "_id", 1), bsoncore.AppendTimestampElement(nil, "ts", 10, 1))))
+		assert.Equal(t, bson.TypeTimestamp, cf.resumeValue().Type, "expected the ts value to be resumed after")
+	})
+
+	t.Run("restart after limit", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := mongoutil.NewOptions[options.FindOptions](
+			options.Find().SetRestartOnHostUnreachable(true).SetSort(bson.D{{"_id", -1}}).SetLimit(2))
+		require.NoError(t, err, "options error")
+
+		cf := setupColl("restart").newCursorFailover(context.Background(), filter, false, args)
+		require.NotNil(t, cf.restart, "expected restart to be enabled")
+		assert.True(t, cf.resumable, "expected query sorted by _id to be resumable")
+
+		cursor, err := cf.restart(context.Background(), bson.RawValue{Type: bson.TypeInt32, Value: bsoncore.AppendInt32(nil, 2)}, 2)
+		require.NoError(t, err, "restart error")
+		assert.False(t, cursor.Next(context.Background()), "expected no documents after the limit")
+	})
+}
+
+func TestIDSortDirection(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		sort bson.D
+		want int
+	}{
+		{"ascending", bson.D{{"_id", 1}}, 1},
+		{"descending", bson.D{{"_id", int64(-1)}}, -1},
+		{"other field", bson.D{{"a", 1}}, 0},
+		{"compound", bson.D{{"_id", 1}, {"a", 1}}, 0},
+		{"text score", bson.D{{"_id", bson.D{{"$meta", "textScore"}}}}, 0},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sort, err := bson.Marshal(tc.sort)
+			require.NoError(t, err, "Marshal error")
+			assert.Equal(t, tc.want, idSortDirection(sort), "direction mismatch")
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// killLeakedCursorTimeout is the timeout for closing a cursor that was garbage collected without
+// being closed.
+const killLeakedCursorTimeout = 10 * time.Second
+
+// cursorLeakGuard detects cursors of a Client that are garbage collected while they are still open
+// on the server. It reports them to a handler and optionally closes them.
+type cursorLeakGuard struct {
+	handler func(options.CursorLeak)
+	kill    bool
+}
+
+// newCursorLeakGuard returns a cursorLeakGuard, or nil if leaked cursors are neither reported nor
+// killed.
+func newCursorLeakGuard(handler func(options.CursorLeak), kill bool) *cursorLeakGuard {
+	if handler == nil && !kill {
+		return nil
+	}
+	return &cursorLeakGuard{handler: handler, kill: kill}
+}
+
+// cursorOrigin records where and when a guarded cursor was created.
+type cursorOrigin struct {
+	created time.Time
+	stack   []byte
+}
+
+// track sets a finalizer on c that releases its buffered batch and reports and kills it if it is
+// garbage collected while open on the server. Cursors that are already exhausted are not tracked.
+func (g *cursorLeakGuard) track(c *Cursor) {
+	if g == nil || c.bc.ID() == 0 {
+		return
+	}
+
+	c.origin = &cursorOrigin{created: time.Now()}
+	if g.handler != nil {
+		c.origin.stack = debug.Stack()
+	}
+	runtime.SetFinalizer(c, g.finalize)
+}
+
+// untrack removes the finalizer set by track.
+func (g *cursorLeakGuard) untrack(c *Cursor) {
+	if c.origin == nil {
+		return
+	}
+	c.origin = nil
+	runtime.SetFinalizer(c, nil)
+}
+
+func (g *cursorLeakGuard) finalize(c *Cursor) {
+	// The batch may still be buffered even if the cursor has been exhausted on the server.
+	c.releaseBatch()
+
+	id := c.bc.ID()
+	if id == 0 || c.origin == nil {
+		return
+	}
+
+	leak := options.CursorLeak{
+		ID:      id,
+		Created: c.origin.created,
+		Stack:   c.origin.stack,
+		Killed:  g.kill,
+	}
+	if ns, ok := c.bc.(cursorMetadata); ok {
+		leak.Database, leak.Collection = ns.Namespace()
+	}
+	c.origin = nil
+
+	// Finalizers run sequentially on a single goroutine, so don't block it on network I/O or on
+	// the handler.
+	if g.kill {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), killLeakedCursorTimeout)
+			defer cancel()
+
+			_ = c.Close(ctx)
+		}()
+	}
+	if g.handler != nil {
+		go g.handler(leak)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// closeNotifyingBatchCursor is a testBatchCursor that signals when it is closed.
+type closeNotifyingBatchCursor struct {
+	*testBatchCursor
+	closed chan struct{}
+}
+
+func (c *closeNotifyingBatchCursor) Close(ctx context.Context) error {
+	close(c.closed)
+	return c.testBatchCursor.Close(ctx)
+}
+
+func (c *closeNotifyingBatchCursor) ServerAddress() address.Address {
+	return "localhost:27017"
+}
+
+func (c *closeNotifyingBatchCursor) Namespace() (string, string) {
+	return "db", "coll"
+}
+
+// leakCursor creates a cursor guarded by g and drops the only reference to it.
+func leakCursor(t *testing.T, g *cursorLeakGuard, bc batchCursor) {
+	t.Helper()
+
+	_, err := newCursorWithSession(context.Background(), bc, nil, nil, nil, nil, g)
+	require.NoError(t, err, "newCursorWithSession error")
+}
+
+func TestCursorLeakGuard(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, newCursorLeakGuard(nil, false), "expected no guard")
+	})
+
+	t.Run("reports and kills leaked cursor", func(t *testing.T) {
+		leaks := make(chan options.CursorLeak, 1)
+		g := newCursorLeakGuard(func(leak options.CursorLeak) { leaks <- leak }, true)
+		bc := &closeNotifyingBatchCursor{testBatchCursor: newTestBatchCursor(1, 1), closed: make(chan struct{})}
+		leakCursor(t, g, bc)
+
+		var leak options.CursorLeak
+		deadline := time.After(10 * time.Second)
+	wait:
+		for {
+			runtime.GC()
+			select {
+			case leak = <-leaks:
+				break wait
+			case <-deadline:
+				t.Fatal("timed out waiting for the leaked cursor to be reported")
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		assert.Equal(t, int64(10), leak.ID, "ID mismatch")
+		assert.Equal(t, "db", leak.Database, "database mismatch")
+		assert.Equal(t, "coll", leak.Collection, "collection mismatch")
+		assert.True(t, leak.Killed, "expected the cursor to be killed")
+		assert.False(t, leak.Created.IsZero(), "expected the creation time to be set")
+		assert.Contains(t, string(leak.Stack), "leakCursor", "expected the stack of the cursor's creation")
+
+		select {
+		case <-bc.closed:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the leaked cursor to be closed")
+		}
+	})
+
+	t.Run("closed cursor is not tracked", func(t *testing.T) {
+		g := newCursorLeakGuard(func(options.CursorLeak) {}, false)
+		cursor, err := newCursorWithSession(context.Background(), newTestBatchCursor(1, 1), nil, nil, nil, nil, g)
+		require.NoError(t, err, "newCursorWithSession error")
+		require.NotNil(t, cursor.origin, "expected the cursor to be tracked")
+
+		require.NoError(t, cursor.Close(context.Background()), "Close error")
+		assert.Nil(t, cursor.origin, "expected the cursor to be untracked")
+	})
+
+	t.Run("exhausted cursor is not tracked", func(t *testing.T) {
+		g := newCursorLeakGuard(nil, true)
+		cursor, err := newCursorWithSession(context.Background(), newTestBatchCursor(0, 0), nil, nil, nil, nil, g)
+		require.NoError(t, err, "newCursorWithSession error")
+		assert.Nil(t, cursor.origin, "expected the cursor to not be tracked")
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCursorMemoryLimitExceeded is returned by a Cursor when buffering its next batch would cause the
+// total memory buffered by all cursors of the Client to exceed the limit set with
+// options.ClientOptionsBuilder.SetMaxCursorMemory.
+var ErrCursorMemoryLimitExceeded = errors.New("cursor memory limit exceeded")
+
+// cursorMemoryTracker accounts for the bytes of batches buffered by the cursors of a Client and
+// enforces an optional limit on their total.
+type cursorMemoryTracker struct {
+	limit        int64
+	backpressure bool
+
+	mu       sync.Mutex
+	used     int64
+	released chan struct{} // closed and replaced each time memory is released
+}
+
+func newCursorMemoryTracker(limit int64, backpressure bool) *cursorMemoryTracker {
+	return &cursorMemoryTracker{
+		limit:        limit,
+		backpressure: backpressure,
+		released:     make(chan struct{}),
+	}
+}
+
+// waitForCapacity blocks until the buffered memory is below the limit or ctx expires. It returns
+// immediately if the tracker does not apply backpressure. Waiting stops once no memory is buffered
+// so that a batch larger than the limit cannot block forever.
+func (t *cursorMemoryTracker) waitForCapacity(ctx context.Context) error {
+	if t == nil || t.limit <= 0 || !t.backpressure {
+		return nil
+	}
+
+	for {
+		t.mu.Lock()
+		if t.used == 0 || t.used < t.limit {
+			t.mu.Unlock()
+			return nil
+		}
+		released := t.released
+		t.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve records n additional buffered bytes. If the tracker does not apply backpressure and the
+// limit would be exceeded, nothing is recorded and an error wrapping ErrCursorMemoryLimitExceeded is
+// returned.
+func (t *cursorMemoryTracker) reserve(n int64) error {
+	if t == nil || n == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.limit > 0 && !t.backpressure && t.used+n > t.limit {
+		return fmt.Errorf("%w: buffering %d bytes would exceed the limit of %d bytes (%d bytes in use)",
+			ErrCursorMemoryLimitExceeded, n, t.limit, t.used)
+	}
+	t.used += n
+	return nil
+}
+
+// release records that n buffered bytes are no longer in use and wakes up waiting cursors.
+func (t *cursorMemoryTracker) release(n int64) {
+	if t == nil || n == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.used -= n
+	close(t.released)
+	t.released = make(chan struct{})
+}
+
+func (t *cursorMemoryTracker) usage() int64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.used
+}
+
+// CursorMemoryUsage returns the total number of bytes of batches currently buffered by the open
+// cursors of the Client.
+func (c *Client) CursorMemoryUsage() int64 {
+	return c.cursorMemory.usage()
+}
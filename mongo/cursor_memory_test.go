@@ -0,0 +1,124 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// newInitialBatchCursor returns a testBatchCursor whose first batch is already loaded, like the
+// batch returned by the command that creates a cursor.
+func newInitialBatchCursor(numBatches, batchSize int) *testBatchCursor {
+	bc := newTestBatchCursor(numBatches, batchSize)
+	bc.Next(context.Background())
+	return bc
+}
+
+func TestCursorMemory(t *testing.T) {
+	batchBytes := int64(len(newTestBatchCursor(1, 5).batches[0].List))
+
+	t.Run("tracks buffered batches", func(t *testing.T) {
+		tracker := newCursorMemoryTracker(0, false)
+		cursor, err := newCursorWithSession(context.Background(), newTestBatchCursor(2, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+
+		assert.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		assert.Equal(t, batchBytes, cursor.BufferedBytes(), "expected first batch to be buffered")
+		assert.Equal(t, batchBytes, tracker.usage(), "expected first batch to be tracked")
+
+		for i := 0; i < 5; i++ {
+			assert.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		}
+		assert.Equal(t, batchBytes, tracker.usage(), "expected only the second batch to be tracked")
+
+		err = cursor.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+		assert.Equal(t, int64(0), cursor.BufferedBytes(), "expected no buffered bytes after Close")
+		assert.Equal(t, int64(0), tracker.usage(), "expected memory to be released after Close")
+	})
+	t.Run("returns error when limit is exceeded", func(t *testing.T) {
+		tracker := newCursorMemoryTracker(batchBytes, false)
+		first, err := newCursorWithSession(context.Background(), newInitialBatchCursor(1, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+
+		bc := newInitialBatchCursor(2, 5)
+		_, err = newCursorWithSession(context.Background(), bc, nil, nil, nil, tracker, nil)
+		assert.ErrorIs(t, err, ErrCursorMemoryLimitExceeded, "expected ErrCursorMemoryLimitExceeded")
+		assert.True(t, bc.closed, "expected the rejected cursor to be closed")
+
+		third, err := newCursorWithSession(context.Background(), newTestBatchCursor(1, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+		assert.False(t, third.Next(context.Background()), "expected Next to return false")
+		assert.ErrorIs(t, third.Err(), ErrCursorMemoryLimitExceeded, "expected ErrCursorMemoryLimitExceeded")
+		assert.Equal(t, batchBytes, tracker.usage(), "expected rejected batches not to be tracked")
+
+		err = first.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+	})
+	t.Run("releases memory of cursors that are never closed", func(t *testing.T) {
+		tracker := newCursorMemoryTracker(0, false)
+		func() {
+			_, err := newCursorWithSession(context.Background(), newInitialBatchCursor(2, 5), nil, nil, nil, tracker, nil)
+			require.NoError(t, err, "newCursorWithSession error: %v", err)
+		}()
+		require.Equal(t, batchBytes, tracker.usage(), "expected first batch to be tracked")
+
+		assert.Eventually(t, func() bool {
+			runtime.GC()
+			return tracker.usage() == 0
+		}, 10*time.Second, 10*time.Millisecond, "expected memory to be released when the cursor is garbage collected")
+	})
+	t.Run("All releases memory between batches", func(t *testing.T) {
+		tracker := newCursorMemoryTracker(batchBytes, false)
+		cursor, err := newCursorWithSession(context.Background(), newTestBatchCursor(3, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+
+		var docs []bson.D
+		err = cursor.All(context.Background(), &docs)
+		require.NoError(t, err, "All error: %v", err)
+		assert.Len(t, docs, 15, "expected 15 documents")
+		assert.Equal(t, int64(0), tracker.usage(), "expected memory to be released after All")
+	})
+	t.Run("backpressure waits for memory to be released", func(t *testing.T) {
+		tracker := newCursorMemoryTracker(batchBytes, true)
+		first, err := newCursorWithSession(context.Background(), newTestBatchCursor(1, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+		second, err := newCursorWithSession(context.Background(), newTestBatchCursor(1, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+
+		assert.True(t, first.Next(context.Background()), "expected Next to return true")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.False(t, second.Next(ctx), "expected Next to return false")
+		assert.ErrorIs(t, second.Err(), context.DeadlineExceeded, "expected context.DeadlineExceeded")
+
+		third, err := newCursorWithSession(context.Background(), newTestBatchCursor(1, 5), nil, nil, nil, tracker, nil)
+		require.NoError(t, err, "newCursorWithSession error: %v", err)
+		done := make(chan bool)
+		go func() {
+			done <- third.Next(context.Background())
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("expected Next to wait for memory to be released")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		err = first.Close(context.Background())
+		require.NoError(t, err, "Close error: %v", err)
+		assert.True(t, <-done, "expected Next to return true after memory was released")
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Resetter is implemented by values that can be reset for reuse. DecodePooled calls Reset on a
+// value taken from a pool before decoding a document into it. Reset must clear every field that
+// a document may not set, and may keep memory referenced by the value, such as slices truncated
+// to length 0, which the decoder then reuses instead of allocating.
+type Resetter interface {
+	Reset()
+}
+
+// DecodePooled decodes the current document of c into a value of type *T taken from pool and
+// returns it, which avoids allocating a value for each document when iterating over many
+// documents. The caller should Put the value back into pool when it is done with it. pool may be
+// shared by cursors used concurrently by multiple goroutines.
+//
+// If *T implements Resetter, the value is reset with its Reset method and the document is decoded
+// into it as with DecodeInto(v, true). Otherwise the value is reset to its zero value, which
+// releases the memory it references. If pool is empty and has no New function, a new value is
+// allocated. An error is returned if pool contains a value that is not of type *T.
+//
+//	pool := &sync.Pool{New: func() interface{} { return new(Order) }}
+//	for cursor.Next(ctx) {
+//		order, err := mongo.DecodePooled[Order](cursor, pool)
+//		if err != nil {
+//			return err
+//		}
+//		orders <- order // The consumer puts order back into pool.
+//	}
+func DecodePooled[T any](c *Cursor, pool *sync.Pool) (*T, error) {
+	var v *T
+	switch pv := pool.Get().(type) {
+	case nil:
+		v = new(T)
+	case *T:
+		if pv == nil {
+			pv = new(T)
+		}
+		v = pv
+	default:
+		return nil, fmt.Errorf("pool returned a value of type %T, expected %T", pv, v)
+	}
+
+	var err error
+	if r, ok := interface{}(v).(Resetter); ok {
+		r.Reset()
+		err = c.DecodeInto(v, true)
+	} else {
+		err = c.DecodeInto(v, false)
+	}
+	if err != nil {
+		pool.Put(v)
+		return nil, err
+	}
+	return v, nil
+}
+
+// TypedCursor iterates over a Cursor and decodes its documents into values of type T. Like Cursor,
+// a TypedCursor must not be used concurrently by multiple goroutines, but the values it returns
+// can be.
+//
+//	orders := mongo.NewTypedCursor[Order](cursor).SetPool(pool)
+//	defer orders.Close(ctx)
+//	for orders.Next(ctx) {
+//		order, err := orders.Decode()
+//		if err != nil {
+//			return err
+//		}
+//		process(order)
+//		orders.Release(order)
+//	}
+//	return orders.Err()
+type TypedCursor[T any] struct {
+	cursor *Cursor
+	pool   *sync.Pool
+}
+
+// NewTypedCursor creates a TypedCursor that decodes the documents of c into values of type T.
+func NewTypedCursor[T any](c *Cursor) *TypedCursor[T] {
+	return &TypedCursor[T]{cursor: c}
+}
+
+// SetPool sets the pool from which Decode takes the values that it decodes documents into, see
+// DecodePooled. If pool is nil, Decode allocates a new value for each document. The default value
+// is nil.
+func (tc *TypedCursor[T]) SetPool(pool *sync.Pool) *TypedCursor[T] {
+	tc.pool = pool
+	return tc
+}
+
+// Cursor returns the underlying Cursor.
+func (tc *TypedCursor[T]) Cursor() *Cursor {
+	return tc.cursor
+}
+
+// Next is like Cursor.Next.
+func (tc *TypedCursor[T]) Next(ctx context.Context) bool {
+	return tc.cursor.Next(ctx)
+}
+
+// TryNext is like Cursor.TryNext.
+func (tc *TypedCursor[T]) TryNext(ctx context.Context) bool {
+	return tc.cursor.TryNext(ctx)
+}
+
+// Decode decodes the current document into a value of type T and returns it. If a pool is set,
+// the value is taken from the pool and can be returned to it with Release.
+func (tc *TypedCursor[T]) Decode() (*T, error) {
+	if tc.pool != nil {
+		return DecodePooled[T](tc.cursor, tc.pool)
+	}
+	v := new(T)
+	if err := tc.cursor.Decode(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Release returns v to the pool of the TypedCursor once the caller is done with it. It does
+// nothing if no pool is set. v must not be used after it is released.
+func (tc *TypedCursor[T]) Release(v *T) {
+	if tc.pool != nil && v != nil {
+		tc.pool.Put(v)
+	}
+}
+
+// Err is like Cursor.Err.
+func (tc *TypedCursor[T]) Err() error {
+	return tc.cursor.Err()
+}
+
+// Close is like Cursor.Close.
+func (tc *TypedCursor[T]) Close(ctx context.Context) error {
+	return tc.cursor.Close(ctx)
+}
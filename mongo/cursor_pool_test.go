@@ -0,0 +1,127 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+type pooledDocument struct {
+	Foo    int32   `bson:"foo"`
+	Tags   []int32 `bson:"tags"`
+	Resets int     `bson:"-"`
+}
+
+func (d *pooledDocument) Reset() {
+	d.Foo = 0
+	d.Tags = d.Tags[:0]
+	d.Resets++
+}
+
+type plainDocument struct {
+	Foo int32 `bson:"foo"`
+	Bar string
+}
+
+func TestDecodePooled(t *testing.T) {
+	t.Run("resets values implementing Resetter", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		tags := make([]int32, 3, 8)
+		pooled := &pooledDocument{Foo: 10, Tags: tags}
+		pool := &sync.Pool{}
+		pool.Put(pooled)
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		doc, err := DecodePooled[pooledDocument](cursor, pool)
+		require.NoError(t, err, "DecodePooled error: %v", err)
+		// sync.Pool may drop values, so only check reuse if the pooled value was returned.
+		if doc == pooled {
+			assert.Equal(t, 1, doc.Resets, "expected Reset to be called")
+			assert.Equal(t, 8, cap(doc.Tags), "expected the tags capacity to be kept")
+		}
+		assert.Equal(t, int32(0), doc.Foo, "expected foo to be decoded")
+		assert.Len(t, doc.Tags, 0, "expected tags to be reset")
+	})
+	t.Run("zeroes other values", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		pool := &sync.Pool{New: func() interface{} { return &plainDocument{Foo: 10, Bar: "stale"} }}
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		doc, err := DecodePooled[plainDocument](cursor, pool)
+		require.NoError(t, err, "DecodePooled error: %v", err)
+		assert.Equal(t, plainDocument{Foo: 0}, *doc, "expected value to be reset")
+	})
+	t.Run("allocates when the pool is empty", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		doc, err := DecodePooled[plainDocument](cursor, &sync.Pool{})
+		require.NoError(t, err, "DecodePooled error: %v", err)
+		assert.Equal(t, plainDocument{Foo: 1}, *doc, "expected the second document")
+	})
+	t.Run("rejects values of another type", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		pool := &sync.Pool{New: func() interface{} { return &pooledDocument{} }}
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		_, err = DecodePooled[plainDocument](cursor, pool)
+		assert.EqualError(t, err, "pool returned a value of type *mongo.pooledDocument, expected *mongo.plainDocument")
+	})
+}
+
+func TestTypedCursor(t *testing.T) {
+	t.Run("without pool", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(2, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		tc := NewTypedCursor[plainDocument](cursor)
+		var got []int32
+		for tc.Next(context.Background()) {
+			doc, err := tc.Decode()
+			require.NoError(t, err, "Decode error: %v", err)
+			got = append(got, doc.Foo)
+			tc.Release(doc)
+		}
+		require.NoError(t, tc.Err(), "Err error: %v", tc.Err())
+		require.NoError(t, tc.Close(context.Background()), "Close error")
+		assert.Equal(t, []int32{0, 1, 2, 3}, got, "documents mismatch")
+	})
+	t.Run("with pool", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(2, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		var allocated int
+		pool := &sync.Pool{New: func() interface{} {
+			allocated++
+			return &pooledDocument{}
+		}}
+		tc := NewTypedCursor[pooledDocument](cursor).SetPool(pool)
+		var got []int32
+		for tc.Next(context.Background()) {
+			doc, err := tc.Decode()
+			require.NoError(t, err, "Decode error: %v", err)
+			got = append(got, doc.Foo)
+			tc.Release(doc)
+		}
+		require.NoError(t, tc.Err(), "Err error: %v", tc.Err())
+		assert.Equal(t, []int32{0, 1, 2, 3}, got, "documents mismatch")
+		assert.True(t, allocated >= 1 && allocated <= 4, "expected at most one value per document, got %d", allocated)
+	})
+}
@@ -16,9 +16,11 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 )
 
 type testBatchCursor struct {
@@ -240,6 +242,163 @@ func TestCursor(t *testing.T) {
 
 			assert.Equal(t, want, got, "expected and actual All results are different")
 		})
+		t.Run("with decode workers", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(3, 50), nil, nil)
+			require.NoError(t, err, "newCursor error: %v", err)
+			cursor.SetDecodeWorkers(4)
+
+			var docs []bson.D
+			err = cursor.All(context.Background(), &docs)
+			require.NoError(t, err, "All error: %v", err)
+			require.Len(t, docs, 150, "expected 150 documents, got %d", len(docs))
+
+			for i := range docs {
+				assert.Equal(t, bson.D{{"foo", int32(i)}}, docs[i], "expected document %d to be in order", i)
+			}
+		})
+		t.Run("with decode workers error", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(1, 10), nil, nil)
+			require.NoError(t, err, "newCursor error: %v", err)
+			cursor.SetDecodeWorkers(4)
+
+			var docs []struct {
+				Foo string `bson:"foo"`
+			}
+			err = cursor.All(context.Background(), &docs)
+			assert.Error(t, err, "expected decode error")
+			assert.Len(t, docs, 0, "expected results not to be set on error")
+		})
+		t.Run("with Allocator", func(t *testing.T) {
+			alloc := &countingAllocator{Allocator: bson.NewPoolAllocator()}
+			cursor, err := newCursor(
+				newTestBatchCursor(1, 3),
+				&options.BSONOptions{
+					Allocator: alloc,
+				},
+				nil)
+			require.NoError(t, err, "newCursor error: %v", err)
+
+			var got []bson.Raw
+			err = cursor.All(context.Background(), &got)
+			require.NoError(t, err, "All error: %v", err)
+			require.Len(t, got, 3, "expected 3 documents, got %d", len(got))
+			assert.Equal(t, 3, alloc.allocated, "expected every document to be allocated by the Allocator")
+
+			for i, doc := range got {
+				assert.Equal(t, int32(i), doc.Lookup("foo").Int32(), "expected document %d to be copied", i)
+				alloc.Release(doc)
+			}
+		})
+	})
+	t.Run("TestAllInto", func(t *testing.T) {
+		type myDocument struct {
+			Foo int32  `bson:"foo"`
+			Bar string `bson:"bar"`
+		}
+
+		t.Run("reuses slice capacity", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(2, 2), nil, nil)
+			require.NoError(t, err, "newCursor error: %v", err)
+
+			docs := make([]myDocument, 1, 10)
+			backing := &docs[:1][0]
+			err = cursor.AllInto(context.Background(), &docs, false)
+			require.NoError(t, err, "AllInto error: %v", err)
+
+			want := []myDocument{{Foo: 0}, {Foo: 1}, {Foo: 2}, {Foo: 3}}
+			assert.Equal(t, want, docs, "expected and actual AllInto results are different")
+			assert.Equal(t, 10, cap(docs), "expected slice capacity to be reused")
+			assert.True(t, backing == &docs[0], "expected slice backing array to be reused")
+		})
+		t.Run("grows slice when capacity is exceeded", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(1, 5), nil, nil)
+			require.NoError(t, err, "newCursor error: %v", err)
+
+			docs := make([]myDocument, 0, 2)
+			err = cursor.AllInto(context.Background(), &docs, false)
+			require.NoError(t, err, "AllInto error: %v", err)
+			assert.Len(t, docs, 5, "expected 5 documents, got %v", len(docs))
+		})
+		t.Run("resets elements unless reused", func(t *testing.T) {
+			testCases := []struct {
+				name  string
+				reuse bool
+				want  string
+			}{
+				{"reset", false, ""},
+				{"reuse", true, "stale"},
+			}
+			for _, tc := range testCases {
+				tc := tc
+				t.Run(tc.name, func(t *testing.T) {
+					cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+					require.NoError(t, err, "newCursor error: %v", err)
+
+					docs := []myDocument{{Foo: 10, Bar: "stale"}, {Foo: 11, Bar: "stale"}}
+					err = cursor.AllInto(context.Background(), &docs, tc.reuse)
+					require.NoError(t, err, "AllInto error: %v", err)
+
+					want := []myDocument{{Foo: 0, Bar: tc.want}, {Foo: 1, Bar: tc.want}}
+					assert.Equal(t, want, docs, "expected and actual AllInto results are different")
+				})
+			}
+		})
+	})
+	t.Run("TestDecodeInto", func(t *testing.T) {
+		type myDocument struct {
+			Foo int32  `bson:"foo"`
+			Bar string `bson:"bar"`
+		}
+
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		doc := myDocument{Foo: 10, Bar: "stale"}
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		err = cursor.DecodeInto(&doc, true)
+		require.NoError(t, err, "DecodeInto error: %v", err)
+		assert.Equal(t, myDocument{Foo: 0, Bar: "stale"}, doc, "expected unset fields to be kept when reused")
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		err = cursor.DecodeInto(&doc, false)
+		require.NoError(t, err, "DecodeInto error: %v", err)
+		assert.Equal(t, myDocument{Foo: 1}, doc, "expected value to be reset")
+	})
+	t.Run("TestBatchMetadata", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(2, 3), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		assert.Equal(t, 3, cursor.BatchLength(), "expected first batch length")
+		remaining, err := cursor.RemainingBatch()
+		require.NoError(t, err, "RemainingBatch error: %v", err)
+		require.Len(t, remaining, 2, "expected two remaining documents")
+		assert.Equal(t, int32(1), remaining[0].Lookup("foo").Int32(), "expected the next document first")
+		assert.Equal(t, 2, cursor.RemainingBatchLength(), "expected RemainingBatch not to advance the cursor")
+
+		for i := 0; i < 3; i++ {
+			require.True(t, cursor.Next(context.Background()), "expected Next to return true")
+		}
+		assert.Equal(t, 3, cursor.BatchLength(), "expected second batch length")
+		assert.Equal(t, int32(3), cursor.Current.Lookup("foo").Int32(), "expected first document of second batch")
+
+		assert.Equal(t, address.Address(""), cursor.Server(), "expected no server for a test cursor")
+		assert.Equal(t, "", cursor.Namespace(), "expected no namespace for a test cursor")
+		assert.Nil(t, cursor.PostBatchResumeToken(), "expected no postBatchResumeToken")
+	})
+	t.Run("TestServerMetadata", func(t *testing.T) {
+		bc, err := driver.NewBatchCursor(driver.CursorResponse{
+			Desc:       description.Server{Addr: "db1.example.com:27017"},
+			Database:   "app",
+			Collection: "users",
+			FirstBatch: &bsoncore.Iterator{},
+		}, nil, nil, driver.CursorOptions{})
+		require.NoError(t, err, "NewBatchCursor error: %v", err)
+
+		cursor, err := newCursor(bc, nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+		assert.Equal(t, address.Address("db1.example.com:27017"), cursor.Server(), "server mismatch")
+		assert.Equal(t, "app.users", cursor.Namespace(), "namespace mismatch")
 	})
 }
 
@@ -297,6 +456,17 @@ func TestNewCursorFromDocuments(t *testing.T) {
 	})
 }
 
+// countingAllocator is a bson.Allocator that counts the buffers it allocates.
+type countingAllocator struct {
+	bson.Allocator
+	allocated int
+}
+
+func (ca *countingAllocator) Allocate(size int) []byte {
+	ca.allocated++
+	return ca.Allocator.Allocate(size)
+}
+
 func TestGetDecoder(t *testing.T) {
 	t.Parallel()
 
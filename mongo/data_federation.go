@@ -0,0 +1,104 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// dataFederationHostSuffix is the domain of the endpoints of Atlas Data Federation instances and
+// Online Archives.
+const dataFederationHostSuffix = ".query.mongodb.net"
+
+// dataFederationServerSelectionTimeout is the default server selection timeout against Atlas Data
+// Federation, whose endpoints can take longer to become available than a cluster.
+const dataFederationServerSelectionTimeout = time.Minute
+
+// isDataFederationHost reports whether host, which may include a port, is the endpoint of an
+// Atlas Data Federation instance or Online Archive.
+func isDataFederationHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return strings.HasSuffix(host, dataFederationHostSuffix)
+}
+
+// isDataFederationHosts reports whether hosts are all endpoints of Atlas Data Federation.
+func isDataFederationHosts(hosts []string) bool {
+	if len(hosts) == 0 {
+		return false
+	}
+	for _, host := range hosts {
+		if !isDataFederationHost(host) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDataFederationDefaults changes the default server selection timeout to one that suits Atlas
+// Data Federation if it is not set. It is only applied when the hosts of the Client are federated,
+// because the timeout is needed before any server has responded to the handshake.
+func applyDataFederationDefaults(args *options.ClientOptions) {
+	if args.ServerSelectionTimeout == nil {
+		timeout := dataFederationServerSelectionTimeout
+		args.ServerSelectionTimeout = &timeout
+	}
+}
+
+// isDataFederationTopology reports whether desc describes Atlas Data Federation. Servers that
+// responded to the handshake are federated if their hello response says so; the host names of the
+// servers, or federatedHosts if there are none, are only used while no server has responded.
+func isDataFederationTopology(desc description.Topology, federatedHosts bool) bool {
+	hosts := make([]string, 0, len(desc.Servers))
+	known := false
+	for _, s := range desc.Servers {
+		if s.DataFederation {
+			return true
+		}
+		if s.Kind != description.Unknown {
+			known = true
+		}
+		hosts = append(hosts, s.Addr.String())
+	}
+	if known {
+		return false
+	}
+	return federatedHosts || isDataFederationHosts(hosts)
+}
+
+// IsDataFederation returns true if the Client is connected to an Atlas Data Federation instance or
+// an Online Archive. Federation is detected from the hello responses of the servers of the Client;
+// until a server has responded, the host names of its endpoints are used instead.
+//
+// Atlas Data Federation behaves like a mongos, but only supports reads and aggregations that write
+// with $out. To avoid failures that are specific to federated endpoints, writes are not retried
+// unless RetryWrites is set, the Client does not run endSessions when it is disconnected, and, when
+// the Client is created with federated hosts, the default server selection timeout is one minute.
+func (c *Client) IsDataFederation() bool {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return c.dataFederation
+	}
+	return isDataFederationTopology(topo.Description(), c.dataFederation)
+}
+
+// retryWritesEnabled reports whether the writes of the Client are retried. Writes are retried by
+// default, except against Atlas Data Federation, which does not support retryable writes.
+func (c *Client) retryWritesEnabled() bool {
+	if c.retryWritesSet {
+		return c.retryWrites
+	}
+	return !c.IsDataFederation()
+}
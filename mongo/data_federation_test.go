@@ -0,0 +1,114 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+func TestDataFederation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hosts", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, isDataFederationHost("federateddatabaseinstance0-abcde.a.query.mongodb.net:27017"), "expected federated host")
+		assert.True(t, isDataFederationHost("ATLAS-ONLINE-ARCHIVE-ABC.A.QUERY.MONGODB.NET"), "expected federated host")
+		assert.False(t, isDataFederationHost("cluster0-shard-00-00.abcde.mongodb.net:27017"), "expected cluster host")
+		assert.False(t, isDataFederationHost("localhost:27017"), "expected local host")
+		assert.False(t, isDataFederationHosts(nil), "expected no hosts to not be federated")
+		assert.False(t, isDataFederationHosts([]string{"a.query.mongodb.net", "localhost"}), "expected mixed hosts to not be federated")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := newClient(options.Client().ApplyURI("mongodb://instance-abcde.a.query.mongodb.net/?ssl=true"))
+		require.NoError(t, err, "newClient error")
+		assert.True(t, client.IsDataFederation(), "expected Data Federation")
+		assert.False(t, client.retryWritesEnabled(), "expected retryable writes to be disabled")
+
+		args := &options.ClientOptions{}
+		applyDataFederationDefaults(args)
+		require.NotNil(t, args.ServerSelectionTimeout, "expected server selection timeout")
+		assert.Equal(t, time.Minute, *args.ServerSelectionTimeout, "server selection timeout mismatch")
+	})
+
+	t.Run("explicit options", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Client().
+			ApplyURI("mongodb://instance-abcde.a.query.mongodb.net/?retryWrites=true").
+			SetServerSelectionTimeout(time.Second)
+		args, err := mongoutil.NewOptions[options.ClientOptions](opts)
+		require.NoError(t, err, "options error")
+		applyDataFederationDefaults(args)
+		assert.Equal(t, time.Second, *args.ServerSelectionTimeout, "server selection timeout mismatch")
+
+		client, err := newClient(opts)
+		require.NoError(t, err, "newClient error")
+		assert.True(t, client.retryWritesEnabled(), "expected retryable writes to be enabled")
+	})
+
+	t.Run("hello response", func(t *testing.T) {
+		t.Parallel()
+
+		hello := bson.D{{"ok", 1}, {"isWritablePrimary", true}, {"msg", "isdbgrid"}, {"maxWireVersion", 21}}
+		helloBytes, err := bson.Marshal(hello)
+		require.NoError(t, err, "Marshal error")
+		federatedBytes, err := bson.Marshal(append(hello, bson.E{"dataLake", bson.D{{"version", "v20240101"}}}))
+		require.NoError(t, err, "Marshal error")
+
+		mongos := driverutil.NewServerDescription("federated.example.com:27017", helloBytes)
+		federated := driverutil.NewServerDescription("federated.example.com:27017", federatedBytes)
+		require.NoError(t, federated.LastError, "server description error")
+		assert.False(t, mongos.DataFederation, "expected mongos to not be federated")
+		assert.True(t, federated.DataFederation, "expected Data Federation from the hello response")
+
+		unknown := description.Server{Addr: "instance-abcde.a.query.mongodb.net:27017"}
+		knownFederatedHost := mongos
+		knownFederatedHost.Addr = unknown.Addr
+
+		testCases := []struct {
+			name           string
+			servers        []description.Server
+			federatedHosts bool
+			want           bool
+		}{
+			{"federated hello response", []description.Server{federated}, false, true},
+			{"mongos hello response", []description.Server{mongos}, true, false},
+			{"hello response overrides host name", []description.Server{knownFederatedHost}, true, false},
+			{"unknown federated host", []description.Server{unknown}, false, true},
+			{"federated seed hosts", []description.Server{{Addr: "localhost:27017"}}, true, true},
+			{"unknown host", []description.Server{{Addr: "localhost:27017"}}, false, false},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				got := isDataFederationTopology(description.Topology{Servers: tc.servers}, tc.federatedHosts)
+				assert.Equal(t, tc.want, got, "isDataFederationTopology result mismatch")
+			})
+		}
+	})
+
+	t.Run("not federated", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := newClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+		require.NoError(t, err, "newClient error")
+		assert.False(t, client.IsDataFederation(), "expected no Data Federation")
+		assert.True(t, client.retryWritesEnabled(), "expected retryable writes to be enabled")
+	})
+}
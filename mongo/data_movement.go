@@ -0,0 +1,113 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DataMovementProgress is the progress of a resharding operation on one shard, such as an
+// operation started by Collection.MoveCollection or Collection.UnshardCollection. Each of the
+// coordinator, donor, and recipient shards of the operation reports its own progress; only the
+// fields relevant to its role are set.
+type DataMovementProgress struct {
+	// Description identifies the role of the shard, such as "ReshardingRecipientService
+	// <reshardingUUID>".
+	Description string `bson:"desc"`
+
+	// Provenance is the command that started the operation: "moveCollection",
+	// "unshardCollection", or "reshardCollection".
+	Provenance string `bson:"provenance"`
+
+	// Shard is the name of the shard reporting the progress.
+	Shard string `bson:"shard"`
+
+	CoordinatorState string `bson:"coordinatorState"`
+	DonorState       string `bson:"donorState"`
+	RecipientState   string `bson:"recipientState"`
+
+	ApproxDocumentsToCopy int64 `bson:"approxDocumentsToCopy"`
+	DocumentsCopied       int64 `bson:"documentsCopied"`
+	ApproxBytesToCopy     int64 `bson:"approxBytesToCopy"`
+	BytesCopied           int64 `bson:"bytesCopied"`
+
+	TotalOperationTimeElapsedSecs       int64 `bson:"totalOperationTimeElapsedSecs"`
+	RemainingOperationTimeEstimatedSecs int64 `bson:"remainingOperationTimeEstimatedSecs"`
+}
+
+// MoveCollection runs the moveCollection command to move the unsharded collection to the shard
+// toShard. The command blocks until the collection has been moved, which can take a long time for
+// large collections; DataMovementProgress can be called concurrently to report progress. The
+// command requires MongoDB 8.0 or later and must be run against a sharded cluster.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/moveCollection/.
+func (coll *Collection) MoveCollection(ctx context.Context, toShard string) error {
+	cmd := bson.D{
+		{"moveCollection", coll.db.name + "." + coll.name},
+		{"toShard", toShard},
+	}
+	return coll.client.Database("admin").RunCommand(ctx, cmd).Err()
+}
+
+// UnshardCollection runs the unshardCollection command to unshard the collection and move its data
+// to a single shard. Like MoveCollection, the command blocks until the data has been moved and
+// DataMovementProgress can be used to report progress. The command requires MongoDB 8.0 or later.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.UnshardCollectionOptions documentation).
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/unshardCollection/.
+func (coll *Collection) UnshardCollection(
+	ctx context.Context,
+	opts ...options.Lister[options.UnshardCollectionOptions],
+) error {
+	args, err := mongoutil.NewOptions[options.UnshardCollectionOptions](opts...)
+	if err != nil {
+		return fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	cmd := bson.D{{"unshardCollection", coll.db.name + "." + coll.name}}
+	if args.ToShard != nil {
+		cmd = append(cmd, bson.E{"toShard", *args.ToShard})
+	}
+	return coll.client.Database("admin").RunCommand(ctx, cmd).Err()
+}
+
+// DataMovementProgress returns the progress of the resharding operations on the collection that are
+// in progress, such as those started by MoveCollection, UnshardCollection, or the reshardCollection
+// command, as reported by the $currentOp aggregation stage. It returns an empty slice if there are
+// no operations in progress. Running $currentOp requires the inprog privilege.
+func (coll *Collection) DataMovementProgress(ctx context.Context) ([]DataMovementProgress, error) {
+	cur, err := coll.client.Database("admin").Aggregate(ctx, coll.dataMovementProgressPipeline())
+	if err != nil {
+		return nil, err
+	}
+
+	progress := []DataMovementProgress{}
+	if err := cur.All(ctx, &progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (coll *Collection) dataMovementProgressPipeline() bson.A {
+	return bson.A{
+		bson.D{{"$currentOp", bson.D{{"allUsers", true}, {"localOps", false}}}},
+		bson.D{{"$match", bson.D{
+			{"type", "op"},
+			{"ns", coll.db.name + "." + coll.name},
+			{"desc", bson.D{{"$regex", "^Resharding(Coordinator|Donor|Recipient)Service"}}},
+		}}},
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestDataMovementProgress(t *testing.T) {
+	t.Run("pipeline matches namespace", func(t *testing.T) {
+		coll := setupColl("inventory")
+		pipeline := coll.dataMovementProgressPipeline()
+		require.Len(t, pipeline, 2, "expected $currentOp and $match stages")
+
+		b, err := bson.Marshal(pipeline[1])
+		require.NoError(t, err, "Marshal error: %v", err)
+		ns, err := bson.Raw(b).LookupErr("$match", "ns")
+		require.NoError(t, err, "expected $match on ns: %v", err)
+		assert.Equal(t, testDbName+".inventory", ns.StringValue(), "expected collection namespace")
+	})
+	t.Run("decode", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{
+			{"type", "op"},
+			{"desc", "ReshardingRecipientService 2f0a1b7c-0000-0000-0000-000000000000"},
+			{"provenance", "moveCollection"},
+			{"shard", "shard02"},
+			{"recipientState", "cloning"},
+			{"approxDocumentsToCopy", int64(1000)},
+			{"documentsCopied", int32(250)},
+			{"approxBytesToCopy", int64(64000)},
+			{"bytesCopied", int64(16000)},
+			{"remainingOperationTimeEstimatedSecs", int64(30)},
+		})
+		require.NoError(t, err, "Marshal error: %v", err)
+
+		var p DataMovementProgress
+		err = bson.Unmarshal(doc, &p)
+		require.NoError(t, err, "Unmarshal error: %v", err)
+		assert.Equal(t, "moveCollection", p.Provenance, "expected provenance")
+		assert.Equal(t, "shard02", p.Shard, "expected shard")
+		assert.Equal(t, "cloning", p.RecipientState, "expected recipient state")
+		assert.Equal(t, int64(250), p.DocumentsCopied, "expected documents copied")
+		assert.Equal(t, int64(30), p.RemainingOperationTimeEstimatedSecs, "expected remaining time")
+	})
+}
@@ -182,6 +182,22 @@ func (db *Database) processRunCommand(
 		return nil, sess, err
 	}
 
+	sequences := make([]driver.DocumentSequence, 0, len(args.DocumentSequences))
+	for _, seq := range args.DocumentSequences {
+		if seq.Identifier == "" {
+			return nil, sess, errors.New("document sequence identifier must not be empty")
+		}
+		docs := make([]bsoncore.Document, 0, len(seq.Documents))
+		for _, doc := range seq.Documents {
+			marshaled, err := marshal(doc, db.bsonOpts, db.registry)
+			if err != nil {
+				return nil, sess, fmt.Errorf("error marshaling document sequence %q: %w", seq.Identifier, err)
+			}
+			docs = append(docs, marshaled)
+		}
+		sequences = append(sequences, driver.DocumentSequence{Identifier: seq.Identifier, Documents: docs})
+	}
+
 	var readSelect description.ServerSelector
 
 	readSelect = &serverselector.Composite{
@@ -201,13 +217,14 @@ func (db *Database) processRunCommand(
 		cursorOpts := db.client.createBaseCursorOptions()
 
 		cursorOpts.MarshalValueEncoderFn = newEncoderFn(db.bsonOpts, db.registry)
+		cursorOpts.Exhaust = args.Exhaust != nil && *args.Exhaust
 
 		op = operation.NewCursorCommand(runCmdDoc, cursorOpts)
 	default:
 		op = operation.NewCommand(runCmdDoc)
 	}
 
-	return op.Session(sess).CommandMonitor(db.client.monitor).
+	return op.Session(sess).CommandMonitor(db.client.monitor).DocumentSequences(sequences).
 		ServerSelector(readSelect).ClusterClock(db.client.clock).
 		Database(db.name).Deployment(db.client.deployment).
 		Crypt(db.client.cryptFLE).ReadPreference(args.ReadPreference).ServerAPI(db.client.serverAPI).
@@ -269,6 +286,7 @@ func (db *Database) RunCommand(
 // This must be an order-preserving type such as bson.D. Map types such as bson.M are not valid.
 //
 // The opts parameter can be used to specify options for this operation (see the options.RunCmdOptions documentation).
+// Set options.RunCmdOptionsBuilder.SetExhaust to have the server stream the batches of the cursor.
 //
 // The behavior of RunCommandCursor is undefined if the command document contains any of the following:
 // - A session ID or any transaction-specific fields
@@ -303,7 +321,7 @@ func (db *Database) RunCommandCursor(
 		closeImplicitSession(sess)
 		return nil, replaceErrors(err)
 	}
-	cursor, err := newCursorWithSession(bc, db.bsonOpts, db.registry, sess)
+	cursor, err := newCursorWithSession(ctx, bc, db.bsonOpts, db.registry, sess, db.client.cursorMemory, db.client.cursorLeaks)
 	return cursor, replaceErrors(err)
 }
 
@@ -393,7 +411,7 @@ func (db *Database) ListCollectionSpecifications(
 			Name:    spec.Name,
 			Type:    spec.Type,
 			Options: spec.Options,
-			IDIndex: IndexSpecification(spec.IDIndex),
+			IDIndex: spec.IDIndex.specification(),
 		}
 
 		if spec.Info != nil {
@@ -503,7 +521,7 @@ func (db *Database) ListCollections(
 		closeImplicitSession(sess)
 		return nil, replaceErrors(err)
 	}
-	cursor, err := newCursorWithSession(bc, db.bsonOpts, db.registry, sess)
+	cursor, err := newCursorWithSession(ctx, bc, db.bsonOpts, db.registry, sess, db.client.cursorMemory, db.client.cursorLeaks)
 	return cursor, replaceErrors(err)
 }
 
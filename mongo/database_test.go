@@ -13,12 +13,14 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
 )
 
@@ -154,3 +156,35 @@ func TestDatabase(t *testing.T) {
 		assert.Equal(t, ErrNilDocument, err, "expected error %v, got %v", ErrNilDocument, err)
 	})
 }
+
+func TestDatabaseRunCommandDocumentSequences(t *testing.T) {
+	var started []bson.Raw
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			started = append(started, evt.Command)
+		},
+	}
+	clientOpts := options.Client().SetMonitor(monitor)
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = drivertest.NewMockDeployment(bson.D{{"ok", 1}})
+
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+	db := client.Database("db")
+
+	opts := options.RunCmd().SetDocumentSequence("documents", bson.D{{"x", 1}}, bson.D{{"x", 2}})
+	err = db.RunCommand(context.Background(), bson.D{{"insert", "coll"}}, opts).Err()
+	require.NoError(t, err, "RunCommand error")
+
+	require.Len(t, started, 1, "expected one command")
+	docs, err := started[0].Lookup("documents").Array().Values()
+	require.NoError(t, err, "expected the document sequence in the monitored command")
+	require.Len(t, docs, 2, "expected two documents")
+	assert.Equal(t, int32(2), docs[1].Document().Lookup("x").Int32(), "document mismatch")
+
+	opts = options.RunCmd().SetDocumentSequence("", bson.D{{"x", 1}})
+	err = db.RunCommand(context.Background(), bson.D{{"insert", "coll"}}, opts).Err()
+	assert.EqualError(t, err, "document sequence identifier must not be empty")
+}
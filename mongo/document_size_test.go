@@ -0,0 +1,74 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+// sizeCheckDeployment is a MockDeployment that checks the size of command documents.
+type sizeCheckDeployment struct {
+	*drivertest.MockDeployment
+}
+
+func (sizeCheckDeployment) CheckDocumentSize() bool { return true }
+
+func TestDocumentTooLargeError(t *testing.T) {
+	t.Parallel()
+
+	newCollection := func(t *testing.T, d driver.Deployment) *Collection {
+		t.Helper()
+
+		clientOpts := options.Client()
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = d
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		return client.Database("db").Collection("coll")
+	}
+	large := bson.D{
+		{"name", "report"},
+		{"payload", bson.D{{"blob", make([]byte, 17*1024*1024)}}},
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		t.Parallel()
+
+		coll := newCollection(t, drivertest.NewMockDeployment())
+		_, err := coll.InsertMany(context.Background(), []interface{}{bson.D{{"x", 1}}, large})
+
+		var dte DocumentTooLargeError
+		require.True(t, errors.As(err, &dte), "expected DocumentTooLargeError, got %v", err)
+		assert.Equal(t, 1, dte.Index, "index mismatch")
+		require.True(t, len(dte.Fields) > 0, "expected the largest fields to be reported")
+		assert.Equal(t, "payload.blob", dte.Fields[0].Path, "largest field mismatch")
+		assert.True(t, errors.Is(err, driver.ErrDocumentTooLarge), "expected error to wrap ErrDocumentTooLarge")
+	})
+	t.Run("command check", func(t *testing.T) {
+		t.Parallel()
+
+		coll := newCollection(t, sizeCheckDeployment{drivertest.NewMockDeployment()})
+		err := coll.FindOneAndReplace(context.Background(), bson.D{}, large).Err()
+
+		var dte DocumentTooLargeError
+		require.True(t, errors.As(err, &dte), "expected DocumentTooLargeError, got %v", err)
+		assert.Equal(t, -1, dte.Index, "expected command index")
+		require.True(t, len(dte.Fields) > 0, "expected the largest fields to be reported")
+		assert.Equal(t, "update.payload.blob", dte.Fields[0].Path, "largest field mismatch")
+	})
+}
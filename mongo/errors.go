@@ -12,12 +12,14 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/codecutil"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mongocrypt"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
 )
 
@@ -30,12 +32,43 @@ var ErrNilDocument = errors.New("document is nil")
 // ErrNilValue is returned when a nil value is passed to a CRUD method.
 var ErrNilValue = errors.New("value is nil")
 
+// ErrNoTagSetMatch is returned by Client.CheckReadPrefTags if no replica set member matches the tag
+// sets of a read preference.
+var ErrNoTagSetMatch = errors.New("no replica set members match the read preference tag sets")
+
 // ErrEmptySlice is returned when an empty slice is passed to a CRUD method that requires a non-empty slice.
 var ErrEmptySlice = errors.New("must provide at least one element in input slice")
 
 // ErrNotSlice is returned when a type other than slice is passed to InsertMany.
 var ErrNotSlice = errors.New("must provide a non-empty slice")
 
+// ErrConcernInTransaction is returned when a read or write concern is set in the options of an
+// operation that runs in a transaction. Operations in a transaction use the read and write concern
+// of the transaction.
+var ErrConcernInTransaction = errors.New("cannot set a read or write concern for an operation in a transaction")
+
+// ErrETagMismatch is returned by the ReplaceOneIfMatch, UpdateOneIfMatch, and DeleteOneIfMatch
+// methods of Collection if no document matches both the filter and the ETag, either because the
+// document was modified since the ETag was read or because it does not exist.
+var ErrETagMismatch = errors.New("no document matches the filter and the ETag")
+
+// The following errors classify write concern errors. They can be tested for with errors.Is on a
+// WriteConcernError or on a WriteException or BulkWriteException that contains one. The write that
+// returned the write concern error was applied on the primary, but may not have been replicated.
+var (
+	// ErrWriteConcernTimeout means that the write concern was not satisfied within its wtimeout
+	// or the operation timeout.
+	ErrWriteConcernTimeout = errors.New("write concern timed out")
+
+	// ErrWriteConcernUnsatisfiable means that the write concern cannot be satisfied by the replica
+	// set, e.g. because it requires more members than the replica set has or an unknown tag set.
+	ErrWriteConcernUnsatisfiable = errors.New("write concern cannot be satisfied")
+
+	// ErrWriteConcernShutdown means that the server shut down while waiting for the write concern
+	// to be satisfied.
+	ErrWriteConcernShutdown = errors.New("server shut down while waiting for write concern")
+)
+
 // ErrMapForOrderedArgument is returned when a map with multiple keys is passed to a CRUD method for an ordered parameter
 type ErrMapForOrderedArgument struct {
 	ParamName string
@@ -64,14 +97,21 @@ func replaceErrors(err error) error {
 	if errors.Is(err, topology.ErrTopologyClosed) {
 		return ErrClientDisconnected
 	}
+	if errors.Is(err, session.ErrSnapshotWrite) {
+		return ErrSnapshotWrite
+	}
+	if sse, ok := err.(topology.ServerSelectionError); ok {
+		return newServerSelectionError(sse)
+	}
 	if de, ok := err.(driver.Error); ok {
 		return CommandError{
-			Code:    de.Code,
-			Message: de.Message,
-			Labels:  de.Labels,
-			Name:    de.Name,
-			Wrapped: de.Wrapped,
-			Raw:     bson.Raw(de.Raw),
+			Code:      de.Code,
+			Message:   de.Message,
+			Labels:    de.Labels,
+			Name:      de.Name,
+			Wrapped:   de.Wrapped,
+			Raw:       bson.Raw(de.Raw),
+			RetryInfo: convertDriverRetryInfo(de.RetryInfo),
 		}
 	}
 	if qe, ok := err.(driver.QueryFailureError); ok {
@@ -101,6 +141,10 @@ func replaceErrors(err error) error {
 		return ErrNilValue
 	}
 
+	if dte := (*driver.DocumentTooLargeError)(nil); errors.As(err, &dte) {
+		return newDocumentTooLargeError(dte)
+	}
+
 	if marshalErr, ok := err.(codecutil.MarshalError); ok {
 		return MarshalError{
 			Value: marshalErr.Value,
@@ -166,6 +210,28 @@ func IsTimeout(err error) bool {
 	return false
 }
 
+// IsDurable returns true if the write that returned err may have been applied despite the error, so
+// that retrying it could apply it twice. This is the case if err is nil, if the write failed to
+// satisfy its write concern, if the connection failed after the write was sent, or if the result of
+// a transaction commit is unknown. IsDurable returns false for errors returned before the write was
+// applied, such as command errors, write errors, and client-side validation errors. For bulk
+// writes, the results report which of the writes were applied.
+func IsDurable(err error) bool {
+	if err == nil {
+		return true
+	}
+	if IsNetworkError(err) || errorHasLabel(err, driver.UnknownTransactionCommitResult) {
+		return true
+	}
+	if we := (WriteException{}); errors.As(err, &we) {
+		return we.WriteConcernError != nil
+	}
+	if bwe := (BulkWriteException{}); errors.As(err, &bwe) {
+		return bwe.WriteConcernError != nil
+	}
+	return false
+}
+
 // unwrap returns the inner error if err implements Unwrap(), otherwise it returns nil.
 func unwrap(err error) error {
 	u, ok := err.(interface {
@@ -268,6 +334,8 @@ type CommandError struct {
 	Name    string   // A human-readable name corresponding to the error code
 	Wrapped error    // The underlying error, if one exists.
 	Raw     bson.Raw // The original server response containing the error.
+	// RetryInfo describes the attempts made to execute the operation if it was retried.
+	RetryInfo *RetryInfo
 }
 
 // Error implements the error interface.
@@ -283,6 +351,11 @@ func (e CommandError) Unwrap() error {
 	return e.Wrapped
 }
 
+// As implements errors.As for *RetryInfo targets.
+func (e CommandError) As(target interface{}) bool {
+	return asRetryInfo(e.RetryInfo, target)
+}
+
 // HasErrorCode returns true if the error has the specified code.
 func (e CommandError) HasErrorCode(code int) bool {
 	return int(e.Code) == code
@@ -412,6 +485,45 @@ func (wce WriteConcernError) IsMaxTimeMSExpiredError() bool {
 	return wce.Code == 50
 }
 
+// Is implements errors.Is for ErrWriteConcernTimeout, ErrWriteConcernUnsatisfiable, and
+// ErrWriteConcernShutdown.
+func (wce WriteConcernError) Is(target error) bool {
+	switch target {
+	case ErrWriteConcernTimeout:
+		// WriteConcernFailed is returned when the wtimeout expires, MaxTimeMSExpired when the
+		// operation timeout expires.
+		return wce.Code == 64 || wce.IsMaxTimeMSExpiredError()
+	case ErrWriteConcernUnsatisfiable:
+		// UnknownReplWriteConcern and UnsatisfiableWriteConcern.
+		return wce.Code == 79 || wce.Code == 100
+	case ErrWriteConcernShutdown:
+		// InterruptedAtShutdown and ShutdownInProgress.
+		return wce.Code == 11600 || wce.Code == 91
+	}
+	return false
+}
+
+// Timeout returns true if the write concern was not satisfied in time.
+func (wce WriteConcernError) Timeout() bool {
+	return wce.Is(ErrWriteConcernTimeout)
+}
+
+// asWriteConcernError implements errors.As for WriteConcernError and *WriteConcernError targets.
+func asWriteConcernError(wce *WriteConcernError, target interface{}) bool {
+	if wce == nil {
+		return false
+	}
+	switch p := target.(type) {
+	case *WriteConcernError:
+		*p = *wce
+	case **WriteConcernError:
+		*p = wce
+	default:
+		return false
+	}
+	return true
+}
+
 // WriteException is the error type returned by the InsertOne, DeleteOne, DeleteMany, UpdateOne, UpdateMany, and
 // ReplaceOne operations.
 type WriteException struct {
@@ -426,6 +538,21 @@ type WriteException struct {
 
 	// The original server response containing the error.
 	Raw bson.Raw
+
+	// RetryInfo describes the attempts made to execute the operation if it was retried.
+	RetryInfo *RetryInfo
+}
+
+// As implements errors.As for *RetryInfo targets and for WriteConcernError and *WriteConcernError
+// targets if the exception contains a write concern error.
+func (mwe WriteException) As(target interface{}) bool {
+	return asRetryInfo(mwe.RetryInfo, target) || asWriteConcernError(mwe.WriteConcernError, target)
+}
+
+// Is implements errors.Is for the write concern error classes, e.g. ErrWriteConcernTimeout, if the
+// exception contains a write concern error.
+func (mwe WriteException) Is(target error) bool {
+	return mwe.WriteConcernError != nil && mwe.WriteConcernError.Is(target)
 }
 
 // Error implements the error interface.
@@ -515,10 +642,11 @@ func convertDriverWriteConcernError(wce *driver.WriteConcernError) *WriteConcern
 }
 
 // BulkWriteError is an error that occurred during execution of one operation in a BulkWrite. This error type is only
-// returned as part of a BulkWriteException.
+// returned as part of a BulkWriteException. The Index of the WriteError is the index of the operation in the models
+// passed to BulkWrite or the documents passed to InsertMany.
 type BulkWriteError struct {
 	WriteError            // The WriteError that occurred.
-	Request    WriteModel // The WriteModel that caused this error.
+	Request    WriteModel // The WriteModel that caused this error. For InsertMany, an *InsertOneModel with the document.
 }
 
 // Error implements the error interface.
@@ -538,6 +666,18 @@ type BulkWriteException struct {
 	Labels []string
 }
 
+// As implements errors.As for WriteConcernError and *WriteConcernError targets if the exception
+// contains a write concern error.
+func (bwe BulkWriteException) As(target interface{}) bool {
+	return asWriteConcernError(bwe.WriteConcernError, target)
+}
+
+// Is implements errors.Is for the write concern error classes, e.g. ErrWriteConcernTimeout, if the
+// exception contains a write concern error.
+func (bwe BulkWriteException) Is(target error) bool {
+	return bwe.WriteConcernError != nil && bwe.WriteConcernError.Is(target)
+}
+
 // Error implements the error interface.
 func (bwe BulkWriteException) Error() string {
 	causes := make([]string, 0, 2)
@@ -609,6 +749,56 @@ func (bwe BulkWriteException) HasErrorCodeWithMessage(code int, message string)
 	return false
 }
 
+// ErrorsByIndex returns the write errors keyed by the index of the failed operation in the models passed to BulkWrite
+// or the documents passed to InsertMany.
+func (bwe BulkWriteException) ErrorsByIndex() map[int]BulkWriteError {
+	errs := make(map[int]BulkWriteError, len(bwe.WriteErrors))
+	for _, we := range bwe.WriteErrors {
+		errs[we.Index] = we
+	}
+	return errs
+}
+
+// FailedIndexes returns the indexes of the failed operations in the models passed to BulkWrite or the documents
+// passed to InsertMany, in increasing order.
+func (bwe BulkWriteException) FailedIndexes() []int {
+	indexes := make([]int, 0, len(bwe.WriteErrors))
+	for _, we := range bwe.WriteErrors {
+		indexes = append(indexes, we.Index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// SplitModels splits the models passed to the BulkWrite that returned the exception into the models that succeeded,
+// the models that failed with a write error, and the models that were not attempted, preserving their order, so that
+// the failed or unattempted models can be requeued. Models are only left unattempted by ordered bulk writes, which
+// stop at the first write error. ordered must match the Ordered option of the BulkWrite.
+//
+// Models whose writes were applied are considered successful even if the exception has a write concern error, which
+// only indicates that the writes were not confirmed to satisfy the write concern.
+func (bwe BulkWriteException) SplitModels(models []WriteModel, ordered bool) (succeeded, failed, notAttempted []WriteModel) {
+	errs := bwe.ErrorsByIndex()
+
+	// Ordered bulk writes do not attempt the models after the first failed model.
+	stop := len(models)
+	if indexes := bwe.FailedIndexes(); ordered && len(indexes) > 0 && indexes[0] < stop {
+		stop = indexes[0] + 1
+	}
+
+	for i, model := range models {
+		switch _, isFailed := errs[i]; {
+		case isFailed:
+			failed = append(failed, model)
+		case i >= stop:
+			notAttempted = append(notAttempted, model)
+		default:
+			succeeded = append(succeeded, model)
+		}
+	}
+	return succeeded, failed, notAttempted
+}
+
 // serverError implements the ServerError interface.
 func (bwe BulkWriteException) serverError() {}
 
@@ -660,6 +850,7 @@ func processWriteError(err error) (returnResult, error) {
 		WriteErrors:       writeErrorsFromDriverWriteErrors(wce.WriteErrors),
 		Labels:            wce.Labels,
 		Raw:               bson.Raw(wce.Raw),
+		RetryInfo:         convertDriverRetryInfo(wce.RetryInfo),
 	}
 }
 
@@ -694,3 +885,59 @@ func joinBatchErrors(errs []error) string {
 
 	return buf.String()
 }
+
+// DocumentFieldSize is the size in bytes of a field of a document, including its name and type.
+type DocumentFieldSize struct {
+	// Path is the dotted path of the field, with array indexes as path components, e.g.
+	// "items.3.payload".
+	Path string
+	Size int
+}
+
+// DocumentTooLargeError is returned when an operation would send a document that is larger than
+// the maxBsonObjectSize of the server. The documents of insert, update, and delete operations are
+// always checked. Other commands are checked if enabled with
+// options.ClientOptionsBuilder.SetCheckDocumentSize.
+type DocumentTooLargeError struct {
+	// Size is the size of the document in bytes and MaxSize is the maximum size accepted by the
+	// server.
+	Size    int
+	MaxSize int
+
+	// Index is the index of the document in the documents or statements of the operation, such as
+	// the documents passed to InsertMany, or -1 if the document is a command.
+	Index int
+
+	// Fields are the largest fields of the document, sorted by decreasing size. A field that
+	// consists mostly of a single embedded field is reported as the embedded field instead, so the
+	// paths point at the values that make the document large.
+	Fields []DocumentFieldSize
+}
+
+func newDocumentTooLargeError(dte *driver.DocumentTooLargeError) DocumentTooLargeError {
+	fields := make([]DocumentFieldSize, 0, len(dte.Fields))
+	for _, f := range dte.Fields {
+		fields = append(fields, DocumentFieldSize{Path: f.Path, Size: f.Size})
+	}
+	return DocumentTooLargeError{
+		Size:    dte.Size,
+		MaxSize: dte.MaxSize,
+		Index:   dte.Index,
+		Fields:  fields,
+	}
+}
+
+// Error implements the error interface.
+func (e DocumentTooLargeError) Error() string {
+	fields := make([]driver.FieldSize, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, driver.FieldSize{Path: f.Path, Size: f.Size})
+	}
+	dte := driver.DocumentTooLargeError{Size: e.Size, MaxSize: e.MaxSize, Index: e.Index, Fields: fields}
+	return dte.Error()
+}
+
+// Unwrap returns driver.ErrDocumentTooLarge.
+func (e DocumentTooLargeError) Unwrap() error {
+	return driver.ErrDocumentTooLarge
+}
@@ -12,11 +12,14 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
 )
 
@@ -696,3 +699,217 @@ func (n netErr) Temporary() bool {
 }
 
 var _ net.Error = (*netErr)(nil)
+
+func TestRetryInfo(t *testing.T) {
+	t.Parallel()
+
+	ri := &driver.RetryInfo{
+		Attempts: 2,
+		Reason:   driver.RetryReasonNetworkError,
+		Labels:   []string{driver.NetworkError},
+		Servers:  []address.Address{"a:27017", "b:27017"},
+	}
+	want := &RetryInfo{
+		Attempts: 2,
+		Reason:   "network error",
+		Labels:   []string{driver.NetworkError},
+		Servers:  []address.Address{"a:27017", "b:27017"},
+	}
+
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{"CommandError", replaceErrors(driver.Error{Code: 1, RetryInfo: ri})},
+		{"WriteException", func() error {
+			_, err := processWriteError(driver.WriteCommandError{RetryInfo: ri})
+			return err
+		}()},
+		{"wrapped", fmt.Errorf("wrapped: %w", replaceErrors(driver.Error{Code: 1, RetryInfo: ri}))},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got *RetryInfo
+			require.True(t, errors.As(tc.err, &got), "expected error to contain RetryInfo")
+			assert.Equal(t, want, got, "expected RetryInfo to match")
+		})
+	}
+
+	t.Run("not retried", func(t *testing.T) {
+		t.Parallel()
+
+		var got *RetryInfo
+		assert.False(t, errors.As(replaceErrors(driver.Error{Code: 1}), &got), "expected no RetryInfo")
+	})
+	t.Run("CommandError still matches", func(t *testing.T) {
+		t.Parallel()
+
+		var ce CommandError
+		assert.True(t, errors.As(replaceErrors(driver.Error{Code: 1, RetryInfo: ri}), &ce),
+			"expected errors.As to match CommandError")
+	})
+}
+
+func TestBulkWriteExceptionSplitModels(t *testing.T) {
+	t.Parallel()
+
+	models := make([]WriteModel, 6)
+	for i := range models {
+		models[i] = NewInsertOneModel().SetDocument(bson.D{{"_id", i}})
+	}
+	bwe := BulkWriteException{
+		WriteErrors: []BulkWriteError{
+			{WriteError: WriteError{Index: 4, Code: 11000}, Request: models[4]},
+			{WriteError: WriteError{Index: 1, Code: 121}, Request: models[1]},
+		},
+	}
+
+	assert.Equal(t, []int{1, 4}, bwe.FailedIndexes(), "expected sorted failed indexes")
+	errs := bwe.ErrorsByIndex()
+	assert.Equal(t, 121, errs[1].Code, "expected error code of index 1")
+	assert.Equal(t, models[4], errs[4].Request, "expected model of index 4")
+
+	t.Run("unordered", func(t *testing.T) {
+		t.Parallel()
+
+		succeeded, failed, notAttempted := bwe.SplitModels(models, false)
+		assert.Equal(t, []WriteModel{models[0], models[2], models[3], models[5]}, succeeded, "expected succeeded models")
+		assert.Equal(t, []WriteModel{models[1], models[4]}, failed, "expected failed models")
+		assert.Len(t, notAttempted, 0, "expected all models to be attempted")
+	})
+	t.Run("ordered", func(t *testing.T) {
+		t.Parallel()
+
+		ordered := BulkWriteException{WriteErrors: bwe.WriteErrors[1:]}
+		succeeded, failed, notAttempted := ordered.SplitModels(models, true)
+		assert.Equal(t, []WriteModel{models[0]}, succeeded, "expected succeeded models")
+		assert.Equal(t, []WriteModel{models[1]}, failed, "expected failed models")
+		assert.Equal(t, models[2:], notAttempted, "expected models after the failure to be unattempted")
+	})
+}
+
+func TestWriteConcernErrorClasses(t *testing.T) {
+	testCases := []struct {
+		name string
+		code int
+		want error
+	}{
+		{"WriteConcernFailed", 64, ErrWriteConcernTimeout},
+		{"MaxTimeMSExpired", 50, ErrWriteConcernTimeout},
+		{"UnknownReplWriteConcern", 79, ErrWriteConcernUnsatisfiable},
+		{"UnsatisfiableWriteConcern", 100, ErrWriteConcernUnsatisfiable},
+		{"InterruptedAtShutdown", 11600, ErrWriteConcernShutdown},
+		{"ShutdownInProgress", 91, ErrWriteConcernShutdown},
+		{"other", 1, nil},
+	}
+	classes := []error{ErrWriteConcernTimeout, ErrWriteConcernUnsatisfiable, ErrWriteConcernShutdown}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wce := &WriteConcernError{Name: tc.name, Code: tc.code}
+			errs := []error{
+				*wce,
+				WriteException{WriteConcernError: wce},
+				fmt.Errorf("wrapped: %w", BulkWriteException{WriteConcernError: wce}),
+			}
+			for _, err := range errs {
+				for _, class := range classes {
+					got := errors.Is(err, class)
+					assert.Equal(t, class == tc.want, got, "expected errors.Is(%v, %v) to be %v", err, class, !got)
+				}
+			}
+
+			var gotWCE *WriteConcernError
+			require.True(t, errors.As(errs[1], &gotWCE), "expected errors.As to find the write concern error")
+			assert.Equal(t, wce, gotWCE, "write concern error mismatch")
+			var gotValue WriteConcernError
+			require.True(t, errors.As(errs[2], &gotValue), "expected errors.As to find the write concern error")
+			assert.Equal(t, *wce, gotValue, "write concern error mismatch")
+		})
+	}
+
+	t.Run("no write concern error", func(t *testing.T) {
+		err := WriteException{WriteErrors: WriteErrors{{Code: 11000}}}
+		assert.False(t, errors.Is(err, ErrWriteConcernTimeout), "expected no write concern error class")
+		var wce WriteConcernError
+		assert.False(t, errors.As(err, &wce), "expected errors.As to find no write concern error")
+	})
+}
+
+func TestIsDurable(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, true},
+		{"write concern error", WriteException{WriteConcernError: &WriteConcernError{Code: 64}}, true},
+		{"bulk write concern error", BulkWriteException{WriteConcernError: &WriteConcernError{Code: 91}}, true},
+		{"network error", driver.Error{Labels: []string{driver.NetworkError}}, true},
+		{"unknown commit result", CommandError{Labels: []string{driver.UnknownTransactionCommitResult}}, true},
+		{"wrapped write concern error", fmt.Errorf("insert: %w", WriteException{WriteConcernError: &WriteConcernError{}}), true},
+		{"write error", WriteException{WriteErrors: WriteErrors{{Code: 11000}}}, false},
+		{"command error", CommandError{Code: 13, Name: "Unauthorized"}, false},
+		{"client error", ErrNilDocument, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsDurable(tc.err), "IsDurable mismatch for %v", tc.err)
+		})
+	}
+}
+
+func TestServerSelectionError(t *testing.T) {
+	now := time.Now()
+	dnsErr := &net.DNSError{Err: "no such host", Name: "c.example.com", IsNotFound: true}
+	sse := topology.ServerSelectionError{
+		Wrapped: context.DeadlineExceeded,
+		Desc: description.Topology{
+			Kind:    description.TopologyKindReplicaSetWithPrimary,
+			SetName: "rs0",
+			Servers: []description.Server{
+				{
+					Addr:           "a:27017",
+					Kind:           description.ServerKindRSPrimary,
+					LastUpdateTime: now,
+					LastWriteTime:  now,
+				},
+				{
+					Addr:              "b:27017",
+					Kind:              description.ServerKindRSSecondary,
+					LastUpdateTime:    now,
+					LastWriteTime:     now.Add(-time.Minute),
+					HeartbeatInterval: 10 * time.Second,
+					AverageRTT:        time.Millisecond,
+				},
+				{
+					Addr:      "c.example.com:27017",
+					Kind:      description.Unknown,
+					LastError: dnsErr,
+				},
+			},
+		},
+	}
+
+	err := replaceErrors(sse)
+	var got ServerSelectionError
+	require.True(t, errors.As(err, &got), "expected a ServerSelectionError, got %T", err)
+	assert.Equal(t, sse.Error(), got.Error(), "expected the message of the wrapped error")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected the error to wrap context.DeadlineExceeded")
+	assert.True(t, errors.As(err, &topology.ServerSelectionError{}), "expected the error to wrap the topology error")
+	assert.True(t, IsTimeout(err), "expected IsTimeout to return true")
+
+	assert.Equal(t, "ReplicaSetWithPrimary", got.TopologyKind, "topology kind mismatch")
+	assert.Equal(t, "rs0", got.SetName, "set name mismatch")
+	require.Len(t, got.Servers, 3, "expected a state per server")
+	assert.Equal(t, time.Duration(0), got.Servers[0].Staleness, "expected no staleness for the primary")
+	assert.Equal(t, "RSSecondary", got.Servers[1].Kind, "server kind mismatch")
+	assert.Equal(t, 70*time.Second, got.Servers[1].Staleness, "staleness mismatch")
+	assert.Equal(t, time.Millisecond, got.Servers[1].AverageRTT, "average RTT mismatch")
+
+	var gotDNSErr *net.DNSError
+	assert.True(t, errors.As(got.Servers[2].LastError, &gotDNSErr), "expected the DNS error of the unknown server")
+}
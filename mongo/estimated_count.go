@@ -0,0 +1,56 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// checkEstimatedCount returns estimate if it is consistent with the contents of the collection,
+// or the number of documents counted with the _id index otherwise. The count metadata used for
+// the estimate is not updated atomically with the documents, so after an unclean shutdown it can
+// be negative, or zero for a collection that contains documents. The comment and read concern of
+// the EstimatedDocumentCount options are applied to the commands used to check the estimate.
+func (coll *Collection) checkEstimatedCount(
+	ctx context.Context,
+	estimate int64,
+	args *options.EstimatedDocumentCountOptions,
+) (int64, error) {
+	switch {
+	case estimate < 0:
+	case estimate == 0:
+		findOpts := options.FindOne().SetProjection(bson.D{{"_id", 1}})
+		if args.Comment != nil {
+			findOpts.SetComment(args.Comment)
+		}
+		if args.ReadConcern != nil {
+			findOpts.SetReadConcern(args.ReadConcern)
+		}
+		err := coll.FindOne(ctx, bson.D{}, findOpts).Err()
+		if errors.Is(err, ErrNoDocuments) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return estimate, nil
+	}
+
+	countOpts := options.Count().SetHint(bson.D{{"_id", 1}})
+	if args.Comment != nil {
+		countOpts.SetComment(args.Comment)
+	}
+	if args.ReadConcern != nil {
+		countOpts.SetReadConcern(args.ReadConcern)
+	}
+	return coll.CountDocuments(ctx, bson.D{}, countOpts)
+}
@@ -0,0 +1,71 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestEstimatedDocumentCountStaleFallback(t *testing.T) {
+	t.Parallel()
+
+	countReply := func(n int64) bson.D {
+		return bson.D{{"ok", 1}, {"n", n}}
+	}
+	cursorReply := func(docs ...interface{}) bson.D {
+		return bson.D{{"ok", 1}, {"cursor", bson.D{
+			{"id", int64(0)},
+			{"ns", "db.coll"},
+			{"firstBatch", append(bson.A{}, docs...)},
+		}}}
+	}
+
+	testCases := []struct {
+		name      string
+		responses []bson.D
+		want      int64
+	}{
+		{"positive estimate", []bson.D{countReply(7)}, 7},
+		{"negative estimate", []bson.D{countReply(-2), cursorReply(bson.D{{"n", int32(3)}})}, 3},
+		{"zero estimate of empty collection", []bson.D{countReply(0), cursorReply()}, 0},
+		{"zero estimate of non-empty collection", []bson.D{
+			countReply(0),
+			cursorReply(bson.D{{"_id", 1}}),
+			cursorReply(bson.D{{"n", int32(4)}}),
+		}, 4},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			md := drivertest.NewMockDeployment(tc.responses...)
+			clientOpts := options.Client()
+			clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+				args.Deployment = md
+
+				return nil
+			})
+			client, err := Connect(clientOpts)
+			require.NoError(t, err, "Connect error")
+			defer func() { _ = client.Disconnect(context.Background()) }()
+
+			coll := client.Database("db").Collection("coll")
+			n, err := coll.EstimatedDocumentCount(context.Background(), options.EstimatedDocumentCount().SetStaleFallback(true))
+			require.NoError(t, err, "EstimatedDocumentCount error")
+			assert.Equal(t, tc.want, n, "count mismatch")
+		})
+	}
+}
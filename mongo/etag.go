@@ -0,0 +1,352 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// defaultETagField is the default name of the field that stores the ETag of a document.
+const defaultETagField = "_etag"
+
+var errNoETag = errors.New("the collection is not configured with an ETag; see CollectionOptionsBuilder.SetETag")
+
+// etagConfig computes and stores the ETags of the documents written by a Collection.
+type etagConfig struct {
+	field   string
+	exclude map[string]struct{}
+}
+
+// newETagConfig returns an etagConfig for opts, or nil if opts is nil.
+func newETagConfig(opts *options.ETagOptions) *etagConfig {
+	if opts == nil {
+		return nil
+	}
+
+	ec := &etagConfig{
+		field:   opts.Field,
+		exclude: make(map[string]struct{}, len(opts.Exclude)),
+	}
+	if ec.field == "" {
+		ec.field = defaultETagField
+	}
+	for _, name := range opts.Exclude {
+		ec.exclude[name] = struct{}{}
+	}
+	return ec
+}
+
+// hashed reports whether the top-level field with the given name is included in the ETag.
+func (ec *etagConfig) hashed(name string) bool {
+	if name == "_id" || name == ec.field {
+		return false
+	}
+	_, excluded := ec.exclude[name]
+	return !excluded
+}
+
+// compute returns the ETag of doc, which is the base64url-encoded SHA-256 hash of the canonical form
+// of its hashed fields, as returned by bson.Canonicalize with the types of numbers preserved.
+func (ec *etagConfig) compute(doc bsoncore.Document) (string, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return "", err
+	}
+	idx, hashed := bsoncore.AppendDocumentStart(make([]byte, 0, len(doc)))
+	for _, elem := range elems {
+		if ec.hashed(elem.Key()) {
+			hashed = append(hashed, elem...)
+		}
+	}
+	if hashed, err = bsoncore.AppendDocumentEnd(hashed, idx); err != nil {
+		return "", err
+	}
+
+	canonical, err := bson.Canonicalize(hashed, bson.CanonicalNumbersPreserve)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// stamp returns doc with its ETag field set to the ETag of doc. It returns doc unchanged if ec is
+// nil.
+func (ec *etagConfig) stamp(doc bsoncore.Document) (bsoncore.Document, error) {
+	if ec == nil {
+		return doc, nil
+	}
+
+	etag, err := ec.compute(doc)
+	if err != nil {
+		return nil, err
+	}
+	return ec.set(doc, etag)
+}
+
+// set returns a copy of doc with its ETag field set to value.
+func (ec *etagConfig) set(doc bsoncore.Document, value string) (bsoncore.Document, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, dst := bsoncore.AppendDocumentStart(make([]byte, 0, len(doc)+len(ec.field)+len(value)+7))
+	for _, elem := range elems {
+		if elem.Key() != ec.field {
+			dst = append(dst, elem...)
+		}
+	}
+	dst = bsoncore.AppendStringElement(dst, ec.field, value)
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+// setPending returns the update document update with an additional $set of the ETag field to
+// pending. It returns an error if update already modifies the ETag field with $set.
+func (ec *etagConfig) setPending(update bsoncore.Document, pending string) (bsoncore.Document, error) {
+	elems, err := update.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var set bsoncore.Document
+	idx, dst := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		if elem.Key() != "$set" {
+			dst = append(dst, elem...)
+			continue
+		}
+		var ok bool
+		if set, ok = elem.Value().DocumentOK(); !ok {
+			return nil, fmt.Errorf("$set must be a document, got %v", elem.Value().Type)
+		}
+		if _, err := set.LookupErr(ec.field); err == nil {
+			return nil, fmt.Errorf("update cannot set the ETag field %q", ec.field)
+		}
+	}
+
+	setIdx, dst := bsoncore.AppendDocumentElementStart(dst, "$set")
+	if len(set) > 0 {
+		dst = append(dst, set[4:len(set)-1]...)
+	}
+	dst = bsoncore.AppendStringElement(dst, ec.field, pending)
+	if dst, err = bsoncore.AppendDocumentEnd(dst, setIdx); err != nil {
+		return nil, err
+	}
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+// ETag returns the ETag that the Collection stores in doc when doc is written. The ETag is
+// computed from the content of doc, excluding the _id field, the ETag field, the fields excluded
+// by the ETagOptions of the Collection, and the fields derived by its field transformers, so it can
+// be compared with the ETag stored in a document read from the collection. It returns an error if
+// the Collection is not configured with CollectionOptionsBuilder.SetETag.
+func (coll *Collection) ETag(doc interface{}) (string, error) {
+	if coll.etag == nil {
+		return "", errNoETag
+	}
+
+	d, err := marshal(doc, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return "", err
+	}
+	stripped, err := coll.transforms.strip(bson.Raw(d))
+	if err != nil {
+		return "", err
+	}
+	return coll.etag.compute(bsoncore.Document(stripped))
+}
+
+// matchETag returns a filter that matches the documents that match filter and have the given
+// ETag.
+func (coll *Collection) matchETag(filter interface{}, etag string) (bson.D, error) {
+	f, err := marshal(filter, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{"$and", bson.A{bson.Raw(f), bson.D{{coll.etag.field, etag}}}}}, nil
+}
+
+// ReplaceOneIfMatch replaces the document that matches filter with replacement if the document has
+// the given ETag, and returns the ETag of replacement. It returns ErrETagMismatch if no document
+// matches both the filter and the ETag. An upsert is never performed. The Collection must be
+// configured with CollectionOptionsBuilder.SetETag.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.ReplaceOptions documentation).
+func (coll *Collection) ReplaceOneIfMatch(
+	ctx context.Context,
+	filter interface{},
+	replacement interface{},
+	etag string,
+	opts ...options.Lister[options.ReplaceOptions],
+) (string, error) {
+	if coll.etag == nil {
+		return "", errNoETag
+	}
+
+	args, err := mongoutil.NewOptions[options.ReplaceOptions](opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	f, err := coll.matchETag(filter, etag)
+	if err != nil {
+		return "", err
+	}
+	r, err := marshalDocument(replacement, args.RawDocuments, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return "", err
+	}
+	newETag, err := coll.etag.compute(r)
+	if err != nil {
+		return "", err
+	}
+	if r, err = coll.etag.set(r, newETag); err != nil {
+		return "", err
+	}
+
+	res, err := coll.ReplaceOne(ctx, f, bson.Raw(r), append(opts, options.Replace().SetUpsert(false))...)
+	if err != nil {
+		return "", err
+	}
+	if res.Acknowledged && res.MatchedCount == 0 {
+		return "", ErrETagMismatch
+	}
+	return newETag, nil
+}
+
+// UpdateOneIfMatch applies update to the document that matches filter if the document has the
+// given ETag, and returns the new ETag of the document. It returns ErrETagMismatch if no document
+// matches both the filter and the ETag. An upsert is never performed. The Collection must be
+// configured with CollectionOptionsBuilder.SetETag.
+//
+// The update parameter must be a document of update operators; update pipelines are not supported.
+// Because the new ETag can only be computed from the updated document, the update sets the ETag
+// field to a unique pending value, which no ETag matches, and a second write replaces it with the
+// new ETag unless the document was modified in the meantime. The WriteConcern option applies to
+// both writes.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.UpdateOneOptions documentation).
+func (coll *Collection) UpdateOneIfMatch(
+	ctx context.Context,
+	filter interface{},
+	update interface{},
+	etag string,
+	opts ...options.Lister[options.UpdateOneOptions],
+) (string, error) {
+	if coll.etag == nil {
+		return "", errNoETag
+	}
+
+	args, err := mongoutil.NewOptions[options.UpdateOneOptions](opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	f, err := coll.matchETag(filter, etag)
+	if err != nil {
+		return "", err
+	}
+	u, err := marshalUpdateValue(update, coll.bsonOpts, coll.registry, true)
+	if err != nil {
+		return "", err
+	}
+	if u.Type != bsoncore.TypeEmbeddedDocument {
+		return "", errors.New("UpdateOneIfMatch does not support update pipelines")
+	}
+	pending := "pending:" + bson.NewObjectID().Hex()
+	ud, err := coll.etag.setPending(u.Data, pending)
+	if err != nil {
+		return "", err
+	}
+
+	fOpts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	fOpts.Opts = append(fOpts.Opts, func(fo *options.FindOneAndUpdateOptions) error {
+		fo.ArrayFilters = args.ArrayFilters
+		fo.BypassDocumentValidation = args.BypassDocumentValidation
+		fo.Collation = args.Collation
+		fo.Comment = args.Comment
+		fo.Hint = args.Hint
+		fo.Let = args.Let
+		fo.WriteConcern = args.WriteConcern
+
+		return nil
+	})
+	updated, err := coll.FindOneAndUpdate(ctx, f, bson.Raw(ud), fOpts).Raw()
+	if errors.Is(err, ErrNoDocuments) {
+		return "", ErrETagMismatch
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The ETag does not include the fields derived by the field transformers of the Collection.
+	if updated, err = coll.transforms.strip(updated); err != nil {
+		return "", err
+	}
+	newETag, err := coll.etag.compute(bsoncore.Document(updated))
+	if err != nil {
+		return "", err
+	}
+	id, err := updated.LookupErr("_id")
+	if err != nil {
+		return "", fmt.Errorf("updated document has no _id: %w", err)
+	}
+	setOpts := options.UpdateOne()
+	if args.WriteConcern != nil {
+		setOpts.SetWriteConcern(args.WriteConcern)
+	}
+	_, err = coll.UpdateOne(ctx,
+		bson.D{{"_id", id}, {coll.etag.field, pending}},
+		bson.D{{"$set", bson.D{{coll.etag.field, newETag}}}},
+		setOpts)
+	if err != nil {
+		return "", err
+	}
+	return newETag, nil
+}
+
+// DeleteOneIfMatch deletes the document that matches filter if the document has the given ETag.
+// It returns ErrETagMismatch if no document matches both the filter and the ETag. The Collection
+// must be configured with CollectionOptionsBuilder.SetETag.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.DeleteOneOptions documentation).
+func (coll *Collection) DeleteOneIfMatch(
+	ctx context.Context,
+	filter interface{},
+	etag string,
+	opts ...options.Lister[options.DeleteOneOptions],
+) error {
+	if coll.etag == nil {
+		return errNoETag
+	}
+
+	f, err := coll.matchETag(filter, etag)
+	if err != nil {
+		return err
+	}
+	res, err := coll.DeleteOne(ctx, f, opts...)
+	if err != nil {
+		return err
+	}
+	if res.Acknowledged && res.DeletedCount == 0 {
+		return ErrETagMismatch
+	}
+	return nil
+}
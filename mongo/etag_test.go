@@ -0,0 +1,194 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+// newETagTestCollection returns a collection configured with an ETag that excludes the
+// "updatedAt" field, and the commands it sends to a mock deployment that replies with responses.
+func newETagTestCollection(t *testing.T, responses ...bson.D) (*Collection, *[]bson.Raw) {
+	t.Helper()
+
+	var started []bson.Raw
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			started = append(started, evt.Command)
+		},
+	}
+	md := drivertest.NewMockDeployment(responses...)
+	clientOpts := options.Client().SetMonitor(monitor)
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = md
+
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	collOpts := options.Collection().SetETag(&options.ETagOptions{Exclude: []string{"updatedAt"}})
+	return client.Database("db").Collection("coll", collOpts), &started
+}
+
+func TestCollectionETag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not configured", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newETagTestCollection(t)
+		_, err := coll.Clone(options.Collection()).ETag(bson.D{})
+		assert.NoError(t, err, "expected Clone to keep the ETag")
+
+		coll = coll.Database().Collection("other")
+		_, err = coll.ETag(bson.D{})
+		assert.Equal(t, errNoETag, err, "expected error for a collection without ETag")
+		err = coll.DeleteOneIfMatch(context.Background(), bson.D{}, "tag")
+		assert.Equal(t, errNoETag, err, "expected error for a collection without ETag")
+	})
+
+	t.Run("content hash", func(t *testing.T) {
+		t.Parallel()
+
+		coll, _ := newETagTestCollection(t)
+		etag := func(doc interface{}) string {
+			t.Helper()
+
+			tag, err := coll.ETag(doc)
+			require.NoError(t, err, "ETag error")
+			return tag
+		}
+
+		base := etag(bson.D{{"a", 1}, {"b", bson.D{{"x", 1}, {"y", bson.A{1, bson.D{{"p", 1}, {"q", 2}}}}}}})
+		assert.Equal(t, base,
+			etag(bson.D{{"b", bson.D{{"y", bson.A{1, bson.D{{"q", 2}, {"p", 1}}}}, {"x", 1}}}, {"a", 1}}),
+			"expected the hash to not depend on field order")
+		assert.Equal(t, base,
+			etag(bson.D{
+				{"_id", 5},
+				{"a", 1},
+				{"updatedAt", bson.DateTime(1)},
+				{"b", bson.D{{"x", 1}, {"y", bson.A{1, bson.D{{"p", 1}, {"q", 2}}}}}},
+				{"_etag", "old"},
+			}),
+			"expected the hash to exclude _id, the ETag field, and excluded fields")
+		assert.NotEqual(t, base,
+			etag(bson.D{{"a", 1}, {"b", bson.D{{"x", 1}, {"y", bson.A{bson.D{{"p", 1}, {"q", 2}}, 1}}}}}),
+			"expected the hash to depend on array order")
+		assert.NotEqual(t, base, etag(bson.D{{"a", 2}, {"b", bson.D{{"x", 1}}}}), "expected the hash to depend on values")
+	})
+
+	t.Run("InsertOne stores the ETag", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newETagTestCollection(t, bson.D{{"ok", 1}, {"n", 1}})
+		doc := bson.D{{"x", 1}, {"_etag", "stale"}}
+		_, err := coll.InsertOne(context.Background(), doc)
+		require.NoError(t, err, "InsertOne error")
+
+		want, err := coll.ETag(doc)
+		require.NoError(t, err, "ETag error")
+		require.Len(t, *started, 1, "expected one command")
+		inserted := (*started)[0].Lookup("documents").Array().Index(0).Document()
+		assert.Equal(t, want, inserted.Lookup("_etag").StringValue(), "ETag mismatch")
+		assert.Equal(t, "x", mustIndex(t, inserted, 1).Key(), "expected the ETag to replace the stale field")
+	})
+
+	t.Run("ReplaceOneIfMatch", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newETagTestCollection(t,
+			bson.D{{"ok", 1}, {"n", 1}, {"nModified", 1}},
+			bson.D{{"ok", 1}, {"n", 0}, {"nModified", 0}})
+		replacement := bson.D{{"x", 2}}
+		tag, err := coll.ReplaceOneIfMatch(context.Background(), bson.D{{"_id", 1}}, replacement, "old")
+		require.NoError(t, err, "ReplaceOneIfMatch error")
+		want, err := coll.ETag(replacement)
+		require.NoError(t, err, "ETag error")
+		assert.Equal(t, want, tag, "ETag mismatch")
+
+		update := (*started)[0].Lookup("updates").Array().Index(0).Document()
+		assert.Equal(t, "old", update.Lookup("q", "$and", "1", "_etag").StringValue(), "filter mismatch")
+		assert.Equal(t, want, update.Lookup("u", "_etag").StringValue(), "replacement ETag mismatch")
+		assert.False(t, update.Lookup("upsert").Boolean(), "expected no upsert")
+
+		_, err = coll.ReplaceOneIfMatch(context.Background(), bson.D{{"_id", 1}}, replacement, "old")
+		assert.True(t, errors.Is(err, ErrETagMismatch), "expected ErrETagMismatch, got %v", err)
+	})
+
+	t.Run("UpdateOneIfMatch", func(t *testing.T) {
+		t.Parallel()
+
+		updated := bson.D{{"_id", 1}, {"x", 3}, {"y", 1}, {"_etag", "pending"}}
+		coll, started := newETagTestCollection(t,
+			bson.D{{"ok", 1}, {"value", updated}},
+			bson.D{{"ok", 1}, {"n", 1}, {"nModified", 1}})
+		update := bson.D{{"$set", bson.D{{"x", 3}}}, {"$inc", bson.D{{"y", 1}}}}
+		tag, err := coll.UpdateOneIfMatch(context.Background(), bson.D{{"_id", 1}}, update, "old")
+		require.NoError(t, err, "UpdateOneIfMatch error")
+		want, err := coll.ETag(updated)
+		require.NoError(t, err, "ETag error")
+		assert.Equal(t, want, tag, "ETag mismatch")
+
+		require.Len(t, *started, 2, "expected findAndModify and update commands")
+		fam := (*started)[0]
+		assert.Equal(t, "old", fam.Lookup("query", "$and", "1", "_etag").StringValue(), "filter mismatch")
+		assert.Equal(t, int32(3), fam.Lookup("update", "$set", "x").Int32(), "expected $set of the update")
+		assert.Equal(t, int32(1), fam.Lookup("update", "$inc", "y").Int32(), "expected $inc of the update")
+		pending := fam.Lookup("update", "$set", "_etag").StringValue()
+		assert.NotEqual(t, "", pending, "expected the ETag to be set to a pending value")
+
+		set := (*started)[1].Lookup("updates").Array().Index(0).Document()
+		assert.Equal(t, pending, set.Lookup("q", "_etag").StringValue(), "expected the pending value to be matched")
+		assert.Equal(t, want, set.Lookup("u", "$set", "_etag").StringValue(), "expected the new ETag to be set")
+
+		_, err = coll.UpdateOneIfMatch(context.Background(), bson.D{}, bson.D{{"$set", bson.D{{"_etag", "x"}}}}, "old")
+		assert.Error(t, err, "expected error for an update of the ETag field")
+		_, err = coll.UpdateOneIfMatch(context.Background(), bson.D{}, Pipeline{{{"$set", bson.D{{"x", 1}}}}}, "old")
+		assert.Error(t, err, "expected error for an update pipeline")
+	})
+
+	t.Run("UpdateOneIfMatch mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newETagTestCollection(t, bson.D{{"ok", 1}, {"value", nil}})
+		_, err := coll.UpdateOneIfMatch(context.Background(), bson.D{}, bson.D{{"$set", bson.D{{"x", 1}}}}, "old")
+		assert.True(t, errors.Is(err, ErrETagMismatch), "expected ErrETagMismatch, got %v", err)
+		assert.Len(t, *started, 1, "expected no update of the ETag")
+	})
+
+	t.Run("DeleteOneIfMatch", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newETagTestCollection(t, bson.D{{"ok", 1}, {"n", 1}}, bson.D{{"ok", 1}, {"n", 0}})
+		require.NoError(t, coll.DeleteOneIfMatch(context.Background(), bson.D{{"_id", 1}}, "tag"), "DeleteOneIfMatch error")
+		del := (*started)[0].Lookup("deletes").Array().Index(0).Document()
+		assert.Equal(t, "tag", del.Lookup("q", "$and", "1", "_etag").StringValue(), "filter mismatch")
+
+		err := coll.DeleteOneIfMatch(context.Background(), bson.D{{"_id", 1}}, "tag")
+		assert.True(t, errors.Is(err, ErrETagMismatch), "expected ErrETagMismatch, got %v", err)
+	})
+}
+
+func mustIndex(t *testing.T, doc bson.Raw, i uint) bson.RawElement {
+	t.Helper()
+
+	elem, err := doc.IndexErr(i)
+	require.NoError(t, err, "IndexErr error")
+	return elem
+}
@@ -0,0 +1,375 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ExplainVerbosity is the verbosity mode of an explain command.
+type ExplainVerbosity string
+
+// These constants are the verbosity modes of an explain command.
+const (
+	// ExplainVerbosityQueryPlanner reports the plan selected by the query optimizer without running it.
+	ExplainVerbosityQueryPlanner ExplainVerbosity = "queryPlanner"
+
+	// ExplainVerbosityExecutionStats runs the selected plan and reports its execution statistics. Write
+	// operations are not applied.
+	ExplainVerbosityExecutionStats ExplainVerbosity = "executionStats"
+
+	// ExplainVerbosityAllPlansExecution also reports partial execution statistics of the rejected plans.
+	ExplainVerbosityAllPlansExecution ExplainVerbosity = "allPlansExecution"
+)
+
+// ExplainPlanStage is a stage of a query plan reported by the explain command. Plans are trees of
+// stages: most stages have one InputStage, and stages that combine several inputs, such as OR, have
+// InputStages.
+type ExplainPlanStage struct {
+	Stage       string   `bson:"stage"`
+	Filter      bson.Raw `bson:"filter"`
+	IndexName   string   `bson:"indexName"`
+	KeyPattern  bson.Raw `bson:"keyPattern"`
+	IsMultiKey  bool     `bson:"isMultiKey"`
+	Direction   string   `bson:"direction"`
+	IndexBounds bson.Raw `bson:"indexBounds"`
+
+	// The following fields are only reported in execution statistics.
+	NReturned           int64 `bson:"nReturned"`
+	ExecutionTimeMillis int64 `bson:"executionTimeMillisEstimate"`
+	KeysExamined        int64 `bson:"keysExamined"`
+	DocsExamined        int64 `bson:"docsExamined"`
+
+	InputStage  *ExplainPlanStage  `bson:"inputStage"`
+	InputStages []ExplainPlanStage `bson:"inputStages"`
+
+	// QueryPlan is the plan of servers that report the plan of the slot-based execution engine
+	// separately, in which case the other fields are not set.
+	QueryPlan *ExplainPlanStage `bson:"queryPlan"`
+
+	// Shards are the plans of each shard for the SINGLE_SHARD and SHARD_MERGE stages reported by
+	// mongos.
+	Shards []ExplainShardPlan `bson:"shards"`
+}
+
+// ExplainShardPlan is the query plan of a shard for an operation routed by mongos.
+type ExplainShardPlan struct {
+	ShardName        string             `bson:"shardName"`
+	ConnectionString string             `bson:"connectionString"`
+	WinningPlan      ExplainPlanStage   `bson:"winningPlan"`
+	RejectedPlans    []ExplainPlanStage `bson:"rejectedPlans"`
+}
+
+// ExplainQueryPlanner is the queryPlanner section of an explain result.
+type ExplainQueryPlanner struct {
+	Namespace      string             `bson:"namespace"`
+	ParsedQuery    bson.Raw           `bson:"parsedQuery"`
+	QueryHash      string             `bson:"queryHash"`
+	PlanCacheKey   string             `bson:"planCacheKey"`
+	WinningPlan    ExplainPlanStage   `bson:"winningPlan"`
+	RejectedPlans  []ExplainPlanStage `bson:"rejectedPlans"`
+	PlannerVersion int32              `bson:"plannerVersion"`
+}
+
+// ExplainShardExecutionStats are the execution statistics of a shard for an operation routed by
+// mongos.
+type ExplainShardExecutionStats struct {
+	ShardName           string           `bson:"shardName"`
+	NReturned           int64            `bson:"nReturned"`
+	ExecutionTimeMillis int64            `bson:"executionTimeMillis"`
+	TotalKeysExamined   int64            `bson:"totalKeysExamined"`
+	TotalDocsExamined   int64            `bson:"totalDocsExamined"`
+	ExecutionStages     ExplainPlanStage `bson:"executionStages"`
+}
+
+// ExplainExecutionStats is the executionStats section of an explain result, which is only
+// reported for the ExplainVerbosityExecutionStats and ExplainVerbosityAllPlansExecution verbosities.
+type ExplainExecutionStats struct {
+	ExecutionSuccess    bool                         `bson:"executionSuccess"`
+	NReturned           int64                        `bson:"nReturned"`
+	ExecutionTimeMillis int64                        `bson:"executionTimeMillis"`
+	TotalKeysExamined   int64                        `bson:"totalKeysExamined"`
+	TotalDocsExamined   int64                        `bson:"totalDocsExamined"`
+	ExecutionStages     ExplainPlanStage             `bson:"executionStages"`
+	Shards              []ExplainShardExecutionStats `bson:"shards"`
+}
+
+// ExplainServerInfo identifies the server that explained the operation.
+type ExplainServerInfo struct {
+	Host    string `bson:"host"`
+	Port    int32  `bson:"port"`
+	Version string `bson:"version"`
+}
+
+// ExplainResult is the result of an explain command.
+type ExplainResult struct {
+	QueryPlanner   ExplainQueryPlanner    `bson:"queryPlanner"`
+	ExecutionStats *ExplainExecutionStats `bson:"executionStats"`
+	ServerInfo     ExplainServerInfo      `bson:"serverInfo"`
+
+	// Stages are the explained stages of an aggregation pipeline whose stages were not all
+	// pushed down to the query layer. The query plan of the first, $cursor, stage is also
+	// reported in QueryPlanner and ExecutionStats.
+	Stages []bson.Raw `bson:"stages"`
+
+	// Raw is the complete explain output.
+	Raw bson.Raw `bson:"-"`
+}
+
+// IndexesUsed returns the names of the indexes scanned by the winning plan, including the plans of
+// all shards, without duplicates.
+func (er *ExplainResult) IndexesUsed() []string {
+	var names []string
+	seen := map[string]bool{}
+	er.QueryPlanner.WinningPlan.walk(func(s *ExplainPlanStage) {
+		if s.IndexName != "" && !seen[s.IndexName] {
+			seen[s.IndexName] = true
+			names = append(names, s.IndexName)
+		}
+	})
+	return names
+}
+
+// HasCollectionScan reports whether the winning plan, or the plan of any shard, scans the whole
+// collection.
+func (er *ExplainResult) HasCollectionScan() bool {
+	found := false
+	er.QueryPlanner.WinningPlan.walk(func(s *ExplainPlanStage) {
+		found = found || s.Stage == "COLLSCAN"
+	})
+	return found
+}
+
+// walk calls fn for the stage and all the stages it contains.
+func (s *ExplainPlanStage) walk(fn func(*ExplainPlanStage)) {
+	fn(s)
+	if s.QueryPlan != nil {
+		s.QueryPlan.walk(fn)
+	}
+	if s.InputStage != nil {
+		s.InputStage.walk(fn)
+	}
+	for i := range s.InputStages {
+		s.InputStages[i].walk(fn)
+	}
+	for i := range s.Shards {
+		s.Shards[i].WinningPlan.walk(fn)
+	}
+}
+
+// ExplainFind explains a find operation with the given filter and options using the explain
+// command with the given verbosity. The options that do not affect the query plan, such as the
+// batch size and cursor type, are ignored.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/explain/.
+func (coll *Collection) ExplainFind(
+	ctx context.Context,
+	filter interface{},
+	verbosity ExplainVerbosity,
+	opts ...options.Lister[options.FindOptions],
+) (*ExplainResult, error) {
+	args, err := mongoutil.NewOptions[options.FindOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	cmd := bson.D{{"find", coll.name}, {"filter", filter}}
+	appendIf := func(key string, val interface{}, ok bool) {
+		if ok {
+			cmd = append(cmd, bson.E{key, val})
+		}
+	}
+	appendIf("sort", args.Sort, args.Sort != nil)
+	appendIf("projection", args.Projection, args.Projection != nil)
+	appendIf("hint", args.Hint, args.Hint != nil)
+	appendIf("min", args.Min, args.Min != nil)
+	appendIf("max", args.Max, args.Max != nil)
+	appendIf("let", args.Let, args.Let != nil)
+	appendIf("comment", args.Comment, args.Comment != nil)
+	if args.Skip != nil {
+		cmd = append(cmd, bson.E{"skip", *args.Skip})
+	}
+	if args.Limit != nil && *args.Limit != 0 {
+		limit := *args.Limit
+		if limit < 0 {
+			limit = -limit
+			cmd = append(cmd, bson.E{"singleBatch", true})
+		}
+		cmd = append(cmd, bson.E{"limit", limit})
+	}
+	if args.Collation != nil {
+		cmd = append(cmd, bson.E{"collation", toDocument(args.Collation)})
+	}
+	if args.AllowDiskUse != nil {
+		cmd = append(cmd, bson.E{"allowDiskUse", *args.AllowDiskUse})
+	}
+	if args.ReturnKey != nil {
+		cmd = append(cmd, bson.E{"returnKey", *args.ReturnKey})
+	}
+	if args.ShowRecordID != nil {
+		cmd = append(cmd, bson.E{"showRecordId", *args.ShowRecordID})
+	}
+	return coll.explain(ctx, cmd, verbosity)
+}
+
+// ExplainAggregate explains an aggregate operation with the given pipeline and options using the
+// explain command with the given verbosity. Pipelines with $out or $merge stages can only be
+// explained with the ExplainVerbosityQueryPlanner verbosity.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/explain/.
+func (coll *Collection) ExplainAggregate(
+	ctx context.Context,
+	pipeline interface{},
+	verbosity ExplainVerbosity,
+	opts ...options.Lister[options.AggregateOptions],
+) (*ExplainResult, error) {
+	args, err := mongoutil.NewOptions[options.AggregateOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	pipelineArr, _, err := marshalAggregatePipeline(pipeline, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := bson.D{{"aggregate", coll.name}, {"pipeline", bson.RawArray(pipelineArr)}, {"cursor", bson.D{}}}
+	if args.AllowDiskUse != nil {
+		cmd = append(cmd, bson.E{"allowDiskUse", *args.AllowDiskUse})
+	}
+	if args.Collation != nil {
+		cmd = append(cmd, bson.E{"collation", toDocument(args.Collation)})
+	}
+	if args.Hint != nil {
+		cmd = append(cmd, bson.E{"hint", args.Hint})
+	}
+	if args.Let != nil {
+		cmd = append(cmd, bson.E{"let", args.Let})
+	}
+	if args.Comment != nil {
+		cmd = append(cmd, bson.E{"comment", args.Comment})
+	}
+	return coll.explain(ctx, cmd, verbosity)
+}
+
+// ExplainWrite explains the update, replace, or delete operation described by model, such as a
+// model of a BulkWrite, using the explain command with the given verbosity. The write is not
+// applied, even with the ExplainVerbosityExecutionStats verbosity. InsertOneModels cannot be explained.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/explain/.
+func (coll *Collection) ExplainWrite(
+	ctx context.Context,
+	model WriteModel,
+	verbosity ExplainVerbosity,
+) (*ExplainResult, error) {
+	cmd, err := explainWriteCommand(coll.name, model)
+	if err != nil {
+		return nil, err
+	}
+	return coll.explain(ctx, cmd, verbosity)
+}
+
+func explainWriteCommand(collName string, model WriteModel) (bson.D, error) {
+	filterOrEmpty := func(filter interface{}) interface{} {
+		if filter == nil {
+			return bson.D{}
+		}
+		return filter
+	}
+	update := func(filter, update interface{}, multi bool, upsert *bool, collation *options.Collation,
+		arrayFilters []interface{}, hint interface{},
+	) bson.D {
+		stmt := bson.D{{"q", filterOrEmpty(filter)}, {"u", update}, {"multi", multi}}
+		if upsert != nil {
+			stmt = append(stmt, bson.E{"upsert", *upsert})
+		}
+		if collation != nil {
+			stmt = append(stmt, bson.E{"collation", toDocument(collation)})
+		}
+		if arrayFilters != nil {
+			stmt = append(stmt, bson.E{"arrayFilters", arrayFilters})
+		}
+		if hint != nil {
+			stmt = append(stmt, bson.E{"hint", hint})
+		}
+		return bson.D{{"update", collName}, {"updates", bson.A{stmt}}}
+	}
+	del := func(filter interface{}, limit int32, collation *options.Collation, hint interface{}) bson.D {
+		stmt := bson.D{{"q", filterOrEmpty(filter)}, {"limit", limit}}
+		if collation != nil {
+			stmt = append(stmt, bson.E{"collation", toDocument(collation)})
+		}
+		if hint != nil {
+			stmt = append(stmt, bson.E{"hint", hint})
+		}
+		return bson.D{{"delete", collName}, {"deletes", bson.A{stmt}}}
+	}
+
+	switch m := model.(type) {
+	case *UpdateOneModel:
+		return update(m.Filter, m.Update, false, m.Upsert, m.Collation, m.ArrayFilters, m.Hint), nil
+	case *UpdateManyModel:
+		return update(m.Filter, m.Update, true, m.Upsert, m.Collation, m.ArrayFilters, m.Hint), nil
+	case *ReplaceOneModel:
+		return update(m.Filter, m.Replacement, false, m.Upsert, m.Collation, nil, m.Hint), nil
+	case *DeleteOneModel:
+		return del(m.Filter, 1, m.Collation, m.Hint), nil
+	case *DeleteManyModel:
+		return del(m.Filter, 0, m.Collation, m.Hint), nil
+	}
+	return nil, fmt.Errorf("cannot explain write model of type %T", model)
+}
+
+// explain runs the explain command for cmd and decodes its result.
+func (coll *Collection) explain(ctx context.Context, cmd bson.D, verbosity ExplainVerbosity) (*ExplainResult, error) {
+	if verbosity == "" {
+		verbosity = ExplainVerbosityQueryPlanner
+	}
+	explainCmd := bson.D{{"explain", cmd}, {"verbosity", string(verbosity)}}
+
+	rcOpts := options.RunCmd()
+	if coll.readPreference != nil {
+		rcOpts.SetReadPreference(coll.readPreference)
+	}
+	raw, err := coll.db.RunCommand(ctx, explainCmd, rcOpts).Raw()
+	if err != nil {
+		return nil, err
+	}
+	return decodeExplainResult(raw)
+}
+
+func decodeExplainResult(raw bson.Raw) (*ExplainResult, error) {
+	var res ExplainResult
+	if err := bson.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("error decoding explain result: %w", err)
+	}
+	res.Raw = raw
+
+	// Aggregations that were not pushed down to the query layer report the query plan in the
+	// $cursor stage.
+	if len(res.Stages) > 0 {
+		if cursorStage, err := res.Stages[0].LookupErr("$cursor"); err == nil {
+			if doc, ok := cursorStage.DocumentOK(); ok {
+				if err := bson.Unmarshal(doc, &res); err != nil {
+					return nil, fmt.Errorf("error decoding explain result: %w", err)
+				}
+			}
+		}
+	}
+	return &res, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestDecodeExplainResult(t *testing.T) {
+	marshal := func(v interface{}) bson.Raw {
+		b, err := bson.Marshal(v)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	ixscan := bson.D{{"stage", "FETCH"}, {"inputStage", bson.D{
+		{"stage", "IXSCAN"},
+		{"indexName", "sku_1"},
+		{"keyPattern", bson.D{{"sku", 1}}},
+	}}}
+
+	t.Run("find", func(t *testing.T) {
+		raw := marshal(bson.D{
+			{"queryPlanner", bson.D{
+				{"namespace", "db.coll"},
+				{"winningPlan", bson.D{{"queryPlan", ixscan}}},
+				{"rejectedPlans", bson.A{bson.D{{"stage", "COLLSCAN"}}}},
+			}},
+			{"executionStats", bson.D{
+				{"executionSuccess", true},
+				{"nReturned", int32(3)},
+				{"totalKeysExamined", int32(3)},
+				{"totalDocsExamined", int32(3)},
+			}},
+			{"serverInfo", bson.D{{"host", "db1"}, {"port", int32(27017)}, {"version", "8.0.0"}}},
+			{"ok", 1.0},
+		})
+
+		res, err := decodeExplainResult(raw)
+		require.NoError(t, err, "decodeExplainResult error: %v", err)
+		assert.Equal(t, "db.coll", res.QueryPlanner.Namespace, "expected namespace")
+		assert.Equal(t, []string{"sku_1"}, res.IndexesUsed(), "expected indexes used")
+		assert.False(t, res.HasCollectionScan(), "expected rejected plans to be ignored")
+		require.NotNil(t, res.ExecutionStats, "expected execution stats")
+		assert.Equal(t, int64(3), res.ExecutionStats.NReturned, "expected nReturned")
+		assert.Equal(t, "8.0.0", res.ServerInfo.Version, "expected server version")
+		assert.Equal(t, raw, res.Raw, "expected raw result")
+	})
+	t.Run("sharded", func(t *testing.T) {
+		raw := marshal(bson.D{{"queryPlanner", bson.D{{"winningPlan", bson.D{
+			{"stage", "SHARD_MERGE"},
+			{"shards", bson.A{
+				bson.D{{"shardName", "s1"}, {"winningPlan", ixscan}},
+				bson.D{{"shardName", "s2"}, {"winningPlan", bson.D{{"stage", "COLLSCAN"}}}},
+			}},
+		}}}}})
+
+		res, err := decodeExplainResult(raw)
+		require.NoError(t, err, "decodeExplainResult error: %v", err)
+		require.Len(t, res.QueryPlanner.WinningPlan.Shards, 2, "expected shard plans")
+		assert.Equal(t, "s2", res.QueryPlanner.WinningPlan.Shards[1].ShardName, "expected shard name")
+		assert.True(t, res.HasCollectionScan(), "expected collection scan on a shard")
+	})
+	t.Run("aggregate with $cursor stage", func(t *testing.T) {
+		raw := marshal(bson.D{{"stages", bson.A{
+			bson.D{{"$cursor", bson.D{{"queryPlanner", bson.D{{"winningPlan", ixscan}}}}}},
+			bson.D{{"$group", bson.D{{"_id", "$sku"}}}},
+		}}})
+
+		res, err := decodeExplainResult(raw)
+		require.NoError(t, err, "decodeExplainResult error: %v", err)
+		assert.Len(t, res.Stages, 2, "expected pipeline stages")
+		assert.Equal(t, []string{"sku_1"}, res.IndexesUsed(), "expected plan of the $cursor stage")
+	})
+}
+
+func TestExplainWriteCommand(t *testing.T) {
+	cmd, err := explainWriteCommand("orders", NewUpdateManyModel().
+		SetFilter(bson.D{{"status", "A"}}).
+		SetUpdate(bson.D{{"$set", bson.D{{"status", "B"}}}}).
+		SetUpsert(true))
+	require.NoError(t, err, "explainWriteCommand error: %v", err)
+	want := bson.D{{"update", "orders"}, {"updates", bson.A{bson.D{
+		{"q", bson.D{{"status", "A"}}},
+		{"u", bson.D{{"$set", bson.D{{"status", "B"}}}}},
+		{"multi", true},
+		{"upsert", true},
+	}}}}
+	assert.Equal(t, want, cmd, "expected update command")
+
+	cmd, err = explainWriteCommand("orders", NewDeleteOneModel().SetCollation(&options.Collation{Locale: "en"}))
+	require.NoError(t, err, "explainWriteCommand error: %v", err)
+	assert.Equal(t, "delete", cmd[0].Key, "expected delete command")
+
+	_, err = explainWriteCommand("orders", NewInsertOneModel())
+	assert.Error(t, err, "expected error for InsertOneModel")
+}
@@ -0,0 +1,14 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+// NewKeyVaultClientEncryption returns a ClientEncryption whose key vault is the
+// keyvault.datakeys collection of client. It has no Crypt, so only the methods that
+// manage key documents can be used.
+func NewKeyVaultClientEncryption(client *Client) *ClientEncryption {
+	return &ClientEncryption{keyVaultClient: client, keyVaultColl: client.Database("keyvault").Collection("datakeys")}
+}
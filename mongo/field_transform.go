@@ -0,0 +1,349 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// HMACTransform returns a FieldTransformer transform that derives the HMAC-SHA256 of the BSON type
+// and value of a field with key, as a BSON binary. Values of different BSON types, such as an int32
+// and an int64, have different HMACs.
+func HMACTransform(key []byte) func(bson.RawValue) (interface{}, error) {
+	return func(val bson.RawValue) (interface{}, error) {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte{byte(val.Type)})
+		mac.Write(val.Value)
+		return bson.Binary{Data: mac.Sum(nil)}, nil
+	}
+}
+
+// fieldTransformers derives the fields of the documents written by a Collection configured with
+// CollectionOptionsBuilder.SetFieldTransformers.
+type fieldTransformers struct {
+	transformers []fieldTransformer
+	err          error // the error of an invalid transformer, returned when transforming
+}
+
+type fieldTransformer struct {
+	field      string
+	path       []string // the components of field
+	target     string
+	targetPath string // the path of the target field
+	transform  func(bson.RawValue) (interface{}, error)
+}
+
+// newFieldTransformers returns the fieldTransformers for opts, or nil if opts is empty.
+func newFieldTransformers(opts []options.FieldTransformer) *fieldTransformers {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	ft := &fieldTransformers{}
+	for _, o := range opts {
+		path := strings.Split(o.Field, ".")
+		switch {
+		case o.Field == "" || strings.Contains(o.Field, "..") ||
+			strings.HasPrefix(o.Field, ".") || strings.HasSuffix(o.Field, "."):
+			ft.err = fmt.Errorf("invalid field transformer: invalid field %q", o.Field)
+		case o.Target == "" || strings.Contains(o.Target, "."):
+			ft.err = fmt.Errorf("invalid field transformer for field %q: invalid target %q", o.Field, o.Target)
+		case o.Target == path[len(path)-1]:
+			ft.err = fmt.Errorf("invalid field transformer for field %q: the target must differ from the field", o.Field)
+		case o.Transform == nil:
+			ft.err = fmt.Errorf("invalid field transformer for field %q: Transform must be set", o.Field)
+		}
+		if ft.err != nil {
+			return ft
+		}
+
+		targetPath := strings.Join(append(path[:len(path)-1:len(path)-1], o.Target), ".")
+		ft.transformers = append(ft.transformers, fieldTransformer{
+			field:      o.Field,
+			path:       path,
+			target:     o.Target,
+			targetPath: targetPath,
+			transform:  o.Transform,
+		})
+	}
+	return ft
+}
+
+// apply returns doc with the derived fields of its source fields set. It returns doc unchanged if
+// ft is nil.
+func (ft *fieldTransformers) apply(
+	doc bsoncore.Document,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Document, error) {
+	if ft == nil {
+		return doc, nil
+	}
+	if ft.err != nil {
+		return nil, ft.err
+	}
+
+	var err error
+	for i := range ft.transformers {
+		if doc, err = ft.transformers[i].applyAt(doc, ft.transformers[i].path, bsonOpts, registry); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// applyUpdate returns the update value u, which is a replacement document, an update document, or
+// an aggregation pipeline, with the derived fields of the source fields it sets. Pipelines are
+// returned unchanged, as is u if ft is nil.
+func (ft *fieldTransformers) applyUpdate(
+	u bsoncore.Value,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Value, error) {
+	if ft == nil || u.Type != bsoncore.TypeEmbeddedDocument {
+		return u, nil
+	}
+	if ft.err != nil {
+		return bsoncore.Value{}, ft.err
+	}
+
+	doc := bsoncore.Document(u.Data)
+	first, err := doc.IndexErr(0)
+	if err != nil || !strings.HasPrefix(first.Key(), "$") {
+		// A replacement document.
+		transformed, err := ft.apply(doc, bsonOpts, registry)
+		return bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: transformed}, err
+	}
+
+	elems, err := doc.Elements()
+	if err != nil {
+		return bsoncore.Value{}, err
+	}
+	for _, elem := range elems {
+		op, ok := elem.Value().DocumentOK()
+		if !ok {
+			continue
+		}
+
+		var transformed bsoncore.Document
+		switch elem.Key() {
+		case "$set", "$setOnInsert":
+			transformed, err = ft.applySet(op, bsonOpts, registry)
+		case "$unset":
+			transformed, err = ft.applyUnset(op)
+		default:
+			continue
+		}
+		if err != nil {
+			return bsoncore.Value{}, err
+		}
+		doc = setElement(doc, elem.Key(), bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: transformed})
+	}
+	return bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: doc}, nil
+}
+
+// applySet returns the $set document set with the derived fields of the source fields it sets,
+// either directly or with the embedded documents that contain them.
+func (ft *fieldTransformers) applySet(
+	set bsoncore.Document,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Document, error) {
+	elems, err := set.Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		for i := range ft.transformers {
+			t := &ft.transformers[i]
+			switch {
+			case key == t.field:
+				derived, err := t.derive(val, bsonOpts, registry)
+				if err != nil {
+					return nil, err
+				}
+				set = setElement(set, t.targetPath, derived)
+			case strings.HasPrefix(t.field, key+"."):
+				sub, ok := val.DocumentOK()
+				if !ok {
+					continue
+				}
+				parts := strings.Count(key, ".") + 1
+				sub, err := t.applyAt(sub, t.path[parts:], bsonOpts, registry)
+				if err != nil {
+					return nil, err
+				}
+				val = bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: sub}
+				set = setElement(set, key, val)
+			}
+		}
+	}
+	return set, nil
+}
+
+// applyUnset returns the $unset document unset with the derived fields of the source fields it
+// unsets.
+func (ft *fieldTransformers) applyUnset(unset bsoncore.Document) (bsoncore.Document, error) {
+	elems, err := unset.Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range elems {
+		for _, t := range ft.transformers {
+			if elem.Key() == t.field {
+				unset = setElement(unset, t.targetPath, bsoncore.Value{Type: bsoncore.TypeString, Data: bsoncore.AppendString(nil, "")})
+			}
+		}
+	}
+	return unset, nil
+}
+
+// strip returns doc without the derived fields. It returns doc unchanged if ft is nil.
+func (ft *fieldTransformers) strip(doc bson.Raw) (bson.Raw, error) {
+	if ft == nil || ft.err != nil {
+		return doc, nil
+	}
+
+	stripped := bsoncore.Document(doc)
+	for _, t := range ft.transformers {
+		var err error
+		if stripped, err = removeAt(stripped, t.path[:len(t.path)-1], t.target); err != nil {
+			return nil, err
+		}
+	}
+	return bson.Raw(stripped), nil
+}
+
+// applyAt returns doc with the derived field set for the source field at path in doc.
+func (t *fieldTransformer) applyAt(
+	doc bsoncore.Document,
+	path []string,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Document, error) {
+	val, err := doc.LookupErr(path[0])
+	if err != nil {
+		return doc, nil
+	}
+	if len(path) > 1 {
+		sub, ok := val.DocumentOK()
+		if !ok {
+			return doc, nil
+		}
+		if sub, err = t.applyAt(sub, path[1:], bsonOpts, registry); err != nil {
+			return nil, err
+		}
+		return setElement(doc, path[0], bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: sub}), nil
+	}
+
+	derived, err := t.derive(val, bsonOpts, registry)
+	if err != nil {
+		return nil, err
+	}
+	return setElement(doc, t.target, derived), nil
+}
+
+// derive returns the derived value of the source field value val.
+func (t *fieldTransformer) derive(
+	val bsoncore.Value,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Value, error) {
+	derived, err := t.transform(bson.RawValue{Type: bson.Type(val.Type), Value: val.Data})
+	if err != nil {
+		return bsoncore.Value{}, fmt.Errorf("failed to transform field %q: %w", t.field, err)
+	}
+	return marshalValue(derived, bsonOpts, registry)
+}
+
+// setElement returns a copy of doc with the field key set to val, in place if doc has the field and
+// last otherwise.
+func setElement(doc bsoncore.Document, key string, val bsoncore.Value) bsoncore.Document {
+	elems, _ := doc.Elements()
+	idx, dst := bsoncore.AppendDocumentStart(make([]byte, 0, len(doc)+len(key)+len(val.Data)+2))
+	var found bool
+	for _, elem := range elems {
+		if elem.Key() == key {
+			dst = bsoncore.AppendValueElement(dst, key, val)
+			found = true
+			continue
+		}
+		dst = append(dst, elem...)
+	}
+	if !found {
+		dst = bsoncore.AppendValueElement(dst, key, val)
+	}
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+	return dst
+}
+
+// removeAt returns doc without the field key of the embedded document at path.
+func removeAt(doc bsoncore.Document, path []string, key string) (bsoncore.Document, error) {
+	if len(path) > 0 {
+		val, err := doc.LookupErr(path[0])
+		if err != nil {
+			return doc, nil
+		}
+		sub, ok := val.DocumentOK()
+		if !ok {
+			return doc, nil
+		}
+		if sub, err = removeAt(sub, path[1:], key); err != nil {
+			return nil, err
+		}
+		return setElement(doc, path[0], bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: sub}), nil
+	}
+
+	if _, err := doc.LookupErr(key); err != nil {
+		return doc, nil
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	idx, dst := bsoncore.AppendDocumentStart(make([]byte, 0, len(doc)))
+	for _, elem := range elems {
+		if elem.Key() != key {
+			dst = append(dst, elem...)
+		}
+	}
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+// FieldToken returns the value derived from value by the FieldTransformer of the Collection for
+// the given source field, which can be used to query the derived field, e.g.
+//
+//	token, err := coll.FieldToken("ssn", "123-45-6789")
+//	cursor, err := coll.Find(ctx, bson.D{{"ssnToken", token}})
+//
+// It returns an error if the Collection has no FieldTransformer for field.
+func (coll *Collection) FieldToken(field string, value interface{}) (interface{}, error) {
+	if coll.transforms != nil && coll.transforms.err != nil {
+		return nil, coll.transforms.err
+	}
+	if coll.transforms != nil {
+		for _, t := range coll.transforms.transformers {
+			if t.field != field {
+				continue
+			}
+			val, err := marshalValue(value, coll.bsonOpts, coll.registry)
+			if err != nil {
+				return nil, err
+			}
+			return t.transform(bson.RawValue{Type: bson.Type(val.Type), Value: val.Data})
+		}
+	}
+	return nil, fmt.Errorf("the collection has no field transformer for field %q", field)
+}
@@ -0,0 +1,169 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var testTransformKey = []byte("0123456789abcdef")
+
+// newTransformTestCollection returns a collection of a Client that runs its operations against d
+// and derives "ssnToken" from "ssn" and "profile.emailToken" from "profile.email".
+func newTransformTestCollection(t *testing.T, d *mongotest.Deployment) *mongo.Collection {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	collOpts := options.Collection().SetFieldTransformers(
+		options.FieldTransformer{Field: "ssn", Target: "ssnToken", Transform: mongo.HMACTransform(testTransformKey)},
+		options.FieldTransformer{Field: "profile.email", Target: "emailToken", Transform: mongo.HMACTransform(testTransformKey)},
+	)
+	return client.Database("db").Collection("coll", collOpts)
+}
+
+// rawValue marshals v to a bson.RawValue.
+func rawValue(t *testing.T, v interface{}) bson.RawValue {
+	t.Helper()
+
+	typ, data, err := bson.MarshalValue(v)
+	require.NoError(t, err, "MarshalValue error")
+	return bson.RawValue{Type: typ, Value: data}
+}
+
+func TestFieldTransformers(t *testing.T) {
+	t.Parallel()
+
+	token := func(t *testing.T, coll *mongo.Collection, field string, value interface{}) bson.RawValue {
+		t.Helper()
+
+		tok, err := coll.FieldToken(field, value)
+		require.NoError(t, err, "FieldToken error")
+		return rawValue(t, tok)
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("insert", mongotest.Success(bson.E{Key: "n", Value: 1}))
+		coll := newTransformTestCollection(t, d)
+		_, err := coll.InsertOne(context.Background(), bson.D{
+			{"_id", 1},
+			{"ssn", "123-45-6789"},
+			{"profile", bson.D{{"email", "a@example.com"}}},
+		})
+		require.NoError(t, err, "InsertOne error")
+
+		doc := d.Commands("insert")[0].Document.Lookup("documents").Array().Index(0).Document()
+		assert.Equal(t, token(t, coll, "ssn", "123-45-6789"), doc.Lookup("ssnToken"), "ssn token mismatch")
+		assert.Equal(t, token(t, coll, "profile.email", "a@example.com"), doc.Lookup("profile", "emailToken"),
+			"email token mismatch")
+		assert.Equal(t, "123-45-6789", doc.Lookup("ssn").StringValue(), "expected the source field to be kept")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		updated := mongotest.Success(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+		d.AddReplies("update", updated, updated)
+		coll := newTransformTestCollection(t, d)
+		_, err := coll.UpdateOne(context.Background(), bson.D{}, bson.D{
+			{"$set", bson.D{{"ssn", "987-65-4321"}, {"profile", bson.D{{"email", "b@example.com"}}}}},
+			{"$unset", bson.D{{"profile.email", ""}}},
+		})
+		require.NoError(t, err, "UpdateOne error")
+		_, err = coll.UpdateOne(context.Background(), bson.D{}, bson.A{bson.D{{"$set", bson.D{{"ssn", "x"}}}}})
+		require.NoError(t, err, "UpdateOne error")
+
+		updates := d.Commands("update")
+		u := updates[0].Document.Lookup("updates").Array().Index(0).Document().Lookup("u").Document()
+		assert.Equal(t, token(t, coll, "ssn", "987-65-4321"), u.Lookup("$set", "ssnToken"), "ssn token mismatch")
+		assert.Equal(t, token(t, coll, "profile.email", "b@example.com"), u.Lookup("$set", "profile", "emailToken"),
+			"email token mismatch")
+		_, err = u.LookupErr("$unset", "profile.emailToken")
+		assert.NoError(t, err, "expected the token of the unset field to be unset")
+
+		pipeline := updates[1].Document.Lookup("updates").Array().Index(0).Document().Lookup("u").Array()
+		_, err = pipeline.Index(0).Document().LookupErr("$set", "ssnToken")
+		assert.Error(t, err, "expected the pipeline to not be transformed")
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+
+		doc := bson.D{
+			{"_id", 1},
+			{"ssn", "123"},
+			{"ssnToken", bson.Binary{Data: []byte{1}}},
+			{"profile", bson.D{{"email", "a@example.com"}, {"emailToken", bson.Binary{Data: []byte{2}}}}},
+		}
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.coll", 0, doc))
+		coll := newTransformTestCollection(t, d)
+
+		res := coll.FindOne(context.Background(), bson.D{})
+		var got bson.M
+		require.NoError(t, res.Decode(&got), "Decode error")
+		assert.NotContains(t, got, "ssnToken", "expected the ssn token to be stripped")
+		profile, ok := got["profile"].(bson.D)
+		require.True(t, ok, "expected profile to be a bson.D, got %T", got["profile"])
+		assert.Equal(t, bson.D{{"email", "a@example.com"}}, profile, "expected the email token to be stripped")
+		assert.Equal(t, "123", got["ssn"], "expected the source field to be kept")
+
+		raw, err := res.Raw()
+		require.NoError(t, err, "Raw error")
+		_, err = raw.LookupErr("ssnToken")
+		assert.NoError(t, err, "expected the raw document to keep the token")
+	})
+
+	t.Run("FieldToken without transformer", func(t *testing.T) {
+		t.Parallel()
+
+		coll := newTransformTestCollection(t, mongotest.NewDeployment())
+		_, err := coll.FieldToken("name", "x")
+		assert.Error(t, err, "expected an error for a field without transformer")
+	})
+
+	t.Run("invalid transformer", func(t *testing.T) {
+		t.Parallel()
+
+		coll := newTransformTestCollection(t, mongotest.NewDeployment())
+		coll = coll.Clone(options.Collection().SetFieldTransformers(
+			options.FieldTransformer{Field: "a.b", Target: "c.d", Transform: mongo.HMACTransform(testTransformKey)}))
+		_, err := coll.InsertOne(context.Background(), bson.D{{"a", 1}})
+		assert.Error(t, err, "expected an error for an invalid target")
+	})
+}
+
+func TestHMACTransform(t *testing.T) {
+	t.Parallel()
+
+	transform := mongo.HMACTransform(testTransformKey)
+	hash := func(v interface{}) interface{} {
+		t.Helper()
+
+		out, err := transform(rawValue(t, v))
+		require.NoError(t, err, "transform error")
+		return out
+	}
+
+	assert.Equal(t, hash("a"), hash("a"), "expected the transform to be deterministic")
+	assert.NotEqual(t, hash("a"), hash("b"), "expected different values to have different tokens")
+	assert.NotEqual(t, hash(int32(1)), hash(int64(1)), "expected different types to have different tokens")
+}
@@ -54,8 +54,10 @@ type GridFSBucket struct {
 
 // upload contains options to upload a file to a bucket.
 type upload struct {
-	chunkSize int32
-	metadata  bson.D
+	chunkSize          int32
+	metadata           bson.D
+	disableCompression bool
+	computeSHA256      bool
 }
 
 // OpenUploadStream creates a file ID new upload stream for a file given the
@@ -508,7 +510,16 @@ func (b *GridFSBucket) createIndexes(ctx context.Context) error {
 
 	filesIv := b.filesColl.Indexes()
 	chunksIv := b.chunksColl.Indexes()
+	filesModel, chunksModel := gridFSIndexModels()
+
+	if err = createNumericalIndexIfNotExists(ctx, filesIv, filesModel); err != nil {
+		return err
+	}
+	return createNumericalIndexIfNotExists(ctx, chunksIv, chunksModel)
+}
 
+// gridFSIndexModels returns the indexes required by the GridFS spec on the files and chunks collections.
+func gridFSIndexModels() (IndexModel, IndexModel) {
 	filesModel := IndexModel{
 		Keys: bson.D{
 			{"filename", int32(1)},
@@ -523,11 +534,7 @@ func (b *GridFSBucket) createIndexes(ctx context.Context) error {
 		},
 		Options: options.Index().SetUnique(true),
 	}
-
-	if err = createNumericalIndexIfNotExists(ctx, filesIv, filesModel); err != nil {
-		return err
-	}
-	return createNumericalIndexIfNotExists(ctx, chunksIv, chunksModel)
+	return filesModel, chunksModel
 }
 
 func (b *GridFSBucket) checkFirstWrite(ctx context.Context) error {
@@ -557,6 +564,12 @@ func (b *GridFSBucket) parseGridFSUploadOptions(opts ...options.Lister[options.G
 	if args.ChunkSizeBytes != nil {
 		upload.chunkSize = *args.ChunkSizeBytes
 	}
+	if args.DisableCompression != nil {
+		upload.disableCompression = *args.DisableCompression
+	}
+	if args.ComputeSHA256 != nil {
+		upload.computeSHA256 = *args.ComputeSHA256
+	}
 	if args.Registry == nil {
 		args.Registry = defaultRegistry
 	}
@@ -7,7 +7,10 @@
 package mongo
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"hash"
 
 	"context"
 	"time"
@@ -39,6 +42,7 @@ type GridFSUploadStream struct {
 	buffer      []byte
 	bufferIndex int
 	fileLen     int64
+	hash        hash.Hash
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
@@ -52,7 +56,7 @@ func newUploadStream(
 	filename string,
 	chunks, files *Collection,
 ) *GridFSUploadStream {
-	return &GridFSUploadStream{
+	us := &GridFSUploadStream{
 		upload: up,
 		FileID: fileID,
 
@@ -63,6 +67,10 @@ func newUploadStream(
 		ctx:        ctx,
 		cancel:     cancel,
 	}
+	if up.computeSHA256 {
+		us.hash = sha256.New()
+	}
+	return us
 }
 
 // Close writes file metadata to the files collection and cleans up any resources associated with the UploadStream.
@@ -99,6 +107,9 @@ func (us *GridFSUploadStream) Write(p []byte) (int, error) {
 	}
 
 	origLen := len(p)
+	if us.hash != nil {
+		_, _ = us.hash.Write(p)
+	}
 	for {
 		if len(p) == 0 {
 			break
@@ -173,6 +184,9 @@ func (us *GridFSUploadStream) uploadChunks(ctx context.Context, uploadPartial bo
 		us.fileLen += int64(len(chunkData))
 	}
 
+	if us.disableCompression {
+		ctx = WithoutCompression(ctx)
+	}
 	_, err := us.chunksColl.InsertMany(ctx, docs)
 	if err != nil {
 		return err
@@ -196,8 +210,18 @@ func (us *GridFSUploadStream) createFilesCollDoc(ctx context.Context) error {
 		{"filename", us.filename},
 	}
 
-	if us.metadata != nil {
-		doc = append(doc, bson.E{"metadata", us.metadata})
+	metadata := us.metadata
+	if us.hash != nil {
+		metadata = make(bson.D, 0, len(us.metadata)+1)
+		for _, e := range us.metadata {
+			if e.Key != gridFSSHA256Field {
+				metadata = append(metadata, e)
+			}
+		}
+		metadata = append(metadata, bson.E{gridFSSHA256Field, hex.EncodeToString(us.hash.Sum(nil))})
+	}
+	if metadata != nil {
+		doc = append(doc, bson.E{"metadata", metadata})
 	}
 
 	_, err := us.filesColl.InsertOne(ctx, doc)
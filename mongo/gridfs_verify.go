@@ -0,0 +1,315 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/csot"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// gridFSSHA256Field is the field of the file metadata that stores the SHA-256 digest of the file
+// contents, see options.GridFSUploadOptionsBuilder.SetComputeSHA256.
+const gridFSSHA256Field = "sha256"
+
+// ErrGridFSNotWritablePrimary is returned by GridFSBucket.EnsureIndexes if the server that would
+// create the indexes is not a writable primary.
+var ErrGridFSNotWritablePrimary = errors.New("GridFS indexes can only be created on a writable primary")
+
+// GridFSFileVerification is the result of GridFSBucket.VerifyFile.
+type GridFSFileVerification struct {
+	// FileID is the ID of the verified file.
+	FileID interface{}
+
+	// Length and ChunkSize are the length and the chunk size of the file, and ExpectedChunks is the
+	// number of chunks that the file consists of.
+	Length         int64
+	ChunkSize      int32
+	ExpectedChunks int64
+
+	// Chunks is the number of chunks of the file found in the chunks collection.
+	Chunks int64
+
+	// MissingChunks are the indexes of the chunks of the file that were not found.
+	MissingChunks []int64
+
+	// ExtraChunks are the indexes of the chunks that do not belong to the file because their index
+	// is beyond the length of the file or is duplicated. They are removed by GridFSBucket.Repair.
+	ExtraChunks []int64
+
+	// InvalidChunks are the indexes of the chunks whose data is not binary or does not have the
+	// expected length.
+	InvalidChunks []int64
+
+	// SHA256 is the hexadecimal SHA-256 digest stored in the metadata of the file, or empty if the
+	// file has none, and SHA256Verified is true if it matches the digest of the chunks.
+	SHA256         string
+	SHA256Verified bool
+}
+
+// OK returns true if the file has all its chunks with the expected lengths and no extra chunks,
+// and its contents match its SHA-256 digest if it has one.
+func (v *GridFSFileVerification) OK() bool {
+	return len(v.MissingChunks) == 0 && len(v.ExtraChunks) == 0 && len(v.InvalidChunks) == 0 &&
+		(v.SHA256 == "" || v.SHA256Verified)
+}
+
+// GridFSRepairResult is the result of GridFSBucket.Repair.
+type GridFSRepairResult struct {
+	// OrphanedFileIDs are the files_id values of the chunks whose file document does not exist.
+	OrphanedFileIDs []interface{}
+
+	// TruncatedFileIDs are the IDs of the files that have extra chunks beyond their length.
+	TruncatedFileIDs []interface{}
+
+	// RemovedChunks is the number of chunks removed, or that would be removed if the repair was a
+	// dry run.
+	RemovedChunks int64
+}
+
+// VerifyFile reads the chunks of the file with the given ID and checks that the file is complete:
+// each chunk exists exactly once and has the length expected from the length and the chunk size
+// of the file. If the metadata of the file has a "sha256" field with the hexadecimal SHA-256
+// digest of the file, as stored by uploads with options.GridFSUploadOptionsBuilder.SetComputeSHA256,
+// the digest is recomputed from the chunks and compared. ErrFileNotFound is returned if the file
+// does not exist.
+func (b *GridFSBucket) VerifyFile(ctx context.Context, fileID interface{}) (*GridFSFileVerification, error) {
+	ctx, cancel := csot.WithTimeout(ctx, b.db.client.timeout)
+	defer cancel()
+
+	var resp findFileResponse
+	if err := b.filesColl.FindOne(ctx, bson.D{{"_id", fileID}}).Decode(&resp); err != nil {
+		if errors.Is(err, ErrNoDocuments) {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("error decoding files collection document: %w", err)
+	}
+	file := newFileFromResponse(resp)
+	if file.Length > 0 && file.ChunkSize <= 0 {
+		return nil, ErrMissingGridFSChunkSize
+	}
+
+	v := &GridFSFileVerification{
+		FileID:    file.ID,
+		Length:    file.Length,
+		ChunkSize: file.ChunkSize,
+	}
+	if file.Length > 0 {
+		v.ExpectedChunks = (file.Length + int64(file.ChunkSize) - 1) / int64(file.ChunkSize)
+	}
+
+	var wantDigest []byte
+	if file.Metadata != nil {
+		if val, err := file.Metadata.LookupErr(gridFSSHA256Field); err == nil {
+			if str, ok := val.StringValueOK(); ok {
+				v.SHA256 = str
+				wantDigest, _ = hex.DecodeString(str)
+			}
+		}
+	}
+
+	cursor, err := b.findChunks(ctx, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	hash := sha256.New()
+	next := int64(0)
+	for cursor.Next(ctx) {
+		v.Chunks++
+
+		n, ok := cursor.Current.Lookup("n").AsInt64OK()
+		if !ok || n < 0 || n >= v.ExpectedChunks || n < next {
+			v.ExtraChunks = append(v.ExtraChunks, n)
+			continue
+		}
+		for ; next < n; next++ {
+			v.MissingChunks = append(v.MissingChunks, next)
+		}
+		next = n + 1
+
+		wantLen := int64(file.ChunkSize)
+		if n == v.ExpectedChunks-1 {
+			wantLen = file.Length - n*int64(file.ChunkSize)
+		}
+		subtype, data, ok := cursor.Current.Lookup("data").BinaryOK()
+		if !ok || subtype != bson.TypeBinaryGeneric || int64(len(data)) != wantLen {
+			v.InvalidChunks = append(v.InvalidChunks, n)
+			continue
+		}
+		_, _ = hash.Write(data)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	for ; next < v.ExpectedChunks; next++ {
+		v.MissingChunks = append(v.MissingChunks, next)
+	}
+
+	v.SHA256Verified = wantDigest != nil && len(v.MissingChunks) == 0 && len(v.InvalidChunks) == 0 &&
+		bytes.Equal(hash.Sum(nil), wantDigest)
+	return v, nil
+}
+
+// Repair removes the orphaned chunks of the bucket: the chunks whose file document does not exist,
+// e.g. because an upload was interrupted or the file document was removed without its chunks,
+// and the chunks whose index is beyond the length of their file. Only chunks older than the grace
+// period of the options are removed, so that the chunks of the uploads in progress, which are
+// written before the documents of their files, are kept. Chunks whose _id is not an ObjectID are
+// never removed because their age is unknown.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.GridFSRepairOptions documentation).
+func (b *GridFSBucket) Repair(
+	ctx context.Context,
+	opts ...options.Lister[options.GridFSRepairOptions],
+) (*GridFSRepairResult, error) {
+	args, err := mongoutil.NewOptions[options.GridFSRepairOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+	gracePeriod := options.DefaultGridFSRepairGracePeriod
+	if args.GracePeriod != nil {
+		gracePeriod = *args.GracePeriod
+	}
+	dryRun := args.DryRun != nil && *args.DryRun
+
+	ctx, cancel := csot.WithTimeout(ctx, b.db.client.timeout)
+	defer cancel()
+
+	cutoff := bson.D{{"$lt", bson.MinObjectIDFromTimestamp(time.Now().Add(-gracePeriod))}}
+	pipeline := Pipeline{
+		{{"$match", bson.D{{"_id", cutoff}}}},
+		{{"$group", bson.D{
+			{"_id", "$files_id"},
+			{"maxN", bson.D{{"$max", "$n"}}},
+		}}},
+		{{"$lookup", bson.D{
+			{"from", b.filesColl.Name()},
+			{"localField", "_id"},
+			{"foreignField", "_id"},
+			{"as", "file"},
+		}}},
+		{{"$project", bson.D{{"maxN", 1}, {"file.length", 1}, {"file.chunkSize", 1}}}},
+	}
+	cursor, err := b.chunksColl.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type chunkGroup struct {
+		FilesID bson.RawValue `bson:"_id"`
+		MaxN    int64         `bson:"maxN"`
+		File    []struct {
+			Length    int64 `bson:"length"`
+			ChunkSize int32 `bson:"chunkSize"`
+		} `bson:"file"`
+	}
+
+	result := &GridFSRepairResult{}
+	for cursor.Next(ctx) {
+		var group chunkGroup
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		var filesID interface{}
+		if err := group.FilesID.Unmarshal(&filesID); err != nil {
+			return nil, err
+		}
+
+		filter := bson.D{{"files_id", group.FilesID}, {"_id", cutoff}}
+		if len(group.File) == 0 {
+			result.OrphanedFileIDs = append(result.OrphanedFileIDs, filesID)
+		} else {
+			file := group.File[0]
+			if file.ChunkSize <= 0 {
+				continue
+			}
+			expected := (file.Length + int64(file.ChunkSize) - 1) / int64(file.ChunkSize)
+			if group.MaxN < expected {
+				continue
+			}
+			result.TruncatedFileIDs = append(result.TruncatedFileIDs, filesID)
+			filter = append(filter, bson.E{"n", bson.D{{"$gte", expected}}})
+		}
+
+		var removed int64
+		if dryRun {
+			removed, err = b.chunksColl.CountDocuments(ctx, filter)
+		} else {
+			var res *DeleteResult
+			res, err = b.chunksColl.DeleteMany(ctx, filter)
+			if res != nil {
+				removed = res.DeletedCount
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.RemovedChunks += removed
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EnsureIndexes creates the indexes recommended for the bucket if they do not exist: the index on
+// the filename and upload date of the files collection and the unique index on the file ID and
+// chunk index of the chunks collection. It also creates an ascending index on each of the given
+// fields of the file metadata, e.g. "owner" for the "metadata.owner" field, to support finding
+// files by their metadata with Find.
+//
+// The indexes are created by the first upload to an empty bucket, so EnsureIndexes is only needed
+// for buckets whose files were written by other means, or to add metadata indexes. It returns
+// ErrGridFSNotWritablePrimary without creating any index if the server selected for the indexes
+// is not a writable primary, e.g. for a direct connection to a secondary.
+func (b *GridFSBucket) EnsureIndexes(ctx context.Context, metadataFields ...string) error {
+	ctx, cancel := csot.WithTimeout(ctx, b.db.client.timeout)
+	defer cancel()
+
+	var hello struct {
+		IsWritablePrimary bool `bson:"isWritablePrimary"`
+	}
+	err := b.db.RunCommand(ctx, bson.D{{"hello", 1}}, options.RunCmd().SetReadPreference(readpref.Primary())).
+		Decode(&hello)
+	if err != nil {
+		return err
+	}
+	if !hello.IsWritablePrimary {
+		return ErrGridFSNotWritablePrimary
+	}
+
+	filesModel, chunksModel := gridFSIndexModels()
+	if err := createNumericalIndexIfNotExists(ctx, b.filesColl.Indexes(), filesModel); err != nil {
+		return err
+	}
+	if err := createNumericalIndexIfNotExists(ctx, b.chunksColl.Indexes(), chunksModel); err != nil {
+		return err
+	}
+	for _, field := range metadataFields {
+		model := IndexModel{Keys: bson.D{{"metadata." + field, int32(1)}}}
+		if err := createNumericalIndexIfNotExists(ctx, b.filesColl.Indexes(), model); err != nil {
+			return err
+		}
+	}
+
+	b.firstWriteDone = true
+	return nil
+}
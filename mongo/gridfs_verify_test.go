@@ -0,0 +1,221 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// newTestBucket returns a GridFSBucket of a Client that runs its operations against d.
+func newTestBucket(t *testing.T, d *mongotest.Deployment) *mongo.GridFSBucket {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return client.Database("db").GridFSBucket()
+}
+
+func gridFSChunk(fileID interface{}, n int32, data string) bson.D {
+	return bson.D{
+		{"_id", bson.NewObjectID()},
+		{"files_id", fileID},
+		{"n", n},
+		{"data", bson.Binary{Data: []byte(data)}},
+	}
+}
+
+func TestGridFSBucketVerifyFile(t *testing.T) {
+	t.Parallel()
+
+	fileID := bson.NewObjectID()
+	digest := sha256.Sum256([]byte("abcdefghij"))
+	fileDoc := bson.D{
+		{"_id", fileID},
+		{"length", int64(10)},
+		{"chunkSize", int32(4)},
+		{"filename", "file"},
+		{"metadata", bson.D{{"sha256", hex.EncodeToString(digest[:])}}},
+	}
+
+	t.Run("complete file", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find",
+			mongotest.Cursor("db.fs.files", 0, fileDoc),
+			mongotest.Cursor("db.fs.chunks", 0,
+				gridFSChunk(fileID, 0, "abcd"),
+				gridFSChunk(fileID, 1, "efgh"),
+				gridFSChunk(fileID, 2, "ij"),
+			),
+		)
+		bucket := newTestBucket(t, d)
+
+		v, err := bucket.VerifyFile(context.Background(), fileID)
+		require.NoError(t, err, "VerifyFile error")
+		assert.True(t, v.OK(), "expected the file to be verified: %+v", v)
+		assert.Equal(t, int64(3), v.ExpectedChunks, "ExpectedChunks mismatch")
+		assert.Equal(t, int64(3), v.Chunks, "Chunks mismatch")
+		assert.True(t, v.SHA256Verified, "expected the SHA-256 digest to match")
+	})
+	t.Run("damaged file", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find",
+			mongotest.Cursor("db.fs.files", 0, fileDoc),
+			mongotest.Cursor("db.fs.chunks", 0,
+				gridFSChunk(fileID, 0, "abcd"),
+				gridFSChunk(fileID, 2, "i"),
+				gridFSChunk(fileID, 2, "ij"),
+				gridFSChunk(fileID, 5, "kl"),
+			),
+		)
+		bucket := newTestBucket(t, d)
+
+		v, err := bucket.VerifyFile(context.Background(), fileID)
+		require.NoError(t, err, "VerifyFile error")
+		assert.False(t, v.OK(), "expected the file not to be verified")
+		assert.Equal(t, []int64{1}, v.MissingChunks, "MissingChunks mismatch")
+		assert.Equal(t, []int64{2, 5}, v.ExtraChunks, "ExtraChunks mismatch")
+		assert.Equal(t, []int64{2}, v.InvalidChunks, "InvalidChunks mismatch")
+		assert.False(t, v.SHA256Verified, "expected the SHA-256 digest not to match")
+	})
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("find", mongotest.Cursor("db.fs.files", 0))
+		bucket := newTestBucket(t, d)
+
+		_, err := bucket.VerifyFile(context.Background(), fileID)
+		assert.True(t, errors.Is(err, mongo.ErrFileNotFound), "expected ErrFileNotFound, got %v", err)
+	})
+}
+
+func TestGridFSBucketRepair(t *testing.T) {
+	t.Parallel()
+
+	orphaned := bson.NewObjectID()
+	truncated := bson.NewObjectID()
+	complete := bson.NewObjectID()
+	file := bson.A{bson.D{{"length", int64(10)}, {"chunkSize", int32(4)}}}
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("aggregate", mongotest.Cursor("db.fs.chunks", 0,
+		bson.D{{"_id", orphaned}, {"maxN", int32(2)}, {"file", bson.A{}}},
+		bson.D{{"_id", truncated}, {"maxN", int32(3)}, {"file", file}},
+		bson.D{{"_id", complete}, {"maxN", int32(2)}, {"file", file}},
+	))
+	d.AddReplies("delete",
+		mongotest.Success(bson.E{Key: "n", Value: 3}),
+		mongotest.Success(bson.E{Key: "n", Value: 1}),
+	)
+	bucket := newTestBucket(t, d)
+
+	res, err := bucket.Repair(context.Background())
+	require.NoError(t, err, "Repair error")
+	assert.Equal(t, []interface{}{orphaned}, res.OrphanedFileIDs, "OrphanedFileIDs mismatch")
+	assert.Equal(t, []interface{}{truncated}, res.TruncatedFileIDs, "TruncatedFileIDs mismatch")
+	assert.Equal(t, int64(4), res.RemovedChunks, "RemovedChunks mismatch")
+
+	cmds := d.Commands()
+	require.Len(t, cmds, 3, "expected an aggregate and two deletes")
+	lookup := cmds[0].Document.Lookup("pipeline").Array().Index(2).Document().Lookup("$lookup")
+	assert.Equal(t, "fs.files", lookup.Document().Lookup("from").StringValue(), "$lookup mismatch")
+
+	orphanedFilter := cmds[1].Document.Lookup("deletes").Array().Index(0).Document().Lookup("q").Document()
+	assert.Equal(t, orphaned, orphanedFilter.Lookup("files_id").ObjectID(), "orphaned filter mismatch")
+	_, err = orphanedFilter.LookupErr("_id", "$lt")
+	assert.NoError(t, err, "expected the grace period to be applied")
+
+	truncatedFilter := cmds[2].Document.Lookup("deletes").Array().Index(0).Document().Lookup("q").Document()
+	assert.Equal(t, truncated, truncatedFilter.Lookup("files_id").ObjectID(), "truncated filter mismatch")
+	assert.Equal(t, int64(3), truncatedFilter.Lookup("n", "$gte").Int64(), "expected chunks beyond the file to be removed")
+}
+
+func TestGridFSBucketEnsureIndexes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not writable primary", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("hello", mongotest.Success(bson.E{Key: "isWritablePrimary", Value: false}))
+		bucket := newTestBucket(t, d)
+
+		err := bucket.EnsureIndexes(context.Background())
+		assert.True(t, errors.Is(err, mongo.ErrGridFSNotWritablePrimary), "expected ErrGridFSNotWritablePrimary, got %v", err)
+		assert.Len(t, d.Commands(), 1, "expected only the hello command")
+	})
+	t.Run("creates indexes", func(t *testing.T) {
+		t.Parallel()
+
+		d := mongotest.NewDeployment()
+		d.AddReplies("hello", mongotest.Success(bson.E{Key: "isWritablePrimary", Value: true}))
+		d.AddReplies("listIndexes",
+			mongotest.Cursor("db.fs.files", 0),
+			mongotest.Cursor("db.fs.chunks", 0),
+			mongotest.Cursor("db.fs.files", 0),
+		)
+		d.AddReplies("createIndexes", mongotest.Success(), mongotest.Success(), mongotest.Success())
+		bucket := newTestBucket(t, d)
+
+		err := bucket.EnsureIndexes(context.Background(), "owner")
+		require.NoError(t, err, "EnsureIndexes error")
+
+		var keys []bson.Raw
+		for _, cmd := range d.Commands("createIndexes") {
+			keys = append(keys, cmd.Document.Lookup("indexes").Array().Index(0).Document().Lookup("key").Document())
+		}
+		require.Len(t, keys, 3, "expected three indexes to be created")
+		assert.Equal(t, "filename", keys[0].Index(0).Key(), "files index mismatch")
+		assert.Equal(t, "files_id", keys[1].Index(0).Key(), "chunks index mismatch")
+		assert.Equal(t, "metadata.owner", keys[2].Index(0).Key(), "metadata index mismatch")
+	})
+}
+
+func TestGridFSUploadComputeSHA256(t *testing.T) {
+	t.Parallel()
+
+	// The files collection is not empty, so the upload does not create the indexes.
+	d := mongotest.NewDeployment()
+	d.AddReplies("find", mongotest.Cursor("db.fs.files", 0, bson.D{{"_id", 1}}))
+	d.AddReplies("insert",
+		mongotest.Success(bson.E{Key: "n", Value: 3}),
+		mongotest.Success(bson.E{Key: "n", Value: 1}),
+	)
+	bucket := newTestBucket(t, d)
+
+	opts := options.GridFSUpload().
+		SetChunkSizeBytes(4).
+		SetMetadata(bson.D{{"owner", "alice"}, {"sha256", "stale"}}).
+		SetComputeSHA256(true)
+	_, err := bucket.UploadFromStream(context.Background(), "file", bytes.NewReader([]byte("abcdefghij")), opts)
+	require.NoError(t, err, "UploadFromStream error")
+
+	cmds := d.Commands("insert")
+	require.Len(t, cmds, 2, "expected the chunks and the file document to be inserted")
+	metadata := cmds[1].Document.Lookup("documents").Array().Index(0).Document().Lookup("metadata").Document()
+	digest := sha256.Sum256([]byte("abcdefghij"))
+	assert.Equal(t, "alice", metadata.Lookup("owner").StringValue(), "expected the metadata to be kept")
+	assert.Equal(t, hex.EncodeToString(digest[:]), metadata.Lookup("sha256").StringValue(), "sha256 mismatch")
+}
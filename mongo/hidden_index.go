@@ -0,0 +1,240 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Hide runs the collMod command to hide the index with the given name from the query planner. A
+// hidden index is still maintained, and still enforces unique constraints and TTL expiration, but
+// is not used by queries, so hiding an index shows the effect of dropping it without having to
+// rebuild it to undo the change. Hidden indexes require MongoDB 4.4 or later.
+//
+// For more information about hidden indexes, see
+// https://www.mongodb.com/docs/manual/core/index-hidden/.
+func (iv IndexView) Hide(ctx context.Context, name string) error {
+	return iv.setHidden(ctx, name, true)
+}
+
+// Unhide runs the collMod command to make the hidden index with the given name visible to the
+// query planner again.
+func (iv IndexView) Unhide(ctx context.Context, name string) error {
+	return iv.setHidden(ctx, name, false)
+}
+
+func (iv IndexView) setHidden(ctx context.Context, name string, hidden bool) error {
+	cmd := bson.D{
+		{"collMod", iv.coll.name},
+		{"index", bson.D{{"name", name}, {"hidden", hidden}}},
+	}
+	return iv.coll.db.RunCommand(ctx, cmd).Err()
+}
+
+// indexHidden reports whether the index with the given name exists and is hidden.
+func (iv IndexView) indexHidden(ctx context.Context, name string) (exists, hidden bool, err error) {
+	specs, err := iv.ListSpecifications(ctx)
+	if err != nil {
+		return false, false, err
+	}
+	for _, spec := range specs {
+		if spec.Name == name {
+			return true, spec.Hidden != nil && *spec.Hidden, nil
+		}
+	}
+	return false, false, nil
+}
+
+// indexOps returns the total number of operations that used the index with the given name.
+func (iv IndexView) indexOps(ctx context.Context, name string) (int64, error) {
+	stats, err := iv.UsageStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range stats {
+		if s.Name == name {
+			return s.Ops, nil
+		}
+	}
+	return 0, fmt.Errorf("index %q not found", name)
+}
+
+// SafeIndexDropState is the state of a SafeIndexDrop.
+type SafeIndexDropState string
+
+// These constants are the states of a SafeIndexDrop.
+const (
+	// SafeIndexDropPending means the index has not been hidden yet.
+	SafeIndexDropPending SafeIndexDropState = "pending"
+
+	// SafeIndexDropObserving means the index is hidden and the observation period has not ended
+	// or the index has not been checked since it ended.
+	SafeIndexDropObserving SafeIndexDropState = "observing"
+
+	// SafeIndexDropDropped means the index has been dropped.
+	SafeIndexDropDropped SafeIndexDropState = "dropped"
+
+	// SafeIndexDropAborted means the drop was aborted and the index was made visible again. The
+	// reason is recorded in SafeIndexDrop.Reason.
+	SafeIndexDropAborted SafeIndexDropState = "aborted"
+)
+
+// SafeIndexDrop drops an index in stages so that dropping an index that is still needed can be
+// undone cheaply: the index is first hidden, then observed for a period, and only dropped if it is
+// still hidden and its usage statistics did not change while it was hidden. Operations that still
+// use a hidden index, such as those that hint it, fail during the observation period, and the index
+// can be made visible again with Abort.
+//
+// SafeIndexDrop is resumable: its exported fields describe the progress of the drop and can be
+// stored, for example as a BSON document, between calls to Step, which are typically made by a
+// periodic job. Step and Abort are not safe for concurrent use.
+type SafeIndexDrop struct {
+	// IndexName is the name of the index to drop.
+	IndexName string `bson:"indexName"`
+
+	// ObservationPeriod is how long the index is hidden before it is dropped.
+	ObservationPeriod time.Duration `bson:"observationPeriod"`
+
+	// State is the current state of the drop.
+	State SafeIndexDropState `bson:"state"`
+
+	// HiddenAt is when the index was hidden.
+	HiddenAt time.Time `bson:"hiddenAt,omitempty"`
+
+	// BaselineOps is the number of operations that had used the index when it was hidden, as
+	// reported by $indexStats.
+	BaselineOps int64 `bson:"baselineOps"`
+
+	// Reason is why the drop was aborted.
+	Reason string `bson:"reason,omitempty"`
+}
+
+// NewSafeIndexDrop creates a SafeIndexDrop that drops the index with the given name after hiding
+// it for the given observation period.
+func NewSafeIndexDrop(indexName string, observationPeriod time.Duration) *SafeIndexDrop {
+	return &SafeIndexDrop{
+		IndexName:         indexName,
+		ObservationPeriod: observationPeriod,
+		State:             SafeIndexDropPending,
+	}
+}
+
+// ReadyAt returns the time after which Step drops the index, or the zero time if the index has not
+// been hidden yet.
+func (sd *SafeIndexDrop) ReadyAt() time.Time {
+	if sd.HiddenAt.IsZero() {
+		return time.Time{}
+	}
+	return sd.HiddenAt.Add(sd.ObservationPeriod)
+}
+
+// Done reports whether the drop has finished, either because the index was dropped or because the
+// drop was aborted.
+func (sd *SafeIndexDrop) Done() bool {
+	return sd.State == SafeIndexDropDropped || sd.State == SafeIndexDropAborted
+}
+
+// Step advances the drop of the index of iv's collection as far as possible:
+//
+//   - If the drop is pending, it records the usage statistics of the index and hides it.
+//   - If the index is being observed and the observation period has ended, it drops the index if
+//     it is still hidden and has not been used since it was hidden. Otherwise, it makes the index
+//     visible again and aborts the drop.
+//   - Otherwise, it does nothing.
+//
+// Step returns an error, and leaves the state unchanged, if a command fails, so it can be retried.
+func (sd *SafeIndexDrop) Step(ctx context.Context, iv IndexView) error {
+	return sd.step(ctx, iv, time.Now())
+}
+
+// Abort makes the index visible again, if it was hidden, and aborts the drop with the given
+// reason. It does nothing if the drop has finished.
+func (sd *SafeIndexDrop) Abort(ctx context.Context, iv IndexView, reason string) error {
+	return sd.abort(ctx, iv, reason)
+}
+
+// safeIndexDropTarget is the subset of IndexView used by SafeIndexDrop.
+type safeIndexDropTarget interface {
+	setHidden(ctx context.Context, name string, hidden bool) error
+	indexHidden(ctx context.Context, name string) (exists, hidden bool, err error)
+	indexOps(ctx context.Context, name string) (int64, error)
+	DropOne(ctx context.Context, name string, opts ...options.Lister[options.DropIndexesOptions]) error
+}
+
+var _ safeIndexDropTarget = IndexView{}
+
+func (sd *SafeIndexDrop) step(ctx context.Context, target safeIndexDropTarget, now time.Time) error {
+	switch sd.State {
+	case SafeIndexDropPending:
+		ops, err := target.indexOps(ctx, sd.IndexName)
+		if err != nil {
+			return err
+		}
+		if err := target.setHidden(ctx, sd.IndexName, true); err != nil {
+			return err
+		}
+		sd.BaselineOps = ops
+		sd.HiddenAt = now
+		sd.State = SafeIndexDropObserving
+		return nil
+	case SafeIndexDropObserving:
+		if now.Before(sd.ReadyAt()) {
+			return nil
+		}
+
+		exists, hidden, err := target.indexHidden(ctx, sd.IndexName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			sd.State = SafeIndexDropAborted
+			sd.Reason = "index no longer exists"
+			return nil
+		}
+		if !hidden {
+			sd.State = SafeIndexDropAborted
+			sd.Reason = "index was made visible during the observation period"
+			return nil
+		}
+
+		ops, err := target.indexOps(ctx, sd.IndexName)
+		if err != nil {
+			return err
+		}
+		// Usage statistics are reset when a mongod restarts, so only an increase means the index
+		// was used while hidden.
+		if ops > sd.BaselineOps {
+			return sd.abort(ctx, target, fmt.Sprintf(
+				"index was used %d times during the observation period", ops-sd.BaselineOps))
+		}
+
+		if err := target.DropOne(ctx, sd.IndexName); err != nil {
+			return err
+		}
+		sd.State = SafeIndexDropDropped
+	}
+	return nil
+}
+
+func (sd *SafeIndexDrop) abort(ctx context.Context, target safeIndexDropTarget, reason string) error {
+	if sd.Done() {
+		return nil
+	}
+	if sd.State == SafeIndexDropObserving {
+		if err := target.setHidden(ctx, sd.IndexName, false); err != nil {
+			return err
+		}
+	}
+	sd.State = SafeIndexDropAborted
+	sd.Reason = reason
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fakeIndexes is a safeIndexDropTarget with a single index.
+type fakeIndexes struct {
+	exists  bool
+	hidden  bool
+	ops     int64
+	dropped bool
+}
+
+func (fi *fakeIndexes) setHidden(_ context.Context, _ string, hidden bool) error {
+	fi.hidden = hidden
+	return nil
+}
+
+func (fi *fakeIndexes) indexHidden(context.Context, string) (bool, bool, error) {
+	return fi.exists, fi.hidden, nil
+}
+
+func (fi *fakeIndexes) indexOps(context.Context, string) (int64, error) {
+	return fi.ops, nil
+}
+
+func (fi *fakeIndexes) DropOne(context.Context, string, ...options.Lister[options.DropIndexesOptions]) error {
+	fi.dropped = true
+	fi.exists = false
+	return nil
+}
+
+func TestSafeIndexDrop(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("drops unused index after observation period", func(t *testing.T) {
+		indexes := &fakeIndexes{exists: true, ops: 10}
+		sd := NewSafeIndexDrop("sku_1", 24*time.Hour)
+
+		require.NoError(t, sd.step(ctx, indexes, start), "step error")
+		assert.Equal(t, SafeIndexDropObserving, sd.State, "expected index to be observed")
+		assert.True(t, indexes.hidden, "expected index to be hidden")
+		assert.Equal(t, int64(10), sd.BaselineOps, "expected baseline ops")
+		assert.Equal(t, start.Add(24*time.Hour), sd.ReadyAt(), "expected ready time")
+
+		require.NoError(t, sd.step(ctx, indexes, start.Add(time.Hour)), "step error")
+		assert.Equal(t, SafeIndexDropObserving, sd.State, "expected observation to continue")
+
+		require.NoError(t, sd.step(ctx, indexes, start.Add(25*time.Hour)), "step error")
+		assert.Equal(t, SafeIndexDropDropped, sd.State, "expected index to be dropped")
+		assert.True(t, indexes.dropped, "expected DropOne to be called")
+		assert.True(t, sd.Done(), "expected drop to be done")
+	})
+	t.Run("aborts if index was used", func(t *testing.T) {
+		indexes := &fakeIndexes{exists: true, ops: 10}
+		sd := NewSafeIndexDrop("sku_1", time.Hour)
+		require.NoError(t, sd.step(ctx, indexes, start), "step error")
+
+		indexes.ops = 12
+		require.NoError(t, sd.step(ctx, indexes, start.Add(2*time.Hour)), "step error")
+		assert.Equal(t, SafeIndexDropAborted, sd.State, "expected drop to be aborted")
+		assert.False(t, indexes.hidden, "expected index to be visible again")
+		assert.False(t, indexes.dropped, "expected index not to be dropped")
+		assert.Equal(t, "index was used 2 times during the observation period", sd.Reason, "expected reason")
+	})
+	t.Run("aborts if index was unhidden", func(t *testing.T) {
+		indexes := &fakeIndexes{exists: true}
+		sd := NewSafeIndexDrop("sku_1", time.Hour)
+		require.NoError(t, sd.step(ctx, indexes, start), "step error")
+
+		indexes.hidden = false
+		require.NoError(t, sd.step(ctx, indexes, start.Add(2*time.Hour)), "step error")
+		assert.Equal(t, SafeIndexDropAborted, sd.State, "expected drop to be aborted")
+		assert.False(t, indexes.dropped, "expected index not to be dropped")
+	})
+	t.Run("manual abort", func(t *testing.T) {
+		indexes := &fakeIndexes{exists: true}
+		sd := NewSafeIndexDrop("sku_1", time.Hour)
+		require.NoError(t, sd.step(ctx, indexes, start), "step error")
+
+		require.NoError(t, sd.abort(ctx, indexes, "slow queries"), "abort error")
+		assert.Equal(t, SafeIndexDropAborted, sd.State, "expected drop to be aborted")
+		assert.False(t, indexes.hidden, "expected index to be visible again")
+
+		require.NoError(t, sd.step(ctx, indexes, start.Add(2*time.Hour)), "step error")
+		assert.False(t, indexes.dropped, "expected aborted drop not to drop the index")
+	})
+}
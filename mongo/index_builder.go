@@ -0,0 +1,493 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// ErrNoIndexKeys indicates that an IndexModelBuilder was built without any keys.
+var ErrNoIndexKeys = errors.New("index must have at least one key")
+
+// IndexModelBuilder builds an IndexModel one key and option at a time. Unlike an IndexModel
+// literal, combinations of keys and options that the server rejects, such as a unique wildcard
+// index or a sparse partial index, are reported when the model is built instead of when the index
+// is created.
+//
+// For more information about index types and properties, see
+// https://www.mongodb.com/docs/manual/indexes/.
+type IndexModelBuilder struct {
+	keys bson.D
+	err  error
+
+	name               *string
+	unique             bool
+	prepareUnique      bool
+	sparse             bool
+	hidden             bool
+	expireAfter        *time.Duration
+	partialFilter      interface{}
+	wildcardProjection interface{}
+	collation          *options.Collation
+}
+
+// NewIndexModelBuilder creates an empty IndexModelBuilder.
+func NewIndexModelBuilder() *IndexModelBuilder {
+	return &IndexModelBuilder{}
+}
+
+func (b *IndexModelBuilder) key(field string, value interface{}) *IndexModelBuilder {
+	if b.err != nil {
+		return b
+	}
+	if field == "" {
+		b.err = errors.New("index key field cannot be empty")
+		return b
+	}
+	for _, k := range b.keys {
+		if k.Key == field {
+			b.err = fmt.Errorf("index key %q specified more than once", field)
+			return b
+		}
+	}
+
+	b.keys = append(b.keys, bson.E{Key: field, Value: value})
+	return b
+}
+
+// Ascending adds an ascending key on the given field.
+func (b *IndexModelBuilder) Ascending(field string) *IndexModelBuilder {
+	return b.key(field, int32(1))
+}
+
+// Descending adds a descending key on the given field.
+func (b *IndexModelBuilder) Descending(field string) *IndexModelBuilder {
+	return b.key(field, int32(-1))
+}
+
+// Hashed adds a hashed key on the given field. An index can have at most one hashed key.
+func (b *IndexModelBuilder) Hashed(field string) *IndexModelBuilder {
+	return b.key(field, "hashed")
+}
+
+// Text adds a text key on the given field.
+func (b *IndexModelBuilder) Text(field string) *IndexModelBuilder {
+	return b.key(field, "text")
+}
+
+// Geo2DSphere adds a 2dsphere key on the given field.
+func (b *IndexModelBuilder) Geo2DSphere(field string) *IndexModelBuilder {
+	return b.key(field, "2dsphere")
+}
+
+// Name sets the name of the index. If it is not set, the name is generated from the keys.
+func (b *IndexModelBuilder) Name(name string) *IndexModelBuilder {
+	b.name = &name
+	return b
+}
+
+// Unique makes the index reject documents with duplicate keys.
+func (b *IndexModelBuilder) Unique() *IndexModelBuilder {
+	b.unique = true
+	return b
+}
+
+// PrepareUnique makes the index reject new duplicate keys without requiring that existing keys be
+// unique, so that it can be converted to a unique index with IndexView.ConvertToUnique. It cannot
+// be combined with Unique.
+func (b *IndexModelBuilder) PrepareUnique() *IndexModelBuilder {
+	b.prepareUnique = true
+	return b
+}
+
+// Sparse makes the index skip documents that do not have the indexed fields. It cannot be combined
+// with Partial.
+func (b *IndexModelBuilder) Sparse() *IndexModelBuilder {
+	b.sparse = true
+	return b
+}
+
+// Hidden creates the index hidden from the query planner. See IndexView.Hide.
+func (b *IndexModelBuilder) Hidden() *IndexModelBuilder {
+	b.hidden = true
+	return b
+}
+
+// ExpireAfter makes the index a TTL index that removes documents the given duration after the
+// date in the indexed field. The duration is rounded down to whole seconds. TTL indexes must have
+// exactly one key.
+func (b *IndexModelBuilder) ExpireAfter(d time.Duration) *IndexModelBuilder {
+	b.expireAfter = &d
+	return b
+}
+
+// Partial makes the index only include the documents that match the given filter, which can be
+// built with a PartialFilterBuilder. The filter is validated with ValidatePartialFilterExpression.
+func (b *IndexModelBuilder) Partial(filter interface{}) *IndexModelBuilder {
+	b.partialFilter = filter
+	return b
+}
+
+// Collation sets the collation of the index.
+func (b *IndexModelBuilder) Collation(collation *options.Collation) *IndexModelBuilder {
+	b.collation = collation
+	return b
+}
+
+// Build validates the keys and options and returns the IndexModel, or the first error
+// encountered.
+func (b *IndexModelBuilder) Build() (IndexModel, error) {
+	if b.err != nil {
+		return IndexModel{}, b.err
+	}
+	if err := b.validate(); err != nil {
+		return IndexModel{}, err
+	}
+
+	opts := options.Index()
+	if b.name != nil {
+		opts.SetName(*b.name)
+	}
+	if b.unique {
+		opts.SetUnique(true)
+	}
+	if b.prepareUnique {
+		opts.SetPrepareUnique(true)
+	}
+	if b.sparse {
+		opts.SetSparse(true)
+	}
+	if b.hidden {
+		opts.SetHidden(true)
+	}
+	if b.expireAfter != nil {
+		opts.SetExpireAfterSeconds(int32(*b.expireAfter / time.Second))
+	}
+	if b.partialFilter != nil {
+		filter := b.partialFilter
+		if pb, ok := filter.(*PartialFilterBuilder); ok {
+			d, err := pb.Build()
+			if err != nil {
+				return IndexModel{}, err
+			}
+			filter = d
+		}
+		opts.SetPartialFilterExpression(filter)
+	}
+	if b.wildcardProjection != nil {
+		opts.SetWildcardProjection(b.wildcardProjection)
+	}
+	if b.collation != nil {
+		opts.SetCollation(b.collation)
+	}
+
+	keys := make(bson.D, len(b.keys))
+	copy(keys, b.keys)
+	return IndexModel{Keys: keys, Options: opts}, nil
+}
+
+func (b *IndexModelBuilder) validate() error {
+	if len(b.keys) == 0 {
+		return ErrNoIndexKeys
+	}
+	if b.name != nil && *b.name == "" {
+		return errors.New("index name cannot be empty")
+	}
+
+	if err := validateWildcardIndex(b.keys, b.wildcardProjection); err != nil {
+		return err
+	}
+	if b.partialFilter != nil {
+		if err := ValidatePartialFilterExpression(b.partialFilter); err != nil {
+			return err
+		}
+	}
+
+	var wildcard bool
+	var hashed int
+	for _, k := range b.keys {
+		switch {
+		case isWildcardKey(k.Key):
+			wildcard = true
+		case k.Value == "hashed":
+			hashed++
+		}
+	}
+
+	switch {
+	case wildcard && (b.unique || b.prepareUnique):
+		return errors.New("wildcard indexes cannot be unique")
+	case wildcard && b.expireAfter != nil:
+		return errors.New("wildcard indexes cannot be TTL indexes")
+	case hashed > 1:
+		return errors.New("an index can have at most one hashed key")
+	case hashed > 0 && (b.unique || b.prepareUnique):
+		return errors.New("hashed indexes cannot be unique")
+	case b.unique && b.prepareUnique:
+		return errors.New("an index cannot be both unique and prepareUnique")
+	case b.sparse && b.partialFilter != nil:
+		return errors.New("an index cannot be both sparse and partial")
+	case b.expireAfter != nil && len(b.keys) != 1:
+		return errors.New("TTL indexes must have exactly one key")
+	case b.expireAfter != nil && *b.expireAfter < 0:
+		return errors.New("TTL index expiration cannot be negative")
+	}
+	return nil
+}
+
+// PrepareUnique runs the collMod command to make the index with the given name reject new
+// duplicate keys, the first step of converting an existing index to a unique index. Existing
+// duplicates are not checked until the index is converted with ConvertToUnique. This requires
+// MongoDB 6.0 or later.
+//
+// For more information about converting an index to unique, see
+// https://www.mongodb.com/docs/manual/reference/command/collMod/#convert-an-existing-index-to-a-unique-index.
+func (iv IndexView) PrepareUnique(ctx context.Context, name string) error {
+	cmd := bson.D{
+		{"collMod", iv.coll.name},
+		{"index", bson.D{{"name", name}, {"prepareUnique", true}}},
+	}
+	return iv.coll.db.RunCommand(ctx, cmd).Err()
+}
+
+// ConvertToUnique runs the collMod command to convert the index with the given name, which must
+// have been prepared with PrepareUnique, to a unique index. If dryRun is true, the index is not
+// converted, and the command only fails if the collection has duplicate keys. In both cases, the
+// error returned for duplicate keys is a ServerError whose message lists the conflicting
+// documents.
+func (iv IndexView) ConvertToUnique(ctx context.Context, name string, dryRun bool) error {
+	cmd := bson.D{
+		{"collMod", iv.coll.name},
+		{"index", bson.D{{"name", name}, {"unique", true}}},
+	}
+	if dryRun {
+		cmd = append(cmd, bson.E{"dryRun", true})
+	}
+	return iv.coll.db.RunCommand(ctx, cmd).Err()
+}
+
+// IndexDiff is the difference between the indexes of a collection and a desired set of indexes,
+// as returned by IndexView.CompareWithServer.
+type IndexDiff struct {
+	// Missing are the desired indexes that do not exist on the collection.
+	Missing []IndexModel
+
+	// Extra are the names of the indexes that exist on the collection but are not desired. The
+	// _id index is never included.
+	Extra []string
+
+	// Changed are desired indexes that exist on the collection with different keys or options.
+	Changed []IndexChange
+}
+
+// IndexChange describes a desired index that exists on the collection with different keys or
+// options.
+type IndexChange struct {
+	// Name is the name of the index.
+	Name string
+
+	// Desired is the desired index.
+	Desired IndexModel
+
+	// Actual is the index specification returned by the listIndexes command.
+	Actual bson.Raw
+
+	// Fields are the names of the fields that differ, such as "key" or "unique", in the order
+	// the desired index defines them.
+	Fields []string
+}
+
+// Empty reports whether the collection has exactly the desired indexes.
+func (d IndexDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// CompareWithServer compares the indexes of the collection with the desired indexes, which are
+// matched to existing indexes by name, generating the names of models without one as CreateMany
+// does. Only the keys and the options set in the desired models are compared, except that boolean
+// properties such as unique, sparse, and hidden and the TTL, partial filter, and wildcard
+// projection of an existing index are reported if they are not desired. Numbers compare equal
+// regardless of their BSON type, and collations only compare the fields set in the desired
+// collation, as the server fills in the others.
+//
+// Indexes with the same name and different keys cannot be modified in place; they must be dropped
+// and recreated. Changes to hidden, prepareUnique, unique, and expireAfterSeconds can be applied
+// with the collMod command.
+func (iv IndexView) CompareWithServer(ctx context.Context, desired []IndexModel) (IndexDiff, error) {
+	cursor, err := iv.List(ctx)
+	if err != nil {
+		return IndexDiff{}, err
+	}
+	var actual []bson.Raw
+	if err := cursor.All(ctx, &actual); err != nil {
+		return IndexDiff{}, err
+	}
+	return iv.diffIndexes(desired, actual)
+}
+
+// comparedIndexOptions are the options of an existing index that are reported as changed if they
+// are not set in the desired index.
+var comparedIndexOptions = []string{
+	"unique", "prepareUnique", "sparse", "hidden", "expireAfterSeconds", "partialFilterExpression", "wildcardProjection",
+}
+
+func (iv IndexView) diffIndexes(desired []IndexModel, actual []bson.Raw) (IndexDiff, error) {
+	byName := make(map[string]bson.Raw, len(actual))
+	for _, spec := range actual {
+		name, ok := spec.Lookup("name").StringValueOK()
+		if !ok || name == "_id_" {
+			continue
+		}
+		byName[name] = spec
+	}
+
+	var diff IndexDiff
+	seen := make(map[string]bool, len(desired))
+	for _, model := range desired {
+		if model.Keys == nil {
+			return IndexDiff{}, fmt.Errorf("index model keys cannot be nil")
+		}
+		if isUnorderedMap(model.Keys) {
+			return IndexDiff{}, ErrMapForOrderedArgument{"keys"}
+		}
+		keys, err := marshal(model.Keys, iv.coll.bsonOpts, iv.coll.registry)
+		if err != nil {
+			return IndexDiff{}, err
+		}
+		name, err := getOrGenerateIndexName(keys, model)
+		if err != nil {
+			return IndexDiff{}, err
+		}
+		seen[name] = true
+
+		spec, ok := byName[name]
+		if !ok {
+			diff.Missing = append(diff.Missing, model)
+			continue
+		}
+
+		var optsDoc bsoncore.Document
+		if model.Options != nil {
+			elems, err := iv.createOptionsDoc(model.Options)
+			if err != nil {
+				return IndexDiff{}, err
+			}
+			optsDoc = bsoncore.BuildDocumentFromElements(nil, elems)
+		}
+		if fields := diffIndexSpec(keys, optsDoc, spec); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, IndexChange{Name: name, Desired: model, Actual: spec, Fields: fields})
+		}
+	}
+
+	for _, spec := range actual {
+		name, ok := spec.Lookup("name").StringValueOK()
+		if ok && name != "_id_" && !seen[name] {
+			diff.Extra = append(diff.Extra, name)
+		}
+	}
+	return diff, nil
+}
+
+// diffIndexSpec returns the names of the fields of the desired keys and options that differ from
+// the existing index specification.
+func diffIndexSpec(keys, optsDoc bsoncore.Document, spec bson.Raw) []string {
+	var fields []string
+	if !rawValuesEqual(bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: keys}, spec.Lookup("key")) {
+		fields = append(fields, "key")
+	}
+
+	set := map[string]bool{}
+	elems, _ := optsDoc.Elements()
+	for _, elem := range elems {
+		key := elem.Key()
+		set[key] = true
+		if key == "name" || key == "v" {
+			continue
+		}
+
+		want := bson.RawValue{Type: bson.Type(elem.Value().Type), Value: elem.Value().Data}
+		got := spec.Lookup(key)
+		switch {
+		case key == "collation":
+			if !collationSubset(want, got) {
+				fields = append(fields, key)
+			}
+		case key == "partialFilterExpression":
+			if !partialFiltersMatch(want, got) {
+				fields = append(fields, key)
+			}
+		case want.Type == bson.TypeBoolean && !want.Boolean() && got.Type == 0:
+			// A false boolean property is the same as an unset one.
+		case !rawValuesEqual(want, got):
+			fields = append(fields, key)
+		}
+	}
+
+	for _, key := range comparedIndexOptions {
+		if set[key] {
+			continue
+		}
+		got := spec.Lookup(key)
+		if got.Type == 0 || (got.Type == bson.TypeBoolean && !got.Boolean()) {
+			continue
+		}
+		fields = append(fields, key)
+	}
+	return fields
+}
+
+func rawValuesEqual(a, b bson.RawValue) bool {
+	if a.Type == 0 || b.Type == 0 {
+		return a.Type == b.Type
+	}
+	return compareValues(a, b) == 0
+}
+
+// partialFiltersMatch reports whether the desired and existing partial filter expressions are
+// equivalent. Expressions that cannot be normalized only match if they are equal.
+func partialFiltersMatch(want, got bson.RawValue) bool {
+	wantDoc, ok := want.DocumentOK()
+	if !ok {
+		return false
+	}
+	gotDoc, ok := got.DocumentOK()
+	if !ok {
+		return false
+	}
+	equivalent, err := PartialFilterExpressionsEquivalent(wantDoc, gotDoc)
+	if err != nil {
+		return rawValuesEqual(want, got)
+	}
+	return equivalent
+}
+
+// collationSubset reports whether every field of the desired collation has the same value in the
+// existing collation.
+func collationSubset(want, got bson.RawValue) bool {
+	wantDoc, ok := want.DocumentOK()
+	if !ok {
+		return false
+	}
+	gotDoc, ok := got.DocumentOK()
+	if !ok {
+		return false
+	}
+	elems, _ := wantDoc.Elements()
+	for _, elem := range elems {
+		if !rawValuesEqual(elem.Value(), gotDoc.Lookup(elem.Key())) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func TestIndexModelBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		iv := setupColl("index_builder").Indexes()
+		model, err := NewIndexModelBuilder().
+			Ascending("email").
+			Descending("createdAt").
+			Unique().
+			Partial(bson.D{{"deleted", false}}).
+			Build()
+		require.NoError(t, err, "Build error")
+		assert.Equal(t, bson.D{{"email", int32(1)}, {"createdAt", int32(-1)}}, model.Keys, "keys mismatch")
+
+		elems, err := iv.createOptionsDoc(model.Options)
+		require.NoError(t, err, "createOptionsDoc error")
+		want := bson.D{{"unique", true}, {"partialFilterExpression", bson.D{{"deleted", false}}}}
+		wantDoc, err := bson.Marshal(want)
+		require.NoError(t, err, "Marshal error")
+		assert.Equal(t, bson.Raw(wantDoc), bson.Raw(bsoncore.BuildDocumentFromElements(nil, elems)), "options mismatch")
+	})
+
+	t.Run("wildcard projection", func(t *testing.T) {
+		t.Parallel()
+
+		model, err := NewIndexModelBuilder().
+			Wildcard("").
+			WildcardProjection(bson.D{{"secret", 0}}).
+			Build()
+		require.NoError(t, err, "Build error")
+		assert.Equal(t, bson.D{{"$**", int32(1)}}, model.Keys, "keys mismatch")
+
+		model, err = NewIndexModelBuilder().Wildcard("attributes").Build()
+		require.NoError(t, err, "Build error")
+		assert.Equal(t, bson.D{{"attributes.$**", int32(1)}}, model.Keys, "keys mismatch")
+	})
+
+	testCases := []struct {
+		name    string
+		builder *IndexModelBuilder
+	}{
+		{"no keys", NewIndexModelBuilder().Unique()},
+		{"duplicate key", NewIndexModelBuilder().Ascending("a").Descending("a")},
+		{"projection without wildcard", NewIndexModelBuilder().Ascending("a").WildcardProjection(bson.D{{"b", 1}})},
+		{"projection with path wildcard", NewIndexModelBuilder().Wildcard("a").WildcardProjection(bson.D{{"b", 1}})},
+		{"unique wildcard", NewIndexModelBuilder().Wildcard("").Unique()},
+		{"TTL wildcard", NewIndexModelBuilder().Wildcard("a").ExpireAfter(time.Hour)},
+		{"two hashed keys", NewIndexModelBuilder().Hashed("a").Hashed("b")},
+		{"unique hashed", NewIndexModelBuilder().Hashed("a").Unique()},
+		{"unique and prepareUnique", NewIndexModelBuilder().Ascending("a").Unique().PrepareUnique()},
+		{"sparse and partial", NewIndexModelBuilder().Ascending("a").Sparse().Partial(bson.D{{"a", 1}})},
+		{"compound TTL", NewIndexModelBuilder().Ascending("a").Ascending("b").ExpireAfter(time.Hour)},
+		{"empty name", NewIndexModelBuilder().Ascending("a").Name("")},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.builder.Build()
+			assert.Error(t, err, "expected Build error")
+		})
+	}
+}
+
+func TestIndexViewDiffIndexes(t *testing.T) {
+	t.Parallel()
+
+	iv := setupColl("index_diff").Indexes()
+	spec := func(d bson.D) bson.Raw {
+		b, err := bson.Marshal(d)
+		require.NoError(t, err, "Marshal error")
+		return b
+	}
+	actual := []bson.Raw{
+		spec(bson.D{{"v", 2}, {"key", bson.D{{"_id", 1}}}, {"name", "_id_"}}),
+		spec(bson.D{{"v", 2}, {"key", bson.D{{"email", 1}}}, {"name", "email_1"}, {"unique", true}}),
+		spec(bson.D{{"v", 2}, {"key", bson.D{{"createdAt", 1}}}, {"name", "ttl"}, {"expireAfterSeconds", int64(3600)}}),
+		spec(bson.D{{"v", 2}, {"key", bson.D{{"status", 1}}}, {"name", "status_1"}, {"sparse", true}}),
+		spec(bson.D{
+			{"v", 2}, {"key", bson.D{{"title", 1}}}, {"name", "title_1"},
+			{"collation", bson.D{{"locale", "en"}, {"strength", 2}, {"caseLevel", false}}},
+		}),
+		spec(bson.D{{"v", 2}, {"key", bson.D{{"old", 1}}}, {"name", "old_1"}}),
+	}
+
+	desired := []IndexModel{
+		{Keys: bson.D{{"email", 1}}, Options: options.Index().SetUnique(true).SetHidden(false)},
+		{Keys: bson.D{{"createdAt", -1}}, Options: options.Index().SetName("ttl").SetExpireAfterSeconds(3600)},
+		{Keys: bson.D{{"status", 1}}},
+		{Keys: bson.D{{"title", 1}}, Options: options.Index().SetCollation(&options.Collation{Locale: "en", Strength: 2})},
+		{Keys: bson.D{{"sku", 1}}, Options: options.Index().SetUnique(true)},
+	}
+
+	diff, err := iv.diffIndexes(desired, actual)
+	require.NoError(t, err, "diffIndexes error")
+	assert.False(t, diff.Empty(), "expected differences")
+
+	require.Len(t, diff.Missing, 1, "expected one missing index")
+	assert.Equal(t, desired[4].Keys, diff.Missing[0].Keys, "missing index mismatch")
+	assert.Equal(t, []string{"old_1"}, diff.Extra, "extra indexes mismatch")
+
+	require.Len(t, diff.Changed, 2, "expected two changed indexes")
+	assert.Equal(t, "ttl", diff.Changed[0].Name, "changed index mismatch")
+	assert.Equal(t, []string{"key"}, diff.Changed[0].Fields, "changed fields mismatch")
+	assert.Equal(t, "status_1", diff.Changed[1].Name, "changed index mismatch")
+	assert.Equal(t, []string{"sparse"}, diff.Changed[1].Fields, "changed fields mismatch")
+
+	diff, err = iv.diffIndexes(desired[:4], actual[:5])
+	require.NoError(t, err, "diffIndexes error")
+	assert.Len(t, diff.Missing, 0, "expected no missing indexes")
+	assert.Len(t, diff.Extra, 0, "expected no extra indexes")
+}
@@ -0,0 +1,138 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// IndexHostUsage is the usage of an index on one host, as reported by the $indexStats aggregation
+// stage.
+type IndexHostUsage struct {
+	// Host is the address of the mongod that reported the usage.
+	Host string
+
+	// Shard is the name of the shard of the host. It is only set for sharded collections.
+	Shard string
+
+	// Ops is the number of operations that used the index on the host since Since.
+	Ops int64
+
+	// Since is the time from which the host has counted the operations, which is when the mongod
+	// started or the index was created, whichever is later.
+	Since time.Time
+}
+
+// IndexUsageStats is the usage of an index across all the hosts that reported it.
+type IndexUsageStats struct {
+	// Name is the name of the index.
+	Name string
+
+	// Key is the key specification of the index.
+	Key bson.Raw
+
+	// Ops is the total number of operations that used the index on all hosts.
+	Ops int64
+
+	// Since is the latest of the Since times of the hosts: every host has counted the operations
+	// that used the index since this time.
+	Since time.Time
+
+	// Building is true if the index is still being built on any host.
+	Building bool
+
+	// Hosts is the usage of the index on each host, ordered by shard and host.
+	Hosts []IndexHostUsage
+}
+
+// indexStatsEntry is a document returned by the $indexStats aggregation stage.
+type indexStatsEntry struct {
+	Name     string   `bson:"name"`
+	Key      bson.Raw `bson:"key"`
+	Host     string   `bson:"host"`
+	Shard    string   `bson:"shard"`
+	Building bool     `bson:"building"`
+	Accesses struct {
+		Ops   int64     `bson:"ops"`
+		Since time.Time `bson:"since"`
+	} `bson:"accesses"`
+}
+
+// UsageStats runs the $indexStats aggregation stage and returns the usage of each index of the
+// collection, aggregated across the hosts that report it, ordered by index name. For sharded
+// collections, each shard reports the usage on one of its members; for replica sets, the usage is
+// reported by the member selected by the read preference of the collection. Usage statistics are
+// reset when a mongod restarts.
+//
+// For more information about the stage, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/indexStats/.
+func (iv IndexView) UsageStats(ctx context.Context) ([]IndexUsageStats, error) {
+	cursor, err := iv.coll.Aggregate(ctx, bson.A{bson.D{{"$indexStats", bson.D{}}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []indexStatsEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return aggregateIndexStats(entries), nil
+}
+
+func aggregateIndexStats(entries []indexStatsEntry) []IndexUsageStats {
+	byName := make(map[string]*IndexUsageStats)
+	for _, e := range entries {
+		stats, ok := byName[e.Name]
+		if !ok {
+			stats = &IndexUsageStats{Name: e.Name, Key: e.Key}
+			byName[e.Name] = stats
+		}
+		stats.Ops += e.Accesses.Ops
+		stats.Building = stats.Building || e.Building
+		if e.Accesses.Since.After(stats.Since) {
+			stats.Since = e.Accesses.Since
+		}
+		stats.Hosts = append(stats.Hosts, IndexHostUsage{
+			Host:  e.Host,
+			Shard: e.Shard,
+			Ops:   e.Accesses.Ops,
+			Since: e.Accesses.Since,
+		})
+	}
+
+	result := make([]IndexUsageStats, 0, len(byName))
+	for _, stats := range byName {
+		sort.Slice(stats.Hosts, func(i, j int) bool {
+			if stats.Hosts[i].Shard != stats.Hosts[j].Shard {
+				return stats.Hosts[i].Shard < stats.Hosts[j].Shard
+			}
+			return stats.Hosts[i].Host < stats.Hosts[j].Host
+		})
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// UnusedIndexes returns the indexes in stats that no operation has used since the given time on
+// any host. An index is only reported if every host has counted its usage since that time, so
+// indexes whose usage statistics were reset after it, such as by a restart, are not reported. The
+// _id index, which cannot be dropped, and indexes that are still being built are never reported.
+func UnusedIndexes(stats []IndexUsageStats, since time.Time) []IndexUsageStats {
+	var unused []IndexUsageStats
+	for _, s := range stats {
+		if s.Name == "_id_" || s.Building || s.Ops > 0 || s.Since.After(since) {
+			continue
+		}
+		unused = append(unused, s)
+	}
+	return unused
+}
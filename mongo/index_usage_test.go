@@ -0,0 +1,59 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestIndexUsageStats(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	entry := func(name, shard string, ops int64, since time.Time) indexStatsEntry {
+		doc, err := bson.Marshal(bson.D{
+			{"name", name},
+			{"key", bson.D{{name, 1}}},
+			{"host", shard + "-a:27017"},
+			{"shard", shard},
+			{"accesses", bson.D{{"ops", ops}, {"since", since}}},
+		})
+		require.NoError(t, err, "Marshal error: %v", err)
+
+		var e indexStatsEntry
+		err = bson.Unmarshal(doc, &e)
+		require.NoError(t, err, "Unmarshal error: %v", err)
+		return e
+	}
+
+	stats := aggregateIndexStats([]indexStatsEntry{
+		entry("_id_", "s1", 0, day(1)),
+		entry("sku", "s2", 5, day(1)),
+		entry("sku", "s1", 7, day(3)),
+		entry("unused", "s1", 0, day(1)),
+		entry("unused", "s2", 0, day(2)),
+		entry("restarted", "s1", 0, day(1)),
+		entry("restarted", "s2", 0, day(20)),
+	})
+	require.Len(t, stats, 4, "expected stats for each index")
+
+	sku := stats[2]
+	assert.Equal(t, "sku", sku.Name, "expected stats ordered by name")
+	assert.Equal(t, int64(12), sku.Ops, "expected ops summed across shards")
+	assert.Equal(t, day(3), sku.Since, "expected the latest since time")
+	require.Len(t, sku.Hosts, 2, "expected usage of each host")
+	assert.Equal(t, "s1", sku.Hosts[0].Shard, "expected hosts ordered by shard")
+
+	unused := UnusedIndexes(stats, day(10))
+	require.Len(t, unused, 1, "expected one unused index")
+	assert.Equal(t, "unused", unused[0].Name, "expected unused index")
+}
@@ -132,7 +132,7 @@ func (iv IndexView) List(ctx context.Context, opts ...options.Lister[options.Lis
 		closeImplicitSession(sess)
 		return nil, replaceErrors(err)
 	}
-	cursor, err := newCursorWithSession(bc, iv.coll.bsonOpts, iv.coll.registry, sess)
+	cursor, err := newCursorWithSession(ctx, bc, iv.coll.bsonOpts, iv.coll.registry, sess, iv.coll.client.cursorMemory, iv.coll.client.cursorLeaks)
 	return cursor, replaceErrors(err)
 }
 
@@ -156,7 +156,7 @@ func (iv IndexView) ListSpecifications(
 
 	specs := make([]IndexSpecification, len(resp))
 	for idx, spec := range resp {
-		specs[idx] = IndexSpecification(spec)
+		specs[idx] = spec.specification()
 		specs[idx].Namespace = namespace
 	}
 
@@ -379,6 +379,9 @@ func (iv IndexView) createOptionsDoc(opts options.Lister[options.IndexOptions])
 	if args.Hidden != nil {
 		optsDoc = bsoncore.AppendBooleanElement(optsDoc, "hidden", *args.Hidden)
 	}
+	if args.PrepareUnique != nil {
+		optsDoc = bsoncore.AppendBooleanElement(optsDoc, "prepareUnique", *args.PrepareUnique)
+	}
 
 	return optsDoc, nil
 }
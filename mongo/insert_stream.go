@@ -0,0 +1,263 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// These are the default batch limits of InsertStream, which match the maxWriteBatchSize and
+// maxMessageSizeBytes limits of current servers.
+const (
+	defaultInsertStreamBatchDocuments = 100000
+	defaultInsertStreamBatchBytes     = 48000000
+)
+
+// InsertStreamResult is the result of an InsertStream operation.
+type InsertStreamResult struct {
+	// InsertedCount is the number of documents inserted. For unacknowledged writes, it is the
+	// number of documents sent to the server.
+	InsertedCount int64
+
+	// Batches is the number of batches that were inserted or failed.
+	Batches int
+
+	// FailedBatches is the number of batches that returned an error.
+	FailedBatches int
+}
+
+// InsertStream inserts the documents received from the documents channel until the channel is
+// closed, without holding more than a few batches of documents in memory. Documents are marshaled
+// as they are received and grouped into batches limited by count and by total size; each batch is
+// inserted with InsertMany, which splits it into insert commands that fit the limits of the
+// server. Documents without an _id field are given a generated ObjectID, as with InsertMany.
+//
+// If the stream is ordered, which is the default, the batches are inserted one at a time and the
+// stream stops at the first failed batch. Otherwise, up to Parallelism batches are inserted
+// concurrently, the stream continues after failed batches, and the documents of different batches
+// can be inserted in any order. Either way, InsertStream returns the first error encountered along
+// with the result so far; the errors of all batches are passed to the BatchCallback. If
+// InsertStream returns before the channel is closed, because of an error or because ctx is done,
+// the remaining documents are not read.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.InsertStreamOptions documentation).
+func (coll *Collection) InsertStream(
+	ctx context.Context,
+	documents <-chan interface{},
+	opts ...options.Lister[options.InsertStreamOptions],
+) (*InsertStreamResult, error) {
+	args, err := mongoutil.NewOptions[options.InsertStreamOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	is := newInsertStream(args)
+	imOpts := options.InsertMany().SetOrdered(is.ordered)
+	if args.BypassDocumentValidation != nil {
+		imOpts.SetBypassDocumentValidation(*args.BypassDocumentValidation)
+	}
+	if args.Comment != nil {
+		imOpts.SetComment(args.Comment)
+	}
+	is.marshal = func(doc interface{}) (bson.Raw, error) {
+		bsoncoreDoc, err := marshal(doc, coll.bsonOpts, coll.registry)
+		if err != nil {
+			return nil, err
+		}
+		bsoncoreDoc, _, err = ensureID(bsoncoreDoc, bson.NilObjectID, coll.bsonOpts, coll.registry)
+		return bson.Raw(bsoncoreDoc), err
+	}
+	is.insert = func(ctx context.Context, docs []interface{}) (*InsertManyResult, error) {
+		return coll.InsertMany(ctx, docs, imOpts)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return is.run(ctx, documents)
+}
+
+// insertStream batches and inserts the documents of an InsertStream operation.
+type insertStream struct {
+	ordered      bool
+	parallelism  int
+	maxDocuments int
+	maxBytes     int
+	callback     func(options.InsertStreamBatch)
+
+	marshal func(doc interface{}) (bson.Raw, error)
+	insert  func(ctx context.Context, docs []interface{}) (*InsertManyResult, error)
+
+	mu     sync.Mutex
+	result InsertStreamResult
+	err    error
+}
+
+// insertStreamBatch is a batch of marshaled documents waiting to be inserted.
+type insertStreamBatch struct {
+	number int
+	docs   []interface{}
+	bytes  int
+}
+
+func newInsertStream(args *options.InsertStreamOptions) *insertStream {
+	is := &insertStream{
+		ordered:      true,
+		parallelism:  1,
+		maxDocuments: defaultInsertStreamBatchDocuments,
+		maxBytes:     defaultInsertStreamBatchBytes,
+		callback:     args.BatchCallback,
+	}
+	if args.Ordered != nil {
+		is.ordered = *args.Ordered
+	}
+	if args.Parallelism != nil && !is.ordered {
+		is.parallelism = *args.Parallelism
+	}
+	if args.MaxBatchDocuments != nil {
+		is.maxDocuments = *args.MaxBatchDocuments
+	}
+	if args.MaxBatchBytes != nil {
+		is.maxBytes = *args.MaxBatchBytes
+	}
+	return is
+}
+
+func (is *insertStream) run(parent context.Context, documents <-chan interface{}) (*InsertStreamResult, error) {
+	// ctx is canceled to stop an ordered stream after a failed batch.
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	batches := make(chan insertStreamBatch)
+	var wg sync.WaitGroup
+	for i := 0; i < is.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				if ctx.Err() != nil {
+					continue
+				}
+				if !is.insertBatch(ctx, b) && is.ordered {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	readErr := is.read(ctx, documents, batches)
+	close(batches)
+	wg.Wait()
+
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	result := is.result
+	switch {
+	case is.err != nil:
+		return &result, is.err
+	case readErr != nil:
+		return &result, readErr
+	case parent.Err() != nil:
+		return &result, parent.Err()
+	}
+	return &result, nil
+}
+
+// read marshals the documents received from documents and sends them to batches until documents
+// is closed or ctx is done.
+func (is *insertStream) read(ctx context.Context, documents <-chan interface{}, batches chan<- insertStreamBatch) error {
+	batch := insertStreamBatch{number: 1}
+	send := func() bool {
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			return false
+		}
+		batch = insertStreamBatch{number: batch.number + 1}
+		return true
+	}
+
+	for n := 0; ; n++ {
+		var doc interface{}
+		var ok bool
+		select {
+		case doc, ok = <-documents:
+		case <-ctx.Done():
+			return nil
+		}
+		if !ok {
+			if len(batch.docs) > 0 {
+				send()
+			}
+			return nil
+		}
+
+		raw, err := is.marshal(doc)
+		if err != nil {
+			return fmt.Errorf("error marshaling document %d: %w", n, err)
+		}
+		if len(batch.docs) > 0 && (len(batch.docs) >= is.maxDocuments || batch.bytes+len(raw) > is.maxBytes) {
+			if !send() {
+				return nil
+			}
+		}
+		batch.docs = append(batch.docs, raw)
+		batch.bytes += len(raw)
+	}
+}
+
+// insertBatch inserts a batch, records its result, and reports whether it succeeded.
+func (is *insertStream) insertBatch(ctx context.Context, b insertStreamBatch) bool {
+	res, err := is.insert(ctx, b.docs)
+
+	inserted := len(b.docs)
+	if err != nil {
+		inserted = 0
+		var bwe BulkWriteException
+		if errors.As(err, &bwe) && len(bwe.WriteErrors) > 0 {
+			if is.ordered {
+				inserted = bwe.WriteErrors[0].Index
+			} else {
+				inserted = len(b.docs) - len(bwe.WriteErrors)
+			}
+		}
+	}
+
+	is.mu.Lock()
+	is.result.Batches++
+	is.result.InsertedCount += int64(inserted)
+	if err != nil {
+		is.result.FailedBatches++
+		if is.err == nil {
+			is.err = fmt.Errorf("error inserting batch %d: %w", b.number, err)
+		}
+	}
+	is.mu.Unlock()
+
+	if is.callback != nil {
+		batchResult := options.InsertStreamBatch{
+			Batch:     b.number,
+			Documents: len(b.docs),
+			Bytes:     b.bytes,
+			Err:       err,
+		}
+		if res != nil {
+			batchResult.InsertedIDs = res.InsertedIDs
+		}
+		is.callback(batchResult)
+	}
+	return err == nil
+}
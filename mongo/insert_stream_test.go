@@ -0,0 +1,148 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestInsertStream(t *testing.T) {
+	docs := func(n int) <-chan interface{} {
+		ch := make(chan interface{}, n)
+		for i := 0; i < n; i++ {
+			ch <- bson.D{{"_id", int32(i)}}
+		}
+		close(ch)
+		return ch
+	}
+
+	// newStream returns an insertStream that records the size of each batch and fails the batches
+	// for which fail returns a non-nil error.
+	newStream := func(
+		t *testing.T,
+		opts *options.InsertStreamOptionsBuilder,
+		fail func(batch []interface{}) error,
+	) (*insertStream, *[]int) {
+		t.Helper()
+
+		args, err := mongoutil.NewOptions[options.InsertStreamOptions](opts)
+		require.NoError(t, err, "error constructing options: %v", err)
+
+		var mu sync.Mutex
+		var sizes []int
+		is := newInsertStream(args)
+		is.marshal = func(doc interface{}) (bson.Raw, error) {
+			b, err := bson.Marshal(doc)
+			return b, err
+		}
+		is.insert = func(_ context.Context, batch []interface{}) (*InsertManyResult, error) {
+			mu.Lock()
+			sizes = append(sizes, len(batch))
+			mu.Unlock()
+
+			if fail != nil {
+				if err := fail(batch); err != nil {
+					return &InsertManyResult{}, err
+				}
+			}
+			return &InsertManyResult{InsertedIDs: make([]interface{}, len(batch))}, nil
+		}
+		return is, &sizes
+	}
+
+	t.Run("batches by document count", func(t *testing.T) {
+		is, sizes := newStream(t, options.InsertStream().SetMaxBatchDocuments(4), nil)
+		res, err := is.run(context.Background(), docs(10))
+		require.NoError(t, err, "run error: %v", err)
+
+		assert.Equal(t, []int{4, 4, 2}, *sizes, "expected batch sizes")
+		assert.Equal(t, InsertStreamResult{InsertedCount: 10, Batches: 3}, *res, "expected result")
+	})
+	t.Run("batches by size", func(t *testing.T) {
+		// Each document is 14 bytes.
+		is, sizes := newStream(t, options.InsertStream().SetMaxBatchBytes(30), nil)
+		_, err := is.run(context.Background(), docs(5))
+		require.NoError(t, err, "run error: %v", err)
+
+		assert.Equal(t, []int{2, 2, 1}, *sizes, "expected batch sizes")
+	})
+	t.Run("ordered stops after failed batch", func(t *testing.T) {
+		failure := BulkWriteException{WriteErrors: []BulkWriteError{{WriteError: WriteError{Index: 1, Code: 11000}}}}
+		var batches []options.InsertStreamBatch
+		opts := options.InsertStream().
+			SetMaxBatchDocuments(3).
+			SetBatchCallback(func(b options.InsertStreamBatch) { batches = append(batches, b) })
+		is, sizes := newStream(t, opts, func(batch []interface{}) error {
+			if len(batches) == 1 {
+				return failure
+			}
+			return nil
+		})
+
+		res, err := is.run(context.Background(), docs(12))
+		var bwe BulkWriteException
+		require.True(t, errors.As(err, &bwe), "expected BulkWriteException, got %v", err)
+
+		assert.Equal(t, []int{3, 3}, *sizes, "expected no batches after the failed batch")
+		assert.Equal(t, InsertStreamResult{InsertedCount: 4, Batches: 2, FailedBatches: 1}, *res, "expected result")
+		require.Len(t, batches, 2, "expected callback for each batch")
+		assert.Equal(t, 2, batches[1].Batch, "expected batch number")
+		assert.Equal(t, error(failure), batches[1].Err, "expected batch error")
+	})
+	t.Run("unordered continues after failed batches", func(t *testing.T) {
+		var mu sync.Mutex
+		calls := 0
+		opts := options.InsertStream().SetOrdered(false).SetParallelism(4).SetMaxBatchDocuments(2)
+		is, sizes := newStream(t, opts, func(batch []interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls++
+			if calls%2 == 0 {
+				return BulkWriteException{WriteErrors: []BulkWriteError{{WriteError: WriteError{Index: 0, Code: 11000}}}}
+			}
+			return nil
+		})
+
+		res, err := is.run(context.Background(), docs(20))
+		assert.Error(t, err, "expected error from failed batches")
+		assert.Len(t, *sizes, 10, "expected every batch to be inserted")
+		assert.Equal(t, InsertStreamResult{InsertedCount: 15, Batches: 10, FailedBatches: 5}, *res, "expected result")
+	})
+	t.Run("marshal error", func(t *testing.T) {
+		is, sizes := newStream(t, options.InsertStream(), nil)
+		ch := make(chan interface{}, 2)
+		ch <- bson.D{{"x", 1}}
+		ch <- 42
+		close(ch)
+
+		_, err := is.run(context.Background(), ch)
+		assert.ErrorContains(t, err, "error marshaling document 1", "expected marshal error")
+		assert.Len(t, *sizes, 0, "expected no batches to be inserted")
+	})
+	t.Run("context canceled", func(t *testing.T) {
+		is, _ := newStream(t, options.InsertStream(), nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := is.run(ctx, make(chan interface{}))
+		assert.ErrorIs(t, err, context.Canceled, "expected context error")
+	})
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := mongoutil.NewOptions[options.InsertStreamOptions](options.InsertStream().SetParallelism(0))
+		assert.Error(t, err, "expected error for zero parallelism")
+	})
+}
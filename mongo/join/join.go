@@ -0,0 +1,513 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package join joins the results of two queries on the client, for cases where a $lookup stage
+// cannot be used, such as collections of different clusters or a sharded foreign collection on
+// servers that do not support it.
+//
+// A HashJoin reads the documents of the foreign cursor into a hash table keyed by the foreign
+// field, then adds the matching foreign documents to each document of the local cursor, like a
+// $lookup stage with localField and foreignField:
+//
+//	local, err := orders.Find(ctx, bson.D{{"status", "open"}})
+//	...
+//	foreign, err := otherCluster.Database("crm").Collection("customers").Find(ctx, bson.D{})
+//	...
+//	cur, err := join.New("customerId", "_id", "customer").Run(ctx, local, foreign)
+//	...
+//	defer cur.Close(ctx)
+//	for cur.Next(ctx) {
+//		fmt.Println(cur.Current.Lookup("customer"))
+//	}
+//
+// If the foreign documents exceed the memory limit of the join, they are spilled to files in
+// partitions by the hash of their keys, and the local documents are partitioned the same way, so
+// that each partition can be joined in memory.
+package join
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+const (
+	// DefaultMaxMemory is the default memory limit of a HashJoin, which is the memory limit of
+	// the $lookup stage of the server.
+	DefaultMaxMemory = 100 * 1024 * 1024
+
+	// DefaultPartitions is the default number of partitions of a HashJoin that spills to disk.
+	DefaultPartitions = 16
+)
+
+// HashJoin joins the documents of a local cursor with the documents of a foreign cursor whose
+// foreign field equals the local field of the local document.
+//
+// Keys are compared like the server compares values for equality: numbers of different types are
+// equal if their values are, and a missing field equals null. Unlike $lookup, arrays are compared
+// as whole values rather than by their elements.
+type HashJoin struct {
+	localField   string
+	foreignField string
+	as           string
+	maxMemory    int64
+	spillDir     string
+	partitions   int
+	inner        bool
+}
+
+// New creates a HashJoin that adds the foreign documents whose foreignField equals the localField
+// of a local document to the array field as of the local document. The fields can be dotted paths.
+func New(localField, foreignField, as string) *HashJoin {
+	return &HashJoin{
+		localField:   localField,
+		foreignField: foreignField,
+		as:           as,
+		maxMemory:    DefaultMaxMemory,
+		partitions:   DefaultPartitions,
+	}
+}
+
+// SetMaxMemory sets the number of bytes of foreign documents that the join holds in memory. If
+// the foreign documents exceed it, the join spills them to disk. A value of 0 or less means there
+// is no limit. The default value is DefaultMaxMemory.
+func (j *HashJoin) SetMaxMemory(bytes int64) *HashJoin {
+	j.maxMemory = bytes
+	return j
+}
+
+// SetSpillDir sets the directory in which the join creates its spill files. The default value is
+// os.TempDir().
+func (j *HashJoin) SetSpillDir(dir string) *HashJoin {
+	j.spillDir = dir
+	return j
+}
+
+// SetPartitions sets the number of partitions of the documents spilled to disk. Each partition
+// of the foreign documents must fit in memory to respect the memory limit, so the number should be
+// at least their total size divided by the memory limit. The default value is DefaultPartitions.
+func (j *HashJoin) SetPartitions(n int) *HashJoin {
+	j.partitions = n
+	return j
+}
+
+// SetInner sets whether the join omits the local documents that match no foreign document, like
+// an inner join. By default, they are returned with an empty array, like a left outer join.
+func (j *HashJoin) SetInner(inner bool) *HashJoin {
+	j.inner = inner
+	return j
+}
+
+// Run reads the foreign cursor and returns a Cursor over the joined local documents. Run closes
+// both cursors once they are read. If the join fits in memory, the local documents are read as the
+// returned Cursor is iterated and are returned in their order. Otherwise, Run reads both cursors
+// to disk and the documents are returned in the order of their partitions.
+func (j *HashJoin) Run(ctx context.Context, local, foreign *mongo.Cursor) (*Cursor, error) {
+	if j.as == "" {
+		_ = local.Close(ctx)
+		_ = foreign.Close(ctx)
+		return nil, errors.New("join requires an output field")
+	}
+	if j.partitions < 1 {
+		_ = local.Close(ctx)
+		_ = foreign.Close(ctx)
+		return nil, fmt.Errorf("join partitions must be at least 1, got %d", j.partitions)
+	}
+
+	c := &Cursor{join: j, local: local, table: make(map[string][]bson.Raw)}
+	if err := c.build(ctx, foreign); err != nil {
+		_ = c.Close(ctx)
+		return nil, err
+	}
+	if c.spill != nil {
+		if err := c.spillLocal(ctx); err != nil {
+			_ = c.Close(ctx)
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Cursor iterates over the documents of a HashJoin.
+type Cursor struct {
+	// Current contains the current joined document. It is only valid until the next call to Next.
+	Current bson.Raw
+
+	join  *HashJoin
+	local *mongo.Cursor
+	table map[string][]bson.Raw
+	err   error
+
+	spill     *spillFiles // the spill files, if the join spilled
+	partition int         // the partition being joined, if the join spilled
+	probe     *bufio.Reader
+	probeFile *os.File
+}
+
+// Next gets the next joined document. It returns true if there were no errors and the cursor
+// contains the next document, and false otherwise. Err should be checked after Next returns false.
+func (c *Cursor) Next(ctx context.Context) bool {
+	if c.err != nil {
+		return false
+	}
+	if c.spill == nil {
+		for c.local.Next(ctx) {
+			if c.joinDocument(c.local.Current) {
+				return true
+			}
+		}
+		c.err = c.local.Err()
+		return false
+	}
+
+	for c.err == nil {
+		if c.probe == nil {
+			if c.partition >= c.join.partitions {
+				return false
+			}
+			c.err = c.loadPartition(c.partition)
+			c.partition++
+			continue
+		}
+
+		doc, err := readDocument(c.probe)
+		if err == io.EOF {
+			c.err = c.probeFile.Close()
+			c.probe, c.probeFile = nil, nil
+			continue
+		}
+		if err != nil {
+			c.err = err
+			return false
+		}
+		if c.joinDocument(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode will unmarshal the current document into val.
+func (c *Cursor) Decode(val interface{}) error {
+	return bson.Unmarshal(c.Current, val)
+}
+
+// Err returns the last error seen by the Cursor, or nil if no error has occurred.
+func (c *Cursor) Err() error { return c.err }
+
+// Close closes the local cursor and removes the spill files of the join.
+func (c *Cursor) Close(ctx context.Context) error {
+	err := c.local.Close(ctx)
+	if c.probeFile != nil {
+		_ = c.probeFile.Close()
+		c.probe, c.probeFile = nil, nil
+	}
+	if c.spill != nil {
+		if rmErr := c.spill.remove(); err == nil {
+			err = rmErr
+		}
+		c.spill = nil
+	}
+	c.table = nil
+	return err
+}
+
+// build reads the foreign cursor into the hash table, spilling it to disk if it exceeds the
+// memory limit.
+func (c *Cursor) build(ctx context.Context, foreign *mongo.Cursor) error {
+	defer func() { _ = foreign.Close(ctx) }()
+
+	var size int64
+	for foreign.Next(ctx) {
+		doc := append(bson.Raw(nil), foreign.Current...)
+		key := lookupKey(doc, c.join.foreignField)
+		if c.spill != nil {
+			if err := c.spill.writeBuild(key, doc); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c.table[key] = append(c.table[key], doc)
+		size += int64(len(doc) + len(key))
+		if c.join.maxMemory > 0 && size > c.join.maxMemory {
+			if err := c.spillTable(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := foreign.Err(); err != nil {
+		return err
+	}
+	if c.spill != nil {
+		return c.spill.flushBuild()
+	}
+	return nil
+}
+
+// spillTable creates the spill files and moves the hash table to them.
+func (c *Cursor) spillTable() error {
+	spill, err := newSpillFiles(c.join.spillDir, c.join.partitions)
+	if err != nil {
+		return err
+	}
+	c.spill = spill
+	for key, docs := range c.table {
+		for _, doc := range docs {
+			if err := spill.writeBuild(key, doc); err != nil {
+				return err
+			}
+		}
+	}
+	c.table = make(map[string][]bson.Raw)
+	return nil
+}
+
+// spillLocal reads the local cursor into the probe partitions of the spill files.
+func (c *Cursor) spillLocal(ctx context.Context) error {
+	defer func() { _ = c.local.Close(ctx) }()
+
+	for c.local.Next(ctx) {
+		doc := c.local.Current
+		if err := c.spill.writeProbe(lookupKey(doc, c.join.localField), doc); err != nil {
+			return err
+		}
+	}
+	if err := c.local.Err(); err != nil {
+		return err
+	}
+	return c.spill.flushProbe()
+}
+
+// loadPartition reads the foreign documents of partition p into the hash table and opens its
+// local documents.
+func (c *Cursor) loadPartition(p int) error {
+	c.table = make(map[string][]bson.Raw)
+	f, err := os.Open(c.spill.buildPath(p))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	for {
+		doc, err := readDocument(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		key := lookupKey(doc, c.join.foreignField)
+		c.table[key] = append(c.table[key], doc)
+	}
+
+	if c.probeFile, err = os.Open(c.spill.probePath(p)); err != nil {
+		return err
+	}
+	c.probe = bufio.NewReader(c.probeFile)
+	return nil
+}
+
+// joinDocument sets Current to the local document doc with its matching foreign documents, and
+// reports whether it should be returned.
+func (c *Cursor) joinDocument(doc bson.Raw) bool {
+	matches := c.table[lookupKey(doc, c.join.localField)]
+	if c.join.inner && len(matches) == 0 {
+		return false
+	}
+
+	elems, err := bsoncore.Document(doc).Elements()
+	if err != nil {
+		c.err = err
+		return false
+	}
+	idx, out := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		if elem.Key() != c.join.as {
+			out = append(out, elem...)
+		}
+	}
+	aidx, out := bsoncore.AppendArrayElementStart(out, c.join.as)
+	for i, match := range matches {
+		out = bsoncore.AppendDocumentElement(out, fmt.Sprint(i), match)
+	}
+	out, _ = bsoncore.AppendArrayEnd(out, aidx)
+	out, _ = bsoncore.AppendDocumentEnd(out, idx)
+	c.Current = out
+	return true
+}
+
+// lookupKey returns the hash key of the value of the field at path in doc.
+func lookupKey(doc bson.Raw, path string) string {
+	val, err := doc.LookupErr(strings.Split(path, ".")...)
+	if err != nil {
+		return "null"
+	}
+
+	switch val.Type {
+	case bson.TypeNull, bson.TypeUndefined:
+		return "null"
+	case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble:
+		// Numbers of different types with the same value are equal.
+		var b [9]byte
+		if i, ok := val.AsInt64OK(); ok && float64(i) == numberValue(val) {
+			b[0] = 'i'
+			binary.LittleEndian.PutUint64(b[1:], uint64(i))
+		} else {
+			b[0] = 'd'
+			binary.LittleEndian.PutUint64(b[1:], math.Float64bits(numberValue(val)))
+		}
+		return string(b[:])
+	}
+	return string(append([]byte{byte(val.Type)}, val.Value...))
+}
+
+// numberValue returns the value of the number val as a float64.
+func numberValue(val bson.RawValue) float64 {
+	switch val.Type {
+	case bson.TypeInt32:
+		return float64(val.Int32())
+	case bson.TypeInt64:
+		return float64(val.Int64())
+	default:
+		return val.Double()
+	}
+}
+
+// spillFiles are the partition files of a join that spilled to disk.
+type spillFiles struct {
+	dir   string
+	build []*partitionWriter
+	probe []*partitionWriter
+}
+
+type partitionWriter struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func newSpillFiles(parent string, partitions int) (*spillFiles, error) {
+	dir, err := os.MkdirTemp(parent, "mongo-join-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating join spill directory: %w", err)
+	}
+	return &spillFiles{
+		dir:   dir,
+		build: make([]*partitionWriter, partitions),
+		probe: make([]*partitionWriter, partitions),
+	}, nil
+}
+
+func (s *spillFiles) buildPath(p int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("foreign-%d", p))
+}
+
+func (s *spillFiles) probePath(p int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("local-%d", p))
+}
+
+func (s *spillFiles) writeBuild(key string, doc bson.Raw) error {
+	return s.write(s.build, s.buildPath, key, doc)
+}
+
+func (s *spillFiles) writeProbe(key string, doc bson.Raw) error {
+	return s.write(s.probe, s.probePath, key, doc)
+}
+
+func (s *spillFiles) write(writers []*partitionWriter, path func(int) string, key string, doc bson.Raw) error {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	p := int(h.Sum64() % uint64(len(writers)))
+
+	if writers[p] == nil {
+		f, err := os.Create(path(p))
+		if err != nil {
+			return fmt.Errorf("error creating join spill file: %w", err)
+		}
+		writers[p] = &partitionWriter{f: f, w: bufio.NewWriter(f)}
+	}
+	_, err := writers[p].w.Write(doc)
+	return err
+}
+
+func (s *spillFiles) flushBuild() error {
+	return s.flush(s.build, s.buildPath)
+}
+
+func (s *spillFiles) flushProbe() error {
+	return s.flush(s.probe, s.probePath)
+}
+
+// flush flushes and closes the writers, and creates the files of the empty partitions.
+func (s *spillFiles) flush(writers []*partitionWriter, path func(int) string) error {
+	for p, pw := range writers {
+		if pw == nil {
+			f, err := os.Create(path(p))
+			if err != nil {
+				return fmt.Errorf("error creating join spill file: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := pw.w.Flush(); err != nil {
+			_ = pw.f.Close()
+			return err
+		}
+		if err := pw.f.Close(); err != nil {
+			return err
+		}
+		writers[p] = nil
+	}
+	return nil
+}
+
+// remove closes the open writers and removes the spill directory.
+func (s *spillFiles) remove() error {
+	for _, writers := range [][]*partitionWriter{s.build, s.probe} {
+		for _, pw := range writers {
+			if pw != nil {
+				_ = pw.f.Close()
+			}
+		}
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// readDocument reads a BSON document from r.
+func readDocument(r *bufio.Reader) (bson.Raw, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	n := int32(binary.LittleEndian.Uint32(length[:]))
+	if n < 5 {
+		return nil, fmt.Errorf("invalid document length %d in join spill file", n)
+	}
+	doc := make(bson.Raw, n)
+	copy(doc, length[:])
+	if _, err := io.ReadFull(r, doc[4:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return doc, nil
+}
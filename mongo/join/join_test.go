@@ -0,0 +1,161 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package join
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type joinedOrder struct {
+	ID       int32 `bson:"_id"`
+	Customer []struct {
+		ID   interface{} `bson:"_id"`
+		Name string      `bson:"name"`
+	} `bson:"customer"`
+}
+
+func newCursor(t *testing.T, docs ...interface{}) *mongo.Cursor {
+	t.Helper()
+
+	cur, err := mongo.NewCursorFromDocuments(docs, nil, nil)
+	require.NoError(t, err, "NewCursorFromDocuments error")
+	return cur
+}
+
+func orders(t *testing.T) *mongo.Cursor {
+	return newCursor(t,
+		bson.D{{"_id", int32(1)}, {"customerId", int32(10)}},
+		bson.D{{"_id", int32(2)}, {"customerId", int64(20)}},
+		bson.D{{"_id", int32(3)}, {"customerId", int32(30)}},
+		bson.D{{"_id", int32(4)}, {"customer", "replaced"}},
+		bson.D{{"_id", int32(5)}, {"customerId", int32(10)}},
+	)
+}
+
+func customers(t *testing.T) *mongo.Cursor {
+	return newCursor(t,
+		bson.D{{"_id", int32(10)}, {"name", "a"}},
+		bson.D{{"_id", 20.0}, {"name", "b"}},
+		bson.D{{"_id", nil}, {"name", "nobody"}},
+		bson.D{{"_id", "10"}, {"name", "string"}},
+	)
+}
+
+// readAll returns the joined documents of cur, sorted by _id, and closes it.
+func readAll(t *testing.T, cur *Cursor) []joinedOrder {
+	t.Helper()
+
+	var got []joinedOrder
+	for cur.Next(context.Background()) {
+		var o joinedOrder
+		require.NoError(t, cur.Decode(&o), "Decode error")
+		got = append(got, o)
+	}
+	require.NoError(t, cur.Err(), "cursor error")
+	require.NoError(t, cur.Close(context.Background()), "Close error")
+
+	sort.Slice(got, func(i, j int) bool { return got[i].ID < got[j].ID })
+	return got
+}
+
+func names(o joinedOrder) []string {
+	var n []string
+	for _, c := range o.Customer {
+		n = append(n, c.Name)
+	}
+	return n
+}
+
+func TestHashJoin(t *testing.T) {
+	t.Parallel()
+
+	check := func(t *testing.T, got []joinedOrder) {
+		t.Helper()
+
+		require.Len(t, got, 5, "expected every order")
+		assert.Equal(t, []string{"a"}, names(got[0]), "order 1 mismatch")
+		assert.Equal(t, []string{"b"}, names(got[1]), "expected numbers of different types to match")
+		assert.Nil(t, names(got[2]), "expected no match for order 3")
+		assert.Equal(t, []string{"nobody"}, names(got[3]), "expected a missing field to match null")
+		assert.Equal(t, []string{"a"}, names(got[4]), "order 5 mismatch")
+	}
+
+	t.Run("in memory", func(t *testing.T) {
+		t.Parallel()
+
+		cur, err := New("customerId", "_id", "customer").Run(context.Background(), orders(t), customers(t))
+		require.NoError(t, err, "Run error")
+		assert.Nil(t, cur.spill, "expected the join to fit in memory")
+
+		var ids []int32
+		var got []joinedOrder
+		for cur.Next(context.Background()) {
+			var o joinedOrder
+			require.NoError(t, cur.Decode(&o), "Decode error")
+			ids = append(ids, o.ID)
+			got = append(got, o)
+		}
+		require.NoError(t, cur.Err(), "cursor error")
+		require.NoError(t, cur.Close(context.Background()), "Close error")
+		assert.Equal(t, []int32{1, 2, 3, 4, 5}, ids, "expected the order of the local documents")
+		check(t, got)
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		cur, err := New("customerId", "_id", "customer").
+			SetMaxMemory(1).
+			SetPartitions(3).
+			SetSpillDir(dir).
+			Run(context.Background(), orders(t), customers(t))
+		require.NoError(t, err, "Run error")
+		require.NotNil(t, cur.spill, "expected the join to spill")
+
+		check(t, readAll(t, cur))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err, "ReadDir error")
+		assert.Len(t, entries, 0, "expected the spill files to be removed")
+	})
+
+	t.Run("inner", func(t *testing.T) {
+		t.Parallel()
+
+		for _, maxMemory := range []int64{0, 1} {
+			cur, err := New("customerId", "_id", "customer").
+				SetInner(true).
+				SetMaxMemory(maxMemory).
+				SetSpillDir(t.TempDir()).
+				Run(context.Background(), orders(t), customers(t))
+			require.NoError(t, err, "Run error")
+
+			var ids []int32
+			for _, o := range readAll(t, cur) {
+				ids = append(ids, o.ID)
+			}
+			assert.Equal(t, []int32{1, 2, 4, 5}, ids, "expected only matched orders with max memory %d", maxMemory)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New("customerId", "_id", "").Run(context.Background(), orders(t), customers(t))
+		assert.ErrorContains(t, err, "output field", "expected an error without an output field")
+		_, err = New("customerId", "_id", "c").SetPartitions(0).Run(context.Background(), orders(t), customers(t))
+		assert.ErrorContains(t, err, "partitions", "expected an error for no partitions")
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// keyAltNameCrypt is a driver.Crypt that automatically encrypts commands with a Crypt whose schemas
+// use the data key of the key alternate name resolved from the context of the command. All other
+// operations, including decryption, use the base Crypt.
+type keyAltNameCrypt struct {
+	driver.Crypt
+
+	resolve  func(ctx context.Context) (string, error)
+	newCrypt func(ctx context.Context, keyAltName string) (driver.Crypt, error)
+
+	mu     sync.Mutex
+	crypts map[string]driver.Crypt
+}
+
+var _ driver.Crypt = (*keyAltNameCrypt)(nil)
+
+// newKeyAltNameCrypt returns a keyAltNameCrypt that creates the Crypt for each resolved key
+// alternate name with the auto-encryption options of the Client.
+func (c *Client) newKeyAltNameCrypt(
+	base driver.Crypt,
+	resolve func(ctx context.Context) (string, error),
+	opts options.Lister[options.AutoEncryptionOptions],
+) *keyAltNameCrypt {
+	return &keyAltNameCrypt{
+		Crypt:   base,
+		resolve: resolve,
+		newCrypt: func(ctx context.Context, keyAltName string) (driver.Crypt, error) {
+			keyID, err := c.keyIDForAltName(ctx, keyAltName)
+			if err != nil {
+				return nil, err
+			}
+			mc, err := c.newMongoCrypt(opts, &keyID)
+			if err != nil {
+				return nil, err
+			}
+			return c.newCryptFLE(mc, opts), nil
+		},
+		crypts: make(map[string]driver.Crypt),
+	}
+}
+
+// keyIDForAltName returns the _id of the data key with the given key alternate name.
+func (c *Client) keyIDForAltName(ctx context.Context, keyAltName string) (bson.Binary, error) {
+	var key struct {
+		ID bson.Binary `bson:"_id"`
+	}
+	err := c.keyVaultCollFLE.FindOne(ctx, bson.D{{"keyAltNames", keyAltName}}).Decode(&key)
+	if errors.Is(err, ErrNoDocuments) {
+		return bson.Binary{}, fmt.Errorf("no data key with key alternate name %q", keyAltName)
+	}
+	if err != nil {
+		return bson.Binary{}, fmt.Errorf("error finding data key with key alternate name %q: %w", keyAltName, err)
+	}
+	return key.ID, nil
+}
+
+// Encrypt encrypts cmd with the Crypt of the key alternate name resolved from ctx.
+func (kc *keyAltNameCrypt) Encrypt(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+	crypt, err := kc.cryptFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Encrypt(ctx, db, cmd)
+}
+
+func (kc *keyAltNameCrypt) cryptFor(ctx context.Context) (driver.Crypt, error) {
+	keyAltName, err := kc.resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving key alternate name: %w", err)
+	}
+	if keyAltName == "" {
+		return kc.Crypt, nil
+	}
+
+	// Crypts are created while holding the lock so that concurrent operations for a new key
+	// alternate name do not create several of them.
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if crypt, ok := kc.crypts[keyAltName]; ok {
+		return crypt, nil
+	}
+	crypt, err := kc.newCrypt(ctx, keyAltName)
+	if err != nil {
+		return nil, err
+	}
+	kc.crypts[keyAltName] = crypt
+	return crypt, nil
+}
+
+// Close closes the Crypts of all resolved key alternate names and the base Crypt.
+func (kc *keyAltNameCrypt) Close() {
+	kc.mu.Lock()
+	for _, crypt := range kc.crypts {
+		crypt.Close()
+	}
+	kc.crypts = nil
+	kc.mu.Unlock()
+
+	kc.Crypt.Close()
+}
+
+// replaceKeyAltNamePlaceholder returns a copy of doc in which every "keyId" field whose value is
+// options.KeyAltNamePlaceholder, in doc or in any nested document or array, is set to keyID.
+func replaceKeyAltNamePlaceholder(doc bsoncore.Document, keyID bsoncore.Value) bsoncore.Document {
+	elems, err := doc.Elements()
+	if err != nil {
+		return doc
+	}
+
+	idx, dst := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		dst = appendReplacedKeyID(dst, elem.Key(), elem.Value(), keyID)
+	}
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+	return dst
+}
+
+func appendReplacedKeyID(dst []byte, key string, val, keyID bsoncore.Value) []byte {
+	switch val.Type {
+	case bsoncore.TypeString:
+		if key == "keyId" && val.StringValue() == options.KeyAltNamePlaceholder {
+			return bsoncore.AppendValueElement(dst, key, keyID)
+		}
+	case bsoncore.TypeEmbeddedDocument:
+		return bsoncore.AppendDocumentElement(dst, key, replaceKeyAltNamePlaceholder(val.Document(), keyID))
+	case bsoncore.TypeArray:
+		values, err := val.Array().Values()
+		if err != nil {
+			break
+		}
+		idx, arr := bsoncore.AppendArrayElementStart(dst, key)
+		for i, v := range values {
+			arr = appendReplacedKeyID(arr, strconv.Itoa(i), v, keyID)
+		}
+		arr, _ = bsoncore.AppendArrayEnd(arr, idx)
+		return arr
+	}
+	return bsoncore.AppendValueElement(dst, key, val)
+}
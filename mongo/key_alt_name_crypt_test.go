@@ -0,0 +1,128 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// namedCrypt is a driver.Crypt that records the commands it encrypts.
+type namedCrypt struct {
+	driver.Crypt
+
+	name      string
+	encrypted int
+	closed    bool
+}
+
+func (nc *namedCrypt) Encrypt(context.Context, string, bsoncore.Document) (bsoncore.Document, error) {
+	nc.encrypted++
+	return bsoncore.NewDocumentBuilder().AppendString("crypt", nc.name).Build(), nil
+}
+
+func (nc *namedCrypt) Close() {
+	nc.closed = true
+}
+
+type tenantKey struct{}
+
+func TestKeyAltNameCrypt(t *testing.T) {
+	base := &namedCrypt{name: "base"}
+	created := map[string]*namedCrypt{}
+	kc := &keyAltNameCrypt{
+		Crypt: base,
+		resolve: func(ctx context.Context) (string, error) {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			if tenant == "invalid" {
+				return "", errors.New("unknown tenant")
+			}
+			return tenant, nil
+		},
+		newCrypt: func(_ context.Context, keyAltName string) (driver.Crypt, error) {
+			if keyAltName == "missing" {
+				return nil, errors.New("no data key")
+			}
+			nc := &namedCrypt{name: keyAltName}
+			created[keyAltName] = nc
+			return nc, nil
+		},
+		crypts: map[string]driver.Crypt{},
+	}
+
+	encrypt := func(tenant string) (string, error) {
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		doc, err := kc.Encrypt(ctx, "db", bsoncore.NewDocumentBuilder().Build())
+		if err != nil {
+			return "", err
+		}
+		return doc.Lookup("crypt").StringValue(), nil
+	}
+
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-a", ""} {
+		got, err := encrypt(tenant)
+		require.NoError(t, err, "Encrypt error: %v", err)
+
+		want := tenant
+		if tenant == "" {
+			want = "base"
+		}
+		assert.Equal(t, want, got, "expected command to be encrypted by the tenant Crypt")
+	}
+	assert.Len(t, created, 2, "expected one Crypt per tenant")
+	assert.Equal(t, 2, created["tenant-a"].encrypted, "expected tenant Crypt to be reused")
+
+	_, err := encrypt("invalid")
+	assert.ErrorContains(t, err, "error resolving key alternate name", "expected resolver error")
+	_, err = encrypt("missing")
+	assert.ErrorContains(t, err, "no data key", "expected Crypt creation error")
+
+	kc.Close()
+	assert.True(t, base.closed, "expected base Crypt to be closed")
+	assert.True(t, created["tenant-b"].closed, "expected tenant Crypt to be closed")
+}
+
+func TestReplaceKeyAltNamePlaceholder(t *testing.T) {
+	keyID := bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte("0123456789abcdef")}
+	keyIDValue := bsoncore.Value{Type: bsoncore.TypeBinary, Data: bsoncore.AppendBinary(nil, keyID.Subtype, keyID.Data)}
+
+	marshal := func(v interface{}) bsoncore.Document {
+		b, err := bson.Marshal(v)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+
+	doc := marshal(bson.D{
+		{"fields", bson.A{
+			bson.D{{"path", "ssn"}, {"keyId", options.KeyAltNamePlaceholder}},
+			bson.D{{"path", "name"}, {"keyId", "/other"}},
+		}},
+		{"properties", bson.D{
+			{"ssn", bson.D{{"encrypt", bson.D{{"keyId", options.KeyAltNamePlaceholder}}}}},
+		}},
+	})
+	want := marshal(bson.D{
+		{"fields", bson.A{
+			bson.D{{"path", "ssn"}, {"keyId", keyID}},
+			bson.D{{"path", "name"}, {"keyId", "/other"}},
+		}},
+		{"properties", bson.D{
+			{"ssn", bson.D{{"encrypt", bson.D{{"keyId", keyID}}}}},
+		}},
+	})
+
+	got := replaceKeyAltNamePlaceholder(doc, keyIDValue)
+	assert.Equal(t, bson.Raw(want), bson.Raw(got), "expected placeholders to be replaced")
+}
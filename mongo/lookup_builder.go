@@ -0,0 +1,123 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/lookuputil"
+)
+
+// LookupBuilder builds a correlated $lookup aggregation stage, which joins the documents of a
+// collection with the results of a sub-pipeline that references the variables of the stage, e.g.
+//
+//	stage, err := mongo.NewLookupBuilder("orders", "orders").
+//		Let("customerId", "$_id").
+//		Pipeline(bson.D{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$customerId", "$$customerId"}}}}}}}).
+//		Build()
+//
+// Build checks that the sub-pipeline references the variables correctly, because the server
+// silently evaluates the mistakes to missing values and returns empty joins instead of failing. It
+// returns an error if the sub-pipeline
+//   - references a variable that is not defined, such as "$$customerID" for "$$customerId";
+//   - does not reference a variable, including when it references a field with the name of the
+//     variable, such as "$customerId" for "$$customerId";
+//   - references a variable in a $match stage outside of $expr, where variables are not evaluated.
+//
+// Variables defined in the sub-pipeline, such as those of $let, $map, $filter, $reduce, and nested
+// $lookup stages, and system variables such as "$$ROOT" can be referenced without being defined
+// with Let.
+//
+// For more information about $lookup, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/lookup/.
+type LookupBuilder struct {
+	from         string
+	as           string
+	localField   string
+	foreignField string
+	let          bson.D
+	pipeline     []interface{}
+	err          error
+}
+
+// NewLookupBuilder creates a LookupBuilder that joins the documents of the collection from into the
+// array field as.
+func NewLookupBuilder(from, as string) *LookupBuilder {
+	return &LookupBuilder{from: from, as: as}
+}
+
+// Let defines the variable name with the value of the expression expr, which is evaluated for each
+// input document, e.g. "$_id". The sub-pipeline references it as "$$" + name.
+func (b *LookupBuilder) Let(name string, expr interface{}) *LookupBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := lookuputil.ValidateVariableName(name); err != nil {
+		b.err = err
+		return b
+	}
+	for _, e := range b.let {
+		if e.Key == name {
+			b.err = fmt.Errorf("$lookup variable %q defined more than once", name)
+			return b
+		}
+	}
+
+	b.let = append(b.let, bson.E{Key: name, Value: expr})
+	return b
+}
+
+// Pipeline appends stages to the sub-pipeline run on the joined collection.
+func (b *LookupBuilder) Pipeline(stages ...interface{}) *LookupBuilder {
+	b.pipeline = append(b.pipeline, stages...)
+	return b
+}
+
+// On sets the local and foreign fields whose values must be equal for documents to be joined, in
+// addition to the sub-pipeline. Combining them with a sub-pipeline requires MongoDB 5.0 or later.
+func (b *LookupBuilder) On(localField, foreignField string) *LookupBuilder {
+	b.localField = localField
+	b.foreignField = foreignField
+	return b
+}
+
+// Build validates the stage and returns it, or the first error encountered.
+func (b *LookupBuilder) Build() (bson.D, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	switch {
+	case b.from == "":
+		return nil, errors.New("$lookup requires a collection to join")
+	case b.as == "":
+		return nil, errors.New("$lookup requires an output field")
+	case (b.localField == "") != (b.foreignField == ""):
+		return nil, errors.New("$lookup requires both a local and a foreign field")
+	case len(b.let) > 0 && len(b.pipeline) == 0:
+		return nil, errors.New("$lookup variables require a pipeline that references them")
+	case len(b.pipeline) == 0 && b.localField == "":
+		return nil, errors.New("$lookup requires a pipeline or local and foreign fields")
+	}
+	if err := lookuputil.ValidateReferences(b.let, b.pipeline); err != nil {
+		return nil, err
+	}
+
+	spec := bson.D{{"from", b.from}}
+	if b.localField != "" {
+		spec = append(spec, bson.E{Key: "localField", Value: b.localField}, bson.E{Key: "foreignField", Value: b.foreignField})
+	}
+	if len(b.let) > 0 {
+		spec = append(spec, bson.E{Key: "let", Value: append(bson.D(nil), b.let...)})
+	}
+	if len(b.pipeline) > 0 {
+		spec = append(spec, bson.E{Key: "pipeline", Value: append(bson.A(nil), b.pipeline...)})
+	}
+	spec = append(spec, bson.E{Key: "as", Value: b.as})
+	return bson.D{{"$lookup", spec}}, nil
+}
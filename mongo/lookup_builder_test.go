@@ -0,0 +1,164 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestLookupBuilder(t *testing.T) {
+	t.Parallel()
+
+	exprMatch := func(expr bson.D) bson.D {
+		return bson.D{{"$match", bson.D{{"$expr", expr}}}}
+	}
+
+	t.Run("correlated", func(t *testing.T) {
+		t.Parallel()
+
+		stage, err := NewLookupBuilder("orders", "orders").
+			Let("customerId", "$_id").
+			Let("minTotal", 10).
+			Pipeline(
+				exprMatch(bson.D{{"$and", bson.A{
+					bson.D{{"$eq", bson.A{"$customerId", "$$customerId"}}},
+					bson.D{{"$gte", bson.A{"$total", "$$minTotal"}}},
+				}}}),
+				bson.D{{"$project", bson.D{
+					{"items", bson.D{{"$map", bson.D{{"input", "$items"}, {"as", "item"}, {"in", "$$item.sku"}}}}},
+					{"root", "$$ROOT"},
+				}}},
+			).
+			Build()
+		require.NoError(t, err, "Build error")
+
+		spec := stage[0].Value.(bson.D)
+		assert.Equal(t, "$lookup", stage[0].Key, "stage name mismatch")
+		assert.Equal(t, bson.E{Key: "from", Value: "orders"}, spec[0], "from mismatch")
+		assert.Equal(t, bson.E{Key: "let", Value: bson.D{{"customerId", "$_id"}, {"minTotal", 10}}}, spec[1],
+			"let mismatch")
+		assert.Equal(t, "pipeline", spec[2].Key, "pipeline mismatch")
+		assert.Equal(t, bson.E{Key: "as", Value: "orders"}, spec[3], "as mismatch")
+	})
+
+	t.Run("local and foreign fields", func(t *testing.T) {
+		t.Parallel()
+
+		stage, err := NewLookupBuilder("orders", "orders").On("_id", "customerId").Build()
+		require.NoError(t, err, "Build error")
+		assert.Equal(t, bson.D{{"$lookup", bson.D{
+			{"from", "orders"},
+			{"localField", "_id"},
+			{"foreignField", "customerId"},
+			{"as", "orders"},
+		}}}, stage, "stage mismatch")
+	})
+
+	testCases := []struct {
+		name    string
+		builder *LookupBuilder
+		wantErr string
+	}{
+		{
+			name: "field instead of variable",
+			builder: NewLookupBuilder("orders", "orders").
+				Let("customerId", "$_id").
+				Pipeline(exprMatch(bson.D{{"$eq", bson.A{"$customerId", "$customerId"}}})),
+			wantErr: `references field "$customerId" instead of variable "$$customerId"`,
+		},
+		{
+			name: "undefined variable",
+			builder: NewLookupBuilder("orders", "orders").
+				Let("customerId", "$_id").
+				Pipeline(exprMatch(bson.D{{"$eq", bson.A{"$customerId", "$$customerID"}}})),
+			wantErr: `undefined variable "$$customerID" at pipeline.0.$match.$expr.$eq.1`,
+		},
+		{
+			name: "unused variable",
+			builder: NewLookupBuilder("orders", "orders").
+				Let("customerId", "$_id").
+				Pipeline(bson.D{{"$limit", 1}}),
+			wantErr: `variable "customerId" is not referenced`,
+		},
+		{
+			name: "variable in match without expr",
+			builder: NewLookupBuilder("orders", "orders").
+				Let("customerId", "$_id").
+				Pipeline(bson.D{{"$match", bson.D{{"customerId", "$$customerId"}}}}),
+			wantErr: `in $match outside of $expr`,
+		},
+		{
+			name:    "invalid variable name",
+			builder: NewLookupBuilder("orders", "orders").Let("CustomerId", "$_id"),
+			wantErr: `invalid $lookup variable name "CustomerId"`,
+		},
+		{
+			name:    "duplicate variable",
+			builder: NewLookupBuilder("orders", "orders").Let("id", "$_id").Let("id", "$x"),
+			wantErr: `variable "id" defined more than once`,
+		},
+		{
+			name:    "variables without pipeline",
+			builder: NewLookupBuilder("orders", "orders").Let("id", "$_id"),
+			wantErr: "require a pipeline",
+		},
+		{
+			name:    "no join condition",
+			builder: NewLookupBuilder("orders", "orders"),
+			wantErr: "requires a pipeline or local and foreign fields",
+		},
+		{
+			name:    "no output field",
+			builder: NewLookupBuilder("orders", "").On("_id", "customerId"),
+			wantErr: "requires an output field",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.builder.Build()
+			require.Error(t, err, "expected a Build error")
+			assert.Contains(t, err.Error(), tc.wantErr, "error mismatch")
+		})
+	}
+
+	t.Run("pipeline variables", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewLookupBuilder("orders", "orders").
+			Let("customerId", "$_id").
+			Pipeline(
+				exprMatch(bson.D{{"$eq", bson.A{"$customerId", "$$customerId"}}}),
+				bson.D{{"$project", bson.D{
+					{"total", bson.D{{"$let", bson.D{
+						{"vars", bson.D{{"rate", 2}}},
+						{"in", bson.D{{"$multiply", bson.A{"$total", "$$rate"}}}},
+					}}}},
+					{"sum", bson.D{{"$reduce", bson.D{
+						{"input", "$items"},
+						{"initialValue", 0},
+						{"in", bson.D{{"$add", bson.A{"$$value", "$$this.qty"}}}},
+					}}}},
+				}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", "items"},
+					{"let", bson.D{{"orderId", "$_id"}}},
+					{"pipeline", bson.A{exprMatch(bson.D{{"$eq", bson.A{"$orderId", "$$orderId"}}})}},
+					{"as", "items"},
+				}}},
+			).
+			Build()
+		assert.NoError(t, err, "expected the variables defined in the pipeline to be allowed")
+	})
+}
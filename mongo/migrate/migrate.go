@@ -0,0 +1,536 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package migrate runs ordered, versioned schema migrations against a database.
+//
+// Each Migration has a unique version. A Migrator records the applied versions in a migrations
+// collection, which also holds a lock that prevents concurrent migrators, such as the instances
+// of an application starting at the same time, from running the same migrations:
+//
+//	m, err := migrate.New(client.Database("app"),
+//		migrate.Migration{
+//			Version:     1,
+//			Description: "add email index",
+//			Up: func(ctx context.Context, db *mongo.Database) error {
+//				_, err := db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+//					Keys:    bson.D{{"email", 1}},
+//					Options: options.Index().SetUnique(true),
+//				})
+//				return err
+//			},
+//			Down: func(ctx context.Context, db *mongo.Database) error {
+//				return db.Collection("users").Indexes().DropOne(ctx, "email_1")
+//			},
+//		},
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	result, err := m.Up(ctx)
+//
+// Migrations that only write documents can set Transactional to run in a transaction together
+// with the record of the migration, so that a failure leaves no partial changes. Transactions
+// require a replica set or sharded cluster, and most DDL operations, such as creating indexes on
+// existing collections, cannot run in them. A failed migration that is not transactional leaves
+// its record marked dirty, and the Migrator refuses to run until the record is fixed with Force.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+// DefaultCollection is the default name of the migrations collection.
+const DefaultCollection = "schema_migrations"
+
+// DefaultLockTTL is the default time after which the lock of a migrator that stopped without
+// releasing it expires.
+const DefaultLockTTL = 5 * time.Minute
+
+// lockID is the _id of the lock document in the migrations collection.
+const lockID = "lock"
+
+var (
+	// ErrLocked is returned if another migrator holds the lock of the migrations collection.
+	ErrLocked = errors.New("migrations are locked by another migrator")
+
+	// ErrDirty is returned if a migration that was not run in a transaction failed, leaving the
+	// database in an unknown state. Fix the database and record its version with Force.
+	ErrDirty = errors.New("a previous migration failed and left the database dirty")
+
+	// ErrIrreversible is returned if reverting to a version requires reverting a migration without
+	// a Down function.
+	ErrIrreversible = errors.New("migration cannot be reverted")
+)
+
+// Migration is a versioned change to a database.
+type Migration struct {
+	// Version orders the migrations. It must be positive and unique.
+	Version uint64
+
+	// Description describes the migration. It is recorded with the version.
+	Description string
+
+	// Up applies the migration.
+	Up func(ctx context.Context, db *mongo.Database) error
+
+	// Down reverts the migration. If it is nil, the migration cannot be reverted.
+	Down func(ctx context.Context, db *mongo.Database) error
+
+	// Transactional runs Up and Down in a transaction that also records the migration. The ctx
+	// passed to Up and Down must be used for all operations so that they run in the transaction.
+	Transactional bool
+}
+
+// Direction is the direction of a migration step.
+type Direction string
+
+// These constants are the directions of a migration step.
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Step is a migration applied or reverted by Migrate, or that would be in a dry run.
+type Step struct {
+	Version     uint64
+	Description string
+	Direction   Direction
+}
+
+// Result describes a run of Migrate.
+type Result struct {
+	// From is the version before the run.
+	From uint64
+
+	// To is the version after the run. For a dry run, it is the version the run would migrate to.
+	To uint64
+
+	// Steps are the migrations applied or reverted, in order. If the run fails, they only include
+	// the steps that completed.
+	Steps []Step
+
+	// DryRun is whether the steps were only planned.
+	DryRun bool
+}
+
+// record is a document of the migrations collection recording an applied migration.
+type record struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+	Dirty       bool      `bson:"dirty"`
+}
+
+// Migrator runs migrations against a database.
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	collection string
+	lockTTL    time.Duration
+	owner      string
+	dryRun     bool
+	now        func() time.Time
+}
+
+// New creates a Migrator that runs the given migrations against db. It returns an error if a
+// migration has a zero or duplicate version or no Up function.
+func New(db *mongo.Database, migrations ...Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		switch {
+		case m.Version == 0:
+			return nil, errors.New("migration version must be positive")
+		case m.Version > 1<<63-1:
+			return nil, fmt.Errorf("migration version %d is too large", m.Version)
+		case i > 0 && sorted[i-1].Version == m.Version:
+			return nil, fmt.Errorf("migration version %d is defined more than once", m.Version)
+		case m.Up == nil:
+			return nil, fmt.Errorf("migration %d has no Up function", m.Version)
+		}
+	}
+
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		collection: DefaultCollection,
+		lockTTL:    DefaultLockTTL,
+		owner:      host + ":" + strconv.Itoa(os.Getpid()) + ":" + bson.NewObjectID().Hex(),
+		now:        time.Now,
+	}, nil
+}
+
+// SetCollection sets the name of the migrations collection. The default is DefaultCollection.
+func (m *Migrator) SetCollection(name string) *Migrator {
+	m.collection = name
+	return m
+}
+
+// SetLockTTL sets the time after which the lock of a migrator that stopped without releasing it
+// expires. A running migrator renews its lock, so the TTL does not limit how long migrations can
+// run. The default is DefaultLockTTL.
+func (m *Migrator) SetLockTTL(ttl time.Duration) *Migrator {
+	m.lockTTL = ttl
+	return m
+}
+
+// SetDryRun configures the Migrator to plan the steps of Migrate and Up without taking the lock or
+// running any migration.
+func (m *Migrator) SetDryRun(dryRun bool) *Migrator {
+	m.dryRun = dryRun
+	return m
+}
+
+// Latest returns the highest version of the migrations, or 0 if there are none.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// coll returns the migrations collection. Records are written with a majority write concern so
+// that they survive a failover.
+func (m *Migrator) coll() *mongo.Collection {
+	return m.db.Collection(m.collection, options.Collection().SetWriteConcern(writeconcern.Majority()))
+}
+
+// Version returns the highest applied version, or 0 if no migration has been applied, and whether
+// a failed migration left the database dirty.
+func (m *Migrator) Version(ctx context.Context) (version uint64, dirty bool, err error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, r := range records {
+		if uint64(r.Version) > version {
+			version = uint64(r.Version)
+		}
+		dirty = dirty || r.Dirty
+	}
+	return version, dirty, nil
+}
+
+func (m *Migrator) records(ctx context.Context) ([]record, error) {
+	cursor, err := m.coll().Find(ctx, bson.D{{"_id", bson.D{{"$type", "number"}}}})
+	if err != nil {
+		return nil, err
+	}
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Up applies all pending migrations. See Migrate.
+func (m *Migrator) Up(ctx context.Context) (*Result, error) {
+	return m.Migrate(ctx, m.Latest())
+}
+
+// Migrate applies the pending migrations up to and including target, in ascending order, and
+// reverts the applied migrations after target, in descending order. Use a target of 0 to revert
+// all migrations. Migrate holds the lock of the migrations collection while it runs and returns
+// ErrLocked if another migrator holds it.
+//
+// Migrate returns ErrDirty without running any migration if a previous migration failed, and an
+// error if a migration before an applied one is pending, which happens when migrations are added
+// out of order, or if an applied migration after target is unknown.
+func (m *Migrator) Migrate(ctx context.Context, target uint64) (*Result, error) {
+	if m.dryRun {
+		result, _, err := m.plan(ctx, target)
+		return result, err
+	}
+
+	release, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, applied, err := m.plan(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	steps := result.Steps
+	result.Steps, result.DryRun, result.To = nil, false, result.From
+
+	for _, step := range steps {
+		if err := m.run(ctx, migrationByVersion(m.migrations, step.Version), step.Direction); err != nil {
+			return result, fmt.Errorf("migration %d %s: %w", step.Version, step.Direction, err)
+		}
+		if step.Direction == DirectionUp {
+			applied[step.Version] = true
+		} else {
+			delete(applied, step.Version)
+		}
+		result.Steps = append(result.Steps, step)
+		result.To = maxVersion(applied)
+	}
+	return result, nil
+}
+
+func maxVersion(applied map[uint64]bool) uint64 {
+	var max uint64
+	for v := range applied {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// plan returns the steps to migrate from the recorded versions to target and the applied
+// versions.
+func (m *Migrator) plan(ctx context.Context, target uint64) (*Result, map[uint64]bool, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	applied := make(map[uint64]bool, len(records))
+	for _, r := range records {
+		if r.Dirty {
+			return nil, nil, fmt.Errorf("%w: version %d", ErrDirty, r.Version)
+		}
+		applied[uint64(r.Version)] = true
+	}
+
+	steps, err := planSteps(m.migrations, applied, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Result{From: maxVersion(applied), To: versionAfter(applied, steps), Steps: steps, DryRun: true}, applied, nil
+}
+
+// versionAfter returns the version after running steps on a database with the given applied
+// versions.
+func versionAfter(applied map[uint64]bool, steps []Step) uint64 {
+	after := make(map[uint64]bool, len(applied)+len(steps))
+	for v := range applied {
+		after[v] = true
+	}
+	for _, step := range steps {
+		if step.Direction == DirectionUp {
+			after[step.Version] = true
+		} else {
+			delete(after, step.Version)
+		}
+	}
+	return maxVersion(after)
+}
+
+// planSteps returns the steps that migrate a database with the given applied versions to target.
+func planSteps(migrations []Migration, applied map[uint64]bool, target uint64) ([]Step, error) {
+	known := make(map[uint64]bool, len(migrations))
+	for _, mig := range migrations {
+		known[mig.Version] = true
+	}
+
+	var down []Step
+	for v := range applied {
+		if v <= target {
+			continue
+		}
+		if !known[v] {
+			return nil, fmt.Errorf("applied migration %d is unknown", v)
+		}
+		down = append(down, Step{Version: v})
+	}
+	sort.Slice(down, func(i, j int) bool { return down[i].Version > down[j].Version })
+	for i := range down {
+		mig := migrationByVersion(migrations, down[i].Version)
+		if mig.Down == nil {
+			return nil, fmt.Errorf("%w: migration %d has no Down function", ErrIrreversible, mig.Version)
+		}
+		down[i] = Step{Version: mig.Version, Description: mig.Description, Direction: DirectionDown}
+	}
+	if len(down) > 0 {
+		return down, nil
+	}
+
+	var up []Step
+	maxApplied := maxVersion(applied)
+	for _, mig := range migrations {
+		if mig.Version > target || applied[mig.Version] {
+			continue
+		}
+		if mig.Version < maxApplied {
+			return nil, fmt.Errorf("migration %d is pending but later migration %d is applied", mig.Version, maxApplied)
+		}
+		up = append(up, Step{Version: mig.Version, Description: mig.Description, Direction: DirectionUp})
+	}
+	return up, nil
+}
+
+func migrationByVersion(migrations []Migration, version uint64) Migration {
+	for _, mig := range migrations {
+		if mig.Version == version {
+			return mig
+		}
+	}
+	return Migration{Version: version}
+}
+
+// run applies or reverts a migration and updates its record.
+func (m *Migrator) run(ctx context.Context, mig Migration, dir Direction) error {
+	fn := mig.Up
+	if dir == DirectionDown {
+		fn = mig.Down
+	}
+	coll := m.coll()
+
+	if mig.Transactional {
+		sess, err := m.db.Client().StartSession()
+		if err != nil {
+			return err
+		}
+		defer sess.EndSession(ctx)
+
+		_, err = sess.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+			if err := fn(ctx, m.db); err != nil {
+				return nil, err
+			}
+			return nil, m.writeRecord(ctx, coll, mig, dir, false)
+		})
+		return err
+	}
+
+	// Mark the migration dirty while it runs so that a failure, or a crash, is detected by the
+	// next run.
+	if dir == DirectionUp {
+		if err := m.writeRecord(ctx, coll, mig, dir, true); err != nil {
+			return err
+		}
+	} else {
+		_, err := coll.UpdateOne(ctx, bson.D{{"_id", int64(mig.Version)}}, bson.D{{"$set", bson.D{{"dirty", true}}}})
+		if err != nil {
+			return err
+		}
+	}
+	if err := fn(ctx, m.db); err != nil {
+		return err
+	}
+	return m.writeRecord(ctx, coll, mig, dir, false)
+}
+
+// writeRecord records a migration as applied, possibly dirty, or removes the record of a reverted
+// migration.
+func (m *Migrator) writeRecord(ctx context.Context, coll *mongo.Collection, mig Migration, dir Direction, dirty bool) error {
+	filter := bson.D{{"_id", int64(mig.Version)}}
+	if dir == DirectionDown {
+		_, err := coll.DeleteOne(ctx, filter)
+		return err
+	}
+	r := record{Version: int64(mig.Version), Description: mig.Description, AppliedAt: m.now().UTC(), Dirty: dirty}
+	_, err := coll.ReplaceOne(ctx, filter, r, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Force records version as the current version without running any migration: the records of
+// later versions are removed, the versions up to and including it are recorded as applied, and
+// all records are marked clean. Use it to recover from ErrDirty after fixing the database, or to
+// adopt migrations for a database whose schema was created by other means.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	release, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	coll := m.coll()
+	if _, err := coll.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$type", "number"}, {"$gt", int64(version)}}}}); err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if err := m.writeRecord(ctx, coll, mig, DirectionUp, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lock acquires the lock of the migrations collection and renews it until the returned function
+// is called.
+func (m *Migrator) lock(ctx context.Context) (release func(), err error) {
+	coll := m.coll()
+	if err := m.acquire(ctx, coll); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.lockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// A failed renewal is retried on the next tick. If the lock expires, another
+				// migrator can take it, which is the same outcome as this migrator crashing.
+				_, _ = coll.UpdateOne(ctx,
+					bson.D{{"_id", lockID}, {"owner", m.owner}},
+					bson.D{{"$set", bson.D{{"expiresAt", m.now().Add(m.lockTTL)}}}},
+				)
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		// Release the lock even if ctx is done.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, _ = coll.DeleteOne(releaseCtx, bson.D{{"_id", lockID}, {"owner", m.owner}})
+	}, nil
+}
+
+// acquire takes the lock if no migrator holds it or its holder's lock expired. The upsert fails
+// with a duplicate key error if the lock document exists and is held.
+func (m *Migrator) acquire(ctx context.Context, coll *mongo.Collection) error {
+	if m.lockTTL <= 0 {
+		return errors.New("lock TTL must be positive")
+	}
+	now := m.now()
+	_, err := coll.UpdateOne(ctx,
+		bson.D{{"_id", lockID}, {"$or", bson.A{
+			bson.D{{"owner", m.owner}},
+			bson.D{{"expiresAt", bson.D{{"$lt", now}}}},
+		}}},
+		bson.D{{"$set", bson.D{
+			{"owner", m.owner},
+			{"acquiredAt", now},
+			{"expiresAt", now.Add(m.lockTTL)},
+		}}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	return err
+}
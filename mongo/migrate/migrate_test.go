@@ -0,0 +1,130 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func noop(context.Context, *mongo.Database) error { return nil }
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(nil,
+		Migration{Version: 3, Up: noop},
+		Migration{Version: 1, Up: noop},
+		Migration{Version: 2, Up: noop},
+	)
+	require.NoError(t, err, "New error")
+	assert.Equal(t, uint64(3), m.Latest(), "latest version mismatch")
+	assert.Equal(t, uint64(1), m.migrations[0].Version, "expected migrations to be sorted")
+
+	invalid := [][]Migration{
+		{{Version: 0, Up: noop}},
+		{{Version: 1, Up: noop}, {Version: 1, Up: noop}},
+		{{Version: 1}},
+		{{Version: 1 << 63, Up: noop}},
+	}
+	for _, migrations := range invalid {
+		_, err := New(nil, migrations...)
+		assert.Error(t, err, "expected New error for %v", migrations)
+	}
+}
+
+func TestPlanSteps(t *testing.T) {
+	t.Parallel()
+
+	migrations := []Migration{
+		{Version: 1, Description: "one", Up: noop, Down: noop},
+		{Version: 2, Description: "two", Up: noop, Down: noop},
+		{Version: 3, Description: "three", Up: noop},
+		{Version: 4, Description: "four", Up: noop, Down: noop},
+	}
+	applied := func(versions ...uint64) map[uint64]bool {
+		m := make(map[uint64]bool, len(versions))
+		for _, v := range versions {
+			m[v] = true
+		}
+		return m
+	}
+	versions := func(steps []Step) []uint64 {
+		var vs []uint64
+		for _, s := range steps {
+			vs = append(vs, s.Version)
+		}
+		return vs
+	}
+
+	t.Run("up", func(t *testing.T) {
+		t.Parallel()
+
+		steps, err := planSteps(migrations, applied(1), 4)
+		require.NoError(t, err, "planSteps error")
+		assert.Equal(t, []uint64{2, 3, 4}, versions(steps), "steps mismatch")
+		assert.Equal(t, DirectionUp, steps[0].Direction, "direction mismatch")
+		assert.Equal(t, "two", steps[0].Description, "description mismatch")
+		assert.Equal(t, uint64(4), versionAfter(applied(1), steps), "version mismatch")
+	})
+
+	t.Run("up to target", func(t *testing.T) {
+		t.Parallel()
+
+		steps, err := planSteps(migrations, applied(), 2)
+		require.NoError(t, err, "planSteps error")
+		assert.Equal(t, []uint64{1, 2}, versions(steps), "steps mismatch")
+	})
+
+	t.Run("down", func(t *testing.T) {
+		t.Parallel()
+
+		steps, err := planSteps(migrations, applied(1, 2, 3, 4), 3)
+		require.NoError(t, err, "planSteps error")
+		assert.Equal(t, []uint64{4}, versions(steps), "steps mismatch")
+		assert.Equal(t, DirectionDown, steps[0].Direction, "direction mismatch")
+		assert.Equal(t, uint64(3), versionAfter(applied(1, 2, 3, 4), steps), "version mismatch")
+	})
+
+	t.Run("irreversible", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := planSteps(migrations, applied(1, 2, 3, 4), 1)
+		assert.True(t, errors.Is(err, ErrIrreversible), "expected ErrIrreversible, got %v", err)
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := planSteps(migrations, applied(1, 3), 4)
+		assert.Error(t, err, "expected error for a pending migration before an applied one")
+	})
+
+	t.Run("unknown applied", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := planSteps(migrations, applied(1, 2, 3, 4, 5), 4)
+		assert.Error(t, err, "expected error for an unknown applied migration")
+
+		steps, err := planSteps(migrations, applied(1, 2, 3, 4, 5), 5)
+		require.NoError(t, err, "planSteps error")
+		assert.Len(t, steps, 0, "expected no steps")
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		t.Parallel()
+
+		steps, err := planSteps(migrations, applied(1, 2, 3, 4), 4)
+		require.NoError(t, err, "planSteps error")
+		assert.Len(t, steps, 0, "expected no steps")
+	})
+}
@@ -7,7 +7,6 @@
 package mongo
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -89,6 +88,13 @@ func getEncoder(
 		if opts.UseJSONStructTags {
 			enc.UseJSONStructTags()
 		}
+		if opts.UseJSONAndTextMarshalers {
+			enc.UseJSONAndTextMarshalers()
+		}
+		if opts.SortFields {
+			enc.SortFields()
+		}
+		enc.SetTimeEncoding(opts.TimeEncoding)
 	}
 
 	if reg != nil {
@@ -127,14 +133,49 @@ func marshal(
 		val = bson.Raw(bs)
 	}
 
-	buf := new(bytes.Buffer)
-	enc := getEncoder(buf, bsonOpts, registry)
-	err := enc.Encode(val)
+	// Append directly to a new slice so the document is not copied out of an intermediate buffer.
+	enc := getEncoder(nil, bsonOpts, registry)
+	doc, err := enc.EncodeAppend(nil, val)
 	if err != nil {
 		return nil, MarshalError{Value: val, Err: err}
 	}
 
-	return buf.Bytes(), nil
+	return doc, nil
+}
+
+// marshalDocument marshals val like marshal, except that a document that is already encoded as
+// BSON is returned as is after the checks of mode, if mode is set to ValidateRawDocuments or
+// TrustRawDocuments.
+func marshalDocument(
+	val interface{},
+	mode *options.RawDocuments,
+	bsonOpts *options.BSONOptions,
+	registry *bson.Registry,
+) (bsoncore.Document, error) {
+	if mode == nil || *mode == options.ReencodeRawDocuments {
+		return marshal(val, bsonOpts, registry)
+	}
+
+	var doc bsoncore.Document
+	switch v := val.(type) {
+	case bson.Raw:
+		doc = bsoncore.Document(v)
+	case []byte:
+		doc = v
+	default:
+		return marshal(val, bsonOpts, registry)
+	}
+
+	var err error
+	if *mode == options.ValidateRawDocuments {
+		err = doc.Validate()
+	} else if length, _, ok := bsoncore.ReadLength(doc); !ok || int(length) != len(doc) || doc[len(doc)-1] != 0 {
+		err = errors.New("document length prefix does not match its size or document is not terminated")
+	}
+	if err != nil {
+		return nil, MarshalError{Value: val, Err: err}
+	}
+	return doc, nil
 }
 
 // ensureID inserts the given ObjectID as an element named "_id" at the
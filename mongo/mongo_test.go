@@ -516,6 +516,65 @@ func TestMarshalAggregatePipeline(t *testing.T) {
 	}
 }
 
+func TestMarshalDocument(t *testing.T) {
+	t.Parallel()
+
+	valid, err := bson.Marshal(bson.D{{"_id", 1}, {"x", "y"}})
+	require.NoError(t, err, "Marshal error")
+	// A string element whose length prefix exceeds the document.
+	malformed := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "x", "y"))
+	malformed[7] = 0x7f
+	truncated := valid[:len(valid)-1]
+
+	mode := func(rd options.RawDocuments) *options.RawDocuments { return &rd }
+	testCases := []struct {
+		name        string
+		value       interface{}
+		mode        *options.RawDocuments
+		passthrough bool
+		wantErr     bool
+	}{
+		{"default re-encodes", bson.Raw(valid), nil, false, false},
+		{"re-encode", bson.Raw(valid), mode(options.ReencodeRawDocuments), false, false},
+		{"validate raw", bson.Raw(valid), mode(options.ValidateRawDocuments), true, false},
+		{"validate bytes", valid, mode(options.ValidateRawDocuments), true, false},
+		{"validate malformed", bson.Raw(malformed), mode(options.ValidateRawDocuments), false, true},
+		{"trust", bson.Raw(valid), mode(options.TrustRawDocuments), true, false},
+		{"trust malformed", bson.Raw(malformed), mode(options.TrustRawDocuments), true, false},
+		{"trust truncated", truncated, mode(options.TrustRawDocuments), false, true},
+		{"trust non-raw value", bson.D{{"x", "y"}}, mode(options.TrustRawDocuments), false, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := marshalDocument(tc.value, tc.mode, nil, nil)
+			if tc.wantErr {
+				var me MarshalError
+				assert.True(t, errors.As(err, &me), "expected MarshalError, got %v", err)
+				return
+			}
+			require.NoError(t, err, "marshalDocument error")
+			// Documents that cannot be re-encoded are only passed through without validation.
+			if want, err := marshal(tc.value, nil, nil); err == nil {
+				assert.Equal(t, want, got, "document mismatch")
+			}
+
+			var raw []byte
+			switch v := tc.value.(type) {
+			case bson.Raw:
+				raw = v
+			case []byte:
+				raw = v
+			}
+			sameArray := len(raw) > 0 && &raw[0] == &got[0]
+			assert.Equal(t, tc.passthrough, sameArray, "expected passthrough %v, got %v", tc.passthrough, sameArray)
+		})
+	}
+}
+
 func TestMarshalValue(t *testing.T) {
 	t.Parallel()
 
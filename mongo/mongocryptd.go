@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
@@ -30,11 +32,56 @@ const (
 var defaultTimeoutArgs = []string{"--idleShutdownTimeoutSecs=60"}
 var databaseOpts = options.Database().SetReadConcern(&readconcern.ReadConcern{}).SetReadPreference(readpref.Primary())
 
+// QueryAnalysisProvider identifies the component a Client uses to analyze commands for automatic
+// encryption.
+type QueryAnalysisProvider string
+
+// These constants are the valid values for QueryAnalysisProvider.
+const (
+	// QueryAnalysisNone indicates that automatic encryption is not configured or that commands are
+	// not analyzed because BypassAutoEncryption or BypassQueryAnalysis is set.
+	QueryAnalysisNone QueryAnalysisProvider = ""
+	// QueryAnalysisCryptShared indicates that the crypt_shared library was loaded and is used to
+	// analyze commands.
+	QueryAnalysisCryptShared QueryAnalysisProvider = "crypt_shared"
+	// QueryAnalysisMongocryptd indicates that commands are sent to mongocryptd for analysis.
+	QueryAnalysisMongocryptd QueryAnalysisProvider = "mongocryptd"
+)
+
+// AutoEncryptionStatus describes the state of automatic encryption for a Client.
+type AutoEncryptionStatus struct {
+	// Enabled is true if the Client was configured with AutoEncryptionOptions.
+	Enabled bool
+
+	// Provider is the component used to analyze commands for automatic encryption.
+	Provider QueryAnalysisProvider
+
+	// CryptSharedLibVersion is the version string of the loaded crypt_shared library. It is empty if
+	// the library was not loaded.
+	CryptSharedLibVersion string
+
+	// MongocryptdSpawnBypassed is true if the Client uses mongocryptd but never spawns it, e.g. because
+	// the "mongocryptdBypassSpawn" extra option is set.
+	MongocryptdSpawnBypassed bool
+
+	// MongocryptdSpawnCount is the number of times the Client has successfully spawned mongocryptd.
+	MongocryptdSpawnCount int
+
+	// LastSpawnError is the error returned by the most recent attempt to spawn mongocryptd, or nil if
+	// that attempt succeeded or no attempt was made.
+	LastSpawnError error
+}
+
 type mongocryptdClient struct {
 	bypassSpawn bool
 	client      *Client
 	path        string
 	spawnArgs   []string
+	monitor     *event.QueryAnalysisMonitor
+
+	mu           sync.Mutex
+	spawnCount   int
+	lastSpawnErr error
 }
 
 // newMongocryptdClient creates a client to mongocryptd.
@@ -65,9 +112,12 @@ func newMongocryptdClient(opts options.Lister[options.AutoEncryptionOptions]) (*
 		// - bypassAutoEncryption is true because mongocryptd is not used during decryption
 		// - bypassQueryAnalysis is true because mongocryptd is not used during decryption
 		bypassSpawn: bypassSpawn || bypassAutoEncryption || bypassQueryAnalysis,
+		monitor:     args.QueryAnalysisMonitor,
 	}
 
-	if !mc.bypassSpawn {
+	if mc.bypassSpawn {
+		publishQueryAnalysisEvent(mc.monitor, &event.QueryAnalysisEvent{Type: event.MongocryptdSpawnBypassed})
+	} else {
 		mc.path, mc.spawnArgs = createSpawnArgs(args.ExtraOptions)
 		if err := mc.spawnProcess(); err != nil {
 			return nil, err
@@ -135,7 +185,37 @@ func (mc *mongocryptdClient) spawnProcess() error {
 	cmd := exec.Command(mc.path, mc.spawnArgs...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
-	return cmd.Start()
+	err := cmd.Start()
+
+	mc.mu.Lock()
+	if err == nil {
+		mc.spawnCount++
+	}
+	mc.lastSpawnErr = err
+	mc.mu.Unlock()
+
+	evt := &event.QueryAnalysisEvent{
+		Type:            event.MongocryptdSpawned,
+		MongocryptdPath: mc.path,
+		MongocryptdArgs: mc.spawnArgs,
+	}
+	if err != nil {
+		evt.Type = event.MongocryptdSpawnFailed
+		evt.Error = err
+	}
+	publishQueryAnalysisEvent(mc.monitor, evt)
+
+	return err
+}
+
+// status fills in the mongocryptd-specific fields of the given AutoEncryptionStatus.
+func (mc *mongocryptdClient) status(s *AutoEncryptionStatus) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	s.MongocryptdSpawnBypassed = mc.bypassSpawn
+	s.MongocryptdSpawnCount = mc.spawnCount
+	s.LastSpawnError = mc.lastSpawnErr
 }
 
 // createSpawnArgs creates arguments to spawn mcryptClient. It returns the path and a slice of arguments.
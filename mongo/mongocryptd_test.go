@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestMongocryptdSpawnStatus(t *testing.T) {
+	t.Run("spawn failure is recorded and published", func(t *testing.T) {
+		var events []*event.QueryAnalysisEvent
+		mc := &mongocryptdClient{
+			path:      "/path/to/nonexistent/mongocryptd",
+			spawnArgs: []string{"--idleShutdownTimeoutSecs=60"},
+			monitor: &event.QueryAnalysisMonitor{
+				Event: func(evt *event.QueryAnalysisEvent) {
+					events = append(events, evt)
+				},
+			},
+		}
+
+		err := mc.spawnProcess()
+		require.Error(t, err, "expected error spawning nonexistent mongocryptd")
+
+		var status AutoEncryptionStatus
+		mc.status(&status)
+		assert.Equal(t, 0, status.MongocryptdSpawnCount, "expected no successful spawns")
+		assert.Equal(t, err, status.LastSpawnError, "expected last spawn error %v, got %v", err, status.LastSpawnError)
+
+		require.Len(t, events, 1, "expected 1 event, got %d", len(events))
+		assert.Equal(t, event.MongocryptdSpawnFailed, events[0].Type, "unexpected event type")
+		assert.Equal(t, mc.path, events[0].MongocryptdPath, "unexpected mongocryptd path")
+		assert.Equal(t, err, events[0].Error, "unexpected event error")
+	})
+	t.Run("client without auto encryption", func(t *testing.T) {
+		client, err := newClient()
+		require.NoError(t, err, "newClient error: %v", err)
+
+		status := client.AutoEncryptionStatus()
+		assert.Equal(t, AutoEncryptionStatus{}, status, "expected empty status, got %+v", status)
+	})
+}
@@ -0,0 +1,476 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package mongotest provides an in-memory deployment with scripted command replies, to unit test
+// applications that use the driver without running a mongod.
+//
+// A Deployment replies to the commands it receives with the replies scripted for their command
+// names, in order, and records the commands so that tests can inspect them. NewClient creates a
+// Client that runs all of its operations against the Deployment:
+//
+//	d := mongotest.NewDeployment()
+//	d.AddReplies("insert",
+//		mongotest.NetworkError(),
+//		mongotest.Success(bson.E{Key: "n", Value: 1}),
+//	)
+//	client, err := d.NewClient()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer client.Disconnect(context.Background())
+//
+//	// The insert is retried after the network error and succeeds.
+//	_, err = client.Database("db").Collection("coll").InsertOne(context.Background(), bson.D{{"x", 1}})
+//	inserts := d.Commands("insert")
+//
+// The Deployment behaves like a single replica set primary that supports sessions and retryable
+// reads and writes, so the retry and error handling paths of the driver run as they do against a
+// real deployment. It does not execute commands: a command with no scripted reply fails with a
+// CommandNotFound error, except for endSessions and killCursors, which succeed. Server monitoring,
+// authentication, and the connection handshake are not run.
+//
+// A WireStub is an in-memory server that speaks a subset of the wire protocol, to run those as
+// well. It executes simple inserts and finds, and injects latency, disconnections, and malformed
+// replies.
+package mongotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/csot"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
+)
+
+// errorCommandNotFound is the error code of the reply to a command without a scripted reply.
+const errorCommandNotFound = 59
+
+// Command is a command received by a Deployment.
+type Command struct {
+	// Name is the name of the command, which is the first key of its document, e.g. "find".
+	Name string
+
+	// Database is the database the command was run on.
+	Database string
+
+	// Document is the command document. The documents sent in document sequences, such as the
+	// documents of an insert command, are included as arrays.
+	Document bson.Raw
+}
+
+// Handler computes the reply to a command.
+type Handler func(cmd Command) Reply
+
+// Deployment is an in-memory deployment that replies to commands with scripted replies. It is
+// safe for concurrent use.
+type Deployment struct {
+	mu       sync.Mutex
+	replies  map[string][]Reply
+	handlers map[string]Handler
+	commands []Command
+
+	subscriptions map[*driver.Subscription]chan description.Topology
+	connectionID  int64
+}
+
+var _ driver.Deployment = &Deployment{}
+var _ driver.Server = &Deployment{}
+var _ driver.Connector = &Deployment{}
+var _ driver.Disconnector = &Deployment{}
+var _ driver.Subscriber = &Deployment{}
+
+// NewDeployment creates a Deployment with no scripted replies.
+func NewDeployment() *Deployment {
+	return &Deployment{
+		replies:  make(map[string][]Reply),
+		handlers: make(map[string]Handler),
+	}
+}
+
+// NewClient creates a Client with the given options that runs its operations against d. Options
+// that configure the connection to a deployment, such as the hosts, TLS, authentication, and pool
+// options, are ignored.
+func (d *Deployment) NewClient(opts ...options.Lister[options.ClientOptions]) (*mongo.Client, error) {
+	deployment := options.Client()
+	deployment.Opts = append(deployment.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = d
+
+		return nil
+	})
+	return mongo.Connect(append(opts, deployment)...)
+}
+
+// AddReplies adds replies to the commands with the given name. Each command consumes the next
+// reply, in the order the replies were added.
+func (d *Deployment) AddReplies(command string, replies ...Reply) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.replies[command] = append(d.replies[command], replies...)
+}
+
+// Handle sets the handler that computes the replies to the commands with the given name when no
+// reply added with AddReplies remains. A nil handler removes the handler of the command.
+func (d *Deployment) Handle(command string, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if h == nil {
+		delete(d.handlers, command)
+		return
+	}
+	d.handlers[command] = h
+}
+
+// Pending returns the number of replies added with AddReplies that have not been consumed by
+// commands with the given name.
+func (d *Deployment) Pending(command string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.replies[command])
+}
+
+// Commands returns the commands received by d with the given names, or all received commands
+// if no name is given, in the order they were received.
+func (d *Deployment) Commands(names ...string) []Command {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cmds := make([]Command, 0, len(d.commands))
+	for _, cmd := range d.commands {
+		if len(names) == 0 || containsName(names, cmd.Name) {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// Reset removes the remaining replies, the handlers, and the received commands of d.
+func (d *Deployment) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.replies = make(map[string][]Reply)
+	d.handlers = make(map[string]Handler)
+	d.commands = nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reply records cmd and returns its reply.
+func (d *Deployment) reply(cmd Command) Reply {
+	d.mu.Lock()
+	d.commands = append(d.commands, cmd)
+	if replies := d.replies[cmd.Name]; len(replies) > 0 {
+		d.replies[cmd.Name] = replies[1:]
+		d.mu.Unlock()
+		return replies[0]
+	}
+	h := d.handlers[cmd.Name]
+	d.mu.Unlock()
+
+	if h != nil {
+		return h(cmd)
+	}
+	switch cmd.Name {
+	case "endSessions", "killCursors":
+		return Success()
+	}
+	return CommandError(errorCommandNotFound, "CommandNotFound",
+		fmt.Sprintf("mongotest: no reply scripted for command %q", cmd.Name))
+}
+
+// SelectServer implements the driver.Deployment interface. It returns d, which is the only server
+// of the deployment.
+func (d *Deployment) SelectServer(context.Context, description.ServerSelector) (driver.Server, error) {
+	return d, nil
+}
+
+// GetServerSelectionTimeout implements the driver.Deployment interface. It returns zero because
+// server selection does not wait.
+func (*Deployment) GetServerSelectionTimeout() time.Duration {
+	return 0
+}
+
+// Kind implements the driver.Deployment interface. It always returns description.TopologyKindSingle.
+func (*Deployment) Kind() description.TopologyKind {
+	return description.TopologyKindSingle
+}
+
+// Connection implements the driver.Server interface. It returns a new connection that sends the
+// commands written to it to d.
+func (d *Deployment) Connection(context.Context) (*mnet.Connection, error) {
+	return mnet.NewConnection(&connection{
+		deployment: d,
+		id:         atomic.AddInt64(&d.connectionID, 1),
+	}), nil
+}
+
+// RTTMonitor implements the driver.Server interface.
+func (*Deployment) RTTMonitor() driver.RTTMonitor {
+	return &csot.ZeroRTTMonitor{}
+}
+
+// Connect is a no-op method which implements the driver.Connector interface.
+func (*Deployment) Connect() error {
+	return nil
+}
+
+// Disconnect implements the driver.Disconnector interface. It closes the subscriptions of d.
+func (d *Deployment) Disconnect(context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for sub, updates := range d.subscriptions {
+		close(updates)
+		delete(d.subscriptions, sub)
+	}
+	return nil
+}
+
+// Subscribe implements the driver.Subscriber interface. The subscription receives a single
+// topology description, which enables sessions.
+func (d *Deployment) Subscribe() (*driver.Subscription, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sessionTimeoutMinutes := *drivertest.MockDescription.SessionTimeoutMinutes
+	updates := make(chan description.Topology, 1)
+	updates <- description.Topology{SessionTimeoutMinutes: &sessionTimeoutMinutes}
+
+	sub := &driver.Subscription{Updates: updates}
+	if d.subscriptions == nil {
+		d.subscriptions = make(map[*driver.Subscription]chan description.Topology)
+	}
+	d.subscriptions[sub] = updates
+	return sub, nil
+}
+
+// Unsubscribe implements the driver.Subscriber interface.
+func (d *Deployment) Unsubscribe(sub *driver.Subscription) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if updates, ok := d.subscriptions[sub]; ok {
+		close(updates)
+		delete(d.subscriptions, sub)
+	}
+	return nil
+}
+
+// connection implements the driver.Connection interface. It sends each command written to it to
+// its deployment and returns the reply of the command when it is read.
+type connection struct {
+	deployment *Deployment
+	id         int64
+
+	requestID int32
+	pending   *Reply
+}
+
+var _ mnet.ReadWriteCloser = &connection{}
+var _ mnet.Describer = &connection{}
+
+// Write parses the command in wm and computes its reply.
+func (c *connection) Write(_ context.Context, wm []byte) error {
+	requestID, flags, cmd, err := parseCommand(wm)
+	if err != nil {
+		return err
+	}
+
+	reply := c.deployment.reply(cmd)
+	if flags&wiremessage.MoreToCome != 0 {
+		// The driver does not read the replies of unacknowledged writes.
+		return nil
+	}
+	c.requestID = requestID
+	c.pending = &reply
+	return nil
+}
+
+// Read returns the reply of the last command written to the connection.
+func (c *connection) Read(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.pending == nil {
+		return nil, errors.New("mongotest: no command is waiting for a reply")
+	}
+	reply := *c.pending
+	c.pending = nil
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+
+	return appendReply(nil, c.requestID, reply.Document)
+}
+
+// appendReply appends the OP_MSG reply document doc to the command with the request ID responseTo
+// to dst. An "ok" field with the value 1 is added if doc does not contain one.
+func appendReply(dst []byte, responseTo int32, doc bson.D) ([]byte, error) {
+	if !hasKey(doc, "ok") {
+		doc = append(bson.D{{"ok", 1}}, doc...)
+	}
+	resBytes, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("mongotest: error marshaling reply: %w", err)
+	}
+
+	var wmindex int32
+	wmindex, dst = wiremessage.AppendHeaderStart(dst, wiremessage.NextRequestID(), responseTo, wiremessage.OpMsg)
+	dst = wiremessage.AppendMsgFlags(dst, 0)
+	dst = wiremessage.AppendMsgSectionType(dst, wiremessage.SingleDocument)
+	dst = append(dst, resBytes...)
+	dst = bsoncore.UpdateLength(dst, wmindex, int32(len(dst[wmindex:])))
+	return dst, nil
+}
+
+func hasKey(doc bson.D, key string) bool {
+	for _, e := range doc {
+		if e.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCommand returns the request ID, flags, and command of an OP_MSG wire message.
+func parseCommand(wm []byte) (int32, wiremessage.MsgFlag, Command, error) {
+	errMalformed := errors.New("mongotest: malformed OP_MSG wire message")
+
+	length, requestID, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok || int(length) > len(wm) {
+		return 0, 0, Command{}, errMalformed
+	}
+	if opcode != wiremessage.OpMsg {
+		return 0, 0, Command{}, fmt.Errorf("mongotest: unsupported opcode %v", opcode)
+	}
+	rem = rem[:int(length)-16]
+	flags, rem, ok := wiremessage.ReadMsgFlags(rem)
+	if !ok {
+		return 0, 0, Command{}, errMalformed
+	}
+	if flags&wiremessage.ChecksumPresent != 0 && len(rem) >= 4 {
+		rem = rem[:len(rem)-4]
+	}
+
+	var body bsoncore.Document
+	type sequence struct {
+		identifier string
+		docs       []bsoncore.Document
+	}
+	var sequences []sequence
+	for len(rem) > 0 {
+		var stype wiremessage.SectionType
+		stype, rem, ok = wiremessage.ReadMsgSectionType(rem)
+		if !ok {
+			return 0, 0, Command{}, errMalformed
+		}
+		switch stype {
+		case wiremessage.SingleDocument:
+			body, rem, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+		case wiremessage.DocumentSequence:
+			var seq sequence
+			seq.identifier, seq.docs, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem)
+			sequences = append(sequences, seq)
+		default:
+			ok = false
+		}
+		if !ok {
+			return 0, 0, Command{}, errMalformed
+		}
+	}
+
+	elems, err := body.Elements()
+	if err != nil || len(elems) == 0 {
+		return 0, 0, Command{}, errMalformed
+	}
+
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	doc = append(doc, body[4:len(body)-1]...)
+	for _, seq := range sequences {
+		var aidx int32
+		aidx, doc = bsoncore.AppendArrayElementStart(doc, seq.identifier)
+		for i, d := range seq.docs {
+			doc = bsoncore.AppendDocumentElement(doc, strconv.Itoa(i), d)
+		}
+		doc, _ = bsoncore.AppendArrayEnd(doc, aidx)
+	}
+	doc, _ = bsoncore.AppendDocumentEnd(doc, idx)
+
+	db, _ := body.Lookup("$db").StringValueOK()
+	return requestID, flags, Command{
+		Name:     elems[0].Key(),
+		Database: db,
+		Document: bson.Raw(doc),
+	}, nil
+}
+
+// Description returns the description of the server of the deployment, a replica set primary.
+func (*connection) Description() description.Server {
+	return drivertest.MockDescription
+}
+
+// Close is a no-op operation.
+func (*connection) Close() error {
+	return nil
+}
+
+// ID returns an identifier for the connection.
+func (c *connection) ID() string {
+	return "<mongotest_connection>[-" + strconv.FormatInt(c.id, 10) + "]"
+}
+
+// DriverConnectionID returns the identifier of the connection.
+func (c *connection) DriverConnectionID() int64 {
+	return c.id
+}
+
+// ServerConnectionID returns the identifier of the connection.
+func (c *connection) ServerConnectionID() *int64 {
+	id := c.id
+	return &id
+}
+
+// Address returns the address of the server of the deployment.
+func (*connection) Address() address.Address {
+	return drivertest.MockDescription.CanonicalAddr
+}
+
+// Stale returns false because connections do not become stale.
+func (*connection) Stale() bool {
+	return false
+}
+
+// OIDCTokenGenID returns zero because authentication is not run.
+func (*connection) OIDCTokenGenID() uint64 {
+	return 0
+}
+
+// SetOIDCTokenGenID is a no-op method.
+func (*connection) SetOIDCTokenGenID(uint64) {}
@@ -0,0 +1,155 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func newTestClient(t *testing.T, d *Deployment, opts ...options.Lister[options.ClientOptions]) *mongo.Client {
+	t.Helper()
+
+	client, err := d.NewClient(opts...)
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+	})
+	return client
+}
+
+func TestDeployment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries write after network error", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("insert", NetworkError(), Success(bson.E{Key: "n", Value: 1}))
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"x", 1}})
+		require.NoError(t, err, "InsertOne error")
+
+		inserts := d.Commands("insert")
+		require.Len(t, inserts, 2, "expected the insert to be retried")
+		assert.Equal(t, "db", inserts[0].Database, "database mismatch")
+		assert.Equal(t, "coll", inserts[0].Document.Lookup("insert").StringValue(), "collection mismatch")
+		assert.Equal(t, int32(1), inserts[0].Document.Lookup("documents", "0", "x").Int32(), "document mismatch")
+		assert.Equal(t,
+			inserts[0].Document.Lookup("txnNumber"), inserts[1].Document.Lookup("txnNumber"),
+			"expected the retry to use the same transaction number")
+		assert.Equal(t, 0, d.Pending("insert"), "expected all replies to be consumed")
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("insert", NetworkError())
+		coll := newTestClient(t, d, options.Client().SetRetryWrites(false)).Database("db").Collection("coll")
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"x", 1}})
+		assert.True(t, mongo.IsNetworkError(err), "expected network error, got %v", err)
+		assert.Len(t, d.Commands("insert"), 1, "expected the insert to not be retried")
+	})
+
+	t.Run("command error", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("delete", CommandError(13, "Unauthorized", "not authorized"))
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		_, err := coll.DeleteOne(context.Background(), bson.D{})
+		var ce mongo.CommandError
+		require.True(t, errors.As(err, &ce), "expected CommandError, got %v", err)
+		assert.Equal(t, int32(13), ce.Code, "code mismatch")
+		assert.Equal(t, "Unauthorized", ce.Name, "code name mismatch")
+	})
+
+	t.Run("write errors", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("insert", WriteErrors(0, WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"_id", 1}})
+		assert.True(t, mongo.IsDuplicateKeyError(err), "expected duplicate key error, got %v", err)
+	})
+
+	t.Run("write concern error", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("update", WriteConcernError(1, 64, "WriteConcernTimeout", "waiting for replication timed out"))
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		_, err := coll.UpdateOne(context.Background(), bson.D{}, bson.D{{"$set", bson.D{{"x", 1}}}})
+		var we mongo.WriteException
+		require.True(t, errors.As(err, &we), "expected WriteException, got %v", err)
+		require.NotNil(t, we.WriteConcernError, "expected write concern error")
+		assert.Equal(t, 64, we.WriteConcernError.Code, "code mismatch")
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.AddReplies("find", Cursor("db.coll", 42, bson.D{{"_id", 1}}))
+		d.AddReplies("getMore", NextBatch("db.coll", 0, bson.D{{"_id", 2}}, bson.D{{"_id", 3}}))
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		cursor, err := coll.Find(context.Background(), bson.D{{"x", 1}})
+		require.NoError(t, err, "Find error")
+		var docs []bson.D
+		require.NoError(t, cursor.All(context.Background(), &docs), "All error")
+		assert.Len(t, docs, 3, "expected documents from both batches")
+
+		getMores := d.Commands("getMore")
+		require.Len(t, getMores, 1, "expected one getMore")
+		assert.Equal(t, int64(42), getMores[0].Document.Lookup("getMore").Int64(), "cursor ID mismatch")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		d.Handle("count", func(cmd Command) Reply {
+			return Success(bson.E{Key: "n", Value: len(cmd.Document.Lookup("count").StringValue())})
+		})
+		coll := newTestClient(t, d).Database("db").Collection("coll")
+
+		n, err := coll.EstimatedDocumentCount(context.Background())
+		require.NoError(t, err, "EstimatedDocumentCount error")
+		assert.Equal(t, int64(4), n, "count mismatch")
+	})
+
+	t.Run("unscripted command", func(t *testing.T) {
+		t.Parallel()
+
+		d := NewDeployment()
+		client := newTestClient(t, d)
+
+		err := client.Ping(context.Background(), nil)
+		var ce mongo.CommandError
+		require.True(t, errors.As(err, &ce), "expected CommandError, got %v", err)
+		assert.Equal(t, int32(errorCommandNotFound), ce.Code, "code mismatch")
+		assert.Len(t, d.Commands("ping"), 1, "expected ping to be recorded")
+
+		d.Reset()
+		assert.Len(t, d.Commands(), 0, "expected Reset to remove the received commands")
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongotest
+
+import (
+	"io"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Reply is the scripted reply to a command.
+type Reply struct {
+	// Document is the reply document sent to the driver. An "ok" field with the value 1 is added
+	// if the document does not contain one.
+	Document bson.D
+
+	// Err, if set, makes the connection fail with Err while reading the reply instead of sending
+	// Document, which the driver reports as a network error.
+	Err error
+}
+
+// Success returns a successful reply that contains the given fields.
+func Success(fields ...bson.E) Reply {
+	return Reply{Document: append(bson.D{{"ok", 1}}, fields...)}
+}
+
+// CommandError returns a reply that makes the command fail with the given code, code name,
+// message, and error labels, which the driver reports as a mongo.CommandError.
+func CommandError(code int32, codeName, message string, labels ...string) Reply {
+	doc := bson.D{
+		{"ok", 0},
+		{"code", code},
+		{"codeName", codeName},
+		{"errmsg", message},
+	}
+	if len(labels) > 0 {
+		doc = append(doc, bson.E{Key: "errorLabels", Value: labels})
+	}
+	return Reply{Document: doc}
+}
+
+// WriteError describes an error of a single write in a WriteErrors reply.
+type WriteError struct {
+	// Index is the index of the failed write in the documents, updates, or deletes of the
+	// command.
+	Index int
+
+	// Code is the error code of the failed write, e.g. 11000 for a duplicate key error.
+	Code int32
+
+	// Message is the error message of the failed write.
+	Message string
+}
+
+// WriteErrors returns the reply of a write command that applied n writes and failed the given
+// writes, which the driver reports as a mongo.WriteException or mongo.BulkWriteException.
+func WriteErrors(n int, errs ...WriteError) Reply {
+	writeErrors := make(bson.A, 0, len(errs))
+	for _, we := range errs {
+		writeErrors = append(writeErrors, bson.D{
+			{"index", we.Index},
+			{"code", we.Code},
+			{"errmsg", we.Message},
+		})
+	}
+	return Success(
+		bson.E{Key: "n", Value: n},
+		bson.E{Key: "writeErrors", Value: writeErrors},
+	)
+}
+
+// WriteConcernError returns the reply of a write command that applied n writes but failed to
+// satisfy the write concern with the given code, code name, message, and error labels.
+func WriteConcernError(n int, code int32, codeName, message string, labels ...string) Reply {
+	wce := bson.D{
+		{"code", code},
+		{"codeName", codeName},
+		{"errmsg", message},
+	}
+	reply := Success(
+		bson.E{Key: "n", Value: n},
+		bson.E{Key: "writeConcernError", Value: wce},
+	)
+	if len(labels) > 0 {
+		reply.Document = append(reply.Document, bson.E{Key: "errorLabels", Value: labels})
+	}
+	return reply
+}
+
+// NetworkError returns a reply that makes the connection fail while reading the reply, as if the
+// server closed the connection.
+func NetworkError() Reply {
+	return Reply{Err: io.ErrUnexpectedEOF}
+}
+
+// Cursor returns the reply of a command that creates a cursor, such as find or aggregate, with
+// the given namespace, in the form "database.collection", cursor ID, and first batch of
+// documents. An ID of 0 means that the cursor is exhausted.
+func Cursor(ns string, id int64, docs ...interface{}) Reply {
+	return cursorReply("firstBatch", ns, id, docs)
+}
+
+// NextBatch returns the reply of a getMore command with the given namespace, cursor ID, and batch
+// of documents. An ID of 0 means that the cursor is exhausted.
+func NextBatch(ns string, id int64, docs ...interface{}) Reply {
+	return cursorReply("nextBatch", ns, id, docs)
+}
+
+func cursorReply(batchKey, ns string, id int64, docs []interface{}) Reply {
+	batch := make(bson.A, 0, len(docs))
+	batch = append(batch, docs...)
+	return Success(bson.E{Key: "cursor", Value: bson.D{
+		{"id", id},
+		{"ns", ns},
+		{batchKey, batch},
+	}})
+}
@@ -0,0 +1,680 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongotest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
+)
+
+// WireStubAddress is the address of the server of the Clients created by WireStub.NewClient.
+const WireStubAddress = "mongotest.invalid:27017"
+
+// Error codes of the replies of a WireStub.
+const (
+	errorBadValue       = 2
+	errorCursorNotFound = 43
+	errorDuplicateKey   = 11000
+)
+
+// maxWireMessageSize is the size of the largest wire message a WireStub reads.
+const maxWireMessageSize = 48000000
+
+// defaultBatchSize is the number of documents of the first batch of a find without batch size.
+const defaultBatchSize = 101
+
+// Fault describes a fault injected by a WireStub into its replies.
+type Fault struct {
+	// Command is the name of the command the fault applies to. A fault with no command applies to
+	// all commands except hello, which includes the legacy hello commands and must be named
+	// explicitly, so that server monitoring is not affected.
+	Command string
+
+	// Times is the number of commands the fault applies to. A fault with Times 0 applies to all
+	// matching commands until WireStub.ClearFaults is called.
+	Times int
+
+	// Latency delays the reply, or the disconnection or malformed reply.
+	Latency time.Duration
+
+	// Disconnect closes the connection instead of replying.
+	Disconnect bool
+
+	// Malformed sends a reply that is not a valid wire message instead of the reply.
+	Malformed bool
+}
+
+// WireStub is an in-memory server that speaks a subset of the MongoDB wire protocol over
+// in-process connections, to run the whole driver, including server monitoring, the connection
+// handshake, and connection pooling, against it:
+//
+//	stub := mongotest.NewWireStub()
+//	defer stub.Close()
+//	client, err := stub.NewClient()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer client.Disconnect(context.Background())
+//
+//	stub.AddFault(mongotest.Fault{Command: "find", Times: 1, Disconnect: true})
+//	// The find is retried after the disconnection and succeeds.
+//	err = client.Database("db").Collection("coll").FindOne(ctx, bson.D{{"x", 1}}).Err()
+//
+// A WireStub behaves like a standalone server. It stores the inserted documents in memory and
+// supports the hello, ping, insert, find, getMore, killCursors, and endSessions commands. A find
+// filter can only match fields, including those of embedded documents with dotted paths, to
+// values of the same BSON type and encoding; a find with a query operator fails, and the sort and
+// projection are ignored. Other commands fail with a CommandNotFound error unless a handler is
+// set with Handle. Authentication and compression are not supported.
+//
+// A WireStub is safe for concurrent use.
+type WireStub struct {
+	mu          sync.Mutex
+	collections map[string][]bson.Raw // the documents of each namespace, in insertion order
+	cursors     map[int64]*stubCursor
+	handlers    map[string]Handler
+	faults      []Fault
+	commands    []Command
+	conns       map[net.Conn]struct{}
+	closed      bool
+
+	cursorID     int64
+	connectionID int64
+}
+
+type stubCursor struct {
+	ns   string
+	docs []bson.Raw // the remaining documents
+}
+
+// NewWireStub creates a WireStub with no documents.
+func NewWireStub() *WireStub {
+	return &WireStub{
+		collections: make(map[string][]bson.Raw),
+		cursors:     make(map[int64]*stubCursor),
+		handlers:    make(map[string]Handler),
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// ConnFactory returns a ConnFactory that creates in-process connections to s.
+func (s *WireStub) ConnFactory() options.ConnFactory {
+	return options.PipeConnFactory(func(conn net.Conn, _ string) {
+		s.serve(conn)
+	})
+}
+
+// NewClient creates a Client with the given options that connects directly to s at
+// WireStubAddress. The hosts, the direct connection, and the ConnFactory options are overridden.
+func (s *WireStub) NewClient(opts ...options.Lister[options.ClientOptions]) (*mongo.Client, error) {
+	stub := options.Client().
+		SetHosts([]string{WireStubAddress}).
+		SetDirect(true).
+		SetConnFactory(s.ConnFactory())
+	return mongo.Connect(append(opts, stub)...)
+}
+
+// Insert adds docs to the collection coll of the database db. An ObjectID _id is added to the
+// documents that do not have one.
+func (s *WireStub) Insert(db, coll string, docs ...interface{}) error {
+	raws := make([]bson.Raw, 0, len(docs))
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		raws = append(raws, ensureID(raw))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := db + "." + coll
+	s.collections[ns] = append(s.collections[ns], raws...)
+	return nil
+}
+
+// Documents returns the documents of the collection coll of the database db, in insertion order.
+func (s *WireStub) Documents(db, coll string) []bson.Raw {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]bson.Raw(nil), s.collections[db+"."+coll]...)
+}
+
+// Handle sets the handler that computes the replies to the commands with the given name, instead
+// of the command supported by s if any. A reply with an error closes the connection. A nil
+// handler removes the handler of the command.
+func (s *WireStub) Handle(command string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if h == nil {
+		delete(s.handlers, command)
+		return
+	}
+	s.handlers[command] = h
+}
+
+// AddFault adds a fault to the replies of s. When a command matches several faults, the first
+// added applies.
+func (s *WireStub) AddFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, f)
+}
+
+// ClearFaults removes the faults of s.
+func (s *WireStub) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Commands returns the commands received by s with the given names, or all received commands if
+// no name is given, in the order they were received. The commands include those of server
+// monitoring and the connection handshake.
+func (s *WireStub) Commands(names ...string) []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmds := make([]Command, 0, len(s.commands))
+	for _, cmd := range s.commands {
+		if len(names) == 0 || containsName(names, cmd.Name) {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// Close closes the connections to s. The connections created after Close are closed immediately.
+func (s *WireStub) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+		delete(s.conns, conn)
+	}
+	return nil
+}
+
+// serve replies to the commands received on conn until it is closed.
+func (s *WireStub) serve(conn net.Conn) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+	connectionID := atomic.AddInt64(&s.connectionID, 1)
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		wm, err := readWireMessage(conn)
+		if err != nil {
+			return
+		}
+		if err := s.handleWireMessage(conn, connectionID, wm); err != nil {
+			return
+		}
+	}
+}
+
+// readWireMessage reads the next wire message from r.
+func readWireMessage(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	length := int32(binary.LittleEndian.Uint32(size[:]))
+	if length < 16 || length > maxWireMessageSize {
+		return nil, fmt.Errorf("mongotest: invalid wire message length %d", length)
+	}
+	wm := make([]byte, length)
+	copy(wm, size[:])
+	if _, err := io.ReadFull(r, wm[4:]); err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// handleWireMessage replies to the command in wm on conn. It returns an error if conn must be
+// closed.
+func (s *WireStub) handleWireMessage(conn net.Conn, connectionID int64, wm []byte) error {
+	_, requestID, _, opcode, _, _ := wiremessage.ReadHeader(wm)
+
+	var cmd Command
+	var flags wiremessage.MsgFlag
+	var err error
+	switch opcode {
+	case wiremessage.OpQuery:
+		cmd, err = parseQuery(wm)
+	default:
+		_, flags, cmd, err = parseCommand(wm)
+	}
+	if err != nil {
+		return err
+	}
+
+	reply := s.reply(cmd, connectionID)
+	if flags&wiremessage.MoreToCome != 0 {
+		return nil
+	}
+
+	if f, ok := s.fault(cmd.Name); ok {
+		if f.Latency > 0 {
+			time.Sleep(f.Latency)
+		}
+		switch {
+		case f.Disconnect:
+			return errors.New("mongotest: disconnect fault")
+		case f.Malformed:
+			_, err := conn.Write(malformedReply(requestID))
+			return err
+		}
+	}
+	if reply.Err != nil {
+		return reply.Err
+	}
+
+	var out []byte
+	if opcode == wiremessage.OpQuery {
+		out, err = appendQueryReply(nil, requestID, reply.Document)
+	} else {
+		out, err = appendReply(nil, requestID, reply.Document)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(out)
+	return err
+}
+
+// fault returns the fault that applies to the command with the given name, if any, and consumes
+// it.
+func (s *WireStub) fault(name string) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hello := isHello(name)
+	for i, f := range s.faults {
+		if f.Command == "" && hello || f.Command != "" && f.Command != name && !(hello && f.Command == "hello") {
+			continue
+		}
+		if f.Times > 0 {
+			s.faults[i].Times--
+			if s.faults[i].Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// malformedReply returns a reply to the command with the request ID responseTo whose section
+// claims to be longer than the message.
+func malformedReply(responseTo int32) []byte {
+	wmindex, dst := wiremessage.AppendHeaderStart(nil, wiremessage.NextRequestID(), responseTo, wiremessage.OpMsg)
+	dst = wiremessage.AppendMsgFlags(dst, 0)
+	dst = wiremessage.AppendMsgSectionType(dst, wiremessage.SingleDocument)
+	dst = append(dst, 0xff, 0xff, 0x00, 0x00, 0x00)
+	return bsoncore.UpdateLength(dst, wmindex, int32(len(dst[wmindex:])))
+}
+
+// parseQuery returns the command of an OP_QUERY wire message, which the driver only sends for
+// the legacy hello of the connection handshake.
+func parseQuery(wm []byte) (Command, error) {
+	errMalformed := errors.New("mongotest: malformed OP_QUERY wire message")
+
+	_, _, _, _, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	_, rem, ok = wiremessage.ReadQueryFlags(rem)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	collName, rem, ok := wiremessage.ReadQueryFullCollectionName(rem)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	_, rem, ok = wiremessage.ReadQueryNumberToSkip(rem)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	_, rem, ok = wiremessage.ReadQueryNumberToReturn(rem)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	query, _, ok := wiremessage.ReadQueryQuery(rem)
+	if !ok {
+		return Command{}, errMalformed
+	}
+	if wrapped, ok := query.Lookup("$query").DocumentOK(); ok {
+		query = wrapped
+	}
+	elems, err := query.Elements()
+	if err != nil || len(elems) == 0 {
+		return Command{}, errMalformed
+	}
+	return Command{
+		Name:     elems[0].Key(),
+		Database: strings.TrimSuffix(collName, ".$cmd"),
+		Document: bson.Raw(query),
+	}, nil
+}
+
+// appendQueryReply appends the OP_REPLY reply document doc to the command with the request ID
+// responseTo to dst.
+func appendQueryReply(dst []byte, responseTo int32, doc bson.D) ([]byte, error) {
+	if !hasKey(doc, "ok") {
+		doc = append(bson.D{{"ok", 1}}, doc...)
+	}
+	resBytes, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("mongotest: error marshaling reply: %w", err)
+	}
+
+	var wmindex int32
+	wmindex, dst = wiremessage.AppendHeaderStart(dst, wiremessage.NextRequestID(), responseTo, wiremessage.OpReply)
+	dst = wiremessage.AppendReplyFlags(dst, 0)
+	dst = wiremessage.AppendReplyCursorID(dst, 0)
+	dst = wiremessage.AppendReplyStartingFrom(dst, 0)
+	dst = wiremessage.AppendReplyNumberReturned(dst, 1)
+	dst = append(dst, resBytes...)
+	return bsoncore.UpdateLength(dst, wmindex, int32(len(dst[wmindex:]))), nil
+}
+
+func isHello(name string) bool {
+	return name == "hello" || name == "isMaster" || name == "ismaster"
+}
+
+// reply records cmd and returns its reply.
+func (s *WireStub) reply(cmd Command, connectionID int64) Reply {
+	s.mu.Lock()
+	s.commands = append(s.commands, cmd)
+	h := s.handlers[cmd.Name]
+	s.mu.Unlock()
+
+	if h != nil {
+		return h(cmd)
+	}
+	switch {
+	case isHello(cmd.Name):
+		return helloReply(connectionID)
+	case cmd.Name == "ping", cmd.Name == "endSessions":
+		return Success()
+	case cmd.Name == "insert":
+		return s.insert(cmd)
+	case cmd.Name == "find":
+		return s.find(cmd)
+	case cmd.Name == "getMore":
+		return s.getMore(cmd)
+	case cmd.Name == "killCursors":
+		return s.killCursors(cmd)
+	}
+	return CommandError(errorCommandNotFound, "CommandNotFound",
+		fmt.Sprintf("mongotest: unsupported command %q", cmd.Name))
+}
+
+// helloReply returns the reply to a hello command of a standalone server. It has no topology
+// version so that the driver polls the server instead of streaming hello replies.
+func helloReply(connectionID int64) Reply {
+	return Success(
+		bson.E{Key: "helloOk", Value: true},
+		bson.E{Key: "isWritablePrimary", Value: true},
+		bson.E{Key: "ismaster", Value: true},
+		bson.E{Key: "maxBsonObjectSize", Value: int32(16 * 1024 * 1024)},
+		bson.E{Key: "maxMessageSizeBytes", Value: int32(maxWireMessageSize)},
+		bson.E{Key: "maxWriteBatchSize", Value: int32(100000)},
+		bson.E{Key: "localTime", Value: time.Now()},
+		bson.E{Key: "logicalSessionTimeoutMinutes", Value: int32(30)},
+		bson.E{Key: "connectionId", Value: connectionID},
+		bson.E{Key: "minWireVersion", Value: int32(0)},
+		bson.E{Key: "maxWireVersion", Value: driverutil.MaxWireVersion},
+	)
+}
+
+func (s *WireStub) insert(cmd Command) Reply {
+	coll, _ := cmd.Document.Lookup("insert").StringValueOK()
+	docs, ok := cmd.Document.Lookup("documents").ArrayOK()
+	if !ok {
+		return CommandError(errorBadValue, "BadValue", "mongotest: insert requires documents")
+	}
+	values, err := docs.Values()
+	if err != nil {
+		return CommandError(errorBadValue, "BadValue", err.Error())
+	}
+	ordered := true
+	if o, ok := cmd.Document.Lookup("ordered").BooleanOK(); ok {
+		ordered = o
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := cmd.Database + "." + coll
+	var n int
+	var writeErrors []WriteError
+	for i, v := range values {
+		doc, ok := v.DocumentOK()
+		if !ok {
+			return CommandError(errorBadValue, "BadValue", "mongotest: insert documents must be documents")
+		}
+		doc = ensureID(doc)
+		if s.hasID(ns, doc.Lookup("_id")) {
+			writeErrors = append(writeErrors, WriteError{
+				Index:   i,
+				Code:    errorDuplicateKey,
+				Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: _id_", ns),
+			})
+			if ordered {
+				break
+			}
+			continue
+		}
+		s.collections[ns] = append(s.collections[ns], doc)
+		n++
+	}
+	if len(writeErrors) > 0 {
+		return WriteErrors(n, writeErrors...)
+	}
+	return Success(bson.E{Key: "n", Value: n})
+}
+
+// hasID returns whether the namespace ns has a document with the given _id.
+func (s *WireStub) hasID(ns string, id bson.RawValue) bool {
+	for _, doc := range s.collections[ns] {
+		if doc.Lookup("_id").Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WireStub) find(cmd Command) Reply {
+	coll, _ := cmd.Document.Lookup("find").StringValueOK()
+	filter, _ := cmd.Document.Lookup("filter").DocumentOK()
+	if err := validateFilter(filter); err != nil {
+		return CommandError(errorBadValue, "BadValue", err.Error())
+	}
+	skip := intValue(cmd.Document.Lookup("skip"))
+	limit := intValue(cmd.Document.Lookup("limit"))
+	batchSize := defaultBatchSize
+	if _, err := cmd.Document.LookupErr("batchSize"); err == nil {
+		batchSize = intValue(cmd.Document.Lookup("batchSize"))
+	}
+	singleBatch, _ := cmd.Document.Lookup("singleBatch").BooleanOK()
+	if limit < 0 {
+		limit, singleBatch = -limit, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := cmd.Database + "." + coll
+	var docs []bson.Raw
+	for _, doc := range s.collections[ns] {
+		if matches(doc, filter) {
+			docs = append(docs, doc)
+		}
+	}
+	if skip >= len(docs) {
+		docs = nil
+	} else {
+		docs = docs[skip:]
+	}
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	if singleBatch && batchSize > 0 && batchSize < len(docs) {
+		docs = docs[:batchSize]
+	}
+	return s.batch(ns, "firstBatch", 0, batchSize, docs)
+}
+
+func (s *WireStub) getMore(cmd Command) Reply {
+	id, _ := cmd.Document.Lookup("getMore").Int64OK()
+	batchSize := intValue(cmd.Document.Lookup("batchSize"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.cursors[id]
+	if !ok {
+		return CommandError(errorCursorNotFound, "CursorNotFound", fmt.Sprintf("cursor id %d not found", id))
+	}
+	delete(s.cursors, id)
+	return s.batch(c.ns, "nextBatch", id, batchSize, c.docs)
+}
+
+// batch returns the reply with the next batch of docs, the documents of the cursor with the given
+// ID. It stores the remaining documents in a cursor, which is a new cursor if id is zero.
+func (s *WireStub) batch(ns, field string, id int64, batchSize int, docs []bson.Raw) Reply {
+	if batchSize <= 0 || batchSize > len(docs) {
+		batchSize = len(docs)
+	}
+	batch := make(bson.A, 0, batchSize)
+	for _, doc := range docs[:batchSize] {
+		batch = append(batch, doc)
+	}
+	if rem := docs[batchSize:]; len(rem) > 0 {
+		if id == 0 {
+			s.cursorID++
+			id = s.cursorID
+		}
+		s.cursors[id] = &stubCursor{ns: ns, docs: rem}
+	} else {
+		id = 0
+	}
+	return Success(bson.E{Key: "cursor", Value: bson.D{
+		{"id", id},
+		{"ns", ns},
+		{field, batch},
+	}})
+}
+
+func (s *WireStub) killCursors(cmd Command) Reply {
+	ids, _ := cmd.Document.Lookup("cursors").ArrayOK()
+	values, _ := ids.Values()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	killed, notFound := bson.A{}, bson.A{}
+	for _, v := range values {
+		id, _ := v.Int64OK()
+		if _, ok := s.cursors[id]; ok {
+			delete(s.cursors, id)
+			killed = append(killed, id)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+	return Success(
+		bson.E{Key: "cursorsKilled", Value: killed},
+		bson.E{Key: "cursorsNotFound", Value: notFound},
+	)
+}
+
+// validateFilter returns an error if filter contains a query operator.
+func validateFilter(filter bson.Raw) error {
+	elems, _ := filter.Elements()
+	for _, elem := range elems {
+		if strings.HasPrefix(elem.Key(), "$") {
+			return fmt.Errorf("mongotest: unsupported query operator %q", elem.Key())
+		}
+		doc, ok := elem.Value().DocumentOK()
+		if !ok {
+			continue
+		}
+		if first, err := doc.IndexErr(0); err == nil && strings.HasPrefix(first.Key(), "$") {
+			return fmt.Errorf("mongotest: unsupported query operator %q", first.Key())
+		}
+	}
+	return nil
+}
+
+// matches returns whether the fields of doc at the paths of the keys of filter equal the values
+// of filter.
+func matches(doc, filter bson.Raw) bool {
+	elems, _ := filter.Elements()
+	for _, elem := range elems {
+		val, err := doc.LookupErr(strings.Split(elem.Key(), ".")...)
+		if err != nil || !val.Equal(elem.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+// intValue returns the integer value of val, or zero if val is not a number.
+func intValue(val bson.RawValue) int {
+	if i, ok := val.AsInt64OK(); ok {
+		return int(i)
+	}
+	return 0
+}
+
+// ensureID returns doc with an ObjectID _id prepended if it does not have an _id.
+func ensureID(doc bson.Raw) bson.Raw {
+	if _, err := doc.LookupErr("_id"); err == nil {
+		return doc
+	}
+	idx, dst := bsoncore.AppendDocumentStart(nil)
+	dst = bsoncore.AppendObjectIDElement(dst, "_id", bson.NewObjectID())
+	dst = append(dst, doc[4:len(doc)-1]...)
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+	return bson.Raw(dst)
+}
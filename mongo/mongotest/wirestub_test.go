@@ -0,0 +1,164 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func newTestWireStubClient(t *testing.T, s *WireStub, opts ...options.Lister[options.ClientOptions]) *mongo.Client {
+	t.Helper()
+
+	client, err := s.NewClient(opts...)
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+		_ = s.Close()
+	})
+	return client
+}
+
+func TestWireStub(t *testing.T) {
+	t.Parallel()
+
+	t.Run("insert and find", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		client := newTestWireStubClient(t, s)
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+
+		coll := client.Database("db").Collection("coll")
+		docs := make([]interface{}, 0, 5)
+		for i := 0; i < 5; i++ {
+			docs = append(docs, bson.D{{"_id", i}, {"x", bson.D{{"even", i%2 == 0}}}})
+		}
+		_, err := coll.InsertMany(context.Background(), docs)
+		require.NoError(t, err, "InsertMany error")
+		assert.Len(t, s.Documents("db", "coll"), 5, "expected the documents to be stored")
+
+		cursor, err := coll.Find(context.Background(), bson.D{{"x.even", true}}, options.Find().SetBatchSize(1))
+		require.NoError(t, err, "Find error")
+		var got []bson.M
+		require.NoError(t, cursor.All(context.Background(), &got), "All error")
+		require.Len(t, got, 3, "expected the even documents")
+		assert.Equal(t, int32(4), got[2]["_id"], "expected the documents in insertion order")
+		assert.Len(t, s.Commands("getMore"), 2, "expected the remaining documents to be fetched with getMore")
+
+		_, err = coll.InsertOne(context.Background(), bson.D{{"_id", 0}})
+		var we mongo.WriteException
+		require.True(t, errors.As(err, &we), "expected WriteException, got %v", err)
+		assert.True(t, mongo.IsDuplicateKeyError(err), "expected duplicate key error, got %v", err)
+
+		err = coll.FindOne(context.Background(), bson.D{{"_id", bson.D{{"$gt", 1}}}}).Err()
+		var ce mongo.CommandError
+		require.True(t, errors.As(err, &ce), "expected CommandError, got %v", err)
+		assert.Equal(t, int32(errorBadValue), ce.Code, "code mismatch")
+	})
+
+	t.Run("seeded documents", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		require.NoError(t, s.Insert("db", "coll", bson.D{{"name", "a"}}, bson.D{{"name", "b"}}), "Insert error")
+		coll := newTestWireStubClient(t, s).Database("db").Collection("coll")
+
+		var got bson.Raw
+		require.NoError(t, coll.FindOne(context.Background(), bson.D{{"name", "b"}}).Decode(&got), "Decode error")
+		_, ok := got.Lookup("_id").ObjectIDOK()
+		assert.True(t, ok, "expected an ObjectID _id to be added")
+
+		err := coll.FindOne(context.Background(), bson.D{{"name", "c"}}).Err()
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments, "expected no documents")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		s.Handle("count", func(Command) Reply {
+			return Success(bson.E{Key: "n", Value: 42})
+		})
+		db := newTestWireStubClient(t, s).Database("db")
+
+		var res struct{ N int }
+		require.NoError(t, db.RunCommand(context.Background(), bson.D{{"count", "coll"}}).Decode(&res),
+			"RunCommand error")
+		assert.Equal(t, 42, res.N, "handler reply mismatch")
+
+		err := db.RunCommand(context.Background(), bson.D{{"distinct", "coll"}}).Err()
+		var ce mongo.CommandError
+		require.True(t, errors.As(err, &ce), "expected CommandError, got %v", err)
+		assert.Equal(t, int32(errorCommandNotFound), ce.Code, "code mismatch")
+	})
+
+	t.Run("disconnect fault", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		require.NoError(t, s.Insert("db", "coll", bson.D{{"_id", 1}}), "Insert error")
+		coll := newTestWireStubClient(t, s).Database("db").Collection("coll")
+		require.NoError(t, coll.Database().Client().Ping(context.Background(), nil), "Ping error")
+
+		s.AddFault(Fault{Command: "find", Times: 1, Disconnect: true})
+		require.NoError(t, coll.FindOne(context.Background(), bson.D{}).Err(), "FindOne error")
+		assert.Len(t, s.Commands("find"), 2, "expected the find to be retried")
+	})
+
+	t.Run("disconnect fault without retries", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		client := newTestWireStubClient(t, s, options.Client().SetRetryReads(false))
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+
+		s.AddFault(Fault{Command: "find", Times: 1, Disconnect: true})
+		err := client.Database("db").Collection("coll").FindOne(context.Background(), bson.D{}).Err()
+		assert.True(t, mongo.IsNetworkError(err), "expected network error, got %v", err)
+	})
+
+	t.Run("malformed fault", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		client := newTestWireStubClient(t, s, options.Client().SetRetryReads(false))
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+
+		s.AddFault(Fault{Command: "ping", Times: 1, Malformed: true})
+		assert.Error(t, client.Ping(context.Background(), nil), "expected an error for a malformed reply")
+	})
+
+	t.Run("latency fault", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewWireStub()
+		client := newTestWireStubClient(t, s)
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+
+		s.AddFault(Fault{Command: "ping", Latency: 200 * time.Millisecond})
+		start := time.Now()
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "expected the reply to be delayed")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := client.Ping(ctx, nil)
+		assert.True(t, mongo.IsTimeout(err), "expected timeout error, got %v", err)
+
+		s.ClearFaults()
+		require.NoError(t, client.Ping(context.Background(), nil), "Ping error")
+	})
+}
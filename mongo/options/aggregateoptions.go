@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 )
 
 // AggregateOptions represents arguments that can be used to configure an
@@ -26,6 +28,9 @@ type AggregateOptions struct {
 	Hint                     interface{}
 	Let                      interface{}
 	Custom                   bson.M
+	ReadConcern              *readconcern.ReadConcern
+	WriteConcern             *writeconcern.WriteConcern
+	LocalThreshold           *time.Duration
 }
 
 // AggregateOptionsBuilder contains options to configure aggregate operations.
@@ -166,3 +171,46 @@ func (ao *AggregateOptionsBuilder) SetCustom(c bson.M) *AggregateOptionsBuilder
 
 	return ao
 }
+
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (ao *AggregateOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return ao
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (ao *AggregateOptionsBuilder) SetLocalThreshold(d time.Duration) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return ao
+}
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for a pipeline with an $out or $merge stage instead of the write concern of the Collection. It cannot
+// be set for an operation that runs in a transaction. The default value is nil, which means that the
+// write concern of the Collection will be used.
+func (ao *AggregateOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return ao
+}
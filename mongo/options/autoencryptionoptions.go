@@ -7,8 +7,12 @@
 package options
 
 import (
+	"context"
 	"crypto/tls"
 	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
 )
 
 // AutoEncryptionOptions represents arguments used to configure auto encryption/decryption behavior for a mongo.Client
@@ -28,18 +32,29 @@ import (
 //
 // See corresponding setter methods for documentation.
 type AutoEncryptionOptions struct {
-	KeyVaultClientOptions Lister[ClientOptions]
-	KeyVaultNamespace     string
-	KmsProviders          map[string]map[string]interface{}
-	SchemaMap             map[string]interface{}
-	BypassAutoEncryption  *bool
-	ExtraOptions          map[string]interface{}
-	TLSConfig             map[string]*tls.Config
-	HTTPClient            *http.Client
-	EncryptedFieldsMap    map[string]interface{}
-	BypassQueryAnalysis   *bool
+	KeyVaultClientOptions  Lister[ClientOptions]
+	KeyVaultNamespace      string
+	KmsProviders           map[string]map[string]interface{}
+	SchemaMap              map[string]interface{}
+	BypassAutoEncryption   *bool
+	ExtraOptions           map[string]interface{}
+	TLSConfig              map[string]*tls.Config
+	HTTPClient             *http.Client
+	EncryptedFieldsMap     map[string]interface{}
+	BypassQueryAnalysis    *bool
+	CryptSharedLibRequired *bool
+	QueryAnalysisMonitor   *event.QueryAnalysisMonitor
+	KeyAltNameResolver     func(ctx context.Context) (string, error)
+	KeyExpiration          *time.Duration
+	KeyCacheMaxEntries     *int
+	KeyCacheRefreshBefore  *time.Duration
 }
 
+// KeyAltNamePlaceholder can be used as the "keyId" of an encrypted field in the SchemaMap or EncryptedFieldsMap
+// documents of a Client configured with SetKeyAltNameResolver. For each resolved key alternate name, it is replaced
+// with the _id of the data key with that alternate name.
+const KeyAltNamePlaceholder = "$$keyAltName"
+
 // AutoEncryptionOptionsBuilder contains options to configure automatic
 // encryption for operations. Each option can be set through setter functions.
 // See documentation for each setter function for an explanation of the option.
@@ -225,3 +240,93 @@ func (a *AutoEncryptionOptionsBuilder) SetBypassQueryAnalysis(bypass bool) *Auto
 
 	return a
 }
+
+// SetCryptSharedLibRequired specifies whether Client creation should return an error if the crypt_shared
+// library cannot be loaded. If true, the Client will never fall back to spawning or connecting to
+// mongocryptd. This takes precedence over the "cryptSharedLibRequired" extra option. The default is false.
+func (a *AutoEncryptionOptionsBuilder) SetCryptSharedLibRequired(required bool) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.CryptSharedLibRequired = &required
+
+		return nil
+	})
+
+	return a
+}
+
+// SetQueryAnalysisMonitor specifies a monitor that is notified when the crypt_shared library is loaded
+// and whenever the Client spawns or fails to spawn mongocryptd.
+func (a *AutoEncryptionOptionsBuilder) SetQueryAnalysisMonitor(monitor *event.QueryAnalysisMonitor) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.QueryAnalysisMonitor = monitor
+
+		return nil
+	})
+
+	return a
+}
+
+// SetKeyAltNameResolver specifies a function that resolves the key alternate name used to automatically encrypt the
+// fields whose "keyId" is KeyAltNamePlaceholder in the SchemaMap and EncryptedFieldsMap. It is called with the context
+// of each operation that is automatically encrypted, so multi-tenant applications can encrypt the documents of each
+// tenant with the tenant's data key, identified for example by a tenant ID stored in the context, using a single
+// schema for all tenants.
+//
+// The data key with the resolved alternate name must exist in the key vault collection. If the function returns an
+// empty name, the operation is encrypted with the schemas as given, which fails for fields that use the placeholder.
+// Decryption does not use the resolver, since encrypted values identify their data keys. The Client keeps the
+// encryption state of each resolved name until it is disconnected.
+func (a *AutoEncryptionOptionsBuilder) SetKeyAltNameResolver(fn func(ctx context.Context) (string, error)) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.KeyAltNameResolver = fn
+
+		return nil
+	})
+
+	return a
+}
+
+// SetKeyExpiration specifies how long the data keys used for automatic encryption and decryption are cached after they
+// are fetched from the key vault collection and decrypted with their KMS provider. A value of 0 caches the keys until
+// the Client is disconnected. Setting this option requires libmongocrypt 1.11.0 or later. The default is 60 seconds.
+func (a *AutoEncryptionOptionsBuilder) SetKeyExpiration(d time.Duration) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.KeyExpiration = &d
+
+		return nil
+	})
+
+	return a
+}
+
+// SetKeyCacheMaxEntries specifies the maximum number of key vault documents kept by the key document cache of the
+// Client. When the cache is full, the least recently used document is evicted. Setting this option enables the key
+// document cache, see SetKeyCacheRefreshBefore. The default is 0, which does not limit the number of documents.
+func (a *AutoEncryptionOptionsBuilder) SetKeyCacheMaxEntries(n int) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.KeyCacheMaxEntries = &n
+
+		return nil
+	})
+
+	return a
+}
+
+// SetKeyCacheRefreshBefore specifies how long before they expire the documents of the key document cache are fetched
+// again from the key vault collection in the background, so that operations do not wait for the key vault when the
+// cached data keys expire. Documents that have not been used since they were last fetched are not refreshed and are
+// evicted when they expire.
+//
+// Setting this option or SetKeyCacheMaxEntries enables the key document cache, which keeps the key vault documents
+// fetched by the Client for the key expiration (see SetKeyExpiration) and serves them when the data keys have to be
+// decrypted again. Decrypting a data key still requires a request to its KMS provider, unless it is a "local" key. Use
+// Client.KeyCacheStats to monitor the cache. The default is 0, which does not refresh documents in the background.
+func (a *AutoEncryptionOptionsBuilder) SetKeyCacheRefreshBefore(d time.Duration) *AutoEncryptionOptionsBuilder {
+	a.Opts = append(a.Opts, func(args *AutoEncryptionOptions) error {
+		args.KeyCacheRefreshBefore = &d
+
+		return nil
+	})
+
+	return a
+}
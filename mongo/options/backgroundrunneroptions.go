@@ -0,0 +1,112 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "time"
+
+// BackgroundRunnerOptions represents arguments that can be used to configure a mongo.BackgroundRunner.
+//
+// See corresponding setter methods for documentation.
+type BackgroundRunnerOptions struct {
+	OpsPerSecond  *float64
+	Concurrency   *int
+	QueueSize     *int
+	RTTThreshold  *time.Duration
+	RTTFunc       func() time.Duration
+	PauseInterval *time.Duration
+	ErrorHandler  func(error)
+}
+
+// BackgroundRunnerOptionsBuilder contains options to configure a mongo.BackgroundRunner. Each
+// option can be set through setter functions. See documentation for each setter function for an
+// explanation of the option.
+type BackgroundRunnerOptionsBuilder struct {
+	Opts []func(*BackgroundRunnerOptions) error
+}
+
+// BackgroundRunner creates a new BackgroundRunnerOptionsBuilder instance.
+func BackgroundRunner() *BackgroundRunnerOptionsBuilder {
+	return &BackgroundRunnerOptionsBuilder{}
+}
+
+// List returns a list of BackgroundRunnerOptions setter functions.
+func (b *BackgroundRunnerOptionsBuilder) List() []func(*BackgroundRunnerOptions) error {
+	return b.Opts
+}
+
+// SetOpsPerSecond sets the value for the OpsPerSecond field. Specifies the maximum rate at which
+// queued operations are started. A value of 0 means there is no rate limit. The default value is 0.
+func (b *BackgroundRunnerOptionsBuilder) SetOpsPerSecond(ops float64) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.OpsPerSecond = &ops
+		return nil
+	})
+	return b
+}
+
+// SetConcurrency sets the value for the Concurrency field. Specifies the maximum number of queued
+// operations that run at the same time. The default value is 1.
+func (b *BackgroundRunnerOptionsBuilder) SetConcurrency(n int) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.Concurrency = &n
+		return nil
+	})
+	return b
+}
+
+// SetQueueSize sets the value for the QueueSize field. Specifies the number of operations that can
+// be queued before Submit blocks. The default value is 1000.
+func (b *BackgroundRunnerOptionsBuilder) SetQueueSize(n int) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.QueueSize = &n
+		return nil
+	})
+	return b
+}
+
+// SetRTTThreshold sets the value for the RTTThreshold field. If the round trip time reported by the
+// RTT function exceeds this value, the runner pauses and does not start new operations until the
+// round trip time falls back below it. A value of 0 disables pausing. The default value is 0.
+func (b *BackgroundRunnerOptionsBuilder) SetRTTThreshold(d time.Duration) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.RTTThreshold = &d
+		return nil
+	})
+	return b
+}
+
+// SetRTTFunc sets the value for the RTTFunc field. Specifies a function that reports the round trip
+// time compared against RTTThreshold. The default reports the highest average round trip time of
+// the heartbeats of the servers known to the Client, which reflects the load of the servers and the
+// network but not the duration of the operations run by the application.
+func (b *BackgroundRunnerOptionsBuilder) SetRTTFunc(fn func() time.Duration) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.RTTFunc = fn
+		return nil
+	})
+	return b
+}
+
+// SetPauseInterval sets the value for the PauseInterval field. Specifies how often a paused runner
+// checks whether the round trip time has recovered. The default value is 1 second.
+func (b *BackgroundRunnerOptionsBuilder) SetPauseInterval(d time.Duration) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.PauseInterval = &d
+		return nil
+	})
+	return b
+}
+
+// SetErrorHandler sets the value for the ErrorHandler field. Specifies a function that is called
+// with the error returned by each operation that fails. By default, errors are discarded.
+func (b *BackgroundRunnerOptionsBuilder) SetErrorHandler(fn func(error)) *BackgroundRunnerOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BackgroundRunnerOptions) error {
+		opts.ErrorHandler = fn
+		return nil
+	})
+	return b
+}
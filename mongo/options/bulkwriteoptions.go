@@ -6,6 +6,8 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+
 // DefaultOrdered is the default value for the Ordered option in BulkWriteOptions.
 var DefaultOrdered = true
 
@@ -18,6 +20,7 @@ type BulkWriteOptions struct {
 	Comment                  interface{}
 	Ordered                  *bool
 	Let                      interface{}
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // BulkWriteOptionsBuilder contains options to configure bulk write operations.
@@ -93,3 +96,17 @@ func (b *BulkWriteOptionsBuilder) SetLet(let interface{}) *BulkWriteOptionsBuild
 
 	return b
 }
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (b *BulkWriteOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *BulkWriteOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BulkWriteOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return b
+}
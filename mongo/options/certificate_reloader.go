@@ -0,0 +1,135 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertificateReloader provides a client certificate that is read again from its files when they
+// change, so that short-lived certificates, such as SPIFFE X.509 SVIDs, can be rotated without
+// recreating the Client. Set its GetClientCertificate method as the GetClientCertificate field of
+// a tls.Config:
+//
+//	reloader, err := options.NewCertificateReloader("/run/svid/cert.pem", "/run/svid/key.pem", "")
+//	if err != nil {
+//		return err
+//	}
+//	tlsConfig := &tls.Config{GetClientCertificate: reloader.GetClientCertificate}
+//	opts := options.Client().ApplyURI(uri).SetTLSConfig(tlsConfig)
+//
+// The certificate is only read when a TLS connection is established, so new connections use the
+// current certificate while established connections keep using the certificate they were created
+// with until they are closed, e.g. because they have been idle for longer than the max connection
+// idle time.
+//
+// The files are checked for changes of their modification time and size each time a certificate
+// is requested. If a changed file cannot be loaded, e.g. because it is being written, the last
+// certificate is used until it expires and the files are loaded again on the next request.
+type CertificateReloader struct {
+	certFile    string
+	keyFile     string
+	keyPassword string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	versions [2]fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewCertificateReloader creates a CertificateReloader for a PEM-encoded certificate and private
+// key. If keyFile is empty, certFile must contain both the certificate and the private key, as
+// with the "tlsCertificateKeyFile" URI option. keyPassword is the password of an encrypted
+// private key, or "" if the key is not encrypted. NewCertificateReloader returns an error if the
+// certificate cannot be loaded.
+func NewCertificateReloader(certFile, keyFile, keyPassword string) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		certFile:    certFile,
+		keyFile:     keyFile,
+		keyPassword: keyPassword,
+	}
+	if _, err := r.GetClientCertificate(nil); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate returns the current certificate, reloading it if its files changed. It
+// implements the GetClientCertificate function of a tls.Config.
+func (r *CertificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions, err := r.fileVersions()
+	if err == nil && r.cert != nil && versions == r.versions {
+		return r.cert, nil
+	}
+	if err == nil {
+		var cert *tls.Certificate
+		if cert, err = r.load(); err == nil {
+			r.cert = cert
+			r.versions = versions
+			return cert, nil
+		}
+	}
+
+	// Keep using the last certificate while the files cannot be loaded, unless it expired.
+	if r.cert != nil && r.cert.Leaf != nil && time.Now().Before(r.cert.Leaf.NotAfter) {
+		return r.cert, nil
+	}
+	return nil, err
+}
+
+// fileVersions returns the versions of the certificate and key files.
+func (r *CertificateReloader) fileVersions() ([2]fileVersion, error) {
+	var versions [2]fileVersion
+	for i, file := range []string{r.certFile, r.keyFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return versions, err
+		}
+		versions[i] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+	}
+	return versions, nil
+}
+
+// load reads the certificate from its files.
+func (r *CertificateReloader) load() (*tls.Certificate, error) {
+	cfg := new(tls.Config)
+	var err error
+	if r.keyFile == "" {
+		_, err = addClientCertFromConcatenatedFile(cfg, r.certFile, r.keyPassword)
+	} else {
+		_, err = addClientCertFromSeparateFiles(cfg, r.keyFile, r.certFile, r.keyPassword)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Certificates) == 0 {
+		return nil, errors.New("failed to load the client certificate")
+	}
+
+	cert := cfg.Certificates[0]
+	if cert.Leaf == nil && len(cert.Certificate) > 0 {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, err
+		}
+	}
+	return &cert, nil
+}
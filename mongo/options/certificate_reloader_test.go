@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// writeCertificate writes a self-signed certificate with the given common name and its private
+// key to file, and sets the modification time of file to modTime.
+func writeCertificate(t *testing.T, file, commonName string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey error")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err, "CreateCertificate error")
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err, "MarshalECPrivateKey error")
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})...)
+	require.NoError(t, os.WriteFile(file, data, 0o600), "WriteFile error")
+	require.NoError(t, os.Chtimes(file, modTime, modTime), "Chtimes error")
+}
+
+func TestCertificateReloader(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "client.pem")
+	now := time.Now()
+	writeCertificate(t, file, "first", now)
+
+	reloader, err := NewCertificateReloader(file, "", "")
+	require.NoError(t, err, "NewCertificateReloader error")
+	cert, err := reloader.GetClientCertificate(nil)
+	require.NoError(t, err, "GetClientCertificate error")
+	assert.Equal(t, "first", cert.Leaf.Subject.CommonName, "expected the first certificate")
+
+	writeCertificate(t, file, "second", now.Add(time.Minute))
+	cert, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err, "GetClientCertificate error")
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName, "expected the rotated certificate")
+
+	require.NoError(t, os.WriteFile(file, []byte("partial"), 0o600), "WriteFile error")
+	cert, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err, "GetClientCertificate error")
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName, "expected the last certificate for an invalid file")
+
+	_, err = NewCertificateReloader(file, "", "")
+	assert.Error(t, err, "expected an error for an invalid file")
+}
+
+func TestSetTLSCertificateReload(t *testing.T) {
+	t.Parallel()
+
+	uri := "mongodb://localhost/?tlsCertificateKeyFile=testdata/nopass/certificate.pem"
+	opts, err := getOptions[ClientOptions](Client().SetTLSCertificateReload(true).ApplyURI(uri))
+	require.NoError(t, err, "error applying options")
+	require.NotNil(t, opts.TLSConfig, "expected a TLS config")
+	assert.Len(t, opts.TLSConfig.Certificates, 0, "expected no static certificates")
+	require.NotNil(t, opts.TLSConfig.GetClientCertificate, "expected GetClientCertificate to be set")
+	cert, err := opts.TLSConfig.GetClientCertificate(nil)
+	require.NoError(t, err, "GetClientCertificate error")
+	assert.NotNil(t, cert.Leaf, "expected a certificate")
+
+	opts, err = getOptions[ClientOptions](Client().ApplyURI(uri))
+	require.NoError(t, err, "error applying options")
+	assert.Len(t, opts.TLSConfig.Certificates, 1, "expected a static certificate by default")
+	assert.Nil(t, opts.TLSConfig.GetClientCertificate, "expected GetClientCertificate to be unset by default")
+}
@@ -7,6 +7,7 @@
 package options
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -28,6 +29,7 @@ type ChangeStreamOptions struct {
 	StartAfter               interface{}
 	Custom                   bson.M
 	CustomPipeline           bson.M
+	BatchCoalescing          *BatchCoalescing
 }
 
 // ChangeStreamOptionsBuilder contains options to configure change stream
@@ -102,6 +104,24 @@ func (cso *ChangeStreamOptionsBuilder) SetFullDocumentBeforeChange(fdbc FullDocu
 	return cso
 }
 
+// SetPrePostImages sets the values for the FullDocument and FullDocumentBeforeChange fields to fd,
+// which must be options.WhenAvailable or options.Required, so that the change notifications include
+// the post-images and pre-images of the changed documents. Pre-images and post-images must be
+// enabled on the collection with the changeStreamPreAndPostImages option. This option is only valid
+// for MongoDB versions >= 6.0.
+func (cso *ChangeStreamOptionsBuilder) SetPrePostImages(fd FullDocument) *ChangeStreamOptionsBuilder {
+	cso.Opts = append(cso.Opts, func(opts *ChangeStreamOptions) error {
+		if fd != WhenAvailable && fd != Required {
+			return fmt.Errorf("invalid value %q for pre-images and post-images, expected %q or %q",
+				fd, WhenAvailable, Required)
+		}
+		opts.FullDocument = &fd
+		opts.FullDocumentBeforeChange = &fd
+		return nil
+	})
+	return cso
+}
+
 // SetMaxAwaitTime sets the value for the MaxAwaitTime field. The maximum amount of time that the server should
 // wait for new documents to satisfy a tailable cursor query.
 func (cso *ChangeStreamOptionsBuilder) SetMaxAwaitTime(d time.Duration) *ChangeStreamOptionsBuilder {
@@ -112,6 +132,18 @@ func (cso *ChangeStreamOptionsBuilder) SetMaxAwaitTime(d time.Duration) *ChangeS
 	return cso
 }
 
+// SetBatchCoalescing sets the value for the BatchCoalescing field. BatchCoalescing configures the
+// change stream to combine the batches of consecutive getMore commands until a batch contains a
+// minimum number of events or a time window has elapsed. The default value is nil, which means that
+// each batch is returned as soon as it is received.
+func (cso *ChangeStreamOptionsBuilder) SetBatchCoalescing(bc BatchCoalescing) *ChangeStreamOptionsBuilder {
+	cso.Opts = append(cso.Opts, func(opts *ChangeStreamOptions) error {
+		opts.BatchCoalescing = &bc
+		return nil
+	})
+	return cso
+}
+
 // SetResumeAfter sets the value for the ResumeAfter field. Specifies a document specifying the logical starting
 // point for the change stream. Only changes corresponding to an oplog entry immediately after the resume token
 // will be returned. If this is specified, StartAtOperationTime and StartAfter must not be set.
@@ -32,6 +32,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/dns"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
@@ -55,6 +56,22 @@ const (
 	ServerMonitoringModeStream = connstring.ServerMonitoringModeStream
 )
 
+const (
+	// RTTEstimatorEWMA indicates that the round-trip time of a server used for
+	// server selection is the exponentially weighted moving average of its
+	// round-trip time samples. This is the default estimator.
+	RTTEstimatorEWMA = "ewma"
+
+	// RTTEstimatorP90 indicates that the round-trip time of a server used for
+	// server selection is the 90th percentile of its recent round-trip time
+	// samples. It reacts faster than RTTEstimatorEWMA to servers that become
+	// slower and is not skewed by a single fast sample.
+	RTTEstimatorP90 = "p90"
+)
+
+// defaultHeartbeatInterval is the interval between server checks if HeartbeatInterval is not set.
+const defaultHeartbeatInterval = 10 * time.Second
+
 // ContextDialer is an interface that can be implemented by types that can create connections. It should be used to
 // provide a custom dialer when configuring a Client.
 //
@@ -63,12 +80,55 @@ type ContextDialer interface {
 	DialContext(ctx context.Context, network, address string) (net.Conn, error)
 }
 
+// ConnFactory creates the network connection to the server at the given address, which is a unix
+// domain socket path or a host and port. Unlike a ContextDialer, a ConnFactory creates the whole
+// transport: the connections it returns are used as is, without applying the TLS configuration of
+// the Client. It can be used to connect to in-process servers, e.g. one end of a net.Pipe served
+// by a test double that implements the wire protocol.
+type ConnFactory func(ctx context.Context, address string) (net.Conn, error)
+
+// HandshakeHook is called after the handshake of each new connection to a server completes,
+// including the connections used to monitor servers, with the address of the server and the
+// description reported by its handshake. If it returns an error, the connection is closed and the
+// error is reported as a connection error.
+type HandshakeHook func(address string, desc event.ServerDescription) error
+
+// DNSResolver looks up the SRV and TXT records of "mongodb+srv" URIs. A *net.Resolver configured
+// with a custom Dial function, e.g. to query the DNS interface of a service discovery system such
+// as Consul, implements DNSResolver.
+type DNSResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// OCSPVerification is the outcome of the OCSP verification of the certificate of a server when a connection is
+// established, which is passed to the function set with SetOCSPVerificationCallback.
+type OCSPVerification struct {
+	// Address is the address of the server.
+	Address string
+
+	// Status is the status of the certificate: "good", "revoked", or "unknown" if the status could not be determined.
+	Status string
+
+	// Source is the source of the OCSP response: "staple", "cache", "responder", or "" if no response was available.
+	Source string
+
+	// SoftFail is true if the status of the certificate could not be determined but the connection was allowed
+	// because hard-fail is not enabled.
+	SoftFail bool
+
+	// Err is the verification error, or nil if verification succeeded.
+	Err error
+}
+
 // Credential can be used to provide authentication options when configuring a Client.
 //
 // AuthMechanism: the mechanism to use for authentication. Supported values include "SCRAM-SHA-256", "SCRAM-SHA-1",
 // "MONGODB-CR", "PLAIN", "GSSAPI", "MONGODB-X509", and "MONGODB-AWS". This can also be set through the "authMechanism"
 // URI option. (e.g. "authMechanism=PLAIN"). For more information, see
-// https://www.mongodb.com/docs/manual/core/authentication-mechanisms/.
+// https://www.mongodb.com/docs/manual/core/authentication-mechanisms/. Custom SASL mechanisms registered with
+// the RegisterSASLMechanism function of the go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth package are also
+// supported.
 //
 // AuthMechanismProperties can be used to specify additional configuration options for certain mechanisms. They can also
 // be set through the "authMechanismProperites" URI option
@@ -148,6 +208,12 @@ type BSONOptions struct {
 	// struct tag if a "bson" struct tag is not specified.
 	UseJSONStructTags bool
 
+	// UseJSONAndTextMarshalers causes the driver to marshal and unmarshal
+	// values whose types implement json.Marshaler/json.Unmarshaler or
+	// encoding.TextMarshaler/encoding.TextUnmarshaler, but have no other
+	// registered codec, using those methods.
+	UseJSONAndTextMarshalers bool
+
 	// ErrorOnInlineDuplicates causes the driver to return an error if there is
 	// a duplicate field in the marshaled BSON when the "inline" struct tag
 	// option is set.
@@ -189,6 +255,17 @@ type BSONOptions struct {
 	// string conversion logic.
 	StringifyMapKeysWithFmt bool
 
+	// TimeEncoding specifies the BSON type that the driver marshals Go
+	// time.Time values as. The default is bson.TimeEncodingDateTime. See
+	// bson.Encoder.SetTimeEncoding for details.
+	TimeEncoding bson.TimeEncoding
+
+	// SortFields causes the driver to marshal the fields of Go maps and
+	// structs in ascending order of their BSON field names, so that equal
+	// values always marshal to the same bytes. See bson.Encoder.SortFields
+	// for details.
+	SortFields bool
+
 	// AllowTruncatingDoubles causes the driver to truncate the fractional part
 	// of BSON "double" values when attempting to unmarshal them into a Go
 	// integer (int, int8, int16, int32, or int64) struct field. The truncation
@@ -205,6 +282,12 @@ type BSONOptions struct {
 	// "interface{}" or "map[string]interface{}".
 	DefaultDocumentM bool
 
+	// DefaultDocumentOrderedMap causes the driver to always unmarshal
+	// documents into the *bson.OrderedMap type, which preserves the order of
+	// their fields. This behavior is restricted to data typed as
+	// "interface{}" or "map[string]interface{}".
+	DefaultDocumentOrderedMap bool
+
 	// ObjectIDAsHexString causes the Decoder to decode object IDs to their hex
 	// representation.
 	ObjectIDAsHexString bool
@@ -213,6 +296,14 @@ type BSONOptions struct {
 	// local timezone instead of the UTC timezone.
 	UseLocalTimeZone bool
 
+	// UnsafeZeroCopy causes the driver to unmarshal BSON string values and
+	// binary data into strings and byte slices that refer to the reply from
+	// the server instead of copying them. The decoded values remain valid
+	// after the cursor moves to another batch, but they keep the whole batch
+	// in memory for as long as they are referenced and the byte slices must
+	// not be modified. See bson.Decoder.UnsafeZeroCopy for details.
+	UnsafeZeroCopy bool
+
 	// ZeroMaps causes the driver to delete any existing values from Go maps in
 	// the destination value before unmarshaling BSON documents into them.
 	ZeroMaps bool
@@ -221,50 +312,198 @@ type BSONOptions struct {
 	// structs in the destination value before unmarshaling BSON documents into
 	// them.
 	ZeroStructs bool
+
+	// Allocator, if set, provides the buffers for bson.Raw values that the
+	// driver materializes when unmarshaling results into a nil bson.Raw, such
+	// as the elements appended by Cursor.All into a []bson.Raw. Applications
+	// own those buffers and can return them to the Allocator with Release
+	// once they are no longer referenced.
+	Allocator bson.Allocator
+}
+
+// CursorLeak describes a cursor that was garbage collected while it was still open on the server,
+// because it was neither closed nor exhausted. See ClientOptionsBuilder.SetCursorLeakHandler for
+// more information.
+type CursorLeak struct {
+	// ID is the ID of the cursor on the server.
+	ID int64
+
+	// Database and Collection are the namespace of the cursor.
+	Database   string
+	Collection string
+
+	// Created is the time the cursor was created.
+	Created time.Time
+
+	// Stack is the stack trace of the goroutine that created the cursor, formatted like the output
+	// of runtime/debug.Stack.
+	Stack []byte
+
+	// Killed is true if the driver is sending a killCursors command for the cursor because
+	// ClientOptionsBuilder.SetKillLeakedCursors is enabled.
+	Killed bool
+}
+
+// RetryPolicy configures how retryable reads and writes are retried. See
+// ClientOptionsBuilder.SetRetryPolicy for more information.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times an operation is attempted, including the first
+	// attempt. Values less than 2 retry once, which is the default. It does not apply to operations
+	// run with a Timeout, which are retried until the timeout expires.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The delay doubles for each subsequent
+	// retry up to MaxBackoff, and a random jitter of up to half the delay is subtracted so that
+	// clients retrying at the same time spread out. If InitialBackoff is 0, retries are immediate.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. If it is 0, the delay is capped at 10
+	// seconds.
+	MaxBackoff time.Duration
+
+	// Retryable, if set, decides whether a failed attempt is retried. It is called with the failed
+	// attempt, which includes the default decision of the driver, and returns the decision to use
+	// instead. Retrying writes that the server does not consider retryable can apply them more than
+	// once.
+	Retryable func(attempt RetryAttempt) bool
+}
+
+// RetryAttempt describes a failed attempt of an operation passed to RetryPolicy.Retryable.
+type RetryAttempt struct {
+	// CommandName is the name of the command that failed, such as "find" or "insert".
+	CommandName string
+
+	// Write is true for write operations and false for read operations.
+	Write bool
+
+	// Attempt is the number of the failed attempt, starting at 1.
+	Attempt int
+
+	// Err is the error of the failed attempt. Server errors implement
+	// interface{ HasErrorLabel(string) bool }, and functions such as mongo.IsNetworkError and
+	// mongo.IsTimeout can be used to classify it.
+	Err error
+
+	// Retryable is whether the driver would retry the error by default.
+	Retryable bool
+}
+
+// OperationInfo describes an operation dispatched by a Client, which is passed to its
+// OperationMiddleware.
+type OperationInfo struct {
+	// CommandName is the name of the operation, such as "find" or "insert".
+	CommandName string
+
+	// Database is the database the operation runs against.
+	Database string
+
+	// Write is true for write operations and false for read operations.
+	Write bool
+
+	// Comment, if set before the next handler is called, replaces the comment of the commands sent
+	// for the operation, e.g. to tag them with a tenant or trace ID. It is marshaled with the
+	// default registry.
+	Comment interface{}
+}
+
+// OperationHandler runs an operation, including its retries, and returns its error.
+type OperationHandler func(ctx context.Context, op *OperationInfo) error
+
+// OperationMiddleware wraps the OperationHandler that runs the operations of a Client. See
+// ClientOptionsBuilder.SetOperationMiddleware for more information.
+type OperationMiddleware func(next OperationHandler) OperationHandler
+
+// CausalTimes are the highest cluster time and operation time seen by an operation. See
+// ClientOptionsBuilder.SetCausalTimeStore.
+type CausalTimes struct {
+	// ClusterTime is the $clusterTime document gossiped by the servers. It must be stored as is,
+	// including its signature.
+	ClusterTime bson.Raw
+
+	// OperationTime is the operation time of the operation.
+	OperationTime *bson.Timestamp
+}
+
+// CausalTimeStore stores the CausalTimes of the operations of a Client. See
+// ClientOptionsBuilder.SetCausalTimeStore for more information.
+type CausalTimeStore interface {
+	// Load returns the stored times for the context of an operation, or zero CausalTimes if no
+	// times are stored.
+	Load(ctx context.Context) (CausalTimes, error)
+
+	// Save stores the times seen by an operation. It is only called if the times are higher than
+	// the loaded times, but they may be lower than times saved concurrently by another operation,
+	// so implementations should keep the highest times.
+	Save(ctx context.Context, times CausalTimes) error
 }
 
 // ClientOptions contains arguments to configure a Client instance. Arguments
 // can be set through the ClientOptions setter functions. See each function for
 // documentation.
 type ClientOptions struct {
-	AppName                  *string
-	Auth                     *Credential
-	AutoEncryptionOptions    Lister[AutoEncryptionOptions]
-	ConnectTimeout           *time.Duration
-	Compressors              []string
-	Dialer                   ContextDialer
-	Direct                   *bool
-	DisableOCSPEndpointCheck *bool
-	HeartbeatInterval        *time.Duration
-	Hosts                    []string
-	HTTPClient               *http.Client
-	LoadBalanced             *bool
-	LocalThreshold           *time.Duration
-	LoggerOptions            Lister[LoggerOptions]
-	MaxConnIdleTime          *time.Duration
-	MaxPoolSize              *uint64
-	MinPoolSize              *uint64
-	MaxConnecting            *uint64
-	PoolMonitor              *event.PoolMonitor
-	Monitor                  *event.CommandMonitor
-	ServerMonitor            *event.ServerMonitor
-	ReadConcern              *readconcern.ReadConcern
-	ReadPreference           *readpref.ReadPref
-	BSONOptions              *BSONOptions
-	Registry                 *bson.Registry
-	ReplicaSet               *string
-	RetryReads               *bool
-	RetryWrites              *bool
-	ServerAPIOptions         Lister[ServerAPIOptions]
-	ServerMonitoringMode     *string
-	ServerSelectionTimeout   *time.Duration
-	SRVMaxHosts              *int
-	SRVServiceName           *string
-	Timeout                  *time.Duration
-	TLSConfig                *tls.Config
-	WriteConcern             *writeconcern.WriteConcern
-	ZlibLevel                *int
-	ZstdLevel                *int
+	AppName                     *string
+	Auth                        *Credential
+	AutoEncryptionOptions       Lister[AutoEncryptionOptions]
+	ConnectTimeout              *time.Duration
+	Compressors                 []string
+	CausalTimeStore             CausalTimeStore
+	CheckDocumentSize           *bool
+	CommandSizeStats            *bool
+	ConnFactory                 ConnFactory
+	CursorLeakHandler           func(CursorLeak)
+	CursorMemoryBackpressure    *bool
+	Dialer                      ContextDialer
+	Direct                      *bool
+	DisableOCSPCache            *bool
+	DisableOCSPEndpointCheck    *bool
+	DisableRTTMonitor           *bool
+	DNSResolver                 DNSResolver
+	HandshakeHook               HandshakeHook
+	HeartbeatInterval           *time.Duration
+	Hosts                       []string
+	HTTPClient                  *http.Client
+	KillLeakedCursors           *bool
+	LoadBalanced                *bool
+	LocalThreshold              *time.Duration
+	LoggerOptions               Lister[LoggerOptions]
+	MaxConnIdleTime             *time.Duration
+	MaxPoolSize                 *uint64
+	MinPoolSize                 *uint64
+	MaxConnecting               *uint64
+	MaxConnections              *uint64
+	MaxCursorMemory             *int64
+	MaxIdleSessions             *uint64
+	MaxTimeAllowance            *time.Duration
+	OCSPCacheMaxAge             *time.Duration
+	OCSPHardFail                *bool
+	OCSPRequireStaple           *bool
+	OCSPVerificationCallback    func(OCSPVerification)
+	PoolMonitor                 *event.PoolMonitor
+	Monitor                     *event.CommandMonitor
+	OperationMiddleware         []OperationMiddleware
+	ServerMonitor               *event.ServerMonitor
+	ReadConcern                 *readconcern.ReadConcern
+	ReadPreference              *readpref.ReadPref
+	BSONOptions                 *BSONOptions
+	Registry                    *bson.Registry
+	ReplicaSet                  *string
+	RetryPolicy                 *RetryPolicy
+	RetryReads                  *bool
+	RetryWrites                 *bool
+	RTTEstimator                *string
+	ServerAPIOptions            Lister[ServerAPIOptions]
+	ServerMonitoringMode        *string
+	ServerMonitorConnectTimeout *time.Duration
+	ServerSelectionTimeout      *time.Duration
+	SRVMaxHosts                 *int
+	SRVPollingInterval          *time.Duration
+	SRVServiceName              *string
+	Timeout                     *time.Duration
+	TLSCertificateReload        *bool
+	TLSConfig                   *tls.Config
+	WriteConcern                *writeconcern.WriteConcern
+	ZlibLevel                   *int
+	ZstdLevel                   *int
 
 	// Crypt specifies a custom driver.Crypt to be used to encrypt and decrypt documents. The default is no
 	// encryption.
@@ -313,7 +552,11 @@ func (opts *ClientOptions) GetURI() string {
 }
 
 func setURIOpts(uri string, opts *ClientOptions) error {
-	connString, err := connstring.ParseAndValidate(uri)
+	resolver := dns.DefaultResolver
+	if opts.DNSResolver != nil {
+		resolver = dns.NewResolver(opts.DNSResolver)
+	}
+	connString, err := connstring.ParseAndValidateWithResolver(uri, resolver)
 	if err != nil {
 		return err
 	}
@@ -474,6 +717,19 @@ func setURIOpts(uri string, opts *ClientOptions) error {
 			return err
 		}
 
+		if opts.TLSCertificateReload != nil && *opts.TLSCertificateReload && len(tlsConfig.Certificates) > 0 {
+			certFile, keyFile := connString.SSLClientCertificateKeyFile, ""
+			if !connString.SSLClientCertificateKeyFileSet {
+				certFile, keyFile = connString.SSLCertificateFile, connString.SSLPrivateKeyFile
+			}
+			reloader, err := NewCertificateReloader(certFile, keyFile, keyPasswd)
+			if err != nil {
+				return err
+			}
+			tlsConfig.Certificates = nil
+			tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+		}
+
 		// If a username wasn't specified fork x509, add one from the certificate.
 		if opts.Auth != nil && strings.ToLower(opts.Auth.AuthMechanism) == "mongodb-x509" && opts.Auth.Username == "" {
 			// The Go x509 package gives the subject with the pairs in reverse order that we want.
@@ -551,12 +807,35 @@ func (c *ClientOptionsBuilder) Validate() error {
 			*args.HeartbeatInterval)
 	}
 
+	if args.ServerMonitorConnectTimeout != nil && *args.ServerMonitorConnectTimeout < 0 {
+		return fmt.Errorf("server monitor connect timeout must not be negative, got %v",
+			*args.ServerMonitorConnectTimeout)
+	}
+
 	if args.MaxPoolSize != nil && args.MinPoolSize != nil && *args.MaxPoolSize != 0 &&
 		*args.MinPoolSize > *args.MaxPoolSize {
 		return fmt.Errorf("minPoolSize must be less than or equal to maxPoolSize, got minPoolSize=%d maxPoolSize=%d",
 			*args.MinPoolSize, *args.MaxPoolSize)
 	}
 
+	if args.MaxCursorMemory != nil && *args.MaxCursorMemory < 0 {
+		return fmt.Errorf("max cursor memory must not be negative, got %d", *args.MaxCursorMemory)
+	}
+
+	if args.MaxTimeAllowance != nil && *args.MaxTimeAllowance < 0 {
+		return fmt.Errorf("max time allowance must not be negative, got %v", *args.MaxTimeAllowance)
+	}
+
+	if args.ReadPreference != nil {
+		heartbeatInterval := defaultHeartbeatInterval
+		if args.HeartbeatInterval != nil {
+			heartbeatInterval = *args.HeartbeatInterval
+		}
+		if err := args.ReadPreference.Validate(heartbeatInterval); err != nil {
+			return fmt.Errorf("invalid read preference: %w", err)
+		}
+	}
+
 	// verify server API version if ServerAPIOptions are passed in.
 	if args.ServerAPIOptions != nil {
 		serverAPIopts, err := getOptions[ServerAPIOptions](args.ServerAPIOptions)
@@ -592,10 +871,26 @@ func (c *ClientOptionsBuilder) Validate() error {
 		}
 	}
 
+	if args.ConnFactory != nil && args.Dialer != nil {
+		return errors.New("cannot set both a ConnFactory and a Dialer")
+	}
+
+	if d := args.SRVPollingInterval; d != nil && *d <= 0 {
+		return fmt.Errorf("invalid SRV polling interval %v: value must be positive", *d)
+	}
+
 	if mode := args.ServerMonitoringMode; mode != nil && !connstring.IsValidServerMonitoringMode(*mode) {
 		return fmt.Errorf("invalid server monitoring mode: %q", *mode)
 	}
 
+	if e := args.RTTEstimator; e != nil && *e != RTTEstimatorEWMA && *e != RTTEstimatorP90 {
+		return fmt.Errorf("invalid RTT estimator: %q", *e)
+	}
+
+	if rp := args.RetryPolicy; rp != nil && (rp.MaxAttempts < 0 || rp.InitialBackoff < 0 || rp.MaxBackoff < 0) {
+		return errors.New("retry policy attempts and backoffs must not be negative")
+	}
+
 	if to := args.Timeout; to != nil && *to < 0 {
 		return fmt.Errorf(`invalid value %q for "Timeout": value must be positive`, *to)
 	}
@@ -721,6 +1016,22 @@ func (c *ClientOptionsBuilder) SetConnectTimeout(d time.Duration) *ClientOptions
 	return c
 }
 
+// SetConnFactory specifies a ConnFactory that creates the connections to the servers instead of
+// dialing them. TLS options are not applied to the connections it creates. A ConnFactory cannot be
+// used together with a Dialer. The connections are still authenticated if Auth is set, so Auth
+// should not be set if the transport authenticates them. ConnFactoryFromConns and PipeConnFactory
+// create a ConnFactory from established connections and for in-memory servers. The default is to
+// dial connections with the Dialer.
+func (c *ClientOptionsBuilder) SetConnFactory(f ConnFactory) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.ConnFactory = f
+
+		return nil
+	})
+
+	return c
+}
+
 // SetDialer specifies a custom ContextDialer to be used to create new connections to the server. This method overrides
 // the default net.Dialer, so dialer options such as Timeout, KeepAlive, Resolver, etc can be set.
 // See https://golang.org/pkg/net/#Dialer for more information about the net.Dialer type.
@@ -759,6 +1070,65 @@ func (c *ClientOptionsBuilder) SetDirect(b bool) *ClientOptionsBuilder {
 	return c
 }
 
+// SetDisableRTTMonitor specifies whether the Client should skip opening the additional connection
+// to each server that measures round-trip times while the monitoring connection streams heartbeats.
+// Without it, round-trip times are only measured by polling heartbeats and connection handshakes,
+// so server selection by latency and the round-trip time estimate used by Timeout are less
+// accurate. Disabling the RTT monitor saves one connection per server, which can reduce costs in
+// serverless and function-as-a-service environments, where ServerMonitoringModePoll, which never
+// opens the RTT connection, may also be used. The default is false.
+func (c *ClientOptionsBuilder) SetDisableRTTMonitor(disable bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.DisableRTTMonitor = &disable
+
+		return nil
+	})
+
+	return c
+}
+
+// SetRTTEstimator specifies how the round-trip time of each server used to compute the latency
+// window is estimated. See the helper constants RTTEstimatorEWMA and RTTEstimatorP90 for more
+// information about valid estimators. The default is RTTEstimatorEWMA.
+func (c *ClientOptionsBuilder) SetRTTEstimator(estimator string) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.RTTEstimator = &estimator
+
+		return nil
+	})
+
+	return c
+}
+
+// SetDNSResolver specifies the DNSResolver used to look up the SRV and TXT records of a
+// "mongodb+srv" URI, both when the URI is applied and when the SRV records are polled for changes.
+// It can be used in environments with split-horizon DNS or with service discovery systems that
+// serve SRV records. To resolve the URI passed to ApplyURI, this function must be called before
+// ApplyURI. Each lookup is canceled after 10 seconds. The default is the resolver of the net
+// package.
+func (c *ClientOptionsBuilder) SetDNSResolver(r DNSResolver) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.DNSResolver = r
+
+		return nil
+	})
+
+	return c
+}
+
+// SetHandshakeHook specifies a HandshakeHook that is called after the handshake of each new
+// connection completes. Returning an error from the hook fails the connection, which can be used to
+// check the servers a test connects to or to simulate handshake failures. The default is nil.
+func (c *ClientOptionsBuilder) SetHandshakeHook(h HandshakeHook) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.HandshakeHook = h
+
+		return nil
+	})
+
+	return c
+}
+
 // SetHeartbeatInterval specifies the amount of time to wait between periodic background server checks. This can also be
 // set through the "heartbeatFrequencyMS" URI option (e.g. "heartbeatFrequencyMS=10000"). The default is 10 seconds.
 // The minimum is 500ms.
@@ -777,6 +1147,9 @@ func (c *ClientOptionsBuilder) SetHeartbeatInterval(d time.Duration) *ClientOpti
 //
 // Hosts can also be specified as a comma-separated list in a URI. For example, to include "localhost:27017" and
 // "localhost:27018", a URI could be "mongodb://localhost:27017,localhost:27018". The default is ["localhost:27017"]
+//
+// A host can also be the absolute path of a unix domain socket, such as "/tmp/mongodb-27017.sock". In a URI, the
+// slashes of the path must be percent-encoded, e.g. "mongodb://%2Ftmp%2Fmongodb-27017.sock".
 func (c *ClientOptionsBuilder) SetHosts(s []string) *ClientOptionsBuilder {
 	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
 		opts.Hosts = s
@@ -885,6 +1258,173 @@ func (c *ClientOptionsBuilder) SetMaxConnecting(u uint64) *ClientOptionsBuilder
 	return c
 }
 
+// SetMaxConnections specifies the maximum number of connections of all the connection pools together,
+// in addition to the maximum of each pool set with SetMaxPoolSize, e.g. to stay within a file
+// descriptor limit when connecting to many mongos instances. Each server's pool is entitled to an
+// equal share of the maximum and can use more while no pool below its share is waiting for a
+// connection. Once another pool waits below its share, a pool above its share closes its idle
+// connections and the connections checked in until it is back to its share. Checkouts that need a
+// new connection wait while the maximum is reached. Connections used for server monitoring are not
+// counted. See Client.ConnectionBudgetStats for the number of connections and how often checkouts
+// waited. If this is 0, the number of connections is only limited per pool. The default is 0.
+func (c *ClientOptionsBuilder) SetMaxConnections(u uint64) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.MaxConnections = &u
+
+		return nil
+	})
+
+	return c
+}
+
+// SetCausalTimeStore specifies a store that shares the cluster time and operation time of the
+// operations of the Client, so that reads observe the preceding writes of the same user even if
+// they are run by different Clients, e.g. by stateless application replicas that serve the HTTP
+// requests of a user. Implementations typically derive the key of the stored times from the
+// context of the operation, e.g. from a user ID set by an HTTP middleware, and store them in a
+// shared cache such as Redis.
+//
+// Before each operation, the times returned by Load are injected into the session of the
+// operation, which makes implicit sessions causally consistent, so that reads include the
+// "afterClusterTime" read concern field. After the operation, its times are passed to Save if they
+// have advanced. If Load fails, the operation is not run and the error is returned. If Save fails,
+// the error is returned even though the operation has run. The times of snapshot sessions and of
+// sessions with a transaction in progress are not changed. Causal consistency requires reads and
+// writes with the "majority" read and write concerns to hold across replica set elections. The
+// getMore and killCursors commands run by cursors do not use the store.
+func (c *ClientOptionsBuilder) SetCausalTimeStore(store CausalTimeStore) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.CausalTimeStore = store
+
+		return nil
+	})
+
+	return c
+}
+
+// SetCheckDocumentSize specifies whether the Client checks the size of every command document
+// against the maxBsonObjectSize reported by the selected server before sending it, so that
+// operations with a document that is too large, such as an update with a large replacement
+// document, fail with a mongo.DocumentTooLargeError that names the largest fields of the document instead
+// of a server error. The documents of insert, update, and delete commands are always checked. The
+// default is false.
+func (c *ClientOptionsBuilder) SetCheckDocumentSize(b bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.CheckDocumentSize = &b
+
+		return nil
+	})
+
+	return c
+}
+
+// SetCommandSizeStats specifies whether the Client aggregates the sizes of the wire messages of
+// the commands it runs per collection, which can be read with Client.CommandSizeStats to find the
+// operations that send or receive the largest documents before they reach the 16MB document size
+// limit of the server. The default is false.
+func (c *ClientOptionsBuilder) SetCommandSizeStats(b bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.CommandSizeStats = &b
+
+		return nil
+	})
+
+	return c
+}
+
+// SetMaxCursorMemory specifies the maximum total number of bytes of batches that may be buffered by
+// all open cursors of a Client at the same time. If buffering a new batch would exceed the limit, the
+// cursor fails with mongo.ErrCursorMemoryLimitExceeded, unless backpressure is enabled with
+// SetCursorMemoryBackpressure. If the first batch of a cursor exceeds the limit, the cursor is
+// closed and the operation that created it returns the error. If this is 0, buffered memory is not
+// limited. The default is 0.
+func (c *ClientOptionsBuilder) SetMaxCursorMemory(bytes int64) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.MaxCursorMemory = &bytes
+
+		return nil
+	})
+
+	return c
+}
+
+// SetCursorMemoryBackpressure specifies whether cursors wait for other cursors to release buffered
+// memory before fetching another batch when the limit set with SetMaxCursorMemory has been reached,
+// instead of returning an error. A cursor waits until memory is released or the Context passed to
+// Next, TryNext, or All expires. The default is false.
+func (c *ClientOptionsBuilder) SetCursorMemoryBackpressure(b bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.CursorMemoryBackpressure = &b
+
+		return nil
+	})
+
+	return c
+}
+
+// SetCursorLeakHandler specifies a function that is called for each cursor that is garbage collected
+// while it is still open on the server, because it was neither closed nor exhausted. Such cursors
+// use server memory until they time out, 10 minutes after their last use by default. The function
+// is called from its own goroutine with a description of the cursor that includes the stack trace
+// of its creation, which is captured for every cursor created while the handler is set. The
+// default is nil, which means that leaked cursors are not reported.
+func (c *ClientOptionsBuilder) SetCursorLeakHandler(fn func(CursorLeak)) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.CursorLeakHandler = fn
+
+		return nil
+	})
+
+	return c
+}
+
+// SetKillLeakedCursors specifies whether the driver sends a killCursors command for each cursor that
+// is garbage collected while it is still open on the server, and returns its implicit session to
+// the session pool. Garbage collection is not guaranteed to detect a leaked cursor promptly, so
+// cursors should still be closed with Cursor.Close. The default is false.
+func (c *ClientOptionsBuilder) SetKillLeakedCursors(b bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.KillLeakedCursors = &b
+
+		return nil
+	})
+
+	return c
+}
+
+// SetMaxIdleSessions specifies the maximum number of server sessions kept in the session pool of a
+// Client for reuse. Sessions that are ended when the pool is full are discarded without ending them
+// on the server, which removes them once they have been idle for the session timeout of the
+// deployment (30 minutes by default). This bounds the memory used by the pool after bursts of
+// concurrent operations. If this is 0, the pool is unbounded. The default is 0.
+func (c *ClientOptionsBuilder) SetMaxIdleSessions(u uint64) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.MaxIdleSessions = &u
+
+		return nil
+	})
+
+	return c
+}
+
+// SetMaxTimeAllowance specifies the part of the remaining time until the context deadline of an
+// operation that is reserved for the network round trip when the driver derives the "maxTimeMS"
+// field of commands from the deadline, so that the server stops working on commands whose results
+// would arrive too late to be used. By default, the driver sets maxTimeMS to the remaining time
+// minus the minimum round-trip time observed for the selected server. If an allowance is set, the
+// larger of the allowance and the minimum round-trip time is reserved instead. Operations whose
+// remaining time is not larger than the reserved time fail without being sent to the server. The
+// default is 0.
+func (c *ClientOptionsBuilder) SetMaxTimeAllowance(d time.Duration) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.MaxTimeAllowance = &d
+
+		return nil
+	})
+
+	return c
+}
+
 // SetPoolMonitor specifies a PoolMonitor to receive connection pool events. See the event.PoolMonitor documentation
 // for more information about the structure of the monitor and events that can be received.
 func (c *ClientOptionsBuilder) SetPoolMonitor(m *event.PoolMonitor) *ClientOptionsBuilder {
@@ -909,6 +1449,37 @@ func (c *ClientOptionsBuilder) SetMonitor(m *event.CommandMonitor) *ClientOption
 	return c
 }
 
+// SetOperationMiddleware specifies middleware that wraps every operation dispatched by the Client,
+// such as a find, an insert, or a commit, e.g. for tracing, metrics, or slow query logging:
+//
+//	logSlow := func(next options.OperationHandler) options.OperationHandler {
+//		return func(ctx context.Context, op *options.OperationInfo) error {
+//			start := time.Now()
+//			err := next(ctx, op)
+//			if d := time.Since(start); d > time.Second {
+//				log.Printf("slow %s on %s: %v (error: %v)", op.CommandName, op.Database, d, err)
+//			}
+//			return err
+//		}
+//	}
+//	opts := options.Client().SetOperationMiddleware(logSlow)
+//
+// The first middleware is the outermost: it is called first and its next handler calls the
+// second middleware. The innermost handler selects a server and runs the operation, including its
+// retries, so a middleware observes the total duration and the final error of the operation. A
+// middleware can pass a derived context to the next handler, and can set the Comment of the
+// operation, which requires MongoDB 4.4 or later for most commands. The getMore and killCursors
+// commands run by cursors and change streams are not wrapped; use SetMonitor to observe them.
+func (c *ClientOptionsBuilder) SetOperationMiddleware(middleware ...OperationMiddleware) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.OperationMiddleware = middleware
+
+		return nil
+	})
+
+	return c
+}
+
 // SetServerMonitor specifies an SDAM monitor used to monitor SDAM events.
 func (c *ClientOptionsBuilder) SetServerMonitor(m *event.ServerMonitor) *ClientOptionsBuilder {
 	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
@@ -1032,6 +1603,20 @@ func (c *ClientOptionsBuilder) SetRetryReads(b bool) *ClientOptionsBuilder {
 	return c
 }
 
+// SetRetryPolicy specifies how supported read and write operations are retried when retries are
+// enabled by SetRetryReads and SetRetryWrites. By default, an operation is retried once,
+// immediately. A RetryPolicy allows more attempts with exponential backoff and jitter, for example
+// to survive a replica set election, and can change which errors are retried.
+func (c *ClientOptionsBuilder) SetRetryPolicy(rp *RetryPolicy) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.RetryPolicy = rp
+
+		return nil
+	})
+
+	return c
+}
+
 // SetServerSelectionTimeout specifies how long the driver will wait to find an available, suitable server to execute an
 // operation. This can also be set through the "serverSelectionTimeoutMS" URI option (e.g.
 // "serverSelectionTimeoutMS=30000"). The default value is 30 seconds.
@@ -1066,6 +1651,21 @@ func (c *ClientOptionsBuilder) SetTimeout(d time.Duration) *ClientOptionsBuilder
 	return c
 }
 
+// SetTLSCertificateReload specifies whether the client certificate set through the "tlsCertificateKeyFile" URI
+// option, or the "tlsCertificateFile" and "tlsPrivateKeyFile" URI options, is loaded again from its files when they
+// change, as with a CertificateReloader. New connections use the current certificate, while established connections
+// keep using the certificate they were created with until they are closed. This function must be called before
+// ApplyURI. The default value is false.
+func (c *ClientOptionsBuilder) SetTLSCertificateReload(reload bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.TLSCertificateReload = &reload
+
+		return nil
+	})
+
+	return c
+}
+
 // SetTLSConfig specifies a tls.Config instance to use use to configure TLS on all connections created to the cluster.
 // This can also be set through the following URI options:
 //
@@ -1089,6 +1689,10 @@ func (c *ClientOptionsBuilder) SetTimeout(d time.Duration) *ClientOptionsBuilder
 // server and any host name in that certificate. Note that setting this to true makes TLS susceptible to
 // man-in-the-middle attacks and should only be done for testing.
 //
+// The functions of the tls.Config, such as GetClientCertificate, are called for each new connection, so a client
+// certificate returned by GetClientCertificate can be rotated without recreating the Client. See CertificateReloader
+// for a GetClientCertificate function that reloads a certificate from files.
+//
 // The default is nil, meaning no TLS will be enabled.
 func (c *ClientOptionsBuilder) SetTLSConfig(cfg *tls.Config) *ClientOptionsBuilder {
 	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
@@ -1196,6 +1800,71 @@ func (c *ClientOptionsBuilder) SetDisableOCSPEndpointCheck(disableCheck bool) *C
 	return c
 }
 
+// SetOCSPRequireStaple specifies whether connections fail if the server does not staple an OCSP response to its
+// certificate during the TLS handshake, even if its certificate does not have the Must-Staple extension. OCSP
+// verification is not performed if tlsInsecure is set. The default value is false.
+func (c *ClientOptionsBuilder) SetOCSPRequireStaple(require bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.OCSPRequireStaple = &require
+
+		return nil
+	})
+
+	return c
+}
+
+// SetOCSPHardFail specifies whether connections fail if the OCSP status of the server certificate cannot be
+// determined, e.g. because the server did not staple a response and no OCSP responder could be reached. By default,
+// OCSP verification soft-fails: connections are allowed if the status is unknown and only fail if the certificate is
+// revoked. Use SetOCSPVerificationCallback to observe soft-fail occurrences. The default value is false.
+func (c *ClientOptionsBuilder) SetOCSPHardFail(hardFail bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.OCSPHardFail = &hardFail
+
+		return nil
+	})
+
+	return c
+}
+
+// SetOCSPVerificationCallback specifies a function that is called with the outcome of the OCSP verification of each
+// new TLS connection, including failed and soft-failed verifications. The function is called synchronously while the
+// connection is established, so it should return quickly. The default value is nil.
+func (c *ClientOptionsBuilder) SetOCSPVerificationCallback(fn func(OCSPVerification)) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.OCSPVerificationCallback = fn
+
+		return nil
+	})
+
+	return c
+}
+
+// SetOCSPCacheMaxAge specifies the maximum duration for which an OCSP response is cached and used to verify new
+// connections, even if the response is valid for longer. The default value is 0, meaning that responses are cached
+// until their nextUpdate time.
+func (c *ClientOptionsBuilder) SetOCSPCacheMaxAge(d time.Duration) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.OCSPCacheMaxAge = &d
+
+		return nil
+	})
+
+	return c
+}
+
+// SetDisableOCSPCache specifies whether OCSP responses are not cached, so that each new connection is verified with a
+// stapled response or by contacting the OCSP responders. The default value is false.
+func (c *ClientOptionsBuilder) SetDisableOCSPCache(disable bool) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.DisableOCSPCache = &disable
+
+		return nil
+	})
+
+	return c
+}
+
 // SetServerAPIOptions specifies a ServerAPIOptions instance used to configure the API version sent to the server
 // when running commands. See the options.ServerAPIOptions documentation for more information about the supported
 // options.
@@ -1223,6 +1892,22 @@ func (c *ClientOptionsBuilder) SetServerMonitoringMode(mode string) *ClientOptio
 	return c
 }
 
+// SetServerMonitorConnectTimeout specifies a timeout that is used for creating and checking the
+// connections used to monitor the servers, instead of the ConnectTimeout. In polling mode it is
+// also the timeout of each heartbeat, and in streaming mode it is added to the heartbeat interval
+// to compute the timeout of each streamed response. A shorter timeout makes the Client detect
+// unreachable servers faster without limiting the time allowed to establish application
+// connections. A value of 0 means no timeout. The default is the ConnectTimeout.
+func (c *ClientOptionsBuilder) SetServerMonitorConnectTimeout(d time.Duration) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.ServerMonitorConnectTimeout = &d
+
+		return nil
+	})
+
+	return c
+}
+
 // SetSRVMaxHosts specifies the maximum number of SRV results to randomly select during polling. To limit the number
 // of hosts selected in SRV discovery, this function must be called before ApplyURI. This can also be set through
 // the "srvMaxHosts" URI option.
@@ -1236,6 +1921,20 @@ func (c *ClientOptionsBuilder) SetSRVMaxHosts(srvMaxHosts int) *ClientOptionsBui
 	return c
 }
 
+// SetSRVPollingInterval specifies how often the SRV records of a "mongodb+srv" URI are polled to
+// discover added and removed mongos hosts. Set the SRVHostsChanged callback of an
+// event.ServerMonitor to be notified of the changes. The interval must be positive. The default is
+// 60 seconds.
+func (c *ClientOptionsBuilder) SetSRVPollingInterval(d time.Duration) *ClientOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ClientOptions) error {
+		opts.SRVPollingInterval = &d
+
+		return nil
+	})
+
+	return c
+}
+
 // SetSRVServiceName specifies a custom SRV service name to use in SRV polling. To use a custom SRV service name
 // in SRV discovery, this function must be called before ApplyURI. This can also be set through the "srvServiceName"
 // URI option.
@@ -69,6 +69,8 @@ func TestClientOptions(t *testing.T) {
 			{"MaxPoolSize", (*ClientOptionsBuilder).SetMaxPoolSize, uint64(250), "MaxPoolSize", true},
 			{"MinPoolSize", (*ClientOptionsBuilder).SetMinPoolSize, uint64(10), "MinPoolSize", true},
 			{"MaxConnecting", (*ClientOptionsBuilder).SetMaxConnecting, uint64(10), "MaxConnecting", true},
+			{"MaxCursorMemory", (*ClientOptionsBuilder).SetMaxCursorMemory, int64(1 << 20), "MaxCursorMemory", true},
+			{"CursorMemoryBackpressure", (*ClientOptionsBuilder).SetCursorMemoryBackpressure, true, "CursorMemoryBackpressure", true},
 			{"PoolMonitor", (*ClientOptionsBuilder).SetPoolMonitor, &event.PoolMonitor{}, "PoolMonitor", false},
 			{"Monitor", (*ClientOptionsBuilder).SetMonitor, &event.CommandMonitor{}, "Monitor", false},
 			{"ReadConcern", (*ClientOptionsBuilder).SetReadConcern, readconcern.Majority(), "ReadConcern", false},
@@ -434,6 +436,66 @@ func TestClientOptions(t *testing.T) {
 			})
 		}
 	})
+	t.Run("read preference validation", func(t *testing.T) {
+		t.Parallel()
+
+		rp := readpref.Secondary(readpref.WithMaxStaleness(100 * time.Second))
+		err := Client().SetReadPreference(rp).Validate()
+		assert.Nil(t, err, "Validate error with the default heartbeat interval: %v", err)
+
+		err = Client().SetReadPreference(rp).SetHeartbeatInterval(95 * time.Second).Validate()
+		assert.NotNil(t, err, "expected Validate error for a max staleness below the heartbeat interval")
+
+		err = Client().ApplyURI("mongodb://localhost/?readPreference=secondary&maxStalenessSeconds=30").Validate()
+		assert.NotNil(t, err, "expected Validate error for a max staleness below 90 seconds")
+	})
+	t.Run("max time allowance validation", func(t *testing.T) {
+		t.Parallel()
+
+		err := Client().SetMaxTimeAllowance(10 * time.Millisecond).Validate()
+		assert.Nil(t, err, "Validate error for a positive allowance: %v", err)
+
+		err = Client().SetMaxTimeAllowance(-time.Millisecond).Validate()
+		assert.NotNil(t, err, "expected Validate error for a negative allowance")
+	})
+	t.Run("RTT estimator validation", func(t *testing.T) {
+		t.Parallel()
+
+		for _, estimator := range []string{RTTEstimatorEWMA, RTTEstimatorP90} {
+			err := Client().SetRTTEstimator(estimator).Validate()
+			assert.Nil(t, err, "Validate error for estimator %q: %v", estimator, err)
+		}
+
+		err := Client().SetRTTEstimator("median").Validate()
+		assert.Equal(t, errors.New("invalid RTT estimator: \"median\""), err, "expected Validate error for an unknown estimator")
+	})
+	t.Run("SRV polling interval validation", func(t *testing.T) {
+		t.Parallel()
+
+		err := Client().SetSRVPollingInterval(time.Second).Validate()
+		assert.Nil(t, err, "Validate error for a positive interval: %v", err)
+
+		err = Client().SetSRVPollingInterval(0).Validate()
+		assert.NotNil(t, err, "expected Validate error for a zero interval")
+	})
+	t.Run("ConnFactory validation", func(t *testing.T) {
+		t.Parallel()
+
+		factory := func(context.Context, string) (net.Conn, error) { return nil, nil }
+		err := Client().SetConnFactory(factory).Validate()
+		assert.Nil(t, err, "Validate error for a ConnFactory: %v", err)
+
+		err = Client().SetConnFactory(factory).SetDialer(&net.Dialer{}).Validate()
+		assert.NotNil(t, err, "expected Validate error for both a ConnFactory and a Dialer")
+	})
+	t.Run("unix socket host", func(t *testing.T) {
+		t.Parallel()
+
+		opts := Client().ApplyURI("mongodb://%2Ftmp%2Fmongodb-27017.sock")
+		args, err := getOptions[ClientOptions](opts)
+		assert.Nil(t, err, "error constructing options: %v", err)
+		assert.Equal(t, []string{"/tmp/mongodb-27017.sock"}, args.Hosts, "hosts mismatch")
+	})
 	t.Run("OIDC auth configuration validation", func(t *testing.T) {
 		t.Parallel()
 
@@ -22,6 +22,37 @@ type CollectionOptions struct {
 	ReadPreference *readpref.ReadPref
 	BSONOptions    *BSONOptions
 	Registry       *bson.Registry
+	ETag           *ETagOptions
+	Transformers   []FieldTransformer
+}
+
+// ETagOptions configures the content hash that a Collection stores in the documents it writes. See
+// CollectionOptionsBuilder.SetETag for more information.
+type ETagOptions struct {
+	// Field is the name of the top-level field that stores the hash. The default is "_etag".
+	Field string
+
+	// Exclude contains the names of top-level fields that are not hashed, e.g. timestamps that
+	// change without a change to the content of the document. The _id field and Field are never
+	// hashed.
+	Exclude []string
+}
+
+// FieldTransformer derives a field of the documents written by a Collection from another field,
+// e.g. to store a searchable token of a sensitive field. See
+// CollectionOptionsBuilder.SetFieldTransformers for more information.
+type FieldTransformer struct {
+	// Field is the path of the source field, with the names of the embedded documents that contain
+	// it separated by dots. Paths through arrays are not supported.
+	Field string
+
+	// Target is the name of the field that stores the derived value. It is set in the document that
+	// contains the source field and must not contain dots.
+	Target string
+
+	// Transform returns the derived value for the value of the source field. It should be
+	// deterministic so that the derived value can be queried.
+	Transform func(val bson.RawValue) (interface{}, error)
 }
 
 // CollectionOptionsBuilder contains options to configure a Collection instance.
@@ -103,3 +134,39 @@ func (c *CollectionOptionsBuilder) SetRegistry(r *bson.Registry) *CollectionOpti
 	})
 	return c
 }
+
+// SetETag sets the value for the ETag field. ETag configures the Collection to compute a hash of
+// the content of each document written by InsertOne, InsertMany, ReplaceOne, FindOneAndReplace, and
+// the InsertOneModel and ReplaceOneModel models of BulkWrite, and to store it in the document. The
+// hash is insensitive to the order of the fields of the document and of its embedded documents, so
+// it can be used as an HTTP entity tag for the document with the conditional ReplaceOneIfMatch,
+// UpdateOneIfMatch, and DeleteOneIfMatch methods of Collection. The default value is nil, which
+// means that no hash is stored.
+func (c *CollectionOptionsBuilder) SetETag(eo *ETagOptions) *CollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CollectionOptions) error {
+		opts.ETag = eo
+
+		return nil
+	})
+	return c
+}
+
+// SetFieldTransformers sets the value for the Transformers field. Transformers derive fields from
+// the fields of the documents written by InsertOne, InsertMany, ReplaceOne, FindOneAndReplace, and
+// the InsertOneModel and ReplaceOneModel models of BulkWrite, and from the fields set with $set or
+// $setOnInsert by UpdateOne, UpdateMany, FindOneAndUpdate, and the UpdateOneModel and
+// UpdateManyModel models of BulkWrite. A field unset with $unset also unsets its derived field.
+// Updates with an aggregation pipeline are not transformed.
+//
+// The derived fields are stripped from the documents decoded with the Decode methods of the cursors
+// and results of Find, FindOne, and the FindOneAnd methods, but not from their raw documents.
+// Collection.FieldToken returns the derived value of a field to query it. The default value is
+// nil, which means that no fields are derived.
+func (c *CollectionOptionsBuilder) SetFieldTransformers(transformers ...FieldTransformer) *CollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CollectionOptions) error {
+		opts.Transformers = transformers
+
+		return nil
+	})
+	return c
+}
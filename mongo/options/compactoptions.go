@@ -0,0 +1,77 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "errors"
+
+// CompactOptions represents arguments that can be used to configure a Compact operation.
+//
+// See corresponding setter methods for documentation.
+type CompactOptions struct {
+	Force             *bool
+	FreeSpaceTargetMB *int64
+	DryRun            *bool
+}
+
+// CompactOptionsBuilder contains options to configure compact operations. Each option can be set
+// through setter functions. See documentation for each setter function for an explanation of the
+// option.
+type CompactOptionsBuilder struct {
+	Opts []func(*CompactOptions) error
+}
+
+// Compact creates a new CompactOptions instance.
+func Compact() *CompactOptionsBuilder {
+	return &CompactOptionsBuilder{}
+}
+
+// List returns a list of CompactOptions setter functions.
+func (c *CompactOptionsBuilder) List() []func(*CompactOptions) error {
+	return c.Opts
+}
+
+// SetForce sets the value for the Force field. If true, compact is allowed to run on the primary
+// of a replica set, where it can block or slow down the operations of the application. The default
+// is false, so the operation fails if the read preference of the collection selects a primary.
+func (c *CompactOptionsBuilder) SetForce(force bool) *CompactOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CompactOptions) error {
+		opts.Force = &force
+
+		return nil
+	})
+
+	return c
+}
+
+// SetFreeSpaceTargetMB sets the value for the FreeSpaceTargetMB field. It specifies the minimum
+// amount of storage space, in megabytes, that must be recoverable for compaction to proceed. It
+// must be positive. This option is only valid for MongoDB versions >= 7.0.
+func (c *CompactOptionsBuilder) SetFreeSpaceTargetMB(mb int64) *CompactOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CompactOptions) error {
+		if mb <= 0 {
+			return errors.New("free space target must be positive")
+		}
+		opts.FreeSpaceTargetMB = &mb
+
+		return nil
+	})
+
+	return c
+}
+
+// SetDryRun sets the value for the DryRun field. If true, the server only estimates the space
+// that compaction would free, which is reported in CompactResult.EstimatedBytesFreed. This option
+// is only valid for MongoDB versions >= 8.0.
+func (c *CompactOptionsBuilder) SetDryRun(dryRun bool) *CompactOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CompactOptions) error {
+		opts.DryRun = &dryRun
+
+		return nil
+	})
+
+	return c
+}
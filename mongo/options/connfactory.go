@@ -0,0 +1,51 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ErrConnsClosed is returned by the ConnFactory created by ConnFactoryFromConns when the channel of
+// connections is closed.
+var ErrConnsClosed = errors.New("no more connections: the channel of connections is closed")
+
+// ConnFactoryFromConns returns a ConnFactory that uses the connections received from conns, which
+// are already established by a custom transport such as a QUIC tunnel or an SSH port forward. The
+// address a connection is requested for is ignored, so conns must only deliver connections to one
+// server, which is typically used with a direct connection. The ConnFactory waits for a connection
+// until the context of the request is done, and returns ErrConnsClosed once conns is closed.
+//
+// A Client needs a connection for each pooled connection and two to monitor each server, so conns
+// must deliver connections as long as the Client is in use.
+func ConnFactoryFromConns(conns <-chan net.Conn) ConnFactory {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		select {
+		case conn, ok := <-conns:
+			if !ok {
+				return nil, ErrConnsClosed
+			}
+			return conn, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// PipeConnFactory returns a ConnFactory that connects to an in-memory server. For each connection,
+// it creates a net.Pipe, calls serve with the server end of the pipe and the requested address in
+// a new goroutine, and returns the client end. serve should implement the wire protocol and close
+// the connection when it is done.
+func PipeConnFactory(serve func(conn net.Conn, address string)) ConnFactory {
+	return func(_ context.Context, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go serve(server, address)
+		return client, nil
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestConnFactoryFromConns(t *testing.T) {
+	t.Parallel()
+
+	conns := make(chan net.Conn, 1)
+	factory := ConnFactoryFromConns(conns)
+
+	client, server := net.Pipe()
+	defer server.Close()
+	conns <- client
+	got, err := factory(context.Background(), "localhost:27017")
+	require.NoError(t, err, "factory error")
+	assert.Equal(t, client, got, "expected the connection from the channel")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = factory(ctx, "localhost:27017")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected a deadline error, got %v", err)
+
+	close(conns)
+	_, err = factory(context.Background(), "localhost:27017")
+	assert.True(t, errors.Is(err, ErrConnsClosed), "expected ErrConnsClosed, got %v", err)
+}
+
+func TestPipeConnFactory(t *testing.T) {
+	t.Parallel()
+
+	addrs := make(chan string, 1)
+	factory := PipeConnFactory(func(conn net.Conn, address string) {
+		defer conn.Close()
+		addrs <- address
+		_, _ = conn.Write([]byte("hello"))
+	})
+
+	conn, err := factory(context.Background(), "memory:27017")
+	require.NoError(t, err, "factory error")
+	defer conn.Close()
+
+	buf := make([]byte, 5)
+	_, err = conn.Read(buf)
+	require.NoError(t, err, "Read error")
+	assert.Equal(t, "hello", string(buf), "expected to read from the server end")
+	assert.Equal(t, "memory:27017", <-addrs, "expected serve to be called with the address")
+}
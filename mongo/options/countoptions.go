@@ -6,16 +6,25 @@
 
 package options
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+)
+
 // CountOptions represents arguments that can be used to configure a
 // CountDocuments operation.
 //
 // See corresponding setter methods for documentation.
 type CountOptions struct {
-	Collation *Collation
-	Comment   interface{}
-	Hint      interface{}
-	Limit     *int64
-	Skip      *int64
+	Collation      *Collation
+	Comment        interface{}
+	Hint           interface{}
+	Let            interface{}
+	Limit          *int64
+	Skip           *int64
+	ReadConcern    *readconcern.ReadConcern
+	LocalThreshold *time.Duration
 }
 
 // CountOptionsBuilder contains options to configure count operations. Each
@@ -76,6 +85,22 @@ func (co *CountOptionsBuilder) SetHint(h interface{}) *CountOptionsBuilder {
 	return co
 }
 
+// SetLet sets the value for the Let field. Specifies parameters for the filter, which is run in
+// the $match stage of an aggregation. This option is only valid for MongoDB versions >= 5.0.
+// Older servers will report an error for using this option. This must be a document mapping
+// parameter names to values. Values must be constant or closed expressions that do not
+// reference document fields. Parameters can then be accessed as variables in an aggregate
+// expression context (e.g. "$$var").
+func (co *CountOptionsBuilder) SetLet(let interface{}) *CountOptionsBuilder {
+	co.Opts = append(co.Opts, func(opts *CountOptions) error {
+		opts.Let = let
+
+		return nil
+	})
+
+	return co
+}
+
 // SetLimit sets the value for the Limit field. Specifies the maximum number of documents to count. The
 // default value is 0, which means that there is no limit and all documents matching the filter will be
 // counted.
@@ -100,3 +125,32 @@ func (co *CountOptionsBuilder) SetSkip(i int64) *CountOptionsBuilder {
 
 	return co
 }
+
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (co *CountOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *CountOptionsBuilder {
+	co.Opts = append(co.Opts, func(opts *CountOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return co
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (co *CountOptionsBuilder) SetLocalThreshold(d time.Duration) *CountOptionsBuilder {
+	co.Opts = append(co.Opts, func(opts *CountOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return co
+}
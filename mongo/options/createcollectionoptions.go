@@ -8,6 +8,8 @@ package options
 
 import (
 	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // DefaultIndexOptions represents the default arguments for a collection to
@@ -142,6 +144,55 @@ func (tso *TimeSeriesOptionsBuilder) SetBucketRounding(dur time.Duration) *TimeS
 	return tso
 }
 
+// ClusteredIndexOptions specifies the clustered index of a clustered collection.
+//
+// See corresponding setter methods for documentation.
+type ClusteredIndexOptions struct {
+	Key  interface{}
+	Name *string
+}
+
+// ClusteredIndexOptionsBuilder contains options to configure the clustered index of a collection.
+// Each option can be set through setter functions. See documentation for each setter function for
+// an explanation of the option.
+type ClusteredIndexOptionsBuilder struct {
+	Opts []func(*ClusteredIndexOptions) error
+}
+
+// ClusteredIndex creates a new ClusteredIndexOptions instance.
+func ClusteredIndex() *ClusteredIndexOptionsBuilder {
+	return &ClusteredIndexOptionsBuilder{}
+}
+
+// List returns a list of ClusteredIndexOptions setter functions.
+func (ci *ClusteredIndexOptionsBuilder) List() []func(*ClusteredIndexOptions) error {
+	return ci.Opts
+}
+
+// SetKey sets the value for the Key field. Specifies the key of the clustered index. The server
+// only supports clustering on _id, so the default value of {_id: 1} is the only valid key.
+func (ci *ClusteredIndexOptionsBuilder) SetKey(key interface{}) *ClusteredIndexOptionsBuilder {
+	ci.Opts = append(ci.Opts, func(opts *ClusteredIndexOptions) error {
+		opts.Key = key
+
+		return nil
+	})
+
+	return ci
+}
+
+// SetName sets the value for the Name field. Specifies the name of the clustered index. The default
+// value is nil, which means that the server generates a name.
+func (ci *ClusteredIndexOptionsBuilder) SetName(name string) *ClusteredIndexOptionsBuilder {
+	ci.Opts = append(ci.Opts, func(opts *ClusteredIndexOptions) error {
+		opts.Name = &name
+
+		return nil
+	})
+
+	return ci
+}
+
 // CreateCollectionOptions represents arguments that can be used to configure a
 // CreateCollection operation.
 //
@@ -222,6 +273,20 @@ func (c *CreateCollectionOptionsBuilder) SetChangeStreamPreAndPostImages(csppi i
 	return c
 }
 
+// SetChangeStreamPreAndPostImagesEnabled sets the value for the ChangeStreamPreAndPostImages field
+// to {enabled: <enabled>}. If enabled is true, change streams opened against the collection can
+// return pre- and post-images of updated documents. This option is only valid for MongoDB versions
+// >= 6.0.
+func (c *CreateCollectionOptionsBuilder) SetChangeStreamPreAndPostImagesEnabled(enabled bool) *CreateCollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CreateCollectionOptions) error {
+		opts.ChangeStreamPreAndPostImages = bson.D{{"enabled", enabled}}
+
+		return nil
+	})
+
+	return c
+}
+
 // SetDefaultIndexOptions sets the value for the DefaultIndexOptions field. Specifies a default
 // configuration for indexes on the collection. This option is only valid for MongoDB versions
 // >= 3.4. The default value is nil, meaning indexes will be configured using server defaults.
@@ -317,6 +382,24 @@ func (c *CreateCollectionOptionsBuilder) SetValidator(validator interface{}) *Cr
 	return c
 }
 
+// SetJSONSchemaValidator sets the value for the Validator field to a $jsonSchema validator generated
+// from the Go type of v, which must be a struct or a pointer to a struct. See JSONSchema for how the
+// schema is generated. An error is returned when the options are applied if a schema cannot be
+// generated for v.
+func (c *CreateCollectionOptionsBuilder) SetJSONSchemaValidator(v interface{}) *CreateCollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CreateCollectionOptions) error {
+		schema, err := JSONSchema(v)
+		if err != nil {
+			return err
+		}
+		opts.Validator = bson.D{{"$jsonSchema", schema}}
+
+		return nil
+	})
+
+	return c
+}
+
 // SetExpireAfterSeconds sets the value for the ExpireAfterSeconds field. Specifies value
 // indicating after how many seconds old time-series data should be deleted.
 // See https://www.mongodb.com/docs/manual/reference/command/create/ for supported options,
@@ -377,6 +460,34 @@ func (c *CreateCollectionOptionsBuilder) SetClusteredIndex(clusteredIndex interf
 	return c
 }
 
+// SetClusteredIndexOptions sets the value for the ClusteredIndex field from a typed builder. The
+// clustered index is always unique. It is an alternative to SetClusteredIndex, and the last call
+// to either method takes precedence.
+//
+// This option is only valid for MongoDB versions >= 5.3
+func (c *CreateCollectionOptionsBuilder) SetClusteredIndexOptions(ci *ClusteredIndexOptionsBuilder) *CreateCollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CreateCollectionOptions) error {
+		args, err := getOptions[ClusteredIndexOptions](ci)
+		if err != nil {
+			return err
+		}
+
+		var key interface{} = bson.D{{"_id", 1}}
+		if args.Key != nil {
+			key = args.Key
+		}
+		doc := bson.D{{"key", key}, {"unique", true}}
+		if args.Name != nil {
+			doc = append(doc, bson.E{"name", *args.Name})
+		}
+		opts.ClusteredIndex = doc
+
+		return nil
+	})
+
+	return c
+}
+
 // CreateViewOptions represents arguments that can be used to configure a
 // CreateView operation.
 //
@@ -6,6 +6,8 @@
 
 package options
 
+import "errors"
+
 // DataKeyOptions represents all possible options used to create a new data key.
 //
 // See corresponding setter methods for documentation.
@@ -71,6 +73,9 @@ func (dk *DataKeyOptionsBuilder) List() []func(*DataKeyOptions) error {
 //	}
 //
 // If unset, "keyVersion" defaults to the key's primary version and "endpoint" defaults to "cloudkms.googleapis.com".
+//
+// SetAWSMasterKey, SetAzureMasterKey, SetGCPMasterKey, and SetKMIPMasterKey set the master key from a typed struct
+// and validate its required fields, and should be preferred over this setter.
 func (dk *DataKeyOptionsBuilder) SetMasterKey(masterKey interface{}) *DataKeyOptionsBuilder {
 	dk.Opts = append(dk.Opts, func(opts *DataKeyOptions) error {
 		opts.MasterKey = masterKey
@@ -81,6 +86,43 @@ func (dk *DataKeyOptionsBuilder) SetMasterKey(masterKey interface{}) *DataKeyOpt
 	return dk
 }
 
+// SetAWSMasterKey sets the MasterKey field to an AWS KMS key. It returns an error when the options are applied if a
+// required field is missing.
+func (dk *DataKeyOptionsBuilder) SetAWSMasterKey(masterKey AWSMasterKey) *DataKeyOptionsBuilder {
+	return dk.setTypedMasterKey(masterKey.Validate, masterKey)
+}
+
+// SetAzureMasterKey sets the MasterKey field to an Azure Key Vault key. It returns an error when the options are
+// applied if a required field is missing.
+func (dk *DataKeyOptionsBuilder) SetAzureMasterKey(masterKey AzureMasterKey) *DataKeyOptionsBuilder {
+	return dk.setTypedMasterKey(masterKey.Validate, masterKey)
+}
+
+// SetGCPMasterKey sets the MasterKey field to a Google Cloud KMS key. It returns an error when the options are applied
+// if a required field is missing.
+func (dk *DataKeyOptionsBuilder) SetGCPMasterKey(masterKey GCPMasterKey) *DataKeyOptionsBuilder {
+	return dk.setTypedMasterKey(masterKey.Validate, masterKey)
+}
+
+// SetKMIPMasterKey sets the MasterKey field to a KMIP managed object. It returns an error when the options are applied
+// if the endpoint is invalid.
+func (dk *DataKeyOptionsBuilder) SetKMIPMasterKey(masterKey KMIPMasterKey) *DataKeyOptionsBuilder {
+	return dk.setTypedMasterKey(masterKey.Validate, masterKey)
+}
+
+func (dk *DataKeyOptionsBuilder) setTypedMasterKey(validate func() error, masterKey interface{}) *DataKeyOptionsBuilder {
+	dk.Opts = append(dk.Opts, func(opts *DataKeyOptions) error {
+		if err := validate(); err != nil {
+			return err
+		}
+		opts.MasterKey = masterKey
+
+		return nil
+	})
+
+	return dk
+}
+
 // SetKeyAltNames specifies an optional list of string alternate names used to reference a key. If a key is created'
 // with alternate names, encryption may refer to the key by a unique alternate name instead of by _id.
 func (dk *DataKeyOptionsBuilder) SetKeyAltNames(keyAltNames []string) *DataKeyOptionsBuilder {
@@ -105,3 +147,58 @@ func (dk *DataKeyOptionsBuilder) SetKeyMaterial(keyMaterial []byte) *DataKeyOpti
 
 	return dk
 }
+
+// CreateDataKeysOptions represents all possible options used to create data keys in bulk.
+//
+// See corresponding setter methods for documentation.
+type CreateDataKeysOptions struct {
+	Concurrency          *int
+	KMSRequestsPerSecond *float64
+}
+
+// CreateDataKeysOptionsBuilder contains options to configure CreateDataKeys operations. Each option can be set
+// through setter functions. See documentation for each setter function for an explanation of the option.
+type CreateDataKeysOptionsBuilder struct {
+	Opts []func(*CreateDataKeysOptions) error
+}
+
+// CreateDataKeys creates a new CreateDataKeysOptions instance.
+func CreateDataKeys() *CreateDataKeysOptionsBuilder {
+	return &CreateDataKeysOptionsBuilder{}
+}
+
+// List returns a list of CreateDataKeys setter functions.
+func (cdk *CreateDataKeysOptionsBuilder) List() []func(*CreateDataKeysOptions) error {
+	return cdk.Opts
+}
+
+// SetConcurrency sets the value for the Concurrency field. Concurrency is the maximum number of key documents created
+// at the same time, each of which makes a request to the KMS provider. The default value is 8.
+func (cdk *CreateDataKeysOptionsBuilder) SetConcurrency(n int) *CreateDataKeysOptionsBuilder {
+	cdk.Opts = append(cdk.Opts, func(opts *CreateDataKeysOptions) error {
+		if n < 1 {
+			return errors.New("data key creation concurrency must be at least 1")
+		}
+		opts.Concurrency = &n
+
+		return nil
+	})
+
+	return cdk
+}
+
+// SetKMSRequestsPerSecond sets the value for the KMSRequestsPerSecond field. KMSRequestsPerSecond limits the rate at
+// which key documents are created, and therefore the rate of requests to the KMS provider, to stay within the request
+// quotas of cloud KMS providers. The default value is 0, which does not limit the rate.
+func (cdk *CreateDataKeysOptionsBuilder) SetKMSRequestsPerSecond(rate float64) *CreateDataKeysOptionsBuilder {
+	cdk.Opts = append(cdk.Opts, func(opts *CreateDataKeysOptions) error {
+		if rate < 0 {
+			return errors.New("KMS request rate must not be negative")
+		}
+		opts.KMSRequestsPerSecond = &rate
+
+		return nil
+	})
+
+	return cdk
+}
@@ -0,0 +1,45 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// UnshardCollectionOptions represents arguments that can be used to configure an UnshardCollection
+// operation.
+//
+// See corresponding setter methods for documentation.
+type UnshardCollectionOptions struct {
+	ToShard *string
+}
+
+// UnshardCollectionOptionsBuilder contains arguments to configure unshardCollection operations.
+// Each option can be set through setter functions. See documentation for each setter function for
+// an explanation of the option.
+type UnshardCollectionOptionsBuilder struct {
+	Opts []func(*UnshardCollectionOptions) error
+}
+
+// UnshardCollection creates a new UnshardCollectionOptions instance.
+func UnshardCollection() *UnshardCollectionOptionsBuilder {
+	return &UnshardCollectionOptionsBuilder{}
+}
+
+// List returns a list of UnshardCollectionOptions setter functions.
+func (u *UnshardCollectionOptionsBuilder) List() []func(*UnshardCollectionOptions) error {
+	return u.Opts
+}
+
+// SetToShard sets the value for the ToShard field. Specifies the shard the collection is moved to
+// after it is unsharded. The default value is nil, which means that the shard with the least amount
+// of data is chosen.
+func (u *UnshardCollectionOptionsBuilder) SetToShard(shard string) *UnshardCollectionOptionsBuilder {
+	u.Opts = append(u.Opts, func(opts *UnshardCollectionOptions) error {
+		opts.ToShard = &shard
+
+		return nil
+	})
+
+	return u
+}
@@ -6,15 +6,18 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+
 // DeleteOneOptions represents arguments that can be used to configure DeleteOne
 // operations.
 //
 // See corresponding setter methods for documentation.
 type DeleteOneOptions struct {
-	Collation *Collation
-	Comment   interface{}
-	Hint      interface{}
-	Let       interface{}
+	Collation    *Collation
+	Comment      interface{}
+	Hint         interface{}
+	Let          interface{}
+	WriteConcern *writeconcern.WriteConcern
 }
 
 // DeleteOneOptionsBuilder contains options to configure DeleteOne operations. Each
@@ -94,15 +97,30 @@ func (do *DeleteOneOptionsBuilder) SetLet(let interface{}) *DeleteOneOptionsBuil
 	return do
 }
 
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (do *DeleteOneOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *DeleteOneOptionsBuilder {
+	do.Opts = append(do.Opts, func(opts *DeleteOneOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return do
+}
+
 // DeleteManyOptions represents arguments that can be used to configure DeleteMany
 // operations.
 //
 // See corresponding setter methods for documentation.
 type DeleteManyOptions struct {
-	Collation *Collation
-	Comment   interface{}
-	Hint      interface{}
-	Let       interface{}
+	Collation    *Collation
+	Comment      interface{}
+	Hint         interface{}
+	Let          interface{}
+	WriteConcern *writeconcern.WriteConcern
 }
 
 // DeleteManyOptionsBuilder contains options to configure DeleteMany operations.
@@ -181,3 +199,17 @@ func (do *DeleteManyOptionsBuilder) SetLet(let interface{}) *DeleteManyOptionsBu
 
 	return do
 }
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (do *DeleteManyOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *DeleteManyOptionsBuilder {
+	do.Opts = append(do.Opts, func(opts *DeleteManyOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return do
+}
@@ -6,13 +6,21 @@
 
 package options
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+)
+
 // DistinctOptions represents arguments that can be used to configure a Distinct
 // operation.
 //
 // See corresponding setter methods for documentation.
 type DistinctOptions struct {
-	Collation *Collation
-	Comment   interface{}
+	Collation      *Collation
+	Comment        interface{}
+	ReadConcern    *readconcern.ReadConcern
+	LocalThreshold *time.Duration
 }
 
 // DistinctOptionsBuilder contains options to configure distinct operations. Each
@@ -60,3 +68,32 @@ func (do *DistinctOptionsBuilder) SetComment(comment interface{}) *DistinctOptio
 
 	return do
 }
+
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (do *DistinctOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *DistinctOptionsBuilder {
+	do.Opts = append(do.Opts, func(opts *DistinctOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return do
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (do *DistinctOptionsBuilder) SetLocalThreshold(d time.Duration) *DistinctOptionsBuilder {
+	do.Opts = append(do.Opts, func(opts *DistinctOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return do
+}
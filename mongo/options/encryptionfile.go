@@ -0,0 +1,581 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EncryptionFileOptions configures how LoadEncryptedFieldsMap and LoadSchemaMap load and validate
+// files.
+type EncryptionFileOptions struct {
+	// ServerVersion is the version of the servers the definitions are used with, such as "7.0".
+	// If set, query types that the version does not support are rejected. If empty, all known
+	// query types are accepted.
+	ServerVersion string
+
+	// UnmarshalYAML unmarshals the files with the ".yaml" or ".yml" extension, such as
+	// yaml.Unmarshal from gopkg.in/yaml.v3. The driver does not depend on a YAML library, so
+	// loading a YAML file without UnmarshalYAML returns an error. Other files are read as
+	// Extended JSON.
+	UnmarshalYAML func(data []byte, v interface{}) error
+
+	// LookupEnv looks up the environment variables interpolated in key IDs. The default is
+	// os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+}
+
+// queryTypeVersions maps the Queryable Encryption query types to the first server version that
+// supports them and the first server version that no longer does, which is empty if the query
+// type is still supported.
+var queryTypeVersions = map[string][2]string{
+	"equality":         {"7.0", ""},
+	"rangePreview":     {"7.0", "8.0"},
+	"range":            {"8.0", ""},
+	"prefixPreview":    {"8.2", ""},
+	"suffixPreview":    {"8.2", ""},
+	"substringPreview": {"8.2", ""},
+}
+
+var (
+	encryptedFieldsKeys = fieldSet("escCollection", "eccCollection", "ecocCollection", "fields", "strEncodeVersion")
+	encryptedFieldKeys  = fieldSet("path", "bsonType", "keyId", "queries")
+	encryptedQueryKeys  = fieldSet(
+		"queryType", "contention", "min", "max", "sparsity", "precision", "trimFactor",
+		"strMaxLength", "strMinQueryLength", "strMaxQueryLength", "caseSensitive", "diacriticSensitive")
+	encryptKeys         = fieldSet("keyId", "algorithm", "bsonType")
+	encryptMetadataKeys = fieldSet("keyId", "algorithm")
+
+	encryptionAlgorithms = fieldSet(
+		"AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic",
+		"AEAD_AES_256_CBC_HMAC_SHA_512-Random")
+
+	envVarRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+func fieldSet(keys ...string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// LoadEncryptedFieldsMap loads an EncryptedFieldsMap for Queryable Encryption from the file at
+// path, which holds a document that maps "db.collection" namespaces to encryptedFields documents.
+// The file is validated strictly: unknown fields, invalid namespaces, missing paths or BSON types,
+// and unsupported query types are errors.
+//
+// The "keyId" of a field can be a UUID binary, a UUID string, a base64-encoded UUID, or
+// KeyAltNamePlaceholder. References to environment variables of the form ${NAME} in key ID strings
+// are replaced with the value of the variable, and referencing an unset variable is an error.
+//
+// The returned map can be passed to AutoEncryptionOptionsBuilder.SetEncryptedFieldsMap.
+func LoadEncryptedFieldsMap(path string, opts *EncryptionFileOptions) (map[string]interface{}, error) {
+	return loadEncryptionFile(path, opts, parseEncryptedFields)
+}
+
+// LoadSchemaMap loads a SchemaMap for Client-Side Field Level Encryption from the file at path,
+// which holds a document that maps "db.collection" namespaces to $jsonSchema documents. The
+// "encrypt" and "encryptMetadata" keywords of the schemas are validated strictly and their key IDs
+// are interpolated as described in LoadEncryptedFieldsMap. Other keywords are not validated.
+//
+// The returned map can be passed to AutoEncryptionOptionsBuilder.SetSchemaMap.
+func LoadSchemaMap(path string, opts *EncryptionFileOptions) (map[string]interface{}, error) {
+	return loadEncryptionFile(path, opts, parseSchema)
+}
+
+// encryptionFileLoader parses and validates the document of a namespace in an encryption file.
+type encryptionFileLoader struct {
+	serverVersion []int
+	lookupEnv     func(string) (string, bool)
+}
+
+func loadEncryptionFile(
+	path string,
+	opts *EncryptionFileOptions,
+	parse func(*encryptionFileLoader, string, bson.Raw) (bson.D, error),
+) (map[string]interface{}, error) {
+	if opts == nil {
+		opts = &EncryptionFileOptions{}
+	}
+	l := &encryptionFileLoader{lookupEnv: opts.LookupEnv}
+	if l.lookupEnv == nil {
+		l.lookupEnv = os.LookupEnv
+	}
+	if opts.ServerVersion != "" {
+		v, err := parseServerVersion(opts.ServerVersion)
+		if err != nil {
+			return nil, err
+		}
+		l.serverVersion = v
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeEncryptionFile(path, data, opts.UnmarshalYAML)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	result := make(map[string]interface{}, len(elems))
+	for _, elem := range elems {
+		ns := elem.Key()
+		if db, coll, ok := strings.Cut(ns, "."); !ok || db == "" || coll == "" {
+			return nil, fmt.Errorf("%s: invalid namespace %q: must be of the form \"db.collection\"", path, ns)
+		}
+		nsDoc, ok := elem.Value().DocumentOK()
+		if !ok {
+			return nil, fmt.Errorf("%s: %s: expected a document, got BSON type %s", path, ns, elem.Value().Type)
+		}
+		parsed, err := parse(l, ns, nsDoc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		result[ns] = parsed
+	}
+	return result, nil
+}
+
+// decodeEncryptionFile decodes data, the content of the file at path, to a BSON document.
+func decodeEncryptionFile(path string, data []byte, unmarshalYAML func([]byte, interface{}) error) (bson.Raw, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if unmarshalYAML == nil {
+			return nil, fmt.Errorf("loading YAML files requires EncryptionFileOptions.UnmarshalYAML")
+		}
+		var v interface{}
+		if err := unmarshalYAML(data, &v); err != nil {
+			return nil, err
+		}
+		doc, err := yamlToDocument(v)
+		if err != nil {
+			return nil, err
+		}
+		return bson.Marshal(doc)
+	default:
+		var doc bson.Raw
+		if err := bson.UnmarshalExtJSON(data, false, &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+}
+
+// yamlToDocument converts the value of a YAML document to a BSON-marshalable value. YAML libraries
+// decode mappings to map[string]interface{} or map[interface{}]interface{}, the latter of which
+// cannot be marshaled.
+func yamlToDocument(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(bson.M, len(v))
+		for key, val := range v {
+			conv, err := yamlToDocument(val)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = conv
+		}
+		return m, nil
+	case map[interface{}]interface{}:
+		m := make(bson.M, len(v))
+		for key, val := range v {
+			s, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid key %v: keys must be strings", key)
+			}
+			conv, err := yamlToDocument(val)
+			if err != nil {
+				return nil, err
+			}
+			m[s] = conv
+		}
+		return m, nil
+	case []interface{}:
+		a := make(bson.A, len(v))
+		for i, val := range v {
+			conv, err := yamlToDocument(val)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = conv
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+// parseEncryptedFields validates the encryptedFields document of namespace ns and returns it with
+// the key IDs resolved.
+func parseEncryptedFields(l *encryptionFileLoader, ns string, doc bson.Raw) (bson.D, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var result bson.D
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		if !encryptedFieldsKeys[key] {
+			return nil, fmt.Errorf("%s: unknown field %q", ns, key)
+		}
+		if key != "fields" {
+			result = append(result, bson.E{Key: key, Value: val})
+			continue
+		}
+
+		arr, ok := val.ArrayOK()
+		if !ok {
+			return nil, fmt.Errorf("%s.fields: expected an array, got BSON type %s", ns, val.Type)
+		}
+		values, err := arr.Values()
+		if err != nil {
+			return nil, err
+		}
+		fields := make(bson.A, 0, len(values))
+		for i, v := range values {
+			fieldDoc, ok := v.DocumentOK()
+			if !ok {
+				return nil, fmt.Errorf("%s.fields.%d: expected a document, got BSON type %s", ns, i, v.Type)
+			}
+			field, err := l.parseEncryptedField(fmt.Sprintf("%s.fields.%d", ns, i), fieldDoc)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+		result = append(result, bson.E{Key: key, Value: fields})
+	}
+	return result, nil
+}
+
+// parseEncryptedField validates the encrypted field document at loc and returns it with the key ID
+// resolved.
+func (l *encryptionFileLoader) parseEncryptedField(loc string, doc bson.Raw) (bson.D, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var result bson.D
+	var hasPath, hasBSONType bool
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		if !encryptedFieldKeys[key] {
+			return nil, fmt.Errorf("%s: unknown field %q", loc, key)
+		}
+
+		switch key {
+		case "path", "bsonType":
+			if s, ok := val.StringValueOK(); !ok || s == "" {
+				return nil, fmt.Errorf("%s.%s: expected a non-empty string", loc, key)
+			}
+			hasPath = hasPath || key == "path"
+			hasBSONType = hasBSONType || key == "bsonType"
+		case "keyId":
+			keyID, err := l.keyID(loc+".keyId", val)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, bson.E{Key: key, Value: keyID})
+			continue
+		case "queries":
+			if err := l.validateQueries(loc+".queries", val); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, bson.E{Key: key, Value: val})
+	}
+	if !hasPath {
+		return nil, fmt.Errorf("%s: missing required field \"path\"", loc)
+	}
+	if !hasBSONType {
+		return nil, fmt.Errorf("%s: missing required field \"bsonType\"", loc)
+	}
+	return result, nil
+}
+
+// validateQueries validates the queries of an encrypted field, which can be a query document or an
+// array of query documents.
+func (l *encryptionFileLoader) validateQueries(loc string, val bson.RawValue) error {
+	var queries []bson.RawValue
+	switch val.Type {
+	case bson.TypeEmbeddedDocument:
+		queries = []bson.RawValue{val}
+	case bson.TypeArray:
+		values, err := val.Array().Values()
+		if err != nil {
+			return err
+		}
+		queries = values
+	default:
+		return fmt.Errorf("%s: expected a document or an array, got BSON type %s", loc, val.Type)
+	}
+
+	for i, q := range queries {
+		qloc := loc
+		if val.Type == bson.TypeArray {
+			qloc = fmt.Sprintf("%s.%d", loc, i)
+		}
+		doc, ok := q.DocumentOK()
+		if !ok {
+			return fmt.Errorf("%s: expected a document, got BSON type %s", qloc, q.Type)
+		}
+		elems, err := doc.Elements()
+		if err != nil {
+			return err
+		}
+		var queryType string
+		for _, elem := range elems {
+			if !encryptedQueryKeys[elem.Key()] {
+				return fmt.Errorf("%s: unknown field %q", qloc, elem.Key())
+			}
+			if elem.Key() == "queryType" {
+				queryType, _ = elem.Value().StringValueOK()
+			}
+		}
+		if err := l.validateQueryType(queryType); err != nil {
+			return fmt.Errorf("%s.queryType: %w", qloc, err)
+		}
+	}
+	return nil
+}
+
+func (l *encryptionFileLoader) validateQueryType(queryType string) error {
+	if queryType == "" {
+		return fmt.Errorf("expected a non-empty string")
+	}
+	versions, ok := queryTypeVersions[queryType]
+	if !ok {
+		return fmt.Errorf("unsupported query type %q", queryType)
+	}
+	if l.serverVersion == nil {
+		return nil
+	}
+	first, _ := parseServerVersion(versions[0])
+	if compareServerVersions(l.serverVersion, first) < 0 {
+		return fmt.Errorf("query type %q requires server version %s or later", queryType, versions[0])
+	}
+	if versions[1] != "" {
+		removed, _ := parseServerVersion(versions[1])
+		if compareServerVersions(l.serverVersion, removed) >= 0 {
+			return fmt.Errorf("query type %q is not supported by server version %s or later", queryType, versions[1])
+		}
+	}
+	return nil
+}
+
+// parseSchema validates the encryption keywords of the $jsonSchema document of namespace ns and
+// returns it with the key IDs resolved.
+func parseSchema(l *encryptionFileLoader, ns string, doc bson.Raw) (bson.D, error) {
+	return l.parseSchemaDocument(ns, doc)
+}
+
+// parseSchemaDocument walks the schema document at loc, validating and resolving the "encrypt" and
+// "encryptMetadata" keywords and the keywords of nested schemas.
+func (l *encryptionFileLoader) parseSchemaDocument(loc string, doc bson.Raw) (bson.D, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(bson.D, 0, len(elems))
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		var conv interface{} = val
+		switch key {
+		case "encrypt", "encryptMetadata":
+			allowed := encryptKeys
+			if key == "encryptMetadata" {
+				allowed = encryptMetadataKeys
+			}
+			conv, err = l.parseEncrypt(loc+"."+key, val, allowed)
+		default:
+			conv, err = l.parseSchemaValue(loc+"."+key, val)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, bson.E{Key: key, Value: conv})
+	}
+	return result, nil
+}
+
+// parseSchemaValue resolves the nested schemas of a keyword value, which can be found in documents
+// and arrays of documents.
+func (l *encryptionFileLoader) parseSchemaValue(loc string, val bson.RawValue) (interface{}, error) {
+	switch val.Type {
+	case bson.TypeEmbeddedDocument:
+		return l.parseSchemaDocument(loc, val.Document())
+	case bson.TypeArray:
+		values, err := val.Array().Values()
+		if err != nil {
+			return nil, err
+		}
+		arr := make(bson.A, len(values))
+		for i, v := range values {
+			conv, err := l.parseSchemaValue(fmt.Sprintf("%s.%d", loc, i), v)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = conv
+		}
+		return arr, nil
+	default:
+		return val, nil
+	}
+}
+
+// parseEncrypt validates an "encrypt" or "encryptMetadata" document, whose fields must be in
+// allowed, and returns it with its key IDs resolved.
+func (l *encryptionFileLoader) parseEncrypt(loc string, val bson.RawValue, allowed map[string]bool) (bson.D, error) {
+	doc, ok := val.DocumentOK()
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a document, got BSON type %s", loc, val.Type)
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var result bson.D
+	for _, elem := range elems {
+		key, v := elem.Key(), elem.Value()
+		if !allowed[key] {
+			return nil, fmt.Errorf("%s: unknown field %q", loc, key)
+		}
+		switch key {
+		case "algorithm":
+			if s, _ := v.StringValueOK(); !encryptionAlgorithms[s] {
+				return nil, fmt.Errorf("%s.algorithm: unsupported algorithm %v", loc, v)
+			}
+		case "keyId":
+			// A string keyId is a JSON pointer to the field that holds the key alternate name.
+			if pointer, ok := v.StringValueOK(); ok {
+				if !strings.HasPrefix(pointer, "/") {
+					return nil, fmt.Errorf("%s.keyId: expected an array of UUIDs or a JSON pointer", loc)
+				}
+				break
+			}
+			arr, ok := v.ArrayOK()
+			if !ok {
+				return nil, fmt.Errorf("%s.keyId: expected an array of UUIDs or a JSON pointer", loc)
+			}
+			values, err := arr.Values()
+			if err != nil {
+				return nil, err
+			}
+			ids := make(bson.A, len(values))
+			for i, id := range values {
+				if ids[i], err = l.keyID(fmt.Sprintf("%s.keyId.%d", loc, i), id); err != nil {
+					return nil, err
+				}
+			}
+			result = append(result, bson.E{Key: key, Value: ids})
+			continue
+		}
+		result = append(result, bson.E{Key: key, Value: v})
+	}
+	return result, nil
+}
+
+// keyID resolves the key ID val at loc to a UUID binary, interpolating environment variables in
+// key ID strings. KeyAltNamePlaceholder is returned as is.
+func (l *encryptionFileLoader) keyID(loc string, val bson.RawValue) (interface{}, error) {
+	if subtype, data, ok := val.BinaryOK(); ok {
+		if subtype != bson.TypeBinaryUUID || len(data) != 16 {
+			return nil, fmt.Errorf("%s: expected a UUID binary", loc)
+		}
+		return val, nil
+	}
+	s, ok := val.StringValueOK()
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a UUID binary or string, got BSON type %s", loc, val.Type)
+	}
+
+	var missing string
+	s = envVarRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVarRegexp.FindStringSubmatch(ref)[1]
+		v, ok := l.lookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return v
+	})
+	if missing != "" {
+		return nil, fmt.Errorf("%s: environment variable %s is not set", loc, missing)
+	}
+	if s == KeyAltNamePlaceholder {
+		return s, nil
+	}
+
+	if id, err := parseKeyUUID(s); err == nil {
+		return bson.Binary{Subtype: bson.TypeBinaryUUID, Data: id}, nil
+	}
+	if id, err := base64.StdEncoding.DecodeString(s); err == nil && len(id) == 16 {
+		return bson.Binary{Subtype: bson.TypeBinaryUUID, Data: id}, nil
+	}
+	return nil, fmt.Errorf("%s: %q is not a UUID or a base64-encoded UUID", loc, s)
+}
+
+// parseKeyUUID parses a UUID in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form.
+func parseKeyUUID(s string) ([]byte, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return nil, fmt.Errorf("invalid UUID format")
+	}
+	return hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+}
+
+// parseServerVersion parses a server version of the form "major[.minor[.patch]]".
+func parseServerVersion(s string) ([]int, error) {
+	parts := strings.Split(s, ".")
+	v := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid server version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// compareServerVersions compares the server versions a and b, treating missing components as 0.
+func compareServerVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+const testKeyID = "2e2a8b0c-1c4f-4a3e-9d5b-7c1f0e6a9b3d"
+
+func writeEncryptionFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600), "WriteFile error")
+	return path
+}
+
+func testLookupEnv(key string) (string, bool) {
+	if key == "SSN_KEY_ID" {
+		return testKeyID, true
+	}
+	return "", false
+}
+
+func TestLoadEncryptedFieldsMap(t *testing.T) {
+	t.Parallel()
+
+	wantKeyID := bson.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{
+		0x2e, 0x2a, 0x8b, 0x0c, 0x1c, 0x4f, 0x4a, 0x3e, 0x9d, 0x5b, 0x7c, 0x1f, 0x0e, 0x6a, 0x9b, 0x3d,
+	}}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeEncryptionFile(t, "fields.json", `{
+			"db.users": {
+				"fields": [
+					{"path": "ssn", "bsonType": "string", "keyId": "${SSN_KEY_ID}", "queries": {"queryType": "equality"}},
+					{"path": "age", "bsonType": "int", "keyId": "$$keyAltName", "queries": [{"queryType": "range", "min": 0, "max": 150}]}
+				]
+			}
+		}`)
+		efm, err := LoadEncryptedFieldsMap(path, &EncryptionFileOptions{ServerVersion: "8.0", LookupEnv: testLookupEnv})
+		require.NoError(t, err, "LoadEncryptedFieldsMap error")
+
+		raw, err := bson.Marshal(efm["db.users"])
+		require.NoError(t, err, "Marshal error")
+		fields := bson.Raw(raw).Lookup("fields").Array()
+		subtype, data := fields.Index(0).Document().Lookup("keyId").Binary()
+		assert.Equal(t, wantKeyID, bson.Binary{Subtype: subtype, Data: data}, "resolved key ID mismatch")
+		assert.Equal(t, KeyAltNamePlaceholder, fields.Index(1).Document().Lookup("keyId").StringValue(),
+			"expected the placeholder to be kept")
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeEncryptionFile(t, "fields.yaml", "ignored")
+		unmarshal := func(_ []byte, v interface{}) error {
+			*v.(*interface{}) = map[interface{}]interface{}{
+				"db.users": map[interface{}]interface{}{
+					"fields": []interface{}{
+						map[interface{}]interface{}{"path": "ssn", "bsonType": "string", "keyId": testKeyID},
+					},
+				},
+			}
+			return nil
+		}
+		efm, err := LoadEncryptedFieldsMap(path, &EncryptionFileOptions{UnmarshalYAML: unmarshal})
+		require.NoError(t, err, "LoadEncryptedFieldsMap error")
+		assert.Len(t, efm, 1, "expected one namespace")
+
+		_, err = LoadEncryptedFieldsMap(path, nil)
+		assert.Error(t, err, "expected an error loading YAML without UnmarshalYAML")
+	})
+
+	testCases := []struct {
+		name    string
+		content string
+		version string
+		errMsg  string
+	}{
+		{
+			name:    "unknown field",
+			content: `{"db.users": {"fields": [{"path": "ssn", "bsonType": "string", "keyID": "x"}]}}`,
+			errMsg:  `db.users.fields.0: unknown field "keyID"`,
+		},
+		{
+			name:    "invalid namespace",
+			content: `{"users": {"fields": []}}`,
+			errMsg:  `invalid namespace "users"`,
+		},
+		{
+			name:    "missing path",
+			content: `{"db.users": {"fields": [{"bsonType": "string"}]}}`,
+			errMsg:  `missing required field "path"`,
+		},
+		{
+			name:    "unknown query type",
+			content: `{"db.users": {"fields": [{"path": "a", "bsonType": "int", "queries": {"queryType": "fuzzy"}}]}}`,
+			errMsg:  `unsupported query type "fuzzy"`,
+		},
+		{
+			name:    "query type too new",
+			content: `{"db.users": {"fields": [{"path": "a", "bsonType": "int", "queries": {"queryType": "range"}}]}}`,
+			version: "7.0",
+			errMsg:  `query type "range" requires server version 8.0 or later`,
+		},
+		{
+			name:    "query type removed",
+			content: `{"db.users": {"fields": [{"path": "a", "bsonType": "int", "queries": {"queryType": "rangePreview"}}]}}`,
+			version: "8.0.1",
+			errMsg:  `query type "rangePreview" is not supported by server version 8.0 or later`,
+		},
+		{
+			name:    "unset environment variable",
+			content: `{"db.users": {"fields": [{"path": "a", "bsonType": "int", "keyId": "${OTHER_KEY_ID}"}]}}`,
+			errMsg:  "environment variable OTHER_KEY_ID is not set",
+		},
+		{
+			name:    "invalid key ID",
+			content: `{"db.users": {"fields": [{"path": "a", "bsonType": "int", "keyId": "not-a-uuid"}]}}`,
+			errMsg:  `"not-a-uuid" is not a UUID`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeEncryptionFile(t, "fields.json", tc.content)
+			_, err := LoadEncryptedFieldsMap(path, &EncryptionFileOptions{ServerVersion: tc.version, LookupEnv: testLookupEnv})
+			require.Error(t, err, "expected an error")
+			assert.Contains(t, err.Error(), tc.errMsg, "error message mismatch")
+		})
+	}
+}
+
+func TestLoadSchemaMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeEncryptionFile(t, "schema.json", `{
+			"db.users": {
+				"bsonType": "object",
+				"encryptMetadata": {"keyId": ["${SSN_KEY_ID}"]},
+				"properties": {
+					"ssn": {"encrypt": {"bsonType": "string", "algorithm": "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic"}},
+					"notes": {"encrypt": {"keyId": "/keyName", "algorithm": "AEAD_AES_256_CBC_HMAC_SHA_512-Random"}}
+				}
+			}
+		}`)
+		sm, err := LoadSchemaMap(path, &EncryptionFileOptions{LookupEnv: testLookupEnv})
+		require.NoError(t, err, "LoadSchemaMap error")
+
+		raw, err := bson.Marshal(sm["db.users"])
+		require.NoError(t, err, "Marshal error")
+		keyID := bson.Raw(raw).Lookup("encryptMetadata", "keyId").Array().Index(0)
+		assert.Equal(t, bson.TypeBinary, keyID.Type, "expected the key ID to be resolved to a binary")
+		assert.Equal(t, "/keyName", bson.Raw(raw).Lookup("properties", "notes", "encrypt", "keyId").StringValue(),
+			"expected the JSON pointer to be kept")
+	})
+
+	t.Run("invalid algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeEncryptionFile(t, "schema.json",
+			`{"db.users": {"properties": {"ssn": {"encrypt": {"algorithm": "AES"}}}}}`)
+		_, err := LoadSchemaMap(path, nil)
+		require.Error(t, err, "expected an error")
+		assert.Contains(t, err.Error(), "db.users.properties.ssn.encrypt.algorithm", "error message mismatch")
+	})
+
+	t.Run("unknown encrypt field", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeEncryptionFile(t, "schema.json",
+			`{"db.users": {"properties": {"ssn": {"encrypt": {"queries": []}}}}}`)
+		_, err := LoadSchemaMap(path, nil)
+		require.Error(t, err, "expected an error")
+		assert.Contains(t, err.Error(), `unknown field "queries"`, "error message mismatch")
+	})
+}
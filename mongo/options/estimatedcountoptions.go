@@ -6,12 +6,21 @@
 
 package options
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+)
+
 // EstimatedDocumentCountOptions represents arguments that can be used to configure
 // an EstimatedDocumentCount operation.
 //
 // See corresponding setter methods for documentation.
 type EstimatedDocumentCountOptions struct {
-	Comment interface{}
+	Comment        interface{}
+	StaleFallback  *bool
+	ReadConcern    *readconcern.ReadConcern
+	LocalThreshold *time.Duration
 }
 
 // EstimatedDocumentCountOptionsBuilder contains options to estimate document
@@ -44,3 +53,48 @@ func (eco *EstimatedDocumentCountOptionsBuilder) SetComment(comment interface{})
 
 	return eco
 }
+
+// SetStaleFallback sets the value for the StaleFallback field. If true, the estimate is checked
+// for signs that the count metadata of the collection is stale, which can happen after an unclean
+// shutdown of the server, and the documents are counted with an indexed countDocuments instead if
+// it is. The estimate is considered stale if it is negative, or if it is zero but the collection
+// contains a document, which takes an additional query to check. Counting the documents scans the
+// _id index, so it is much slower than the estimate on large collections. The default is false.
+func (eco *EstimatedDocumentCountOptionsBuilder) SetStaleFallback(b bool) *EstimatedDocumentCountOptionsBuilder {
+	eco.Opts = append(eco.Opts, func(opts *EstimatedDocumentCountOptions) error {
+		opts.StaleFallback = &b
+
+		return nil
+	})
+
+	return eco
+}
+
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (eco *EstimatedDocumentCountOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *EstimatedDocumentCountOptionsBuilder {
+	eco.Opts = append(eco.Opts, func(opts *EstimatedDocumentCountOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return eco
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (eco *EstimatedDocumentCountOptionsBuilder) SetLocalThreshold(d time.Duration) *EstimatedDocumentCountOptionsBuilder {
+	eco.Opts = append(eco.Opts, func(opts *EstimatedDocumentCountOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return eco
+}
@@ -8,6 +8,9 @@ package options
 
 import (
 	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 )
 
 // FindOptions represents arguments that can be used to configure a Find
@@ -34,6 +37,27 @@ type FindOptions struct {
 	Let             interface{}
 	Limit           *int64
 	NoCursorTimeout *bool
+
+	RestartOnHostUnreachable *bool
+	TailableResumeField      *string
+	ReadConcern              *readconcern.ReadConcern
+	BatchCoalescing          *BatchCoalescing
+	LocalThreshold           *time.Duration
+}
+
+// BatchCoalescing configures a tailable await cursor to combine the batches returned by consecutive
+// getMore commands into a single batch, trading latency for fewer, larger batches. A getMore that
+// returns fewer than MinDocuments documents is followed by further getMores, whose maximum await
+// time is shortened to the remainder of Window, until the combined batch contains at least
+// MinDocuments documents or Window has elapsed. Batches are not combined by TryNext, which runs at
+// most one getMore.
+type BatchCoalescing struct {
+	// MinDocuments is the number of documents at which the combined batch is returned.
+	MinDocuments int
+
+	// Window is the maximum time spent waiting for more documents once a getMore returns fewer
+	// than MinDocuments documents.
+	Window time.Duration
 }
 
 // FindOptionsBuilder represents functional options that configure an Findopts.
@@ -179,6 +203,20 @@ func (f *FindOptionsBuilder) SetMaxAwaitTime(d time.Duration) *FindOptionsBuilde
 	return f
 }
 
+// SetBatchCoalescing sets the value for the BatchCoalescing field. BatchCoalescing configures the
+// cursor to combine the batches of consecutive getMore commands until a batch contains a minimum
+// number of documents or a time window has elapsed, which suits consumers of capped collections
+// that process documents in batches. This option only applies to cursors with a CursorType of
+// TailableAwait. The default value is nil, which means that each batch is returned as soon as it is
+// received.
+func (f *FindOptionsBuilder) SetBatchCoalescing(bc BatchCoalescing) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.BatchCoalescing = &bc
+		return nil
+	})
+	return f
+}
+
 // SetMin sets the value for the Min field. Min is a document specifying the inclusive lower bound
 // for a specific index. The default value is 0, which means that there is no minimum value.
 func (f *FindOptionsBuilder) SetMin(min interface{}) *FindOptionsBuilder {
@@ -222,6 +260,23 @@ func (f *FindOptionsBuilder) SetReturnKey(b bool) *FindOptionsBuilder {
 	return f
 }
 
+// SetRestartOnHostUnreachable sets the value for the RestartOnHostUnreachable field.
+// RestartOnHostUnreachable specifies whether the cursor created by the operation restarts the
+// query on another server if the server it was created on, such as a mongos, becomes unreachable
+// while the cursor is iterated. The cursor must not be tailable, and once documents have been
+// returned, the query is only restarted if it is sorted by _id alone and the documents include
+// _id, so that the restarted query resumes after the last returned document instead of returning
+// documents again. Otherwise, or if restarting fails, the cursor error is a
+// mongo.CursorHostUnreachableError. Only enable it for queries that can observe writes made after
+// the original query started. The default value is false.
+func (f *FindOptionsBuilder) SetRestartOnHostUnreachable(b bool) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.RestartOnHostUnreachable = &b
+		return nil
+	})
+	return f
+}
+
 // SetShowRecordID sets the value for the ShowRecordID field. ShowRecordID specifies whether
 // a $recordId field with a record identifier will be included in the documents returned by
 // the Find operation. The default value is false.
@@ -254,6 +309,51 @@ func (f *FindOptionsBuilder) SetSort(sort interface{}) *FindOptionsBuilder {
 	return f
 }
 
+// SetTailableResumeField sets the value for the TailableResumeField field. TailableResumeField
+// specifies the field used to recreate a tailable cursor, positioned after the last document it
+// returned, when a getMore reports that the cursor was not found on the server, e.g. because it was
+// killed after being idle. The values of the field must increase in insertion order, such as
+// ObjectID _id values generated by a single client or the "ts" field of the oplog. If the cursor
+// cannot be recreated, the CursorNotFound error is returned as usual. This option only applies to
+// cursors with a CursorType of Tailable or TailableAwait. The default is to return the
+// CursorNotFound error.
+func (f *FindOptionsBuilder) SetTailableResumeField(field string) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.TailableResumeField = &field
+		return nil
+	})
+	return f
+}
+
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (f *FindOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return f
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (f *FindOptionsBuilder) SetLocalThreshold(d time.Duration) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return f
+}
+
 // FindOneOptions represents arguments that can be used to configure a FindOne
 // operation.
 //
@@ -263,6 +363,7 @@ type FindOneOptions struct {
 	Collation           *Collation
 	Comment             interface{}
 	Hint                interface{}
+	Let                 interface{}
 	Max                 interface{}
 	Min                 interface{}
 	Projection          interface{}
@@ -270,6 +371,8 @@ type FindOneOptions struct {
 	ShowRecordID        *bool
 	Skip                *int64
 	Sort                interface{}
+	ReadConcern         *readconcern.ReadConcern
+	LocalThreshold      *time.Duration
 }
 
 // FindOneOptionsBuilder represents functional options that configure an
@@ -334,6 +437,19 @@ func (f *FindOneOptionsBuilder) SetHint(hint interface{}) *FindOneOptionsBuilder
 	return f
 }
 
+// SetLet sets the value for the Let field. Let specifies parameters for the find expression.
+// This option is only valid for MongoDB versions >= 5.0. Older servers will report an error
+// for using this option. This must be a document mapping parameter names to values. Values
+// must be constant or closed expressions that do not reference document fields. Parameters
+// can then be accessed as variables in an aggregate expression context (e.g. "$$var").
+func (f *FindOneOptionsBuilder) SetLet(let interface{}) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.Let = let
+		return nil
+	})
+	return f
+}
+
 // SetMax sets the value for the Max field. Sets a document specifying the exclusive upper bound
 // for a specific index. The default value is nil, which means that there is no maximum value.
 func (f *FindOneOptionsBuilder) SetMax(max interface{}) *FindOneOptionsBuilder {
@@ -408,6 +524,35 @@ func (f *FindOneOptionsBuilder) SetSort(sort interface{}) *FindOneOptionsBuilder
 	return f
 }
 
+// SetReadConcern sets the value for the ReadConcern field. ReadConcern is the read concern to use for
+// the operation instead of the read concern of the Collection. It cannot be set for an operation that
+// runs in a transaction. The default value is nil, which means that the read concern of the Collection
+// will be used.
+func (f *FindOneOptionsBuilder) SetReadConcern(rc *readconcern.ReadConcern) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.ReadConcern = rc
+
+		return nil
+	})
+
+	return f
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold is the width of the
+// latency window used to select a server for the operation instead of the LocalThreshold of the
+// client. A server is only selected if its round-trip time is within LocalThreshold of the fastest
+// suitable server. The default value is nil, which means that the LocalThreshold of the client will
+// be used.
+func (f *FindOneOptionsBuilder) SetLocalThreshold(d time.Duration) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return f
+}
+
 // FindOneAndReplaceOptions represents arguments that can be used to configure a
 // FindOneAndReplace instance.
 //
@@ -422,6 +567,7 @@ type FindOneAndReplaceOptions struct {
 	Upsert                   *bool
 	Hint                     interface{}
 	Let                      interface{}
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // FindOneAndReplaceOptionsBuilder contains options to perform a findAndModify
@@ -569,6 +715,20 @@ func (f *FindOneAndReplaceOptionsBuilder) SetLet(let interface{}) *FindOneAndRep
 	return f
 }
 
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (f *FindOneAndReplaceOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndReplaceOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneAndReplaceOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return f
+}
+
 // FindOneAndUpdateOptions represents arguments that can be used to configure a
 // FindOneAndUpdate options.
 //
@@ -584,6 +744,7 @@ type FindOneAndUpdateOptions struct {
 	Upsert                   *bool
 	Hint                     interface{}
 	Let                      interface{}
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // FindOneAndUpdateOptionsBuilder contains options to configure a
@@ -746,17 +907,32 @@ func (f *FindOneAndUpdateOptionsBuilder) SetLet(let interface{}) *FindOneAndUpda
 	return f
 }
 
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (f *FindOneAndUpdateOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndUpdateOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneAndUpdateOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return f
+}
+
 // FindOneAndDeleteOptions represents arguments that can be used to configure a
 // FindOneAndDelete operation.
 //
 // See corresponding setter methods for documentation.
 type FindOneAndDeleteOptions struct {
-	Collation  *Collation
-	Comment    interface{}
-	Projection interface{}
-	Sort       interface{}
-	Hint       interface{}
-	Let        interface{}
+	Collation    *Collation
+	Comment      interface{}
+	Projection   interface{}
+	Sort         interface{}
+	Hint         interface{}
+	Let          interface{}
+	WriteConcern *writeconcern.WriteConcern
 }
 
 // FindOneAndDeleteOptionsBuilder contains options to configure delete
@@ -861,3 +1037,17 @@ func (f *FindOneAndDeleteOptionsBuilder) SetLet(let interface{}) *FindOneAndDele
 
 	return f
 }
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (f *FindOneAndDeleteOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndDeleteOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneAndDeleteOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return f
+}
@@ -0,0 +1,86 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "time"
+
+// GetKeysOptions represents arguments that can be used to filter the key documents returned by
+// ClientEncryption.GetKeys. The filters that are set must all match.
+//
+// See corresponding setter methods for documentation.
+type GetKeysOptions struct {
+	KeyAltNamePrefix  *string
+	CreatedAfter      *time.Time
+	CreatedBefore     *time.Time
+	MasterKeyProvider *string
+}
+
+// GetKeysOptionsBuilder contains options to filter the key documents returned by
+// ClientEncryption.GetKeys. Each option can be set through setter functions. See documentation for
+// each setter function for an explanation of the option.
+type GetKeysOptionsBuilder struct {
+	Opts []func(*GetKeysOptions) error
+}
+
+// GetKeys creates a new GetKeysOptions instance.
+func GetKeys() *GetKeysOptionsBuilder {
+	return &GetKeysOptionsBuilder{}
+}
+
+// List returns a list of GetKeysOptions setter functions.
+func (g *GetKeysOptionsBuilder) List() []func(*GetKeysOptions) error {
+	return g.Opts
+}
+
+// SetKeyAltNamePrefix sets the value for the KeyAltNamePrefix field. If set, only the keys with a
+// keyAltName that starts with the prefix are returned.
+func (g *GetKeysOptionsBuilder) SetKeyAltNamePrefix(prefix string) *GetKeysOptionsBuilder {
+	g.Opts = append(g.Opts, func(opts *GetKeysOptions) error {
+		opts.KeyAltNamePrefix = &prefix
+
+		return nil
+	})
+
+	return g
+}
+
+// SetCreatedAfter sets the value for the CreatedAfter field. If set, only the keys whose
+// creationDate is at or after t are returned.
+func (g *GetKeysOptionsBuilder) SetCreatedAfter(t time.Time) *GetKeysOptionsBuilder {
+	g.Opts = append(g.Opts, func(opts *GetKeysOptions) error {
+		opts.CreatedAfter = &t
+
+		return nil
+	})
+
+	return g
+}
+
+// SetCreatedBefore sets the value for the CreatedBefore field. If set, only the keys whose
+// creationDate is before t are returned.
+func (g *GetKeysOptionsBuilder) SetCreatedBefore(t time.Time) *GetKeysOptionsBuilder {
+	g.Opts = append(g.Opts, func(opts *GetKeysOptions) error {
+		opts.CreatedBefore = &t
+
+		return nil
+	})
+
+	return g
+}
+
+// SetMasterKeyProvider sets the value for the MasterKeyProvider field. If set, only the keys whose
+// master key is managed by the KMS provider are returned. A provider such as "aws" also matches
+// the named providers of that type such as "aws:name"; a named provider only matches itself.
+func (g *GetKeysOptionsBuilder) SetMasterKeyProvider(provider string) *GetKeysOptionsBuilder {
+	g.Opts = append(g.Opts, func(opts *GetKeysOptions) error {
+		opts.MasterKeyProvider = &provider
+
+		return nil
+	})
+
+	return g
+}
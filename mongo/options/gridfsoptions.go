@@ -7,6 +7,8 @@
 package options
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
@@ -122,9 +124,11 @@ func (b *BucketOptionsBuilder) SetReadPreference(rp *readpref.ReadPref) *BucketO
 //
 // See corresponding setter methods for documentation.
 type GridFSUploadOptions struct {
-	ChunkSizeBytes *int32
-	Metadata       interface{}
-	Registry       *bson.Registry
+	ChunkSizeBytes     *int32
+	Metadata           interface{}
+	Registry           *bson.Registry
+	DisableCompression *bool
+	ComputeSHA256      *bool
 }
 
 // GridFSUploadOptionsBuilder contains options to configure a GridFS Upload.
@@ -186,6 +190,34 @@ func (u *GridFSUploadOptionsBuilder) SetRegistry(registry *bson.Registry) *GridF
 	return u
 }
 
+// SetDisableCompression sets the value for the DisableCompression field. Specifies whether the
+// chunks of the file are sent to the server without wire compression, even if the Client was
+// configured with compressors. Disabling compression avoids spending CPU time on file contents
+// that are already compressed, such as images or archives. The default value is false.
+func (u *GridFSUploadOptionsBuilder) SetDisableCompression(b bool) *GridFSUploadOptionsBuilder {
+	u.Opts = append(u.Opts, func(opts *GridFSUploadOptions) error {
+		opts.DisableCompression = &b
+
+		return nil
+	})
+
+	return u
+}
+
+// SetComputeSHA256 sets the value for the ComputeSHA256 field. Specifies whether the SHA-256 digest
+// of the file contents is computed during the upload and stored as a hexadecimal string in the
+// "sha256" field of the metadata of the file, so that GridFSBucket.VerifyFile can verify the
+// contents of the file. The default value is false.
+func (u *GridFSUploadOptionsBuilder) SetComputeSHA256(b bool) *GridFSUploadOptionsBuilder {
+	u.Opts = append(u.Opts, func(opts *GridFSUploadOptions) error {
+		opts.ComputeSHA256 = &b
+
+		return nil
+	})
+
+	return u
+}
+
 // GridFSNameOptions represents arguments that can be used to configure a GridFS
 // DownloadByName operation.
 //
@@ -339,3 +371,60 @@ func (f *GridFSFindOptionsBuilder) SetSort(sort interface{}) *GridFSFindOptionsB
 
 	return f
 }
+
+// DefaultGridFSRepairGracePeriod is the default grace period of a GridFS repair.
+const DefaultGridFSRepairGracePeriod = time.Hour
+
+// GridFSRepairOptions represents arguments that can be used to configure a
+// GridFSBucket.Repair operation.
+//
+// See corresponding setter methods for documentation.
+type GridFSRepairOptions struct {
+	GracePeriod *time.Duration
+	DryRun      *bool
+}
+
+// GridFSRepairOptionsBuilder contains options to configure a GridFS repair.
+// Each option can be set through setter functions. See documentation for each
+// setter function for an explanation of the option.
+type GridFSRepairOptionsBuilder struct {
+	Opts []func(*GridFSRepairOptions) error
+}
+
+// GridFSRepair creates a new GridFSRepairOptionsBuilder instance.
+func GridFSRepair() *GridFSRepairOptionsBuilder {
+	return &GridFSRepairOptionsBuilder{}
+}
+
+// List returns a list of GridFSRepairOptions setter functions.
+func (r *GridFSRepairOptionsBuilder) List() []func(*GridFSRepairOptions) error {
+	return r.Opts
+}
+
+// SetGracePeriod sets the value for the GracePeriod field. Specifies the minimum
+// age of the chunks that are removed. Chunks are written before the document of
+// their file, so the chunks of the uploads in progress are orphaned until the
+// uploads complete. The grace period must be longer than the longest upload.
+// The default value is DefaultGridFSRepairGracePeriod (1 hour).
+func (r *GridFSRepairOptionsBuilder) SetGracePeriod(d time.Duration) *GridFSRepairOptionsBuilder {
+	r.Opts = append(r.Opts, func(opts *GridFSRepairOptions) error {
+		opts.GracePeriod = &d
+
+		return nil
+	})
+
+	return r
+}
+
+// SetDryRun sets the value for the DryRun field. Specifies whether the repair
+// only reports the orphaned chunks without removing them. The default value is
+// false.
+func (r *GridFSRepairOptionsBuilder) SetDryRun(b bool) *GridFSRepairOptionsBuilder {
+	r.Opts = append(r.Opts, func(opts *GridFSRepairOptions) error {
+		opts.DryRun = &b
+
+		return nil
+	})
+
+	return r
+}
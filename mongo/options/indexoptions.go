@@ -201,6 +201,7 @@ type IndexOptions struct {
 	Collation               *Collation
 	WildcardProjection      interface{}
 	Hidden                  *bool
+	PrepareUnique           *bool
 }
 
 // IndexOptionsBuilder contains options to configure index operations. Each option
@@ -473,3 +474,17 @@ func (i *IndexOptionsBuilder) SetHidden(hidden bool) *IndexOptionsBuilder {
 
 	return i
 }
+
+// SetPrepareUnique sets the value for the PrepareUnique field. If true, the index will reject
+// inserts and updates that would add duplicate keys, without failing for duplicates that already
+// exist, so that it can later be converted to a unique index with the collMod command. This
+// option is only valid for MongoDB versions >= 6.0. The default value is false.
+func (i *IndexOptionsBuilder) SetPrepareUnique(prepareUnique bool) *IndexOptionsBuilder {
+	i.Opts = append(i.Opts, func(opts *IndexOptions) error {
+		opts.PrepareUnique = &prepareUnique
+
+		return nil
+	})
+
+	return i
+}
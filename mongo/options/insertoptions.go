@@ -6,6 +6,8 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+
 // InsertOneOptions represents arguments that can be used to configure an InsertOne
 // operation.
 //
@@ -13,6 +15,8 @@ package options
 type InsertOneOptions struct {
 	BypassDocumentValidation *bool
 	Comment                  interface{}
+	RawDocuments             *RawDocuments
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // InsertOneOptionsBuilder represents functional options that configure an
@@ -54,6 +58,33 @@ func (ioo *InsertOneOptionsBuilder) SetComment(comment interface{}) *InsertOneOp
 	return ioo
 }
 
+// SetRawDocuments sets the value for the RawDocuments field. Specifies how a document that is
+// already encoded as BSON, i.e. a bson.Raw or []byte value, is written. The default value is
+// ReencodeRawDocuments, which copies the document element by element. ValidateRawDocuments and
+// TrustRawDocuments append the document to the command as is, which avoids the cost of encoding
+// it again.
+func (ioo *InsertOneOptionsBuilder) SetRawDocuments(rd RawDocuments) *InsertOneOptionsBuilder {
+	ioo.Opts = append(ioo.Opts, func(opts *InsertOneOptions) error {
+		opts.RawDocuments = &rd
+		return nil
+	})
+	return ioo
+}
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (ioo *InsertOneOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *InsertOneOptionsBuilder {
+	ioo.Opts = append(ioo.Opts, func(opts *InsertOneOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return ioo
+}
+
 // InsertManyOptions represents arguments that can be used to configure an
 // InsertMany operation.
 //
@@ -62,6 +93,8 @@ type InsertManyOptions struct {
 	BypassDocumentValidation *bool
 	Comment                  interface{}
 	Ordered                  *bool
+	RawDocuments             *RawDocuments
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // InsertManyOptionsBuilder contains options to configure insert operations.
@@ -123,3 +156,32 @@ func (imo *InsertManyOptionsBuilder) SetOrdered(b bool) *InsertManyOptionsBuilde
 
 	return imo
 }
+
+// SetRawDocuments sets the value for the RawDocuments field. Specifies how documents that are
+// already encoded as BSON, i.e. bson.Raw and []byte values, are written. The default value is
+// ReencodeRawDocuments, which copies the documents element by element. ValidateRawDocuments and
+// TrustRawDocuments append the documents to the command as is, which avoids the cost of encoding
+// them again.
+func (imo *InsertManyOptionsBuilder) SetRawDocuments(rd RawDocuments) *InsertManyOptionsBuilder {
+	imo.Opts = append(imo.Opts, func(opts *InsertManyOptions) error {
+		opts.RawDocuments = &rd
+
+		return nil
+	})
+
+	return imo
+}
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (imo *InsertManyOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *InsertManyOptionsBuilder {
+	imo.Opts = append(imo.Opts, func(opts *InsertManyOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return imo
+}
@@ -0,0 +1,164 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "errors"
+
+// InsertStreamBatch describes a batch of documents inserted by Collection.InsertStream. It is passed
+// to the BatchCallback once the batch has been inserted or has failed.
+type InsertStreamBatch struct {
+	// Batch is the number of the batch, starting at 1, in the order the batches were formed.
+	Batch int
+
+	// Documents is the number of documents in the batch.
+	Documents int
+
+	// Bytes is the total size of the marshaled documents in the batch.
+	Bytes int
+
+	// InsertedIDs are the _id values of the documents in the batch.
+	InsertedIDs []interface{}
+
+	// Err is the error returned by inserting the batch, if any. It is a mongo.BulkWriteException
+	// if some of the documents failed to insert.
+	Err error
+}
+
+// InsertStreamOptions represents arguments that can be used to configure an InsertStream
+// operation.
+//
+// See corresponding setter methods for documentation.
+type InsertStreamOptions struct {
+	BypassDocumentValidation *bool
+	Comment                  interface{}
+	Ordered                  *bool
+	Parallelism              *int
+	MaxBatchDocuments        *int
+	MaxBatchBytes            *int
+	BatchCallback            func(InsertStreamBatch)
+}
+
+// InsertStreamOptionsBuilder contains options to configure InsertStream operations. Each option
+// can be set through setter functions. See documentation for each setter function for an
+// explanation of the option.
+type InsertStreamOptionsBuilder struct {
+	Opts []func(*InsertStreamOptions) error
+}
+
+// InsertStream creates a new InsertStreamOptions instance.
+func InsertStream() *InsertStreamOptionsBuilder {
+	return &InsertStreamOptionsBuilder{}
+}
+
+// List returns a list of InsertStreamOptions setter functions.
+func (iso *InsertStreamOptionsBuilder) List() []func(*InsertStreamOptions) error {
+	return iso.Opts
+}
+
+// SetBypassDocumentValidation sets the value for the BypassDocumentValidation field. If true,
+// writes executed as part of the operation will opt out of document-level validation on the
+// server. The default value is false.
+func (iso *InsertStreamOptionsBuilder) SetBypassDocumentValidation(b bool) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		opts.BypassDocumentValidation = &b
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetComment sets the value for the Comment field. Specifies a string or document that will be
+// included in server logs, profiling logs, and currentOp queries to help trace the insert
+// commands of every batch. The default value is nil.
+func (iso *InsertStreamOptionsBuilder) SetComment(comment interface{}) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		opts.Comment = comment
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetOrdered sets the value for the Ordered field. If true, batches are inserted one at a time in
+// order and no documents are inserted after one fails. If false, batches are inserted
+// concurrently as configured by SetParallelism and the stream continues after a failed batch.
+// The default value is true.
+func (iso *InsertStreamOptionsBuilder) SetOrdered(b bool) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		opts.Ordered = &b
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetParallelism sets the value for the Parallelism field. Parallelism is the maximum number of
+// batches inserted concurrently for unordered streams. Each batch selects a server separately, so
+// against a sharded cluster with several mongos routers, concurrent batches are spread across
+// them. It is ignored for ordered streams. The default value is 1.
+func (iso *InsertStreamOptionsBuilder) SetParallelism(n int) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		if n < 1 {
+			return errors.New("insert stream parallelism must be at least 1")
+		}
+		opts.Parallelism = &n
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetMaxBatchDocuments sets the value for the MaxBatchDocuments field. MaxBatchDocuments is the
+// maximum number of documents in a batch. The default value is 100,000, the maxWriteBatchSize of
+// current servers. Batches larger than the limits of the server are split into several insert
+// commands.
+func (iso *InsertStreamOptionsBuilder) SetMaxBatchDocuments(n int) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		if n < 1 {
+			return errors.New("insert stream batch document count must be at least 1")
+		}
+		opts.MaxBatchDocuments = &n
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetMaxBatchBytes sets the value for the MaxBatchBytes field. MaxBatchBytes is the maximum total
+// size of the marshaled documents in a batch, which bounds the memory used by the stream to about
+// (Parallelism + 1) * MaxBatchBytes. A document larger than MaxBatchBytes is inserted in a batch of
+// its own. The default value is 48,000,000 bytes, the maxMessageSizeBytes of current servers.
+func (iso *InsertStreamOptionsBuilder) SetMaxBatchBytes(n int) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		if n < 1 {
+			return errors.New("insert stream batch size must be at least 1 byte")
+		}
+		opts.MaxBatchBytes = &n
+
+		return nil
+	})
+
+	return iso
+}
+
+// SetBatchCallback sets the value for the BatchCallback field. BatchCallback is called with the
+// result of each batch once it has been inserted or has failed. For unordered streams it can be
+// called concurrently from several goroutines.
+func (iso *InsertStreamOptionsBuilder) SetBatchCallback(fn func(InsertStreamBatch)) *InsertStreamOptionsBuilder {
+	iso.Opts = append(iso.Opts, func(opts *InsertStreamOptions) error {
+		opts.BatchCallback = fn
+
+		return nil
+	})
+
+	return iso
+}
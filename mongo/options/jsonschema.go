@@ -0,0 +1,221 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	tTime       = reflect.TypeOf(time.Time{})
+	tDateTime   = reflect.TypeOf(bson.DateTime(0))
+	tObjectID   = reflect.TypeOf(bson.ObjectID{})
+	tDecimal128 = reflect.TypeOf(bson.Decimal128{})
+	tBinary     = reflect.TypeOf(bson.Binary{})
+	tTimestamp  = reflect.TypeOf(bson.Timestamp{})
+	tRegex      = reflect.TypeOf(bson.Regex{})
+	tRaw        = reflect.TypeOf(bson.Raw(nil))
+	tD          = reflect.TypeOf(bson.D(nil))
+	tM          = reflect.TypeOf(bson.M(nil))
+	tA          = reflect.TypeOf(bson.A(nil))
+	tByteSlice  = reflect.TypeOf([]byte(nil))
+)
+
+// JSONSchema generates a $jsonSchema document that validates the documents produced by marshaling
+// values of the Go type of v, which must be a struct or a pointer to a struct, with the default
+// BSON registry. Fields are named and skipped as the "bson" struct tags specify, and inline
+// structs are flattened. The generated schema:
+//
+//   - requires every field without the "omitempty" option,
+//   - sets the bsonType of each field from its Go type, allowing null for pointers, slices, and
+//     maps, which marshal to null when nil,
+//   - describes nested structs with nested "properties" and slice elements with "items", and
+//   - does not constrain interface fields.
+//
+// The schema does not restrict additional fields. It can be used with
+// CreateCollectionOptionsBuilder.SetJSONSchemaValidator or in a collMod command.
+func JSONSchema(v interface{}) (bson.D, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot generate a JSON schema for %T: must be a struct or a pointer to a struct", v)
+	}
+	return structSchema(t, map[reflect.Type]bool{})
+}
+
+// structSchema returns the schema of a struct type. seen contains the struct types being generated
+// to detect recursive types, which a $jsonSchema cannot describe.
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) (bson.D, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("cannot generate a JSON schema for recursive type %v", t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	properties := bson.D{}
+	required := bson.A{}
+	if err := appendStructProperties(t, seen, &properties, &required); err != nil {
+		return nil, err
+	}
+
+	schema := bson.D{{"bsonType", "object"}}
+	if len(required) > 0 {
+		schema = append(schema, bson.E{"required", required})
+	}
+	return append(schema, bson.E{"properties", properties}), nil
+}
+
+func appendStructProperties(t reflect.Type, seen map[reflect.Type]bool, properties *bson.D, required *bson.A) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, omitEmpty, inline, skip := parseBSONTag(field)
+		if skip {
+			continue
+		}
+
+		ft := field.Type
+		if inline {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct {
+				// Inline maps accept any fields.
+				continue
+			}
+			if err := appendStructProperties(ft, seen, properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+
+		schema, err := typeSchema(ft, seen)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		*properties = append(*properties, bson.E{name, schema})
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// parseBSONTag returns the key and options of a struct field as the default struct codec uses them.
+func parseBSONTag(field reflect.StructField) (name string, omitEmpty, inline, skip bool) {
+	tag, ok := field.Tag.Lookup("bson")
+	if !ok && !strings.Contains(string(field.Tag), ":") && len(field.Tag) > 0 {
+		tag = string(field.Tag)
+	}
+	if tag == "-" {
+		return "", false, false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty", "omitzero":
+			omitEmpty = true
+		case "inline":
+			inline = true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, omitEmpty, inline, false
+}
+
+// typeSchema returns the schema of the BSON values produced by marshaling a value of type t.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) (bson.D, error) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+
+	withNull := func(bsonType string) interface{} {
+		if nullable {
+			return bson.A{bsonType, "null"}
+		}
+		return bsonType
+	}
+
+	switch t {
+	case tTime, tDateTime:
+		return bson.D{{"bsonType", withNull("date")}}, nil
+	case tObjectID:
+		return bson.D{{"bsonType", withNull("objectId")}}, nil
+	case tDecimal128:
+		return bson.D{{"bsonType", withNull("decimal")}}, nil
+	case tBinary, tByteSlice:
+		return bson.D{{"bsonType", bson.A{"binData", "null"}}}, nil
+	case tTimestamp:
+		return bson.D{{"bsonType", withNull("timestamp")}}, nil
+	case tRegex:
+		return bson.D{{"bsonType", withNull("regex")}}, nil
+	case tRaw, tD, tM:
+		return bson.D{{"bsonType", bson.A{"object", "null"}}}, nil
+	case tA:
+		return bson.D{{"bsonType", bson.A{"array", "null"}}}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return bson.D{{"bsonType", withNull("string")}}, nil
+	case reflect.Bool:
+		return bson.D{{"bsonType", withNull("bool")}}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return bson.D{{"bsonType", withNull("int")}}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		// Depending on the value and the encoder options, these types marshal to int32 or int64.
+		types := bson.A{"int", "long"}
+		if nullable {
+			types = append(types, "null")
+		}
+		return bson.D{{"bsonType", types}}, nil
+	case reflect.Float32, reflect.Float64:
+		return bson.D{{"bsonType", withNull("double")}}, nil
+	case reflect.Struct:
+		schema, err := structSchema(t, seen)
+		if err != nil {
+			return nil, err
+		}
+		schema[0].Value = withNull("object")
+		return schema, nil
+	case reflect.Map:
+		return bson.D{{"bsonType", bson.A{"object", "null"}}}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		bsonType := interface{}(bson.A{"array", "null"})
+		if t.Kind() == reflect.Array && !nullable {
+			bsonType = "array"
+		}
+		schema := bson.D{{"bsonType", bsonType}}
+		if len(items) > 0 {
+			schema = append(schema, bson.E{"items", items})
+		}
+		return schema, nil
+	case reflect.Interface:
+		return bson.D{}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", t)
+}
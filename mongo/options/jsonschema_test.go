@@ -0,0 +1,126 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+type schemaAddress struct {
+	City string `bson:"city"`
+	Zip  string `bson:"zip,omitempty"`
+}
+
+type schemaAudit struct {
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+type schemaUser struct {
+	ID       bson.ObjectID `bson:"_id"`
+	Name     string        `bson:"name"`
+	Age      int32         `bson:"age,omitempty"`
+	Score    *float64      `bson:"score"`
+	Tags     []string      `bson:"tags"`
+	Address  schemaAddress `bson:"address"`
+	Extra    interface{}   `bson:"extra,omitempty"`
+	Ignored  string        `bson:"-"`
+	Untagged bool
+	internal string
+	Audit    schemaAudit    `bson:",inline"`
+	Previous *schemaAddress `bson:"previous,omitempty"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	marshal := func(v interface{}) bson.Raw {
+		b, err := bson.Marshal(v)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+
+	got, err := JSONSchema(&schemaUser{})
+	require.NoError(t, err, "JSONSchema error: %v", err)
+
+	want := bson.D{
+		{"bsonType", "object"},
+		{"required", bson.A{"_id", "name", "score", "tags", "address", "untagged", "createdAt"}},
+		{"properties", bson.D{
+			{"_id", bson.D{{"bsonType", "objectId"}}},
+			{"name", bson.D{{"bsonType", "string"}}},
+			{"age", bson.D{{"bsonType", "int"}}},
+			{"score", bson.D{{"bsonType", bson.A{"double", "null"}}}},
+			{"tags", bson.D{{"bsonType", bson.A{"array", "null"}}, {"items", bson.D{{"bsonType", "string"}}}}},
+			{"address", bson.D{
+				{"bsonType", "object"},
+				{"required", bson.A{"city"}},
+				{"properties", bson.D{
+					{"city", bson.D{{"bsonType", "string"}}},
+					{"zip", bson.D{{"bsonType", "string"}}},
+				}},
+			}},
+			{"extra", bson.D{}},
+			{"untagged", bson.D{{"bsonType", "bool"}}},
+			{"createdAt", bson.D{{"bsonType", "date"}}},
+			{"previous", bson.D{
+				{"bsonType", bson.A{"object", "null"}},
+				{"required", bson.A{"city"}},
+				{"properties", bson.D{
+					{"city", bson.D{{"bsonType", "string"}}},
+					{"zip", bson.D{{"bsonType", "string"}}},
+				}},
+			}},
+		}},
+	}
+	assert.Equal(t, marshal(want), marshal(got), "expected schema %v, got %v", marshal(want), marshal(got))
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := JSONSchema(42)
+		assert.Error(t, err, "expected error for a non-struct type")
+
+		type node struct {
+			Next *node `bson:"next"`
+		}
+		_, err = JSONSchema(node{})
+		assert.ErrorContains(t, err, "recursive", "expected error for a recursive type")
+
+		_, err = JSONSchema(struct{ C chan int }{})
+		assert.ErrorContains(t, err, "unsupported type", "expected error for an unsupported type")
+	})
+}
+
+func TestCreateCollectionTypedOptions(t *testing.T) {
+	apply := func(t *testing.T, b *CreateCollectionOptionsBuilder) *CreateCollectionOptions {
+		t.Helper()
+
+		opts, err := getOptions[CreateCollectionOptions](b)
+		require.NoError(t, err, "error applying options: %v", err)
+		return opts
+	}
+
+	t.Run("clustered index", func(t *testing.T) {
+		opts := apply(t, CreateCollection().SetClusteredIndexOptions(ClusteredIndex().SetName("byID")))
+		want := bson.D{{"key", bson.D{{"_id", 1}}}, {"unique", true}, {"name", "byID"}}
+		assert.Equal(t, want, opts.ClusteredIndex, "expected clustered index")
+	})
+	t.Run("change stream pre- and post-images", func(t *testing.T) {
+		opts := apply(t, CreateCollection().SetChangeStreamPreAndPostImagesEnabled(true))
+		assert.Equal(t, bson.D{{"enabled", true}}, opts.ChangeStreamPreAndPostImages, "expected pre- and post-images")
+	})
+	t.Run("JSON schema validator", func(t *testing.T) {
+		opts := apply(t, CreateCollection().SetJSONSchemaValidator(schemaAddress{}))
+		validator, ok := opts.Validator.(bson.D)
+		require.True(t, ok, "expected validator to be a bson.D, got %T", opts.Validator)
+		assert.Equal(t, "$jsonSchema", validator[0].Key, "expected $jsonSchema validator")
+
+		_, err := getOptions[CreateCollectionOptions](CreateCollection().SetJSONSchemaValidator("invalid"))
+		assert.Error(t, err, "expected error for a non-struct validator")
+	})
+}
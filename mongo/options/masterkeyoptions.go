@@ -0,0 +1,139 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AWSMasterKey identifies an AWS KMS customer master key used to encrypt a data key.
+type AWSMasterKey struct {
+	// Region is the AWS region of the key. It is required.
+	Region string `bson:"region"`
+
+	// Key is the Amazon Resource Name (ARN) of the customer master key. It is required.
+	Key string `bson:"key"`
+
+	// Endpoint is an optional alternate host name, with an optional port, to send KMS requests to.
+	// If unset, it defaults to "kms.<region>.amazonaws.com".
+	Endpoint string `bson:"endpoint,omitempty"`
+}
+
+// Validate returns an error if a required field of the master key is missing or the endpoint is
+// not a host name.
+func (k AWSMasterKey) Validate() error {
+	if err := requireMasterKeyFields("aws", "region", k.Region, "key", k.Key); err != nil {
+		return err
+	}
+	return validateKMSEndpoint("aws", "endpoint", k.Endpoint)
+}
+
+// AzureMasterKey identifies an Azure Key Vault key used to encrypt a data key.
+type AzureMasterKey struct {
+	// KeyVaultEndpoint is the host name, with an optional port, of the key vault. It is required.
+	KeyVaultEndpoint string `bson:"keyVaultEndpoint"`
+
+	// KeyName is the name of the key. It is required.
+	KeyName string `bson:"keyName"`
+
+	// KeyVersion is an optional version of the key. If unset, the primary version of the key is
+	// used.
+	KeyVersion string `bson:"keyVersion,omitempty"`
+}
+
+// Validate returns an error if a required field of the master key is missing or the endpoint is
+// not a host name.
+func (k AzureMasterKey) Validate() error {
+	err := requireMasterKeyFields("azure", "keyVaultEndpoint", k.KeyVaultEndpoint, "keyName", k.KeyName)
+	if err != nil {
+		return err
+	}
+	return validateKMSEndpoint("azure", "keyVaultEndpoint", k.KeyVaultEndpoint)
+}
+
+// GCPMasterKey identifies a Google Cloud KMS key used to encrypt a data key.
+type GCPMasterKey struct {
+	// ProjectID is the ID of the project of the key. It is required.
+	ProjectID string `bson:"projectId"`
+
+	// Location is the location of the key ring. It is required.
+	Location string `bson:"location"`
+
+	// KeyRing is the name of the key ring of the key. It is required.
+	KeyRing string `bson:"keyRing"`
+
+	// KeyName is the name of the key. It is required.
+	KeyName string `bson:"keyName"`
+
+	// KeyVersion is an optional version of the key. If unset, the primary version of the key is
+	// used.
+	KeyVersion string `bson:"keyVersion,omitempty"`
+
+	// Endpoint is an optional alternate host name, with an optional port, to send KMS requests to.
+	// If unset, it defaults to "cloudkms.googleapis.com".
+	Endpoint string `bson:"endpoint,omitempty"`
+}
+
+// Validate returns an error if a required field of the master key is missing or the endpoint is
+// not a host name.
+func (k GCPMasterKey) Validate() error {
+	err := requireMasterKeyFields("gcp",
+		"projectId", k.ProjectID,
+		"location", k.Location,
+		"keyRing", k.KeyRing,
+		"keyName", k.KeyName)
+	if err != nil {
+		return err
+	}
+	return validateKMSEndpoint("gcp", "endpoint", k.Endpoint)
+}
+
+// KMIPMasterKey identifies a KMIP managed object used to encrypt a data key. All of its fields are
+// optional.
+type KMIPMasterKey struct {
+	// KeyID is the unique identifier of a 96-byte KMIP secret data managed object. If unset, the
+	// driver creates a random managed object.
+	KeyID string `bson:"keyId,omitempty"`
+
+	// Endpoint is an optional host name, with an optional port, of the KMIP server. If unset, the
+	// endpoint configured in the KMS providers is used.
+	Endpoint string `bson:"endpoint,omitempty"`
+
+	// Delegated specifies that the KMIP server performs encryption and decryption, rather than the
+	// driver using a key retrieved from it.
+	Delegated bool `bson:"delegated,omitempty"`
+}
+
+// Validate returns an error if the endpoint of the master key is not a host name.
+func (k KMIPMasterKey) Validate() error {
+	return validateKMSEndpoint("kmip", "endpoint", k.Endpoint)
+}
+
+// requireMasterKeyFields returns an error naming the first empty field. fields alternates field
+// names and values.
+func requireMasterKeyFields(provider string, fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			return fmt.Errorf("%s master key: %q is required", provider, fields[i])
+		}
+	}
+	return nil
+}
+
+// validateKMSEndpoint returns an error if a non-empty endpoint is a URL rather than a host name
+// with an optional port.
+func validateKMSEndpoint(provider, field, endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	if strings.Contains(endpoint, "://") || strings.ContainsAny(endpoint, "/ ") {
+		return fmt.Errorf("%s master key: %q must be a host name with an optional port, got %q",
+			provider, field, endpoint)
+	}
+	return nil
+}
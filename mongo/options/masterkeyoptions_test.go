@@ -0,0 +1,97 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestTypedMasterKeys(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder *DataKeyOptionsBuilder
+		want    bson.D
+		wantErr string
+	}{
+		{
+			name:    "AWS",
+			builder: DataKey().SetAWSMasterKey(AWSMasterKey{Region: "us-east-1", Key: "arn:aws:kms:key"}),
+			want:    bson.D{{"region", "us-east-1"}, {"key", "arn:aws:kms:key"}},
+		},
+		{
+			name:    "AWS missing key",
+			builder: DataKey().SetAWSMasterKey(AWSMasterKey{Region: "us-east-1"}),
+			wantErr: `aws master key: "key" is required`,
+		},
+		{
+			name: "AWS endpoint URL",
+			builder: DataKey().SetAWSMasterKey(AWSMasterKey{
+				Region:   "us-east-1",
+				Key:      "arn:aws:kms:key",
+				Endpoint: "https://kms.us-east-1.amazonaws.com",
+			}),
+			wantErr: `"endpoint" must be a host name`,
+		},
+		{
+			name:    "Azure",
+			builder: DataKey().SetAzureMasterKey(AzureMasterKey{KeyVaultEndpoint: "vault.azure.net", KeyName: "k", KeyVersion: "1"}),
+			want:    bson.D{{"keyVaultEndpoint", "vault.azure.net"}, {"keyName", "k"}, {"keyVersion", "1"}},
+		},
+		{
+			name:    "Azure missing endpoint",
+			builder: DataKey().SetAzureMasterKey(AzureMasterKey{KeyName: "k"}),
+			wantErr: `azure master key: "keyVaultEndpoint" is required`,
+		},
+		{
+			name: "GCP",
+			builder: DataKey().SetGCPMasterKey(GCPMasterKey{
+				ProjectID: "p",
+				Location:  "global",
+				KeyRing:   "r",
+				KeyName:   "k",
+				Endpoint:  "cloudkms.googleapis.com:443",
+			}),
+			want: bson.D{
+				{"projectId", "p"},
+				{"location", "global"},
+				{"keyRing", "r"},
+				{"keyName", "k"},
+				{"endpoint", "cloudkms.googleapis.com:443"},
+			},
+		},
+		{
+			name:    "GCP missing key ring",
+			builder: DataKey().SetGCPMasterKey(GCPMasterKey{ProjectID: "p", Location: "global", KeyName: "k"}),
+			wantErr: `gcp master key: "keyRing" is required`,
+		},
+		{
+			name:    "KMIP",
+			builder: DataKey().SetKMIPMasterKey(KMIPMasterKey{Delegated: true}),
+			want:    bson.D{{"delegated", true}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := getOptions[DataKeyOptions](tc.builder)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr, "expected validation error")
+				return
+			}
+			require.NoError(t, err, "error applying options: %v", err)
+
+			got, err := bson.Marshal(opts.MasterKey)
+			require.NoError(t, err, "Marshal error: %v", err)
+			want, err := bson.Marshal(tc.want)
+			require.NoError(t, err, "Marshal error: %v", err)
+			assert.Equal(t, bson.Raw(want), bson.Raw(got), "expected master key %v, got %v", bson.Raw(want), bson.Raw(got))
+		})
+	}
+}
@@ -68,3 +68,21 @@ const (
 	// if the post-image for this event is available.
 	WhenAvailable FullDocument = "whenAvailable"
 )
+
+// RawDocuments specifies how insert and replace operations write documents that are already
+// encoded as BSON, i.e. bson.Raw and []byte values.
+type RawDocuments int8
+
+const (
+	// ReencodeRawDocuments copies the documents element by element with the registry, like any
+	// other value, which fully validates them.
+	ReencodeRawDocuments RawDocuments = iota
+	// ValidateRawDocuments appends the documents to the command without copying them, after
+	// validating their structure.
+	ValidateRawDocuments
+	// TrustRawDocuments appends the documents to the command without copying them, after only
+	// checking their length and terminator. It should only be used for documents read from a
+	// trusted source, such as documents returned by a server, because malformed documents are
+	// rejected by the server instead of the driver.
+	TrustRawDocuments
+)
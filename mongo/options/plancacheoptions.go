@@ -0,0 +1,138 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// PlanCacheClearOptions represents arguments that can be used to configure a PlanCacheClear
+// operation.
+//
+// See corresponding setter methods for documentation.
+type PlanCacheClearOptions struct {
+	Query      interface{}
+	Sort       interface{}
+	Projection interface{}
+}
+
+// PlanCacheClearOptionsBuilder contains options to configure planCacheClear operations. Each
+// option can be set through setter functions. See documentation for each setter function for an
+// explanation of the option.
+type PlanCacheClearOptionsBuilder struct {
+	Opts []func(*PlanCacheClearOptions) error
+}
+
+// PlanCacheClear creates a new PlanCacheClearOptions instance.
+func PlanCacheClear() *PlanCacheClearOptionsBuilder {
+	return &PlanCacheClearOptionsBuilder{}
+}
+
+// List returns a list of PlanCacheClearOptions setter functions.
+func (p *PlanCacheClearOptionsBuilder) List() []func(*PlanCacheClearOptions) error {
+	return p.Opts
+}
+
+// SetQuery sets the value for the Query field. Query is a filter whose query shape identifies the
+// plan cache entries to clear, together with the sort and projection. If it is not set, all entries
+// of the collection are cleared.
+func (p *PlanCacheClearOptionsBuilder) SetQuery(query interface{}) *PlanCacheClearOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheClearOptions) error {
+		opts.Query = query
+
+		return nil
+	})
+
+	return p
+}
+
+// SetSort sets the value for the Sort field. Sort is the sort of the query shape whose entries are
+// cleared. It is only used with SetQuery.
+func (p *PlanCacheClearOptionsBuilder) SetSort(sort interface{}) *PlanCacheClearOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheClearOptions) error {
+		opts.Sort = sort
+
+		return nil
+	})
+
+	return p
+}
+
+// SetProjection sets the value for the Projection field. Projection is the projection of the query
+// shape whose entries are cleared. It is only used with SetQuery.
+func (p *PlanCacheClearOptionsBuilder) SetProjection(projection interface{}) *PlanCacheClearOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheClearOptions) error {
+		opts.Projection = projection
+
+		return nil
+	})
+
+	return p
+}
+
+// PlanCacheListOptions represents arguments that can be used to configure a PlanCacheList
+// operation.
+//
+// See corresponding setter methods for documentation.
+type PlanCacheListOptions struct {
+	AllHosts     *bool
+	PlanCacheKey *string
+	QueryHash    *string
+}
+
+// PlanCacheListOptionsBuilder contains options to configure PlanCacheList operations. Each option
+// can be set through setter functions. See documentation for each setter function for an
+// explanation of the option.
+type PlanCacheListOptionsBuilder struct {
+	Opts []func(*PlanCacheListOptions) error
+}
+
+// PlanCacheList creates a new PlanCacheListOptions instance.
+func PlanCacheList() *PlanCacheListOptionsBuilder {
+	return &PlanCacheListOptionsBuilder{}
+}
+
+// List returns a list of PlanCacheListOptions setter functions.
+func (p *PlanCacheListOptionsBuilder) List() []func(*PlanCacheListOptions) error {
+	return p.Opts
+}
+
+// SetAllHosts sets the value for the AllHosts field. If true, the plan cache entries of every
+// member of every shard are returned, instead of one member per shard selected by the read
+// preference. This option is only valid against sharded clusters running MongoDB 7.1 or later. The
+// default value is false.
+func (p *PlanCacheListOptionsBuilder) SetAllHosts(b bool) *PlanCacheListOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheListOptions) error {
+		opts.AllHosts = &b
+
+		return nil
+	})
+
+	return p
+}
+
+// SetPlanCacheKey sets the value for the PlanCacheKey field. If set, only the entries with this
+// plan cache key are returned. The plan cache key of a query is reported by explain, so it can be
+// used to find the cached plan of a specific query.
+func (p *PlanCacheListOptionsBuilder) SetPlanCacheKey(key string) *PlanCacheListOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheListOptions) error {
+		opts.PlanCacheKey = &key
+
+		return nil
+	})
+
+	return p
+}
+
+// SetQueryHash sets the value for the QueryHash field. If set, only the entries with this query
+// hash, which identifies the query shape, are returned. The query hash of a query is reported by
+// explain and in slow query logs.
+func (p *PlanCacheListOptionsBuilder) SetQueryHash(hash string) *PlanCacheListOptionsBuilder {
+	p.Opts = append(p.Opts, func(opts *PlanCacheListOptions) error {
+		opts.QueryHash = &hash
+
+		return nil
+	})
+
+	return p
+}
@@ -6,6 +6,8 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+
 // ReplaceOptions represents arguments that can be used to configure a ReplaceOne
 // operation.
 //
@@ -17,6 +19,8 @@ type ReplaceOptions struct {
 	Hint                     interface{}
 	Upsert                   *bool
 	Let                      interface{}
+	RawDocuments             *RawDocuments
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // ReplaceOptionsBuilder contains options to configure replace operations. Each
@@ -96,6 +100,21 @@ func (ro *ReplaceOptionsBuilder) SetHint(h interface{}) *ReplaceOptionsBuilder {
 	return ro
 }
 
+// SetRawDocuments sets the value for the RawDocuments field. Specifies how a replacement
+// document that is already encoded as BSON, i.e. a bson.Raw or []byte value, is written. The
+// default value is ReencodeRawDocuments, which copies the document element by element.
+// ValidateRawDocuments and TrustRawDocuments append the document to the command as is, which
+// avoids the cost of encoding it again.
+func (ro *ReplaceOptionsBuilder) SetRawDocuments(rd RawDocuments) *ReplaceOptionsBuilder {
+	ro.Opts = append(ro.Opts, func(opts *ReplaceOptions) error {
+		opts.RawDocuments = &rd
+
+		return nil
+	})
+
+	return ro
+}
+
 // SetUpsert sets the value for the Upsert field. If true, a new document will be inserted
 // if the filter does not match any documents in the collection. The default value is false.
 func (ro *ReplaceOptionsBuilder) SetUpsert(b bool) *ReplaceOptionsBuilder {
@@ -122,3 +141,17 @@ func (ro *ReplaceOptionsBuilder) SetLet(l interface{}) *ReplaceOptionsBuilder {
 
 	return ro
 }
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (ro *ReplaceOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *ReplaceOptionsBuilder {
+	ro.Opts = append(ro.Opts, func(opts *ReplaceOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return ro
+}
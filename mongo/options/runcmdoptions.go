@@ -15,7 +15,16 @@ import (
 //
 // See corresponding setter methods for documentation.
 type RunCmdOptions struct {
-	ReadPreference *readpref.ReadPref
+	ReadPreference    *readpref.ReadPref
+	DocumentSequences []DocumentSequence
+	Exhaust           *bool
+}
+
+// DocumentSequence is an OP_MSG document sequence sent with a command. The server treats the
+// documents as an array in the command field named Identifier.
+type DocumentSequence struct {
+	Identifier string
+	Documents  []interface{}
 }
 
 // RunCmdOptionsBuilder contains options to configure runCommand operations.
@@ -47,3 +56,39 @@ func (rc *RunCmdOptionsBuilder) SetReadPreference(rp *readpref.ReadPref) *RunCmd
 
 	return rc
 }
+
+// SetDocumentSequence appends a value to the DocumentSequences field. Specifies documents to send
+// as an OP_MSG document sequence (a kind 1 section) with the identifier instead of as an array in
+// the command document, as the driver does for the documents of inserts. Document sequences do not
+// count toward the maximum size of the command document, but the whole message must be smaller
+// than the maximum message size of the server. The identifier must not be a field of the command
+// document. Document sequences are sent as arrays in the command document if auto encryption is
+// enabled. The default is nil, which means that no document sequences are sent.
+func (rc *RunCmdOptionsBuilder) SetDocumentSequence(identifier string, documents ...interface{}) *RunCmdOptionsBuilder {
+	rc.Opts = append(rc.Opts, func(opts *RunCmdOptions) error {
+		opts.DocumentSequences = append(opts.DocumentSequences, DocumentSequence{
+			Identifier: identifier,
+			Documents:  documents,
+		})
+
+		return nil
+	})
+
+	return rc
+}
+
+// SetExhaust sets the value for the Exhaust field. If true, the cursor returned by RunCommandCursor
+// is an exhaust cursor: it keeps a connection checked out from the pool after its first batch, and
+// the server streams the following batches on it after a single getMore command, which avoids a
+// round trip per batch. The connection is returned to the pool when the cursor is exhausted, and is
+// closed if the cursor is closed before. It is ignored by RunCommand, in load balanced mode, and
+// if auto encryption is enabled. The default is false.
+func (rc *RunCmdOptionsBuilder) SetExhaust(b bool) *RunCmdOptionsBuilder {
+	rc.Opts = append(rc.Opts, func(opts *RunCmdOptions) error {
+		opts.Exhaust = &b
+
+		return nil
+	})
+
+	return rc
+}
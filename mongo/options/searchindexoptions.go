@@ -6,6 +6,11 @@
 
 package options
 
+import (
+	"errors"
+	"time"
+)
+
 // SearchIndexesOptions represents arguments that can be used to configure a
 // SearchIndexView.
 type SearchIndexesOptions struct {
@@ -116,3 +121,62 @@ type UpdateSearchIndexOptionsBuilder struct {
 func (usio *UpdateSearchIndexOptionsBuilder) List() []func(*UpdateSearchIndexOptions) error {
 	return usio.Opts
 }
+
+// WaitForSearchIndexOptions represents arguments that can be used to configure a
+// SearchIndexView.WaitForReady operation.
+//
+// See corresponding setter methods for documentation.
+type WaitForSearchIndexOptions struct {
+	InitialInterval *time.Duration
+	MaxInterval     *time.Duration
+}
+
+// WaitForSearchIndexOptionsBuilder contains options to configure waiting for a
+// search index. Each option can be set through setter functions. See
+// documentation for each setter function for an explanation of the option.
+type WaitForSearchIndexOptionsBuilder struct {
+	Opts []func(*WaitForSearchIndexOptions) error
+}
+
+// WaitForSearchIndex creates a new WaitForSearchIndexOptions instance.
+func WaitForSearchIndex() *WaitForSearchIndexOptionsBuilder {
+	return &WaitForSearchIndexOptionsBuilder{}
+}
+
+// List returns a list of WaitForSearchIndexOptions setter functions.
+func (wsio *WaitForSearchIndexOptionsBuilder) List() []func(*WaitForSearchIndexOptions) error {
+	return wsio.Opts
+}
+
+// SetInitialInterval sets the value for the InitialInterval field. Specifies the
+// time to wait before listing the search index again after the first check. The
+// interval doubles after each check up to MaxInterval. The default value is 1
+// second.
+func (wsio *WaitForSearchIndexOptionsBuilder) SetInitialInterval(d time.Duration) *WaitForSearchIndexOptionsBuilder {
+	wsio.Opts = append(wsio.Opts, func(opts *WaitForSearchIndexOptions) error {
+		if d <= 0 {
+			return errors.New("initial interval must be positive")
+		}
+		opts.InitialInterval = &d
+
+		return nil
+	})
+
+	return wsio
+}
+
+// SetMaxInterval sets the value for the MaxInterval field. Specifies the maximum
+// time to wait between checks of the search index. The default value is 30
+// seconds.
+func (wsio *WaitForSearchIndexOptionsBuilder) SetMaxInterval(d time.Duration) *WaitForSearchIndexOptionsBuilder {
+	wsio.Opts = append(wsio.Opts, func(opts *WaitForSearchIndexOptions) error {
+		if d <= 0 {
+			return errors.New("max interval must be positive")
+		}
+		opts.MaxInterval = &d
+
+		return nil
+	})
+
+	return wsio
+}
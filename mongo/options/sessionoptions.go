@@ -6,6 +6,8 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/bson"
+
 // DefaultCausalConsistency is the default value for the CausalConsistency option.
 var DefaultCausalConsistency = true
 
@@ -16,6 +18,7 @@ type SessionOptions struct {
 	CausalConsistency         *bool
 	DefaultTransactionOptions *TransactionOptionsBuilder
 	Snapshot                  *bool
+	SnapshotTime              *bson.Timestamp
 }
 
 // SessionOptionsBuilder represents functional options that configure a Sessionopts.
@@ -69,3 +72,16 @@ func (s *SessionOptionsBuilder) SetSnapshot(b bool) *SessionOptionsBuilder {
 	})
 	return s
 }
+
+// SetSnapshotTime sets the value for the SnapshotTime field. If set, all read operations
+// performed with the session will read from the snapshot at the given cluster time instead of
+// the snapshot chosen by the server for the first read. This is useful to read from the same
+// snapshot across several sessions, e.g. by passing the time returned by Session.SnapshotTime
+// of another session. This option can only be set if Snapshot is set to true.
+func (s *SessionOptionsBuilder) SetSnapshotTime(ts bson.Timestamp) *SessionOptionsBuilder {
+	s.Opts = append(s.Opts, func(opts *SessionOptions) error {
+		opts.SnapshotTime = &ts
+		return nil
+	})
+	return s
+}
@@ -0,0 +1,127 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// AnalyzeShardKeyOptions represents arguments that can be used to configure an AnalyzeShardKey
+// operation.
+//
+// See corresponding setter methods for documentation.
+type AnalyzeShardKeyOptions struct {
+	KeyCharacteristics    *bool
+	ReadWriteDistribution *bool
+	SampleRate            *float64
+	SampleSize            *int64
+}
+
+// AnalyzeShardKeyOptionsBuilder contains arguments to configure analyzeShardKey operations. Each
+// option can be set through setter functions. See documentation for each setter function for an
+// explanation of the option.
+type AnalyzeShardKeyOptionsBuilder struct {
+	Opts []func(*AnalyzeShardKeyOptions) error
+}
+
+// AnalyzeShardKey creates a new AnalyzeShardKeyOptions instance.
+func AnalyzeShardKey() *AnalyzeShardKeyOptionsBuilder {
+	return &AnalyzeShardKeyOptionsBuilder{}
+}
+
+// List returns a list of AnalyzeShardKeyOptions setter functions.
+func (a *AnalyzeShardKeyOptionsBuilder) List() []func(*AnalyzeShardKeyOptions) error {
+	return a.Opts
+}
+
+// SetKeyCharacteristics sets the value for the KeyCharacteristics field. Specifies whether the
+// cardinality, frequency, and monotonicity of the shard key are calculated. The default value is
+// true.
+func (a *AnalyzeShardKeyOptionsBuilder) SetKeyCharacteristics(b bool) *AnalyzeShardKeyOptionsBuilder {
+	a.Opts = append(a.Opts, func(opts *AnalyzeShardKeyOptions) error {
+		opts.KeyCharacteristics = &b
+
+		return nil
+	})
+
+	return a
+}
+
+// SetReadWriteDistribution sets the value for the ReadWriteDistribution field. Specifies whether the
+// distribution of sampled reads and writes is calculated. Queries must have been sampled with
+// Collection.ConfigureQueryAnalyzer for the distribution to be calculated. The default value is
+// true.
+func (a *AnalyzeShardKeyOptionsBuilder) SetReadWriteDistribution(b bool) *AnalyzeShardKeyOptionsBuilder {
+	a.Opts = append(a.Opts, func(opts *AnalyzeShardKeyOptions) error {
+		opts.ReadWriteDistribution = &b
+
+		return nil
+	})
+
+	return a
+}
+
+// SetSampleRate sets the value for the SampleRate field. Specifies the proportion of the documents
+// in the collection to sample when calculating the key characteristics, between 0 (exclusive) and 1
+// (inclusive). SampleRate cannot be used with SampleSize. The default is to sample up to 10 million
+// documents.
+func (a *AnalyzeShardKeyOptionsBuilder) SetSampleRate(rate float64) *AnalyzeShardKeyOptionsBuilder {
+	a.Opts = append(a.Opts, func(opts *AnalyzeShardKeyOptions) error {
+		opts.SampleRate = &rate
+
+		return nil
+	})
+
+	return a
+}
+
+// SetSampleSize sets the value for the SampleSize field. Specifies the number of documents to sample
+// when calculating the key characteristics. SampleSize cannot be used with SampleRate. The default
+// is to sample up to 10 million documents.
+func (a *AnalyzeShardKeyOptionsBuilder) SetSampleSize(size int64) *AnalyzeShardKeyOptionsBuilder {
+	a.Opts = append(a.Opts, func(opts *AnalyzeShardKeyOptions) error {
+		opts.SampleSize = &size
+
+		return nil
+	})
+
+	return a
+}
+
+// ConfigureQueryAnalyzerOptions represents arguments that can be used to configure a
+// ConfigureQueryAnalyzer operation.
+//
+// See corresponding setter methods for documentation.
+type ConfigureQueryAnalyzerOptions struct {
+	SamplesPerSecond *float64
+}
+
+// ConfigureQueryAnalyzerOptionsBuilder contains arguments to configure configureQueryAnalyzer
+// operations. Each option can be set through setter functions. See documentation for each setter
+// function for an explanation of the option.
+type ConfigureQueryAnalyzerOptionsBuilder struct {
+	Opts []func(*ConfigureQueryAnalyzerOptions) error
+}
+
+// ConfigureQueryAnalyzer creates a new ConfigureQueryAnalyzerOptions instance.
+func ConfigureQueryAnalyzer() *ConfigureQueryAnalyzerOptionsBuilder {
+	return &ConfigureQueryAnalyzerOptionsBuilder{}
+}
+
+// List returns a list of ConfigureQueryAnalyzerOptions setter functions.
+func (c *ConfigureQueryAnalyzerOptionsBuilder) List() []func(*ConfigureQueryAnalyzerOptions) error {
+	return c.Opts
+}
+
+// SetSamplesPerSecond sets the value for the SamplesPerSecond field. Specifies the number of queries
+// to sample per second, between 0 (exclusive) and 50 (inclusive). It is required when enabling the
+// query analyzer and cannot be set when disabling it.
+func (c *ConfigureQueryAnalyzerOptionsBuilder) SetSamplesPerSecond(rate float64) *ConfigureQueryAnalyzerOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *ConfigureQueryAnalyzerOptions) error {
+		opts.SamplesPerSecond = &rate
+
+		return nil
+	})
+
+	return c
+}
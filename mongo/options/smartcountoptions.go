@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// CountAccuracy is the trade-off between accuracy and latency that a SmartCount operation makes
+// when choosing how to count the documents of a collection.
+type CountAccuracy int
+
+// These constants are the accuracies of a SmartCount operation.
+const (
+	// CountAccuracyBalanced counts the documents of the collection with the collection metadata if
+	// there is no filter, and with countDocuments otherwise, using an index when one covers the
+	// filter. This is the default.
+	CountAccuracyBalanced CountAccuracy = iota
+
+	// CountAccuracyFast only uses strategies that do not scan the collection: the collection
+	// metadata if there is no filter, or an index that covers the filter. The operation fails if
+	// no index covers the filter.
+	CountAccuracyFast
+
+	// CountAccuracyExact always counts the documents with countDocuments, even if there is no
+	// filter, using an index when one covers the filter.
+	CountAccuracyExact
+)
+
+// String returns the name of the accuracy.
+func (ca CountAccuracy) String() string {
+	switch ca {
+	case CountAccuracyBalanced:
+		return "balanced"
+	case CountAccuracyFast:
+		return "fast"
+	case CountAccuracyExact:
+		return "exact"
+	default:
+		return "unknown"
+	}
+}
+
+// SmartCountOptions represents arguments that can be used to configure a
+// SmartCount operation.
+//
+// See corresponding setter methods for documentation.
+type SmartCountOptions struct {
+	Accuracy *CountAccuracy
+	Comment  interface{}
+}
+
+// SmartCountOptionsBuilder contains options to configure smart count
+// operations. Each option can be set through setter functions. See
+// documentation for each setter function for an explanation of the option.
+type SmartCountOptionsBuilder struct {
+	Opts []func(*SmartCountOptions) error
+}
+
+// SmartCount creates a new SmartCountOptions instance.
+func SmartCount() *SmartCountOptionsBuilder {
+	return &SmartCountOptionsBuilder{}
+}
+
+// List returns a list of SmartCountOptions setter functions.
+func (sco *SmartCountOptionsBuilder) List() []func(*SmartCountOptions) error {
+	return sco.Opts
+}
+
+// SetAccuracy sets the value for the Accuracy field. Specifies the trade-off between accuracy and
+// latency used to choose how the documents are counted. The default is CountAccuracyBalanced.
+func (sco *SmartCountOptionsBuilder) SetAccuracy(accuracy CountAccuracy) *SmartCountOptionsBuilder {
+	sco.Opts = append(sco.Opts, func(opts *SmartCountOptions) error {
+		opts.Accuracy = &accuracy
+
+		return nil
+	})
+
+	return sco
+}
+
+// SetComment sets the value for the Comment field. Specifies a string or document that will be
+// included in server logs, profiling logs, and currentOp queries to help trace the operations run
+// to count the documents. The default is nil, which means that no comment will be included in the
+// logs.
+func (sco *SmartCountOptionsBuilder) SetComment(comment interface{}) *SmartCountOptionsBuilder {
+	sco.Opts = append(sco.Opts, func(opts *SmartCountOptions) error {
+		opts.Comment = comment
+
+		return nil
+	})
+
+	return sco
+}
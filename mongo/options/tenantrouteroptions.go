@@ -0,0 +1,98 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+// TenantConfig is the configuration of a tenant returned by the resolver of a mongo.TenantRouter.
+// The zero value routes the tenant to the database named by the database prefix followed by the
+// tenant key, with the options of the router's Client.
+type TenantConfig struct {
+	// Database is the name of the database of the tenant. If empty, the name is the database
+	// prefix followed by the tenant key.
+	Database string
+
+	ReadConcern    *readconcern.ReadConcern
+	WriteConcern   *writeconcern.WriteConcern
+	ReadPreference *readpref.ReadPref
+
+	// Credential, if set, authenticates the operations of the tenant with a separate Client
+	// created from the client options of the router. The Client is disconnected when the tenant
+	// is evicted or the router is closed.
+	Credential *Credential
+}
+
+// TenantRouterOptions represents arguments that can be used to configure a mongo.TenantRouter.
+//
+// See corresponding setter methods for documentation.
+type TenantRouterOptions struct {
+	DatabasePrefix *string
+	Resolver       func(ctx context.Context, tenant string) (*TenantConfig, error)
+	ClientOptions  []Lister[ClientOptions]
+}
+
+// TenantRouterOptionsBuilder contains options to configure a mongo.TenantRouter. Each option can
+// be set through setter functions. See documentation for each setter function for an explanation
+// of the option.
+type TenantRouterOptionsBuilder struct {
+	Opts []func(*TenantRouterOptions) error
+}
+
+// TenantRouter creates a new TenantRouterOptionsBuilder instance.
+func TenantRouter() *TenantRouterOptionsBuilder {
+	return &TenantRouterOptionsBuilder{}
+}
+
+// List returns a list of TenantRouterOptions setter functions.
+func (t *TenantRouterOptionsBuilder) List() []func(*TenantRouterOptions) error {
+	return t.Opts
+}
+
+// SetDatabasePrefix sets the value for the DatabasePrefix field. Specifies the prefix of the
+// database names of the tenants whose configuration does not name a database, e.g. "tenant_". The
+// default value is "".
+func (t *TenantRouterOptionsBuilder) SetDatabasePrefix(prefix string) *TenantRouterOptionsBuilder {
+	t.Opts = append(t.Opts, func(opts *TenantRouterOptions) error {
+		opts.DatabasePrefix = &prefix
+		return nil
+	})
+	return t
+}
+
+// SetResolver sets the value for the Resolver field. Specifies the function that returns the
+// configuration of a tenant, e.g. by looking it up in a catalog collection. It is called once for
+// each tenant, when the first handle of the tenant is requested, and its result is cached until the
+// tenant is evicted. If it returns an error, the error is returned to the caller and the next
+// request for the tenant calls it again. The default resolver returns a zero TenantConfig for every
+// tenant.
+func (t *TenantRouterOptionsBuilder) SetResolver(
+	resolver func(ctx context.Context, tenant string) (*TenantConfig, error),
+) *TenantRouterOptionsBuilder {
+	t.Opts = append(t.Opts, func(opts *TenantRouterOptions) error {
+		opts.Resolver = resolver
+		return nil
+	})
+	return t
+}
+
+// SetClientOptions sets the value for the ClientOptions field. Specifies the options, such as the
+// URI, used to create the Clients of the tenants that have a Credential. They are applied before
+// the credential of the tenant. The default value is nil, in which case tenants with a Credential
+// cannot be routed.
+func (t *TenantRouterOptionsBuilder) SetClientOptions(opts ...Lister[ClientOptions]) *TenantRouterOptionsBuilder {
+	t.Opts = append(t.Opts, func(args *TenantRouterOptions) error {
+		args.ClientOptions = opts
+		return nil
+	})
+	return t
+}
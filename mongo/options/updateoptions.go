@@ -6,6 +6,8 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+
 // UpdateOneOptions represents arguments that can be used to configure UpdateOne
 // operations.
 //
@@ -18,6 +20,7 @@ type UpdateOneOptions struct {
 	Hint                     interface{}
 	Upsert                   *bool
 	Let                      interface{}
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // UpdateOneOptionsBuilder contains options to configure UpdateOne operations.
@@ -137,6 +140,20 @@ func (uo *UpdateOneOptionsBuilder) SetLet(l interface{}) *UpdateOneOptionsBuilde
 	return uo
 }
 
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (uo *UpdateOneOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *UpdateOneOptionsBuilder {
+	uo.Opts = append(uo.Opts, func(opts *UpdateOneOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return uo
+}
+
 // UpdateManyOptions represents arguments that can be used to configure UpdateMany
 // operations.
 //
@@ -149,6 +166,7 @@ type UpdateManyOptions struct {
 	Hint                     interface{}
 	Upsert                   *bool
 	Let                      interface{}
+	WriteConcern             *writeconcern.WriteConcern
 }
 
 // UpdateManyOptionsBuilder contains options to configure UpdateMany operations.
@@ -267,3 +285,17 @@ func (uo *UpdateManyOptionsBuilder) SetLet(l interface{}) *UpdateManyOptionsBuil
 
 	return uo
 }
+
+// SetWriteConcern sets the value for the WriteConcern field. WriteConcern is the write concern to use
+// for the operation instead of the write concern of the Collection. It cannot be set for an operation
+// that runs in a transaction. The default value is nil, which means that the write concern of the
+// Collection will be used.
+func (uo *UpdateManyOptionsBuilder) SetWriteConcern(wc *writeconcern.WriteConcern) *UpdateManyOptionsBuilder {
+	uo.Opts = append(uo.Opts, func(opts *UpdateManyOptions) error {
+		opts.WriteConcern = wc
+
+		return nil
+	})
+
+	return uo
+}
@@ -0,0 +1,112 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// WithTransactionOptions represents arguments that can be used to configure the mongo.WithTransaction
+// helper.
+//
+// See corresponding setter methods for documentation.
+type WithTransactionOptions struct {
+	TransactionOptions Lister[TransactionOptions]
+	Timeout            *time.Duration
+	InitialBackoff     *time.Duration
+	MaxBackoff         *time.Duration
+	AttemptMonitor     func(context.Context, *event.TransactionAttemptEvent)
+}
+
+// WithTransactionOptionsBuilder contains arguments to configure the mongo.WithTransaction helper.
+// Each option can be set through setter functions. See documentation for each setter function for
+// an explanation of the option.
+type WithTransactionOptionsBuilder struct {
+	Opts []func(*WithTransactionOptions) error
+}
+
+// WithTransaction creates a new WithTransactionOptions instance.
+func WithTransaction() *WithTransactionOptionsBuilder {
+	return &WithTransactionOptionsBuilder{}
+}
+
+// List returns a list of WithTransactionOptions setter functions.
+func (w *WithTransactionOptionsBuilder) List() []func(*WithTransactionOptions) error {
+	return w.Opts
+}
+
+// SetTransactionOptions sets the value for the TransactionOptions field. Specifies the options used
+// to start each transaction attempt. The default value is nil, which means that the defaults of the
+// session are used.
+func (w *WithTransactionOptionsBuilder) SetTransactionOptions(opts Lister[TransactionOptions]) *WithTransactionOptionsBuilder {
+	w.Opts = append(w.Opts, func(args *WithTransactionOptions) error {
+		args.TransactionOptions = opts
+
+		return nil
+	})
+
+	return w
+}
+
+// SetTimeout sets the value for the Timeout field. Specifies how long transaction attempts are
+// retried after the first attempt starts. Errors that occur after the timeout are returned without
+// retrying. The timeout cannot exceed 120 seconds, the limit used by Session.WithTransaction, and
+// larger values are capped. The default value is 120 seconds.
+func (w *WithTransactionOptionsBuilder) SetTimeout(d time.Duration) *WithTransactionOptionsBuilder {
+	w.Opts = append(w.Opts, func(args *WithTransactionOptions) error {
+		args.Timeout = &d
+
+		return nil
+	})
+
+	return w
+}
+
+// SetInitialBackoff sets the value for the InitialBackoff field. Specifies how long to wait before
+// retrying a transaction that failed with a TransientTransactionError, such as a write conflict
+// with a concurrent transaction. The delay doubles for each subsequent retry up to MaxBackoff, and a
+// random jitter of up to half the delay is subtracted. A value of 0 retries immediately. The default
+// value is 5 milliseconds.
+func (w *WithTransactionOptionsBuilder) SetInitialBackoff(d time.Duration) *WithTransactionOptionsBuilder {
+	w.Opts = append(w.Opts, func(args *WithTransactionOptions) error {
+		args.InitialBackoff = &d
+
+		return nil
+	})
+
+	return w
+}
+
+// SetMaxBackoff sets the value for the MaxBackoff field. Specifies the maximum delay between
+// transaction retries. The default value is 500 milliseconds.
+func (w *WithTransactionOptionsBuilder) SetMaxBackoff(d time.Duration) *WithTransactionOptionsBuilder {
+	w.Opts = append(w.Opts, func(args *WithTransactionOptions) error {
+		args.MaxBackoff = &d
+
+		return nil
+	})
+
+	return w
+}
+
+// SetAttemptMonitor sets the value for the AttemptMonitor field. Specifies a function called after
+// each transaction attempt with an event describing its outcome. The default value is nil, which
+// means no events are published.
+func (w *WithTransactionOptionsBuilder) SetAttemptMonitor(
+	fn func(context.Context, *event.TransactionAttemptEvent),
+) *WithTransactionOptionsBuilder {
+	w.Opts = append(w.Opts, func(args *WithTransactionOptions) error {
+		args.AttemptMonitor = fn
+
+		return nil
+	})
+
+	return w
+}
@@ -0,0 +1,452 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PartialFilterBuilder builds a partialFilterExpression for a partial index, which only supports
+// a subset of query operators: equality, $exists: true, $gt, $gte, $lt, $lte, $type, $in, and the
+// $and and $or logical operators. $in and $or require MongoDB 6.0 or later. Unlike a filter
+// document, constraints that the server rejects are reported when the expression is built instead
+// of when the index is created.
+//
+// For more information about partial indexes, see
+// https://www.mongodb.com/docs/manual/core/index-partial/.
+type PartialFilterBuilder struct {
+	fields bson.D
+	ors    []bson.A
+	err    error
+}
+
+// NewPartialFilterBuilder creates an empty PartialFilterBuilder.
+func NewPartialFilterBuilder() *PartialFilterBuilder {
+	return &PartialFilterBuilder{}
+}
+
+func (b *PartialFilterBuilder) operator(field, op string, value interface{}) *PartialFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+	if field == "" || strings.HasPrefix(field, "$") {
+		b.err = fmt.Errorf("invalid partial filter field %q", field)
+		return b
+	}
+	for i, f := range b.fields {
+		if f.Key != field {
+			continue
+		}
+		ops, ok := f.Value.(bson.D)
+		if !ok {
+			b.err = fmt.Errorf("partial filter field %q already has an equality constraint", field)
+			return b
+		}
+		for _, o := range ops {
+			if o.Key == op {
+				b.err = fmt.Errorf("partial filter field %q already has a %s constraint", field, op)
+				return b
+			}
+		}
+		b.fields[i].Value = append(ops, bson.E{Key: op, Value: value})
+		return b
+	}
+
+	b.fields = append(b.fields, bson.E{Key: field, Value: bson.D{{Key: op, Value: value}}})
+	return b
+}
+
+// Eq adds a constraint that the given field equals value.
+func (b *PartialFilterBuilder) Eq(field string, value interface{}) *PartialFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, f := range b.fields {
+		if f.Key == field {
+			b.err = fmt.Errorf("partial filter field %q already has a constraint", field)
+			return b
+		}
+	}
+	if field == "" || strings.HasPrefix(field, "$") {
+		b.err = fmt.Errorf("invalid partial filter field %q", field)
+		return b
+	}
+
+	b.fields = append(b.fields, bson.E{Key: field, Value: value})
+	return b
+}
+
+// Exists adds a constraint that the given field exists. Partial indexes do not support
+// {$exists: false}.
+func (b *PartialFilterBuilder) Exists(field string) *PartialFilterBuilder {
+	return b.operator(field, "$exists", true)
+}
+
+// Gt adds a constraint that the given field is greater than value.
+func (b *PartialFilterBuilder) Gt(field string, value interface{}) *PartialFilterBuilder {
+	return b.operator(field, "$gt", value)
+}
+
+// Gte adds a constraint that the given field is greater than or equal to value.
+func (b *PartialFilterBuilder) Gte(field string, value interface{}) *PartialFilterBuilder {
+	return b.operator(field, "$gte", value)
+}
+
+// Lt adds a constraint that the given field is less than value.
+func (b *PartialFilterBuilder) Lt(field string, value interface{}) *PartialFilterBuilder {
+	return b.operator(field, "$lt", value)
+}
+
+// Lte adds a constraint that the given field is less than or equal to value.
+func (b *PartialFilterBuilder) Lte(field string, value interface{}) *PartialFilterBuilder {
+	return b.operator(field, "$lte", value)
+}
+
+// Type adds a constraint that the given field has the BSON type with the given alias, such as
+// "string" or "date".
+func (b *PartialFilterBuilder) Type(field, alias string) *PartialFilterBuilder {
+	return b.operator(field, "$type", alias)
+}
+
+// In adds a constraint that the given field equals one of values. It requires MongoDB 6.0 or
+// later.
+func (b *PartialFilterBuilder) In(field string, values ...interface{}) *PartialFilterBuilder {
+	if len(values) == 0 && b.err == nil {
+		b.err = fmt.Errorf("partial filter $in for field %q requires at least one value", field)
+		return b
+	}
+	return b.operator(field, "$in", bson.A(values))
+}
+
+// Or adds a constraint that at least one of the given expressions matches. It requires MongoDB
+// 6.0 or later.
+func (b *PartialFilterBuilder) Or(branches ...*PartialFilterBuilder) *PartialFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(branches) == 0 {
+		b.err = errors.New("partial filter $or requires at least one expression")
+		return b
+	}
+
+	or := make(bson.A, 0, len(branches))
+	for _, branch := range branches {
+		d, err := branch.Build()
+		if err != nil {
+			b.err = err
+			return b
+		}
+		or = append(or, d)
+	}
+	b.ors = append(b.ors, or)
+	return b
+}
+
+// Build returns the partialFilterExpression, or the first error encountered while adding
+// constraints.
+func (b *PartialFilterBuilder) Build() (bson.D, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.fields) == 0 && len(b.ors) == 0 {
+		return nil, errors.New("partial filter expression cannot be empty")
+	}
+
+	d := make(bson.D, len(b.fields), len(b.fields)+1)
+	copy(d, b.fields)
+	switch len(b.ors) {
+	case 0:
+	case 1:
+		d = append(d, bson.E{Key: "$or", Value: b.ors[0]})
+	default:
+		and := make(bson.A, 0, len(b.ors))
+		for _, or := range b.ors {
+			and = append(and, bson.D{{Key: "$or", Value: or}})
+		}
+		d = append(d, bson.E{Key: "$and", Value: and})
+	}
+	return d, nil
+}
+
+// partialFilterOperators are the query operators supported in a partialFilterExpression.
+var partialFilterOperators = map[string]bool{
+	"$eq": true, "$exists": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true, "$type": true, "$in": true,
+}
+
+// ValidatePartialFilterExpression reports whether filter only uses the query operators that the
+// server supports in a partialFilterExpression. See PartialFilterBuilder for the supported
+// operators.
+func ValidatePartialFilterExpression(filter interface{}) error {
+	_, err := normalizePartialFilter(filter)
+	return err
+}
+
+// PartialFilterExpressionsEquivalent reports whether two partialFilterExpressions select the same
+// documents regardless of how they are written. Top-level and nested $and expressions are
+// flattened, operators on the same field are split into separate constraints, and the order of
+// constraints, $or branches, and $in values is ignored. Numbers compare equal regardless of their
+// BSON type, and {field: value} is equivalent to {field: {$eq: value}}.
+//
+// Comparing a desired expression with the one returned by listIndexes with this function avoids
+// recreating an index because the server or the application reformatted the expression.
+func PartialFilterExpressionsEquivalent(a, b interface{}) (bool, error) {
+	na, err := normalizePartialFilter(a)
+	if err != nil {
+		return false, err
+	}
+	nb, err := normalizePartialFilter(b)
+	if err != nil {
+		return false, err
+	}
+	return compareBSONDocuments(na, nb, false) == 0, nil
+}
+
+// normalizePartialFilter validates a partialFilterExpression and returns its canonical form, a
+// document {$and: [...]} whose elements are the sorted, distinct constraints of the expression.
+func normalizePartialFilter(filter interface{}) (bson.Raw, error) {
+	doc, err := marshalPartialFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	conjuncts, err := partialFilterConjuncts(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(conjuncts) == 0 {
+		return nil, errors.New("partial filter expression cannot be empty")
+	}
+	return canonicalConjunction(conjuncts)
+}
+
+func marshalPartialFilter(filter interface{}) (bson.Raw, error) {
+	switch f := filter.(type) {
+	case nil:
+		return nil, errors.New("partial filter expression cannot be nil")
+	case bson.Raw:
+		return f, nil
+	case *PartialFilterBuilder:
+		d, err := f.Build()
+		if err != nil {
+			return nil, err
+		}
+		filter = d
+	}
+	doc, err := bson.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling partial filter expression: %w", err)
+	}
+	return doc, nil
+}
+
+// partialFilterConjuncts returns the constraints of a partial filter document, each of which is a
+// single-element document {field: {op: value}} or {$or: [...]}.
+func partialFilterConjuncts(doc bson.Raw) ([]bson.Raw, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var conjuncts []bson.Raw
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		switch {
+		case key == "$and":
+			branches, err := partialFilterBranches(key, val)
+			if err != nil {
+				return nil, err
+			}
+			for _, branch := range branches {
+				c, err := partialFilterConjuncts(branch)
+				if err != nil {
+					return nil, err
+				}
+				conjuncts = append(conjuncts, c...)
+			}
+		case key == "$or":
+			branches, err := partialFilterBranches(key, val)
+			if err != nil {
+				return nil, err
+			}
+			or := make(bson.A, 0, len(branches))
+			for _, branch := range branches {
+				normalized, err := normalizePartialFilter(branch)
+				if err != nil {
+					return nil, err
+				}
+				or = append(or, normalized)
+			}
+			sortRawValues(or)
+			c, err := bson.Marshal(bson.D{{Key: "$or", Value: dedupeSorted(or)}})
+			if err != nil {
+				return nil, err
+			}
+			conjuncts = append(conjuncts, c)
+		case strings.HasPrefix(key, "$"):
+			return nil, fmt.Errorf("operator %s is not supported in a partial filter expression", key)
+		default:
+			c, err := fieldConjuncts(key, val)
+			if err != nil {
+				return nil, err
+			}
+			conjuncts = append(conjuncts, c...)
+		}
+	}
+	return conjuncts, nil
+}
+
+func partialFilterBranches(op string, val bson.RawValue) ([]bson.Raw, error) {
+	arr, ok := val.ArrayOK()
+	if !ok {
+		return nil, fmt.Errorf("partial filter %s must be an array", op)
+	}
+	values, err := arr.Values()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("partial filter %s must not be empty", op)
+	}
+	branches := make([]bson.Raw, 0, len(values))
+	for _, v := range values {
+		d, ok := v.DocumentOK()
+		if !ok {
+			return nil, fmt.Errorf("partial filter %s elements must be documents", op)
+		}
+		branches = append(branches, d)
+	}
+	return branches, nil
+}
+
+// fieldConjuncts returns a constraint for each operator applied to a field.
+func fieldConjuncts(field string, val bson.RawValue) ([]bson.Raw, error) {
+	if val.Type == bson.TypeRegex {
+		return nil, fmt.Errorf("regular expressions are not supported in a partial filter expression")
+	}
+
+	ops, isDoc := val.DocumentOK()
+	if isDoc {
+		if first, err := ops.IndexErr(0); err != nil || !strings.HasPrefix(first.Key(), "$") {
+			// An empty document or one without operators is an equality match.
+			isDoc = false
+		}
+	}
+	if !isDoc {
+		c, err := fieldConjunct(field, "$eq", val)
+		if err != nil {
+			return nil, err
+		}
+		return []bson.Raw{c}, nil
+	}
+
+	elems, err := ops.Elements()
+	if err != nil {
+		return nil, err
+	}
+	conjuncts := make([]bson.Raw, 0, len(elems))
+	for _, elem := range elems {
+		op, v := elem.Key(), elem.Value()
+		if !partialFilterOperators[op] {
+			return nil, fmt.Errorf("operator %s is not supported in a partial filter expression", op)
+		}
+		switch op {
+		case "$exists":
+			exists, ok := v.BooleanOK()
+			if !ok && v.IsNumber() {
+				exists, ok = numberAsFloat(v) != 0, true
+			}
+			if !ok || !exists {
+				return nil, fmt.Errorf("partial filter expressions only support {$exists: true} for field %q", field)
+			}
+			v = bson.RawValue{Type: bson.TypeBoolean, Value: []byte{1}}
+		case "$in":
+			arr, ok := v.ArrayOK()
+			if !ok {
+				return nil, fmt.Errorf("partial filter $in for field %q must be an array", field)
+			}
+			values, err := arr.Values()
+			if err != nil {
+				return nil, err
+			}
+			if len(values) == 0 {
+				return nil, fmt.Errorf("partial filter $in for field %q requires at least one value", field)
+			}
+			in := make(bson.A, 0, len(values))
+			for _, iv := range values {
+				if iv.Type == bson.TypeRegex {
+					return nil, fmt.Errorf("regular expressions are not supported in a partial filter expression")
+				}
+				in = append(in, iv)
+			}
+			sortRawValues(in)
+			if len(in) == 1 {
+				// {$in: [x]} is the same as {$eq: x}.
+				op, v = "$eq", in[0].(bson.RawValue)
+				break
+			}
+			c, err := bson.Marshal(bson.D{{Key: field, Value: bson.D{{Key: op, Value: dedupeSorted(in)}}}})
+			if err != nil {
+				return nil, err
+			}
+			conjuncts = append(conjuncts, c)
+			continue
+		}
+		c, err := fieldConjunct(field, op, v)
+		if err != nil {
+			return nil, err
+		}
+		conjuncts = append(conjuncts, c)
+	}
+	return conjuncts, nil
+}
+
+func fieldConjunct(field, op string, v bson.RawValue) (bson.Raw, error) {
+	return bson.Marshal(bson.D{{Key: field, Value: bson.D{{Key: op, Value: v}}}})
+}
+
+// canonicalConjunction sorts and deduplicates the given constraints.
+func canonicalConjunction(conjuncts []bson.Raw) (bson.Raw, error) {
+	and := make(bson.A, 0, len(conjuncts))
+	for _, c := range conjuncts {
+		and = append(and, c)
+	}
+	sortRawValues(and)
+	return bson.Marshal(bson.D{{Key: "$and", Value: dedupeSorted(and)}})
+}
+
+// sortRawValues sorts a slice of bson.Raw and bson.RawValue values in BSON order.
+func sortRawValues(values bson.A) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return compareValues(toRawValue(values[i]), toRawValue(values[j])) < 0
+	})
+}
+
+// dedupeSorted removes adjacent equal values from a sorted slice.
+func dedupeSorted(values bson.A) bson.A {
+	out := make(bson.A, 0, len(values))
+	for _, v := range values {
+		if len(out) > 0 && compareValues(toRawValue(out[len(out)-1]), toRawValue(v)) == 0 {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func toRawValue(v interface{}) bson.RawValue {
+	switch tv := v.(type) {
+	case bson.Raw:
+		return bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: tv}
+	case bson.RawValue:
+		return tv
+	}
+	return bson.RawValue{}
+}
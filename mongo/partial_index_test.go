@@ -0,0 +1,175 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestPartialFilterBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("build", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewPartialFilterBuilder().
+			Eq("status", "active").
+			Gte("age", 18).
+			Lt("age", 65).
+			Exists("email").
+			Or(NewPartialFilterBuilder().Eq("tier", "gold"), NewPartialFilterBuilder().In("region", "eu", "us")).
+			Build()
+		require.NoError(t, err, "Build error")
+		want := bson.D{
+			{"status", "active"},
+			{"age", bson.D{{"$gte", 18}, {"$lt", 65}}},
+			{"email", bson.D{{"$exists", true}}},
+			{"$or", bson.A{
+				bson.D{{"tier", "gold"}},
+				bson.D{{"region", bson.D{{"$in", bson.A{"eu", "us"}}}}},
+			}},
+		}
+		assert.Equal(t, want, filter, "filter mismatch")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		builders := []*PartialFilterBuilder{
+			NewPartialFilterBuilder(),
+			NewPartialFilterBuilder().Eq("a", 1).Gt("a", 0),
+			NewPartialFilterBuilder().Gt("a", 0).Gt("a", 1),
+			NewPartialFilterBuilder().Eq("$a", 1),
+			NewPartialFilterBuilder().In("a"),
+			NewPartialFilterBuilder().Or(),
+			NewPartialFilterBuilder().Or(NewPartialFilterBuilder()),
+		}
+		for i, b := range builders {
+			_, err := b.Build()
+			assert.Error(t, err, "expected Build error for builder %d", i)
+		}
+	})
+
+	t.Run("index model", func(t *testing.T) {
+		t.Parallel()
+
+		model, err := NewIndexModelBuilder().
+			Ascending("email").
+			Unique().
+			Partial(NewPartialFilterBuilder().Exists("email")).
+			Build()
+		require.NoError(t, err, "Build error")
+		args, err := mongoutil.NewOptions[options.IndexOptions](model.Options)
+		require.NoError(t, err, "NewOptions error")
+		assert.Equal(t, bson.D{{"email", bson.D{{"$exists", true}}}}, args.PartialFilterExpression, "filter mismatch")
+
+		_, err = NewIndexModelBuilder().Ascending("a").Partial(bson.D{{"a", bson.D{{"$ne", 1}}}}).Build()
+		assert.Error(t, err, "expected Build error")
+	})
+}
+
+func TestValidatePartialFilterExpression(t *testing.T) {
+	t.Parallel()
+
+	valid := []bson.D{
+		{{"a", 1}},
+		{{"a", bson.D{{"b", 1}}}},
+		{{"a", bson.D{{"$type", "string"}}}, {"b", bson.D{{"$exists", 1}}}},
+		{{"$and", bson.A{bson.D{{"a", 1}}, bson.D{{"b", bson.D{{"$gt", 2}}}}}}},
+		{{"$or", bson.A{bson.D{{"a", 1}}, bson.D{{"b", bson.D{{"$in", bson.A{1, 2}}}}}}}},
+	}
+	for _, filter := range valid {
+		assert.NoError(t, ValidatePartialFilterExpression(filter), "unexpected error for %v", filter)
+	}
+
+	invalid := []bson.D{
+		{},
+		{{"a", bson.D{{"$ne", 1}}}},
+		{{"a", bson.D{{"$exists", false}}}},
+		{{"a", bson.Regex{Pattern: "^x"}}},
+		{{"a", bson.D{{"$in", bson.A{}}}}},
+		{{"$nor", bson.A{bson.D{{"a", 1}}}}},
+		{{"$and", bson.D{{"a", 1}}}},
+		{{"$expr", bson.D{{"$eq", bson.A{"$a", 1}}}}},
+	}
+	for _, filter := range invalid {
+		assert.Error(t, ValidatePartialFilterExpression(filter), "expected error for %v", filter)
+	}
+}
+
+func TestPartialFilterExpressionsEquivalent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		a, b       bson.D
+		equivalent bool
+	}{
+		{"numeric types", bson.D{{"a", int32(1)}}, bson.D{{"a", 1.0}}, true},
+		{"implicit equality", bson.D{{"a", 1}}, bson.D{{"a", bson.D{{"$eq", 1}}}}, true},
+		{
+			"field order",
+			bson.D{{"a", 1}, {"b", bson.D{{"$gt", 2}, {"$lt", 5}}}},
+			bson.D{{"b", bson.D{{"$lt", 5}}}, {"a", 1}, {"b", bson.D{{"$gt", 2}}}},
+			true,
+		},
+		{
+			"and flattening",
+			bson.D{{"$and", bson.A{bson.D{{"a", 1}}, bson.D{{"b", 2}}}}},
+			bson.D{{"b", 2}, {"a", 1}},
+			true,
+		},
+		{
+			"or branch and in value order",
+			bson.D{{"$or", bson.A{bson.D{{"a", bson.D{{"$in", bson.A{3, 1}}}}}, bson.D{{"b", true}}}}},
+			bson.D{{"$or", bson.A{bson.D{{"b", true}}, bson.D{{"a", bson.D{{"$in", bson.A{1, 3}}}}}}}},
+			true,
+		},
+		{"single value in", bson.D{{"a", bson.D{{"$in", bson.A{1}}}}}, bson.D{{"a", 1}}, true},
+		{"exists number", bson.D{{"a", bson.D{{"$exists", 1}}}}, bson.D{{"a", bson.D{{"$exists", true}}}}, true},
+		{"different values", bson.D{{"a", 1}}, bson.D{{"a", 2}}, false},
+		{"different operators", bson.D{{"a", bson.D{{"$gt", 1}}}}, bson.D{{"a", bson.D{{"$gte", 1}}}}, false},
+		{"extra constraint", bson.D{{"a", 1}}, bson.D{{"a", 1}, {"b", 1}}, false},
+		{"embedded document order", bson.D{{"a", bson.D{{"x", 1}, {"y", 2}}}}, bson.D{{"a", bson.D{{"y", 2}, {"x", 1}}}}, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			equivalent, err := PartialFilterExpressionsEquivalent(tc.a, tc.b)
+			require.NoError(t, err, "PartialFilterExpressionsEquivalent error")
+			assert.Equal(t, tc.equivalent, equivalent, "equivalence mismatch")
+		})
+	}
+
+	t.Run("CompareWithServer", func(t *testing.T) {
+		t.Parallel()
+
+		iv := setupColl("partial_diff").Indexes()
+		spec, err := bson.Marshal(bson.D{
+			{"v", 2}, {"key", bson.D{{"a", 1}}}, {"name", "a_1"},
+			{"partialFilterExpression", bson.D{{"$and", bson.A{bson.D{{"b", bson.D{{"$gt", int64(5)}}}}, bson.D{{"c", "x"}}}}}},
+		})
+		require.NoError(t, err, "Marshal error")
+
+		desired := []IndexModel{{
+			Keys:    bson.D{{"a", 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.D{{"c", "x"}, {"b", bson.D{{"$gt", 5}}}}),
+		}}
+		diff, err := iv.diffIndexes(desired, []bson.Raw{spec})
+		require.NoError(t, err, "diffIndexes error")
+		assert.True(t, diff.Empty(), "expected no differences, got %+v", diff)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+)
+
+// PinnedConnection describes the connection a cursor or transaction is pinned to when the Client
+// is connected to a load balancer. In load balanced mode, a cursor or transaction must use the same
+// connection for all of its commands so that they reach the same mongos, and the connection is not
+// returned to the pool until the cursor is closed or exhausted or the transaction is committed or
+// aborted. Pinned connections that are never released are a common cause of connection pool
+// exhaustion.
+//
+// The ConnectionPinned and ConnectionUnpinned pool events published to the event.PoolMonitor of the
+// Client can be matched with a PinnedConnection by ConnectionID.
+type PinnedConnection struct {
+	// ConnectionID is the driver-generated ID of the connection, as reported in pool events.
+	ConnectionID int64
+
+	// ServerConnectionID is the server-generated ID of the connection, if the server reported one.
+	ServerConnectionID *int64
+
+	// ServiceID identifies the mongos behind the load balancer that the connection is connected to.
+	ServiceID *bson.ObjectID
+
+	// Address is the address of the load balancer.
+	Address address.Address
+}
+
+func newPinnedConnection(conn mnet.Describer) *PinnedConnection {
+	if conn == nil {
+		return nil
+	}
+	return &PinnedConnection{
+		ConnectionID:       conn.DriverConnectionID(),
+		ServerConnectionID: conn.ServerConnectionID(),
+		ServiceID:          conn.Description().ServiceID,
+		Address:            conn.Address(),
+	}
+}
+
+// PinnedConnection returns the connection the cursor is pinned to, or nil if the cursor is not
+// pinned to a connection. Cursors are only pinned when the Client is connected to a load balancer
+// and are unpinned when they are closed or exhausted.
+func (c *Cursor) PinnedConnection() *PinnedConnection {
+	pc, ok := c.bc.(interface{ PinnedConnection() mnet.Describer })
+	if !ok {
+		return nil
+	}
+	return newPinnedConnection(pc.PinnedConnection())
+}
+
+// PinnedConnection returns the connection the transaction in progress on the session is pinned to,
+// or nil if there is no pinned connection. Transactions are only pinned when the Client is connected
+// to a load balancer and are unpinned when they are committed or aborted.
+func (s *Session) PinnedConnection() *PinnedConnection {
+	if s.clientSession.PinnedConnection == nil {
+		return nil
+	}
+	return newPinnedConnection(s.clientSession.PinnedConnection)
+}
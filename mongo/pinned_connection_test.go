@@ -0,0 +1,62 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+)
+
+// pinnedDescriber is an mnet.Describer for a connection pinned behind a load balancer. Methods
+// that are not overridden panic.
+type pinnedDescriber struct {
+	mnet.Describer
+	serviceID bson.ObjectID
+}
+
+func (pd *pinnedDescriber) DriverConnectionID() int64  { return 7 }
+func (pd *pinnedDescriber) ServerConnectionID() *int64 { return nil }
+func (pd *pinnedDescriber) Address() address.Address   { return "lb:27017" }
+func (pd *pinnedDescriber) Description() description.Server {
+	return description.Server{ServiceID: &pd.serviceID}
+}
+
+type pinnedBatchCursor struct {
+	*testBatchCursor
+	conn mnet.Describer
+}
+
+func (pbc *pinnedBatchCursor) PinnedConnection() mnet.Describer { return pbc.conn }
+
+func TestCursorPinnedConnection(t *testing.T) {
+	t.Run("not pinned", func(t *testing.T) {
+		cur, err := newCursor(newTestBatchCursor(1, 1), nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+		assert.Nil(t, cur.PinnedConnection(), "expected no pinned connection")
+
+		cur, err = newCursor(&pinnedBatchCursor{testBatchCursor: newTestBatchCursor(1, 1)}, nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+		assert.Nil(t, cur.PinnedConnection(), "expected no pinned connection")
+	})
+	t.Run("pinned", func(t *testing.T) {
+		conn := &pinnedDescriber{serviceID: bson.NewObjectID()}
+		cur, err := newCursor(&pinnedBatchCursor{testBatchCursor: newTestBatchCursor(1, 1), conn: conn}, nil, nil)
+		require.NoError(t, err, "newCursor error: %v", err)
+
+		pc := cur.PinnedConnection()
+		require.NotNil(t, pc, "expected a pinned connection")
+		assert.Equal(t, int64(7), pc.ConnectionID, "expected connection ID")
+		assert.Equal(t, address.Address("lb:27017"), pc.Address, "expected address")
+		assert.Equal(t, &conn.serviceID, pc.ServiceID, "expected service ID")
+	})
+}
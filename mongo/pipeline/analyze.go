@@ -0,0 +1,511 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package pipeline provides tools for working with aggregation pipelines.
+//
+// The stage builders construct pipelines from typed stages, which render to the same documents as
+// a hand-written mongo.Pipeline. Validate checks the order of the stages and their specifications:
+//
+//	p := pipeline.New(
+//		pipeline.Match(pipeline.Eq("status", "active"), pipeline.Gte("qty", 10)),
+//		pipeline.Group("$region", pipeline.Sum("n", 1), pipeline.Avg("avgQty", "$qty")),
+//		pipeline.Sort(bson.D{{"n", -1}}),
+//		pipeline.Limit(10),
+//	)
+//	if err := p.Validate(); err != nil {
+//		return err
+//	}
+//	cursor, err := coll.Aggregate(ctx, p)
+//
+// Analyze checks a pipeline for common inefficiencies without running it, which makes it suitable
+// for tests and CI checks of the pipelines of an application:
+//
+//	warnings, err := pipeline.Analyze(mongo.Pipeline{
+//		{{"$project", bson.D{{"name", 1}}}},
+//		{{"$match", bson.D{{"status", "active"}}}},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	for _, w := range warnings {
+//		t.Error(w)
+//	}
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Rule identifies the check that produced a Warning.
+type Rule string
+
+// These constants are the rules checked by Analyze.
+const (
+	// RuleMatchDroppedField reports a $match stage that filters on a field removed by an earlier
+	// $project, $unset, or $group stage, so that it never matches or always matches.
+	RuleMatchDroppedField Rule = "match-dropped-field"
+
+	// RuleLeadingMatch reports a pipeline that does not start with a $match stage, which is the
+	// only position where a filter can use an index.
+	RuleLeadingMatch Rule = "leading-match"
+
+	// RuleSortBeforeGroup reports a $sort stage without a $limit before a $group stage that does
+	// not depend on the order of its input, which sorts the documents for nothing.
+	RuleSortBeforeGroup Rule = "sort-before-group"
+)
+
+// Warning is an inefficiency found by Analyze.
+type Warning struct {
+	Rule Rule
+
+	// Stage is the index of the stage the warning applies to.
+	Stage int
+
+	Message string
+}
+
+// String returns a description of the warning that includes its stage and rule.
+func (w Warning) String() string {
+	return fmt.Sprintf("stage %d: %s (%s)", w.Stage, w.Message, w.Rule)
+}
+
+// sourceStages are the stages that must be the first stage of a pipeline, which cannot be preceded
+// by a $match stage.
+var sourceStages = map[string]bool{
+	"$changeStream":   true,
+	"$collStats":      true,
+	"$currentOp":      true,
+	"$documents":      true,
+	"$geoNear":        true,
+	"$indexStats":     true,
+	"$listSessions":   true,
+	"$planCacheStats": true,
+	"$search":         true,
+	"$searchMeta":     true,
+	"$vectorSearch":   true,
+}
+
+// orderedAccumulators are the $group accumulators whose results depend on the order of the input
+// documents.
+var orderedAccumulators = map[string]bool{
+	"$first":        true,
+	"$firstN":       true,
+	"$last":         true,
+	"$lastN":        true,
+	"$mergeObjects": true,
+	"$push":         true,
+}
+
+// Analyze checks the aggregation pipeline p, which must be an array of stage documents such as a
+// mongo.Pipeline or a bson.A, for the inefficiencies described by the Rule constants. It returns
+// the warnings in stage order, or an error if p is not a valid pipeline.
+//
+// Analyze only inspects the stages of p. It does not know the indexes of the collection, so a
+// warning is a hint to review the pipeline rather than proof of a slow query.
+func Analyze(p interface{}) ([]Warning, error) {
+	stages, err := marshalStages(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []Warning
+	warnings = append(warnings, checkLeadingMatch(stages)...)
+	warnings = append(warnings, checkDroppedFields(stages)...)
+	warnings = append(warnings, checkSortBeforeGroup(stages)...)
+	sortWarnings(warnings)
+	return warnings, nil
+}
+
+// stage is a stage of a pipeline.
+type stage struct {
+	name string
+	spec bson.RawValue
+}
+
+func marshalStages(p interface{}) ([]stage, error) {
+	if p == nil {
+		return nil, errors.New("pipeline cannot be nil")
+	}
+	t, data, err := bson.MarshalValue(p)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling pipeline: %w", err)
+	}
+	if t != bson.TypeArray {
+		return nil, fmt.Errorf("pipeline must be an array of stages, got BSON type %s", t)
+	}
+	values, err := bson.RawArray(data).Values()
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]stage, 0, len(values))
+	for i, val := range values {
+		doc, ok := val.DocumentOK()
+		if !ok {
+			return nil, fmt.Errorf("pipeline stage %d: expected a document, got BSON type %s", i, val.Type)
+		}
+		elems, err := doc.Elements()
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) != 1 {
+			return nil, fmt.Errorf("pipeline stage %d: expected a single stage, got %d fields", i, len(elems))
+		}
+		stages = append(stages, stage{name: elems[0].Key(), spec: elems[0].Value()})
+	}
+	return stages, nil
+}
+
+func checkLeadingMatch(stages []stage) []Warning {
+	if len(stages) == 0 || stages[0].name == "$match" || sourceStages[stages[0].name] {
+		return nil
+	}
+	for i, s := range stages {
+		if s.name == "$match" {
+			return []Warning{{
+				Rule:    RuleLeadingMatch,
+				Stage:   i,
+				Message: fmt.Sprintf("$match follows %s and cannot use an index; move it to the start of the pipeline", stages[0].name),
+			}}
+		}
+	}
+	return []Warning{{
+		Rule:    RuleLeadingMatch,
+		Stage:   0,
+		Message: "pipeline does not start with $match and reads every document of the collection",
+	}}
+}
+
+// fieldState tracks the top-level fields of the documents output by the stages of a pipeline.
+type fieldState struct {
+	known      bool            // whether the fields are known
+	restricted bool            // whether only the kept fields exist
+	kept       map[string]bool // the fields that exist if restricted
+	stage      int             // the stage that restricted the fields
+	dropped    map[string]int  // the fields removed by a stage, to that stage
+}
+
+func checkDroppedFields(stages []stage) []Warning {
+	var warnings []Warning
+	state := fieldState{known: true, dropped: make(map[string]int)}
+	for i, s := range stages {
+		switch s.name {
+		case "$match":
+			if !state.known {
+				continue
+			}
+			doc, ok := s.spec.DocumentOK()
+			if !ok {
+				continue
+			}
+			for _, field := range filterFields(doc, nil) {
+				if by, ok := state.removedBy(field); ok {
+					warnings = append(warnings, Warning{
+						Rule:  RuleMatchDroppedField,
+						Stage: i,
+						Message: fmt.Sprintf("$match references field %q, which is removed by the %s stage %d",
+							field, stages[by].name, by),
+					})
+				}
+			}
+		case "$project":
+			state.project(i, s.spec)
+		case "$unset":
+			state.unset(i, s.spec)
+		case "$addFields", "$set":
+			if doc, ok := s.spec.DocumentOK(); ok {
+				elems, _ := doc.Elements()
+				for _, elem := range elems {
+					state.add(topLevel(elem.Key()))
+				}
+			}
+		case "$lookup", "$graphLookup":
+			if doc, ok := s.spec.DocumentOK(); ok {
+				if as, ok := doc.Lookup("as").StringValueOK(); ok {
+					state.add(topLevel(as))
+				}
+			}
+		case "$group":
+			state.restrict(i)
+			if doc, ok := s.spec.DocumentOK(); ok {
+				elems, _ := doc.Elements()
+				for _, elem := range elems {
+					state.kept[elem.Key()] = true
+				}
+			}
+		case "$replaceRoot", "$replaceWith", "$facet", "$bucket", "$bucketAuto", "$sortByCount", "$count",
+			"$setWindowFields", "$densify", "$fill":
+			state.known = false
+		}
+	}
+	return warnings
+}
+
+// removedBy returns the stage that removed the top-level field, if any.
+func (s *fieldState) removedBy(field string) (int, bool) {
+	if by, ok := s.dropped[field]; ok {
+		return by, true
+	}
+	if s.restricted && !s.kept[field] {
+		return s.stage, true
+	}
+	return 0, false
+}
+
+func (s *fieldState) restrict(stage int) {
+	s.restricted = true
+	s.kept = make(map[string]bool)
+	s.stage = stage
+	s.dropped = make(map[string]int)
+}
+
+func (s *fieldState) add(field string) {
+	delete(s.dropped, field)
+	if s.restricted {
+		s.kept[field] = true
+	}
+}
+
+func (s *fieldState) drop(stage int, field string) {
+	s.dropped[field] = stage
+	delete(s.kept, field)
+}
+
+func (s *fieldState) project(stage int, spec bson.RawValue) {
+	doc, ok := spec.DocumentOK()
+	if !ok {
+		return
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return
+	}
+
+	inclusion := false
+	for _, elem := range elems {
+		if elem.Key() != "_id" && !isExclusion(elem.Value()) {
+			inclusion = true
+		}
+	}
+	if !inclusion {
+		for _, elem := range elems {
+			if !strings.Contains(elem.Key(), ".") && isFalse(elem.Value()) {
+				s.drop(stage, elem.Key())
+			}
+		}
+		return
+	}
+
+	prev := *s
+	s.restrict(stage)
+	s.kept["_id"] = true
+	for _, elem := range elems {
+		field := topLevel(elem.Key())
+		if isFalse(elem.Value()) {
+			delete(s.kept, field)
+			continue
+		}
+		if _, removed := prev.removedBy(field); removed && isTrue(elem.Value()) {
+			// Including a field removed by an earlier stage does not restore it.
+			continue
+		}
+		s.kept[field] = true
+	}
+}
+
+func (s *fieldState) unset(stage int, spec bson.RawValue) {
+	if field, ok := spec.StringValueOK(); ok {
+		if !strings.Contains(field, ".") {
+			s.drop(stage, field)
+		}
+		return
+	}
+	arr, ok := spec.ArrayOK()
+	if !ok {
+		return
+	}
+	values, _ := arr.Values()
+	for _, val := range values {
+		if field, ok := val.StringValueOK(); ok && !strings.Contains(field, ".") {
+			s.drop(stage, field)
+		}
+	}
+}
+
+// filterFields appends the top-level fields referenced by the query filter to fields.
+func filterFields(filter bson.Raw, fields []string) []string {
+	elems, err := filter.Elements()
+	if err != nil {
+		return fields
+	}
+	for _, elem := range elems {
+		key := elem.Key()
+		switch key {
+		case "$and", "$or", "$nor":
+			arr, ok := elem.Value().ArrayOK()
+			if !ok {
+				continue
+			}
+			values, _ := arr.Values()
+			for _, val := range values {
+				if doc, ok := val.DocumentOK(); ok {
+					fields = filterFields(doc, fields)
+				}
+			}
+		case "$expr":
+			fields = exprFields(elem.Value(), fields)
+		default:
+			if !strings.HasPrefix(key, "$") {
+				fields = appendField(fields, topLevel(key))
+			}
+		}
+	}
+	return fields
+}
+
+// exprFields appends the top-level fields of the field paths in the aggregation expression to
+// fields.
+func exprFields(expr bson.RawValue, fields []string) []string {
+	switch expr.Type {
+	case bson.TypeString:
+		s := expr.StringValue()
+		if strings.HasPrefix(s, "$") && !strings.HasPrefix(s, "$$") {
+			fields = appendField(fields, topLevel(s[1:]))
+		}
+	case bson.TypeEmbeddedDocument, bson.TypeArray:
+		values, err := bson.Raw(expr.Value).Elements()
+		if err != nil {
+			return fields
+		}
+		for _, elem := range values {
+			if elem.Key() == "$literal" {
+				continue
+			}
+			fields = exprFields(elem.Value(), fields)
+		}
+	}
+	return fields
+}
+
+func appendField(fields []string, field string) []string {
+	for _, f := range fields {
+		if f == field {
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+func checkSortBeforeGroup(stages []stage) []Warning {
+	var warnings []Warning
+	sortStage := -1
+	for i, s := range stages {
+		switch s.name {
+		case "$sort":
+			sortStage = i
+		case "$limit":
+			sortStage = -1
+		case "$group":
+			if sortStage >= 0 && !isOrderedGroup(s.spec) {
+				warnings = append(warnings, Warning{
+					Rule:    RuleSortBeforeGroup,
+					Stage:   sortStage,
+					Message: fmt.Sprintf("$sort without $limit before the $group stage %d, which does not depend on the order of its input", i),
+				})
+			}
+			sortStage = -1
+		case "$bucket", "$bucketAuto", "$sortByCount", "$count", "$facet":
+			sortStage = -1
+		}
+	}
+	return warnings
+}
+
+// isOrderedGroup reports whether the $group stage spec uses an accumulator that depends on the
+// order of its input documents.
+func isOrderedGroup(spec bson.RawValue) bool {
+	doc, ok := spec.DocumentOK()
+	if !ok {
+		return false
+	}
+	elems, _ := doc.Elements()
+	for _, elem := range elems {
+		acc, ok := elem.Value().DocumentOK()
+		if elem.Key() == "_id" || !ok {
+			continue
+		}
+		accElems, _ := acc.Elements()
+		for _, accElem := range accElems {
+			if orderedAccumulators[accElem.Key()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortWarnings sorts warnings by stage, keeping the order of the warnings of each stage.
+func sortWarnings(warnings []Warning) {
+	for i := 1; i < len(warnings); i++ {
+		for j := i; j > 0 && warnings[j].Stage < warnings[j-1].Stage; j-- {
+			warnings[j], warnings[j-1] = warnings[j-1], warnings[j]
+		}
+	}
+}
+
+func topLevel(path string) string {
+	return strings.SplitN(path, ".", 2)[0]
+}
+
+// isFalse reports whether the projection value excludes its field.
+func isFalse(val bson.RawValue) bool {
+	if b, ok := val.BooleanOK(); ok {
+		return !b
+	}
+	if val.IsNumber() {
+		i, ok := val.AsInt64OK()
+		return ok && i == 0
+	}
+	return false
+}
+
+// isTrue reports whether the projection value includes its field as is.
+func isTrue(val bson.RawValue) bool {
+	if b, ok := val.BooleanOK(); ok {
+		return b
+	}
+	if val.IsNumber() {
+		i, ok := val.AsInt64OK()
+		return !ok || i != 0
+	}
+	return false
+}
+
+// isExclusion reports whether the projection value excludes its field or only fields of its
+// embedded documents.
+func isExclusion(val bson.RawValue) bool {
+	if isFalse(val) {
+		return true
+	}
+	doc, ok := val.DocumentOK()
+	if !ok {
+		return false
+	}
+	elems, err := doc.Elements()
+	if err != nil || len(elems) == 0 {
+		return false
+	}
+	for _, elem := range elems {
+		if strings.HasPrefix(elem.Key(), "$") || !isExclusion(elem.Value()) {
+			return false
+		}
+	}
+	return true
+}
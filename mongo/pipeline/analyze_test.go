@@ -0,0 +1,182 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	match := func(filter bson.D) bson.D { return bson.D{{"$match", filter}} }
+
+	testCases := []struct {
+		name     string
+		pipeline interface{}
+		want     []Warning
+	}{
+		{
+			name: "efficient",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"status", "active"}}),
+				{{"$sort", bson.D{{"createdAt", -1}}}},
+				{{"$limit", 10}},
+				{{"$group", bson.D{{"_id", "$region"}, {"n", bson.D{{"$sum", 1}}}}}},
+				{{"$project", bson.D{{"n", 1}}}},
+				match(bson.D{{"n", bson.D{{"$gt", 1}}}}),
+			},
+		},
+		{
+			name: "match after inclusion projection",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"status", "active"}}),
+				{{"$project", bson.D{{"name", 1}, {"total", bson.D{{"$sum", "$items.price"}}}}}},
+				match(bson.D{{"$or", bson.A{
+					bson.D{{"name", "a"}},
+					bson.D{{"status", "active"}},
+				}}}),
+				match(bson.D{{"$expr", bson.D{{"$gt", bson.A{"$total", "$limit.max"}}}}}),
+			},
+			want: []Warning{
+				{
+					Rule:    RuleMatchDroppedField,
+					Stage:   2,
+					Message: `$match references field "status", which is removed by the $project stage 1`,
+				},
+				{
+					Rule:    RuleMatchDroppedField,
+					Stage:   3,
+					Message: `$match references field "limit", which is removed by the $project stage 1`,
+				},
+			},
+		},
+		{
+			name: "match after exclusion and unset",
+			pipeline: bson.A{
+				match(bson.D{{"a", 1}}),
+				bson.D{{"$project", bson.D{{"secret", 0}, {"nested.field", 0}}}},
+				bson.D{{"$unset", bson.A{"tmp"}}},
+				bson.D{{"$set", bson.D{{"tmp", 1}}}},
+				match(bson.D{{"secret", 1}, {"nested.field", 1}, {"tmp", 1}}),
+			},
+			want: []Warning{{
+				Rule:    RuleMatchDroppedField,
+				Stage:   4,
+				Message: `$match references field "secret", which is removed by the $project stage 1`,
+			}},
+		},
+		{
+			name: "match after group",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"a", 1}}),
+				{{"$group", bson.D{{"_id", "$region"}, {"total", bson.D{{"$sum", "$amount"}}}}}},
+				match(bson.D{{"region", "eu"}}),
+			},
+			want: []Warning{{
+				Rule:    RuleMatchDroppedField,
+				Stage:   2,
+				Message: `$match references field "region", which is removed by the $group stage 1`,
+			}},
+		},
+		{
+			name: "match after replaceRoot",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"a", 1}}),
+				{{"$project", bson.D{{"doc", 1}}}},
+				{{"$replaceRoot", bson.D{{"newRoot", "$doc"}}}},
+				match(bson.D{{"status", "active"}}),
+			},
+		},
+		{
+			name: "late match",
+			pipeline: mongo.Pipeline{
+				{{"$addFields", bson.D{{"year", bson.D{{"$year", "$createdAt"}}}}}},
+				match(bson.D{{"status", "active"}}),
+			},
+			want: []Warning{{
+				Rule:    RuleLeadingMatch,
+				Stage:   1,
+				Message: "$match follows $addFields and cannot use an index; move it to the start of the pipeline",
+			}},
+		},
+		{
+			name: "no match",
+			pipeline: mongo.Pipeline{
+				{{"$group", bson.D{{"_id", nil}, {"n", bson.D{{"$sum", 1}}}}}},
+			},
+			want: []Warning{{
+				Rule:    RuleLeadingMatch,
+				Stage:   0,
+				Message: "pipeline does not start with $match and reads every document of the collection",
+			}},
+		},
+		{
+			name: "source stage",
+			pipeline: mongo.Pipeline{
+				{{"$search", bson.D{{"text", bson.D{{"query", "coffee"}, {"path", "title"}}}}}},
+				{{"$limit", 10}},
+			},
+		},
+		{
+			name: "sort before group",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"a", 1}}),
+				{{"$sort", bson.D{{"b", 1}}}},
+				{{"$unwind", "$items"}},
+				{{"$group", bson.D{{"_id", "$items.sku"}, {"n", bson.D{{"$sum", 1}}}}}},
+			},
+			want: []Warning{{
+				Rule:    RuleSortBeforeGroup,
+				Stage:   1,
+				Message: "$sort without $limit before the $group stage 3, which does not depend on the order of its input",
+			}},
+		},
+		{
+			name: "sort before ordered group",
+			pipeline: mongo.Pipeline{
+				match(bson.D{{"a", 1}}),
+				{{"$sort", bson.D{{"b", 1}}}},
+				{{"$group", bson.D{{"_id", "$c"}, {"first", bson.D{{"$first", "$b"}}}}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			warnings, err := Analyze(tc.pipeline)
+			require.NoError(t, err, "Analyze error")
+			assert.Equal(t, tc.want, warnings, "warnings mismatch")
+		})
+	}
+
+	t.Run("invalid pipeline", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Analyze(bson.D{{"$match", bson.D{}}})
+		assert.ErrorContains(t, err, "must be an array of stages", "error mismatch")
+		_, err = Analyze(bson.A{bson.D{{"$match", bson.D{}}, {"$limit", 1}}})
+		assert.ErrorContains(t, err, "expected a single stage", "error mismatch")
+		_, err = Analyze(nil)
+		assert.Error(t, err, "expected an error for a nil pipeline")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		w := Warning{Rule: RuleLeadingMatch, Stage: 2, Message: "message"}
+		assert.Equal(t, "stage 2: message (leading-match)", w.String(), "String mismatch")
+	})
+}
@@ -0,0 +1,264 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Stage is a stage of an aggregation pipeline. The stage builders of this package and of the
+// search package implement Stage.
+type Stage interface {
+	// Stage returns the stage as a document with a single field, e.g. {$limit: 10}.
+	Stage() bson.D
+}
+
+// validator is implemented by stages that can check their own specification.
+type validator interface {
+	validate() error
+}
+
+// Pipeline is an aggregation pipeline built from typed stages. It can be passed directly to
+// Collection.Aggregate, Database.Aggregate and Analyze, or converted to a mongo.Pipeline with
+// Render.
+type Pipeline []Stage
+
+// New returns a Pipeline of the given stages.
+func New(stages ...Stage) Pipeline {
+	return Pipeline(stages)
+}
+
+// Render returns the stages of p as documents, which is the representation of a mongo.Pipeline.
+func (p Pipeline) Render() []bson.D {
+	docs := make([]bson.D, 0, len(p))
+	for _, s := range p {
+		docs = append(docs, s.Stage())
+	}
+	return docs
+}
+
+// MarshalBSONValue marshals p as a BSON array of stage documents.
+func (p Pipeline) MarshalBSONValue() (byte, []byte, error) {
+	t, data, err := bson.MarshalValue(p.Render())
+	return byte(t), data, err
+}
+
+// facetForbiddenStages are the stages that cannot be used in the sub-pipelines of $facet.
+var facetForbiddenStages = map[string]bool{
+	"$changeStream":   true,
+	"$collStats":      true,
+	"$facet":          true,
+	"$geoNear":        true,
+	"$indexStats":     true,
+	"$merge":          true,
+	"$out":            true,
+	"$planCacheStats": true,
+	"$search":         true,
+	"$searchMeta":     true,
+	"$vectorSearch":   true,
+}
+
+// pipelineKind identifies where a pipeline is used, which determines the stages it can contain.
+type pipelineKind int
+
+const (
+	topLevelPipeline pipelineKind = iota
+	lookupPipeline
+	facetPipeline
+)
+
+// Validate checks the stages of p and their order without running the pipeline. It returns an
+// error for the first problem found, such as:
+//
+//   - a stage document without exactly one field or whose field is not a stage name;
+//   - a stage that must be the first stage, such as $geoNear or $search, after another stage;
+//   - a $out or $merge stage that is not the last stage, or that is in a sub-pipeline;
+//   - a stage that cannot be used in a $facet sub-pipeline, such as $facet or $out;
+//   - an invalid stage specification, such as a negative $limit or a $lookup without a
+//     pipeline or a localField and foreignField.
+//
+// The sub-pipelines of $lookup and $facet stages built with this package are validated as well.
+// Validate cannot check the stages that the server does not know and does not replace running the
+// pipeline in tests.
+func (p Pipeline) Validate() error {
+	return p.validate(topLevelPipeline)
+}
+
+func (p Pipeline) validate(kind pipelineKind) error {
+	for i, s := range p {
+		if s == nil {
+			return fmt.Errorf("stage %d: stage cannot be nil", i)
+		}
+		name, err := stageName(s.Stage())
+		if err != nil {
+			return fmt.Errorf("stage %d: %w", i, err)
+		}
+		if err := validateStage(p, i, name, s, kind); err != nil {
+			return fmt.Errorf("stage %d (%s): %w", i, name, err)
+		}
+	}
+	return nil
+}
+
+func validateStage(p Pipeline, i int, name string, s Stage, kind pipelineKind) error {
+	switch {
+	case kind == facetPipeline && facetForbiddenStages[name]:
+		return errors.New("stage cannot be used in a $facet sub-pipeline")
+	case sourceStages[name] && i != 0:
+		return errors.New("stage must be the first stage of the pipeline")
+	case name == "$out" || name == "$merge":
+		if kind == lookupPipeline {
+			return errors.New("stage cannot be used in a $lookup sub-pipeline")
+		}
+		if i != len(p)-1 {
+			return errors.New("stage must be the last stage of the pipeline")
+		}
+	}
+
+	if v, ok := s.(validator); ok {
+		return v.validate()
+	}
+	return nil
+}
+
+func stageName(doc bson.D) (string, error) {
+	if len(doc) != 1 {
+		return "", fmt.Errorf("expected a single stage, got %d fields", len(doc))
+	}
+	if !strings.HasPrefix(doc[0].Key, "$") {
+		return "", fmt.Errorf("stage name %q must start with '$'", doc[0].Key)
+	}
+	return doc[0].Key, nil
+}
+
+// simpleStage is a stage with a name and a body.
+type simpleStage struct {
+	name string
+	body interface{}
+	err  error
+}
+
+func (s simpleStage) Stage() bson.D {
+	return bson.D{{Key: s.name, Value: s.body}}
+}
+
+func (s simpleStage) validate() error {
+	return s.err
+}
+
+// Raw returns a Stage for a stage document, e.g. a stage that this package does not provide a
+// builder for. The document must have a single field whose name is the stage name.
+func Raw(stage bson.D) Stage {
+	return rawStage(stage)
+}
+
+type rawStage bson.D
+
+func (s rawStage) Stage() bson.D {
+	return bson.D(s)
+}
+
+// Match returns a $match stage that filters the documents by the given filters, e.g.
+// Match(Eq("status", "active"), Gte("qty", 10)). The fields of the filters are combined into a
+// single filter document if their top-level fields are distinct, or with $and otherwise.
+//
+// For more information about $match, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/match/
+func Match(filters ...Filter) Stage {
+	return simpleStage{name: "$match", body: combineFilters(filters)}
+}
+
+// Sort returns a $sort stage that sorts the documents by the fields of sort, e.g.
+// bson.D{{"createdAt", -1}}.
+func Sort(sort bson.D) Stage {
+	s := simpleStage{name: "$sort", body: sort}
+	if len(sort) == 0 {
+		s.err = errors.New("sort must have at least one field")
+	}
+	return s
+}
+
+// Limit returns a $limit stage that passes the first n documents.
+func Limit(n int64) Stage {
+	s := simpleStage{name: "$limit", body: n}
+	if n <= 0 {
+		s.err = fmt.Errorf("limit must be positive, got %d", n)
+	}
+	return s
+}
+
+// Skip returns a $skip stage that skips the first n documents.
+func Skip(n int64) Stage {
+	s := simpleStage{name: "$skip", body: n}
+	if n < 0 {
+		s.err = fmt.Errorf("skip cannot be negative, got %d", n)
+	}
+	return s
+}
+
+// Project returns a $project stage with the given specification, e.g.
+// bson.D{{"name", 1}, {"total", bson.D{{"$sum", "$items.price"}}}}.
+func Project(spec bson.D) Stage {
+	s := simpleStage{name: "$project", body: spec}
+	if len(spec) == 0 {
+		s.err = errors.New("projection must have at least one field")
+	}
+	return s
+}
+
+// Set returns a $set stage, also known as $addFields, that adds or replaces the given fields.
+func Set(fields bson.D) Stage {
+	s := simpleStage{name: "$set", body: fields}
+	if len(fields) == 0 {
+		s.err = errors.New("$set must have at least one field")
+	}
+	return s
+}
+
+// Unset returns an $unset stage that removes the given fields.
+func Unset(fields ...string) Stage {
+	s := simpleStage{name: "$unset", body: fields}
+	if len(fields) == 0 {
+		s.err = errors.New("$unset must have at least one field")
+	}
+	return s
+}
+
+// Count returns a $count stage that outputs a single document with the number of input documents
+// in field.
+func Count(field string) Stage {
+	s := simpleStage{name: "$count", body: field}
+	s.err = validateOutputField(field)
+	return s
+}
+
+// Out returns an $out stage that writes the documents to the collection coll of the same
+// database, replacing it.
+func Out(coll string) Stage {
+	s := simpleStage{name: "$out", body: coll}
+	if coll == "" {
+		s.err = errors.New("collection name cannot be empty")
+	}
+	return s
+}
+
+// validateOutputField checks the name of a field created by a stage.
+func validateOutputField(field string) error {
+	switch {
+	case field == "":
+		return errors.New("field name cannot be empty")
+	case strings.HasPrefix(field, "$"):
+		return fmt.Errorf("field name %q cannot start with '$'", field)
+	case strings.Contains(field, "."):
+		return fmt.Errorf("field name %q cannot contain '.'", field)
+	}
+	return nil
+}
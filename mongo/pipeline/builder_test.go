@@ -0,0 +1,198 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/search"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	p := New(
+		Match(Eq("status", "active"), Gte("qty", 10)),
+		Match(Gt("qty", 1), Lt("qty", 5)),
+		Match(Or(In("tag", "a", "b"), Exists("flag", true))),
+		Lookup("orders", "orders").
+			SetLet(bson.D{{"id", "$_id"}}).
+			SetPipeline(Match(Expr(bson.D{{"$eq", bson.A{"$customer", "$$id"}}})), Limit(5)),
+		Unwind("$orders").SetPreserveNullAndEmptyArrays(true),
+		Group("$region", Sum("n", 1), Avg("avgQty", "$qty")),
+		SetWindowFields(
+			Sum("running", "$n").Over(Documents("unbounded", "current")),
+			Accumulate("rank", "$rank", bson.D{}),
+		).SetPartitionBy("$state").SetSortBy(bson.D{{"n", -1}}),
+		Facet().Add("top", Sort(bson.D{{"n", -1}}), Limit(3)).Add("total", Count("count")),
+		Merge("summary").SetOn("_id").SetWhenMatched("replace"),
+	)
+
+	want := mongo.Pipeline{
+		{{"$match", bson.D{{"status", bson.D{{"$eq", "active"}}}, {"qty", bson.D{{"$gte", 10}}}}}},
+		{{"$match", bson.D{{"$and", bson.A{
+			bson.D{{"qty", bson.D{{"$gt", 1}}}},
+			bson.D{{"qty", bson.D{{"$lt", 5}}}},
+		}}}}},
+		{{"$match", bson.D{{"$or", bson.A{
+			bson.D{{"tag", bson.D{{"$in", bson.A{"a", "b"}}}}},
+			bson.D{{"flag", bson.D{{"$exists", true}}}},
+		}}}}},
+		{{"$lookup", bson.D{
+			{"from", "orders"},
+			{"let", bson.D{{"id", "$_id"}}},
+			{"pipeline", []bson.D{
+				{{"$match", bson.D{{"$expr", bson.D{{"$eq", bson.A{"$customer", "$$id"}}}}}}},
+				{{"$limit", int64(5)}},
+			}},
+			{"as", "orders"},
+		}}},
+		{{"$unwind", bson.D{{"path", "$orders"}, {"preserveNullAndEmptyArrays", true}}}},
+		{{"$group", bson.D{{"_id", "$region"}, {"n", bson.D{{"$sum", 1}}}, {"avgQty", bson.D{{"$avg", "$qty"}}}}}},
+		{{"$setWindowFields", bson.D{
+			{"partitionBy", "$state"},
+			{"sortBy", bson.D{{"n", -1}}},
+			{"output", bson.D{
+				{"running", bson.D{{"$sum", "$n"}, {"window", bson.D{{"documents", bson.A{"unbounded", "current"}}}}}},
+				{"rank", bson.D{{"$rank", bson.D{}}}},
+			}},
+		}}},
+		{{"$facet", bson.D{
+			{"top", []bson.D{{{"$sort", bson.D{{"n", -1}}}}, {{"$limit", int64(3)}}}},
+			{"total", []bson.D{{{"$count", "count"}}}},
+		}}},
+		{{"$merge", bson.D{{"into", "summary"}, {"on", []string{"_id"}}, {"whenMatched", "replace"}}}},
+	}
+
+	require.NoError(t, p.Validate(), "Validate error")
+	got, err := bson.Marshal(bson.D{{"pipeline", p}})
+	require.NoError(t, err, "Marshal error")
+	wantBytes, err := bson.Marshal(bson.D{{"pipeline", want}})
+	require.NoError(t, err, "Marshal error")
+	assert.Equal(t, bson.Raw(wantBytes).String(), bson.Raw(got).String(), "rendered pipeline mismatch")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		p       Pipeline
+		wantErr string
+	}{
+		{
+			name: "search stage interop",
+			p:    New(search.NewSearch(search.Text("coffee", "title")), Limit(10)),
+		},
+		{
+			name:    "source stage not first",
+			p:       New(Limit(1), search.NewSearch(search.Text("coffee", "title"))),
+			wantErr: "stage 1 ($search): stage must be the first stage of the pipeline",
+		},
+		{
+			name:    "out not last",
+			p:       New(Out("archive"), Limit(1)),
+			wantErr: "stage 0 ($out): stage must be the last stage of the pipeline",
+		},
+		{
+			name:    "invalid limit",
+			p:       New(Limit(0)),
+			wantErr: "stage 0 ($limit): limit must be positive, got 0",
+		},
+		{
+			name:    "raw stage without name",
+			p:       New(Raw(bson.D{{"limit", 1}})),
+			wantErr: `stage 0: stage name "limit" must start with '$'`,
+		},
+		{
+			name:    "out in facet",
+			p:       New(Facet().Add("archive", Out("archive"))),
+			wantErr: `stage 0 ($facet): facet "archive": stage 0 ($out): stage cannot be used in a $facet sub-pipeline`,
+		},
+		{
+			name:    "merge in lookup",
+			p:       New(Lookup("orders", "orders").SetPipeline(Merge("x"))),
+			wantErr: "stage 0 ($lookup): stage 0 ($merge): stage cannot be used in a $lookup sub-pipeline",
+		},
+		{
+			name: "lookup field instead of variable",
+			p: New(Lookup("orders", "orders").
+				SetLet(bson.D{{"id", "$_id"}}).
+				SetPipeline(Match(Expr(bson.D{{"$eq", bson.A{"$customer", "$id"}}})))),
+			wantErr: `stage 0 ($lookup): $lookup pipeline references field "$id" instead of variable "$$id"`,
+		},
+		{
+			name: "lookup undefined variable",
+			p: New(Lookup("orders", "orders").
+				SetLet(bson.D{{"id", "$_id"}}).
+				SetPipeline(Match(Expr(bson.D{{"$eq", bson.A{"$customer", "$$ids"}}})))),
+			wantErr: `stage 0 ($lookup): $lookup pipeline references undefined variable "$$ids" at pipeline.0.$match.$expr.$eq.1`,
+		},
+		{
+			name:    "lookup without join condition",
+			p:       New(Lookup("orders", "orders")),
+			wantErr: "stage 0 ($lookup): either localField and foreignField or a pipeline must be set",
+		},
+		{
+			name:    "group accumulator on _id",
+			p:       New(Group(nil, Sum("_id", 1))),
+			wantErr: `stage 0 ($group): duplicate output field "_id"`,
+		},
+		{
+			name:    "group accumulator with window",
+			p:       New(Group(nil, Sum("n", 1).Over(Documents(-1, 0)))),
+			wantErr: `stage 0 ($group): accumulator "n" cannot have a window in a $group stage`,
+		},
+		{
+			name:    "rank without sortBy",
+			p:       New(SetWindowFields(Accumulate("rank", "$rank", bson.D{}))),
+			wantErr: `stage 0 ($setWindowFields): operator $rank of field "rank" requires sortBy`,
+		},
+		{
+			name: "range window with two sort fields",
+			p: New(SetWindowFields(Sum("s", "$n").Over(Range(-10, 0))).
+				SetSortBy(bson.D{{"a", 1}, {"b", 1}})),
+			wantErr: `stage 0 ($setWindowFields): range window of field "s" requires sortBy with a single field`,
+		},
+		{
+			name:    "unwind without $",
+			p:       New(Unwind("items")),
+			wantErr: `stage 0 ($unwind): path "items" must be a field path starting with '$'`,
+		},
+		{
+			name:    "nil stage",
+			p:       Pipeline{nil},
+			wantErr: "stage 0: stage cannot be nil",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.p.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err, "Validate error")
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr, "Validate error mismatch")
+		})
+	}
+}
+
+func TestAnalyzeBuiltPipeline(t *testing.T) {
+	t.Parallel()
+
+	warnings, err := Analyze(New(Project(bson.D{{"name", 1}}), Match(Eq("status", "active"))))
+	require.NoError(t, err, "Analyze error")
+	require.Len(t, warnings, 2, "expected leading-match and dropped-field warnings")
+	assert.Equal(t, RuleLeadingMatch, warnings[0].Rule, "rule mismatch")
+}
@@ -0,0 +1,208 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Filter is a query filter document used by Match.
+type Filter bson.D
+
+func fieldOp(field, op string, value interface{}) Filter {
+	return Filter{{Key: field, Value: bson.D{{Key: op, Value: value}}}}
+}
+
+// Eq returns a filter that matches documents whose field equals value.
+func Eq(field string, value interface{}) Filter { return fieldOp(field, "$eq", value) }
+
+// Ne returns a filter that matches documents whose field does not equal value.
+func Ne(field string, value interface{}) Filter { return fieldOp(field, "$ne", value) }
+
+// Gt returns a filter that matches documents whose field is greater than value.
+func Gt(field string, value interface{}) Filter { return fieldOp(field, "$gt", value) }
+
+// Gte returns a filter that matches documents whose field is greater than or equal to value.
+func Gte(field string, value interface{}) Filter { return fieldOp(field, "$gte", value) }
+
+// Lt returns a filter that matches documents whose field is less than value.
+func Lt(field string, value interface{}) Filter { return fieldOp(field, "$lt", value) }
+
+// Lte returns a filter that matches documents whose field is less than or equal to value.
+func Lte(field string, value interface{}) Filter { return fieldOp(field, "$lte", value) }
+
+// In returns a filter that matches documents whose field equals any of values.
+func In(field string, values ...interface{}) Filter {
+	return fieldOp(field, "$in", bson.A(values))
+}
+
+// Nin returns a filter that matches documents whose field equals none of values.
+func Nin(field string, values ...interface{}) Filter {
+	return fieldOp(field, "$nin", bson.A(values))
+}
+
+// Exists returns a filter that matches documents that have field if exists is true, or that do not
+// have it otherwise.
+func Exists(field string, exists bool) Filter { return fieldOp(field, "$exists", exists) }
+
+// Regex returns a filter that matches documents whose field matches the regular expression pattern
+// with the given options, e.g. "i" for a case-insensitive match.
+func Regex(field, pattern, options string) Filter {
+	return Filter{{Key: field, Value: bson.Regex{Pattern: pattern, Options: options}}}
+}
+
+// Expr returns a filter that matches documents for which the aggregation expression expr is true.
+func Expr(expr interface{}) Filter {
+	return Filter{{Key: "$expr", Value: expr}}
+}
+
+// And returns a filter that matches documents that match all of filters.
+func And(filters ...Filter) Filter { return logicalOp("$and", filters) }
+
+// Or returns a filter that matches documents that match any of filters.
+func Or(filters ...Filter) Filter { return logicalOp("$or", filters) }
+
+// Nor returns a filter that matches documents that match none of filters.
+func Nor(filters ...Filter) Filter { return logicalOp("$nor", filters) }
+
+func logicalOp(op string, filters []Filter) Filter {
+	arr := make(bson.A, 0, len(filters))
+	for _, f := range filters {
+		arr = append(arr, bson.D(f))
+	}
+	return Filter{{Key: op, Value: arr}}
+}
+
+// combineFilters merges filters into a single filter document if their fields are distinct, or
+// combines them with $and otherwise.
+func combineFilters(filters []Filter) bson.D {
+	seen := make(map[string]bool)
+	merged := bson.D{}
+	for _, f := range filters {
+		for _, e := range f {
+			if seen[e.Key] {
+				return bson.D(And(filters...))
+			}
+			seen[e.Key] = true
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// Accumulator computes the field of the documents output by a $group or $setWindowFields stage,
+// e.g. Sum("total", "$qty") computes {total: {$sum: "$qty"}}.
+type Accumulator struct {
+	// Field is the output field.
+	Field string
+
+	// Operator is the accumulator or window operator, e.g. "$sum" or "$rank".
+	Operator string
+
+	// Expr is the argument of the operator.
+	Expr interface{}
+
+	// Window is the window of a $setWindowFields output. It must be nil in a $group stage.
+	Window *Window
+}
+
+// Accumulate returns an Accumulator that computes field with the operator op and its argument
+// expr. It supports operators without a dedicated constructor, e.g.
+// Accumulate("rank", "$rank", bson.D{}).
+func Accumulate(field, op string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: op, Expr: expr}
+}
+
+// Sum returns an Accumulator that computes field as the sum of expr. Use Sum(field, 1) to count
+// documents.
+func Sum(field string, expr interface{}) Accumulator { return Accumulate(field, "$sum", expr) }
+
+// Avg returns an Accumulator that computes field as the average of expr.
+func Avg(field string, expr interface{}) Accumulator { return Accumulate(field, "$avg", expr) }
+
+// Min returns an Accumulator that computes field as the minimum of expr.
+func Min(field string, expr interface{}) Accumulator { return Accumulate(field, "$min", expr) }
+
+// Max returns an Accumulator that computes field as the maximum of expr.
+func Max(field string, expr interface{}) Accumulator { return Accumulate(field, "$max", expr) }
+
+// First returns an Accumulator that computes field as expr for the first document.
+func First(field string, expr interface{}) Accumulator { return Accumulate(field, "$first", expr) }
+
+// Last returns an Accumulator that computes field as expr for the last document.
+func Last(field string, expr interface{}) Accumulator { return Accumulate(field, "$last", expr) }
+
+// Push returns an Accumulator that computes field as the array of expr for all documents.
+func Push(field string, expr interface{}) Accumulator { return Accumulate(field, "$push", expr) }
+
+// AddToSet returns an Accumulator that computes field as the array of the distinct values of expr.
+func AddToSet(field string, expr interface{}) Accumulator {
+	return Accumulate(field, "$addToSet", expr)
+}
+
+// Over returns a copy of a that is computed over the window w in a $setWindowFields stage.
+func (a Accumulator) Over(w *Window) Accumulator {
+	a.Window = w
+	return a
+}
+
+func (a Accumulator) element() bson.E {
+	body := bson.D{{Key: a.Operator, Value: a.Expr}}
+	if a.Window != nil {
+		body = append(body, bson.E{Key: "window", Value: a.Window.document()})
+	}
+	return bson.E{Key: a.Field, Value: body}
+}
+
+// Window is the window of a $setWindowFields output, which is either a range of documents relative
+// to the current document or a range of values of the sort field.
+//
+// For more information about windows, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/setWindowFields/
+type Window struct {
+	// Documents are the lower and upper bounds of a documents window: "unbounded", "current", or
+	// an integer offset from the current document.
+	Documents []interface{}
+
+	// Range are the lower and upper bounds of a range window: "unbounded", "current", or a number
+	// added to the value of the sort field of the current document.
+	Range []interface{}
+
+	// Unit is the unit of the bounds of a range window on a date sort field, e.g. "day".
+	Unit string
+}
+
+// Documents returns a documents window from lower to upper, e.g. Documents("unbounded",
+// "current") for a running total.
+func Documents(lower, upper interface{}) *Window {
+	return &Window{Documents: []interface{}{lower, upper}}
+}
+
+// Range returns a range window from lower to upper.
+func Range(lower, upper interface{}) *Window {
+	return &Window{Range: []interface{}{lower, upper}}
+}
+
+// SetUnit sets the unit of the bounds of a range window on a date sort field, e.g. "day".
+func (w *Window) SetUnit(unit string) *Window {
+	w.Unit = unit
+	return w
+}
+
+func (w *Window) document() bson.D {
+	doc := bson.D{}
+	if w.Documents != nil {
+		doc = append(doc, bson.E{Key: "documents", Value: bson.A(w.Documents)})
+	}
+	if w.Range != nil {
+		doc = append(doc, bson.E{Key: "range", Value: bson.A(w.Range)})
+	}
+	if w.Unit != "" {
+		doc = append(doc, bson.E{Key: "unit", Value: w.Unit})
+	}
+	return doc
+}
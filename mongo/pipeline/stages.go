@@ -0,0 +1,421 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/lookuputil"
+)
+
+// GroupStage builds a $group stage.
+//
+// For more information about $group, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/group/
+type GroupStage struct {
+	// ID is the group key expression, e.g. "$region". A nil ID groups all documents together.
+	ID           interface{}
+	Accumulators []Accumulator
+}
+
+// Group returns a $group stage that groups the documents by the expression id and computes the
+// given accumulators for each group, e.g. Group("$region", Sum("n", 1), Avg("avgQty", "$qty")).
+func Group(id interface{}, accumulators ...Accumulator) *GroupStage {
+	return &GroupStage{ID: id, Accumulators: accumulators}
+}
+
+// Stage returns the $group stage.
+func (g *GroupStage) Stage() bson.D {
+	body := bson.D{{Key: "_id", Value: g.ID}}
+	for _, a := range g.Accumulators {
+		body = append(body, a.element())
+	}
+	return bson.D{{Key: "$group", Value: body}}
+}
+
+func (g *GroupStage) validate() error {
+	seen := map[string]bool{"_id": true}
+	for _, a := range g.Accumulators {
+		if err := validateAccumulator(a, seen); err != nil {
+			return err
+		}
+		if a.Window != nil {
+			return fmt.Errorf("accumulator %q cannot have a window in a $group stage", a.Field)
+		}
+	}
+	return nil
+}
+
+func validateAccumulator(a Accumulator, seen map[string]bool) error {
+	if err := validateOutputField(a.Field); err != nil {
+		return err
+	}
+	if seen[a.Field] {
+		return fmt.Errorf("duplicate output field %q", a.Field)
+	}
+	seen[a.Field] = true
+	if !strings.HasPrefix(a.Operator, "$") {
+		return fmt.Errorf("operator %q of field %q must start with '$'", a.Operator, a.Field)
+	}
+	return nil
+}
+
+// LookupStage builds a $lookup stage, which joins the documents of another collection of the same
+// database by equality of a local and a foreign field, by a sub-pipeline run for each document, or
+// by both.
+//
+// For more information about $lookup, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/lookup/
+type LookupStage struct {
+	From         string
+	As           string
+	LocalField   string
+	ForeignField string
+	Let          bson.D
+	Pipeline     Pipeline
+}
+
+// Lookup returns a $lookup stage that joins the documents of the collection from into the array
+// field as.
+func Lookup(from, as string) *LookupStage {
+	return &LookupStage{From: from, As: as}
+}
+
+// SetOn sets the local and foreign fields whose values must be equal for documents to be joined.
+func (l *LookupStage) SetOn(localField, foreignField string) *LookupStage {
+	l.LocalField = localField
+	l.ForeignField = foreignField
+	return l
+}
+
+// SetLet sets the variables that the sub-pipeline can reference as "$$name", e.g.
+// bson.D{{"orderID", "$_id"}}. The pipeline must reference each variable, and only as "$$name" and
+// inside $expr in $match stages; see mongo.LookupBuilder.
+func (l *LookupStage) SetLet(let bson.D) *LookupStage {
+	l.Let = let
+	return l
+}
+
+// SetPipeline sets the sub-pipeline run on the joined collection.
+func (l *LookupStage) SetPipeline(stages ...Stage) *LookupStage {
+	l.Pipeline = stages
+	return l
+}
+
+// Stage returns the $lookup stage.
+func (l *LookupStage) Stage() bson.D {
+	body := bson.D{{Key: "from", Value: l.From}}
+	if l.LocalField != "" || l.ForeignField != "" {
+		body = append(body,
+			bson.E{Key: "localField", Value: l.LocalField},
+			bson.E{Key: "foreignField", Value: l.ForeignField})
+	}
+	if l.Let != nil {
+		body = append(body, bson.E{Key: "let", Value: l.Let})
+	}
+	if l.Pipeline != nil {
+		body = append(body, bson.E{Key: "pipeline", Value: l.Pipeline.Render()})
+	}
+	body = append(body, bson.E{Key: "as", Value: l.As})
+	return bson.D{{Key: "$lookup", Value: body}}
+}
+
+func (l *LookupStage) validate() error {
+	switch {
+	case l.From == "":
+		return errors.New("from cannot be empty")
+	case l.As == "":
+		return errors.New("as cannot be empty")
+	case (l.LocalField == "") != (l.ForeignField == ""):
+		return errors.New("localField and foreignField must be set together")
+	case l.LocalField == "" && l.Pipeline == nil:
+		return errors.New("either localField and foreignField or a pipeline must be set")
+	case l.Let != nil && l.Pipeline == nil:
+		return errors.New("let requires a pipeline")
+	}
+	if err := l.Pipeline.validate(lookupPipeline); err != nil {
+		return err
+	}
+	if err := lookuputil.ValidateLet(l.Let); err != nil {
+		return err
+	}
+	if l.Pipeline == nil {
+		return nil
+	}
+	stages := make([]interface{}, 0, len(l.Pipeline))
+	for _, stage := range l.Pipeline.Render() {
+		stages = append(stages, stage)
+	}
+	return lookuputil.ValidateReferences(l.Let, stages)
+}
+
+// SetWindowFieldsStage builds a $setWindowFields stage.
+//
+// For more information about $setWindowFields, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/setWindowFields/
+type SetWindowFieldsStage struct {
+	PartitionBy interface{}
+	SortBy      bson.D
+	Output      []Accumulator
+}
+
+// SetWindowFields returns a $setWindowFields stage that computes the given outputs, e.g.
+// SetWindowFields(Sum("runningQty", "$qty").Over(Documents("unbounded", "current"))).
+func SetWindowFields(output ...Accumulator) *SetWindowFieldsStage {
+	return &SetWindowFieldsStage{Output: output}
+}
+
+// SetPartitionBy sets the expression that partitions the documents, e.g. "$state".
+func (s *SetWindowFieldsStage) SetPartitionBy(expr interface{}) *SetWindowFieldsStage {
+	s.PartitionBy = expr
+	return s
+}
+
+// SetSortBy sets the fields that sort the documents of each partition.
+func (s *SetWindowFieldsStage) SetSortBy(sort bson.D) *SetWindowFieldsStage {
+	s.SortBy = sort
+	return s
+}
+
+// Stage returns the $setWindowFields stage.
+func (s *SetWindowFieldsStage) Stage() bson.D {
+	body := bson.D{}
+	if s.PartitionBy != nil {
+		body = append(body, bson.E{Key: "partitionBy", Value: s.PartitionBy})
+	}
+	if s.SortBy != nil {
+		body = append(body, bson.E{Key: "sortBy", Value: s.SortBy})
+	}
+	output := bson.D{}
+	for _, a := range s.Output {
+		output = append(output, a.element())
+	}
+	body = append(body, bson.E{Key: "output", Value: output})
+	return bson.D{{Key: "$setWindowFields", Value: body}}
+}
+
+// sortedWindowOperators are the window operators that require the documents to be sorted.
+var sortedWindowOperators = map[string]bool{
+	"$denseRank":      true,
+	"$derivative":     true,
+	"$documentNumber": true,
+	"$expMovingAvg":   true,
+	"$integral":       true,
+	"$linearFill":     true,
+	"$rank":           true,
+	"$shift":          true,
+}
+
+func (s *SetWindowFieldsStage) validate() error {
+	if len(s.Output) == 0 {
+		return errors.New("output must have at least one field")
+	}
+	seen := make(map[string]bool)
+	for _, a := range s.Output {
+		if err := validateAccumulator(a, seen); err != nil {
+			return err
+		}
+		if sortedWindowOperators[a.Operator] && len(s.SortBy) == 0 {
+			return fmt.Errorf("operator %s of field %q requires sortBy", a.Operator, a.Field)
+		}
+		if a.Window == nil {
+			continue
+		}
+		if (a.Window.Documents == nil) == (a.Window.Range == nil) {
+			return fmt.Errorf("window of field %q must have either documents or range bounds", a.Field)
+		}
+		if len(a.Window.Documents) != 0 && len(a.Window.Documents) != 2 ||
+			len(a.Window.Range) != 0 && len(a.Window.Range) != 2 {
+			return fmt.Errorf("window of field %q must have a lower and an upper bound", a.Field)
+		}
+		if a.Window.Documents != nil && len(s.SortBy) == 0 && !isUnbounded(a.Window.Documents) {
+			return fmt.Errorf("documents window of field %q requires sortBy", a.Field)
+		}
+		if a.Window.Range != nil && len(s.SortBy) != 1 {
+			return fmt.Errorf("range window of field %q requires sortBy with a single field", a.Field)
+		}
+	}
+	return nil
+}
+
+func isUnbounded(bounds []interface{}) bool {
+	for _, b := range bounds {
+		if b != "unbounded" {
+			return false
+		}
+	}
+	return true
+}
+
+// FacetStage builds a $facet stage, which runs several sub-pipelines on the same input documents
+// and outputs a single document with the results of each sub-pipeline in a field.
+//
+// For more information about $facet, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/facet/
+type FacetStage struct {
+	Facets []NamedPipeline
+}
+
+// NamedPipeline is a sub-pipeline of a $facet stage and the name of its output field.
+type NamedPipeline struct {
+	Name     string
+	Pipeline Pipeline
+}
+
+// Facet returns an empty $facet stage. Add its sub-pipelines with Add.
+func Facet() *FacetStage {
+	return &FacetStage{}
+}
+
+// Add adds a sub-pipeline whose results are output in the field name.
+func (f *FacetStage) Add(name string, stages ...Stage) *FacetStage {
+	f.Facets = append(f.Facets, NamedPipeline{Name: name, Pipeline: stages})
+	return f
+}
+
+// Stage returns the $facet stage.
+func (f *FacetStage) Stage() bson.D {
+	body := bson.D{}
+	for _, np := range f.Facets {
+		body = append(body, bson.E{Key: np.Name, Value: np.Pipeline.Render()})
+	}
+	return bson.D{{Key: "$facet", Value: body}}
+}
+
+func (f *FacetStage) validate() error {
+	if len(f.Facets) == 0 {
+		return errors.New("$facet must have at least one sub-pipeline")
+	}
+	seen := make(map[string]bool)
+	for _, np := range f.Facets {
+		if err := validateOutputField(np.Name); err != nil {
+			return err
+		}
+		if seen[np.Name] {
+			return fmt.Errorf("duplicate output field %q", np.Name)
+		}
+		seen[np.Name] = true
+		if err := np.Pipeline.validate(facetPipeline); err != nil {
+			return fmt.Errorf("facet %q: %w", np.Name, err)
+		}
+	}
+	return nil
+}
+
+// UnwindStage builds an $unwind stage.
+//
+// For more information about $unwind, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/unwind/
+type UnwindStage struct {
+	Path                       string
+	IncludeArrayIndex          string
+	PreserveNullAndEmptyArrays *bool
+}
+
+// Unwind returns an $unwind stage that outputs a document for each element of the array field at
+// path, e.g. "$items".
+func Unwind(path string) *UnwindStage {
+	return &UnwindStage{Path: path}
+}
+
+// SetIncludeArrayIndex sets the field that holds the array index of the element.
+func (u *UnwindStage) SetIncludeArrayIndex(field string) *UnwindStage {
+	u.IncludeArrayIndex = field
+	return u
+}
+
+// SetPreserveNullAndEmptyArrays specifies whether to output the documents whose path is missing,
+// null, or an empty array.
+func (u *UnwindStage) SetPreserveNullAndEmptyArrays(b bool) *UnwindStage {
+	u.PreserveNullAndEmptyArrays = &b
+	return u
+}
+
+// Stage returns the $unwind stage.
+func (u *UnwindStage) Stage() bson.D {
+	if u.IncludeArrayIndex == "" && u.PreserveNullAndEmptyArrays == nil {
+		return bson.D{{Key: "$unwind", Value: u.Path}}
+	}
+	body := bson.D{{Key: "path", Value: u.Path}}
+	if u.IncludeArrayIndex != "" {
+		body = append(body, bson.E{Key: "includeArrayIndex", Value: u.IncludeArrayIndex})
+	}
+	if u.PreserveNullAndEmptyArrays != nil {
+		body = append(body, bson.E{Key: "preserveNullAndEmptyArrays", Value: *u.PreserveNullAndEmptyArrays})
+	}
+	return bson.D{{Key: "$unwind", Value: body}}
+}
+
+func (u *UnwindStage) validate() error {
+	if !strings.HasPrefix(u.Path, "$") || len(u.Path) == 1 {
+		return fmt.Errorf("path %q must be a field path starting with '$'", u.Path)
+	}
+	return nil
+}
+
+// MergeStage builds a $merge stage.
+//
+// For more information about $merge, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/merge/
+type MergeStage struct {
+	Into           string
+	On             []string
+	WhenMatched    string
+	WhenNotMatched string
+}
+
+// Merge returns a $merge stage that writes the documents to the collection into of the same
+// database.
+func Merge(into string) *MergeStage {
+	return &MergeStage{Into: into}
+}
+
+// SetOn sets the fields that identify the existing document that an output document matches. The
+// fields must have a unique index in the output collection.
+func (m *MergeStage) SetOn(fields ...string) *MergeStage {
+	m.On = fields
+	return m
+}
+
+// SetWhenMatched sets the action for an output document that matches an existing document:
+// "replace", "keepExisting", "merge", or "fail".
+func (m *MergeStage) SetWhenMatched(action string) *MergeStage {
+	m.WhenMatched = action
+	return m
+}
+
+// SetWhenNotMatched sets the action for an output document that matches no existing document:
+// "insert", "discard", or "fail".
+func (m *MergeStage) SetWhenNotMatched(action string) *MergeStage {
+	m.WhenNotMatched = action
+	return m
+}
+
+// Stage returns the $merge stage.
+func (m *MergeStage) Stage() bson.D {
+	body := bson.D{{Key: "into", Value: m.Into}}
+	if len(m.On) != 0 {
+		body = append(body, bson.E{Key: "on", Value: m.On})
+	}
+	if m.WhenMatched != "" {
+		body = append(body, bson.E{Key: "whenMatched", Value: m.WhenMatched})
+	}
+	if m.WhenNotMatched != "" {
+		body = append(body, bson.E{Key: "whenNotMatched", Value: m.WhenNotMatched})
+	}
+	return bson.D{{Key: "$merge", Value: body}}
+}
+
+func (m *MergeStage) validate() error {
+	if m.Into == "" {
+		return errors.New("into cannot be empty")
+	}
+	return nil
+}
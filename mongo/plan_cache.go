@@ -0,0 +1,169 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// PlanCacheQueryShape is the query that created a plan cache entry.
+type PlanCacheQueryShape struct {
+	Query      bson.Raw `bson:"query"`
+	Sort       bson.Raw `bson:"sort"`
+	Projection bson.Raw `bson:"projection"`
+	Collation  bson.Raw `bson:"collation"`
+}
+
+// PlanCacheEntry is an entry of the query plan cache of a collection, as reported by the
+// $planCacheStats aggregation stage.
+type PlanCacheEntry struct {
+	// Version is the plan cache entry format: 1 for the classic execution engine and 2 for the
+	// slot-based execution engine.
+	Version string `bson:"version"`
+
+	// QueryHash identifies the query shape of the entry.
+	QueryHash string `bson:"queryHash"`
+
+	// PlanCacheShapeHash replaces QueryHash on MongoDB 8.0 and later.
+	PlanCacheShapeHash string `bson:"planCacheShapeHash"`
+
+	// PlanCacheKey identifies the entry: it includes the query shape and the indexes available to
+	// the query.
+	PlanCacheKey string `bson:"planCacheKey"`
+
+	// IsActive is whether the entry is used to plan queries. Inactive entries are recorded until
+	// the plan proves itself.
+	IsActive bool `bson:"isActive"`
+
+	// Works is the amount of work done by the plan during the trial period that selected it.
+	Works int64 `bson:"works"`
+
+	// CreatedFromQuery is the query that created the entry.
+	CreatedFromQuery PlanCacheQueryShape `bson:"createdFromQuery"`
+
+	// CachedPlan is the cached plan.
+	CachedPlan bson.Raw `bson:"cachedPlan"`
+
+	// TimeOfCreation is when the entry was created.
+	TimeOfCreation time.Time `bson:"timeOfCreation"`
+
+	// EstimatedSizeBytes is the estimated size of the entry.
+	EstimatedSizeBytes int64 `bson:"estimatedSizeBytes"`
+
+	// Host is the address of the mongod that reported the entry.
+	Host string `bson:"host"`
+
+	// Shard is the shard of Host. It is only set against sharded clusters.
+	Shard string `bson:"shard"`
+}
+
+// PlanCacheList runs the $planCacheStats aggregation stage and returns the entries of the query
+// plan cache of the collection on the member selected by the read preference of the collection,
+// or on one member of each shard for sharded collections.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.PlanCacheListOptions documentation).
+//
+// For more information about the stage, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/planCacheStats/.
+func (coll *Collection) PlanCacheList(
+	ctx context.Context,
+	opts ...options.Lister[options.PlanCacheListOptions],
+) ([]PlanCacheEntry, error) {
+	pipeline, err := planCacheListPipeline(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []PlanCacheEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func planCacheListPipeline(opts ...options.Lister[options.PlanCacheListOptions]) (bson.A, error) {
+	args, err := mongoutil.NewOptions[options.PlanCacheListOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	stats := bson.D{}
+	if args.AllHosts != nil {
+		stats = append(stats, bson.E{"allHosts", *args.AllHosts})
+	}
+	pipeline := bson.A{bson.D{{"$planCacheStats", stats}}}
+
+	match := bson.D{}
+	if args.PlanCacheKey != nil {
+		match = append(match, bson.E{"planCacheKey", *args.PlanCacheKey})
+	}
+	if args.QueryHash != nil {
+		match = append(match, bson.E{"$or", bson.A{
+			bson.D{{"queryHash", *args.QueryHash}},
+			bson.D{{"planCacheShapeHash", *args.QueryHash}},
+		}})
+	}
+	if len(match) > 0 {
+		pipeline = append(pipeline, bson.D{{"$match", match}})
+	}
+	return pipeline, nil
+}
+
+// PlanCacheClear runs the planCacheClear command to remove entries from the query plan cache of
+// the collection, forcing the affected queries to be planned again. By default, all entries are
+// removed; the opts parameter can be used to only remove the entries of a query shape (see the
+// options.PlanCacheClearOptions documentation). The command only affects the member it runs on,
+// which is selected by the read preference of the collection.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/planCacheClear/.
+func (coll *Collection) PlanCacheClear(
+	ctx context.Context,
+	opts ...options.Lister[options.PlanCacheClearOptions],
+) error {
+	cmd, err := planCacheClearCommand(coll.name, opts...)
+	if err != nil {
+		return err
+	}
+
+	rcOpts := options.RunCmd()
+	if coll.readPreference != nil {
+		rcOpts.SetReadPreference(coll.readPreference)
+	}
+	return coll.db.RunCommand(ctx, cmd, rcOpts).Err()
+}
+
+func planCacheClearCommand(collName string, opts ...options.Lister[options.PlanCacheClearOptions]) (bson.D, error) {
+	args, err := mongoutil.NewOptions[options.PlanCacheClearOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	cmd := bson.D{{"planCacheClear", collName}}
+	if args.Query != nil {
+		cmd = append(cmd, bson.E{"query", args.Query})
+		if args.Sort != nil {
+			cmd = append(cmd, bson.E{"sort", args.Sort})
+		}
+		if args.Projection != nil {
+			cmd = append(cmd, bson.E{"projection", args.Projection})
+		}
+	}
+	return cmd, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestPlanCache(t *testing.T) {
+	t.Run("list pipeline", func(t *testing.T) {
+		pipeline, err := planCacheListPipeline()
+		require.NoError(t, err, "planCacheListPipeline error: %v", err)
+		assert.Equal(t, bson.A{bson.D{{"$planCacheStats", bson.D{}}}}, pipeline, "expected only $planCacheStats")
+
+		pipeline, err = planCacheListPipeline(options.PlanCacheList().SetAllHosts(true).SetPlanCacheKey("ABCD"))
+		require.NoError(t, err, "planCacheListPipeline error: %v", err)
+		want := bson.A{
+			bson.D{{"$planCacheStats", bson.D{{"allHosts", true}}}},
+			bson.D{{"$match", bson.D{{"planCacheKey", "ABCD"}}}},
+		}
+		assert.Equal(t, want, pipeline, "expected filtered pipeline")
+	})
+	t.Run("clear command", func(t *testing.T) {
+		cmd, err := planCacheClearCommand("orders")
+		require.NoError(t, err, "planCacheClearCommand error: %v", err)
+		assert.Equal(t, bson.D{{"planCacheClear", "orders"}}, cmd, "expected command clearing all entries")
+
+		cmd, err = planCacheClearCommand("orders", options.PlanCacheClear().
+			SetQuery(bson.D{{"status", "A"}}).
+			SetSort(bson.D{{"ts", -1}}))
+		require.NoError(t, err, "planCacheClearCommand error: %v", err)
+		want := bson.D{
+			{"planCacheClear", "orders"},
+			{"query", bson.D{{"status", "A"}}},
+			{"sort", bson.D{{"ts", -1}}},
+		}
+		assert.Equal(t, want, cmd, "expected command clearing a query shape")
+	})
+	t.Run("decode entry", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{
+			{"version", "1"},
+			{"queryHash", "5F5FC979"},
+			{"planCacheKey", "9A3C1B13"},
+			{"isActive", true},
+			{"works", int64(3)},
+			{"createdFromQuery", bson.D{{"query", bson.D{{"status", "A"}}}, {"sort", bson.D{}}}},
+			{"host", "db1:27017"},
+		})
+		require.NoError(t, err, "Marshal error: %v", err)
+
+		var entry PlanCacheEntry
+		err = bson.Unmarshal(doc, &entry)
+		require.NoError(t, err, "Unmarshal error: %v", err)
+		assert.Equal(t, "9A3C1B13", entry.PlanCacheKey, "expected plan cache key")
+		assert.True(t, entry.IsActive, "expected active entry")
+		assert.Equal(t, "A", entry.CreatedFromQuery.Query.Lookup("status").StringValue(), "expected query shape")
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// shardZonesCacheTTL is how long the sharding metadata read by AnalyzeQueryTargets is reused before
+// it is read again.
+const shardZonesCacheTTL = time.Minute
+
+// QueryTargets describes how a query filter is routed in a sharded cluster.
+type QueryTargets struct {
+	// Namespace is the namespace of the collection, in "database.collection" form.
+	Namespace string
+
+	// Sharded is true if the collection is sharded. Queries on unsharded collections are always
+	// targeted.
+	Sharded bool
+
+	// Targeted is true if the filter has equality conditions on the shard key, or on a prefix of
+	// it, so that it is routed only to the shards that own matching chunks. A filter that is not
+	// targeted is a scatter-gather query sent to every shard that owns chunks of the collection.
+	Targeted bool
+
+	// ShardKeyFields are the shard key fields that have equality conditions in the filter.
+	ShardKeyFields []string
+
+	// Shards are the sorted names of the shards the filter is routed to. It is empty if the
+	// collection is not sharded.
+	Shards []string
+}
+
+// AnalyzeQueryTargets reports whether a query with the given filter on coll would be targeted to
+// specific shards or broadcast to all shards as a scatter-gather query. It can be used in tests or
+// CI checks to flag hot-path queries that do not include the shard key.
+//
+// The analysis uses the sharding metadata of the collection read from the config database, which is
+// cached by the Client for one minute, so it does not reflect chunk migrations that happened since.
+// See Client.ShardZones for the privileges required.
+func AnalyzeQueryTargets(ctx context.Context, coll *Collection, filter interface{}) (QueryTargets, error) {
+	ns := coll.db.name + "." + coll.name
+	targets := QueryTargets{Namespace: ns, Targeted: true}
+
+	doc, err := marshal(filter, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return targets, err
+	}
+
+	sz, err := coll.client.shardZones.get(ctx, coll.client, ns)
+	if err != nil || sz == nil {
+		return targets, err
+	}
+
+	shards, prefix := sz.targets(bson.Raw(doc))
+	targets.Sharded = true
+	targets.Targeted = prefix > 0
+	targets.ShardKeyFields = sz.fields[:prefix]
+	targets.Shards = shards
+	return targets, nil
+}
+
+// shardZonesCache caches the ShardZones of collections by namespace. A nil ShardZones is cached for
+// collections that are not sharded.
+type shardZonesCache struct {
+	mu      sync.Mutex
+	entries map[string]shardZonesEntry
+}
+
+type shardZonesEntry struct {
+	zones   *ShardZones
+	fetched time.Time
+}
+
+func (c *shardZonesCache) get(ctx context.Context, client *Client, ns string) (*ShardZones, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[ns]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetched) < shardZonesCacheTTL {
+		return entry.zones, nil
+	}
+
+	sz, err := client.ShardZones(ctx, ns)
+	if err != nil && !errors.Is(err, ErrCollectionNotSharded) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]shardZonesEntry)
+	}
+	c.entries[ns] = shardZonesEntry{zones: sz, fetched: time.Now()}
+	c.mu.Unlock()
+	return sz, nil
+}
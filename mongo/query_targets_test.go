@@ -0,0 +1,59 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestAnalyzeQueryTargets(t *testing.T) {
+	raw := func(d bson.D) bson.Raw {
+		b, err := bson.Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	chunks := []ShardChunk{
+		{Min: raw(bson.D{{"tenant", bson.MinKey{}}, {"ts", bson.MinKey{}}}), Max: raw(bson.D{{"tenant", "m"}, {"ts", bson.MinKey{}}}), Shard: "shard0"},
+		{Min: raw(bson.D{{"tenant", "m"}, {"ts", bson.MinKey{}}}), Max: raw(bson.D{{"tenant", bson.MaxKey{}}, {"ts", bson.MaxKey{}}}), Shard: "shard1"},
+	}
+	sz, err := newShardZones(testDbName+".events", raw(bson.D{{"tenant", 1}, {"ts", 1}}), chunks, nil)
+	require.NoError(t, err, "newShardZones error: %v", err)
+
+	coll := setupColl("events")
+	coll.client.shardZones.entries = map[string]shardZonesEntry{
+		testDbName + ".events":    {zones: sz, fetched: time.Now()},
+		testDbName + ".unsharded": {fetched: time.Now()},
+	}
+
+	t.Run("targeted", func(t *testing.T) {
+		targets, err := AnalyzeQueryTargets(context.Background(), coll, bson.D{{"tenant", "acme"}, {"ts", bson.D{{"$gt", 1}}}})
+		require.NoError(t, err, "AnalyzeQueryTargets error: %v", err)
+		assert.True(t, targets.Sharded, "expected sharded collection")
+		assert.True(t, targets.Targeted, "expected targeted query")
+		assert.Equal(t, []string{"tenant"}, targets.ShardKeyFields, "expected shard key fields")
+		assert.Equal(t, []string{"shard0"}, targets.Shards, "expected targeted shards")
+	})
+	t.Run("scatter-gather", func(t *testing.T) {
+		targets, err := AnalyzeQueryTargets(context.Background(), coll, bson.D{{"ts", 1}})
+		require.NoError(t, err, "AnalyzeQueryTargets error: %v", err)
+		assert.False(t, targets.Targeted, "expected scatter-gather query")
+		assert.Equal(t, 0, len(targets.ShardKeyFields), "expected no shard key fields")
+		assert.Equal(t, []string{"shard0", "shard1"}, targets.Shards, "expected all shards")
+	})
+	t.Run("unsharded", func(t *testing.T) {
+		targets, err := AnalyzeQueryTargets(context.Background(), coll.db.Collection("unsharded"), bson.D{})
+		require.NoError(t, err, "AnalyzeQueryTargets error: %v", err)
+		assert.False(t, targets.Sharded, "expected unsharded collection")
+		assert.True(t, targets.Targeted, "expected unsharded collection to be targeted")
+	})
+}
@@ -0,0 +1,441 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package queue implements an at-least-once work queue on top of a collection.
+//
+// Each message is a document of the queue collection. Receiving a message leases it to the
+// receiver for the visibility timeout of the Queue by atomically moving its visibility time into
+// the future with a findAndModify command, so that no other receiver gets it until the lease
+// expires. A receiver that processes a message acknowledges it with Ack, which deletes it. If the
+// receiver fails or does not acknowledge the message in time, the message becomes visible again
+// and is delivered to another receiver:
+//
+//	q := queue.New(db.Collection("jobs")).SetVisibilityTimeout(time.Minute)
+//	if err := q.Setup(ctx); err != nil {
+//		return err
+//	}
+//	if _, err := q.Enqueue(ctx, bson.D{{"task", "resize"}, {"image", id}}); err != nil {
+//		return err
+//	}
+//	...
+//	msg, err := q.Receive(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	var job Job
+//	if err := msg.Decode(&job); err != nil {
+//		return err
+//	}
+//	if err := process(job); err != nil {
+//		return msg.Nack(ctx, 10*time.Second)
+//	}
+//	return msg.Ack(ctx)
+//
+// Because a message can be delivered more than once, e.g. if its processing takes longer than the
+// visibility timeout or an acknowledgement is lost, processing must be idempotent. Messages that
+// are received more than the maximum number of attempts are moved to a dead-letter collection.
+//
+// The queue writes with the "majority" write concern, so that a lease or an acknowledgement is not
+// rolled back by a replica set election, which would deliver a message again. Leases are computed
+// from the clock of the receivers, which should be synchronized.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+const (
+	// DefaultVisibilityTimeout is the default duration for which a received message is invisible to
+	// other receivers.
+	DefaultVisibilityTimeout = 30 * time.Second
+
+	// DefaultPollInterval is the default interval at which Receive checks for visible messages
+	// when the queue is empty.
+	DefaultPollInterval = time.Second
+
+	// DefaultMaxAttempts is the default number of times a message is received before it is moved
+	// to the dead-letter collection.
+	DefaultMaxAttempts = 5
+
+	// DefaultNotificationsSize is the default size in bytes of the capped notifications collection
+	// created by Setup.
+	DefaultNotificationsSize = 1024 * 1024
+)
+
+// Fields of the message documents.
+const (
+	fieldPayload    = "payload"
+	fieldVisibleAt  = "visibleAt"
+	fieldAttempts   = "attempts"
+	fieldReceipt    = "receipt"
+	fieldEnqueuedAt = "enqueuedAt"
+	fieldError      = "error"
+)
+
+// errorCodeNamespaceExists is the code of the error returned when creating a collection that
+// already exists.
+const errorCodeNamespaceExists = 48
+
+// ErrEmpty is returned by TryReceive if no message is visible.
+var ErrEmpty = errors.New("queue is empty")
+
+// ErrLeaseExpired is returned by the methods of a Message if its lease has expired and it may
+// have been received again, or it has been acknowledged or moved to the dead-letter collection.
+var ErrLeaseExpired = errors.New("message lease expired")
+
+// Queue is an at-least-once work queue stored in a collection.
+type Queue struct {
+	coll              *mongo.Collection
+	deadLetter        *mongo.Collection
+	notifications     *mongo.Collection
+	visibilityTimeout time.Duration
+	pollInterval      time.Duration
+	maxAttempts       int
+	now               func() time.Time
+}
+
+// New creates a Queue that stores its messages in coll. The Queue uses a copy of coll with the
+// "majority" write concern.
+func New(coll *mongo.Collection) *Queue {
+	return &Queue{
+		coll:              majority(coll),
+		visibilityTimeout: DefaultVisibilityTimeout,
+		pollInterval:      DefaultPollInterval,
+		maxAttempts:       DefaultMaxAttempts,
+		now:               time.Now,
+	}
+}
+
+func majority(coll *mongo.Collection) *mongo.Collection {
+	return coll.Clone(options.Collection().SetWriteConcern(writeconcern.Majority()))
+}
+
+// SetVisibilityTimeout sets the duration for which a received message is invisible to other
+// receivers. It should be longer than the time needed to process a message, or the lease should
+// be extended with Message.Extend. The default value is DefaultVisibilityTimeout.
+func (q *Queue) SetVisibilityTimeout(d time.Duration) *Queue {
+	q.visibilityTimeout = d
+	return q
+}
+
+// SetPollInterval sets the interval at which Receive checks for visible messages when the queue
+// is empty. With a notifications collection, it is the maximum time a receiver waits for a
+// notification. The default value is DefaultPollInterval.
+func (q *Queue) SetPollInterval(d time.Duration) *Queue {
+	q.pollInterval = d
+	return q
+}
+
+// SetMaxAttempts sets the number of times a message is received before it is moved to the
+// dead-letter collection instead of being delivered again. If this is 0, messages are delivered
+// until they are acknowledged. The default value is DefaultMaxAttempts.
+func (q *Queue) SetMaxAttempts(n int) *Queue {
+	q.maxAttempts = n
+	return q
+}
+
+// SetDeadLetter sets the collection that messages are moved to when they have been received the
+// maximum number of times. If it is nil, the messages are deleted. The default value is nil.
+func (q *Queue) SetDeadLetter(coll *mongo.Collection) *Queue {
+	q.deadLetter = nil
+	if coll != nil {
+		q.deadLetter = majority(coll)
+	}
+	return q
+}
+
+// SetNotifications sets a capped collection with which Enqueue wakes up the receivers waiting
+// in Receive through a tailable cursor, instead of letting them poll the queue collection. Setup
+// creates the capped collection. The default value is nil.
+func (q *Queue) SetNotifications(capped *mongo.Collection) *Queue {
+	q.notifications = capped
+	return q
+}
+
+// Setup creates the index used to receive messages and, if set, the capped notifications
+// collection. It is safe to call Setup again for an existing queue.
+func (q *Queue) Setup(ctx context.Context) error {
+	_, err := q.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: fieldVisibleAt, Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating queue index: %w", err)
+	}
+	if q.notifications == nil {
+		return nil
+	}
+
+	err = q.notifications.Database().CreateCollection(ctx, q.notifications.Name(),
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(DefaultNotificationsSize))
+	var ce mongo.CommandError
+	if errors.As(err, &ce) && ce.Code == errorCodeNamespaceExists {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error creating notifications collection: %w", err)
+	}
+
+	// A tailable cursor on an empty capped collection is closed immediately, so insert a first
+	// notification that receivers can tail from.
+	if _, err := q.notifications.InsertOne(ctx, bson.D{{Key: "_id", Value: bson.NewObjectID()}}); err != nil {
+		return fmt.Errorf("error initializing notifications collection: %w", err)
+	}
+	return nil
+}
+
+// Enqueue adds a message with the given payload to the queue and returns its ID. The message is
+// visible immediately.
+func (q *Queue) Enqueue(ctx context.Context, payload interface{}) (bson.ObjectID, error) {
+	return q.EnqueueAfter(ctx, payload, 0)
+}
+
+// EnqueueAfter adds a message with the given payload to the queue that becomes visible after
+// delay, and returns its ID.
+func (q *Queue) EnqueueAfter(ctx context.Context, payload interface{}, delay time.Duration) (bson.ObjectID, error) {
+	now := q.now()
+	id := bson.NewObjectID()
+	_, err := q.coll.InsertOne(ctx, bson.D{
+		{Key: "_id", Value: id},
+		{Key: fieldPayload, Value: payload},
+		{Key: fieldVisibleAt, Value: now.Add(delay)},
+		{Key: fieldAttempts, Value: 0},
+		{Key: fieldEnqueuedAt, Value: now},
+	})
+	if err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	if q.notifications != nil && delay <= 0 {
+		// The message is stored, so a failed notification only delays its delivery until the
+		// receivers poll the queue.
+		_, _ = q.notifications.InsertOne(ctx, bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "message", Value: id}})
+	}
+	return id, nil
+}
+
+// TryReceive leases the visible message that has been visible the longest and returns it, or
+// returns ErrEmpty if no message is visible. Messages that have been received the maximum number
+// of times are moved to the dead-letter collection instead of being returned.
+func (q *Queue) TryReceive(ctx context.Context) (*Message, error) {
+	for {
+		now := q.now()
+		receipt := bson.NewObjectID()
+		var doc messageDocument
+		err := q.coll.FindOneAndUpdate(ctx,
+			bson.D{{Key: fieldVisibleAt, Value: bson.D{{Key: "$lte", Value: now}}}},
+			bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: fieldVisibleAt, Value: now.Add(q.visibilityTimeout)},
+					{Key: fieldReceipt, Value: receipt},
+				}},
+				{Key: "$inc", Value: bson.D{{Key: fieldAttempts, Value: 1}}},
+			},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: fieldVisibleAt, Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrEmpty
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		msg := &Message{
+			ID:         doc.ID,
+			Payload:    doc.Payload,
+			Attempts:   doc.Attempts,
+			EnqueuedAt: doc.EnqueuedAt,
+			receipt:    receipt,
+			queue:      q,
+		}
+		if q.maxAttempts > 0 && msg.Attempts > q.maxAttempts {
+			if err := msg.deadLetter(ctx, fmt.Sprintf("received more than %d times", q.maxAttempts)); err != nil &&
+				!errors.Is(err, ErrLeaseExpired) {
+				return nil, err
+			}
+			continue
+		}
+		return msg, nil
+	}
+}
+
+// Receive leases the visible message that has been visible the longest and returns it, waiting
+// until a message is visible or ctx is done.
+func (q *Queue) Receive(ctx context.Context) (*Message, error) {
+	since := bson.MinObjectIDFromTimestamp(q.now())
+	for {
+		msg, err := q.TryReceive(ctx)
+		if !errors.Is(err, ErrEmpty) {
+			return msg, err
+		}
+		if since, err = q.wait(ctx, since); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait waits for the poll interval, or until a notification inserted after since is received.
+// It returns the ID of the received notification, or since if none was received.
+func (q *Queue) wait(ctx context.Context, since bson.ObjectID) (bson.ObjectID, error) {
+	if q.notifications != nil {
+		last, err := q.waitForNotification(ctx, since)
+		if err != nil || last != since {
+			return last, err
+		}
+	}
+
+	timer := time.NewTimer(q.pollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return since, ctx.Err()
+	case <-timer.C:
+		return since, nil
+	}
+}
+
+// waitForNotification tails the notifications collection for up to the poll interval and returns
+// the ID of the first notification inserted after since, or since if there is none. It returns
+// without waiting if the collection cannot be tailed, e.g. because it is empty.
+func (q *Queue) waitForNotification(ctx context.Context, since bson.ObjectID) (bson.ObjectID, error) {
+	cursor, err := q.notifications.Find(ctx,
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: since}}}},
+		options.Find().SetCursorType(options.TailableAwait).SetMaxAwaitTime(q.pollInterval))
+	if err != nil {
+		return since, err
+	}
+	defer cursor.Close(context.Background())
+
+	// The first batch is returned immediately and, if it is empty, the getMore of the second call
+	// waits for up to the poll interval.
+	for i := 0; i < 2; i++ {
+		if i > 0 && cursor.ID() == 0 {
+			break
+		}
+		if cursor.TryNext(ctx) {
+			var notification struct {
+				ID bson.ObjectID `bson:"_id"`
+			}
+			if err := cursor.Decode(&notification); err != nil {
+				return since, err
+			}
+			return notification.ID, nil
+		}
+		if err := cursor.Err(); err != nil {
+			return since, err
+		}
+	}
+	return since, ctx.Err()
+}
+
+type messageDocument struct {
+	ID         bson.ObjectID `bson:"_id"`
+	Payload    bson.RawValue `bson:"payload"`
+	Attempts   int           `bson:"attempts"`
+	EnqueuedAt time.Time     `bson:"enqueuedAt"`
+}
+
+// Message is a message leased from a Queue.
+type Message struct {
+	// ID is the _id of the message document.
+	ID bson.ObjectID
+
+	// Payload is the payload passed to Enqueue.
+	Payload bson.RawValue
+
+	// Attempts is the number of times the message has been received, including this time.
+	Attempts int
+
+	// EnqueuedAt is the time the message was enqueued.
+	EnqueuedAt time.Time
+
+	receipt bson.ObjectID
+	queue   *Queue
+}
+
+// Decode unmarshals the payload of the message into v.
+func (m *Message) Decode(v interface{}) error {
+	return m.Payload.Unmarshal(v)
+}
+
+// leased returns a filter that matches the message document while its lease is held.
+func (m *Message) leased() bson.D {
+	return bson.D{{Key: "_id", Value: m.ID}, {Key: fieldReceipt, Value: m.receipt}}
+}
+
+// Ack acknowledges the message, which deletes it from the queue. It returns ErrLeaseExpired if
+// the lease has expired and the message may have been delivered again.
+func (m *Message) Ack(ctx context.Context) error {
+	res, err := m.queue.coll.DeleteOne(ctx, m.leased())
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrLeaseExpired
+	}
+	return nil
+}
+
+// Nack releases the lease of the message, so that it is delivered again after delay. It returns
+// ErrLeaseExpired if the lease has already expired.
+func (m *Message) Nack(ctx context.Context, delay time.Duration) error {
+	return m.setVisibleAt(ctx, m.queue.now().Add(delay), bson.D{{Key: fieldReceipt, Value: ""}})
+}
+
+// Extend extends the lease of the message to d from now, e.g. to keep processing a message for
+// longer than the visibility timeout. It returns ErrLeaseExpired if the lease has already expired.
+func (m *Message) Extend(ctx context.Context, d time.Duration) error {
+	return m.setVisibleAt(ctx, m.queue.now().Add(d), nil)
+}
+
+// DeadLetter moves the message to the dead-letter collection of the queue with the given reason,
+// e.g. because its payload is invalid, or deletes it if the queue has no dead-letter collection.
+// It returns ErrLeaseExpired if the lease has already expired.
+func (m *Message) DeadLetter(ctx context.Context, reason string) error {
+	return m.deadLetter(ctx, reason)
+}
+
+func (m *Message) setVisibleAt(ctx context.Context, visibleAt time.Time, unset bson.D) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: fieldVisibleAt, Value: visibleAt}}}}
+	if unset != nil {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+	res, err := m.queue.coll.UpdateOne(ctx, m.leased(), update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrLeaseExpired
+	}
+	return nil
+}
+
+func (m *Message) deadLetter(ctx context.Context, reason string) error {
+	if dl := m.queue.deadLetter; dl != nil {
+		_, err := dl.InsertOne(ctx, bson.D{
+			{Key: "_id", Value: m.ID},
+			{Key: fieldPayload, Value: m.Payload},
+			{Key: fieldAttempts, Value: m.Attempts},
+			{Key: fieldEnqueuedAt, Value: m.EnqueuedAt},
+			{Key: fieldError, Value: reason},
+		})
+		// A duplicate key error means that a previous attempt to move the message inserted it but
+		// did not delete it from the queue.
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("error moving message %s to the dead-letter collection: %w", m.ID.Hex(), err)
+		}
+	}
+	return m.Ack(ctx)
+}
@@ -0,0 +1,251 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/mongotest"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newDatabase returns a Database of a Client that runs its operations against d.
+func newDatabase(t *testing.T, d *mongotest.Deployment) *mongo.Database {
+	t.Helper()
+
+	client, err := d.NewClient()
+	require.NoError(t, err, "NewClient error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return client.Database("db")
+}
+
+func newQueue(db *mongo.Database) *Queue {
+	q := New(db.Collection("jobs"))
+	q.now = func() time.Time { return testNow }
+	return q
+}
+
+func messageReply(id bson.ObjectID, attempts int32) mongotest.Reply {
+	return mongotest.Success(bson.E{Key: "value", Value: bson.D{
+		{"_id", id},
+		{"payload", bson.D{{"task", "resize"}}},
+		{"visibleAt", testNow.Add(DefaultVisibilityTimeout)},
+		{"attempts", attempts},
+		{"enqueuedAt", testNow.Add(-time.Minute)},
+	}})
+}
+
+func emptyReply() mongotest.Reply {
+	return mongotest.Success(bson.E{Key: "value", Value: nil})
+}
+
+func writeReply(n int32) mongotest.Reply {
+	return mongotest.Success(bson.E{Key: "n", Value: n})
+}
+
+func TestQueueTryReceive(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", messageReply(id, 1))
+	d.AddReplies("delete", writeReply(1), writeReply(0))
+	q := newQueue(newDatabase(t, d)).SetVisibilityTimeout(time.Minute)
+
+	msg, err := q.TryReceive(context.Background())
+	require.NoError(t, err, "TryReceive error")
+	assert.Equal(t, id, msg.ID, "ID mismatch")
+	assert.Equal(t, 1, msg.Attempts, "Attempts mismatch")
+	assert.True(t, msg.EnqueuedAt.Equal(testNow.Add(-time.Minute)), "EnqueuedAt mismatch: %v", msg.EnqueuedAt)
+
+	var payload struct{ Task string }
+	require.NoError(t, msg.Decode(&payload), "Decode error")
+	assert.Equal(t, "resize", payload.Task, "payload mismatch")
+
+	require.NoError(t, msg.Ack(context.Background()), "Ack error")
+	err = msg.Ack(context.Background())
+	assert.True(t, errors.Is(err, ErrLeaseExpired), "expected ErrLeaseExpired, got %v", err)
+
+	cmds := d.Commands()
+	require.Equal(t, 3, len(cmds), "expected 3 commands")
+
+	fam := cmds[0].Document
+	assert.Equal(t, "jobs", fam.Lookup("findAndModify").StringValue(), "findAndModify mismatch")
+	lte := fam.Lookup("query", "visibleAt", "$lte").Time()
+	assert.True(t, lte.Equal(testNow), "query mismatch: %v", lte)
+	visibleAt := fam.Lookup("update", "$set", "visibleAt").Time()
+	assert.True(t, visibleAt.Equal(testNow.Add(time.Minute)), "visibleAt mismatch: %v", visibleAt)
+	receipt := fam.Lookup("update", "$set", "receipt").ObjectID()
+	assert.Equal(t, receipt, msg.receipt, "receipt mismatch")
+	assert.Equal(t, int32(1), fam.Lookup("update", "$inc", "attempts").Int32(), "$inc mismatch")
+	assert.Equal(t, int32(1), fam.Lookup("sort", "visibleAt").Int32(), "sort mismatch")
+	assert.True(t, fam.Lookup("new").Boolean(), "expected the updated document to be returned")
+	assert.Equal(t, "majority", fam.Lookup("writeConcern", "w").StringValue(), "write concern mismatch")
+
+	del := cmds[1].Document
+	assert.Equal(t, "jobs", del.Lookup("delete").StringValue(), "delete mismatch")
+	q0 := del.Lookup("deletes").Array().Index(0).Document().Lookup("q")
+	assert.Equal(t, id, q0.Document().Lookup("_id").ObjectID(), "delete _id mismatch")
+	assert.Equal(t, receipt, q0.Document().Lookup("receipt").ObjectID(), "delete receipt mismatch")
+}
+
+func TestQueueTryReceiveEmpty(t *testing.T) {
+	t.Parallel()
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", emptyReply())
+
+	_, err := newQueue(newDatabase(t, d)).TryReceive(context.Background())
+	assert.True(t, errors.Is(err, ErrEmpty), "expected ErrEmpty, got %v", err)
+}
+
+func TestQueueDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", messageReply(id, 4), emptyReply())
+	d.AddReplies("insert", writeReply(1))
+	d.AddReplies("delete", writeReply(1))
+	db := newDatabase(t, d)
+	q := newQueue(db).SetMaxAttempts(3).SetDeadLetter(db.Collection("jobs.dead"))
+
+	_, err := q.TryReceive(context.Background())
+	assert.True(t, errors.Is(err, ErrEmpty), "expected ErrEmpty, got %v", err)
+
+	cmds := d.Commands()
+	require.Equal(t, 4, len(cmds), "expected 4 commands")
+
+	ins := cmds[1].Document
+	assert.Equal(t, "jobs.dead", ins.Lookup("insert").StringValue(), "insert mismatch")
+	doc := ins.Lookup("documents").Array().Index(0).Document()
+	assert.Equal(t, id, doc.Lookup("_id").ObjectID(), "dead letter _id mismatch")
+	assert.Equal(t, int32(4), doc.Lookup("attempts").Int32(), "dead letter attempts mismatch")
+	assert.Equal(t, "received more than 3 times", doc.Lookup("error").StringValue(), "dead letter error mismatch")
+	assert.Equal(t, "majority", ins.Lookup("writeConcern", "w").StringValue(), "write concern mismatch")
+
+	assert.Equal(t, "jobs", cmds[2].Document.Lookup("delete").StringValue(), "delete mismatch")
+	assert.Equal(t, "jobs", cmds[3].Document.Lookup("findAndModify").StringValue(), "findAndModify mismatch")
+}
+
+func TestMessageNack(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", messageReply(id, 1))
+	d.AddReplies("update",
+		mongotest.Success(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		mongotest.Success(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+	)
+	q := newQueue(newDatabase(t, d))
+
+	msg, err := q.TryReceive(context.Background())
+	require.NoError(t, err, "TryReceive error")
+	require.NoError(t, msg.Nack(context.Background(), 10*time.Second), "Nack error")
+	err = msg.Extend(context.Background(), time.Minute)
+	assert.True(t, errors.Is(err, ErrLeaseExpired), "expected ErrLeaseExpired, got %v", err)
+
+	cmds := d.Commands()
+	require.Equal(t, 3, len(cmds), "expected 3 commands")
+
+	update := cmds[1].Document.Lookup("updates").Array().Index(0).Document()
+	assert.Equal(t, id, update.Lookup("q", "_id").ObjectID(), "update _id mismatch")
+	visibleAt := update.Lookup("u", "$set", "visibleAt").Time()
+	assert.True(t, visibleAt.Equal(testNow.Add(10*time.Second)), "visibleAt mismatch: %v", visibleAt)
+	_, err = update.LookupErr("u", "$unset", "receipt")
+	assert.NoError(t, err, "expected the receipt to be unset")
+}
+
+func TestQueueEnqueue(t *testing.T) {
+	t.Parallel()
+
+	d := mongotest.NewDeployment()
+	d.AddReplies("insert", writeReply(1), writeReply(1), writeReply(1))
+	db := newDatabase(t, d)
+	q := newQueue(db).SetNotifications(db.Collection("jobs.notifications"))
+
+	id, err := q.Enqueue(context.Background(), bson.D{{"task", "resize"}})
+	require.NoError(t, err, "Enqueue error")
+	_, err = q.EnqueueAfter(context.Background(), bson.D{{"task", "later"}}, time.Hour)
+	require.NoError(t, err, "EnqueueAfter error")
+
+	cmds := d.Commands()
+	require.Equal(t, 3, len(cmds), "expected 3 commands")
+
+	doc := cmds[0].Document.Lookup("documents").Array().Index(0).Document()
+	assert.Equal(t, id, doc.Lookup("_id").ObjectID(), "_id mismatch")
+	assert.Equal(t, "resize", doc.Lookup("payload", "task").StringValue(), "payload mismatch")
+	assert.True(t, doc.Lookup("visibleAt").Time().Equal(testNow), "visibleAt mismatch")
+	assert.Equal(t, int32(0), doc.Lookup("attempts").Int32(), "attempts mismatch")
+
+	notification := cmds[1].Document
+	assert.Equal(t, "jobs.notifications", notification.Lookup("insert").StringValue(), "notification mismatch")
+	assert.Equal(t, id, notification.Lookup("documents").Array().Index(0).Document().Lookup("message").ObjectID(),
+		"notification message mismatch")
+
+	// Delayed messages are not notified, since they are not visible yet.
+	delayed := cmds[2].Document.Lookup("documents").Array().Index(0).Document()
+	assert.Equal(t, "jobs", cmds[2].Document.Lookup("insert").StringValue(), "insert mismatch")
+	assert.True(t, delayed.Lookup("visibleAt").Time().Equal(testNow.Add(time.Hour)), "delayed visibleAt mismatch")
+}
+
+func TestQueueReceive(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", emptyReply(), messageReply(id, 1))
+	q := newQueue(newDatabase(t, d)).SetPollInterval(time.Millisecond)
+
+	msg, err := q.Receive(context.Background())
+	require.NoError(t, err, "Receive error")
+	assert.Equal(t, id, msg.ID, "ID mismatch")
+	assert.Equal(t, 2, len(d.Commands()), "expected 2 commands")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d = mongotest.NewDeployment()
+	d.AddReplies("findAndModify", emptyReply())
+	_, err = newQueue(newDatabase(t, d)).Receive(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+}
+
+func TestQueueReceiveNotification(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	notification := bson.NewObjectID()
+	d := mongotest.NewDeployment()
+	d.AddReplies("findAndModify", emptyReply(), messageReply(id, 1))
+	d.AddReplies("find",
+		mongotest.Cursor("db.jobs.notifications", 0, bson.D{{"_id", notification}, {"message", id}}))
+	db := newDatabase(t, d)
+	q := newQueue(db).SetNotifications(db.Collection("jobs.notifications")).SetPollInterval(time.Hour)
+
+	msg, err := q.Receive(context.Background())
+	require.NoError(t, err, "Receive error")
+	assert.Equal(t, id, msg.ID, "ID mismatch")
+
+	cmds := d.Commands()
+	require.Equal(t, 3, len(cmds), "expected 3 commands")
+	find := cmds[1].Document
+	assert.Equal(t, "jobs.notifications", find.Lookup("find").StringValue(), "find mismatch")
+	assert.True(t, find.Lookup("tailable").Boolean(), "expected a tailable cursor")
+	assert.True(t, find.Lookup("awaitData").Boolean(), "expected an awaitData cursor")
+	since := find.Lookup("filter", "_id", "$gt").ObjectID()
+	assert.True(t, since.Timestamp().Equal(testNow), "filter mismatch: %v", since.Timestamp())
+}
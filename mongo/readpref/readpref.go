@@ -20,6 +20,15 @@ var (
 	errInvalidReadPreference = errors.New("can not specify tags, max staleness, or hedge with mode primary")
 )
 
+const (
+	// minMaxStaleness is the smallest max staleness allowed by the server selection specification.
+	minMaxStaleness = 90 * time.Second
+
+	// idleWritePeriod is the interval at which the primary of a replica set writes a no-op, which
+	// bounds how accurately the staleness of a secondary can be estimated.
+	idleWritePeriod = 10 * time.Second
+)
+
 // Primary constructs a read preference with a PrimaryMode.
 func Primary() *ReadPref {
 	return &ReadPref{mode: PrimaryMode}
@@ -109,6 +118,34 @@ func (r *ReadPref) HedgeEnabled() *bool {
 	return r.hedgeEnabled
 }
 
+// Validate returns an error if the read preference cannot be used by a client that checks servers
+// every heartbeatInterval: if its mode is invalid, if tags, max staleness, or hedge are specified
+// with mode primary, or if its max staleness is less than 90 seconds or than heartbeatInterval
+// plus the 10 second idle write period of the primary. Validating a read preference when a client
+// is constructed reports these errors instead of failing server selection for each operation.
+func (r *ReadPref) Validate(heartbeatInterval time.Duration) error {
+	if !r.mode.IsValid() {
+		return fmt.Errorf("invalid read preference mode %d", r.mode)
+	}
+	if r.mode == PrimaryMode && (r.maxStalenessSet || len(r.tagSets) > 0 || r.hedgeEnabled != nil) {
+		return errInvalidReadPreference
+	}
+	if !r.maxStalenessSet {
+		return nil
+	}
+
+	if r.maxStaleness < minMaxStaleness {
+		return fmt.Errorf("max staleness (%s) must be greater than or equal to 90s", r.maxStaleness)
+	}
+	if r.maxStaleness < heartbeatInterval+idleWritePeriod {
+		return fmt.Errorf(
+			"max staleness (%s) must be greater than or equal to the heartbeat interval (%s) plus idle write period (%s)",
+			r.maxStaleness, heartbeatInterval, idleWritePeriod,
+		)
+	}
+	return nil
+}
+
 // String returns a human-readable description of the read preference.
 func (r *ReadPref) String() string {
 	var b bytes.Buffer
@@ -126,6 +126,57 @@ func TestHedge(t *testing.T) {
 	})
 }
 
+func TestReadPref_Validate(t *testing.T) {
+	testCases := []struct {
+		name              string
+		rp                *ReadPref
+		heartbeatInterval time.Duration
+		wantErr           bool
+	}{
+		{
+			name: "no max staleness",
+			rp:   Nearest(WithHedgeEnabled(false)),
+		},
+		{
+			name:              "valid max staleness",
+			rp:                Secondary(WithMaxStaleness(90 * time.Second)),
+			heartbeatInterval: 10 * time.Second,
+		},
+		{
+			name:    "max staleness below 90 seconds",
+			rp:      Secondary(WithMaxStaleness(30 * time.Second)),
+			wantErr: true,
+		},
+		{
+			name:              "max staleness below heartbeat interval plus idle write period",
+			rp:                Secondary(WithMaxStaleness(100 * time.Second)),
+			heartbeatInterval: 95 * time.Second,
+			wantErr:           true,
+		},
+		{
+			name:    "invalid mode",
+			rp:      &ReadPref{mode: 42},
+			wantErr: true,
+		},
+		{
+			name:    "hedge with primary mode",
+			rp:      &ReadPref{mode: PrimaryMode, hedgeEnabled: new(bool)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rp.Validate(tc.heartbeatInterval)
+			if tc.wantErr {
+				assert.NotNil(t, err, "expected Validate error")
+			} else {
+				assert.Nil(t, err, "Validate error: %v", err)
+			}
+		})
+	}
+}
+
 func TestReadPref_String(t *testing.T) {
 	t.Run("ReadPref.String() with all options", func(t *testing.T) {
 		readPref := Nearest(
@@ -0,0 +1,105 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package readpref
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/tag"
+)
+
+// ErrEmptyTagName indicates that a tag with an empty name was specified.
+var ErrEmptyTagName = errors.New("tag name cannot be empty")
+
+// TagSetBuilder builds a tag set one tag at a time. Unlike a map literal passed to
+// [tag.NewTagSetFromMap], the tags keep the order in which they were added, and mistakes such as an
+// empty tag name or a tag set twice to different values, which would prevent any server from
+// matching the tag set, are reported when the tag set is built.
+//
+// For more information about read preference tags, see
+// https://www.mongodb.com/docs/manual/core/read-preference-tags/
+type TagSetBuilder struct {
+	set tag.Set
+	err error
+}
+
+// Tags creates an empty TagSetBuilder.
+func Tags() *TagSetBuilder {
+	return &TagSetBuilder{}
+}
+
+// Tag adds a tag with the given name and value.
+func (b *TagSetBuilder) Tag(name, value string) *TagSetBuilder {
+	if b.err != nil {
+		return b
+	}
+	if name == "" {
+		b.err = ErrEmptyTagName
+		return b
+	}
+	for _, t := range b.set {
+		if t.Name != name {
+			continue
+		}
+		if t.Value != value {
+			b.err = fmt.Errorf("tag %q set to both %q and %q", name, t.Value, value)
+		}
+		return b
+	}
+
+	b.set = append(b.set, tag.Tag{Name: name, Value: value})
+	return b
+}
+
+// DC adds a "dc" tag, commonly used to identify the data center of a replica set member.
+func (b *TagSetBuilder) DC(dc string) *TagSetBuilder {
+	return b.Tag("dc", dc)
+}
+
+// Rack adds a "rack" tag, commonly used to identify the rack of a replica set member.
+func (b *TagSetBuilder) Rack(rack string) *TagSetBuilder {
+	return b.Tag("rack", rack)
+}
+
+// Region adds a "region" tag, such as the cloud provider region tag of MongoDB Atlas nodes.
+func (b *TagSetBuilder) Region(region string) *TagSetBuilder {
+	return b.Tag("region", region)
+}
+
+// NodeType adds a "nodeType" tag, such as the "ELECTABLE", "READ_ONLY", or "ANALYTICS" tags of
+// MongoDB Atlas nodes.
+func (b *TagSetBuilder) NodeType(nodeType string) *TagSetBuilder {
+	return b.Tag("nodeType", nodeType)
+}
+
+// Build returns the tag set, or the first error encountered while adding tags.
+func (b *TagSetBuilder) Build() (tag.Set, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.set, nil
+}
+
+// WithTagSetBuilders specifies a list of tag sets used to match replica set members, built from the
+// given builders. It returns an error if any builder fails to build. See [WithTagSets] for how the
+// tag sets are used.
+//
+// The last call to [WithTags], [WithTagSets], or [WithTagSetBuilders] overrides all previous calls.
+func WithTagSetBuilders(builders ...*TagSetBuilder) Option {
+	return func(rp *ReadPref) error {
+		sets := make([]tag.Set, 0, len(builders))
+		for _, b := range builders {
+			set, err := b.Build()
+			if err != nil {
+				return err
+			}
+			sets = append(sets, set)
+		}
+		return WithTagSets(sets...)(rp)
+	}
+}
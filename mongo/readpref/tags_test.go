@@ -0,0 +1,52 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package readpref
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/tag"
+)
+
+func TestTagSetBuilder(t *testing.T) {
+	t.Run("builds tags in order", func(t *testing.T) {
+		set, err := Tags().DC("east").Rack("r1").Region("us-east-1").NodeType("ANALYTICS").Tag("disk", "ssd").Build()
+		require.NoError(t, err, "Build error: %v", err)
+
+		want := tag.Set{
+			{Name: "dc", Value: "east"},
+			{Name: "rack", Value: "r1"},
+			{Name: "region", Value: "us-east-1"},
+			{Name: "nodeType", Value: "ANALYTICS"},
+			{Name: "disk", Value: "ssd"},
+		}
+		assert.Equal(t, want, set, "expected tag set to match")
+	})
+	t.Run("repeated tag", func(t *testing.T) {
+		set, err := Tags().DC("east").DC("east").Build()
+		require.NoError(t, err, "Build error: %v", err)
+		assert.Equal(t, tag.Set{{Name: "dc", Value: "east"}}, set, "expected repeated tag to be added once")
+	})
+	t.Run("conflicting tag", func(t *testing.T) {
+		_, err := Tags().DC("east").Rack("r1").DC("west").Build()
+		assert.ErrorContains(t, err, `tag "dc" set to both "east" and "west"`, "expected conflicting tag error")
+	})
+	t.Run("empty name", func(t *testing.T) {
+		_, err := Tags().Tag("", "x").Build()
+		assert.ErrorIs(t, err, ErrEmptyTagName, "expected ErrEmptyTagName")
+	})
+	t.Run("WithTagSetBuilders", func(t *testing.T) {
+		rp, err := New(SecondaryMode, WithTagSetBuilders(Tags().DC("east"), Tags()))
+		require.NoError(t, err, "New error: %v", err)
+		assert.Equal(t, []tag.Set{{{Name: "dc", Value: "east"}}, nil}, rp.TagSets(), "expected tag sets to match")
+
+		_, err = New(SecondaryMode, WithTagSetBuilders(Tags().Rack("")), WithTagSetBuilders(Tags().Tag("", "x")))
+		assert.ErrorIs(t, err, ErrEmptyTagName, "expected builder error to be returned")
+	})
+}
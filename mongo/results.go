@@ -146,17 +146,123 @@ type IndexSpecification struct {
 
 	// The clustered index.
 	Clustered *bool
+
+	// If true, the index is hidden from the query planner. See IndexView.Hide.
+	Hidden *bool
+
+	// If true, new documents with duplicate index key values are rejected while the index is being
+	// converted to a unique index. See IndexView.PrepareUnique.
+	PrepareUnique *bool
+
+	// The collation of the index.
+	Collation *options.Collation
+
+	// The filter of a partial index, which only references the documents that match it.
+	PartialFilterExpression bson.Raw
+
+	// The projection of a wildcard index, which specifies the fields included in or excluded from
+	// the index.
+	WildcardProjection bson.Raw
+
+	// The storage engine options of the index.
+	StorageEngine bson.Raw
+
+	// The weights of the fields of a text index.
+	Weights bson.Raw
+
+	// The default language and the name of the field that overrides it, for text indexes.
+	DefaultLanguage  *string
+	LanguageOverride *string
+
+	// The text index version number.
+	TextVersion *int32
+
+	// The 2dsphere index version number.
+	SphereVersion *int32
+
+	// The precision of the geohash and the bounds of the location values, for 2d indexes.
+	Bits *int32
+	Min  *float64
+	Max  *float64
 }
 
 type indexListSpecificationResponse struct {
-	Name               string   `bson:"name"`
-	Namespace          string   `bson:"ns"`
-	KeysDocument       bson.Raw `bson:"key"`
-	Version            int32    `bson:"v"`
-	ExpireAfterSeconds *int32   `bson:"expireAfterSeconds"`
-	Sparse             *bool    `bson:"sparse"`
-	Unique             *bool    `bson:"unique"`
-	Clustered          *bool    `bson:"clustered"`
+	Name                    string                  `bson:"name"`
+	Namespace               string                  `bson:"ns"`
+	KeysDocument            bson.Raw                `bson:"key"`
+	Version                 int32                   `bson:"v"`
+	ExpireAfterSeconds      *int32                  `bson:"expireAfterSeconds"`
+	Sparse                  *bool                   `bson:"sparse"`
+	Unique                  *bool                   `bson:"unique"`
+	Clustered               *bool                   `bson:"clustered"`
+	Hidden                  *bool                   `bson:"hidden"`
+	PrepareUnique           *bool                   `bson:"prepareUnique"`
+	Collation               *indexCollationResponse `bson:"collation"`
+	PartialFilterExpression bson.Raw                `bson:"partialFilterExpression"`
+	WildcardProjection      bson.Raw                `bson:"wildcardProjection"`
+	StorageEngine           bson.Raw                `bson:"storageEngine"`
+	Weights                 bson.Raw                `bson:"weights"`
+	DefaultLanguage         *string                 `bson:"default_language"`
+	LanguageOverride        *string                 `bson:"language_override"`
+	TextVersion             *int32                  `bson:"textIndexVersion"`
+	SphereVersion           *int32                  `bson:"2dsphereIndexVersion"`
+	Bits                    *int32                  `bson:"bits"`
+	Min                     *float64                `bson:"min"`
+	Max                     *float64                `bson:"max"`
+}
+
+// indexCollationResponse is a collation as reported by the server, which uses camel case field
+// names that do not match the default names of the options.Collation fields.
+type indexCollationResponse struct {
+	Locale          string `bson:"locale"`
+	CaseLevel       bool   `bson:"caseLevel"`
+	CaseFirst       string `bson:"caseFirst"`
+	Strength        int    `bson:"strength"`
+	NumericOrdering bool   `bson:"numericOrdering"`
+	Alternate       string `bson:"alternate"`
+	MaxVariable     string `bson:"maxVariable"`
+	Normalization   bool   `bson:"normalization"`
+	Backwards       bool   `bson:"backwards"`
+}
+
+func (resp indexListSpecificationResponse) specification() IndexSpecification {
+	spec := IndexSpecification{
+		Name:                    resp.Name,
+		Namespace:               resp.Namespace,
+		KeysDocument:            resp.KeysDocument,
+		Version:                 resp.Version,
+		ExpireAfterSeconds:      resp.ExpireAfterSeconds,
+		Sparse:                  resp.Sparse,
+		Unique:                  resp.Unique,
+		Clustered:               resp.Clustered,
+		Hidden:                  resp.Hidden,
+		PrepareUnique:           resp.PrepareUnique,
+		PartialFilterExpression: resp.PartialFilterExpression,
+		WildcardProjection:      resp.WildcardProjection,
+		StorageEngine:           resp.StorageEngine,
+		Weights:                 resp.Weights,
+		DefaultLanguage:         resp.DefaultLanguage,
+		LanguageOverride:        resp.LanguageOverride,
+		TextVersion:             resp.TextVersion,
+		SphereVersion:           resp.SphereVersion,
+		Bits:                    resp.Bits,
+		Min:                     resp.Min,
+		Max:                     resp.Max,
+	}
+	if c := resp.Collation; c != nil {
+		spec.Collation = &options.Collation{
+			Locale:          c.Locale,
+			CaseLevel:       c.CaseLevel,
+			CaseFirst:       c.CaseFirst,
+			Strength:        c.Strength,
+			NumericOrdering: c.NumericOrdering,
+			Alternate:       c.Alternate,
+			MaxVariable:     c.MaxVariable,
+			Normalization:   c.Normalization,
+			Backwards:       c.Backwards,
+		}
+	}
+	return spec
 }
 
 // CollectionSpecification represents a collection in a database. This type is returned by the
@@ -0,0 +1,80 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestIndexSpecificationDecode(t *testing.T) {
+	t.Parallel()
+
+	doc, err := bson.Marshal(bson.D{
+		{"v", int32(2)},
+		{"key", bson.D{{"a.$**", int32(1)}}},
+		{"name", "a.$**_1"},
+		{"hidden", true},
+		{"prepareUnique", true},
+		{"collation", bson.D{
+			{"locale", "fr"},
+			{"caseLevel", true},
+			{"caseFirst", "off"},
+			{"strength", int32(2)},
+			{"numericOrdering", false},
+			{"alternate", "non-ignorable"},
+			{"maxVariable", "punct"},
+			{"normalization", false},
+			{"backwards", true},
+			{"version", "57.1"},
+		}},
+		{"partialFilterExpression", bson.D{{"status", bson.D{{"$eq", "active"}}}}},
+		{"wildcardProjection", bson.D{{"a.b", int32(0)}}},
+		{"default_language", "french"},
+		{"textIndexVersion", int32(3)},
+		{"2dsphereIndexVersion", int32(3)},
+		{"bits", int32(26)},
+		{"min", -180.0},
+		{"max", int32(180)},
+	})
+	require.NoError(t, err, "Marshal error")
+
+	var resp indexListSpecificationResponse
+	require.NoError(t, bson.Unmarshal(doc, &resp), "Unmarshal error")
+	spec := resp.specification()
+
+	assert.Equal(t, "a.$**_1", spec.Name, "name mismatch")
+	require.NotNil(t, spec.Hidden, "expected hidden")
+	assert.True(t, *spec.Hidden, "expected hidden to be true")
+	require.NotNil(t, spec.PrepareUnique, "expected prepareUnique")
+	assert.True(t, *spec.PrepareUnique, "expected prepareUnique to be true")
+	assert.Equal(t, &options.Collation{
+		Locale:      "fr",
+		CaseLevel:   true,
+		CaseFirst:   "off",
+		Strength:    2,
+		Alternate:   "non-ignorable",
+		MaxVariable: "punct",
+		Backwards:   true,
+	}, spec.Collation, "collation mismatch")
+	assert.Equal(t, "active", spec.PartialFilterExpression.Lookup("status", "$eq").StringValue(), "partial filter mismatch")
+	assert.Equal(t, int32(0), spec.WildcardProjection.Lookup("a.b").Int32(), "wildcard projection mismatch")
+	require.NotNil(t, spec.DefaultLanguage, "expected default language")
+	assert.Equal(t, "french", *spec.DefaultLanguage, "default language mismatch")
+	assert.Nil(t, spec.LanguageOverride, "expected no language override")
+	require.NotNil(t, spec.TextVersion, "expected text version")
+	assert.Equal(t, int32(3), *spec.TextVersion, "text version mismatch")
+	require.NotNil(t, spec.SphereVersion, "expected 2dsphere version")
+	assert.Equal(t, int32(3), *spec.SphereVersion, "2dsphere version mismatch")
+	require.NotNil(t, spec.Max, "expected max")
+	assert.Equal(t, 180.0, *spec.Max, "max mismatch")
+	assert.Nil(t, spec.Unique, "expected unique to be unset")
+}
@@ -0,0 +1,68 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// RetryInfo describes the attempts made to execute an operation that was retried. It is set on the
+// CommandError or WriteException returned by a retried operation and can also be retrieved from the
+// error chain with errors.As:
+//
+//	var ri *mongo.RetryInfo
+//	if errors.As(err, &ri) {
+//		log.Printf("failed after %d attempts on %v: %s", ri.Attempts, ri.Servers, ri.Reason)
+//	}
+type RetryInfo struct {
+	// Attempts is the number of times the command was sent to a server, including the first
+	// attempt.
+	Attempts int
+
+	// Reason describes the error that caused the last retry, such as "network error" or "not
+	// writable primary".
+	Reason string
+
+	// Labels are the error labels of the error that caused the last retry.
+	Labels []string
+
+	// Servers are the addresses of the servers the attempts were sent to, in order. After a
+	// failover, the first and last servers are the old and new primaries.
+	Servers []address.Address
+}
+
+// Error implements the error interface so that a *RetryInfo can be the target of errors.As.
+func (ri *RetryInfo) Error() string {
+	return fmt.Sprintf("operation attempted %d times, last retried because of %s on servers %v",
+		ri.Attempts, ri.Reason, ri.Servers)
+}
+
+func convertDriverRetryInfo(ri *driver.RetryInfo) *RetryInfo {
+	if ri == nil {
+		return nil
+	}
+
+	return &RetryInfo{
+		Attempts: ri.Attempts,
+		Reason:   string(ri.Reason),
+		Labels:   ri.Labels,
+		Servers:  ri.Servers,
+	}
+}
+
+// asRetryInfo implements errors.As for *RetryInfo targets.
+func asRetryInfo(ri *RetryInfo, target interface{}) bool {
+	p, ok := target.(**RetryInfo)
+	if !ok || ri == nil {
+		return false
+	}
+	*p = ri
+	return true
+}
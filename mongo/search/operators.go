@@ -0,0 +1,526 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package search
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Operator is an Atlas Search operator that can be used in a $search or $searchMeta stage or as a
+// clause of a compound operator.
+//
+// For more information about Atlas Search operators, see
+// https://www.mongodb.com/docs/atlas/atlas-search/operators-and-collectors/
+type Operator interface {
+	// Name returns the name of the operator, e.g. "text".
+	Name() string
+
+	// Document returns the body of the operator.
+	Document() bson.D
+}
+
+// Score modifies the relevance score of the documents matched by an operator.
+//
+// For more information about scoring, see
+// https://www.mongodb.com/docs/atlas/atlas-search/scoring/
+type Score bson.D
+
+// BoostScore returns a Score that multiplies the score of matching documents by value.
+func BoostScore(value float64) Score {
+	return Score{{Key: "boost", Value: bson.D{{Key: "value", Value: value}}}}
+}
+
+// BoostPathScore returns a Score that multiplies the score of matching documents by the value of
+// the numeric field at path.
+func BoostPathScore(path string) Score {
+	return Score{{Key: "boost", Value: bson.D{{Key: "path", Value: path}}}}
+}
+
+// ConstantScore returns a Score that replaces the score of matching documents with value.
+func ConstantScore(value float64) Score {
+	return Score{{Key: "constant", Value: bson.D{{Key: "value", Value: value}}}}
+}
+
+// Fuzzy configures fuzzy matching for the text and autocomplete operators.
+type Fuzzy struct {
+	MaxEdits      *int32
+	PrefixLength  *int32
+	MaxExpansions *int32
+}
+
+// NewFuzzy creates a new Fuzzy with the server's default settings.
+func NewFuzzy() *Fuzzy {
+	return &Fuzzy{}
+}
+
+// SetMaxEdits sets the maximum number of single-character edits required to match the search
+// term. Valid values are 1 and 2.
+func (f *Fuzzy) SetMaxEdits(n int32) *Fuzzy {
+	f.MaxEdits = &n
+	return f
+}
+
+// SetPrefixLength sets the number of characters at the beginning of each term that must exactly
+// match.
+func (f *Fuzzy) SetPrefixLength(n int32) *Fuzzy {
+	f.PrefixLength = &n
+	return f
+}
+
+// SetMaxExpansions sets the maximum number of variations to generate and search for.
+func (f *Fuzzy) SetMaxExpansions(n int32) *Fuzzy {
+	f.MaxExpansions = &n
+	return f
+}
+
+func (f *Fuzzy) document() bson.D {
+	doc := bson.D{}
+	if f.MaxEdits != nil {
+		doc = append(doc, bson.E{Key: "maxEdits", Value: *f.MaxEdits})
+	}
+	if f.PrefixLength != nil {
+		doc = append(doc, bson.E{Key: "prefixLength", Value: *f.PrefixLength})
+	}
+	if f.MaxExpansions != nil {
+		doc = append(doc, bson.E{Key: "maxExpansions", Value: *f.MaxExpansions})
+	}
+	return doc
+}
+
+// TextOperator is the text operator, which performs a full-text search.
+//
+// For more information about the text operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/text/
+type TextOperator struct {
+	Query    []string
+	Path     []string
+	Fuzzy    *Fuzzy
+	Synonyms string
+	Score    Score
+}
+
+var _ Operator = (*TextOperator)(nil)
+
+// Text creates a text operator that searches the given paths for query.
+func Text(query string, paths ...string) *TextOperator {
+	return &TextOperator{Query: []string{query}, Path: paths}
+}
+
+// SetFuzzy enables fuzzy matching. It cannot be used together with SetSynonyms.
+func (t *TextOperator) SetFuzzy(f *Fuzzy) *TextOperator {
+	t.Fuzzy = f
+	return t
+}
+
+// SetSynonyms sets the name of the synonym mapping definition in the index to use.
+func (t *TextOperator) SetSynonyms(name string) *TextOperator {
+	t.Synonyms = name
+	return t
+}
+
+// SetScore sets the score modifier for the operator.
+func (t *TextOperator) SetScore(s Score) *TextOperator {
+	t.Score = s
+	return t
+}
+
+// Name implements the Operator interface.
+func (t *TextOperator) Name() string { return "text" }
+
+// Document implements the Operator interface.
+func (t *TextOperator) Document() bson.D {
+	doc := bson.D{
+		{Key: "query", Value: stringOrArray(t.Query)},
+		{Key: "path", Value: stringOrArray(t.Path)},
+	}
+	if t.Fuzzy != nil {
+		doc = append(doc, bson.E{Key: "fuzzy", Value: t.Fuzzy.document()})
+	}
+	if t.Synonyms != "" {
+		doc = append(doc, bson.E{Key: "synonyms", Value: t.Synonyms})
+	}
+	return appendScore(doc, t.Score)
+}
+
+// PhraseOperator is the phrase operator, which searches for terms in an order similar to the query.
+//
+// For more information about the phrase operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/phrase/
+type PhraseOperator struct {
+	Query []string
+	Path  []string
+	Slop  *int32
+	Score Score
+}
+
+var _ Operator = (*PhraseOperator)(nil)
+
+// Phrase creates a phrase operator that searches the given paths for query.
+func Phrase(query string, paths ...string) *PhraseOperator {
+	return &PhraseOperator{Query: []string{query}, Path: paths}
+}
+
+// SetSlop sets the allowable distance between words in the query phrase.
+func (p *PhraseOperator) SetSlop(n int32) *PhraseOperator {
+	p.Slop = &n
+	return p
+}
+
+// SetScore sets the score modifier for the operator.
+func (p *PhraseOperator) SetScore(s Score) *PhraseOperator {
+	p.Score = s
+	return p
+}
+
+// Name implements the Operator interface.
+func (p *PhraseOperator) Name() string { return "phrase" }
+
+// Document implements the Operator interface.
+func (p *PhraseOperator) Document() bson.D {
+	doc := bson.D{
+		{Key: "query", Value: stringOrArray(p.Query)},
+		{Key: "path", Value: stringOrArray(p.Path)},
+	}
+	if p.Slop != nil {
+		doc = append(doc, bson.E{Key: "slop", Value: *p.Slop})
+	}
+	return appendScore(doc, p.Score)
+}
+
+// AutocompleteOperator is the autocomplete operator, which performs a search-as-you-type query.
+//
+// For more information about the autocomplete operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/autocomplete/
+type AutocompleteOperator struct {
+	Query      string
+	Path       string
+	TokenOrder string
+	Fuzzy      *Fuzzy
+	Score      Score
+}
+
+var _ Operator = (*AutocompleteOperator)(nil)
+
+// Autocomplete creates an autocomplete operator that searches the field at path for query.
+func Autocomplete(query, path string) *AutocompleteOperator {
+	return &AutocompleteOperator{Query: query, Path: path}
+}
+
+// SetTokenOrder sets the order in which to search for tokens. Valid values are "any" and
+// "sequential".
+func (a *AutocompleteOperator) SetTokenOrder(order string) *AutocompleteOperator {
+	a.TokenOrder = order
+	return a
+}
+
+// SetFuzzy enables fuzzy matching.
+func (a *AutocompleteOperator) SetFuzzy(f *Fuzzy) *AutocompleteOperator {
+	a.Fuzzy = f
+	return a
+}
+
+// SetScore sets the score modifier for the operator.
+func (a *AutocompleteOperator) SetScore(s Score) *AutocompleteOperator {
+	a.Score = s
+	return a
+}
+
+// Name implements the Operator interface.
+func (a *AutocompleteOperator) Name() string { return "autocomplete" }
+
+// Document implements the Operator interface.
+func (a *AutocompleteOperator) Document() bson.D {
+	doc := bson.D{
+		{Key: "query", Value: a.Query},
+		{Key: "path", Value: a.Path},
+	}
+	if a.TokenOrder != "" {
+		doc = append(doc, bson.E{Key: "tokenOrder", Value: a.TokenOrder})
+	}
+	if a.Fuzzy != nil {
+		doc = append(doc, bson.E{Key: "fuzzy", Value: a.Fuzzy.document()})
+	}
+	return appendScore(doc, a.Score)
+}
+
+// EqualsOperator is the equals operator, which checks whether a field matches a value.
+//
+// For more information about the equals operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/equals/
+type EqualsOperator struct {
+	Path  string
+	Value interface{}
+	Score Score
+}
+
+var _ Operator = (*EqualsOperator)(nil)
+
+// Equals creates an equals operator that matches documents where the field at path equals value.
+// The value may be a boolean, ObjectID, number, date, string, UUID, or null.
+func Equals(path string, value interface{}) *EqualsOperator {
+	return &EqualsOperator{Path: path, Value: value}
+}
+
+// SetScore sets the score modifier for the operator.
+func (e *EqualsOperator) SetScore(s Score) *EqualsOperator {
+	e.Score = s
+	return e
+}
+
+// Name implements the Operator interface.
+func (e *EqualsOperator) Name() string { return "equals" }
+
+// Document implements the Operator interface.
+func (e *EqualsOperator) Document() bson.D {
+	doc := bson.D{
+		{Key: "path", Value: e.Path},
+		{Key: "value", Value: e.Value},
+	}
+	return appendScore(doc, e.Score)
+}
+
+// RangeOperator is the range operator, which matches numbers, dates, and strings within a range.
+//
+// For more information about the range operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/range/
+type RangeOperator struct {
+	Path  []string
+	Gt    interface{}
+	Gte   interface{}
+	Lt    interface{}
+	Lte   interface{}
+	Score Score
+}
+
+var _ Operator = (*RangeOperator)(nil)
+
+// Range creates a range operator over the given paths. At least one bound must be set.
+func Range(paths ...string) *RangeOperator {
+	return &RangeOperator{Path: paths}
+}
+
+// SetGt sets an exclusive lower bound.
+func (r *RangeOperator) SetGt(v interface{}) *RangeOperator {
+	r.Gt = v
+	return r
+}
+
+// SetGte sets an inclusive lower bound.
+func (r *RangeOperator) SetGte(v interface{}) *RangeOperator {
+	r.Gte = v
+	return r
+}
+
+// SetLt sets an exclusive upper bound.
+func (r *RangeOperator) SetLt(v interface{}) *RangeOperator {
+	r.Lt = v
+	return r
+}
+
+// SetLte sets an inclusive upper bound.
+func (r *RangeOperator) SetLte(v interface{}) *RangeOperator {
+	r.Lte = v
+	return r
+}
+
+// SetScore sets the score modifier for the operator.
+func (r *RangeOperator) SetScore(s Score) *RangeOperator {
+	r.Score = s
+	return r
+}
+
+// Name implements the Operator interface.
+func (r *RangeOperator) Name() string { return "range" }
+
+// Document implements the Operator interface.
+func (r *RangeOperator) Document() bson.D {
+	doc := bson.D{{Key: "path", Value: stringOrArray(r.Path)}}
+	if r.Gt != nil {
+		doc = append(doc, bson.E{Key: "gt", Value: r.Gt})
+	}
+	if r.Gte != nil {
+		doc = append(doc, bson.E{Key: "gte", Value: r.Gte})
+	}
+	if r.Lt != nil {
+		doc = append(doc, bson.E{Key: "lt", Value: r.Lt})
+	}
+	if r.Lte != nil {
+		doc = append(doc, bson.E{Key: "lte", Value: r.Lte})
+	}
+	return appendScore(doc, r.Score)
+}
+
+// ExistsOperator is the exists operator, which matches documents that contain an indexed field.
+//
+// For more information about the exists operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/exists/
+type ExistsOperator struct {
+	Path  string
+	Score Score
+}
+
+var _ Operator = (*ExistsOperator)(nil)
+
+// Exists creates an exists operator for the field at path.
+func Exists(path string) *ExistsOperator {
+	return &ExistsOperator{Path: path}
+}
+
+// SetScore sets the score modifier for the operator.
+func (e *ExistsOperator) SetScore(s Score) *ExistsOperator {
+	e.Score = s
+	return e
+}
+
+// Name implements the Operator interface.
+func (e *ExistsOperator) Name() string { return "exists" }
+
+// Document implements the Operator interface.
+func (e *ExistsOperator) Document() bson.D {
+	return appendScore(bson.D{{Key: "path", Value: e.Path}}, e.Score)
+}
+
+// CompoundOperator is the compound operator, which combines other operators into a single query.
+//
+// For more information about the compound operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/compound/
+type CompoundOperator struct {
+	MustClauses        []Operator
+	MustNotClauses     []Operator
+	ShouldClauses      []Operator
+	FilterClauses      []Operator
+	MinimumShouldMatch *int32
+	Score              Score
+}
+
+var _ Operator = (*CompoundOperator)(nil)
+
+// Compound creates an empty compound operator. At least one clause must be added.
+func Compound() *CompoundOperator {
+	return &CompoundOperator{}
+}
+
+// Must adds clauses that must match for a document to be included in the results.
+func (c *CompoundOperator) Must(ops ...Operator) *CompoundOperator {
+	c.MustClauses = append(c.MustClauses, ops...)
+	return c
+}
+
+// MustNot adds clauses that must not match for a document to be included in the results.
+func (c *CompoundOperator) MustNot(ops ...Operator) *CompoundOperator {
+	c.MustNotClauses = append(c.MustNotClauses, ops...)
+	return c
+}
+
+// Should adds clauses that documents are preferred to match. Documents that match more Should
+// clauses have higher scores.
+func (c *CompoundOperator) Should(ops ...Operator) *CompoundOperator {
+	c.ShouldClauses = append(c.ShouldClauses, ops...)
+	return c
+}
+
+// Filter adds clauses that must match for a document to be included in the results but that do
+// not contribute to the score.
+func (c *CompoundOperator) Filter(ops ...Operator) *CompoundOperator {
+	c.FilterClauses = append(c.FilterClauses, ops...)
+	return c
+}
+
+// SetMinimumShouldMatch sets the minimum number of Should clauses that must match.
+func (c *CompoundOperator) SetMinimumShouldMatch(n int32) *CompoundOperator {
+	c.MinimumShouldMatch = &n
+	return c
+}
+
+// SetScore sets the score modifier for the operator.
+func (c *CompoundOperator) SetScore(s Score) *CompoundOperator {
+	c.Score = s
+	return c
+}
+
+// Name implements the Operator interface.
+func (c *CompoundOperator) Name() string { return "compound" }
+
+// Document implements the Operator interface.
+func (c *CompoundOperator) Document() bson.D {
+	doc := bson.D{}
+	doc = appendClauses(doc, "must", c.MustClauses)
+	doc = appendClauses(doc, "mustNot", c.MustNotClauses)
+	doc = appendClauses(doc, "should", c.ShouldClauses)
+	doc = appendClauses(doc, "filter", c.FilterClauses)
+	if c.MinimumShouldMatch != nil {
+		doc = append(doc, bson.E{Key: "minimumShouldMatch", Value: *c.MinimumShouldMatch})
+	}
+	return appendScore(doc, c.Score)
+}
+
+// KnnBetaOperator is the knnBeta operator, which performs a k-nearest neighbor search on vector
+// fields. New applications should use the $vectorSearch stage instead.
+//
+// For more information about the knnBeta operator, see
+// https://www.mongodb.com/docs/atlas/atlas-search/knn-beta/
+type KnnBetaOperator struct {
+	Path   string
+	Vector interface{}
+	K      int64
+	Filter Operator
+	Score  Score
+}
+
+var _ Operator = (*KnnBetaOperator)(nil)
+
+// KnnBeta creates a knnBeta operator that returns the k nearest neighbors of vector in the field at
+// path.
+func KnnBeta(path string, vector interface{}, k int64) *KnnBetaOperator {
+	return &KnnBetaOperator{Path: path, Vector: vector, K: k}
+}
+
+// SetFilter sets an operator used to prefilter the documents that are considered.
+func (k *KnnBetaOperator) SetFilter(op Operator) *KnnBetaOperator {
+	k.Filter = op
+	return k
+}
+
+// SetScore sets the score modifier for the operator.
+func (k *KnnBetaOperator) SetScore(s Score) *KnnBetaOperator {
+	k.Score = s
+	return k
+}
+
+// Name implements the Operator interface.
+func (k *KnnBetaOperator) Name() string { return "knnBeta" }
+
+// Document implements the Operator interface.
+func (k *KnnBetaOperator) Document() bson.D {
+	doc := bson.D{
+		{Key: "path", Value: k.Path},
+		{Key: "vector", Value: k.Vector},
+		{Key: "k", Value: k.K},
+	}
+	if k.Filter != nil {
+		doc = append(doc, bson.E{Key: "filter", Value: bson.D{{Key: k.Filter.Name(), Value: k.Filter.Document()}}})
+	}
+	return appendScore(doc, k.Score)
+}
+
+func appendScore(doc bson.D, s Score) bson.D {
+	if len(s) == 0 {
+		return doc
+	}
+	return append(doc, bson.E{Key: "score", Value: bson.D(s)})
+}
+
+func appendClauses(doc bson.D, key string, ops []Operator) bson.D {
+	if len(ops) == 0 {
+		return doc
+	}
+	clauses := make(bson.A, 0, len(ops))
+	for _, op := range ops {
+		clauses = append(clauses, bson.D{{Key: op.Name(), Value: op.Document()}})
+	}
+	return append(doc, bson.E{Key: key, Value: clauses})
+}
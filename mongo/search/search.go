@@ -0,0 +1,290 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package search provides typed builders for the Atlas Search $search, $searchMeta, and
+// $vectorSearch aggregation stages.
+//
+// Each stage builder produces a bson.D that can be used directly as an element of a
+// mongo.Pipeline:
+//
+//	pipeline := mongo.Pipeline{
+//		search.NewSearch(search.Text("coffee", "title")).SetIndex("default").Stage(),
+//		{{"$limit", 10}},
+//	}
+//
+// For more information about Atlas Search, see
+// https://www.mongodb.com/docs/atlas/atlas-search/
+package search
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Count types that can be used with the count option of the $search and $searchMeta stages.
+const (
+	CountLowerBound = "lowerBound"
+	CountTotal      = "total"
+)
+
+// Search builds a $search aggregation stage.
+//
+// For more information about the $search stage, see
+// https://www.mongodb.com/docs/atlas/atlas-search/aggregation-stages/search/
+type Search struct {
+	Index              string
+	Operator           Operator
+	Highlight          *Highlight
+	Count              string
+	ReturnStoredSource *bool
+	ScoreDetails       *bool
+	Concurrent         *bool
+	Sort               interface{}
+}
+
+// NewSearch creates a new Search stage that evaluates the given operator.
+func NewSearch(op Operator) *Search {
+	return &Search{Operator: op}
+}
+
+// SetIndex sets the name of the Atlas Search index to use. If unset, the server uses the index named
+// "default".
+func (s *Search) SetIndex(index string) *Search {
+	s.Index = index
+	return s
+}
+
+// SetHighlight specifies that the results should contain highlighted search terms for the given
+// paths.
+func (s *Search) SetHighlight(h *Highlight) *Search {
+	s.Highlight = h
+	return s
+}
+
+// SetCount specifies the type of count of the documents in the result set. Valid values are
+// CountLowerBound and CountTotal.
+func (s *Search) SetCount(countType string) *Search {
+	s.Count = countType
+	return s
+}
+
+// SetReturnStoredSource specifies whether to perform a full document lookup on the backend database
+// or return only the fields stored in Atlas Search.
+func (s *Search) SetReturnStoredSource(b bool) *Search {
+	s.ReturnStoredSource = &b
+	return s
+}
+
+// SetScoreDetails specifies whether to retrieve a detailed breakdown of the score for each document.
+func (s *Search) SetScoreDetails(b bool) *Search {
+	s.ScoreDetails = &b
+	return s
+}
+
+// SetConcurrent specifies whether the query should be parallelized across segments on dedicated
+// search nodes.
+func (s *Search) SetConcurrent(b bool) *Search {
+	s.Concurrent = &b
+	return s
+}
+
+// SetSort specifies the fields to sort the results by. It must be a document.
+func (s *Search) SetSort(sort interface{}) *Search {
+	s.Sort = sort
+	return s
+}
+
+// Stage returns the $search stage as a document that can be used in a mongo.Pipeline.
+func (s *Search) Stage() bson.D {
+	body := bson.D{}
+	if s.Index != "" {
+		body = append(body, bson.E{Key: "index", Value: s.Index})
+	}
+	body = appendOperator(body, s.Operator)
+	if s.Highlight != nil {
+		body = append(body, bson.E{Key: "highlight", Value: s.Highlight.document()})
+	}
+	if s.Count != "" {
+		body = append(body, bson.E{Key: "count", Value: bson.D{{Key: "type", Value: s.Count}}})
+	}
+	if s.ReturnStoredSource != nil {
+		body = append(body, bson.E{Key: "returnStoredSource", Value: *s.ReturnStoredSource})
+	}
+	if s.ScoreDetails != nil {
+		body = append(body, bson.E{Key: "scoreDetails", Value: *s.ScoreDetails})
+	}
+	if s.Concurrent != nil {
+		body = append(body, bson.E{Key: "concurrent", Value: *s.Concurrent})
+	}
+	if s.Sort != nil {
+		body = append(body, bson.E{Key: "sort", Value: s.Sort})
+	}
+	return bson.D{{Key: "$search", Value: body}}
+}
+
+// SearchMeta builds a $searchMeta aggregation stage, which returns metadata such as counts and
+// facets rather than documents.
+//
+// For more information about the $searchMeta stage, see
+// https://www.mongodb.com/docs/atlas/atlas-search/aggregation-stages/searchMeta/
+type SearchMeta struct {
+	Index    string
+	Operator Operator
+	Count    string
+}
+
+// NewSearchMeta creates a new SearchMeta stage that evaluates the given operator.
+func NewSearchMeta(op Operator) *SearchMeta {
+	return &SearchMeta{Operator: op}
+}
+
+// SetIndex sets the name of the Atlas Search index to use.
+func (s *SearchMeta) SetIndex(index string) *SearchMeta {
+	s.Index = index
+	return s
+}
+
+// SetCount specifies the type of count of the documents in the result set. Valid values are
+// CountLowerBound and CountTotal.
+func (s *SearchMeta) SetCount(countType string) *SearchMeta {
+	s.Count = countType
+	return s
+}
+
+// Stage returns the $searchMeta stage as a document that can be used in a mongo.Pipeline.
+func (s *SearchMeta) Stage() bson.D {
+	body := bson.D{}
+	if s.Index != "" {
+		body = append(body, bson.E{Key: "index", Value: s.Index})
+	}
+	body = appendOperator(body, s.Operator)
+	if s.Count != "" {
+		body = append(body, bson.E{Key: "count", Value: bson.D{{Key: "type", Value: s.Count}}})
+	}
+	return bson.D{{Key: "$searchMeta", Value: body}}
+}
+
+// VectorSearch builds a $vectorSearch aggregation stage.
+//
+// For more information about the $vectorSearch stage, see
+// https://www.mongodb.com/docs/atlas/atlas-vector-search/vector-search-stage/
+type VectorSearch struct {
+	Index         string
+	Path          string
+	QueryVector   interface{}
+	NumCandidates *int64
+	Limit         int64
+	Filter        interface{}
+	Exact         *bool
+}
+
+// NewVectorSearch creates a new VectorSearch stage that returns at most limit documents whose
+// vectors at path are nearest to queryVector. The queryVector must be an array of numbers, such
+// as a []float64 or []float32.
+func NewVectorSearch(index, path string, queryVector interface{}, limit int64) *VectorSearch {
+	return &VectorSearch{
+		Index:       index,
+		Path:        path,
+		QueryVector: queryVector,
+		Limit:       limit,
+	}
+}
+
+// SetNumCandidates sets the number of nearest neighbors to consider during an approximate
+// nearest neighbor search.
+func (v *VectorSearch) SetNumCandidates(n int64) *VectorSearch {
+	v.NumCandidates = &n
+	return v
+}
+
+// SetFilter sets an MQL filter that is applied to indexed filter fields before the vector search.
+// It must be a document.
+func (v *VectorSearch) SetFilter(filter interface{}) *VectorSearch {
+	v.Filter = filter
+	return v
+}
+
+// SetExact specifies whether to run an exact nearest neighbor search instead of an approximate one.
+func (v *VectorSearch) SetExact(b bool) *VectorSearch {
+	v.Exact = &b
+	return v
+}
+
+// Stage returns the $vectorSearch stage as a document that can be used in a mongo.Pipeline.
+func (v *VectorSearch) Stage() bson.D {
+	body := bson.D{
+		{Key: "index", Value: v.Index},
+		{Key: "path", Value: v.Path},
+		{Key: "queryVector", Value: v.QueryVector},
+	}
+	if v.NumCandidates != nil {
+		body = append(body, bson.E{Key: "numCandidates", Value: *v.NumCandidates})
+	}
+	body = append(body, bson.E{Key: "limit", Value: v.Limit})
+	if v.Filter != nil {
+		body = append(body, bson.E{Key: "filter", Value: v.Filter})
+	}
+	if v.Exact != nil {
+		body = append(body, bson.E{Key: "exact", Value: *v.Exact})
+	}
+	return bson.D{{Key: "$vectorSearch", Value: body}}
+}
+
+// Highlight configures the highlight option of a $search stage.
+type Highlight struct {
+	Paths             []string
+	MaxCharsToExamine *int64
+	MaxNumPassages    *int64
+}
+
+// NewHighlight creates a new Highlight for the given paths.
+func NewHighlight(paths ...string) *Highlight {
+	return &Highlight{Paths: paths}
+}
+
+// SetMaxCharsToExamine sets the maximum number of characters to examine on a document when
+// performing highlighting for a field.
+func (h *Highlight) SetMaxCharsToExamine(n int64) *Highlight {
+	h.MaxCharsToExamine = &n
+	return h
+}
+
+// SetMaxNumPassages sets the number of high-scoring passages to return per document in the
+// highlights results for each field.
+func (h *Highlight) SetMaxNumPassages(n int64) *Highlight {
+	h.MaxNumPassages = &n
+	return h
+}
+
+func (h *Highlight) document() bson.D {
+	doc := bson.D{{Key: "path", Value: stringOrArray(h.Paths)}}
+	if h.MaxCharsToExamine != nil {
+		doc = append(doc, bson.E{Key: "maxCharsToExamine", Value: *h.MaxCharsToExamine})
+	}
+	if h.MaxNumPassages != nil {
+		doc = append(doc, bson.E{Key: "maxNumPassages", Value: *h.MaxNumPassages})
+	}
+	return doc
+}
+
+func appendOperator(doc bson.D, op Operator) bson.D {
+	if op == nil {
+		return doc
+	}
+	return append(doc, bson.E{Key: op.Name(), Value: op.Document()})
+}
+
+// stringOrArray returns a single value as a string and multiple values as an array, matching the forms
+// accepted by the server for options such as "path" and "query".
+func stringOrArray(paths []string) interface{} {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	arr := make(bson.A, 0, len(paths))
+	for _, p := range paths {
+		arr = append(arr, p)
+	}
+	return arr
+}
@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package search
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestStages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stage bson.D
+		want  string
+	}{
+		{
+			name:  "search with text",
+			stage: NewSearch(Text("coffee", "title")).SetIndex("default").Stage(),
+			want:  `{"$search": {"index": "default", "text": {"query": "coffee", "path": "title"}}}`,
+		},
+		{
+			name: "search with fuzzy text and options",
+			stage: NewSearch(Text("coffee", "title", "plot").
+				SetFuzzy(NewFuzzy().SetMaxEdits(1)).
+				SetScore(BoostScore(2))).
+				SetHighlight(NewHighlight("plot").SetMaxNumPassages(3)).
+				SetCount(CountTotal).
+				SetReturnStoredSource(true).
+				Stage(),
+			want: `{"$search": {` +
+				`"text": {"query": "coffee", "path": ["title", "plot"], "fuzzy": {"maxEdits": {"$numberInt": "1"}}, "score": {"boost": {"value": {"$numberDouble": "2.0"}}}}, ` +
+				`"highlight": {"path": "plot", "maxNumPassages": {"$numberLong": "3"}}, ` +
+				`"count": {"type": "total"}, "returnStoredSource": true}}`,
+		},
+		{
+			name: "search with compound",
+			stage: NewSearch(Compound().
+				Must(Text("coffee", "title")).
+				MustNot(Equals("sold", true)).
+				Should(Phrase("dark roast", "plot").SetSlop(2)).
+				Filter(Range("price").SetGte(1).SetLt(10), Exists("stock")).
+				SetMinimumShouldMatch(1)).
+				Stage(),
+			want: `{"$search": {"compound": {` +
+				`"must": [{"text": {"query": "coffee", "path": "title"}}], ` +
+				`"mustNot": [{"equals": {"path": "sold", "value": true}}], ` +
+				`"should": [{"phrase": {"query": "dark roast", "path": "plot", "slop": {"$numberInt": "2"}}}], ` +
+				`"filter": [{"range": {"path": "price", "gte": {"$numberInt": "1"}, "lt": {"$numberInt": "10"}}}, {"exists": {"path": "stock"}}], ` +
+				`"minimumShouldMatch": {"$numberInt": "1"}}}}`,
+		},
+		{
+			name:  "search with knnBeta",
+			stage: NewSearch(KnnBeta("embedding", []float64{0.5, 1}, 5).SetFilter(Equals("genre", "drama"))).Stage(),
+			want: `{"$search": {"knnBeta": {"path": "embedding", "vector": [{"$numberDouble": "0.5"}, {"$numberDouble": "1.0"}], ` +
+				`"k": {"$numberLong": "5"}, "filter": {"equals": {"path": "genre", "value": "drama"}}}}}`,
+		},
+		{
+			name:  "searchMeta",
+			stage: NewSearchMeta(Autocomplete("cof", "title")).SetIndex("idx").SetCount(CountLowerBound).Stage(),
+			want:  `{"$searchMeta": {"index": "idx", "autocomplete": {"query": "cof", "path": "title"}, "count": {"type": "lowerBound"}}}`,
+		},
+		{
+			name: "vectorSearch",
+			stage: NewVectorSearch("vector_index", "embedding", []float64{0.5}, 10).
+				SetNumCandidates(100).
+				SetFilter(bson.D{{"year", bson.D{{"$gt", 2000}}}}).
+				Stage(),
+			want: `{"$vectorSearch": {"index": "vector_index", "path": "embedding", "queryVector": [{"$numberDouble": "0.5"}], ` +
+				`"numCandidates": {"$numberLong": "100"}, "limit": {"$numberLong": "10"}, "filter": {"year": {"$gt": {"$numberInt": "2000"}}}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bson.Marshal(tc.stage)
+			require.NoError(t, err, "Marshal error: %v", err)
+
+			var want bson.Raw
+			err = bson.UnmarshalExtJSON([]byte(tc.want), true, &want)
+			require.NoError(t, err, "UnmarshalExtJSON error: %v", err)
+
+			assert.Equal(t, want.String(), bson.Raw(got).String(), "stage documents do not match")
+		})
+	}
+}
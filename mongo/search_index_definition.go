@@ -0,0 +1,257 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SearchIndexDefinition is the definition of an Atlas Search index. It can be used as the
+// Definition of a SearchIndexModel or passed to SearchIndexView.UpdateOne. Call Validate to check
+// the definition before sending it to the server, which only reports some mistakes, such as an
+// undefined analyzer, after the index build fails.
+//
+// For more information about search index definitions, see
+// https://www.mongodb.com/docs/atlas/atlas-search/index-definitions/.
+type SearchIndexDefinition struct {
+	// Analyzer is the analyzer applied to string fields when indexing. The default is
+	// "lucene.standard".
+	Analyzer string `bson:"analyzer,omitempty"`
+
+	// SearchAnalyzer is the analyzer applied to query text. The default is Analyzer.
+	SearchAnalyzer string `bson:"searchAnalyzer,omitempty"`
+
+	// Mappings specifies how fields are indexed.
+	Mappings SearchMappings `bson:"mappings"`
+
+	// Analyzers are the custom analyzers that the index can use.
+	Analyzers []SearchAnalyzer `bson:"analyzers,omitempty"`
+
+	// StoredSource specifies the fields stored on Atlas Search. It can be true to store all
+	// fields, false to store none, or a SearchStoredSource.
+	StoredSource interface{} `bson:"storedSource,omitempty"`
+
+	// Synonyms are the synonym mappings that queries can use.
+	Synonyms []SearchSynonymMapping `bson:"synonyms,omitempty"`
+
+	// NumPartitions is the number of sub-indexes to create for the index, if more than 1.
+	NumPartitions int32 `bson:"numPartitions,omitempty"`
+}
+
+// SearchMappings specifies how the fields of a search index are indexed.
+type SearchMappings struct {
+	// Dynamic indexes all fields of supported types. If it is false, Fields must be set.
+	Dynamic bool `bson:"dynamic"`
+
+	// Fields are the mappings of specific fields, by name.
+	Fields map[string]SearchField `bson:"fields,omitempty"`
+}
+
+// SearchField is the mapping of a field in a search index. To index a field as more than one
+// type, use a bson.D definition instead of a SearchIndexDefinition.
+type SearchField struct {
+	// Type is the field type, such as "string", "number", "date", "document", or "autocomplete".
+	Type string `bson:"type"`
+
+	// Analyzer and SearchAnalyzer override the analyzers of the index for a string field.
+	Analyzer       string `bson:"analyzer,omitempty"`
+	SearchAnalyzer string `bson:"searchAnalyzer,omitempty"`
+
+	// Dynamic indexes all fields of a document field.
+	Dynamic *bool `bson:"dynamic,omitempty"`
+
+	// Fields are the mappings of the fields of a document field.
+	Fields map[string]SearchField `bson:"fields,omitempty"`
+
+	// Multi are alternate analyzers for a string field, by name.
+	Multi map[string]SearchField `bson:"multi,omitempty"`
+
+	// Options are other options of the field type, such as "ignoreAbove" or "minGrams".
+	Options map[string]interface{} `bson:",inline"`
+}
+
+// SearchAnalyzer is a custom analyzer of a search index.
+type SearchAnalyzer struct {
+	// Name is the name of the analyzer. It cannot start with "lucene." or "builtin.".
+	Name string `bson:"name"`
+
+	// CharFilters are applied to the text before it is tokenized.
+	CharFilters []SearchAnalyzerComponent `bson:"charFilters,omitempty"`
+
+	// Tokenizer splits the text into tokens. It is required.
+	Tokenizer SearchAnalyzerComponent `bson:"tokenizer"`
+
+	// TokenFilters are applied to the tokens.
+	TokenFilters []SearchAnalyzerComponent `bson:"tokenFilters,omitempty"`
+}
+
+// SearchAnalyzerComponent is a character filter, tokenizer, or token filter of a custom
+// analyzer, such as {type: "edgeGram", minGram: 2, maxGram: 10}.
+type SearchAnalyzerComponent struct {
+	// Type is the type of the component, such as "standard" or "lowercase".
+	Type string `bson:"type"`
+
+	// Options are the options of the component.
+	Options map[string]interface{} `bson:",inline"`
+}
+
+// SearchStoredSource specifies the fields stored on Atlas Search. Only one of Include and Exclude
+// can be set.
+type SearchStoredSource struct {
+	Include []string `bson:"include,omitempty"`
+	Exclude []string `bson:"exclude,omitempty"`
+}
+
+// SearchSynonymMapping maps the synonyms in a collection to an analyzer.
+type SearchSynonymMapping struct {
+	// Name is the name used to refer to the mapping in queries.
+	Name string `bson:"name"`
+
+	// Analyzer is the analyzer used with the mapping. It must be the analyzer of the fields
+	// queried with the mapping.
+	Analyzer string `bson:"analyzer"`
+
+	// Source is the collection that contains the synonyms.
+	Source SearchSynonymSource `bson:"source"`
+}
+
+// SearchSynonymSource is the source collection of a synonym mapping.
+type SearchSynonymSource struct {
+	Collection string `bson:"collection"`
+}
+
+// isBuiltinAnalyzer reports whether name is the name of an analyzer provided by Atlas Search.
+func isBuiltinAnalyzer(name string) bool {
+	return strings.HasPrefix(name, "lucene.")
+}
+
+// Validate checks that the definition has mappings, that the analyzers it refers to are built-in
+// or custom analyzers of the index, that custom analyzers have a tokenizer, and that synonym
+// mappings and the stored source are complete.
+func (d SearchIndexDefinition) Validate() error {
+	analyzers := make(map[string]bool, len(d.Analyzers))
+	for i, a := range d.Analyzers {
+		switch {
+		case a.Name == "":
+			return fmt.Errorf("analyzer %d has no name", i)
+		case isBuiltinAnalyzer(a.Name) || strings.HasPrefix(a.Name, "builtin."):
+			return fmt.Errorf("custom analyzer name %q cannot use a reserved prefix", a.Name)
+		case analyzers[a.Name]:
+			return fmt.Errorf("analyzer %q is defined more than once", a.Name)
+		case a.Tokenizer.Type == "":
+			return fmt.Errorf("analyzer %q has no tokenizer", a.Name)
+		}
+		for _, c := range append(append([]SearchAnalyzerComponent{}, a.CharFilters...), a.TokenFilters...) {
+			if c.Type == "" {
+				return fmt.Errorf("analyzer %q has a filter without a type", a.Name)
+			}
+		}
+		analyzers[a.Name] = true
+	}
+
+	checkAnalyzer := func(where, name string) error {
+		if name == "" || isBuiltinAnalyzer(name) || analyzers[name] {
+			return nil
+		}
+		return fmt.Errorf("%s refers to undefined analyzer %q", where, name)
+	}
+	if err := checkAnalyzer("index", d.Analyzer); err != nil {
+		return err
+	}
+	if err := checkAnalyzer("index", d.SearchAnalyzer); err != nil {
+		return err
+	}
+
+	if !d.Mappings.Dynamic && len(d.Mappings.Fields) == 0 {
+		return errors.New("static mappings must define at least one field")
+	}
+	if err := validateSearchFields("", d.Mappings.Fields, checkAnalyzer); err != nil {
+		return err
+	}
+
+	for i, s := range d.Synonyms {
+		switch {
+		case s.Name == "":
+			return fmt.Errorf("synonym mapping %d has no name", i)
+		case s.Source.Collection == "":
+			return fmt.Errorf("synonym mapping %q has no source collection", s.Name)
+		case s.Analyzer == "":
+			return fmt.Errorf("synonym mapping %q has no analyzer", s.Name)
+		}
+		if err := checkAnalyzer(fmt.Sprintf("synonym mapping %q", s.Name), s.Analyzer); err != nil {
+			return err
+		}
+	}
+
+	switch ss := d.StoredSource.(type) {
+	case nil, bool:
+	case SearchStoredSource:
+		return validateStoredSource(&ss)
+	case *SearchStoredSource:
+		return validateStoredSource(ss)
+	default:
+		return fmt.Errorf("stored source must be a bool or a SearchStoredSource, got %T", d.StoredSource)
+	}
+	return nil
+}
+
+func validateSearchFields(prefix string, fields map[string]SearchField, checkAnalyzer func(where, name string) error) error {
+	// Sort the names so that the same definition always reports the same error.
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := fields[name]
+		path := prefix + name
+		if f.Type == "" {
+			return fmt.Errorf("field %q has no type", path)
+		}
+		where := fmt.Sprintf("field %q", path)
+		if err := checkAnalyzer(where, f.Analyzer); err != nil {
+			return err
+		}
+		if err := checkAnalyzer(where, f.SearchAnalyzer); err != nil {
+			return err
+		}
+		if len(f.Fields) > 0 || f.Dynamic != nil {
+			if f.Type != "document" && f.Type != "embeddedDocuments" {
+				return fmt.Errorf("field %q of type %q cannot have nested mappings", path, f.Type)
+			}
+		}
+		if len(f.Multi) > 0 && f.Type != "string" {
+			return fmt.Errorf("field %q of type %q cannot have multi analyzers", path, f.Type)
+		}
+		if err := validateSearchFields(path+".", f.Fields, checkAnalyzer); err != nil {
+			return err
+		}
+		for multiName, m := range f.Multi {
+			if err := checkAnalyzer(fmt.Sprintf("multi analyzer %q of field %q", multiName, path), m.Analyzer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateStoredSource(ss *SearchStoredSource) error {
+	if ss == nil {
+		return nil
+	}
+	if len(ss.Include) > 0 && len(ss.Exclude) > 0 {
+		return errors.New("stored source cannot both include and exclude fields")
+	}
+	if len(ss.Include) == 0 && len(ss.Exclude) == 0 {
+		return errors.New("stored source must include or exclude at least one field")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestSearchIndexDefinition(t *testing.T) {
+	t.Parallel()
+
+	valid := func() SearchIndexDefinition {
+		return SearchIndexDefinition{
+			Analyzer: "autocomplete",
+			Mappings: SearchMappings{
+				Fields: map[string]SearchField{
+					"title": {
+						Type:  "string",
+						Multi: map[string]SearchField{"english": {Type: "string", Analyzer: "lucene.english"}},
+					},
+					"meta": {
+						Type:   "document",
+						Fields: map[string]SearchField{"tags": {Type: "token"}},
+					},
+					"name": {Type: "autocomplete", Options: map[string]interface{}{"minGrams": 2}},
+				},
+			},
+			Analyzers: []SearchAnalyzer{{
+				Name:         "autocomplete",
+				Tokenizer:    SearchAnalyzerComponent{Type: "edgeGram", Options: map[string]interface{}{"minGram": 2, "maxGram": 10}},
+				TokenFilters: []SearchAnalyzerComponent{{Type: "lowercase"}},
+			}},
+			StoredSource: SearchStoredSource{Include: []string{"title"}},
+			Synonyms: []SearchSynonymMapping{{
+				Name: "words", Analyzer: "lucene.standard", Source: SearchSynonymSource{Collection: "synonyms"},
+			}},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		def := valid()
+		require.NoError(t, def.Validate(), "Validate error")
+
+		doc, err := bson.Marshal(def)
+		require.NoError(t, err, "Marshal error")
+		raw := bson.Raw(doc)
+		assert.Equal(t, "autocomplete", raw.Lookup("analyzer").StringValue(), "analyzer mismatch")
+		assert.Equal(t, int32(2), raw.Lookup("mappings", "fields", "name", "minGrams").Int32(), "field option mismatch")
+		assert.Equal(t, "edgeGram", raw.Lookup("analyzers", "0", "tokenizer", "type").StringValue(), "tokenizer mismatch")
+		assert.Equal(t, int32(10), raw.Lookup("analyzers", "0", "tokenizer", "maxGram").Int32(), "tokenizer option mismatch")
+		assert.Equal(t, "synonyms", raw.Lookup("synonyms", "0", "source", "collection").StringValue(), "synonym source mismatch")
+		_, err = raw.LookupErr("searchAnalyzer")
+		assert.Error(t, err, "expected searchAnalyzer to be omitted")
+	})
+
+	testCases := []struct {
+		name   string
+		modify func(*SearchIndexDefinition)
+	}{
+		{"static without fields", func(d *SearchIndexDefinition) { d.Mappings.Fields = nil }},
+		{"undefined index analyzer", func(d *SearchIndexDefinition) { d.Analyzer = "missing" }},
+		{"undefined field analyzer", func(d *SearchIndexDefinition) {
+			d.Mappings.Fields["body"] = SearchField{Type: "string", SearchAnalyzer: "missing"}
+		}},
+		{"field without type", func(d *SearchIndexDefinition) { d.Mappings.Fields["body"] = SearchField{} }},
+		{"nested mapping on string", func(d *SearchIndexDefinition) {
+			d.Mappings.Fields["body"] = SearchField{Type: "string", Fields: map[string]SearchField{"x": {Type: "string"}}}
+		}},
+		{"reserved analyzer name", func(d *SearchIndexDefinition) { d.Analyzers[0].Name = "lucene.custom" }},
+		{"duplicate analyzer", func(d *SearchIndexDefinition) { d.Analyzers = append(d.Analyzers, d.Analyzers[0]) }},
+		{"analyzer without tokenizer", func(d *SearchIndexDefinition) { d.Analyzers[0].Tokenizer = SearchAnalyzerComponent{} }},
+		{"synonyms without source", func(d *SearchIndexDefinition) { d.Synonyms[0].Source.Collection = "" }},
+		{"stored source include and exclude", func(d *SearchIndexDefinition) {
+			d.StoredSource = &SearchStoredSource{Include: []string{"a"}, Exclude: []string{"b"}}
+		}},
+		{"stored source type", func(d *SearchIndexDefinition) { d.StoredSource = "all" }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			def := valid()
+			tc.modify(&def)
+			assert.Error(t, def.Validate(), "expected Validate error")
+		})
+	}
+}
+
+func TestWaitForSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	args := &options.WaitForSearchIndexOptions{}
+	interval := time.Millisecond
+	args.InitialInterval = &interval
+
+	poll := func(statuses ...*SearchIndexStatus) func(context.Context) (*SearchIndexStatus, error) {
+		return func(context.Context) (*SearchIndexStatus, error) {
+			s := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			return s, nil
+		}
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		status, err := waitForSearchIndex(context.Background(), "idx", args, poll(
+			nil,
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusPending},
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusBuilding},
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusReady, Queryable: true},
+		))
+		require.NoError(t, err, "waitForSearchIndex error")
+		assert.Equal(t, SearchIndexStatusReady, status.Status, "status mismatch")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		status, err := waitForSearchIndex(context.Background(), "idx", args, poll(
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusBuilding},
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusFailed, Message: "analyzer not found"},
+		))
+		assert.True(t, errors.Is(err, ErrSearchIndexFailed), "expected ErrSearchIndexFailed, got %v", err)
+		assert.Equal(t, SearchIndexStatusFailed, status.Status, "status mismatch")
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		status, err := waitForSearchIndex(ctx, "idx", args, poll(
+			&SearchIndexStatus{Name: "idx", Status: SearchIndexStatusReady, Queryable: false},
+		))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected DeadlineExceeded, got %v", err)
+		require.NotNil(t, status, "expected last status")
+		assert.False(t, status.Queryable, "expected status to not be queryable")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		t.Parallel()
+
+		listErr := errors.New("list failed")
+		_, err := waitForSearchIndex(context.Background(), "idx", args, func(context.Context) (*SearchIndexStatus, error) {
+			return nil, listErr
+		})
+		assert.True(t, errors.Is(err, listErr), "expected list error, got %v", err)
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrSearchIndexFailed is returned by SearchIndexView.WaitForReady if the search index build
+// failed.
+var ErrSearchIndexFailed = errors.New("search index build failed")
+
+// These constants are the statuses of a search index reported by listSearchIndexes.
+const (
+	SearchIndexStatusPending      = "PENDING"
+	SearchIndexStatusBuilding     = "BUILDING"
+	SearchIndexStatusReady        = "READY"
+	SearchIndexStatusFailed       = "FAILED"
+	SearchIndexStatusStale        = "STALE"
+	SearchIndexStatusDeleting     = "DELETING"
+	SearchIndexStatusDoesNotExist = "DOES_NOT_EXIST"
+)
+
+const (
+	defaultSearchIndexInitialInterval = time.Second
+	defaultSearchIndexMaxInterval     = 30 * time.Second
+)
+
+// SearchIndexStatus is the status of a search index returned by listSearchIndexes.
+type SearchIndexStatus struct {
+	ID        string `bson:"id"`
+	Name      string `bson:"name"`
+	Type      string `bson:"type"`
+	Status    string `bson:"status"`
+	Queryable bool   `bson:"queryable"`
+
+	// Message is the reason the index build failed, if any.
+	Message string `bson:"message"`
+
+	// Raw is the document returned by listSearchIndexes, which also contains the latest definition
+	// and the status of the index on each host.
+	Raw bson.Raw `bson:"-"`
+}
+
+// WaitForReady runs the listSearchIndexes command until the search index with the given name is
+// ready or its build fails, waiting between checks with exponential backoff, and returns its last
+// status. Because search indexes are built asynchronously after CreateOne, CreateMany, or
+// UpdateOne return, use WaitForReady before running queries that require the new definition.
+//
+// WaitForReady keeps waiting while the index does not exist yet, and returns once it is READY and
+// queryable. It returns an error wrapping ErrSearchIndexFailed if the index status is FAILED, and
+// the error of ctx if ctx is done first. The opts parameter can be used to specify options for
+// this operation (see the options.WaitForSearchIndexOptions documentation).
+func (siv SearchIndexView) WaitForReady(
+	ctx context.Context,
+	name string,
+	opts ...options.Lister[options.WaitForSearchIndexOptions],
+) (*SearchIndexStatus, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	args, err := mongoutil.NewOptions[options.WaitForSearchIndexOptions](opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return waitForSearchIndex(ctx, name, args, func(ctx context.Context) (*SearchIndexStatus, error) {
+		cursor, err := siv.List(ctx, options.SearchIndexes().SetName(name))
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var status SearchIndexStatus
+			if err := cursor.Decode(&status); err != nil {
+				return nil, err
+			}
+			if status.Name == name {
+				status.Raw = cursor.Current
+				return &status, nil
+			}
+		}
+		return nil, cursor.Err()
+	})
+}
+
+// waitForSearchIndex polls the status of a search index with get, which returns a nil status if
+// the index does not exist.
+func waitForSearchIndex(
+	ctx context.Context,
+	name string,
+	args *options.WaitForSearchIndexOptions,
+	get func(context.Context) (*SearchIndexStatus, error),
+) (*SearchIndexStatus, error) {
+	interval, maxInterval := defaultSearchIndexInitialInterval, defaultSearchIndexMaxInterval
+	if args.InitialInterval != nil {
+		interval = *args.InitialInterval
+	}
+	if args.MaxInterval != nil {
+		maxInterval = *args.MaxInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	var last *SearchIndexStatus
+	for {
+		status, err := get(ctx)
+		if err != nil {
+			return last, err
+		}
+		if status != nil {
+			last = status
+			switch {
+			case status.Status == SearchIndexStatusFailed:
+				if status.Message != "" {
+					return status, fmt.Errorf("%w: index %q: %s", ErrSearchIndexFailed, name, status.Message)
+				}
+				return status, fmt.Errorf("%w: index %q", ErrSearchIndexFailed, name)
+			case status.Status == SearchIndexStatusReady && status.Queryable:
+				return status, nil
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -27,7 +27,8 @@ import (
 // Search index commands are asynchronous and return from the server before
 // the index is successfully updated, created or dropped. In order to determine
 // when an index has been created / updated, users are expected to run the
-// listSearchIndexes repeatedly until index changes appear.
+// listSearchIndexes repeatedly until index changes appear, or to use
+// SearchIndexView.WaitForReady.
 type SearchIndexView struct {
 	coll *Collection
 }
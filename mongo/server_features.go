@@ -0,0 +1,189 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/internal/csot"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// Wire versions of the server releases that introduced the features reported by ServerFeatures.
+const (
+	wireVersion36 = 6
+	wireVersion40 = 7
+	wireVersion42 = 8
+	wireVersion50 = 13
+	wireVersion53 = 16
+	wireVersion60 = 17
+	wireVersion70 = 21
+	wireVersion80 = 25
+)
+
+// ServerFeatures reports the features supported by the servers of a deployment, derived from the
+// wire versions negotiated with them. See Client.ServerFeatures.
+//
+// Each feature is reported as supported only if every data-bearing server of the deployment
+// supports it, so that an operation using the feature works whichever server it is sent to. During
+// a rolling upgrade, a feature is therefore only reported once all servers have been upgraded.
+type ServerFeatures struct {
+	// MinWireVersion and MaxWireVersion are the lowest maxWireVersion and the highest
+	// maxWireVersion reported by the data-bearing servers.
+	MinWireVersion int32
+	MaxWireVersion int32
+
+	// BulkWriteCommand is whether the servers support the bulkWrite command, which writes to
+	// multiple collections in a single command (MongoDB 8.0).
+	BulkWriteCommand bool
+
+	// QueryableEncryption is whether the servers support Queryable Encryption (MongoDB 7.0).
+	QueryableEncryption bool
+
+	// QueryableEncryptionRange is whether the servers support range queries on fields encrypted
+	// with Queryable Encryption (MongoDB 8.0).
+	QueryableEncryptionRange bool
+
+	// SnapshotReads is whether the deployment supports reads with the "snapshot" read concern
+	// outside of transactions, which requires a replica set or sharded cluster (MongoDB 5.0).
+	SnapshotReads bool
+
+	// Transactions is whether the deployment supports multi-document transactions, which
+	// requires a replica set (MongoDB 4.0) or a sharded cluster (MongoDB 4.2).
+	Transactions bool
+
+	// RetryableWrites is whether the deployment supports retryable writes, which requires a
+	// replica set or sharded cluster (MongoDB 3.6).
+	RetryableWrites bool
+
+	// TimeSeriesCollections is whether the servers support time series collections (MongoDB 5.0).
+	TimeSeriesCollections bool
+
+	// ClusteredCollections is whether the servers support clustered collections (MongoDB 5.3).
+	ClusteredCollections bool
+
+	// ChangeStreamPreAndPostImages is whether change streams can include the full document before
+	// and after a change (MongoDB 6.0).
+	ChangeStreamPreAndPostImages bool
+}
+
+// ServerFeatures returns the features supported by the servers of the deployment, so that
+// applications can branch on a feature without running buildInfo and parsing server versions.
+//
+// The features are derived from the latest hello responses of the monitored servers, so they
+// reflect upgrades and downgrades of the deployment as its servers are monitored. If no
+// data-bearing server has been discovered yet, ServerFeatures waits for server selection, which is
+// bounded by ctx and the server selection timeout of the Client.
+func (c *Client) ServerFeatures(ctx context.Context) (*ServerFeatures, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if topo, ok := c.deployment.(*topology.Topology); ok {
+		if features, ok := newServerFeatures(topo.Description().Servers); ok {
+			return features, nil
+		}
+	}
+
+	// No data-bearing server with a known wire version has been discovered yet, or the deployment
+	// is not monitored, e.g. with a load balancer. Use the description of a connection instead.
+	ctx, cancel := csot.WithServerSelectionTimeout(ctx, c.deployment.GetServerSelectionTimeout())
+	defer cancel()
+
+	server, err := c.deployment.SelectServer(ctx, &serverselector.ReadPref{ReadPref: readpref.Nearest()})
+	if err != nil {
+		return nil, fmt.Errorf("error selecting server to check server features: %w", replaceErrors(err))
+	}
+	conn, err := server.Connection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting connection to check server features: %w", replaceErrors(err))
+	}
+	defer conn.Close()
+
+	if topo, ok := c.deployment.(*topology.Topology); ok {
+		if features, ok := newServerFeatures(topo.Description().Servers); ok {
+			return features, nil
+		}
+	}
+	if features, ok := newServerFeatures([]description.Server{conn.Description()}); ok {
+		return features, nil
+	}
+	return nil, errors.New("server features are unknown because the server did not report its wire version")
+}
+
+// newServerFeatures returns the features supported by all of the given servers that are
+// data-bearing and have a known wire version, or false if there is no such server.
+func newServerFeatures(servers []description.Server) (*ServerFeatures, bool) {
+	var features *ServerFeatures
+	for _, s := range servers {
+		if s.WireVersion == nil || !isDataBearing(s.Kind) {
+			continue
+		}
+		sf := serverFeatures(s)
+		if features == nil {
+			features = sf
+			continue
+		}
+		if sf.MaxWireVersion < features.MinWireVersion {
+			features.MinWireVersion = sf.MaxWireVersion
+		}
+		if sf.MaxWireVersion > features.MaxWireVersion {
+			features.MaxWireVersion = sf.MaxWireVersion
+		}
+		features.BulkWriteCommand = features.BulkWriteCommand && sf.BulkWriteCommand
+		features.QueryableEncryption = features.QueryableEncryption && sf.QueryableEncryption
+		features.QueryableEncryptionRange = features.QueryableEncryptionRange && sf.QueryableEncryptionRange
+		features.SnapshotReads = features.SnapshotReads && sf.SnapshotReads
+		features.Transactions = features.Transactions && sf.Transactions
+		features.RetryableWrites = features.RetryableWrites && sf.RetryableWrites
+		features.TimeSeriesCollections = features.TimeSeriesCollections && sf.TimeSeriesCollections
+		features.ClusteredCollections = features.ClusteredCollections && sf.ClusteredCollections
+		features.ChangeStreamPreAndPostImages = features.ChangeStreamPreAndPostImages && sf.ChangeStreamPreAndPostImages
+	}
+	return features, features != nil
+}
+
+// serverFeatures returns the features supported by the server s.
+func serverFeatures(s description.Server) *ServerFeatures {
+	wv := s.WireVersion.Max
+	replicated := s.Kind != description.ServerKindStandalone
+
+	transactions := false
+	switch s.Kind {
+	case description.ServerKindRSPrimary, description.ServerKindRSSecondary:
+		transactions = wv >= wireVersion40
+	case description.ServerKindMongos, description.ServerKindLoadBalancer:
+		transactions = wv >= wireVersion42
+	}
+
+	return &ServerFeatures{
+		MinWireVersion:               wv,
+		MaxWireVersion:               wv,
+		BulkWriteCommand:             wv >= wireVersion80,
+		QueryableEncryption:          wv >= wireVersion70 && replicated,
+		QueryableEncryptionRange:     wv >= wireVersion80 && replicated,
+		SnapshotReads:                wv >= wireVersion50 && replicated,
+		Transactions:                 transactions,
+		RetryableWrites:              wv >= wireVersion36 && replicated,
+		TimeSeriesCollections:        wv >= wireVersion50,
+		ClusteredCollections:         wv >= wireVersion53,
+		ChangeStreamPreAndPostImages: wv >= wireVersion60 && replicated,
+	}
+}
+
+func isDataBearing(kind description.ServerKind) bool {
+	switch kind {
+	case description.ServerKindStandalone, description.ServerKindRSPrimary, description.ServerKindRSSecondary,
+		description.ServerKindMongos, description.ServerKindLoadBalancer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestNewServerFeatures(t *testing.T) {
+	t.Parallel()
+
+	server := func(kind description.ServerKind, maxWireVersion int32) description.Server {
+		return description.Server{Kind: kind, WireVersion: &description.VersionRange{Max: maxWireVersion}}
+	}
+
+	testCases := []struct {
+		name    string
+		servers []description.Server
+		want    *ServerFeatures
+	}{
+		{
+			name:    "no known servers",
+			servers: []description.Server{{Kind: description.ServerKindRSPrimary}, {Kind: description.Unknown}},
+		},
+		{
+			name:    "replica set 8.0",
+			servers: []description.Server{server(description.ServerKindRSPrimary, 25), server(description.ServerKindRSSecondary, 25)},
+			want: &ServerFeatures{
+				MinWireVersion:               25,
+				MaxWireVersion:               25,
+				BulkWriteCommand:             true,
+				QueryableEncryption:          true,
+				QueryableEncryptionRange:     true,
+				SnapshotReads:                true,
+				Transactions:                 true,
+				RetryableWrites:              true,
+				TimeSeriesCollections:        true,
+				ClusteredCollections:         true,
+				ChangeStreamPreAndPostImages: true,
+			},
+		},
+		{
+			name: "rolling upgrade",
+			servers: []description.Server{
+				server(description.ServerKindRSPrimary, 25),
+				server(description.ServerKindRSSecondary, 21),
+				server(description.ServerKindRSArbiter, 6),
+			},
+			want: &ServerFeatures{
+				MinWireVersion:               21,
+				MaxWireVersion:               25,
+				QueryableEncryption:          true,
+				SnapshotReads:                true,
+				Transactions:                 true,
+				RetryableWrites:              true,
+				TimeSeriesCollections:        true,
+				ClusteredCollections:         true,
+				ChangeStreamPreAndPostImages: true,
+			},
+		},
+		{
+			name:    "sharded 4.0",
+			servers: []description.Server{server(description.ServerKindMongos, 7)},
+			want: &ServerFeatures{
+				MinWireVersion:  7,
+				MaxWireVersion:  7,
+				RetryableWrites: true,
+			},
+		},
+		{
+			name:    "standalone 8.0",
+			servers: []description.Server{server(description.ServerKindStandalone, 25)},
+			want: &ServerFeatures{
+				MinWireVersion:        25,
+				MaxWireVersion:        25,
+				BulkWriteCommand:      true,
+				TimeSeriesCollections: true,
+				ClusteredCollections:  true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := newServerFeatures(tc.servers)
+			assert.Equal(t, tc.want != nil, ok, "expected features to be known: %v", tc.want != nil)
+			assert.Equal(t, tc.want, got, "ServerFeatures mismatch")
+		})
+	}
+}
+
+func TestClientServerFeatures(t *testing.T) {
+	t.Parallel()
+
+	clientOpts := options.Client()
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = drivertest.NewMockDeployment()
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+
+	features, err := client.ServerFeatures(context.Background())
+	require.NoError(t, err, "ServerFeatures error")
+	assert.Equal(t, int32(driverutil.MaxWireVersion), features.MaxWireVersion, "MaxWireVersion mismatch")
+	assert.True(t, features.Transactions, "expected the mock replica set primary to support transactions")
+}
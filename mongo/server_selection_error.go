@@ -0,0 +1,136 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/tag"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// ServerSelectionError is returned when no server could be selected for an operation, e.g.
+// because server selection timed out or the read preference matches no server. It carries a
+// snapshot of the state of every known server when selection failed, so that applications can
+// tell causes apart without parsing the error message:
+//
+//	var sse mongo.ServerSelectionError
+//	if errors.As(err, &sse) {
+//		for _, s := range sse.Servers {
+//			var dnsErr *net.DNSError
+//			if errors.As(s.LastError, &dnsErr) {
+//				// The host of s could not be resolved.
+//			}
+//		}
+//	}
+type ServerSelectionError struct {
+	// TopologyKind is the kind of the topology, e.g. "ReplicaSetNoPrimary".
+	TopologyKind string
+
+	// SetName is the name of the replica set, if any.
+	SetName string
+
+	// Servers are the states of the known servers.
+	Servers []ServerState
+
+	// Wrapped is the underlying error, e.g. a context.DeadlineExceeded error if server selection
+	// timed out.
+	Wrapped error
+}
+
+// ServerState is the state of a server when server selection failed.
+type ServerState struct {
+	Addr address.Address
+
+	// Kind is the kind of the server, e.g. "RSSecondary", or "Unknown" if the server could not be
+	// checked.
+	Kind string
+
+	SetName string
+	Tags    tag.Set
+
+	// LastError is the error of the last check of the server, e.g. a DNS or connection error, or
+	// nil if the last check succeeded.
+	LastError error
+
+	// AverageRTT is the average round trip time to the server.
+	AverageRTT time.Duration
+
+	// LastUpdateTime is the time of the last check of the server.
+	LastUpdateTime time.Time
+
+	// LastWriteTime is the time of the last write reported by a replica set member.
+	LastWriteTime time.Time
+
+	// Staleness is the estimated replication lag of a secondary, as compared with the max
+	// staleness of a read preference. It is 0 for servers that are not secondaries.
+	Staleness time.Duration
+}
+
+// Error implements the error interface.
+func (e ServerSelectionError) Error() string {
+	if e.Wrapped == nil {
+		return "server selection error"
+	}
+	return e.Wrapped.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e ServerSelectionError) Unwrap() error {
+	return e.Wrapped
+}
+
+// newServerSelectionError creates a ServerSelectionError that wraps sse.
+func newServerSelectionError(sse topology.ServerSelectionError) ServerSelectionError {
+	desc := sse.Desc
+	servers := make([]ServerState, 0, len(desc.Servers))
+	for _, s := range desc.Servers {
+		servers = append(servers, ServerState{
+			Addr:           s.Addr,
+			Kind:           s.Kind.String(),
+			SetName:        s.SetName,
+			Tags:           s.Tags,
+			LastError:      s.LastError,
+			AverageRTT:     s.AverageRTT,
+			LastUpdateTime: s.LastUpdateTime,
+			LastWriteTime:  s.LastWriteTime,
+			Staleness:      estimateStaleness(desc, s),
+		})
+	}
+
+	return ServerSelectionError{
+		TopologyKind: desc.Kind.String(),
+		SetName:      desc.SetName,
+		Servers:      servers,
+		Wrapped:      sse,
+	}
+}
+
+// estimateStaleness returns the staleness of a secondary as estimated by the server selection
+// specification, or 0 if srv is not a secondary.
+func estimateStaleness(desc description.Topology, srv description.Server) time.Duration {
+	if srv.Kind != description.ServerKindRSSecondary {
+		return 0
+	}
+
+	for _, primary := range desc.Servers {
+		if primary.Kind == description.ServerKindRSPrimary {
+			return srv.LastUpdateTime.Sub(srv.LastWriteTime) -
+				primary.LastUpdateTime.Sub(primary.LastWriteTime) + srv.HeartbeatInterval
+		}
+	}
+
+	latest := srv.LastWriteTime
+	for _, s := range desc.Servers {
+		if s.Kind == description.ServerKindRSSecondary && s.LastWriteTime.After(latest) {
+			latest = s.LastWriteTime
+		}
+	}
+	return latest.Sub(srv.LastWriteTime) + srv.HeartbeatInterval
+}
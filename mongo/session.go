@@ -26,6 +26,10 @@ import (
 // the method call is using.
 var ErrWrongClient = errors.New("session was not created by this client")
 
+// ErrSnapshotWrite is returned when a write operation, including an aggregation with an $out or
+// $merge stage, is run with a snapshot session. Snapshot sessions are read-only.
+var ErrSnapshotWrite = errors.New("write operations are not supported in snapshot sessions")
+
 var withTransactionTimeout = 120 * time.Second
 
 // Session is a MongoDB logical session. Sessions can be used to enable causal
@@ -120,87 +124,14 @@ func (s *Session) EndSession(ctx context.Context) {
 // AbortTransaction. Because this method must succeed to ensure that server-side
 // resources are properly cleaned up, context deadlines and cancellations will
 // not be respected during this call. For a usage example, see the
-// Client.StartSession method documentation.
+// Client.StartSession method documentation. To get a typed result and wait
+// between retries of transient errors, use the WithTransaction function.
 func (s *Session) WithTransaction(
 	ctx context.Context,
 	fn func(ctx context.Context) (interface{}, error),
 	opts ...options.Lister[options.TransactionOptions],
 ) (interface{}, error) {
-	timeout := time.NewTimer(withTransactionTimeout)
-	defer timeout.Stop()
-	var err error
-	for {
-		err = s.StartTransaction(opts...)
-		if err != nil {
-			return nil, err
-		}
-
-		res, err := fn(NewSessionContext(ctx, s))
-		if err != nil {
-			if s.clientSession.TransactionRunning() {
-				// Wrap the user-provided Context in a new one that behaves like context.Background() for deadlines and
-				// cancellations, but forwards Value requests to the original one.
-				_ = s.AbortTransaction(newBackgroundContext(ctx))
-			}
-
-			select {
-			case <-timeout.C:
-				return nil, err
-			default:
-			}
-
-			if errorHasLabel(err, driver.TransientTransactionError) {
-				continue
-			}
-			return res, err
-		}
-
-		// Check if callback intentionally aborted and, if so, return immediately
-		// with no error.
-		err = s.clientSession.CheckAbortTransaction()
-		if err != nil {
-			return res, nil
-		}
-
-		// If context has errored, run AbortTransaction and return, as the CommitLoop
-		// has no chance of succeeding.
-		//
-		// Aborting after a failed CommitTransaction is dangerous. Failed transaction
-		// commits may unpin the session server-side, and subsequent transaction aborts
-		// may run on a new mongos which could end up with commit and abort being executed
-		// simultaneously.
-		if ctx.Err() != nil {
-			// Wrap the user-provided Context in a new one that behaves like context.Background() for deadlines and
-			// cancellations, but forwards Value requests to the original one.
-			_ = s.AbortTransaction(newBackgroundContext(ctx))
-			return nil, ctx.Err()
-		}
-
-	CommitLoop:
-		for {
-			err = s.CommitTransaction(newBackgroundContext(ctx))
-			// End when error is nil, as transaction has been committed.
-			if err == nil {
-				return res, nil
-			}
-
-			select {
-			case <-timeout.C:
-				return res, err
-			default:
-			}
-
-			if cerr, ok := err.(CommandError); ok {
-				if cerr.HasErrorLabel(driver.UnknownTransactionCommitResult) && !cerr.IsMaxTimeMSExpiredError() {
-					continue
-				}
-				if cerr.HasErrorLabel(driver.TransientTransactionError) {
-					break CommitLoop
-				}
-			}
-			return res, err
-		}
-	}
+	return runTransaction(ctx, s, fn, transactionRun{txnOpts: opts, timeout: withTransactionTimeout})
 }
 
 // StartTransaction starts a new transaction. This method returns an error if
@@ -320,6 +251,18 @@ func (s *Session) OperationTime() *bson.Timestamp {
 	return s.clientSession.OperationTime
 }
 
+// SnapshotTime returns the cluster time of the snapshot that read operations performed with a
+// snapshot session read from. It returns nil if the session is not a snapshot session or if the
+// snapshot time has not been established yet, i.e. no read has been performed with the session
+// and no snapshot time was set in the SessionOptions.
+func (s *Session) SnapshotTime() *bson.Timestamp {
+	if !s.clientSession.Snapshot || s.clientSession.SnapshotTime == nil {
+		return nil
+	}
+	ts := *s.clientSession.SnapshotTime
+	return &ts
+}
+
 // AdvanceOperationTime advances the operation time for a session. This method
 // returns an error if the session has ended.
 func (s *Session) AdvanceOperationTime(ts *bson.Timestamp) error {
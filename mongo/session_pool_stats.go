@@ -0,0 +1,63 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import "time"
+
+// SessionPoolStats contains statistics about the server session pool of a Client. See
+// Client.SessionPoolStats.
+//
+// The counters only increase. The rate at which sessions are acquired, which includes the implicit
+// sessions of operations, is the difference between two values of Acquired divided by the time
+// between them.
+type SessionPoolStats struct {
+	// Idle is the number of server sessions in the pool that are available for reuse.
+	Idle int
+
+	// InUse is the number of server sessions used by sessions or operations in progress.
+	InUse int64
+
+	// Acquired is the total number of server sessions taken from the pool or created.
+	Acquired int64
+
+	// Created is the total number of server sessions created because the pool had no unexpired
+	// session.
+	Created int64
+
+	// Pruned is the total number of server sessions removed from the pool because they were about
+	// to expire on the server.
+	Pruned int64
+
+	// Discarded is the total number of server sessions that were not returned to the pool because
+	// they were used by an operation that failed with a network error or the pool was full. See
+	// options.ClientOptionsBuilder.SetMaxIdleSessions.
+	Discarded int64
+
+	// LastPruned is the time an expired session was last removed from the pool, or the zero time if
+	// no session has expired. Expired sessions are removed when sessions are taken from or returned
+	// to the pool.
+	LastPruned time.Time
+}
+
+// SessionPoolStats returns statistics about the server session pool of the Client. It returns the
+// zero value if the Client is not connected.
+func (c *Client) SessionPoolStats() SessionPoolStats {
+	if c.sessionPool == nil {
+		return SessionPoolStats{}
+	}
+
+	s := c.sessionPool.Stats()
+	return SessionPoolStats{
+		Idle:       s.Idle,
+		InUse:      s.CheckedOut,
+		Acquired:   s.Acquired,
+		Created:    s.Created,
+		Pruned:     s.Pruned,
+		Discarded:  s.Discarded,
+		LastPruned: s.LastPruned,
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrInvalidSessionToken is returned by Session.ApplyCausalConsistencyToken if the token was not
+// created by Session.CausalConsistencyToken.
+var ErrInvalidSessionToken = errors.New("invalid causal consistency token")
+
+// sessionTokenVersion is the version of the format of causal consistency tokens.
+const sessionTokenVersion = 1
+
+type sessionToken struct {
+	Version       int32           `bson:"v"`
+	ClusterTime   bson.Raw        `bson:"clusterTime,omitempty"`
+	OperationTime *bson.Timestamp `bson:"operationTime,omitempty"`
+}
+
+// CausalConsistencyToken returns an opaque token containing the cluster time and operation time of
+// the session. The token can be passed to Session.ApplyCausalConsistencyToken on another session,
+// possibly in another process, so that its causally consistent reads observe the writes made
+// by this session. For example, a service behind a load balancer can return the token to its
+// callers after a write and apply it to the session used to serve the next request.
+//
+// The token is valid BSON, but its contents are not part of the API and may change.
+func (s *Session) CausalConsistencyToken() ([]byte, error) {
+	return bson.Marshal(sessionToken{
+		Version:       sessionTokenVersion,
+		ClusterTime:   s.clientSession.ClusterTime,
+		OperationTime: s.clientSession.OperationTime,
+	})
+}
+
+// ApplyCausalConsistencyToken advances the cluster time and operation time of the session to those
+// in a token returned by Session.CausalConsistencyToken. Times in the token that are older than
+// those of the session are ignored. This method returns an error wrapping ErrInvalidSessionToken if
+// token is invalid and an error if the session has ended.
+func (s *Session) ApplyCausalConsistencyToken(token []byte) error {
+	var st sessionToken
+	if err := bson.Unmarshal(token, &st); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSessionToken, err)
+	}
+	if st.Version != sessionTokenVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSessionToken, st.Version)
+	}
+
+	if st.ClusterTime != nil {
+		if err := s.clientSession.AdvanceClusterTime(st.ClusterTime); err != nil {
+			return err
+		}
+	}
+	if st.OperationTime != nil {
+		if err := s.clientSession.AdvanceOperationTime(st.OperationTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
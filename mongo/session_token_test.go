@@ -0,0 +1,74 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestCausalConsistencyToken(t *testing.T) {
+	client := setupClient()
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	newSession := func(t *testing.T) *Session {
+		t.Helper()
+
+		sess, err := client.StartSession()
+		require.NoError(t, err, "StartSession error: %v", err)
+		t.Cleanup(func() { sess.EndSession(context.Background()) })
+		return sess
+	}
+	marshalDoc := func(d bson.D) bson.Raw {
+		b, err := bson.Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	clusterTime := func(ts uint32) bson.Raw {
+		return marshalDoc(bson.D{{"$clusterTime", bson.D{{"clusterTime", bson.Timestamp{T: ts, I: 1}}}}})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		src := newSession(t)
+		require.NoError(t, src.AdvanceClusterTime(clusterTime(20)), "AdvanceClusterTime error")
+		require.NoError(t, src.AdvanceOperationTime(&bson.Timestamp{T: 20, I: 1}), "AdvanceOperationTime error")
+
+		token, err := src.CausalConsistencyToken()
+		require.NoError(t, err, "CausalConsistencyToken error: %v", err)
+
+		dst := newSession(t)
+		err = dst.ApplyCausalConsistencyToken(token)
+		require.NoError(t, err, "ApplyCausalConsistencyToken error: %v", err)
+		assert.Equal(t, src.ClusterTime(), dst.ClusterTime(), "expected cluster time to be applied")
+		assert.Equal(t, src.OperationTime(), dst.OperationTime(), "expected operation time to be applied")
+	})
+	t.Run("older times are ignored", func(t *testing.T) {
+		src := newSession(t)
+		require.NoError(t, src.AdvanceOperationTime(&bson.Timestamp{T: 5, I: 1}), "AdvanceOperationTime error")
+		token, err := src.CausalConsistencyToken()
+		require.NoError(t, err, "CausalConsistencyToken error: %v", err)
+
+		dst := newSession(t)
+		require.NoError(t, dst.AdvanceOperationTime(&bson.Timestamp{T: 10, I: 1}), "AdvanceOperationTime error")
+		err = dst.ApplyCausalConsistencyToken(token)
+		require.NoError(t, err, "ApplyCausalConsistencyToken error: %v", err)
+		assert.Equal(t, &bson.Timestamp{T: 10, I: 1}, dst.OperationTime(), "expected newer operation time to be kept")
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		sess := newSession(t)
+		err := sess.ApplyCausalConsistencyToken([]byte("not a token"))
+		assert.ErrorIs(t, err, ErrInvalidSessionToken, "expected invalid token error")
+
+		token := marshalDoc(bson.D{{"v", int32(2)}})
+		err = sess.ApplyCausalConsistencyToken(token)
+		assert.ErrorIs(t, err, ErrInvalidSessionToken, "expected invalid token error for unknown version")
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// AnalyzeShardKeyResult is the result of an AnalyzeShardKey operation. KeyCharacteristics,
+// ReadDistribution, and WriteDistribution are nil if they were not calculated.
+type AnalyzeShardKeyResult struct {
+	KeyCharacteristics *ShardKeyCharacteristics   `bson:"keyCharacteristics"`
+	ReadDistribution   *ShardKeyReadDistribution  `bson:"readDistribution"`
+	WriteDistribution  *ShardKeyWriteDistribution `bson:"writeDistribution"`
+}
+
+// ShardKeyCharacteristics describes the cardinality, frequency, and monotonicity of a shard key.
+type ShardKeyCharacteristics struct {
+	NumDocsTotal      int64                    `bson:"numDocsTotal"`
+	NumOrphanDocs     int64                    `bson:"numOrphanDocs"`
+	AvgDocSizeBytes   int64                    `bson:"avgDocSizeBytes"`
+	NumDocsSampled    int64                    `bson:"numDocsSampled"`
+	IsUnique          bool                     `bson:"isUnique"`
+	NumDistinctValues int64                    `bson:"numDistinctValues"`
+	MostCommonValues  []ShardKeyValueFrequency `bson:"mostCommonValues"`
+	Monotonicity      ShardKeyMonotonicity     `bson:"monotonicity"`
+}
+
+// ShardKeyValueFrequency is the number of sampled documents with a shard key value.
+type ShardKeyValueFrequency struct {
+	Value     bson.Raw `bson:"value"`
+	Frequency int64    `bson:"frequency"`
+}
+
+// ShardKeyMonotonicity describes whether shard key values increase or decrease with insertion
+// order. Type is "monotonic", "not monotonic", or "unknown".
+type ShardKeyMonotonicity struct {
+	RecordIDCorrelationCoefficient float64 `bson:"recordIdCorrelationCoefficient"`
+	Type                           string  `bson:"type"`
+}
+
+// ShardKeyReadDistribution describes how sampled reads would be routed with a shard key.
+type ShardKeyReadDistribution struct {
+	SampleSize                     ShardKeyReadSampleSize `bson:"sampleSize"`
+	PercentageOfSingleShardReads   float64                `bson:"percentageOfSingleShardReads"`
+	PercentageOfMultiShardReads    float64                `bson:"percentageOfMultiShardReads"`
+	PercentageOfScatterGatherReads float64                `bson:"percentageOfScatterGatherReads"`
+	NumReadsByRange                []int64                `bson:"numReadsByRange"`
+}
+
+// ShardKeyReadSampleSize is the number of sampled reads by command.
+type ShardKeyReadSampleSize struct {
+	Total     int64 `bson:"total"`
+	Find      int64 `bson:"find"`
+	Aggregate int64 `bson:"aggregate"`
+	Count     int64 `bson:"count"`
+	Distinct  int64 `bson:"distinct"`
+}
+
+// ShardKeyWriteDistribution describes how sampled writes would be routed with a shard key.
+type ShardKeyWriteDistribution struct {
+	SampleSize                              ShardKeyWriteSampleSize `bson:"sampleSize"`
+	PercentageOfSingleShardWrites           float64                 `bson:"percentageOfSingleShardWrites"`
+	PercentageOfMultiShardWrites            float64                 `bson:"percentageOfMultiShardWrites"`
+	PercentageOfScatterGatherWrites         float64                 `bson:"percentageOfScatterGatherWrites"`
+	NumWritesByRange                        []int64                 `bson:"numWritesByRange"`
+	PercentageOfShardKeyUpdates             float64                 `bson:"percentageOfShardKeyUpdates"`
+	PercentageOfSingleWritesWithoutShardKey float64                 `bson:"percentageOfSingleWritesWithoutShardKey"`
+	PercentageOfMultiWritesWithoutShardKey  float64                 `bson:"percentageOfMultiWritesWithoutShardKey"`
+}
+
+// ShardKeyWriteSampleSize is the number of sampled writes by command.
+type ShardKeyWriteSampleSize struct {
+	Total         int64 `bson:"total"`
+	Update        int64 `bson:"update"`
+	Delete        int64 `bson:"delete"`
+	FindAndModify int64 `bson:"findAndModify"`
+}
+
+// QueryAnalyzerMode is the mode of the query analyzer of a collection.
+type QueryAnalyzerMode string
+
+// These constants are the valid query analyzer modes.
+const (
+	// QueryAnalyzerModeFull enables query sampling.
+	QueryAnalyzerModeFull QueryAnalyzerMode = "full"
+	// QueryAnalyzerModeOff disables query sampling.
+	QueryAnalyzerModeOff QueryAnalyzerMode = "off"
+)
+
+// QueryAnalyzerConfiguration is the configuration of the query analyzer of a collection.
+type QueryAnalyzerConfiguration struct {
+	Mode             QueryAnalyzerMode `bson:"mode"`
+	SamplesPerSecond float64           `bson:"samplesPerSecond,omitempty"`
+}
+
+// ConfigureQueryAnalyzerResult is the result of a ConfigureQueryAnalyzer operation.
+// OldConfiguration is nil if the query analyzer was not configured before.
+type ConfigureQueryAnalyzerResult struct {
+	NewConfiguration QueryAnalyzerConfiguration  `bson:"newConfiguration"`
+	OldConfiguration *QueryAnalyzerConfiguration `bson:"oldConfiguration"`
+}
+
+// AnalyzeShardKey runs the analyzeShardKey command to measure how suitable key is as the shard key
+// of the collection, in terms of its cardinality, frequency, and monotonicity and of the routing of
+// queries sampled with ConfigureQueryAnalyzer. The collection does not need to be sharded, but the
+// key must be supported by an index. The command requires MongoDB 7.0 or later and must be run
+// against a sharded cluster or replica set.
+//
+// The opts parameter can be used to specify options for the operation (see the
+// options.AnalyzeShardKeyOptions documentation).
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/analyzeShardKey/.
+func (coll *Collection) AnalyzeShardKey(
+	ctx context.Context,
+	key interface{},
+	opts ...options.Lister[options.AnalyzeShardKeyOptions],
+) (*AnalyzeShardKeyResult, error) {
+	args, err := mongoutil.NewOptions[options.AnalyzeShardKeyOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	cmd, err := coll.analyzeShardKeyCommand(key, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var res AnalyzeShardKeyResult
+	err = coll.client.Database("admin").RunCommand(ctx, cmd).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (coll *Collection) analyzeShardKeyCommand(key interface{}, args *options.AnalyzeShardKeyOptions) (bson.D, error) {
+	keyDoc, err := marshal(key, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := bson.D{
+		{"analyzeShardKey", coll.db.name + "." + coll.name},
+		{"key", bson.Raw(keyDoc)},
+	}
+	if args.KeyCharacteristics != nil {
+		cmd = append(cmd, bson.E{"keyCharacteristics", *args.KeyCharacteristics})
+	}
+	if args.ReadWriteDistribution != nil {
+		cmd = append(cmd, bson.E{"readWriteDistribution", *args.ReadWriteDistribution})
+	}
+	if args.SampleRate != nil {
+		cmd = append(cmd, bson.E{"sampleRate", *args.SampleRate})
+	}
+	if args.SampleSize != nil {
+		cmd = append(cmd, bson.E{"sampleSize", *args.SampleSize})
+	}
+	return cmd, nil
+}
+
+// ConfigureQueryAnalyzer runs the configureQueryAnalyzer command to enable or disable sampling of
+// the queries on the collection. The sampled queries are used by AnalyzeShardKey to calculate the
+// read and write distribution of a candidate shard key. When enabling the query analyzer, the
+// sampling rate must be specified with options.ConfigureQueryAnalyzerOptionsBuilder.SetSamplesPerSecond.
+// The command requires MongoDB 7.0 or later.
+//
+// For more information about the command, see
+// https://www.mongodb.com/docs/manual/reference/command/configureQueryAnalyzer/.
+func (coll *Collection) ConfigureQueryAnalyzer(
+	ctx context.Context,
+	mode QueryAnalyzerMode,
+	opts ...options.Lister[options.ConfigureQueryAnalyzerOptions],
+) (*ConfigureQueryAnalyzerResult, error) {
+	args, err := mongoutil.NewOptions[options.ConfigureQueryAnalyzerOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	cmd := bson.D{
+		{"configureQueryAnalyzer", coll.db.name + "." + coll.name},
+		{"mode", string(mode)},
+	}
+	if args.SamplesPerSecond != nil {
+		cmd = append(cmd, bson.E{"samplesPerSecond", *args.SamplesPerSecond})
+	}
+
+	var res ConfigureQueryAnalyzerResult
+	err = coll.client.Database("admin").RunCommand(ctx, cmd).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
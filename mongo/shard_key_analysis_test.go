@@ -0,0 +1,79 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestAnalyzeShardKey(t *testing.T) {
+	t.Run("command", func(t *testing.T) {
+		coll := setupColl("orders")
+		args := &options.AnalyzeShardKeyOptions{}
+		for _, set := range options.AnalyzeShardKey().SetReadWriteDistribution(false).SetSampleSize(1000).List() {
+			require.NoError(t, set(args), "option error")
+		}
+
+		cmd, err := coll.analyzeShardKeyCommand(bson.D{{"customerId", 1}}, args)
+		require.NoError(t, err, "analyzeShardKeyCommand error: %v", err)
+
+		got, err := bson.Marshal(cmd)
+		require.NoError(t, err, "Marshal error: %v", err)
+		want, err := bson.Marshal(bson.D{
+			{"analyzeShardKey", testDbName + ".orders"},
+			{"key", bson.D{{"customerId", 1}}},
+			{"readWriteDistribution", false},
+			{"sampleSize", int64(1000)},
+		})
+		require.NoError(t, err, "Marshal error: %v", err)
+		assert.Equal(t, bson.Raw(want), bson.Raw(got), "expected command %v, got %v", bson.Raw(want), bson.Raw(got))
+	})
+	t.Run("result", func(t *testing.T) {
+		reply, err := bson.Marshal(bson.D{
+			{"keyCharacteristics", bson.D{
+				{"numDocsTotal", int64(100)},
+				{"avgDocSizeBytes", int32(64)},
+				{"numDocsSampled", int64(100)},
+				{"isUnique", false},
+				{"numDistinctValues", int64(10)},
+				{"mostCommonValues", bson.A{bson.D{{"value", bson.D{{"customerId", 7}}}, {"frequency", int64(30)}}}},
+				{"monotonicity", bson.D{{"recordIdCorrelationCoefficient", 0.99}, {"type", "monotonic"}}},
+			}},
+			{"readDistribution", bson.D{
+				{"sampleSize", bson.D{{"total", int64(4)}, {"find", int64(4)}}},
+				{"percentageOfSingleShardReads", 75.0},
+				{"percentageOfScatterGatherReads", 25.0},
+				{"numReadsByRange", bson.A{int64(3), int64(1)}},
+			}},
+			{"ok", 1.0},
+		})
+		require.NoError(t, err, "Marshal error: %v", err)
+
+		var res AnalyzeShardKeyResult
+		err = bson.Unmarshal(reply, &res)
+		require.NoError(t, err, "Unmarshal error: %v", err)
+
+		require.NotNil(t, res.KeyCharacteristics, "expected key characteristics")
+		kc := res.KeyCharacteristics
+		assert.Equal(t, int64(64), kc.AvgDocSizeBytes, "expected average document size")
+		assert.Equal(t, int64(10), kc.NumDistinctValues, "expected distinct values")
+		require.Len(t, kc.MostCommonValues, 1, "expected most common values")
+		assert.Equal(t, int64(30), kc.MostCommonValues[0].Frequency, "expected frequency")
+		assert.Equal(t, "monotonic", kc.Monotonicity.Type, "expected monotonicity")
+
+		require.NotNil(t, res.ReadDistribution, "expected read distribution")
+		assert.Equal(t, int64(4), res.ReadDistribution.SampleSize.Find, "expected sampled finds")
+		assert.Equal(t, 25.0, res.ReadDistribution.PercentageOfScatterGatherReads, "expected scatter-gather reads")
+		assert.Equal(t, []int64{3, 1}, res.ReadDistribution.NumReadsByRange, "expected reads by range")
+		assert.Nil(t, res.WriteDistribution, "expected no write distribution")
+	})
+}
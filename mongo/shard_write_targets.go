@@ -0,0 +1,190 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrMissingShardKey is wrapped by MissingShardKeyError.
+var ErrMissingShardKey = errors.New("filter does not include the full shard key")
+
+// MissingShardKeyError is returned by ValidateShardKey and GroupWriteModelsByShard for a
+// single-document write whose filter does not have equality conditions on all shard key fields.
+// Servers before MongoDB 7.0 reject such writes, and later servers run them as a broadcast to every
+// shard that owns chunks of the collection.
+type MissingShardKeyError struct {
+	// Namespace is the namespace of the collection, in "database.collection" form.
+	Namespace string
+
+	// Index is the index of the write model in the models passed to GroupWriteModelsByShard, or -1
+	// for ValidateShardKey.
+	Index int
+
+	// Fields are the shard key fields that do not have equality conditions in the filter.
+	Fields []string
+}
+
+// Error implements the error interface.
+func (e MissingShardKeyError) Error() string {
+	msg := fmt.Sprintf("filter on %s is missing shard key fields %s", e.Namespace, strings.Join(e.Fields, ", "))
+	if e.Index >= 0 {
+		msg = fmt.Sprintf("write model %d: %s", e.Index, msg)
+	}
+	return msg
+}
+
+// Unwrap returns ErrMissingShardKey.
+func (e MissingShardKeyError) Unwrap() error {
+	return ErrMissingShardKey
+}
+
+// ShardWriteGroup is a group of write models that target the same shard, see
+// GroupWriteModelsByShard.
+type ShardWriteGroup struct {
+	// Shard is the name of the shard that the writes target, or "" for writes that target several
+	// shards or whose shard cannot be determined, such as writes on hashed shard keys and all the
+	// writes on an unsharded collection.
+	Shard string
+
+	// Models are the write models of the group, in their original order.
+	Models []WriteModel
+
+	// Indexes are the indexes of Models in the models passed to GroupWriteModelsByShard.
+	Indexes []int
+}
+
+// ValidateShardKey returns a MissingShardKeyError if the filter does not have equality conditions
+// on all shard key fields of coll, so that a single-document update, replace, or delete with the
+// filter cannot be targeted to a single shard. It returns nil if coll is not sharded.
+//
+// The shard key is read from the config database and cached by the Client for one minute. See
+// Client.ShardZones for the privileges required.
+func ValidateShardKey(ctx context.Context, coll *Collection, filter interface{}) error {
+	doc, err := marshal(filter, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return err
+	}
+
+	ns := coll.db.name + "." + coll.name
+	sz, err := coll.client.shardZones.get(ctx, coll.client, ns)
+	if err != nil || sz == nil {
+		return err
+	}
+	if missing := sz.missingShardKeyFields(bson.Raw(doc)); len(missing) > 0 {
+		return MissingShardKeyError{Namespace: ns, Index: -1, Fields: missing}
+	}
+	return nil
+}
+
+// GroupWriteModelsByShard groups the write models of a BulkWrite on coll by the shard that each
+// write targets, using the chunk ranges of the collection. The groups are in the order in which
+// their first model appears. Running an unordered BulkWrite per group lets mongos send each batch
+// to a single shard instead of splitting it.
+//
+// Inserts are grouped by the shard key value of their document and other writes by the shards
+// targeted by their filter. It returns a MissingShardKeyError before any write is sent if the filter
+// of an UpdateOneModel, ReplaceOneModel, or DeleteOneModel, or of an upsert, does not include the
+// full shard key. If coll is not sharded, all models are returned in a single group.
+//
+// The sharding metadata is read from the config database and cached by the Client for one minute,
+// so the groups do not reflect chunk migrations that happened since. See Client.ShardZones for the
+// privileges required.
+func GroupWriteModelsByShard(ctx context.Context, coll *Collection, models []WriteModel) ([]ShardWriteGroup, error) {
+	ns := coll.db.name + "." + coll.name
+	sz, err := coll.client.shardZones.get(ctx, coll.client, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []ShardWriteGroup
+	positions := make(map[string]int)
+	for i, model := range models {
+		shard := ""
+		if sz != nil {
+			shard, err = sz.writeTarget(coll, i, model)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		pos, ok := positions[shard]
+		if !ok {
+			pos = len(groups)
+			positions[shard] = pos
+			groups = append(groups, ShardWriteGroup{Shard: shard})
+		}
+		groups[pos].Models = append(groups[pos].Models, model)
+		groups[pos].Indexes = append(groups[pos].Indexes, i)
+	}
+	return groups, nil
+}
+
+// writeTarget returns the shard targeted by the write model at index i of a bulk write, or "" if
+// the write targets several shards.
+func (sz *ShardZones) writeTarget(coll *Collection, i int, model WriteModel) (string, error) {
+	var filter interface{}
+	var single bool
+	var upsert *bool
+	switch m := model.(type) {
+	case *InsertOneModel:
+		doc, err := marshal(m.Document, coll.bsonOpts, coll.registry)
+		if err != nil {
+			return "", err
+		}
+		p, err := sz.Locate(bson.Raw(doc))
+		if errors.Is(err, ErrHashedShardKey) {
+			return "", nil
+		}
+		return p.Shard, err
+	case *UpdateOneModel:
+		filter, single, upsert = m.Filter, true, m.Upsert
+	case *ReplaceOneModel:
+		filter, single, upsert = m.Filter, true, m.Upsert
+	case *DeleteOneModel:
+		filter, single = m.Filter, true
+	case *UpdateManyModel:
+		filter, upsert = m.Filter, m.Upsert
+	case *DeleteManyModel:
+		filter = m.Filter
+	default:
+		return "", fmt.Errorf("write model %d: unsupported write model type %T", i, model)
+	}
+
+	doc, err := marshal(filter, coll.bsonOpts, coll.registry)
+	if err != nil {
+		return "", fmt.Errorf("write model %d: %w", i, err)
+	}
+	if single || (upsert != nil && *upsert) {
+		if missing := sz.missingShardKeyFields(bson.Raw(doc)); len(missing) > 0 {
+			return "", MissingShardKeyError{Namespace: sz.Namespace, Index: i, Fields: missing}
+		}
+	}
+
+	shards, _ := sz.targets(bson.Raw(doc))
+	if len(shards) != 1 {
+		return "", nil
+	}
+	return shards[0], nil
+}
+
+// missingShardKeyFields returns the shard key fields that do not have equality conditions in
+// filter.
+func (sz *ShardZones) missingShardKeyFields(filter bson.Raw) []string {
+	var missing []string
+	for _, field := range sz.fields {
+		if _, ok := equalityValue(filter, field); !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestShardWriteTargets(t *testing.T) {
+	raw := func(d bson.D) bson.Raw {
+		b, err := bson.Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	chunks := []ShardChunk{
+		{Min: raw(bson.D{{"tenant", bson.MinKey{}}, {"ts", bson.MinKey{}}}), Max: raw(bson.D{{"tenant", "m"}, {"ts", bson.MinKey{}}}), Shard: "shard0"},
+		{Min: raw(bson.D{{"tenant", "m"}, {"ts", bson.MinKey{}}}), Max: raw(bson.D{{"tenant", bson.MaxKey{}}, {"ts", bson.MaxKey{}}}), Shard: "shard1"},
+	}
+	sz, err := newShardZones(testDbName+".orders", raw(bson.D{{"tenant", 1}, {"ts", 1}}), chunks, nil)
+	require.NoError(t, err, "newShardZones error: %v", err)
+	hashed, err := newShardZones(testDbName+".hashed", raw(bson.D{{"tenant", "hashed"}}), chunks, nil)
+	require.NoError(t, err, "newShardZones error: %v", err)
+
+	coll := setupColl("orders")
+	coll.client.shardZones.entries = map[string]shardZonesEntry{
+		testDbName + ".orders":    {zones: sz, fetched: time.Now()},
+		testDbName + ".hashed":    {zones: hashed, fetched: time.Now()},
+		testDbName + ".unsharded": {fetched: time.Now()},
+	}
+
+	t.Run("ValidateShardKey", func(t *testing.T) {
+		err := ValidateShardKey(context.Background(), coll, bson.D{{"tenant", "acme"}, {"ts", bson.D{{"$eq", 5}}}})
+		assert.NoError(t, err, "expected the full shard key")
+
+		err = ValidateShardKey(context.Background(), coll, bson.D{{"tenant", "acme"}, {"ts", bson.D{{"$gt", 5}}}})
+		assert.True(t, errors.Is(err, ErrMissingShardKey), "expected ErrMissingShardKey, got %v", err)
+		var mske MissingShardKeyError
+		require.True(t, errors.As(err, &mske), "expected a MissingShardKeyError, got %v", err)
+		assert.Equal(t, []string{"ts"}, mske.Fields, "missing fields mismatch")
+		assert.Equal(t, -1, mske.Index, "index mismatch")
+
+		err = ValidateShardKey(context.Background(), coll.db.Collection("unsharded"), bson.D{})
+		assert.NoError(t, err, "expected unsharded collections to be valid")
+	})
+	t.Run("GroupWriteModelsByShard", func(t *testing.T) {
+		models := []WriteModel{
+			NewInsertOneModel().SetDocument(bson.D{{"tenant", "zeta"}, {"ts", 1}}),
+			NewUpdateOneModel().SetFilter(bson.D{{"tenant", "acme"}, {"ts", 2}}).SetUpdate(bson.D{{"$set", bson.D{{"x", 1}}}}),
+			NewDeleteManyModel().SetFilter(bson.D{{"x", 1}}),
+			NewInsertOneModel().SetDocument(bson.D{{"tenant", "beta"}, {"ts", 3}}),
+			NewDeleteManyModel().SetFilter(bson.D{{"tenant", "omega"}}),
+		}
+		groups, err := GroupWriteModelsByShard(context.Background(), coll, models)
+		require.NoError(t, err, "GroupWriteModelsByShard error: %v", err)
+		require.Len(t, groups, 3, "expected three groups")
+		assert.Equal(t, "shard1", groups[0].Shard, "first group shard mismatch")
+		assert.Equal(t, []int{0, 4}, groups[0].Indexes, "first group indexes mismatch")
+		assert.Equal(t, "shard0", groups[1].Shard, "second group shard mismatch")
+		assert.Equal(t, []int{1, 3}, groups[1].Indexes, "second group indexes mismatch")
+		assert.Equal(t, "", groups[2].Shard, "expected a broadcast group")
+		assert.Equal(t, []int{2}, groups[2].Indexes, "broadcast group indexes mismatch")
+		assert.Equal(t, models[2], groups[2].Models[0], "expected the original models")
+	})
+	t.Run("missing shard key", func(t *testing.T) {
+		models := []WriteModel{
+			NewDeleteManyModel().SetFilter(bson.D{{"x", 1}}),
+			NewUpdateManyModel().SetFilter(bson.D{{"tenant", "acme"}}).SetUpdate(bson.D{{"$set", bson.D{{"x", 1}}}}).SetUpsert(true),
+		}
+		_, err := GroupWriteModelsByShard(context.Background(), coll, models)
+		var mske MissingShardKeyError
+		require.True(t, errors.As(err, &mske), "expected a MissingShardKeyError, got %v", err)
+		assert.Equal(t, 1, mske.Index, "index mismatch")
+		assert.Equal(t, "write model 1: filter on "+testDbName+".orders is missing shard key fields ts", err.Error(), "message mismatch")
+	})
+	t.Run("hashed shard key", func(t *testing.T) {
+		models := []WriteModel{
+			NewInsertOneModel().SetDocument(bson.D{{"tenant", "acme"}}),
+			NewDeleteOneModel().SetFilter(bson.D{{"tenant", "acme"}}),
+		}
+		groups, err := GroupWriteModelsByShard(context.Background(), coll.db.Collection("hashed"), models)
+		require.NoError(t, err, "GroupWriteModelsByShard error: %v", err)
+		require.Len(t, groups, 1, "expected one group")
+		assert.Equal(t, "", groups[0].Shard, "expected writes on hashed shard keys not to be grouped")
+	})
+	t.Run("unsharded", func(t *testing.T) {
+		models := []WriteModel{NewDeleteOneModel().SetFilter(bson.D{{"x", 1}})}
+		groups, err := GroupWriteModelsByShard(context.Background(), coll.db.Collection("unsharded"), models)
+		require.NoError(t, err, "GroupWriteModelsByShard error: %v", err)
+		require.Len(t, groups, 1, "expected one group")
+		assert.Equal(t, []int{0}, groups[0].Indexes, "indexes mismatch")
+	})
+}
@@ -0,0 +1,462 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrCollectionNotSharded is returned by Client.ShardZones if the namespace is not a sharded
+// collection.
+var ErrCollectionNotSharded = errors.New("collection is not sharded")
+
+// ErrHashedShardKey is returned by ShardZones methods that need to compute the hash of a shard key
+// value, which is not supported.
+var ErrHashedShardKey = errors.New("locating values of hashed shard keys is not supported")
+
+// ShardChunk is a range of shard key values owned by a shard, as recorded in config.chunks. Min is
+// inclusive and Max is exclusive.
+type ShardChunk struct {
+	Min   bson.Raw
+	Max   bson.Raw
+	Shard string
+}
+
+// ShardZoneRange is a range of shard key values assigned to a zone, as recorded in config.tags. Min
+// is inclusive and Max is exclusive.
+type ShardZoneRange struct {
+	Min  bson.Raw
+	Max  bson.Raw
+	Zone string
+}
+
+// ShardPlacement describes where a shard key value is stored.
+type ShardPlacement struct {
+	// Shard is the name of the shard that owns the chunk containing the value.
+	Shard string
+
+	// Zone is the zone whose range contains the value, or "" if the value is not in a zone range.
+	Zone string
+
+	// Chunk is the chunk containing the value.
+	Chunk ShardChunk
+}
+
+// ShardZones is a snapshot of the chunk and zone ranges of a sharded collection. It can be used to
+// check which shard and zone a document would be stored on before inserting it, or to find which
+// shards a query would be sent to. Chunks are moved by the balancer, so the snapshot can become
+// stale; call Client.ShardZones again to refresh it.
+type ShardZones struct {
+	// Namespace is the namespace of the collection, in "database.collection" form.
+	Namespace string
+
+	// Key is the shard key of the collection.
+	Key bson.Raw
+
+	// Chunks are the chunks of the collection, sorted by Min.
+	Chunks []ShardChunk
+
+	// Zones are the zone ranges of the collection, sorted by Min.
+	Zones []ShardZoneRange
+
+	fields []string
+	hashed []bool
+}
+
+// ShardZones reads the shard key, chunks, and zone ranges of the sharded collection with the
+// namespace ns, in "database.collection" form, from the config database of the cluster. It
+// returns an error wrapping ErrCollectionNotSharded if the collection is not sharded.
+//
+// Reading the config database requires the clusterMonitor role or equivalent privileges.
+func (c *Client) ShardZones(ctx context.Context, ns string) (*ShardZones, error) {
+	config := c.Database("config")
+
+	var coll struct {
+		Key     bson.Raw       `bson:"key"`
+		UUID    *bson.RawValue `bson:"uuid"`
+		Dropped bool           `bson:"dropped"`
+	}
+	err := config.Collection("collections").FindOne(ctx, bson.D{{"_id", ns}}).Decode(&coll)
+	if errors.Is(err, ErrNoDocuments) || (err == nil && coll.Dropped) {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotSharded, ns)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Chunks are keyed by collection UUID since MongoDB 5.0 and by namespace before.
+	chunkFilter := bson.D{{"ns", ns}}
+	if coll.UUID != nil {
+		chunkFilter = bson.D{{"$or", bson.A{bson.D{{"uuid", *coll.UUID}}, chunkFilter}}}
+	}
+	sortByMin := options.Find().SetSort(bson.D{{"min", 1}})
+
+	var chunks []ShardChunk
+	cur, err := config.Collection("chunks").Find(ctx, chunkFilter, sortByMin)
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &chunks); err != nil {
+		return nil, err
+	}
+
+	var zones []struct {
+		Min bson.Raw `bson:"min"`
+		Max bson.Raw `bson:"max"`
+		Tag string   `bson:"tag"`
+	}
+	cur, err = config.Collection("tags").Find(ctx, bson.D{{"ns", ns}}, sortByMin)
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &zones); err != nil {
+		return nil, err
+	}
+
+	zoneRanges := make([]ShardZoneRange, 0, len(zones))
+	for _, z := range zones {
+		zoneRanges = append(zoneRanges, ShardZoneRange{Min: z.Min, Max: z.Max, Zone: z.Tag})
+	}
+	return newShardZones(ns, coll.Key, chunks, zoneRanges)
+}
+
+func newShardZones(ns string, key bson.Raw, chunks []ShardChunk, zones []ShardZoneRange) (*ShardZones, error) {
+	elems, err := key.Elements()
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard key for %s: %w", ns, err)
+	}
+	if len(elems) == 0 {
+		return nil, fmt.Errorf("%w: %s has an empty shard key", ErrCollectionNotSharded, ns)
+	}
+
+	sz := &ShardZones{
+		Namespace: ns,
+		Key:       key,
+		Chunks:    chunks,
+		Zones:     zones,
+	}
+	for _, elem := range elems {
+		s, ok := elem.Value().StringValueOK()
+		sz.fields = append(sz.fields, elem.Key())
+		sz.hashed = append(sz.hashed, ok && s == "hashed")
+	}
+	return sz, nil
+}
+
+// Locate returns the shard and zone of the document with the given shard key value. shardKey must
+// be a document, such as the document to be inserted, that contains the shard key fields. Shard key
+// fields missing from shardKey are treated as null, as the server does. It returns
+// ErrHashedShardKey if the shard key is hashed.
+func (sz *ShardZones) Locate(shardKey interface{}) (ShardPlacement, error) {
+	doc, err := marshal(shardKey, nil, nil)
+	if err != nil {
+		return ShardPlacement{}, err
+	}
+
+	key := make([]bson.RawValue, len(sz.fields))
+	for i, field := range sz.fields {
+		if sz.hashed[i] {
+			return ShardPlacement{}, ErrHashedShardKey
+		}
+		key[i] = lookupShardKeyField(bson.Raw(doc), field)
+	}
+
+	for _, chunk := range sz.Chunks {
+		if compareShardKeys(key, chunk.Min) >= 0 && compareShardKeys(key, chunk.Max) < 0 {
+			return ShardPlacement{Shard: chunk.Shard, Zone: sz.zone(key), Chunk: chunk}, nil
+		}
+	}
+	return ShardPlacement{}, fmt.Errorf("no chunk of %s contains shard key %v", sz.Namespace, doc)
+}
+
+// Targets returns the sorted names of the shards that a query with the given filter would be sent
+// to. A filter with equality conditions on all shard key fields targets a single shard, and a
+// filter with equality conditions on a prefix of the shard key targets the shards owning chunks
+// of that prefix. Any other filter is broadcast to all shards that own chunks of the collection,
+// which is known as a scatter-gather query. Conditions on hashed shard key fields are not used.
+func (sz *ShardZones) Targets(filter interface{}) ([]string, error) {
+	doc, err := marshal(filter, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	shards, _ := sz.targets(bson.Raw(doc))
+	return shards, nil
+}
+
+// targets returns the shards targeted by filter and the number of shard key fields, starting from
+// the first, that have equality conditions in filter.
+func (sz *ShardZones) targets(filter bson.Raw) ([]string, int) {
+	// Build the range of shard key values matched by the equality conditions on the shard key
+	// prefix, with MinKey and MaxKey for the remaining fields.
+	lower := make([]bson.RawValue, len(sz.fields))
+	upper := make([]bson.RawValue, len(sz.fields))
+	prefix := 0
+	for ; prefix < len(sz.fields) && !sz.hashed[prefix]; prefix++ {
+		val, ok := equalityValue(filter, sz.fields[prefix])
+		if !ok {
+			break
+		}
+		lower[prefix], upper[prefix] = val, val
+	}
+	for i := prefix; i < len(sz.fields); i++ {
+		lower[i] = bson.RawValue{Type: bson.TypeMinKey}
+		upper[i] = bson.RawValue{Type: bson.TypeMaxKey}
+	}
+
+	seen := make(map[string]bool)
+	var shards []string
+	for _, chunk := range sz.Chunks {
+		// The MaxKey values padding a prefix are never stored, so a range ending in them does not
+		// include a chunk starting at them.
+		c := compareShardKeys(upper, chunk.Min)
+		overlaps := (c > 0 || c == 0 && prefix == len(sz.fields)) && compareShardKeys(lower, chunk.Max) < 0
+		if !overlaps || seen[chunk.Shard] {
+			continue
+		}
+		seen[chunk.Shard] = true
+		shards = append(shards, chunk.Shard)
+	}
+	sort.Strings(shards)
+	return shards, prefix
+}
+
+// zone returns the zone whose range contains key.
+func (sz *ShardZones) zone(key []bson.RawValue) string {
+	for _, z := range sz.Zones {
+		if compareShardKeys(key, z.Min) >= 0 && compareShardKeys(key, z.Max) < 0 {
+			return z.Zone
+		}
+	}
+	return ""
+}
+
+// lookupShardKeyField returns the value of the possibly dotted field in doc, or null if the field
+// is missing.
+func lookupShardKeyField(doc bson.Raw, field string) bson.RawValue {
+	val, err := doc.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return bson.RawValue{Type: bson.TypeNull}
+	}
+	return val
+}
+
+// equalityValue returns the value of an equality condition on field in a query filter.
+func equalityValue(filter bson.Raw, field string) (bson.RawValue, bool) {
+	val, err := filter.LookupErr(field)
+	if err != nil {
+		return bson.RawValue{}, false
+	}
+	cond, ok := val.DocumentOK()
+	if !ok {
+		return val, val.Type != bson.TypeArray && val.Type != bson.TypeRegex
+	}
+
+	elems, err := cond.Elements()
+	if err != nil || len(elems) == 0 || !strings.HasPrefix(elems[0].Key(), "$") {
+		// An embedded document matches by equality.
+		return val, true
+	}
+	if len(elems) == 1 && elems[0].Key() == "$eq" {
+		return elems[0].Value(), true
+	}
+	return bson.RawValue{}, false
+}
+
+// compareShardKeys compares the shard key value key to the chunk or zone bound, whose fields are
+// in shard key order.
+func compareShardKeys(key []bson.RawValue, bound bson.Raw) int {
+	values, err := bound.Values()
+	if err != nil {
+		return 0
+	}
+	for i, val := range key {
+		if i >= len(values) {
+			return 1
+		}
+		if c := compareValues(val, values[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// canonicalTypeOrder returns the position of t in the order the server uses to compare values of
+// different types.
+func canonicalTypeOrder(t bson.Type) int {
+	switch t {
+	case bson.TypeMinKey:
+		return 0
+	case bson.TypeNull, bson.TypeUndefined:
+		return 1
+	case bson.TypeDouble, bson.TypeInt32, bson.TypeInt64, bson.TypeDecimal128:
+		return 2
+	case bson.TypeString, bson.TypeSymbol:
+		return 3
+	case bson.TypeEmbeddedDocument:
+		return 4
+	case bson.TypeArray:
+		return 5
+	case bson.TypeBinary:
+		return 6
+	case bson.TypeObjectID:
+		return 7
+	case bson.TypeBoolean:
+		return 8
+	case bson.TypeDateTime:
+		return 9
+	case bson.TypeTimestamp:
+		return 10
+	case bson.TypeRegex:
+		return 11
+	case bson.TypeMaxKey:
+		return 13
+	}
+	return 12
+}
+
+// compareValues compares two BSON values in the order the server uses for shard key values.
+func compareValues(a, b bson.RawValue) int {
+	if oa, ob := canonicalTypeOrder(a.Type), canonicalTypeOrder(b.Type); oa != ob {
+		return compareInts(int64(oa), int64(ob))
+	}
+
+	switch a.Type {
+	case bson.TypeDouble, bson.TypeInt32, bson.TypeInt64, bson.TypeDecimal128:
+		ia, aIsInt := a.AsInt64OK()
+		ib, bIsInt := b.AsInt64OK()
+		if aIsInt && bIsInt && a.Type != bson.TypeDouble && b.Type != bson.TypeDouble {
+			return compareInts(ia, ib)
+		}
+		return compareFloats(numberAsFloat(a), numberAsFloat(b))
+	case bson.TypeString, bson.TypeSymbol:
+		return strings.Compare(stringValue(a), stringValue(b))
+	case bson.TypeEmbeddedDocument, bson.TypeArray:
+		return compareBSONDocuments(a.Value, b.Value, a.Type == bson.TypeArray)
+	case bson.TypeBinary:
+		sa, da := a.Binary()
+		sb, db := b.Binary()
+		if len(da) != len(db) {
+			return compareInts(int64(len(da)), int64(len(db)))
+		}
+		if sa != sb {
+			return compareInts(int64(sa), int64(sb))
+		}
+		return bytes.Compare(da, db)
+	case bson.TypeObjectID:
+		oa, ob := a.ObjectID(), b.ObjectID()
+		return bytes.Compare(oa[:], ob[:])
+	case bson.TypeBoolean:
+		return compareInts(boolInt(a.Boolean()), boolInt(b.Boolean()))
+	case bson.TypeDateTime:
+		return compareInts(a.DateTime(), b.DateTime())
+	case bson.TypeTimestamp:
+		ta, ia := a.Timestamp()
+		tb, ib := b.Timestamp()
+		if ta != tb {
+			return compareInts(int64(ta), int64(tb))
+		}
+		return compareInts(int64(ia), int64(ib))
+	case bson.TypeRegex:
+		pa, fa := a.Regex()
+		pb, fb := b.Regex()
+		if c := strings.Compare(pa, pb); c != 0 {
+			return c
+		}
+		return strings.Compare(fa, fb)
+	}
+	return bytes.Compare(a.Value, b.Value)
+}
+
+// compareBSONDocuments compares two documents or arrays element by element.
+func compareBSONDocuments(a, b []byte, array bool) int {
+	ea, _ := bson.Raw(a).Elements()
+	eb, _ := bson.Raw(b).Elements()
+	for i := 0; i < len(ea) && i < len(eb); i++ {
+		va, vb := ea[i].Value(), eb[i].Value()
+		if oa, ob := canonicalTypeOrder(va.Type), canonicalTypeOrder(vb.Type); oa != ob {
+			return compareInts(int64(oa), int64(ob))
+		}
+		if !array {
+			if c := strings.Compare(ea[i].Key(), eb[i].Key()); c != 0 {
+				return c
+			}
+		}
+		if c := compareValues(va, vb); c != 0 {
+			return c
+		}
+	}
+	return compareInts(int64(len(ea)), int64(len(eb)))
+}
+
+func numberAsFloat(v bson.RawValue) float64 {
+	switch v.Type {
+	case bson.TypeDouble:
+		return v.Double()
+	case bson.TypeInt32:
+		return float64(v.Int32())
+	case bson.TypeInt64:
+		return float64(v.Int64())
+	case bson.TypeDecimal128:
+		f, err := strconv.ParseFloat(v.Decimal128().String(), 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return f
+	}
+	return math.NaN()
+}
+
+func stringValue(v bson.RawValue) string {
+	if v.Type == bson.TypeSymbol {
+		return v.Symbol()
+	}
+	return v.StringValue()
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloats compares two floats, ordering NaN before all other numbers as the server does.
+func compareFloats(a, b float64) int {
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a):
+		return -1
+	case math.IsNaN(b):
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestShardZones(t *testing.T) {
+	raw := func(d bson.D) bson.Raw {
+		b, err := bson.Marshal(d)
+		require.NoError(t, err, "Marshal error: %v", err)
+		return b
+	}
+	minKey, maxKey := bson.MinKey{}, bson.MaxKey{}
+
+	// Shard key {region: 1, userId: 1} with the "EU" region split across two shards and the rest of
+	// the key space on a third shard.
+	chunks := []ShardChunk{
+		{Min: raw(bson.D{{"region", minKey}, {"userId", minKey}}), Max: raw(bson.D{{"region", "EU"}, {"userId", minKey}}), Shard: "shardNA"},
+		{Min: raw(bson.D{{"region", "EU"}, {"userId", minKey}}), Max: raw(bson.D{{"region", "EU"}, {"userId", 1000}}), Shard: "shardEU1"},
+		{Min: raw(bson.D{{"region", "EU"}, {"userId", 1000}}), Max: raw(bson.D{{"region", "EU"}, {"userId", maxKey}}), Shard: "shardEU2"},
+		{Min: raw(bson.D{{"region", "EU"}, {"userId", maxKey}}), Max: raw(bson.D{{"region", maxKey}, {"userId", maxKey}}), Shard: "shardNA"},
+	}
+	zones := []ShardZoneRange{
+		{Min: raw(bson.D{{"region", "EU"}, {"userId", minKey}}), Max: raw(bson.D{{"region", "EU"}, {"userId", maxKey}}), Zone: "europe"},
+	}
+	sz, err := newShardZones("db.users", raw(bson.D{{"region", 1}, {"userId", 1}}), chunks, zones)
+	require.NoError(t, err, "newShardZones error: %v", err)
+
+	t.Run("Locate", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			key   bson.D
+			shard string
+			zone  string
+		}{
+			{"zone lower chunk", bson.D{{"region", "EU"}, {"userId", int32(5)}}, "shardEU1", "europe"},
+			{"zone upper chunk", bson.D{{"region", "EU"}, {"userId", 1000.0}}, "shardEU2", "europe"},
+			{"outside zone", bson.D{{"region", "US"}, {"userId", 5}}, "shardNA", ""},
+			{"missing field is null", bson.D{{"userId", 5}}, "shardNA", ""},
+			{"extra fields", bson.D{{"name", "x"}, {"userId", int64(1)}, {"region", "EU"}}, "shardEU1", "europe"},
+		}
+		for _, tc := range testCases {
+			tc := tc // Capture range variable.
+
+			t.Run(tc.name, func(t *testing.T) {
+				p, err := sz.Locate(tc.key)
+				require.NoError(t, err, "Locate error: %v", err)
+				assert.Equal(t, tc.shard, p.Shard, "expected shard")
+				assert.Equal(t, tc.zone, p.Zone, "expected zone")
+			})
+		}
+	})
+	t.Run("Targets", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			filter bson.D
+			shards []string
+		}{
+			{"full shard key", bson.D{{"region", "EU"}, {"userId", 2000}}, []string{"shardEU2"}},
+			{"$eq", bson.D{{"region", bson.D{{"$eq", "EU"}}}, {"userId", bson.D{{"$eq", 1}}}}, []string{"shardEU1"}},
+			{"prefix", bson.D{{"region", "EU"}}, []string{"shardEU1", "shardEU2"}},
+			{"prefix outside zone", bson.D{{"region", "US"}, {"userId", bson.D{{"$gt", 1}}}}, []string{"shardNA"}},
+			{"scatter-gather", bson.D{{"userId", 1}}, []string{"shardEU1", "shardEU2", "shardNA"}},
+			{"range on prefix", bson.D{{"region", bson.D{{"$in", bson.A{"EU"}}}}}, []string{"shardEU1", "shardEU2", "shardNA"}},
+		}
+		for _, tc := range testCases {
+			tc := tc // Capture range variable.
+
+			t.Run(tc.name, func(t *testing.T) {
+				shards, err := sz.Targets(tc.filter)
+				require.NoError(t, err, "Targets error: %v", err)
+				assert.Equal(t, tc.shards, shards, "expected targeted shards")
+			})
+		}
+	})
+	t.Run("hashed", func(t *testing.T) {
+		hashed, err := newShardZones("db.users", raw(bson.D{{"userId", "hashed"}}), chunks[:1], nil)
+		require.NoError(t, err, "newShardZones error: %v", err)
+
+		_, err = hashed.Locate(bson.D{{"userId", 1}})
+		assert.ErrorIs(t, err, ErrHashedShardKey, "expected hashed shard key error")
+	})
+	t.Run("compareValues", func(t *testing.T) {
+		val := func(v interface{}) bson.RawValue {
+			typ, data, err := bson.MarshalValue(v)
+			require.NoError(t, err, "MarshalValue error: %v", err)
+			return bson.RawValue{Type: typ, Value: data}
+		}
+		ordered := []interface{}{
+			minKey, bson.Null{}, int32(-1), 0.5, int64(2), "a", "b", bson.D{{"a", 1}}, bson.A{1},
+			bson.Binary{Data: []byte{1}}, bson.NewObjectID(), false, true, bson.DateTime(0), maxKey,
+		}
+		for i := 1; i < len(ordered); i++ {
+			a, b := val(ordered[i-1]), val(ordered[i])
+			assert.Equal(t, -1, compareValues(a, b), "expected %v < %v", ordered[i-1], ordered[i])
+			assert.Equal(t, 1, compareValues(b, a), "expected %v > %v", ordered[i], ordered[i-1])
+		}
+		assert.Equal(t, 0, compareValues(val(int32(3)), val(3.0)), "expected numbers of different types to be equal")
+	})
+}
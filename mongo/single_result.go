@@ -29,6 +29,9 @@ type SingleResult struct {
 	bsonOpts *options.BSONOptions
 	reg      *bson.Registry
 
+	// transforms strips the derived fields of the document when it is decoded, if set.
+	transforms *fieldTransformers
+
 	// Operation performed with an acknowledged write. Values returned by
 	// SingleResult methods may not be deterministic if the write operation was
 	// unacknowledged and so should not be relied upon.
@@ -82,7 +85,11 @@ func (sr *SingleResult) Decode(v interface{}) error {
 		return sr.err
 	}
 
-	dec := getDecoder(sr.rdr, sr.bsonOpts, sr.reg)
+	doc, err := sr.transforms.strip(sr.rdr)
+	if err != nil {
+		return err
+	}
+	dec := getDecoder(doc, sr.bsonOpts, sr.reg)
 
 	return dec.Decode(v)
 }
@@ -138,3 +145,43 @@ func (sr *SingleResult) Err() error {
 
 	return sr.err
 }
+
+// RawValue returns the document represented by this SingleResult, or nil if the operation failed
+// or returned no documents. Unlike Raw, RawValue does not return an error, which can be checked
+// with Err, so it can be used in expressions:
+//
+//	if doc := coll.FindOne(ctx, filter).RawValue(); doc != nil {
+//		name := doc.Lookup("name").StringValue()
+//	}
+func (sr *SingleResult) RawValue() bson.Raw {
+	if sr.err = sr.setRdrContents(); sr.err != nil {
+		return nil
+	}
+
+	return sr.rdr
+}
+
+// Exists reports whether the operation that created this SingleResult returned a document,
+// without decoding it. If the operation returned no documents, Exists returns false and a nil
+// error instead of ErrNoDocuments. If there was an error from the operation, Exists returns false
+// and that error.
+func (sr *SingleResult) Exists() (bool, error) {
+	switch err := sr.Err(); {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNoDocuments):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// DecodeTo decodes the document represented by sr into a new value of type T and returns it. The
+// errors are the same as those of SingleResult.Decode.
+//
+//	user, err := mongo.DecodeTo[User](coll.FindOne(ctx, bson.D{{"_id", id}}))
+func DecodeTo[T any](sr *SingleResult) (T, error) {
+	var v T
+	err := sr.Decode(&v)
+	return v, err
+}
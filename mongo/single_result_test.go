@@ -135,3 +135,55 @@ func TestSingleResult_Err(t *testing.T) {
 		assert.Equal(t, ErrNoDocuments, sr.Err(), "expected error %v, got %v", ErrNoDocuments, sr.Err())
 	})
 }
+
+func TestSingleResult_RawValue(t *testing.T) {
+	t.Run("document", func(t *testing.T) {
+		sr := NewSingleResultFromDocument(bson.D{{"x", 1}}, nil, nil)
+		doc := sr.RawValue()
+		assert.NotNil(t, doc, "expected document")
+		assert.Equal(t, int32(1), doc.Lookup("x").Int32(), "document mismatch")
+		assert.Nil(t, sr.Err(), "Err error: %v", sr.Err())
+	})
+	t.Run("no documents", func(t *testing.T) {
+		sr := &SingleResult{}
+		assert.Nil(t, sr.RawValue(), "expected nil document")
+		assert.Equal(t, ErrNoDocuments, sr.Err(), "expected error %v, got %v", ErrNoDocuments, sr.Err())
+	})
+}
+
+func TestSingleResult_Exists(t *testing.T) {
+	testCases := []struct {
+		name    string
+		sr      *SingleResult
+		want    bool
+		wantErr error
+	}{
+		{"document", NewSingleResultFromDocument(bson.D{{"x", 1}}, nil, nil), true, nil},
+		{"no documents", &SingleResult{}, false, nil},
+		{"error", &SingleResult{err: errors.New("find error")}, false, errors.New("find error")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.sr.Exists()
+			assert.Equal(t, tc.want, got, "expected Exists %v, got %v", tc.want, got)
+			assert.Equal(t, tc.wantErr, err, "expected error %v, got %v", tc.wantErr, err)
+		})
+	}
+}
+
+func TestDecodeTo(t *testing.T) {
+	type doc struct {
+		X int `bson:"x"`
+	}
+
+	t.Run("document", func(t *testing.T) {
+		got, err := DecodeTo[doc](NewSingleResultFromDocument(bson.D{{"x", 1}}, nil, nil))
+		assert.Nil(t, err, "DecodeTo error: %v", err)
+		assert.Equal(t, doc{X: 1}, got, "expected %v, got %v", doc{X: 1}, got)
+	})
+	t.Run("no documents", func(t *testing.T) {
+		got, err := DecodeTo[doc](&SingleResult{reg: defaultRegistry})
+		assert.Equal(t, ErrNoDocuments, err, "expected error %v, got %v", ErrNoDocuments, err)
+		assert.Equal(t, doc{}, got, "expected zero value, got %v", got)
+	})
+}
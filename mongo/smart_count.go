@@ -0,0 +1,229 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// ErrCountRequiresCollectionScan is returned by Collection.SmartCount with options.CountAccuracyFast
+// if no index covers the filter, so the documents could only be counted by scanning the collection.
+var ErrCountRequiresCollectionScan = errors.New("no index covers the count filter")
+
+// CountStrategy is the strategy used by Collection.SmartCount to count documents.
+type CountStrategy string
+
+// These constants are the strategies used by Collection.SmartCount.
+const (
+	// CountStrategyEstimated counts the documents with the collection metadata, see
+	// Collection.EstimatedDocumentCount.
+	CountStrategyEstimated CountStrategy = "estimated"
+
+	// CountStrategyIndex counts the documents with countDocuments hinted to use an index that
+	// covers the filter.
+	CountStrategyIndex CountStrategy = "index"
+
+	// CountStrategyCountDocuments counts the documents with countDocuments without a hint, which
+	// may scan the collection.
+	CountStrategyCountDocuments CountStrategy = "countDocuments"
+)
+
+// SmartCountResult is the result of Collection.SmartCount.
+type SmartCountResult struct {
+	// Count is the number of documents.
+	Count int64
+
+	// Strategy is the strategy used to count the documents.
+	Strategy CountStrategy
+
+	// Index is the name of the index used by CountStrategyIndex.
+	Index string
+
+	// Exact is true if the count is exact, i.e. it was not estimated from the collection metadata.
+	Exact bool
+}
+
+// SmartCount counts the documents of the collection that match the filter, choosing how to count
+// them from the filter and the accuracy of the options:
+//
+//   - Without a filter, the documents are counted with EstimatedDocumentCount, unless the accuracy
+//     is options.CountAccuracyExact, which counts them with CountDocuments.
+//   - With a filter, the documents are counted with CountDocuments hinted to use the index with
+//     the fewest keys that covers the filter, if there is one. An index covers the filter if the
+//     filter only has conditions on top-level fields that are keys of the index, including its
+//     first key, and the index is a regular index that is not sparse, partial, hidden or
+//     collated, so it references every document. Otherwise the documents are counted with
+//     CountDocuments without a hint, unless the accuracy is options.CountAccuracyFast, which
+//     returns ErrCountRequiresCollectionScan.
+//
+// The filter parameter can be nil or an empty document to count all documents in the collection.
+// Choosing an index lists the indexes of the collection, which takes an additional round trip to
+// the server.
+//
+// The opts parameter can be used to specify options for the operation (see the options.SmartCountOptions
+// documentation).
+func (coll *Collection) SmartCount(
+	ctx context.Context,
+	filter interface{},
+	opts ...options.Lister[options.SmartCountOptions],
+) (*SmartCountResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	args, err := mongoutil.NewOptions[options.SmartCountOptions](opts...)
+	if err != nil {
+		return nil, err
+	}
+	accuracy := options.CountAccuracyBalanced
+	if args.Accuracy != nil {
+		accuracy = *args.Accuracy
+	}
+
+	var filterDoc bsoncore.Document
+	var elems []bsoncore.Element
+	if filter != nil {
+		filterDoc, err = marshal(filter, coll.bsonOpts, coll.registry)
+		if err != nil {
+			return nil, err
+		}
+		if elems, err = filterDoc.Elements(); err != nil {
+			return nil, err
+		}
+	}
+
+	countOpts := options.Count()
+	if args.Comment != nil {
+		countOpts.SetComment(args.Comment)
+	}
+
+	if len(elems) == 0 {
+		if accuracy != options.CountAccuracyExact {
+			estimateOpts := options.EstimatedDocumentCount()
+			if args.Comment != nil {
+				estimateOpts.SetComment(args.Comment)
+			}
+			n, err := coll.EstimatedDocumentCount(ctx, estimateOpts)
+			if err != nil {
+				return nil, err
+			}
+			return &SmartCountResult{Count: n, Strategy: CountStrategyEstimated}, nil
+		}
+
+		n, err := coll.CountDocuments(ctx, bson.D{}, countOpts)
+		if err != nil {
+			return nil, err
+		}
+		return &SmartCountResult{Count: n, Strategy: CountStrategyCountDocuments, Exact: true}, nil
+	}
+
+	index, err := coll.coveringCountIndex(ctx, elems)
+	if err != nil {
+		return nil, err
+	}
+	if index != "" {
+		n, err := coll.CountDocuments(ctx, bson.Raw(filterDoc), countOpts.SetHint(index))
+		if err != nil {
+			return nil, err
+		}
+		return &SmartCountResult{Count: n, Strategy: CountStrategyIndex, Index: index, Exact: true}, nil
+	}
+	if accuracy == options.CountAccuracyFast {
+		return nil, ErrCountRequiresCollectionScan
+	}
+
+	n, err := coll.CountDocuments(ctx, bson.Raw(filterDoc), countOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &SmartCountResult{Count: n, Strategy: CountStrategyCountDocuments, Exact: true}, nil
+}
+
+// coveringCountIndex returns the name of the index with the fewest keys that covers the filter
+// with the given elements, see Collection.SmartCount, or an empty string if there is none.
+func (coll *Collection) coveringCountIndex(
+	ctx context.Context,
+	filter []bsoncore.Element,
+) (string, error) {
+	fields := make([]string, 0, len(filter))
+	for _, elem := range filter {
+		key := elem.Key()
+		if strings.HasPrefix(key, "$") {
+			return "", nil
+		}
+		fields = append(fields, key)
+	}
+
+	specs, err := coll.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error listing indexes: %w", err)
+	}
+
+	var best string
+	bestKeys := 0
+	for _, spec := range specs {
+		keys, ok := countIndexKeys(spec)
+		if !ok || !containsFirstKey(spec, fields) {
+			continue
+		}
+		covered := true
+		for _, field := range fields {
+			covered = covered && keys[field]
+		}
+		if covered && (best == "" || len(keys) < bestKeys) {
+			best, bestKeys = spec.Name, len(keys)
+		}
+	}
+	return best, nil
+}
+
+// countIndexKeys returns the keys of the index if it references every document of the collection
+// in key order, i.e. it is an ascending or descending index that is not sparse, partial, hidden,
+// clustered or collated.
+func countIndexKeys(spec IndexSpecification) (map[string]bool, bool) {
+	if isTrue(spec.Sparse) || isTrue(spec.Hidden) || isTrue(spec.Clustered) ||
+		spec.Collation != nil || spec.PartialFilterExpression != nil {
+		return nil, false
+	}
+	elems, err := spec.KeysDocument.Elements()
+	if err != nil || len(elems) == 0 {
+		return nil, false
+	}
+	keys := make(map[string]bool, len(elems))
+	for _, elem := range elems {
+		switch elem.Value().Type {
+		case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble:
+		default:
+			return nil, false
+		}
+		keys[elem.Key()] = true
+	}
+	return keys, true
+}
+
+// containsFirstKey returns true if the first key of the index is one of the fields.
+func containsFirstKey(spec IndexSpecification, fields []string) bool {
+	first := spec.KeysDocument.Index(0).Key()
+	for _, field := range fields {
+		if field == first {
+			return true
+		}
+	}
+	return false
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+// newSmartCountTestCollection returns a collection and the commands it sends to a mock deployment
+// that replies with responses.
+func newSmartCountTestCollection(t *testing.T, responses ...bson.D) (*Collection, *[]bson.Raw) {
+	t.Helper()
+
+	var started []bson.Raw
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			started = append(started, evt.Command)
+		},
+	}
+	md := drivertest.NewMockDeployment(responses...)
+	clientOpts := options.Client().SetMonitor(monitor)
+	clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+		args.Deployment = md
+
+		return nil
+	})
+	client, err := Connect(clientOpts)
+	require.NoError(t, err, "Connect error")
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+	return client.Database("db").Collection("coll"), &started
+}
+
+func smartCountCursorReply(docs ...interface{}) bson.D {
+	return bson.D{{"ok", 1}, {"cursor", bson.D{
+		{"id", int64(0)},
+		{"ns", "db.coll"},
+		{"firstBatch", append(bson.A{}, docs...)},
+	}}}
+}
+
+func TestCollectionSmartCount(t *testing.T) {
+	t.Parallel()
+
+	indexes := smartCountCursorReply(
+		bson.D{{"v", 2}, {"key", bson.D{{"_id", 1}}}, {"name", "_id_"}},
+		bson.D{{"v", 2}, {"key", bson.D{{"a", 1}, {"b", -1}, {"c", 1}}}, {"name", "a_1_b_-1_c_1"}},
+		bson.D{{"v", 2}, {"key", bson.D{{"a", 1}, {"b", 1}}}, {"name", "a_1_b_1"}},
+		bson.D{{"v", 2}, {"key", bson.D{{"s", 1}}}, {"name", "s_1"}, {"sparse", true}},
+		bson.D{{"v", 2}, {"key", bson.D{{"t", "text"}}}, {"name", "t_text"}},
+	)
+	countReply := smartCountCursorReply(bson.D{{"n", int32(3)}})
+
+	testCases := []struct {
+		name      string
+		filter    interface{}
+		accuracy  options.CountAccuracy
+		responses []bson.D
+		want      *SmartCountResult
+		wantErr   error
+		wantHint  string
+	}{
+		{
+			name:      "no filter",
+			filter:    nil,
+			accuracy:  options.CountAccuracyBalanced,
+			responses: []bson.D{{{"ok", 1}, {"n", int64(7)}}},
+			want:      &SmartCountResult{Count: 7, Strategy: CountStrategyEstimated},
+		},
+		{
+			name:      "no filter exact",
+			filter:    bson.D{},
+			accuracy:  options.CountAccuracyExact,
+			responses: []bson.D{countReply},
+			want:      &SmartCountResult{Count: 3, Strategy: CountStrategyCountDocuments, Exact: true},
+		},
+		{
+			name:      "covering index",
+			filter:    bson.D{{"b", 2}, {"a", bson.D{{"$gt", 1}}}},
+			accuracy:  options.CountAccuracyFast,
+			responses: []bson.D{indexes, countReply},
+			want:      &SmartCountResult{Count: 3, Strategy: CountStrategyIndex, Index: "a_1_b_1", Exact: true},
+			wantHint:  "a_1_b_1",
+		},
+		{
+			name:      "first key not in filter",
+			filter:    bson.D{{"b", 2}},
+			accuracy:  options.CountAccuracyBalanced,
+			responses: []bson.D{indexes, countReply},
+			want:      &SmartCountResult{Count: 3, Strategy: CountStrategyCountDocuments, Exact: true},
+		},
+		{
+			name:      "sparse index",
+			filter:    bson.D{{"s", 2}},
+			accuracy:  options.CountAccuracyFast,
+			responses: []bson.D{indexes},
+			wantErr:   ErrCountRequiresCollectionScan,
+		},
+		{
+			name:      "top-level operator",
+			filter:    bson.D{{"$or", bson.A{bson.D{{"a", 1}}, bson.D{{"a", 2}}}}},
+			accuracy:  options.CountAccuracyFast,
+			responses: nil,
+			wantErr:   ErrCountRequiresCollectionScan,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			coll, started := newSmartCountTestCollection(t, tc.responses...)
+			res, err := coll.SmartCount(context.Background(), tc.filter, options.SmartCount().SetAccuracy(tc.accuracy))
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr), "expected error %v, got %v", tc.wantErr, err)
+				return
+			}
+			require.NoError(t, err, "SmartCount error")
+			assert.Equal(t, tc.want, res, "result mismatch")
+
+			require.Len(t, *started, len(tc.responses), "expected one command per response")
+			last := (*started)[len(*started)-1]
+			hint, err := last.LookupErr("hint")
+			if tc.wantHint == "" {
+				assert.Error(t, err, "expected no hint")
+				return
+			}
+			require.NoError(t, err, "expected a hint")
+			assert.Equal(t, tc.wantHint, hint.StringValue(), "hint mismatch")
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrNoTenant is returned by the methods of a TenantRouter if their context does not carry a
+// tenant.
+var ErrNoTenant = errors.New("context does not carry a tenant")
+
+// ErrTenantRouterClosed is returned by the methods of a TenantRouter after it has been closed.
+var ErrTenantRouterClosed = errors.New("tenant router is closed")
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx that carries tenant, which a TenantRouter uses to route the
+// handles requested with the returned context.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantRouter routes the tenants carried by contexts to their databases and collections, e.g.
+//
+//	router, err := mongo.NewTenantRouter(client, options.TenantRouter().SetDatabasePrefix("tenant_"))
+//	...
+//	ctx = mongo.WithTenant(ctx, "acme")
+//	orders, err := router.Collection(ctx, "orders") // the "orders" collection of "tenant_acme"
+//
+// The Database and Collection handles of each tenant are created with the options of its
+// TenantConfig when they are first requested and cached until the tenant is evicted, so that
+// routing an operation does not resolve the tenant or clone options again.
+//
+// A TenantRouter is safe for concurrent use by multiple goroutines.
+type TenantRouter struct {
+	client        *Client
+	prefix        string
+	resolver      func(ctx context.Context, tenant string) (*options.TenantConfig, error)
+	clientOptions []options.Lister[options.ClientOptions]
+
+	mu         sync.Mutex
+	tenants    map[string]*tenantHandles
+	closed     bool
+	unregister func()
+}
+
+// tenantHandles are the cached handles of a tenant.
+type tenantHandles struct {
+	ready  chan struct{} // closed when db, client, and err are set
+	db     *Database
+	client *Client // the Client created for the credential of the tenant, if any
+	err    error
+
+	collMu sync.RWMutex
+	colls  map[string]*Collection
+}
+
+// NewTenantRouter creates a TenantRouter that routes tenants to the databases of client. The
+// returned router must be closed with Close if some tenants have a credential, to disconnect
+// their Clients. The router is registered with client, so client.Disconnect closes it if it is
+// still open.
+func NewTenantRouter(client *Client, opts ...options.Lister[options.TenantRouterOptions]) (*TenantRouter, error) {
+	if client == nil {
+		return nil, errors.New("tenant router requires a client")
+	}
+	args, err := mongoutil.NewOptions[options.TenantRouterOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	r := &TenantRouter{
+		client:        client,
+		resolver:      args.Resolver,
+		clientOptions: args.ClientOptions,
+		tenants:       make(map[string]*tenantHandles),
+	}
+	if args.DatabasePrefix != nil {
+		r.prefix = *args.DatabasePrefix
+	}
+	r.unregister = client.Register(r)
+	return r, nil
+}
+
+// Database returns the Database of the tenant carried by ctx. It returns ErrNoTenant if ctx does
+// not carry a tenant, and the error of the resolver if the tenant cannot be resolved.
+func (r *TenantRouter) Database(ctx context.Context) (*Database, error) {
+	h, err := r.handles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.db, nil
+}
+
+// Collection returns the collection with the given name in the Database of the tenant carried by
+// ctx. It returns the same errors as Database.
+func (r *TenantRouter) Collection(ctx context.Context, name string) (*Collection, error) {
+	h, err := r.handles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h.collMu.RLock()
+	coll, ok := h.colls[name]
+	h.collMu.RUnlock()
+	if ok {
+		return coll, nil
+	}
+
+	h.collMu.Lock()
+	defer h.collMu.Unlock()
+	if coll, ok := h.colls[name]; ok {
+		return coll, nil
+	}
+	coll = h.db.Collection(name)
+	h.colls[name] = coll
+	return coll, nil
+}
+
+// Evict removes the cached handles of tenant, so that the next request for the tenant resolves it
+// again, e.g. after its configuration changed. If the tenant has a Client for its credential, the
+// Client is disconnected with ctx once the handles are resolved.
+func (r *TenantRouter) Evict(ctx context.Context, tenant string) error {
+	r.mu.Lock()
+	h, ok := r.tenants[tenant]
+	delete(r.tenants, tenant)
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return h.disconnect(ctx)
+}
+
+// Close evicts all tenants, disconnects the Clients of their credentials, and unregisters the
+// router from its Client. The Client of the router is not disconnected. After Close, the methods
+// of the router return ErrTenantRouterClosed.
+func (r *TenantRouter) Close(ctx context.Context) error {
+	r.unregister()
+
+	r.mu.Lock()
+	tenants := r.tenants
+	r.tenants = nil
+	r.closed = true
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, h := range tenants {
+		if err := h.disconnect(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// handles returns the handles of the tenant carried by ctx, resolving the tenant if it is not
+// cached. Concurrent requests for a tenant that is being resolved wait for the resolution.
+func (r *TenantRouter) handles(ctx context.Context) (*tenantHandles, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, ErrTenantRouterClosed
+	}
+	h, ok := r.tenants[tenant]
+	if !ok {
+		h = &tenantHandles{ready: make(chan struct{}), colls: make(map[string]*Collection)}
+		r.tenants[tenant] = h
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		h.db, h.client, h.err = r.resolve(ctx, tenant)
+		close(h.ready)
+		if h.err != nil {
+			r.mu.Lock()
+			if r.tenants[tenant] == h {
+				delete(r.tenants, tenant)
+			}
+			r.mu.Unlock()
+		}
+	}
+
+	select {
+	case <-h.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if h.err != nil {
+		return nil, h.err
+	}
+	return h, nil
+}
+
+// resolve creates the Database of tenant and, if the tenant has a credential, its Client.
+func (r *TenantRouter) resolve(ctx context.Context, tenant string) (*Database, *Client, error) {
+	cfg := &options.TenantConfig{}
+	if r.resolver != nil {
+		var err error
+		if cfg, err = r.resolver(ctx, tenant); err != nil {
+			return nil, nil, fmt.Errorf("error resolving tenant %q: %w", tenant, err)
+		}
+		if cfg == nil {
+			cfg = &options.TenantConfig{}
+		}
+	}
+
+	name := cfg.Database
+	if name == "" {
+		name = r.prefix + tenant
+	}
+	if err := validateTenantDatabaseName(name); err != nil {
+		return nil, nil, fmt.Errorf("tenant %q: %w", tenant, err)
+	}
+
+	client := r.client
+	var owned *Client
+	if cfg.Credential != nil {
+		if len(r.clientOptions) == 0 {
+			return nil, nil, fmt.Errorf("tenant %q has a credential but the router has no client options", tenant)
+		}
+		opts := append(append([]options.Lister[options.ClientOptions](nil), r.clientOptions...),
+			options.Client().SetAuth(*cfg.Credential))
+		var err error
+		if owned, err = Connect(opts...); err != nil {
+			return nil, nil, fmt.Errorf("error connecting the client of tenant %q: %w", tenant, err)
+		}
+		client = owned
+	}
+
+	dbOpts := options.Database()
+	if cfg.ReadConcern != nil {
+		dbOpts.SetReadConcern(cfg.ReadConcern)
+	}
+	if cfg.WriteConcern != nil {
+		dbOpts.SetWriteConcern(cfg.WriteConcern)
+	}
+	if cfg.ReadPreference != nil {
+		dbOpts.SetReadPreference(cfg.ReadPreference)
+	}
+	return client.Database(name, dbOpts), owned, nil
+}
+
+// disconnect waits for the handles to be resolved and disconnects their Client, if any.
+func (h *tenantHandles) disconnect(ctx context.Context) error {
+	select {
+	case <-h.ready:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if h.client == nil {
+		return nil
+	}
+	return h.client.Disconnect(ctx)
+}
+
+// validateTenantDatabaseName returns an error if name is not a valid database name.
+func validateTenantDatabaseName(name string) error {
+	if name == "" {
+		return errors.New("database name cannot be empty")
+	}
+	if len(name) > 63 {
+		return fmt.Errorf("database name %q is longer than 63 bytes", name)
+	}
+	if i := strings.IndexAny(name, "/\\. \"$*<>:|?\x00"); i >= 0 {
+		return fmt.Errorf("database name %q contains the invalid character %q", name, name[i])
+	}
+	return nil
+}
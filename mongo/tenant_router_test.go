@@ -0,0 +1,158 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestTenantRouter(t *testing.T) {
+	t.Parallel()
+
+	newClient := func(t *testing.T) *Client {
+		t.Helper()
+
+		clientOpts := options.Client()
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = drivertest.NewMockDeployment()
+
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+		return client
+	}
+
+	t.Run("routes and caches", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t)
+		var resolved int32
+		router, err := NewTenantRouter(client, options.TenantRouter().
+			SetDatabasePrefix("tenant_").
+			SetResolver(func(_ context.Context, tenant string) (*options.TenantConfig, error) {
+				atomic.AddInt32(&resolved, 1)
+				if tenant == "big" {
+					return &options.TenantConfig{Database: "dedicated", WriteConcern: writeconcern.Majority()}, nil
+				}
+				return nil, nil
+			}))
+		require.NoError(t, err, "NewTenantRouter error")
+
+		ctx := WithTenant(context.Background(), "acme")
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := router.Database(ctx)
+				assert.NoError(t, err, "Database error")
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&resolved), "expected the tenant to be resolved once")
+
+		db, err := router.Database(ctx)
+		require.NoError(t, err, "Database error")
+		assert.Equal(t, "tenant_acme", db.Name(), "database name mismatch")
+		assert.Equal(t, client, db.Client(), "expected the router's client")
+
+		coll, err := router.Collection(ctx, "orders")
+		require.NoError(t, err, "Collection error")
+		again, err := router.Collection(ctx, "orders")
+		require.NoError(t, err, "Collection error")
+		assert.True(t, coll == again, "expected the collection handle to be cached")
+		assert.Equal(t, "tenant_acme", coll.Database().Name(), "collection database mismatch")
+
+		big, err := router.Collection(WithTenant(context.Background(), "big"), "orders")
+		require.NoError(t, err, "Collection error")
+		assert.Equal(t, "dedicated", big.Database().Name(), "database name mismatch")
+		assert.Equal(t, writeconcern.Majority(), big.writeConcern, "write concern mismatch")
+
+		require.NoError(t, router.Evict(context.Background(), "acme"), "Evict error")
+		evicted, err := router.Collection(ctx, "orders")
+		require.NoError(t, err, "Collection error")
+		assert.False(t, coll == evicted, "expected a new handle after eviction")
+		assert.Equal(t, int32(3), atomic.LoadInt32(&resolved), "expected the evicted tenant to be resolved again")
+
+		require.NoError(t, router.Close(context.Background()), "Close error")
+		_, err = router.Database(ctx)
+		assert.ErrorIs(t, err, ErrTenantRouterClosed, "expected ErrTenantRouterClosed")
+	})
+
+	t.Run("resolver errors are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		errCatalog := errors.New("catalog unavailable")
+		fail := true
+		router, err := NewTenantRouter(newClient(t), options.TenantRouter().
+			SetResolver(func(context.Context, string) (*options.TenantConfig, error) {
+				if fail {
+					return nil, errCatalog
+				}
+				return &options.TenantConfig{}, nil
+			}))
+		require.NoError(t, err, "NewTenantRouter error")
+
+		ctx := WithTenant(context.Background(), "acme")
+		_, err = router.Database(ctx)
+		assert.ErrorIs(t, err, errCatalog, "expected the resolver error")
+
+		fail = false
+		_, err = router.Database(ctx)
+		assert.NoError(t, err, "expected the tenant to be resolved again")
+	})
+
+	t.Run("credential", func(t *testing.T) {
+		t.Parallel()
+
+		cred := &options.Credential{Username: "acme", Password: "secret"}
+		resolver := func(context.Context, string) (*options.TenantConfig, error) {
+			return &options.TenantConfig{Credential: cred}, nil
+		}
+		ctx := WithTenant(context.Background(), "acme")
+
+		router, err := NewTenantRouter(newClient(t), options.TenantRouter().SetResolver(resolver))
+		require.NoError(t, err, "NewTenantRouter error")
+		_, err = router.Database(ctx)
+		assert.ErrorContains(t, err, "no client options", "expected an error without client options")
+
+		client := newClient(t)
+		router, err = NewTenantRouter(client, options.TenantRouter().
+			SetResolver(resolver).
+			SetClientOptions(options.Client().ApplyURI("mongodb://localhost:27017")))
+		require.NoError(t, err, "NewTenantRouter error")
+		db, err := router.Database(ctx)
+		require.NoError(t, err, "Database error")
+		assert.False(t, db.Client() == client, "expected a client for the tenant credential")
+		assert.NotNil(t, db.Client().authenticator, "expected the tenant client to authenticate")
+		assert.NoError(t, router.Close(context.Background()), "Close error")
+	})
+
+	t.Run("invalid tenant", func(t *testing.T) {
+		t.Parallel()
+
+		router, err := NewTenantRouter(newClient(t))
+		require.NoError(t, err, "NewTenantRouter error")
+
+		_, err = router.Database(context.Background())
+		assert.ErrorIs(t, err, ErrNoTenant, "expected ErrNoTenant")
+		_, err = router.Collection(WithTenant(context.Background(), "a.b"), "orders")
+		assert.ErrorContains(t, err, "invalid character", "expected an invalid database name error")
+	})
+}
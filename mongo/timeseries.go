@@ -0,0 +1,239 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// These constants are the granularities of time-series collections.
+const (
+	TimeSeriesGranularitySeconds = "seconds"
+	TimeSeriesGranularityMinutes = "minutes"
+	TimeSeriesGranularityHours   = "hours"
+)
+
+// ErrMissingTimeField is returned by Database.CreateTimeSeriesCollection if the time-series options
+// do not specify a time field.
+var ErrMissingTimeField = errors.New("time-series collections require a time field")
+
+// CreateTimeSeriesCollection creates a time-series collection with the specified name and
+// time-series options. Unlike passing the time-series options to CreateCollection, the options are
+// validated before the command is sent: a time field is required, the granularity must be one of
+// the TimeSeriesGranularity constants, and a custom bucket span must be set together with an equal
+// bucket rounding and without a granularity, as the server requires.
+//
+// The opts parameter can be used to specify other options for the collection, such as
+// SetExpireAfterSeconds to delete documents automatically (see the
+// options.CreateCollectionOptions documentation). Time-series options set in opts are overridden
+// by timeSeries.
+//
+// For more information about time-series collections, see
+// https://www.mongodb.com/docs/manual/core/timeseries-collections/.
+func (db *Database) CreateTimeSeriesCollection(
+	ctx context.Context,
+	name string,
+	timeSeries *options.TimeSeriesOptionsBuilder,
+	opts ...options.Lister[options.CreateCollectionOptions],
+) error {
+	if err := validateTimeSeriesOptions(timeSeries); err != nil {
+		return err
+	}
+
+	opts = append(opts, options.CreateCollection().SetTimeSeriesOptions(timeSeries))
+	return db.CreateCollection(ctx, name, opts...)
+}
+
+func validateTimeSeriesOptions(timeSeries *options.TimeSeriesOptionsBuilder) error {
+	if timeSeries == nil {
+		return ErrMissingTimeField
+	}
+	args, err := mongoutil.NewOptions[options.TimeSeriesOptions](timeSeries)
+	if err != nil {
+		return fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	if args.TimeField == "" {
+		return ErrMissingTimeField
+	}
+	if args.MetaField != nil && (*args.MetaField == args.TimeField || *args.MetaField == "_id") {
+		return fmt.Errorf("time-series meta field %q must differ from the time field and _id", *args.MetaField)
+	}
+	if g := args.Granularity; g != nil {
+		switch *g {
+		case TimeSeriesGranularitySeconds, TimeSeriesGranularityMinutes, TimeSeriesGranularityHours:
+		default:
+			return fmt.Errorf("invalid time-series granularity %q: must be %q, %q, or %q", *g,
+				TimeSeriesGranularitySeconds, TimeSeriesGranularityMinutes, TimeSeriesGranularityHours)
+		}
+	}
+	if args.BucketMaxSpan == nil && args.BucketRounding == nil {
+		return nil
+	}
+	if args.Granularity != nil {
+		return errors.New("time-series bucket span and rounding cannot be set with a granularity")
+	}
+	if args.BucketMaxSpan == nil || args.BucketRounding == nil ||
+		*args.BucketMaxSpan/time.Second != *args.BucketRounding/time.Second {
+		return errors.New("time-series bucket span and rounding must be set together to the same number of seconds")
+	}
+	if *args.BucketMaxSpan < time.Second {
+		return errors.New("time-series bucket span must be at least one second")
+	}
+	return nil
+}
+
+// SetExpiration runs the collMod command to set the time after which documents in the collection
+// are deleted automatically. For time-series collections, documents are deleted once the value of
+// the time field is older than ttl. A ttl of 0 or less disables automatic deletion.
+//
+// For other collections, use a TTL index instead; see
+// https://www.mongodb.com/docs/manual/core/index-ttl/.
+func (coll *Collection) SetExpiration(ctx context.Context, ttl time.Duration) error {
+	var expireAfterSeconds interface{} = "off"
+	if ttl > 0 {
+		expireAfterSeconds = int64(ttl / time.Second)
+	}
+	cmd := bson.D{
+		{"collMod", coll.name},
+		{"expireAfterSeconds", expireAfterSeconds},
+	}
+	return coll.db.RunCommand(ctx, cmd).Err()
+}
+
+// These constants are the units of DensifyStage.Unit.
+const (
+	DensifyUnitMillisecond = "millisecond"
+	DensifyUnitSecond      = "second"
+	DensifyUnitMinute      = "minute"
+	DensifyUnitHour        = "hour"
+	DensifyUnitDay         = "day"
+	DensifyUnitWeek        = "week"
+	DensifyUnitMonth       = "month"
+	DensifyUnitQuarter     = "quarter"
+	DensifyUnitYear        = "year"
+)
+
+// These constants are the values of DensifyStage.Bounds that do not specify an explicit range.
+const (
+	// DensifyBoundsFull densifies the range between the lowest and highest values of the field in
+	// all documents.
+	DensifyBoundsFull = "full"
+
+	// DensifyBoundsPartition densifies the range between the lowest and highest values of the field
+	// in each partition.
+	DensifyBoundsPartition = "partition"
+)
+
+// DensifyStage describes a $densify aggregation stage, which adds documents so that the values of a
+// field in a sequence of documents are spaced by a fixed step. It is typically used to fill gaps in
+// time-series data before a $fill stage.
+//
+// For more information about the stage, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/densify/.
+type DensifyStage struct {
+	// Field is the field to densify. It is required and must contain dates or numbers.
+	Field string
+
+	// PartitionByFields are the fields used to group documents that are densified separately.
+	PartitionByFields []string
+
+	// Step is the distance between consecutive values of the field. It is required.
+	Step interface{}
+
+	// Unit is the unit of Step if Field contains dates, such as DensifyUnitMinute. It must not be
+	// set if Field contains numbers.
+	Unit string
+
+	// Bounds is DensifyBoundsFull, DensifyBoundsPartition, or a two-element array with the lower and
+	// upper bounds of the range to densify. It is required.
+	Bounds interface{}
+}
+
+// Stage returns the $densify stage document.
+func (s DensifyStage) Stage() bson.D {
+	rng := bson.D{{"step", s.Step}}
+	if s.Unit != "" {
+		rng = append(rng, bson.E{"unit", s.Unit})
+	}
+	rng = append(rng, bson.E{"bounds", s.Bounds})
+
+	densify := bson.D{{"field", s.Field}}
+	if len(s.PartitionByFields) > 0 {
+		densify = append(densify, bson.E{"partitionByFields", s.PartitionByFields})
+	}
+	densify = append(densify, bson.E{"range", rng})
+	return bson.D{{"$densify", densify}}
+}
+
+// These constants are the methods of FillOutput.Method.
+const (
+	// FillMethodLinear fills missing values by linear interpolation between the surrounding
+	// values.
+	FillMethodLinear = "linear"
+
+	// FillMethodLOCF fills missing values with the last non-null value (last observation carried
+	// forward).
+	FillMethodLOCF = "locf"
+)
+
+// FillOutput describes how a $fill stage fills a missing or null field.
+type FillOutput struct {
+	// Field is the field to fill.
+	Field string
+
+	// Value is an expression evaluated to fill the field. Only one of Value and Method may be set.
+	Value interface{}
+
+	// Method is FillMethodLinear or FillMethodLOCF. Only one of Value and Method may be set. Both
+	// methods require SortBy to be set on the stage.
+	Method string
+}
+
+// FillStage describes a $fill aggregation stage, which populates missing and null field values.
+//
+// For more information about the stage, see
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/fill/.
+type FillStage struct {
+	// PartitionByFields are the fields used to group documents that are filled separately.
+	PartitionByFields []string
+
+	// SortBy is the order of the documents in each partition, such as bson.D{{"timestamp", 1}}.
+	SortBy bson.D
+
+	// Output are the fields to fill. At least one is required.
+	Output []FillOutput
+}
+
+// Stage returns the $fill stage document.
+func (s FillStage) Stage() bson.D {
+	fill := bson.D{}
+	if len(s.PartitionByFields) > 0 {
+		fill = append(fill, bson.E{"partitionByFields", s.PartitionByFields})
+	}
+	if len(s.SortBy) > 0 {
+		fill = append(fill, bson.E{"sortBy", s.SortBy})
+	}
+
+	output := make(bson.D, 0, len(s.Output))
+	for _, o := range s.Output {
+		if o.Method != "" {
+			output = append(output, bson.E{o.Field, bson.D{{"method", o.Method}}})
+		} else {
+			output = append(output, bson.E{o.Field, bson.D{{"value", o.Value}}})
+		}
+	}
+	fill = append(fill, bson.E{"output", output})
+	return bson.D{{"$fill", fill}}
+}
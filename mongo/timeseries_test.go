@@ -0,0 +1,95 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestValidateTimeSeriesOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    *options.TimeSeriesOptionsBuilder
+		wantErr string
+	}{
+		{"nil", nil, ErrMissingTimeField.Error()},
+		{"no time field", options.TimeSeries().SetMetaField("sensor"), ErrMissingTimeField.Error()},
+		{"valid", options.TimeSeries().SetTimeField("ts").SetMetaField("sensor").SetGranularity("minutes"), ""},
+		{"meta field is time field", options.TimeSeries().SetTimeField("ts").SetMetaField("ts"), "must differ"},
+		{"invalid granularity", options.TimeSeries().SetTimeField("ts").SetGranularity("days"), "invalid time-series granularity"},
+		{
+			"custom buckets",
+			options.TimeSeries().SetTimeField("ts").SetBucketMaxSpan(time.Hour).SetBucketRounding(time.Hour),
+			"",
+		},
+		{
+			"custom buckets with granularity",
+			options.TimeSeries().SetTimeField("ts").SetGranularity("hours").SetBucketMaxSpan(time.Hour).SetBucketRounding(time.Hour),
+			"cannot be set with a granularity",
+		},
+		{
+			"span without rounding",
+			options.TimeSeries().SetTimeField("ts").SetBucketMaxSpan(time.Hour),
+			"must be set together",
+		},
+		{
+			"unequal span and rounding",
+			options.TimeSeries().SetTimeField("ts").SetBucketMaxSpan(time.Hour).SetBucketRounding(time.Minute),
+			"must be set together",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTimeSeriesOptions(tc.opts)
+			if tc.wantErr == "" {
+				assert.NoError(t, err, "validateTimeSeriesOptions error: %v", err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr, "expected validation error")
+		})
+	}
+}
+
+func TestTimeSeriesStages(t *testing.T) {
+	t.Run("densify", func(t *testing.T) {
+		got := DensifyStage{
+			Field:             "ts",
+			PartitionByFields: []string{"sensor"},
+			Step:              15,
+			Unit:              DensifyUnitMinute,
+			Bounds:            DensifyBoundsPartition,
+		}.Stage()
+		want := bson.D{{"$densify", bson.D{
+			{"field", "ts"},
+			{"partitionByFields", []string{"sensor"}},
+			{"range", bson.D{{"step", 15}, {"unit", "minute"}, {"bounds", "partition"}}},
+		}}}
+		assert.Equal(t, want, got, "expected $densify stage")
+	})
+	t.Run("fill", func(t *testing.T) {
+		got := FillStage{
+			SortBy: bson.D{{"ts", 1}},
+			Output: []FillOutput{
+				{Field: "temperature", Method: FillMethodLinear},
+				{Field: "status", Value: "unknown"},
+			},
+		}.Stage()
+		want := bson.D{{"$fill", bson.D{
+			{"sortBy", bson.D{{"ts", 1}}},
+			{"output", bson.D{
+				{"temperature", bson.D{{"method", "linear"}}},
+				{"status", bson.D{{"value", "unknown"}}},
+			}},
+		}}}
+		assert.Equal(t, want, got, "expected $fill stage")
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// updatePipelineStages are the aggregation stages that can be used in a pipeline-style update.
+var updatePipelineStages = map[string]bool{
+	"$addFields":   true,
+	"$set":         true,
+	"$project":     true,
+	"$unset":       true,
+	"$replaceRoot": true,
+	"$replaceWith": true,
+}
+
+// errArrayFiltersWithPipeline is returned for a pipeline-style update with array filters, which the
+// server rejects.
+var errArrayFiltersWithPipeline = errors.New("arrayFilters cannot be used with a pipeline-style update")
+
+// validateUpdatePipeline returns an error if the pipeline-style update pipeline contains a stage
+// that cannot be used in updates, or if it is combined with array filters.
+func validateUpdatePipeline(pipeline bsoncore.Array, hasArrayFilters bool) error {
+	if hasArrayFilters {
+		return errArrayFiltersWithPipeline
+	}
+	values, err := pipeline.Values()
+	if err != nil {
+		return err
+	}
+	for i, val := range values {
+		stage, ok := val.DocumentOK()
+		if !ok {
+			return fmt.Errorf("update pipeline stage %d: expected a document, got BSON type %s", i, val.Type)
+		}
+		elems, err := stage.Elements()
+		if err != nil {
+			return err
+		}
+		if len(elems) != 1 {
+			return fmt.Errorf("update pipeline stage %d: expected a single stage, got %d fields", i, len(elems))
+		}
+		if name := elems[0].Key(); !updatePipelineStages[name] {
+			return fmt.Errorf("update pipeline stage %d: %s is not allowed in updates", i, name)
+		}
+	}
+	return nil
+}
+
+// validateUpdateModels checks the pipeline-style updates of the update models of a bulk write, so
+// that an invalid model fails the bulk write before any of its batches are sent rather than when
+// its batch is marshaled.
+func validateUpdateModels(models []WriteModel, bsonOpts *options.BSONOptions, registry *bson.Registry) error {
+	for i, model := range models {
+		var update interface{}
+		var arrayFilters []interface{}
+		switch converted := model.(type) {
+		case *UpdateOneModel:
+			update, arrayFilters = converted.Update, converted.ArrayFilters
+		case *UpdateManyModel:
+			update, arrayFilters = converted.Update, converted.ArrayFilters
+		default:
+			continue
+		}
+
+		// Documents are marshaled when their batch is run; only pipelines are checked here.
+		switch update.(type) {
+		case nil, bson.D, bson.Raw, bsoncore.Document, []byte, bson.Marshaler:
+			continue
+		}
+		u, err := marshalUpdateValue(update, bsonOpts, registry, true)
+		if err != nil {
+			return fmt.Errorf("invalid update at index %d: %w", i, err)
+		}
+		if u.Type != bsoncore.TypeArray {
+			continue
+		}
+		if err := validateUpdatePipeline(u.Data, arrayFilters != nil); err != nil {
+			return fmt.Errorf("invalid update at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
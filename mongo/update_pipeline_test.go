@@ -0,0 +1,122 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+func TestBulkWriteUpdatePipeline(t *testing.T) {
+	t.Parallel()
+
+	newCollection := func(t *testing.T, responses ...bson.D) (*Collection, *[]bson.Raw) {
+		t.Helper()
+
+		var started []bson.Raw
+		monitor := &event.CommandMonitor{
+			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+				started = append(started, evt.Command)
+			},
+		}
+		md := drivertest.NewMockDeployment(responses...)
+		clientOpts := options.Client().SetMonitor(monitor)
+		clientOpts.Opts = append(clientOpts.Opts, func(args *options.ClientOptions) error {
+			args.Deployment = md
+
+			return nil
+		})
+		client, err := Connect(clientOpts)
+		require.NoError(t, err, "Connect error")
+		t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+
+		return client.Database("db").Collection("coll"), &started
+	}
+
+	t.Run("pipeline update", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newCollection(t, bson.D{{"ok", 1}, {"n", 2}, {"nModified", 2}})
+		models := []WriteModel{
+			NewUpdateManyModel().
+				SetFilter(bson.D{}).
+				SetUpdate(Pipeline{
+					{{"$set", bson.D{{"total", bson.D{{"$add", bson.A{"$price", "$tax"}}}}}}},
+					{{"$unset", "tax"}},
+				}),
+		}
+		res, err := coll.BulkWrite(context.Background(), models)
+		require.NoError(t, err, "BulkWrite error")
+		assert.Equal(t, int64(2), res.ModifiedCount, "modified count mismatch")
+
+		require.Len(t, *started, 1, "expected a single command")
+		u := (*started)[0].Lookup("updates", "0", "u")
+		assert.Equal(t, bson.TypeArray, u.Type, "expected the update to be sent as a pipeline")
+	})
+
+	testCases := []struct {
+		name    string
+		model   WriteModel
+		wantErr string
+	}{
+		{
+			name: "stage not allowed",
+			model: NewUpdateOneModel().
+				SetFilter(bson.D{}).
+				SetUpdate(bson.A{bson.D{{"$set", bson.D{{"a", 1}}}}, bson.D{{"$match", bson.D{{"a", 1}}}}}),
+			wantErr: "invalid update at index 1: update pipeline stage 1: $match is not allowed in updates",
+		},
+		{
+			name: "multiple stages in a document",
+			model: NewUpdateManyModel().
+				SetFilter(bson.D{}).
+				SetUpdate(bson.A{bson.D{{"$set", bson.D{{"a", 1}}}, {"$unset", "b"}}}),
+			wantErr: "update pipeline stage 0: expected a single stage, got 2 fields",
+		},
+		{
+			name: "array filters",
+			model: NewUpdateManyModel().
+				SetFilter(bson.D{}).
+				SetUpdate(Pipeline{{{"$set", bson.D{{"a", 1}}}}}).
+				SetArrayFilters([]interface{}{bson.D{{"x.y", 1}}}),
+			wantErr: errArrayFiltersWithPipeline.Error(),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			coll, started := newCollection(t)
+			models := []WriteModel{
+				NewInsertOneModel().SetDocument(bson.D{{"x", 1}}),
+				tc.model,
+			}
+			_, err := coll.BulkWrite(context.Background(), models)
+			require.Error(t, err, "expected a BulkWrite error")
+			assert.Contains(t, err.Error(), tc.wantErr, "error mismatch")
+			assert.Len(t, *started, 0, "expected no command to be sent")
+		})
+	}
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		t.Parallel()
+
+		coll, started := newCollection(t)
+		_, err := coll.UpdateMany(context.Background(), bson.D{}, bson.A{bson.D{{"$group", bson.D{{"_id", 1}}}}})
+		assert.ErrorContains(t, err, "$group is not allowed in updates", "error mismatch")
+		assert.Len(t, *started, 0, "expected no command to be sent")
+	})
+}
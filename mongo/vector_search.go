@@ -0,0 +1,133 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/search"
+)
+
+const (
+	defaultVectorSearchScoreField = "score"
+
+	// maxVectorSearchNumCandidates is the maximum numCandidates value accepted by the server.
+	maxVectorSearchNumCandidates = 10000
+
+	// vectorSearchCandidatesPerResult is the multiple of Limit used for NumCandidates if it is not
+	// set, following the Atlas Vector Search recommendation to consider 10 to 20 times as many
+	// candidates as results.
+	vectorSearchCandidatesPerResult = 10
+)
+
+// VectorSearchOptions describes an Atlas Vector Search query run by Collection.VectorSearch.
+//
+// For more information about the $vectorSearch stage, see
+// https://www.mongodb.com/docs/atlas/atlas-vector-search/vector-search-stage/
+type VectorSearchOptions struct {
+	// Index is the name of the Atlas Vector Search index to use. It is required.
+	Index string
+
+	// Path is the indexed vector field to search. It is required.
+	Path string
+
+	// QueryVector is the vector to find the nearest neighbors of. It must be an array of numbers,
+	// such as a []float64 or []float32, a bson.Vector, or a bson.Binary with subtype
+	// bson.TypeBinaryVector. It is required.
+	QueryVector interface{}
+
+	// NumCandidates is the number of nearest neighbors to consider during an approximate search. If
+	// it is 0, ten times Limit is used, up to the server maximum of 10000. It is ignored if Exact is
+	// true.
+	NumCandidates int64
+
+	// Limit is the maximum number of documents to return. It must be greater than 0.
+	Limit int64
+
+	// Filter is an optional MQL filter applied to indexed filter fields before the search. It must
+	// be a document.
+	Filter interface{}
+
+	// Exact specifies whether to run an exact nearest neighbor search instead of an approximate one.
+	Exact bool
+
+	// ScoreField is the name of the field in each returned document that holds the vector search
+	// score. The default is "score".
+	ScoreField string
+}
+
+// VectorSearch runs an Atlas Vector Search query against the collection and returns a cursor over
+// the matching documents, ordered from most to least similar. Each document contains the vector
+// search score in the field named by vs.ScoreField.
+//
+// The opts parameter can be used to specify options for the underlying aggregate operation (see the
+// options.AggregateOptions documentation).
+func (coll *Collection) VectorSearch(
+	ctx context.Context,
+	vs VectorSearchOptions,
+	opts ...options.Lister[options.AggregateOptions],
+) (*Cursor, error) {
+	pipeline, err := vectorSearchPipeline(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	return coll.Aggregate(ctx, pipeline, opts...)
+}
+
+// vectorSearchPipeline validates vs and returns the aggregation pipeline that runs it.
+func vectorSearchPipeline(vs VectorSearchOptions) (Pipeline, error) {
+	switch {
+	case vs.Index == "":
+		return nil, errors.New("vector search index must be specified")
+	case vs.Path == "":
+		return nil, errors.New("vector search path must be specified")
+	case vs.QueryVector == nil:
+		return nil, errors.New("vector search query vector must be specified")
+	case vs.Limit <= 0:
+		return nil, errors.New("vector search limit must be greater than 0")
+	case vs.NumCandidates < 0:
+		return nil, errors.New("vector search numCandidates must not be negative")
+	case !vs.Exact && vs.NumCandidates != 0 && vs.NumCandidates < vs.Limit:
+		return nil, errors.New("vector search numCandidates must be greater than or equal to limit")
+	}
+
+	stage := search.NewVectorSearch(vs.Index, vs.Path, vs.QueryVector, vs.Limit)
+	if vs.Exact {
+		stage.SetExact(true)
+	} else {
+		numCandidates := vs.NumCandidates
+		if numCandidates == 0 {
+			numCandidates = vs.Limit * vectorSearchCandidatesPerResult
+			if numCandidates > maxVectorSearchNumCandidates {
+				numCandidates = maxVectorSearchNumCandidates
+			}
+			if numCandidates < vs.Limit {
+				numCandidates = vs.Limit
+			}
+		}
+		stage.SetNumCandidates(numCandidates)
+	}
+	if vs.Filter != nil {
+		stage.SetFilter(vs.Filter)
+	}
+
+	scoreField := vs.ScoreField
+	if scoreField == "" {
+		scoreField = defaultVectorSearchScoreField
+	}
+
+	return Pipeline{
+		stage.Stage(),
+		{{Key: "$addFields", Value: bson.D{
+			{Key: scoreField, Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}},
+		}}},
+	}, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestVectorSearchPipeline(t *testing.T) {
+	valid := VectorSearchOptions{
+		Index:       "vector_index",
+		Path:        "embedding",
+		QueryVector: []float64{0.5, 1},
+		Limit:       5,
+	}
+
+	t.Run("invalid options", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			modify func(*VectorSearchOptions)
+		}{
+			{"missing index", func(vs *VectorSearchOptions) { vs.Index = "" }},
+			{"missing path", func(vs *VectorSearchOptions) { vs.Path = "" }},
+			{"missing query vector", func(vs *VectorSearchOptions) { vs.QueryVector = nil }},
+			{"zero limit", func(vs *VectorSearchOptions) { vs.Limit = 0 }},
+			{"numCandidates less than limit", func(vs *VectorSearchOptions) { vs.NumCandidates = 1 }},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				vs := valid
+				tc.modify(&vs)
+				_, err := vectorSearchPipeline(vs)
+				assert.Error(t, err, "expected error for invalid options")
+			})
+		}
+	})
+	t.Run("default numCandidates and score field", func(t *testing.T) {
+		pipeline, err := vectorSearchPipeline(valid)
+		require.NoError(t, err, "vectorSearchPipeline error: %v", err)
+		require.Len(t, pipeline, 2, "expected 2 stages")
+
+		stage := pipeline[0][0].Value.(bson.D)
+		assert.Contains(t, stage, bson.E{Key: "numCandidates", Value: int64(50)}, "expected default numCandidates")
+		assert.Equal(t, bson.D{{"$addFields", bson.D{{"score", bson.D{{"$meta", "vectorSearchScore"}}}}}},
+			pipeline[1], "unexpected score stage")
+	})
+	t.Run("exact search", func(t *testing.T) {
+		vs := valid
+		vs.Exact = true
+		vs.ScoreField = "similarity"
+		vs.Filter = bson.D{{"year", 2000}}
+
+		pipeline, err := vectorSearchPipeline(vs)
+		require.NoError(t, err, "vectorSearchPipeline error: %v", err)
+
+		stage := pipeline[0][0].Value.(bson.D)
+		assert.Contains(t, stage, bson.E{Key: "exact", Value: true}, "expected exact option")
+		assert.Contains(t, stage, bson.E{Key: "filter", Value: vs.Filter}, "expected filter option")
+		for _, e := range stage {
+			assert.NotEqual(t, "numCandidates", e.Key, "expected no numCandidates for exact search")
+		}
+		assert.Equal(t, "similarity", pipeline[1][0].Value.(bson.D)[0].Key, "unexpected score field")
+	})
+}
@@ -0,0 +1,202 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// wildcardKey is the key of a wildcard index on all fields.
+const wildcardKey = "$**"
+
+// Wildcard adds a wildcard key on the fields under the given path, or on all fields if path is
+// empty. Use WildcardProjection, WildcardIncluding, or WildcardExcluding to include or exclude
+// fields from a wildcard key on all fields.
+//
+// An index can have at most one wildcard key. Adding ascending or descending keys to an index with
+// a wildcard key creates a compound wildcard index, which requires MongoDB 7.0 or later. The other
+// keys of a compound wildcard index cannot be under the wildcard path, and if the wildcard key is
+// on all fields, the wildcard projection must exclude them.
+//
+// For more information about wildcard indexes, see
+// https://www.mongodb.com/docs/manual/core/indexes/index-types/index-wildcard/.
+func (b *IndexModelBuilder) Wildcard(path string) *IndexModelBuilder {
+	if path == "" {
+		return b.key(wildcardKey, int32(1))
+	}
+	return b.key(path+"."+wildcardKey, int32(1))
+}
+
+// WildcardProjection sets the fields included in or excluded from a wildcard key on all fields,
+// such as bson.D{{"user.password", 0}}. It requires a key added with Wildcard(""). The projection
+// must either include or exclude fields, except for the _id field, which can be included in a
+// projection that excludes fields or excluded from one that includes fields.
+func (b *IndexModelBuilder) WildcardProjection(projection interface{}) *IndexModelBuilder {
+	b.wildcardProjection = projection
+	return b
+}
+
+// WildcardIncluding sets a wildcard projection that only includes the given fields and their
+// subfields in a wildcard key on all fields.
+func (b *IndexModelBuilder) WildcardIncluding(fields ...string) *IndexModelBuilder {
+	return b.WildcardProjection(wildcardProjection(fields, 1))
+}
+
+// WildcardExcluding sets a wildcard projection that excludes the given fields and their subfields
+// from a wildcard key on all fields.
+func (b *IndexModelBuilder) WildcardExcluding(fields ...string) *IndexModelBuilder {
+	return b.WildcardProjection(wildcardProjection(fields, 0))
+}
+
+func wildcardProjection(fields []string, value int32) bson.D {
+	projection := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		projection = append(projection, bson.E{Key: field, Value: value})
+	}
+	return projection
+}
+
+func isWildcardKey(key string) bool {
+	return key == wildcardKey || strings.HasSuffix(key, "."+wildcardKey)
+}
+
+// pathsOverlap reports whether a is the same field as b or one is a subfield of the other.
+func pathsOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// validateWildcardIndex checks the wildcard key, the other keys of a compound wildcard index, and
+// the wildcard projection of an index with the given keys, reporting the definitions that the
+// server rejects.
+func validateWildcardIndex(keys bson.D, projection interface{}) error {
+	var wildcard string
+	var regular []bson.E
+	for _, k := range keys {
+		if !isWildcardKey(k.Key) {
+			regular = append(regular, k)
+			continue
+		}
+		if wildcard != "" {
+			return errors.New("an index can have at most one wildcard key")
+		}
+		wildcard = k.Key
+	}
+
+	if wildcard == "" {
+		if projection != nil {
+			return fmt.Errorf("a wildcard projection requires a %q key", wildcardKey)
+		}
+		return nil
+	}
+	if projection != nil && wildcard != wildcardKey {
+		return fmt.Errorf("a wildcard projection requires a %q key, not %q", wildcardKey, wildcard)
+	}
+
+	for _, k := range regular {
+		if _, ok := k.Value.(string); ok {
+			return fmt.Errorf("compound wildcard indexes cannot have %v key %q", k.Value, k.Key)
+		}
+		if path := strings.TrimSuffix(wildcard, "."+wildcardKey); wildcard != wildcardKey && pathsOverlap(k.Key, path) {
+			return fmt.Errorf("key %q overlaps the wildcard key %q", k.Key, wildcard)
+		}
+	}
+
+	if projection == nil {
+		if wildcard == wildcardKey && len(regular) > 0 {
+			return fmt.Errorf("a compound wildcard index with a %q key requires a wildcard projection that excludes its other keys", wildcardKey)
+		}
+		return nil
+	}
+
+	include, paths, err := parseWildcardProjection(projection)
+	if err != nil {
+		return err
+	}
+	for _, k := range regular {
+		var covered bool
+		for _, p := range paths {
+			if pathsOverlap(k.Key, p) {
+				covered = true
+				break
+			}
+		}
+		switch {
+		case include && covered:
+			return fmt.Errorf("the wildcard projection includes key %q, which must be excluded", k.Key)
+		case !include && !covered:
+			return fmt.Errorf("the wildcard projection does not exclude key %q", k.Key)
+		}
+	}
+	return nil
+}
+
+// parseWildcardProjection returns whether a wildcard projection includes or excludes fields and
+// the dotted paths of the fields it includes or excludes, ignoring the _id field.
+func parseWildcardProjection(projection interface{}) (include bool, paths []string, err error) {
+	doc, err := bson.Marshal(projection)
+	if err != nil {
+		return false, nil, fmt.Errorf("error marshaling wildcard projection: %w", err)
+	}
+
+	values := map[string]bool{}
+	if err := flattenWildcardProjection(bson.Raw(doc), "", values); err != nil {
+		return false, nil, err
+	}
+	if len(values) == 0 {
+		return false, nil, errors.New("wildcard projection cannot be empty")
+	}
+
+	var seen, sawInclude, sawExclude bool
+	for path, v := range values {
+		if path == "_id" {
+			continue
+		}
+		seen = true
+		paths = append(paths, path)
+		if v {
+			sawInclude = true
+		} else {
+			sawExclude = true
+		}
+	}
+	switch {
+	case sawInclude && sawExclude:
+		return false, nil, errors.New("wildcard projection cannot both include and exclude fields")
+	case !seen:
+		// Only the _id field is projected.
+		return values["_id"], []string{"_id"}, nil
+	}
+	return sawInclude, paths, nil
+}
+
+func flattenWildcardProjection(doc bson.Raw, prefix string, values map[string]bool) error {
+	elems, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		path := prefix + elem.Key()
+		val := elem.Value()
+		switch val.Type {
+		case bson.TypeEmbeddedDocument:
+			if err := flattenWildcardProjection(val.Document(), path+".", values); err != nil {
+				return err
+			}
+		case bson.TypeBoolean:
+			values[path] = val.Boolean()
+		case bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128:
+			values[path] = numberAsFloat(val) != 0
+		default:
+			return fmt.Errorf("wildcard projection value for %q must be a number or a boolean, got %v", path, val.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestWildcardIndexBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("compound", func(t *testing.T) {
+		t.Parallel()
+
+		model, err := NewIndexModelBuilder().
+			Ascending("tenantId").
+			Wildcard("").
+			WildcardExcluding("tenantId", "secret").
+			Build()
+		require.NoError(t, err, "Build error")
+		assert.Equal(t, bson.D{{"tenantId", int32(1)}, {"$**", int32(1)}}, model.Keys, "keys mismatch")
+
+		_, err = NewIndexModelBuilder().
+			Descending("tenantId").
+			Wildcard("").
+			WildcardIncluding("attributes", "_id").
+			Build()
+		assert.NoError(t, err, "Build error")
+
+		_, err = NewIndexModelBuilder().
+			Ascending("tenantId").
+			Wildcard("attributes").
+			Build()
+		assert.NoError(t, err, "Build error")
+
+		_, err = NewIndexModelBuilder().
+			Wildcard("").
+			WildcardProjection(bson.D{{"a", bson.D{{"b", false}, {"c", 0}}}, {"_id", 1}}).
+			Build()
+		assert.NoError(t, err, "Build error")
+	})
+
+	testCases := []struct {
+		name    string
+		builder *IndexModelBuilder
+	}{
+		{"two wildcard keys", NewIndexModelBuilder().Wildcard("a").Wildcard("b")},
+		{"empty projection", NewIndexModelBuilder().Wildcard("").WildcardProjection(bson.D{})},
+		{"mixed projection", NewIndexModelBuilder().Wildcard("").WildcardProjection(bson.D{{"a", 1}, {"b", 0}})},
+		{"invalid projection value", NewIndexModelBuilder().Wildcard("").WildcardProjection(bson.D{{"a", "x"}})},
+		{"compound without projection", NewIndexModelBuilder().Ascending("a").Wildcard("")},
+		{"compound projection does not exclude key", NewIndexModelBuilder().Ascending("a").Wildcard("").WildcardExcluding("b")},
+		{"compound projection includes key", NewIndexModelBuilder().Ascending("a").Wildcard("").WildcardIncluding("a.b")},
+		{"compound key under wildcard path", NewIndexModelBuilder().Ascending("a.b").Wildcard("a")},
+		{"compound hashed key", NewIndexModelBuilder().Hashed("a").Wildcard("b")},
+		{"compound text key", NewIndexModelBuilder().Text("a").Wildcard("b")},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.builder.Build()
+			assert.Error(t, err, "expected Build error")
+		})
+	}
+}
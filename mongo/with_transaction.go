@@ -0,0 +1,229 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// Default backoff between transaction attempts used by WithTransaction.
+const (
+	defaultTransactionInitialBackoff = 5 * time.Millisecond
+	defaultTransactionMaxBackoff     = 500 * time.Millisecond
+)
+
+// WithTransaction runs fn in a transaction on sess and returns its result, like
+// Session.WithTransaction, but with a typed result and a configurable backoff between attempts.
+//
+// When the transaction fails with a TransientTransactionError, for example because of a write
+// conflict with a concurrent transaction, WithTransaction waits before the next attempt instead of
+// retrying immediately, which prevents contending transactions from repeatedly conflicting. The
+// backoff, the timeout for retries, and a monitor for each attempt can be configured with opts.
+// The requirements on fn described in Session.WithTransaction apply.
+func WithTransaction[T any](
+	ctx context.Context,
+	sess *Session,
+	fn func(ctx context.Context) (T, error),
+	opts ...options.Lister[options.WithTransactionOptions],
+) (T, error) {
+	var zero T
+
+	args, err := mongoutil.NewOptions[options.WithTransactionOptions](opts...)
+	if err != nil {
+		return zero, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	run := transactionRun{
+		timeout:        withTransactionTimeout,
+		initialBackoff: defaultTransactionInitialBackoff,
+		maxBackoff:     defaultTransactionMaxBackoff,
+		monitor:        args.AttemptMonitor,
+	}
+	if args.TransactionOptions != nil {
+		run.txnOpts = []options.Lister[options.TransactionOptions]{args.TransactionOptions}
+	}
+	if args.Timeout != nil && *args.Timeout < run.timeout {
+		run.timeout = *args.Timeout
+	}
+	if args.InitialBackoff != nil {
+		run.initialBackoff = *args.InitialBackoff
+	}
+	if args.MaxBackoff != nil {
+		run.maxBackoff = *args.MaxBackoff
+	}
+	if run.timeout < 0 || run.initialBackoff < 0 || run.maxBackoff < 0 {
+		return zero, errors.New("transaction timeout and backoffs must not be negative")
+	}
+
+	return runTransaction(ctx, sess, fn, run)
+}
+
+// transactionRun configures how runTransaction retries transaction attempts.
+type transactionRun struct {
+	txnOpts        []options.Lister[options.TransactionOptions]
+	timeout        time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	monitor        func(context.Context, *event.TransactionAttemptEvent)
+}
+
+// backoff returns the delay before the attempt following the given attempt.
+func (run transactionRun) backoff(attempt int) time.Duration {
+	if run.initialBackoff <= 0 {
+		return 0
+	}
+
+	d := run.initialBackoff
+	for i := 1; i < attempt && d < run.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > run.maxBackoff {
+		d = run.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Subtract a random jitter of up to half the delay.
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// runTransaction runs fn in transactions on s until an attempt commits, fails with an error that
+// cannot be retried, or run.timeout expires.
+func runTransaction[T any](
+	ctx context.Context,
+	s *Session,
+	fn func(ctx context.Context) (T, error),
+	run transactionRun,
+) (T, error) {
+	deadline := time.Now().Add(run.timeout)
+	expired := func() bool { return !time.Now().Before(deadline) }
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		res, committed, retry, err := transactionAttempt(ctx, s, fn, run.txnOpts, expired)
+
+		var backoff time.Duration
+		if retry {
+			backoff = run.backoff(attempt)
+			// Do not wait past the timeout only to return the error afterwards.
+			if backoff > 0 && time.Now().Add(backoff).After(deadline) {
+				retry = false
+				backoff = 0
+			}
+		}
+
+		if run.monitor != nil {
+			run.monitor(ctx, &event.TransactionAttemptEvent{
+				Attempt:   attempt,
+				Duration:  time.Since(start),
+				Committed: committed,
+				Failure:   err,
+				Retrying:  retry,
+				Backoff:   backoff,
+			})
+		}
+
+		if !retry {
+			return res, err
+		}
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return res, err
+			}
+		}
+	}
+}
+
+// transactionAttempt runs fn in a transaction on s and commits it. It reports whether the
+// transaction was committed and whether a new attempt should be made.
+func transactionAttempt[T any](
+	ctx context.Context,
+	s *Session,
+	fn func(ctx context.Context) (T, error),
+	txnOpts []options.Lister[options.TransactionOptions],
+	expired func() bool,
+) (res T, committed bool, retry bool, err error) {
+	var zero T
+
+	err = s.StartTransaction(txnOpts...)
+	if err != nil {
+		return zero, false, false, err
+	}
+
+	res, err = fn(NewSessionContext(ctx, s))
+	if err != nil {
+		if s.clientSession.TransactionRunning() {
+			// Wrap the user-provided Context in a new one that behaves like context.Background() for deadlines and
+			// cancellations, but forwards Value requests to the original one.
+			_ = s.AbortTransaction(newBackgroundContext(ctx))
+		}
+
+		if expired() {
+			return zero, false, false, err
+		}
+		if errorHasLabel(err, driver.TransientTransactionError) {
+			return zero, false, true, err
+		}
+		return res, false, false, err
+	}
+
+	// Check if callback intentionally aborted and, if so, return immediately
+	// with no error.
+	if s.clientSession.CheckAbortTransaction() != nil {
+		return res, false, false, nil
+	}
+
+	// If context has errored, run AbortTransaction and return, as the commit
+	// has no chance of succeeding.
+	//
+	// Aborting after a failed CommitTransaction is dangerous. Failed transaction
+	// commits may unpin the session server-side, and subsequent transaction aborts
+	// may run on a new mongos which could end up with commit and abort being executed
+	// simultaneously.
+	if ctx.Err() != nil {
+		// Wrap the user-provided Context in a new one that behaves like context.Background() for deadlines and
+		// cancellations, but forwards Value requests to the original one.
+		_ = s.AbortTransaction(newBackgroundContext(ctx))
+		return zero, false, false, ctx.Err()
+	}
+
+	for {
+		err = s.CommitTransaction(newBackgroundContext(ctx))
+		// End when error is nil, as transaction has been committed.
+		if err == nil {
+			return res, true, false, nil
+		}
+
+		if expired() {
+			return res, false, false, err
+		}
+
+		if cerr, ok := err.(CommandError); ok {
+			if cerr.HasErrorLabel(driver.UnknownTransactionCommitResult) && !cerr.IsMaxTimeMSExpiredError() {
+				continue
+			}
+			if cerr.HasErrorLabel(driver.TransientTransactionError) {
+				return res, false, true, err
+			}
+		}
+		return res, false, false, err
+	}
+}
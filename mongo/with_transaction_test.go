@@ -0,0 +1,93 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+func TestWithTransactionGeneric(t *testing.T) {
+	client := setupClient()
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	sess, err := client.StartSession()
+	require.NoError(t, err, "StartSession error: %v", err)
+	defer sess.EndSession(context.Background())
+
+	transient := CommandError{Name: "WriteConflict", Labels: []string{driver.TransientTransactionError}}
+
+	t.Run("typed result with backoff", func(t *testing.T) {
+		var events []event.TransactionAttemptEvent
+		calls := 0
+		opts := options.WithTransaction().
+			SetInitialBackoff(10 * time.Millisecond).
+			SetMaxBackoff(40 * time.Millisecond).
+			SetAttemptMonitor(func(_ context.Context, evt *event.TransactionAttemptEvent) {
+				events = append(events, *evt)
+			})
+
+		start := time.Now()
+		res, err := WithTransaction(context.Background(), sess, func(context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, transient
+			}
+			return 42, nil
+		}, opts)
+		require.NoError(t, err, "WithTransaction error: %v", err)
+		assert.Equal(t, 42, res, "expected typed result")
+		assert.Equal(t, 3, calls, "expected three attempts")
+
+		require.Len(t, events, 3, "expected an event per attempt")
+		for i, evt := range events[:2] {
+			assert.Equal(t, i+1, evt.Attempt, "expected attempt number")
+			assert.True(t, evt.Retrying, "expected attempt %d to be retried", evt.Attempt)
+			assert.True(t, errorHasLabel(evt.Failure, driver.TransientTransactionError), "expected transient attempt failure, got %v", evt.Failure)
+			assert.True(t, evt.Backoff > 0, "expected a backoff before the next attempt")
+		}
+		assert.True(t, events[2].Committed, "expected last attempt to commit")
+		assert.False(t, events[2].Retrying, "expected last attempt not to be retried")
+		assert.True(t, time.Since(start) >= events[0].Backoff+events[1].Backoff,
+			"expected WithTransaction to wait between attempts")
+	})
+	t.Run("timeout", func(t *testing.T) {
+		calls := 0
+		opts := options.WithTransaction().
+			SetTimeout(50 * time.Millisecond).
+			SetInitialBackoff(20 * time.Millisecond)
+
+		_, err := WithTransaction(context.Background(), sess, func(context.Context) (string, error) {
+			calls++
+			return "", transient
+		}, opts)
+		assert.True(t, errorHasLabel(err, driver.TransientTransactionError), "expected the last transient error, got %v", err)
+		assert.True(t, calls >= 2 && calls <= 4, "expected a few attempts within the timeout, got %d", calls)
+	})
+	t.Run("non-retryable error", func(t *testing.T) {
+		calls := 0
+		_, err := WithTransaction(context.Background(), sess, func(context.Context) (struct{}, error) {
+			calls++
+			return struct{}{}, CommandError{Name: "other"}
+		})
+		assert.Error(t, err, "expected WithTransaction error")
+		assert.Equal(t, 1, calls, "expected a single attempt")
+	})
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := WithTransaction(context.Background(), sess, func(context.Context) (int, error) {
+			return 0, nil
+		}, options.WithTransaction().SetInitialBackoff(-time.Second))
+		assert.Error(t, err, "expected error for a negative backoff")
+	})
+}
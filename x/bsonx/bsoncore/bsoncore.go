@@ -101,24 +101,38 @@ func ReadElement(src []byte) (Element, []byte, bool) {
 	if len(src) < 1 {
 		return nil, src, false
 	}
-	t := Type(src[0])
-	idx := 1
-	for idx < len(src) && src[idx] != 0x00 {
-		idx++
-	}
-	if idx >= len(src) {
-		return nil, src, false
-	}
-	idx++ // Move past the null byte
-	length, ok := valueLength(src[idx:], t)
+	_, _, _, rem, ok := readElementParts(src)
 	if !ok {
 		return nil, src, false
 	}
-	elemLength := idx + int(length)
-	if elemLength > len(src) {
-		return nil, src, false
+	elemLength := len(src) - len(rem)
+	return src[:elemLength], rem, true
+}
+
+// readElementParts reads an element from src and returns its type, key, and value bytes along with
+// the remaining bytes. The key is located with bytes.IndexByte, which is vectorized on most
+// platforms, and the value is sliced using its length prefix or fixed size, so each byte of the
+// element header is scanned only once. The returned slices alias src.
+func readElementParts(src []byte) (t Type, key, value, rem []byte, ok bool) {
+	if len(src) < 2 {
+		return 0, nil, nil, src, false
+	}
+	t = Type(src[0])
+	idx := bytes.IndexByte(src[1:], 0x00)
+	if idx < 0 {
+		return 0, nil, nil, src, false
+	}
+	key = src[1 : idx+1]
+	start := idx + 2 // Move past the type byte and the null byte
+	length, ok := valueLength(src[start:], t)
+	if !ok || length < 0 {
+		return 0, nil, nil, src, false
+	}
+	end := start + int(length)
+	if end > len(src) {
+		return 0, nil, nil, src, false
 	}
-	return src[:elemLength], src[elemLength:], true
+	return t, key, src[start:end], src[end:], true
 }
 
 // AppendValueElement appends value to dst as an element using key as the element's key.
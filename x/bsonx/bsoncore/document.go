@@ -156,38 +156,31 @@ func (d Document) LookupErr(key ...string) (Value, error) {
 
 	length -= 4
 
-	var elem Element
 	for length > 1 {
-		elem, rem, ok = ReadElement(rem)
-		length -= int32(len(elem))
+		t, k, data, next, ok := readElementParts(rem)
 		if !ok {
 			return Value{}, NewInsufficientBytesError(d, rem)
 		}
-		// We use `KeyBytes` rather than `Key` to avoid a needless string alloc.
-		if string(elem.KeyBytes()) != key[0] {
+		length -= int32(len(rem) - len(next))
+		rem = next
+		// Converting the key bytes to a string for the comparison does not allocate.
+		if string(k) != key[0] {
 			continue
 		}
+		val, _, ok := ReadValue(data, t)
+		if !ok {
+			return Value{}, NewInsufficientBytesError(d, rem)
+		}
 		if len(key) > 1 {
-			tt := Type(elem[0])
-			switch tt {
-			case TypeEmbeddedDocument:
-				val, err := elem.Value().Document().LookupErr(key[1:]...)
-				if err != nil {
-					return Value{}, err
-				}
-				return val, nil
-			case TypeArray:
-				// Convert to Document to continue Lookup recursion.
-				val, err := Document(elem.Value().Array()).LookupErr(key[1:]...)
-				if err != nil {
-					return Value{}, err
-				}
-				return val, nil
+			switch t {
+			case TypeEmbeddedDocument, TypeArray:
+				// Arrays are converted to Document to continue Lookup recursion.
+				return Document(val.Data).LookupErr(key[1:]...)
 			default:
-				return Value{}, InvalidDepthTraversalError{Key: elem.Key(), Type: tt}
+				return Value{}, InvalidDepthTraversalError{Key: string(k), Type: t}
 			}
 		}
-		return elem.ValueErr()
+		return val, nil
 	}
 	return Value{}, ErrElementNotFound
 }
@@ -368,10 +361,11 @@ func values(b []byte) ([]Value, error) {
 		if !ok {
 			return vals, NewInsufficientBytesError(b, rem)
 		}
-		if err := elem.Value().Validate(); err != nil {
+		val := elem.Value()
+		if err := val.Validate(); err != nil {
 			return vals, err
 		}
-		vals = append(vals, elem.Value())
+		vals = append(vals, val)
 	}
 	return vals, nil
 }
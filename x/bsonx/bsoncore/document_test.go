@@ -36,6 +36,24 @@ func BenchmarkDocumentValidate(b *testing.B) {
 	}
 }
 
+func BenchmarkDocumentLookup(b *testing.B) {
+	// A wide, flat document where the looked up key is the last element.
+	idx, doc := AppendDocumentStart(nil)
+	for i := 0; i < 100; i++ {
+		doc = AppendInt32Element(doc, fmt.Sprintf("field%03d", i), int32(i))
+	}
+	doc, _ = AppendDocumentEnd(doc, idx)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Document(doc).LookupErr("field099")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestDocument(t *testing.T) {
 	t.Run("Validate", func(t *testing.T) {
 		t.Run("TooShort", func(t *testing.T) {
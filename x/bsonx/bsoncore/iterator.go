@@ -101,13 +101,13 @@ func (iter *Iterator) Next() (*Value, error) {
 		return nil, io.EOF // At the end of the document
 	}
 
-	elem, _, ok := ReadElement(rem)
+	t, _, data, next, ok := readElementParts(rem)
 	if !ok {
 		return nil, errCorruptedDocument
 	}
 
-	iter.pos += len(elem)
-	val := elem.Value()
+	iter.pos += len(rem) - len(next)
+	val, _, _ := ReadValue(data, t)
 
 	return &val, nil
 }
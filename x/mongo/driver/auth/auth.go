@@ -39,11 +39,15 @@ func init() {
 	RegisterAuthenticatorFactory(MongoDBOIDC, newOIDCAuthenticator)
 }
 
-// CreateAuthenticator creates an authenticator.
+// CreateAuthenticator creates an authenticator for a built-in mechanism or a mechanism registered
+// with RegisterSASLMechanism.
 func CreateAuthenticator(name string, cred *Cred, httpClient *http.Client) (Authenticator, error) {
 	if f, ok := authFactories[name]; ok {
 		return f(cred, httpClient)
 	}
+	if mech, ok := LookupSASLMechanism(name); ok {
+		return newSASLMechanismAuthenticator(mech, cred, httpClient)
+	}
 
 	return nil, newAuthError(fmt.Sprintf("unknown authenticator: %s", name), nil)
 }
@@ -0,0 +1,132 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+)
+
+// SASLMechanism is a SASL authentication mechanism implemented outside of the driver. A registered
+// mechanism can be used like a built-in mechanism, by setting the AuthMechanism field of the
+// Credential or the authMechanism URI option to its name.
+type SASLMechanism struct {
+	// Name is the name of the mechanism sent to the server, e.g. "ACME-SSO". It must not be the
+	// name of a built-in mechanism.
+	Name string
+
+	// DefaultSource is the auth source used if the credential does not specify one. If empty,
+	// "$external" is used.
+	DefaultSource string
+
+	// NewClient creates the client side of a SASL conversation for cred. It is called at least
+	// once for each authenticated connection. The client may implement SaslClientCloser to release
+	// its resources once the conversation is over and ExtraOptionsSaslClient to add options to the
+	// saslStart command.
+	NewClient func(cred *Cred) (SaslClient, error)
+
+	// Speculative enables speculative authentication, in which the first message of the
+	// conversation is sent with the initial hello command of a connection to save a round trip.
+	// It should only be set if the client can be started before the server is known.
+	Speculative bool
+}
+
+var (
+	saslMechanismsMu sync.RWMutex
+	saslMechanisms   = make(map[string]SASLMechanism)
+)
+
+// RegisterSASLMechanism registers a custom SASL mechanism. It should be called before the clients
+// that use the mechanism are created, e.g. in an init function. RegisterSASLMechanism returns an
+// error if the mechanism has no name or NewClient function, if its name is the name of a built-in
+// mechanism, or if a mechanism with the same name was already registered.
+func RegisterSASLMechanism(mech SASLMechanism) error {
+	if mech.Name == "" {
+		return errors.New("SASL mechanism name must not be empty")
+	}
+	if mech.NewClient == nil {
+		return fmt.Errorf("SASL mechanism %q must have a NewClient function", mech.Name)
+	}
+	for name := range authFactories {
+		if strings.EqualFold(name, mech.Name) {
+			return fmt.Errorf("SASL mechanism %q is a built-in mechanism", mech.Name)
+		}
+	}
+
+	saslMechanismsMu.Lock()
+	defer saslMechanismsMu.Unlock()
+
+	if _, ok := saslMechanisms[mech.Name]; ok {
+		return fmt.Errorf("SASL mechanism %q is already registered", mech.Name)
+	}
+	saslMechanisms[mech.Name] = mech
+	return nil
+}
+
+// LookupSASLMechanism returns the custom SASL mechanism registered with the given name.
+func LookupSASLMechanism(name string) (SASLMechanism, bool) {
+	saslMechanismsMu.RLock()
+	defer saslMechanismsMu.RUnlock()
+
+	mech, ok := saslMechanisms[name]
+	return mech, ok
+}
+
+func newSASLMechanismAuthenticator(mech SASLMechanism, cred *Cred, _ *http.Client) (Authenticator, error) {
+	source := cred.Source
+	if source == "" {
+		source = mech.DefaultSource
+	}
+	if source == "" {
+		source = sourceExternal
+	}
+
+	c := *cred
+	c.Source = source
+	return &SASLMechanismAuthenticator{mech: mech, cred: &c}, nil
+}
+
+// SASLMechanismAuthenticator authenticates a connection with a custom SASL mechanism.
+type SASLMechanismAuthenticator struct {
+	mech SASLMechanism
+	cred *Cred
+}
+
+var _ SpeculativeAuthenticator = (*SASLMechanismAuthenticator)(nil)
+
+// Auth authenticates the connection.
+func (a *SASLMechanismAuthenticator) Auth(ctx context.Context, cfg *driver.AuthConfig) error {
+	client, err := a.mech.NewClient(a.cred)
+	if err != nil {
+		return newError(err, a.mech.Name)
+	}
+	return ConductSaslConversation(ctx, cfg, a.cred.Source, client)
+}
+
+// Reauth reauthenticates the connection.
+func (a *SASLMechanismAuthenticator) Reauth(_ context.Context, _ *driver.AuthConfig) error {
+	return newAuthError(fmt.Sprintf("%s authentication does not support reauthentication", a.mech.Name), nil)
+}
+
+// CreateSpeculativeConversation creates a speculative conversation if the mechanism enables
+// speculative authentication, or returns nil otherwise.
+func (a *SASLMechanismAuthenticator) CreateSpeculativeConversation() (SpeculativeConversation, error) {
+	if !a.mech.Speculative {
+		return nil, nil
+	}
+	client, err := a.mech.NewClient(a.cred)
+	if err != nil {
+		return nil, err
+	}
+	return newSaslConversation(client, a.cred.Source, true), nil
+}
@@ -0,0 +1,138 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/handshake"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+)
+
+type tokenSaslClient struct {
+	mechanism string
+	token     string
+}
+
+func (c *tokenSaslClient) Start() (string, []byte, error) {
+	return c.mechanism, []byte(c.token), nil
+}
+
+func (c *tokenSaslClient) Next(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("unexpected server challenge")
+}
+
+func (c *tokenSaslClient) Completed() bool {
+	return true
+}
+
+func registerTokenMechanism(t *testing.T, name string, speculative bool) {
+	t.Helper()
+
+	err := RegisterSASLMechanism(SASLMechanism{
+		Name: name,
+		NewClient: func(cred *Cred) (SaslClient, error) {
+			return &tokenSaslClient{mechanism: name, token: cred.Username}, nil
+		},
+		Speculative: speculative,
+	})
+	require.NoError(t, err, "RegisterSASLMechanism error")
+}
+
+func TestSASLMechanism(t *testing.T) {
+	saslDone := bsoncore.BuildDocumentFromElements(nil,
+		bsoncore.AppendInt32Element(nil, "conversationId", 1),
+		bsoncore.AppendBooleanElement(nil, "done", true),
+		bsoncore.AppendBinaryElement(nil, "payload", 0x00, nil),
+		bsoncore.AppendInt32Element(nil, "ok", 1),
+	)
+
+	handshakeWith := func(t *testing.T, mechanism string, replies ...bsoncore.Document) []bsoncore.Document {
+		t.Helper()
+
+		authenticator, err := CreateAuthenticator(mechanism, &Cred{Username: "token"}, &http.Client{})
+		require.NoError(t, err, "CreateAuthenticator error")
+		handshaker := Handshaker(nil, &HandshakeOptions{Authenticator: authenticator})
+
+		responses := make(chan []byte, len(replies))
+		writeReplies(responses, replies...)
+		conn := &drivertest.ChannelConn{
+			Written:  make(chan []byte, len(replies)),
+			ReadResp: responses,
+		}
+		mnetconn := mnet.NewConnection(conn)
+
+		info, err := handshaker.GetHandshakeInformation(context.Background(), address.Address("localhost:27017"), mnetconn)
+		require.NoError(t, err, "GetHandshakeInformation error")
+		conn.Desc = info.Description
+		require.NoError(t, handshaker.FinishHandshake(context.Background(), mnetconn), "FinishHandshake error")
+		assert.Equal(t, 0, len(conn.ReadResp), "%d messages left unread", len(conn.ReadResp))
+
+		var cmds []bsoncore.Document
+		for i := 0; len(conn.Written) > 0; i++ {
+			wm := <-conn.Written
+			parse := drivertest.GetCommandFromMsgWireMessage
+			if i == 0 {
+				parse = drivertest.GetCommandFromQueryWireMessage
+			}
+			cmd, err := parse(wm)
+			require.NoError(t, err, "error parsing command")
+			cmds = append(cmds, cmd)
+		}
+		return cmds
+	}
+
+	t.Run("speculative", func(t *testing.T) {
+		registerTokenMechanism(t, "TEST-SPECULATIVE-SSO", true)
+
+		hello := bsoncore.BuildDocumentFromElements(nil,
+			append(handshakeHelloElements, bsoncore.AppendDocumentElement(nil, "speculativeAuthenticate", saslDone))...)
+		cmds := handshakeWith(t, "TEST-SPECULATIVE-SSO", hello)
+		require.Len(t, cmds, 1, "expected only the hello command")
+		assertCommandName(t, cmds[0], handshake.LegacyHello)
+
+		authDoc := cmds[0].Lookup("speculativeAuthenticate").Document()
+		assert.Equal(t, "TEST-SPECULATIVE-SSO", authDoc.Lookup("mechanism").StringValue(), "mechanism mismatch")
+		assert.Equal(t, "$external", authDoc.Lookup("db").StringValue(), "expected the default auth source")
+		_, payload := authDoc.Lookup("payload").Binary()
+		assert.Equal(t, "token", string(payload), "payload mismatch")
+	})
+
+	t.Run("not speculative", func(t *testing.T) {
+		registerTokenMechanism(t, "TEST-SSO", false)
+
+		hello := bsoncore.BuildDocumentFromElements(nil, handshakeHelloElements...)
+		cmds := handshakeWith(t, "TEST-SSO", hello, saslDone)
+		require.Len(t, cmds, 2, "expected hello and saslStart commands")
+		_, err := cmds[0].LookupErr("speculativeAuthenticate")
+		assert.Error(t, err, "expected no speculative authentication")
+		assertCommandName(t, cmds[1], "saslStart")
+		assert.Equal(t, "TEST-SSO", cmds[1].Lookup("mechanism").StringValue(), "mechanism mismatch")
+		assert.Equal(t, "$external", cmds[1].Lookup("$db").StringValue(), "expected the default auth source")
+	})
+
+	t.Run("registration errors", func(t *testing.T) {
+		newClient := func(*Cred) (SaslClient, error) { return &tokenSaslClient{}, nil }
+
+		assert.Error(t, RegisterSASLMechanism(SASLMechanism{NewClient: newClient}), "expected error without a name")
+		assert.Error(t, RegisterSASLMechanism(SASLMechanism{Name: "TEST-NO-CLIENT"}), "expected error without NewClient")
+		assert.Error(t, RegisterSASLMechanism(SASLMechanism{Name: "plain", NewClient: newClient}),
+			"expected error for a built-in mechanism")
+
+		registerTokenMechanism(t, "TEST-DUPLICATE", false)
+		assert.Error(t, RegisterSASLMechanism(SASLMechanism{Name: "TEST-DUPLICATE", NewClient: newClient}),
+			"expected error for a duplicate mechanism")
+	})
+}
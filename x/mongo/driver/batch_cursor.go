@@ -19,6 +19,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/codecutil"
 	"go.mongodb.org/mongo-driver/v2/internal/csot"
 	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
@@ -56,6 +57,11 @@ type BatchCursor struct {
 	// is set, it will be used as the "maxTimeMS" field on getMore commands.
 	maxAwaitTime *time.Duration
 
+	// exhaust is true if getMore commands are sent with the exhaustAllowed flag, and exhaustConn
+	// is the connection checked out for them until the cursor is exhausted or closed.
+	exhaust     bool
+	exhaustConn *mnet.Connection
+
 	// legacy server (< 3.2) fields
 	limit       int32
 	numReturned int32 // number of docs returned by server
@@ -168,6 +174,13 @@ type CursorOptions struct {
 	// MaxAwaitTime is only valid for tailable awaitData cursors. If this option
 	// is set, it will be used as the "maxTimeMS" field on getMore commands.
 	MaxAwaitTime *time.Duration
+
+	// Exhaust requests an exhaust cursor: the first getMore is sent with the exhaustAllowed flag
+	// on a connection that the cursor keeps checked out, and the server streams the following
+	// batches on it without further getMore commands. It is ignored for cursors pinned to a
+	// connection in load balanced mode and with auto encryption, and command monitoring only
+	// reports the getMore commands that are sent.
+	Exhaust bool
 }
 
 // SetMaxAwaitTime will set the maxTimeMS value on getMore commands for
@@ -204,6 +217,7 @@ func NewBatchCursor(
 		serverAPI:            opts.ServerAPI,
 		serverDescription:    cr.Desc,
 		encoderFn:            opts.MarshalValueEncoderFn,
+		exhaust:              opts.Exhaust,
 	}
 
 	if firstBatch != nil {
@@ -276,6 +290,9 @@ func (bc *BatchCursor) Close(ctx context.Context) error {
 		ctx = context.Background()
 	}
 
+	// A connection that is streaming batches cannot run the killCursors command, so it is released
+	// first and the command is run on another connection.
+	bc.releaseExhaustConnection()
 	err := bc.KillCursor(ctx)
 	bc.id = 0
 
@@ -303,11 +320,31 @@ func (bc *BatchCursor) unpinConnection() error {
 	return err
 }
 
+// PinnedConnection returns the connection the cursor is pinned to when running against a load
+// balancer, or nil if the cursor is not pinned to a connection.
+func (bc *BatchCursor) PinnedConnection() mnet.Describer {
+	if bc.connection == nil || bc.connection.Pinner == nil {
+		return nil
+	}
+	return bc.connection.Describer
+}
+
 // Server returns the server for this cursor.
 func (bc *BatchCursor) Server() Server {
 	return bc.server
 }
 
+// ServerAddress returns the address of the server the cursor was created on. getMore and
+// killCursors commands for the cursor are sent to the same server.
+func (bc *BatchCursor) ServerAddress() address.Address {
+	return bc.serverDescription.Addr
+}
+
+// Namespace returns the database and collection names of the cursor.
+func (bc *BatchCursor) Namespace() (database, collection string) {
+	return bc.database, bc.collection
+}
+
 func (bc *BatchCursor) clearBatch() {
 	bc.currentBatch.List = bc.currentBatch.List[:0]
 }
@@ -373,7 +410,7 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 		return
 	}
 
-	bc.err = Operation{
+	bc.err = bc.executeGetMore(ctx, Operation{
 		CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
 			dst = bsoncore.AppendInt64Element(dst, "getMore", bc.id)
 			dst = bsoncore.AppendStringElement(dst, "collection", bc.collection)
@@ -452,10 +489,11 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 		// Since this could be confusing, and there is no requirement
 		// to use a read preference here, we omit it.
 		omitReadPreference: true,
-	}.Execute(ctx)
+	})
 
 	// Once the cursor has been drained, we can unpin the connection if one is currently pinned.
 	if bc.id == 0 {
+		bc.releaseExhaustConnection()
 		err := bc.unpinConnection()
 		if err != nil && bc.err == nil {
 			bc.err = err
@@ -479,6 +517,129 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 	}
 }
 
+// executeGetMore runs the getMore operation op. For an exhaust cursor, it runs op on the exhaust
+// connection of the cursor, and only reads the next batch if the server is streaming batches on it.
+func (bc *BatchCursor) executeGetMore(ctx context.Context, op Operation) error {
+	if !bc.exhaust || bc.connection != nil || bc.crypt != nil {
+		return op.Execute(ctx)
+	}
+
+	if bc.exhaustConn == nil {
+		conn, err := bc.server.Connection(ctx)
+		if err != nil {
+			return err
+		}
+		bc.exhaustConn = newExhaustConnection(conn)
+	}
+
+	var err error
+	if bc.exhaustConn.CurrentlyStreaming() {
+		// ExecuteExhaust only reads the reply, so errors returned by the server are checked here.
+		processResponse := op.ProcessResponseFn
+		op.ProcessResponseFn = func(info ResponseInfo) error {
+			if err := ExtractErrorFromServerResponse(info.ServerResponse); err != nil {
+				return err
+			}
+			return processResponse(info)
+		}
+		err = op.ExecuteExhaust(ctx, bc.exhaustConn)
+	} else {
+		op.Deployment = &exhaustCursorDeployment{server: bc.server, conn: bc.exhaustConn}
+		err = op.Execute(ctx)
+	}
+	if err != nil {
+		// The connection may have unread batches, so it cannot be used again.
+		bc.releaseExhaustConnection()
+	}
+	return err
+}
+
+// releaseExhaustConnection returns the exhaust connection of the cursor to the pool. If the server
+// is still streaming batches on it, the connection is closed instead.
+func (bc *BatchCursor) releaseExhaustConnection() {
+	if bc.exhaustConn == nil {
+		return
+	}
+
+	ec := bc.exhaustConn.ReadWriteCloser.(*exhaustConnection)
+	if ec.streaming {
+		if expirable, ok := ec.conn.ReadWriteCloser.(Expirable); ok {
+			_ = expirable.Expire()
+		}
+	}
+	_ = ec.conn.Close()
+	bc.exhaustConn = nil
+}
+
+// exhaustConnection is a connection checked out by an exhaust cursor. It supports streaming, so
+// that getMore commands run on it are sent with the exhaustAllowed flag, and ignores Close, so
+// that the operations run on it do not return it to the pool.
+type exhaustConnection struct {
+	mnet.ReadWriteCloser
+	conn      *mnet.Connection
+	streaming bool
+}
+
+var _ mnet.Streamer = (*exhaustConnection)(nil)
+
+func newExhaustConnection(conn *mnet.Connection) *mnet.Connection {
+	ec := &exhaustConnection{ReadWriteCloser: conn.ReadWriteCloser, conn: conn}
+	return &mnet.Connection{
+		ReadWriteCloser: ec,
+		Describer:       conn.Describer,
+		Streamer:        ec,
+		Compressor:      conn.Compressor,
+	}
+}
+
+func (ec *exhaustConnection) Close() error                { return nil }
+func (ec *exhaustConnection) SetStreaming(streaming bool) { ec.streaming = streaming }
+func (ec *exhaustConnection) CurrentlyStreaming() bool    { return ec.streaming }
+func (ec *exhaustConnection) SupportsStreaming() bool     { return true }
+
+// exhaustCursorDeployment is used as a Deployment for the getMore commands of an exhaust cursor.
+// It runs them on the exhaust connection of the cursor and processes their errors with the server
+// of the cursor. Like the SingleServerDeployment used for the getMore commands of other cursors, it
+// does not apply the retry policy, operation middleware, causal time store or MaxTimeAllowance of
+// the Topology that ran the command that created the cursor.
+type exhaustCursorDeployment struct {
+	server Server
+	conn   *mnet.Connection
+}
+
+var _ Deployment = (*exhaustCursorDeployment)(nil)
+var _ Server = (*exhaustCursorDeployment)(nil)
+var _ ErrorProcessor = (*exhaustCursorDeployment)(nil)
+
+func (ecd *exhaustCursorDeployment) SelectServer(context.Context, description.ServerSelector) (Server, error) {
+	return ecd, nil
+}
+
+func (ecd *exhaustCursorDeployment) Kind() description.TopologyKind {
+	return description.TopologyKindSingle
+}
+
+func (ecd *exhaustCursorDeployment) Connection(context.Context) (*mnet.Connection, error) {
+	return ecd.conn, nil
+}
+
+// RTTMonitor implements the driver.Server interface.
+func (ecd *exhaustCursorDeployment) RTTMonitor() RTTMonitor {
+	return ecd.server.RTTMonitor()
+}
+
+func (ecd *exhaustCursorDeployment) ProcessError(err error, desc mnet.Describer) ProcessErrorResult {
+	if ep, ok := ecd.server.(ErrorProcessor); ok {
+		return ep.ProcessError(err, desc)
+	}
+	return NoChange
+}
+
+// GetServerSelectionTimeout returns zero as the server of an exhaust cursor is already selected.
+func (*exhaustCursorDeployment) GetServerSelectionTimeout() time.Duration {
+	return 0
+}
+
 // PostBatchResumeToken returns the latest seen post batch resume token.
 func (bc *BatchCursor) PostBatchResumeToken() bsoncore.Document {
 	return bc.postBatchResumeToken
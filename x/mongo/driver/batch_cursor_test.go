@@ -7,9 +7,17 @@
 package driver
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
 )
 
 func TestBatchCursor(t *testing.T) {
@@ -90,3 +98,143 @@ func TestBatchCursor(t *testing.T) {
 		}
 	})
 }
+
+// exhaustTestConnection replies to wire messages with queued OP_MSG replies, and records the wire
+// messages written and whether it was closed or expired.
+type exhaustTestConnection struct {
+	mockConnection
+	replies [][]byte
+	written [][]byte
+	closed  int
+	expired bool
+}
+
+func (c *exhaustTestConnection) Write(_ context.Context, wm []byte) error {
+	c.written = append(c.written, append([]byte(nil), wm...))
+	return nil
+}
+
+func (c *exhaustTestConnection) Read(context.Context) ([]byte, error) {
+	if len(c.replies) == 0 {
+		return nil, errors.New("no replies remaining")
+	}
+	wm := c.replies[0]
+	c.replies = c.replies[1:]
+	return wm, nil
+}
+
+func (c *exhaustTestConnection) Close() error {
+	c.closed++
+	return nil
+}
+
+func (c *exhaustTestConnection) Expire() error {
+	c.expired = true
+	return nil
+}
+
+func (c *exhaustTestConnection) Alive() bool {
+	return !c.expired
+}
+
+// getMoreReply returns an OP_MSG getMore reply with one document whose _id is the given value.
+func getMoreReply(cursorID int64, id int32, moreToCome bool) []byte {
+	doc := bsoncore.NewDocumentBuilder().AppendInt32("_id", id).Build()
+	reply := bsoncore.NewDocumentBuilder().
+		AppendDocument("cursor", bsoncore.NewDocumentBuilder().
+			AppendInt64("id", cursorID).
+			AppendString("ns", "db.coll").
+			AppendArray("nextBatch", bsoncore.NewArrayBuilder().AppendDocument(doc).Build()).
+			Build()).
+		AppendDouble("ok", 1).
+		Build()
+
+	return createExhaustServerResponse(reply, moreToCome)
+}
+
+func TestBatchCursorExhaust(t *testing.T) {
+	t.Parallel()
+
+	newCursor := func(t *testing.T, conn *exhaustTestConnection) *BatchCursor {
+		t.Helper()
+
+		conn.rDesc = description.Server{
+			Addr:        address.Address("localhost:27017"),
+			Kind:        description.ServerKindStandalone,
+			WireVersion: &description.VersionRange{Max: 21},
+		}
+		server := mockServer{conn: mnet.NewConnection(conn), rttMonitor: mockRTTMonitor{}}
+		bc, err := NewBatchCursor(CursorResponse{
+			Server: server,
+			Desc:   conn.rDesc,
+			FirstBatch: &bsoncore.Iterator{List: bsoncore.NewArrayBuilder().
+				AppendDocument(bsoncore.NewDocumentBuilder().AppendInt32("_id", 0).Build()).
+				Build()},
+			Database:   "db",
+			Collection: "coll",
+			ID:         42,
+		}, nil, nil, CursorOptions{Exhaust: true})
+		require.NoError(t, err, "NewBatchCursor error")
+		return bc
+	}
+
+	t.Run("streams batches", func(t *testing.T) {
+		t.Parallel()
+
+		conn := &exhaustTestConnection{replies: [][]byte{
+			getMoreReply(42, 1, true),
+			getMoreReply(42, 2, true),
+			getMoreReply(0, 3, false),
+		}}
+		bc := newCursor(t, conn)
+
+		var ids []int32
+		for bc.Next(context.Background()) {
+			docs, err := bc.Batch().Documents()
+			require.NoError(t, err, "Documents error")
+			for _, doc := range docs {
+				ids = append(ids, doc.Lookup("_id").Int32())
+			}
+		}
+		require.NoError(t, bc.Err(), "cursor error")
+		assert.Equal(t, []int32{0, 1, 2, 3}, ids, "expected the streamed batches")
+
+		require.Len(t, conn.written, 1, "expected a single getMore command")
+		assertExhaustAllowedSet(t, conn.written[0], true)
+		assert.Equal(t, 1, conn.closed, "expected the connection to be returned to the pool")
+		assert.False(t, conn.expired, "expected the connection not to be expired")
+	})
+	t.Run("closed while streaming", func(t *testing.T) {
+		t.Parallel()
+
+		conn := &exhaustTestConnection{replies: [][]byte{
+			getMoreReply(42, 1, true),
+			getMoreReply(0, 0, false), // killCursors reply
+		}}
+		bc := newCursor(t, conn)
+
+		require.True(t, bc.Next(context.Background()), "expected the first batch")
+		require.True(t, bc.Next(context.Background()), "expected a streamed batch")
+		require.NoError(t, bc.Close(context.Background()), "Close error")
+		assert.True(t, conn.expired, "expected the streaming connection to be expired")
+	})
+	t.Run("deployment options", func(t *testing.T) {
+		t.Parallel()
+
+		conn := &exhaustTestConnection{}
+		bc := newCursor(t, conn)
+
+		// The getMore commands of exhaust cursors skip the options of the Topology, as do the
+		// getMore commands of other cursors.
+		for _, d := range []Deployment{
+			bc.getOperationDeployment(),
+			&exhaustCursorDeployment{server: bc.server, conn: mnet.NewConnection(conn)},
+		} {
+			op := Operation{Deployment: d}
+			assert.Nil(t, op.retryPolicy(), "expected no retry policy for %T", d)
+			assert.Nil(t, op.operationMiddleware(), "expected no operation middleware for %T", d)
+			assert.Nil(t, op.causalTimeStore(), "expected no causal time store for %T", d)
+			assert.Equal(t, time.Duration(0), op.maxTimeAllowance(), "expected no MaxTimeAllowance for %T", d)
+		}
+	})
+}
@@ -13,7 +13,8 @@ import (
 )
 
 // ErrDocumentTooLarge occurs when a document that is larger than the maximum size accepted by a
-// server is passed to an insert command.
+// server is passed to an insert command. It is wrapped by a DocumentTooLargeError that identifies
+// the document and its largest fields.
 var ErrDocumentTooLarge = errors.New("an inserted document is too large")
 
 // Batches contains the necessary information to batch split an operation. This is only used for write
@@ -23,6 +24,17 @@ type Batches struct {
 	Documents  []bsoncore.Document
 	Current    []bsoncore.Document
 	Ordered    *bool
+
+	// Offset is the number of documents moved out of Documents by AdvanceBatch, i.e. the index of
+	// the first document of Documents in the documents of the operation.
+	Offset int
+}
+
+// DocumentSequence is an OP_MSG document sequence (a kind 1 section) whose documents are the value
+// of the command field named Identifier. See Operation.DocumentSequences.
+type DocumentSequence struct {
+	Identifier string
+	Documents  []bsoncore.Document
 }
 
 // Valid returns true if Batches contains both an identifier and the length of Documents is greater
@@ -55,7 +67,7 @@ func (b *Batches) AdvanceBatch(maxCount, targetBatchSize, maxDocSize int) error
 			break
 		}
 		if len(doc) > maxDocSize {
-			return ErrDocumentTooLarge
+			return newDocumentTooLargeError(doc, maxDocSize, b.Offset+i)
 		}
 		if size+len(doc) > targetBatchSize {
 			break
@@ -72,5 +84,6 @@ func (b *Batches) AdvanceBatch(maxCount, targetBatchSize, maxDocSize int) error
 	}
 
 	b.Current, b.Documents = b.Documents[:splitAfter], b.Documents[splitAfter:]
+	b.Offset += splitAfter
 	return nil
 }
@@ -74,14 +74,14 @@ func TestBatches(t *testing.T) {
 				"documents fit in targetBatchSize",
 				&Batches{Documents: documents},
 				10, 600, 1000, nil,
-				&Batches{Documents: documents[:0], Current: documents[0:]},
+				&Batches{Documents: documents[:0], Current: documents[0:], Offset: len(documents)},
 			},
 			{
 				// the first doc is bigger than targetBatchSize but smaller than maxDocSize so it is taken alone
 				"first document larger than targetBatchSize, smaller than maxDocSize",
 				&Batches{Documents: documents},
 				10, 5, 100, nil,
-				&Batches{Documents: documents[1:], Current: documents[:1]},
+				&Batches{Documents: documents[1:], Current: documents[:1], Offset: 1},
 			},
 		}
 
@@ -116,21 +116,21 @@ func TestBatches(t *testing.T) {
 			// first batch should take first 2 docs (size 100 each)
 			err := batches.AdvanceBatch(maxCount, targetSize, maxDocSize)
 			assert.Nil(t, err, "AdvanceBatch error: %v", err)
-			want := &Batches{Current: middleLargeDoc[:2], Documents: middleLargeDoc[2:]}
+			want := &Batches{Current: middleLargeDoc[:2], Documents: middleLargeDoc[2:], Offset: 2}
 			assert.Equal(t, want, batches, "expected batches %v, got %v", want, batches)
 
 			// second batch should take single large doc (size 900)
 			batches.ClearBatch()
 			err = batches.AdvanceBatch(maxCount, targetSize, maxDocSize)
 			assert.Nil(t, err, "AdvanceBatch error: %v", err)
-			want = &Batches{Current: middleLargeDoc[2:3], Documents: middleLargeDoc[3:]}
+			want = &Batches{Current: middleLargeDoc[2:3], Documents: middleLargeDoc[3:], Offset: 3}
 			assert.Equal(t, want, batches, "expected batches %v, got %v", want, batches)
 
 			// last batch should take last 2 docs (size 100 each)
 			batches.ClearBatch()
 			err = batches.AdvanceBatch(maxCount, targetSize, maxDocSize)
 			assert.Nil(t, err, "AdvanceBatch error: %v", err)
-			want = &Batches{Current: middleLargeDoc[3:], Documents: middleLargeDoc[:0]}
+			want = &Batches{Current: middleLargeDoc[3:], Documents: middleLargeDoc[:0], Offset: 5}
 			assert.Equal(t, want, batches, "expected batches %v, got %v", want, batches)
 		})
 	})
@@ -0,0 +1,54 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+)
+
+// CausalTimeStoreDeployment is implemented by Deployments that share the cluster time and
+// operation time of the operations executed against them through a session.CausalTimeStore.
+type CausalTimeStoreDeployment interface {
+	CausalTimeStore() session.CausalTimeStore
+}
+
+// causalTimeStore returns the CausalTimeStore of the operation's Deployment, if any.
+func (op Operation) causalTimeStore() session.CausalTimeStore {
+	if d, ok := op.Deployment.(CausalTimeStoreDeployment); ok {
+		return d.CausalTimeStore()
+	}
+	return nil
+}
+
+// executeWithCausalTimes injects the times loaded from store into the operation's session, runs
+// the operation with run, and saves the times of the session to store if they have advanced.
+func (op Operation) executeWithCausalTimes(
+	ctx context.Context,
+	store session.CausalTimeStore,
+	run func(context.Context) error,
+) error {
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading causal times: %w", err)
+	}
+	if err := op.Client.InjectCausalTimes(loaded); err != nil {
+		return err
+	}
+
+	err = run(ctx)
+
+	if times := op.Client.CausalTimes(); times.After(loaded) {
+		if saveErr := store.Save(ctx, times); saveErr != nil && err == nil {
+			// The operation ran, but the next operations that load the times may not observe it.
+			err = fmt.Errorf("error saving causal times: %w", saveErr)
+		}
+	}
+	return err
+}
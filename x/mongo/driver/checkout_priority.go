@@ -0,0 +1,70 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+	"time"
+)
+
+// CheckoutPriority is the priority class of an operation waiting to check out a connection from a
+// connection pool. When the pool is exhausted, waiting operations with a higher priority are given
+// connections before operations with a lower priority. Operations with the same priority are given
+// connections in the order they started waiting.
+type CheckoutPriority int
+
+// These constants are the supported checkout priorities.
+const (
+	// CheckoutPriorityBackground is for bulk or background work that can tolerate waiting longer
+	// for a connection.
+	CheckoutPriorityBackground CheckoutPriority = -1
+
+	// CheckoutPriorityNormal is the priority used for operations that do not specify one.
+	CheckoutPriorityNormal CheckoutPriority = 0
+
+	// CheckoutPriorityCritical is for latency-sensitive work, such as health checks or payment
+	// writes, that should be given a connection ahead of other waiting operations.
+	CheckoutPriorityCritical CheckoutPriority = 1
+)
+
+type checkoutPriorityKey struct{}
+
+// WithCheckoutPriority returns a copy of ctx that carries the given checkout priority.
+func WithCheckoutPriority(ctx context.Context, priority CheckoutPriority) context.Context {
+	return context.WithValue(ctx, checkoutPriorityKey{}, priority)
+}
+
+// CheckoutPriorityFromContext returns the checkout priority carried by ctx, or
+// CheckoutPriorityNormal if ctx does not carry one.
+func CheckoutPriorityFromContext(ctx context.Context) CheckoutPriority {
+	if ctx == nil {
+		return CheckoutPriorityNormal
+	}
+	if p, ok := ctx.Value(checkoutPriorityKey{}).(CheckoutPriority); ok {
+		return p
+	}
+	return CheckoutPriorityNormal
+}
+
+type checkoutQueueTimeoutKey struct{}
+
+// WithCheckoutQueueTimeout returns a copy of ctx that limits how long an operation waits in a
+// connection pool's wait queue to the given duration, independently of the deadline of ctx. A
+// non-positive timeout means the wait is only limited by ctx.
+func WithCheckoutQueueTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, checkoutQueueTimeoutKey{}, timeout)
+}
+
+// CheckoutQueueTimeoutFromContext returns the checkout queue timeout carried by ctx and whether
+// ctx carries a positive one.
+func CheckoutQueueTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	timeout, ok := ctx.Value(checkoutQueueTimeoutKey{}).(time.Duration)
+	return timeout, ok && timeout > 0
+}
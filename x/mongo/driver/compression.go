@@ -9,6 +9,7 @@ package driver
 import (
 	"bytes"
 	"compress/zlib"
+	"context"
 	"fmt"
 	"io"
 	"sync"
@@ -26,6 +27,25 @@ type CompressionOpts struct {
 	UncompressedSize int32
 }
 
+type withoutCompressionKey struct{}
+
+// WithoutCompression returns a copy of ctx that disables wire message compression for the
+// operations executed with it, e.g. when their payloads are already compressed and would only
+// waste CPU time on the compressor. Replies may still be compressed by the server.
+func WithoutCompression(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutCompressionKey{}, true)
+}
+
+// CompressionDisabledFromContext reports whether wire message compression is disabled for the
+// operations executed with ctx.
+func CompressionDisabledFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	disabled, _ := ctx.Value(withoutCompressionKey{}).(bool)
+	return disabled
+}
+
 // mustZstdNewWriter creates a zstd.Encoder with the given level and a nil
 // destination writer. It panics on any errors and should only be used at
 // package initialization time.
@@ -81,7 +81,13 @@ var random = randutil.NewLockedRand()
 // ParseAndValidate parses the provided URI into a ConnString object.
 // It check that all values are valid.
 func ParseAndValidate(s string) (*ConnString, error) {
-	connStr, err := Parse(s)
+	return ParseAndValidateWithResolver(s, dns.DefaultResolver)
+}
+
+// ParseAndValidateWithResolver is like ParseAndValidate, but uses the provided
+// resolver to look up the SRV and TXT records of a mongodb+srv URI.
+func ParseAndValidateWithResolver(s string, resolver *dns.Resolver) (*ConnString, error) {
+	connStr, err := ParseWithResolver(s, resolver)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +102,13 @@ func ParseAndValidate(s string) (*ConnString, error) {
 // but does not check that all values are valid. Use `ConnString.Validate()`
 // to run the validation checks separately.
 func Parse(s string) (*ConnString, error) {
-	p := parser{dnsResolver: dns.DefaultResolver}
+	return ParseWithResolver(s, dns.DefaultResolver)
+}
+
+// ParseWithResolver is like Parse, but uses the provided resolver to look up
+// the SRV and TXT records of a mongodb+srv URI.
+func ParseWithResolver(s string, resolver *dns.Resolver) (*ConnString, error) {
+	p := parser{dnsResolver: resolver}
 	connStr, err := p.parse(s)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing uri: %w", err)
@@ -317,7 +329,16 @@ func (u *ConnString) setDefaultAuthParams(dbName string) error {
 			}
 		}
 	default:
-		return fmt.Errorf("invalid auth mechanism")
+		mech, ok := auth.LookupSASLMechanism(u.AuthMechanism)
+		if !ok {
+			return fmt.Errorf("invalid auth mechanism")
+		}
+		if u.AuthSource == "" {
+			u.AuthSource = mech.DefaultSource
+			if u.AuthSource == "" {
+				u.AuthSource = "$external"
+			}
+		}
 	}
 	return nil
 }
@@ -778,7 +799,9 @@ func (u *ConnString) validateAuth() error {
 			return fmt.Errorf("username required if URI contains user info")
 		}
 	default:
-		return fmt.Errorf("invalid auth mechanism")
+		if _, ok := auth.LookupSASLMechanism(u.AuthMechanism); !ok {
+			return fmt.Errorf("invalid auth mechanism")
+		}
 	}
 	return nil
 }
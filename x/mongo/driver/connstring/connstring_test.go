@@ -13,6 +13,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	driverauth "go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
 )
 
@@ -67,6 +68,24 @@ func TestAuthMechanism(t *testing.T) {
 	}
 }
 
+func TestCustomAuthMechanism(t *testing.T) {
+	err := driverauth.RegisterSASLMechanism(driverauth.SASLMechanism{
+		Name:          "CONNSTRING-TEST-SSO",
+		DefaultSource: "sso",
+		NewClient:     func(*driverauth.Cred) (driverauth.SaslClient, error) { return nil, nil },
+	})
+	require.NoError(t, err)
+
+	cs, err := connstring.ParseAndValidate("mongodb://user@localhost/?authMechanism=CONNSTRING-TEST-SSO&authMechanismProperties=TENANT:acme")
+	require.NoError(t, err)
+	require.Equal(t, "CONNSTRING-TEST-SSO", cs.AuthMechanism)
+	require.Equal(t, "sso", cs.AuthSource)
+	require.Equal(t, map[string]string{"TENANT": "acme"}, cs.AuthMechanismProperties)
+
+	_, err = connstring.ParseAndValidate("mongodb://user@localhost/?authMechanism=CONNSTRING-TEST-UNKNOWN")
+	require.Error(t, err)
+}
+
 func TestAuthSource(t *testing.T) {
 	tests := []struct {
 		s        string
@@ -85,6 +85,7 @@ type Server struct {
 	AverageRTTSet         bool
 	Compression           []string // compression methods returned by server
 	CanonicalAddr         address.Address
+	DataFederation        bool // whether the server is an Atlas Data Federation endpoint
 	ElectionID            bson.ObjectID
 	HeartbeatInterval     time.Duration
 	HelloOK               bool
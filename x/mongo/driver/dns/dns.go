@@ -14,11 +14,13 @@
 package dns
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Resolver resolves DNS records.
@@ -31,6 +33,35 @@ type Resolver struct {
 // DefaultResolver is a Resolver that uses the default Resolver from the net package.
 var DefaultResolver = &Resolver{net.LookupSRV, net.LookupTXT}
 
+// Provider looks up SRV and TXT records. *net.Resolver implements Provider.
+type Provider interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// lookupTimeout bounds each lookup of a Resolver created by NewResolver, because the
+// Resolver's callers cannot cancel a lookup.
+const lookupTimeout = 10 * time.Second
+
+// NewResolver creates a Resolver that looks up records with the given Provider. Each lookup
+// times out after 10 seconds.
+func NewResolver(p Provider) *Resolver {
+	return &Resolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+			defer cancel()
+
+			return p.LookupSRV(ctx, service, proto, name)
+		},
+		LookupTXT: func(name string) ([]string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+			defer cancel()
+
+			return p.LookupTXT(ctx, name)
+		},
+	}
+}
+
 // ParseHosts uses the srv string and service name to get the hosts.
 func (r *Resolver) ParseHosts(host string, srvName string, stopOnErr bool) ([]string, error) {
 	parsedHosts := strings.Split(host, ",")
@@ -0,0 +1,157 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// commandSizeAllowance is the number of bytes by which a command document may exceed the
+// maxBsonObjectSize of the server, which leaves room for the command fields around a document of
+// the maximum size, such as the filter of an update statement.
+const commandSizeAllowance = 16 * 1024
+
+// maxReportedFields is the maximum number of fields reported by a DocumentTooLargeError.
+const maxReportedFields = 5
+
+// FieldSize is the size in bytes of a field of a document, including its name and type.
+type FieldSize struct {
+	// Path is the dotted path of the field, with array indexes as path components, e.g.
+	// "items.3.payload".
+	Path string
+	Size int
+}
+
+// DocumentTooLargeError is returned by operations that would send a document larger than the
+// maxBsonObjectSize of the server. It identifies the fields that account for most of the size of
+// the document. DocumentTooLargeError wraps ErrDocumentTooLarge.
+type DocumentTooLargeError struct {
+	// Size is the size of the document in bytes and MaxSize is the maximum size accepted by the
+	// server.
+	Size    int
+	MaxSize int
+
+	// Index is the index of the document in the documents or statements of a write command, such
+	// as the documents of an insert, or -1 if the document is a command.
+	Index int
+
+	// Fields are the largest fields of the document, sorted by decreasing size. A field that
+	// consists mostly of a single embedded field is reported as the embedded field instead, so the
+	// paths point at the values that make the document large.
+	Fields []FieldSize
+}
+
+func newDocumentTooLargeError(doc bsoncore.Document, maxSize, index int) *DocumentTooLargeError {
+	var fields []FieldSize
+	collectLargeFields(doc, "", &fields)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Size > fields[j].Size })
+	if len(fields) > maxReportedFields {
+		fields = fields[:maxReportedFields]
+	}
+
+	return &DocumentTooLargeError{
+		Size:    len(doc),
+		MaxSize: maxSize,
+		Index:   index,
+		Fields:  fields,
+	}
+}
+
+// collectLargeFields appends the fields of doc to fields. Embedded documents and arrays with an
+// element that accounts for at least half of their size are replaced by their elements.
+func collectLargeFields(doc bsoncore.Document, prefix string, fields *[]FieldSize) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return
+	}
+	for _, elem := range elems {
+		path := elem.Key()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		var sub bsoncore.Document
+		switch val := elem.Value(); val.Type {
+		case bsoncore.TypeEmbeddedDocument, bsoncore.TypeArray:
+			sub = val.Data
+		}
+		if sub != nil && hasDominantElement(sub) {
+			collectLargeFields(sub, path, fields)
+			continue
+		}
+		*fields = append(*fields, FieldSize{Path: path, Size: len(elem)})
+	}
+}
+
+// hasDominantElement returns whether an element of doc accounts for at least half of its size.
+func hasDominantElement(doc bsoncore.Document) bool {
+	elems, err := doc.Elements()
+	if err != nil {
+		return false
+	}
+	for _, elem := range elems {
+		if 2*len(elem) >= len(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// Error implements the error interface.
+func (e *DocumentTooLargeError) Error() string {
+	var sb strings.Builder
+	if e.Index >= 0 {
+		fmt.Fprintf(&sb, "document %d is too large", e.Index)
+	} else {
+		sb.WriteString("command document is too large")
+	}
+	fmt.Fprintf(&sb, ": %d bytes exceeds the maximum of %d bytes", e.Size, e.MaxSize)
+	for i, f := range e.Fields {
+		if i == 0 {
+			sb.WriteString("; largest fields: ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Quote(f.Path))
+		fmt.Fprintf(&sb, " (%d bytes)", f.Size)
+	}
+	return sb.String()
+}
+
+// Unwrap returns ErrDocumentTooLarge.
+func (e *DocumentTooLargeError) Unwrap() error {
+	return ErrDocumentTooLarge
+}
+
+// DocumentSizeCheckDeployment is implemented by Deployments that check the size of command
+// documents against the maxBsonObjectSize of the selected server before sending them.
+type DocumentSizeCheckDeployment interface {
+	CheckDocumentSize() bool
+}
+
+// checkDocumentSize returns whether the operation's Deployment checks the size of command documents.
+func (op Operation) checkDocumentSize() bool {
+	d, ok := op.Deployment.(DocumentSizeCheckDeployment)
+	return ok && d.CheckDocumentSize()
+}
+
+// validateCommandSize returns a DocumentTooLargeError if cmd exceeds the maximum command size
+// accepted by a server with the given maxBsonObjectSize.
+func validateCommandSize(cmd bsoncore.Document, maxDocumentSize uint32) error {
+	if maxDocumentSize == 0 {
+		return nil
+	}
+	if maxSize := int(maxDocumentSize) + commandSizeAllowance; len(cmd) > maxSize {
+		return newDocumentTooLargeError(cmd, maxSize, -1)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func TestNewDocumentTooLargeError(t *testing.T) {
+	t.Parallel()
+
+	big := strings.Repeat("x", 1000)
+	doc := bsoncore.NewDocumentBuilder().
+		AppendString("name", "a").
+		AppendDocument("payload", bsoncore.NewDocumentBuilder().
+			AppendInt32("v", 1).
+			AppendString("blob", big).
+			Build()).
+		AppendArray("items", bsoncore.NewArrayBuilder().
+			AppendString(strings.Repeat("y", 100)).
+			AppendString(strings.Repeat("y", 100)).
+			AppendString(strings.Repeat("y", 100)).
+			Build()).
+		Build()
+
+	err := newDocumentTooLargeError(doc, 500, 2)
+	assert.Equal(t, len(doc), err.Size, "size mismatch")
+	assert.Equal(t, 2, err.Index, "index mismatch")
+	require.Len(t, err.Fields, 4, "expected the blob, items, name and v fields")
+	assert.Equal(t, "payload.blob", err.Fields[0].Path, "expected the blob to be the largest field")
+	assert.Equal(t, "items", err.Fields[1].Path, "expected an array without a dominant element to be reported whole")
+	assert.True(t, errors.Is(err, ErrDocumentTooLarge), "expected error to wrap ErrDocumentTooLarge")
+	assert.True(t, strings.HasPrefix(err.Error(), `document 2 is too large: `), "unexpected message %q", err.Error())
+	assert.True(t, strings.Contains(err.Error(), `largest fields: "payload.blob" (1011 bytes), "items"`),
+		"unexpected message %q", err.Error())
+}
+
+func TestValidateCommandSize(t *testing.T) {
+	t.Parallel()
+
+	cmd := bsoncore.NewDocumentBuilder().
+		AppendString("findAndModify", "coll").
+		AppendString("update", strings.Repeat("x", 2*commandSizeAllowance)).
+		Build()
+
+	assert.NoError(t, validateCommandSize(cmd, 0), "expected no check without a known maximum size")
+	assert.NoError(t, validateCommandSize(cmd, uint32(len(cmd))), "expected command within the allowance to be valid")
+
+	err := validateCommandSize(cmd, 1024)
+	var dte *DocumentTooLargeError
+	require.True(t, errors.As(err, &dte), "expected DocumentTooLargeError, got %v", err)
+	assert.Equal(t, -1, dte.Index, "expected command index")
+	assert.Equal(t, 1024+commandSizeAllowance, dte.MaxSize, "max size mismatch")
+	assert.Equal(t, "update", dte.Fields[0].Path, "largest field mismatch")
+}
+
+func TestAdvanceBatchDocumentTooLarge(t *testing.T) {
+	t.Parallel()
+
+	documents := []bsoncore.Document{
+		make(bsoncore.Document, 100),
+		make(bsoncore.Document, 100),
+		make(bsoncore.Document, 100),
+		bsoncore.NewDocumentBuilder().AppendString("s", strings.Repeat("x", 200)).Build(),
+	}
+	batches := &Batches{Identifier: "documents", Documents: documents}
+
+	require.NoError(t, batches.AdvanceBatch(2, 1000, 150), "AdvanceBatch error")
+	batches.ClearBatch()
+	err := batches.AdvanceBatch(2, 1000, 150)
+	var dte *DocumentTooLargeError
+	require.True(t, errors.As(err, &dte), "expected DocumentTooLargeError, got %v", err)
+	assert.Equal(t, 3, dte.Index, "expected the index of the document in all documents")
+}
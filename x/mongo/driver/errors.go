@@ -103,6 +103,9 @@ type WriteCommandError struct {
 	WriteErrors       WriteErrors
 	Labels            []string
 	Raw               bsoncore.Document
+
+	// RetryInfo describes the attempts made to execute the operation if it was retried.
+	RetryInfo *RetryInfo
 }
 
 // UnsupportedStorageEngine returns whether or not the WriteCommandError comes from a retryable write being attempted
@@ -116,6 +119,11 @@ func (wce WriteCommandError) UnsupportedStorageEngine() bool {
 	return false
 }
 
+// As implements errors.As for *RetryInfo targets.
+func (wce WriteCommandError) As(target interface{}) bool {
+	return asRetryInfo(wce.RetryInfo, target)
+}
+
 func (wce WriteCommandError) Error() string {
 	var buf bytes.Buffer
 	fmt.Fprint(&buf, "write command error: [")
@@ -252,6 +260,9 @@ type Error struct {
 	Wrapped         error
 	TopologyVersion *description.TopologyVersion
 	Raw             bsoncore.Document
+
+	// RetryInfo describes the attempts made to execute the operation if it was retried.
+	RetryInfo *RetryInfo
 }
 
 // UnsupportedStorageEngine returns whether e came as a result of an unsupported storage engine
@@ -277,6 +288,11 @@ func (e Error) Unwrap() error {
 	return e.Wrapped
 }
 
+// As implements errors.As for *RetryInfo targets.
+func (e Error) As(target interface{}) bool {
+	return asRetryInfo(e.RetryInfo, target)
+}
+
 // HasErrorLabel returns true if the error contains the specified label.
 func (e Error) HasErrorLabel(label string) bool {
 	for _, l := range e.Labels {
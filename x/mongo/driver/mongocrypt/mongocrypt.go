@@ -104,6 +104,16 @@ func NewMongoCrypt(opts *options.MongoCryptOptions) (*MongoCrypt, error) {
 
 	C.mongocrypt_setopt_use_need_kms_credentials_state(crypt.wrapped)
 
+	if opts.KeyExpiration != nil {
+		ms := opts.KeyExpiration.Milliseconds()
+		if ms < 0 {
+			return nil, fmt.Errorf("key expiration must not be negative, got %v", *opts.KeyExpiration)
+		}
+		if ok := C.mongocrypt_setopt_key_expiration(crypt.wrapped, C.uint64_t(ms)); !ok {
+			return nil, crypt.createErrorFromStatus()
+		}
+	}
+
 	// initialize handle
 	if !C.mongocrypt_init(crypt.wrapped) {
 		return nil, crypt.createErrorFromStatus()
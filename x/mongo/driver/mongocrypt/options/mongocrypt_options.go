@@ -8,6 +8,7 @@ package options
 
 import (
 	"net/http"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 )
@@ -21,6 +22,7 @@ type MongoCryptOptions struct {
 	CryptSharedLibDisabled     bool
 	CryptSharedLibOverridePath string
 	HTTPClient                 *http.Client
+	KeyExpiration              *time.Duration
 }
 
 // MongoCrypt creates a new MongoCryptOptions instance.
@@ -70,3 +72,9 @@ func (mo *MongoCryptOptions) SetHTTPClient(httpClient *http.Client) *MongoCryptO
 	mo.HTTPClient = httpClient
 	return mo
 }
+
+// SetKeyExpiration sets the expiration time of the data key cache. A value of 0 disables expiration.
+func (mo *MongoCryptOptions) SetKeyExpiration(d *time.Duration) *MongoCryptOptions {
+	mo.KeyExpiration = d
+	return mo
+}
@@ -29,7 +29,8 @@ type Cache interface {
 
 // ConcurrentCache is an implementation of ocsp.Cache that's safe for concurrent use.
 type ConcurrentCache struct {
-	cache map[cacheKey]*ResponseDetails
+	cache  map[cacheKey]*ResponseDetails
+	maxAge time.Duration
 	sync.Mutex
 }
 
@@ -42,6 +43,14 @@ func NewCache() *ConcurrentCache {
 	}
 }
 
+// NewCacheWithMaxAge creates an empty OCSP cache that keeps responses for at most maxAge, even if they are valid for
+// longer. A maxAge of 0 means that responses are kept until their NextUpdate time.
+func NewCacheWithMaxAge(maxAge time.Duration) *ConcurrentCache {
+	c := NewCache()
+	c.maxAge = maxAge
+	return c
+}
+
 // Update updates the cache entry for the provided request. The provided response will only be cached if it has a
 // status that is not ocsp.Unknown and has a non-zero NextUpdate time. If there is an existing cache entry for request,
 // it will be overwritten by response if response.NextUpdate is further ahead in the future than the existing entry's
@@ -54,6 +63,14 @@ func (c *ConcurrentCache) Update(request *ocsp.Request, response *ResponseDetail
 	canBeCached := !unknown && hasUpdateTime
 	key := createCacheKey(request)
 
+	if c.maxAge > 0 && canBeCached {
+		if limit := time.Now().UTC().Add(c.maxAge); response.NextUpdate.After(limit) {
+			clamped := *response
+			clamped.NextUpdate = limit
+			response = &clamped
+		}
+	}
+
 	c.Lock()
 	defer c.Unlock()
 
@@ -111,6 +128,22 @@ func (c *ConcurrentCache) Get(request *ocsp.Request) *ResponseDetails {
 	return nil
 }
 
+// NoCache is an implementation of ocsp.Cache that does not cache responses, so that every verification uses a stapled
+// response or contacts the OCSP responders.
+type NoCache struct{}
+
+var _ Cache = NoCache{}
+
+// Update returns response without caching it.
+func (NoCache) Update(_ *ocsp.Request, response *ResponseDetails) *ResponseDetails {
+	return response
+}
+
+// Get always returns nil.
+func (NoCache) Get(*ocsp.Request) *ResponseDetails {
+	return nil
+}
+
 func createCacheKey(request *ocsp.Request) cacheKey {
 	return cacheKey{
 		HashAlgorithm:  request.HashAlgorithm,
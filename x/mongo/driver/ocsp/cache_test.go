@@ -187,6 +187,25 @@ func TestCache(t *testing.T) {
 	})
 }
 
+func TestCacheMaxAge(t *testing.T) {
+	testRequest := &ocsp.Request{
+		HashAlgorithm:  crypto.SHA1,
+		IssuerNameHash: []byte("issuerNameHash"),
+		IssuerKeyHash:  []byte("issuerKeyHash"),
+	}
+
+	cache := NewCacheWithMaxAge(time.Minute)
+	res := cache.Update(testRequest, &ResponseDetails{Status: ocsp.Good, NextUpdate: futureTime(10)})
+	assert.True(t, res.NextUpdate.Before(futureTime(2)), "expected NextUpdate to be limited by the max age, got %v",
+		res.NextUpdate)
+	assert.NotNil(t, cache.Get(testRequest), "expected the response to be cached")
+
+	var noCache NoCache
+	res = noCache.Update(testRequest, &ResponseDetails{Status: ocsp.Good, NextUpdate: futureTime(10)})
+	assert.NotNil(t, res, "expected Update to return the response")
+	assert.Nil(t, noCache.Get(testRequest), "expected no cached response")
+}
+
 func futureTime(minutes int) time.Time {
 	return time.Now().Add(time.Duration(minutes) * time.Minute).UTC()
 }
@@ -69,75 +69,129 @@ func extractResponseDetails(res *ocsp.Response) *ResponseDetails {
 	}
 }
 
-// Verify performs OCSP verification for the provided ConnectionState instance.
+// Source is the source of the OCSP response used to verify a certificate.
+type Source string
+
+// These constants are the sources of OCSP responses.
+const (
+	// SourceNone means that no OCSP response was available.
+	SourceNone Source = ""
+	// SourceStaple means that the response was stapled by the server during the TLS handshake.
+	SourceStaple Source = "staple"
+	// SourceCache means that the response was cached from a previous verification.
+	SourceCache Source = "cache"
+	// SourceResponder means that the response was requested from an OCSP responder.
+	SourceResponder Source = "responder"
+)
+
+// Result is the outcome of the OCSP verification of a server certificate.
+type Result struct {
+	// Status is the status of the certificate, which is ocsp.Good, ocsp.Revoked, or ocsp.Unknown if the status could
+	// not be determined.
+	Status int
+
+	// Source is the source of the OCSP response.
+	Source Source
+
+	// SoftFail is true if the status of the certificate could not be determined but verification succeeded because
+	// hard-fail was not requested.
+	SoftFail bool
+
+	// Err is the verification error, or nil if verification succeeded.
+	Err error
+}
+
+// Verify performs OCSP verification for the provided ConnectionState instance. If opts has a Callback, it is called
+// with the outcome of the verification.
 func Verify(ctx context.Context, connState tls.ConnectionState, opts *VerifyOptions) error {
+	res := verify(ctx, connState, opts)
+	if opts.Callback != nil {
+		opts.Callback(res)
+	}
+	return res.Err
+}
+
+func verify(ctx context.Context, connState tls.ConnectionState, opts *VerifyOptions) Result {
+	res := Result{Status: ocsp.Unknown}
 	if opts.Cache == nil {
 		// There should always be an OCSP cache. Even if the user has specified the URI option to disable communication
 		// with OCSP responders, the driver will cache any stapled responses. Requiring that the cache is non-nil
 		// allows us to confirm that the cache is correctly being passed down from a higher level.
-		return newOCSPError(errors.New("no OCSP cache provided"))
+		res.Err = newOCSPError(errors.New("no OCSP cache provided"))
+		return res
 	}
 	if len(connState.VerifiedChains) == 0 {
-		return newOCSPError(errors.New("no verified certificate chains reported after TLS handshake"))
+		res.Err = newOCSPError(errors.New("no verified certificate chains reported after TLS handshake"))
+		return res
 	}
 
 	certChain := connState.VerifiedChains[0]
 	if numCerts := len(certChain); numCerts == 0 {
-		return newOCSPError(errors.New("verified chain contained no certificates"))
+		res.Err = newOCSPError(errors.New("verified chain contained no certificates"))
+		return res
 	}
 
 	ocspCfg, err := newConfig(certChain, opts)
 	if err != nil {
-		return newOCSPError(err)
+		res.Err = newOCSPError(err)
+		return res
 	}
 
-	res, err := getParsedResponse(ctx, ocspCfg, connState)
+	details, source, err := getParsedResponse(ctx, ocspCfg, connState)
+	res.Source = source
 	if err != nil {
-		return err
+		res.Err = err
+		return res
 	}
-	if res == nil {
-		// If no response was parsed from the staple and responders, the status of the certificate is unknown, so don't
-		// error.
-		return nil
+	if details == nil || details.Status == ocsp.Unknown {
+		// If no conclusive response was parsed from the staple and responders, the status of the certificate is
+		// unknown, so only error if hard-fail was requested.
+		if opts.HardFail {
+			res.Err = newOCSPError(errors.New("certificate status could not be determined"))
+			return res
+		}
+		res.SoftFail = true
+		return res
 	}
 
-	if res.Status == ocsp.Revoked {
-		return newOCSPError(errors.New("certificate is revoked"))
+	res.Status = details.Status
+	if details.Status == ocsp.Revoked {
+		res.Err = newOCSPError(errors.New("certificate is revoked"))
 	}
-	return nil
+	return res
 }
 
 // getParsedResponse attempts to parse a response from the stapled OCSP data or by contacting OCSP responders if no
-// staple is present.
-func getParsedResponse(ctx context.Context, cfg config, connState tls.ConnectionState) (*ResponseDetails, error) {
+// staple is present. It also returns the source of the response.
+func getParsedResponse(ctx context.Context, cfg config, connState tls.ConnectionState) (*ResponseDetails, Source, error) {
 	stapledResponse, err := processStaple(cfg, connState.OCSPResponse)
 	if err != nil {
-		return nil, err
+		return nil, SourceStaple, err
 	}
 
 	if stapledResponse != nil {
 		// If there is a staple, attempt to cache it. The cache.Update call will resolve conflicts with an existing
 		// cache enry if necessary.
-		return cfg.cache.Update(cfg.ocspRequest, stapledResponse), nil
+		return cfg.cache.Update(cfg.ocspRequest, stapledResponse), SourceStaple, nil
 	}
 	if cachedResponse := cfg.cache.Get(cfg.ocspRequest); cachedResponse != nil {
-		return cachedResponse, nil
+		return cachedResponse, SourceCache, nil
 	}
 
 	// If there is no stapled or cached response, fall back to querying the responders if that functionality has not
 	// been disabled.
 	if cfg.disableEndpointChecking {
-		return nil, nil
+		return nil, SourceNone, nil
 	}
 	externalResponse := contactResponders(ctx, cfg)
 	if externalResponse == nil {
 		// None of the responders were available.
-		return nil, nil
+		return nil, SourceNone, nil
 	}
 
 	// Similar to the stapled response case above, unconditionally call Update and it will either cache the response
 	// or resolve conflicts if a different connection has cached a response since the previous call to Get.
-	return cfg.cache.Update(cfg.ocspRequest, externalResponse), nil
+	return cfg.cache.Update(cfg.ocspRequest, externalResponse), SourceResponder, nil
 }
 
 // processStaple returns the OCSP response from the provided staple. An error will be returned if any of the following
@@ -158,6 +212,9 @@ func processStaple(cfg config, staple []byte) (*ResponseDetails, error) {
 		return nil, errors.New("server provided a certificate with the Must-Staple extension but did not " +
 			"provide a stapled OCSP response")
 	}
+	if cfg.requireStaple && len(staple) == 0 {
+		return nil, errors.New("a stapled OCSP response is required but the server did not provide one")
+	}
 
 	if len(staple) == 0 {
 		return nil, nil
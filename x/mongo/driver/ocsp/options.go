@@ -13,4 +13,13 @@ type VerifyOptions struct {
 	Cache                   Cache
 	DisableEndpointChecking bool
 	HTTPClient              *http.Client
+
+	// RequireStaple causes verification to fail if the server does not staple an OCSP response.
+	RequireStaple bool
+
+	// HardFail causes verification to fail if the status of the certificate cannot be determined.
+	HardFail bool
+
+	// Callback is called with the outcome of each verification.
+	Callback func(Result)
 }
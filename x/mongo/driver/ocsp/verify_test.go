@@ -0,0 +1,93 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package ocsp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newSelfSignedConnState returns a ConnectionState for a self-signed server certificate and, if
+// status is not ocsp.Unknown, a staple with that status signed by the certificate.
+func newSelfSignedConnState(t *testing.T, status int) tls.ConnectionState {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "GenerateKey error")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err, "CreateCertificate error")
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "ParseCertificate error")
+
+	connState := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	if status != ocsp.Unknown {
+		connState.OCSPResponse, err = ocsp.CreateResponse(cert, cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: cert.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, key)
+		require.NoError(t, err, "CreateResponse error")
+	}
+	return connState
+}
+
+func TestVerifyPolicy(t *testing.T) {
+	verify := func(connState tls.ConnectionState, opts *VerifyOptions) (Result, error) {
+		var res Result
+		opts.Cache = NewCache()
+		opts.DisableEndpointChecking = true
+		opts.Callback = func(r Result) { res = r }
+		err := Verify(ctx, connState, opts)
+		return res, err
+	}
+
+	t.Run("soft-fail", func(t *testing.T) {
+		res, err := verify(newSelfSignedConnState(t, ocsp.Unknown), &VerifyOptions{})
+		assert.NoError(t, err, "expected soft-fail")
+		assert.True(t, res.SoftFail, "expected SoftFail to be reported")
+		assert.Equal(t, SourceNone, res.Source, "source mismatch")
+		assert.Equal(t, ocsp.Unknown, res.Status, "status mismatch")
+	})
+	t.Run("hard-fail", func(t *testing.T) {
+		res, err := verify(newSelfSignedConnState(t, ocsp.Unknown), &VerifyOptions{HardFail: true})
+		assert.Error(t, err, "expected hard-fail")
+		assert.Equal(t, err, res.Err, "expected the error to be reported")
+		assert.False(t, res.SoftFail, "expected no SoftFail")
+	})
+	t.Run("require staple", func(t *testing.T) {
+		_, err := verify(newSelfSignedConnState(t, ocsp.Unknown), &VerifyOptions{RequireStaple: true})
+		assert.ErrorContains(t, err, "stapled OCSP response is required", "expected an error without a staple")
+
+		res, err := verify(newSelfSignedConnState(t, ocsp.Good), &VerifyOptions{RequireStaple: true, HardFail: true})
+		assert.NoError(t, err, "Verify error")
+		assert.Equal(t, SourceStaple, res.Source, "source mismatch")
+		assert.Equal(t, ocsp.Good, res.Status, "status mismatch")
+	})
+	t.Run("revoked", func(t *testing.T) {
+		res, err := verify(newSelfSignedConnState(t, ocsp.Revoked), &VerifyOptions{})
+		assert.ErrorContains(t, err, "revoked", "expected a revoked error")
+		assert.Equal(t, ocsp.Revoked, res.Status, "status mismatch")
+	})
+}
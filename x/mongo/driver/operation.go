@@ -110,11 +110,18 @@ type startedInformation struct {
 	redacted                 bool
 	serviceID                *bson.ObjectID
 	serverAddress            address.Address
+	comment                  bson.RawValue
+	collection               string
+
+	// documentSequencesIncluded is true if the DocumentSequences of the operation were sent as
+	// document sequences.
+	documentSequencesIncluded bool
 }
 
 // finishedInformation keeps track of all of the information necessary for monitoring success and failure events.
 type finishedInformation struct {
 	cmdName            string
+	collection         string
 	requestID          int32
 	response           bsoncore.Document
 	cmdErr             error
@@ -124,7 +131,22 @@ type finishedInformation struct {
 	redacted           bool
 	serviceID          *bson.ObjectID
 	serverAddress      address.Address
+	comment            bson.RawValue
 	duration           time.Duration
+	requestSize        messageSize
+	replySize          messageSize
+	requestDocuments   int
+
+	retryAttempt          int
+	retryReason           RetryReason
+	previousServerAddress address.Address
+}
+
+// messageSize holds the size in bytes of a wire message as it is sent over the network and before
+// compression or after decompression.
+type messageSize struct {
+	wire         int
+	uncompressed int
 }
 
 // success returns true if there was no command error or the command error is a
@@ -160,10 +182,15 @@ func redactStartedInformationCmd(op Operation, info startedInformation) bson.Raw
 		cmdCopy = make([]byte, len(info.cmd))
 		copy(cmdCopy, info.cmd)
 
-		if info.documentSequenceIncluded {
+		if info.documentSequenceIncluded || info.documentSequencesIncluded {
 			// remove 0 byte at end
 			cmdCopy = cmdCopy[:len(info.cmd)-1]
-			cmdCopy = op.addBatchArray(cmdCopy)
+			if info.documentSequenceIncluded {
+				cmdCopy = op.addBatchArray(cmdCopy)
+			}
+			if info.documentSequencesIncluded {
+				cmdCopy = op.addDocumentSequenceArrays(cmdCopy)
+			}
 
 			// add back 0 byte and update length
 			cmdCopy, _ = bsoncore.AppendDocumentEnd(cmdCopy, 0)
@@ -181,6 +208,15 @@ func redactFinishedInformationResponse(info finishedInformation) bson.Raw {
 	return bson.Raw{}
 }
 
+// applyRedactFunc returns the result of the command monitor's Redact function for a command or
+// reply document that was not already redacted because it is security sensitive.
+func (op Operation) applyRedactFunc(cmdName string, redacted bool, doc bson.Raw) bson.Raw {
+	if redacted || op.CommandMonitor == nil || op.CommandMonitor.Redact == nil {
+		return doc
+	}
+	return op.CommandMonitor.Redact(cmdName, doc)
+}
+
 // Operation is used to execute an operation. It contains all of the common code required to
 // select a server, transform an operation into a command, write the command to a connection from
 // the selected server, read a response from that connection, process the response, and potentially
@@ -270,6 +306,12 @@ type Operation struct {
 	// Batches.
 	Batches *Batches
 
+	// DocumentSequences are sent as OP_MSG document sequences (kind 1 sections) after the command
+	// document. Unlike Batches, they are never split, so the whole message must fit in the maximum
+	// message size of the server. If auto encryption is enabled, they are sent as arrays in the
+	// command document instead.
+	DocumentSequences []DocumentSequence
+
 	// Legacy sets the legacy type for this operation. There are only 3 types that require legacy
 	// support: find, getMore, and killCursors. For more information about LegacyOperationKind,
 	// please refer to it's definition.
@@ -495,8 +537,39 @@ var memoryPool = sync.Pool{
 	},
 }
 
-// Execute runs this operation.
+// Execute runs this operation. If the operation was retried and fails with an Error or a
+// WriteCommandError, its RetryInfo field describes the attempts. If the Deployment has an
+// OperationMiddleware, the operation is run through it. If the Deployment has a CausalTimeStore,
+// the stored times are injected into the session of the operation before it runs and the times
+// of the session are saved after it runs.
 func (op Operation) Execute(ctx context.Context) error {
+	if store := op.causalTimeStore(); store != nil && op.Client != nil {
+		return op.executeWithCausalTimes(ctx, store, op.executeWithDeploymentMiddleware)
+	}
+	return op.executeWithDeploymentMiddleware(ctx)
+}
+
+// executeWithDeploymentMiddleware runs this operation through the OperationMiddleware of the
+// Deployment, if any.
+func (op Operation) executeWithDeploymentMiddleware(ctx context.Context) error {
+	if mw := op.operationMiddleware(); mw != nil {
+		return op.executeWithMiddleware(ctx, mw)
+	}
+	return op.executeWithRetryInfo(ctx)
+}
+
+// executeWithRetryInfo runs this operation and adds its RetryInfo to the returned error.
+func (op Operation) executeWithRetryInfo(ctx context.Context) error {
+	var retryInfo RetryInfo
+	err := op.execute(ctx, &retryInfo)
+	if err != nil && retryInfo.Reason != "" {
+		err = withRetryInfo(err, &retryInfo)
+	}
+	return err
+}
+
+// execute runs this operation and records its retries in retryInfo.
+func (op Operation) execute(ctx context.Context, retryInfo *RetryInfo) error {
 	err := op.Validate()
 	if err != nil {
 		return err
@@ -506,11 +579,18 @@ func (op Operation) Execute(ctx context.Context) error {
 	defer cancel()
 
 	if op.Client != nil {
+		// Snapshot sessions are read-only. Reject writes before selecting a server so the error
+		// does not depend on the server version.
+		if op.Client.Snapshot && (op.Type == Write || op.IsOutputAggregate) {
+			return session.ErrSnapshotWrite
+		}
 		if err := op.Client.StartCommand(); err != nil {
 			return err
 		}
 	}
 
+	policy := op.retryPolicy()
+
 	var retries int
 	if op.RetryMode != nil {
 		switch op.Type {
@@ -520,14 +600,14 @@ func (op Operation) Execute(ctx context.Context) error {
 			}
 			switch *op.RetryMode {
 			case RetryOnce, RetryOncePerCommand:
-				retries = 1
+				retries = policy.retries()
 			case RetryContext:
 				retries = -1
 			}
 		case Read:
 			switch *op.RetryMode {
 			case RetryOnce, RetryOncePerCommand:
-				retries = 1
+				retries = policy.retries()
 			case RetryContext:
 				retries = -1
 			}
@@ -556,9 +636,14 @@ func (op Operation) Execute(ctx context.Context) error {
 	// only ever deprioritize the "previous server".
 	var deprioritizedServers []description.Server
 
-	// resetForRetry records the error that caused the retry, decrements retries, and resets the
+	// retryCount is the number of retries started so far for the current batch, and
+	// retryServer is the server of the attempt that the last retry replaced.
+	var retryCount int
+	var retryServer address.Address
+
+	// resetConnection records the error that caused the retry, decrements retries, and resets the
 	// retry loop variables to request a new server and a new connection for the next attempt.
-	resetForRetry := func(err error) {
+	resetConnection := func(err error) {
 		retries--
 		prevErr = err
 
@@ -597,6 +682,19 @@ func (op Operation) Execute(ctx context.Context) error {
 		conn = nil
 	}
 
+	// resetForRetry resets the retry loop variables after a retryable error, records the retry
+	// reason, and waits before the retry if the retry policy configures a backoff.
+	resetForRetry := func(err error) {
+		resetConnection(err)
+		retryInfo.Reason, retryInfo.Labels = retryReasonFor(err)
+		if n := len(retryInfo.Servers); n > 0 {
+			retryServer = retryInfo.Servers[n-1]
+		}
+
+		retryCount++
+		policy.wait(ctx, retryCount)
+	}
+
 	wm := memoryPool.Get().(*[]byte)
 	defer func() {
 		// Proper usage of a sync.Pool requires each entry to have approximately the same memory
@@ -726,6 +824,11 @@ func (op Operation) Execute(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if op.checkDocumentSize() {
+			if err := validateCommandSize(startedInfo.cmd, desc.MaxDocumentSize); err != nil {
+				return err
+			}
+		}
 
 		// set extra data and send event if possible
 		startedInfo.connID = conn.ID()
@@ -744,14 +847,21 @@ func (op Operation) Execute(ctx context.Context) error {
 		startedInfo.serviceID = conn.Description().ServiceID
 		startedInfo.serverConnID = conn.ServerConnectionID()
 		startedInfo.serverAddress = conn.Description().Addr
+		if !startedInfo.redacted {
+			startedInfo.comment = commandComment(startedInfo.cmd)
+			startedInfo.collection = commandCollection(startedInfo.cmdName, startedInfo.cmd)
+		}
 
 		op.publishStartedEvent(ctx, startedInfo)
 
 		// get the moreToCome flag information before we compress
 		moreToCome := wiremessage.IsMsgMoreToCome(*wm)
 
+		requestSize := messageSize{uncompressed: len(*wm)}
+
 		// compress wiremessage if allowed
-		if compressor := conn.Compressor; compressor != nil && op.canCompress(startedInfo.cmdName) {
+		if compressor := conn.Compressor; compressor != nil && op.canCompress(startedInfo.cmdName) &&
+			!CompressionDisabledFromContext(ctx) {
 			b := memoryPool.Get().(*[]byte)
 			*b, err = compressor.CompressWireMessage(*wm, (*b)[:0])
 			memoryPool.Put(wm)
@@ -770,6 +880,20 @@ func (op Operation) Execute(ctx context.Context) error {
 			redacted:           startedInfo.redacted,
 			serviceID:          startedInfo.serviceID,
 			serverAddress:      desc.Server.Addr,
+			comment:            startedInfo.comment,
+			collection:         startedInfo.collection,
+		}
+		retryInfo.Attempts++
+		retryInfo.Servers = append(retryInfo.Servers, startedInfo.serverAddress)
+		if retryCount > 0 {
+			finishedInfo.retryAttempt = retryCount
+			finishedInfo.retryReason = retryInfo.Reason
+			finishedInfo.previousServerAddress = retryServer
+		}
+		requestSize.wire = len(*wm)
+		finishedInfo.requestSize = requestSize
+		if startedInfo.documentSequenceIncluded {
+			finishedInfo.requestDocuments = len(op.Batches.Current)
 		}
 
 		startedTime := time.Now()
@@ -792,7 +916,7 @@ func (op Operation) Execute(ctx context.Context) error {
 			if moreToCome {
 				roundTrip = op.moreToComeRoundTrip
 			}
-			res, err = roundTrip(ctx, conn, *wm)
+			res, err = roundTrip(ctx, conn, *wm, &finishedInfo.replySize)
 
 			if ep, ok := srvr.(ErrorProcessor); ok {
 				_ = ep.ProcessError(err, conn)
@@ -830,6 +954,10 @@ func (op Operation) Execute(ctx context.Context) error {
 				tt.Labels = append(tt.Labels, RetryableWriteError)
 			}
 
+			if retrySupported && retries != 0 {
+				retryableErr = policy.retryable(op, retryCount+1, tt, retryableErr)
+			}
+
 			// If retries are supported for the current operation on the first server description,
 			// the error is considered retryable, and there are retries remaining (negative retries
 			// means retry indefinitely), then retry the operation.
@@ -918,7 +1046,9 @@ func (op Operation) Execute(ctx context.Context) error {
 						op.Client.UpdateCommitTransactionWriteConcern()
 						op.WriteConcern = op.Client.CurrentWc
 					}
-					resetForRetry(tt)
+					// Reauthentication is not a retry after a retryable error, so don't record a
+					// retry reason or wait for the retry policy's backoff.
+					resetConnection(tt)
 					continue
 				}
 			}
@@ -949,6 +1079,10 @@ func (op Operation) Execute(ctx context.Context) error {
 				retryableErr = tt.RetryableRead()
 			}
 
+			if retrySupported && retries != 0 {
+				retryableErr = policy.retryable(op, retryCount+1, tt, retryableErr)
+			}
+
 			// If retries are supported for the current operation on the first server description,
 			// the error is considered retryable, and there are retries remaining (negative retries
 			// means retry indefinitely), then retry the operation.
@@ -1035,9 +1169,14 @@ func (op Operation) Execute(ctx context.Context) error {
 				// Reset the retries number for RetryOncePerCommand unless context is a Timeout context, in
 				// which case retries should remain as -1 (as many times as possible).
 				if *op.RetryMode == RetryOncePerCommand && !csot.IsTimeoutContext(ctx) {
-					retries = 1
+					retries = policy.retries()
 				}
 			}
+			// Each batch is retried and backs off from the start of the retry policy, and its
+			// retries are recorded on their own.
+			retryCount = 0
+			retryServer = ""
+			*retryInfo = RetryInfo{}
 			currIndex += len(op.Batches.Current)
 			op.Batches.ClearBatch()
 			continue
@@ -1076,15 +1215,17 @@ func (op Operation) retryable(desc description.Server) bool {
 
 // roundTrip writes a wiremessage to the connection and then reads a wiremessage. The wm parameter
 // is reused when reading the wiremessage.
-func (op Operation) roundTrip(ctx context.Context, conn *mnet.Connection, wm []byte) ([]byte, error) {
+func (op Operation) roundTrip(ctx context.Context, conn *mnet.Connection, wm []byte, size *messageSize) ([]byte, error) {
 	err := conn.Write(ctx, wm)
 	if err != nil {
 		return nil, op.networkError(err)
 	}
-	return op.readWireMessage(ctx, conn)
+	return op.readWireMessage(ctx, conn, size)
 }
 
-func (op Operation) readWireMessage(ctx context.Context, conn *mnet.Connection) (result []byte, err error) {
+// readWireMessage reads and decodes a reply from conn. If size is not nil, the size of the reply
+// wire message is recorded in it.
+func (op Operation) readWireMessage(ctx context.Context, conn *mnet.Connection, size *messageSize) (result []byte, err error) {
 	wm, err := conn.Read(ctx)
 	if err != nil {
 		return nil, op.networkError(err)
@@ -1108,6 +1249,10 @@ func (op Operation) readWireMessage(ctx context.Context, conn *mnet.Connection)
 			return nil, err
 		}
 	}
+	if size != nil {
+		size.wire = int(length)
+		size.uncompressed = 16 + len(rem) // add back header size
+	}
 
 	// decode
 	res, err := op.decodeResult(opcode, rem)
@@ -1156,7 +1301,12 @@ func (op Operation) networkError(err error) error {
 
 // moreToComeRoundTrip writes a wiremessage to the provided connection. This is used when an OP_MSG is
 // being sent with  the moreToCome bit set.
-func (op *Operation) moreToComeRoundTrip(ctx context.Context, conn *mnet.Connection, wm []byte) (result []byte, err error) {
+func (op *Operation) moreToComeRoundTrip(
+	ctx context.Context,
+	conn *mnet.Connection,
+	wm []byte,
+	_ *messageSize,
+) (result []byte, err error) {
 	err = conn.Write(ctx, wm)
 	if err != nil {
 		if op.Client != nil {
@@ -1205,6 +1355,19 @@ func (op Operation) addBatchArray(dst []byte) []byte {
 	return dst
 }
 
+// addDocumentSequenceArrays appends the DocumentSequences of the operation to dst as arrays.
+func (op Operation) addDocumentSequenceArrays(dst []byte) []byte {
+	for _, seq := range op.DocumentSequences {
+		var aidx int32
+		aidx, dst = bsoncore.AppendArrayElementStart(dst, seq.Identifier)
+		for i, doc := range seq.Documents {
+			dst = bsoncore.AppendDocumentElement(dst, strconv.Itoa(i), doc)
+		}
+		dst, _ = bsoncore.AppendArrayEnd(dst, aidx)
+	}
+	return dst
+}
+
 func (op Operation) createLegacyHandshakeWireMessage(
 	ctx context.Context,
 	maxTimeMS int64,
@@ -1369,6 +1532,22 @@ func (op Operation) createMsgWireMessage(
 
 		dst = bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:])))
 	}
+	if !op.shouldEncrypt() && len(op.DocumentSequences) > 0 {
+		info.documentSequencesIncluded = true
+		for _, seq := range op.DocumentSequences {
+			dst = wiremessage.AppendMsgSectionType(dst, wiremessage.DocumentSequence)
+			idx, dst = bsoncore.ReserveLength(dst)
+
+			dst = append(dst, seq.Identifier...)
+			dst = append(dst, 0x00)
+
+			for _, doc := range seq.Documents {
+				dst = append(dst, doc...)
+			}
+
+			dst = bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:])))
+		}
+	}
 
 	return bsoncore.UpdateLength(dst, wmindex, int32(len(dst[wmindex:]))), info, nil
 }
@@ -1418,6 +1597,7 @@ func (op Operation) addCommandFields(ctx context.Context, dst []byte, desc descr
 	if op.Batches != nil && len(op.Batches.Current) > 0 {
 		cmdDst = op.addBatchArray(cmdDst)
 	}
+	cmdDst = op.addDocumentSequenceArrays(cmdDst)
 	cmdDst, _ = bsoncore.AppendDocumentEnd(cmdDst, cidx)
 
 	// encrypt the command
@@ -1655,11 +1835,27 @@ func (op Operation) addClusterTime(dst []byte, desc description.SelectedServer)
 	// return bsoncore.AppendDocumentElement(dst, "$clusterTime", clusterTime)
 }
 
+// MaxTimeAllowanceDeployment is implemented by Deployments that reserve a minimum part of the
+// remaining time until the context deadline of an operation for the network round trip when
+// calculating maxTimeMS.
+type MaxTimeAllowanceDeployment interface {
+	MaxTimeAllowance() time.Duration
+}
+
+// maxTimeAllowance returns the MaxTimeAllowance of the operation's Deployment, if any.
+func (op Operation) maxTimeAllowance() time.Duration {
+	if d, ok := op.Deployment.(MaxTimeAllowanceDeployment); ok {
+		return d.MaxTimeAllowance()
+	}
+	return 0
+}
+
 // calculateMaxTimeMS calculates the value of the 'maxTimeMS' field to potentially append
 // to the wire message based on the current context's deadline and the 90th percentile RTT
 // if the ctx is a Timeout context. If the context is not a Timeout context, it uses the
 // operation's MaxTimeMS if set. If no MaxTimeMS is set on the operation, and context is
-// not a Timeout context, calculateMaxTimeMS returns 0.
+// not a Timeout context, calculateMaxTimeMS returns 0. If the Deployment reserves a
+// MaxTimeAllowance larger than rttMin, the allowance is subtracted instead of rttMin.
 func (op Operation) calculateMaxTimeMS(ctx context.Context, rttMin time.Duration, rttStats string) (int64, error) {
 	if op.OmitMaxTimeMS {
 		return 0, nil
@@ -1671,11 +1867,23 @@ func (op Operation) calculateMaxTimeMS(ctx context.Context, rttMin time.Duration
 	}
 
 	remainingTimeout := time.Until(deadline)
+	reserved := rttMin
+	allowance := op.maxTimeAllowance()
+	if allowance > rttMin {
+		reserved = allowance
+	}
 
 	// Always round up to the next millisecond value so we never truncate the calculated
 	// maxTimeMS value (e.g. 400 microseconds evaluates to 1ms, not 0ms).
-	maxTimeMS := int64((remainingTimeout - rttMin + time.Millisecond - 1) / time.Millisecond)
+	maxTimeMS := int64((remainingTimeout - reserved + time.Millisecond - 1) / time.Millisecond)
 	if maxTimeMS <= 0 {
+		if allowance > rttMin {
+			return 0, fmt.Errorf(
+				"remaining time %v until context deadline is less than or equal to the max time allowance %v: %w",
+				remainingTimeout,
+				allowance,
+				ErrDeadlineWouldBeExceeded)
+		}
 		return 0, fmt.Errorf(
 			"remaining time %v until context deadline is less than or equal to min network round-trip time %v (%v): %w",
 			remainingTimeout,
@@ -2057,13 +2265,14 @@ func (op Operation) publishStartedEvent(ctx context.Context, info startedInforma
 
 	if op.canPublishStartedEvent() {
 		started := &event.CommandStartedEvent{
-			Command:            redactStartedInformationCmd(op, info),
+			Command:            op.applyRedactFunc(info.cmdName, info.redacted, redactStartedInformationCmd(op, info)),
 			DatabaseName:       op.Database,
 			CommandName:        info.cmdName,
 			RequestID:          int64(info.requestID),
 			ConnectionID:       info.connID,
 			ServerConnectionID: info.serverConnID,
 			ServiceID:          info.serviceID,
+			Comment:            info.comment,
 		}
 		op.CommandMonitor.Started(ctx, started)
 	}
@@ -2137,19 +2346,30 @@ func (op Operation) publishFinishedEvent(ctx context.Context, info finishedInfor
 	}
 
 	finished := event.CommandFinishedEvent{
-		CommandName:        info.cmdName,
-		DatabaseName:       op.Database,
-		RequestID:          int64(info.requestID),
-		ConnectionID:       info.connID,
-		Duration:           info.duration,
-		ServerConnectionID: info.serverConnID,
-		ServiceID:          info.serviceID,
+		CommandName:           info.cmdName,
+		DatabaseName:          op.Database,
+		RequestID:             int64(info.requestID),
+		ConnectionID:          info.connID,
+		Duration:              info.duration,
+		ServerConnectionID:    info.serverConnID,
+		ServiceID:             info.serviceID,
+		RetryAttempt:          info.retryAttempt,
+		RetryReason:           string(info.retryReason),
+		PreviousServerAddress: info.previousServerAddress,
+		Comment:               info.comment,
+		CollectionName:        info.collection,
 	}
 
 	if info.success() {
 		successEvent := &event.CommandSucceededEvent{
-			Reply:                redactFinishedInformationResponse(info),
-			CommandFinishedEvent: finished,
+			Reply:                   op.applyRedactFunc(info.cmdName, info.redacted, redactFinishedInformationResponse(info)),
+			CommandFinishedEvent:    finished,
+			RequestSize:             info.requestSize.wire,
+			UncompressedRequestSize: info.requestSize.uncompressed,
+			ReplySize:               info.replySize.wire,
+			UncompressedReplySize:   info.replySize.uncompressed,
+			RequestDocuments:        info.requestDocuments,
+			ReplyDocuments:          replyDocumentCount(info.response),
 		}
 		op.CommandMonitor.Succeeded(ctx, successEvent)
 
@@ -2163,6 +2383,17 @@ func (op Operation) publishFinishedEvent(ctx context.Context, info finishedInfor
 	op.CommandMonitor.Failed(ctx, failedEvent)
 }
 
+// replyDocumentCount returns the number of documents in the cursor batch of a reply.
+func replyDocumentCount(reply bsoncore.Document) int {
+	for _, key := range []string{"firstBatch", "nextBatch"} {
+		if batch, ok := reply.Lookup("cursor", key).ArrayOK(); ok {
+			iter := bsoncore.Iterator{List: batch}
+			return iter.Count()
+		}
+	}
+	return 0
+}
+
 // sessionsSupported returns true of the given server version indicates that it supports sessions.
 func sessionsSupported(wireVersion *description.VersionRange) bool {
 	return wireVersion != nil
@@ -2172,3 +2403,30 @@ func sessionsSupported(wireVersion *description.VersionRange) bool {
 func retryWritesSupported(s description.Server) bool {
 	return s.SessionTimeoutMinutes != nil && s.Kind != description.ServerKindStandalone
 }
+
+// commandCollection returns the name of the collection a command runs on, which is the value of
+// the first element of the command for most commands and of the "collection" field for getMore,
+// or an empty string if the command does not run on a collection, such as {aggregate: 1} or
+// {hello: 1}.
+func commandCollection(cmdName string, cmd bsoncore.Document) string {
+	if cmdName == "getMore" {
+		coll, _ := cmd.Lookup("collection").StringValueOK()
+		return coll
+	}
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	coll, _ := elem.Value().StringValueOK()
+	return coll
+}
+
+// commandComment returns a copy of the comment of cmd, which is sent in a wire message that is
+// reused after the command is sent, or the zero value if cmd has no comment.
+func commandComment(cmd bsoncore.Document) bson.RawValue {
+	val, err := cmd.LookupErr("comment")
+	if err != nil {
+		return bson.RawValue{}
+	}
+	return bson.RawValue{Type: bson.Type(val.Type), Value: append([]byte(nil), val.Data...)}
+}
@@ -24,6 +24,7 @@ import (
 type Command struct {
 	authenticator  driver.Authenticator
 	command        bsoncore.Document
+	sequences      []driver.DocumentSequence
 	database       string
 	deployment     driver.Deployment
 	selector       description.ServerSelector
@@ -109,6 +110,8 @@ func (c *Command) Execute(ctx context.Context) error {
 		Timeout:        c.timeout,
 		Logger:         c.logger,
 		Authenticator:  c.authenticator,
+
+		DocumentSequences: c.sequences,
 	}.Execute(ctx)
 }
 
@@ -231,3 +234,13 @@ func (c *Command) Authenticator(authenticator driver.Authenticator) *Command {
 	c.authenticator = authenticator
 	return c
 }
+
+// DocumentSequences sets the OP_MSG document sequences to send after the command document.
+func (c *Command) DocumentSequences(sequences []driver.DocumentSequence) *Command {
+	if c == nil {
+		c = new(Command)
+	}
+
+	c.sequences = sequences
+	return c
+}
@@ -0,0 +1,100 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package operation
+
+import (
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// maxCommandCacheEntries bounds the number of encoded commands held by a CommandCache so that
+// operations with many distinct options, such as a unique comment per call, cannot grow it without
+// limit. Commands are no longer cached once the limit is reached.
+const maxCommandCacheEntries = 128
+
+// CommandCache holds the encoded command fields of write operations, such as the "insert",
+// "ordered", and "comment" elements of an insert command, so that repeated operations on the same
+// namespace with the same options append the cached bytes instead of encoding the fields again. The
+// documents are sent as a separate document sequence and are never cached. A CommandCache is safe
+// for concurrent use.
+type CommandCache struct {
+	mu   sync.RWMutex
+	cmds map[commandCacheKey][]byte
+}
+
+// commandCacheKey identifies the encoded command fields of an operation. It contains every input
+// used to build the fields, so two operations with equal keys produce identical bytes.
+type commandCacheKey struct {
+	command                  string
+	collection               string
+	bypassDocumentValidation optionalBool
+	ordered                  optionalBool
+	comment                  string
+	let                      string
+}
+
+// optionalBool is a comparable representation of a *bool.
+type optionalBool uint8
+
+const (
+	boolUnset optionalBool = iota
+	boolFalse
+	boolTrue
+)
+
+func newOptionalBool(b *bool) optionalBool {
+	switch {
+	case b == nil:
+		return boolUnset
+	case *b:
+		return boolTrue
+	default:
+		return boolFalse
+	}
+}
+
+// NewCommandCache creates an empty CommandCache.
+func NewCommandCache() *CommandCache {
+	return &CommandCache{cmds: make(map[commandCacheKey][]byte)}
+}
+
+// appendCommand appends the cached command fields for key to dst. If no fields are cached for key,
+// build is called to append them and the result is cached.
+func (cc *CommandCache) appendCommand(
+	dst []byte,
+	key commandCacheKey,
+	build func([]byte) ([]byte, error),
+) ([]byte, error) {
+	cc.mu.RLock()
+	cmd, ok := cc.cmds[key]
+	cc.mu.RUnlock()
+	if ok {
+		return append(dst, cmd...), nil
+	}
+
+	start := len(dst)
+	dst, err := build(dst)
+	if err != nil {
+		return dst, err
+	}
+
+	cc.mu.Lock()
+	if len(cc.cmds) < maxCommandCacheEntries {
+		cc.cmds[key] = append([]byte(nil), dst[start:]...)
+	}
+	cc.mu.Unlock()
+	return dst, nil
+}
+
+// valueKey returns the bytes of a BSON value in a form suitable for a commandCacheKey.
+func valueKey(val bsoncore.Value) string {
+	if val.Type == bsoncore.Type(0) {
+		return ""
+	}
+	return string(append([]byte{byte(val.Type)}, val.Data...))
+}
@@ -0,0 +1,84 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package operation
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+func TestCommandCache(t *testing.T) {
+	desc := description.SelectedServer{
+		Server: description.Server{WireVersion: &description.VersionRange{Min: 0, Max: 21}},
+	}
+	comment := bsoncore.Value{Type: bsoncore.TypeString, Data: bsoncore.AppendString(nil, "trace")}
+
+	command := func(t *testing.T, cache *CommandCache, op *Insert) []byte {
+		t.Helper()
+
+		op.CommandCache(cache)
+		cmd, err := op.command([]byte{0xFF}, desc)
+		require.NoError(t, err, "command error: %v", err)
+		return cmd
+	}
+
+	t.Run("matches uncached command", func(t *testing.T) {
+		cache := NewCommandCache()
+		newOp := func() *Insert {
+			return NewInsert().Collection("coll").Ordered(false).BypassDocumentValidation(true).Comment(comment)
+		}
+
+		want := command(t, nil, newOp())
+		assert.Equal(t, want, command(t, cache, newOp()), "expected first cached command to match")
+		assert.Equal(t, want, command(t, cache, newOp()), "expected reused command to match")
+		assert.Equal(t, 1, len(cache.cmds), "expected one cached command, got %d", len(cache.cmds))
+	})
+	t.Run("distinct options", func(t *testing.T) {
+		cache := NewCommandCache()
+		ordered := command(t, cache, NewInsert().Collection("coll").Ordered(true))
+		unordered := command(t, cache, NewInsert().Collection("coll").Ordered(false))
+		other := command(t, cache, NewInsert().Collection("other").Ordered(true))
+		update := NewUpdate().Collection("coll").Ordered(true).CommandCache(cache)
+		_, err := update.command(nil, desc)
+		require.NoError(t, err, "command error: %v", err)
+
+		assert.NotEqual(t, ordered, unordered, "expected ordered to change the command")
+		assert.NotEqual(t, ordered, other, "expected collection to change the command")
+		assert.Equal(t, 4, len(cache.cmds), "expected four cached commands, got %d", len(cache.cmds))
+	})
+	t.Run("bounded", func(t *testing.T) {
+		cache := NewCommandCache()
+		for i := 0; i < maxCommandCacheEntries+10; i++ {
+			c := bsoncore.Value{Type: bsoncore.TypeInt32, Data: bsoncore.AppendInt32(nil, int32(i))}
+			command(t, cache, NewInsert().Collection("coll").Comment(c))
+		}
+		assert.Equal(t, maxCommandCacheEntries, len(cache.cmds), "expected cache size to be bounded")
+	})
+	t.Run("update validation is not cached", func(t *testing.T) {
+		cache := NewCommandCache()
+		newOp := func() *Update {
+			return NewUpdate().Collection("coll").ArrayFilters(true).CommandCache(cache)
+		}
+
+		_, err := newOp().command(nil, desc)
+		require.NoError(t, err, "command error: %v", err)
+
+		oldDesc := description.SelectedServer{
+			Server: description.Server{WireVersion: &description.VersionRange{Min: 0, Max: 5}},
+		}
+		_, err = newOp().command(nil, oldDesc)
+		assert.ErrorContains(t, err, "arrayFilters", "expected arrayFilters wire version error")
+
+		oldDesc.WireVersion.Max = 7
+		_, err = NewUpdate().Collection("coll").Pipeline(true).CommandCache(cache).command(nil, oldDesc)
+		assert.ErrorContains(t, err, "pipeline-style updates", "expected pipeline wire version error")
+	})
+}
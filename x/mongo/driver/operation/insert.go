@@ -27,6 +27,7 @@ type Insert struct {
 	authenticator            driver.Authenticator
 	bypassDocumentValidation *bool
 	comment                  bsoncore.Value
+	commandCache             *CommandCache
 	documents                []bsoncore.Document
 	ordered                  *bool
 	session                  *session.Client
@@ -120,11 +121,30 @@ func (i *Insert) Execute(ctx context.Context) error {
 }
 
 func (i *Insert) command(dst []byte, desc description.SelectedServer) ([]byte, error) {
-	dst = bsoncore.AppendStringElement(dst, "insert", i.collection)
-	if i.bypassDocumentValidation != nil && (desc.WireVersion != nil &&
-		driverutil.VersionRangeIncludes(*desc.WireVersion, 4)) {
+	bypassDocumentValidation := i.bypassDocumentValidation
+	if desc.WireVersion == nil || !driverutil.VersionRangeIncludes(*desc.WireVersion, 4) {
+		bypassDocumentValidation = nil
+	}
+	if i.commandCache == nil {
+		return i.appendCommand(dst, bypassDocumentValidation)
+	}
 
-		dst = bsoncore.AppendBooleanElement(dst, "bypassDocumentValidation", *i.bypassDocumentValidation)
+	key := commandCacheKey{
+		command:                  "insert",
+		collection:               i.collection,
+		bypassDocumentValidation: newOptionalBool(bypassDocumentValidation),
+		ordered:                  newOptionalBool(i.ordered),
+		comment:                  valueKey(i.comment),
+	}
+	return i.commandCache.appendCommand(dst, key, func(dst []byte) ([]byte, error) {
+		return i.appendCommand(dst, bypassDocumentValidation)
+	})
+}
+
+func (i *Insert) appendCommand(dst []byte, bypassDocumentValidation *bool) ([]byte, error) {
+	dst = bsoncore.AppendStringElement(dst, "insert", i.collection)
+	if bypassDocumentValidation != nil {
+		dst = bsoncore.AppendBooleanElement(dst, "bypassDocumentValidation", *bypassDocumentValidation)
 	}
 	if i.comment.Type != bsoncore.Type(0) {
 		dst = bsoncore.AppendValueElement(dst, "comment", i.comment)
@@ -156,6 +176,17 @@ func (i *Insert) Comment(comment bsoncore.Value) *Insert {
 	return i
 }
 
+// CommandCache sets the cache used to reuse the encoded command fields across operations on the
+// same collection with the same options.
+func (i *Insert) CommandCache(cache *CommandCache) *Insert {
+	if i == nil {
+		i = new(Insert)
+	}
+
+	i.commandCache = cache
+	return i
+}
+
 // Documents adds documents to this operation that will be inserted when this operation is
 // executed.
 func (i *Insert) Documents(documents ...bsoncore.Document) *Insert {
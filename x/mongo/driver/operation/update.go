@@ -28,6 +28,7 @@ type Update struct {
 	authenticator            driver.Authenticator
 	bypassDocumentValidation *bool
 	comment                  bsoncore.Value
+	commandCache             *CommandCache
 	ordered                  *bool
 	updates                  []bsoncore.Document
 	session                  *session.Client
@@ -38,6 +39,7 @@ type Update struct {
 	deployment               driver.Deployment
 	hint                     *bool
 	arrayFilters             *bool
+	pipeline                 *bool
 	selector                 description.ServerSelector
 	writeConcern             *writeconcern.WriteConcern
 	retry                    *driver.RetryMode
@@ -173,19 +175,6 @@ func (u *Update) Execute(ctx context.Context) error {
 }
 
 func (u *Update) command(dst []byte, desc description.SelectedServer) ([]byte, error) {
-	dst = bsoncore.AppendStringElement(dst, "update", u.collection)
-	if u.bypassDocumentValidation != nil &&
-		(desc.WireVersion != nil && driverutil.VersionRangeIncludes(*desc.WireVersion, 4)) {
-
-		dst = bsoncore.AppendBooleanElement(dst, "bypassDocumentValidation", *u.bypassDocumentValidation)
-	}
-	if u.comment.Type != bsoncore.Type(0) {
-		dst = bsoncore.AppendValueElement(dst, "comment", u.comment)
-	}
-	if u.ordered != nil {
-
-		dst = bsoncore.AppendBooleanElement(dst, "ordered", *u.ordered)
-	}
 	if u.hint != nil && *u.hint {
 
 		if desc.WireVersion == nil || !driverutil.VersionRangeIncludes(*desc.WireVersion, 5) {
@@ -200,11 +189,50 @@ func (u *Update) command(dst []byte, desc description.SelectedServer) ([]byte, e
 			return nil, errors.New("the 'arrayFilters' command parameter requires a minimum server wire version of 6")
 		}
 	}
+
+	if u.pipeline != nil && *u.pipeline {
+		if desc.WireVersion == nil || !driverutil.VersionRangeIncludes(*desc.WireVersion, 8) {
+			return nil, errors.New("pipeline-style updates require a minimum server wire version of 8")
+		}
+	}
+
+	bypassDocumentValidation := u.bypassDocumentValidation
+	if desc.WireVersion == nil || !driverutil.VersionRangeIncludes(*desc.WireVersion, 4) {
+		bypassDocumentValidation = nil
+	}
+	if u.commandCache == nil {
+		return u.appendCommand(dst, bypassDocumentValidation), nil
+	}
+
+	key := commandCacheKey{
+		command:                  "update",
+		collection:               u.collection,
+		bypassDocumentValidation: newOptionalBool(bypassDocumentValidation),
+		ordered:                  newOptionalBool(u.ordered),
+		comment:                  valueKey(u.comment),
+		let:                      string(u.let),
+	}
+	return u.commandCache.appendCommand(dst, key, func(dst []byte) ([]byte, error) {
+		return u.appendCommand(dst, bypassDocumentValidation), nil
+	})
+}
+
+func (u *Update) appendCommand(dst []byte, bypassDocumentValidation *bool) []byte {
+	dst = bsoncore.AppendStringElement(dst, "update", u.collection)
+	if bypassDocumentValidation != nil {
+		dst = bsoncore.AppendBooleanElement(dst, "bypassDocumentValidation", *bypassDocumentValidation)
+	}
+	if u.comment.Type != bsoncore.Type(0) {
+		dst = bsoncore.AppendValueElement(dst, "comment", u.comment)
+	}
+	if u.ordered != nil {
+
+		dst = bsoncore.AppendBooleanElement(dst, "ordered", *u.ordered)
+	}
 	if u.let != nil {
 		dst = bsoncore.AppendDocumentElement(dst, "let", u.let)
 	}
-
-	return dst, nil
+	return dst
 }
 
 // BypassDocumentValidation allows the operation to opt-out of document level validation. Valid
@@ -241,6 +269,17 @@ func (u *Update) ArrayFilters(arrayFilters bool) *Update {
 	return u
 }
 
+// Pipeline is a flag to indicate that an update document contains an aggregation pipeline update. This option is only
+// supported on server versions 4.2 and higher. For servers < 4.2, the driver will return an error.
+func (u *Update) Pipeline(pipeline bool) *Update {
+	if u == nil {
+		u = new(Update)
+	}
+
+	u.pipeline = &pipeline
+	return u
+}
+
 // Ordered sets ordered. If true, when a write fails, the operation will return the error, when
 // false write failures do not stop execution of the operation.
 func (u *Update) Ordered(ordered bool) *Update {
@@ -304,6 +343,17 @@ func (u *Update) CommandMonitor(monitor *event.CommandMonitor) *Update {
 	return u
 }
 
+// CommandCache sets the cache used to reuse the encoded command fields across operations on the
+// same collection with the same options.
+func (u *Update) CommandCache(cache *CommandCache) *Update {
+	if u == nil {
+		u = new(Update)
+	}
+
+	u.commandCache = cache
+	return u
+}
+
 // Comment sets a value to help trace an operation.
 func (u *Update) Comment(comment bsoncore.Value) *Update {
 	if u == nil {
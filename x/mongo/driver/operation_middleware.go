@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+// OperationInfo describes an operation passed to an OperationMiddleware.
+type OperationInfo struct {
+	// CommandName is the name of the operation, such as "find" or "insert".
+	CommandName string
+
+	// Database is the database the operation runs against.
+	Database string
+
+	// Type is the type of the operation, Read or Write.
+	Type Type
+
+	// Comment, if set before the next handler is called, replaces the comment of the commands of
+	// the operation.
+	Comment bsoncore.Value
+}
+
+// OperationHandler runs an operation, including its retries.
+type OperationHandler func(ctx context.Context, info *OperationInfo) error
+
+// OperationMiddleware wraps the handler that runs operations, e.g. to record their duration and
+// errors or to change their comments.
+type OperationMiddleware func(next OperationHandler) OperationHandler
+
+// OperationMiddlewareDeployment is implemented by Deployments that wrap the operations executed
+// against them with an OperationMiddleware.
+type OperationMiddlewareDeployment interface {
+	OperationMiddleware() OperationMiddleware
+}
+
+// operationMiddleware returns the OperationMiddleware of the operation's Deployment, if any.
+func (op Operation) operationMiddleware() OperationMiddleware {
+	if d, ok := op.Deployment.(OperationMiddlewareDeployment); ok {
+		return d.OperationMiddleware()
+	}
+	return nil
+}
+
+// executeWithMiddleware runs the operation through the middleware mw.
+func (op Operation) executeWithMiddleware(ctx context.Context, mw OperationMiddleware) error {
+	info := &OperationInfo{
+		CommandName: op.Name,
+		Database:    op.Database,
+		Type:        op.Type,
+	}
+	return mw(func(ctx context.Context, info *OperationInfo) error {
+		if info.Comment.Type != 0 {
+			op.CommandFn = replaceComment(op.CommandFn, info.Comment)
+		}
+		return op.executeWithRetryInfo(ctx)
+	})(ctx, info)
+}
+
+// replaceComment returns a CommandFn that replaces the comment of the command created by fn with
+// comment.
+func replaceComment(
+	fn func(dst []byte, desc description.SelectedServer) ([]byte, error),
+	comment bsoncore.Value,
+) func(dst []byte, desc description.SelectedServer) ([]byte, error) {
+	return func(dst []byte, desc description.SelectedServer) ([]byte, error) {
+		start := len(dst)
+		dst, err := fn(dst, desc)
+		if err != nil {
+			return dst, err
+		}
+
+		elems := append([]byte(nil), dst[start:]...)
+		dst = dst[:start]
+		for len(elems) > 0 {
+			elem, rem, ok := bsoncore.ReadElement(elems)
+			if !ok {
+				dst = append(dst, elems...)
+				break
+			}
+			if elem.Key() != "comment" {
+				dst = append(dst, elem...)
+			}
+			elems = rem
+		}
+		return bsoncore.AppendValueElement(dst, "comment", comment), nil
+	}
+}
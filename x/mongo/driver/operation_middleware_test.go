@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+)
+
+// middlewareDeployment is a mockDeployment with an OperationMiddleware.
+type middlewareDeployment struct {
+	mockDeployment
+	middleware OperationMiddleware
+}
+
+func (d *middlewareDeployment) OperationMiddleware() OperationMiddleware { return d.middleware }
+
+func TestOperationMiddleware(t *testing.T) {
+	ok := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+		bsoncore.AppendInt32Element(nil, "ok", 1),
+	), false)
+	tag := bsoncore.Value{Type: bsoncore.TypeString, Data: bsoncore.AppendString(nil, "tenant=a")}
+
+	var started []bsoncore.Document
+	execute := func(mw OperationMiddleware) error {
+		d := &middlewareDeployment{middleware: mw}
+		d.returns.server = &sequenceServer{conns: []*mnet.Connection{mnet.NewConnection(&mockConnection{
+			rDesc: description.Server{
+				Kind:        description.ServerKindRSPrimary,
+				WireVersion: &description.VersionRange{Max: 21},
+			},
+			rReadWM: ok,
+		})}}
+
+		return Operation{
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				dst = bsoncore.AppendStringElement(dst, "insert", "coll")
+				dst = bsoncore.AppendStringElement(dst, "comment", "original")
+				return bsoncore.AppendBooleanElement(dst, "ordered", true), nil
+			},
+			Name:       "insert",
+			Deployment: d,
+			Database:   "testing",
+			Type:       Write,
+			CommandMonitor: &event.CommandMonitor{
+				Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+					started = append(started, bsoncore.Document(evt.Command))
+				},
+			},
+		}.Execute(context.Background())
+	}
+
+	t.Run("wraps operation", func(t *testing.T) {
+		started = nil
+		var info OperationInfo
+		err := execute(func(next OperationHandler) OperationHandler {
+			return func(ctx context.Context, op *OperationInfo) error {
+				info = *op
+				op.Comment = tag
+				return next(ctx, op)
+			}
+		})
+		require.NoError(t, err, "Execute error: %v", err)
+
+		assert.Equal(t, "insert", info.CommandName, "command name mismatch")
+		assert.Equal(t, "testing", info.Database, "database mismatch")
+		assert.Equal(t, Write, info.Type, "type mismatch")
+
+		require.Len(t, started, 1, "expected one command")
+		assert.Equal(t, "tenant=a", started[0].Lookup("comment").StringValue(), "expected the comment to be replaced")
+		elems, err := started[0].Elements()
+		require.NoError(t, err, "Elements error: %v", err)
+		var comments int
+		for _, elem := range elems {
+			if elem.Key() == "comment" {
+				comments++
+			}
+		}
+		assert.Equal(t, 1, comments, "expected a single comment")
+		assert.True(t, started[0].Lookup("ordered").Boolean(), "expected the other fields to be kept")
+	})
+	t.Run("short-circuits", func(t *testing.T) {
+		started = nil
+		errRejected := errors.New("rejected")
+		err := execute(func(OperationHandler) OperationHandler {
+			return func(context.Context, *OperationInfo) error { return errRejected }
+		})
+		assert.ErrorIs(t, err, errRejected, "expected the middleware error")
+		assert.Len(t, started, 0, "expected no command to be sent")
+	})
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/csot"
 	"go.mongodb.org/mongo-driver/v2/internal/handshake"
@@ -305,6 +306,30 @@ func TestOperation(t *testing.T) {
 				want:     1,
 				err:      nil,
 			},
+			{
+				name:   "uses max time allowance larger than rtt",
+				op:     Operation{Deployment: &allowanceDeployment{allowance: time.Second}},
+				ctx:    timeoutCtx,
+				rttMin: shortRTT,
+				want:   4000,
+				err:    nil,
+			},
+			{
+				name:   "uses rtt larger than max time allowance",
+				op:     Operation{Deployment: &allowanceDeployment{allowance: time.Millisecond}},
+				ctx:    timeoutCtx,
+				rttMin: shortRTT,
+				want:   4950,
+				err:    nil,
+			},
+			{
+				name:   "max time allowance exceeds remaining time",
+				op:     Operation{Deployment: &allowanceDeployment{allowance: longRTT}},
+				ctx:    timeoutCtx,
+				rttMin: shortRTT,
+				want:   0,
+				err:    ErrDeadlineWouldBeExceeded,
+			},
 		}
 		for _, tc := range testCases {
 			// Capture test-case for parallel sub-test.
@@ -536,6 +561,47 @@ func TestOperation(t *testing.T) {
 			}
 		})
 	})
+	t.Run("CommandMonitor Redact", func(t *testing.T) {
+		cmd := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "insert", "coll"),
+			bsoncore.AppendStringElement(nil, "secret", "value"),
+		)
+		reply := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+			bsoncore.AppendStringElement(nil, "secret", "value"),
+		)
+
+		var started *event.CommandStartedEvent
+		var succeeded *event.CommandSucceededEvent
+		var redactedNames []string
+		op := Operation{
+			Database: "db",
+			CommandMonitor: &event.CommandMonitor{
+				Started:   func(_ context.Context, evt *event.CommandStartedEvent) { started = evt },
+				Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) { succeeded = evt },
+				Redact: func(name string, doc bson.Raw) bson.Raw {
+					redactedNames = append(redactedNames, name)
+					return event.DenyFields("secret")(name, doc)
+				},
+			},
+		}
+
+		op.publishStartedEvent(context.Background(), startedInformation{cmd: cmd, cmdName: "insert"})
+		op.publishFinishedEvent(context.Background(), finishedInformation{cmdName: "insert", response: reply})
+		assert.Equal(t, bson.Raw(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "insert", "coll"))), started.Command,
+			"expected secret field to be removed from command")
+		assert.Equal(t, bson.Raw(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1))), succeeded.Reply,
+			"expected secret field to be removed from reply")
+		assert.Equal(t, []string{"insert", "insert"}, redactedNames, "expected Redact to be called for both events")
+
+		// Security-sensitive commands are redacted by the driver without calling Redact.
+		redactedNames = nil
+		op.publishStartedEvent(context.Background(), startedInformation{cmd: cmd, cmdName: "saslStart", redacted: true})
+		assert.Len(t, started.Command, 0, "expected sensitive command to be redacted")
+		assert.Len(t, redactedNames, 0, "expected Redact not to be called for sensitive commands")
+	})
 	t.Run("ExecuteExhaust", func(t *testing.T) {
 		t.Run("errors if connection is not streaming", func(t *testing.T) {
 			conn := mnet.NewConnection(&mockConnection{
@@ -600,6 +666,113 @@ func TestOperation(t *testing.T) {
 		assert.Nil(t, err, "ExecuteExhaust error: %v", err)
 		assert.True(t, conn.CurrentlyStreaming(), "expected CurrentlyStreaming to be true")
 	})
+	t.Run("CommandSucceededEvent sizes", func(t *testing.T) {
+		doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "x", 1))
+		reply := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendDocumentElement(nil, "cursor", bsoncore.BuildDocumentFromElements(nil,
+				bsoncore.AppendArrayElement(nil, "firstBatch", bsoncore.BuildArray(nil,
+					bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: doc},
+					bsoncore.Value{Type: bsoncore.TypeEmbeddedDocument, Data: doc},
+				)),
+			)),
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+		)
+		conn := &mockConnection{
+			rDesc: description.Server{
+				WireVersion:     &description.VersionRange{Max: 21},
+				MaxBatchCount:   100,
+				MaxDocumentSize: 1024,
+				MaxMessageSize:  4096,
+			},
+			rReadWM: createExhaustServerResponse(reply, false),
+		}
+
+		var succeeded *event.CommandSucceededEvent
+		op := Operation{
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				return bsoncore.AppendStringElement(dst, "insert", "coll"), nil
+			},
+			Database:   "db",
+			Deployment: SingleConnectionDeployment{C: mnet.NewConnection(conn)},
+			Batches: &Batches{
+				Identifier: "documents",
+				Documents:  []bsoncore.Document{doc, doc, doc},
+			},
+			CommandMonitor: &event.CommandMonitor{
+				Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) { succeeded = evt },
+			},
+		}
+		err := op.Execute(context.Background())
+		require.NoError(t, err, "Execute error: %v", err)
+		require.NotNil(t, succeeded, "expected CommandSucceededEvent to be published")
+
+		assert.Equal(t, len(conn.pWriteWM), succeeded.RequestSize, "expected request size to match")
+		assert.Equal(t, len(conn.pWriteWM), succeeded.UncompressedRequestSize, "expected uncompressed request size to match")
+		assert.Equal(t, len(conn.rReadWM), succeeded.ReplySize, "expected reply size to match")
+		assert.Equal(t, len(conn.rReadWM), succeeded.UncompressedReplySize, "expected uncompressed reply size to match")
+		assert.Equal(t, 3, succeeded.RequestDocuments, "expected request document count to match")
+		assert.Equal(t, 2, succeeded.ReplyDocuments, "expected reply document count to match")
+		assert.Equal(t, 1.0, succeeded.RequestCompressionRatio(), "expected no request compression")
+		assert.Equal(t, 1.0, succeeded.ReplyCompressionRatio(), "expected no reply compression")
+	})
+	t.Run("WithoutCompression", func(t *testing.T) {
+		reply := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "ok", 1))
+		for _, disabled := range []bool{false, true} {
+			conn := &compressingConnection{mockConnection: &mockConnection{
+				rDesc:   description.Server{WireVersion: &description.VersionRange{Max: 21}},
+				rReadWM: createExhaustServerResponse(reply, false),
+			}}
+			op := Operation{
+				CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+					return bsoncore.AppendInt32Element(dst, "ping", 1), nil
+				},
+				Database:   "admin",
+				Deployment: SingleConnectionDeployment{C: mnet.NewConnection(conn)},
+			}
+
+			ctx := context.Background()
+			if disabled {
+				ctx = WithoutCompression(ctx)
+			}
+			err := op.Execute(ctx)
+			require.NoError(t, err, "Execute error: %v", err)
+
+			want := 1
+			if disabled {
+				want = 0
+			}
+			assert.Equal(t, want, conn.compressed, "compressed message count mismatch with compression disabled=%v", disabled)
+		}
+	})
+	t.Run("command events include comment", func(t *testing.T) {
+		reply := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "ok", 1))
+		conn := &mockConnection{
+			rDesc:   description.Server{WireVersion: &description.VersionRange{Max: 21}},
+			rReadWM: createExhaustServerResponse(reply, false),
+		}
+
+		var started *event.CommandStartedEvent
+		var succeeded *event.CommandSucceededEvent
+		op := Operation{
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				dst = bsoncore.AppendInt32Element(dst, "ping", 1)
+				return bsoncore.AppendStringElement(dst, "comment", "trace-42"), nil
+			},
+			Database:   "db",
+			Deployment: SingleConnectionDeployment{C: mnet.NewConnection(conn)},
+			CommandMonitor: &event.CommandMonitor{
+				Started:   func(_ context.Context, evt *event.CommandStartedEvent) { started = evt },
+				Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) { succeeded = evt },
+			},
+		}
+		err := op.Execute(context.Background())
+		require.NoError(t, err, "Execute error: %v", err)
+		require.NotNil(t, started, "expected CommandStartedEvent to be published")
+		require.NotNil(t, succeeded, "expected CommandSucceededEvent to be published")
+
+		assert.Equal(t, "trace-42", started.Comment.StringValue(), "expected started event comment to match")
+		assert.Equal(t, "trace-42", succeeded.Comment.StringValue(), "expected succeeded event comment to match")
+	})
 	t.Run("context deadline exceeded not marked as TransientTransactionError", func(t *testing.T) {
 		conn := mnet.NewConnection(&mockConnection{})
 
@@ -757,6 +930,18 @@ func (mrm mockRTTMonitor) EWMA() time.Duration { return mrm.ewma }
 func (mrm mockRTTMonitor) Min() time.Duration  { return mrm.min }
 func (mrm mockRTTMonitor) Stats() string       { return mrm.stats }
 
+// compressingConnection is a mockConnection that implements mnet.Compressor and counts the wire
+// messages it compresses. It does not change the messages.
+type compressingConnection struct {
+	*mockConnection
+	compressed int
+}
+
+func (c *compressingConnection) CompressWireMessage(src, dst []byte) ([]byte, error) {
+	c.compressed++
+	return append(dst, src...), nil
+}
+
 type mockConnection struct {
 	// parameters
 	pWriteWM []byte
@@ -828,7 +1013,283 @@ func (ms *mockRetryServer) RTTMonitor() RTTMonitor {
 	return &csot.ZeroRTTMonitor{}
 }
 
+// sequenceServer is a Server that returns its connections in order.
+type sequenceServer struct {
+	conns []*mnet.Connection
+}
+
+func (ss *sequenceServer) Connection(context.Context) (*mnet.Connection, error) {
+	conn := ss.conns[0]
+	ss.conns = ss.conns[1:]
+	return conn, nil
+}
+
+func (ss *sequenceServer) RTTMonitor() RTTMonitor {
+	return &csot.ZeroRTTMonitor{}
+}
+
+// replySequenceConnection is a mockConnection that reads the replies in order.
+type replySequenceConnection struct {
+	*mockConnection
+	replies [][]byte
+}
+
+func (c *replySequenceConnection) Read(context.Context) ([]byte, error) {
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
+// allowanceDeployment is a mockDeployment with a MaxTimeAllowance.
+type allowanceDeployment struct {
+	mockDeployment
+	allowance time.Duration
+}
+
+func (d *allowanceDeployment) MaxTimeAllowance() time.Duration { return d.allowance }
+
+// retryPolicyDeployment is a mockDeployment with a RetryPolicy.
+type retryPolicyDeployment struct {
+	mockDeployment
+	policy *RetryPolicy
+}
+
+func (d *retryPolicyDeployment) RetryPolicy() *RetryPolicy { return d.policy }
+
 func TestRetry(t *testing.T) {
+	t.Run("records retry attempts", func(t *testing.T) {
+		notPrimary := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 0),
+			bsoncore.AppendInt32Element(nil, "code", 10107),
+			bsoncore.AppendStringElement(nil, "errmsg", "not primary"),
+		), false)
+		ok := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+		), false)
+		newConn := func(addr address.Address, reply []byte) *mnet.Connection {
+			return mnet.NewConnection(&mockConnection{
+				rDesc: description.Server{
+					Addr:        addr,
+					Kind:        description.ServerKindRSPrimary,
+					WireVersion: &description.VersionRange{Max: 21},
+				},
+				rReadWM: reply,
+			})
+		}
+
+		var events []*event.CommandFailedEvent
+		var succeeded *event.CommandSucceededEvent
+		execute := func(conns ...*mnet.Connection) error {
+			d := new(mockDeployment)
+			d.returns.server = &sequenceServer{conns: conns}
+
+			retry := RetryOnce
+			return Operation{
+				CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+					return bsoncore.AppendInt32Element(dst, "find", 1), nil
+				},
+				Deployment: d,
+				Database:   "testing",
+				RetryMode:  &retry,
+				Type:       Read,
+				CommandMonitor: &event.CommandMonitor{
+					Failed:    func(_ context.Context, evt *event.CommandFailedEvent) { events = append(events, evt) },
+					Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) { succeeded = evt },
+				},
+			}.Execute(context.Background())
+		}
+
+		err := execute(newConn("a:27017", notPrimary), newConn("b:27017", ok))
+		require.NoError(t, err, "Execute error: %v", err)
+		require.Len(t, events, 1, "expected one CommandFailedEvent")
+		assert.Equal(t, 0, events[0].RetryAttempt, "expected first attempt")
+		assert.Equal(t, "", events[0].RetryReason, "expected no retry reason for the first attempt")
+		assert.Equal(t, 1, succeeded.RetryAttempt, "expected retry attempt")
+		assert.Equal(t, string(RetryReasonNotWritablePrimary), succeeded.RetryReason, "expected retry reason")
+		assert.Equal(t, address.Address("a:27017"), succeeded.PreviousServerAddress, "expected previous server")
+
+		err = execute(newConn("a:27017", notPrimary), newConn("b:27017", notPrimary))
+		var ri *RetryInfo
+		require.True(t, errors.As(err, &ri), "expected error to contain RetryInfo, got %v", err)
+		assert.Equal(t, 2, ri.Attempts, "expected two attempts")
+		assert.Equal(t, RetryReasonNotWritablePrimary, ri.Reason, "expected retry reason")
+		assert.Equal(t, []address.Address{"a:27017", "b:27017"}, ri.Servers, "expected both servers")
+
+		var de Error
+		require.True(t, errors.As(err, &de), "expected Error, got %T", err)
+		assert.Equal(t, int32(10107), de.Code, "expected error from the last attempt")
+	})
+	t.Run("RetryPolicy", func(t *testing.T) {
+		notPrimary := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 0),
+			bsoncore.AppendInt32Element(nil, "code", 10107),
+			bsoncore.AppendStringElement(nil, "errmsg", "not primary"),
+		), false)
+		ok := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+		), false)
+		newConn := func(reply []byte) *mnet.Connection {
+			return mnet.NewConnection(&mockConnection{
+				rDesc: description.Server{
+					Kind:        description.ServerKindRSPrimary,
+					WireVersion: &description.VersionRange{Max: 21},
+				},
+				rReadWM: reply,
+			})
+		}
+		execute := func(policy *RetryPolicy, conns ...*mnet.Connection) error {
+			d := &retryPolicyDeployment{policy: policy}
+			d.returns.server = &sequenceServer{conns: conns}
+
+			retry := RetryOnce
+			return Operation{
+				CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+					return bsoncore.AppendInt32Element(dst, "find", 1), nil
+				},
+				Name:       "find",
+				Deployment: d,
+				Database:   "testing",
+				RetryMode:  &retry,
+				Type:       Read,
+			}.Execute(context.Background())
+		}
+
+		t.Run("MaxAttempts", func(t *testing.T) {
+			policy := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+			err := execute(policy, newConn(notPrimary), newConn(notPrimary), newConn(ok))
+			assert.NoError(t, err, "Execute error: %v", err)
+
+			err = execute(policy, newConn(notPrimary), newConn(notPrimary), newConn(notPrimary))
+			var ri *RetryInfo
+			require.True(t, errors.As(err, &ri), "expected error to contain RetryInfo, got %v", err)
+			assert.Equal(t, 3, ri.Attempts, "expected three attempts")
+		})
+		t.Run("Retryable", func(t *testing.T) {
+			var attempts []RetryAttempt
+			policy := &RetryPolicy{
+				MaxAttempts: 3,
+				Retryable: func(a RetryAttempt) bool {
+					attempts = append(attempts, a)
+					return false
+				},
+			}
+			err := execute(policy, newConn(notPrimary), newConn(ok))
+			var de Error
+			require.True(t, errors.As(err, &de), "expected Error, got %v", err)
+			assert.Equal(t, int32(10107), de.Code, "expected error from the first attempt")
+			require.Len(t, attempts, 1, "expected Retryable to be called once")
+			assert.Equal(t, "find", attempts[0].CommandName, "expected command name")
+			assert.Equal(t, Read, attempts[0].Type, "expected operation type")
+			assert.Equal(t, 1, attempts[0].Attempt, "expected first attempt")
+			assert.True(t, attempts[0].Retryable, "expected error to be retryable by default")
+		})
+	})
+	t.Run("batches", func(t *testing.T) {
+		sessionTimeout := int64(30)
+		notPrimary := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 0),
+			bsoncore.AppendInt32Element(nil, "code", 10107),
+			bsoncore.AppendStringElement(nil, "errmsg", "not primary"),
+			bsoncore.BuildArrayElement(nil, "errorLabels", bsoncore.Value{Type: bsoncore.TypeString, Data: bsoncore.AppendString(nil, RetryableWriteError)}),
+		), false)
+		ok := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+			bsoncore.AppendInt32Element(nil, "n", 1),
+		), false)
+		newConn := func(addr address.Address, replies ...[]byte) *mnet.Connection {
+			return mnet.NewConnection(&replySequenceConnection{
+				mockConnection: &mockConnection{
+					rDesc: description.Server{
+						Addr:                  addr,
+						Kind:                  description.ServerKindRSPrimary,
+						WireVersion:           &description.VersionRange{Max: 21},
+						SessionTimeoutMinutes: &sessionTimeout,
+						MaxBatchCount:         1,
+						MaxDocumentSize:       1024,
+						MaxMessageSize:        4096,
+					},
+				},
+				replies: replies,
+			})
+		}
+
+		type finished struct {
+			attempt  int
+			reason   string
+			previous address.Address
+		}
+		// execute inserts two documents, one per batch, and returns the retry metadata of the
+		// finished events.
+		execute := func(t *testing.T, policy *RetryPolicy, conns ...*mnet.Connection) []finished {
+			t.Helper()
+
+			d := &retryPolicyDeployment{policy: policy}
+			d.returns.server = &sequenceServer{conns: conns}
+
+			id, err := uuid.New()
+			require.NoError(t, err, "uuid.New error: %v", err)
+			sess, err := session.NewClientSession(session.NewPool(nil), id)
+			require.NoError(t, err, "NewClientSession error: %v", err)
+
+			var events []finished
+			doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "_id", 1))
+			retry := RetryOncePerCommand
+			err = Operation{
+				CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+					return bsoncore.AppendStringElement(dst, "insert", "coll"), nil
+				},
+				Name:       "insert",
+				Database:   "db",
+				Deployment: d,
+				Client:     sess,
+				Clock:      &session.ClusterClock{},
+				Batches: &Batches{
+					Identifier: "documents",
+					Documents:  []bsoncore.Document{doc, doc},
+				},
+				RetryMode: &retry,
+				Type:      Write,
+				CommandMonitor: &event.CommandMonitor{
+					Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+						events = append(events, finished{evt.RetryAttempt, evt.RetryReason, evt.PreviousServerAddress})
+					},
+					Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+						events = append(events, finished{evt.RetryAttempt, evt.RetryReason, evt.PreviousServerAddress})
+					},
+				},
+			}.Execute(context.Background())
+			require.NoError(t, err, "Execute error: %v", err)
+			return events
+		}
+
+		t.Run("retry metadata of later batches", func(t *testing.T) {
+			events := execute(t, nil, newConn("a:27017", notPrimary), newConn("b:27017", ok, ok))
+			want := []finished{
+				{0, "", ""},
+				{1, string(RetryReasonNotWritablePrimary), "a:27017"},
+				{0, "", ""},
+			}
+			assert.Equal(t, want, events, "expected no retry metadata for the second batch")
+		})
+		t.Run("retry of a later batch", func(t *testing.T) {
+			var attempts []int
+			policy := &RetryPolicy{
+				MaxAttempts: 2,
+				Retryable: func(a RetryAttempt) bool {
+					attempts = append(attempts, a.Attempt)
+					return a.Retryable
+				},
+			}
+			events := execute(t, policy, newConn("a:27017", ok, notPrimary), newConn("b:27017", ok))
+			want := []finished{
+				{0, "", ""},
+				{0, "", ""},
+				{1, string(RetryReasonNotWritablePrimary), "a:27017"},
+			}
+			assert.Equal(t, want, events, "expected the second batch to be retried")
+			assert.Equal(t, []int{1}, attempts, "expected the attempts to be counted per batch")
+		})
+	})
 	t.Run("retries multiple times with RetryContext", func(t *testing.T) {
 		d := new(mockDeployment)
 		ms := new(mockRetryServer)
@@ -1063,3 +1524,102 @@ func TestMarshalBSONWriteConcern(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandCollection(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		cmdName string
+		cmd     bsoncore.Document
+		want    string
+	}{
+		{
+			name:    "collection command",
+			cmdName: "find",
+			cmd:     bsoncore.NewDocumentBuilder().AppendString("find", "coll").AppendInt32("limit", 1).Build(),
+			want:    "coll",
+		},
+		{
+			name:    "database aggregation",
+			cmdName: "aggregate",
+			cmd:     bsoncore.NewDocumentBuilder().AppendInt32("aggregate", 1).Build(),
+			want:    "",
+		},
+		{
+			name:    "getMore",
+			cmdName: "getMore",
+			cmd:     bsoncore.NewDocumentBuilder().AppendInt64("getMore", 10).AppendString("collection", "coll").Build(),
+			want:    "coll",
+		},
+		{
+			name:    "empty command",
+			cmdName: "",
+			cmd:     bsoncore.NewDocumentBuilder().Build(),
+			want:    "",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, commandCollection(tc.cmdName, tc.cmd), "collection mismatch")
+		})
+	}
+}
+
+func TestOperationDocumentSequences(t *testing.T) {
+	t.Parallel()
+
+	doc := func(i int32) bsoncore.Document {
+		return bsoncore.NewDocumentBuilder().AppendInt32("x", i).Build()
+	}
+	op := Operation{
+		CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+			return bsoncore.AppendStringElement(dst, "custom", "coll"), nil
+		},
+		Database: "db",
+		DocumentSequences: []DocumentSequence{
+			{Identifier: "first", Documents: []bsoncore.Document{doc(1), doc(2)}},
+			{Identifier: "second", Documents: []bsoncore.Document{doc(3)}},
+		},
+	}
+
+	conn := mnet.NewConnection(&mockConnection{})
+	wm, info, err := op.createMsgWireMessage(context.Background(), 0, nil, description.SelectedServer{}, conn, 1)
+	require.NoError(t, err, "createMsgWireMessage error")
+
+	_, _, _, _, wm, ok := wiremessage.ReadHeader(wm)
+	require.True(t, ok, "could not read header")
+	_, wm, ok = wiremessage.ReadMsgFlags(wm)
+	require.True(t, ok, "could not read flags")
+	_, wm, ok = wiremessage.ReadMsgSectionType(wm)
+	require.True(t, ok, "could not read section type")
+	cmd, wm, ok := wiremessage.ReadMsgSectionSingleDocument(wm)
+	require.True(t, ok, "could not read command document")
+	_, err = cmd.LookupErr("first")
+	assert.Error(t, err, "expected the documents not to be in the command document")
+
+	for _, want := range op.DocumentSequences {
+		var stype wiremessage.SectionType
+		stype, wm, ok = wiremessage.ReadMsgSectionType(wm)
+		require.True(t, ok, "could not read section type")
+		assert.Equal(t, wiremessage.DocumentSequence, stype, "expected a document sequence")
+
+		var identifier string
+		var docs []bsoncore.Document
+		identifier, docs, wm, ok = wiremessage.ReadMsgSectionDocumentSequence(wm)
+		require.True(t, ok, "could not read document sequence")
+		assert.Equal(t, want.Identifier, identifier, "identifier mismatch")
+		assert.Equal(t, want.Documents, docs, "documents mismatch")
+	}
+	assert.Len(t, wm, 0, "expected no other sections")
+
+	monitored := redactStartedInformationCmd(op, info)
+	values, err := monitored.Lookup("first").Array().Values()
+	require.NoError(t, err, "expected the monitored command to have the documents as an array")
+	assert.Len(t, values, 2, "expected two documents")
+	_, err = monitored.LookupErr("second")
+	assert.NoError(t, err, "expected the second sequence in the monitored command")
+}
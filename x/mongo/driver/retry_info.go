@@ -0,0 +1,119 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+)
+
+// RetryReason describes why a command was retried.
+type RetryReason string
+
+// These constants are the possible reasons for a retry.
+const (
+	// RetryReasonNetworkError indicates that the previous attempt failed with a network error.
+	RetryReasonNetworkError RetryReason = "network error"
+	// RetryReasonNotWritablePrimary indicates that the previous attempt was sent to a server that
+	// was no longer the primary.
+	RetryReasonNotWritablePrimary RetryReason = "not writable primary"
+	// RetryReasonNodeRecovering indicates that the previous attempt was sent to a server that was
+	// recovering or shutting down.
+	RetryReasonNodeRecovering RetryReason = "node recovering"
+	// RetryReasonPoolError indicates that a connection could not be checked out for the previous
+	// attempt.
+	RetryReasonPoolError RetryReason = "connection pool error"
+	// RetryReasonErrorLabel indicates that the previous attempt failed with an error labeled as
+	// retryable by the server, such as RetryableWriteError.
+	RetryReasonErrorLabel RetryReason = "retryable error label"
+	// RetryReasonRetryableError indicates that the previous attempt failed with another retryable
+	// error, such as a retryable read error code.
+	RetryReasonRetryableError RetryReason = "retryable error"
+)
+
+// RetryInfo describes the attempts made to execute an operation that was retried. It is attached
+// to the Error or WriteCommandError returned by an operation that was retried and can be retrieved
+// with errors.As. For a write split into several batches, it describes the attempts of the batch
+// that failed.
+type RetryInfo struct {
+	// Attempts is the number of times the command was sent to a server, including the first
+	// attempt.
+	Attempts int
+
+	// Reason is the reason for the last retry.
+	Reason RetryReason
+
+	// Labels are the error labels of the error that caused the last retry.
+	Labels []string
+
+	// Servers are the addresses of the servers the attempts were sent to, in order.
+	Servers []address.Address
+}
+
+// Error implements the error interface so that a *RetryInfo can be the target of errors.As.
+func (ri *RetryInfo) Error() string {
+	return fmt.Sprintf("operation attempted %d times, last retried because of %s on servers %v",
+		ri.Attempts, ri.Reason, ri.Servers)
+}
+
+// retryReasonFor returns the RetryReason for a retryable error.
+func retryReasonFor(err error) (RetryReason, []string) {
+	switch tt := err.(type) {
+	case Error:
+		switch {
+		case tt.NetworkError():
+			return RetryReasonNetworkError, tt.Labels
+		case tt.NotPrimary():
+			return RetryReasonNotWritablePrimary, tt.Labels
+		case tt.NodeIsRecovering(), tt.NodeIsShuttingDown():
+			return RetryReasonNodeRecovering, tt.Labels
+		case tt.HasErrorLabel(RetryableWriteError):
+			return RetryReasonErrorLabel, tt.Labels
+		}
+		return RetryReasonRetryableError, tt.Labels
+	case WriteCommandError:
+		if wce := tt.WriteConcernError; wce != nil {
+			switch {
+			case wce.NotPrimary():
+				return RetryReasonNotWritablePrimary, tt.Labels
+			case wce.NodeIsRecovering(), wce.NodeIsShuttingDown():
+				return RetryReasonNodeRecovering, tt.Labels
+			}
+		}
+		if tt.HasErrorLabel(RetryableWriteError) {
+			return RetryReasonErrorLabel, tt.Labels
+		}
+		return RetryReasonRetryableError, tt.Labels
+	case RetryablePoolError:
+		return RetryReasonPoolError, nil
+	}
+	return RetryReasonRetryableError, nil
+}
+
+// withRetryInfo attaches ri to err if err is an Error or a WriteCommandError.
+func withRetryInfo(err error, ri *RetryInfo) error {
+	switch tt := err.(type) {
+	case Error:
+		tt.RetryInfo = ri
+		return tt
+	case WriteCommandError:
+		tt.RetryInfo = ri
+		return tt
+	}
+	return err
+}
+
+// asRetryInfo implements errors.As for *RetryInfo targets.
+func asRetryInfo(ri *RetryInfo, target interface{}) bool {
+	p, ok := target.(**RetryInfo)
+	if !ok || ri == nil {
+		return false
+	}
+	*p = ri
+	return true
+}
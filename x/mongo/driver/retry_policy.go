@@ -0,0 +1,135 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures how retryable operations are retried. Without a RetryPolicy, a retryable
+// operation is retried once, immediately, on errors that the retryable reads and writes
+// specifications consider retryable.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times an operation is attempted, including the first
+	// attempt. Values less than 2 retry once. It does not apply to operations run with a timeout
+	// (RetryContext), which are retried until the timeout expires.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The delay doubles for each subsequent
+	// retry up to MaxBackoff, and a random jitter of up to half the delay is subtracted so that
+	// clients retrying at the same time spread out. If InitialBackoff is 0, retries are immediate.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. If it is 0, the delay is capped at 10
+	// seconds.
+	MaxBackoff time.Duration
+
+	// Retryable, if set, decides whether a failed attempt is retried. It is called with the failed
+	// attempt and the default decision of the driver, and returns the decision to use instead. It is
+	// only called for operations that support retries, such as retryable reads and writes.
+	// Retrying writes that the server does not consider retryable can apply them more than once.
+	Retryable func(attempt RetryAttempt) bool
+}
+
+// defaultMaxBackoff is the maximum delay between retries if RetryPolicy.MaxBackoff is not set.
+const defaultMaxBackoff = 10 * time.Second
+
+// RetryAttempt describes a failed attempt of an operation passed to RetryPolicy.Retryable.
+type RetryAttempt struct {
+	// CommandName is the name of the command that failed, such as "find" or "insert".
+	CommandName string
+
+	// Type is the type of the operation, Read or Write.
+	Type Type
+
+	// Attempt is the number of the failed attempt, starting at 1. For a write split into several
+	// batches, the attempts of each batch are counted separately.
+	Attempt int
+
+	// Err is the error of the failed attempt.
+	Err error
+
+	// Retryable is whether the driver would retry the error by default.
+	Retryable bool
+}
+
+// RetryPolicyDeployment is implemented by Deployments that configure how the operations executed
+// against them are retried.
+type RetryPolicyDeployment interface {
+	RetryPolicy() *RetryPolicy
+}
+
+// retryPolicy returns the RetryPolicy of the operation's Deployment, if any.
+func (op Operation) retryPolicy() *RetryPolicy {
+	if d, ok := op.Deployment.(RetryPolicyDeployment); ok {
+		return d.RetryPolicy()
+	}
+	return nil
+}
+
+// retries returns the number of retries allowed for operations retried with RetryOnce or
+// RetryOncePerCommand.
+func (rp *RetryPolicy) retries() int {
+	if rp == nil || rp.MaxAttempts < 2 {
+		return 1
+	}
+	return rp.MaxAttempts - 1
+}
+
+// retryable returns whether a failed attempt should be retried.
+func (rp *RetryPolicy) retryable(op Operation, attempt int, err error, retryable bool) bool {
+	if rp == nil || rp.Retryable == nil {
+		return retryable
+	}
+	return rp.Retryable(RetryAttempt{
+		CommandName: op.Name,
+		Type:        op.Type,
+		Attempt:     attempt,
+		Err:         err,
+		Retryable:   retryable,
+	})
+}
+
+// backoff returns the delay before the given retry, starting at 1.
+func (rp *RetryPolicy) backoff(retry int) time.Duration {
+	if rp == nil || rp.InitialBackoff <= 0 {
+		return 0
+	}
+
+	maxBackoff := rp.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	d := rp.InitialBackoff
+	for i := 1; i < retry && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	// Subtract a random jitter of up to half the delay.
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// wait blocks for the backoff before the given retry or until ctx is done.
+func (rp *RetryPolicy) wait(ctx context.Context, retry int) {
+	d := rp.backoff(retry)
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
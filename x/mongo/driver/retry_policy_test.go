@@ -0,0 +1,58 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retries", func(t *testing.T) {
+		var nilPolicy *RetryPolicy
+		assert.Equal(t, 1, nilPolicy.retries(), "expected one retry without a policy")
+		assert.Equal(t, 1, (&RetryPolicy{MaxAttempts: 1}).retries(), "expected at least one retry")
+		assert.Equal(t, 4, (&RetryPolicy{MaxAttempts: 5}).retries(), "expected MaxAttempts-1 retries")
+	})
+	t.Run("backoff", func(t *testing.T) {
+		var nilPolicy *RetryPolicy
+		assert.Equal(t, time.Duration(0), nilPolicy.backoff(1), "expected no backoff without a policy")
+
+		policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+		testCases := []struct {
+			retry int
+			max   time.Duration
+		}{
+			{1, 100 * time.Millisecond},
+			{2, 200 * time.Millisecond},
+			{3, 400 * time.Millisecond},
+			{4, 800 * time.Millisecond},
+			{5, time.Second},
+			{50, time.Second},
+		}
+		for _, tc := range testCases {
+			for i := 0; i < 20; i++ {
+				d := policy.backoff(tc.retry)
+				assert.True(t, d >= tc.max/2 && d <= tc.max,
+					"expected backoff for retry %d in [%v, %v], got %v", tc.retry, tc.max/2, tc.max, d)
+			}
+		}
+
+		d := (&RetryPolicy{InitialBackoff: time.Hour}).backoff(1)
+		assert.True(t, d <= defaultMaxBackoff, "expected backoff to be capped at %v, got %v", defaultMaxBackoff, d)
+	})
+	t.Run("retryable", func(t *testing.T) {
+		var nilPolicy *RetryPolicy
+		assert.True(t, nilPolicy.retryable(Operation{}, 1, nil, true), "expected default decision without a policy")
+
+		policy := &RetryPolicy{Retryable: func(a RetryAttempt) bool { return !a.Retryable }}
+		assert.False(t, policy.retryable(Operation{}, 1, nil, true), "expected predicate decision")
+		assert.True(t, policy.retryable(Operation{}, 1, nil, false), "expected predicate decision")
+	})
+}
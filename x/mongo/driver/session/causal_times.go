@@ -0,0 +1,78 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package session
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CausalTimes are the highest cluster time and operation time seen by a session, which are
+// required to read the writes of the session from another session.
+type CausalTimes struct {
+	// ClusterTime is the $clusterTime document gossiped by the servers.
+	ClusterTime bson.Raw
+
+	// OperationTime is the operation time of the last operation.
+	OperationTime *bson.Timestamp
+}
+
+// After returns whether t has a higher cluster time or operation time than other.
+func (t CausalTimes) After(other CausalTimes) bool {
+	if t.OperationTime != nil && (other.OperationTime == nil || t.OperationTime.After(*other.OperationTime)) {
+		return true
+	}
+	epoch, ord := getClusterTime(t.ClusterTime)
+	otherEpoch, otherOrd := getClusterTime(other.ClusterTime)
+	return epoch > otherEpoch || (epoch == otherEpoch && ord > otherOrd)
+}
+
+// CausalTimeStore stores the CausalTimes of the operations run by a Client, so that they can be
+// shared by Clients that serve the same user, e.g. by stateless application replicas behind a load
+// balancer. Implementations typically derive the key of the stored times from the context, e.g.
+// from a user ID, and must be safe for concurrent use.
+type CausalTimeStore interface {
+	// Load returns the stored times for the context of an operation. It returns zero CausalTimes
+	// if no times are stored.
+	Load(ctx context.Context) (CausalTimes, error)
+
+	// Save stores the times seen by an operation. The times are only saved if they are higher
+	// than the loaded times, but may be lower than times saved concurrently by another operation,
+	// so implementations should keep the highest times.
+	Save(ctx context.Context, times CausalTimes) error
+}
+
+// InjectCausalTimes advances the cluster time and operation time of the session to the given
+// times, so that the next read of the session reads the writes that produced them. Implicit
+// sessions are made causally consistent. The times of snapshot sessions and of sessions with a
+// transaction in progress are not changed.
+func (c *Client) InjectCausalTimes(times CausalTimes) error {
+	if c.Snapshot || c.TransactionInProgress() {
+		return nil
+	}
+	if times.ClusterTime != nil {
+		if err := c.AdvanceClusterTime(times.ClusterTime); err != nil {
+			return err
+		}
+	}
+	if times.OperationTime != nil {
+		ts := *times.OperationTime
+		if err := c.AdvanceOperationTime(&ts); err != nil {
+			return err
+		}
+	}
+	if c.IsImplicit {
+		c.Consistent = true
+	}
+	return nil
+}
+
+// CausalTimes returns the cluster time and operation time of the session.
+func (c *Client) CausalTimes() CausalTimes {
+	return CausalTimes{ClusterTime: c.ClusterTime, OperationTime: c.OperationTime}
+}
@@ -0,0 +1,68 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package session
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/uuid"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func clusterTimeDoc(t, i uint32) bson.Raw {
+	return bson.Raw(bsoncore.NewDocumentBuilder().
+		AppendDocument("$clusterTime", bsoncore.NewDocumentBuilder().AppendTimestamp("clusterTime", t, i).Build()).
+		Build())
+}
+
+func TestCausalTimesAfter(t *testing.T) {
+	t.Parallel()
+
+	older := CausalTimes{ClusterTime: clusterTimeDoc(10, 1), OperationTime: &bson.Timestamp{T: 10, I: 1}}
+	newerOpTime := CausalTimes{ClusterTime: clusterTimeDoc(10, 1), OperationTime: &bson.Timestamp{T: 10, I: 2}}
+	newerClusterTime := CausalTimes{ClusterTime: clusterTimeDoc(11, 0), OperationTime: &bson.Timestamp{T: 10, I: 1}}
+
+	assert.True(t, newerOpTime.After(older), "expected a higher operation time to be after")
+	assert.True(t, newerClusterTime.After(older), "expected a higher cluster time to be after")
+	assert.True(t, older.After(CausalTimes{}), "expected times to be after zero times")
+	assert.False(t, older.After(older), "expected equal times not to be after")
+	assert.False(t, CausalTimes{}.After(older), "expected zero times not to be after")
+}
+
+func TestInjectCausalTimes(t *testing.T) {
+	t.Parallel()
+
+	times := CausalTimes{ClusterTime: clusterTimeDoc(20, 3), OperationTime: &bson.Timestamp{T: 20, I: 3}}
+	id, err := uuid.New()
+	require.NoError(t, err, "uuid.New error")
+
+	t.Run("implicit session", func(t *testing.T) {
+		t.Parallel()
+
+		sess := NewImplicitClientSession(nil, id)
+		require.NoError(t, sess.InjectCausalTimes(times), "InjectCausalTimes error")
+		assert.True(t, sess.Consistent, "expected implicit session to be causally consistent")
+		assert.Equal(t, times, sess.CausalTimes(), "times mismatch")
+
+		// Lower times do not move the session back.
+		require.NoError(t, sess.InjectCausalTimes(CausalTimes{OperationTime: &bson.Timestamp{T: 1}}), "InjectCausalTimes error")
+		assert.Equal(t, times, sess.CausalTimes(), "expected times not to move back")
+	})
+	t.Run("snapshot session", func(t *testing.T) {
+		t.Parallel()
+
+		snapshot := true
+		sess, err := NewClientSession(NewPool(nil), id, &ClientOptions{Snapshot: &snapshot})
+		require.NoError(t, err, "NewClientSession error")
+		require.NoError(t, sess.InjectCausalTimes(times), "InjectCausalTimes error")
+		assert.Equal(t, CausalTimes{}, sess.CausalTimes(), "expected snapshot session times not to change")
+		assert.False(t, sess.Consistent, "expected snapshot session not to be causally consistent")
+	})
+}
@@ -45,6 +45,9 @@ var ErrUnackWCUnsupported = errors.New("transactions do not support unacknowledg
 // ErrSnapshotTransaction is returned if an transaction is started on a snapshot session.
 var ErrSnapshotTransaction = errors.New("transactions are not supported in snapshot sessions")
 
+// ErrSnapshotWrite is returned if a write operation is run on a snapshot session.
+var ErrSnapshotWrite = errors.New("write operations are not supported in snapshot sessions")
+
 // TransactionState indicates the state of the transactions FSM.
 type TransactionState uint8
 
@@ -194,6 +197,13 @@ func NewClientSession(pool *Pool, clientID uuid.UUID, opts ...*ClientOptions) (*
 	if mergedOpts.Snapshot != nil {
 		c.Snapshot = *mergedOpts.Snapshot
 	}
+	if mergedOpts.SnapshotTime != nil {
+		if !c.Snapshot {
+			return nil, errors.New("a snapshot time can only be set for a snapshot session")
+		}
+		ts := *mergedOpts.SnapshotTime
+		c.SnapshotTime = &ts
+	}
 
 	// For explicit sessions, the default for causalConsistency is true, unless Snapshot is
 	// enabled, then it's false. Set the default and then allow any explicit causalConsistency
@@ -9,6 +9,7 @@ package session
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
@@ -28,6 +29,26 @@ type topologyDescription struct {
 	timeoutMinutes *int64
 }
 
+// PoolStats contains statistics about a Pool.
+type PoolStats struct {
+	// Idle is the number of sessions in the pool.
+	Idle int
+	// CheckedOut is the number of sessions checked out of the pool.
+	CheckedOut int64
+	// Acquired is the total number of sessions checked out of the pool, including new sessions.
+	Acquired int64
+	// Created is the total number of new sessions created because the pool had no unexpired session.
+	Created int64
+	// Pruned is the total number of expired sessions removed from the pool.
+	Pruned int64
+	// Discarded is the total number of returned sessions that were not added to the pool because they
+	// were dirty or the pool was full.
+	Discarded int64
+	// LastPruned is the time an expired session was last removed from the pool, or the zero time if no
+	// session has expired.
+	LastPruned time.Time
+}
+
 // Pool is a pool of server sessions that can be reused.
 type Pool struct {
 	// number of sessions checked out of pool (accessed atomically)
@@ -38,6 +59,16 @@ type Pool struct {
 	tail           *Node
 	latestTopology topologyDescription
 	mutex          sync.Mutex // mutex to protect list and sessionTimeout
+
+	// maxIdle is the maximum number of sessions in the pool, or 0 if the pool is unbounded. The
+	// statistics of the pool are protected by mutex.
+	maxIdle    int
+	idle       int
+	acquired   int64
+	created    int64
+	pruned     int64
+	discarded  int64
+	lastPruned time.Time
 }
 
 func (p *Pool) createServerSession() (*Server, error) {
@@ -46,10 +77,44 @@ func (p *Pool) createServerSession() (*Server, error) {
 		return nil, err
 	}
 
+	p.acquired++
+	p.created++
 	atomic.AddInt64(&p.checkedOut, 1)
 	return s, nil
 }
 
+// SetMaxIdle sets the maximum number of sessions kept in the pool. Sessions returned to a full
+// pool are discarded. A value of 0 means that the pool is unbounded.
+func (p *Pool) SetMaxIdle(n int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.maxIdle = n
+}
+
+// Stats returns statistics about the pool.
+func (p *Pool) Stats() PoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return PoolStats{
+		Idle:       p.idle,
+		CheckedOut: atomic.LoadInt64(&p.checkedOut),
+		Acquired:   p.acquired,
+		Created:    p.created,
+		Pruned:     p.pruned,
+		Discarded:  p.discarded,
+		LastPruned: p.lastPruned,
+	}
+}
+
+// assumes caller has mutex to protect the pool
+func (p *Pool) recordPruned() {
+	p.idle--
+	p.pruned++
+	p.lastPruned = time.Now()
+}
+
 // NewPool creates a new server session pool
 func NewPool(descChan <-chan description.Topology) *Pool {
 	p := &Pool{
@@ -87,6 +152,7 @@ func (p *Pool) GetSession() (*Server, error) {
 		// pull session from head of queue and return if it is valid for at least 1 more minute
 		if p.head.expired(p.latestTopology) {
 			p.head = p.head.next
+			p.recordPruned()
 			continue
 		}
 
@@ -102,6 +168,8 @@ func (p *Pool) GetSession() (*Server, error) {
 			p.head = p.head.next
 		}
 
+		p.idle--
+		p.acquired++
 		atomic.AddInt64(&p.checkedOut, 1)
 		return session, nil
 	}
@@ -129,15 +197,22 @@ func (p *Pool) ReturnSession(ss *Server) {
 			p.tail.prev.next = nil
 		}
 		p.tail = p.tail.prev
+		p.recordPruned()
+	}
+	if p.tail == nil {
+		p.head = nil
 	}
 
 	// session expired
 	if ss.expired(p.latestTopology) {
+		p.pruned++
+		p.lastPruned = time.Now()
 		return
 	}
 
-	// session is dirty
-	if ss.Dirty {
+	// session is dirty or the pool is full
+	if ss.Dirty || (p.maxIdle > 0 && p.idle >= p.maxIdle) {
+		p.discarded++
 		return
 	}
 
@@ -146,6 +221,7 @@ func (p *Pool) ReturnSession(ss *Server) {
 		next:   nil,
 		prev:   nil,
 	}
+	p.idle++
 
 	// empty list
 	if p.tail == nil {
@@ -160,6 +236,19 @@ func (p *Pool) ReturnSession(ss *Server) {
 	p.head = newNode
 }
 
+// Clear discards the sessions in the pool, e.g. because they may be used by another process after
+// a fork. The discarded sessions are counted in the Discarded statistic. Sessions that are checked
+// out are not affected.
+func (p *Pool) Clear() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.discarded += int64(p.idle)
+	p.idle = 0
+	p.head = nil
+	p.tail = nil
+}
+
 // IDSlice returns a slice of session IDs for each session in the pool
 func (p *Pool) IDSlice() []bsoncore.Document {
 	p.mutex.Lock()
@@ -69,4 +69,85 @@ func TestSessionPool(t *testing.T) {
 		assert.False(t, bytes.Equal(sess.SessionID, firstID), "first expired session was not removed")
 		assert.False(t, bytes.Equal(sess.SessionID, secondID), "second expired session was not removed")
 	})
+
+	t.Run("TestStats", func(t *testing.T) {
+		descChan := make(chan description.Topology)
+		p := NewPool(descChan)
+		p.latestTopology = topologyDescription{timeoutMinutes: int64ToPtr(30)}
+
+		first, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		second, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		p.ReturnSession(first)
+		second.Dirty = true
+		p.ReturnSession(second)
+		_, err = p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+
+		stats := p.Stats()
+		assert.Equal(t, 0, stats.Idle, "idle mismatch")
+		assert.Equal(t, int64(1), stats.CheckedOut, "checked out mismatch")
+		assert.Equal(t, int64(3), stats.Acquired, "acquired mismatch")
+		assert.Equal(t, int64(2), stats.Created, "created mismatch")
+		assert.Equal(t, int64(1), stats.Discarded, "discarded mismatch")
+		assert.Equal(t, int64(0), stats.Pruned, "pruned mismatch")
+		assert.True(t, stats.LastPruned.IsZero(), "expected no pruned sessions")
+
+		// Sessions expire once the session timeout is 0.
+		third, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		p.ReturnSession(third)
+		p.latestTopology = topologyDescription{}
+		_, err = p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+
+		stats = p.Stats()
+		assert.Equal(t, 0, stats.Idle, "idle mismatch")
+		assert.Equal(t, int64(1), stats.Pruned, "pruned mismatch")
+		assert.False(t, stats.LastPruned.IsZero(), "expected LastPruned to be set")
+	})
+
+	t.Run("TestMaxIdle", func(t *testing.T) {
+		descChan := make(chan description.Topology)
+		p := NewPool(descChan)
+		p.latestTopology = topologyDescription{timeoutMinutes: int64ToPtr(30)}
+		p.SetMaxIdle(2)
+
+		var sessions []*Server
+		for i := 0; i < 3; i++ {
+			sess, err := p.GetSession()
+			assert.Nil(t, err, "GetSession error: %v", err)
+			sessions = append(sessions, sess)
+		}
+		for _, sess := range sessions {
+			p.ReturnSession(sess)
+		}
+
+		stats := p.Stats()
+		assert.Equal(t, 2, stats.Idle, "idle mismatch")
+		assert.Equal(t, int64(1), stats.Discarded, "discarded mismatch")
+		assert.Equal(t, 2, len(p.IDSlice()), "expected pool to hold 2 sessions")
+	})
+
+	t.Run("TestClear", func(t *testing.T) {
+		descChan := make(chan description.Topology)
+		p := NewPool(descChan)
+		p.latestTopology = topologyDescription{timeoutMinutes: int64ToPtr(30)}
+
+		first, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		second, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		p.ReturnSession(first)
+		p.Clear()
+		p.ReturnSession(second)
+
+		stats := p.Stats()
+		assert.Equal(t, 1, stats.Idle, "idle mismatch")
+		assert.Equal(t, int64(1), stats.Discarded, "discarded mismatch")
+		sess, err := p.GetSession()
+		assert.Nil(t, err, "GetSession error: %v", err)
+		assert.True(t, bytes.Equal(sess.SessionID, second.SessionID), "expected the session returned after Clear")
+	})
 }
@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
@@ -210,13 +211,19 @@ func (c *connection) connect(ctx context.Context) (err error) {
 	}()
 
 	// Assign the result of DialContext to a temporary net.Conn to ensure that c.nc is not set in an error case.
-	tempNc, err := c.config.dialer.DialContext(ctx, c.addr.Network(), c.addr.String())
+	var tempNc net.Conn
+	if c.config.connFactory != nil {
+		tempNc, err = c.config.connFactory(ctx, c.addr.String())
+	} else {
+		tempNc, err = c.config.dialer.DialContext(ctx, c.addr.Network(), c.addr.String())
+	}
 	if err != nil {
 		return ConnectionError{Wrapped: err, init: true}
 	}
 	c.nc = tempNc
 
-	if c.config.tlsConfig != nil {
+	// Connections created by a connection factory are used as is.
+	if c.config.tlsConfig != nil && c.config.connFactory == nil {
 		tlsConfig := c.config.tlsConfig.Clone()
 
 		// store the result of configureTLS in a separate variable than c.nc to avoid overwriting c.nc with nil in
@@ -225,6 +232,12 @@ func (c *connection) connect(ctx context.Context) (err error) {
 			Cache:                   c.config.ocspCache,
 			DisableEndpointChecking: c.config.disableOCSPEndpointCheck,
 			HTTPClient:              c.config.httpClient,
+			RequireStaple:           c.config.ocspRequireStaple,
+			HardFail:                c.config.ocspHardFail,
+		}
+		if callback := c.config.ocspCallback; callback != nil {
+			addr := c.addr
+			ocspOpts.Callback = func(res ocsp.Result) { callback(addr, res) }
 		}
 		tlsNc, err := configureTLS(ctx, c.config.tlsConnectionSource, c.nc, c.addr, tlsConfig, ocspOpts)
 
@@ -272,6 +285,9 @@ func (c *connection) connect(ctx context.Context) (err error) {
 		// the handshake.
 		err = handshaker.FinishHandshake(ctx, handshakeConn)
 	}
+	if err == nil && c.config.handshakeHook != nil {
+		err = c.config.handshakeHook(c.addr, c.desc)
+	}
 
 	// We have a failed handshake here
 	if err != nil {
@@ -842,18 +858,18 @@ func (c *Connection) LocalAddress() address.Address {
 
 // PinToCursor updates this connection to reflect that it is pinned to a cursor.
 func (c *Connection) PinToCursor() error {
-	return c.pin("cursor", c.connection.pool.pinConnectionToCursor, c.connection.pool.unpinConnectionFromCursor)
+	return c.pin(event.PinReasonCursor, c.connection.pool.pinConnectionToCursor, c.connection.pool.unpinConnectionFromCursor)
 }
 
 // PinToTransaction updates this connection to reflect that it is pinned to a transaction.
 func (c *Connection) PinToTransaction() error {
-	return c.pin("transaction", c.connection.pool.pinConnectionToTransaction, c.connection.pool.unpinConnectionFromTransaction)
+	return c.pin(event.PinReasonTransaction, c.connection.pool.pinConnectionToTransaction, c.connection.pool.unpinConnectionFromTransaction)
 }
 
 func (c *Connection) pin(reason string, updatePoolFn, cleanupPoolFn func()) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	if c.connection == nil {
+		c.mu.Unlock()
 		return fmt.Errorf("attempted to pin a connection for a %s, but the connection has already been returned to the pool", reason)
 	}
 
@@ -864,34 +880,57 @@ func (c *Connection) pin(reason string, updatePoolFn, cleanupPoolFn func()) erro
 		c.cleanupPoolFn = cleanupPoolFn
 	}
 	c.refCount++
+	conn := c.connection
+	c.mu.Unlock()
+
+	conn.publishPinEvent(event.ConnectionPinned, reason)
 	return nil
 }
 
 // UnpinFromCursor updates this connection to reflect that it is no longer pinned to a cursor.
 func (c *Connection) UnpinFromCursor() error {
-	return c.unpin("cursor")
+	return c.unpin(event.PinReasonCursor)
 }
 
 // UnpinFromTransaction updates this connection to reflect that it is no longer pinned to a transaction.
 func (c *Connection) UnpinFromTransaction() error {
-	return c.unpin("transaction")
+	return c.unpin(event.PinReasonTransaction)
 }
 
 func (c *Connection) unpin(reason string) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	if c.connection == nil {
+		c.mu.Unlock()
 		// We don't error here because the resource could have been forcefully closed via Expire.
 		return nil
 	}
 	if c.refCount == 0 {
+		c.mu.Unlock()
 		return fmt.Errorf("attempted to unpin a connection from a %s, but the connection is not pinned by any resources", reason)
 	}
 
 	c.refCount--
+	conn := c.connection
+	c.mu.Unlock()
+
+	conn.publishPinEvent(event.ConnectionUnpinned, reason)
 	return nil
 }
 
+// publishPinEvent publishes a ConnectionPinned or ConnectionUnpinned event for the connection.
+func (c *connection) publishPinEvent(typ, reason string) {
+	if c.pool == nil || c.pool.monitor == nil {
+		return
+	}
+	c.pool.monitor.Event(&event.PoolEvent{
+		Type:         typ,
+		Address:      c.addr.String(),
+		ConnectionID: c.driverConnectionID,
+		Reason:       reason,
+		ServiceID:    c.desc.ServiceID,
+	})
+}
+
 // DriverConnectionID returns the driver connection ID.
 func (c *Connection) DriverConnectionID() int64 {
 	return c.connection.DriverConnectionID()
@@ -0,0 +1,267 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/logger"
+)
+
+// budgetReason is the reason of the ConnectionClosed events of the connections that a pool closes
+// to give their slot in the connection budget to a pool that is below its fair share.
+var budgetReason = reason{
+	loggerConn: logger.ReasonConnClosedConnectionBudget,
+	event:      event.ReasonConnectionBudget,
+}
+
+// ConnectionBudgetStats contains statistics about the connection budget of a Topology. See
+// Topology.ConnectionBudgetStats.
+type ConnectionBudgetStats struct {
+	// Max is the maximum number of connections of all the connection pools.
+	Max uint64
+
+	// InUse is the number of connections of all the connection pools, including the connections
+	// being established.
+	InUse uint64
+
+	// Throttled is the total number of times a connection pool had to wait for the budget to
+	// create a connection.
+	Throttled int64
+
+	// Waiting is the number of connection pools currently waiting for the budget.
+	Waiting int
+
+	// Servers is the number of connections of the connection pool of each server, by address.
+	Servers map[string]uint64
+}
+
+// budgetShare is the part of the connection budget used by a connection pool.
+type budgetShare struct {
+	used    uint64
+	waiting bool // waiting is true while the pool has checkouts that wait for the budget.
+	closed  bool // closed is true once the pool is closed. The share is removed when unused.
+}
+
+// connectionBudget limits the total number of connections of all the connection pools of a
+// Topology. Each open pool is entitled to an equal share of the budget. A pool can use more than
+// its share while no pool below its share is waiting for a connection, and closes its idle and
+// checked in connections while another pool is waiting below its share.
+//
+// The budget never takes the createConnectionsCond lock of a pool while holding its own lock, so
+// its methods can be called with the createConnectionsCond lock of any pool held.
+type connectionBudget struct {
+	max uint64
+
+	mu        sync.Mutex
+	inUse     uint64
+	throttled int64
+	pools     map[*pool]*budgetShare
+}
+
+func newConnectionBudget(max uint64) *connectionBudget {
+	return &connectionBudget{
+		max:   max,
+		pools: make(map[*pool]*budgetShare),
+	}
+}
+
+// register adds p to the pools that share the budget.
+func (b *connectionBudget) register(p *pool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pools[p] = &budgetShare{}
+}
+
+// unregister removes p from the pools that share the budget. The connections of p still count
+// against the budget until they are removed from p.
+func (b *connectionBudget) unregister(p *pool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.pools[p]
+	if !ok {
+		return
+	}
+	if s.used == 0 {
+		delete(b.pools, p)
+		return
+	}
+	s.closed = true
+	s.waiting = false
+}
+
+// fairShareLocked returns the number of connections each open pool is entitled to.
+func (b *connectionBudget) fairShareLocked() uint64 {
+	open := uint64(0)
+	for _, s := range b.pools {
+		if !s.closed {
+			open++
+		}
+	}
+	if open == 0 {
+		return b.max
+	}
+	if share := b.max / open; share > 0 {
+		return share
+	}
+	return 1
+}
+
+// starvedLocked returns a pool other than p that is waiting for the budget below its fair share,
+// or nil if there is none.
+func (b *connectionBudget) starvedLocked(p *pool, share uint64) *pool {
+	for q, s := range b.pools {
+		if q != p && s.waiting && s.used < share {
+			return q
+		}
+	}
+	return nil
+}
+
+// acquire reserves a connection of the budget for p and returns true, or marks p as waiting for
+// the budget and returns false. A reservation must be given back with release.
+func (b *connectionBudget) acquire(p *pool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.pools[p]
+	if !ok {
+		return true
+	}
+
+	share := b.fairShareLocked()
+	if b.inUse < b.max {
+		starved := (*pool)(nil)
+		if s.used >= share {
+			starved = b.starvedLocked(p, share)
+		}
+		if starved == nil {
+			s.used++
+			b.inUse++
+			// Pools that waited for p to reach its share can use the rest of the budget now.
+			if s.used == share && b.inUse < b.max {
+				b.wakeLocked(p)
+			}
+			return true
+		}
+		// Wake the starved pool so that it takes the free connection, or notices that it no
+		// longer waits.
+		wakePool(starved)
+	}
+
+	if !s.waiting {
+		s.waiting = true
+		b.throttled++
+		if s.used < share {
+			if victim := b.overShareLocked(share); victim != nil {
+				go victim.closeIdleConnection()
+			}
+		}
+	}
+	return false
+}
+
+// notWaiting marks p as no longer waiting for the budget.
+func (b *connectionBudget) notWaiting(p *pool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.pools[p]; ok && s.waiting {
+		s.waiting = false
+		b.wakeLocked(p)
+	}
+}
+
+// release gives back a connection reserved by p with acquire.
+func (b *connectionBudget) release(p *pool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.pools[p]
+	if !ok || s.used == 0 {
+		return
+	}
+	s.used--
+	b.inUse--
+	if s.closed && s.used == 0 {
+		delete(b.pools, p)
+	}
+	b.wakeLocked(nil)
+}
+
+// yield returns true if p uses more than its fair share while another pool is waiting below its
+// share, in which case p closes the connections that are checked in.
+func (b *connectionBudget) yield(p *pool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.pools[p]
+	if !ok {
+		return false
+	}
+	share := b.fairShareLocked()
+	return s.used > share && b.starvedLocked(p, share) != nil
+}
+
+// overShareLocked returns the pool that uses the most connections above the fair share, or nil if
+// there is none.
+func (b *connectionBudget) overShareLocked(share uint64) *pool {
+	var victim *pool
+	most := share
+	for q, s := range b.pools {
+		if s.used > most {
+			victim, most = q, s.used
+		}
+	}
+	return victim
+}
+
+// wakeLocked wakes the createConnections goroutines of the pools other than except that wait for
+// the budget.
+func (b *connectionBudget) wakeLocked(except *pool) {
+	for q, s := range b.pools {
+		if q != except && s.waiting {
+			wakePool(q)
+		}
+	}
+}
+
+// wakePool wakes the createConnections goroutines of p so that they check the budget again. It
+// broadcasts in a new goroutine because the caller may hold the createConnectionsCond lock of
+// another pool, and holds the lock of p so that the broadcast is not missed by a goroutine that
+// checked the budget but is not waiting yet.
+func wakePool(p *pool) {
+	go func() {
+		p.createConnectionsCond.L.Lock()
+		p.createConnectionsCond.Broadcast()
+		p.createConnectionsCond.L.Unlock()
+	}()
+}
+
+func (b *connectionBudget) stats() ConnectionBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := ConnectionBudgetStats{
+		Max:       b.max,
+		InUse:     b.inUse,
+		Throttled: b.throttled,
+		Servers:   make(map[string]uint64, len(b.pools)),
+	}
+	for p, s := range b.pools {
+		if s.waiting {
+			stats.Waiting++
+		}
+		if !s.closed {
+			stats.Servers[p.address.String()] = s.used
+		}
+	}
+	return stats
+}
@@ -0,0 +1,144 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+)
+
+// newBudgetTestPools returns two ready pools for servers that accept up to conns connections each
+// and share a connection budget of max connections.
+func newBudgetTestPools(t *testing.T, max uint64, conns int) (*connectionBudget, *pool, *pool) {
+	t.Helper()
+
+	cleanup := make(chan struct{})
+	t.Cleanup(func() { close(cleanup) })
+
+	budget := newConnectionBudget(max)
+	newTestPool := func() *pool {
+		addr := bootstrapConnections(t, conns, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+		p := newPool(poolConfig{
+			Address:          address.Address(addr.String()),
+			ConnectTimeout:   defaultConnectionTimeout,
+			ConnectionBudget: budget,
+		})
+		err := p.ready()
+		require.NoError(t, err, "ready error")
+		t.Cleanup(func() { p.close(context.Background()) })
+		return p
+	}
+	return budget, newTestPool(), newTestPool()
+}
+
+func TestConnectionBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("busy pools share the budget", func(t *testing.T) {
+		t.Parallel()
+
+		budget, p1, p2 := newBudgetTestPools(t, 2, 3)
+
+		// p1 can use the whole budget while p2 does not need connections.
+		c1, err := p1.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+		c2, err := p1.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = p1.checkOut(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected the budget to be exhausted, got %v", err)
+
+		// Checking in a connection while p2 waits below its share closes the connection.
+		done := make(chan *connection)
+		go func() {
+			conn, err := p2.checkOut(context.Background())
+			assert.NoError(t, err, "checkOut error")
+			done <- conn
+		}()
+		assert.Eventually(t, func() bool {
+			return budget.stats().Waiting > 0
+		}, time.Second, time.Millisecond, "expected p2 to wait for the budget")
+
+		err = p1.checkIn(c1)
+		require.NoError(t, err, "checkIn error")
+		select {
+		case conn := <-done:
+			require.NotNil(t, conn, "expected a connection")
+			err = p2.checkIn(conn)
+			require.NoError(t, err, "checkIn error")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for p2 to check out a connection")
+		}
+
+		// The timed out checkOut of p1 stops waiting once p1 checks its wait queue again.
+		assert.Eventually(t, func() bool {
+			return budget.stats().Waiting == 0
+		}, time.Second, time.Millisecond, "expected no pool to wait")
+		stats := budget.stats()
+		assert.Equal(t, uint64(2), stats.InUse, "InUse mismatch")
+		assert.Equal(t, int64(2), stats.Throttled, "Throttled mismatch")
+		assert.Equal(t, uint64(1), stats.Servers[p1.address.String()], "expected p1 to be back to its share")
+		assert.Equal(t, uint64(1), stats.Servers[p2.address.String()], "expected p2 to have its share")
+		assert.Equal(t, 1, p1.totalConnectionCount(), "expected the connection of p1 to be closed")
+
+		err = p1.checkIn(c2)
+		require.NoError(t, err, "checkIn error")
+		assert.Equal(t, 1, p1.availableConnectionCount(), "expected connections within the share to be kept")
+	})
+	t.Run("idle connections are reclaimed", func(t *testing.T) {
+		t.Parallel()
+
+		budget, p1, p2 := newBudgetTestPools(t, 2, 2)
+
+		c1, err := p1.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+		c2, err := p1.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+		require.NoError(t, p1.checkIn(c1), "checkIn error")
+		require.NoError(t, p1.checkIn(c2), "checkIn error")
+		require.Equal(t, 2, p1.availableConnectionCount(), "expected idle connections")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := p2.checkOut(ctx)
+		require.NoError(t, err, "checkOut error")
+		require.NoError(t, p2.checkIn(conn), "checkIn error")
+
+		assert.Equal(t, 1, p1.totalConnectionCount(), "expected an idle connection of p1 to be closed")
+		assert.Equal(t, uint64(2), budget.stats().InUse, "InUse mismatch")
+	})
+	t.Run("closed pools leave the budget", func(t *testing.T) {
+		t.Parallel()
+
+		budget, p1, p2 := newBudgetTestPools(t, 4, 1)
+
+		conn, err := p1.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+		require.NoError(t, p1.checkIn(conn), "checkIn error")
+
+		p1.close(context.Background())
+		stats := budget.stats()
+		assert.Equal(t, uint64(0), stats.InUse, "expected the connections of p1 to be released")
+		assert.Equal(t, map[string]uint64{p2.address.String(): 0}, stats.Servers, "Servers mismatch")
+
+		budget.mu.Lock()
+		defer budget.mu.Unlock()
+		assert.Equal(t, uint64(4), budget.fairShareLocked(), "expected p2 to be entitled to the whole budget")
+	})
+}
@@ -16,7 +16,9 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/httputil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
 )
 
@@ -47,8 +49,16 @@ type Handshaker = driver.Handshaker
 // generationNumberFn is a callback type used by a connection to fetch its generation number given its service ID.
 type generationNumberFn func(serviceID *bson.ObjectID) uint64
 
+// connFactoryFn is a callback type used by a connection to create its network connection instead of dialing it.
+type connFactoryFn func(ctx context.Context, address string) (net.Conn, error)
+
+// handshakeHookFn is a callback type called with the description of a connection after its handshake completes.
+type handshakeHookFn func(addr address.Address, desc description.Server) error
+
 type connectionConfig struct {
 	dialer                   Dialer
+	connFactory              connFactoryFn
+	handshakeHook            handshakeHookFn
 	handshaker               Handshaker
 	idleTimeout              time.Duration
 	cmdMonitor               *event.CommandMonitor
@@ -59,6 +69,9 @@ type connectionConfig struct {
 	zstdLevel                *int
 	ocspCache                ocsp.Cache
 	disableOCSPEndpointCheck bool
+	ocspRequireStaple        bool
+	ocspHardFail             bool
+	ocspCallback             func(address.Address, ocsp.Result)
 	tlsConnectionSource      tlsConnectionSource
 	loadBalanced             bool
 	getGenerationFn          generationNumberFn
@@ -110,6 +123,22 @@ func WithDialer(fn func(Dialer) Dialer) ConnectionOption {
 	}
 }
 
+// withConnFactory configures a function that creates the network connections to MongoDB instead of the Dialer.
+// TLS is not applied to the connections it creates.
+func withConnFactory(fn func(connFactoryFn) connFactoryFn) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.connFactory = fn(c.connFactory)
+	}
+}
+
+// withHandshakeHook configures a function called after the handshake of each new connection completes. If it
+// returns an error, the connection fails to connect.
+func withHandshakeHook(fn func(handshakeHookFn) handshakeHookFn) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.handshakeHook = fn(c.handshakeHook)
+	}
+}
+
 // WithHandshaker configures the Handshaker that wll be used to initialize newly
 // dialed connections.
 func WithHandshaker(fn func(Handshaker) Handshaker) ConnectionOption {
@@ -176,6 +205,28 @@ func WithDisableOCSPEndpointCheck(fn func(bool) bool) ConnectionOption {
 	}
 }
 
+// WithOCSPRequireStaple specifies whether OCSP verification fails if the server does not staple an OCSP response.
+func WithOCSPRequireStaple(fn func(bool) bool) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.ocspRequireStaple = fn(c.ocspRequireStaple)
+	}
+}
+
+// WithOCSPHardFail specifies whether OCSP verification fails if the status of the server certificate cannot be
+// determined.
+func WithOCSPHardFail(fn func(bool) bool) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.ocspHardFail = fn(c.ocspHardFail)
+	}
+}
+
+// WithOCSPCallback specifies a function called with the outcome of the OCSP verification of each connection.
+func WithOCSPCallback(fn func(func(address.Address, ocsp.Result)) func(address.Address, ocsp.Result)) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.ocspCallback = fn(c.ocspCallback)
+	}
+}
+
 // WithConnectionLoadBalanced specifies whether or not the connection is to a server behind a load balancer.
 func WithConnectionLoadBalanced(fn func(bool) bool) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
@@ -98,6 +99,55 @@ func TestConnection(t *testing.T) {
 				connState := atomic.LoadInt64(&conn.state)
 				assert.Equal(t, connDisconnected, connState, "expected connection state %v, got %v", connDisconnected, connState)
 			})
+			t.Run("connection factory", func(t *testing.T) {
+				var gotAddr string
+				client, server := net.Pipe()
+				defer server.Close()
+				conn := newConnection(address.Address("/tmp/mongodb-27017.sock"),
+					withConnFactory(func(connFactoryFn) connFactoryFn {
+						return func(_ context.Context, addr string) (net.Conn, error) {
+							gotAddr = addr
+							return client, nil
+						}
+					}),
+					WithTLSConfig(func(*tls.Config) *tls.Config { return &tls.Config{} }),
+					WithHandshaker(func(Handshaker) Handshaker {
+						return &testHandshaker{}
+					}),
+				)
+				err := conn.connect(context.Background())
+				require.NoError(t, err, "connect error")
+				assert.Equal(t, "/tmp/mongodb-27017.sock", gotAddr, "expected connection factory to be called with address")
+				assert.Equal(t, client, conn.nc, "expected connection from the factory to be used without TLS")
+			})
+			t.Run("handshake hook error", func(t *testing.T) {
+				err := errors.New("handshake hook error")
+				var want error = ConnectionError{Wrapped: err, init: true}
+				var gotAddr address.Address
+				conn := newConnection(address.Address("localhost:27017"),
+					withHandshakeHook(func(handshakeHookFn) handshakeHookFn {
+						return func(addr address.Address, _ description.Server) error {
+							gotAddr = addr
+							return err
+						}
+					}),
+					WithHandshaker(func(Handshaker) Handshaker {
+						return &testHandshaker{}
+					}),
+					WithDialer(func(Dialer) Dialer {
+						return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+							return &net.TCPConn{}, nil
+						})
+					}),
+				)
+				got := conn.connect(context.Background())
+				if !cmp.Equal(got, want, cmp.Comparer(compareErrors)) {
+					t.Errorf("errors do not match. got %v; want %v", got, want)
+				}
+				assert.Equal(t, address.Address("localhost:27017"), gotAddr, "expected handshake hook to be called with address")
+				connState := atomic.LoadInt64(&conn.state)
+				assert.Equal(t, connDisconnected, connState, "expected connection state %v, got %v", connDisconnected, connState)
+			})
 			t.Run("context is not pinned by connect", func(t *testing.T) {
 				// connect creates a cancel-able version of the context passed to it and stores the CancelFunc on the
 				// connection. The CancelFunc must be set to nil once the connection has been established so the driver
@@ -785,6 +835,55 @@ func TestConnection(t *testing.T) {
 				assert.Nil(t, conn.connection, "expected connection to be released to the pool but was not")
 				assertPoolPinnedStats(t, pool, 0, 0)
 			})
+			t.Run("pin events", func(t *testing.T) {
+				var mu sync.Mutex
+				var events []*event.PoolEvent
+				monitor := &event.PoolMonitor{
+					Event: func(evt *event.PoolEvent) {
+						if evt.Type != event.ConnectionPinned && evt.Type != event.ConnectionUnpinned {
+							return
+						}
+						mu.Lock()
+						events = append(events, evt)
+						mu.Unlock()
+					},
+				}
+
+				addr := bootstrapConnections(t, 1, func(net.Conn) {})
+				pool := newPool(poolConfig{
+					Address:        address.Address(addr.String()),
+					ConnectTimeout: defaultConnectionTimeout,
+					PoolMonitor:    monitor,
+				})
+				defer pool.close(context.Background())
+				err := pool.ready()
+				assert.Nil(t, err, "pool.ready() error: %v", err)
+
+				c, err := pool.checkOut(context.Background())
+				assert.Nil(t, err, "checkOut error: %v", err)
+				conn := &Connection{connection: c}
+
+				err = conn.PinToCursor()
+				assert.Nil(t, err, "PinToCursor error: %v", err)
+				err = conn.PinToTransaction()
+				assert.Nil(t, err, "PinToTransaction error: %v", err)
+				err = conn.UnpinFromCursor()
+				assert.Nil(t, err, "UnpinFromCursor error: %v", err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				want := []struct{ typ, reason string }{
+					{event.ConnectionPinned, event.PinReasonCursor},
+					{event.ConnectionPinned, event.PinReasonTransaction},
+					{event.ConnectionUnpinned, event.PinReasonCursor},
+				}
+				assert.Equal(t, len(want), len(events), "expected %d pin events, got %d", len(want), len(events))
+				for i, evt := range events {
+					assert.Equal(t, want[i].typ, evt.Type, "expected event type")
+					assert.Equal(t, want[i].reason, evt.Reason, "expected pin reason")
+					assert.Equal(t, c.driverConnectionID, evt.ConnectionID, "expected connection ID")
+				}
+			})
 		})
 	})
 }
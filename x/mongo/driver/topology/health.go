@@ -0,0 +1,55 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+// ServerHealth is a snapshot of the monitoring state of a server in a Topology.
+type ServerHealth struct {
+	// Description is the latest description of the server, including the error of the last
+	// heartbeat, if it failed.
+	Description description.Server
+
+	// RTT are the round-trip times observed by the server's RTT monitor.
+	RTT RTTStats
+
+	// OperationCount is the number of operations in progress on the server.
+	OperationCount int64
+}
+
+func (t *Topology) serverList() []*Server {
+	t.serversLock.Lock()
+	defer t.serversLock.Unlock()
+
+	servers := make([]*Server, 0, len(t.servers))
+	for _, s := range t.servers {
+		servers = append(servers, s)
+	}
+	return servers
+}
+
+// ServerHealth returns a snapshot of the monitoring state of each server in the topology, sorted
+// by address. It does not send any command to the servers.
+func (t *Topology) ServerHealth() []ServerHealth {
+	servers := t.serverList()
+	health := make([]ServerHealth, 0, len(servers))
+	for _, s := range servers {
+		health = append(health, ServerHealth{
+			Description:    s.Description(),
+			RTT:            s.rttMonitor.stats(),
+			OperationCount: s.OperationCount(),
+		})
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Description.Addr < health[j].Description.Addr
+	})
+	return health
+}
@@ -11,11 +11,14 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -422,3 +425,66 @@ func TestPollSRVRecordsServiceName(t *testing.T) {
 		compareHosts(t, actualHosts, expectedHosts)
 	})
 }
+
+// staticProvider is a dns.Provider that serves its SRV records without querying DNS.
+type staticProvider struct {
+	mu      sync.Mutex
+	records []*net.SRV
+}
+
+func (p *staticProvider) setRecords(records ...*net.SRV) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records = records
+}
+
+func (p *staticProvider) LookupSRV(context.Context, string, string, string) (string, []*net.SRV, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return "", p.records, nil
+}
+
+func (p *staticProvider) LookupTXT(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func TestPollSRVRecordsDNSResolver(t *testing.T) {
+	provider := &staticProvider{}
+	provider.setRecords(&net.SRV{Target: "db1.example.com.", Port: 27017})
+
+	events := make(chan *event.SRVHostsChangedEvent, 10)
+	monitor := &event.ServerMonitor{
+		SRVHostsChanged: func(evt *event.SRVHostsChangedEvent) { events <- evt },
+	}
+	opts := options.Client().
+		SetDNSResolver(provider).
+		SetSRVPollingInterval(5 * time.Millisecond).
+		SetServerMonitor(monitor).
+		ApplyURI("mongodb+srv://cluster.example.com/?heartbeatFrequencyMS=500")
+
+	args, err := mongoutil.NewOptions[options.ClientOptions](opts)
+	require.NoError(t, err, "error constructing options: %v", err)
+	assert.Equal(t, []string{"db1.example.com:27017"}, args.Hosts, "expected hosts from the provider")
+
+	cfg, err := NewConfig(opts, nil)
+	require.NoError(t, err, "error constructing topology config: %v", err)
+	topo, err := New(cfg)
+	require.NoError(t, err, "Could not create the topology: %v", err)
+	assert.Equal(t, 5*time.Millisecond, topo.rescanSRVInterval, "expected the configured polling interval")
+
+	err = topo.Connect()
+	require.NoError(t, err, "Could not Connect to the topology: %v", err)
+	defer func() { _ = topo.Disconnect(context.Background()) }()
+
+	provider.setRecords(&net.SRV{Target: "db2.example.com.", Port: 27017})
+
+	select {
+	case evt := <-events:
+		assert.Equal(t, topo.id, evt.TopologyID, "topology ID mismatch")
+		assert.Equal(t, []address.Address{"db2.example.com:27017"}, evt.Added, "added hosts mismatch")
+		assert.Equal(t, []address.Address{"db1.example.com:27017"}, evt.Removed, "removed hosts mismatch")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SRVHostsChangedEvent")
+	}
+	compareHosts(t, topo.Description().Servers, []string{"db2.example.com:27017"})
+}
@@ -39,6 +39,10 @@ var ErrPoolClosed = PoolError("attempted to check out a connection from closed c
 // ErrConnectionClosed is returned from an attempt to use an already closed connection.
 var ErrConnectionClosed = ConnectionError{ConnectionID: "<closed>", message: "connection is closed"}
 
+// ErrCheckoutQueueTimeout is wrapped by the WaitQueueTimeoutError returned when a checkOut waits
+// longer than the checkout queue timeout carried by its Context.
+var ErrCheckoutQueueTimeout = PoolError("checkout queue timeout exceeded")
+
 // ErrWrongPool is return when a connection is returned to a pool it doesn't belong to.
 var ErrWrongPool = PoolError("connection does not belong to this pool")
 
@@ -80,6 +84,7 @@ type poolConfig struct {
 	Logger           *logger.Logger
 	handshakeErrFn   func(error, uint64, *bson.ObjectID)
 	ConnectTimeout   time.Duration
+	ConnectionBudget *connectionBudget
 }
 
 type pool struct {
@@ -91,6 +96,7 @@ type pool struct {
 	nextID                       int64 // nextID is the next pool ID for a new connection.
 	pinnedCursorConnections      uint64
 	pinnedTransactionConnections uint64
+	hibernating                  int32 // hibernating is 1 while the pool does not keep idle connections.
 
 	address       address.Address
 	minSize       uint64
@@ -100,6 +106,10 @@ type pool struct {
 	monitor       *event.PoolMonitor
 	logger        *logger.Logger
 
+	// budget limits the connections of all the pools of the topology, or is nil if there is no
+	// limit other than maxSize.
+	budget *connectionBudget
+
 	// handshakeErrFn is used to handle any errors that happen during connection establishment and
 	// handshaking.
 	handshakeErrFn func(error, uint64, *bson.ObjectID)
@@ -228,6 +238,7 @@ func newPool(config poolConfig, connOpts ...ConnectionOption) *pool {
 		conns:                 make(map[int64]*connection, config.MaxPoolSize),
 		idleConns:             make([]*connection, 0, config.MaxPoolSize),
 		connectTimeout:        config.ConnectTimeout,
+		budget:                config.ConnectionBudget,
 	}
 	// minSize must not exceed maxSize if maxSize is not 0
 	if pool.maxSize != 0 && pool.minSize > pool.maxSize {
@@ -236,6 +247,9 @@ func newPool(config poolConfig, connOpts ...ConnectionOption) *pool {
 	pool.connOpts = append(pool.connOpts, withGenerationNumberFn(func(_ generationNumberFn) generationNumberFn { return pool.getGenerationForNewConnection }))
 
 	pool.generation.connect()
+	if pool.budget != nil {
+		pool.budget.register(pool)
+	}
 
 	// Create a Context with cancellation that's used to signal the createConnections() and
 	// maintain() background goroutines to stop. Also create a "backgroundDone" WaitGroup that is
@@ -245,9 +259,15 @@ func newPool(config poolConfig, connOpts ...ConnectionOption) *pool {
 
 	for i := 0; i < int(pool.maxConnecting); i++ {
 		pool.backgroundDone.Add(1)
-		go pool.createConnections(ctx, pool.backgroundDone)
+		go pool.createConnections(ctx, pool.backgroundDone, driver.CheckoutPriorityBackground)
 	}
 
+	// Start one more createConnections() goroutine that only serves critical checkOut requests, so
+	// a critical checkOut that needs a new connection is not queued behind maxConnecting slow
+	// connection establishments started for lower priority requests.
+	pool.backgroundDone.Add(1)
+	go pool.createConnections(ctx, pool.backgroundDone, driver.CheckoutPriorityCritical)
+
 	// If maintainInterval is not positive, don't start the maintain() goroutine. Expect that
 	// negative values are only used in testing; this config value is not user-configurable.
 	if maintainInterval > 0 {
@@ -348,6 +368,10 @@ func (p *pool) close(ctx context.Context) {
 	p.createConnectionsCond.Broadcast()
 	p.createConnectionsCond.L.Unlock()
 
+	if p.budget != nil {
+		p.budget.unregister(p)
+	}
+
 	// Wait for all background goroutines to exit.
 	p.backgroundDone.Wait()
 
@@ -539,6 +563,7 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 	// are returned to the pool (e.g. if a connection was delivered immediately after the Context
 	// timed out).
 	w := newWantConn()
+	w.priority = driver.CheckoutPriorityFromContext(ctx)
 	defer func() {
 		if err != nil {
 			w.cancel(p, err)
@@ -603,8 +628,17 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 	p.queueForNewConn(w)
 	p.stateMu.RUnlock()
 
-	// Wait for either the wantConn to be ready or for the Context to time out.
+	// Wait for either the wantConn to be ready, for the Context to time out, or for the checkout
+	// queue timeout carried by the Context to expire.
+	var queueTimeout <-chan time.Time
+	if timeout, ok := driver.CheckoutQueueTimeoutFromContext(ctx); ok {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		queueTimeout = timer.C
+	}
+
 	waitQueueStart := time.Now()
+	var waitErr error
 	select {
 	case <-w.ready:
 		if w.err != nil {
@@ -652,43 +686,47 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 		}
 		return w.conn, nil
 	case <-ctx.Done():
-		waitQueueDuration := time.Since(waitQueueStart)
+		waitErr = ctx.Err()
+	case <-queueTimeout:
+		waitErr = ErrCheckoutQueueTimeout
+	}
 
-		duration := time.Since(start)
-		if mustLogPoolMessage(p) {
-			keysAndValues := logger.KeyValues{
-				logger.KeyDurationMS, duration.Milliseconds(),
-				logger.KeyReason, logger.ReasonConnCheckoutFailedTimout,
-			}
+	waitQueueDuration := time.Since(waitQueueStart)
 
-			logPoolMessage(p, logger.ConnectionCheckoutFailed, keysAndValues...)
+	duration := time.Since(start)
+	if mustLogPoolMessage(p) {
+		keysAndValues := logger.KeyValues{
+			logger.KeyDurationMS, duration.Milliseconds(),
+			logger.KeyReason, logger.ReasonConnCheckoutFailedTimout,
 		}
 
-		if p.monitor != nil {
-			p.monitor.Event(&event.PoolEvent{
-				Type:     event.ConnectionCheckOutFailed,
-				Address:  p.address.String(),
-				Duration: duration,
-				Reason:   event.ReasonTimedOut,
-				Error:    ctx.Err(),
-			})
-		}
+		logPoolMessage(p, logger.ConnectionCheckoutFailed, keysAndValues...)
+	}
 
-		err := WaitQueueTimeoutError{
-			Wrapped:              ctx.Err(),
-			maxPoolSize:          p.maxSize,
-			totalConnections:     p.totalConnectionCount(),
-			availableConnections: p.availableConnectionCount(),
-			waitDuration:         waitQueueDuration,
-		}
-		if p.loadBalanced {
-			err.pinnedConnections = &pinnedConnections{
-				cursorConnections:      atomic.LoadUint64(&p.pinnedCursorConnections),
-				transactionConnections: atomic.LoadUint64(&p.pinnedTransactionConnections),
-			}
+	if p.monitor != nil {
+		p.monitor.Event(&event.PoolEvent{
+			Type:     event.ConnectionCheckOutFailed,
+			Address:  p.address.String(),
+			Duration: duration,
+			Reason:   event.ReasonTimedOut,
+			Error:    waitErr,
+		})
+	}
+
+	timeoutErr := WaitQueueTimeoutError{
+		Wrapped:              waitErr,
+		maxPoolSize:          p.maxSize,
+		totalConnections:     p.totalConnectionCount(),
+		availableConnections: p.availableConnectionCount(),
+		waitDuration:         waitQueueDuration,
+	}
+	if p.loadBalanced {
+		timeoutErr.pinnedConnections = &pinnedConnections{
+			cursorConnections:      atomic.LoadUint64(&p.pinnedCursorConnections),
+			transactionConnections: atomic.LoadUint64(&p.pinnedTransactionConnections),
 		}
-		return nil, err
 	}
+	return nil, timeoutErr
 }
 
 // closeConnection closes a connection.
@@ -733,9 +771,13 @@ func (p *pool) removeConnection(conn *connection, reason reason, err error) erro
 		return nil
 	}
 	delete(p.conns, conn.driverConnectionID)
-	// Signal the createConnectionsCond so any goroutines waiting for a new connection slot in the
-	// pool will proceed.
-	p.createConnectionsCond.Signal()
+	if p.budget != nil {
+		p.budget.release(p)
+	}
+	// Broadcast to the createConnectionsCond so any goroutines waiting for a new connection slot in
+	// the pool will proceed. Signal is not enough because the woken goroutine may only serve
+	// critical checkOut requests.
+	p.createConnectionsCond.Broadcast()
 	p.createConnectionsCond.L.Unlock()
 
 	// Only update the generation numbers map if the connection has retrieved its generation number.
@@ -921,6 +963,17 @@ func (p *pool) checkInNoEvent(conn *connection) error {
 			event:      event.ReasonPoolClosed,
 		}
 	}
+	if !perished && atomic.LoadInt32(&p.hibernating) == 1 {
+		perished = true
+		r = reason{
+			loggerConn: logger.ReasonConnClosedIdle,
+			event:      event.ReasonIdle,
+		}
+	}
+	if !perished && p.budget != nil && p.budget.yield(p) {
+		perished = true
+		r = budgetReason
+	}
 	if perished {
 		_ = p.removeConnection(conn, r, nil)
 		go func() {
@@ -1075,6 +1128,9 @@ func (p *pool) clearImpl(err error, serviceID *bson.ObjectID, interruptAllConnec
 			}
 			w.tryDeliver(nil, pcErr)
 		}
+		if p.budget != nil {
+			p.budget.notWaiting(p)
+		}
 		p.createConnectionsCond.L.Unlock()
 	}
 }
@@ -1126,7 +1182,9 @@ func (p *pool) queueForNewConn(w *wantConn) {
 
 	p.newConnWait.cleanFront()
 	p.newConnWait.pushBack(w)
-	p.createConnectionsCond.Signal()
+	// Wake up all createConnections() goroutines because a goroutine that only serves critical
+	// checkOut requests cannot serve w if w has a lower priority.
+	p.createConnectionsCond.Broadcast()
 }
 
 func (p *pool) totalConnectionCount() int {
@@ -1143,18 +1201,39 @@ func (p *pool) availableConnectionCount() int {
 	return len(p.idleConns)
 }
 
-// createConnections creates connections for wantConn requests on the newConnWait queue.
-func (p *pool) createConnections(ctx context.Context, wg *sync.WaitGroup) {
+// createConnections creates connections for wantConn requests on the newConnWait queue that have
+// at least the given checkout priority.
+func (p *pool) createConnections(ctx context.Context, wg *sync.WaitGroup, minPriority driver.CheckoutPriority) {
 	defer wg.Done()
 
 	// condition returns true if the createConnections() loop should continue and false if it should
 	// wait. Note that the condition also listens for Context cancellation, which also causes the
 	// loop to continue, allowing for a subsequent check to return from createConnections().
+	//
+	// If the pool has a connection budget, the condition also reserves a connection of the budget
+	// and sets reserved when it returns true because a checkOut is waiting.
+	var reserved bool
 	condition := func() bool {
-		checkOutWaiting := p.newConnWait.len() > 0
+		if p.budget != nil {
+			// Remove the checkOut requests that timed out so that they don't take a connection of
+			// the budget.
+			p.newConnWait.cleanFront()
+		}
+		checkOutWaiting := p.newConnWait.lenAtLeast(minPriority) > 0
 		poolHasSpace := p.maxSize == 0 || uint64(len(p.conns)) < p.maxSize
 		cancelled := ctx.Err() != nil
-		return (checkOutWaiting && poolHasSpace) || cancelled
+		if cancelled || p.budget == nil {
+			return (checkOutWaiting && poolHasSpace) || cancelled
+		}
+		if !checkOutWaiting || !poolHasSpace {
+			// Only goroutines that serve all checkout priorities know that no checkOut waits.
+			if !poolHasSpace || p.newConnWait.len() == 0 {
+				p.budget.notWaiting(p)
+			}
+			return false
+		}
+		reserved = p.budget.acquire(p)
+		return reserved
 	}
 
 	// wait waits for there to be an available wantConn and for the pool to have space for a new
@@ -1165,17 +1244,24 @@ func (p *pool) createConnections(ctx context.Context, wg *sync.WaitGroup) {
 		p.createConnectionsCond.L.Lock()
 		defer p.createConnectionsCond.L.Unlock()
 
+		reserved = false
 		for !condition() {
 			p.createConnectionsCond.Wait()
 		}
 
 		if ctx.Err() != nil {
+			if reserved {
+				p.budget.release(p)
+			}
 			return nil, nil, false
 		}
 
 		p.newConnWait.cleanFront()
-		w := p.newConnWait.popFront()
+		w := p.newConnWait.popFrontAtLeast(minPriority)
 		if w == nil {
+			if reserved {
+				p.budget.release(p)
+			}
 			return nil, nil, false
 		}
 
@@ -1331,7 +1417,7 @@ func (p *pool) maintain(ctx context.Context, wg *sync.WaitGroup) {
 		// clear() pauses the pool and clears the wait queue, resulting in createConnections()
 		// doing work while the pool is "paused".
 		p.stateMu.RLock()
-		if p.state != poolReady {
+		if p.state != poolReady || atomic.LoadInt32(&p.hibernating) == 1 {
 			p.stateMu.RUnlock()
 			continue
 		}
@@ -1369,6 +1455,52 @@ func (p *pool) maintain(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// hibernate closes all idle connections and makes the pool close connections when they are
+// checked in, instead of keeping them idle, and stop maintaining minPoolSize until wake is called.
+// Connections can still be checked out, which creates new connections.
+func (p *pool) hibernate() {
+	atomic.StoreInt32(&p.hibernating, 1)
+
+	p.idleMu.Lock()
+	defer p.idleMu.Unlock()
+
+	for _, conn := range p.idleConns {
+		conn := conn
+		_ = p.removeConnection(conn, reason{
+			loggerConn: logger.ReasonConnClosedIdle,
+			event:      event.ReasonIdle,
+		}, nil)
+		go func() {
+			_ = p.closeConnection(conn)
+		}()
+	}
+	p.idleConns = p.idleConns[:0]
+}
+
+// closeIdleConnection closes the least recently used idle connection of the pool, if any, to give
+// its slot in the connection budget to a pool that is below its fair share.
+func (p *pool) closeIdleConnection() {
+	p.idleMu.Lock()
+	if len(p.idleConns) == 0 {
+		p.idleMu.Unlock()
+		return
+	}
+	conn := p.idleConns[0]
+	p.idleConns = p.idleConns[1:]
+	p.idleMu.Unlock()
+	if conn == nil {
+		return
+	}
+
+	_ = p.removeConnection(conn, budgetReason, nil)
+	_ = p.closeConnection(conn)
+}
+
+// wake makes the pool keep idle connections and maintain minPoolSize again.
+func (p *pool) wake() {
+	atomic.StoreInt32(&p.hibernating, 0)
+}
+
 func (p *pool) removePerishedConns() {
 	p.idleMu.Lock()
 	defer p.idleMu.Unlock()
@@ -1412,7 +1544,8 @@ func compact(arr []*connection) []*connection {
 // other and use wantConn to coordinate and agree about the winning outcome.
 // Based on https://cs.opensource.google/go/go/+/refs/tags/go1.16.6:src/net/http/transport.go;l=1174-1240
 type wantConn struct {
-	ready chan struct{}
+	ready    chan struct{}
+	priority driver.CheckoutPriority
 
 	mu   sync.Mutex // Guards conn, err
 	conn *connection
@@ -1477,9 +1610,78 @@ func (w *wantConn) cancel(p *pool, err error) {
 	}
 }
 
-// A wantConnQueue is a queue of wantConns.
-// Based on https://cs.opensource.google/go/go/+/refs/tags/go1.16.6:src/net/http/transport.go;l=1242-1306
+// A wantConnQueue is a priority queue of wantConns. wantConns are dequeued in order of their
+// checkout priority, and in FIFO order among wantConns with the same priority.
 type wantConnQueue struct {
+	// lanes holds one FIFO queue per checkout priority, ordered from lowest to highest priority.
+	lanes [numCheckoutPriorities]wantConnLane
+}
+
+const numCheckoutPriorities = int(driver.CheckoutPriorityCritical-driver.CheckoutPriorityBackground) + 1
+
+// lane returns the FIFO queue for the given checkout priority. Priorities outside of the supported
+// range are treated as the nearest supported priority.
+func (q *wantConnQueue) lane(priority driver.CheckoutPriority) *wantConnLane {
+	return &q.lanes[q.laneIndex(priority)]
+}
+
+// laneIndex returns the index in lanes of the FIFO queue for the given checkout priority.
+func (q *wantConnQueue) laneIndex(priority driver.CheckoutPriority) int {
+	i := int(priority - driver.CheckoutPriorityBackground)
+	if i < 0 {
+		i = 0
+	}
+	if i >= numCheckoutPriorities {
+		i = numCheckoutPriorities - 1
+	}
+	return i
+}
+
+// len returns the number of items in the queue.
+func (q *wantConnQueue) len() int {
+	return q.lenAtLeast(driver.CheckoutPriorityBackground)
+}
+
+// lenAtLeast returns the number of items in the queue with at least the given checkout priority.
+func (q *wantConnQueue) lenAtLeast(priority driver.CheckoutPriority) int {
+	n := 0
+	for i := q.laneIndex(priority); i < len(q.lanes); i++ {
+		n += q.lanes[i].len()
+	}
+	return n
+}
+
+// pushBack adds w to the back of the lane for its checkout priority.
+func (q *wantConnQueue) pushBack(w *wantConn) {
+	q.lane(w.priority).pushBack(w)
+}
+
+// popFront removes and returns the wantConn at the front of the highest priority non-empty lane.
+func (q *wantConnQueue) popFront() *wantConn {
+	return q.popFrontAtLeast(driver.CheckoutPriorityBackground)
+}
+
+// popFrontAtLeast removes and returns the wantConn at the front of the highest priority non-empty
+// lane, or nil if no lane with at least the given checkout priority has a wantConn.
+func (q *wantConnQueue) popFrontAtLeast(priority driver.CheckoutPriority) *wantConn {
+	for i := len(q.lanes) - 1; i >= q.laneIndex(priority); i-- {
+		if w := q.lanes[i].popFront(); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// cleanFront pops any wantConns that are no longer waiting from the head of every lane.
+func (q *wantConnQueue) cleanFront() {
+	for i := range q.lanes {
+		q.lanes[i].cleanFront()
+	}
+}
+
+// A wantConnLane is a FIFO queue of wantConns.
+// Based on https://cs.opensource.google/go/go/+/refs/tags/go1.16.6:src/net/http/transport.go;l=1242-1306
+type wantConnLane struct {
 	// This is a queue, not a deque.
 	// It is split into two stages - head[headPos:] and tail.
 	// popFront is trivial (headPos++) on the first stage, and
@@ -1496,17 +1698,17 @@ type wantConnQueue struct {
 }
 
 // len returns the number of items in the queue.
-func (q *wantConnQueue) len() int {
+func (q *wantConnLane) len() int {
 	return len(q.head) - q.headPos + len(q.tail)
 }
 
 // pushBack adds w to the back of the queue.
-func (q *wantConnQueue) pushBack(w *wantConn) {
+func (q *wantConnLane) pushBack(w *wantConn) {
 	q.tail = append(q.tail, w)
 }
 
 // popFront removes and returns the wantConn at the front of the queue.
-func (q *wantConnQueue) popFront() *wantConn {
+func (q *wantConnLane) popFront() *wantConn {
 	if q.headPos >= len(q.head) {
 		if len(q.tail) == 0 {
 			return nil
@@ -1521,7 +1723,7 @@ func (q *wantConnQueue) popFront() *wantConn {
 }
 
 // peekFront returns the wantConn at the front of the queue without removing it.
-func (q *wantConnQueue) peekFront() *wantConn {
+func (q *wantConnLane) peekFront() *wantConn {
 	if q.headPos < len(q.head) {
 		return q.head[q.headPos]
 	}
@@ -1532,7 +1734,7 @@ func (q *wantConnQueue) peekFront() *wantConn {
 }
 
 // cleanFront pops any wantConns that are no longer waiting from the head of the queue.
-func (q *wantConnQueue) cleanFront() {
+func (q *wantConnLane) cleanFront() {
 	for {
 		w := q.peekFront()
 		if w == nil || w.waiting() {
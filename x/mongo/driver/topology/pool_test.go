@@ -12,6 +12,7 @@ import (
 	"net"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/eventtest"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
 )
 
@@ -807,6 +809,151 @@ func TestPool_checkOut(t *testing.T) {
 
 		p.close(context.Background())
 	})
+	t.Run("higher priority checkOut is served first", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		p := newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			MaxPoolSize:    1,
+			ConnectTimeout: defaultConnectionTimeout,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+
+		c, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+
+		waitForIdleWaiters := func(n int) {
+			assert.Eventually(t, func() bool {
+				p.idleMu.Lock()
+				defer p.idleMu.Unlock()
+				return p.idleConnWait.len() == n
+			}, time.Second, time.Millisecond, "expected %d checkOut calls in the wait queue", n)
+		}
+
+		type checkOutResult struct {
+			priority driver.CheckoutPriority
+			err      error
+		}
+		order := make(chan checkOutResult, 2)
+		checkInErrs := make(chan error, 2)
+		checkOut := func(priority driver.CheckoutPriority) {
+			ctx := driver.WithCheckoutPriority(context.Background(), priority)
+			conn, err := p.checkOut(ctx)
+			order <- checkOutResult{priority: priority, err: err}
+			if err == nil {
+				checkInErrs <- p.checkIn(conn)
+			}
+		}
+
+		// Queue a background checkOut before a critical one and expect the critical checkOut to
+		// get the connection first.
+		go checkOut(driver.CheckoutPriorityBackground)
+		waitForIdleWaiters(1)
+		go checkOut(driver.CheckoutPriorityCritical)
+		waitForIdleWaiters(2)
+
+		require.NoError(t, p.checkIn(c))
+		first := <-order
+		require.NoError(t, first.err)
+		assert.Equal(t, driver.CheckoutPriorityCritical, first.priority, "expected critical checkOut to be served first")
+		second := <-order
+		require.NoError(t, second.err)
+		assert.Equal(t, driver.CheckoutPriorityBackground, second.priority, "expected background checkOut to be served second")
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, <-checkInErrs)
+		}
+
+		p.close(context.Background())
+	})
+	t.Run("checkout queue timeout error", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		p := newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			MaxPoolSize:    1,
+			ConnectTimeout: defaultConnectionTimeout,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+
+		_, err = p.checkOut(context.Background())
+		require.NoError(t, err)
+
+		// Check out again with a Context that has no deadline and expect the checkout queue
+		// timeout to end the wait.
+		ctx := driver.WithCheckoutQueueTimeout(context.Background(), 10*time.Millisecond)
+		_, err = p.checkOut(ctx)
+		assert.IsTypef(t, WaitQueueTimeoutError{}, err, "expected a WaitQueueTimeoutError")
+		assert.ErrorIs(t, err, ErrCheckoutQueueTimeout, "expected the error to wrap ErrCheckoutQueueTimeout")
+
+		p.close(context.Background())
+	})
+	t.Run("critical checkOut is not blocked by maxConnecting", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		// Block the first dial until release is closed, so the only regular createConnections()
+		// goroutine is busy.
+		release := make(chan struct{})
+		var dials int32
+		p := newPool(
+			poolConfig{
+				Address:        address.Address(addr.String()),
+				MaxConnecting:  1,
+				ConnectTimeout: defaultConnectionTimeout,
+			},
+			WithDialer(func(Dialer) Dialer {
+				return DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+					if atomic.AddInt32(&dials, 1) == 1 {
+						<-release
+					}
+					return (&net.Dialer{}).DialContext(ctx, network, address)
+				})
+			}),
+		)
+		err := p.ready()
+		require.NoError(t, err)
+
+		normal := make(chan error, 1)
+		go func() {
+			_, err := p.checkOut(context.Background())
+			normal <- err
+		}()
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&dials) == 1
+		}, time.Second, time.Millisecond, "expected the normal checkOut to start dialing")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = p.checkOut(driver.WithCheckoutPriority(ctx, driver.CheckoutPriorityCritical))
+		assert.NoError(t, err, "expected the critical checkOut to get a new connection")
+
+		close(release)
+		assert.NoError(t, <-normal, "expected the normal checkOut to succeed")
+
+		p.close(context.Background())
+	})
 	t.Run("canceled context in wait queue", func(t *testing.T) {
 		t.Parallel()
 
@@ -1090,6 +1237,52 @@ func TestPool_checkIn(t *testing.T) {
 	})
 }
 
+func TestPool_hibernate(t *testing.T) {
+	t.Parallel()
+
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 3, func(nc net.Conn) {
+		<-cleanup
+		_ = nc.Close()
+	})
+
+	d := newdialer(&net.Dialer{})
+	p := newPool(poolConfig{
+		Address:        address.Address(addr.String()),
+		ConnectTimeout: defaultConnectionTimeout,
+	}, WithDialer(func(Dialer) Dialer { return d }))
+	err := p.ready()
+	require.NoError(t, err)
+	defer p.close(context.Background())
+
+	idle, err := p.checkOut(context.Background())
+	require.NoError(t, err)
+	inUse, err := p.checkOut(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, p.checkIn(idle))
+
+	p.hibernate()
+	assert.Equalf(t, 0, p.availableConnectionCount(), "should have 0 idle connections in pool")
+	assert.Equalf(t, 1, p.totalConnectionCount(), "should have 1 total connection in pool")
+	assert.Eventuallyf(t, func() bool { return d.lenclosed() == 1 }, time.Second, 10*time.Millisecond,
+		"should have closed the idle connection")
+
+	// Connections returned while the pool hibernates are closed instead of kept idle.
+	require.NoError(t, p.checkIn(inUse))
+	assert.Equalf(t, 0, p.totalConnectionCount(), "should have 0 total connections in pool")
+	assert.Eventuallyf(t, func() bool { return d.lenclosed() == 2 }, time.Second, 10*time.Millisecond,
+		"should have closed the returned connection")
+
+	// Connections can still be checked out, and are kept idle again once the pool wakes up.
+	c, err := p.checkOut(context.Background())
+	require.NoError(t, err)
+	p.wake()
+	require.NoError(t, p.checkIn(c))
+	assert.Equalf(t, 1, p.availableConnectionCount(), "should have 1 idle connection in pool")
+	assert.Equalf(t, 2, d.lenclosed(), "should have closed 2 connections")
+}
+
 func TestPool_maintain(t *testing.T) {
 	t.Parallel()
 
@@ -10,6 +10,8 @@ import (
 	"container/list"
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -77,6 +79,12 @@ func (r *rttMonitor) connect() {
 	r.connMu.Lock()
 	defer r.connMu.Unlock()
 
+	// Reconnecting after a disconnect, e.g. when a hibernating server wakes up, requires a new
+	// context because disconnect cancels the current one.
+	if r.ctx.Err() != nil {
+		r.ctx, r.cancelFn = context.WithCancel(context.Background())
+	}
+
 	r.closeWg.Add(1)
 
 	go func() {
@@ -296,3 +304,43 @@ func (r *rttMonitor) Stats() string {
 		r.minRTT,
 		r.stddevRTT)
 }
+
+// RTTStats is a snapshot of the round-trip times observed by the RTT monitor of a server.
+type RTTStats struct {
+	// Min is the minimum round-trip time over the recent samples, or 0 if there are fewer than
+	// two samples.
+	Min time.Duration
+
+	// Average is the exponentially weighted moving average round-trip time.
+	Average time.Duration
+
+	// P90 is the 90th percentile round-trip time over the recent samples.
+	P90 time.Duration
+
+	// Samples is the number of recent samples Min and P90 are computed from.
+	Samples int
+}
+
+// stats returns a snapshot of the observed round-trip times.
+func (r *rttMonitor) stats() RTTStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := RTTStats{Min: r.minRTT, Average: r.averageRTT}
+	if r.movingMin == nil || r.movingMin.Len() == 0 {
+		return stats
+	}
+
+	samples := make([]time.Duration, 0, r.movingMin.Len())
+	for e := r.movingMin.Front(); e != nil; e = e.Next() {
+		samples = append(samples, e.Value.(time.Duration))
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	// Use the nearest-rank method: the smallest sample that is greater than or equal to 90% of the
+	// samples.
+	rank := int(math.Ceil(0.9*float64(len(samples)))) - 1
+	stats.P90 = samples[rank]
+	stats.Samples = len(samples)
+	return stats
+}
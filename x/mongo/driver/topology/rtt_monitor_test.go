@@ -484,3 +484,29 @@ func TestRTTMonitor_stddev(t *testing.T) {
 		})
 	}
 }
+
+func TestRTTMonitor_stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no samples", func(t *testing.T) {
+		t.Parallel()
+
+		rtt := &rttMonitor{movingMin: list.New()}
+		assert.Equal(t, RTTStats{}, rtt.stats())
+	})
+
+	t.Run("samples", func(t *testing.T) {
+		t.Parallel()
+
+		rtt := &rttMonitor{movingMin: list.New()}
+		for _, sample := range []time.Duration{5, 1, 9, 3, 7, 2, 10, 4, 8, 6} {
+			rtt.addSample(sample * time.Millisecond)
+		}
+
+		stats := rtt.stats()
+		assert.Equal(t, 10, stats.Samples)
+		assert.Equal(t, 1*time.Millisecond, stats.Min)
+		assert.Equal(t, 9*time.Millisecond, stats.P90)
+		assert.Equal(t, rtt.EWMA(), stats.Average)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
@@ -109,6 +110,7 @@ type Server struct {
 
 	state          int64
 	operationCount int64
+	hibernating    int32
 
 	cfg     *serverConfig
 	address address.Address
@@ -120,6 +122,7 @@ type Server struct {
 	done          chan struct{}
 	checkNow      chan struct{}
 	disconnecting chan struct{}
+	wake          chan struct{}
 	closewg       sync.WaitGroup
 
 	// description related fields
@@ -185,6 +188,7 @@ func NewServer(
 
 		done:          make(chan struct{}),
 		checkNow:      make(chan struct{}, 1),
+		wake:          make(chan struct{}, 1),
 		disconnecting: make(chan struct{}),
 
 		topologyID: topologyID,
@@ -198,7 +202,7 @@ func NewServer(
 		minRTTWindow:       5 * time.Minute,
 		createConnectionFn: s.createConnection,
 		createOperationFn:  s.createBaseOperation,
-		connectTimeout:     connectTimeout,
+		connectTimeout:     cfg.monitorConnectTimeout,
 	}
 	s.rttMonitor = newRTTMonitor(rttCfg)
 
@@ -214,6 +218,7 @@ func NewServer(
 		Logger:           cfg.logger,
 		handshakeErrFn:   s.ProcessHandshakeError,
 		ConnectTimeout:   connectTimeout,
+		ConnectionBudget: cfg.connectionBudget,
 	}
 
 	connectionOpts := copyConnectionOpts(cfg.connectionOpts)
@@ -315,12 +320,53 @@ func (s *Server) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// Hibernate closes the idle connections and the monitoring connections of the server and stops
+// monitoring it until Wake is called or an operation requests a connection. Connections in use
+// are closed when they are returned to the pool. The last known description of the server is
+// kept, so the server can be selected while it hibernates.
+func (s *Server) Hibernate() {
+	if !atomic.CompareAndSwapInt32(&s.hibernating, 0, 1) {
+		return
+	}
+
+	// Drain a wake signal sent before the previous hibernation was observed by the monitor.
+	select {
+	case <-s.wake:
+	default:
+	}
+
+	s.pool.hibernate()
+
+	// Cancel the in-progress check, which closes the monitoring connection, so that the monitor
+	// notices the hibernation without waiting for a streamed heartbeat or the heartbeat interval.
+	s.heartbeatListener.StopListening()
+	s.RequestImmediateCheck()
+}
+
+// Wake resumes the monitoring of a hibernating server and allows its pool to keep idle
+// connections again. It does nothing if the server is not hibernating.
+func (s *Server) Wake() {
+	if !atomic.CompareAndSwapInt32(&s.hibernating, 1, 0) {
+		return
+	}
+
+	s.pool.wake()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
 // Connection gets a connection to the server.
 func (s *Server) Connection(ctx context.Context) (*mnet.Connection, error) {
 	if atomic.LoadInt64(&s.state) != serverConnected {
 		return nil, ErrServerClosed
 	}
 
+	// Operations transparently resume a hibernating server.
+	s.Wake()
+
 	// Increment the operation count before calling checkOut to make sure that all connection
 	// requests are included in the operation count, including those in the wait queue. If we got an
 	// error instead of a connection, immediately decrement the operation count.
@@ -676,13 +722,30 @@ func (s *Server) update() {
 		default:
 		}
 
+		// While the server hibernates, close the monitoring connections and wait until it is woken
+		// up or disconnected. The next check opens a new monitoring connection.
+		if atomic.LoadInt32(&s.hibernating) == 1 {
+			if s.conn != nil {
+				_ = s.conn.close()
+				s.conn = nil
+			}
+			s.rttMonitor.disconnect()
+			s.monitorOnce = sync.Once{}
+
+			select {
+			case <-s.wake:
+			case <-done:
+			}
+			continue
+		}
+
 		previousDescription := s.Description()
 
 		desc, err := checkServerWithSignal(s, s.conn, s.heartbeatListener)
 
 		// The only error returned from checkServerWithSignal is errCheckCancelled.
 		if errors.Is(err, errCheckCancelled) {
-			if atomic.LoadInt64(&s.state) != serverConnected {
+			if atomic.LoadInt64(&s.state) != serverConnected || atomic.LoadInt32(&s.hibernating) == 1 {
 				continue
 			}
 
@@ -740,7 +803,7 @@ func (s *Server) update() {
 		transitionedFromNetworkError := desc.LastError != nil && unwrapConnectionError(desc.LastError) != nil &&
 			previousDescription.Kind != description.Unknown
 
-		if isStreamingEnabled(s) && isStreamable(s) {
+		if isStreamingEnabled(s) && isStreamable(s) && !s.cfg.rttMonitorDisabled {
 			s.monitorOnce.Do(s.rttMonitor.connect)
 		}
 
@@ -828,9 +891,9 @@ func (s *Server) setupHeartbeatConnection(ctx context.Context) error {
 
 	s.conn = conn
 
-	if s.cfg.connectTimeout != 0 {
+	if s.cfg.monitorConnectTimeout != 0 {
 		var cancelFn context.CancelFunc
-		ctx, cancelFn = context.WithTimeout(ctx, s.cfg.connectTimeout)
+		ctx, cancelFn = context.WithTimeout(ctx, s.cfg.monitorConnectTimeout)
 
 		defer cancelFn()
 	}
@@ -873,7 +936,7 @@ func getHeartbeatTimeout(srv *Server) time.Duration {
 		// Otherwise, it is connectTimeoutMS + heartbeatFrequencyMS to account for
 		// the fact that the query will block for heartbeatFrequencyMS
 		// server-side.
-		streamingTO := srv.cfg.connectTimeout
+		streamingTO := srv.cfg.monitorConnectTimeout
 		if streamingTO != 0 {
 			streamingTO += srv.cfg.heartbeatInterval
 		}
@@ -884,7 +947,7 @@ func getHeartbeatTimeout(srv *Server) time.Duration {
 	// The server doesn't support the awaitable protocol. Set the timeout to
 	// connectTimeoutMS and execute a regular heartbeat without any additional
 	// parameters.
-	return srv.cfg.connectTimeout
+	return srv.cfg.monitorConnectTimeout
 }
 
 // withHeartbeatTimeout will apply the appropriate timeout to the parent context
@@ -1024,7 +1087,7 @@ func (s *Server) check(ctx context.Context) (description.Server, error) {
 	if descPtr != nil {
 		// The check was successful. Set the average RTT and return.
 		desc := *descPtr
-		desc.AverageRTT = s.rttMonitor.EWMA()
+		desc.AverageRTT = s.estimateRTT()
 		desc.AverageRTTSet = true
 		desc.HeartbeatInterval = s.cfg.heartbeatInterval
 
@@ -1061,6 +1124,18 @@ func extractTopologyVersion(err error) *description.TopologyVersion {
 	return nil
 }
 
+// estimateRTT returns the round-trip time of the server reported in its description, which is
+// the 90th percentile of the recent samples for options.RTTEstimatorP90 and the moving average
+// otherwise.
+func (s *Server) estimateRTT() time.Duration {
+	if s.cfg.rttEstimator == options.RTTEstimatorP90 {
+		if stats := s.rttMonitor.stats(); stats.Samples > 0 {
+			return stats.P90
+		}
+	}
+	return s.rttMonitor.EWMA()
+}
+
 // RTTMonitor returns this server's round-trip-time monitor.
 func (s *Server) RTTMonitor() driver.RTTMonitor {
 	return s.rttMonitor
@@ -20,18 +20,21 @@ import (
 var defaultRegistry = bson.NewRegistry()
 
 type serverConfig struct {
-	clock                *session.ClusterClock
-	compressionOpts      []string
-	connectionOpts       []ConnectionOption
-	appname              string
-	heartbeatInterval    time.Duration
-	connectTimeout       time.Duration
-	serverMonitoringMode string
-	serverMonitor        *event.ServerMonitor
-	registry             *bson.Registry
-	monitoringDisabled   bool
-	serverAPI            *driver.ServerAPIOptions
-	loadBalanced         bool
+	clock                 *session.ClusterClock
+	compressionOpts       []string
+	connectionOpts        []ConnectionOption
+	appname               string
+	heartbeatInterval     time.Duration
+	connectTimeout        time.Duration
+	monitorConnectTimeout time.Duration
+	rttMonitorDisabled    bool
+	rttEstimator          string
+	serverMonitoringMode  string
+	serverMonitor         *event.ServerMonitor
+	registry              *bson.Registry
+	monitoringDisabled    bool
+	serverAPI             *driver.ServerAPIOptions
+	loadBalanced          bool
 
 	// Connection pool options.
 	maxConns             uint64
@@ -41,13 +44,15 @@ type serverConfig struct {
 	logger               *logger.Logger
 	poolMaxIdleTime      time.Duration
 	poolMaintainInterval time.Duration
+	connectionBudget     *connectionBudget
 }
 
 func newServerConfig(connectTimeout time.Duration, opts ...ServerOption) *serverConfig {
 	cfg := &serverConfig{
-		heartbeatInterval: 10 * time.Second,
-		connectTimeout:    connectTimeout,
-		registry:          defaultRegistry,
+		heartbeatInterval:     10 * time.Second,
+		connectTimeout:        connectTimeout,
+		monitorConnectTimeout: connectTimeout,
+		registry:              defaultRegistry,
 	}
 
 	for _, opt := range opts {
@@ -129,6 +134,14 @@ func WithMaxConnecting(fn func(uint64) uint64) ServerOption {
 	}
 }
 
+// withConnectionBudget configures the connection budget shared by the connection pools of all the
+// servers of a topology.
+func withConnectionBudget(fn func(*connectionBudget) *connectionBudget) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.connectionBudget = fn(cfg.connectionBudget)
+	}
+}
+
 // WithConnectionPoolMaxIdleTime configures the maximum time that a connection can remain idle in the connection pool
 // before being removed. If connectionPoolMaxIdleTime is 0, then no idle time is set and connections will not be removed
 // because of their age
@@ -196,6 +209,30 @@ func withLogger(fn func() *logger.Logger) ServerOption {
 	}
 }
 
+// withMonitorConnectTimeout configures the connect and heartbeat timeout of the connections used to
+// monitor the server. It defaults to the connect timeout of the server.
+func withMonitorConnectTimeout(fn func(time.Duration) time.Duration) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.monitorConnectTimeout = fn(cfg.monitorConnectTimeout)
+	}
+}
+
+// withRTTMonitorDisabled configures whether the server skips running the RTT monitor when streaming
+// heartbeats.
+func withRTTMonitorDisabled(fn func(bool) bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.rttMonitorDisabled = fn(cfg.rttMonitorDisabled)
+	}
+}
+
+// withRTTEstimator configures how the server estimates the round-trip time reported in its
+// description, either options.RTTEstimatorEWMA or options.RTTEstimatorP90.
+func withRTTEstimator(fn func(string) string) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.rttEstimator = fn(cfg.rttEstimator)
+	}
+}
+
 // withServerMonitoringMode configures the mode (stream, poll, or auto) to use
 // for monitoring.
 func withServerMonitoringMode(mode *string) ServerOption {
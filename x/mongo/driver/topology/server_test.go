@@ -30,6 +30,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/eventtest"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
@@ -1175,6 +1176,62 @@ func TestServer_ProcessError(t *testing.T) {
 	}
 }
 
+func TestServer_estimateRTT(t *testing.T) {
+	t.Parallel()
+
+	samples := []time.Duration{
+		10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond,
+		10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond,
+		50 * time.Millisecond, 100 * time.Millisecond,
+	}
+
+	testCases := []struct {
+		name      string
+		estimator string
+		samples   []time.Duration
+		want      func(*rttMonitor) time.Duration
+	}{
+		{
+			name:    "default",
+			samples: samples,
+			want:    (*rttMonitor).EWMA,
+		},
+		{
+			name:      "ewma",
+			estimator: options.RTTEstimatorEWMA,
+			samples:   samples,
+			want:      (*rttMonitor).EWMA,
+		},
+		{
+			name:      "p90",
+			estimator: options.RTTEstimatorP90,
+			samples:   samples,
+			want:      func(*rttMonitor) time.Duration { return 50 * time.Millisecond },
+		},
+		{
+			name:      "p90 without samples",
+			estimator: options.RTTEstimatorP90,
+			want:      func(*rttMonitor) time.Duration { return 0 },
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewServer(address.Address("localhost:27017"), bson.NewObjectID(), defaultConnectionTimeout,
+				withRTTEstimator(func(string) string { return tc.estimator }))
+			for _, sample := range tc.samples {
+				s.rttMonitor.addSample(sample)
+			}
+
+			assert.Equal(t, tc.want(s.rttMonitor), s.estimateRTT(), "RTT estimate mismatch")
+		})
+	}
+}
+
 func TestServer_getSocketTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -1229,10 +1286,9 @@ func TestServer_getSocketTimeout(t *testing.T) {
 			t.Parallel()
 
 			srv := &Server{
-				cfg: &serverConfig{
-					connectTimeout:    test.connectTimeout,
-					heartbeatInterval: test.heartbeatInterval,
-				},
+				cfg: newServerConfig(test.connectTimeout, WithHeartbeatInterval(
+					func(time.Duration) time.Duration { return test.heartbeatInterval },
+				)),
 				conn: &connection{},
 			}
 
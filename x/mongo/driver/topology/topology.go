@@ -41,6 +41,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/dns"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
 )
 
 // Topology state constants.
@@ -143,13 +144,19 @@ func New(cfg *Config) (*Topology, error) {
 		dnsResolver:       dns.DefaultResolver,
 		id:                bson.NewObjectID(),
 	}
+	if cfg.DNSResolver != nil {
+		t.dnsResolver = cfg.DNSResolver
+	}
+	if cfg.SRVPollingInterval > 0 {
+		t.rescanSRVInterval = cfg.SRVPollingInterval
+	}
 	t.desc.Store(description.Topology{})
 	t.updateCallback = func(desc description.Server) description.Server {
 		return t.apply(context.Background(), desc)
 	}
 
 	if t.cfg.URI != "" {
-		connStr, err := connstring.Parse(t.cfg.URI)
+		connStr, err := connstring.ParseWithResolver(t.cfg.URI, t.dnsResolver)
 		if err != nil {
 			return nil, err
 		}
@@ -280,7 +287,40 @@ func (t *Topology) Connect() error {
 	t.desc.Store(description.Topology{})
 	var err error
 	t.serversLock.Lock()
+	err = t.initServers(description.Topology{})
+	if err != nil {
+		t.serversLock.Unlock()
+		return err
+	}
+	t.serversLock.Unlock()
+	if mustLogTopologyMessage(t, logger.LevelInfo) {
+		logTopologyThirdPartyUsage(t, t.hosts)
+	}
+	if t.pollingRequired {
+		// sanity check before passing the hostname to resolver
+		if len(t.hosts) != 1 {
+			return fmt.Errorf("URI with SRV must include one and only one hostname")
+		}
+		_, _, err = net.SplitHostPort(t.hosts[0])
+		if err == nil {
+			// we were able to successfully extract a port from the host,
+			// but should not be able to when using SRV
+			return fmt.Errorf("URI with srv must not include a port number")
+		}
+		go t.pollSRVRecords(t.hosts[0])
+		t.pollingwg.Add(1)
+	}
 
+	t.subscriptionsClosed = false // explicitly set in case topology was disconnected and then reconnected
+
+	atomic.StoreInt64(&t.state, topologyConnected)
+	return nil
+}
+
+// initServers initializes the FSM of the topology from its configuration and starts monitoring
+// the servers of the seed list. It publishes a TopologyDescriptionChangedEvent from prev to the
+// initial description. The caller must hold serversLock.
+func (t *Topology) initServers(prev description.Topology) error {
 	// A replica set name sets the initial topology type to ReplicaSetNoPrimary unless a direct connection is also
 	// specified, in which case the initial type is Single.
 	if t.cfg.ReplicaSetName != "" {
@@ -307,11 +347,10 @@ func (t *Topology) Connect() error {
 
 		// Transition from Unknown with no servers to LoadBalanced with a single Unknown server.
 		t.fsm.Kind = description.TopologyKindLoadBalanced
-		t.publishTopologyDescriptionChangedEvent(description.Topology{}, t.fsm.Topology)
+		t.publishTopologyDescriptionChangedEvent(prev, t.fsm.Topology)
 
 		addr := address.Address(t.cfg.SeedList[0]).Canonicalize()
 		if err := t.addServer(addr); err != nil {
-			t.serversLock.Unlock()
 			return err
 		}
 
@@ -336,39 +375,15 @@ func (t *Topology) Connect() error {
 			SessionTimeoutMinutes: t.fsm.SessionTimeoutMinutes,
 		}
 		t.desc.Store(newDesc)
-		t.publishTopologyDescriptionChangedEvent(description.Topology{}, t.fsm.Topology)
+		t.publishTopologyDescriptionChangedEvent(prev, t.fsm.Topology)
 		for _, a := range t.cfg.SeedList {
 			addr := address.Address(a).Canonicalize()
-			err = t.addServer(addr)
-			if err != nil {
-				t.serversLock.Unlock()
+			if err := t.addServer(addr); err != nil {
 				return err
 			}
 		}
 	}
 
-	t.serversLock.Unlock()
-	if mustLogTopologyMessage(t, logger.LevelInfo) {
-		logTopologyThirdPartyUsage(t, t.hosts)
-	}
-	if t.pollingRequired {
-		// sanity check before passing the hostname to resolver
-		if len(t.hosts) != 1 {
-			return fmt.Errorf("URI with SRV must include one and only one hostname")
-		}
-		_, _, err = net.SplitHostPort(t.hosts[0])
-		if err == nil {
-			// we were able to successfully extract a port from the host,
-			// but should not be able to when using SRV
-			return fmt.Errorf("URI with srv must not include a port number")
-		}
-		go t.pollSRVRecords(t.hosts[0])
-		t.pollingwg.Add(1)
-	}
-
-	t.subscriptionsClosed = false // explicitly set in case topology was disconnected and then reconnected
-
-	atomic.StoreInt64(&t.state, topologyConnected)
 	return nil
 }
 
@@ -412,6 +427,74 @@ func (t *Topology) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// Hibernate hibernates every server in the topology, closing their idle and monitoring
+// connections until Wake is called or an operation checks out a connection from a server. SRV
+// polling is not stopped.
+func (t *Topology) Hibernate() {
+	for _, s := range t.serverList() {
+		s.Hibernate()
+	}
+}
+
+// Wake resumes the monitoring of every hibernating server in the topology.
+func (t *Topology) Wake() {
+	for _, s := range t.serverList() {
+		s.Wake()
+	}
+}
+
+// Reset closes every server of the topology, including their pooled and monitoring connections,
+// and restarts the discovery of the deployment from the seed list as Connect does. Unlike
+// Disconnect followed by Connect, Reset keeps the subscriptions of the topology open and sends
+// them the new description. Operations that use a connection of a closed server fail, and
+// operations that select a server after Reset returns wait for the new servers to be discovered.
+//
+// Reset is intended for use after the connections of the topology became unusable without being
+// closed, e.g. in the child of a process fork or after the network of the process changed.
+func (t *Topology) Reset(ctx context.Context) error {
+	if atomic.LoadInt64(&t.state) != topologyConnected {
+		return ErrTopologyClosed
+	}
+
+	t.serversLock.Lock()
+	if t.serversClosed {
+		t.serversLock.Unlock()
+		return ErrTopologyClosed
+	}
+
+	// Stop the monitors of the old servers from updating the new FSM, which may have servers with
+	// the same addresses.
+	servers := t.servers
+	for _, server := range servers {
+		server.updateTopologyCallback.Store((updateTopologyCallback)(nil))
+	}
+
+	prev := t.fsm.Topology
+	t.servers = make(map[address.Address]*Server)
+	t.fsm = newFSM()
+	err := t.initServers(prev)
+	current := t.Description()
+
+	t.subLock.Lock()
+	for _, ch := range t.subscribers {
+		// We drain the description if there's one in the channel
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- current
+	}
+	t.subLock.Unlock()
+	t.serversLock.Unlock()
+
+	for _, server := range servers {
+		_ = server.Disconnect(ctx)
+		t.publishServerClosedEvent(server.address)
+	}
+
+	return err
+}
+
 // Description returns a description of the topology.
 func (t *Topology) Description() description.Topology {
 	td, ok := t.desc.Load().(description.Topology)
@@ -421,6 +504,60 @@ func (t *Topology) Description() description.Topology {
 	return td
 }
 
+// RetryPolicy returns the RetryPolicy configured for this Topology, if any. It implements the
+// driver.RetryPolicyDeployment interface.
+func (t *Topology) RetryPolicy() *driver.RetryPolicy {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.RetryPolicy
+}
+
+// MaxTimeAllowance returns the time reserved for the network round trip when deriving maxTimeMS
+// from the context deadline of an operation. It implements the driver.MaxTimeAllowanceDeployment
+// interface.
+func (t *Topology) MaxTimeAllowance() time.Duration {
+	if t.cfg == nil {
+		return 0
+	}
+	return t.cfg.MaxTimeAllowance
+}
+
+// CheckDocumentSize returns whether the size of command documents is checked against the
+// maxBsonObjectSize of the selected server before they are sent. It implements the
+// driver.DocumentSizeCheckDeployment interface.
+func (t *Topology) CheckDocumentSize() bool {
+	return t.cfg != nil && t.cfg.CheckDocumentSize
+}
+
+// CausalTimeStore returns the store that shares the cluster time and operation time of the
+// operations run against this Topology, if any. It implements the
+// driver.CausalTimeStoreDeployment interface.
+func (t *Topology) CausalTimeStore() session.CausalTimeStore {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.CausalTimeStore
+}
+
+// ConnectionBudgetStats returns statistics about the connection budget shared by the connection
+// pools of the servers of this Topology, and false if the Topology has no connection budget.
+func (t *Topology) ConnectionBudgetStats() (ConnectionBudgetStats, bool) {
+	if t.cfg == nil || t.cfg.connectionBudget == nil {
+		return ConnectionBudgetStats{}, false
+	}
+	return t.cfg.connectionBudget.stats(), true
+}
+
+// OperationMiddleware returns the OperationMiddleware configured for this Topology, if any. It
+// implements the driver.OperationMiddlewareDeployment interface.
+func (t *Topology) OperationMiddleware() driver.OperationMiddleware {
+	if t.cfg == nil {
+		return nil
+	}
+	return t.cfg.OperationMiddleware
+}
+
 // Kind returns the topology kind of this Topology.
 func (t *Topology) Kind() description.TopologyKind { return t.Description().Kind }
 
@@ -845,12 +982,14 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 		return true
 	}
 
+	var added, removed []address.Address
 	for _, r := range diff.Removed {
 		addr := address.Address(r).Canonicalize()
 		s, ok := t.servers[addr]
 		if !ok {
 			continue
 		}
+		removed = append(removed, addr)
 		go func() {
 			cancelCtx, cancel := context.WithCancel(context.Background())
 			cancel()
@@ -877,6 +1016,11 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 		addr := address.Address(a).Canonicalize()
 		_ = t.addServer(addr)
 		t.fsm.addServer(addr)
+		added = append(added, addr)
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		t.publishSRVHostsChangedEvent(added, removed)
 	}
 
 	// store new description
@@ -996,11 +1140,13 @@ func (t *Topology) String() string {
 
 // publishes a ServerDescriptionChangedEvent to indicate the server description has changed
 func (t *Topology) publishServerDescriptionChangedEvent(prev description.Server, current description.Server) {
+	prevDesc, currentDesc := newEventServerDescription(prev), newEventServerDescription(current)
 	serverDescriptionChanged := &event.ServerDescriptionChangedEvent{
 		Address:             current.Addr,
 		TopologyID:          t.id,
-		PreviousDescription: newEventServerDescription(prev),
-		NewDescription:      newEventServerDescription(current),
+		PreviousDescription: prevDesc,
+		NewDescription:      currentDesc,
+		Diff:                event.DiffServerDescriptions(prevDesc, currentDesc),
 	}
 
 	if t.cfg.ServerMonitor != nil && t.cfg.ServerMonitor.ServerDescriptionChanged != nil {
@@ -1034,6 +1180,17 @@ func (t *Topology) publishServerClosedEvent(addr address.Address) {
 	}
 }
 
+// publishes a SRVHostsChangedEvent to indicate that SRV polling added or removed hosts
+func (t *Topology) publishSRVHostsChangedEvent(added, removed []address.Address) {
+	if t.cfg.ServerMonitor != nil && t.cfg.ServerMonitor.SRVHostsChanged != nil {
+		t.cfg.ServerMonitor.SRVHostsChanged(&event.SRVHostsChangedEvent{
+			TopologyID: t.id,
+			Added:      added,
+			Removed:    removed,
+		})
+	}
+}
+
 // publishes a TopologyDescriptionChangedEvent to indicate the topology description has changed
 func (t *Topology) publishTopologyDescriptionChangedEvent(prev description.Topology, current description.Topology) {
 	topologyDescriptionChanged := &event.TopologyDescriptionChangedEvent{
@@ -1118,6 +1275,7 @@ func newEventServerDescription(srv description.Server) event.ServerDescription {
 		SetName:               srv.SetName,
 		SetVersion:            srv.SetVersion,
 		Tags:                  srv.Tags,
+		AverageRTT:            srv.AverageRTT,
 	}
 
 	if srv.WireVersion != nil {
@@ -13,15 +13,21 @@ import (
 	"net/http"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/dns"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+	xocsp "golang.org/x/crypto/ocsp"
 )
 
 const defaultServerSelectionTimeout = 30 * time.Second
@@ -40,8 +46,16 @@ type Config struct {
 	ServerMonitor          *event.ServerMonitor
 	SRVMaxHosts            int
 	SRVServiceName         string
+	SRVPollingInterval     time.Duration
+	DNSResolver            *dns.Resolver
 	LoadBalanced           bool
+	RetryPolicy            *driver.RetryPolicy
+	OperationMiddleware    driver.OperationMiddleware
+	MaxTimeAllowance       time.Duration
+	CheckDocumentSize      bool
+	CausalTimeStore        session.CausalTimeStore
 	logger                 *logger.Logger
+	connectionBudget       *connectionBudget
 }
 
 // ConvertToDriverAPIOptions converts a options.ServerAPIOptions instance to a driver.ServerAPIOptions.
@@ -217,6 +231,14 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		cfgp.SRVMaxHosts = *opts.SRVMaxHosts
 	}
 
+	if opts.SRVPollingInterval != nil {
+		cfgp.SRVPollingInterval = *opts.SRVPollingInterval
+	}
+
+	if opts.DNSResolver != nil {
+		cfgp.DNSResolver = dns.NewResolver(opts.DNSResolver)
+	}
+
 	// AppName
 	var appName string
 	if opts.AppName != nil {
@@ -300,6 +322,22 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			func(Dialer) Dialer { return opts.Dialer },
 		))
 	}
+	// ConnFactory
+	if opts.ConnFactory != nil {
+		connOpts = append(connOpts, withConnFactory(
+			func(connFactoryFn) connFactoryFn { return connFactoryFn(opts.ConnFactory) },
+		))
+	}
+	// HandshakeHook
+	if opts.HandshakeHook != nil {
+		connOpts = append(connOpts, withHandshakeHook(
+			func(handshakeHookFn) handshakeHookFn {
+				return func(addr address.Address, desc description.Server) error {
+					return opts.HandshakeHook(addr.String(), newEventServerDescription(desc))
+				}
+			},
+		))
+	}
 	// Direct
 	if opts.Direct != nil && *opts.Direct {
 		cfgp.Mode = SingleMode
@@ -311,6 +349,24 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			func(time.Duration) time.Duration { return *opts.HeartbeatInterval },
 		))
 	}
+	// ServerMonitorConnectTimeout
+	if opts.ServerMonitorConnectTimeout != nil {
+		serverOpts = append(serverOpts, withMonitorConnectTimeout(
+			func(time.Duration) time.Duration { return *opts.ServerMonitorConnectTimeout },
+		))
+	}
+	// DisableRTTMonitor
+	if opts.DisableRTTMonitor != nil {
+		serverOpts = append(serverOpts, withRTTMonitorDisabled(
+			func(bool) bool { return *opts.DisableRTTMonitor },
+		))
+	}
+	// RTTEstimator
+	if opts.RTTEstimator != nil {
+		serverOpts = append(serverOpts, withRTTEstimator(
+			func(string) string { return *opts.RTTEstimator },
+		))
+	}
 	// Hosts
 	cfgp.SeedList = []string{"localhost:27017"} // default host
 	if len(opts.Hosts) > 0 {
@@ -344,6 +400,14 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			WithMaxConnecting(func(uint64) uint64 { return *opts.MaxConnecting }),
 		)
 	}
+	// MaxConnections
+	if opts.MaxConnections != nil && *opts.MaxConnections > 0 {
+		cfgp.connectionBudget = newConnectionBudget(*opts.MaxConnections)
+		serverOpts = append(
+			serverOpts,
+			withConnectionBudget(func(*connectionBudget) *connectionBudget { return cfgp.connectionBudget }),
+		)
+	}
 	// PoolMonitor
 	if opts.PoolMonitor != nil {
 		serverOpts = append(
@@ -396,12 +460,41 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 	}
 
 	// OCSP cache
-	ocspCache := ocsp.NewCache()
+	var ocspCache ocsp.Cache = ocsp.NewCache()
+	if opts.DisableOCSPCache != nil && *opts.DisableOCSPCache {
+		ocspCache = ocsp.NoCache{}
+	} else if opts.OCSPCacheMaxAge != nil {
+		ocspCache = ocsp.NewCacheWithMaxAge(*opts.OCSPCacheMaxAge)
+	}
 	connOpts = append(
 		connOpts,
 		WithOCSPCache(func(ocsp.Cache) ocsp.Cache { return ocspCache }),
 	)
 
+	// OCSP stapling and failure policy
+	if opts.OCSPRequireStaple != nil {
+		connOpts = append(
+			connOpts,
+			WithOCSPRequireStaple(func(bool) bool { return *opts.OCSPRequireStaple }),
+		)
+	}
+	if opts.OCSPHardFail != nil {
+		connOpts = append(
+			connOpts,
+			WithOCSPHardFail(func(bool) bool { return *opts.OCSPHardFail }),
+		)
+	}
+	if callback := opts.OCSPVerificationCallback; callback != nil {
+		connOpts = append(
+			connOpts,
+			WithOCSPCallback(func(func(address.Address, ocsp.Result)) func(address.Address, ocsp.Result) {
+				return func(addr address.Address, res ocsp.Result) {
+					callback(newOCSPVerification(addr, res))
+				}
+			}),
+		)
+	}
+
 	// Disable communication with external OCSP responders.
 	if opts.DisableOCSPEndpointCheck != nil {
 		connOpts = append(
@@ -424,6 +517,31 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		)
 	}
 
+	// RetryPolicy
+	if rp := opts.RetryPolicy; rp != nil {
+		cfgp.RetryPolicy = newRetryPolicy(rp)
+	}
+
+	// OperationMiddleware
+	if len(opts.OperationMiddleware) > 0 {
+		cfgp.OperationMiddleware = newOperationMiddleware(opts.OperationMiddleware)
+	}
+
+	// MaxTimeAllowance
+	if opts.MaxTimeAllowance != nil {
+		cfgp.MaxTimeAllowance = *opts.MaxTimeAllowance
+	}
+
+	// CheckDocumentSize
+	if opts.CheckDocumentSize != nil {
+		cfgp.CheckDocumentSize = *opts.CheckDocumentSize
+	}
+
+	// CausalTimeStore
+	if opts.CausalTimeStore != nil {
+		cfgp.CausalTimeStore = causalTimeStore{store: opts.CausalTimeStore}
+	}
+
 	lgr, err := newLogger(opts.LoggerOptions)
 	if err != nil {
 		return nil, err
@@ -446,3 +564,91 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 
 	return cfgp, nil
 }
+
+// newRetryPolicy converts the client RetryPolicy to the driver RetryPolicy.
+func newRetryPolicy(rp *options.RetryPolicy) *driver.RetryPolicy {
+	policy := &driver.RetryPolicy{
+		MaxAttempts:    rp.MaxAttempts,
+		InitialBackoff: rp.InitialBackoff,
+		MaxBackoff:     rp.MaxBackoff,
+	}
+	if retryable := rp.Retryable; retryable != nil {
+		policy.Retryable = func(a driver.RetryAttempt) bool {
+			return retryable(options.RetryAttempt{
+				CommandName: a.CommandName,
+				Write:       a.Type == driver.Write,
+				Attempt:     a.Attempt,
+				Err:         a.Err,
+				Retryable:   a.Retryable,
+			})
+		}
+	}
+	return policy
+}
+
+// newOperationMiddleware chains the client OperationMiddleware into a driver OperationMiddleware.
+func newOperationMiddleware(middleware []options.OperationMiddleware) driver.OperationMiddleware {
+	return func(next driver.OperationHandler) driver.OperationHandler {
+		var handler options.OperationHandler = func(ctx context.Context, op *options.OperationInfo) error {
+			info := &driver.OperationInfo{
+				CommandName: op.CommandName,
+				Database:    op.Database,
+				Type:        driver.Read,
+			}
+			if op.Write {
+				info.Type = driver.Write
+			}
+			if op.Comment != nil {
+				t, data, err := bson.MarshalValue(op.Comment)
+				if err != nil {
+					return fmt.Errorf("error marshaling operation comment: %w", err)
+				}
+				info.Comment = bsoncore.Value{Type: bsoncore.Type(t), Data: data}
+			}
+			return next(ctx, info)
+		}
+		for i := len(middleware) - 1; i >= 0; i-- {
+			handler = middleware[i](handler)
+		}
+
+		return func(ctx context.Context, info *driver.OperationInfo) error {
+			return handler(ctx, &options.OperationInfo{
+				CommandName: info.CommandName,
+				Database:    info.Database,
+				Write:       info.Type == driver.Write,
+			})
+		}
+	}
+}
+
+// causalTimeStore adapts an options.CausalTimeStore to a session.CausalTimeStore.
+type causalTimeStore struct {
+	store options.CausalTimeStore
+}
+
+func (s causalTimeStore) Load(ctx context.Context) (session.CausalTimes, error) {
+	times, err := s.store.Load(ctx)
+	return session.CausalTimes{ClusterTime: times.ClusterTime, OperationTime: times.OperationTime}, err
+}
+
+func (s causalTimeStore) Save(ctx context.Context, times session.CausalTimes) error {
+	return s.store.Save(ctx, options.CausalTimes{ClusterTime: times.ClusterTime, OperationTime: times.OperationTime})
+}
+
+// newOCSPVerification converts the outcome of the OCSP verification of a connection to addr.
+func newOCSPVerification(addr address.Address, res ocsp.Result) options.OCSPVerification {
+	status := "unknown"
+	switch res.Status {
+	case xocsp.Good:
+		status = "good"
+	case xocsp.Revoked:
+		status = "revoked"
+	}
+	return options.OCSPVerification{
+		Address:  addr.String(),
+		Status:   status,
+		Source:   string(res.Source),
+		SoftFail: res.SoftFail,
+		Err:      res.Err,
+	}
+}
@@ -7,18 +7,37 @@
 package topology
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"reflect"
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
+	xocsp "golang.org/x/crypto/ocsp"
 )
 
+type testCausalTimeStore struct {
+	times options.CausalTimes
+}
+
+func (s *testCausalTimeStore) Load(context.Context) (options.CausalTimes, error) {
+	return s.times, nil
+}
+
+func (s *testCausalTimeStore) Save(_ context.Context, times options.CausalTimes) error {
+	s.times = times
+	return nil
+}
+
 func TestDirectConnectionFromConnString(t *testing.T) {
 	type testCaseQuery [][2]string
 
@@ -106,6 +125,126 @@ func TestTopologyNewConfig(t *testing.T) {
 		assert.Nil(t, err, "error constructing topology config: %v", err)
 		assert.Equal(t, []string{"localhost:27018"}, cfg.SeedList)
 	})
+	t.Run("MaxTimeAllowance", func(t *testing.T) {
+		cfg, err := NewConfig(options.Client(), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		assert.Equal(t, time.Duration(0), cfg.MaxTimeAllowance)
+
+		cfg, err = NewConfig(options.Client().SetMaxTimeAllowance(20*time.Millisecond), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		assert.Equal(t, 20*time.Millisecond, cfg.MaxTimeAllowance)
+		assert.Equal(t, 20*time.Millisecond, (&Topology{cfg: cfg}).MaxTimeAllowance())
+	})
+	t.Run("CausalTimeStore", func(t *testing.T) {
+		cfg, err := NewConfig(options.Client(), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		assert.Nil(t, (&Topology{cfg: cfg}).CausalTimeStore(), "expected no store by default")
+
+		store := &testCausalTimeStore{times: options.CausalTimes{OperationTime: &bson.Timestamp{T: 1}}}
+		cfg, err = NewConfig(options.Client().SetCausalTimeStore(store), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+
+		ds := (&Topology{cfg: cfg}).CausalTimeStore()
+		require.NotNil(t, ds, "expected a store")
+		times, err := ds.Load(context.Background())
+		require.NoError(t, err, "Load error")
+		assert.Equal(t, store.times.OperationTime, times.OperationTime, "loaded operation time mismatch")
+
+		err = ds.Save(context.Background(), session.CausalTimes{OperationTime: &bson.Timestamp{T: 2}})
+		require.NoError(t, err, "Save error")
+		assert.Equal(t, &bson.Timestamp{T: 2}, store.times.OperationTime, "saved operation time mismatch")
+	})
+	t.Run("MaxConnections", func(t *testing.T) {
+		cfg, err := NewConfig(options.Client(), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		_, ok := (&Topology{cfg: cfg}).ConnectionBudgetStats()
+		assert.False(t, ok, "expected no connection budget by default")
+
+		cfg, err = NewConfig(options.Client().SetMaxConnections(50), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		stats, ok := (&Topology{cfg: cfg}).ConnectionBudgetStats()
+		require.True(t, ok, "expected a connection budget")
+		assert.Equal(t, uint64(50), stats.Max, "Max mismatch")
+
+		serverCfg := newServerConfig(cfg.ConnectTimeout, cfg.ServerOpts...)
+		assert.Equal(t, cfg.connectionBudget, serverCfg.connectionBudget, "expected the servers to share the budget")
+	})
+	t.Run("default server monitor options", func(t *testing.T) {
+		cfg, err := NewConfig(options.Client().SetConnectTimeout(5*time.Second), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+
+		serverCfg := newServerConfig(cfg.ConnectTimeout, cfg.ServerOpts...)
+		assert.Equal(t, 5*time.Second, serverCfg.monitorConnectTimeout)
+		assert.False(t, serverCfg.rttMonitorDisabled)
+		assert.Equal(t, "", serverCfg.rttEstimator)
+	})
+	t.Run("non-default server monitor options", func(t *testing.T) {
+		opts := options.Client().
+			SetConnectTimeout(5 * time.Second).
+			SetServerMonitorConnectTimeout(time.Second).
+			SetDisableRTTMonitor(true).
+			SetRTTEstimator(options.RTTEstimatorP90)
+		cfg, err := NewConfig(opts, nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+
+		serverCfg := newServerConfig(cfg.ConnectTimeout, cfg.ServerOpts...)
+		assert.Equal(t, 5*time.Second, serverCfg.connectTimeout)
+		assert.Equal(t, time.Second, serverCfg.monitorConnectTimeout)
+		assert.True(t, serverCfg.rttMonitorDisabled)
+		assert.Equal(t, options.RTTEstimatorP90, serverCfg.rttEstimator)
+	})
+	t.Run("OperationMiddleware", func(t *testing.T) {
+		cfg, err := NewConfig(options.Client(), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+		assert.Nil(t, (&Topology{cfg: cfg}).OperationMiddleware(), "expected no middleware by default")
+
+		var calls []string
+		record := func(name string) options.OperationMiddleware {
+			return func(next options.OperationHandler) options.OperationHandler {
+				return func(ctx context.Context, op *options.OperationInfo) error {
+					calls = append(calls, name+":"+op.CommandName)
+					op.Comment = name
+					return next(ctx, op)
+				}
+			}
+		}
+		cfg, err = NewConfig(options.Client().SetOperationMiddleware(record("outer"), record("inner")), nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+
+		var got *driver.OperationInfo
+		handler := (&Topology{cfg: cfg}).OperationMiddleware()(func(_ context.Context, info *driver.OperationInfo) error {
+			got = info
+			return nil
+		})
+		err = handler(context.Background(), &driver.OperationInfo{CommandName: "find", Database: "db", Type: driver.Read})
+		assert.Nil(t, err, "handler error: %v", err)
+
+		assert.Equal(t, []string{"outer:find", "inner:find"}, calls, "expected the first middleware to be outermost")
+		require.NotNil(t, got, "expected the driver handler to be called")
+		assert.Equal(t, "db", got.Database)
+		assert.Equal(t, driver.Read, got.Type)
+		assert.Equal(t, "inner", got.Comment.StringValue(), "expected the comment of the innermost middleware")
+	})
+	t.Run("OCSP options", func(t *testing.T) {
+		var got []options.OCSPVerification
+		opts := options.Client().
+			SetOCSPRequireStaple(true).
+			SetOCSPHardFail(true).
+			SetDisableOCSPCache(true).
+			SetOCSPVerificationCallback(func(v options.OCSPVerification) { got = append(got, v) })
+		cfg, err := NewConfig(opts, nil)
+		assert.Nil(t, err, "error constructing topology config: %v", err)
+
+		serverCfg := newServerConfig(cfg.ConnectTimeout, cfg.ServerOpts...)
+		connCfg := newConnectionConfig(serverCfg.connectionOpts...)
+		assert.True(t, connCfg.ocspRequireStaple, "expected stapling to be required")
+		assert.True(t, connCfg.ocspHardFail, "expected hard-fail")
+		assert.Equal(t, ocsp.NoCache{}, connCfg.ocspCache, "expected no OCSP cache")
+
+		require.NotNil(t, connCfg.ocspCallback, "expected an OCSP callback")
+		connCfg.ocspCallback(address.Address("localhost:27017"), ocsp.Result{Status: xocsp.Good, Source: ocsp.SourceStaple})
+		assert.Equal(t, []options.OCSPVerification{{Address: "localhost:27017", Status: "good", Source: "staple"}}, got)
+	})
 }
 
 // Test that convertOIDCArgs exhaustively copies all fields of a driver.OIDCArgs
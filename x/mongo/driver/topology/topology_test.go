@@ -480,6 +480,40 @@ func TestMinPoolSize(t *testing.T) {
 	}
 }
 
+func TestTopologyReset(t *testing.T) {
+	cfg, err := NewConfig(options.Client().SetHosts([]string{"localhost:27017"}), nil)
+	require.NoError(t, err, "error constructing topology config")
+	topo, err := New(cfg)
+	require.NoError(t, err, "topology.New error")
+	require.NoError(t, topo.Connect(), "topology.Connect error")
+
+	sub, err := topo.Subscribe()
+	require.NoError(t, err, "Subscribe error")
+	<-sub.Updates
+
+	addr := address.Address("localhost:27017")
+	topo.serversLock.Lock()
+	old := topo.servers[addr]
+	topo.serversLock.Unlock()
+	require.NoError(t, topo.Reset(context.Background()), "Reset error")
+
+	select {
+	case desc := <-sub.Updates:
+		assert.Len(t, desc.Servers, 1, "expected the seed server in the new description")
+	case <-time.After(testTimeout):
+		t.Fatal("timed out waiting for the new description")
+	}
+	topo.serversLock.Lock()
+	current := topo.servers[addr]
+	topo.serversLock.Unlock()
+	assert.NotNil(t, current, "expected a new server for the seed")
+	assert.NotEqual(t, old, current, "expected the server to be replaced")
+	assert.Equal(t, int64(serverDisconnected), atomic.LoadInt64(&old.state), "expected the old server to be disconnected")
+
+	require.NoError(t, topo.Disconnect(context.Background()), "Disconnect error")
+	assert.ErrorIs(t, topo.Reset(context.Background()), ErrTopologyClosed, "expected Reset error after Disconnect")
+}
+
 func TestTopology_String_Race(_ *testing.T) {
 	ch := make(chan bool)
 	topo := &Topology{